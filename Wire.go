@@ -30,21 +30,31 @@ import (
 	chartProvider "github.com/devtron-labs/devtron/api/appStore/chartProvider"
 	appStoreDeployment "github.com/devtron-labs/devtron/api/appStore/deployment"
 	appStoreDiscover "github.com/devtron-labs/devtron/api/appStore/discover"
+	"github.com/devtron-labs/devtron/api/appStore/upgradeAdvisor"
 	appStoreValues "github.com/devtron-labs/devtron/api/appStore/values"
+	"github.com/devtron-labs/devtron/api/appTransfer"
+	"github.com/devtron-labs/devtron/api/appsAsCode"
 	"github.com/devtron-labs/devtron/api/argoApplication"
+	"github.com/devtron-labs/devtron/api/auth/scim"
 	"github.com/devtron-labs/devtron/api/auth/sso"
 	"github.com/devtron-labs/devtron/api/auth/user"
+	"github.com/devtron-labs/devtron/api/canaryAnalysis"
 	chartRepo "github.com/devtron-labs/devtron/api/chartRepo"
 	"github.com/devtron-labs/devtron/api/cluster"
 	"github.com/devtron-labs/devtron/api/connector"
 	"github.com/devtron-labs/devtron/api/dashboardEvent"
 	"github.com/devtron-labs/devtron/api/deployment"
+	"github.com/devtron-labs/devtron/api/deploymentWindow"
 	"github.com/devtron-labs/devtron/api/devtronResource"
 	"github.com/devtron-labs/devtron/api/externalLink"
 	fluxApplication "github.com/devtron-labs/devtron/api/fluxApplication"
 	client "github.com/devtron-labs/devtron/api/helm-app"
+	"github.com/devtron-labs/devtron/api/helmDrift"
+	"github.com/devtron-labs/devtron/api/imageRetention"
 	"github.com/devtron-labs/devtron/api/k8s"
 	"github.com/devtron-labs/devtron/api/module"
+	"github.com/devtron-labs/devtron/api/registryPromotion"
+	"github.com/devtron-labs/devtron/api/resourceQuota"
 	"github.com/devtron-labs/devtron/api/resourceScan"
 	"github.com/devtron-labs/devtron/api/restHandler"
 	"github.com/devtron-labs/devtron/api/restHandler/app/appInfo"
@@ -111,6 +121,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/app"
 	read4 "github.com/devtron-labs/devtron/pkg/app/appDetails/read"
+	"github.com/devtron-labs/devtron/pkg/app/coreApp"
 	"github.com/devtron-labs/devtron/pkg/app/dbMigration"
 	"github.com/devtron-labs/devtron/pkg/app/status"
 	"github.com/devtron-labs/devtron/pkg/appClone"
@@ -191,6 +202,7 @@ func InitializeApp() (*App, error) {
 		wire.Bind(new(util4.K8sService), new(*util4.K8sServiceImpl)),
 		user.UserWireSet,
 		sso.SsoConfigWireSet,
+		scim.WireSet,
 		cluster.ClusterWireSet,
 		dashboard.DashboardWireSet,
 		proxy.ProxyWireSet,
@@ -200,6 +212,7 @@ func InitializeApp() (*App, error) {
 		appStoreDiscover.AppStoreDiscoverWireSet,
 		chartProvider.AppStoreChartProviderWireSet,
 		appStoreValues.WireSet,
+		upgradeAdvisor.WireSet,
 		util2.GetEnvironmentVariables,
 		appStoreDeployment.FullModeWireSet,
 		server.ServerWireSet,
@@ -218,6 +231,14 @@ func InitializeApp() (*App, error) {
 		userResource.UserResourceWireSet,
 		policyGovernance.PolicyGovernanceWireSet,
 		resourceScan.ScanningResultWireSet,
+		deploymentWindow.DeploymentWindowWireSet,
+		canaryAnalysis.CanaryAnalysisWireSet,
+		helmDrift.HelmDriftWireSet,
+		imageRetention.ImageRetentionWireSet,
+		registryPromotion.RegistryPromotionWireSet,
+		resourceQuota.ResourceQuotaWireSet,
+		appsAsCode.AppsAsCodeWireSet,
+		appTransfer.AppTransferWireSet,
 
 		// -------wireset end ----------
 		// -------
@@ -709,6 +730,8 @@ func InitializeApp() (*App, error) {
 		wire.Bind(new(router.CoreAppRouter), new(*router.CoreAppRouterImpl)),
 		restHandler.NewCoreAppRestHandlerImpl,
 		wire.Bind(new(restHandler.CoreAppRestHandler), new(*restHandler.CoreAppRestHandlerImpl)),
+		coreApp.NewCoreAppServiceImpl,
+		wire.Bind(new(coreApp.CoreAppService), new(*coreApp.CoreAppServiceImpl)),
 
 		// Webhook
 		restHandler.NewGitHostRestHandlerImpl,
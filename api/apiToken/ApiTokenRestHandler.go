@@ -36,6 +36,7 @@ type ApiTokenRestHandler interface {
 	GetAllApiTokens(w http.ResponseWriter, r *http.Request)
 	CreateApiToken(w http.ResponseWriter, r *http.Request)
 	UpdateApiToken(w http.ResponseWriter, r *http.Request)
+	RotateApiToken(w http.ResponseWriter, r *http.Request)
 	DeleteApiToken(w http.ResponseWriter, r *http.Request)
 	GetAllApiTokensForWebhook(w http.ResponseWriter, r *http.Request)
 }
@@ -179,6 +180,56 @@ func (impl ApiTokenRestHandlerImpl) UpdateApiToken(w http.ResponseWriter, r *htt
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
 
+func (impl ApiTokenRestHandlerImpl) RotateApiToken(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+
+	// handle super-admin RBAC
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+
+	// get api-token Id
+	vars := mux.Vars(r)
+	apiTokenId, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		impl.logger.Errorw("request err in getting apiTokenId in RotateApiToken", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	// decode request
+	decoder := json.NewDecoder(r.Body)
+	var request *openapi.RotateApiTokenRequest
+	err = decoder.Decode(&request)
+	if err != nil {
+		impl.logger.Errorw("err in decoding request, RotateApiToken", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	// validate request
+	err = impl.validator.Struct(request)
+	if err != nil {
+		impl.logger.Errorw("validation err in RotateApiToken", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+
+	res, err := impl.apiTokenService.RotateApiToken(apiTokenId, request, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, RotateApiToken", "err", err, "apiTokenId", apiTokenId, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, err, res, http.StatusOK)
+}
+
 func (impl ApiTokenRestHandlerImpl) DeleteApiToken(w http.ResponseWriter, r *http.Request) {
 	userId, err := impl.userService.GetLoggedInUser(r)
 	if userId == 0 || err != nil {
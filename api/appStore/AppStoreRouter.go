@@ -20,6 +20,7 @@ import (
 	"github.com/devtron-labs/devtron/api/appStore/chartProvider"
 	appStoreDeployment "github.com/devtron-labs/devtron/api/appStore/deployment"
 	appStoreDiscover "github.com/devtron-labs/devtron/api/appStore/discover"
+	"github.com/devtron-labs/devtron/api/appStore/upgradeAdvisor"
 	appStoreValues "github.com/devtron-labs/devtron/api/appStore/values"
 	"github.com/gorilla/mux"
 )
@@ -35,6 +36,7 @@ type AppStoreRouterImpl struct {
 	appStoreDeploymentRouter          appStoreDeployment.AppStoreDeploymentRouter
 	chartProviderRouter               chartProvider.ChartProviderRouter
 	appStoreStatusTimelineRestHandler AppStoreStatusTimelineRestHandler
+	upgradeAdvisorRouter              upgradeAdvisor.UpgradeAdvisorRouter
 }
 
 func NewAppStoreRouterImpl(restHandler InstalledAppRestHandler,
@@ -42,7 +44,8 @@ func NewAppStoreRouterImpl(restHandler InstalledAppRestHandler,
 	appStoreDiscoverRouter appStoreDiscover.AppStoreDiscoverRouter,
 	chartProviderRouter chartProvider.ChartProviderRouter,
 	appStoreDeploymentRouter appStoreDeployment.AppStoreDeploymentRouter,
-	appStoreStatusTimelineRestHandler AppStoreStatusTimelineRestHandler) *AppStoreRouterImpl {
+	appStoreStatusTimelineRestHandler AppStoreStatusTimelineRestHandler,
+	upgradeAdvisorRouter upgradeAdvisor.UpgradeAdvisorRouter) *AppStoreRouterImpl {
 	return &AppStoreRouterImpl{
 		deployRestHandler:                 restHandler,
 		appStoreValuesRouter:              appStoreValuesRouter,
@@ -50,6 +53,7 @@ func NewAppStoreRouterImpl(restHandler InstalledAppRestHandler,
 		chartProviderRouter:               chartProviderRouter,
 		appStoreDeploymentRouter:          appStoreDeploymentRouter,
 		appStoreStatusTimelineRestHandler: appStoreStatusTimelineRestHandler,
+		upgradeAdvisorRouter:              upgradeAdvisorRouter,
 	}
 }
 
@@ -77,6 +81,11 @@ func (router AppStoreRouterImpl) Init(configRouter *mux.Router) {
 	chartProviderSubRouter := configRouter.PathPrefix("/chart-provider").Subrouter()
 	router.chartProviderRouter.Init(chartProviderSubRouter)
 	// chart provider router ends
+
+	// upgrade advisor router starts
+	upgradeAdvisorSubRouter := configRouter.PathPrefix("/upgrade-advisor").Subrouter()
+	router.upgradeAdvisorRouter.Init(upgradeAdvisorSubRouter)
+	// upgrade advisor router ends
 	configRouter.Path("/overview").Queries("installedAppId", "{installedAppId}").
 		HandlerFunc(router.deployRestHandler.FetchAppOverview).Methods("GET")
 	configRouter.Path("/application/exists").
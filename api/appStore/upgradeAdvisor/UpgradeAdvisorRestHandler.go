@@ -0,0 +1,253 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package upgradeAdvisor
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service"
+	"github.com/devtron-labs/devtron/pkg/appStore/upgradeAdvisor"
+	"github.com/devtron-labs/devtron/pkg/appStore/upgradeAdvisor/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	util2 "github.com/devtron-labs/devtron/util"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type UpgradeAdvisorRestHandler interface {
+	GetPendingUpgrades(w http.ResponseWriter, r *http.Request)
+	GetPendingUpgrade(w http.ResponseWriter, r *http.Request)
+	GetPolicy(w http.ResponseWriter, r *http.Request)
+	SavePolicy(w http.ResponseWriter, r *http.Request)
+	DeletePolicy(w http.ResponseWriter, r *http.Request)
+	GetUpgradeRuns(w http.ResponseWriter, r *http.Request)
+}
+
+type UpgradeAdvisorRestHandlerImpl struct {
+	logger                      *zap.SugaredLogger
+	upgradeAdvisorService       upgradeAdvisor.UpgradeAdvisorService
+	appStoreDeploymentDBService service.AppStoreDeploymentDBService
+	userService                 user.UserService
+	enforcer                    casbin.Enforcer
+	enforcerUtil                rbac.EnforcerUtil
+	enforcerUtilHelm            rbac.EnforcerUtilHelm
+	validator                   *validator.Validate
+}
+
+func NewUpgradeAdvisorRestHandlerImpl(logger *zap.SugaredLogger,
+	upgradeAdvisorService upgradeAdvisor.UpgradeAdvisorService,
+	appStoreDeploymentDBService service.AppStoreDeploymentDBService,
+	userService user.UserService, enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil, enforcerUtilHelm rbac.EnforcerUtilHelm,
+	validator *validator.Validate) *UpgradeAdvisorRestHandlerImpl {
+	return &UpgradeAdvisorRestHandlerImpl{
+		logger:                      logger,
+		upgradeAdvisorService:       upgradeAdvisorService,
+		appStoreDeploymentDBService: appStoreDeploymentDBService,
+		userService:                 userService,
+		enforcer:                    enforcer,
+		enforcerUtil:                enforcerUtil,
+		enforcerUtilHelm:            enforcerUtilHelm,
+		validator:                   validator,
+	}
+}
+
+func (handler *UpgradeAdvisorRestHandlerImpl) GetPendingUpgrades(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	pendingUpgrades, err := handler.upgradeAdvisorService.GetPendingUpgrades()
+	if err != nil {
+		handler.logger.Errorw("error in fetching pending chart upgrades", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	authorizedUpgrades := make([]*bean.PendingUpgradeDto, 0, len(pendingUpgrades))
+	for _, pendingUpgrade := range pendingUpgrades {
+		if handler.checkHelmAppAccess(token, casbin.ActionGet, pendingUpgrade.AppOfferingMode, pendingUpgrade.ClusterId,
+			pendingUpgrade.Namespace, pendingUpgrade.AppName, pendingUpgrade.AppId, pendingUpgrade.EnvironmentId) {
+			authorizedUpgrades = append(authorizedUpgrades, pendingUpgrade)
+		}
+	}
+	common.WriteJsonResp(w, nil, authorizedUpgrades, http.StatusOK)
+}
+
+func (handler *UpgradeAdvisorRestHandlerImpl) GetPendingUpgrade(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	installedAppId, ok := handler.getInstalledAppIdAndAuthorize(w, r, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	targetVersionId := 0
+	if targetVersionIdParam := r.URL.Query().Get("targetVersionId"); len(targetVersionIdParam) > 0 {
+		targetVersionId, err = strconv.Atoi(targetVersionIdParam)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	pendingUpgrade, err := handler.upgradeAdvisorService.GetPendingUpgrade(installedAppId, targetVersionId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching pending chart upgrade", "installedAppId", installedAppId, "targetVersionId", targetVersionId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, pendingUpgrade, http.StatusOK)
+}
+
+func (handler *UpgradeAdvisorRestHandlerImpl) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	installedAppId, ok := handler.getInstalledAppIdAndAuthorize(w, r, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	policy, err := handler.upgradeAdvisorService.GetPolicy(installedAppId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching chart upgrade policy", "installedAppId", installedAppId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, policy, http.StatusOK)
+}
+
+func (handler *UpgradeAdvisorRestHandlerImpl) SavePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request bean.UpgradePolicyDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, SavePolicy", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, SavePolicy", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if !handler.checkInstalledAppAccess(w, r.Header.Get("token"), casbin.ActionUpdate, request.InstalledAppId) {
+		return
+	}
+	request.UserId = userId
+	resp, err := handler.upgradeAdvisorService.SavePolicy(&request)
+	if err != nil {
+		handler.logger.Errorw("error in saving chart upgrade policy", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *UpgradeAdvisorRestHandlerImpl) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	installedAppId, ok := handler.getInstalledAppIdAndAuthorize(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	err = handler.upgradeAdvisorService.DeletePolicy(installedAppId, userId)
+	if err != nil {
+		handler.logger.Errorw("error in deleting chart upgrade policy", "installedAppId", installedAppId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, nil, http.StatusOK)
+}
+
+func (handler *UpgradeAdvisorRestHandlerImpl) GetUpgradeRuns(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	installedAppId, ok := handler.getInstalledAppIdAndAuthorize(w, r, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	runs, err := handler.upgradeAdvisorService.GetUpgradeRuns(installedAppId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching chart upgrade runs", "installedAppId", installedAppId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, runs, http.StatusOK)
+}
+
+func (handler *UpgradeAdvisorRestHandlerImpl) getInstalledAppIdAndAuthorize(w http.ResponseWriter, r *http.Request, action string) (int, bool) {
+	installedAppId, err := strconv.Atoi(mux.Vars(r)["installedAppId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return 0, false
+	}
+	if !handler.checkInstalledAppAccess(w, r.Header.Get("token"), action, installedAppId) {
+		return 0, false
+	}
+	return installedAppId, true
+}
+
+// checkInstalledAppAccess enforces helm app rbac on the installed app and writes the error response if access is denied
+func (handler *UpgradeAdvisorRestHandlerImpl) checkInstalledAppAccess(w http.ResponseWriter, token string, action string, installedAppId int) bool {
+	installedApp, err := handler.appStoreDeploymentDBService.GetInstalledApp(installedAppId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching installed app", "installedAppId", installedAppId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return false
+	}
+	if !handler.checkHelmAppAccess(token, action, installedApp.AppOfferingMode, installedApp.ClusterId,
+		installedApp.Namespace, installedApp.AppName, installedApp.AppId, installedApp.EnvironmentId) {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), nil, http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func (handler *UpgradeAdvisorRestHandlerImpl) checkHelmAppAccess(token, action, appOfferingMode string, clusterId int, namespace, appName string, appId, envId int) bool {
+	var rbacObject, rbacObject2 string
+	if util2.IsHelmApp(appOfferingMode) {
+		rbacObject, rbacObject2 = handler.enforcerUtilHelm.GetHelmObjectByClusterIdNamespaceAndAppName(clusterId, namespace, appName)
+	} else {
+		rbacObject, rbacObject2 = handler.enforcerUtil.GetHelmObject(appId, envId)
+	}
+	if rbacObject2 == "" {
+		return handler.enforcer.Enforce(token, casbin.ResourceHelmApp, action, rbacObject)
+	}
+	return handler.enforcer.Enforce(token, casbin.ResourceHelmApp, action, rbacObject) || handler.enforcer.Enforce(token, casbin.ResourceHelmApp, action, rbacObject2)
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package upgradeAdvisor
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type UpgradeAdvisorRouter interface {
+	Init(configRouter *mux.Router)
+}
+
+type UpgradeAdvisorRouterImpl struct {
+	upgradeAdvisorRestHandler UpgradeAdvisorRestHandler
+}
+
+func NewUpgradeAdvisorRouterImpl(upgradeAdvisorRestHandler UpgradeAdvisorRestHandler) *UpgradeAdvisorRouterImpl {
+	return &UpgradeAdvisorRouterImpl{
+		upgradeAdvisorRestHandler: upgradeAdvisorRestHandler,
+	}
+}
+
+func (router UpgradeAdvisorRouterImpl) Init(configRouter *mux.Router) {
+	configRouter.Path("/pending").
+		HandlerFunc(router.upgradeAdvisorRestHandler.GetPendingUpgrades).Methods("GET")
+	configRouter.Path("/pending/{installedAppId}").
+		HandlerFunc(router.upgradeAdvisorRestHandler.GetPendingUpgrade).Methods("GET")
+
+	configRouter.Path("/policy").
+		HandlerFunc(router.upgradeAdvisorRestHandler.SavePolicy).Methods("PUT")
+	configRouter.Path("/policy/{installedAppId}").
+		HandlerFunc(router.upgradeAdvisorRestHandler.GetPolicy).Methods("GET")
+	configRouter.Path("/policy/{installedAppId}").
+		HandlerFunc(router.upgradeAdvisorRestHandler.DeletePolicy).Methods("DELETE")
+
+	configRouter.Path("/run/{installedAppId}").
+		HandlerFunc(router.upgradeAdvisorRestHandler.GetUpgradeRuns).Methods("GET")
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appTransfer
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/appTransfer"
+	"github.com/devtron-labs/devtron/pkg/appTransfer/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type AppTransferRestHandler interface {
+	ExportApps(w http.ResponseWriter, r *http.Request)
+	ImportApps(w http.ResponseWriter, r *http.Request)
+}
+
+type AppTransferRestHandlerImpl struct {
+	logger             *zap.SugaredLogger
+	appTransferService appTransfer.AppTransferService
+	userService        user.UserService
+	enforcer           casbin.Enforcer
+	validator          *validator.Validate
+}
+
+func NewAppTransferRestHandlerImpl(logger *zap.SugaredLogger,
+	appTransferService appTransfer.AppTransferService,
+	userService user.UserService, enforcer casbin.Enforcer,
+	validator *validator.Validate) *AppTransferRestHandlerImpl {
+	return &AppTransferRestHandlerImpl{
+		logger:             logger,
+		appTransferService: appTransferService,
+		userService:        userService,
+		enforcer:           enforcer,
+		validator:          validator,
+	}
+}
+
+func (handler *AppTransferRestHandlerImpl) ExportApps(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.checkAccess(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	var appIds []int
+	for _, appIdStr := range strings.Split(r.URL.Query().Get("appIds"), ",") {
+		if len(appIdStr) == 0 {
+			continue
+		}
+		appId, err := strconv.Atoi(appIdStr)
+		if err != nil {
+			common.WriteJsonResp(w, err, "invalid appIds", http.StatusBadRequest)
+			return
+		}
+		appIds = append(appIds, appId)
+	}
+	if len(appIds) == 0 {
+		common.WriteJsonResp(w, errors.New("appIds is required"), nil, http.StatusBadRequest)
+		return
+	}
+	resp, err := handler.appTransferService.Export(r.Context(), appIds, r.Header.Get("token"))
+	if err != nil {
+		handler.logger.Errorw("error in exporting apps", "appIds", appIds, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *AppTransferRestHandlerImpl) ImportApps(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkAccess(w, r, casbin.ActionCreate)
+	if !ok {
+		return
+	}
+	request := &bean.AppImportRequest{}
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("error in decoding app import request", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err in app import request", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	resp, err := handler.appTransferService.Import(r.Context(), request, userId, r.Header.Get("token"))
+	if err != nil {
+		handler.logger.Errorw("error in importing apps", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+// checkAccess allows exports and imports to platform admins only as the bundle spans projects and environments
+func (handler *AppTransferRestHandlerImpl) checkAccess(w http.ResponseWriter, r *http.Request, action string) (int32, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, false
+	}
+	token := r.Header.Get("token")
+	if !handler.enforcer.Enforce(token, casbin.ResourceGlobal, action, "*") {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return 0, false
+	}
+	return userId, true
+}
@@ -0,0 +1,43 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appTransfer
+
+import "github.com/gorilla/mux"
+
+type AppTransferRouter interface {
+	InitAppTransferRouter(router *mux.Router)
+}
+
+type AppTransferRouterImpl struct {
+	appTransferRestHandler AppTransferRestHandler
+}
+
+func NewAppTransferRouterImpl(appTransferRestHandler AppTransferRestHandler) *AppTransferRouterImpl {
+	return &AppTransferRouterImpl{
+		appTransferRestHandler: appTransferRestHandler,
+	}
+}
+
+func (router *AppTransferRouterImpl) InitAppTransferRouter(appTransferRouter *mux.Router) {
+	appTransferRouter.Path("/export").
+		HandlerFunc(router.appTransferRestHandler.ExportApps).
+		Methods("GET")
+
+	appTransferRouter.Path("/import").
+		HandlerFunc(router.appTransferRestHandler.ImportApps).
+		Methods("POST")
+}
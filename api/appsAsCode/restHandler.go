@@ -0,0 +1,229 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appsAsCode
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/appsAsCode"
+	"github.com/devtron-labs/devtron/pkg/appsAsCode/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type AppsAsCodeRestHandler interface {
+	GetAllSources(w http.ResponseWriter, r *http.Request)
+	SaveSource(w http.ResponseWriter, r *http.Request)
+	DeleteSource(w http.ResponseWriter, r *http.Request)
+	Plan(w http.ResponseWriter, r *http.Request)
+	Apply(w http.ResponseWriter, r *http.Request)
+	GetDriftReport(w http.ResponseWriter, r *http.Request)
+	GetAppStatus(w http.ResponseWriter, r *http.Request)
+}
+
+type AppsAsCodeRestHandlerImpl struct {
+	logger            *zap.SugaredLogger
+	appsAsCodeService appsAsCode.AppsAsCodeService
+	userService       user.UserService
+	enforcer          casbin.Enforcer
+	enforcerUtil      rbac.EnforcerUtil
+	validator         *validator.Validate
+}
+
+func NewAppsAsCodeRestHandlerImpl(logger *zap.SugaredLogger,
+	appsAsCodeService appsAsCode.AppsAsCodeService,
+	userService user.UserService, enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil,
+	validator *validator.Validate) *AppsAsCodeRestHandlerImpl {
+	return &AppsAsCodeRestHandlerImpl{
+		logger:            logger,
+		appsAsCodeService: appsAsCodeService,
+		userService:       userService,
+		enforcer:          enforcer,
+		enforcerUtil:      enforcerUtil,
+		validator:         validator,
+	}
+}
+
+func (handler *AppsAsCodeRestHandlerImpl) GetAllSources(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.checkAccess(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	resp, err := handler.appsAsCodeService.GetAllSources()
+	if err != nil {
+		handler.logger.Errorw("error in fetching apps as code sources", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *AppsAsCodeRestHandlerImpl) SaveSource(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkAccess(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	request := &bean.AppCodeSourceDto{}
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("error in decoding apps as code source request", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err in apps as code source request", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	resp, err := handler.appsAsCodeService.SaveSource(request)
+	if err != nil {
+		handler.logger.Errorw("error in saving apps as code source", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *AppsAsCodeRestHandlerImpl) DeleteSource(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkAccess(w, r, casbin.ActionDelete)
+	if !ok {
+		return
+	}
+	id, ok := handler.getSourceId(w, r)
+	if !ok {
+		return
+	}
+	err := handler.appsAsCodeService.DeleteSource(id, userId)
+	if err != nil {
+		handler.logger.Errorw("error in deleting apps as code source", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, id, http.StatusOK)
+}
+
+func (handler *AppsAsCodeRestHandlerImpl) Plan(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.checkAccess(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	id, ok := handler.getSourceId(w, r)
+	if !ok {
+		return
+	}
+	resp, err := handler.appsAsCodeService.Plan(r.Context(), id, r.Header.Get("token"))
+	if err != nil {
+		handler.logger.Errorw("error in planning apps as code source", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *AppsAsCodeRestHandlerImpl) Apply(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkAccess(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	id, ok := handler.getSourceId(w, r)
+	if !ok {
+		return
+	}
+	resp, err := handler.appsAsCodeService.Apply(r.Context(), id, userId, r.Header.Get("token"))
+	if err != nil {
+		handler.logger.Errorw("error in applying apps as code source", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *AppsAsCodeRestHandlerImpl) GetDriftReport(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.checkAccess(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	id, ok := handler.getSourceId(w, r)
+	if !ok {
+		return
+	}
+	resp, err := handler.appsAsCodeService.GetDriftReport(r.Context(), id, r.Header.Get("token"))
+	if err != nil {
+		handler.logger.Errorw("error in generating apps as code drift report", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+// GetAppStatus tells the app configuration screens whether the app is reconciled from git and locked
+func (handler *AppsAsCodeRestHandlerImpl) GetAppStatus(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, err := strconv.Atoi(mux.Vars(r)["appId"])
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid appId", http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if !handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	resp, err := handler.appsAsCodeService.GetAppStatus(appId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching apps as code status of app", "appId", appId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *AppsAsCodeRestHandlerImpl) getSourceId(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid id", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// checkAccess allows sources, plans and applies to platform admins only as they span any app of the repository
+func (handler *AppsAsCodeRestHandlerImpl) checkAccess(w http.ResponseWriter, r *http.Request, action string) (int32, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, false
+	}
+	token := r.Header.Get("token")
+	if !handler.enforcer.Enforce(token, casbin.ResourceGlobal, action, "*") {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return 0, false
+	}
+	return userId, true
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appsAsCode
+
+import "github.com/gorilla/mux"
+
+type AppsAsCodeRouter interface {
+	InitAppsAsCodeRouter(router *mux.Router)
+}
+
+type AppsAsCodeRouterImpl struct {
+	appsAsCodeRestHandler AppsAsCodeRestHandler
+}
+
+func NewAppsAsCodeRouterImpl(appsAsCodeRestHandler AppsAsCodeRestHandler) *AppsAsCodeRouterImpl {
+	return &AppsAsCodeRouterImpl{
+		appsAsCodeRestHandler: appsAsCodeRestHandler,
+	}
+}
+
+func (router *AppsAsCodeRouterImpl) InitAppsAsCodeRouter(appsAsCodeRouter *mux.Router) {
+	appsAsCodeRouter.Path("/source").
+		HandlerFunc(router.appsAsCodeRestHandler.GetAllSources).
+		Methods("GET")
+
+	appsAsCodeRouter.Path("/source").
+		HandlerFunc(router.appsAsCodeRestHandler.SaveSource).
+		Methods("POST")
+
+	appsAsCodeRouter.Path("/source/{id}").
+		HandlerFunc(router.appsAsCodeRestHandler.DeleteSource).
+		Methods("DELETE")
+
+	appsAsCodeRouter.Path("/source/{id}/plan").
+		HandlerFunc(router.appsAsCodeRestHandler.Plan).
+		Methods("GET")
+
+	appsAsCodeRouter.Path("/source/{id}/apply").
+		HandlerFunc(router.appsAsCodeRestHandler.Apply).
+		Methods("POST")
+
+	appsAsCodeRouter.Path("/source/{id}/drift").
+		HandlerFunc(router.appsAsCodeRestHandler.GetDriftReport).
+		Methods("GET")
+
+	appsAsCodeRouter.Path("/app/{appId}/status").
+		HandlerFunc(router.appsAsCodeRestHandler.GetAppStatus).
+		Methods("GET")
+}
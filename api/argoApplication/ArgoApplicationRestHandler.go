@@ -18,11 +18,15 @@ package argoApplication
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/pkg/argoApplication"
+	"github.com/devtron-labs/devtron/pkg/argoApplication/bean"
+	"github.com/devtron-labs/devtron/pkg/argoApplication/helper"
 	"github.com/devtron-labs/devtron/pkg/argoApplication/read"
 	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
 	"go.uber.org/zap"
 	"net/http"
 	"strconv"
@@ -32,6 +36,10 @@ import (
 type ArgoApplicationRestHandler interface {
 	ListApplications(w http.ResponseWriter, r *http.Request)
 	GetApplicationDetail(w http.ResponseWriter, r *http.Request)
+	SyncApplication(w http.ResponseWriter, r *http.Request)
+	RefreshApplication(w http.ResponseWriter, r *http.Request)
+	RollbackApplication(w http.ResponseWriter, r *http.Request)
+	GetApplicationDiff(w http.ResponseWriter, r *http.Request)
 }
 
 type ArgoApplicationRestHandlerImpl struct {
@@ -39,15 +47,18 @@ type ArgoApplicationRestHandlerImpl struct {
 	readService            read.ArgoApplicationReadService
 	logger                 *zap.SugaredLogger
 	enforcer               casbin.Enforcer
+	userService            user.UserService
 }
 
 func NewArgoApplicationRestHandlerImpl(argoApplicationService argoApplication.ArgoApplicationService,
-	readService read.ArgoApplicationReadService, logger *zap.SugaredLogger, enforcer casbin.Enforcer) *ArgoApplicationRestHandlerImpl {
+	readService read.ArgoApplicationReadService, logger *zap.SugaredLogger, enforcer casbin.Enforcer,
+	userService user.UserService) *ArgoApplicationRestHandlerImpl {
 	return &ArgoApplicationRestHandlerImpl{
 		argoApplicationService: argoApplicationService,
 		readService:            readService,
 		logger:                 logger,
 		enforcer:               enforcer,
+		userService:            userService,
 	}
 
 }
@@ -116,3 +127,112 @@ func (handler *ArgoApplicationRestHandlerImpl) GetApplicationDetail(w http.Respo
 	}
 	common.WriteJsonResp(w, nil, resp, http.StatusOK)
 }
+
+func (handler *ArgoApplicationRestHandlerImpl) SyncApplication(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request := &bean.ArgoAppSyncRequest{}
+	appIdentifier, ok := handler.decodeActionRequest(w, r, request, func() string { return request.AppId })
+	if !ok {
+		return
+	}
+	request.UserId = userId
+	err = handler.argoApplicationService.SyncArgoApplication(r.Context(), appIdentifier, request)
+	if err != nil {
+		handler.logger.Errorw("error in syncing argo application", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, nil, http.StatusOK)
+}
+
+func (handler *ArgoApplicationRestHandlerImpl) RefreshApplication(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request := &bean.ArgoAppRefreshRequest{}
+	appIdentifier, ok := handler.decodeActionRequest(w, r, request, func() string { return request.AppId })
+	if !ok {
+		return
+	}
+	request.UserId = userId
+	err = handler.argoApplicationService.RefreshArgoApplication(r.Context(), appIdentifier, request)
+	if err != nil {
+		handler.logger.Errorw("error in refreshing argo application", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, nil, http.StatusOK)
+}
+
+func (handler *ArgoApplicationRestHandlerImpl) RollbackApplication(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request := &bean.ArgoAppRollbackRequest{}
+	appIdentifier, ok := handler.decodeActionRequest(w, r, request, func() string { return request.AppId })
+	if !ok {
+		return
+	}
+	if request.HistoryId <= 0 {
+		common.WriteJsonResp(w, errors.New("historyId is required"), nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	err = handler.argoApplicationService.RollbackArgoApplication(r.Context(), appIdentifier, request)
+	if err != nil {
+		handler.logger.Errorw("error in rolling back argo application", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, nil, http.StatusOK)
+}
+
+func (handler *ArgoApplicationRestHandlerImpl) GetApplicationDiff(w http.ResponseWriter, r *http.Request) {
+	// handle super-admin RBAC
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	appIdentifier, err := helper.DecodeExternalArgoAppId(r.URL.Query().Get("appId"))
+	if err != nil {
+		common.WriteJsonResp(w, err, "please send valid appId", http.StatusBadRequest)
+		return
+	}
+	resp, err := handler.argoApplicationService.GetArgoApplicationDiff(r.Context(), appIdentifier)
+	if err != nil {
+		handler.logger.Errorw("error in getting argo application diff", "appIdentifier", appIdentifier, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+// decodeActionRequest enforces super-admin RBAC for actions on external argo apps, decodes the request and the app id in it
+func (handler *ArgoApplicationRestHandlerImpl) decodeActionRequest(w http.ResponseWriter, r *http.Request, request interface{}, getAppId func() string) (*bean.ArgoAppIdentifier, bool) {
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return nil, false
+	}
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("error in decoding argo application action request", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	appIdentifier, err := helper.DecodeExternalArgoAppId(getAppId())
+	if err != nil {
+		common.WriteJsonResp(w, err, "please send valid appId", http.StatusBadRequest)
+		return nil, false
+	}
+	return appIdentifier, true
+}
@@ -40,4 +40,20 @@ func (impl *ArgoApplicationRouterImpl) InitArgoApplicationRouter(argoApplication
 	argoApplicationRouter.Path("/detail").
 		Methods("GET").
 		HandlerFunc(impl.argoApplicationRestHandler.GetApplicationDetail)
+
+	argoApplicationRouter.Path("/sync").
+		Methods("POST").
+		HandlerFunc(impl.argoApplicationRestHandler.SyncApplication)
+
+	argoApplicationRouter.Path("/refresh").
+		Methods("POST").
+		HandlerFunc(impl.argoApplicationRestHandler.RefreshApplication)
+
+	argoApplicationRouter.Path("/rollback").
+		Methods("POST").
+		HandlerFunc(impl.argoApplicationRestHandler.RollbackApplication)
+
+	argoApplicationRouter.Path("/diff").
+		Methods("GET").
+		HandlerFunc(impl.argoApplicationRestHandler.GetApplicationDiff)
 }
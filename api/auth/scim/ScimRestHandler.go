@@ -0,0 +1,342 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/scim"
+	"github.com/devtron-labs/devtron/pkg/auth/scim/bean"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ScimRestHandler serves the scim apis to identity providers. These apis are outside the auth middleware as
+// identity providers send the api token as a bearer token, ScimService.Authenticate verifies it instead.
+type ScimRestHandler interface {
+	GetServiceProviderConfig(w http.ResponseWriter, r *http.Request)
+
+	ListUsers(w http.ResponseWriter, r *http.Request)
+	GetUser(w http.ResponseWriter, r *http.Request)
+	CreateUser(w http.ResponseWriter, r *http.Request)
+	ReplaceUser(w http.ResponseWriter, r *http.Request)
+	PatchUser(w http.ResponseWriter, r *http.Request)
+	DeleteUser(w http.ResponseWriter, r *http.Request)
+
+	ListGroups(w http.ResponseWriter, r *http.Request)
+	GetGroup(w http.ResponseWriter, r *http.Request)
+	CreateGroup(w http.ResponseWriter, r *http.Request)
+	ReplaceGroup(w http.ResponseWriter, r *http.Request)
+	PatchGroup(w http.ResponseWriter, r *http.Request)
+	DeleteGroup(w http.ResponseWriter, r *http.Request)
+}
+
+type ScimRestHandlerImpl struct {
+	logger      *zap.SugaredLogger
+	scimService scim.ScimService
+}
+
+func NewScimRestHandlerImpl(logger *zap.SugaredLogger, scimService scim.ScimService) *ScimRestHandlerImpl {
+	return &ScimRestHandlerImpl{
+		logger:      logger,
+		scimService: scimService,
+	}
+}
+
+func (handler *ScimRestHandlerImpl) GetServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.authenticate(w, r); !ok {
+		return
+	}
+	writeResponse(w, bean.GetServiceProviderConfig(), http.StatusOK)
+}
+
+func (handler *ScimRestHandlerImpl) ListUsers(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.authenticate(w, r); !ok {
+		return
+	}
+	request, ok := handler.getListRequest(w, r)
+	if !ok {
+		return
+	}
+	response, err := handler.scimService.ListUsers(request)
+	if err != nil {
+		handler.logger.Errorw("error in listing scim users", "filter", request.Filter, "err", err)
+		writeError(w, err)
+		return
+	}
+	writeResponse(w, response, http.StatusOK)
+}
+
+func (handler *ScimRestHandlerImpl) GetUser(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.authenticate(w, r); !ok {
+		return
+	}
+	id := mux.Vars(r)["id"]
+	scimUser, err := handler.scimService.GetUser(id)
+	if err != nil {
+		handler.logger.Errorw("error in getting scim user", "id", id, "err", err)
+		writeError(w, err)
+		return
+	}
+	writeResponse(w, scimUser, http.StatusOK)
+}
+
+func (handler *ScimRestHandlerImpl) CreateUser(w http.ResponseWriter, r *http.Request) {
+	actor, ok := handler.authenticate(w, r)
+	if !ok {
+		return
+	}
+	scimUser := &bean.User{}
+	if !decodeRequest(w, r, scimUser) {
+		return
+	}
+	handler.logger.Infow("request payload, scim CreateUser", "payload", scimUser)
+	createdUser, err := handler.scimService.CreateUser(r.Context(), actor, scimUser)
+	if err != nil {
+		handler.logger.Errorw("error in creating scim user", "userName", scimUser.UserName, "err", err)
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Location", createdUser.Meta.Location)
+	writeResponse(w, createdUser, http.StatusCreated)
+}
+
+func (handler *ScimRestHandlerImpl) ReplaceUser(w http.ResponseWriter, r *http.Request) {
+	actor, ok := handler.authenticate(w, r)
+	if !ok {
+		return
+	}
+	scimUser := &bean.User{}
+	if !decodeRequest(w, r, scimUser) {
+		return
+	}
+	id := mux.Vars(r)["id"]
+	handler.logger.Infow("request payload, scim ReplaceUser", "id", id, "payload", scimUser)
+	updatedUser, err := handler.scimService.ReplaceUser(r.Context(), actor, id, scimUser)
+	if err != nil {
+		handler.logger.Errorw("error in replacing scim user", "id", id, "err", err)
+		writeError(w, err)
+		return
+	}
+	writeResponse(w, updatedUser, http.StatusOK)
+}
+
+func (handler *ScimRestHandlerImpl) PatchUser(w http.ResponseWriter, r *http.Request) {
+	actor, ok := handler.authenticate(w, r)
+	if !ok {
+		return
+	}
+	request := &bean.PatchRequest{}
+	if !decodeRequest(w, r, request) {
+		return
+	}
+	id := mux.Vars(r)["id"]
+	handler.logger.Infow("request payload, scim PatchUser", "id", id, "payload", request)
+	updatedUser, err := handler.scimService.PatchUser(r.Context(), actor, id, request)
+	if err != nil {
+		handler.logger.Errorw("error in patching scim user", "id", id, "err", err)
+		writeError(w, err)
+		return
+	}
+	writeResponse(w, updatedUser, http.StatusOK)
+}
+
+func (handler *ScimRestHandlerImpl) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	actor, ok := handler.authenticate(w, r)
+	if !ok {
+		return
+	}
+	id := mux.Vars(r)["id"]
+	err := handler.scimService.DeleteUser(r.Context(), actor, id)
+	if err != nil {
+		handler.logger.Errorw("error in deleting scim user", "id", id, "err", err)
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (handler *ScimRestHandlerImpl) ListGroups(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.authenticate(w, r); !ok {
+		return
+	}
+	request, ok := handler.getListRequest(w, r)
+	if !ok {
+		return
+	}
+	response, err := handler.scimService.ListGroups(request)
+	if err != nil {
+		handler.logger.Errorw("error in listing scim groups", "filter", request.Filter, "err", err)
+		writeError(w, err)
+		return
+	}
+	writeResponse(w, response, http.StatusOK)
+}
+
+func (handler *ScimRestHandlerImpl) GetGroup(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.authenticate(w, r); !ok {
+		return
+	}
+	id := mux.Vars(r)["id"]
+	group, err := handler.scimService.GetGroup(id)
+	if err != nil {
+		handler.logger.Errorw("error in getting scim group", "id", id, "err", err)
+		writeError(w, err)
+		return
+	}
+	writeResponse(w, group, http.StatusOK)
+}
+
+func (handler *ScimRestHandlerImpl) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	actor, ok := handler.authenticate(w, r)
+	if !ok {
+		return
+	}
+	group := &bean.Group{}
+	if !decodeRequest(w, r, group) {
+		return
+	}
+	handler.logger.Infow("request payload, scim CreateGroup", "payload", group)
+	createdGroup, err := handler.scimService.CreateGroup(actor, group)
+	if err != nil {
+		handler.logger.Errorw("error in creating scim group", "displayName", group.DisplayName, "err", err)
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Location", createdGroup.Meta.Location)
+	writeResponse(w, createdGroup, http.StatusCreated)
+}
+
+func (handler *ScimRestHandlerImpl) ReplaceGroup(w http.ResponseWriter, r *http.Request) {
+	actor, ok := handler.authenticate(w, r)
+	if !ok {
+		return
+	}
+	group := &bean.Group{}
+	if !decodeRequest(w, r, group) {
+		return
+	}
+	id := mux.Vars(r)["id"]
+	handler.logger.Infow("request payload, scim ReplaceGroup", "id", id, "payload", group)
+	updatedGroup, err := handler.scimService.ReplaceGroup(actor, id, group)
+	if err != nil {
+		handler.logger.Errorw("error in replacing scim group", "id", id, "err", err)
+		writeError(w, err)
+		return
+	}
+	writeResponse(w, updatedGroup, http.StatusOK)
+}
+
+func (handler *ScimRestHandlerImpl) PatchGroup(w http.ResponseWriter, r *http.Request) {
+	actor, ok := handler.authenticate(w, r)
+	if !ok {
+		return
+	}
+	request := &bean.PatchRequest{}
+	if !decodeRequest(w, r, request) {
+		return
+	}
+	id := mux.Vars(r)["id"]
+	handler.logger.Infow("request payload, scim PatchGroup", "id", id, "payload", request)
+	updatedGroup, err := handler.scimService.PatchGroup(actor, id, request)
+	if err != nil {
+		handler.logger.Errorw("error in patching scim group", "id", id, "err", err)
+		writeError(w, err)
+		return
+	}
+	writeResponse(w, updatedGroup, http.StatusOK)
+}
+
+func (handler *ScimRestHandlerImpl) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	actor, ok := handler.authenticate(w, r)
+	if !ok {
+		return
+	}
+	id := mux.Vars(r)["id"]
+	err := handler.scimService.DeleteGroup(actor, id)
+	if err != nil {
+		handler.logger.Errorw("error in deleting scim group", "id", id, "err", err)
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authenticate reads the bearer token, the token header is accepted as well for clients configured like other apis
+func (handler *ScimRestHandlerImpl) authenticate(w http.ResponseWriter, r *http.Request) (*bean.Actor, bool) {
+	token := r.Header.Get("token")
+	if authorization := r.Header.Get("Authorization"); len(authorization) > 0 {
+		token = strings.TrimSpace(strings.TrimPrefix(authorization, "Bearer"))
+	}
+	actor, err := handler.scimService.Authenticate(r.Context(), token)
+	if err != nil {
+		writeError(w, err)
+		return nil, false
+	}
+	return actor, true
+}
+
+func (handler *ScimRestHandlerImpl) getListRequest(w http.ResponseWriter, r *http.Request) (*bean.ListRequest, bool) {
+	query := r.URL.Query()
+	request := &bean.ListRequest{Filter: query.Get("filter")}
+	var err error
+	if startIndex := query.Get("startIndex"); len(startIndex) > 0 {
+		request.StartIndex, err = strconv.Atoi(startIndex)
+	}
+	if count := query.Get("count"); err == nil && len(count) > 0 {
+		request.Count, err = strconv.Atoi(count)
+	}
+	if err != nil {
+		writeError(w, bean.NewError(http.StatusBadRequest, bean.ErrorTypeInvalidValue, fmt.Sprintf("invalid pagination, %s", err.Error())))
+		return nil, false
+	}
+	return request, true
+}
+
+func decodeRequest(w http.ResponseWriter, r *http.Request, request interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		writeError(w, bean.NewError(http.StatusBadRequest, bean.ErrorTypeInvalidSyntax, err.Error()))
+		return false
+	}
+	return true
+}
+
+func writeResponse(w http.ResponseWriter, response interface{}, status int) {
+	w.Header().Set("Content-Type", bean.ContentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// writeError sends errors in the scim error schema, errors of devtron services keep their http status
+func writeError(w http.ResponseWriter, err error) {
+	var scimErr *bean.Error
+	if !errors.As(err, &scimErr) {
+		statusCode := http.StatusInternalServerError
+		var apiErr *util.ApiError
+		if errors.As(err, &apiErr) && apiErr.HttpStatusCode > 0 {
+			statusCode = apiErr.HttpStatusCode
+		}
+		scimErr = bean.NewError(statusCode, "", err.Error())
+	}
+	writeResponse(w, scimErr, scimErr.StatusCode)
+}
@@ -61,6 +61,19 @@ type UserRestHandler interface {
 	UpdateTriggerPolicyForTerminalAccess(w http.ResponseWriter, r *http.Request)
 	GetRoleCacheDump(w http.ResponseWriter, r *http.Request)
 	InvalidateRoleCache(w http.ResponseWriter, r *http.Request)
+	CreateAccessRequest(w http.ResponseWriter, r *http.Request)
+	GetAccessRequests(w http.ResponseWriter, r *http.Request)
+	GetAccessRequestById(w http.ResponseWriter, r *http.Request)
+	ApproveAccessRequest(w http.ResponseWriter, r *http.Request)
+	RejectAccessRequest(w http.ResponseWriter, r *http.Request)
+	CancelAccessRequest(w http.ResponseWriter, r *http.Request)
+	GetRoleGroupClaimMappings(w http.ResponseWriter, r *http.Request)
+	CreateRoleGroupClaimMapping(w http.ResponseWriter, r *http.Request)
+	DeleteRoleGroupClaimMapping(w http.ResponseWriter, r *http.Request)
+	ExplainPermission(w http.ResponseWriter, r *http.Request)
+	GetEffectivePermissions(w http.ResponseWriter, r *http.Request)
+	GetPermissionAudits(w http.ResponseWriter, r *http.Request)
+	ExportPermissionAudits(w http.ResponseWriter, r *http.Request)
 }
 
 type userNamePassword struct {
@@ -76,12 +89,21 @@ type UserRestHandlerImpl struct {
 	roleGroupService    user2.RoleGroupService
 	userCommonService   user2.UserCommonService
 	rbacEnforcementUtil commonEnforcementFunctionsUtil.CommonEnforcementUtil
+
+	roleAccessRequestService user2.RoleAccessRequestService
+	groupClaimsSyncService   user2.GroupClaimsSyncService
+	rbacExplainService       user2.RbacExplainService
+	permissionAuditService   user2.UserPermissionAuditService
 }
 
 func NewUserRestHandlerImpl(userService user2.UserService, validator *validator.Validate,
 	logger *zap.SugaredLogger, enforcer casbin.Enforcer, roleGroupService user2.RoleGroupService,
 	userCommonService user2.UserCommonService,
-	rbacEnforcementUtil commonEnforcementFunctionsUtil.CommonEnforcementUtil) *UserRestHandlerImpl {
+	rbacEnforcementUtil commonEnforcementFunctionsUtil.CommonEnforcementUtil,
+	roleAccessRequestService user2.RoleAccessRequestService,
+	groupClaimsSyncService user2.GroupClaimsSyncService,
+	rbacExplainService user2.RbacExplainService,
+	permissionAuditService user2.UserPermissionAuditService) *UserRestHandlerImpl {
 	userAuthHandler := &UserRestHandlerImpl{
 		userService:         userService,
 		validator:           validator,
@@ -90,6 +112,11 @@ func NewUserRestHandlerImpl(userService user2.UserService, validator *validator.
 		roleGroupService:    roleGroupService,
 		userCommonService:   userCommonService,
 		rbacEnforcementUtil: rbacEnforcementUtil,
+
+		roleAccessRequestService: roleAccessRequestService,
+		groupClaimsSyncService:   groupClaimsSyncService,
+		rbacExplainService:       rbacExplainService,
+		permissionAuditService:   permissionAuditService,
 	}
 	return userAuthHandler
 }
@@ -881,3 +908,413 @@ func (handler UserRestHandlerImpl) CheckManagerAuth(resource, token string, obje
 	return true
 
 }
+
+func (handler UserRestHandlerImpl) CreateAccessRequest(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request bean2.RoleAccessRequestDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, CreateAccessRequest", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	handler.logger.Infow("request payload, CreateAccessRequest", "payload", request)
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, CreateAccessRequest", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// any logged in user can ask for access, rbac is checked against the reviewer
+	res, err := handler.roleAccessRequestService.CreateRequest(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, CreateAccessRequest", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// GetAccessRequests returns the requests raised by the logged in user and the ones they are allowed to review
+func (handler UserRestHandlerImpl) GetAccessRequests(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var statuses []bean2.RoleAccessRequestStatus
+	if status := r.URL.Query().Get("status"); len(status) > 0 {
+		for _, item := range strings.Split(status, ",") {
+			statuses = append(statuses, bean2.RoleAccessRequestStatus(strings.ToUpper(strings.TrimSpace(item))))
+		}
+	}
+	requests, err := handler.roleAccessRequestService.GetRequests(0, statuses)
+	if err != nil {
+		handler.logger.Errorw("service err, GetAccessRequests", "err", err, "statuses", statuses)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	res := make([]*bean2.RoleAccessRequestDto, 0, len(requests))
+	for _, request := range requests {
+		if request.UserId != userId {
+			isAuthorised, err := handler.checkRBACForUserCreate(token, false, request.RoleFilters, request.UserRoleGroups)
+			if err != nil {
+				handler.logger.Errorw("error in checking rbac for access request", "err", err, "id", request.Id)
+				common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+				return
+			}
+			if !isAuthorised {
+				continue
+			}
+		}
+		res = append(res, request)
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler UserRestHandlerImpl) GetAccessRequestById(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		handler.logger.Errorw("request err, GetAccessRequestById", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	res, err := handler.roleAccessRequestService.GetRequestById(id)
+	if err != nil {
+		handler.logger.Errorw("service err, GetAccessRequestById", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if res.UserId != userId {
+		isAuthorised, err := handler.checkRBACForUserCreate(r.Header.Get("token"), false, res.RoleFilters, res.UserRoleGroups)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+			return
+		}
+		if !isAuthorised {
+			response.WriteResponse(http.StatusForbidden, "FORBIDDEN", w, errors.New("unauthorized"))
+			return
+		}
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler UserRestHandlerImpl) ApproveAccessRequest(w http.ResponseWriter, r *http.Request) {
+	review, token, ok := handler.decodeAndAuthoriseAccessRequestReview(w, r, "ApproveAccessRequest")
+	if !ok {
+		return
+	}
+	res, err := handler.roleAccessRequestService.ApproveRequest(review, token, handler.CheckManagerAuth)
+	if err != nil {
+		handler.logger.Errorw("service err, ApproveAccessRequest", "err", err, "payload", review)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler UserRestHandlerImpl) RejectAccessRequest(w http.ResponseWriter, r *http.Request) {
+	review, _, ok := handler.decodeAndAuthoriseAccessRequestReview(w, r, "RejectAccessRequest")
+	if !ok {
+		return
+	}
+	res, err := handler.roleAccessRequestService.RejectRequest(review)
+	if err != nil {
+		handler.logger.Errorw("service err, RejectAccessRequest", "err", err, "payload", review)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler UserRestHandlerImpl) CancelAccessRequest(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var review bean2.RoleAccessRequestReviewDto
+	err = json.NewDecoder(r.Body).Decode(&review)
+	if err != nil {
+		handler.logger.Errorw("request err, CancelAccessRequest", "err", err, "payload", review)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	review.UserId = userId
+	err = handler.validator.Struct(review)
+	if err != nil {
+		handler.logger.Errorw("validation err, CancelAccessRequest", "err", err, "payload", review)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// only the requester can cancel, checked in service
+	res, err := handler.roleAccessRequestService.CancelRequest(&review)
+	if err != nil {
+		handler.logger.Errorw("service err, CancelAccessRequest", "err", err, "payload", review)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// decodeAndAuthoriseAccessRequestReview allows the review only if the reviewer could have granted the requested access themselves
+func (handler UserRestHandlerImpl) decodeAndAuthoriseAccessRequestReview(w http.ResponseWriter, r *http.Request, caller string) (*bean2.RoleAccessRequestReviewDto, string, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return nil, "", false
+	}
+	var review bean2.RoleAccessRequestReviewDto
+	err = json.NewDecoder(r.Body).Decode(&review)
+	if err != nil {
+		handler.logger.Errorw("request err, "+caller, "err", err, "payload", review)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, "", false
+	}
+	review.UserId = userId
+	err = handler.validator.Struct(review)
+	if err != nil {
+		handler.logger.Errorw("validation err, "+caller, "err", err, "payload", review)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, "", false
+	}
+	request, err := handler.roleAccessRequestService.GetRequestById(review.Id)
+	if err != nil {
+		handler.logger.Errorw("service err, "+caller, "err", err, "payload", review)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return nil, "", false
+	}
+	token := r.Header.Get("token")
+	isAuthorised, err := handler.checkRBACForUserCreate(token, false, request.RoleFilters, request.UserRoleGroups)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return nil, "", false
+	}
+	if !isAuthorised {
+		response.WriteResponse(http.StatusForbidden, "FORBIDDEN", w, errors.New("unauthorized"))
+		return nil, "", false
+	}
+	return &review, token, true
+}
+
+func (handler UserRestHandlerImpl) GetRoleGroupClaimMappings(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	res, err := handler.groupClaimsSyncService.GetMappings()
+	if err != nil {
+		handler.logger.Errorw("service err, GetRoleGroupClaimMappings", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler UserRestHandlerImpl) CreateRoleGroupClaimMapping(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request bean2.RoleGroupClaimMappingDto
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, CreateRoleGroupClaimMapping", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, CreateRoleGroupClaimMapping", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	// group claims can grant any role group, so only super admin can map them
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	res, err := handler.groupClaimsSyncService.CreateMapping(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, CreateRoleGroupClaimMapping", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler UserRestHandlerImpl) DeleteRoleGroupClaimMapping(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		handler.logger.Errorw("request err, DeleteRoleGroupClaimMapping", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*"); !isSuperAdmin {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	err = handler.groupClaimsSyncService.DeleteMapping(id, userId)
+	if err != nil {
+		handler.logger.Errorw("service err, DeleteRoleGroupClaimMapping", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, true, http.StatusOK)
+}
+
+func (handler UserRestHandlerImpl) ExplainPermission(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	var request bean2.RbacExplainRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("request err, ExplainPermission", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err, ExplainPermission", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if ok := handler.checkRbacForPermissionInspection(w, r, userId, &request.RbacSubjectDto); !ok {
+		return
+	}
+	res, err := handler.rbacExplainService.Explain(&request)
+	if err != nil {
+		handler.logger.Errorw("service err, ExplainPermission", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler UserRestHandlerImpl) GetEffectivePermissions(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request := &bean2.RbacSubjectDto{}
+	err = schema.NewDecoder().Decode(request, r.URL.Query())
+	if err != nil {
+		handler.logger.Errorw("request err, GetEffectivePermissions", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if ok := handler.checkRbacForPermissionInspection(w, r, userId, request); !ok {
+		return
+	}
+	res, err := handler.rbacExplainService.GetEffectivePermissions(request)
+	if err != nil {
+		handler.logger.Errorw("service err, GetEffectivePermissions", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler UserRestHandlerImpl) GetPermissionAudits(w http.ResponseWriter, r *http.Request) {
+	filter, ok := handler.getPermissionAuditFilter(w, r)
+	if !ok {
+		return
+	}
+	res, err := handler.permissionAuditService.GetAudits(filter)
+	if err != nil {
+		handler.logger.Errorw("service err, GetPermissionAudits", "err", err, "payload", filter)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (handler UserRestHandlerImpl) ExportPermissionAudits(w http.ResponseWriter, r *http.Request) {
+	filter, ok := handler.getPermissionAuditFilter(w, r)
+	if !ok {
+		return
+	}
+	res, err := handler.permissionAuditService.ExportAudits(filter)
+	if err != nil {
+		handler.logger.Errorw("service err, ExportPermissionAudits", "err", err, "payload", filter)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteOctetStreamResp(w, r, res, bean2.PermissionAuditExportFileName)
+}
+
+// getPermissionAuditFilter checks super admin access and decodes the filter, writes the response itself on failure
+func (handler UserRestHandlerImpl) getPermissionAuditFilter(w http.ResponseWriter, r *http.Request) (*bean2.PermissionAuditFilter, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return nil, false
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return nil, false
+	}
+	filter := &bean2.PermissionAuditFilter{}
+	err = schema.NewDecoder().Decode(filter, r.URL.Query())
+	if err != nil {
+		handler.logger.Errorw("request err, getPermissionAuditFilter", "err", err, "payload", filter)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	return filter, true
+}
+
+// checkRbacForPermissionInspection lets users inspect their own access, inspecting others needs super admin access or
+// user management access across all projects. Writes the response itself when the subject can not be resolved or the
+// user is not authorised.
+func (handler UserRestHandlerImpl) checkRbacForPermissionInspection(w http.ResponseWriter, r *http.Request, userId int32, subject *bean2.RbacSubjectDto) bool {
+	subjectUserId, _, err := handler.rbacExplainService.ResolveSubject(subject)
+	if err != nil {
+		handler.logger.Errorw("service err, ResolveSubject", "err", err, "subject", subject)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return false
+	}
+	if subjectUserId == userId {
+		return true
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*")
+	if !isSuperAdmin && !handler.enforcer.Enforce(token, casbin.ResourceUser, casbin.ActionGet, "*") {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return false
+	}
+	//RBAC enforcer Ends
+	return true
+}
@@ -36,6 +36,20 @@ func NewUserRouterImpl(userRestHandler UserRestHandler) *UserRouterImpl {
 }
 
 func (router UserRouterImpl) InitUserRouter(userAuthRouter *mux.Router) {
+	//Just in time access requests
+	userAuthRouter.Path("/access/request").
+		HandlerFunc(router.userRestHandler.CreateAccessRequest).Methods("POST")
+	userAuthRouter.Path("/access/request").
+		HandlerFunc(router.userRestHandler.GetAccessRequests).Methods("GET")
+	userAuthRouter.Path("/access/request/approve").
+		HandlerFunc(router.userRestHandler.ApproveAccessRequest).Methods("PUT")
+	userAuthRouter.Path("/access/request/reject").
+		HandlerFunc(router.userRestHandler.RejectAccessRequest).Methods("PUT")
+	userAuthRouter.Path("/access/request/cancel").
+		HandlerFunc(router.userRestHandler.CancelAccessRequest).Methods("PUT")
+	userAuthRouter.Path("/access/request/{id}").
+		HandlerFunc(router.userRestHandler.GetAccessRequestById).Methods("GET")
+
 	//User management
 	userAuthRouter.Path("/v2").
 		HandlerFunc(router.userRestHandler.GetAllV2).Methods("GET")
@@ -85,6 +99,22 @@ func (router UserRouterImpl) InitUserRouter(userAuthRouter *mux.Router) {
 		HandlerFunc(router.userRestHandler.BulkDeleteRoleGroups).Methods("DELETE")
 	userAuthRouter.Path("/role/group/{id}").
 		HandlerFunc(router.userRestHandler.DeleteRoleGroup).Methods("DELETE")
+	userAuthRouter.Path("/role/group/claim/mapping").
+		HandlerFunc(router.userRestHandler.GetRoleGroupClaimMappings).Methods("GET")
+	userAuthRouter.Path("/role/group/claim/mapping").
+		HandlerFunc(router.userRestHandler.CreateRoleGroupClaimMapping).Methods("POST")
+	userAuthRouter.Path("/role/group/claim/mapping/{id}").
+		HandlerFunc(router.userRestHandler.DeleteRoleGroupClaimMapping).Methods("DELETE")
+
+	userAuthRouter.Path("/rbac/explain").
+		HandlerFunc(router.userRestHandler.ExplainPermission).Methods("POST")
+	userAuthRouter.Path("/rbac/effective-permissions").
+		HandlerFunc(router.userRestHandler.GetEffectivePermissions).Methods("GET")
+
+	userAuthRouter.Path("/permission/audit").
+		HandlerFunc(router.userRestHandler.GetPermissionAudits).Methods("GET")
+	userAuthRouter.Path("/permission/audit/export").
+		HandlerFunc(router.userRestHandler.ExportPermissionAudits).Methods("GET")
 
 	userAuthRouter.Path("/check/roles").
 		HandlerFunc(router.userRestHandler.CheckUserRoles).Methods("GET")
@@ -53,6 +53,30 @@ var UserWireSet = wire.NewSet(
 	wire.Bind(new(user2.RoleGroupService), new(*user2.RoleGroupServiceImpl)),
 	repository2.NewRoleGroupRepositoryImpl,
 	wire.Bind(new(repository2.RoleGroupRepository), new(*repository2.RoleGroupRepositoryImpl)),
+	user2.NewTimeBoundAccessServiceImpl,
+	wire.Bind(new(user2.TimeBoundAccessService), new(*user2.TimeBoundAccessServiceImpl)),
+	repository2.NewTimeoutWindowConfigRepositoryImpl,
+	wire.Bind(new(repository2.TimeoutWindowConfigRepository), new(*repository2.TimeoutWindowConfigRepositoryImpl)),
+	repository2.NewUserRoleGroupTimeoutWindowRepositoryImpl,
+	wire.Bind(new(repository2.UserRoleGroupTimeoutWindowRepository), new(*repository2.UserRoleGroupTimeoutWindowRepositoryImpl)),
+	user2.NewRoleAccessRequestServiceImpl,
+	wire.Bind(new(user2.RoleAccessRequestService), new(*user2.RoleAccessRequestServiceImpl)),
+	repository2.NewRoleAccessRequestRepositoryImpl,
+	wire.Bind(new(repository2.RoleAccessRequestRepository), new(*repository2.RoleAccessRequestRepositoryImpl)),
+	user2.NewGroupClaimsSyncServiceImpl,
+	wire.Bind(new(user2.GroupClaimsSyncService), new(*user2.GroupClaimsSyncServiceImpl)),
+	repository2.NewRoleGroupClaimMappingRepositoryImpl,
+	wire.Bind(new(repository2.RoleGroupClaimMappingRepository), new(*repository2.RoleGroupClaimMappingRepositoryImpl)),
+	repository2.NewUserGroupClaimsRepositoryImpl,
+	wire.Bind(new(repository2.UserGroupClaimsRepository), new(*repository2.UserGroupClaimsRepositoryImpl)),
+	repository2.NewUserRoleGroupClaimMembershipRepositoryImpl,
+	wire.Bind(new(repository2.UserRoleGroupClaimMembershipRepository), new(*repository2.UserRoleGroupClaimMembershipRepositoryImpl)),
+	user2.NewRbacExplainServiceImpl,
+	wire.Bind(new(user2.RbacExplainService), new(*user2.RbacExplainServiceImpl)),
+	user2.NewUserPermissionAuditServiceImpl,
+	wire.Bind(new(user2.UserPermissionAuditService), new(*user2.UserPermissionAuditServiceImpl)),
+	repository2.NewUserPermissionAuditRepositoryImpl,
+	wire.Bind(new(repository2.UserPermissionAuditRepository), new(*repository2.UserPermissionAuditRepositoryImpl)),
 
 	casbin.NewEnforcerImpl,
 	wire.Bind(new(casbin.Enforcer), new(*casbin.EnforcerImpl)),
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/devtron-labs/devtron/pkg/deployment/common/bean"
+	bean2 "github.com/devtron-labs/devtron/pkg/deployment/helmDrift/bean"
 	"time"
 )
 
@@ -196,6 +197,7 @@ type AppDetailContainer struct {
 	LinkOuts                  []LinkOuts             `json:"linkOuts,omitempty"`
 	ResourceTree              map[string]interface{} `json:"resourceTree,omitempty"`
 	Notes                     string                 `json:"notes,omitempty"`
+	HelmDrift                 *bean2.DriftSummaryDto `json:"helmDrift,omitempty"` //drift of live objects from the last deployment, helm pipelines only
 }
 type AppDetailsContainer struct {
 	ResourceTree  map[string]interface{} `json:"resourceTree,omitempty"`
@@ -25,4 +25,7 @@ type ClusterInfo struct {
 	KeyData               string `json:"keyData"`
 	CertData              string `json:"certData"`
 	CAData                string `json:"CAData"`
+	// TokenProvider returns the current token of clusters using short-lived exec or oidc credentials, long-running
+	// clients call it per request instead of using BearerToken
+	TokenProvider func() (string, error) `json:"-"`
 }
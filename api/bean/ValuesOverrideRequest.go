@@ -82,6 +82,7 @@ type ValuesOverrideRequest struct {
 	DeploymentType                        models.DeploymentType       `json:"deploymentType"`     // required for async install/upgrade handling; previously if was used internally
 	ForceSyncDeployment                   bool                        `json:"forceSyncDeployment,notnull"`
 	IsRollbackDeployment                  bool                        `json:"isRollbackDeployment"`
+	DeploymentWindowOverrideReason        string                      `json:"deploymentWindowOverrideReason,omitempty"` // break-glass reason, only honoured for super admins
 	UserId                                int32                       `json:"-"`
 	EnvId                                 int                         `json:"-"`
 	EnvName                               string                      `json:"-"`
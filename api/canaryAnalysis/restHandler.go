@@ -0,0 +1,253 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package canaryAnalysis
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/deployment/canaryAnalysis"
+	"github.com/devtron-labs/devtron/pkg/deployment/canaryAnalysis/bean"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type CanaryAnalysisRestHandler interface {
+	CreateTemplate(w http.ResponseWriter, r *http.Request)
+	UpdateTemplate(w http.ResponseWriter, r *http.Request)
+	DeleteTemplate(w http.ResponseWriter, r *http.Request)
+	GetTemplate(w http.ResponseWriter, r *http.Request)
+	GetTemplatesByAppId(w http.ResponseWriter, r *http.Request)
+	GetAnalysisRuns(w http.ResponseWriter, r *http.Request)
+}
+
+type CanaryAnalysisRestHandlerImpl struct {
+	logger                *zap.SugaredLogger
+	canaryAnalysisService canaryAnalysis.CanaryAnalysisService
+	userService           user.UserService
+	enforcer              casbin.Enforcer
+	enforcerUtil          rbac.EnforcerUtil
+	validator             *validator.Validate
+}
+
+func NewCanaryAnalysisRestHandlerImpl(logger *zap.SugaredLogger,
+	canaryAnalysisService canaryAnalysis.CanaryAnalysisService,
+	userService user.UserService, enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil, validator *validator.Validate) *CanaryAnalysisRestHandlerImpl {
+	return &CanaryAnalysisRestHandlerImpl{
+		logger:                logger,
+		canaryAnalysisService: canaryAnalysisService,
+		userService:           userService,
+		enforcer:              enforcer,
+		enforcerUtil:          enforcerUtil,
+		validator:             validator,
+	}
+}
+
+func (handler *CanaryAnalysisRestHandlerImpl) CreateTemplate(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request, ok := handler.decodeAndValidate(w, r)
+	if !ok {
+		return
+	}
+	token := r.Header.Get("token")
+	if !handler.checkAppAccess(token, request.AppId, casbin.ActionUpdate) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	request.UserId = userId
+	resp, err := handler.canaryAnalysisService.CreateTemplate(request)
+	if err != nil {
+		handler.logger.Errorw("error in creating analysis template", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *CanaryAnalysisRestHandlerImpl) UpdateTemplate(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request, ok := handler.decodeAndValidate(w, r)
+	if !ok {
+		return
+	}
+	existing, err := handler.canaryAnalysisService.GetTemplateById(request.Id)
+	if err != nil {
+		handler.logger.Errorw("error in fetching analysis template", "id", request.Id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	if !handler.checkAppAccess(token, existing.AppId, casbin.ActionUpdate) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	request.UserId = userId
+	resp, err := handler.canaryAnalysisService.UpdateTemplate(request)
+	if err != nil {
+		handler.logger.Errorw("error in updating analysis template", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *CanaryAnalysisRestHandlerImpl) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	existing, err := handler.canaryAnalysisService.GetTemplateById(id)
+	if err != nil {
+		handler.logger.Errorw("error in fetching analysis template", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	if !handler.checkAppAccess(token, existing.AppId, casbin.ActionUpdate) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	err = handler.canaryAnalysisService.DeleteTemplate(id, userId)
+	if err != nil {
+		handler.logger.Errorw("error in deleting analysis template", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, nil, http.StatusOK)
+}
+
+func (handler *CanaryAnalysisRestHandlerImpl) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	resp, err := handler.canaryAnalysisService.GetTemplateById(id)
+	if err != nil {
+		handler.logger.Errorw("error in fetching analysis template", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	token := r.Header.Get("token")
+	if !handler.checkAppAccess(token, resp.AppId, casbin.ActionGet) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *CanaryAnalysisRestHandlerImpl) GetTemplatesByAppId(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, err := strconv.Atoi(r.URL.Query().Get("appId"))
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid appId", http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if !handler.checkAppAccess(token, appId, casbin.ActionGet) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	resp, err := handler.canaryAnalysisService.GetTemplatesByAppId(appId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching analysis templates", "appId", appId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *CanaryAnalysisRestHandlerImpl) GetAnalysisRuns(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, err := strconv.Atoi(r.URL.Query().Get("appId"))
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid appId", http.StatusBadRequest)
+		return
+	}
+	wfrId, err := strconv.Atoi(r.URL.Query().Get("wfrId"))
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid wfrId", http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if !handler.checkAppAccess(token, appId, casbin.ActionGet) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	resp, err := handler.canaryAnalysisService.GetAnalysisRunsByWfrId(wfrId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching analysis runs", "wfrId", wfrId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *CanaryAnalysisRestHandlerImpl) checkAppAccess(token string, appId int, action string) bool {
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	return handler.enforcer.Enforce(token, casbin.ResourceApplications, action, object)
+}
+
+func (handler *CanaryAnalysisRestHandlerImpl) decodeAndValidate(w http.ResponseWriter, r *http.Request) (*bean.AnalysisTemplateDto, bool) {
+	request := &bean.AnalysisTemplateDto{}
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("error in decoding analysis template request", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err in analysis template request", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	return request, true
+}
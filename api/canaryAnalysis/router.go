@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package canaryAnalysis
+
+import "github.com/gorilla/mux"
+
+type CanaryAnalysisRouter interface {
+	InitCanaryAnalysisRouter(router *mux.Router)
+}
+
+type CanaryAnalysisRouterImpl struct {
+	canaryAnalysisRestHandler CanaryAnalysisRestHandler
+}
+
+func NewCanaryAnalysisRouterImpl(canaryAnalysisRestHandler CanaryAnalysisRestHandler) *CanaryAnalysisRouterImpl {
+	return &CanaryAnalysisRouterImpl{
+		canaryAnalysisRestHandler: canaryAnalysisRestHandler,
+	}
+}
+
+func (router *CanaryAnalysisRouterImpl) InitCanaryAnalysisRouter(canaryAnalysisRouter *mux.Router) {
+	canaryAnalysisRouter.Path("/template").
+		Queries("appId", "{appId}").
+		HandlerFunc(router.canaryAnalysisRestHandler.GetTemplatesByAppId).
+		Methods("GET")
+
+	canaryAnalysisRouter.Path("/template").
+		HandlerFunc(router.canaryAnalysisRestHandler.CreateTemplate).
+		Methods("POST")
+
+	canaryAnalysisRouter.Path("/template").
+		HandlerFunc(router.canaryAnalysisRestHandler.UpdateTemplate).
+		Methods("PUT")
+
+	canaryAnalysisRouter.Path("/template/{id}").
+		HandlerFunc(router.canaryAnalysisRestHandler.GetTemplate).
+		Methods("GET")
+
+	canaryAnalysisRouter.Path("/template/{id}").
+		HandlerFunc(router.canaryAnalysisRestHandler.DeleteTemplate).
+		Methods("DELETE")
+
+	canaryAnalysisRouter.Path("/run").
+		Queries("appId", "{appId}", "wfrId", "{wfrId}").
+		HandlerFunc(router.canaryAnalysisRestHandler.GetAnalysisRuns).
+		Methods("GET")
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster
+
+import (
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/go-pg/pg"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func (impl ClusterRestHandlerImpl) GetClusterHealth(w http.ResponseWriter, r *http.Request) {
+	clusterId, ok := impl.getAuthorisedClusterIdForHealth(w, r, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	vars := r.URL.Query()
+	from, err := parseHealthHistoryTime(vars.Get("from"))
+	if err != nil {
+		impl.logger.Errorw("request err, GetClusterHealth", "err", err, "from", vars.Get("from"))
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	to, err := parseHealthHistoryTime(vars.Get("to"))
+	if err != nil {
+		impl.logger.Errorw("request err, GetClusterHealth", "err", err, "to", vars.Get("to"))
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	history, err := impl.clusterHealthService.GetHealthHistory(clusterId, from, to)
+	if err != nil {
+		impl.logger.Errorw("service err, GetClusterHealth", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, history, http.StatusOK)
+}
+
+func (impl ClusterRestHandlerImpl) ProbeClusterHealth(w http.ResponseWriter, r *http.Request) {
+	clusterId, ok := impl.getAuthorisedClusterIdForHealth(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	probe, err := impl.clusterHealthService.ProbeCluster(clusterId)
+	if err != nil {
+		impl.logger.Errorw("service err, ProbeClusterHealth", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, probe, http.StatusOK)
+}
+
+func (impl ClusterRestHandlerImpl) getAuthorisedClusterIdForHealth(w http.ResponseWriter, r *http.Request, action string) (int, bool) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, false
+	}
+	clusterId, err := strconv.Atoi(mux.Vars(r)["clusterId"])
+	if err != nil {
+		impl.logger.Errorw("request err, cluster health", "err", err, "clusterId", mux.Vars(r)["clusterId"])
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return 0, false
+	}
+	clusterBean, err := impl.clusterService.FindByIdWithoutConfig(clusterId)
+	if err != nil {
+		impl.logger.Errorw("service err, FindByIdWithoutConfig", "err", err, "clusterId", clusterId)
+		if err == pg.ErrNoRows {
+			common.WriteJsonResp(w, err, nil, http.StatusNotFound)
+		} else {
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		}
+		return 0, false
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceCluster, action, clusterBean.ClusterName); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return 0, false
+	}
+	//RBAC enforcer Ends
+	return clusterId, true
+}
+
+// parseHealthHistoryTime accepts unix seconds or RFC3339, an empty value is the zero time
+func parseHealthHistoryTime(value string) (time.Time, error) {
+	if len(value) == 0 {
+		return time.Time{}, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"errors"
 	bean2 "github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterHealth"
 	"github.com/devtron-labs/devtron/pkg/cluster/environment"
 	"github.com/devtron-labs/devtron/pkg/cluster/rbac"
 	"net/http"
@@ -60,6 +61,8 @@ type ClusterRestHandler interface {
 	GetClusterNamespaces(w http.ResponseWriter, r *http.Request)
 	GetAllClusterNamespaces(w http.ResponseWriter, r *http.Request)
 	FindAllForClusterPermission(w http.ResponseWriter, r *http.Request)
+	GetClusterHealth(w http.ResponseWriter, r *http.Request)
+	ProbeClusterHealth(w http.ResponseWriter, r *http.Request)
 }
 
 type ClusterRestHandlerImpl struct {
@@ -73,6 +76,7 @@ type ClusterRestHandlerImpl struct {
 	deleteService             delete2.DeleteService
 	environmentService        environment.EnvironmentService
 	clusterRbacService        rbac.ClusterRbacService
+	clusterHealthService      clusterHealth.ClusterHealthService
 }
 
 func NewClusterRestHandlerImpl(clusterService cluster.ClusterService,
@@ -84,7 +88,8 @@ func NewClusterRestHandlerImpl(clusterService cluster.ClusterService,
 	enforcer casbin.Enforcer,
 	deleteService delete2.DeleteService,
 	environmentService environment.EnvironmentService,
-	clusterRbacService rbac.ClusterRbacService) *ClusterRestHandlerImpl {
+	clusterRbacService rbac.ClusterRbacService,
+	clusterHealthService clusterHealth.ClusterHealthService) *ClusterRestHandlerImpl {
 	return &ClusterRestHandlerImpl{
 		clusterService:            clusterService,
 		clusterNoteService:        clusterNoteService,
@@ -96,6 +101,7 @@ func NewClusterRestHandlerImpl(clusterService cluster.ClusterService,
 		deleteService:             deleteService,
 		environmentService:        environmentService,
 		clusterRbacService:        clusterRbacService,
+		clusterHealthService:      clusterHealthService,
 	}
 }
 
@@ -265,7 +271,7 @@ func (impl ClusterRestHandlerImpl) ValidateKubeconfig(w http.ResponseWriter, r *
 	if util2.IsBaseStack() {
 		ctx = context.WithValue(ctx, "token", token)
 	}
-	res, err := impl.clusterService.ValidateKubeconfig(bean.Config)
+	res, err := impl.clusterService.ValidateKubeconfig(bean.Config, bean.Contexts)
 	if err != nil {
 		impl.logger.Errorw("error in validating kubeconfig")
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
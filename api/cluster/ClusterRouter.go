@@ -77,6 +77,14 @@ func (impl ClusterRouterImpl) InitClusterRouter(clusterRouter *mux.Router) {
 		Methods("GET").
 		HandlerFunc(impl.clusterRestHandler.GetClusterNamespaces)
 
+	clusterRouter.Path("/health/{clusterId}").
+		Methods("GET").
+		HandlerFunc(impl.clusterRestHandler.GetClusterHealth)
+
+	clusterRouter.Path("/health/{clusterId}/probe").
+		Methods("POST").
+		HandlerFunc(impl.clusterRestHandler.ProbeClusterHealth)
+
 	clusterRouter.Path("/namespaces").
 		Methods("GET").
 		HandlerFunc(impl.clusterRestHandler.GetAllClusterNamespaces)
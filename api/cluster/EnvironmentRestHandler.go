@@ -23,6 +23,7 @@ import (
 	request "github.com/devtron-labs/devtron/pkg/cluster/environment"
 	bean2 "github.com/devtron-labs/devtron/pkg/cluster/environment/bean"
 	"github.com/devtron-labs/devtron/pkg/cluster/environment/read"
+	"github.com/devtron-labs/devtron/pkg/cluster/namespaceTemplate"
 	"github.com/devtron-labs/devtron/util/commonEnforcementFunctionsUtil"
 	"net/http"
 	"strconv"
@@ -60,6 +61,15 @@ type EnvironmentRestHandler interface {
 	GetEnvironmentConnection(w http.ResponseWriter, r *http.Request)
 	DeleteEnvironment(w http.ResponseWriter, r *http.Request)
 	GetCombinedEnvironmentListForDropDownByClusterIds(w http.ResponseWriter, r *http.Request)
+
+	CreateNamespaceTemplate(w http.ResponseWriter, r *http.Request)
+	UpdateNamespaceTemplate(w http.ResponseWriter, r *http.Request)
+	DeleteNamespaceTemplate(w http.ResponseWriter, r *http.Request)
+	GetNamespaceTemplate(w http.ResponseWriter, r *http.Request)
+	GetAllNamespaceTemplates(w http.ResponseWriter, r *http.Request)
+	ReapplyNamespaceTemplate(w http.ResponseWriter, r *http.Request)
+	GetNamespaceTemplateDrift(w http.ResponseWriter, r *http.Request)
+	ApplyNamespaceTemplateToEnvironment(w http.ResponseWriter, r *http.Request)
 }
 
 type EnvironmentRestHandlerImpl struct {
@@ -74,6 +84,7 @@ type EnvironmentRestHandlerImpl struct {
 	k8sUtil                           *k8s2.K8sServiceImpl
 	cfg                               *bean.Config
 	rbacEnforcementUtil               commonEnforcementFunctionsUtil.CommonEnforcementUtil
+	namespaceTemplateService          namespaceTemplate.NamespaceTemplateService
 }
 
 type ClusterReachableResponse struct {
@@ -82,7 +93,8 @@ type ClusterReachableResponse struct {
 }
 
 func NewEnvironmentRestHandlerImpl(svc request.EnvironmentService, environmentReadService read.EnvironmentReadService, logger *zap.SugaredLogger, userService user.UserService, validator *validator.Validate, enforcer casbin.Enforcer, deleteService delete2.DeleteService, k8sUtil *k8s2.K8sServiceImpl, k8sCommonService k8s.K8sCommonService,
-	rbacEnforcementUtil commonEnforcementFunctionsUtil.CommonEnforcementUtil) *EnvironmentRestHandlerImpl {
+	rbacEnforcementUtil commonEnforcementFunctionsUtil.CommonEnforcementUtil,
+	namespaceTemplateService namespaceTemplate.NamespaceTemplateService) *EnvironmentRestHandlerImpl {
 	cfg := &bean.Config{}
 	err := env.Parse(cfg)
 	if err != nil {
@@ -102,6 +114,7 @@ func NewEnvironmentRestHandlerImpl(svc request.EnvironmentService, environmentRe
 		k8sUtil:                           k8sUtil,
 		k8sCommonService:                  k8sCommonService,
 		rbacEnforcementUtil:               rbacEnforcementUtil,
+		namespaceTemplateService:          namespaceTemplateService,
 	}
 }
 
@@ -73,4 +73,29 @@ func (impl EnvironmentRouterImpl) InitEnvironmentClusterMappingsRouter(environme
 	environmentClusterMappingsRouter.Path("/{envId}/connection").
 		Methods("GET").
 		HandlerFunc(impl.environmentClusterMappingsRestHandler.GetEnvironmentConnection)
+
+	environmentClusterMappingsRouter.Path("/namespace-template").
+		Methods("GET").
+		HandlerFunc(impl.environmentClusterMappingsRestHandler.GetAllNamespaceTemplates)
+	environmentClusterMappingsRouter.Path("/namespace-template").
+		Methods("POST").
+		HandlerFunc(impl.environmentClusterMappingsRestHandler.CreateNamespaceTemplate)
+	environmentClusterMappingsRouter.Path("/namespace-template/{id}").
+		Methods("GET").
+		HandlerFunc(impl.environmentClusterMappingsRestHandler.GetNamespaceTemplate)
+	environmentClusterMappingsRouter.Path("/namespace-template/{id}").
+		Methods("PUT").
+		HandlerFunc(impl.environmentClusterMappingsRestHandler.UpdateNamespaceTemplate)
+	environmentClusterMappingsRouter.Path("/namespace-template/{id}").
+		Methods("DELETE").
+		HandlerFunc(impl.environmentClusterMappingsRestHandler.DeleteNamespaceTemplate)
+	environmentClusterMappingsRouter.Path("/namespace-template/{id}/apply").
+		Methods("POST").
+		HandlerFunc(impl.environmentClusterMappingsRestHandler.ReapplyNamespaceTemplate)
+	environmentClusterMappingsRouter.Path("/namespace-template/{id}/drift").
+		Methods("GET").
+		HandlerFunc(impl.environmentClusterMappingsRestHandler.GetNamespaceTemplateDrift)
+	environmentClusterMappingsRouter.Path("/{envId}/namespace-template").
+		Methods("POST").
+		HandlerFunc(impl.environmentClusterMappingsRestHandler.ApplyNamespaceTemplateToEnvironment)
 }
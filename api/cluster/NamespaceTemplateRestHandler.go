@@ -0,0 +1,236 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cluster
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/cluster/namespaceTemplate/bean"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+)
+
+func (impl EnvironmentRestHandlerImpl) CreateNamespaceTemplate(w http.ResponseWriter, r *http.Request) {
+	userId, ok := impl.authoriseNamespaceTemplateRequest(w, r, casbin.ActionCreate)
+	if !ok {
+		return
+	}
+	var request bean.NamespaceTemplateRequest
+	if !impl.decodeNamespaceTemplateRequest(w, r, &request) {
+		return
+	}
+	res, err := impl.namespaceTemplateService.CreateTemplate(&request, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, CreateNamespaceTemplate", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl EnvironmentRestHandlerImpl) UpdateNamespaceTemplate(w http.ResponseWriter, r *http.Request) {
+	userId, ok := impl.authoriseNamespaceTemplateRequest(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	templateId, ok := impl.getPathParamId(w, r, "id")
+	if !ok {
+		return
+	}
+	var request bean.NamespaceTemplateRequest
+	if !impl.decodeNamespaceTemplateRequest(w, r, &request) {
+		return
+	}
+	request.Id = templateId
+	res, err := impl.namespaceTemplateService.UpdateTemplate(&request, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, UpdateNamespaceTemplate", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl EnvironmentRestHandlerImpl) DeleteNamespaceTemplate(w http.ResponseWriter, r *http.Request) {
+	userId, ok := impl.authoriseNamespaceTemplateRequest(w, r, casbin.ActionDelete)
+	if !ok {
+		return
+	}
+	templateId, ok := impl.getPathParamId(w, r, "id")
+	if !ok {
+		return
+	}
+	err := impl.namespaceTemplateService.DeleteTemplate(templateId, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, DeleteNamespaceTemplate", "err", err, "templateId", templateId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, "Namespace template deleted successfully.", http.StatusOK)
+}
+
+func (impl EnvironmentRestHandlerImpl) GetNamespaceTemplate(w http.ResponseWriter, r *http.Request) {
+	if _, ok := impl.authoriseNamespaceTemplateRequest(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	templateId, ok := impl.getPathParamId(w, r, "id")
+	if !ok {
+		return
+	}
+	res, err := impl.namespaceTemplateService.GetTemplate(templateId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetNamespaceTemplate", "err", err, "templateId", templateId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl EnvironmentRestHandlerImpl) GetAllNamespaceTemplates(w http.ResponseWriter, r *http.Request) {
+	if _, ok := impl.authoriseNamespaceTemplateRequest(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	res, err := impl.namespaceTemplateService.GetAllTemplates()
+	if err != nil {
+		impl.logger.Errorw("service err, GetAllNamespaceTemplates", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl EnvironmentRestHandlerImpl) ReapplyNamespaceTemplate(w http.ResponseWriter, r *http.Request) {
+	userId, ok := impl.authoriseNamespaceTemplateRequest(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	templateId, ok := impl.getPathParamId(w, r, "id")
+	if !ok {
+		return
+	}
+	request := bean.ApplyTemplateRequest{}
+	if r.ContentLength != 0 {
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			impl.logger.Errorw("request err, ReapplyNamespaceTemplate", "err", err, "templateId", templateId)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	request.TemplateId = templateId
+	res, err := impl.namespaceTemplateService.ReapplyTemplate(&request, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, ReapplyNamespaceTemplate", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl EnvironmentRestHandlerImpl) GetNamespaceTemplateDrift(w http.ResponseWriter, r *http.Request) {
+	if _, ok := impl.authoriseNamespaceTemplateRequest(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	templateId, ok := impl.getPathParamId(w, r, "id")
+	if !ok {
+		return
+	}
+	res, err := impl.namespaceTemplateService.GetTemplateDrift(templateId)
+	if err != nil {
+		impl.logger.Errorw("service err, GetNamespaceTemplateDrift", "err", err, "templateId", templateId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+func (impl EnvironmentRestHandlerImpl) ApplyNamespaceTemplateToEnvironment(w http.ResponseWriter, r *http.Request) {
+	userId, ok := impl.authoriseNamespaceTemplateRequest(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	envId, ok := impl.getPathParamId(w, r, "envId")
+	if !ok {
+		return
+	}
+	var request bean.AttachTemplateRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		impl.logger.Errorw("request err, ApplyNamespaceTemplateToEnvironment", "err", err, "envId", envId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = impl.validator.Struct(request)
+	if err != nil {
+		impl.logger.Errorw("validation err, ApplyNamespaceTemplateToEnvironment", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.EnvId = envId
+	res, err := impl.namespaceTemplateService.ApplyTemplateToEnvironment(&request, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, ApplyNamespaceTemplateToEnvironment", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// authoriseNamespaceTemplateRequest checks the logged-in user and the global environment permission, templates
+// apply to any environment so the permission is checked on all of them
+func (impl EnvironmentRestHandlerImpl) authoriseNamespaceTemplateRequest(w http.ResponseWriter, r *http.Request, action string) (int32, bool) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, false
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobalEnvironment, action, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return 0, false
+	}
+	return userId, true
+}
+
+func (impl EnvironmentRestHandlerImpl) decodeNamespaceTemplateRequest(w http.ResponseWriter, r *http.Request, request *bean.NamespaceTemplateRequest) bool {
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		impl.logger.Errorw("request err, namespace template", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return false
+	}
+	err = impl.validator.Struct(request)
+	if err != nil {
+		impl.logger.Errorw("validation err, namespace template", "err", err, "payload", request)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func (impl EnvironmentRestHandlerImpl) getPathParamId(w http.ResponseWriter, r *http.Request, key string) (int, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)[key])
+	if err != nil {
+		impl.logger.Errorw("request err, namespace template", "err", err, key, mux.Vars(r)[key])
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
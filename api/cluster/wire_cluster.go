@@ -18,9 +18,12 @@ package cluster
 
 import (
 	"github.com/devtron-labs/devtron/pkg/cluster"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterCredentials"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterHealth"
 	"github.com/devtron-labs/devtron/pkg/cluster/environment"
 	read2 "github.com/devtron-labs/devtron/pkg/cluster/environment/read"
 	repository3 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/cluster/namespaceTemplate"
 	"github.com/devtron-labs/devtron/pkg/cluster/rbac"
 	"github.com/devtron-labs/devtron/pkg/cluster/read"
 	"github.com/devtron-labs/devtron/pkg/cluster/repository"
@@ -34,6 +37,8 @@ import (
 var ClusterWireSet = wire.NewSet(
 	repository.NewClusterRepositoryImpl,
 	wire.Bind(new(repository.ClusterRepository), new(*repository.ClusterRepositoryImpl)),
+	clusterCredentials.NewClusterCredentialServiceImpl,
+	wire.Bind(new(clusterCredentials.ClusterCredentialService), new(*clusterCredentials.ClusterCredentialServiceImpl)),
 	cluster.NewClusterServiceImpl,
 	cluster.NewClusterServiceImplExtended,
 	wire.Bind(new(cluster.ClusterService), new(*cluster.ClusterServiceImplExtended)),
@@ -56,6 +61,7 @@ var ClusterWireSet = wire.NewSet(
 	cluster.NewClusterDescriptionServiceImpl,
 	wire.Bind(new(cluster.ClusterDescriptionService), new(*cluster.ClusterDescriptionServiceImpl)),
 
+	clusterHealth.WireSet,
 	NewClusterRestHandlerImpl,
 	wire.Bind(new(ClusterRestHandler), new(*ClusterRestHandlerImpl)),
 	NewClusterRouterImpl,
@@ -63,6 +69,7 @@ var ClusterWireSet = wire.NewSet(
 
 	repository3.NewEnvironmentRepositoryImpl,
 	wire.Bind(new(repository3.EnvironmentRepository), new(*repository3.EnvironmentRepositoryImpl)),
+	namespaceTemplate.WireSet,
 	environment.NewEnvironmentServiceImpl,
 	wire.Bind(new(environment.EnvironmentService), new(*environment.EnvironmentServiceImpl)),
 	read2.NewEnvironmentReadServiceImpl,
@@ -79,6 +86,8 @@ var ClusterWireSetEa = wire.NewSet(
 	wire.Bind(new(repository.ClusterRepository), new(*repository.ClusterRepositoryImpl)),
 	rbac.NewClusterRbacServiceImpl,
 	wire.Bind(new(rbac.ClusterRbacService), new(*rbac.ClusterRbacServiceImpl)),
+	clusterCredentials.NewClusterCredentialServiceImpl,
+	wire.Bind(new(clusterCredentials.ClusterCredentialService), new(*clusterCredentials.ClusterCredentialServiceImpl)),
 	cluster.NewClusterServiceImpl,
 	wire.Bind(new(cluster.ClusterService), new(*cluster.ClusterServiceImpl)),
 	read.NewClusterReadServiceImpl,
@@ -97,12 +106,14 @@ var ClusterWireSetEa = wire.NewSet(
 	cluster.NewClusterDescriptionServiceImpl,
 	wire.Bind(new(cluster.ClusterDescriptionService), new(*cluster.ClusterDescriptionServiceImpl)),
 
+	clusterHealth.WireSet,
 	NewClusterRestHandlerImpl,
 	wire.Bind(new(ClusterRestHandler), new(*ClusterRestHandlerImpl)),
 	NewClusterRouterImpl,
 	wire.Bind(new(ClusterRouter), new(*ClusterRouterImpl)),
 	repository3.NewEnvironmentRepositoryImpl,
 	wire.Bind(new(repository3.EnvironmentRepository), new(*repository3.EnvironmentRepositoryImpl)),
+	namespaceTemplate.WireSet,
 	environment.NewEnvironmentServiceImpl,
 	wire.Bind(new(environment.EnvironmentService), new(*environment.EnvironmentServiceImpl)),
 	read2.NewEnvironmentReadServiceImpl,
@@ -0,0 +1,237 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentWindow
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/deploymentWindow"
+	"github.com/devtron-labs/devtron/pkg/deploymentWindow/bean"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type DeploymentWindowRestHandler interface {
+	CreateDeploymentWindow(w http.ResponseWriter, r *http.Request)
+	UpdateDeploymentWindow(w http.ResponseWriter, r *http.Request)
+	DeleteDeploymentWindow(w http.ResponseWriter, r *http.Request)
+	GetDeploymentWindow(w http.ResponseWriter, r *http.Request)
+	GetAllDeploymentWindows(w http.ResponseWriter, r *http.Request)
+	GetDeploymentWindowState(w http.ResponseWriter, r *http.Request)
+}
+
+type DeploymentWindowRestHandlerImpl struct {
+	logger                  *zap.SugaredLogger
+	deploymentWindowService deploymentWindow.DeploymentWindowService
+	userService             user.UserService
+	enforcer                casbin.Enforcer
+	enforcerUtil            rbac.EnforcerUtil
+	validator               *validator.Validate
+}
+
+func NewDeploymentWindowRestHandlerImpl(logger *zap.SugaredLogger,
+	deploymentWindowService deploymentWindow.DeploymentWindowService,
+	userService user.UserService, enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil, validator *validator.Validate) *DeploymentWindowRestHandlerImpl {
+	return &DeploymentWindowRestHandlerImpl{
+		logger:                  logger,
+		deploymentWindowService: deploymentWindowService,
+		userService:             userService,
+		enforcer:                enforcer,
+		enforcerUtil:            enforcerUtil,
+		validator:               validator,
+	}
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) CreateDeploymentWindow(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionCreate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	request, ok := handler.decodeAndValidate(w, r)
+	if !ok {
+		return
+	}
+	request.UserId = userId
+	resp, err := handler.deploymentWindowService.CreateDeploymentWindow(request)
+	if err != nil {
+		handler.logger.Errorw("error in creating deployment window", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) UpdateDeploymentWindow(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	request, ok := handler.decodeAndValidate(w, r)
+	if !ok {
+		return
+	}
+	if request.Id == 0 {
+		common.WriteJsonResp(w, errors.New("deployment window id is required"), nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	resp, err := handler.deploymentWindowService.UpdateDeploymentWindow(request)
+	if err != nil {
+		handler.logger.Errorw("error in updating deployment window", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) DeleteDeploymentWindow(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionDelete, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.deploymentWindowService.DeleteDeploymentWindow(id, userId)
+	if err != nil {
+		handler.logger.Errorw("error in deleting deployment window", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, nil, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) GetDeploymentWindow(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	resp, err := handler.deploymentWindowService.GetDeploymentWindowById(id)
+	if err != nil {
+		handler.logger.Errorw("error in fetching deployment window", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) GetAllDeploymentWindows(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	resp, err := handler.deploymentWindowService.GetAllDeploymentWindows()
+	if err != nil {
+		handler.logger.Errorw("error in fetching deployment windows", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) GetDeploymentWindowState(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, err := strconv.Atoi(r.URL.Query().Get("appId"))
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid appId", http.StatusBadRequest)
+		return
+	}
+	envId, err := strconv.Atoi(r.URL.Query().Get("envId"))
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid envId", http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object); !ok {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	resp, err := handler.deploymentWindowService.GetDeploymentWindowStateForAppEnv(appId, envId)
+	if err != nil {
+		handler.logger.Errorw("error in evaluating deployment window state", "appId", appId, "envId", envId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *DeploymentWindowRestHandlerImpl) decodeAndValidate(w http.ResponseWriter, r *http.Request) (*bean.DeploymentWindowDto, bool) {
+	request := &bean.DeploymentWindowDto{}
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("error in decoding deployment window request", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err in deployment window request", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	return request, true
+}
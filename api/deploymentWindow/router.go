@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentWindow
+
+import "github.com/gorilla/mux"
+
+type DeploymentWindowRouter interface {
+	InitDeploymentWindowRouter(router *mux.Router)
+}
+
+type DeploymentWindowRouterImpl struct {
+	deploymentWindowRestHandler DeploymentWindowRestHandler
+}
+
+func NewDeploymentWindowRouterImpl(deploymentWindowRestHandler DeploymentWindowRestHandler) *DeploymentWindowRouterImpl {
+	return &DeploymentWindowRouterImpl{
+		deploymentWindowRestHandler: deploymentWindowRestHandler,
+	}
+}
+
+func (router *DeploymentWindowRouterImpl) InitDeploymentWindowRouter(deploymentWindowRouter *mux.Router) {
+	deploymentWindowRouter.Path("/state").
+		Queries("appId", "{appId}", "envId", "{envId}").
+		HandlerFunc(router.deploymentWindowRestHandler.GetDeploymentWindowState).
+		Methods("GET")
+
+	deploymentWindowRouter.Path("").
+		HandlerFunc(router.deploymentWindowRestHandler.GetAllDeploymentWindows).
+		Methods("GET")
+
+	deploymentWindowRouter.Path("").
+		HandlerFunc(router.deploymentWindowRestHandler.CreateDeploymentWindow).
+		Methods("POST")
+
+	deploymentWindowRouter.Path("").
+		HandlerFunc(router.deploymentWindowRestHandler.UpdateDeploymentWindow).
+		Methods("PUT")
+
+	deploymentWindowRouter.Path("/{id}").
+		HandlerFunc(router.deploymentWindowRestHandler.GetDeploymentWindow).
+		Methods("GET")
+
+	deploymentWindowRouter.Path("/{id}").
+		HandlerFunc(router.deploymentWindowRestHandler.DeleteDeploymentWindow).
+		Methods("DELETE")
+}
@@ -21,7 +21,6 @@ import (
 	"errors"
 	"fmt"
 	"github.com/devtron-labs/common-lib/utils/k8s"
-	"github.com/devtron-labs/common-lib/utils/k8s/commonBean"
 	"github.com/devtron-labs/devtron/api/helm-app/bean"
 	"github.com/devtron-labs/devtron/api/helm-app/gRPC"
 	"github.com/devtron-labs/devtron/api/helm-app/models"
@@ -30,7 +29,9 @@ import (
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/internal/constants"
 	repository2 "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/devtron-labs/devtron/pkg/cluster/adapter"
 	bean2 "github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterCredentials"
 	"github.com/devtron-labs/devtron/pkg/cluster/environment"
 	"github.com/go-pg/pg"
 	"net/http"
@@ -115,6 +116,7 @@ type HelmAppServiceImpl struct {
 	K8sUtil                              *k8s.K8sServiceImpl
 	helmReleaseConfig                    *HelmReleaseConfig
 	helmAppReadService                   read.HelmAppReadService
+	clusterCredentialService             clusterCredentials.ClusterCredentialService
 }
 
 func NewHelmAppServiceImpl(Logger *zap.SugaredLogger, clusterService cluster.ClusterService,
@@ -125,7 +127,8 @@ func NewHelmAppServiceImpl(Logger *zap.SugaredLogger, clusterService cluster.Clu
 	installedAppRepository repository.InstalledAppRepository, appRepository app.AppRepository,
 	clusterRepository clusterRepository.ClusterRepository, K8sUtil *k8s.K8sServiceImpl,
 	helmReleaseConfig *HelmReleaseConfig,
-	helmAppReadService read.HelmAppReadService) *HelmAppServiceImpl {
+	helmAppReadService read.HelmAppReadService,
+	clusterCredentialService clusterCredentials.ClusterCredentialService) *HelmAppServiceImpl {
 	return &HelmAppServiceImpl{
 		logger:                               Logger,
 		clusterService:                       clusterService,
@@ -143,6 +146,7 @@ func NewHelmAppServiceImpl(Logger *zap.SugaredLogger, clusterService cluster.Clu
 		K8sUtil:                              K8sUtil,
 		helmReleaseConfig:                    helmReleaseConfig,
 		helmAppReadService:                   helmAppReadService,
+		clusterCredentialService:             clusterCredentialService,
 	}
 }
 
@@ -613,7 +617,11 @@ func (impl *HelmAppServiceImpl) DeleteApplication(ctx context.Context, app *helm
 
 func (impl *HelmAppServiceImpl) checkIfNsExists(namespace string, clusterBean *bean2.ClusterBean) (bool, error) {
 
-	config := clusterBean.GetClusterConfig()
+	config, err := clusterBean.ResolveClusterConfig(impl.clusterCredentialService)
+	if err != nil {
+		impl.logger.Errorw("error in resolving cluster credentials", "err", err, "clusterId", clusterBean.Id)
+		return false, err
+	}
 	v12Client, err := impl.K8sUtil.GetCoreV1Client(config)
 	if err != nil {
 		impl.logger.Errorw("error in getting k8s client", "err", err, "clusterHost", config.Host)
@@ -957,7 +965,7 @@ func (impl *HelmAppServiceImpl) TemplateChart(ctx context.Context, templateChart
 			AwsRegion:           appStoreAppVersion.AppStore.DockerArtifactStore.AWSRegion,
 			AccessKey:           appStoreAppVersion.AppStore.DockerArtifactStore.AWSAccessKeyId,
 			SecretKey:           appStoreAppVersion.AppStore.DockerArtifactStore.AWSSecretAccessKey,
-			RegistryType:        string(appStoreAppVersion.AppStore.DockerArtifactStore.RegistryType),
+			RegistryType:        appStoreAppVersion.AppStore.DockerArtifactStore.RegistryType.GetClientCompatibleType(),
 			RepoName:            appStoreAppVersion.AppStore.Name,
 			IsPublic:            ociRegistryConfig.IsPublic,
 			Connection:          appStoreAppVersion.AppStore.DockerArtifactStore.Connection,
@@ -1103,17 +1111,10 @@ func (impl *HelmAppServiceImpl) listApplications(ctx context.Context, clusterIds
 	}
 	req := &gRPC.AppListRequest{}
 	for _, clusterDetail := range clusters {
-		config := &gRPC.ClusterConfig{
-			ApiServerUrl:          clusterDetail.ServerUrl,
-			Token:                 clusterDetail.Config[commonBean.BearerToken],
-			ClusterId:             int32(clusterDetail.Id),
-			ClusterName:           clusterDetail.ClusterName,
-			InsecureSkipTLSVerify: clusterDetail.InsecureSkipTLSVerify,
-		}
-		if clusterDetail.InsecureSkipTLSVerify == false {
-			config.KeyData = clusterDetail.Config[commonBean.TlsKey]
-			config.CertData = clusterDetail.Config[commonBean.CertData]
-			config.CaData = clusterDetail.Config[commonBean.CertificateAuthorityData]
+		config, err := adapter.GetGrpcClusterConfig(clusterDetail, impl.clusterCredentialService)
+		if err != nil {
+			// the cluster is still listed, kubelink reports it as errored
+			impl.logger.Errorw("error in resolving cluster credentials", "clusterId", clusterDetail.Id, "err", err)
 		}
 		req.Clusters = append(req.Clusters, config)
 	}
@@ -1,15 +1,17 @@
 package read
 
 import (
-	"github.com/devtron-labs/common-lib/utils/k8s/commonBean"
 	"github.com/devtron-labs/devtron/api/helm-app/gRPC"
+	"github.com/devtron-labs/devtron/pkg/cluster/adapter"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterCredentials"
 	"github.com/devtron-labs/devtron/pkg/cluster/read"
 	"go.uber.org/zap"
 )
 
 type HelmAppReadServiceImpl struct {
-	logger             *zap.SugaredLogger
-	clusterReadService read.ClusterReadService
+	logger                   *zap.SugaredLogger
+	clusterReadService       read.ClusterReadService
+	clusterCredentialService clusterCredentials.ClusterCredentialService
 }
 
 type HelmAppReadService interface {
@@ -18,10 +20,12 @@ type HelmAppReadService interface {
 
 func NewHelmAppReadServiceImpl(logger *zap.SugaredLogger,
 	clusterReadService read.ClusterReadService,
+	clusterCredentialService clusterCredentials.ClusterCredentialService,
 ) *HelmAppReadServiceImpl {
 	return &HelmAppReadServiceImpl{
-		logger:             logger,
-		clusterReadService: clusterReadService,
+		logger:                   logger,
+		clusterReadService:       clusterReadService,
+		clusterCredentialService: clusterCredentialService,
 	}
 }
 
@@ -31,17 +35,10 @@ func (impl *HelmAppReadServiceImpl) GetClusterConf(clusterId int) (*gRPC.Cluster
 		impl.logger.Errorw("error in fetching cluster detail", "err", err)
 		return nil, err
 	}
-	config := &gRPC.ClusterConfig{
-		ApiServerUrl:          cluster.ServerUrl,
-		Token:                 cluster.Config[commonBean.BearerToken],
-		ClusterId:             int32(cluster.Id),
-		ClusterName:           cluster.ClusterName,
-		InsecureSkipTLSVerify: cluster.InsecureSkipTLSVerify,
-	}
-	if cluster.InsecureSkipTLSVerify == false {
-		config.KeyData = cluster.Config[commonBean.TlsKey]
-		config.CertData = cluster.Config[commonBean.CertData]
-		config.CaData = cluster.Config[commonBean.CertificateAuthorityData]
+	config, err := adapter.GetGrpcClusterConfig(*cluster, impl.clusterCredentialService)
+	if err != nil {
+		impl.logger.Errorw("error in resolving cluster credentials", "clusterId", clusterId, "err", err)
+		return nil, err
 	}
 	return config, nil
 }
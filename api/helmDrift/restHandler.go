@@ -0,0 +1,194 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helmDrift
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/deployment/helmDrift"
+	"github.com/devtron-labs/devtron/pkg/deployment/helmDrift/bean"
+	"github.com/devtron-labs/devtron/util/rbac"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type HelmDriftRestHandler interface {
+	GetDriftReport(w http.ResponseWriter, r *http.Request)
+	CheckDrift(w http.ResponseWriter, r *http.Request)
+	ReapplyRelease(w http.ResponseWriter, r *http.Request)
+	UpdateAutoRemediate(w http.ResponseWriter, r *http.Request)
+}
+
+type HelmDriftRestHandlerImpl struct {
+	logger           *zap.SugaredLogger
+	helmDriftService helmDrift.HelmDriftService
+	userService      user.UserService
+	enforcer         casbin.Enforcer
+	enforcerUtil     rbac.EnforcerUtil
+	validator        *validator.Validate
+}
+
+func NewHelmDriftRestHandlerImpl(logger *zap.SugaredLogger,
+	helmDriftService helmDrift.HelmDriftService,
+	userService user.UserService, enforcer casbin.Enforcer,
+	enforcerUtil rbac.EnforcerUtil, validator *validator.Validate) *HelmDriftRestHandlerImpl {
+	return &HelmDriftRestHandlerImpl{
+		logger:           logger,
+		helmDriftService: helmDriftService,
+		userService:      userService,
+		enforcer:         enforcer,
+		enforcerUtil:     enforcerUtil,
+		validator:        validator,
+	}
+}
+
+func (handler *HelmDriftRestHandlerImpl) GetDriftReport(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	appId, err := strconv.Atoi(r.URL.Query().Get("appId"))
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid appId", http.StatusBadRequest)
+		return
+	}
+	envId, err := strconv.Atoi(r.URL.Query().Get("envId"))
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid envId", http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if !handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, object) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	resp, err := handler.helmDriftService.GetDriftReport(appId, envId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching helm drift report", "appId", appId, "envId", envId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *HelmDriftRestHandlerImpl) CheckDrift(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request := &bean.DriftActionRequest{}
+	if !handler.decodeAndValidate(w, r, request) {
+		return
+	}
+	token := r.Header.Get("token")
+	if !handler.checkTriggerAccess(token, request.AppId, request.EnvId) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	request.UserId = userId
+	resp, err := handler.helmDriftService.CheckDrift(r.Context(), request)
+	if err != nil {
+		handler.logger.Errorw("error in checking helm drift", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *HelmDriftRestHandlerImpl) ReapplyRelease(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request := &bean.DriftActionRequest{}
+	if !handler.decodeAndValidate(w, r, request) {
+		return
+	}
+	token := r.Header.Get("token")
+	if !handler.checkTriggerAccess(token, request.AppId, request.EnvId) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	request.UserId = userId
+	resp, err := handler.helmDriftService.ReapplyRelease(r.Context(), request)
+	if err != nil {
+		handler.logger.Errorw("error in re-applying helm release", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *HelmDriftRestHandlerImpl) UpdateAutoRemediate(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request := &bean.AutoRemediateRequest{}
+	if !handler.decodeAndValidate(w, r, request) {
+		return
+	}
+	token := r.Header.Get("token")
+	if !handler.checkTriggerAccess(token, request.AppId, request.EnvId) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	request.UserId = userId
+	resp, err := handler.helmDriftService.UpdateAutoRemediate(request)
+	if err != nil {
+		handler.logger.Errorw("error in updating helm drift auto remediation", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+// checkTriggerAccess requires deploy access as re-applying a release is equivalent to a deployment on the environment
+func (handler *HelmDriftRestHandlerImpl) checkTriggerAccess(token string, appId, envId int) bool {
+	object := handler.enforcerUtil.GetAppRBACNameByAppId(appId)
+	if !handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionTrigger, object) {
+		return false
+	}
+	object = handler.enforcerUtil.GetEnvRBACNameByAppId(appId, envId)
+	return handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionTrigger, object)
+}
+
+func (handler *HelmDriftRestHandlerImpl) decodeAndValidate(w http.ResponseWriter, r *http.Request, request interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("error in decoding helm drift request", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return false
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err in helm drift request", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helmDrift
+
+import "github.com/gorilla/mux"
+
+type HelmDriftRouter interface {
+	InitHelmDriftRouter(router *mux.Router)
+}
+
+type HelmDriftRouterImpl struct {
+	helmDriftRestHandler HelmDriftRestHandler
+}
+
+func NewHelmDriftRouterImpl(helmDriftRestHandler HelmDriftRestHandler) *HelmDriftRouterImpl {
+	return &HelmDriftRouterImpl{
+		helmDriftRestHandler: helmDriftRestHandler,
+	}
+}
+
+func (router *HelmDriftRouterImpl) InitHelmDriftRouter(helmDriftRouter *mux.Router) {
+	helmDriftRouter.Path("").
+		Queries("appId", "{appId}", "envId", "{envId}").
+		HandlerFunc(router.helmDriftRestHandler.GetDriftReport).
+		Methods("GET")
+
+	helmDriftRouter.Path("/check").
+		HandlerFunc(router.helmDriftRestHandler.CheckDrift).
+		Methods("POST")
+
+	helmDriftRouter.Path("/reapply").
+		HandlerFunc(router.helmDriftRestHandler.ReapplyRelease).
+		Methods("POST")
+
+	helmDriftRouter.Path("/auto-remediate").
+		HandlerFunc(router.helmDriftRestHandler.UpdateAutoRemediate).
+		Methods("PUT")
+}
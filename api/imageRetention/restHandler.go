@@ -0,0 +1,216 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package imageRetention
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/build/artifacts/retention"
+	"github.com/devtron-labs/devtron/pkg/build/artifacts/retention/bean"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type ImageRetentionRestHandler interface {
+	GetAllPolicies(w http.ResponseWriter, r *http.Request)
+	GetPolicy(w http.ResponseWriter, r *http.Request)
+	CreatePolicy(w http.ResponseWriter, r *http.Request)
+	UpdatePolicy(w http.ResponseWriter, r *http.Request)
+	DeletePolicy(w http.ResponseWriter, r *http.Request)
+	RunPolicy(w http.ResponseWriter, r *http.Request)
+}
+
+type ImageRetentionRestHandlerImpl struct {
+	logger                *zap.SugaredLogger
+	imageRetentionService retention.ImageRetentionService
+	userService           user.UserService
+	enforcer              casbin.Enforcer
+	validator             *validator.Validate
+}
+
+func NewImageRetentionRestHandlerImpl(logger *zap.SugaredLogger,
+	imageRetentionService retention.ImageRetentionService,
+	userService user.UserService, enforcer casbin.Enforcer,
+	validator *validator.Validate) *ImageRetentionRestHandlerImpl {
+	return &ImageRetentionRestHandlerImpl{
+		logger:                logger,
+		imageRetentionService: imageRetentionService,
+		userService:           userService,
+		enforcer:              enforcer,
+		validator:             validator,
+	}
+}
+
+func (handler *ImageRetentionRestHandlerImpl) GetAllPolicies(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.checkAccess(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	resp, err := handler.imageRetentionService.GetAllPolicies()
+	if err != nil {
+		handler.logger.Errorw("error in fetching image retention policies", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ImageRetentionRestHandlerImpl) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.checkAccess(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	id, ok := handler.getPolicyId(w, r)
+	if !ok {
+		return
+	}
+	resp, err := handler.imageRetentionService.GetPolicy(id)
+	if err != nil {
+		handler.logger.Errorw("error in fetching image retention policy", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ImageRetentionRestHandlerImpl) CreatePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkAccess(w, r, casbin.ActionCreate)
+	if !ok {
+		return
+	}
+	request := &bean.ImageRetentionPolicyDto{}
+	if !handler.decodeAndValidate(w, r, request) {
+		return
+	}
+	request.UserId = userId
+	resp, err := handler.imageRetentionService.CreatePolicy(request)
+	if err != nil {
+		handler.logger.Errorw("error in creating image retention policy", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ImageRetentionRestHandlerImpl) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkAccess(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	request := &bean.ImageRetentionPolicyDto{}
+	if !handler.decodeAndValidate(w, r, request) {
+		return
+	}
+	request.UserId = userId
+	resp, err := handler.imageRetentionService.UpdatePolicy(request)
+	if err != nil {
+		handler.logger.Errorw("error in updating image retention policy", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ImageRetentionRestHandlerImpl) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkAccess(w, r, casbin.ActionDelete)
+	if !ok {
+		return
+	}
+	id, ok := handler.getPolicyId(w, r)
+	if !ok {
+		return
+	}
+	err := handler.imageRetentionService.DeletePolicy(id, userId)
+	if err != nil {
+		handler.logger.Errorw("error in deleting image retention policy", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, id, http.StatusOK)
+}
+
+// RunPolicy purges the images deletable by the policy, with dryRun=true only the report is returned
+func (handler *ImageRetentionRestHandlerImpl) RunPolicy(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkAccess(w, r, casbin.ActionDelete)
+	if !ok {
+		return
+	}
+	id, ok := handler.getPolicyId(w, r)
+	if !ok {
+		return
+	}
+	dryRun := true
+	if dryRunParam := r.URL.Query().Get("dryRun"); len(dryRunParam) > 0 {
+		var err error
+		dryRun, err = strconv.ParseBool(dryRunParam)
+		if err != nil {
+			common.WriteJsonResp(w, err, "invalid dryRun", http.StatusBadRequest)
+			return
+		}
+	}
+	resp, err := handler.imageRetentionService.RunPolicy(r.Context(), id, dryRun, userId)
+	if err != nil {
+		handler.logger.Errorw("error in running image retention policy", "id", id, "dryRun", dryRun, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+// checkAccess requires container registry access as policies delete images from the registries
+func (handler *ImageRetentionRestHandlerImpl) checkAccess(w http.ResponseWriter, r *http.Request, action string) (int32, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, false
+	}
+	token := r.Header.Get("token")
+	if !handler.enforcer.Enforce(token, casbin.ResourceDocker, action, "*") {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return 0, false
+	}
+	return userId, true
+}
+
+func (handler *ImageRetentionRestHandlerImpl) getPolicyId(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid id", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+func (handler *ImageRetentionRestHandlerImpl) decodeAndValidate(w http.ResponseWriter, r *http.Request, request interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("error in decoding image retention request", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return false
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err in image retention request", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package imageRetention
+
+import "github.com/gorilla/mux"
+
+type ImageRetentionRouter interface {
+	InitImageRetentionRouter(router *mux.Router)
+}
+
+type ImageRetentionRouterImpl struct {
+	imageRetentionRestHandler ImageRetentionRestHandler
+}
+
+func NewImageRetentionRouterImpl(imageRetentionRestHandler ImageRetentionRestHandler) *ImageRetentionRouterImpl {
+	return &ImageRetentionRouterImpl{
+		imageRetentionRestHandler: imageRetentionRestHandler,
+	}
+}
+
+func (router *ImageRetentionRouterImpl) InitImageRetentionRouter(imageRetentionRouter *mux.Router) {
+	imageRetentionRouter.Path("/policy").
+		HandlerFunc(router.imageRetentionRestHandler.GetAllPolicies).
+		Methods("GET")
+
+	imageRetentionRouter.Path("/policy").
+		HandlerFunc(router.imageRetentionRestHandler.CreatePolicy).
+		Methods("POST")
+
+	imageRetentionRouter.Path("/policy").
+		HandlerFunc(router.imageRetentionRestHandler.UpdatePolicy).
+		Methods("PUT")
+
+	imageRetentionRouter.Path("/policy/{id}").
+		HandlerFunc(router.imageRetentionRestHandler.GetPolicy).
+		Methods("GET")
+
+	imageRetentionRouter.Path("/policy/{id}").
+		HandlerFunc(router.imageRetentionRestHandler.DeletePolicy).
+		Methods("DELETE")
+
+	imageRetentionRouter.Path("/policy/{id}/run").
+		HandlerFunc(router.imageRetentionRestHandler.RunPolicy).
+		Methods("POST")
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	util3 "github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/k8s/aggregatedLogs/bean"
+	bean2 "github.com/devtron-labs/devtron/pkg/k8s/application/bean"
+	"github.com/devtron-labs/devtron/util"
+	"github.com/google/uuid"
+	errors2 "github.com/juju/errors"
+	"io"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"net/http"
+	"strconv"
+)
+
+func (handler *K8sApplicationRestHandlerImpl) GetAggregatedPodLogs(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("token")
+	request, logTargets, ok := handler.getAggregatedLogsRequest(w, r, token)
+	if !ok {
+		return
+	}
+	lastEventId := r.Header.Get(bean2.LastEventID)
+	isReconnect := false
+	if len(lastEventId) > 0 {
+		lastSeenMsgId, err := strconv.ParseInt(lastEventId, bean2.IntegerBase, bean2.IntegerBitSize)
+		if err != nil {
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+		lastSeenMsgId = lastSeenMsgId + bean2.TimestampOffsetToAvoidDuplicateLogs //increased by one ns to avoid duplicate
+		t := v1.Unix(0, lastSeenMsgId)
+		request.ResourceRequest.K8sRequest.PodLogsRequest.SinceTime = &t
+		isReconnect = true
+	}
+	stream, err := handler.aggregatedLogsService.StreamLogs(r.Context(), request, logTargets)
+	//err is handled inside StartK8sStreamWithHeartBeat method
+	ctx, cancel := context.WithCancel(r.Context())
+	if cn, ok := w.(http.CloseNotifier); ok {
+		go func(done <-chan struct{}, closed <-chan bool) {
+			select {
+			case <-done:
+			case <-closed:
+				cancel()
+			}
+		}(ctx.Done(), cn.CloseNotify())
+	}
+	defer cancel()
+	defer util.Close(stream, handler.logger)
+	handler.pump.StartK8sStreamWithHeartBeat(w, isReconnect, stream, err)
+}
+
+func (handler *K8sApplicationRestHandlerImpl) DownloadAggregatedPodLogs(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("token")
+	request, logTargets, ok := handler.getAggregatedLogsRequest(w, r, token)
+	if !ok {
+		return
+	}
+	// just to make sure follow flag is set to false when downloading logs
+	request.ResourceRequest.K8sRequest.PodLogsRequest.Follow = false
+	stream, err := handler.aggregatedLogsService.DownloadLogs(r.Context(), request, logTargets)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	defer util.Close(stream, handler.logger)
+	archiveName := request.LabelSelector
+	if request.IsWorkloadRequest() {
+		archiveName = request.WorkloadName
+	}
+	w.Header().Set(common.CONTENT_TYPE, "application/zip")
+	w.Header().Set(common.CONTENT_DISPOSITION, fmt.Sprintf("attachment; filename=%s-%s-%s.zip", bean.ArchiveFilePrefix, archiveName, uuid.New().String()))
+	w.WriteHeader(http.StatusOK)
+	if _, err = io.Copy(w, stream); err != nil {
+		handler.logger.Errorw("error in writing pod logs archive", "namespace", logTargets.Namespace, "err", err)
+	}
+}
+
+// getAggregatedLogsRequest parses the request and resolves the pod containers to stream logs of. App scoped requests
+// select pods by a workload of the app, validated like any other app resource, whereas resource browser requests need
+// get access on every selected pod
+func (handler *K8sApplicationRestHandlerImpl) getAggregatedLogsRequest(w http.ResponseWriter, r *http.Request, token string) (*bean.AggregatedLogsRequest, *bean.LogTargets, bool) {
+	resourceRequest, err := handler.k8sApplicationService.ValidatePodLogsRequestQuery(r)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, nil, false
+	}
+	v := r.URL.Query()
+	request := &bean.AggregatedLogsRequest{
+		ResourceRequest: resourceRequest,
+		LabelSelector:   v.Get(bean.LabelSelectorParam),
+		WorkloadKind:    v.Get(bean.WorkloadKindParam),
+		WorkloadName:    v.Get(bean.WorkloadNameParam),
+		Grep:            v.Get(bean.GrepParam),
+		Regex:           v.Get(bean.RegexParam),
+	}
+	isAppRequest := resourceRequest.AppIdentifier != nil || resourceRequest.DevtronAppIdentifier != nil ||
+		resourceRequest.ExternalFluxAppIdentifier != nil || len(resourceRequest.ExternalArgoApplicationName) > 0
+	if request.IsWorkloadRequest() {
+		gvk, ok := bean.WorkloadGVKs[request.WorkloadKind]
+		if !ok || len(request.WorkloadName) == 0 {
+			common.WriteJsonResp(w, fmt.Errorf("workloadName and a workloadKind out of Deployment, StatefulSet and Rollout are required"), nil, http.StatusBadRequest)
+			return nil, nil, false
+		}
+		resourceRequest.K8sRequest.ResourceIdentifier.Name = request.WorkloadName
+		resourceRequest.K8sRequest.ResourceIdentifier.GroupVersionKind = gvk
+	} else if len(request.LabelSelector) == 0 {
+		common.WriteJsonResp(w, errors.New("either labelSelector or workloadKind and workloadName are required"), nil, http.StatusBadRequest)
+		return nil, nil, false
+	} else if isAppRequest {
+		common.WriteJsonResp(w, errors.New("pods of an app can only be selected by workload"), nil, http.StatusBadRequest)
+		return nil, nil, false
+	}
+	if isAppRequest {
+		if ok := handler.requestValidationAndRBAC(w, r, token, resourceRequest); !ok {
+			return nil, nil, false
+		}
+	} else if resourceRequest.ClusterId <= 0 {
+		common.WriteJsonResp(w, errors.New("can not get pod logs as target cluster is not provided"), nil, http.StatusBadRequest)
+		return nil, nil, false
+	}
+	logTargets, err := handler.aggregatedLogsService.GetLogTargets(r.Context(), request)
+	if err != nil {
+		handler.logger.Errorw("error in resolving pods for aggregated logs", "clusterId", resourceRequest.ClusterId, "labelSelector", request.LabelSelector,
+			"workloadKind", request.WorkloadKind, "workloadName", request.WorkloadName, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return nil, nil, false
+	}
+	if !isAppRequest {
+		//RBAC enforcer applying For Resource Browser
+		for _, podName := range logTargets.PodNames() {
+			podIdentifier := util3.ResourceIdentifier{
+				Name:             podName,
+				Namespace:        logTargets.Namespace,
+				GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "Pod"},
+			}
+			if !handler.verifyRbacForResource(token, logTargets.ClusterName, podIdentifier, casbin.ActionGet) {
+				common.WriteJsonResp(w, errors2.New("unauthorized"), nil, http.StatusForbidden)
+				return nil, nil, false
+			}
+		}
+		//RBAC enforcer Ends
+	}
+	return request, logTargets, true
+}
@@ -41,9 +41,11 @@ import (
 	clientErrors "github.com/devtron-labs/devtron/pkg/errors"
 	"github.com/devtron-labs/devtron/pkg/fluxApplication"
 	"github.com/devtron-labs/devtron/pkg/k8s"
+	"github.com/devtron-labs/devtron/pkg/k8s/aggregatedLogs"
 	application2 "github.com/devtron-labs/devtron/pkg/k8s/application"
 	bean2 "github.com/devtron-labs/devtron/pkg/k8s/application/bean"
 	bean3 "github.com/devtron-labs/devtron/pkg/k8s/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/portForward"
 	"github.com/devtron-labs/devtron/pkg/terminal"
 	"github.com/devtron-labs/devtron/util"
 	"github.com/devtron-labs/devtron/util/rbac"
@@ -80,6 +82,11 @@ type K8sApplicationRestHandler interface {
 	CreateEphemeralContainer(w http.ResponseWriter, r *http.Request)
 	DeleteEphemeralContainer(w http.ResponseWriter, r *http.Request)
 	GetAllApiResourceGVKWithoutAuthorization(w http.ResponseWriter, r *http.Request)
+	CreatePortForwardSession(w http.ResponseWriter, r *http.Request)
+	ConnectPortForwardSession(w http.ResponseWriter, r *http.Request)
+	ClosePortForwardSession(w http.ResponseWriter, r *http.Request)
+	GetAggregatedPodLogs(w http.ResponseWriter, r *http.Request)
+	DownloadAggregatedPodLogs(w http.ResponseWriter, r *http.Request)
 }
 
 type K8sApplicationRestHandlerImpl struct {
@@ -97,9 +104,13 @@ type K8sApplicationRestHandlerImpl struct {
 	terminalEnvVariables       *util.TerminalEnvVariables
 	fluxAppService             fluxApplication.FluxApplicationService
 	argoApplicationReadService read.ArgoApplicationReadService
+	portForwardService         portForward.PortForwardService
+	aggregatedLogsService      aggregatedLogs.AggregatedLogsService
 }
 
 func NewK8sApplicationRestHandlerImpl(logger *zap.SugaredLogger, k8sApplicationService application2.K8sApplicationService, pump connector.Pump, terminalSessionHandler terminal.TerminalSessionHandler, enforcer casbin.Enforcer, enforcerUtilHelm rbac.EnforcerUtilHelm, enforcerUtil rbac.EnforcerUtil, helmAppService client.HelmAppService, userService user.UserService, k8sCommonService k8s.K8sCommonService, validator *validator.Validate, envVariables *util.EnvironmentVariables, fluxAppService fluxApplication.FluxApplicationService, argoApplicationReadService read.ArgoApplicationReadService,
+	portForwardService portForward.PortForwardService,
+	aggregatedLogsService aggregatedLogs.AggregatedLogsService,
 ) *K8sApplicationRestHandlerImpl {
 	return &K8sApplicationRestHandlerImpl{
 		logger:                     logger,
@@ -116,6 +127,8 @@ func NewK8sApplicationRestHandlerImpl(logger *zap.SugaredLogger, k8sApplicationS
 		terminalEnvVariables:       envVariables.TerminalEnvVariables,
 		fluxAppService:             fluxAppService,
 		argoApplicationReadService: argoApplicationReadService,
+		portForwardService:         portForwardService,
+		aggregatedLogsService:      aggregatedLogsService,
 	}
 }
 
@@ -564,7 +577,9 @@ func (handler *K8sApplicationRestHandlerImpl) GetPodLogs(w http.ResponseWriter,
 		return
 	}
 	handler.logger.Infow("get pod logs request", "request", request)
-	handler.requestValidationAndRBAC(w, r, token, request)
+	if ok := handler.requestValidationAndRBAC(w, r, token, request); !ok {
+		return
+	}
 	lastEventId := r.Header.Get(bean2.LastEventID)
 	isReconnect := false
 	if len(lastEventId) > 0 {
@@ -602,7 +617,9 @@ func (handler *K8sApplicationRestHandlerImpl) DownloadPodLogs(w http.ResponseWri
 		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
 		return
 	}
-	handler.requestValidationAndRBAC(w, r, token, request)
+	if ok := handler.requestValidationAndRBAC(w, r, token, request); !ok {
+		return
+	}
 
 	// just to make sure follow flag is set to false when downloading logs
 	request.K8sRequest.PodLogsRequest.Follow = false
@@ -679,12 +696,12 @@ func generatePodLogsFilename(filename string) string {
 	return fmt.Sprintf("podlogs-%s-%s.log", filename, uuid.New().String())
 }
 
-func (handler *K8sApplicationRestHandlerImpl) requestValidationAndRBAC(w http.ResponseWriter, r *http.Request, token string, request *bean3.ResourceRequestBean) {
+func (handler *K8sApplicationRestHandlerImpl) requestValidationAndRBAC(w http.ResponseWriter, r *http.Request, token string, request *bean3.ResourceRequestBean) bool {
 	if request.AppType == bean2.HelmAppType && request.AppIdentifier != nil {
 		if request.DeploymentType == bean2.HelmInstalledType {
 			if err := handler.k8sApplicationService.ValidateResourceRequest(r.Context(), request.AppIdentifier, request.K8sRequest); err != nil {
 				common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
-				return
+				return false
 			}
 		} else if request.DeploymentType == bean2.ArgoInstalledType {
 			//TODO Implement ResourceRequest Validation for ArgoCD Installed APPs From ResourceTree
@@ -695,7 +712,7 @@ func (handler *K8sApplicationRestHandlerImpl) requestValidationAndRBAC(w http.Re
 
 		if !ok {
 			common.WriteJsonResp(w, errors2.New("unauthorized"), nil, http.StatusForbidden)
-			return
+			return false
 		}
 		//RBAC enforcer Ends
 	} else if request.AppType == bean2.DevtronAppType && request.DevtronAppIdentifier != nil {
@@ -708,7 +725,7 @@ func (handler *K8sApplicationRestHandlerImpl) requestValidationAndRBAC(w http.Re
 		envObject := handler.enforcerUtil.GetEnvRBACNameByAppId(request.DevtronAppIdentifier.AppId, request.DevtronAppIdentifier.EnvId)
 		if !handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionGet, envObject) {
 			common.WriteJsonResp(w, errors2.New("unauthorized"), nil, http.StatusForbidden)
-			return
+			return false
 		}
 		//RBAC enforcer Ends
 	} else if request.AppType == bean2.FluxAppType && request.ExternalFluxAppIdentifier != nil {
@@ -716,12 +733,12 @@ func (handler *K8sApplicationRestHandlerImpl) requestValidationAndRBAC(w http.Re
 		if err != nil || !valid {
 			handler.logger.Errorw("error in validating resource request", "err", err)
 			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
-			return
+			return false
 		}
 		//RBAC enforcer starts here
 		if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
 			common.WriteJsonResp(w, errors2.New("unauthorized"), nil, http.StatusForbidden)
-			return
+			return false
 		}
 		//RBAC enforcer ends here
 	} else if request.AppType == bean2.ArgoAppType && request.ExternalArgoApplicationName != "" {
@@ -729,30 +746,32 @@ func (handler *K8sApplicationRestHandlerImpl) requestValidationAndRBAC(w http.Re
 		if err != nil {
 			handler.logger.Errorw(bean2.AppIdDecodingError, "err", err, "appIdentifier", request.AppIdentifier)
 			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return false
 		}
 		valid, err := handler.argoApplicationReadService.ValidateArgoResourceRequest(r.Context(), appIdentifier, request.K8sRequest)
 		if err != nil || !valid {
 			handler.logger.Errorw("error in validating resource request", "err", err)
 			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
-			return
+			return false
 		}
 
 		//RBAC enforcer starts here
 		if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*"); !ok {
 			common.WriteJsonResp(w, errors2.New("unauthorized"), nil, http.StatusForbidden)
-			return
+			return false
 		}
 		//RBAC enforcer ends here
 	} else if request.AppIdentifier == nil && request.DevtronAppIdentifier == nil && request.ClusterId > 0 && request.ExternalArgoApplicationName == "" {
 		//RBAC enforcer applying For Resource Browser
 		if !handler.handleRbac(r, w, *request, token, casbin.ActionGet) {
-			return
+			return false
 		}
 		//RBAC enforcer Ends
 	} else if request.ClusterId <= 0 {
 		common.WriteJsonResp(w, errors.New("can not get pod logs as target cluster is not provided"), nil, http.StatusBadRequest)
-		return
+		return false
 	}
+	return true
 }
 
 func (handler *K8sApplicationRestHandlerImpl) restrictTerminalAccessForNonSuperUsers(w http.ResponseWriter, token string) bool {
@@ -778,10 +797,20 @@ func (handler *K8sApplicationRestHandlerImpl) GetTerminalSession(w http.Response
 		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
 		return
 	}
+	if ok := handler.verifyTerminalAccess(w, r, token, resourceRequestBean); !ok {
+		return
+	}
+	request.UserId = userId
+	status, message, err := handler.terminalSessionHandler.GetTerminalSession(request)
+	common.WriteJsonResp(w, err, message, status)
+}
+
+// verifyTerminalAccess applies the terminal exec permission, also required for port-forwarding to pods and services
+func (handler *K8sApplicationRestHandlerImpl) verifyTerminalAccess(w http.ResponseWriter, r *http.Request, token string, resourceRequestBean *bean3.ResourceRequestBean) bool {
 	// check for super admin
 	restricted := handler.restrictTerminalAccessForNonSuperUsers(w, token)
 	if restricted {
-		return
+		return false
 	}
 	if resourceRequestBean.AppIdentifier != nil {
 		// RBAC enforcer applying For Helm App
@@ -790,7 +819,7 @@ func (handler *K8sApplicationRestHandlerImpl) GetTerminalSession(w http.Response
 
 		if !ok {
 			common.WriteJsonResp(w, errors2.New("unauthorized"), nil, http.StatusForbidden)
-			return
+			return false
 		}
 		//RBAC enforcer Ends
 	} else if resourceRequestBean.DevtronAppIdentifier != nil {
@@ -798,14 +827,14 @@ func (handler *K8sApplicationRestHandlerImpl) GetTerminalSession(w http.Response
 		envObject := handler.enforcerUtil.GetEnvRBACNameByAppId(resourceRequestBean.DevtronAppIdentifier.AppId, resourceRequestBean.DevtronAppIdentifier.EnvId)
 		if !handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionUpdate, envObject) {
 			common.WriteJsonResp(w, errors2.New("unauthorized"), nil, http.StatusForbidden)
-			return
+			return false
 		}
 		//RBAC enforcer Ends
 	} else if resourceRequestBean.ExternalFluxAppIdentifier != nil {
 		// RBAC enforcer applying For external flux app
 		if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
 			common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
-			return
+			return false
 		}
 		//RBAC enforcer Ends
 
@@ -813,7 +842,7 @@ func (handler *K8sApplicationRestHandlerImpl) GetTerminalSession(w http.Response
 		// RBAC enforcer applying For external Argo app
 		if ok := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
 			common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
-			return
+			return false
 		}
 		//RBAC enforcer Ends
 
@@ -821,16 +850,14 @@ func (handler *K8sApplicationRestHandlerImpl) GetTerminalSession(w http.Response
 		//RBAC enforcer applying for Resource Browser
 		resource, object := handler.enforcerUtil.GetRbacResourceAndObjectForNodeByClusterId(resourceRequestBean.ClusterId, bean2.ALL)
 		if !(handler.enforcer.Enforce(token, resource, casbin.ActionUpdate, object) || handler.handleRbac(r, w, *resourceRequestBean, token, casbin.ActionUpdate)) {
-			return
+			return false
 		}
 		//RBAC enforcer Ends
 	} else if resourceRequestBean.ClusterId <= 0 {
 		common.WriteJsonResp(w, errors.New("can not get terminal session as target cluster is not provided"), nil, http.StatusBadRequest)
-		return
+		return false
 	}
-	request.UserId = userId
-	status, message, err := handler.terminalSessionHandler.GetTerminalSession(request)
-	common.WriteJsonResp(w, err, message, status)
+	return true
 }
 
 func (handler *K8sApplicationRestHandlerImpl) GetResourceInfo(w http.ResponseWriter, r *http.Request) {
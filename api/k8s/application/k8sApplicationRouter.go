@@ -58,6 +58,11 @@ func (impl *K8sApplicationRouterImpl) InitK8sApplicationRouter(k8sAppRouter *mux
 	k8sAppRouter.Path("/events").
 		HandlerFunc(impl.k8sApplicationRestHandler.ListEvents).Methods("POST")
 
+	k8sAppRouter.Path("/pods/aggregated-logs").
+		HandlerFunc(impl.k8sApplicationRestHandler.GetAggregatedPodLogs).Methods("GET")
+	k8sAppRouter.Path("/pods/aggregated-logs/download").
+		HandlerFunc(impl.k8sApplicationRestHandler.DownloadAggregatedPodLogs).Methods("GET")
+
 	k8sAppRouter.Path("/pods/logs/{podName}").
 		Queries("containerName", "{containerName}").
 		Queries("follow", "{follow}").
@@ -71,6 +76,15 @@ func (impl *K8sApplicationRouterImpl) InitK8sApplicationRouter(k8sAppRouter *mux
 		HandlerFunc(impl.k8sApplicationRestHandler.GetTerminalSession).Methods("GET")
 	k8sAppRouter.PathPrefix("/pod/exec/sockjs/ws").Handler(terminal.CreateAttachHandler("/pod/exec/sockjs/ws"))
 
+	k8sAppRouter.Path("/port-forward/session/{identifier}/{namespace}/pod/{pod}/{port}").
+		HandlerFunc(impl.k8sApplicationRestHandler.CreatePortForwardSession).Methods("POST")
+	k8sAppRouter.Path("/port-forward/session/{identifier}/{namespace}/service/{service}/{port}").
+		HandlerFunc(impl.k8sApplicationRestHandler.CreatePortForwardSession).Methods("POST")
+	k8sAppRouter.Path("/port-forward/session/{sessionId}").
+		HandlerFunc(impl.k8sApplicationRestHandler.ClosePortForwardSession).Methods("DELETE")
+	k8sAppRouter.Path("/port-forward/connect/{sessionId}").
+		HandlerFunc(impl.k8sApplicationRestHandler.ConnectPortForwardSession).Methods("GET")
+
 	/*k8sAppRouter.Path("/pod/exec/sockjs/ws/").
 	Handler(terminal.CreateAttachHandler("/api/v1/applications/pod/exec/sockjs/ws/"))*/
 
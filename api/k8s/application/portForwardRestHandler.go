@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package application
+
+import (
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/k8s/portForward/bean"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"net/http"
+	"strconv"
+)
+
+var portForwardUpgrader = websocket.Upgrader{
+	ReadBufferSize:  32 * 1024,
+	WriteBufferSize: 32 * 1024,
+}
+
+func (handler *K8sApplicationRestHandlerImpl) CreatePortForwardSession(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("token")
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request, resourceRequestBean, err := handler.k8sApplicationService.ValidateTerminalRequestQuery(r)
+	if err != nil || request == nil {
+		common.WriteJsonResp(w, err, "invalid identifier", http.StatusBadRequest)
+		return
+	}
+	vars := mux.Vars(r)
+	port, err := strconv.Atoi(vars["port"])
+	if err != nil || port <= 0 || port > 65535 {
+		common.WriteJsonResp(w, errors.New("invalid port"), nil, http.StatusBadRequest)
+		return
+	}
+	serviceName := vars["service"]
+	if len(serviceName) > 0 && resourceRequestBean.K8sRequest != nil {
+		// resource browser access is checked on the service instead of the pod
+		resourceRequestBean.K8sRequest.ResourceIdentifier.Name = serviceName
+		resourceRequestBean.K8sRequest.ResourceIdentifier.GroupVersionKind = schema.GroupVersionKind{Version: "v1", Kind: "Service"}
+	}
+	if ok := handler.verifyTerminalAccess(w, r, token, resourceRequestBean); !ok {
+		return
+	}
+	resp, err := handler.portForwardService.CreateSession(r.Context(), &bean.PortForwardSessionRequest{
+		ClusterId:                   request.ClusterId,
+		ExternalArgoApplicationName: request.ExternalArgoApplicationName,
+		Namespace:                   request.Namespace,
+		PodName:                     request.PodName,
+		ServiceName:                 serviceName,
+		Port:                        port,
+		UserId:                      userId,
+	})
+	if err != nil {
+		handler.logger.Errorw("error in creating port-forward session", "clusterId", request.ClusterId, "namespace", request.Namespace, "podName", request.PodName, "serviceName", serviceName, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *K8sApplicationRestHandlerImpl) ConnectPortForwardSession(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	sessionId := mux.Vars(r)["sessionId"]
+	err = handler.portForwardService.ValidateSession(sessionId, userId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	conn, err := portForwardUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader has responded with the error already
+		handler.logger.Errorw("error in upgrading port-forward connection", "sessionId", sessionId, "err", err)
+		return
+	}
+	_ = handler.portForwardService.Connect(sessionId, conn)
+}
+
+func (handler *K8sApplicationRestHandlerImpl) ClosePortForwardSession(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	sessionId := mux.Vars(r)["sessionId"]
+	err = handler.portForwardService.CloseSession(sessionId, userId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, sessionId, http.StatusOK)
+}
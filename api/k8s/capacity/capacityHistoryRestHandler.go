@@ -0,0 +1,190 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package capacity
+
+import (
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacityHistory/bean"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (handler *K8sCapacityRestHandlerImpl) GetCapacityHistory(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	vars := r.URL.Query()
+	clusterId, err := strconv.Atoi(vars.Get("clusterId"))
+	if err != nil {
+		handler.logger.Errorw("request err, GetCapacityHistory", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request := &bean.CapacityHistoryRequest{ClusterId: clusterId, Scope: vars.Get("scope")}
+	if len(request.Scope) == 0 {
+		request.Scope = bean.ScopeCluster
+	}
+	if names := vars.Get("names"); len(names) > 0 {
+		request.Names = strings.Split(names, ",")
+	}
+	request.From, err = parseHistoryTime(vars.Get("from"))
+	if err == nil {
+		request.To, err = parseHistoryTime(vars.Get("to"))
+	}
+	if err != nil {
+		handler.logger.Errorw("request err, GetCapacityHistory", "err", err, "from", vars.Get("from"), "to", vars.Get("to"))
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	if ok := handler.checkClusterViewAuthorisation(w, r, clusterId, userId); !ok {
+		return
+	}
+	history, err := handler.capacityHistoryService.GetCapacityHistory(request)
+	if err != nil {
+		handler.logger.Errorw("error in getting capacity history", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, history, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) GetRightSizingRecommendations(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	vars := r.URL.Query()
+	request := &bean.RecommendationRequest{}
+	for param, target := range map[string]*int{"clusterId": &request.ClusterId, "appId": &request.AppId, "envId": &request.EnvId} {
+		value := vars.Get(param)
+		if len(value) == 0 && param != "clusterId" {
+			continue
+		}
+		*target, err = strconv.Atoi(value)
+		if err != nil {
+			handler.logger.Errorw("request err, GetRightSizingRecommendations", "err", err, param, value)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+	if ok := handler.checkClusterViewAuthorisation(w, r, request.ClusterId, userId); !ok {
+		return
+	}
+	token := r.Header.Get("token")
+	isSingleAppEnv := request.AppId > 0 && request.EnvId > 0
+	if isSingleAppEnv {
+		// RBAC enforcer applying
+		appObject := handler.enforcerUtil.GetAppRBACNameByAppId(request.AppId)
+		envObject := handler.enforcerUtil.GetEnvRBACNameByAppId(request.AppId, request.EnvId)
+		if !handler.enforcer.Enforce(token, casbin.ResourceApplications, casbin.ActionGet, appObject) ||
+			!handler.enforcer.Enforce(token, casbin.ResourceEnvironment, casbin.ActionGet, envObject) {
+			common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+			return
+		}
+		//RBAC enforcer Ends
+	}
+	recommendations, err := handler.capacityHistoryService.GetRecommendations(request)
+	if err != nil {
+		handler.logger.Errorw("error in getting right sizing recommendations", "err", err, "request", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	if !isSingleAppEnv {
+		recommendations, err = handler.filterAuthorisedRecommendations(token, recommendations)
+		if err != nil {
+			handler.logger.Errorw("error in checking rbac for right sizing recommendations", "err", err, "request", request)
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+			return
+		}
+	}
+	common.WriteJsonResp(w, nil, recommendations, http.StatusOK)
+}
+
+// filterAuthorisedRecommendations keeps the recommendations of the apps and envs the user has get access on
+func (handler *K8sCapacityRestHandlerImpl) filterAuthorisedRecommendations(token string, recommendations []*bean.RightSizingRecommendation) ([]*bean.RightSizingRecommendation, error) {
+	if len(recommendations) == 0 {
+		return recommendations, nil
+	}
+	appEnvPairs := make(map[int][2]int, len(recommendations))
+	for i, recommendation := range recommendations {
+		appEnvPairs[i] = [2]int{recommendation.AppId, recommendation.EnvId}
+	}
+	appObjects, envObjects, _, _, err := handler.enforcerUtil.GetAppAndEnvRBACNamesByAppAndEnvIds(appEnvPairs)
+	if err != nil {
+		return nil, err
+	}
+	appRbacObjects := make([]string, 0, len(appObjects))
+	for _, appObject := range appObjects {
+		appRbacObjects = append(appRbacObjects, appObject)
+	}
+	envRbacObjects := make([]string, 0, len(envObjects))
+	for _, envObject := range envObjects {
+		envRbacObjects = append(envRbacObjects, envObject)
+	}
+	appResults := handler.enforcer.EnforceInBatch(token, casbin.ResourceApplications, casbin.ActionGet, appRbacObjects)
+	envResults := handler.enforcer.EnforceInBatch(token, casbin.ResourceEnvironment, casbin.ActionGet, envRbacObjects)
+	authorisedRecommendations := make([]*bean.RightSizingRecommendation, 0, len(recommendations))
+	for i, recommendation := range recommendations {
+		// pairs of deleted apps or envs have no rbac objects and are dropped
+		appObject, appExists := appObjects[i]
+		envObject, envExists := envObjects[i]
+		if appExists && envExists && appResults[appObject] && envResults[envObject] {
+			authorisedRecommendations = append(authorisedRecommendations, recommendation)
+		}
+	}
+	return authorisedRecommendations, nil
+}
+
+func (handler *K8sCapacityRestHandlerImpl) checkClusterViewAuthorisation(w http.ResponseWriter, r *http.Request, clusterId int, userId int32) bool {
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	cluster, err := handler.clusterReadService.FindById(clusterId)
+	if err != nil {
+		handler.logger.Errorw("error in getting cluster by id", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return false
+	}
+	authenticated, err := handler.clusterRbacService.CheckAuthorization(cluster.ClusterName, cluster.Id, token, userId, true)
+	if err != nil {
+		handler.logger.Errorw("error in checking rbac for cluster", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return false
+	}
+	if !authenticated {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// parseHistoryTime accepts unix seconds or RFC3339, an empty value is the zero time
+func parseHistoryTime(value string) (time.Time, error) {
+	if len(value) == 0 {
+		return time.Time{}, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
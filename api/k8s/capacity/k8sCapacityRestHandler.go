@@ -26,6 +26,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/cluster/rbac"
 	"github.com/devtron-labs/devtron/pkg/cluster/read"
 	bean3 "github.com/devtron-labs/devtron/pkg/k8s/bean"
+	rbac2 "github.com/devtron-labs/devtron/util/rbac"
 	"net/http"
 	"strconv"
 
@@ -35,6 +36,8 @@ import (
 	"github.com/devtron-labs/devtron/pkg/cluster"
 	"github.com/devtron-labs/devtron/pkg/k8s/capacity"
 	"github.com/devtron-labs/devtron/pkg/k8s/capacity/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacityHistory"
+	"github.com/devtron-labs/devtron/pkg/k8s/nodeMaintenance"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 )
@@ -50,16 +53,28 @@ type K8sCapacityRestHandler interface {
 	CordonOrUnCordonNode(w http.ResponseWriter, r *http.Request)
 	DrainNode(w http.ResponseWriter, r *http.Request)
 	EditNodeTaints(w http.ResponseWriter, r *http.Request)
+	AnalyseNodeMaintenance(w http.ResponseWriter, r *http.Request)
+	StartNodeMaintenance(w http.ResponseWriter, r *http.Request)
+	GetNodeMaintenanceList(w http.ResponseWriter, r *http.Request)
+	GetNodeMaintenance(w http.ResponseWriter, r *http.Request)
+	CompleteNodeMaintenance(w http.ResponseWriter, r *http.Request)
+	CancelNodeMaintenance(w http.ResponseWriter, r *http.Request)
+	ResumeNodeMaintenance(w http.ResponseWriter, r *http.Request)
+	GetCapacityHistory(w http.ResponseWriter, r *http.Request)
+	GetRightSizingRecommendations(w http.ResponseWriter, r *http.Request)
 }
 type K8sCapacityRestHandlerImpl struct {
-	logger             *zap.SugaredLogger
-	k8sCapacityService capacity.K8sCapacityService
-	userService        user.UserService
-	enforcer           casbin.Enforcer
-	clusterService     cluster.ClusterService
-	environmentService environment.EnvironmentService
-	clusterRbacService rbac.ClusterRbacService
-	clusterReadService read.ClusterReadService
+	logger                 *zap.SugaredLogger
+	k8sCapacityService     capacity.K8sCapacityService
+	userService            user.UserService
+	enforcer               casbin.Enforcer
+	clusterService         cluster.ClusterService
+	environmentService     environment.EnvironmentService
+	clusterRbacService     rbac.ClusterRbacService
+	clusterReadService     read.ClusterReadService
+	nodeMaintenanceService nodeMaintenance.NodeMaintenanceService
+	capacityHistoryService capacityHistory.CapacityHistoryService
+	enforcerUtil           rbac2.EnforcerUtil
 }
 
 func NewK8sCapacityRestHandlerImpl(logger *zap.SugaredLogger,
@@ -68,16 +83,22 @@ func NewK8sCapacityRestHandlerImpl(logger *zap.SugaredLogger,
 	clusterService cluster.ClusterService,
 	environmentService environment.EnvironmentService,
 	clusterRbacService rbac.ClusterRbacService,
-	clusterReadService read.ClusterReadService) *K8sCapacityRestHandlerImpl {
+	clusterReadService read.ClusterReadService,
+	nodeMaintenanceService nodeMaintenance.NodeMaintenanceService,
+	capacityHistoryService capacityHistory.CapacityHistoryService,
+	enforcerUtil rbac2.EnforcerUtil) *K8sCapacityRestHandlerImpl {
 	return &K8sCapacityRestHandlerImpl{
-		logger:             logger,
-		k8sCapacityService: k8sCapacityService,
-		userService:        userService,
-		enforcer:           enforcer,
-		clusterService:     clusterService,
-		environmentService: environmentService,
-		clusterRbacService: clusterRbacService,
-		clusterReadService: clusterReadService,
+		logger:                 logger,
+		k8sCapacityService:     k8sCapacityService,
+		userService:            userService,
+		enforcer:               enforcer,
+		clusterService:         clusterService,
+		environmentService:     environmentService,
+		clusterRbacService:     clusterRbacService,
+		clusterReadService:     clusterReadService,
+		nodeMaintenanceService: nodeMaintenanceService,
+		capacityHistoryService: capacityHistoryService,
+		enforcerUtil:           enforcerUtil,
 	}
 }
 
@@ -43,6 +43,12 @@ func (impl *K8sCapacityRouterImpl) InitK8sCapacityRouter(k8sCapacityRouter *mux.
 	k8sCapacityRouter.Path("/cluster/{clusterId}").
 		HandlerFunc(impl.k8sCapacityRestHandler.GetClusterDetail).Methods("GET")
 
+	k8sCapacityRouter.Path("/history").
+		HandlerFunc(impl.k8sCapacityRestHandler.GetCapacityHistory).Methods("GET")
+
+	k8sCapacityRouter.Path("/recommendations").
+		HandlerFunc(impl.k8sCapacityRestHandler.GetRightSizingRecommendations).Methods("GET")
+
 	k8sCapacityRouter.Path("/node/list").
 		HandlerFunc(impl.k8sCapacityRestHandler.GetNodeList).Methods("GET")
 
@@ -63,4 +69,25 @@ func (impl *K8sCapacityRouterImpl) InitK8sCapacityRouter(k8sCapacityRouter *mux.
 
 	k8sCapacityRouter.Path("/node/taints/edit").
 		HandlerFunc(impl.k8sCapacityRestHandler.EditNodeTaints).Methods("PUT")
+
+	k8sCapacityRouter.Path("/node/maintenance/analyse").
+		HandlerFunc(impl.k8sCapacityRestHandler.AnalyseNodeMaintenance).Methods("POST")
+
+	k8sCapacityRouter.Path("/node/maintenance").
+		HandlerFunc(impl.k8sCapacityRestHandler.StartNodeMaintenance).Methods("POST")
+
+	k8sCapacityRouter.Path("/node/maintenance").
+		HandlerFunc(impl.k8sCapacityRestHandler.GetNodeMaintenanceList).Methods("GET")
+
+	k8sCapacityRouter.Path("/node/maintenance/{id}").
+		HandlerFunc(impl.k8sCapacityRestHandler.GetNodeMaintenance).Methods("GET")
+
+	k8sCapacityRouter.Path("/node/maintenance/{id}/complete").
+		HandlerFunc(impl.k8sCapacityRestHandler.CompleteNodeMaintenance).Methods("PUT")
+
+	k8sCapacityRouter.Path("/node/maintenance/{id}/cancel").
+		HandlerFunc(impl.k8sCapacityRestHandler.CancelNodeMaintenance).Methods("PUT")
+
+	k8sCapacityRouter.Path("/node/maintenance/{id}/resume").
+		HandlerFunc(impl.k8sCapacityRestHandler.ResumeNodeMaintenance).Methods("PUT")
 }
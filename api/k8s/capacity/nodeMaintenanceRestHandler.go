@@ -0,0 +1,256 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package capacity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/k8s/nodeMaintenance/bean"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+)
+
+func (handler *K8sCapacityRestHandlerImpl) AnalyseNodeMaintenance(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request, ok := handler.decodeNodeMaintenanceRequest(w, r)
+	if !ok {
+		return
+	}
+	if ok = handler.checkNodeMaintenanceRbac(w, r, request, casbin.ActionGet); !ok {
+		return
+	}
+	analysis, err := handler.nodeMaintenanceService.Analyse(r.Context(), request)
+	if err != nil {
+		handler.logger.Errorw("error in analysing node maintenance", "err", err, "req", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, analysis, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) StartNodeMaintenance(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	request, ok := handler.decodeNodeMaintenanceRequest(w, r)
+	if !ok {
+		return
+	}
+	request.UserId = userId
+	if ok = handler.checkNodeMaintenanceRbac(w, r, request, casbin.ActionUpdate); !ok {
+		return
+	}
+	resp, err := handler.nodeMaintenanceService.StartMaintenance(r.Context(), request)
+	if err != nil {
+		handler.logger.Errorw("error in starting node maintenance", "err", err, "req", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) GetNodeMaintenanceList(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	clusterId, err := strconv.Atoi(r.URL.Query().Get("clusterId"))
+	if err != nil {
+		handler.logger.Errorw("request err, GetNodeMaintenanceList", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	maintenances, err := handler.nodeMaintenanceService.GetMaintenances(clusterId)
+	if err != nil {
+		handler.logger.Errorw("error in getting node maintenances", "err", err, "clusterId", clusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	// RBAC enforcer applying, only the maintenances with all their nodes accessible are listed
+	token := r.Header.Get("token")
+	result := make([]*bean.NodeMaintenanceDto, 0, len(maintenances))
+	for _, maintenance := range maintenances {
+		authenticated, err := handler.checkNodesAuthorisation(token, clusterId, maintenance.NodeNames(), casbin.ActionGet)
+		if err != nil {
+			handler.logger.Errorw("error in checking rbac for cluster", "err", err, "clusterId", clusterId)
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+			return
+		}
+		if authenticated {
+			result = append(result, maintenance)
+		}
+	}
+	common.WriteJsonResp(w, nil, result, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) GetNodeMaintenance(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	maintenance, ok := handler.getAuthorisedNodeMaintenance(w, r, casbin.ActionGet)
+	if !ok {
+		return
+	}
+	common.WriteJsonResp(w, nil, maintenance, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) CompleteNodeMaintenance(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	maintenance, ok := handler.getAuthorisedNodeMaintenance(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	resp, err := handler.nodeMaintenanceService.CompleteMaintenance(maintenance.Id, userId)
+	if err != nil {
+		handler.logger.Errorw("error in completing node maintenance", "err", err, "id", maintenance.Id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) CancelNodeMaintenance(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	maintenance, ok := handler.getAuthorisedNodeMaintenance(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	resp, err := handler.nodeMaintenanceService.CancelMaintenance(maintenance.Id, userId)
+	if err != nil {
+		handler.logger.Errorw("error in cancelling node maintenance", "err", err, "id", maintenance.Id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) ResumeNodeMaintenance(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	maintenance, ok := handler.getAuthorisedNodeMaintenance(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	resp, err := handler.nodeMaintenanceService.ResumeMaintenance(maintenance.Id, userId)
+	if err != nil {
+		handler.logger.Errorw("error in resuming node maintenance", "err", err, "id", maintenance.Id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *K8sCapacityRestHandlerImpl) decodeNodeMaintenanceRequest(w http.ResponseWriter, r *http.Request) (*bean.NodeMaintenanceRequest, bool) {
+	decoder := json.NewDecoder(r.Body)
+	var request bean.NodeMaintenanceRequest
+	err := decoder.Decode(&request)
+	if err != nil {
+		handler.logger.Errorw("error in decoding request body", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	if request.ClusterId <= 0 || request.Concurrency < 0 || request.UncordonAfterSecs < 0 {
+		err = fmt.Errorf("invalid request, clusterId is required and concurrency and uncordonAfterSecs can not be negative")
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	return &request, true
+}
+
+// checkNodeMaintenanceRbac authorises the action on every node the request resolves to
+func (handler *K8sCapacityRestHandlerImpl) checkNodeMaintenanceRbac(w http.ResponseWriter, r *http.Request, request *bean.NodeMaintenanceRequest, action string) bool {
+	nodeNames, err := handler.nodeMaintenanceService.ResolveNodeNames(r.Context(), request)
+	if err != nil {
+		handler.logger.Errorw("error in resolving nodes of node maintenance", "err", err, "req", request)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return false
+	}
+	token := r.Header.Get("token")
+	authenticated, err := handler.checkNodesAuthorisation(token, request.ClusterId, nodeNames, action)
+	if err != nil {
+		handler.logger.Errorw("error in checking rbac for cluster", "err", err, "clusterId", request.ClusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return false
+	}
+	if !authenticated {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func (handler *K8sCapacityRestHandlerImpl) getAuthorisedNodeMaintenance(w http.ResponseWriter, r *http.Request, action string) (*bean.NodeMaintenanceDto, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		handler.logger.Errorw("request err, node maintenance id", "err", err, "id", mux.Vars(r)["id"])
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	maintenance, err := handler.nodeMaintenanceService.GetMaintenance(id)
+	if err != nil {
+		handler.logger.Errorw("error in getting node maintenance", "err", err, "id", id)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return nil, false
+	}
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	authenticated, err := handler.checkNodesAuthorisation(token, maintenance.ClusterId, maintenance.NodeNames(), action)
+	if err != nil {
+		handler.logger.Errorw("error in checking rbac for cluster", "err", err, "clusterId", maintenance.ClusterId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return nil, false
+	}
+	if !authenticated {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return nil, false
+	}
+	return maintenance, true
+}
+
+func (handler *K8sCapacityRestHandlerImpl) checkNodesAuthorisation(token string, clusterId int, nodeNames []string, action string) (bool, error) {
+	for _, nodeName := range nodeNames {
+		authenticated, err := handler.clusterRbacService.CheckAuthorisationForNodeWithClusterId(token, clusterId, nodeName, action)
+		if err != nil || !authenticated {
+			return false, err
+		}
+	}
+	return true, nil
+}
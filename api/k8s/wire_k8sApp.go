@@ -22,9 +22,13 @@ import (
 	"github.com/devtron-labs/devtron/pkg/cluster"
 	clusterRepository "github.com/devtron-labs/devtron/pkg/cluster/repository"
 	"github.com/devtron-labs/devtron/pkg/k8s"
+	"github.com/devtron-labs/devtron/pkg/k8s/aggregatedLogs"
 	application2 "github.com/devtron-labs/devtron/pkg/k8s/application"
 	capacity2 "github.com/devtron-labs/devtron/pkg/k8s/capacity"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacityHistory"
 	"github.com/devtron-labs/devtron/pkg/k8s/informer"
+	"github.com/devtron-labs/devtron/pkg/k8s/nodeMaintenance"
+	"github.com/devtron-labs/devtron/pkg/k8s/portForward"
 	"github.com/devtron-labs/devtron/pkg/terminal"
 	"github.com/google/wire"
 )
@@ -53,4 +57,8 @@ var K8sApplicationWireSet = wire.NewSet(
 	informer.NewGlobalMapClusterNamespace,
 	informer.NewK8sInformerFactoryImpl,
 	wire.Bind(new(informer.K8sInformerFactory), new(*informer.K8sInformerFactoryImpl)),
+	portForward.WireSet,
+	aggregatedLogs.WireSet,
+	nodeMaintenance.WireSet,
+	capacityHistory.WireSet,
 )
@@ -36,6 +36,16 @@ type ApiToken struct {
 	LastUsedByIp *string `json:"lastUsedByIp,omitempty"`
 	// token last updatedAt
 	UpdatedAt *string `json:"updatedAt,omitempty"`
+	// Scope of api-token
+	Scope *ApiTokenScope `json:"scope,omitempty"`
+	// Ip addresses and cidr ranges the api-token is accepted from
+	AllowedIps []string `json:"allowedIps,omitempty"`
+	// Version of api-token, incremented on every regeneration or rotation
+	Version *int32 `json:"version,omitempty"`
+	// Time in milliseconds till which the previous version of a rotated api-token is accepted
+	PreviousVersionExpireAtInMs *int64 `json:"previousVersionExpireAtInMs,omitempty"`
+	// Version of api-token used last
+	LastUsedVersion *int32 `json:"lastUsedVersion,omitempty"`
 }
 
 // NewApiToken instantiates a new ApiToken object
@@ -375,6 +385,166 @@ func (o *ApiToken) SetUpdatedAt(v string) {
 	o.UpdatedAt = &v
 }
 
+// GetScope returns the Scope field value if set, zero value otherwise.
+func (o *ApiToken) GetScope() ApiTokenScope {
+	if o == nil || o.Scope == nil {
+		var ret ApiTokenScope
+		return ret
+	}
+	return *o.Scope
+}
+
+// GetScopeOk returns a tuple with the Scope field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ApiToken) GetScopeOk() (*ApiTokenScope, bool) {
+	if o == nil || o.Scope == nil {
+		return nil, false
+	}
+	return o.Scope, true
+}
+
+// HasScope returns a boolean if a field has been set.
+func (o *ApiToken) HasScope() bool {
+	if o != nil && o.Scope != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetScope gets a reference to the given ApiTokenScope and assigns it to the Scope field.
+func (o *ApiToken) SetScope(v ApiTokenScope) {
+	o.Scope = &v
+}
+
+// GetAllowedIps returns the AllowedIps field value if set, zero value otherwise.
+func (o *ApiToken) GetAllowedIps() []string {
+	if o == nil || o.AllowedIps == nil {
+		var ret []string
+		return ret
+	}
+	return o.AllowedIps
+}
+
+// GetAllowedIpsOk returns a tuple with the AllowedIps field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ApiToken) GetAllowedIpsOk() ([]string, bool) {
+	if o == nil || o.AllowedIps == nil {
+		return nil, false
+	}
+	return o.AllowedIps, true
+}
+
+// HasAllowedIps returns a boolean if a field has been set.
+func (o *ApiToken) HasAllowedIps() bool {
+	if o != nil && o.AllowedIps != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetAllowedIps gets a reference to the given []string and assigns it to the AllowedIps field.
+func (o *ApiToken) SetAllowedIps(v []string) {
+	o.AllowedIps = v
+}
+
+// GetVersion returns the Version field value if set, zero value otherwise.
+func (o *ApiToken) GetVersion() int32 {
+	if o == nil || o.Version == nil {
+		var ret int32
+		return ret
+	}
+	return *o.Version
+}
+
+// GetVersionOk returns a tuple with the Version field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ApiToken) GetVersionOk() (*int32, bool) {
+	if o == nil || o.Version == nil {
+		return nil, false
+	}
+	return o.Version, true
+}
+
+// HasVersion returns a boolean if a field has been set.
+func (o *ApiToken) HasVersion() bool {
+	if o != nil && o.Version != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetVersion gets a reference to the given int32 and assigns it to the Version field.
+func (o *ApiToken) SetVersion(v int32) {
+	o.Version = &v
+}
+
+// GetPreviousVersionExpireAtInMs returns the PreviousVersionExpireAtInMs field value if set, zero value otherwise.
+func (o *ApiToken) GetPreviousVersionExpireAtInMs() int64 {
+	if o == nil || o.PreviousVersionExpireAtInMs == nil {
+		var ret int64
+		return ret
+	}
+	return *o.PreviousVersionExpireAtInMs
+}
+
+// GetPreviousVersionExpireAtInMsOk returns a tuple with the PreviousVersionExpireAtInMs field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ApiToken) GetPreviousVersionExpireAtInMsOk() (*int64, bool) {
+	if o == nil || o.PreviousVersionExpireAtInMs == nil {
+		return nil, false
+	}
+	return o.PreviousVersionExpireAtInMs, true
+}
+
+// HasPreviousVersionExpireAtInMs returns a boolean if a field has been set.
+func (o *ApiToken) HasPreviousVersionExpireAtInMs() bool {
+	if o != nil && o.PreviousVersionExpireAtInMs != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetPreviousVersionExpireAtInMs gets a reference to the given int64 and assigns it to the PreviousVersionExpireAtInMs field.
+func (o *ApiToken) SetPreviousVersionExpireAtInMs(v int64) {
+	o.PreviousVersionExpireAtInMs = &v
+}
+
+// GetLastUsedVersion returns the LastUsedVersion field value if set, zero value otherwise.
+func (o *ApiToken) GetLastUsedVersion() int32 {
+	if o == nil || o.LastUsedVersion == nil {
+		var ret int32
+		return ret
+	}
+	return *o.LastUsedVersion
+}
+
+// GetLastUsedVersionOk returns a tuple with the LastUsedVersion field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *ApiToken) GetLastUsedVersionOk() (*int32, bool) {
+	if o == nil || o.LastUsedVersion == nil {
+		return nil, false
+	}
+	return o.LastUsedVersion, true
+}
+
+// HasLastUsedVersion returns a boolean if a field has been set.
+func (o *ApiToken) HasLastUsedVersion() bool {
+	if o != nil && o.LastUsedVersion != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetLastUsedVersion gets a reference to the given int32 and assigns it to the LastUsedVersion field.
+func (o *ApiToken) SetLastUsedVersion(v int32) {
+	o.LastUsedVersion = &v
+}
+
 func (o ApiToken) MarshalJSON() ([]byte, error) {
 	toSerialize := map[string]interface{}{}
 	if o.Id != nil {
@@ -407,6 +577,21 @@ func (o ApiToken) MarshalJSON() ([]byte, error) {
 	if o.UpdatedAt != nil {
 		toSerialize["updatedAt"] = o.UpdatedAt
 	}
+	if o.Scope != nil {
+		toSerialize["scope"] = o.Scope
+	}
+	if o.AllowedIps != nil {
+		toSerialize["allowedIps"] = o.AllowedIps
+	}
+	if o.Version != nil {
+		toSerialize["version"] = o.Version
+	}
+	if o.PreviousVersionExpireAtInMs != nil {
+		toSerialize["previousVersionExpireAtInMs"] = o.PreviousVersionExpireAtInMs
+	}
+	if o.LastUsedVersion != nil {
+		toSerialize["lastUsedVersion"] = o.LastUsedVersion
+	}
 	return json.Marshal(toSerialize)
 }
 
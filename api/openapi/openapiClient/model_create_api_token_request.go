@@ -23,6 +23,10 @@ type CreateApiTokenRequest struct {
 	Description *string `json:"description,omitempty,notnull" validate:"required"`
 	// Expiration time of api-token in milliseconds
 	ExpireAtInMs *int64 `json:"expireAtInMs,omitempty"`
+	// Scope of api-token, an api-token without scope has every permission of its user
+	Scope *ApiTokenScope `json:"scope,omitempty" validate:"omitempty"`
+	// Ip addresses and cidr ranges the api-token is accepted from, every ip is allowed if empty
+	AllowedIps []string `json:"allowedIps,omitempty"`
 }
 
 // NewCreateApiTokenRequest instantiates a new CreateApiTokenRequest object
@@ -138,6 +142,70 @@ func (o *CreateApiTokenRequest) SetExpireAtInMs(v int64) {
 	o.ExpireAtInMs = &v
 }
 
+// GetScope returns the Scope field value if set, zero value otherwise.
+func (o *CreateApiTokenRequest) GetScope() ApiTokenScope {
+	if o == nil || o.Scope == nil {
+		var ret ApiTokenScope
+		return ret
+	}
+	return *o.Scope
+}
+
+// GetScopeOk returns a tuple with the Scope field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *CreateApiTokenRequest) GetScopeOk() (*ApiTokenScope, bool) {
+	if o == nil || o.Scope == nil {
+		return nil, false
+	}
+	return o.Scope, true
+}
+
+// HasScope returns a boolean if a field has been set.
+func (o *CreateApiTokenRequest) HasScope() bool {
+	if o != nil && o.Scope != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetScope gets a reference to the given ApiTokenScope and assigns it to the Scope field.
+func (o *CreateApiTokenRequest) SetScope(v ApiTokenScope) {
+	o.Scope = &v
+}
+
+// GetAllowedIps returns the AllowedIps field value if set, zero value otherwise.
+func (o *CreateApiTokenRequest) GetAllowedIps() []string {
+	if o == nil || o.AllowedIps == nil {
+		var ret []string
+		return ret
+	}
+	return o.AllowedIps
+}
+
+// GetAllowedIpsOk returns a tuple with the AllowedIps field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *CreateApiTokenRequest) GetAllowedIpsOk() ([]string, bool) {
+	if o == nil || o.AllowedIps == nil {
+		return nil, false
+	}
+	return o.AllowedIps, true
+}
+
+// HasAllowedIps returns a boolean if a field has been set.
+func (o *CreateApiTokenRequest) HasAllowedIps() bool {
+	if o != nil && o.AllowedIps != nil {
+		return true
+	}
+
+	return false
+}
+
+// SetAllowedIps gets a reference to the given []string and assigns it to the AllowedIps field.
+func (o *CreateApiTokenRequest) SetAllowedIps(v []string) {
+	o.AllowedIps = v
+}
+
 func (o CreateApiTokenRequest) MarshalJSON() ([]byte, error) {
 	toSerialize := map[string]interface{}{}
 	if o.Name != nil {
@@ -149,6 +217,12 @@ func (o CreateApiTokenRequest) MarshalJSON() ([]byte, error) {
 	if o.ExpireAtInMs != nil {
 		toSerialize["expireAtInMs"] = o.ExpireAtInMs
 	}
+	if o.Scope != nil {
+		toSerialize["scope"] = o.Scope
+	}
+	if o.AllowedIps != nil {
+		toSerialize["allowedIps"] = o.AllowedIps
+	}
 	return json.Marshal(toSerialize)
 }
 
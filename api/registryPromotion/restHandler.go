@@ -0,0 +1,185 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registryPromotion
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/build/artifacts/registryPromotion"
+	"github.com/devtron-labs/devtron/pkg/build/artifacts/registryPromotion/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type RegistryPromotionRestHandler interface {
+	GetEnvironmentConfig(w http.ResponseWriter, r *http.Request)
+	SaveEnvironmentConfig(w http.ResponseWriter, r *http.Request)
+	DeleteEnvironmentConfig(w http.ResponseWriter, r *http.Request)
+	GetArtifactPromotions(w http.ResponseWriter, r *http.Request)
+}
+
+type RegistryPromotionRestHandlerImpl struct {
+	logger                   *zap.SugaredLogger
+	registryPromotionService registryPromotion.RegistryPromotionService
+	environmentService       environment.EnvironmentService
+	userService              user.UserService
+	enforcer                 casbin.Enforcer
+	validator                *validator.Validate
+}
+
+func NewRegistryPromotionRestHandlerImpl(logger *zap.SugaredLogger,
+	registryPromotionService registryPromotion.RegistryPromotionService,
+	environmentService environment.EnvironmentService,
+	userService user.UserService, enforcer casbin.Enforcer,
+	validator *validator.Validate) *RegistryPromotionRestHandlerImpl {
+	return &RegistryPromotionRestHandlerImpl{
+		logger:                   logger,
+		registryPromotionService: registryPromotionService,
+		environmentService:       environmentService,
+		userService:              userService,
+		enforcer:                 enforcer,
+		validator:                validator,
+	}
+}
+
+func (handler *RegistryPromotionRestHandlerImpl) GetEnvironmentConfig(w http.ResponseWriter, r *http.Request) {
+	envId, ok := handler.getIntPathParam(w, r, "envId")
+	if !ok {
+		return
+	}
+	if _, ok = handler.checkEnvironmentAccess(w, r, envId, casbin.ActionGet); !ok {
+		return
+	}
+	resp, err := handler.registryPromotionService.GetEnvironmentConfig(envId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching promotion config of environment", "envId", envId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *RegistryPromotionRestHandlerImpl) SaveEnvironmentConfig(w http.ResponseWriter, r *http.Request) {
+	request := &bean.EnvironmentPromotionConfigDto{}
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("error in decoding environment promotion config request", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err in environment promotion config request", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	userId, ok := handler.checkEnvironmentAccess(w, r, request.EnvironmentId, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	request.UserId = userId
+	resp, err := handler.registryPromotionService.SaveEnvironmentConfig(request)
+	if err != nil {
+		handler.logger.Errorw("error in saving promotion config of environment", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *RegistryPromotionRestHandlerImpl) DeleteEnvironmentConfig(w http.ResponseWriter, r *http.Request) {
+	envId, ok := handler.getIntPathParam(w, r, "envId")
+	if !ok {
+		return
+	}
+	userId, ok := handler.checkEnvironmentAccess(w, r, envId, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	err := handler.registryPromotionService.DeleteEnvironmentConfig(envId, userId)
+	if err != nil {
+		handler.logger.Errorw("error in deleting promotion config of environment", "envId", envId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, envId, http.StatusOK)
+}
+
+// GetArtifactPromotions lists the registries the image of an artifact has been promoted to
+func (handler *RegistryPromotionRestHandlerImpl) GetArtifactPromotions(w http.ResponseWriter, r *http.Request) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	artifactId, ok := handler.getIntPathParam(w, r, "artifactId")
+	if !ok {
+		return
+	}
+	token := r.Header.Get("token")
+	if !handler.enforcer.Enforce(token, casbin.ResourceDocker, casbin.ActionGet, "*") {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return
+	}
+	resp, err := handler.registryPromotionService.GetArtifactPromotions(artifactId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching promotions of artifact", "artifactId", artifactId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+// checkEnvironmentAccess requires global environment access on the environment, its deployments pull from the target registry
+func (handler *RegistryPromotionRestHandlerImpl) checkEnvironmentAccess(w http.ResponseWriter, r *http.Request, envId int, action string) (int32, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, false
+	}
+	env, err := handler.environmentService.FindById(envId)
+	if err != nil {
+		handler.logger.Errorw("error in fetching environment", "envId", envId, "err", err)
+		errMsg := fmt.Sprintf(bean.EnvironmentNotFound, envId)
+		common.WriteJsonResp(w, util.NewApiError(http.StatusNotFound, errMsg, errMsg), nil, http.StatusNotFound)
+		return 0, false
+	}
+	token := r.Header.Get("token")
+	if !handler.enforcer.Enforce(token, casbin.ResourceGlobalEnvironment, action, env.EnvironmentIdentifier) {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return 0, false
+	}
+	return userId, true
+}
+
+func (handler *RegistryPromotionRestHandlerImpl) getIntPathParam(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	value, err := strconv.Atoi(mux.Vars(r)[name])
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid "+name, http.StatusBadRequest)
+		return 0, false
+	}
+	return value, true
+}
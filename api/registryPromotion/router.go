@@ -0,0 +1,51 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registryPromotion
+
+import "github.com/gorilla/mux"
+
+type RegistryPromotionRouter interface {
+	InitRegistryPromotionRouter(router *mux.Router)
+}
+
+type RegistryPromotionRouterImpl struct {
+	registryPromotionRestHandler RegistryPromotionRestHandler
+}
+
+func NewRegistryPromotionRouterImpl(registryPromotionRestHandler RegistryPromotionRestHandler) *RegistryPromotionRouterImpl {
+	return &RegistryPromotionRouterImpl{
+		registryPromotionRestHandler: registryPromotionRestHandler,
+	}
+}
+
+func (router *RegistryPromotionRouterImpl) InitRegistryPromotionRouter(registryPromotionRouter *mux.Router) {
+	registryPromotionRouter.Path("/environment").
+		HandlerFunc(router.registryPromotionRestHandler.SaveEnvironmentConfig).
+		Methods("POST")
+
+	registryPromotionRouter.Path("/environment/{envId}").
+		HandlerFunc(router.registryPromotionRestHandler.GetEnvironmentConfig).
+		Methods("GET")
+
+	registryPromotionRouter.Path("/environment/{envId}").
+		HandlerFunc(router.registryPromotionRestHandler.DeleteEnvironmentConfig).
+		Methods("DELETE")
+
+	registryPromotionRouter.Path("/artifact/{artifactId}").
+		HandlerFunc(router.registryPromotionRestHandler.GetArtifactPromotions).
+		Methods("GET")
+}
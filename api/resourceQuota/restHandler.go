@@ -0,0 +1,157 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resourceQuota
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/resourceQuota"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/resourceQuota/bean"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+)
+
+type ResourceQuotaRestHandler interface {
+	GetAllBudgets(w http.ResponseWriter, r *http.Request)
+	SaveBudget(w http.ResponseWriter, r *http.Request)
+	DeleteBudget(w http.ResponseWriter, r *http.Request)
+	GetConsumptionReport(w http.ResponseWriter, r *http.Request)
+}
+
+type ResourceQuotaRestHandlerImpl struct {
+	logger               *zap.SugaredLogger
+	resourceQuotaService resourceQuota.ResourceQuotaService
+	userService          user.UserService
+	enforcer             casbin.Enforcer
+	validator            *validator.Validate
+}
+
+func NewResourceQuotaRestHandlerImpl(logger *zap.SugaredLogger,
+	resourceQuotaService resourceQuota.ResourceQuotaService,
+	userService user.UserService, enforcer casbin.Enforcer,
+	validator *validator.Validate) *ResourceQuotaRestHandlerImpl {
+	return &ResourceQuotaRestHandlerImpl{
+		logger:               logger,
+		resourceQuotaService: resourceQuotaService,
+		userService:          userService,
+		enforcer:             enforcer,
+		validator:            validator,
+	}
+}
+
+func (handler *ResourceQuotaRestHandlerImpl) GetAllBudgets(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.checkAccess(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	resp, err := handler.resourceQuotaService.GetAllBudgets()
+	if err != nil {
+		handler.logger.Errorw("error in fetching resource budgets", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ResourceQuotaRestHandlerImpl) SaveBudget(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkAccess(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	request := &bean.ResourceBudgetDto{}
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("error in decoding resource budget request", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err in resource budget request", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	resp, err := handler.resourceQuotaService.SaveBudget(request)
+	if err != nil {
+		handler.logger.Errorw("error in saving resource budget", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *ResourceQuotaRestHandlerImpl) DeleteBudget(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkAccess(w, r, casbin.ActionDelete)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid id", http.StatusBadRequest)
+		return
+	}
+	err = handler.resourceQuotaService.DeleteBudget(id, userId)
+	if err != nil {
+		handler.logger.Errorw("error in deleting resource budget", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, id, http.StatusOK)
+}
+
+func (handler *ResourceQuotaRestHandlerImpl) GetConsumptionReport(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.checkAccess(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	envId := 0
+	if envIdParam := r.URL.Query().Get("envId"); len(envIdParam) > 0 {
+		var err error
+		envId, err = strconv.Atoi(envIdParam)
+		if err != nil {
+			common.WriteJsonResp(w, err, "invalid envId", http.StatusBadRequest)
+			return
+		}
+	}
+	resp, err := handler.resourceQuotaService.GetConsumptionReport(envId)
+	if err != nil {
+		handler.logger.Errorw("error in generating resource consumption report", "envId", envId, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+// checkAccess allows budgets and the consumption report of all environments to platform admins only
+func (handler *ResourceQuotaRestHandlerImpl) checkAccess(w http.ResponseWriter, r *http.Request, action string) (int32, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, false
+	}
+	token := r.Header.Get("token")
+	if !handler.enforcer.Enforce(token, casbin.ResourceGlobal, action, "*") {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return 0, false
+	}
+	return userId, true
+}
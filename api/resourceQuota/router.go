@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resourceQuota
+
+import "github.com/gorilla/mux"
+
+type ResourceQuotaRouter interface {
+	InitResourceQuotaRouter(router *mux.Router)
+}
+
+type ResourceQuotaRouterImpl struct {
+	resourceQuotaRestHandler ResourceQuotaRestHandler
+}
+
+func NewResourceQuotaRouterImpl(resourceQuotaRestHandler ResourceQuotaRestHandler) *ResourceQuotaRouterImpl {
+	return &ResourceQuotaRouterImpl{
+		resourceQuotaRestHandler: resourceQuotaRestHandler,
+	}
+}
+
+func (router *ResourceQuotaRouterImpl) InitResourceQuotaRouter(resourceQuotaRouter *mux.Router) {
+	resourceQuotaRouter.Path("/budget").
+		HandlerFunc(router.resourceQuotaRestHandler.GetAllBudgets).
+		Methods("GET")
+
+	resourceQuotaRouter.Path("/budget").
+		HandlerFunc(router.resourceQuotaRestHandler.SaveBudget).
+		Methods("POST")
+
+	resourceQuotaRouter.Path("/budget/{id}").
+		HandlerFunc(router.resourceQuotaRestHandler.DeleteBudget).
+		Methods("DELETE")
+
+	//envId=3, all environments when not given
+	resourceQuotaRouter.Path("/report").
+		HandlerFunc(router.resourceQuotaRestHandler.GetConsumptionReport).
+		Methods("GET")
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/pkg/appsAsCode/read"
 	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
 	"github.com/devtron-labs/devtron/pkg/auth/user"
 	"github.com/devtron-labs/devtron/pkg/chart"
@@ -63,31 +64,34 @@ type ConfigMapRestHandler interface {
 }
 
 type ConfigMapRestHandlerImpl struct {
-	pipelineBuilder    pipeline.PipelineBuilder
-	Logger             *zap.SugaredLogger
-	chartService       chart.ChartService
-	userAuthService    user.UserService
-	teamService        team.TeamService
-	enforcer           casbin.Enforcer
-	pipelineRepository pipelineConfig.PipelineRepository
-	enforcerUtil       rbac.EnforcerUtil
-	configMapService   pipeline.ConfigMapService
+	pipelineBuilder       pipeline.PipelineBuilder
+	Logger                *zap.SugaredLogger
+	chartService          chart.ChartService
+	userAuthService       user.UserService
+	teamService           team.TeamService
+	enforcer              casbin.Enforcer
+	pipelineRepository    pipelineConfig.PipelineRepository
+	enforcerUtil          rbac.EnforcerUtil
+	configMapService      pipeline.ConfigMapService
+	appsAsCodeReadService read.AppsAsCodeReadService
 }
 
 func NewConfigMapRestHandlerImpl(pipelineBuilder pipeline.PipelineBuilder, Logger *zap.SugaredLogger,
 	chartService chart.ChartService, userAuthService user.UserService, teamService team.TeamService,
 	enforcer casbin.Enforcer, pipelineRepository pipelineConfig.PipelineRepository,
-	enforcerUtil rbac.EnforcerUtil, configMapService pipeline.ConfigMapService) *ConfigMapRestHandlerImpl {
+	enforcerUtil rbac.EnforcerUtil, configMapService pipeline.ConfigMapService,
+	appsAsCodeReadService read.AppsAsCodeReadService) *ConfigMapRestHandlerImpl {
 	return &ConfigMapRestHandlerImpl{
-		pipelineBuilder:    pipelineBuilder,
-		Logger:             Logger,
-		chartService:       chartService,
-		userAuthService:    userAuthService,
-		teamService:        teamService,
-		enforcer:           enforcer,
-		pipelineRepository: pipelineRepository,
-		enforcerUtil:       enforcerUtil,
-		configMapService:   configMapService,
+		pipelineBuilder:       pipelineBuilder,
+		Logger:                Logger,
+		chartService:          chartService,
+		userAuthService:       userAuthService,
+		teamService:           teamService,
+		enforcer:              enforcer,
+		pipelineRepository:    pipelineRepository,
+		enforcerUtil:          enforcerUtil,
+		configMapService:      configMapService,
+		appsAsCodeReadService: appsAsCodeReadService,
 	}
 }
 
@@ -118,6 +122,11 @@ func (handler ConfigMapRestHandlerImpl) CMGlobalAddUpdate(w http.ResponseWriter,
 		return
 	}
 	//RBAC END
+	err = handler.appsAsCodeReadService.CheckAppEditable(configMapRequest.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 
 	res, err := handler.configMapService.CMGlobalAddUpdate(&configMapRequest)
 	if err != nil {
@@ -162,6 +171,11 @@ func (handler ConfigMapRestHandlerImpl) CMEnvironmentAddUpdate(w http.ResponseWr
 		}
 	}
 	//RBAC END
+	err = handler.appsAsCodeReadService.CheckAppEditable(configMapRequest.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 
 	res, err := handler.configMapService.CMEnvironmentAddUpdate(&configMapRequest)
 	if err != nil {
@@ -359,6 +373,11 @@ func (handler ConfigMapRestHandlerImpl) CSGlobalAddUpdate(w http.ResponseWriter,
 		return
 	}
 	//RBAC END
+	err = handler.appsAsCodeReadService.CheckAppEditable(configMapRequest.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 
 	res, err := handler.configMapService.CSGlobalAddUpdate(&configMapRequest)
 	if err != nil {
@@ -404,6 +423,11 @@ func (handler ConfigMapRestHandlerImpl) CSEnvironmentAddUpdate(w http.ResponseWr
 		}
 	}
 	//RBAC END
+	err = handler.appsAsCodeReadService.CheckAppEditable(configMapRequest.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 
 	res, err := handler.configMapService.CSEnvironmentAddUpdate(&configMapRequest)
 	if err != nil {
@@ -517,6 +541,11 @@ func (handler ConfigMapRestHandlerImpl) CMGlobalDelete(w http.ResponseWriter, r
 		return
 	}
 	//RBAC END
+	err = handler.appsAsCodeReadService.CheckAppEditable(appId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 
 	res, err := handler.configMapService.CMGlobalDelete(name, id, userId)
 	if err != nil {
@@ -572,6 +601,11 @@ func (handler ConfigMapRestHandlerImpl) CMEnvironmentDelete(w http.ResponseWrite
 		}
 	}
 	//RBAC END
+	err = handler.appsAsCodeReadService.CheckAppEditable(appId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 
 	res, err := handler.configMapService.CMEnvironmentDelete(name, id, userId)
 	if err != nil {
@@ -613,6 +647,11 @@ func (handler ConfigMapRestHandlerImpl) CSGlobalDelete(w http.ResponseWriter, r
 		return
 	}
 	//RBAC END
+	err = handler.appsAsCodeReadService.CheckAppEditable(appId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 
 	res, err := handler.configMapService.CSGlobalDelete(name, id, userId)
 	if err != nil {
@@ -668,6 +707,11 @@ func (handler ConfigMapRestHandlerImpl) CSEnvironmentDelete(w http.ResponseWrite
 		}
 	}
 	//RBAC END
+	err = handler.appsAsCodeReadService.CheckAppEditable(appId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 
 	res, err := handler.configMapService.CSEnvironmentDelete(name, id, userId)
 	if err != nil {
@@ -54,6 +54,8 @@ type DockerRegRestHandler interface {
 	FetchOneDockerAccounts(w http.ResponseWriter, r *http.Request)
 	UpdateDockerRegistryConfig(w http.ResponseWriter, r *http.Request)
 	FetchAllDockerRegistryForAutocomplete(w http.ResponseWriter, r *http.Request)
+	FetchRegistryRepositoriesForAutocomplete(w http.ResponseWriter, r *http.Request)
+	RotateRobotAccountSecret(w http.ResponseWriter, r *http.Request)
 	IsDockerRegConfigured(w http.ResponseWriter, r *http.Request)
 	DeleteDockerRegistryConfig(w http.ResponseWriter, r *http.Request)
 }
@@ -557,6 +559,91 @@ func (impl DockerRegRestHandlerImpl) FetchAllDockerRegistryForAutocomplete(w htt
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
 
+// FetchRegistryRepositoriesForAutocomplete lists repositories of harbor and quay registries, ?search= filters by name
+func (impl DockerRegRestHandlerImpl) FetchRegistryRepositoriesForAutocomplete(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	storeId := mux.Vars(r)["id"]
+	search := r.URL.Query().Get("search")
+
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceDocker, casbin.ActionGet, storeId); !ok {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+
+	res, err := impl.dockerRegistryConfig.FetchRepositoriesForAutocomplete(storeId, search)
+	if err != nil {
+		impl.logger.Errorw("service err, FetchRegistryRepositoriesForAutocomplete", "storeId", storeId, "err", err)
+		if err == pg.ErrNoRows {
+			common.WriteJsonResp(w, err, "registry not found", http.StatusNotFound)
+			return
+		}
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
+// RotateRobotAccountSecret regenerates the secret of the harbor or quay robot account of the registry and saves it
+func (impl DockerRegRestHandlerImpl) RotateRobotAccountSecret(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userAuthService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	storeId := mux.Vars(r)["id"]
+	var request types.RobotSecretRotationRequest
+	// the body is optional, harbor robots rotate their own secret
+	if r.ContentLength != 0 {
+		err = json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			impl.logger.Errorw("request err, RotateRobotAccountSecret", "err", err, "storeId", storeId)
+			common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+			return
+		}
+	}
+
+	// RBAC enforcer applying
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceDocker, casbin.ActionUpdate, storeId); !ok {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	//RBAC enforcer Ends
+
+	res, err := impl.dockerRegistryConfig.RotateRobotAccountSecret(storeId, request.AdminToken, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, RotateRobotAccountSecret", "err", err, "storeId", storeId)
+		if err == pg.ErrNoRows {
+			common.WriteJsonResp(w, err, "registry not found", http.StatusNotFound)
+			return
+		}
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+
+	// trigger a chart sync job, charts are pulled with the new secret
+	if res.IsOCICompliantRegistry && len(res.RepositoryList) != 0 {
+		syncRequest := &chartProviderService.ChartProviderRequestDto{
+			Id:            res.Id,
+			IsOCIRegistry: res.IsOCICompliantRegistry,
+		}
+		err = impl.chartProviderService.SyncChartProvider(syncRequest)
+		if err != nil {
+			impl.logger.Errorw("service err, RotateRobotAccountSecret", "err", err, "storeId", storeId)
+			common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+			return
+		}
+	}
+	common.WriteJsonResp(w, nil, res, http.StatusOK)
+}
+
 func (impl DockerRegRestHandlerImpl) IsDockerRegConfigured(w http.ResponseWriter, r *http.Request) {
 	v := r.URL.Query()
 	storageType := v.Get("storageType")
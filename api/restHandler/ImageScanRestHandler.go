@@ -45,27 +45,31 @@ type ImageScanRestHandler interface {
 	FetchExecutionDetail(w http.ResponseWriter, r *http.Request)
 	FetchMinScanResultByAppIdAndEnvId(w http.ResponseWriter, r *http.Request)
 	VulnerabilityExposure(w http.ResponseWriter, r *http.Request)
+	ImportRegistryScanResult(w http.ResponseWriter, r *http.Request)
 }
 
 type ImageScanRestHandlerImpl struct {
-	logger             *zap.SugaredLogger
-	imageScanService   imageScanning.ImageScanService
-	userService        user.UserService
-	enforcer           casbin.Enforcer
-	enforcerUtil       rbac.EnforcerUtil
-	environmentService environment.EnvironmentService
+	logger                    *zap.SugaredLogger
+	imageScanService          imageScanning.ImageScanService
+	userService               user.UserService
+	enforcer                  casbin.Enforcer
+	enforcerUtil              rbac.EnforcerUtil
+	environmentService        environment.EnvironmentService
+	registryScanImportService imageScanning.RegistryScanImportService
 }
 
 func NewImageScanRestHandlerImpl(logger *zap.SugaredLogger,
 	imageScanService imageScanning.ImageScanService, userService user.UserService, enforcer casbin.Enforcer,
-	enforcerUtil rbac.EnforcerUtil, environmentService environment.EnvironmentService) *ImageScanRestHandlerImpl {
+	enforcerUtil rbac.EnforcerUtil, environmentService environment.EnvironmentService,
+	registryScanImportService imageScanning.RegistryScanImportService) *ImageScanRestHandlerImpl {
 	return &ImageScanRestHandlerImpl{
-		logger:             logger,
-		imageScanService:   imageScanService,
-		userService:        userService,
-		enforcer:           enforcer,
-		enforcerUtil:       enforcerUtil,
-		environmentService: environmentService,
+		logger:                    logger,
+		imageScanService:          imageScanService,
+		userService:               userService,
+		enforcer:                  enforcer,
+		enforcerUtil:              enforcerUtil,
+		environmentService:        environmentService,
+		registryScanImportService: registryScanImportService,
 	}
 }
 
@@ -373,3 +377,29 @@ func (impl ImageScanRestHandlerImpl) VulnerabilityExposure(w http.ResponseWriter
 	results.VulnerabilityExposure = vulnerabilityExposure
 	common.WriteJsonResp(w, err, results, http.StatusOK)
 }
+
+func (impl ImageScanRestHandlerImpl) ImportRegistryScanResult(w http.ResponseWriter, r *http.Request) {
+	userId, err := impl.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return
+	}
+	artifactId, err := strconv.Atoi(r.URL.Query().Get("artifactId"))
+	if err != nil {
+		impl.logger.Errorw("request err, ImportRegistryScanResult", "err", err, "artifactId", r.URL.Query().Get("artifactId"))
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	token := r.Header.Get("token")
+	if ok := impl.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionUpdate, "*"); !ok {
+		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
+		return
+	}
+	result, err := impl.registryScanImportService.ImportRegistryScanResult(r.Context(), artifactId, userId)
+	if err != nil {
+		impl.logger.Errorw("service err, ImportRegistryScanResult", "err", err, "artifactId", artifactId)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, result, http.StatusOK)
+}
@@ -19,6 +19,7 @@ package appInfo
 import (
 	"encoding/json"
 	client "github.com/devtron-labs/devtron/api/helm-app/service"
+	"github.com/devtron-labs/devtron/pkg/appsAsCode/read"
 	"github.com/devtron-labs/devtron/util/commonEnforcementFunctionsUtil"
 	"net/http"
 	"strconv"
@@ -59,13 +60,16 @@ type AppInfoRestHandlerImpl struct {
 	enforcerUtilHelm    rbac.EnforcerUtilHelm
 	genericNoteService  genericNotes.GenericNoteService
 	rbacEnforcementUtil commonEnforcementFunctionsUtil.CommonEnforcementUtil
+	// appsAsCodeReadService blocks metadata changes of apps whose configuration is managed from git
+	appsAsCodeReadService read.AppsAsCodeReadService
 }
 
 func NewAppInfoRestHandlerImpl(logger *zap.SugaredLogger, appService app.AppCrudOperationService,
 	userAuthService user.UserService, validator *validator.Validate, enforcerUtil rbac.EnforcerUtil,
 	enforcer casbin.Enforcer, helmAppService client.HelmAppService, enforcerUtilHelm rbac.EnforcerUtilHelm,
 	genericNoteService genericNotes.GenericNoteService,
-	rbacEnforcementUtil commonEnforcementFunctionsUtil.CommonEnforcementUtil) *AppInfoRestHandlerImpl {
+	rbacEnforcementUtil commonEnforcementFunctionsUtil.CommonEnforcementUtil,
+	appsAsCodeReadService read.AppsAsCodeReadService) *AppInfoRestHandlerImpl {
 	handler := &AppInfoRestHandlerImpl{
 		logger:              logger,
 		appService:          appService,
@@ -77,6 +81,8 @@ func NewAppInfoRestHandlerImpl(logger *zap.SugaredLogger, appService app.AppCrud
 		enforcerUtilHelm:    enforcerUtilHelm,
 		genericNoteService:  genericNoteService,
 		rbacEnforcementUtil: rbacEnforcementUtil,
+
+		appsAsCodeReadService: appsAsCodeReadService,
 	}
 	return handler
 }
@@ -235,6 +241,11 @@ func (handler AppInfoRestHandlerImpl) UpdateApp(w http.ResponseWriter, r *http.R
 		}
 	}
 	//rbac implementation ends here
+	err = handler.appsAsCodeReadService.CheckAppEditable(request.Id)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 
 	res, err := handler.appService.UpdateApp(&request)
 	if err != nil {
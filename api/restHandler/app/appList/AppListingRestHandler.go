@@ -44,6 +44,7 @@ import (
 	bean3 "github.com/devtron-labs/devtron/pkg/deployment/common/bean"
 	bean4 "github.com/devtron-labs/devtron/pkg/deployment/common/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/deployedApp/status/resourceTree"
+	"github.com/devtron-labs/devtron/pkg/deployment/helmDrift"
 	"github.com/devtron-labs/devtron/pkg/deploymentGroup"
 	"github.com/devtron-labs/devtron/pkg/k8s"
 	k8sApplication "github.com/devtron-labs/devtron/pkg/k8s/application"
@@ -96,6 +97,7 @@ type AppListingRestHandlerImpl struct {
 	k8sApplicationService       k8sApplication.K8sApplicationService
 	deploymentConfigService     common2.DeploymentConfigService
 	resourceTreeService         resourceTree.Service
+	helmDriftService            helmDrift.HelmDriftService
 }
 
 type AppStatus struct {
@@ -123,7 +125,8 @@ func NewAppListingRestHandlerImpl(appListingService app.AppListingService,
 	pipelineRepository pipelineConfig.PipelineRepository,
 	k8sApplicationService k8sApplication.K8sApplicationService,
 	deploymentConfigService common2.DeploymentConfigService,
-	resourceTreeService resourceTree.Service) *AppListingRestHandlerImpl {
+	resourceTreeService resourceTree.Service,
+	helmDriftService helmDrift.HelmDriftService) *AppListingRestHandlerImpl {
 	appListingHandler := &AppListingRestHandlerImpl{
 		appListingService:           appListingService,
 		logger:                      logger,
@@ -139,6 +142,7 @@ func NewAppListingRestHandlerImpl(appListingService app.AppListingService,
 		k8sApplicationService:       k8sApplicationService,
 		deploymentConfigService:     deploymentConfigService,
 		resourceTreeService:         resourceTreeService,
+		helmDriftService:            helmDriftService,
 	}
 	return appListingHandler
 }
@@ -492,6 +496,14 @@ func (handler AppListingRestHandlerImpl) FetchAppDetailsV2(w http.ResponseWriter
 		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
 		return
 	}
+	if util.IsHelmApp(appDetail.DeploymentAppType) && appDetail.CdPipelineId > 0 {
+		// drift is informational, app details are served even when it cannot be read
+		appDetail.HelmDrift, err = handler.helmDriftService.GetDriftSummary(appDetail.CdPipelineId)
+		if err != nil {
+			handler.logger.Errorw("error in fetching helm drift summary", "err", err, "appId", appId, "envId", envId)
+			err = nil
+		}
+	}
 
 	appDetail, err = handler.updateApprovalConfigDataInAppDetailResp(appDetail, appId, envId)
 	if err != nil {
@@ -150,6 +150,9 @@ func (handler *PipelineConfigRestHandlerImpl) CreateCiConfig(w http.ResponseWrit
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
 		return
 	}
+	if !handler.checkAppEditable(w, createRequest.AppId) {
+		return
+	}
 	createResp, err := handler.pipelineBuilder.CreateCiPipeline(&createRequest)
 	if err != nil {
 		handler.Logger.Errorw("service err, create", "err", err, "create request", createRequest)
@@ -192,6 +195,9 @@ func (handler *PipelineConfigRestHandlerImpl) UpdateCiTemplate(w http.ResponseWr
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
 		return
 	}
+	if !handler.checkAppEditable(w, configRequest.AppId) {
+		return
+	}
 	createResp, err := handler.pipelineBuilder.UpdateCiTemplate(&configRequest)
 	if err != nil {
 		handler.Logger.Errorw("service err, UpdateCiTemplate", "err", err, "UpdateCiTemplate", configRequest)
@@ -344,7 +350,9 @@ func (handler *PipelineConfigRestHandlerImpl) PatchCiMaterialSourceWithAppIdAndE
 		common.WriteJsonResp(w, err, nil, http.StatusUnauthorized)
 		return
 	}
-
+	if !handler.checkAppEditable(w, patchRequest.AppId) {
+		return
+	}
 	createResp, err := handler.pipelineBuilder.PatchCiMaterialSource(patchRequest, userId)
 	if err != nil {
 		handler.Logger.Errorw("service err, PatchCiPipelines", "err", err, "PatchCiPipelines", patchRequest)
@@ -429,6 +437,9 @@ func (handler *PipelineConfigRestHandlerImpl) PatchCiPipelines(w http.ResponseWr
 		return
 	}
 
+	if !handler.checkAppEditable(w, patchRequest.AppId) {
+		return
+	}
 	ciConf, err := handler.pipelineBuilder.GetCiPipeline(patchRequest.AppId)
 
 	var emptyDockerRegistry string
@@ -1403,6 +1414,9 @@ func (handler *PipelineConfigRestHandlerImpl) CreateMaterial(w http.ResponseWrit
 		}
 	}
 
+	if !handler.checkAppEditable(w, createMaterialDto.AppId) {
+		return
+	}
 	createResp, err := handler.pipelineBuilder.CreateMaterialsForApp(&createMaterialDto)
 	if err != nil {
 		handler.Logger.Errorw("service err, CreateMaterial", "err", err, "CreateMaterial", createMaterialDto)
@@ -1454,6 +1468,9 @@ func (handler *PipelineConfigRestHandlerImpl) UpdateMaterial(w http.ResponseWrit
 		return
 	}
 
+	if !handler.checkAppEditable(w, updateMaterialDto.AppId) {
+		return
+	}
 	createResp, err := handler.pipelineBuilder.UpdateMaterialsForApp(&updateMaterialDto)
 	if err != nil {
 		handler.Logger.Errorw("service err, UpdateMaterial", "err", err, "UpdateMaterial", updateMaterialDto)
@@ -1493,6 +1510,9 @@ func (handler *PipelineConfigRestHandlerImpl) DeleteMaterial(w http.ResponseWrit
 		return
 	}
 	//rbac ends
+	if !handler.checkAppEditable(w, deleteMaterial.AppId) {
+		return
+	}
 	err = handler.pipelineBuilder.DeleteMaterial(&deleteMaterial)
 	if err != nil {
 		handler.Logger.Errorw("service err, DeleteMaterial", "err", err, "DeleteMaterial", deleteMaterial)
@@ -2205,6 +2225,12 @@ func (handler *PipelineConfigRestHandlerImpl) checkAppSpecificAccess(token, acti
 	if ok := handler.enforcer.Enforce(token, casbin.ResourceApplications, action, resourceName); !ok {
 		return false, errors.New(string(bean.CI_PATCH_NOT_AUTHORIZED_MESSAGE))
 	}
+	// picking a branch for a regex source is part of triggering, any other change edits the app configuration
+	if action != casbin.ActionTrigger {
+		if err = handler.appsAsCodeReadService.CheckAppEditable(appId); err != nil {
+			return false, err
+		}
+	}
 	return true, nil
 }
 
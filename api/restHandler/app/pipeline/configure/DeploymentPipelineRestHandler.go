@@ -166,6 +166,9 @@ func (handler *PipelineConfigRestHandlerImpl) ConfigureDeploymentTemplateForApp(
 		return
 	}
 
+	if !handler.checkAppEditable(w, templateRequest.AppId) {
+		return
+	}
 	ctx, cancel := context.WithCancel(r.Context())
 	if cn, ok := w.(http.CloseNotifier); ok {
 		go func(done <-chan struct{}, closed <-chan bool) {
@@ -229,6 +232,9 @@ func (handler *PipelineConfigRestHandlerImpl) CreateCdPipeline(w http.ResponseWr
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
 		return
 	}
+	if !handler.checkAppEditable(w, cdPipeline.AppId) {
+		return
+	}
 	ok := true
 	for _, deploymentPipeline := range cdPipeline.Pipelines {
 
@@ -348,6 +354,9 @@ func (handler *PipelineConfigRestHandlerImpl) PatchCdPipeline(w http.ResponseWri
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
 		return
 	}
+	if !handler.checkAppEditable(w, cdPipeline.AppId) {
+		return
+	}
 	ctx := r.Context()
 	createResp, err := handler.pipelineBuilder.PatchCdPipelines(&cdPipeline, ctx)
 	if err != nil {
@@ -590,6 +599,9 @@ func (handler *PipelineConfigRestHandlerImpl) ChangeChartRef(w http.ResponseWrit
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
 		return
 	}
+	if !handler.checkAppEditable(w, request.AppId) {
+		return
+	}
 
 	//VARIABLE_RESOLVE
 	scope := resourceQualifiers.Scope{
@@ -718,6 +730,9 @@ func (handler *PipelineConfigRestHandlerImpl) EnvConfigOverrideCreate(w http.Res
 		return
 	}
 
+	if !handler.checkAppEditable(w, appId) {
+		return
+	}
 	createResp, err := handler.propertiesConfigService.CreateEnvironmentProperties(appId, &envConfigProperties)
 	if err != nil {
 		if err.Error() == bean4.NOCHARTEXIST {
@@ -820,6 +835,9 @@ func (handler *PipelineConfigRestHandlerImpl) EnvConfigOverrideUpdate(w http.Res
 		return
 	}
 
+	if !handler.checkAppEditable(w, appId) {
+		return
+	}
 	createResp, err := handler.propertiesConfigService.UpdateEnvironmentProperties(appId, &envConfigProperties, userId)
 	if err != nil {
 		handler.Logger.Errorw("service err, EnvConfigOverrideUpdate", "err", err, "payload", envConfigProperties)
@@ -1476,6 +1494,9 @@ func (handler *PipelineConfigRestHandlerImpl) UpdateAppOverride(w http.ResponseW
 		return
 	}
 
+	if !handler.checkAppEditable(w, templateRequest.AppId) {
+		return
+	}
 	_, span = otel.Tracer("orchestrator").Start(ctx, "chartService.UpdateAppOverride")
 	createResp, err := handler.chartService.UpdateAppOverride(ctx, &templateRequest)
 	span.End()
@@ -1612,6 +1633,9 @@ func (handler *PipelineConfigRestHandlerImpl) EnvConfigOverrideReset(w http.Resp
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
 		return
 	}
+	if !handler.checkAppEditable(w, appId) {
+		return
+	}
 	isSuccess, err := handler.propertiesConfigService.ResetEnvironmentProperties(id, userId)
 	if err != nil {
 		handler.Logger.Errorw("service err, EnvConfigOverrideReset", "err", err, "appId", appId, "environmentId", environmentId)
@@ -2164,6 +2188,9 @@ func (handler *PipelineConfigRestHandlerImpl) EnvConfigOverrideCreateNamespace(w
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
 		return
 	}
+	if !handler.checkAppEditable(w, appId) {
+		return
+	}
 	createResp, err := handler.propertiesConfigService.CreateEnvironmentPropertiesWithNamespace(appId, &envConfigProperties)
 	if err != nil {
 		handler.Logger.Errorw("service err, EnvConfigOverrideCreateNamespace", "err", err, "appId", appId, "environmentId", environmentId, "payload", envConfigProperties)
@@ -2480,6 +2507,9 @@ func (handler *PipelineConfigRestHandlerImpl) SaveGitOpsConfiguration(w http.Res
 		common.WriteJsonResp(w, fmt.Errorf("unauthorized user"), "Unauthorized User", http.StatusForbidden)
 		return
 	}
+	if !handler.checkAppEditable(w, appGitOpsConfigRequest.AppId) {
+		return
+	}
 
 	ctx := r.Context()
 
@@ -21,6 +21,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	appsAsCodeRead "github.com/devtron-labs/devtron/pkg/appsAsCode/read"
 	"github.com/devtron-labs/devtron/pkg/build/artifacts/imageTagging"
 	imageTaggingRead "github.com/devtron-labs/devtron/pkg/build/artifacts/imageTagging/read"
 	read2 "github.com/devtron-labs/devtron/pkg/build/git/gitMaterial/read"
@@ -138,6 +139,7 @@ type PipelineConfigRestHandlerImpl struct {
 	teamReadService                     read3.TeamReadService
 	environmentRepository               repository2.EnvironmentRepository
 	chartReadService                    read5.ChartReadService
+	appsAsCodeReadService               appsAsCodeRead.AppsAsCodeReadService
 }
 
 func NewPipelineRestHandlerImpl(pipelineBuilder pipeline.PipelineBuilder, Logger *zap.SugaredLogger,
@@ -171,7 +173,8 @@ func NewPipelineRestHandlerImpl(pipelineBuilder pipeline.PipelineBuilder, Logger
 	gitProviderReadService gitProviderRead.GitProviderReadService,
 	teamReadService read3.TeamReadService,
 	EnvironmentRepository repository2.EnvironmentRepository,
-	chartReadService read5.ChartReadService) *PipelineConfigRestHandlerImpl {
+	chartReadService read5.ChartReadService,
+	appsAsCodeReadService appsAsCodeRead.AppsAsCodeReadService) *PipelineConfigRestHandlerImpl {
 	envConfig := &PipelineRestHandlerEnvConfig{}
 	err := env.Parse(envConfig)
 	if err != nil {
@@ -213,6 +216,7 @@ func NewPipelineRestHandlerImpl(pipelineBuilder pipeline.PipelineBuilder, Logger
 		teamReadService:                     teamReadService,
 		environmentRepository:               EnvironmentRepository,
 		chartReadService:                    chartReadService,
+		appsAsCodeReadService:               appsAsCodeReadService,
 	}
 }
 
@@ -223,6 +227,16 @@ const (
 	argoWFLogIdentifier = "argo=true"
 )
 
+// checkAppEditable writes the error response when the app configuration is locked to its git definition
+func (handler *PipelineConfigRestHandlerImpl) checkAppEditable(w http.ResponseWriter, appId int) bool {
+	err := handler.appsAsCodeReadService.CheckAppEditable(appId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
+
 func (handler *PipelineConfigRestHandlerImpl) DeleteApp(w http.ResponseWriter, r *http.Request) {
 	token := r.Header.Get("token")
 	userId, err := handler.userAuthService.GetLoggedInUser(r)
@@ -24,6 +24,7 @@ import (
 	bean2 "github.com/devtron-labs/devtron/pkg/deployment/deployedApp/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps"
 	bean3 "github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
+	"github.com/devtron-labs/devtron/pkg/deploymentWindow"
 	"github.com/devtron-labs/devtron/pkg/eventProcessor/out"
 	bean4 "github.com/devtron-labs/devtron/pkg/eventProcessor/out/bean"
 	"net/http"
@@ -68,6 +69,7 @@ type PipelineTriggerRestHandlerImpl struct {
 	deployedAppService          deployedApp.DeployedAppService
 	cdTriggerService            devtronApps.TriggerService
 	workflowEventPublishService out.WorkflowEventPublishService
+	deploymentWindowService     deploymentWindow.DeploymentWindowService
 }
 
 func NewPipelineRestHandler(appService app.AppService, userAuthService user.UserService, validator *validator.Validate,
@@ -76,7 +78,8 @@ func NewPipelineRestHandler(appService app.AppService, userAuthService user.User
 	deploymentConfigService pipeline.PipelineDeploymentConfigService,
 	deployedAppService deployedApp.DeployedAppService,
 	cdTriggerService devtronApps.TriggerService,
-	workflowEventPublishService out.WorkflowEventPublishService) *PipelineTriggerRestHandlerImpl {
+	workflowEventPublishService out.WorkflowEventPublishService,
+	deploymentWindowService deploymentWindow.DeploymentWindowService) *PipelineTriggerRestHandlerImpl {
 	pipelineHandler := &PipelineTriggerRestHandlerImpl{
 		appService:                  appService,
 		userAuthService:             userAuthService,
@@ -90,6 +93,7 @@ func NewPipelineRestHandler(appService app.AppService, userAuthService user.User
 		deployedAppService:          deployedAppService,
 		cdTriggerService:            cdTriggerService,
 		workflowEventPublishService: workflowEventPublishService,
+		deploymentWindowService:     deploymentWindowService,
 	}
 	return pipelineHandler
 }
@@ -136,6 +140,11 @@ func (handler PipelineTriggerRestHandlerImpl) OverrideConfig(w http.ResponseWrit
 		return
 	}
 	ctx := r.Context()
+	if len(overrideRequest.DeploymentWindowOverrideReason) > 0 {
+		// only super admins are allowed to override an active deployment window
+		isSuperAdmin := handler.enforcer.Enforce(token, casbin.ResourceGlobal, casbin.ActionGet, "*")
+		ctx = util.SetSuperAdminInContext(ctx, isSuperAdmin)
+	}
 	_, span := otel.Tracer("orchestrator").Start(ctx, "workflowDagExecutor.ManualCdTrigger")
 	triggerContext := bean3.TriggerContext{
 		Context: ctx,
@@ -148,6 +157,13 @@ func (handler PipelineTriggerRestHandlerImpl) OverrideConfig(w http.ResponseWrit
 		return
 	}
 	res := map[string]interface{}{"releaseId": mergeResp, "helmPackageName": helmPackageName}
+	// deployment window state tells the client how long deployments stay open or when they open next
+	windowState, windowErr := handler.deploymentWindowService.GetDeploymentWindowStateForAppEnv(overrideRequest.AppId, overrideRequest.EnvId)
+	if windowErr != nil {
+		handler.logger.Errorw("error in evaluating deployment window state, OverrideConfig", "err", windowErr, "appId", overrideRequest.AppId, "envId", overrideRequest.EnvId)
+	} else {
+		res["deploymentWindowState"] = windowState
+	}
 	common.WriteJsonResp(w, err, res, http.StatusOK)
 }
 
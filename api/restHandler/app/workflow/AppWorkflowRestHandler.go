@@ -19,6 +19,7 @@ package workflow
 import (
 	"encoding/json"
 	bean2 "github.com/devtron-labs/devtron/pkg/appWorkflow/bean"
+	"github.com/devtron-labs/devtron/pkg/appsAsCode/read"
 	"github.com/devtron-labs/devtron/util/stringsUtil"
 	"github.com/gorilla/schema"
 	"net/http"
@@ -65,11 +66,14 @@ type AppWorkflowRestHandlerImpl struct {
 	appRepository      app.AppRepository
 	enforcerUtil       rbac.EnforcerUtil
 	chartService       chart.ChartService
+	// appsAsCodeReadService blocks workflow changes of apps whose configuration is managed from git
+	appsAsCodeReadService read.AppsAsCodeReadService
 }
 
 func NewAppWorkflowRestHandlerImpl(Logger *zap.SugaredLogger, userAuthService user.UserService, appWorkflowService appWorkflow.AppWorkflowService,
 	teamService team.TeamService, enforcer casbin.Enforcer, pipelineBuilder pipeline.PipelineBuilder,
-	appRepository app.AppRepository, enforcerUtil rbac.EnforcerUtil, chartService chart.ChartService) *AppWorkflowRestHandlerImpl {
+	appRepository app.AppRepository, enforcerUtil rbac.EnforcerUtil, chartService chart.ChartService,
+	appsAsCodeReadService read.AppsAsCodeReadService) *AppWorkflowRestHandlerImpl {
 	return &AppWorkflowRestHandlerImpl{
 		Logger:             Logger,
 		appWorkflowService: appWorkflowService,
@@ -80,6 +84,8 @@ func NewAppWorkflowRestHandlerImpl(Logger *zap.SugaredLogger, userAuthService us
 		appRepository:      appRepository,
 		enforcerUtil:       enforcerUtil,
 		chartService:       chartService,
+
+		appsAsCodeReadService: appsAsCodeReadService,
 	}
 }
 
@@ -108,6 +114,11 @@ func (handler AppWorkflowRestHandlerImpl) CreateAppWorkflow(w http.ResponseWrite
 		return
 	}
 	//rback block ends here
+	err = handler.appsAsCodeReadService.CheckAppEditable(request.AppId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 	request.UserId = userId
 
 	res, err := handler.appWorkflowService.CreateAppWorkflow(request)
@@ -163,6 +174,11 @@ func (handler AppWorkflowRestHandlerImpl) DeleteAppWorkflow(w http.ResponseWrite
 		return
 	}
 	//rback block ends here
+	err = handler.appsAsCodeReadService.CheckAppEditable(appId)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
 
 	err = handler.appWorkflowService.DeleteAppWorkflow(appWorkflowId, userId)
 	if err != nil {
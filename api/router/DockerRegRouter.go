@@ -47,6 +47,12 @@ func (impl DockerRegRouterImpl) InitDockerRegRouter(configRouter *mux.Router) {
 	configRouter.Path("/registry/autocomplete").
 		HandlerFunc(impl.dockerRestHandler.FetchAllDockerRegistryForAutocomplete).
 		Methods("GET")
+	configRouter.Path("/registry/{id}/repository/autocomplete").
+		HandlerFunc(impl.dockerRestHandler.FetchRegistryRepositoriesForAutocomplete).
+		Methods("GET")
+	configRouter.Path("/registry/{id}/robot/rotate").
+		HandlerFunc(impl.dockerRestHandler.RotateRobotAccountSecret).
+		Methods("PUT")
 	configRouter.Path("/registry/{id}").
 		HandlerFunc(impl.dockerRestHandler.FetchOneDockerAccounts).
 		Methods("GET")
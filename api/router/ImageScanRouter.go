@@ -41,4 +41,7 @@ func (impl ImageScanRouterImpl) InitImageScanRouter(configRouter *mux.Router) {
 
 	configRouter.Path("/cve/exposure").HandlerFunc(impl.imageScanRestHandler.VulnerabilityExposure).Methods("POST")
 
+	//artifactId=100
+	configRouter.Path("/import/registry").HandlerFunc(impl.imageScanRestHandler.ImportRegistryScanResult).Methods("POST")
+
 }
@@ -22,21 +22,30 @@ import (
 	"github.com/devtron-labs/devtron/api/appStore"
 	"github.com/devtron-labs/devtron/api/appStore/chartGroup"
 	appStoreDeployment "github.com/devtron-labs/devtron/api/appStore/deployment"
+	"github.com/devtron-labs/devtron/api/appTransfer"
+	"github.com/devtron-labs/devtron/api/appsAsCode"
 	"github.com/devtron-labs/devtron/api/argoApplication"
+	"github.com/devtron-labs/devtron/api/auth/scim"
 	"github.com/devtron-labs/devtron/api/auth/sso"
 	"github.com/devtron-labs/devtron/api/auth/user"
+	"github.com/devtron-labs/devtron/api/canaryAnalysis"
 	"github.com/devtron-labs/devtron/api/chartRepo"
 	"github.com/devtron-labs/devtron/api/cluster"
 	"github.com/devtron-labs/devtron/api/dashboardEvent"
 	"github.com/devtron-labs/devtron/api/deployment"
+	"github.com/devtron-labs/devtron/api/deploymentWindow"
 	"github.com/devtron-labs/devtron/api/devtronResource"
 	"github.com/devtron-labs/devtron/api/externalLink"
 	fluxApplication2 "github.com/devtron-labs/devtron/api/fluxApplication"
 	client "github.com/devtron-labs/devtron/api/helm-app"
+	"github.com/devtron-labs/devtron/api/helmDrift"
+	"github.com/devtron-labs/devtron/api/imageRetention"
 	"github.com/devtron-labs/devtron/api/infraConfig"
 	"github.com/devtron-labs/devtron/api/k8s/application"
 	"github.com/devtron-labs/devtron/api/k8s/capacity"
 	"github.com/devtron-labs/devtron/api/module"
+	"github.com/devtron-labs/devtron/api/registryPromotion"
+	"github.com/devtron-labs/devtron/api/resourceQuota"
 	"github.com/devtron-labs/devtron/api/resourceScan"
 	"github.com/devtron-labs/devtron/api/restHandler/common"
 	"github.com/devtron-labs/devtron/api/router/app"
@@ -124,6 +133,17 @@ type MuxRouter struct {
 	devtronResourceRouter              devtronResource.DevtronResourceRouter
 	scanningResultRouter               resourceScan.ScanningResultRouter
 	userResourceRouter                 userResource.Router
+	deploymentWindowRouter             deploymentWindow.DeploymentWindowRouter
+	canaryAnalysisRouter               canaryAnalysis.CanaryAnalysisRouter
+	helmDriftRouter                    helmDrift.HelmDriftRouter
+	scimRouter                         scim.ScimRouter
+	imageRetentionRouter               imageRetention.ImageRetentionRouter
+	registryPromotionRouter            registryPromotion.RegistryPromotionRouter
+	resourceQuotaRouter                resourceQuota.ResourceQuotaRouter
+	appsAsCodeRouter                   appsAsCode.AppsAsCodeRouter
+	appTransferRouter                  appTransfer.AppTransferRouter
+	terminalRecordingRouter            terminal2.TerminalRecordingRouter
+	terminalCommandPolicyRouter        terminal2.TerminalCommandPolicyRouter
 }
 
 func NewMuxRouter(logger *zap.SugaredLogger,
@@ -159,6 +179,17 @@ func NewMuxRouter(logger *zap.SugaredLogger,
 	fluxApplicationRouter fluxApplication2.FluxApplicationRouter,
 	scanningResultRouter resourceScan.ScanningResultRouter,
 	userResourceRouter userResource.Router,
+	deploymentWindowRouter deploymentWindow.DeploymentWindowRouter,
+	canaryAnalysisRouter canaryAnalysis.CanaryAnalysisRouter,
+	helmDriftRouter helmDrift.HelmDriftRouter,
+	scimRouter scim.ScimRouter,
+	imageRetentionRouter imageRetention.ImageRetentionRouter,
+	registryPromotionRouter registryPromotion.RegistryPromotionRouter,
+	resourceQuotaRouter resourceQuota.ResourceQuotaRouter,
+	appsAsCodeRouter appsAsCode.AppsAsCodeRouter,
+	appTransferRouter appTransfer.AppTransferRouter,
+	terminalRecordingRouter terminal2.TerminalRecordingRouter,
+	terminalCommandPolicyRouter terminal2.TerminalCommandPolicyRouter,
 ) *MuxRouter {
 	r := &MuxRouter{
 		Router:                             mux.NewRouter(),
@@ -226,6 +257,17 @@ func NewMuxRouter(logger *zap.SugaredLogger,
 		fluxApplicationRouter:              fluxApplicationRouter,
 		scanningResultRouter:               scanningResultRouter,
 		userResourceRouter:                 userResourceRouter,
+		deploymentWindowRouter:             deploymentWindowRouter,
+		canaryAnalysisRouter:               canaryAnalysisRouter,
+		helmDriftRouter:                    helmDriftRouter,
+		scimRouter:                         scimRouter,
+		imageRetentionRouter:               imageRetentionRouter,
+		registryPromotionRouter:            registryPromotionRouter,
+		resourceQuotaRouter:                resourceQuotaRouter,
+		appsAsCodeRouter:                   appsAsCodeRouter,
+		appTransferRouter:                  appTransferRouter,
+		terminalRecordingRouter:            terminalRecordingRouter,
+		terminalCommandPolicyRouter:        terminalCommandPolicyRouter,
 	}
 	return r
 }
@@ -413,6 +455,10 @@ func (r MuxRouter) Init() {
 	apiTokenRouter := r.Router.PathPrefix("/orchestrator/api-token").Subrouter()
 	r.apiTokenRouter.InitApiTokenRouter(apiTokenRouter)
 
+	// scim router, authenticated by the scim rest handler
+	scimRouter := r.Router.PathPrefix("/orchestrator/scim/v2").Subrouter()
+	r.scimRouter.Init(scimRouter)
+
 	k8sCapacityApp := r.Router.PathPrefix("/orchestrator/k8s/capacity").Subrouter()
 	r.k8sCapacityRouter.InitK8sCapacityRouter(k8sCapacityApp)
 
@@ -435,6 +481,36 @@ func (r MuxRouter) Init() {
 	userResourcesRouter := r.Router.PathPrefix("/orchestrator/user/resource").Subrouter()
 	r.userResourceRouter.InitUserResourceRouter(userResourcesRouter)
 
+	deploymentWindowRouter := r.Router.PathPrefix("/orchestrator/deployment-window").Subrouter()
+	r.deploymentWindowRouter.InitDeploymentWindowRouter(deploymentWindowRouter)
+
+	canaryAnalysisRouter := r.Router.PathPrefix("/orchestrator/canary-analysis").Subrouter()
+	r.canaryAnalysisRouter.InitCanaryAnalysisRouter(canaryAnalysisRouter)
+
+	helmDriftRouter := r.Router.PathPrefix("/orchestrator/helm-drift").Subrouter()
+	r.helmDriftRouter.InitHelmDriftRouter(helmDriftRouter)
+
+	imageRetentionRouter := r.Router.PathPrefix("/orchestrator/image-retention").Subrouter()
+	r.imageRetentionRouter.InitImageRetentionRouter(imageRetentionRouter)
+
+	registryPromotionRouter := r.Router.PathPrefix("/orchestrator/registry-promotion").Subrouter()
+	r.registryPromotionRouter.InitRegistryPromotionRouter(registryPromotionRouter)
+
+	resourceQuotaRouter := r.Router.PathPrefix("/orchestrator/resource-quota").Subrouter()
+	r.resourceQuotaRouter.InitResourceQuotaRouter(resourceQuotaRouter)
+
+	appsAsCodeRouter := r.Router.PathPrefix("/orchestrator/apps-as-code").Subrouter()
+	r.appsAsCodeRouter.InitAppsAsCodeRouter(appsAsCodeRouter)
+
+	appTransferRouter := r.Router.PathPrefix("/orchestrator/app-transfer").Subrouter()
+	r.appTransferRouter.InitAppTransferRouter(appTransferRouter)
+
+	terminalRecordingRouter := r.Router.PathPrefix("/orchestrator/terminal-recording").Subrouter()
+	r.terminalRecordingRouter.InitTerminalRecordingRouter(terminalRecordingRouter)
+
+	terminalCommandPolicyRouter := r.Router.PathPrefix("/orchestrator/terminal-command-policy").Subrouter()
+	r.terminalCommandPolicyRouter.InitTerminalCommandPolicyRouter(terminalCommandPolicyRouter)
+
 	infraConfigRouter := r.Router.PathPrefix("/orchestrator/infra-config").Subrouter()
 	r.infraConfigRouter.InitInfraConfigRouter(infraConfigRouter)
 
@@ -0,0 +1,236 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package terminal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/terminal/commandPolicy"
+	"github.com/devtron-labs/devtron/pkg/terminal/commandPolicy/bean"
+	"github.com/devtron-labs/devtron/pkg/terminal/commandPolicy/repository"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const defaultCommandAuditPageSize = 50
+
+type TerminalCommandPolicyRestHandler interface {
+	GetPolicies(w http.ResponseWriter, r *http.Request)
+	CreatePolicy(w http.ResponseWriter, r *http.Request)
+	UpdatePolicy(w http.ResponseWriter, r *http.Request)
+	DeletePolicy(w http.ResponseWriter, r *http.Request)
+	GetCommandAudits(w http.ResponseWriter, r *http.Request)
+}
+
+type TerminalCommandPolicyRestHandlerImpl struct {
+	logger                       *zap.SugaredLogger
+	terminalCommandPolicyService commandPolicy.TerminalCommandPolicyService
+	userService                  user.UserService
+	enforcer                     casbin.Enforcer
+	validator                    *validator.Validate
+}
+
+func NewTerminalCommandPolicyRestHandlerImpl(logger *zap.SugaredLogger,
+	terminalCommandPolicyService commandPolicy.TerminalCommandPolicyService,
+	userService user.UserService, enforcer casbin.Enforcer,
+	validator *validator.Validate) *TerminalCommandPolicyRestHandlerImpl {
+	return &TerminalCommandPolicyRestHandlerImpl{
+		logger:                       logger,
+		terminalCommandPolicyService: terminalCommandPolicyService,
+		userService:                  userService,
+		enforcer:                     enforcer,
+		validator:                    validator,
+	}
+}
+
+func (handler *TerminalCommandPolicyRestHandlerImpl) GetPolicies(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.checkAccess(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	resp, err := handler.terminalCommandPolicyService.GetPolicies()
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *TerminalCommandPolicyRestHandlerImpl) CreatePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkAccess(w, r, casbin.ActionCreate)
+	if !ok {
+		return
+	}
+	request, ok := handler.decodePolicy(w, r)
+	if !ok {
+		return
+	}
+	request.UserId = userId
+	resp, err := handler.terminalCommandPolicyService.CreatePolicy(request)
+	if err != nil {
+		handler.logger.Errorw("error in creating terminal command policy", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *TerminalCommandPolicyRestHandlerImpl) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkAccess(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	request, ok := handler.decodePolicy(w, r)
+	if !ok {
+		return
+	}
+	if request.Id <= 0 {
+		common.WriteJsonResp(w, errors.New("policy id is required"), nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	resp, err := handler.terminalCommandPolicyService.UpdatePolicy(request)
+	if err != nil {
+		handler.logger.Errorw("error in updating terminal command policy", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *TerminalCommandPolicyRestHandlerImpl) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkAccess(w, r, casbin.ActionDelete)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid policy id", http.StatusBadRequest)
+		return
+	}
+	err = handler.terminalCommandPolicyService.DeletePolicy(id, userId)
+	if err != nil {
+		handler.logger.Errorw("error in deleting terminal command policy", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, id, http.StatusOK)
+}
+
+func (handler *TerminalCommandPolicyRestHandlerImpl) GetCommandAudits(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.checkAccess(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	filter, err := getCommandAuditFilter(r)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	resp, err := handler.terminalCommandPolicyService.GetCommandAudits(filter)
+	if err != nil {
+		handler.logger.Errorw("error in fetching terminal command audits", "filter", filter, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *TerminalCommandPolicyRestHandlerImpl) decodePolicy(w http.ResponseWriter, r *http.Request) (*bean.TerminalCommandPolicyDto, bool) {
+	request := &bean.TerminalCommandPolicyDto{}
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("error in decoding terminal command policy", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err in terminal command policy", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return nil, false
+	}
+	return request, true
+}
+
+// checkAccess allows the command policies and audits to super admins only like the cluster terminal itself
+func (handler *TerminalCommandPolicyRestHandlerImpl) checkAccess(w http.ResponseWriter, r *http.Request, action string) (int32, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, false
+	}
+	token := r.Header.Get("token")
+	if !handler.enforcer.Enforce(token, casbin.ResourceGlobal, action, "*") {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return 0, false
+	}
+	return userId, true
+}
+
+func getCommandAuditFilter(r *http.Request) (*repository.CommandAuditFilter, error) {
+	v := r.URL.Query()
+	filter := &repository.CommandAuditFilter{
+		SessionId:   v.Get("sessionId"),
+		BlockedOnly: v.Get("blocked") == "true",
+		Size:        defaultCommandAuditPageSize,
+	}
+	var err error
+	if userId := v.Get("userId"); len(userId) > 0 {
+		id, parseErr := strconv.ParseInt(userId, 10, 32)
+		if parseErr != nil {
+			return nil, fmt.Errorf("invalid userId %q", userId)
+		}
+		filter.UserId = int32(id)
+	}
+	if clusterId := v.Get("clusterId"); len(clusterId) > 0 {
+		if filter.ClusterId, err = strconv.Atoi(clusterId); err != nil {
+			return nil, fmt.Errorf("invalid clusterId %q", clusterId)
+		}
+	}
+	if from := v.Get("from"); len(from) > 0 {
+		fromTime, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from %q, expected RFC3339 time", from)
+		}
+		filter.From = &fromTime
+	}
+	if to := v.Get("to"); len(to) > 0 {
+		toTime, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to %q, expected RFC3339 time", to)
+		}
+		filter.To = &toTime
+	}
+	if offset := v.Get("offset"); len(offset) > 0 {
+		if filter.Offset, err = strconv.Atoi(offset); err != nil || filter.Offset < 0 {
+			return nil, fmt.Errorf("invalid offset %q", offset)
+		}
+	}
+	if size := v.Get("size"); len(size) > 0 {
+		if filter.Size, err = strconv.Atoi(size); err != nil || filter.Size <= 0 {
+			return nil, fmt.Errorf("invalid size %q", size)
+		}
+	}
+	return filter, nil
+}
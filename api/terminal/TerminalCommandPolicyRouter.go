@@ -0,0 +1,48 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package terminal
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type TerminalCommandPolicyRouter interface {
+	InitTerminalCommandPolicyRouter(terminalCommandPolicyRouter *mux.Router)
+}
+
+type TerminalCommandPolicyRouterImpl struct {
+	terminalCommandPolicyRestHandler TerminalCommandPolicyRestHandler
+}
+
+func NewTerminalCommandPolicyRouterImpl(terminalCommandPolicyRestHandler TerminalCommandPolicyRestHandler) *TerminalCommandPolicyRouterImpl {
+	return &TerminalCommandPolicyRouterImpl{
+		terminalCommandPolicyRestHandler: terminalCommandPolicyRestHandler,
+	}
+}
+
+func (router TerminalCommandPolicyRouterImpl) InitTerminalCommandPolicyRouter(terminalCommandPolicyRouter *mux.Router) {
+	terminalCommandPolicyRouter.Path("").
+		HandlerFunc(router.terminalCommandPolicyRestHandler.GetPolicies).Methods("GET")
+	terminalCommandPolicyRouter.Path("").
+		HandlerFunc(router.terminalCommandPolicyRestHandler.CreatePolicy).Methods("POST")
+	terminalCommandPolicyRouter.Path("").
+		HandlerFunc(router.terminalCommandPolicyRestHandler.UpdatePolicy).Methods("PUT")
+	terminalCommandPolicyRouter.Path("/audit").
+		HandlerFunc(router.terminalCommandPolicyRestHandler.GetCommandAudits).Methods("GET")
+	terminalCommandPolicyRouter.Path("/{id}").
+		HandlerFunc(router.terminalCommandPolicyRestHandler.DeletePolicy).Methods("DELETE")
+}
@@ -0,0 +1,214 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package terminal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/api/restHandler/common"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/terminal/recording"
+	"github.com/devtron-labs/devtron/pkg/terminal/recording/bean"
+	"github.com/devtron-labs/devtron/pkg/terminal/recording/repository"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"gopkg.in/go-playground/validator.v9"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const defaultRecordingPageSize = 20
+
+type TerminalRecordingRestHandler interface {
+	GetRecordings(w http.ResponseWriter, r *http.Request)
+	ReplayRecording(w http.ResponseWriter, r *http.Request)
+	GetRecordingPolicy(w http.ResponseWriter, r *http.Request)
+	SaveRecordingPolicy(w http.ResponseWriter, r *http.Request)
+}
+
+type TerminalRecordingRestHandlerImpl struct {
+	logger                   *zap.SugaredLogger
+	terminalRecordingService recording.TerminalRecordingService
+	userService              user.UserService
+	enforcer                 casbin.Enforcer
+	validator                *validator.Validate
+}
+
+func NewTerminalRecordingRestHandlerImpl(logger *zap.SugaredLogger,
+	terminalRecordingService recording.TerminalRecordingService,
+	userService user.UserService, enforcer casbin.Enforcer,
+	validator *validator.Validate) *TerminalRecordingRestHandlerImpl {
+	return &TerminalRecordingRestHandlerImpl{
+		logger:                   logger,
+		terminalRecordingService: terminalRecordingService,
+		userService:              userService,
+		enforcer:                 enforcer,
+		validator:                validator,
+	}
+}
+
+func (handler *TerminalRecordingRestHandlerImpl) GetRecordings(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.checkAccess(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	filter, err := getRecordingFilter(r)
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	resp, err := handler.terminalRecordingService.GetRecordings(filter)
+	if err != nil {
+		handler.logger.Errorw("error in fetching terminal session recordings", "filter", filter, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *TerminalRecordingRestHandlerImpl) ReplayRecording(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.checkAccess(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		common.WriteJsonResp(w, err, "invalid recording id", http.StatusBadRequest)
+		return
+	}
+	file, cleanUp, err := handler.terminalRecordingService.GetRecordingForReplay(id)
+	if err != nil {
+		handler.logger.Errorw("error in fetching terminal session recording for replay", "id", id, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	defer func() {
+		if err := cleanUp(); err != nil {
+			handler.logger.Warnw("error in cleaning up downloaded terminal session recording", "id", id, "err", err)
+		}
+	}()
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%d%s", id, bean.AsciicastFileSuffix))
+	w.Header().Set("Content-Type", bean.AsciicastContentType)
+	_, err = io.Copy(w, file)
+	if err != nil {
+		handler.logger.Errorw("error in streaming terminal session recording", "id", id, "err", err)
+	}
+}
+
+func (handler *TerminalRecordingRestHandlerImpl) GetRecordingPolicy(w http.ResponseWriter, r *http.Request) {
+	if _, ok := handler.checkAccess(w, r, casbin.ActionGet); !ok {
+		return
+	}
+	resp, err := handler.terminalRecordingService.GetPolicy()
+	if err != nil {
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+func (handler *TerminalRecordingRestHandlerImpl) SaveRecordingPolicy(w http.ResponseWriter, r *http.Request) {
+	userId, ok := handler.checkAccess(w, r, casbin.ActionUpdate)
+	if !ok {
+		return
+	}
+	request := &bean.TerminalRecordingPolicyDto{}
+	err := json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		handler.logger.Errorw("error in decoding terminal recording policy", "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	err = handler.validator.Struct(request)
+	if err != nil {
+		handler.logger.Errorw("validation err in terminal recording policy", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusBadRequest)
+		return
+	}
+	request.UserId = userId
+	resp, err := handler.terminalRecordingService.SavePolicy(request)
+	if err != nil {
+		handler.logger.Errorw("error in saving terminal recording policy", "request", request, "err", err)
+		common.WriteJsonResp(w, err, nil, http.StatusInternalServerError)
+		return
+	}
+	common.WriteJsonResp(w, nil, resp, http.StatusOK)
+}
+
+// checkAccess allows recordings and their policy to super admins only as sessions may contain sensitive output
+func (handler *TerminalRecordingRestHandlerImpl) checkAccess(w http.ResponseWriter, r *http.Request, action string) (int32, bool) {
+	userId, err := handler.userService.GetLoggedInUser(r)
+	if userId == 0 || err != nil {
+		common.WriteJsonResp(w, err, "Unauthorized User", http.StatusUnauthorized)
+		return 0, false
+	}
+	token := r.Header.Get("token")
+	if !handler.enforcer.Enforce(token, casbin.ResourceGlobal, action, "*") {
+		common.WriteJsonResp(w, errors.New("unauthorized"), nil, http.StatusForbidden)
+		return 0, false
+	}
+	return userId, true
+}
+
+func getRecordingFilter(r *http.Request) (*repository.RecordingFilter, error) {
+	v := r.URL.Query()
+	filter := &repository.RecordingFilter{
+		Namespace: v.Get("namespace"),
+		PodName:   v.Get("podName"),
+		Size:      defaultRecordingPageSize,
+	}
+	var err error
+	if userId := v.Get("userId"); len(userId) > 0 {
+		id, parseErr := strconv.ParseInt(userId, 10, 32)
+		if parseErr != nil {
+			return nil, fmt.Errorf("invalid userId %q", userId)
+		}
+		filter.UserId = int32(id)
+	}
+	if clusterId := v.Get("clusterId"); len(clusterId) > 0 {
+		if filter.ClusterId, err = strconv.Atoi(clusterId); err != nil {
+			return nil, fmt.Errorf("invalid clusterId %q", clusterId)
+		}
+	}
+	if from := v.Get("from"); len(from) > 0 {
+		fromTime, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from %q, expected RFC3339 time", from)
+		}
+		filter.From = &fromTime
+	}
+	if to := v.Get("to"); len(to) > 0 {
+		toTime, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to %q, expected RFC3339 time", to)
+		}
+		filter.To = &toTime
+	}
+	if offset := v.Get("offset"); len(offset) > 0 {
+		if filter.Offset, err = strconv.Atoi(offset); err != nil || filter.Offset < 0 {
+			return nil, fmt.Errorf("invalid offset %q", offset)
+		}
+	}
+	if size := v.Get("size"); len(size) > 0 {
+		if filter.Size, err = strconv.Atoi(size); err != nil || filter.Size <= 0 {
+			return nil, fmt.Errorf("invalid size %q", size)
+		}
+	}
+	return filter, nil
+}
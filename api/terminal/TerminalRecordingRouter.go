@@ -0,0 +1,46 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package terminal
+
+import (
+	"github.com/gorilla/mux"
+)
+
+type TerminalRecordingRouter interface {
+	InitTerminalRecordingRouter(terminalRecordingRouter *mux.Router)
+}
+
+type TerminalRecordingRouterImpl struct {
+	terminalRecordingRestHandler TerminalRecordingRestHandler
+}
+
+func NewTerminalRecordingRouterImpl(terminalRecordingRestHandler TerminalRecordingRestHandler) *TerminalRecordingRouterImpl {
+	return &TerminalRecordingRouterImpl{
+		terminalRecordingRestHandler: terminalRecordingRestHandler,
+	}
+}
+
+func (router TerminalRecordingRouterImpl) InitTerminalRecordingRouter(terminalRecordingRouter *mux.Router) {
+	terminalRecordingRouter.Path("").
+		HandlerFunc(router.terminalRecordingRestHandler.GetRecordings).Methods("GET")
+	terminalRecordingRouter.Path("/policy").
+		HandlerFunc(router.terminalRecordingRestHandler.GetRecordingPolicy).Methods("GET")
+	terminalRecordingRouter.Path("/policy").
+		HandlerFunc(router.terminalRecordingRestHandler.SaveRecordingPolicy).Methods("PUT")
+	terminalRecordingRouter.Path("/{id}/replay").
+		HandlerFunc(router.terminalRecordingRestHandler.ReplayRecording).Methods("GET")
+}
@@ -19,6 +19,8 @@ package terminal
 import (
 	"github.com/devtron-labs/devtron/internal/sql/repository"
 	"github.com/devtron-labs/devtron/pkg/clusterTerminalAccess"
+	"github.com/devtron-labs/devtron/pkg/terminal/commandPolicy"
+	"github.com/devtron-labs/devtron/pkg/terminal/recording"
 	"github.com/google/wire"
 )
 
@@ -32,4 +34,16 @@ var TerminalWireSet = wire.NewSet(
 	wire.Bind(new(clusterTerminalAccess.UserTerminalAccessService), new(*clusterTerminalAccess.UserTerminalAccessServiceImpl)),
 	repository.NewTerminalAccessRepositoryImpl,
 	wire.Bind(new(repository.TerminalAccessRepository), new(*repository.TerminalAccessRepositoryImpl)),
+
+	recording.WireSet,
+	NewTerminalRecordingRouterImpl,
+	wire.Bind(new(TerminalRecordingRouter), new(*TerminalRecordingRouterImpl)),
+	NewTerminalRecordingRestHandlerImpl,
+	wire.Bind(new(TerminalRecordingRestHandler), new(*TerminalRecordingRestHandlerImpl)),
+
+	commandPolicy.WireSet,
+	NewTerminalCommandPolicyRouterImpl,
+	wire.Bind(new(TerminalCommandPolicyRouter), new(*TerminalCommandPolicyRouterImpl)),
+	NewTerminalCommandPolicyRestHandlerImpl,
+	wire.Bind(new(TerminalCommandPolicyRestHandler), new(*TerminalCommandPolicyRestHandlerImpl)),
 )
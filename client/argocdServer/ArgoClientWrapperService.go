@@ -90,6 +90,15 @@ type ApplicationClientWrapper interface {
 
 	DeleteArgoAppWithK8sClient(ctx context.Context, clusterId int, namespace, appName string, cascadeDelete bool) error
 
+	// StartArgoAppOperationWithK8sClient sets the operation on the argoCd app object, the application controller picks it up and executes it
+	StartArgoAppOperationWithK8sClient(ctx context.Context, clusterId int, namespace, appName string, operation *v1alpha1.Operation) error
+
+	// RefreshArgoAppWithK8sClient annotates the argoCd app object to request a refresh from the application controller
+	RefreshArgoAppWithK8sClient(ctx context.Context, clusterId int, namespace, appName string, refreshType v1alpha1.RefreshType) error
+
+	// GetManagedResources returns the target and live state of the resources of an app on the argoCd instance connected to devtron
+	GetManagedResources(ctx context.Context, appName, appNamespace string) ([]*v1alpha1.ResourceDiff, error)
+
 	// SyncArgoCDApplicationIfNeededAndRefresh - if ARGO_AUTO_SYNC_ENABLED=true, app will be refreshed to initiate refresh at argoCD side or else it will be synced and refreshed
 	SyncArgoCDApplicationIfNeededAndRefresh(ctx context.Context, argoAppName, targetRevision string) error
 
@@ -440,6 +449,54 @@ func (impl *ArgoClientWrapperServiceImpl) DeleteArgoAppWithK8sClient(ctx context
 	return nil
 }
 
+func (impl *ArgoClientWrapperServiceImpl) StartArgoAppOperationWithK8sClient(ctx context.Context, clusterId int, namespace, appName string, operation *v1alpha1.Operation) error {
+	k8sConfig, err := impl.acdConfigGetter.GetK8sConfigWithClusterIdAndNamespace(clusterId, namespace)
+	if err != nil {
+		impl.logger.Errorw("error in getting k8s config", "err", err)
+		return err
+	}
+	patch, err := json.Marshal(map[string]interface{}{"operation": operation})
+	if err != nil {
+		impl.logger.Errorw("error in marshalling argo app operation", "app", appName, "err", err)
+		return err
+	}
+	err = impl.argoK8sClient.PatchArgoApplication(ctx, k8sConfig, appName, string(patch))
+	if err != nil {
+		impl.logger.Errorw("error in starting operation on argo app", "app", appName, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *ArgoClientWrapperServiceImpl) GetManagedResources(ctx context.Context, appName, appNamespace string) ([]*v1alpha1.ResourceDiff, error) {
+	grpcConfig, err := impl.acdConfigGetter.GetGRPCConfig()
+	if err != nil {
+		impl.logger.Errorw("error in getting grpc config", "err", err)
+		return nil, err
+	}
+	resp, err := impl.acdApplicationClient.ManagedResources(ctx, grpcConfig, &application2.ResourcesQuery{ApplicationName: &appName, AppNamespace: &appNamespace})
+	if err != nil {
+		impl.logger.Errorw("error in getting managed resources of argo app", "app", appName, "namespace", appNamespace, "err", err)
+		return nil, err
+	}
+	return resp.GetItems(), nil
+}
+
+func (impl *ArgoClientWrapperServiceImpl) RefreshArgoAppWithK8sClient(ctx context.Context, clusterId int, namespace, appName string, refreshType v1alpha1.RefreshType) error {
+	k8sConfig, err := impl.acdConfigGetter.GetK8sConfigWithClusterIdAndNamespace(clusterId, namespace)
+	if err != nil {
+		impl.logger.Errorw("error in getting k8s config", "err", err)
+		return err
+	}
+	patch := fmt.Sprintf(`{"metadata": {"annotations": {"%s": "%s"}}}`, v1alpha1.AnnotationKeyRefresh, refreshType)
+	err = impl.argoK8sClient.PatchArgoApplication(ctx, k8sConfig, appName, patch)
+	if err != nil {
+		impl.logger.Errorw("error in refreshing argo app", "app", appName, "refreshType", refreshType, "err", err)
+		return err
+	}
+	return nil
+}
+
 func (impl *ArgoClientWrapperServiceImpl) IsArgoAppPatchRequired(argoAppSpec *v1alpha1.ApplicationSource, currentGitRepoUrl, currentTargetRevision, currentChartPath string) bool {
 	if argoAppSpec == nil {
 		// if argo app spec is nil, then no need to patch
@@ -62,6 +62,21 @@ func (impl *ArgoClientWrapperServiceEAImpl) DeleteArgoAppWithK8sClient(ctx conte
 	return nil
 }
 
+func (impl *ArgoClientWrapperServiceEAImpl) StartArgoAppOperationWithK8sClient(ctx context.Context, clusterId int, namespace, appName string, operation *v1alpha1.Operation) error {
+	impl.logger.Info("not implemented for EA mode")
+	return nil
+}
+
+func (impl *ArgoClientWrapperServiceEAImpl) GetManagedResources(ctx context.Context, appName, appNamespace string) ([]*v1alpha1.ResourceDiff, error) {
+	impl.logger.Info("not implemented for EA mode")
+	return nil, nil
+}
+
+func (impl *ArgoClientWrapperServiceEAImpl) RefreshArgoAppWithK8sClient(ctx context.Context, clusterId int, namespace, appName string, refreshType v1alpha1.RefreshType) error {
+	impl.logger.Info("not implemented for EA mode")
+	return nil
+}
+
 func (impl *ArgoClientWrapperServiceEAImpl) SyncArgoCDApplicationIfNeededAndRefresh(ctx context.Context, argoAppName, targetRevision string) error {
 	impl.logger.Info("not implemented")
 	return nil
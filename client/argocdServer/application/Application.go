@@ -52,6 +52,9 @@ type ServiceClient interface {
 	Delete(ctx context.Context, grpcConfig *argoApplication.ArgoGRPCConfig, query *application.ApplicationDeleteRequest) (*application.ApplicationResponse, error)
 
 	TerminateOperation(ctx context.Context, grpcConfig *argoApplication.ArgoGRPCConfig, query *application.OperationTerminateRequest) (*application.OperationTerminateResponse, error)
+
+	// ManagedResources returns the target state rendered from the app source along with the live state of each resource
+	ManagedResources(ctx context.Context, grpcConfig *argoApplication.ArgoGRPCConfig, query *application.ResourcesQuery) (*application.ManagedResourcesResponse, error)
 }
 
 type ServiceClientImpl struct {
@@ -182,3 +185,15 @@ func (c ServiceClientImpl) TerminateOperation(ctx context.Context, grpcConfig *a
 	resp, err := asc.TerminateOperation(ctx, query)
 	return resp, err
 }
+
+func (c ServiceClientImpl) ManagedResources(ctx context.Context, grpcConfig *argoApplication.ArgoGRPCConfig, query *application.ResourcesQuery) (*application.ManagedResourcesResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, argoApplication.TimeoutSlow)
+	defer cancel()
+	asc, conn, err := c.GetArgoClient(ctx, grpcConfig)
+	if err != nil {
+		c.logger.Errorw("error getting ArgoCD client", "error", err)
+		return nil, err
+	}
+	defer util.Close(conn, c.logger)
+	return asc.ManagedResources(ctx, query)
+}
@@ -20,6 +20,7 @@ import (
 	k8sUtil "github.com/devtron-labs/common-lib/utils/k8s"
 	"github.com/devtron-labs/devtron/client/argocdServer/bean"
 	bean2 "github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterCredentials"
 	"github.com/devtron-labs/devtron/pkg/cluster/read"
 	"github.com/devtron-labs/devtron/pkg/util"
 	util2 "github.com/devtron-labs/devtron/util"
@@ -33,12 +34,13 @@ type ArgoCDConfigGetter interface {
 }
 
 type ArgoCDConfigGetterImpl struct {
-	config              *bean.Config
-	devtronSecretConfig *util2.DevtronSecretConfig
-	ACDAuthConfig       *util.ACDAuthConfig
-	clusterReadService  read.ClusterReadService
-	logger              *zap.SugaredLogger
-	K8sService          k8sUtil.K8sService
+	config                   *bean.Config
+	devtronSecretConfig      *util2.DevtronSecretConfig
+	ACDAuthConfig            *util.ACDAuthConfig
+	clusterReadService       read.ClusterReadService
+	logger                   *zap.SugaredLogger
+	K8sService               k8sUtil.K8sService
+	clusterCredentialService clusterCredentials.ClusterCredentialService
 }
 
 func NewArgoCDConfigGetter(
@@ -48,14 +50,16 @@ func NewArgoCDConfigGetter(
 	clusterReadService read.ClusterReadService,
 	logger *zap.SugaredLogger,
 	K8sService k8sUtil.K8sService,
+	clusterCredentialService clusterCredentials.ClusterCredentialService,
 ) *ArgoCDConfigGetterImpl {
 	return &ArgoCDConfigGetterImpl{
-		config:              config,
-		devtronSecretConfig: environmentVariables.DevtronSecretConfig,
-		ACDAuthConfig:       ACDAuthConfig,
-		clusterReadService:  clusterReadService,
-		logger:              logger,
-		K8sService:          K8sService,
+		config:                   config,
+		devtronSecretConfig:      environmentVariables.DevtronSecretConfig,
+		ACDAuthConfig:            ACDAuthConfig,
+		clusterReadService:       clusterReadService,
+		logger:                   logger,
+		K8sService:               K8sService,
+		clusterCredentialService: clusterCredentialService,
 	}
 }
 
@@ -76,7 +80,11 @@ func (impl *ArgoCDConfigGetterImpl) GetK8sConfig() (*bean.ArgoK8sConfig, error)
 		impl.logger.Errorw("error in fetching cluster bean from db", "err", err)
 		return nil, err
 	}
-	cfg := clusterBean.GetClusterConfig()
+	cfg, err := clusterBean.ResolveClusterConfig(impl.clusterCredentialService)
+	if err != nil {
+		impl.logger.Errorw("error in resolving cluster credentials", "err", err, "clusterId", clusterBean.Id)
+		return nil, err
+	}
 	restConfig, err := impl.K8sService.GetRestConfigByCluster(cfg)
 	if err != nil {
 		impl.logger.Errorw("error in getting k8s config", "err", err)
@@ -96,7 +104,11 @@ func (impl *ArgoCDConfigGetterImpl) GetK8sConfigWithClusterIdAndNamespace(cluste
 		impl.logger.Errorw("error in fetching cluster bean from db", "err", err)
 		return nil, err
 	}
-	cfg := clusterBean.GetClusterConfig()
+	cfg, err := clusterBean.ResolveClusterConfig(impl.clusterCredentialService)
+	if err != nil {
+		impl.logger.Errorw("error in resolving cluster credentials", "err", err, "clusterId", clusterBean.Id)
+		return nil, err
+	}
 	restConfig, err := impl.K8sService.GetRestConfigByCluster(cfg)
 	if err != nil {
 		impl.logger.Errorw("error in getting k8s config", "err", err)
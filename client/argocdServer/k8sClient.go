@@ -60,6 +60,7 @@ type ArgoK8sClient interface {
 	CreateAcdApp(ctx context.Context, appRequest *AppTemplate, applicationTemplatePath string) (string, error)
 	GetArgoApplication(k8sConfig *bean.ArgoK8sConfig, appName string) (map[string]interface{}, error)
 	DeleteArgoApplication(ctx context.Context, k8sConfig *bean.ArgoK8sConfig, appName string, cascadeDelete bool) error
+	PatchArgoApplication(ctx context.Context, k8sConfig *bean.ArgoK8sConfig, appName string, patchJSON string) error
 }
 type ArgoK8sClientImpl struct {
 	logger  *zap.SugaredLogger
@@ -250,3 +251,12 @@ func (impl ArgoK8sClientImpl) DeleteArgoApplication(ctx context.Context, k8sConf
 
 	return nil
 }
+
+func (impl ArgoK8sClientImpl) PatchArgoApplication(ctx context.Context, k8sConfig *bean.ArgoK8sConfig, appName string, patchJSON string) error {
+	_, err := impl.k8sUtil.PatchResourceRequest(ctx, k8sConfig.RestConfig, types.MergePatchType, patchJSON, appName, k8sConfig.AcdNamespace, v1alpha1.ApplicationSchemaGroupVersionKind)
+	if err != nil {
+		impl.logger.Errorw("error in patching argo application", "acdAppName", appName, "err", err)
+		return err
+	}
+	return nil
+}
@@ -22,12 +22,13 @@ import (
 	"encoding/json"
 	"fmt"
 	cloudProviderIdentifier "github.com/devtron-labs/common-lib/cloud-provider-identifier"
-	"github.com/devtron-labs/common-lib/utils/k8s/commonBean"
 	"github.com/devtron-labs/devtron/api/helm-app/gRPC"
 	installedAppReader "github.com/devtron-labs/devtron/pkg/appStore/installedApp/read"
 	bean2 "github.com/devtron-labs/devtron/pkg/attributes/bean"
 	"github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/adapter"
 	bean3 "github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterCredentials"
 	module2 "github.com/devtron-labs/devtron/pkg/module/bean"
 	cron3 "github.com/devtron-labs/devtron/util/cron"
 	"net/http"
@@ -78,6 +79,7 @@ type TelemetryEventClientImpl struct {
 	userAttributesRepository       repository.UserAttributesRepository
 	cloudProviderIdentifierService cloudProviderIdentifier.ProviderIdentifierService
 	telemetryConfig                TelemetryConfig
+	clusterCredentialService       clusterCredentials.ClusterCredentialService
 }
 
 type TelemetryEventClient interface {
@@ -96,7 +98,8 @@ func NewTelemetryEventClientImpl(logger *zap.SugaredLogger, client *http.Client,
 	serverDataStore *serverDataStore.ServerDataStore, userAuditService user2.UserAuditService,
 	helmAppClient gRPC.HelmAppClient,
 	cloudProviderIdentifierService cloudProviderIdentifier.ProviderIdentifierService, cronLogger *cron3.CronLoggerImpl,
-	installedAppReadService installedAppReader.InstalledAppReadServiceEA) (*TelemetryEventClientImpl, error) {
+	installedAppReadService installedAppReader.InstalledAppReadServiceEA,
+	clusterCredentialService clusterCredentials.ClusterCredentialService) (*TelemetryEventClientImpl, error) {
 	cron := cron.New(
 		cron.WithChain(cron.Recover(cronLogger)))
 	cron.Start()
@@ -118,6 +121,7 @@ func NewTelemetryEventClientImpl(logger *zap.SugaredLogger, client *http.Client,
 		installedAppReadService:        installedAppReadService,
 		cloudProviderIdentifierService: cloudProviderIdentifierService,
 		telemetryConfig:                TelemetryConfig{},
+		clusterCredentialService:       clusterCredentialService,
 	}
 
 	watcher.HeartbeatEventForTelemetry()
@@ -265,18 +269,9 @@ func (impl *TelemetryEventClientImpl) SummaryDetailsForTelemetry() (cluster []be
 
 	for _, clusterDetail := range clusters {
 		req := &gRPC.AppListRequest{}
-		config := &gRPC.ClusterConfig{
-			ApiServerUrl:          clusterDetail.ServerUrl,
-			Token:                 clusterDetail.Config[commonBean.BearerToken],
-			ClusterId:             int32(clusterDetail.Id),
-			ClusterName:           clusterDetail.ClusterName,
-			InsecureSkipTLSVerify: clusterDetail.InsecureSkipTLSVerify,
-		}
-
-		if clusterDetail.InsecureSkipTLSVerify == false {
-			config.KeyData = clusterDetail.Config[commonBean.TlsKey]
-			config.CertData = clusterDetail.Config[commonBean.CertData]
-			config.CaData = clusterDetail.Config[commonBean.CertificateAuthorityData]
+		config, err := adapter.GetGrpcClusterConfig(clusterDetail, impl.clusterCredentialService)
+		if err != nil {
+			impl.logger.Errorw("error in resolving cluster credentials", "clusterId", clusterDetail.Id, "err", err)
 		}
 		req.Clusters = append(req.Clusters, config)
 		applicationStream, err := impl.helmAppClient.ListApplication(context.Background(), req)
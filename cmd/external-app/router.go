@@ -22,8 +22,10 @@ import (
 	chartProvider "github.com/devtron-labs/devtron/api/appStore/chartProvider"
 	appStoreDeployment "github.com/devtron-labs/devtron/api/appStore/deployment"
 	appStoreDiscover "github.com/devtron-labs/devtron/api/appStore/discover"
+	"github.com/devtron-labs/devtron/api/appStore/upgradeAdvisor"
 	appStoreValues "github.com/devtron-labs/devtron/api/appStore/values"
 	"github.com/devtron-labs/devtron/api/argoApplication"
+	"github.com/devtron-labs/devtron/api/auth/scim"
 	"github.com/devtron-labs/devtron/api/auth/sso"
 	"github.com/devtron-labs/devtron/api/auth/user"
 	"github.com/devtron-labs/devtron/api/chartRepo"
@@ -68,25 +70,29 @@ type MuxRouter struct {
 	appStoreValuesRouter     appStoreValues.AppStoreValuesRouter
 	appStoreDeploymentRouter appStoreDeployment.AppStoreDeploymentRouter
 	chartProviderRouter      chartProvider.ChartProviderRouter
+	upgradeAdvisorRouter     upgradeAdvisor.UpgradeAdvisorRouter
 	dockerRegRouter          router.DockerRegRouter
 
-	dashboardTelemetryRouter dashboardEvent.DashboardTelemetryRouter
-	commonDeploymentRouter   appStoreDeployment.CommonDeploymentRouter
-	externalLinksRouter      externalLink.ExternalLinkRouter
-	moduleRouter             module.ModuleRouter
-	serverRouter             server.ServerRouter
-	apiTokenRouter           apiToken.ApiTokenRouter
-	k8sCapacityRouter        capacity.K8sCapacityRouter
-	webhookHelmRouter        webhookHelm.WebhookHelmRouter
-	userAttributesRouter     router.UserAttributesRouter
-	telemetryRouter          router.TelemetryRouter
-	userTerminalAccessRouter terminal.UserTerminalAccessRouter
-	attributesRouter         router.AttributesRouter
-	appRouter                app.AppRouterEAMode
-	rbacRoleRouter           user.RbacRoleRouter
-	argoApplicationRouter    argoApplication.ArgoApplicationRouter
-	fluxApplicationRouter    fluxApplication.FluxApplicationRouter
-	userResourceRouter       userResource.Router
+	dashboardTelemetryRouter    dashboardEvent.DashboardTelemetryRouter
+	commonDeploymentRouter      appStoreDeployment.CommonDeploymentRouter
+	externalLinksRouter         externalLink.ExternalLinkRouter
+	moduleRouter                module.ModuleRouter
+	serverRouter                server.ServerRouter
+	apiTokenRouter              apiToken.ApiTokenRouter
+	k8sCapacityRouter           capacity.K8sCapacityRouter
+	webhookHelmRouter           webhookHelm.WebhookHelmRouter
+	userAttributesRouter        router.UserAttributesRouter
+	telemetryRouter             router.TelemetryRouter
+	userTerminalAccessRouter    terminal.UserTerminalAccessRouter
+	attributesRouter            router.AttributesRouter
+	appRouter                   app.AppRouterEAMode
+	rbacRoleRouter              user.RbacRoleRouter
+	argoApplicationRouter       argoApplication.ArgoApplicationRouter
+	fluxApplicationRouter       fluxApplication.FluxApplicationRouter
+	userResourceRouter          userResource.Router
+	scimRouter                  scim.ScimRouter
+	terminalRecordingRouter     terminal.TerminalRecordingRouter
+	terminalCommandPolicyRouter terminal.TerminalCommandPolicyRouter
 }
 
 func NewMuxRouter(
@@ -106,6 +112,7 @@ func NewMuxRouter(
 	appStoreValuesRouter appStoreValues.AppStoreValuesRouter,
 	appStoreDeploymentRouter appStoreDeployment.AppStoreDeploymentRouter,
 	chartProviderRouter chartProvider.ChartProviderRouter,
+	upgradeAdvisorRouter upgradeAdvisor.UpgradeAdvisorRouter,
 	dockerRegRouter router.DockerRegRouter,
 	dashboardTelemetryRouter dashboardEvent.DashboardTelemetryRouter,
 	commonDeploymentRouter appStoreDeployment.CommonDeploymentRouter,
@@ -121,43 +128,50 @@ func NewMuxRouter(
 	appRouter app.AppRouterEAMode,
 	rbacRoleRouter user.RbacRoleRouter, argoApplicationRouter argoApplication.ArgoApplicationRouter, fluxApplicationRouter fluxApplication.FluxApplicationRouter,
 	userResourceRouter userResource.Router,
+	scimRouter scim.ScimRouter,
+	terminalRecordingRouter terminal.TerminalRecordingRouter,
+	terminalCommandPolicyRouter terminal.TerminalCommandPolicyRouter,
 ) *MuxRouter {
 	r := &MuxRouter{
-		Router:                   mux.NewRouter(),
-		logger:                   logger,
-		ssoLoginRouter:           ssoLoginRouter,
-		teamRouter:               teamRouter,
-		UserAuthRouter:           UserAuthRouter,
-		userRouter:               userRouter,
-		commonRouter:             commonRouter,
-		clusterRouter:            clusterRouter,
-		dashboardRouter:          dashboardRouter,
-		helmAppRouter:            helmAppRouter,
-		environmentRouter:        environmentRouter,
-		k8sApplicationRouter:     k8sApplicationRouter,
-		chartRepositoryRouter:    chartRepositoryRouter,
-		appStoreDiscoverRouter:   appStoreDiscoverRouter,
-		appStoreValuesRouter:     appStoreValuesRouter,
-		appStoreDeploymentRouter: appStoreDeploymentRouter,
-		chartProviderRouter:      chartProviderRouter,
-		dockerRegRouter:          dockerRegRouter,
-		dashboardTelemetryRouter: dashboardTelemetryRouter,
-		commonDeploymentRouter:   commonDeploymentRouter,
-		externalLinksRouter:      externalLinkRouter,
-		moduleRouter:             moduleRouter,
-		serverRouter:             serverRouter,
-		apiTokenRouter:           apiTokenRouter,
-		k8sCapacityRouter:        k8sCapacityRouter,
-		webhookHelmRouter:        webhookHelmRouter,
-		userAttributesRouter:     userAttributesRouter,
-		telemetryRouter:          telemetryRouter,
-		userTerminalAccessRouter: userTerminalAccessRouter,
-		attributesRouter:         attributesRouter,
-		appRouter:                appRouter,
-		rbacRoleRouter:           rbacRoleRouter,
-		argoApplicationRouter:    argoApplicationRouter,
-		fluxApplicationRouter:    fluxApplicationRouter,
-		userResourceRouter:       userResourceRouter,
+		Router:                      mux.NewRouter(),
+		logger:                      logger,
+		ssoLoginRouter:              ssoLoginRouter,
+		teamRouter:                  teamRouter,
+		UserAuthRouter:              UserAuthRouter,
+		userRouter:                  userRouter,
+		commonRouter:                commonRouter,
+		clusterRouter:               clusterRouter,
+		dashboardRouter:             dashboardRouter,
+		helmAppRouter:               helmAppRouter,
+		environmentRouter:           environmentRouter,
+		k8sApplicationRouter:        k8sApplicationRouter,
+		chartRepositoryRouter:       chartRepositoryRouter,
+		appStoreDiscoverRouter:      appStoreDiscoverRouter,
+		appStoreValuesRouter:        appStoreValuesRouter,
+		appStoreDeploymentRouter:    appStoreDeploymentRouter,
+		chartProviderRouter:         chartProviderRouter,
+		upgradeAdvisorRouter:        upgradeAdvisorRouter,
+		dockerRegRouter:             dockerRegRouter,
+		dashboardTelemetryRouter:    dashboardTelemetryRouter,
+		commonDeploymentRouter:      commonDeploymentRouter,
+		externalLinksRouter:         externalLinkRouter,
+		moduleRouter:                moduleRouter,
+		serverRouter:                serverRouter,
+		apiTokenRouter:              apiTokenRouter,
+		k8sCapacityRouter:           k8sCapacityRouter,
+		webhookHelmRouter:           webhookHelmRouter,
+		userAttributesRouter:        userAttributesRouter,
+		telemetryRouter:             telemetryRouter,
+		userTerminalAccessRouter:    userTerminalAccessRouter,
+		attributesRouter:            attributesRouter,
+		appRouter:                   appRouter,
+		rbacRoleRouter:              rbacRoleRouter,
+		argoApplicationRouter:       argoApplicationRouter,
+		fluxApplicationRouter:       fluxApplicationRouter,
+		userResourceRouter:          userResourceRouter,
+		scimRouter:                  scimRouter,
+		terminalRecordingRouter:     terminalRecordingRouter,
+		terminalCommandPolicyRouter: terminalCommandPolicyRouter,
 	}
 	return r
 }
@@ -250,6 +264,11 @@ func (r *MuxRouter) Init() {
 	r.chartProviderRouter.Init(chartProviderSubRouter)
 	// chart provider router ends
 
+	// upgrade advisor router starts
+	upgradeAdvisorSubRouter := r.Router.PathPrefix("/orchestrator/app-store/upgrade-advisor").Subrouter()
+	r.upgradeAdvisorRouter.Init(upgradeAdvisorSubRouter)
+	// upgrade advisor router ends
+
 	// docker registry router starts
 	dockerRouter := r.Router.PathPrefix("/orchestrator/docker").Subrouter()
 	r.dockerRegRouter.InitDockerRegRouter(dockerRouter)
@@ -275,6 +294,10 @@ func (r *MuxRouter) Init() {
 	apiTokenRouter := r.Router.PathPrefix("/orchestrator/api-token").Subrouter()
 	r.apiTokenRouter.InitApiTokenRouter(apiTokenRouter)
 
+	// scim router, authenticated by the scim rest handler
+	scimRouter := r.Router.PathPrefix("/orchestrator/scim/v2").Subrouter()
+	r.scimRouter.Init(scimRouter)
+
 	// webhook helm app router
 	webhookHelmRouter := r.Router.PathPrefix("/orchestrator/webhook/helm").Subrouter()
 	r.webhookHelmRouter.InitWebhookHelmRouter(webhookHelmRouter)
@@ -288,6 +311,12 @@ func (r *MuxRouter) Init() {
 	userTerminalAccessRouter := r.Router.PathPrefix("/orchestrator/user/terminal").Subrouter()
 	r.userTerminalAccessRouter.InitTerminalAccessRouter(userTerminalAccessRouter)
 
+	terminalRecordingRouter := r.Router.PathPrefix("/orchestrator/terminal-recording").Subrouter()
+	r.terminalRecordingRouter.InitTerminalRecordingRouter(terminalRecordingRouter)
+
+	terminalCommandPolicyRouter := r.Router.PathPrefix("/orchestrator/terminal-command-policy").Subrouter()
+	r.terminalCommandPolicyRouter.InitTerminalCommandPolicyRouter(terminalCommandPolicyRouter)
+
 	attributeRouter := r.Router.PathPrefix("/orchestrator/attributes").Subrouter()
 	r.attributesRouter.InitAttributesRouter(attributeRouter)
 
@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// port-forward forwards a local port to a pod or service port through the Devtron port-forward API, e.g.
+//
+//	port-forward -server https://devtron.example.com -identifier 1 -namespace default -service redis -port 6379
+//
+// the api token is read from the DEVTRON_TOKEN env variable unless given with -token
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/k8s/portForward/client"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	serverUrl := flag.String("server", "", "devtron url")
+	token := flag.String("token", os.Getenv("DEVTRON_TOKEN"), "devtron api token")
+	target := &client.SessionTarget{}
+	flag.StringVar(&target.Identifier, "identifier", "", "cluster id, or app id as used by the terminal when appType is set")
+	flag.StringVar(&target.AppType, "appType", "", "app type of the identifier, 0 devtron, 1 helm, 2 argo, 3 flux")
+	flag.StringVar(&target.Namespace, "namespace", "default", "namespace of the pod or service")
+	flag.StringVar(&target.PodName, "pod", "", "pod to forward to")
+	flag.StringVar(&target.ServiceName, "service", "", "service to forward to, in place of pod")
+	flag.IntVar(&target.Port, "port", 0, "pod or service port")
+	localAddress := flag.String("address", "127.0.0.1", "local address to listen on")
+	localPort := flag.Int("localPort", 0, "local port to listen on, defaults to the target port")
+	flag.Parse()
+
+	if len(*serverUrl) == 0 || len(*token) == 0 || len(target.Identifier) == 0 || target.Port <= 0 ||
+		(len(target.PodName) == 0) == (len(target.ServiceName) == 0) {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *localPort == 0 {
+		*localPort = target.Port
+	}
+	logger, err := util.NewSugardLogger()
+	if err != nil {
+		log.Fatal(err)
+	}
+	localClient, err := client.NewLocalClient(*serverUrl, *token, logger)
+	if err != nil {
+		log.Fatal(err)
+	}
+	session, err := localClient.CreateSession(target)
+	if err != nil {
+		log.Fatal(err)
+	}
+	listener, err := net.Listen("tcp", net.JoinHostPort(*localAddress, fmt.Sprint(*localPort)))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Forwarding from %s -> %s/%s:%d, closed after %ds idle\n", listener.Addr().String(), session.Namespace, session.PodName, session.TargetPort, session.IdleTimeoutSecs)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	err = localClient.Forward(ctx, listener, session.ConnectPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -83,6 +83,7 @@ type CiArtifact struct {
 	Deployed              bool      `sql:"-"`
 	Latest                bool      `sql:"-"`
 	RunningOnParent       bool      `sql:"-"`
+	PromotedRegistryId    string    `sql:"-"` // registry the image was promoted to for the deployment environment, Image then points to it
 	sql.AuditLog
 }
 
@@ -33,6 +33,8 @@ const (
 	REGISTRYTYPE_ARTIFACT_REGISTRY           = "artifact-registry"
 	REGISTRYTYPE_OTHER                       = "other"
 	REGISTRYTYPE_DOCKER_HUB                  = "docker-hub"
+	REGISTRYTYPE_HARBOR                      = "harbor"
+	REGISTRYTYPE_QUAY                        = "quay"
 	JSON_KEY_USERNAME                 string = "_json_key"
 	STORAGE_ACTION_TYPE_PULL                 = "PULL"
 	STORAGE_ACTION_TYPE_PUSH                 = "PUSH"
@@ -43,6 +45,15 @@ const (
 
 type RegistryType string
 
+// GetClientCompatibleType returns the type handed to ci runner and kubelink, which log into harbor and quay like any
+// other distribution registry
+func (registryType RegistryType) GetClientCompatibleType() string {
+	if registryType == REGISTRYTYPE_HARBOR || registryType == REGISTRYTYPE_QUAY {
+		return REGISTRYTYPE_OTHER
+	}
+	return string(registryType)
+}
+
 var OCI_REGISRTY_REPO_TYPE_LIST = []string{OCI_REGISRTY_REPO_TYPE_CONTAINER, OCI_REGISRTY_REPO_TYPE_CHART}
 
 type DockerArtifactStore struct {
@@ -61,6 +72,7 @@ type DockerArtifactStore struct {
 	Connection             string       `sql:"connection" json:"connection,omitempty"`
 	Cert                   string       `sql:"cert" json:"cert,omitempty"`
 	Active                 bool         `sql:"active,notnull" json:"active"`
+	ImportScanResults      bool         `sql:"import_scan_results,notnull" json:"importScanResults"`
 	IpsConfig              *DockerRegistryIpsConfig
 	OCIRegistryConfig      []*OCIRegistryConfig
 	sql.AuditLog
@@ -126,6 +126,7 @@ type PipelineRepository interface {
 	UpdateCdPipelineAfterDeployment(deploymentAppType string, cdPipelineIdIncludes []int, userId int32, delete bool) error
 	FindNumberOfAppsWithCdPipeline(appIds []int) (count int, err error)
 	GetAppAndEnvDetailsForDeploymentAppTypePipeline(deploymentAppType string, clusterIds []int) ([]*Pipeline, error)
+	FindActiveByDeploymentAppType(deploymentAppType string) ([]*Pipeline, error)
 	GetArgoPipelinesHavingTriggersStuckInLastPossibleNonTerminalTimelines(pendingSinceSeconds int, timeForDegradation int) ([]*Pipeline, error)
 	GetArgoPipelinesHavingLatestTriggerStuckInNonTerminalStatuses(deployedBeforeMinutes int, getPipelineDeployedWithinHours int) ([]*Pipeline, error)
 	FindIdsByAppIdsAndEnvironmentIds(appIds, environmentIds []int) (ids []int, err error)
@@ -666,6 +667,19 @@ func (impl *PipelineRepositoryImpl) GetAppAndEnvDetailsForDeploymentAppTypePipel
 	return pipelines, err
 }
 
+func (impl *PipelineRepositoryImpl) FindActiveByDeploymentAppType(deploymentAppType string) ([]*Pipeline, error) {
+	var pipelines []*Pipeline
+	err := impl.dbConnection.
+		Model(&pipelines).
+		Column("pipeline.*", "App", "Environment").
+		Join("LEFT JOIN deployment_config dc on dc.active=true and dc.app_id = pipeline.app_id and dc.environment_id=pipeline.environment_id").
+		Where("app.active = ?", true).
+		Where("pipeline.deleted = ?", false).
+		Where("(pipeline.deployment_app_type=? or dc.deployment_app_type=?)", deploymentAppType, deploymentAppType).
+		Select()
+	return pipelines, err
+}
+
 func (impl *PipelineRepositoryImpl) GetArgoPipelinesHavingTriggersStuckInLastPossibleNonTerminalTimelines(pendingSinceSeconds int, timeForDegradation int) ([]*Pipeline, error) {
 	var pipelines []*Pipeline
 	queryString := `select p.* from pipeline p inner join cd_workflow cw on cw.pipeline_id = p.id  
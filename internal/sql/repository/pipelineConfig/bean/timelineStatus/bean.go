@@ -51,6 +51,10 @@ const (
 	TIMELINE_STATUS_UNABLE_TO_FETCH_STATUS TimelineStatus = "UNABLE_TO_FETCH_STATUS"
 	TIMELINE_STATUS_DEPLOYMENT_SUPERSEDED  TimelineStatus = "DEPLOYMENT_SUPERSEDED"
 	TIMELINE_STATUS_MANIFEST_GENERATED     TimelineStatus = "HELM_PACKAGE_GENERATED" // TODO: remove as this deployment type is not supported
+
+	TIMELINE_STATUS_CANARY_ANALYSIS_STARTED   TimelineStatus = "CANARY_ANALYSIS_STARTED"
+	TIMELINE_STATUS_CANARY_ANALYSIS_SUCCEEDED TimelineStatus = "CANARY_ANALYSIS_SUCCEEDED"
+	TIMELINE_STATUS_CANARY_ANALYSIS_FAILED    TimelineStatus = "CANARY_ANALYSIS_FAILED"
 )
 
 const (
@@ -62,4 +66,5 @@ const (
 	TIMELINE_DESCRIPTION_ARGOCD_SYNC_COMPLETED        string = "ArgoCD sync completed."
 	TIMELINE_DESCRIPTION_DEPLOYMENT_COMPLETED         string = "Deployment has been performed successfully. Waiting for application to be healthy..."
 	TIMELINE_DESCRIPTION_DEPLOYMENT_SUPERSEDED        string = "This deployment is superseded."
+	TIMELINE_DESCRIPTION_CANARY_ANALYSIS_STARTED      string = "Canary analysis started using template %s."
 )
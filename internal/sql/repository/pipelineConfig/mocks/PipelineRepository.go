@@ -1196,6 +1196,36 @@ func (_m *PipelineRepository) GetAppAndEnvDetailsForDeploymentAppTypePipeline(de
 	return r0, r1
 }
 
+// FindActiveByDeploymentAppType provides a mock function with given fields: deploymentAppType
+func (_m *PipelineRepository) FindActiveByDeploymentAppType(deploymentAppType string) ([]*pipelineConfig.Pipeline, error) {
+	ret := _m.Called(deploymentAppType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindActiveByDeploymentAppType")
+	}
+
+	var r0 []*pipelineConfig.Pipeline
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]*pipelineConfig.Pipeline, error)); ok {
+		return rf(deploymentAppType)
+	}
+	if rf, ok := ret.Get(0).(func(string) []*pipelineConfig.Pipeline); ok {
+		r0 = rf(deploymentAppType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*pipelineConfig.Pipeline)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(deploymentAppType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetArgoPipelineByArgoAppName provides a mock function with given fields: argoAppName
 func (_m *PipelineRepository) GetArgoPipelineByArgoAppName(argoAppName string) (pipelineConfig.Pipeline, error) {
 	ret := _m.Called(argoAppName)
@@ -16,10 +16,19 @@
 
 package apiToken
 
-import "github.com/golang-jwt/jwt/v4"
+import (
+	casbinBean "github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin/bean"
+	"github.com/golang-jwt/jwt/v4"
+)
 
 type ApiTokenCustomClaims struct {
 	Email   string `json:"email"`
 	Version string `json:"version"`
+	// Scope and AllowedIps are embedded only in tokens minted with them
+	Scope      *casbinBean.ApiTokenScope `json:"apiTokenScope,omitempty"`
+	AllowedIps []string                  `json:"allowedIps,omitempty"`
 	jwt.RegisteredClaims
 }
+
+// MaxRotationOverlapInMs caps the period for which the previous token stays valid after a rotation
+const MaxRotationOverlapInMs = int64(7 * 24 * 60 * 60 * 1000)
@@ -22,6 +22,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/go-pg/pg"
 	"github.com/go-pg/pg/orm"
+	"time"
 )
 
 type ApiToken struct {
@@ -33,7 +34,16 @@ type ApiToken struct {
 	Description  string   `sql:"description, notnull"`
 	ExpireAtInMs int64    `sql:"expire_at_in_ms"`
 	Token        string   `sql:"token, notnull"`
-	User         *repository.UserModel
+	// Scope is the json of the casbin bean ApiTokenScope embedded in the token, empty for unscoped tokens
+	Scope      string   `sql:"scope"`
+	AllowedIps []string `sql:"allowed_ips" pg:",array"`
+	// PreviousVersion of a rotated token is accepted till PreviousVersionExpireAtInMs
+	PreviousVersion             int        `sql:"previous_version"`
+	PreviousVersionExpireAtInMs int64      `sql:"previous_version_expire_at_in_ms"`
+	LastUsedAt                  *time.Time `sql:"last_used_at"`
+	LastUsedByIp                string     `sql:"last_used_by_ip"`
+	LastUsedVersion             int        `sql:"last_used_version"`
+	User                        *repository.UserModel
 	sql.AuditLog
 }
 
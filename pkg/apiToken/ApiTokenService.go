@@ -17,9 +17,14 @@
 package apiToken
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
+	casbinBean "github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin/bean"
 	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	userHelper "github.com/devtron-labs/devtron/pkg/auth/user/helper"
+	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
@@ -28,6 +33,7 @@ import (
 	"github.com/devtron-labs/authenticator/middleware"
 	openapi "github.com/devtron-labs/devtron/api/openapi/openapiClient"
 	user2 "github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/auth/user/adapter"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/go-pg/pg"
 	"github.com/golang-jwt/jwt/v4"
@@ -38,26 +44,30 @@ type ApiTokenService interface {
 	GetAllActiveApiTokens() ([]*openapi.ApiToken, error)
 	CreateApiToken(request *openapi.CreateApiTokenRequest, createdBy int32, managerAuth func(resource, token, object string) bool) (*openapi.CreateApiTokenResponse, error)
 	UpdateApiToken(apiTokenId int, request *openapi.UpdateApiTokenRequest, updatedBy int32) (*openapi.UpdateApiTokenResponse, error)
+	// RotateApiToken issues the next version of the token, the previous version stays valid for the overlap period
+	RotateApiToken(apiTokenId int, request *openapi.RotateApiTokenRequest, rotatedBy int32) (*openapi.UpdateApiTokenResponse, error)
 	DeleteApiToken(apiTokenId int, deletedBy int32) (*openapi.ActionResponse, error)
 	GetAllApiTokensForWebhook(projectName string, environmentName string, appName string, auth func(token string, projectObject string, envObject string) bool) ([]*openapi.ApiToken, error)
 }
 
 type ApiTokenServiceImpl struct {
-	logger                *zap.SugaredLogger
-	apiTokenSecretService ApiTokenSecretService
-	userService           user2.UserService
-	userAuditService      user2.UserAuditService
-	apiTokenRepository    ApiTokenRepository
+	logger                     *zap.SugaredLogger
+	apiTokenSecretService      ApiTokenSecretService
+	userService                user2.UserService
+	userAuditService           user2.UserAuditService
+	apiTokenRepository         ApiTokenRepository
+	userPermissionAuditService user2.UserPermissionAuditService
 }
 
 func NewApiTokenServiceImpl(logger *zap.SugaredLogger, apiTokenSecretService ApiTokenSecretService, userService user2.UserService, userAuditService user2.UserAuditService,
-	apiTokenRepository ApiTokenRepository) *ApiTokenServiceImpl {
+	apiTokenRepository ApiTokenRepository, userPermissionAuditService user2.UserPermissionAuditService) *ApiTokenServiceImpl {
 	return &ApiTokenServiceImpl{
-		logger:                logger,
-		apiTokenSecretService: apiTokenSecretService,
-		userService:           userService,
-		userAuditService:      userAuditService,
-		apiTokenRepository:    apiTokenRepository,
+		logger:                     logger,
+		apiTokenSecretService:      apiTokenSecretService,
+		userService:                userService,
+		userAuditService:           userAuditService,
+		apiTokenRepository:         apiTokenRepository,
+		userPermissionAuditService: userPermissionAuditService,
 	}
 }
 
@@ -143,6 +153,10 @@ func (impl ApiTokenServiceImpl) GetAllActiveApiTokens() ([]*openapi.ApiToken, er
 			Token:          &apiTokenFromDb.Token,
 			UpdatedAt:      &updatedAtStr,
 		}
+		err = impl.setScopeAndVersionDetails(apiToken, apiTokenFromDb)
+		if err != nil {
+			return nil, err
+		}
 		if latestAuditLog != nil {
 			lastUsedAtStr := latestAuditLog.CreatedOn.String()
 			apiToken.LastUsedAt = &lastUsedAtStr
@@ -163,6 +177,19 @@ func (impl ApiTokenServiceImpl) CreateApiToken(request *openapi.CreateApiTokenRe
 		return nil, errors.New(fmt.Sprintf("name '%s' contains either white-space or comma, which is not allowed", name))
 	}
 
+	scope, err := buildApiTokenScope(request.Scope)
+	if err != nil {
+		return nil, err
+	}
+	err = userHelper.ValidateAllowedIps(request.AllowedIps)
+	if err != nil {
+		return nil, err
+	}
+	scopeJson, err := getScopeJson(scope)
+	if err != nil {
+		return nil, err
+	}
+
 	// step-1 - check if the name exists, if exists with active user - throw error
 	apiToken, err := impl.apiTokenRepository.FindByName(name)
 	if err != nil && err != pg.ErrNoRows {
@@ -193,7 +220,7 @@ func (impl ApiTokenServiceImpl) CreateApiToken(request *openapi.CreateApiTokenRe
 	}
 
 	// step-3 - Build token
-	token, err := impl.createApiJwtToken(email, tokenVersion, *request.ExpireAtInMs)
+	token, err := impl.createApiJwtToken(email, tokenVersion, *request.ExpireAtInMs, scope, request.AllowedIps)
 	if err != nil {
 		return nil, err
 	}
@@ -223,6 +250,8 @@ func (impl ApiTokenServiceImpl) CreateApiToken(request *openapi.CreateApiTokenRe
 		ExpireAtInMs: *request.ExpireAtInMs,
 		Token:        token,
 		Version:      tokenVersion,
+		Scope:        scopeJson,
+		AllowedIps:   request.AllowedIps,
 		AuditLog:     sql.AuditLog{UpdatedOn: time.Now()},
 	}
 	if apiTokenExists {
@@ -255,6 +284,7 @@ func (impl ApiTokenServiceImpl) CreateApiToken(request *openapi.CreateApiTokenRe
 		}
 		return nil, err
 	}
+	impl.saveAudit(apiTokenSaveRequest, userBean.PermissionAuditActionCreate, createdBy, nil)
 
 	success := true
 	return &openapi.CreateApiTokenResponse{
@@ -278,18 +308,24 @@ func (impl ApiTokenServiceImpl) UpdateApiToken(apiTokenId int, request *openapi.
 		return nil, errors.New(fmt.Sprintf("api-token corresponds to apiTokenId '%d' is not found", apiTokenId))
 	}
 
+	previousAuditState := adapter.BuildPermissionAuditStateForApiToken(apiToken.Name, apiToken.Description, apiToken.ExpireAtInMs)
 	previousTokenVersion := apiToken.Version
 	tokenVersion := apiToken.Version + 1
 
 	// step-2 - If expires_at is not same, then token needs to be generated again
 	if *request.ExpireAtInMs != apiToken.ExpireAtInMs {
-		// regenerate token
-		token, err := impl.createApiJwtToken(apiToken.User.EmailId, tokenVersion, *request.ExpireAtInMs)
+		// regenerate token, the previous version is not accepted anymore unlike in rotation
+		scope, err := getScopeFromJson(apiToken.Scope)
+		if err != nil {
+			return nil, err
+		}
+		token, err := impl.createApiJwtToken(apiToken.User.EmailId, tokenVersion, *request.ExpireAtInMs, scope, apiToken.AllowedIps)
 		if err != nil {
 			return nil, err
 		}
 		apiToken.Token = token
 		apiToken.Version = tokenVersion
+		apiToken.PreviousVersionExpireAtInMs = 0
 	}
 
 	// step-3 - update in DB
@@ -304,6 +340,68 @@ func (impl ApiTokenServiceImpl) UpdateApiToken(apiTokenId int, request *openapi.
 		impl.logger.Errorw("error while updating api-token", "apiTokenId", apiTokenId, "error", err)
 		return nil, err
 	}
+	impl.saveAudit(apiToken, userBean.PermissionAuditActionUpdate, updatedBy, previousAuditState)
+
+	success := true
+	return &openapi.UpdateApiTokenResponse{
+		Success: &success,
+		Token:   &apiToken.Token,
+	}, nil
+}
+
+func (impl ApiTokenServiceImpl) RotateApiToken(apiTokenId int, request *openapi.RotateApiTokenRequest, rotatedBy int32) (*openapi.UpdateApiTokenResponse, error) {
+	impl.logger.Infow("Rotating API token", "request", request, "rotatedBy", rotatedBy, "apiTokenId", apiTokenId)
+
+	overlapPeriodInMs := request.GetOverlapPeriodInMs()
+	if overlapPeriodInMs < 0 || overlapPeriodInMs > MaxRotationOverlapInMs {
+		return nil, util.NewApiError(http.StatusBadRequest, fmt.Sprintf("overlap period must be between 0 and %d ms", MaxRotationOverlapInMs), "invalid overlap period")
+	}
+	apiToken, err := impl.apiTokenRepository.FindActiveById(apiTokenId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error while getting api token by id", "apiTokenId", apiTokenId, "error", err)
+		return nil, err
+	}
+	if apiToken == nil || apiToken.Id == 0 {
+		return nil, errors.New(fmt.Sprintf("api-token corresponds to apiTokenId '%d' is not found", apiTokenId))
+	}
+	now := time.Now()
+	expireAtInMs := apiToken.ExpireAtInMs
+	if request.HasExpireAtInMs() {
+		expireAtInMs = request.GetExpireAtInMs()
+	}
+	if expireAtInMs > 0 && expireAtInMs <= now.UnixMilli() {
+		return nil, util.NewApiError(http.StatusBadRequest, "expiration time of the rotated token must be in future", "expiration time in past")
+	}
+	scope, err := getScopeFromJson(apiToken.Scope)
+	if err != nil {
+		return nil, err
+	}
+
+	previousAuditState := adapter.BuildPermissionAuditStateForApiToken(apiToken.Name, apiToken.Description, apiToken.ExpireAtInMs)
+	previousTokenVersion := apiToken.Version
+	token, err := impl.createApiJwtToken(apiToken.User.EmailId, previousTokenVersion+1, expireAtInMs, scope, apiToken.AllowedIps)
+	if err != nil {
+		return nil, err
+	}
+	apiToken.Token = token
+	apiToken.Version = previousTokenVersion + 1
+	apiToken.ExpireAtInMs = expireAtInMs
+	apiToken.PreviousVersion = previousTokenVersion
+	apiToken.PreviousVersionExpireAtInMs = 0
+	if overlapPeriodInMs > 0 {
+		apiToken.PreviousVersionExpireAtInMs = now.UnixMilli() + overlapPeriodInMs
+	}
+	apiToken.UpdatedBy = rotatedBy
+	apiToken.UpdatedOn = now
+	err = impl.apiTokenRepository.UpdateIf(apiToken, previousTokenVersion)
+	if err != nil {
+		impl.logger.Errorw("error while rotating api-token", "apiTokenId", apiTokenId, "error", err)
+		if errors.Is(err, fmt.Errorf(TokenVersionMismatch)) {
+			return nil, fmt.Errorf(ConcurrentTokenUpdateRequest)
+		}
+		return nil, err
+	}
+	impl.saveAudit(apiToken, userBean.PermissionAuditActionUpdate, rotatedBy, previousAuditState)
 
 	success := true
 	return &openapi.UpdateApiTokenResponse{
@@ -345,6 +443,7 @@ func (impl ApiTokenServiceImpl) DeleteApiToken(apiTokenId int, deletedBy int32)
 	if !success {
 		return nil, errors.New(fmt.Sprintf("Couldn't in-activate user corresponds to apiTokenId '%d'", apiTokenId))
 	}
+	impl.saveAudit(apiToken, userBean.PermissionAuditActionDelete, deletedBy, nil)
 
 	return &openapi.ActionResponse{
 		Success: &success,
@@ -352,15 +451,38 @@ func (impl ApiTokenServiceImpl) DeleteApiToken(apiTokenId int, deletedBy int32)
 
 }
 
-func (impl ApiTokenServiceImpl) createApiJwtToken(email string, tokenVersion int, expireAtInMs int64) (string, error) {
+// saveAudit only logs failures, the token change is already saved by then. delete audits carry no current state
+func (impl ApiTokenServiceImpl) saveAudit(apiToken *ApiToken, action userBean.PermissionAuditAction, actorId int32, previousAuditState *userBean.PermissionAuditState) {
+	audit := &userBean.PermissionAuditDto{
+		EntityType:    userBean.PermissionAuditEntityApiToken,
+		EntityId:      int32(apiToken.Id),
+		EntityName:    apiToken.Name,
+		Action:        action,
+		ActorId:       actorId,
+		PreviousState: previousAuditState,
+	}
+	if action == userBean.PermissionAuditActionDelete {
+		audit.PreviousState = adapter.BuildPermissionAuditStateForApiToken(apiToken.Name, apiToken.Description, apiToken.ExpireAtInMs)
+	} else {
+		audit.CurrentState = adapter.BuildPermissionAuditStateForApiToken(apiToken.Name, apiToken.Description, apiToken.ExpireAtInMs)
+	}
+	err := impl.userPermissionAuditService.SaveAudit(nil, audit)
+	if err != nil {
+		impl.logger.Errorw("error in saving permission audit for api token", "apiTokenId", apiToken.Id, "action", action, "err", err)
+	}
+}
+
+func (impl ApiTokenServiceImpl) createApiJwtToken(email string, tokenVersion int, expireAtInMs int64, scope *casbinBean.ApiTokenScope, allowedIps []string) (string, error) {
 	registeredClaims, secretByteArr, err := impl.setRegisteredClaims(expireAtInMs)
 	if err != nil {
 		return "", err
 	}
 	claims := &ApiTokenCustomClaims{
-		email,
-		strconv.Itoa(tokenVersion),
-		registeredClaims,
+		Email:            email,
+		Version:          strconv.Itoa(tokenVersion),
+		Scope:            scope,
+		AllowedIps:       allowedIps,
+		RegisteredClaims: registeredClaims,
 	}
 	token, err := impl.generateToken(claims, secretByteArr)
 	if err != nil {
@@ -395,3 +517,88 @@ func (impl ApiTokenServiceImpl) generateToken(claims *ApiTokenCustomClaims, secr
 	}
 	return token, nil
 }
+
+func (impl ApiTokenServiceImpl) setScopeAndVersionDetails(apiToken *openapi.ApiToken, apiTokenFromDb *ApiToken) error {
+	scope, err := getScopeFromJson(apiTokenFromDb.Scope)
+	if err != nil {
+		impl.logger.Errorw("error in reading scope of api token", "apiTokenId", apiTokenFromDb.Id, "err", err)
+		return err
+	}
+	if scope != nil {
+		scopeType := string(scope.Type)
+		apiToken.Scope = &openapi.ApiTokenScope{Type: &scopeType}
+		for _, permission := range scope.Permissions {
+			apiToken.Scope.Permissions = append(apiToken.Scope.Permissions, openapi.ApiTokenScopePermission{
+				Resource: &permission.Resource,
+				Action:   &permission.Action,
+				Object:   &permission.Object,
+			})
+		}
+	}
+	apiToken.AllowedIps = apiTokenFromDb.AllowedIps
+	version := int32(apiTokenFromDb.Version)
+	apiToken.Version = &version
+	if apiTokenFromDb.PreviousVersionExpireAtInMs > time.Now().UnixMilli() {
+		apiToken.PreviousVersionExpireAtInMs = &apiTokenFromDb.PreviousVersionExpireAtInMs
+	}
+	if apiTokenFromDb.LastUsedAt != nil {
+		lastUsedVersion := int32(apiTokenFromDb.LastUsedVersion)
+		apiToken.LastUsedVersion = &lastUsedVersion
+	}
+	return nil
+}
+
+// buildApiTokenScope validates the requested scope, readOnly and webhook scopes are fixed while a custom scope needs
+// at least one permission
+func buildApiTokenScope(request *openapi.ApiTokenScope) (*casbinBean.ApiTokenScope, error) {
+	if request == nil {
+		return nil, nil
+	}
+	scope := &casbinBean.ApiTokenScope{Type: casbinBean.ApiTokenScopeType(request.GetType())}
+	switch scope.Type {
+	case casbinBean.ApiTokenScopeTypeReadOnly, casbinBean.ApiTokenScopeTypeWebhook:
+		if len(request.Permissions) > 0 {
+			return nil, util.NewApiError(http.StatusBadRequest, fmt.Sprintf("permissions can not be set for %s scope", scope.Type), "permissions with fixed scope")
+		}
+	case casbinBean.ApiTokenScopeTypeCustom:
+		if len(request.Permissions) == 0 {
+			return nil, util.NewApiError(http.StatusBadRequest, "custom scope needs at least one permission", "custom scope without permissions")
+		}
+		for _, permission := range request.Permissions {
+			if len(permission.GetResource()) == 0 || len(permission.GetAction()) == 0 || len(permission.GetObject()) == 0 {
+				return nil, util.NewApiError(http.StatusBadRequest, "resource, action and object are required in scope permissions", "incomplete scope permission")
+			}
+			scope.Permissions = append(scope.Permissions, &casbinBean.ApiTokenScopePermission{
+				Resource: permission.GetResource(),
+				Action:   permission.GetAction(),
+				Object:   strings.ToLower(permission.GetObject()),
+			})
+		}
+	default:
+		return nil, util.NewApiError(http.StatusBadRequest, fmt.Sprintf("invalid api token scope '%s'", scope.Type), "invalid api token scope")
+	}
+	return scope, nil
+}
+
+func getScopeJson(scope *casbinBean.ApiTokenScope) (string, error) {
+	if scope == nil {
+		return "", nil
+	}
+	scopeJson, err := json.Marshal(scope)
+	if err != nil {
+		return "", err
+	}
+	return string(scopeJson), nil
+}
+
+func getScopeFromJson(scopeJson string) (*casbinBean.ApiTokenScope, error) {
+	if len(scopeJson) == 0 {
+		return nil, nil
+	}
+	scope := &casbinBean.ApiTokenScope{}
+	err := json.Unmarshal([]byte(scopeJson), scope)
+	if err != nil {
+		return nil, err
+	}
+	return scope, nil
+}
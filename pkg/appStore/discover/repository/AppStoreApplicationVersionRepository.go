@@ -32,6 +32,8 @@ type AppStoreApplicationVersionRepository interface {
 	FindById(id int) (*AppStoreApplicationVersion, error)
 	FindVersionsByAppStoreId(id int) ([]*AppStoreApplicationVersion, error)
 	FindChartVersionByAppStoreId(id int) ([]*AppStoreApplicationVersion, error)
+	// FindActiveVersionsByAppStoreIds returns the non-deprecated versions (without values and chart contents) of the given app stores
+	FindActiveVersionsByAppStoreIds(appStoreIds []int) ([]*AppStoreApplicationVersion, error)
 	FindByIds(ids []int) ([]*AppStoreApplicationVersion, error)
 	GetChartInfoById(id int) (*AppStoreApplicationVersion, error)
 	FindLatestVersionByAppStoreIdForChartRepo(id int) (int, error)
@@ -255,6 +257,22 @@ func (impl AppStoreApplicationVersionRepositoryImpl) FindVersionsByAppStoreId(id
 	return appStoreApplicationVersions, err
 }
 
+func (impl AppStoreApplicationVersionRepositoryImpl) FindActiveVersionsByAppStoreIds(appStoreIds []int) ([]*AppStoreApplicationVersion, error) {
+	var appStoreApplicationVersions []*AppStoreApplicationVersion
+	if len(appStoreIds) == 0 {
+		return appStoreApplicationVersions, nil
+	}
+	err := impl.dbConnection.
+		Model(&appStoreApplicationVersions).
+		Column("app_store_application_version.id", "app_store_application_version.version",
+			"app_store_application_version.app_store_id", "app_store_application_version.created").
+		Where("app_store_id in (?)", pg.In(appStoreIds)).
+		Where("deprecated = ?", false).
+		Order("created DESC").
+		Select()
+	return appStoreApplicationVersions, err
+}
+
 func (impl *AppStoreApplicationVersionRepositoryImpl) FindLatestVersionByAppStoreIdForChartRepo(id int) (int, error) {
 	var appStoreApplicationVersionId int
 	queryTemp := "SELECT asv.id AS app_store_application_version_id  FROM app_store_application_version AS asv  JOIN app_store AS ap ON asv.app_store_id = ap.id WHERE ap.id = ? order by created desc limit 1;"
@@ -141,6 +141,8 @@ type InstalledAppRepository interface {
 	GetInstalledAppVersionByAppIdAndEnvId(appId int, envId int) (*InstalledAppVersions, error)
 	GetInstalledAppVersionByClusterIds(clusterIds []int) ([]*InstalledAppVersions, error) //unused
 	GetInstalledAppVersionByClusterIdsV2(clusterIds []int) ([]*InstalledAppVersions, error)
+	// GetAllActiveInstalledAppVersions returns the active installed app version of every active installed app along with app, env and chart version details
+	GetAllActiveInstalledAppVersions() ([]*InstalledAppVersions, error)
 	GetInstalledApplicationByClusterIdAndNamespaceAndAppName(clusterId int, namespace string, appName string) (*InstalledApps, error)
 	GetInstalledApplicationByClusterIdAndNamespaceAndAppIdentifier(clusterId int, namespace string, appIdentifier string, appName string) (*InstalledApps, error)
 	GetAppAndEnvDetailsForDeploymentAppTypeInstalledApps(deploymentAppType string, clusterIds []int) ([]*InstalledApps, error)
@@ -677,6 +679,18 @@ func (impl *InstalledAppRepositoryImpl) GetInstalledAppVersionByClusterIds(clust
 	return installedAppVersions, err
 }
 
+func (impl *InstalledAppRepositoryImpl) GetAllActiveInstalledAppVersions() ([]*InstalledAppVersions, error) {
+	var installedAppVersions []*InstalledAppVersions
+	err := impl.dbConnection.
+		Model(&installedAppVersions).
+		Column("installed_app_versions.*", "InstalledApp", "InstalledApp.App", "InstalledApp.Environment", "AppStoreApplicationVersion").
+		Join("inner join installed_apps ia on ia.id = installed_app_versions.installed_app_id").
+		Where("ia.active = true").Where("installed_app_versions.active = true").
+		Order("installed_app_versions.id desc").
+		Select()
+	return installedAppVersions, err
+}
+
 func (impl *InstalledAppRepositoryImpl) GetInstalledAppVersionByClusterIdsV2(clusterIds []int) ([]*InstalledAppVersions, error) {
 	var installedAppVersions []*InstalledAppVersions
 	err := impl.dbConnection.
@@ -128,7 +128,7 @@ func (impl *EAModeDeploymentServiceImpl) InstallApp(installAppVersionRequest *ap
 			AwsRegion:           appStoreAppVersion.AppStore.DockerArtifactStore.AWSRegion,
 			AccessKey:           appStoreAppVersion.AppStore.DockerArtifactStore.AWSAccessKeyId,
 			SecretKey:           appStoreAppVersion.AppStore.DockerArtifactStore.AWSSecretAccessKey,
-			RegistryType:        string(appStoreAppVersion.AppStore.DockerArtifactStore.RegistryType),
+			RegistryType:        appStoreAppVersion.AppStore.DockerArtifactStore.RegistryType.GetClientCompatibleType(),
 			RepoName:            appStoreAppVersion.AppStore.Name,
 			IsPublic:            ociRegistryConfig.IsPublic,
 			Connection:          appStoreAppVersion.AppStore.DockerArtifactStore.Connection,
@@ -360,7 +360,7 @@ func (impl *EAModeDeploymentServiceImpl) updateApplicationWithChartInfo(ctx cont
 			AwsRegion:           appStoreApplicationVersion.AppStore.DockerArtifactStore.AWSRegion,
 			AccessKey:           appStoreApplicationVersion.AppStore.DockerArtifactStore.AWSAccessKeyId,
 			SecretKey:           appStoreApplicationVersion.AppStore.DockerArtifactStore.AWSSecretAccessKey,
-			RegistryType:        string(appStoreApplicationVersion.AppStore.DockerArtifactStore.RegistryType),
+			RegistryType:        appStoreApplicationVersion.AppStore.DockerArtifactStore.RegistryType.GetClientCompatibleType(),
 			RepoName:            appStoreApplicationVersion.AppStore.Name,
 			IsPublic:            ociRegistryConfig.IsPublic,
 			Connection:          appStoreApplicationVersion.AppStore.DockerArtifactStore.Connection,
@@ -128,7 +128,7 @@ func (impl *InstalledAppResourceServiceImpl) findNotesForArgoApplication(install
 				AwsRegion:           appStoreAppVersion.AppStore.DockerArtifactStore.AWSRegion,
 				AccessKey:           appStoreAppVersion.AppStore.DockerArtifactStore.AWSAccessKeyId,
 				SecretKey:           appStoreAppVersion.AppStore.DockerArtifactStore.AWSSecretAccessKey,
-				RegistryType:        string(appStoreAppVersion.AppStore.DockerArtifactStore.RegistryType),
+				RegistryType:        appStoreAppVersion.AppStore.DockerArtifactStore.RegistryType.GetClientCompatibleType(),
 				RepoName:            appStoreAppVersion.AppStore.Name,
 				IsPublic:            ociRegistryConfig.IsPublic,
 				Connection:          appStoreAppVersion.AppStore.DockerArtifactStore.Connection,
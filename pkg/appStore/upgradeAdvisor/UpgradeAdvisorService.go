@@ -0,0 +1,551 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package upgradeAdvisor
+
+import (
+	"context"
+	"fmt"
+	"github.com/caarlos0/env/v6"
+	openapi2 "github.com/devtron-labs/devtron/api/openapi/openapiClient"
+	"github.com/devtron-labs/devtron/internal/util"
+	appStoreBean "github.com/devtron-labs/devtron/pkg/appStore/bean"
+	appStoreDiscoverRepository "github.com/devtron-labs/devtron/pkg/appStore/discover/repository"
+	appStoreRepository "github.com/devtron-labs/devtron/pkg/appStore/installedApp/repository"
+	"github.com/devtron-labs/devtron/pkg/appStore/installedApp/service"
+	"github.com/devtron-labs/devtron/pkg/appStore/upgradeAdvisor/bean"
+	"github.com/devtron-labs/devtron/pkg/appStore/upgradeAdvisor/repository"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	"github.com/go-pg/pg"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type UpgradeAdvisorService interface {
+	// GetPendingUpgrades lists every chart store installation for which a newer chart version has been synced, along with the values diff against the latest version
+	GetPendingUpgrades() ([]*bean.PendingUpgradeDto, error)
+	// GetPendingUpgrade returns the upgrades available for an installation with the values diff against targetVersionId, or the latest version if targetVersionId is 0
+	GetPendingUpgrade(installedAppId int, targetVersionId int) (*bean.PendingUpgradeDto, error)
+	SavePolicy(request *bean.UpgradePolicyDto) (*bean.UpgradePolicyDto, error)
+	DeletePolicy(installedAppId int, userId int32) error
+	GetPolicy(installedAppId int) (*bean.UpgradePolicyDto, error)
+	GetUpgradeRuns(installedAppId int) ([]*bean.UpgradeRunDto, error)
+	// ProcessAutoUpgrades health checks in-flight auto upgrades, rolling back unhealthy ones, and then applies eligible upgrades as per the configured policies
+	ProcessAutoUpgrades()
+}
+
+type UpgradeAdvisorServiceImpl struct {
+	logger                               *zap.SugaredLogger
+	upgradeAdvisorRepository             repository.UpgradeAdvisorRepository
+	installedAppRepository               appStoreRepository.InstalledAppRepository
+	installedAppVersionHistoryRepository appStoreRepository.InstalledAppVersionHistoryRepository
+	appStoreApplicationVersionRepository appStoreDiscoverRepository.AppStoreApplicationVersionRepository
+	appStoreDeploymentService            service.AppStoreDeploymentService
+	appStoreDeploymentDBService          service.AppStoreDeploymentDBService
+	config                               *bean.UpgradeAdvisorConfig
+	upgradeCron                          *cron.Cron
+}
+
+func GetUpgradeAdvisorConfig() (*bean.UpgradeAdvisorConfig, error) {
+	config := &bean.UpgradeAdvisorConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		return nil, err
+	}
+	return config, err
+}
+
+func NewUpgradeAdvisorServiceImpl(logger *zap.SugaredLogger,
+	upgradeAdvisorRepository repository.UpgradeAdvisorRepository,
+	installedAppRepository appStoreRepository.InstalledAppRepository,
+	installedAppVersionHistoryRepository appStoreRepository.InstalledAppVersionHistoryRepository,
+	appStoreApplicationVersionRepository appStoreDiscoverRepository.AppStoreApplicationVersionRepository,
+	appStoreDeploymentService service.AppStoreDeploymentService,
+	appStoreDeploymentDBService service.AppStoreDeploymentDBService,
+	cronLogger *cron2.CronLoggerImpl) (*UpgradeAdvisorServiceImpl, error) {
+	config, err := GetUpgradeAdvisorConfig()
+	if err != nil {
+		logger.Errorw("error in parsing upgrade advisor config", "err", err)
+		return nil, err
+	}
+	upgradeCron := cron.New(cron.WithChain(cron.SkipIfStillRunning(cronLogger), cron.Recover(cronLogger)))
+	impl := &UpgradeAdvisorServiceImpl{
+		logger:                               logger,
+		upgradeAdvisorRepository:             upgradeAdvisorRepository,
+		installedAppRepository:               installedAppRepository,
+		installedAppVersionHistoryRepository: installedAppVersionHistoryRepository,
+		appStoreApplicationVersionRepository: appStoreApplicationVersionRepository,
+		appStoreDeploymentService:            appStoreDeploymentService,
+		appStoreDeploymentDBService:          appStoreDeploymentDBService,
+		config:                               config,
+		upgradeCron:                          upgradeCron,
+	}
+	upgradeCron.Start()
+	_, err = upgradeCron.AddFunc(fmt.Sprintf("@every %ds", config.CronIntervalSecs), impl.ProcessAutoUpgrades)
+	if err != nil {
+		logger.Errorw("error in starting chart auto upgrade cron", "err", err)
+		return nil, err
+	}
+	return impl, nil
+}
+
+func (impl *UpgradeAdvisorServiceImpl) GetPendingUpgrades() ([]*bean.PendingUpgradeDto, error) {
+	installedAppVersions, err := impl.installedAppRepository.GetAllActiveInstalledAppVersions()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching active installed app versions", "err", err)
+		return nil, err
+	}
+	appStoreIds := make([]int, 0)
+	for _, installedAppVersion := range installedAppVersions {
+		appStoreIds = append(appStoreIds, installedAppVersion.AppStoreApplicationVersion.AppStoreId)
+	}
+	versionsByAppStoreId, err := impl.getActiveVersionsByAppStoreId(appStoreIds)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := impl.upgradeAdvisorRepository.FindAllActivePolicies()
+	if err != nil {
+		impl.logger.Errorw("error in fetching upgrade policies", "err", err)
+		return nil, err
+	}
+	policyByInstalledAppId := make(map[int]*repository.InstalledAppUpgradePolicy, len(policies))
+	for _, policy := range policies {
+		policyByInstalledAppId[policy.InstalledAppId] = policy
+	}
+	pendingUpgrades := make([]*bean.PendingUpgradeDto, 0)
+	for _, installedAppVersion := range installedAppVersions {
+		pendingUpgrade, err := impl.buildPendingUpgrade(installedAppVersion, versionsByAppStoreId[installedAppVersion.AppStoreApplicationVersion.AppStoreId], policyByInstalledAppId[installedAppVersion.InstalledAppId])
+		if err != nil {
+			// a chart with non semver versions should not block the listing for other installations
+			impl.logger.Warnw("skipping installation in upgrade listing", "installedAppId", installedAppVersion.InstalledAppId, "err", err)
+			continue
+		}
+		if pendingUpgrade != nil {
+			pendingUpgrades = append(pendingUpgrades, pendingUpgrade)
+		}
+	}
+	err = impl.updateValuesDiff(pendingUpgrades, installedAppVersions)
+	if err != nil {
+		return nil, err
+	}
+	return pendingUpgrades, nil
+}
+
+func (impl *UpgradeAdvisorServiceImpl) GetPendingUpgrade(installedAppId int, targetVersionId int) (*bean.PendingUpgradeDto, error) {
+	installedAppVersion, err := impl.installedAppRepository.GetActiveInstalledAppVersionByInstalledAppId(installedAppId)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, "installed app not found", "installed app not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching active installed app version", "installedAppId", installedAppId, "err", err)
+		return nil, err
+	}
+	appStoreId := installedAppVersion.AppStoreApplicationVersion.AppStoreId
+	versionsByAppStoreId, err := impl.getActiveVersionsByAppStoreId([]int{appStoreId})
+	if err != nil {
+		return nil, err
+	}
+	policy, err := impl.upgradeAdvisorRepository.FindActivePolicyByInstalledAppId(installedAppId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching upgrade policy", "installedAppId", installedAppId, "err", err)
+		return nil, err
+	}
+	pendingUpgrade, err := impl.buildPendingUpgrade(installedAppVersion, versionsByAppStoreId[appStoreId], policy)
+	if err != nil {
+		return nil, util.NewApiError(http.StatusUnprocessableEntity, err.Error(), err.Error())
+	}
+	if pendingUpgrade == nil {
+		return nil, util.NewApiError(http.StatusNotFound, "no upgrade available for the installed chart version", "no upgrade available")
+	}
+	if targetVersionId > 0 {
+		targetVersion := findChartVersion(pendingUpgrade.AvailableVersions, targetVersionId)
+		if targetVersion == nil {
+			return nil, util.NewApiError(http.StatusBadRequest, "target version is not an upgrade of the installed chart version", "invalid target version")
+		}
+		pendingUpgrade.TargetVersion = targetVersion
+	}
+	err = impl.updateValuesDiff([]*bean.PendingUpgradeDto{pendingUpgrade}, []*appStoreRepository.InstalledAppVersions{installedAppVersion})
+	if err != nil {
+		return nil, err
+	}
+	return pendingUpgrade, nil
+}
+
+func (impl *UpgradeAdvisorServiceImpl) SavePolicy(request *bean.UpgradePolicyDto) (*bean.UpgradePolicyDto, error) {
+	if err := ValidateUpgradePolicy(request); err != nil {
+		return nil, err
+	}
+	policy, err := impl.upgradeAdvisorRepository.FindActivePolicyByInstalledAppId(request.InstalledAppId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching upgrade policy", "installedAppId", request.InstalledAppId, "err", err)
+		return nil, err
+	}
+	if policy.Id > 0 {
+		policy.PolicyType = string(request.PolicyType)
+		policy.VersionConstraint = request.VersionConstraint
+		policy.UpdateAuditLog(request.UserId)
+		err = impl.upgradeAdvisorRepository.UpdatePolicy(policy)
+	} else {
+		policy = &repository.InstalledAppUpgradePolicy{
+			InstalledAppId:    request.InstalledAppId,
+			PolicyType:        string(request.PolicyType),
+			VersionConstraint: request.VersionConstraint,
+			Active:            true,
+		}
+		policy.CreateAuditLog(request.UserId)
+		err = impl.upgradeAdvisorRepository.SavePolicy(policy)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving upgrade policy", "policy", policy, "err", err)
+		return nil, err
+	}
+	request.Id = policy.Id
+	return request, nil
+}
+
+func (impl *UpgradeAdvisorServiceImpl) DeletePolicy(installedAppId int, userId int32) error {
+	policy, err := impl.upgradeAdvisorRepository.FindActivePolicyByInstalledAppId(installedAppId)
+	if err == pg.ErrNoRows {
+		return util.NewApiError(http.StatusNotFound, "no upgrade policy configured for the installed app", "upgrade policy not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching upgrade policy", "installedAppId", installedAppId, "err", err)
+		return err
+	}
+	policy.Active = false
+	policy.UpdateAuditLog(userId)
+	err = impl.upgradeAdvisorRepository.UpdatePolicy(policy)
+	if err != nil {
+		impl.logger.Errorw("error in deleting upgrade policy", "installedAppId", installedAppId, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *UpgradeAdvisorServiceImpl) GetPolicy(installedAppId int) (*bean.UpgradePolicyDto, error) {
+	policy, err := impl.upgradeAdvisorRepository.FindActivePolicyByInstalledAppId(installedAppId)
+	if err == pg.ErrNoRows {
+		return nil, util.NewApiError(http.StatusNotFound, "no upgrade policy configured for the installed app", "upgrade policy not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching upgrade policy", "installedAppId", installedAppId, "err", err)
+		return nil, err
+	}
+	return getPolicyDto(policy), nil
+}
+
+func (impl *UpgradeAdvisorServiceImpl) GetUpgradeRuns(installedAppId int) ([]*bean.UpgradeRunDto, error) {
+	runs, err := impl.upgradeAdvisorRepository.FindRunsByInstalledAppId(installedAppId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching upgrade runs", "installedAppId", installedAppId, "err", err)
+		return nil, err
+	}
+	versionIds := make([]int, 0, 2*len(runs))
+	for _, run := range runs {
+		versionIds = append(versionIds, run.FromAppStoreApplicationVersionId, run.ToAppStoreApplicationVersionId)
+	}
+	versionNameById := make(map[int]string)
+	if len(versionIds) > 0 {
+		versions, err := impl.appStoreApplicationVersionRepository.FindByIds(versionIds)
+		if err != nil {
+			impl.logger.Errorw("error in fetching chart versions", "ids", versionIds, "err", err)
+			return nil, err
+		}
+		for _, version := range versions {
+			versionNameById[version.Id] = version.Version
+		}
+	}
+	runDtos := make([]*bean.UpgradeRunDto, 0, len(runs))
+	for _, run := range runs {
+		runDtos = append(runDtos, &bean.UpgradeRunDto{
+			Id:                       run.Id,
+			InstalledAppId:           run.InstalledAppId,
+			FromVersion:              versionNameById[run.FromAppStoreApplicationVersionId],
+			ToVersion:                versionNameById[run.ToAppStoreApplicationVersionId],
+			PreviousVersionHistoryId: run.PreviousVersionHistoryId,
+			VersionHistoryId:         run.VersionHistoryId,
+			Status:                   bean.UpgradeRunStatus(run.Status),
+			Message:                  run.Message,
+			StartedOn:                run.CreatedOn,
+			FinishedOn:               run.FinishedOn,
+		})
+	}
+	return runDtos, nil
+}
+
+func (impl *UpgradeAdvisorServiceImpl) ProcessAutoUpgrades() {
+	progressingRuns, err := impl.upgradeAdvisorRepository.FindRunsByStatus(string(bean.UpgradeRunStatusProgressing))
+	if err != nil {
+		impl.logger.Errorw("error in fetching in-flight chart upgrades", "err", err)
+		return
+	}
+	upgradingInstalledAppIds := make(map[int]bool)
+	for _, run := range progressingRuns {
+		if !impl.checkUpgradeHealth(run) {
+			upgradingInstalledAppIds[run.InstalledAppId] = true
+		}
+	}
+	policies, err := impl.upgradeAdvisorRepository.FindAllActivePolicies()
+	if err != nil {
+		impl.logger.Errorw("error in fetching upgrade policies", "err", err)
+		return
+	}
+	for _, policy := range policies {
+		if upgradingInstalledAppIds[policy.InstalledAppId] {
+			continue
+		}
+		err = impl.applyUpgradePolicy(policy)
+		if err != nil {
+			impl.logger.Errorw("error in applying chart upgrade policy", "installedAppId", policy.InstalledAppId, "err", err)
+		}
+	}
+}
+
+// checkUpgradeHealth moves an in-flight upgrade run to a terminal status once its deployment settles,
+// rolling back to the previous deployment if it fails or does not become healthy in time. Returns true if the run is finished.
+func (impl *UpgradeAdvisorServiceImpl) checkUpgradeHealth(run *repository.InstalledAppUpgradeRun) bool {
+	history, err := impl.installedAppVersionHistoryRepository.GetInstalledAppVersionHistory(run.VersionHistoryId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment history of chart upgrade", "runId", run.Id, "versionHistoryId", run.VersionHistoryId, "err", err)
+		return false
+	}
+	if IsUpgradeHealthy(history.Status) {
+		impl.finishRun(run, bean.UpgradeRunStatusSucceeded, fmt.Sprintf("release is %s after upgrade", history.Status))
+		return true
+	}
+	var reason string
+	if IsUpgradeFailed(history.Status) {
+		reason = fmt.Sprintf("release is %s after upgrade", history.Status)
+	} else if time.Now().After(run.HealthCheckDeadline) {
+		reason = fmt.Sprintf("release did not become healthy in %d seconds, last status %q", impl.config.HealthCheckTimeoutSecs, history.Status)
+	} else {
+		return false
+	}
+	err = impl.rollbackUpgrade(run)
+	if err != nil {
+		impl.logger.Errorw("error in rolling back chart upgrade", "runId", run.Id, "installedAppId", run.InstalledAppId, "err", err)
+		impl.finishRun(run, bean.UpgradeRunStatusFailed, fmt.Sprintf("%s, rollback failed: %s", reason, err.Error()))
+		return true
+	}
+	impl.finishRun(run, bean.UpgradeRunStatusRolledBack, fmt.Sprintf("%s, rolled back to previous deployment", reason))
+	return true
+}
+
+func (impl *UpgradeAdvisorServiceImpl) rollbackUpgrade(run *repository.InstalledAppUpgradeRun) error {
+	installedApp, err := impl.appStoreDeploymentDBService.GetInstalledApp(run.InstalledAppId)
+	if err != nil {
+		return err
+	}
+	version := int32(run.PreviousVersionHistoryId)
+	_, err = impl.appStoreDeploymentService.RollbackApplication(context.Background(), &openapi2.RollbackReleaseRequest{Version: &version}, installedApp, userBean.SystemUserId)
+	return err
+}
+
+func (impl *UpgradeAdvisorServiceImpl) finishRun(run *repository.InstalledAppUpgradeRun, status bean.UpgradeRunStatus, message string) {
+	finishedOn := time.Now()
+	run.Status = string(status)
+	run.Message = message
+	run.FinishedOn = &finishedOn
+	run.UpdateAuditLog(userBean.SystemUserId)
+	err := impl.upgradeAdvisorRepository.UpdateRun(run)
+	if err != nil {
+		impl.logger.Errorw("error in updating chart upgrade run", "runId", run.Id, "status", status, "err", err)
+	}
+}
+
+func (impl *UpgradeAdvisorServiceImpl) applyUpgradePolicy(policy *repository.InstalledAppUpgradePolicy) error {
+	installedAppVersion, err := impl.installedAppRepository.GetActiveInstalledAppVersionByInstalledAppId(policy.InstalledAppId)
+	if util.IsErrNoRows(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	appStoreId := installedAppVersion.AppStoreApplicationVersion.AppStoreId
+	versionsByAppStoreId, err := impl.getActiveVersionsByAppStoreId([]int{appStoreId})
+	if err != nil {
+		return err
+	}
+	availableUpgrades, err := GetAvailableUpgrades(installedAppVersion.AppStoreApplicationVersion.Version, versionsByAppStoreId[appStoreId])
+	if err != nil {
+		return err
+	}
+	targetVersion, err := GetAutoUpgradeVersion(availableUpgrades, bean.UpgradePolicyType(policy.PolicyType), policy.VersionConstraint)
+	if err != nil || targetVersion == nil {
+		return err
+	}
+	// a version which was already attempted is not retried automatically, it would be rolled back again
+	attempted, err := impl.upgradeAdvisorRepository.IsRunPresentForTargetVersion(policy.InstalledAppId, targetVersion.Id)
+	if err != nil || attempted {
+		return err
+	}
+	previousHistory, err := impl.installedAppVersionHistoryRepository.GetLatestInstalledAppVersionHistoryByInstalledAppId(policy.InstalledAppId)
+	if err != nil {
+		return err
+	}
+	if !IsUpgradeHealthy(previousHistory.Status) {
+		// only a healthy release is upgraded, so that there is a known good deployment to roll back to
+		impl.logger.Debugw("skipping auto upgrade as current release is not healthy", "installedAppId", policy.InstalledAppId, "status", previousHistory.Status)
+		return nil
+	}
+	upgradeRequest, err := impl.appStoreDeploymentDBService.GetInstalledApp(policy.InstalledAppId)
+	if err != nil {
+		return err
+	}
+	upgradeRequest = upgradeRequest.NewInstalledAppVersionRequestDTO(userBean.SystemUserId, policy.InstalledAppId)
+	upgradeRequest.Id = installedAppVersion.Id
+	upgradeRequest.InstalledAppVersionId = installedAppVersion.Id
+	upgradeRequest.AppStoreVersion = targetVersion.Id
+	upgradeRequest.ValuesOverrideYaml = installedAppVersion.ValuesYaml
+	upgradeRequest.ReferenceValueKind = installedAppVersion.ReferenceValueKind
+	upgradeRequest.ReferenceValueId = installedAppVersion.ReferenceValueId
+	if installedAppVersion.ReferenceValueKind == appStoreBean.REFERENCE_TYPE_DEFAULT {
+		upgradeRequest.ReferenceValueId = targetVersion.Id
+	}
+	run := &repository.InstalledAppUpgradeRun{
+		InstalledAppId:                   policy.InstalledAppId,
+		PolicyId:                         policy.Id,
+		FromAppStoreApplicationVersionId: installedAppVersion.AppStoreApplicationVersionId,
+		ToAppStoreApplicationVersionId:   targetVersion.Id,
+		PreviousVersionHistoryId:         previousHistory.Id,
+		Status:                           string(bean.UpgradeRunStatusProgressing),
+		HealthCheckDeadline:              time.Now().Add(time.Duration(impl.config.HealthCheckTimeoutSecs) * time.Second),
+	}
+	run.CreateAuditLog(userBean.SystemUserId)
+	impl.logger.Infow("auto upgrading installed chart", "installedAppId", policy.InstalledAppId, "fromVersion", installedAppVersion.AppStoreApplicationVersion.Version, "toVersion", targetVersion.Version)
+	upgradeResponse, err := impl.appStoreDeploymentService.UpdateInstalledApp(context.Background(), upgradeRequest)
+	if err != nil {
+		finishedOn := time.Now()
+		run.Status = string(bean.UpgradeRunStatusFailed)
+		run.Message = fmt.Sprintf("upgrade failed: %s", err.Error())
+		run.FinishedOn = &finishedOn
+	} else {
+		run.VersionHistoryId = upgradeResponse.InstalledAppVersionHistoryId
+	}
+	if saveErr := impl.upgradeAdvisorRepository.SaveRun(run); saveErr != nil {
+		impl.logger.Errorw("error in saving chart upgrade run", "run", run, "err", saveErr)
+		return saveErr
+	}
+	return err
+}
+
+func (impl *UpgradeAdvisorServiceImpl) getActiveVersionsByAppStoreId(appStoreIds []int) (map[int][]*appStoreDiscoverRepository.AppStoreApplicationVersion, error) {
+	versions, err := impl.appStoreApplicationVersionRepository.FindActiveVersionsByAppStoreIds(appStoreIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching chart versions", "appStoreIds", appStoreIds, "err", err)
+		return nil, err
+	}
+	versionsByAppStoreId := make(map[int][]*appStoreDiscoverRepository.AppStoreApplicationVersion)
+	for _, version := range versions {
+		versionsByAppStoreId[version.AppStoreId] = append(versionsByAppStoreId[version.AppStoreId], version)
+	}
+	return versionsByAppStoreId, nil
+}
+
+// buildPendingUpgrade returns nil if no newer chart version is available for the installation
+func (impl *UpgradeAdvisorServiceImpl) buildPendingUpgrade(installedAppVersion *appStoreRepository.InstalledAppVersions,
+	versions []*appStoreDiscoverRepository.AppStoreApplicationVersion, policy *repository.InstalledAppUpgradePolicy) (*bean.PendingUpgradeDto, error) {
+	currentChartVersion := installedAppVersion.AppStoreApplicationVersion
+	availableUpgrades, err := GetAvailableUpgrades(currentChartVersion.Version, versions)
+	if err != nil {
+		return nil, err
+	}
+	if len(availableUpgrades) == 0 {
+		return nil, nil
+	}
+	installedApp := installedAppVersion.InstalledApp
+	appName := installedApp.App.AppName
+	if len(installedApp.App.DisplayName) > 0 {
+		appName = installedApp.App.DisplayName
+	}
+	pendingUpgrade := &bean.PendingUpgradeDto{
+		InstalledAppId:        installedApp.Id,
+		InstalledAppVersionId: installedAppVersion.Id,
+		AppId:                 installedApp.AppId,
+		AppName:               appName,
+		EnvironmentId:         installedApp.EnvironmentId,
+		EnvironmentName:       installedApp.Environment.Name,
+		ClusterId:             installedApp.Environment.ClusterId,
+		Namespace:             installedApp.Environment.Namespace,
+		AppOfferingMode:       installedApp.App.AppOfferingMode,
+		AppStoreId:            currentChartVersion.AppStoreId,
+		ChartName:             currentChartVersion.Name,
+		CurrentVersion:        &bean.ChartVersionDto{Id: currentChartVersion.Id, Version: currentChartVersion.Version},
+		LatestVersion:         availableUpgrades[0],
+		AvailableVersions:     availableUpgrades,
+		TargetVersion:         availableUpgrades[0],
+	}
+	if policy != nil && policy.Id > 0 {
+		pendingUpgrade.Policy = getPolicyDto(policy)
+		autoUpgradeVersion, err := GetAutoUpgradeVersion(availableUpgrades, bean.UpgradePolicyType(policy.PolicyType), policy.VersionConstraint)
+		if err != nil {
+			impl.logger.Warnw("invalid version constraint in upgrade policy", "installedAppId", installedApp.Id, "constraint", policy.VersionConstraint, "err", err)
+		}
+		pendingUpgrade.AutoUpgradeVersion = autoUpgradeVersion
+	}
+	return pendingUpgrade, nil
+}
+
+// updateValuesDiff sets the values diff of each pending upgrade against its TargetVersion
+func (impl *UpgradeAdvisorServiceImpl) updateValuesDiff(pendingUpgrades []*bean.PendingUpgradeDto, installedAppVersions []*appStoreRepository.InstalledAppVersions) error {
+	if len(pendingUpgrades) == 0 {
+		return nil
+	}
+	installedAppVersionById := make(map[int]*appStoreRepository.InstalledAppVersions, len(installedAppVersions))
+	for _, installedAppVersion := range installedAppVersions {
+		installedAppVersionById[installedAppVersion.Id] = installedAppVersion
+	}
+	targetVersionIds := make([]int, 0, len(pendingUpgrades))
+	for _, pendingUpgrade := range pendingUpgrades {
+		targetVersionIds = append(targetVersionIds, pendingUpgrade.TargetVersion.Id)
+	}
+	targetVersions, err := impl.appStoreApplicationVersionRepository.FindByIds(targetVersionIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching target chart versions", "ids", targetVersionIds, "err", err)
+		return err
+	}
+	targetValuesById := make(map[int]string, len(targetVersions))
+	for _, targetVersion := range targetVersions {
+		targetValuesById[targetVersion.Id] = targetVersion.ValuesYaml
+	}
+	for _, pendingUpgrade := range pendingUpgrades {
+		installedAppVersion := installedAppVersionById[pendingUpgrade.InstalledAppVersionId]
+		valuesDiff, err := GetValuesDiff(installedAppVersion.AppStoreApplicationVersion.ValuesYaml, targetValuesById[pendingUpgrade.TargetVersion.Id], installedAppVersion.ValuesYaml)
+		if err != nil {
+			impl.logger.Warnw("error in computing values diff for chart upgrade", "installedAppId", pendingUpgrade.InstalledAppId, "err", err)
+			continue
+		}
+		pendingUpgrade.ValuesDiff = valuesDiff
+	}
+	return nil
+}
+
+func findChartVersion(versions []*bean.ChartVersionDto, id int) *bean.ChartVersionDto {
+	for _, version := range versions {
+		if version.Id == id {
+			return version
+		}
+	}
+	return nil
+}
+
+func getPolicyDto(policy *repository.InstalledAppUpgradePolicy) *bean.UpgradePolicyDto {
+	return &bean.UpgradePolicyDto{
+		Id:                policy.Id,
+		InstalledAppId:    policy.InstalledAppId,
+		PolicyType:        bean.UpgradePolicyType(policy.PolicyType),
+		VersionConstraint: policy.VersionConstraint,
+	}
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"time"
+)
+
+type UpgradeType string
+
+const (
+	UpgradeTypePatch UpgradeType = "PATCH"
+	UpgradeTypeMinor UpgradeType = "MINOR"
+	UpgradeTypeMajor UpgradeType = "MAJOR"
+)
+
+// UpgradePolicyType decides which chart upgrades are applied automatically for an installed app
+type UpgradePolicyType string
+
+const (
+	// UpgradePolicyPatchOnly auto upgrades to the latest patch release of the installed major.minor version
+	UpgradePolicyPatchOnly UpgradePolicyType = "PATCH"
+	// UpgradePolicyMinor auto upgrades to the latest minor or patch release of the installed major version
+	UpgradePolicyMinor UpgradePolicyType = "MINOR"
+)
+
+func (policyType UpgradePolicyType) IsValid() bool {
+	return policyType == UpgradePolicyPatchOnly || policyType == UpgradePolicyMinor
+}
+
+// Allows reports whether an upgrade of the given type can be applied under this policy
+func (policyType UpgradePolicyType) Allows(upgradeType UpgradeType) bool {
+	switch policyType {
+	case UpgradePolicyPatchOnly:
+		return upgradeType == UpgradeTypePatch
+	case UpgradePolicyMinor:
+		return upgradeType == UpgradeTypePatch || upgradeType == UpgradeTypeMinor
+	}
+	return false
+}
+
+type UpgradeRunStatus string
+
+const (
+	UpgradeRunStatusProgressing UpgradeRunStatus = "PROGRESSING"
+	UpgradeRunStatusSucceeded   UpgradeRunStatus = "SUCCEEDED"
+	UpgradeRunStatusRolledBack  UpgradeRunStatus = "ROLLED_BACK"
+	UpgradeRunStatusFailed      UpgradeRunStatus = "FAILED"
+)
+
+type UpgradeAdvisorConfig struct {
+	CronIntervalSecs       int `env:"CHART_AUTO_UPGRADE_CRON_INTERVAL_SECS" envDefault:"300"`
+	HealthCheckTimeoutSecs int `env:"CHART_AUTO_UPGRADE_HEALTH_TIMEOUT_SECS" envDefault:"900"`
+}
+
+type UpgradePolicyDto struct {
+	Id                int               `json:"id"`
+	InstalledAppId    int               `json:"installedAppId" validate:"required,number,gt=0"`
+	PolicyType        UpgradePolicyType `json:"policyType" validate:"required"`
+	VersionConstraint string            `json:"versionConstraint,omitempty"`
+	UserId            int32             `json:"-"`
+}
+
+type ChartVersionDto struct {
+	Id          int         `json:"id"`
+	Version     string      `json:"version"`
+	UpgradeType UpgradeType `json:"upgradeType,omitempty"`
+}
+
+type ValueChange struct {
+	Path     string      `json:"path"`
+	OldValue interface{} `json:"oldValue,omitempty"`
+	NewValue interface{} `json:"newValue,omitempty"`
+}
+
+// ValuesDiff is the difference between the default values of the installed and the target chart version
+type ValuesDiff struct {
+	Added   []*ValueChange `json:"added"`
+	Removed []*ValueChange `json:"removed"`
+	Changed []*ValueChange `json:"changed"`
+	// OverriddenRemoved are paths overridden in the installation values which no longer exist in the target chart defaults
+	OverriddenRemoved []string `json:"overriddenRemoved"`
+}
+
+type PendingUpgradeDto struct {
+	InstalledAppId        int                `json:"installedAppId"`
+	InstalledAppVersionId int                `json:"installedAppVersionId"`
+	AppId                 int                `json:"appId"`
+	AppName               string             `json:"appName"`
+	EnvironmentId         int                `json:"environmentId"`
+	EnvironmentName       string             `json:"environmentName"`
+	ClusterId             int                `json:"clusterId"`
+	Namespace             string             `json:"namespace"`
+	AppOfferingMode       string             `json:"-"`
+	AppStoreId            int                `json:"appStoreId"`
+	ChartName             string             `json:"chartName"`
+	CurrentVersion        *ChartVersionDto   `json:"currentVersion"`
+	LatestVersion         *ChartVersionDto   `json:"latestVersion"`
+	AvailableVersions     []*ChartVersionDto `json:"availableVersions"`
+	// TargetVersion is the version ValuesDiff is computed against, the latest version unless requested otherwise
+	TargetVersion *ChartVersionDto  `json:"targetVersion"`
+	Policy        *UpgradePolicyDto `json:"policy,omitempty"`
+	// AutoUpgradeVersion is the version the configured policy will upgrade to, nil if the policy allows none of the available versions
+	AutoUpgradeVersion *ChartVersionDto `json:"autoUpgradeVersion,omitempty"`
+	ValuesDiff         *ValuesDiff      `json:"valuesDiff,omitempty"`
+}
+
+type UpgradeRunDto struct {
+	Id                       int              `json:"id"`
+	InstalledAppId           int              `json:"installedAppId"`
+	FromVersion              string           `json:"fromVersion"`
+	ToVersion                string           `json:"toVersion"`
+	PreviousVersionHistoryId int              `json:"previousVersionHistoryId"`
+	VersionHistoryId         int              `json:"versionHistoryId"`
+	Status                   UpgradeRunStatus `json:"status"`
+	Message                  string           `json:"message"`
+	StartedOn                time.Time        `json:"startedOn"`
+	FinishedOn               *time.Time       `json:"finishedOn,omitempty"`
+}
@@ -0,0 +1,218 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package upgradeAdvisor
+
+import (
+	"fmt"
+	"github.com/Masterminds/semver"
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
+	"github.com/devtron-labs/devtron/internal/util"
+	appStoreDiscoverRepository "github.com/devtron-labs/devtron/pkg/appStore/discover/repository"
+	"github.com/devtron-labs/devtron/pkg/appStore/upgradeAdvisor/bean"
+	"net/http"
+	"reflect"
+	"sigs.k8s.io/yaml"
+	"sort"
+	"strings"
+)
+
+// GetUpgradeType classifies the semver jump from current to target
+func GetUpgradeType(current, target *semver.Version) bean.UpgradeType {
+	if target.Major() != current.Major() {
+		return bean.UpgradeTypeMajor
+	} else if target.Minor() != current.Minor() {
+		return bean.UpgradeTypeMinor
+	}
+	return bean.UpgradeTypePatch
+}
+
+// GetAvailableUpgrades returns the chart versions newer than currentVersion, highest first.
+// Versions which are not valid semver are skipped, as are pre-releases unless the installed version is itself a pre-release.
+func GetAvailableUpgrades(currentVersion string, versions []*appStoreDiscoverRepository.AppStoreApplicationVersion) ([]*bean.ChartVersionDto, error) {
+	current, err := semver.NewVersion(currentVersion)
+	if err != nil {
+		return nil, fmt.Errorf("installed chart version %q is not a valid semver: %w", currentVersion, err)
+	}
+	type parsedVersion struct {
+		version *semver.Version
+		dto     *bean.ChartVersionDto
+	}
+	parsedVersions := make([]*parsedVersion, 0)
+	seen := make(map[string]bool)
+	for _, version := range versions {
+		parsed, err := semver.NewVersion(version.Version)
+		if err != nil || !parsed.GreaterThan(current) {
+			continue
+		}
+		if len(parsed.Prerelease()) > 0 && len(current.Prerelease()) == 0 {
+			continue
+		}
+		if seen[parsed.String()] {
+			continue
+		}
+		seen[parsed.String()] = true
+		parsedVersions = append(parsedVersions, &parsedVersion{
+			version: parsed,
+			dto: &bean.ChartVersionDto{
+				Id:          version.Id,
+				Version:     version.Version,
+				UpgradeType: GetUpgradeType(current, parsed),
+			},
+		})
+	}
+	sort.SliceStable(parsedVersions, func(i, j int) bool {
+		return parsedVersions[i].version.GreaterThan(parsedVersions[j].version)
+	})
+	upgrades := make([]*bean.ChartVersionDto, 0, len(parsedVersions))
+	for _, parsed := range parsedVersions {
+		upgrades = append(upgrades, parsed.dto)
+	}
+	return upgrades, nil
+}
+
+// GetAutoUpgradeVersion picks the highest available upgrade allowed by the policy type and version constraint, nil if there is none
+func GetAutoUpgradeVersion(availableUpgrades []*bean.ChartVersionDto, policyType bean.UpgradePolicyType, versionConstraint string) (*bean.ChartVersionDto, error) {
+	var constraint *semver.Constraints
+	if len(versionConstraint) > 0 {
+		var err error
+		constraint, err = semver.NewConstraint(versionConstraint)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, upgrade := range availableUpgrades {
+		if !policyType.Allows(upgrade.UpgradeType) {
+			continue
+		}
+		if constraint != nil {
+			version, err := semver.NewVersion(upgrade.Version)
+			if err != nil || !constraint.Check(version) {
+				continue
+			}
+		}
+		return upgrade, nil
+	}
+	return nil, nil
+}
+
+func ValidateUpgradePolicy(policy *bean.UpgradePolicyDto) error {
+	if !policy.PolicyType.IsValid() {
+		return util.NewApiError(http.StatusBadRequest, fmt.Sprintf("invalid policy type %q, supported types are PATCH and MINOR", policy.PolicyType), "invalid policy type")
+	}
+	if len(policy.VersionConstraint) > 0 {
+		if _, err := semver.NewConstraint(policy.VersionConstraint); err != nil {
+			return util.NewApiError(http.StatusBadRequest, fmt.Sprintf("invalid version constraint %q: %s", policy.VersionConstraint, err.Error()), err.Error())
+		}
+	}
+	return nil
+}
+
+// GetValuesDiff compares the default values of the installed and target chart versions key by key.
+// Lists are compared as a whole. overrideValues are the values of the installation, used to flag
+// keys which the installation has changed from the defaults but the target chart no longer has.
+func GetValuesDiff(currentDefaults, targetDefaults, overrideValues string) (*bean.ValuesDiff, error) {
+	currentValues, err := flattenValuesYaml(currentDefaults)
+	if err != nil {
+		return nil, fmt.Errorf("error in parsing installed chart values: %w", err)
+	}
+	targetValues, err := flattenValuesYaml(targetDefaults)
+	if err != nil {
+		return nil, fmt.Errorf("error in parsing target chart values: %w", err)
+	}
+	overriddenValues, err := flattenValuesYaml(overrideValues)
+	if err != nil {
+		return nil, fmt.Errorf("error in parsing installation values: %w", err)
+	}
+	diff := &bean.ValuesDiff{
+		Added:             make([]*bean.ValueChange, 0),
+		Removed:           make([]*bean.ValueChange, 0),
+		Changed:           make([]*bean.ValueChange, 0),
+		OverriddenRemoved: make([]string, 0),
+	}
+	for _, path := range sortedKeys(targetValues) {
+		currentValue, found := currentValues[path]
+		if !found {
+			diff.Added = append(diff.Added, &bean.ValueChange{Path: path, NewValue: targetValues[path]})
+		} else if !reflect.DeepEqual(currentValue, targetValues[path]) {
+			diff.Changed = append(diff.Changed, &bean.ValueChange{Path: path, OldValue: currentValue, NewValue: targetValues[path]})
+		}
+	}
+	for _, path := range sortedKeys(currentValues) {
+		if _, found := targetValues[path]; !found {
+			diff.Removed = append(diff.Removed, &bean.ValueChange{Path: path, OldValue: currentValues[path]})
+		}
+	}
+	for _, path := range sortedKeys(overriddenValues) {
+		currentValue, inCurrent := currentValues[path]
+		_, inTarget := targetValues[path]
+		if inCurrent && !inTarget && !reflect.DeepEqual(currentValue, overriddenValues[path]) {
+			diff.OverriddenRemoved = append(diff.OverriddenRemoved, path)
+		}
+	}
+	return diff, nil
+}
+
+func flattenValuesYaml(valuesYaml string) (map[string]interface{}, error) {
+	flattened := make(map[string]interface{})
+	if len(strings.TrimSpace(valuesYaml)) == 0 {
+		return flattened, nil
+	}
+	values := make(map[string]interface{})
+	if err := yaml.Unmarshal([]byte(valuesYaml), &values); err != nil {
+		return nil, err
+	}
+	flattenValues("", values, flattened)
+	return flattened, nil
+}
+
+func flattenValues(prefix string, values map[string]interface{}, flattened map[string]interface{}) {
+	for key, value := range values {
+		path := key
+		if len(prefix) > 0 {
+			path = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenValues(path, nested, flattened)
+			continue
+		}
+		flattened[path] = value
+	}
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// IsUpgradeHealthy reports whether the deployment history status of an upgraded release is healthy
+func IsUpgradeHealthy(status string) bool {
+	return status == string(health.HealthStatusHealthy) || status == cdWorkflow.WorkflowSucceeded
+}
+
+// IsUpgradeFailed reports whether the deployment history status of an upgraded release warrants a rollback
+func IsUpgradeFailed(status string) bool {
+	switch status {
+	case cdWorkflow.WorkflowFailed, cdWorkflow.WorkflowAborted, cdWorkflow.WorkflowTimedOut, string(health.HealthStatusDegraded):
+		return true
+	}
+	return false
+}
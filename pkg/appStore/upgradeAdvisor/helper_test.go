@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package upgradeAdvisor
+
+import (
+	appStoreDiscoverRepository "github.com/devtron-labs/devtron/pkg/appStore/discover/repository"
+	"github.com/devtron-labs/devtron/pkg/appStore/upgradeAdvisor/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func getChartVersions(versions ...string) []*appStoreDiscoverRepository.AppStoreApplicationVersion {
+	chartVersions := make([]*appStoreDiscoverRepository.AppStoreApplicationVersion, 0, len(versions))
+	for i, version := range versions {
+		chartVersions = append(chartVersions, &appStoreDiscoverRepository.AppStoreApplicationVersion{Id: i + 1, Version: version})
+	}
+	return chartVersions
+}
+
+func getVersionNames(versions []*bean.ChartVersionDto) []string {
+	names := make([]string, 0, len(versions))
+	for _, version := range versions {
+		names = append(names, version.Version)
+	}
+	return names
+}
+
+func TestGetAvailableUpgrades(t *testing.T) {
+	t.Run("newer versions sorted highest first", func(t *testing.T) {
+		upgrades, err := GetAvailableUpgrades("1.2.3", getChartVersions("1.2.2", "1.2.4", "2.0.0", "1.3.0", "not-semver", "1.3.0-rc.1", "v1.2.5"))
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"2.0.0", "1.3.0", "v1.2.5", "1.2.4"}, getVersionNames(upgrades))
+		assert.Equal(t, bean.UpgradeTypeMajor, upgrades[0].UpgradeType)
+		assert.Equal(t, bean.UpgradeTypeMinor, upgrades[1].UpgradeType)
+		assert.Equal(t, bean.UpgradeTypePatch, upgrades[3].UpgradeType)
+	})
+	t.Run("pre-releases considered for pre-release installs", func(t *testing.T) {
+		upgrades, err := GetAvailableUpgrades("1.3.0-rc.1", getChartVersions("1.3.0-rc.2", "1.3.0"))
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"1.3.0", "1.3.0-rc.2"}, getVersionNames(upgrades))
+	})
+	t.Run("invalid installed version", func(t *testing.T) {
+		_, err := GetAvailableUpgrades("latest", getChartVersions("1.0.0"))
+		assert.NotNil(t, err)
+	})
+}
+
+func TestGetAutoUpgradeVersion(t *testing.T) {
+	upgrades, err := GetAvailableUpgrades("1.2.3", getChartVersions("1.2.4", "1.2.5", "1.3.0", "1.4.0", "2.0.0"))
+	assert.Nil(t, err)
+
+	version, err := GetAutoUpgradeVersion(upgrades, bean.UpgradePolicyPatchOnly, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "1.2.5", version.Version)
+
+	version, err = GetAutoUpgradeVersion(upgrades, bean.UpgradePolicyMinor, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "1.4.0", version.Version)
+
+	version, err = GetAutoUpgradeVersion(upgrades, bean.UpgradePolicyMinor, "<1.4.0")
+	assert.Nil(t, err)
+	assert.Equal(t, "1.3.0", version.Version)
+
+	version, err = GetAutoUpgradeVersion(upgrades, bean.UpgradePolicyPatchOnly, "<1.2.4")
+	assert.Nil(t, err)
+	assert.Nil(t, version)
+
+	_, err = GetAutoUpgradeVersion(upgrades, bean.UpgradePolicyMinor, "not a constraint")
+	assert.NotNil(t, err)
+}
+
+func TestValidateUpgradePolicy(t *testing.T) {
+	assert.Nil(t, ValidateUpgradePolicy(&bean.UpgradePolicyDto{InstalledAppId: 1, PolicyType: bean.UpgradePolicyMinor, VersionConstraint: "~1.2"}))
+	assert.NotNil(t, ValidateUpgradePolicy(&bean.UpgradePolicyDto{InstalledAppId: 1, PolicyType: "MAJOR"}))
+	assert.NotNil(t, ValidateUpgradePolicy(&bean.UpgradePolicyDto{InstalledAppId: 1, PolicyType: bean.UpgradePolicyPatchOnly, VersionConstraint: "~~1"}))
+}
+
+func TestGetValuesDiff(t *testing.T) {
+	currentDefaults := `
+replicaCount: 1
+image:
+  repository: nginx
+  tag: "1.25"
+legacy:
+  enabled: false
+ports: [80]
+`
+	targetDefaults := `
+replicaCount: 1
+image:
+  repository: nginx
+  tag: "1.27"
+  pullPolicy: IfNotPresent
+ports: [80, 443]
+`
+	installationValues := `
+replicaCount: 3
+legacy:
+  enabled: true
+custom: value
+`
+	diff, err := GetValuesDiff(currentDefaults, targetDefaults, installationValues)
+	assert.Nil(t, err)
+	assert.Equal(t, []*bean.ValueChange{{Path: "image.pullPolicy", NewValue: "IfNotPresent"}}, diff.Added)
+	assert.Equal(t, []*bean.ValueChange{{Path: "legacy.enabled", OldValue: false}}, diff.Removed)
+	assert.Equal(t, 2, len(diff.Changed))
+	assert.Equal(t, "image.tag", diff.Changed[0].Path)
+	assert.Equal(t, "ports", diff.Changed[1].Path)
+	assert.Equal(t, []string{"legacy.enabled"}, diff.OverriddenRemoved)
+
+	_, err = GetValuesDiff("a: [", targetDefaults, "")
+	assert.NotNil(t, err)
+}
+
+func TestIsUpgradeHealthy(t *testing.T) {
+	assert.True(t, IsUpgradeHealthy("Healthy"))
+	assert.True(t, IsUpgradeHealthy("Succeeded"))
+	assert.False(t, IsUpgradeHealthy("Progressing"))
+	assert.True(t, IsUpgradeFailed("Degraded"))
+	assert.True(t, IsUpgradeFailed("Failed"))
+	assert.False(t, IsUpgradeFailed("Progressing"))
+}
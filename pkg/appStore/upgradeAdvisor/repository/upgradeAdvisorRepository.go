@@ -0,0 +1,145 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type InstalledAppUpgradePolicy struct {
+	tableName         struct{} `sql:"installed_app_upgrade_policy" pg:",discard_unknown_columns"`
+	Id                int      `sql:"id,pk"`
+	InstalledAppId    int      `sql:"installed_app_id,notnull"`
+	PolicyType        string   `sql:"policy_type,notnull"`
+	VersionConstraint string   `sql:"version_constraint"`
+	Active            bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type InstalledAppUpgradeRun struct {
+	tableName                        struct{}   `sql:"installed_app_upgrade_run" pg:",discard_unknown_columns"`
+	Id                               int        `sql:"id,pk"`
+	InstalledAppId                   int        `sql:"installed_app_id,notnull"`
+	PolicyId                         int        `sql:"policy_id,notnull"`
+	FromAppStoreApplicationVersionId int        `sql:"from_app_store_application_version_id,notnull"`
+	ToAppStoreApplicationVersionId   int        `sql:"to_app_store_application_version_id,notnull"`
+	PreviousVersionHistoryId         int        `sql:"previous_version_history_id"`
+	VersionHistoryId                 int        `sql:"version_history_id"`
+	Status                           string     `sql:"status,notnull"`
+	Message                          string     `sql:"message"`
+	HealthCheckDeadline              time.Time  `sql:"health_check_deadline"`
+	FinishedOn                       *time.Time `sql:"finished_on"`
+	sql.AuditLog
+}
+
+type UpgradeAdvisorRepository interface {
+	SavePolicy(policy *InstalledAppUpgradePolicy) error
+	UpdatePolicy(policy *InstalledAppUpgradePolicy) error
+	FindActivePolicyByInstalledAppId(installedAppId int) (*InstalledAppUpgradePolicy, error)
+	FindAllActivePolicies() ([]*InstalledAppUpgradePolicy, error)
+
+	SaveRun(run *InstalledAppUpgradeRun) error
+	UpdateRun(run *InstalledAppUpgradeRun) error
+	FindRunsByStatus(status string) ([]*InstalledAppUpgradeRun, error)
+	FindRunsByInstalledAppId(installedAppId int) ([]*InstalledAppUpgradeRun, error)
+	// IsRunPresentForTargetVersion reports whether an upgrade to the target version was already attempted for the installed app
+	IsRunPresentForTargetVersion(installedAppId, toAppStoreApplicationVersionId int) (bool, error)
+}
+
+type UpgradeAdvisorRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewUpgradeAdvisorRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *UpgradeAdvisorRepositoryImpl {
+	return &UpgradeAdvisorRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (repo *UpgradeAdvisorRepositoryImpl) SavePolicy(policy *InstalledAppUpgradePolicy) error {
+	return repo.dbConnection.Insert(policy)
+}
+
+func (repo *UpgradeAdvisorRepositoryImpl) UpdatePolicy(policy *InstalledAppUpgradePolicy) error {
+	return repo.dbConnection.Update(policy)
+}
+
+func (repo *UpgradeAdvisorRepositoryImpl) FindActivePolicyByInstalledAppId(installedAppId int) (*InstalledAppUpgradePolicy, error) {
+	policy := &InstalledAppUpgradePolicy{}
+	err := repo.dbConnection.Model(policy).
+		Where("installed_app_id = ?", installedAppId).
+		Where("active = ?", true).
+		Limit(1).
+		Select()
+	return policy, err
+}
+
+func (repo *UpgradeAdvisorRepositoryImpl) FindAllActivePolicies() ([]*InstalledAppUpgradePolicy, error) {
+	var policies []*InstalledAppUpgradePolicy
+	err := repo.dbConnection.Model(&policies).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	if err == pg.ErrNoRows {
+		err = nil
+	}
+	return policies, err
+}
+
+func (repo *UpgradeAdvisorRepositoryImpl) SaveRun(run *InstalledAppUpgradeRun) error {
+	return repo.dbConnection.Insert(run)
+}
+
+func (repo *UpgradeAdvisorRepositoryImpl) UpdateRun(run *InstalledAppUpgradeRun) error {
+	return repo.dbConnection.Update(run)
+}
+
+func (repo *UpgradeAdvisorRepositoryImpl) FindRunsByStatus(status string) ([]*InstalledAppUpgradeRun, error) {
+	var runs []*InstalledAppUpgradeRun
+	err := repo.dbConnection.Model(&runs).
+		Where("status = ?", status).
+		Order("id ASC").
+		Select()
+	if err == pg.ErrNoRows {
+		err = nil
+	}
+	return runs, err
+}
+
+func (repo *UpgradeAdvisorRepositoryImpl) FindRunsByInstalledAppId(installedAppId int) ([]*InstalledAppUpgradeRun, error) {
+	var runs []*InstalledAppUpgradeRun
+	err := repo.dbConnection.Model(&runs).
+		Where("installed_app_id = ?", installedAppId).
+		Order("id DESC").
+		Select()
+	if err == pg.ErrNoRows {
+		err = nil
+	}
+	return runs, err
+}
+
+func (repo *UpgradeAdvisorRepositoryImpl) IsRunPresentForTargetVersion(installedAppId, toAppStoreApplicationVersionId int) (bool, error) {
+	return repo.dbConnection.Model(&InstalledAppUpgradeRun{}).
+		Where("installed_app_id = ?", installedAppId).
+		Where("to_app_store_application_version_id = ?", toAppStoreApplicationVersionId).
+		Exists()
+}
@@ -0,0 +1,260 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appTransfer
+
+import (
+	"context"
+	"fmt"
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	appRepository "github.com/devtron-labs/devtron/internal/sql/repository/app"
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/appTransfer/bean"
+	"github.com/devtron-labs/devtron/pkg/appsAsCode"
+	appsAsCodeBean "github.com/devtron-labs/devtron/pkg/appsAsCode/bean"
+	gitMaterialRead "github.com/devtron-labs/devtron/pkg/build/git/gitMaterial/read"
+	gitProviderRepository "github.com/devtron-labs/devtron/pkg/build/git/gitProvider/repository"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	environmentRepository "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	pluginRepository "github.com/devtron-labs/devtron/pkg/plugin/repository"
+	teamRead "github.com/devtron-labs/devtron/pkg/team/read"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type AppTransferService interface {
+	// Export bundles the configuration of the apps with their instance specific ids referenced by name
+	Export(ctx context.Context, appIds []int, token string) (*bean.AppExportBundle, error)
+	// Import validates every reference of the bundle before creating anything, then creates the apps component by
+	// component reporting the failed components instead of aborting
+	Import(ctx context.Context, request *bean.AppImportRequest, userId int32, token string) (*bean.AppImportResponse, error)
+}
+
+type AppTransferServiceImpl struct {
+	logger                        *zap.SugaredLogger
+	appConfigurationManager       appsAsCode.AppConfigurationManager
+	appRepository                 appRepository.AppRepository
+	teamReadService               teamRead.TeamReadService
+	gitProviderRepository         gitProviderRepository.GitProviderRepository
+	gitMaterialReadService        gitMaterialRead.GitMaterialReadService
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository
+	chartRefRepository            chartRepoRepository.ChartRefRepository
+	environmentRepository         environmentRepository.EnvironmentRepository
+	globalPluginRepository        pluginRepository.GlobalPluginRepository
+	ciPipelineRepository          pipelineConfig.CiPipelineRepository
+}
+
+func NewAppTransferServiceImpl(logger *zap.SugaredLogger,
+	appConfigurationManager appsAsCode.AppConfigurationManager,
+	appRepository appRepository.AppRepository,
+	teamReadService teamRead.TeamReadService,
+	gitProviderRepository gitProviderRepository.GitProviderRepository,
+	gitMaterialReadService gitMaterialRead.GitMaterialReadService,
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository,
+	chartRefRepository chartRepoRepository.ChartRefRepository,
+	environmentRepository environmentRepository.EnvironmentRepository,
+	globalPluginRepository pluginRepository.GlobalPluginRepository,
+	ciPipelineRepository pipelineConfig.CiPipelineRepository) *AppTransferServiceImpl {
+	return &AppTransferServiceImpl{
+		logger:                        logger,
+		appConfigurationManager:       appConfigurationManager,
+		appRepository:                 appRepository,
+		teamReadService:               teamReadService,
+		gitProviderRepository:         gitProviderRepository,
+		gitMaterialReadService:        gitMaterialReadService,
+		dockerArtifactStoreRepository: dockerArtifactStoreRepository,
+		chartRefRepository:            chartRefRepository,
+		environmentRepository:         environmentRepository,
+		globalPluginRepository:        globalPluginRepository,
+		ciPipelineRepository:          ciPipelineRepository,
+	}
+}
+
+func (impl *AppTransferServiceImpl) Export(ctx context.Context, appIds []int, token string) (*bean.AppExportBundle, error) {
+	bundle := &bean.AppExportBundle{
+		ApiVersion: bean.AppExportApiVersion,
+		ExportedOn: time.Now(),
+	}
+	for _, appId := range appIds {
+		detail, err := impl.appConfigurationManager.GetAppConfiguration(ctx, appId, token)
+		if err != nil {
+			impl.logger.Errorw("error in fetching app configuration for export", "appId", appId, "err", err)
+			return nil, err
+		}
+		references, err := impl.buildReferences(detail)
+		if err != nil {
+			impl.logger.Errorw("error in building references of exported app", "appId", appId, "err", err)
+			return nil, err
+		}
+		bundle.Apps = append(bundle.Apps, &bean.ExportedApp{App: detail, References: references})
+	}
+	return bundle, nil
+}
+
+func (impl *AppTransferServiceImpl) buildReferences(detail *appBean.AppDetail) (*bean.References, error) {
+	references := &bean.References{
+		ChartRefs:         make(map[int]*bean.ChartRefReference),
+		GitProviders:      make(map[string]string),
+		Plugins:           make(map[int]*bean.PluginReference),
+		ParentCiPipelines: make(map[int]*bean.CiPipelineReference),
+	}
+
+	var chartRefIds []int
+	for _, template := range getDeploymentTemplates(detail) {
+		chartRefIds = append(chartRefIds, template.ChartRefId)
+	}
+	if len(chartRefIds) > 0 {
+		chartRefs, err := impl.chartRefRepository.FindByIds(chartRefIds)
+		if err != nil && !util.IsErrNoRows(err) {
+			return nil, err
+		}
+		for _, chartRef := range chartRefs {
+			references.ChartRefs[chartRef.Id] = &bean.ChartRefReference{Name: chartRef.Name, Version: chartRef.Version}
+		}
+	}
+
+	if len(detail.GitMaterials) > 0 {
+		gitProviders, err := impl.gitProviderRepository.FindAll()
+		if err != nil && !util.IsErrNoRows(err) {
+			return nil, err
+		}
+		for _, material := range detail.GitMaterials {
+			for _, gitProvider := range gitProviders {
+				if gitProvider.Url == material.GitProviderUrl {
+					references.GitProviders[material.GitProviderUrl] = gitProvider.Name
+					break
+				}
+			}
+		}
+	}
+
+	var pluginIds []int
+	forEachPluginStep(detail, func(step *pipelineBean.RefPluginStepDetailDto) {
+		pluginIds = append(pluginIds, step.PluginId)
+	})
+	if len(pluginIds) > 0 {
+		plugins, err := impl.globalPluginRepository.GetMetaDataByPluginIds(pluginIds)
+		if err != nil && !util.IsErrNoRows(err) {
+			return nil, err
+		}
+		for _, plugin := range plugins {
+			references.Plugins[plugin.Id] = &bean.PluginReference{Name: plugin.Name, Version: plugin.PluginVersion}
+		}
+	}
+
+	for _, workflow := range detail.AppWorkflows {
+		if workflow.CiPipeline == nil || workflow.CiPipeline.ParentCiPipeline == 0 {
+			continue
+		}
+		parentCiPipeline, err := impl.ciPipelineRepository.FindById(workflow.CiPipeline.ParentCiPipeline)
+		if err != nil {
+			return nil, err
+		}
+		parentApp, err := impl.appRepository.FindById(parentCiPipeline.AppId)
+		if err != nil {
+			return nil, err
+		}
+		references.ParentCiPipelines[parentCiPipeline.Id] = &bean.CiPipelineReference{AppName: parentApp.AppName, PipelineName: parentCiPipeline.Name}
+	}
+	// linked ci pipelines of the exporting instance do not count on the importing one
+	for _, workflow := range detail.AppWorkflows {
+		if workflow.CiPipeline != nil {
+			workflow.CiPipeline.LinkedCount = 0
+		}
+	}
+	return references, nil
+}
+
+func (impl *AppTransferServiceImpl) Import(ctx context.Context, request *bean.AppImportRequest, userId int32, token string) (*bean.AppImportResponse, error) {
+	if request.Bundle.ApiVersion != bean.AppExportApiVersion {
+		return nil, util.DefaultApiError().
+			WithHttpStatusCode(http.StatusBadRequest).
+			WithInternalMessage(bean.UnsupportedApiVersionMessage).
+			WithUserMessage(fmt.Sprintf("%s %s, expected %s", bean.UnsupportedApiVersionMessage, request.Bundle.ApiVersion, bean.AppExportApiVersion))
+	}
+	response := &bean.AppImportResponse{DryRun: request.DryRun, Status: bean.ImportStatusValid}
+	resolver := newReferenceResolver(impl, request.Mapping)
+	for _, exportedApp := range request.Bundle.Apps {
+		referenceErrors, err := resolver.resolve(exportedApp)
+		if err != nil {
+			impl.logger.Errorw("error in resolving references of imported app", "appName", exportedApp.App.Metadata.AppName, "err", err)
+			return nil, err
+		}
+		result := &bean.AppImportResult{
+			AppName:         exportedApp.App.Metadata.AppName,
+			Status:          bean.ImportStatusValid,
+			ReferenceErrors: referenceErrors,
+		}
+		if len(referenceErrors) > 0 {
+			result.Status = bean.ImportStatusInvalid
+			response.Status = bean.ImportStatusInvalid
+		}
+		response.Apps = append(response.Apps, result)
+	}
+	// nothing is written unless every app of the bundle can be imported
+	if request.DryRun || response.Status == bean.ImportStatusInvalid {
+		return response, nil
+	}
+	for i, exportedApp := range request.Bundle.Apps {
+		impl.importApp(ctx, resolver, exportedApp, response.Apps[i], userId, token)
+	}
+	response.Status = getImportStatus(response.Apps)
+	return response, nil
+}
+
+// importApp creates the base app and then each remaining component, recording the outcome of every component
+func (impl *AppTransferServiceImpl) importApp(ctx context.Context, resolver *referenceResolver, exportedApp *bean.ExportedApp, result *bean.AppImportResult, userId int32, token string) {
+	detail := exportedApp.App
+	appId, err := impl.appConfigurationManager.CreateAppFromConfiguration(ctx, getBaseApp(detail), userId, token)
+	result.Components = append(result.Components, newComponentResult(appsAsCodeBean.SectionApp, detail.Metadata.AppName, "", err))
+	if err != nil {
+		impl.logger.Errorw("error in creating imported app", "appName", detail.Metadata.AppName, "err", err)
+		result.Status = bean.ImportStatusFailed
+		return
+	}
+	result.AppId = appId
+	for _, change := range getComponentChanges(detail) {
+		err = impl.importComponent(ctx, resolver, exportedApp, appId, change, userId)
+		if err != nil {
+			impl.logger.Errorw("error in importing app component", "appName", detail.Metadata.AppName, "change", change, "err", err)
+		}
+		result.Components = append(result.Components, newComponentResult(change.Section, change.Key, change.Environment, err))
+	}
+	result.Status = getAppImportStatus(result.Components)
+}
+
+func (impl *AppTransferServiceImpl) importComponent(ctx context.Context, resolver *referenceResolver, exportedApp *bean.ExportedApp, appId int, change *appsAsCodeBean.SectionChange, userId int32) error {
+	if change.Section == appsAsCodeBean.SectionWorkflows {
+		err := impl.resolveWorkflow(resolver, exportedApp.App, change.Key, exportedApp.References)
+		if err != nil {
+			return err
+		}
+	}
+	return impl.appConfigurationManager.ApplySectionChange(ctx, appId, exportedApp.App, change, userId)
+}
+
+func (impl *AppTransferServiceImpl) resolveWorkflow(resolver *referenceResolver, detail *appBean.AppDetail, workflowName string, references *bean.References) error {
+	for _, workflow := range detail.AppWorkflows {
+		if workflow.Name == workflowName {
+			return resolver.resolveLinkedCiPipeline(workflow, references)
+		}
+	}
+	return nil
+}
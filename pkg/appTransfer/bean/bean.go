@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	"time"
+)
+
+const AppExportApiVersion = "devtron/app-export/v1"
+
+type ImportStatus string
+
+const (
+	ImportStatusValid              ImportStatus = "valid"
+	ImportStatusInvalid            ImportStatus = "invalid"
+	ImportStatusSucceeded          ImportStatus = "succeeded"
+	ImportStatusPartiallySucceeded ImportStatus = "partiallySucceeded"
+	ImportStatusFailed             ImportStatus = "failed"
+)
+
+type ReferenceKind string
+
+const (
+	ReferenceKindApp              ReferenceKind = "app"
+	ReferenceKindProject          ReferenceKind = "project"
+	ReferenceKindGitProvider      ReferenceKind = "gitProvider"
+	ReferenceKindDockerRegistry   ReferenceKind = "dockerRegistry"
+	ReferenceKindChartRef         ReferenceKind = "chartRef"
+	ReferenceKindEnvironment      ReferenceKind = "environment"
+	ReferenceKindPlugin           ReferenceKind = "plugin"
+	ReferenceKindParentCiPipeline ReferenceKind = "parentCiPipeline"
+)
+
+const (
+	AppAlreadyExistsMessage      = "app already exists on this instance"
+	ReferenceNotFoundMessage     = "not found on this instance, add it or map it to an existing one"
+	ReferenceMissingInBundle     = "reference is missing in the export bundle"
+	UnsupportedApiVersionMessage = "unsupported export bundle apiVersion"
+)
+
+type ChartRefReference struct {
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version"`
+}
+
+type PluginReference struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type CiPipelineReference struct {
+	AppName      string `json:"appName"`
+	PipelineName string `json:"pipelineName"`
+}
+
+// References names the instance specific ids used in an exported app, so that they can be looked up again on import
+type References struct {
+	// ChartRefs is keyed by the chart ref id of the deployment templates
+	ChartRefs map[int]*ChartRefReference `json:"chartRefs,omitempty"`
+	// GitProviders maps the git provider url of the git materials to the git provider name
+	GitProviders map[string]string `json:"gitProviders,omitempty"`
+	// Plugins is keyed by the plugin id of the plugin steps of the pipeline stages
+	Plugins map[int]*PluginReference `json:"plugins,omitempty"`
+	// ParentCiPipelines is keyed by the parent ci pipeline id of linked ci pipelines
+	ParentCiPipelines map[int]*CiPipelineReference `json:"parentCiPipelines,omitempty"`
+}
+
+type ExportedApp struct {
+	App        *appBean.AppDetail `json:"app" validate:"required"`
+	References *References        `json:"references"`
+}
+
+type AppExportBundle struct {
+	ApiVersion string         `json:"apiVersion" validate:"required"`
+	ExportedOn time.Time      `json:"exportedOn"`
+	Apps       []*ExportedApp `json:"apps" validate:"required,min=1,dive"`
+}
+
+// ReferenceMapping renames the references of the bundle, from the name on the exporting instance to the one on this instance
+type ReferenceMapping struct {
+	Environments     map[string]string `json:"environments,omitempty"`
+	DockerRegistries map[string]string `json:"dockerRegistries,omitempty"`
+	GitProviders     map[string]string `json:"gitProviders,omitempty"`
+	Projects         map[string]string `json:"projects,omitempty"`
+}
+
+type AppImportRequest struct {
+	Bundle  *AppExportBundle  `json:"bundle" validate:"required"`
+	Mapping *ReferenceMapping `json:"mapping"`
+	// DryRun only resolves the references of the bundle without creating anything
+	DryRun bool `json:"dryRun"`
+}
+
+type ReferenceError struct {
+	Kind    ReferenceKind `json:"kind"`
+	Name    string        `json:"name"`
+	Message string        `json:"message"`
+}
+
+type ComponentResult struct {
+	Component   string       `json:"component"`
+	Name        string       `json:"name,omitempty"`
+	Environment string       `json:"environment,omitempty"`
+	Status      ImportStatus `json:"status"`
+	Error       string       `json:"error,omitempty"`
+}
+
+type AppImportResult struct {
+	AppName         string             `json:"appName"`
+	AppId           int                `json:"appId,omitempty"`
+	Status          ImportStatus       `json:"status"`
+	ReferenceErrors []*ReferenceError  `json:"referenceErrors,omitempty"`
+	Components      []*ComponentResult `json:"components,omitempty"`
+}
+
+type AppImportResponse struct {
+	Status ImportStatus       `json:"status"`
+	DryRun bool               `json:"dryRun"`
+	Apps   []*AppImportResult `json:"apps"`
+}
@@ -0,0 +1,220 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appTransfer
+
+import (
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	"github.com/devtron-labs/devtron/pkg/appTransfer/bean"
+	appsAsCodeBean "github.com/devtron-labs/devtron/pkg/appsAsCode/bean"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+	"sort"
+)
+
+// mapName returns the name given by the import mapping for name, name itself when it is not mapped
+func mapName(mapping map[string]string, name string) string {
+	if mapped, ok := mapping[name]; ok && len(mapped) > 0 {
+		return mapped
+	}
+	return name
+}
+
+func sortedEnvironmentNames(detail *appBean.AppDetail) []string {
+	envNames := make([]string, 0, len(detail.EnvironmentOverrides))
+	for envName := range detail.EnvironmentOverrides {
+		envNames = append(envNames, envName)
+	}
+	sort.Strings(envNames)
+	return envNames
+}
+
+// getDeploymentTemplates returns the global deployment template followed by the environment overrides, in environment name order
+func getDeploymentTemplates(detail *appBean.AppDetail) []*appBean.DeploymentTemplate {
+	var templates []*appBean.DeploymentTemplate
+	if detail.GlobalDeploymentTemplate != nil {
+		templates = append(templates, detail.GlobalDeploymentTemplate)
+	}
+	for _, envName := range sortedEnvironmentNames(detail) {
+		override := detail.EnvironmentOverrides[envName]
+		if override != nil && override.DeploymentTemplate != nil {
+			templates = append(templates, override.DeploymentTemplate)
+		}
+	}
+	return templates
+}
+
+// forEachPluginStep calls fn for the plugin steps of every ci and cd pipeline stage of the app
+func forEachPluginStep(detail *appBean.AppDetail, fn func(step *pipelineBean.RefPluginStepDetailDto)) {
+	var stages []*pipelineBean.PipelineStageDto
+	for _, workflow := range detail.AppWorkflows {
+		if workflow.CiPipeline != nil {
+			stages = append(stages, workflow.CiPipeline.PreBuildStage, workflow.CiPipeline.PostBuildStage)
+		}
+		for _, cdPipeline := range workflow.CdPipelines {
+			stages = append(stages, cdPipeline.PreDeployStage, cdPipeline.PostDeployStage)
+		}
+	}
+	for _, stage := range stages {
+		if stage == nil {
+			continue
+		}
+		for _, step := range stage.Steps {
+			if step != nil && step.RefPluginStepDetail != nil {
+				fn(step.RefPluginStepDetail)
+			}
+		}
+	}
+}
+
+// renameEnvironments applies the environment mapping to the cd pipelines and the environment overrides of the app
+func renameEnvironments(detail *appBean.AppDetail, mapping map[string]string) {
+	for _, workflow := range detail.AppWorkflows {
+		for _, cdPipeline := range workflow.CdPipelines {
+			cdPipeline.EnvironmentName = mapName(mapping, cdPipeline.EnvironmentName)
+		}
+	}
+	if len(detail.EnvironmentOverrides) == 0 {
+		return
+	}
+	overrides := make(map[string]*appBean.EnvironmentOverride, len(detail.EnvironmentOverrides))
+	for envName, override := range detail.EnvironmentOverrides {
+		overrides[mapName(mapping, envName)] = override
+	}
+	detail.EnvironmentOverrides = overrides
+}
+
+// getEnvironmentNames lists the distinct environments used by the cd pipelines and environment overrides of the app
+func getEnvironmentNames(detail *appBean.AppDetail) []string {
+	envNameSet := make(map[string]bool)
+	for _, workflow := range detail.AppWorkflows {
+		for _, cdPipeline := range workflow.CdPipelines {
+			envNameSet[cdPipeline.EnvironmentName] = true
+		}
+	}
+	for envName := range detail.EnvironmentOverrides {
+		envNameSet[envName] = true
+	}
+	envNames := make([]string, 0, len(envNameSet))
+	for envName := range envNameSet {
+		envNames = append(envNames, envName)
+	}
+	sort.Strings(envNames)
+	return envNames
+}
+
+// getBaseApp is the part of the app created in one go on import, the rest is imported component by component
+func getBaseApp(detail *appBean.AppDetail) *appBean.AppDetail {
+	return &appBean.AppDetail{
+		Metadata:                 detail.Metadata,
+		GitMaterials:             detail.GitMaterials,
+		DockerConfig:             detail.DockerConfig,
+		GlobalDeploymentTemplate: detail.GlobalDeploymentTemplate,
+	}
+}
+
+// getComponentChanges lists the components imported one by one after the base app, workflows first as the
+// environment overrides need the cd pipelines of their environment
+func getComponentChanges(detail *appBean.AppDetail) []*appsAsCodeBean.SectionChange {
+	var changes []*appsAsCodeBean.SectionChange
+	for _, workflow := range detail.AppWorkflows {
+		changes = append(changes, newCreateChange(appsAsCodeBean.SectionWorkflows, workflow.Name, ""))
+	}
+	for _, configMap := range detail.GlobalConfigMaps {
+		changes = append(changes, newCreateChange(appsAsCodeBean.SectionGlobalConfigMaps, configMap.Name, ""))
+	}
+	for _, secret := range detail.GlobalSecrets {
+		changes = append(changes, newCreateChange(appsAsCodeBean.SectionGlobalSecrets, secret.Name, ""))
+	}
+	for _, envName := range sortedEnvironmentNames(detail) {
+		override := detail.EnvironmentOverrides[envName]
+		if override == nil {
+			continue
+		}
+		if override.DeploymentTemplate != nil {
+			changes = append(changes, newCreateChange(appsAsCodeBean.SectionEnvDeploymentTemplate, "", envName))
+		}
+		for _, configMap := range override.ConfigMaps {
+			changes = append(changes, newCreateChange(appsAsCodeBean.SectionEnvConfigMaps, configMap.Name, envName))
+		}
+		for _, secret := range override.Secrets {
+			changes = append(changes, newCreateChange(appsAsCodeBean.SectionEnvSecrets, secret.Name, envName))
+		}
+	}
+	return changes
+}
+
+func newCreateChange(section, key, environment string) *appsAsCodeBean.SectionChange {
+	return &appsAsCodeBean.SectionChange{
+		Section:     section,
+		Key:         key,
+		Environment: environment,
+		Action:      appsAsCodeBean.ChangeActionCreate,
+		Supported:   true,
+	}
+}
+
+func newComponentResult(section, name, environment string, err error) *bean.ComponentResult {
+	result := &bean.ComponentResult{
+		Component:   section,
+		Name:        name,
+		Environment: environment,
+		Status:      bean.ImportStatusSucceeded,
+	}
+	if err != nil {
+		result.Status = bean.ImportStatusFailed
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// getAppImportStatus is succeeded when every component got imported and partiallySucceeded when only some did
+func getAppImportStatus(components []*bean.ComponentResult) bean.ImportStatus {
+	failed := 0
+	for _, component := range components {
+		if component.Status == bean.ImportStatusFailed {
+			failed++
+		}
+	}
+	switch {
+	case failed == 0:
+		return bean.ImportStatusSucceeded
+	case failed == len(components):
+		return bean.ImportStatusFailed
+	default:
+		return bean.ImportStatusPartiallySucceeded
+	}
+}
+
+// getImportStatus sums up the status of the imported apps the same way as for the components of an app
+func getImportStatus(apps []*bean.AppImportResult) bean.ImportStatus {
+	succeeded, failed := 0, 0
+	for _, app := range apps {
+		switch app.Status {
+		case bean.ImportStatusSucceeded:
+			succeeded++
+		case bean.ImportStatusFailed:
+			failed++
+		}
+	}
+	switch {
+	case succeeded == len(apps):
+		return bean.ImportStatusSucceeded
+	case failed == len(apps):
+		return bean.ImportStatusFailed
+	default:
+		return bean.ImportStatusPartiallySucceeded
+	}
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appTransfer
+
+import (
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	"github.com/devtron-labs/devtron/pkg/appTransfer/bean"
+	appsAsCodeBean "github.com/devtron-labs/devtron/pkg/appsAsCode/bean"
+	"reflect"
+	"testing"
+)
+
+func TestRenameEnvironments(t *testing.T) {
+	detail := &appBean.AppDetail{
+		AppWorkflows: []*appBean.AppWorkflow{{
+			Name:        "wf",
+			CdPipelines: []*appBean.CdPipelineDetails{{Name: "cd-qa", EnvironmentName: "qa"}, {Name: "cd-prod", EnvironmentName: "prod"}},
+		}},
+		EnvironmentOverrides: map[string]*appBean.EnvironmentOverride{
+			"qa":   {ConfigMaps: []*appBean.ConfigMap{{Name: "qa-cm"}}},
+			"prod": {ConfigMaps: []*appBean.ConfigMap{{Name: "prod-cm"}}},
+		},
+	}
+	renameEnvironments(detail, map[string]string{"qa": "staging", "prod": ""})
+
+	if got := detail.AppWorkflows[0].CdPipelines[0].EnvironmentName; got != "staging" {
+		t.Errorf("cd pipeline environment = %s, want staging", got)
+	}
+	if got := detail.AppWorkflows[0].CdPipelines[1].EnvironmentName; got != "prod" {
+		t.Errorf("cd pipeline with empty mapping environment = %s, want prod", got)
+	}
+	if override := detail.EnvironmentOverrides["staging"]; override == nil || override.ConfigMaps[0].Name != "qa-cm" {
+		t.Errorf("override of qa not moved to staging: %v", detail.EnvironmentOverrides)
+	}
+	if got := getEnvironmentNames(detail); !reflect.DeepEqual(got, []string{"prod", "staging"}) {
+		t.Errorf("getEnvironmentNames() = %v", got)
+	}
+}
+
+func TestGetComponentChanges(t *testing.T) {
+	detail := &appBean.AppDetail{
+		AppWorkflows:     []*appBean.AppWorkflow{{Name: "build"}},
+		GlobalConfigMaps: []*appBean.ConfigMap{{Name: "cm"}},
+		GlobalSecrets:    []*appBean.Secret{{Name: "secret"}},
+		EnvironmentOverrides: map[string]*appBean.EnvironmentOverride{
+			"qa":   {DeploymentTemplate: &appBean.DeploymentTemplate{ChartRefId: 1}, Secrets: []*appBean.Secret{{Name: "qa-secret"}}},
+			"dev":  {ConfigMaps: []*appBean.ConfigMap{{Name: "dev-cm"}}},
+			"prod": nil,
+		},
+	}
+	var got []string
+	for _, change := range getComponentChanges(detail) {
+		if change.Action != appsAsCodeBean.ChangeActionCreate {
+			t.Errorf("change %v is not a create", change)
+		}
+		got = append(got, change.Section+"/"+change.Environment+"/"+change.Key)
+	}
+	want := []string{
+		"workflows//build",
+		"globalConfigMaps//cm",
+		"globalSecrets//secret",
+		"environmentOverride.configMaps/dev/dev-cm",
+		"environmentOverride.deploymentTemplate/qa/",
+		"environmentOverride.secrets/qa/qa-secret",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getComponentChanges() = %v, want %v", got, want)
+	}
+}
+
+func TestGetImportStatus(t *testing.T) {
+	succeeded := &bean.ComponentResult{Status: bean.ImportStatusSucceeded}
+	failed := &bean.ComponentResult{Status: bean.ImportStatusFailed}
+	tests := []struct {
+		name       string
+		components []*bean.ComponentResult
+		want       bean.ImportStatus
+	}{
+		{name: "all components imported", components: []*bean.ComponentResult{succeeded, succeeded}, want: bean.ImportStatusSucceeded},
+		{name: "some components failed", components: []*bean.ComponentResult{succeeded, failed}, want: bean.ImportStatusPartiallySucceeded},
+		{name: "base app failed", components: []*bean.ComponentResult{failed}, want: bean.ImportStatusFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getAppImportStatus(tt.components); got != tt.want {
+				t.Errorf("getAppImportStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	apps := []*bean.AppImportResult{{Status: bean.ImportStatusSucceeded}, {Status: bean.ImportStatusFailed}}
+	if got := getImportStatus(apps); got != bean.ImportStatusPartiallySucceeded {
+		t.Errorf("getImportStatus() = %v, want %v", got, bean.ImportStatusPartiallySucceeded)
+	}
+}
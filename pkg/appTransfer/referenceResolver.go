@@ -0,0 +1,329 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appTransfer
+
+import (
+	"fmt"
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/appTransfer/bean"
+	gitProviderRepository "github.com/devtron-labs/devtron/pkg/build/git/gitProvider/repository"
+	pipelineBean "github.com/devtron-labs/devtron/pkg/pipeline/bean"
+)
+
+// referenceResolver looks up the references of the imported apps on this instance and rewrites them in place,
+// lookups are cached for the duration of one import
+type referenceResolver struct {
+	impl    *AppTransferServiceImpl
+	mapping *bean.ReferenceMapping
+	// bundleCiPipelines holds the ci pipeline names of the apps of the bundle resolved so far, by app name
+	bundleCiPipelines  map[string]map[string]bool
+	gitProviders       []gitProviderRepository.GitProvider
+	gitProvidersLoaded bool
+	projects           map[string]bool
+	dockerRegistries   map[string]bool
+	environments       map[string]bool
+	chartRefIds        map[string]int
+	pluginIds          map[string]int
+}
+
+func newReferenceResolver(impl *AppTransferServiceImpl, mapping *bean.ReferenceMapping) *referenceResolver {
+	if mapping == nil {
+		mapping = &bean.ReferenceMapping{}
+	}
+	return &referenceResolver{
+		impl:              impl,
+		mapping:           mapping,
+		bundleCiPipelines: make(map[string]map[string]bool),
+		projects:          make(map[string]bool),
+		dockerRegistries:  make(map[string]bool),
+		environments:      make(map[string]bool),
+		chartRefIds:       make(map[string]int),
+		pluginIds:         make(map[string]int),
+	}
+}
+
+// resolve rewrites the references of the exported app to this instance and lists the ones not found here,
+// an error is returned only when a lookup fails for another reason than the reference not existing
+func (resolver *referenceResolver) resolve(exportedApp *bean.ExportedApp) ([]*bean.ReferenceError, error) {
+	detail := exportedApp.App
+	references := exportedApp.References
+	if references == nil {
+		references = &bean.References{}
+	}
+	var referenceErrors []*bean.ReferenceError
+	addError := func(kind bean.ReferenceKind, name, message string) {
+		referenceErrors = append(referenceErrors, &bean.ReferenceError{Kind: kind, Name: name, Message: message})
+	}
+
+	appName := detail.Metadata.AppName
+	existingApp, err := resolver.impl.appRepository.FindActiveByName(appName)
+	if err != nil && !util.IsErrNoRows(err) {
+		return nil, err
+	}
+	if err == nil && existingApp.Id > 0 {
+		addError(bean.ReferenceKindApp, appName, bean.AppAlreadyExistsMessage)
+	}
+
+	detail.Metadata.ProjectName = mapName(resolver.mapping.Projects, detail.Metadata.ProjectName)
+	found, err := resolver.projectExists(detail.Metadata.ProjectName)
+	if err != nil {
+		return nil, err
+	} else if !found {
+		addError(bean.ReferenceKindProject, detail.Metadata.ProjectName, bean.ReferenceNotFoundMessage)
+	}
+
+	for _, material := range detail.GitMaterials {
+		providerName := mapName(resolver.mapping.GitProviders, references.GitProviders[material.GitProviderUrl])
+		providerUrl, err := resolver.findGitProviderUrl(providerName, material.GitProviderUrl)
+		if err != nil {
+			return nil, err
+		} else if len(providerUrl) == 0 {
+			addError(bean.ReferenceKindGitProvider, providerName, bean.ReferenceNotFoundMessage)
+			continue
+		}
+		material.GitProviderUrl = providerUrl
+	}
+
+	if detail.DockerConfig != nil {
+		detail.DockerConfig.DockerRegistry = mapName(resolver.mapping.DockerRegistries, detail.DockerConfig.DockerRegistry)
+		found, err = resolver.dockerRegistryExists(detail.DockerConfig.DockerRegistry)
+		if err != nil {
+			return nil, err
+		} else if !found {
+			addError(bean.ReferenceKindDockerRegistry, detail.DockerConfig.DockerRegistry, bean.ReferenceNotFoundMessage)
+		}
+	}
+
+	for _, template := range getDeploymentTemplates(detail) {
+		chartRef := references.ChartRefs[template.ChartRefId]
+		if chartRef == nil {
+			addError(bean.ReferenceKindChartRef, fmt.Sprintf("%d", template.ChartRefId), bean.ReferenceMissingInBundle)
+			continue
+		}
+		chartRefId, err := resolver.findChartRefId(chartRef)
+		if err != nil {
+			return nil, err
+		} else if chartRefId == 0 {
+			addError(bean.ReferenceKindChartRef, fmt.Sprintf("%s %s", chartRef.Name, chartRef.Version), bean.ReferenceNotFoundMessage)
+			continue
+		}
+		template.ChartRefId = chartRefId
+	}
+
+	renameEnvironments(detail, resolver.mapping.Environments)
+	for _, envName := range getEnvironmentNames(detail) {
+		found, err = resolver.environmentExists(envName)
+		if err != nil {
+			return nil, err
+		} else if !found {
+			addError(bean.ReferenceKindEnvironment, envName, bean.ReferenceNotFoundMessage)
+		}
+	}
+
+	var pluginErr error
+	forEachPluginStep(detail, func(step *pipelineBean.RefPluginStepDetailDto) {
+		if pluginErr != nil {
+			return
+		}
+		plugin := references.Plugins[step.PluginId]
+		if plugin == nil {
+			addError(bean.ReferenceKindPlugin, fmt.Sprintf("%d", step.PluginId), bean.ReferenceMissingInBundle)
+			return
+		}
+		pluginId, err := resolver.findPluginId(plugin)
+		if err != nil {
+			pluginErr = err
+		} else if pluginId == 0 {
+			addError(bean.ReferenceKindPlugin, fmt.Sprintf("%s %s", plugin.Name, plugin.Version), bean.ReferenceNotFoundMessage)
+		} else {
+			step.PluginId = pluginId
+		}
+	})
+	if pluginErr != nil {
+		return nil, pluginErr
+	}
+
+	ciPipelineNames := make(map[string]bool)
+	for _, workflow := range detail.AppWorkflows {
+		if workflow.CiPipeline == nil {
+			continue
+		}
+		ciPipelineNames[workflow.CiPipeline.Name] = true
+		if workflow.CiPipeline.ParentCiPipeline == 0 {
+			continue
+		}
+		parent := references.ParentCiPipelines[workflow.CiPipeline.ParentCiPipeline]
+		if parent == nil {
+			addError(bean.ReferenceKindParentCiPipeline, fmt.Sprintf("%d", workflow.CiPipeline.ParentCiPipeline), bean.ReferenceMissingInBundle)
+			continue
+		}
+		if resolver.bundleCiPipelines[parent.AppName][parent.PipelineName] {
+			// the parent app is imported before this one, its ids are resolved when the workflow is created
+			continue
+		}
+		parentCiPipeline, err := resolver.findParentCiPipeline(parent)
+		if err != nil {
+			return nil, err
+		} else if parentCiPipeline == nil {
+			addError(bean.ReferenceKindParentCiPipeline, fmt.Sprintf("%s/%s", parent.AppName, parent.PipelineName), bean.ReferenceNotFoundMessage)
+		}
+	}
+	resolver.bundleCiPipelines[appName] = ciPipelineNames
+	return referenceErrors, nil
+}
+
+// resolveLinkedCiPipeline points a linked ci pipeline of the workflow to its parent ci pipeline on this instance,
+// it is done right before creating the workflow as the parent can be part of the same import
+func (resolver *referenceResolver) resolveLinkedCiPipeline(workflow *appBean.AppWorkflow, references *bean.References) error {
+	ciPipeline := workflow.CiPipeline
+	if ciPipeline == nil || ciPipeline.ParentCiPipeline == 0 || references == nil {
+		return nil
+	}
+	parent := references.ParentCiPipelines[ciPipeline.ParentCiPipeline]
+	if parent == nil {
+		return fmt.Errorf("parent ci pipeline %d %s", ciPipeline.ParentCiPipeline, bean.ReferenceMissingInBundle)
+	}
+	parentCiPipeline, err := resolver.findParentCiPipeline(parent)
+	if err != nil {
+		return err
+	} else if parentCiPipeline == nil {
+		return fmt.Errorf("parent ci pipeline %s/%s %s", parent.AppName, parent.PipelineName, bean.ReferenceNotFoundMessage)
+	}
+	for _, material := range ciPipeline.CiPipelineMaterialsConfig {
+		gitMaterial, err := resolver.impl.gitMaterialReadService.FindByAppIdAndCheckoutPath(parentCiPipeline.AppId, material.CheckoutPath)
+		if err != nil {
+			return fmt.Errorf("git material %s of parent app %s: %w", material.CheckoutPath, parent.AppName, err)
+		}
+		material.GitMaterialId = gitMaterial.Id
+	}
+	ciPipeline.ParentCiPipeline = parentCiPipeline.Id
+	ciPipeline.ParentAppId = parentCiPipeline.AppId
+	return nil
+}
+
+func (resolver *referenceResolver) projectExists(name string) (bool, error) {
+	if found, ok := resolver.projects[name]; ok {
+		return found, nil
+	}
+	_, err := resolver.impl.teamReadService.FindByTeamName(name)
+	if err != nil && !util.IsErrNoRows(err) {
+		return false, err
+	}
+	resolver.projects[name] = err == nil
+	return err == nil, nil
+}
+
+// findGitProviderUrl finds the git provider by name, or by the url of the exporting instance when the bundle has no
+// name for it, empty when not found
+func (resolver *referenceResolver) findGitProviderUrl(name, url string) (string, error) {
+	if !resolver.gitProvidersLoaded {
+		gitProviders, err := resolver.impl.gitProviderRepository.FindAllActiveForAutocomplete()
+		if err != nil && !util.IsErrNoRows(err) {
+			return "", err
+		}
+		resolver.gitProviders = gitProviders
+		resolver.gitProvidersLoaded = true
+	}
+	for _, gitProvider := range resolver.gitProviders {
+		if (len(name) > 0 && gitProvider.Name == name) || (len(name) == 0 && gitProvider.Url == url) {
+			return gitProvider.Url, nil
+		}
+	}
+	return "", nil
+}
+
+func (resolver *referenceResolver) dockerRegistryExists(id string) (bool, error) {
+	if found, ok := resolver.dockerRegistries[id]; ok {
+		return found, nil
+	}
+	_, err := resolver.impl.dockerArtifactStoreRepository.FindOne(id)
+	if err != nil && !util.IsErrNoRows(err) {
+		return false, err
+	}
+	resolver.dockerRegistries[id] = err == nil
+	return err == nil, nil
+}
+
+func (resolver *referenceResolver) environmentExists(name string) (bool, error) {
+	if found, ok := resolver.environments[name]; ok {
+		return found, nil
+	}
+	_, err := resolver.impl.environmentRepository.FindByName(name)
+	if err != nil && !util.IsErrNoRows(err) {
+		return false, err
+	}
+	resolver.environments[name] = err == nil
+	return err == nil, nil
+}
+
+func (resolver *referenceResolver) findChartRefId(chartRef *bean.ChartRefReference) (int, error) {
+	key := chartRef.Name + "/" + chartRef.Version
+	if id, ok := resolver.chartRefIds[key]; ok {
+		return id, nil
+	}
+	found, err := resolver.impl.chartRefRepository.FindByVersionAndName(chartRef.Name, chartRef.Version)
+	if err != nil && !util.IsErrNoRows(err) {
+		return 0, err
+	}
+	if err == nil {
+		resolver.chartRefIds[key] = found.Id
+	} else {
+		resolver.chartRefIds[key] = 0
+	}
+	return resolver.chartRefIds[key], nil
+}
+
+func (resolver *referenceResolver) findPluginId(plugin *bean.PluginReference) (int, error) {
+	key := plugin.Name + "/" + plugin.Version
+	if id, ok := resolver.pluginIds[key]; ok {
+		return id, nil
+	}
+	plugins, err := resolver.impl.globalPluginRepository.GetPluginByName(plugin.Name)
+	if err != nil && !util.IsErrNoRows(err) {
+		return 0, err
+	}
+	resolver.pluginIds[key] = 0
+	for _, candidate := range plugins {
+		if candidate.PluginVersion == plugin.Version {
+			resolver.pluginIds[key] = candidate.Id
+			break
+		}
+	}
+	return resolver.pluginIds[key], nil
+}
+
+// findParentCiPipeline returns nil when the parent app or its ci pipeline does not exist on this instance
+func (resolver *referenceResolver) findParentCiPipeline(parent *bean.CiPipelineReference) (*pipelineConfig.CiPipeline, error) {
+	parentApp, err := resolver.impl.appRepository.FindActiveByName(parent.AppName)
+	if util.IsErrNoRows(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	ciPipelines, err := resolver.impl.ciPipelineRepository.FindByAppId(parentApp.Id)
+	if err != nil && !util.IsErrNoRows(err) {
+		return nil, err
+	}
+	for _, ciPipeline := range ciPipelines {
+		if ciPipeline.Name == parent.PipelineName {
+			return ciPipeline, nil
+		}
+	}
+	return nil, nil
+}
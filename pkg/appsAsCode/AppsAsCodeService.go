@@ -0,0 +1,475 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appsAsCode
+
+import (
+	"context"
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	appRepository "github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/appsAsCode/bean"
+	"github.com/devtron-labs/devtron/pkg/appsAsCode/repository"
+	gitProviderRepository "github.com/devtron-labs/devtron/pkg/build/git/gitProvider/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// AppConfigurationManager reads and writes the configuration of an app in the appBean.AppDetail form
+type AppConfigurationManager interface {
+	GetAppConfiguration(ctx context.Context, appId int, token string) (*appBean.AppDetail, error)
+	CreateAppFromConfiguration(ctx context.Context, appDetail *appBean.AppDetail, userId int32, token string) (int, error)
+	// ApplySectionChange brings a single section of the app configuration to its desired state
+	ApplySectionChange(ctx context.Context, appId int, desired *appBean.AppDetail, change *bean.SectionChange, userId int32) error
+	DeleteApp(ctx context.Context, appId int, userId int32) error
+}
+
+type AppsAsCodeService interface {
+	GetAllSources() ([]*bean.AppCodeSourceDto, error)
+	SaveSource(request *bean.AppCodeSourceDto) (*bean.AppCodeSourceDto, error)
+	DeleteSource(id int, userId int32) error
+	// Plan diffs the app definitions of the source against the current app configuration without changing anything
+	Plan(ctx context.Context, sourceId int, token string) (*bean.ReconcilePlan, error)
+	// Apply executes the supported changes of the plan and returns the plan with the outcome of every change
+	Apply(ctx context.Context, sourceId int, userId int32, token string) (*bean.ReconcilePlan, error)
+	GetDriftReport(ctx context.Context, sourceId int, token string) (*bean.DriftReportDto, error)
+	GetAppStatus(appId int) (*bean.AppCodeStatusDto, error)
+}
+
+type AppsAsCodeServiceImpl struct {
+	logger                  *zap.SugaredLogger
+	appsAsCodeRepository    repository.AppsAsCodeRepository
+	gitProviderRepository   gitProviderRepository.GitProviderRepository
+	appRepository           appRepository.AppRepository
+	appConfigurationManager AppConfigurationManager
+}
+
+func NewAppsAsCodeServiceImpl(logger *zap.SugaredLogger,
+	appsAsCodeRepository repository.AppsAsCodeRepository,
+	gitProviderRepository gitProviderRepository.GitProviderRepository,
+	appRepository appRepository.AppRepository,
+	appConfigurationManager AppConfigurationManager) *AppsAsCodeServiceImpl {
+	return &AppsAsCodeServiceImpl{
+		logger:                  logger,
+		appsAsCodeRepository:    appsAsCodeRepository,
+		gitProviderRepository:   gitProviderRepository,
+		appRepository:           appRepository,
+		appConfigurationManager: appConfigurationManager,
+	}
+}
+
+func (impl *AppsAsCodeServiceImpl) GetAllSources() ([]*bean.AppCodeSourceDto, error) {
+	sources, err := impl.appsAsCodeRepository.FindAllActiveSources()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching apps as code sources", "err", err)
+		return nil, err
+	}
+	result := make([]*bean.AppCodeSourceDto, 0, len(sources))
+	for _, source := range sources {
+		result = append(result, toSourceDto(source))
+	}
+	return result, nil
+}
+
+func (impl *AppsAsCodeServiceImpl) SaveSource(request *bean.AppCodeSourceDto) (*bean.AppCodeSourceDto, error) {
+	_, err := impl.gitProviderRepository.FindOne(strconv.Itoa(request.GitProviderId))
+	if err == pg.ErrNoRows {
+		return nil, util.NewApiError(http.StatusBadRequest, "git provider not found", "git provider not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching git provider", "gitProviderId", request.GitProviderId, "err", err)
+		return nil, err
+	}
+	source := &repository.AppCodeSource{Active: true}
+	if request.Id > 0 {
+		source, err = impl.getSource(request.Id)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		source.AuditLog = sql.NewDefaultAuditLog(request.UserId)
+	}
+	source.Name = request.Name
+	source.GitProviderId = request.GitProviderId
+	source.RepoUrl = request.RepoUrl
+	source.Branch = request.Branch
+	source.Path = request.Path
+	source.LockUiEdits = request.LockUiEdits
+	source.Prune = request.Prune
+	source.UpdateAuditLog(request.UserId)
+	if source.Id > 0 {
+		err = impl.appsAsCodeRepository.UpdateSource(source)
+	} else {
+		err = impl.appsAsCodeRepository.SaveSource(source)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving apps as code source", "source", source, "err", err)
+		return nil, err
+	}
+	return toSourceDto(source), nil
+}
+
+func (impl *AppsAsCodeServiceImpl) DeleteSource(id int, userId int32) error {
+	source, err := impl.getSource(id)
+	if err != nil {
+		return err
+	}
+	source.Active = false
+	source.UpdateAuditLog(userId)
+	err = impl.appsAsCodeRepository.UpdateSource(source)
+	if err != nil {
+		impl.logger.Errorw("error in deleting apps as code source", "sourceId", id, "err", err)
+		return err
+	}
+	err = impl.appsAsCodeRepository.DeactivateManagedAppsBySourceId(id, userId)
+	if err != nil {
+		impl.logger.Errorw("error in releasing apps of deleted apps as code source", "sourceId", id, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *AppsAsCodeServiceImpl) getSource(id int) (*repository.AppCodeSource, error) {
+	source, err := impl.appsAsCodeRepository.FindActiveSourceById(id)
+	if err == pg.ErrNoRows {
+		return nil, util.NewApiError(http.StatusNotFound, bean.SourceNotFoundMessage, bean.SourceNotFoundMessage)
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching apps as code source", "sourceId", id, "err", err)
+		return nil, err
+	}
+	return source, nil
+}
+
+func (impl *AppsAsCodeServiceImpl) Plan(ctx context.Context, sourceId int, token string) (*bean.ReconcilePlan, error) {
+	source, err := impl.getSource(sourceId)
+	if err != nil {
+		return nil, err
+	}
+	plan, _, err := impl.buildPlan(ctx, source, token)
+	return plan, err
+}
+
+// buildPlan returns the plan of the source along with its managed apps keyed by app name
+func (impl *AppsAsCodeServiceImpl) buildPlan(ctx context.Context, source *repository.AppCodeSource, token string) (*bean.ReconcilePlan, map[string]*repository.AppCodeManagedApp, error) {
+	definitions, definitionErrors, err := impl.fetchDefinitions(source)
+	if err != nil {
+		return nil, nil, err
+	}
+	managedApps, err := impl.appsAsCodeRepository.FindActiveManagedAppsBySourceId(source.Id)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching apps managed by source", "sourceId", source.Id, "err", err)
+		return nil, nil, err
+	}
+	managedAppByName := make(map[string]*repository.AppCodeManagedApp, len(managedApps))
+	for _, managedApp := range managedApps {
+		managedAppByName[managedApp.AppName] = managedApp
+	}
+	plan := &bean.ReconcilePlan{
+		SourceId:    source.Id,
+		SourceName:  source.Name,
+		Apps:        make([]*bean.AppPlan, 0, len(definitions)),
+		Errors:      definitionErrors,
+		GeneratedOn: time.Now(),
+	}
+	appNames := make([]string, 0, len(definitions))
+	for appName := range definitions {
+		appNames = append(appNames, appName)
+	}
+	sort.Strings(appNames)
+	for _, appName := range appNames {
+		appPlan, err := impl.planApp(ctx, source, definitions[appName], token)
+		if err != nil {
+			return nil, nil, err
+		}
+		plan.Apps = append(plan.Apps, appPlan)
+	}
+	for _, managedApp := range managedApps {
+		if _, found := definitions[managedApp.AppName]; found {
+			continue
+		}
+		appPlan := &bean.AppPlan{
+			AppName:  managedApp.AppName,
+			AppId:    managedApp.AppId,
+			FilePath: managedApp.FilePath,
+			Action:   bean.ChangeActionDelete,
+			Changes:  []*bean.SectionChange{newSectionChange(bean.SectionApp, "", "", bean.ChangeActionDelete)},
+		}
+		if !source.Prune {
+			appPlan.Skipped = bean.PruneDisabledReason
+		}
+		plan.Apps = append(plan.Apps, appPlan)
+	}
+	return plan, managedAppByName, nil
+}
+
+func (impl *AppsAsCodeServiceImpl) planApp(ctx context.Context, source *repository.AppCodeSource, definition *appDefinition, token string) (*bean.AppPlan, error) {
+	appName := definition.detail.Metadata.AppName
+	appPlan := &bean.AppPlan{
+		AppName:  appName,
+		FilePath: definition.filePath,
+		Desired:  definition.detail,
+	}
+	app, err := impl.appRepository.FindActiveByName(appName)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching app", "appName", appName, "err", err)
+		return nil, err
+	}
+	if err == pg.ErrNoRows || app == nil || app.Id == 0 {
+		appPlan.Action = bean.ChangeActionCreate
+		appPlan.Changes = []*bean.SectionChange{newSectionChange(bean.SectionApp, "", "", bean.ChangeActionCreate)}
+		return appPlan, nil
+	}
+	appPlan.AppId = app.Id
+	managedApp, err := impl.appsAsCodeRepository.FindActiveManagedAppByAppId(app.Id)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching managed app", "appId", app.Id, "err", err)
+		return nil, err
+	}
+	if err == nil && managedApp.SourceId != source.Id {
+		appPlan.Action = bean.ChangeActionNone
+		appPlan.Skipped = bean.AppOwnedBySourceReason
+		return appPlan, nil
+	}
+	current, err := impl.appConfigurationManager.GetAppConfiguration(ctx, app.Id, token)
+	if err != nil {
+		impl.logger.Errorw("error in fetching current app configuration", "appId", app.Id, "err", err)
+		appPlan.Action = bean.ChangeActionNone
+		appPlan.Error = err.Error()
+		return appPlan, nil
+	}
+	appPlan.Changes = diffAppDetail(definition.detail, current)
+	appPlan.Action = bean.ChangeActionNone
+	if len(appPlan.Changes) > 0 {
+		appPlan.Action = bean.ChangeActionUpdate
+	}
+	return appPlan, nil
+}
+
+func (impl *AppsAsCodeServiceImpl) Apply(ctx context.Context, sourceId int, userId int32, token string) (*bean.ReconcilePlan, error) {
+	source, err := impl.getSource(sourceId)
+	if err != nil {
+		return nil, err
+	}
+	plan, managedAppByName, err := impl.buildPlan(ctx, source, token)
+	if err != nil {
+		return nil, err
+	}
+	for _, appPlan := range plan.Apps {
+		if len(appPlan.Skipped) > 0 || len(appPlan.Error) > 0 {
+			continue
+		}
+		err = impl.applyAppPlan(ctx, appPlan, userId, token)
+		if err != nil {
+			impl.logger.Errorw("error in applying app definition", "sourceId", sourceId, "appName", appPlan.AppName, "err", err)
+			appPlan.Error = err.Error()
+		}
+		if appPlan.AppId == 0 {
+			continue
+		}
+		err = impl.saveManagedApp(source.Id, appPlan, managedAppByName[appPlan.AppName], userId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	now := time.Now()
+	source.LastAppliedOn = &now
+	source.UpdateAuditLog(userId)
+	err = impl.appsAsCodeRepository.UpdateSource(source)
+	if err != nil {
+		impl.logger.Errorw("error in updating apps as code source", "sourceId", sourceId, "err", err)
+		return nil, err
+	}
+	return plan, nil
+}
+
+// applyAppPlan runs deletions first in reverse section order, so that workflows go before the git materials
+// they build from, and then creations and updates in section order
+func (impl *AppsAsCodeServiceImpl) applyAppPlan(ctx context.Context, appPlan *bean.AppPlan, userId int32, token string) error {
+	switch appPlan.Action {
+	case bean.ChangeActionCreate:
+		appId, err := impl.appConfigurationManager.CreateAppFromConfiguration(ctx, appPlan.Desired, userId, token)
+		if err != nil {
+			return err
+		}
+		appPlan.AppId = appId
+		appPlan.Changes[0].Applied = true
+		return nil
+	case bean.ChangeActionDelete:
+		err := impl.appConfigurationManager.DeleteApp(ctx, appPlan.AppId, userId)
+		if err != nil {
+			return err
+		}
+		appPlan.Changes[0].Applied = true
+		return nil
+	}
+	for i := len(appPlan.Changes) - 1; i >= 0; i-- {
+		change := appPlan.Changes[i]
+		if change.Action == bean.ChangeActionDelete && change.Supported {
+			err := impl.appConfigurationManager.ApplySectionChange(ctx, appPlan.AppId, appPlan.Desired, change, userId)
+			if err != nil {
+				return err
+			}
+			change.Applied = true
+		}
+	}
+	for _, change := range appPlan.Changes {
+		if change.Action != bean.ChangeActionDelete && change.Supported {
+			err := impl.appConfigurationManager.ApplySectionChange(ctx, appPlan.AppId, appPlan.Desired, change, userId)
+			if err != nil {
+				return err
+			}
+			change.Applied = true
+		}
+	}
+	return nil
+}
+
+// saveManagedApp records the outcome of an applied app plan, changes which were not applied are left as drift
+func (impl *AppsAsCodeServiceImpl) saveManagedApp(sourceId int, appPlan *bean.AppPlan, managedApp *repository.AppCodeManagedApp, userId int32) error {
+	if managedApp == nil {
+		managedApp = &repository.AppCodeManagedApp{
+			SourceId: sourceId,
+			AppId:    appPlan.AppId,
+			AppName:  appPlan.AppName,
+			Active:   true,
+			AuditLog: sql.NewDefaultAuditLog(userId),
+		}
+	}
+	var pendingChanges []*bean.SectionChange
+	for _, change := range appPlan.Changes {
+		if !change.Applied {
+			pendingChanges = append(pendingChanges, change)
+		}
+	}
+	now := time.Now()
+	if len(appPlan.FilePath) > 0 {
+		managedApp.FilePath = appPlan.FilePath
+	}
+	managedApp.Drifted = len(pendingChanges) > 0
+	managedApp.DriftedSections = getDriftedSections(pendingChanges)
+	managedApp.LastAppliedOn = &now
+	managedApp.LastCheckedOn = &now
+	if appPlan.Action == bean.ChangeActionDelete && len(appPlan.Error) == 0 {
+		managedApp.Active = false
+	}
+	managedApp.UpdateAuditLog(userId)
+	var err error
+	if managedApp.Id > 0 {
+		err = impl.appsAsCodeRepository.UpdateManagedApp(managedApp)
+	} else {
+		err = impl.appsAsCodeRepository.SaveManagedApp(managedApp)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving managed app", "sourceId", sourceId, "appId", appPlan.AppId, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *AppsAsCodeServiceImpl) GetDriftReport(ctx context.Context, sourceId int, token string) (*bean.DriftReportDto, error) {
+	source, err := impl.getSource(sourceId)
+	if err != nil {
+		return nil, err
+	}
+	plan, managedAppByName, err := impl.buildPlan(ctx, source, token)
+	if err != nil {
+		return nil, err
+	}
+	report := &bean.DriftReportDto{
+		SourceId:    source.Id,
+		SourceName:  source.Name,
+		Apps:        make([]*bean.AppCodeStatusDto, 0, len(plan.Apps)),
+		Errors:      plan.Errors,
+		GeneratedOn: plan.GeneratedOn,
+	}
+	for _, appPlan := range plan.Apps {
+		if len(appPlan.Skipped) > 0 && appPlan.Action != bean.ChangeActionDelete {
+			continue
+		}
+		status := &bean.AppCodeStatusDto{
+			AppId:           appPlan.AppId,
+			AppName:         appPlan.AppName,
+			SourceId:        source.Id,
+			SourceName:      source.Name,
+			FilePath:        appPlan.FilePath,
+			Drifted:         len(appPlan.Changes) > 0,
+			DriftedSections: getDriftedSections(appPlan.Changes),
+			LastCheckedOn:   &plan.GeneratedOn,
+		}
+		if managedApp, found := managedAppByName[appPlan.AppName]; found && len(appPlan.Error) == 0 {
+			status.Managed = true
+			status.LastAppliedOn = managedApp.LastAppliedOn
+			managedApp.Drifted = status.Drifted
+			managedApp.DriftedSections = status.DriftedSections
+			managedApp.LastCheckedOn = &plan.GeneratedOn
+			err = impl.appsAsCodeRepository.UpdateManagedApp(managedApp)
+			if err != nil {
+				impl.logger.Errorw("error in updating drift of managed app", "appId", managedApp.AppId, "err", err)
+				return nil, err
+			}
+		}
+		status.Locked = status.Managed && source.LockUiEdits
+		report.Apps = append(report.Apps, status)
+	}
+	return report, nil
+}
+
+func (impl *AppsAsCodeServiceImpl) GetAppStatus(appId int) (*bean.AppCodeStatusDto, error) {
+	status := &bean.AppCodeStatusDto{AppId: appId}
+	managedApp, err := impl.appsAsCodeRepository.FindActiveManagedAppByAppId(appId)
+	if err == pg.ErrNoRows {
+		return status, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching managed app", "appId", appId, "err", err)
+		return nil, err
+	}
+	source, err := impl.appsAsCodeRepository.FindActiveSourceById(managedApp.SourceId)
+	if err == pg.ErrNoRows {
+		return status, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching apps as code source", "sourceId", managedApp.SourceId, "err", err)
+		return nil, err
+	}
+	status.AppName = managedApp.AppName
+	status.Managed = true
+	status.SourceId = source.Id
+	status.SourceName = source.Name
+	status.FilePath = managedApp.FilePath
+	status.Locked = source.LockUiEdits
+	status.Drifted = managedApp.Drifted
+	status.DriftedSections = managedApp.DriftedSections
+	status.LastAppliedOn = managedApp.LastAppliedOn
+	status.LastCheckedOn = managedApp.LastCheckedOn
+	return status, nil
+}
+
+func toSourceDto(source *repository.AppCodeSource) *bean.AppCodeSourceDto {
+	return &bean.AppCodeSourceDto{
+		Id:            source.Id,
+		Name:          source.Name,
+		GitProviderId: source.GitProviderId,
+		RepoUrl:       source.RepoUrl,
+		Branch:        source.Branch,
+		Path:          source.Path,
+		LockUiEdits:   source.LockUiEdits,
+		Prune:         source.Prune,
+		LastAppliedOn: source.LastAppliedOn,
+	}
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	"time"
+)
+
+type ChangeAction string
+
+const (
+	ChangeActionCreate ChangeAction = "create"
+	ChangeActionUpdate ChangeAction = "update"
+	ChangeActionDelete ChangeAction = "delete"
+	ChangeActionNone   ChangeAction = "none"
+)
+
+// sections of an app definition, named after the json keys of appBean.AppDetail
+const (
+	SectionApp                      = "app"
+	SectionMetadata                 = "metadata"
+	SectionGitMaterials             = "gitMaterials"
+	SectionDockerConfig             = "dockerConfig"
+	SectionGlobalDeploymentTemplate = "globalDeploymentTemplate"
+	SectionWorkflows                = "workflows"
+	SectionGlobalConfigMaps         = "globalConfigMaps"
+	SectionGlobalSecrets            = "globalSecrets"
+	SectionEnvDeploymentTemplate    = "environmentOverride.deploymentTemplate"
+	SectionEnvConfigMaps            = "environmentOverride.configMaps"
+	SectionEnvSecrets               = "environmentOverride.secrets"
+)
+
+const (
+	AppLockedMessage            = "app configuration is reconciled from git, edit the definition in the repository instead"
+	SourceNotFoundMessage       = "apps as code source not found"
+	SshAuthNotSupportedMessage  = "ssh git providers are not supported for apps as code sources"
+	ChartChangeManualReason     = "chart change of the deployment template has to be done from the UI"
+	WorkflowChangeManualReason  = "pipelines of an existing workflow have to be changed from the UI"
+	OverrideRemovalManualReason = "deleting an environment override has to be done from the UI"
+	AppOwnedBySourceReason      = "app is reconciled from another source"
+	PruneDisabledReason         = "app definition removed from the repository, enable prune on the source to delete the app"
+)
+
+type AppCodeSourceDto struct {
+	Id            int    `json:"id"`
+	Name          string `json:"name" validate:"required,max=100"`
+	GitProviderId int    `json:"gitProviderId" validate:"required,number,gt=0"`
+	RepoUrl       string `json:"repoUrl" validate:"required"`
+	Branch        string `json:"branch,omitempty"`
+	// Path is the directory of the repository holding one app definition per yaml or json file
+	Path        string `json:"path,omitempty"`
+	LockUiEdits bool   `json:"lockUiEdits"`
+	// Prune deletes the reconciled apps whose definition is removed from the repository
+	Prune         bool       `json:"prune"`
+	LastAppliedOn *time.Time `json:"lastAppliedOn,omitempty"`
+	UserId        int32      `json:"-"`
+}
+
+type SectionChange struct {
+	Section string `json:"section"`
+	// Key identifies the item of list sections, e.g. the git material checkout path or the config map name
+	Key         string       `json:"key,omitempty"`
+	Environment string       `json:"environment,omitempty"`
+	Action      ChangeAction `json:"action"`
+	// Supported is false for changes that are only reported and have to be made from the UI
+	Supported bool   `json:"supported"`
+	Reason    string `json:"reason,omitempty"`
+	Applied   bool   `json:"applied,omitempty"`
+}
+
+type AppPlan struct {
+	AppName  string           `json:"appName"`
+	AppId    int              `json:"appId,omitempty"`
+	FilePath string           `json:"filePath,omitempty"`
+	Action   ChangeAction     `json:"action"`
+	Changes  []*SectionChange `json:"changes,omitempty"`
+	Skipped  string           `json:"skipped,omitempty"`
+	Error    string           `json:"error,omitempty"`
+
+	Desired *appBean.AppDetail `json:"-"`
+}
+
+type DefinitionError struct {
+	FilePath string `json:"filePath"`
+	Error    string `json:"error"`
+}
+
+type ReconcilePlan struct {
+	SourceId    int                `json:"sourceId"`
+	SourceName  string             `json:"sourceName"`
+	Apps        []*AppPlan         `json:"apps"`
+	Errors      []*DefinitionError `json:"errors,omitempty"`
+	GeneratedOn time.Time          `json:"generatedOn"`
+}
+
+type AppCodeStatusDto struct {
+	AppId           int        `json:"appId"`
+	AppName         string     `json:"appName,omitempty"`
+	Managed         bool       `json:"managed"`
+	SourceId        int        `json:"sourceId,omitempty"`
+	SourceName      string     `json:"sourceName,omitempty"`
+	FilePath        string     `json:"filePath,omitempty"`
+	Locked          bool       `json:"locked"`
+	Drifted         bool       `json:"drifted"`
+	DriftedSections []string   `json:"driftedSections,omitempty"`
+	LastAppliedOn   *time.Time `json:"lastAppliedOn,omitempty"`
+	LastCheckedOn   *time.Time `json:"lastCheckedOn,omitempty"`
+}
+
+type DriftReportDto struct {
+	SourceId    int                 `json:"sourceId"`
+	SourceName  string              `json:"sourceName"`
+	Apps        []*AppCodeStatusDto `json:"apps"`
+	Errors      []*DefinitionError  `json:"errors,omitempty"`
+	GeneratedOn time.Time           `json:"generatedOn"`
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appsAsCode
+
+import (
+	"fmt"
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/internal/sql/constants"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/appsAsCode/bean"
+	"github.com/devtron-labs/devtron/pkg/appsAsCode/repository"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/git"
+	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/git/commandManager"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sigs.k8s.io/yaml"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type appDefinition struct {
+	filePath string
+	detail   *appBean.AppDetail
+}
+
+func isDefinitionFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// fetchDefinitions clones the source repository and reads every app definition under the source path, files
+// which can't be read are returned as definition errors so that the other apps are still reconciled
+func (impl *AppsAsCodeServiceImpl) fetchDefinitions(source *repository.AppCodeSource) (map[string]*appDefinition, []*bean.DefinitionError, error) {
+	gitProvider, err := impl.gitProviderRepository.FindOne(strconv.Itoa(source.GitProviderId))
+	if err != nil {
+		impl.logger.Errorw("error in fetching git provider of apps as code source", "sourceId", source.Id, "err", err)
+		return nil, nil, err
+	}
+	if gitProvider.AuthMode == constants.AUTH_MODE_SSH {
+		return nil, nil, util.NewApiError(http.StatusBadRequest, bean.SshAuthNotSupportedMessage, bean.SshAuthNotSupportedMessage)
+	}
+	auth := &commandManager.BasicAuth{Username: gitProvider.UserName, Password: gitProvider.Password}
+	if gitProvider.AuthMode == constants.AUTH_MODE_ACCESS_TOKEN {
+		auth.Password = gitProvider.AccessToken
+	}
+	tlsConfig := &apiBean.TLSConfig{
+		CaData:      gitProvider.CaCert,
+		TLSCertData: gitProvider.TlsCert,
+		TLSKeyData:  gitProvider.TlsKey,
+	}
+	gitOpsHelper := git.NewGitOpsHelperImpl(auth, impl.logger, tlsConfig, gitProvider.EnableTLSVerification)
+	targetDir := fmt.Sprintf("apps-as-code/%d-%d", source.Id, time.Now().UnixNano())
+	defer os.RemoveAll(gitOpsHelper.GetCloneDirectory(targetDir))
+	clonedDir, err := gitOpsHelper.Clone(source.RepoUrl, targetDir, source.Branch)
+	if err != nil {
+		impl.logger.Errorw("error in cloning apps as code repository", "sourceId", source.Id, "repoUrl", source.RepoUrl, "err", err)
+		return nil, nil, err
+	}
+	definitionsDir := filepath.Join(clonedDir, filepath.Clean("/"+source.Path))
+	definitions := make(map[string]*appDefinition)
+	var definitionErrors []*bean.DefinitionError
+	err = filepath.Walk(definitionsDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isDefinitionFile(path) {
+			return nil
+		}
+		relativePath, _ := filepath.Rel(clonedDir, path)
+		definition, err := readDefinition(path)
+		if err == nil {
+			if existing, found := definitions[definition.Metadata.AppName]; found {
+				err = fmt.Errorf("app %s is already defined in %s", definition.Metadata.AppName, existing.filePath)
+			}
+		}
+		if err != nil {
+			definitionErrors = append(definitionErrors, &bean.DefinitionError{FilePath: relativePath, Error: err.Error()})
+			return nil
+		}
+		definitions[definition.Metadata.AppName] = &appDefinition{filePath: relativePath, detail: definition}
+		return nil
+	})
+	if err != nil {
+		impl.logger.Errorw("error in reading apps as code definitions", "sourceId", source.Id, "path", source.Path, "err", err)
+		return nil, nil, err
+	}
+	return definitions, definitionErrors, nil
+}
+
+func readDefinition(path string) (*appBean.AppDetail, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	definition := &appBean.AppDetail{}
+	err = yaml.Unmarshal(data, definition)
+	if err != nil {
+		return nil, err
+	}
+	if definition.Metadata == nil || len(definition.Metadata.AppName) == 0 {
+		return nil, fmt.Errorf("metadata.appName is required")
+	}
+	return definition, nil
+}
@@ -0,0 +1,221 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appsAsCode
+
+import (
+	"encoding/json"
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	"github.com/devtron-labs/devtron/pkg/appsAsCode/bean"
+	"reflect"
+	"sort"
+)
+
+// isSubset compares the json form of desired and current, null and empty string values of desired are left
+// unmanaged so that a definition only has to hold the fields it wants to control
+func isSubset(desired, current interface{}) bool {
+	desiredValue, err := toJsonValue(desired)
+	if err != nil {
+		return false
+	}
+	currentValue, err := toJsonValue(current)
+	if err != nil {
+		return false
+	}
+	return isJsonSubset(desiredValue, currentValue)
+}
+
+func toJsonValue(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var jsonValue interface{}
+	err = json.Unmarshal(data, &jsonValue)
+	return jsonValue, err
+}
+
+func isJsonSubset(desired, current interface{}) bool {
+	switch desiredValue := desired.(type) {
+	case nil:
+		return true
+	case string:
+		if len(desiredValue) == 0 {
+			return true
+		}
+	case map[string]interface{}:
+		currentValue, ok := current.(map[string]interface{})
+		if !ok {
+			return len(desiredValue) == 0 && current == nil
+		}
+		for key, value := range desiredValue {
+			if !isJsonSubset(value, currentValue[key]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		currentValue, ok := current.([]interface{})
+		if !ok {
+			return len(desiredValue) == 0 && current == nil
+		}
+		if len(desiredValue) != len(currentValue) {
+			return false
+		}
+		for i := range desiredValue {
+			if !isJsonSubset(desiredValue[i], currentValue[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(desired, current)
+}
+
+// diffKeyedItems diffs list sections item by item, a nil desired list leaves the section unmanaged
+func diffKeyedItems[T any](section, environment string, desired, current []T, keyOf func(T) string) []*bean.SectionChange {
+	if desired == nil {
+		return nil
+	}
+	var changes []*bean.SectionChange
+	currentByKey := make(map[string]T, len(current))
+	for _, item := range current {
+		currentByKey[keyOf(item)] = item
+	}
+	desiredKeys := make(map[string]bool, len(desired))
+	for _, item := range desired {
+		key := keyOf(item)
+		desiredKeys[key] = true
+		currentItem, found := currentByKey[key]
+		if !found {
+			changes = append(changes, newSectionChange(section, key, environment, bean.ChangeActionCreate))
+		} else if !isSubset(item, currentItem) {
+			changes = append(changes, newSectionChange(section, key, environment, bean.ChangeActionUpdate))
+		}
+	}
+	for _, item := range current {
+		if key := keyOf(item); !desiredKeys[key] {
+			changes = append(changes, newSectionChange(section, key, environment, bean.ChangeActionDelete))
+		}
+	}
+	return changes
+}
+
+func newSectionChange(section, key, environment string, action bean.ChangeAction) *bean.SectionChange {
+	return &bean.SectionChange{
+		Section:     section,
+		Key:         key,
+		Environment: environment,
+		Action:      action,
+		Supported:   true,
+	}
+}
+
+func markManual(change *bean.SectionChange, reason string) *bean.SectionChange {
+	change.Supported = false
+	change.Reason = reason
+	return change
+}
+
+// diffAppDetail lists the changes needed to bring the current configuration of an app to the desired one
+func diffAppDetail(desired, current *appBean.AppDetail) []*bean.SectionChange {
+	var changes []*bean.SectionChange
+	if desired.Metadata != nil && current.Metadata != nil && !isSubset(desired.Metadata, current.Metadata) {
+		changes = append(changes, newSectionChange(bean.SectionMetadata, "", "", bean.ChangeActionUpdate))
+	}
+	changes = append(changes, diffKeyedItems(bean.SectionGitMaterials, "", desired.GitMaterials, current.GitMaterials,
+		func(material *appBean.GitMaterial) string { return material.CheckoutPath })...)
+	if desired.DockerConfig != nil {
+		if current.DockerConfig == nil {
+			changes = append(changes, newSectionChange(bean.SectionDockerConfig, "", "", bean.ChangeActionCreate))
+		} else if !isSubset(desired.DockerConfig, current.DockerConfig) {
+			changes = append(changes, newSectionChange(bean.SectionDockerConfig, "", "", bean.ChangeActionUpdate))
+		}
+	}
+	if desired.GlobalDeploymentTemplate != nil {
+		if current.GlobalDeploymentTemplate == nil {
+			changes = append(changes, newSectionChange(bean.SectionGlobalDeploymentTemplate, "", "", bean.ChangeActionCreate))
+		} else if desired.GlobalDeploymentTemplate.ChartRefId != current.GlobalDeploymentTemplate.ChartRefId {
+			changes = append(changes, markManual(newSectionChange(bean.SectionGlobalDeploymentTemplate, "", "", bean.ChangeActionUpdate),
+				bean.ChartChangeManualReason))
+		} else if !isSubset(desired.GlobalDeploymentTemplate, current.GlobalDeploymentTemplate) {
+			changes = append(changes, newSectionChange(bean.SectionGlobalDeploymentTemplate, "", "", bean.ChangeActionUpdate))
+		}
+	}
+	for _, change := range diffKeyedItems(bean.SectionWorkflows, "", desired.AppWorkflows, current.AppWorkflows,
+		func(workflow *appBean.AppWorkflow) string { return workflow.Name }) {
+		if change.Action == bean.ChangeActionUpdate {
+			markManual(change, bean.WorkflowChangeManualReason)
+		}
+		changes = append(changes, change)
+	}
+	changes = append(changes, diffKeyedItems(bean.SectionGlobalConfigMaps, "", desired.GlobalConfigMaps, current.GlobalConfigMaps,
+		func(configMap *appBean.ConfigMap) string { return configMap.Name })...)
+	changes = append(changes, diffKeyedItems(bean.SectionGlobalSecrets, "", desired.GlobalSecrets, current.GlobalSecrets,
+		func(secret *appBean.Secret) string { return secret.Name })...)
+	changes = append(changes, diffEnvironmentOverrides(desired.EnvironmentOverrides, current.EnvironmentOverrides)...)
+	return changes
+}
+
+// diffEnvironmentOverrides only looks at the environments present in the desired definition
+func diffEnvironmentOverrides(desired, current map[string]*appBean.EnvironmentOverride) []*bean.SectionChange {
+	envNames := make([]string, 0, len(desired))
+	for envName := range desired {
+		envNames = append(envNames, envName)
+	}
+	sort.Strings(envNames)
+	var changes []*bean.SectionChange
+	for _, envName := range envNames {
+		desiredOverride := desired[envName]
+		if desiredOverride == nil {
+			continue
+		}
+		currentOverride := current[envName]
+		if currentOverride == nil {
+			currentOverride = &appBean.EnvironmentOverride{}
+		}
+		if desiredTemplate := desiredOverride.DeploymentTemplate; desiredTemplate != nil {
+			currentTemplate := currentOverride.DeploymentTemplate
+			currentIsOverride := currentTemplate != nil && currentTemplate.IsOverride
+			if desiredTemplate.IsOverride && !currentIsOverride {
+				changes = append(changes, newSectionChange(bean.SectionEnvDeploymentTemplate, "", envName, bean.ChangeActionCreate))
+			} else if desiredTemplate.IsOverride && !isSubset(desiredTemplate, currentTemplate) {
+				changes = append(changes, newSectionChange(bean.SectionEnvDeploymentTemplate, "", envName, bean.ChangeActionUpdate))
+			} else if !desiredTemplate.IsOverride && currentIsOverride {
+				changes = append(changes, markManual(newSectionChange(bean.SectionEnvDeploymentTemplate, "", envName, bean.ChangeActionDelete),
+					bean.OverrideRemovalManualReason))
+			}
+		}
+		changes = append(changes, diffKeyedItems(bean.SectionEnvConfigMaps, envName, desiredOverride.ConfigMaps, currentOverride.ConfigMaps,
+			func(configMap *appBean.ConfigMap) string { return configMap.Name })...)
+		changes = append(changes, diffKeyedItems(bean.SectionEnvSecrets, envName, desiredOverride.Secrets, currentOverride.Secrets,
+			func(secret *appBean.Secret) string { return secret.Name })...)
+	}
+	return changes
+}
+
+// getDriftedSections returns the distinct sections of the changes in the order they appear
+func getDriftedSections(changes []*bean.SectionChange) []string {
+	var sections []string
+	seen := make(map[string]bool)
+	for _, change := range changes {
+		if !seen[change.Section] {
+			seen[change.Section] = true
+			sections = append(sections, change.Section)
+		}
+	}
+	return sections
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appsAsCode
+
+import (
+	appBean "github.com/devtron-labs/devtron/api/appbean"
+	"github.com/devtron-labs/devtron/pkg/appsAsCode/bean"
+	"testing"
+)
+
+func TestIsSubset(t *testing.T) {
+	tests := []struct {
+		name    string
+		desired interface{}
+		current interface{}
+		want    bool
+	}{
+		{
+			name:    "fields missing from desired are ignored",
+			desired: map[string]interface{}{"replicaCount": 2},
+			current: map[string]interface{}{"replicaCount": 2, "image": map[string]interface{}{"pullPolicy": "IfNotPresent"}},
+			want:    true,
+		},
+		{
+			name:    "null and empty string are unmanaged",
+			desired: map[string]interface{}{"service": nil, "name": ""},
+			current: map[string]interface{}{"service": map[string]interface{}{"type": "ClusterIP"}, "name": "app"},
+			want:    true,
+		},
+		{
+			name:    "changed nested value",
+			desired: map[string]interface{}{"resources": map[string]interface{}{"limits": map[string]interface{}{"cpu": "1"}}},
+			current: map[string]interface{}{"resources": map[string]interface{}{"limits": map[string]interface{}{"cpu": "2"}}},
+			want:    false,
+		},
+		{
+			name:    "lists compare by length and position",
+			desired: map[string]interface{}{"ports": []interface{}{8080}},
+			current: map[string]interface{}{"ports": []interface{}{8080, 9090}},
+			want:    false,
+		},
+		{
+			name:    "field missing from current",
+			desired: map[string]interface{}{"replicaCount": 2},
+			current: map[string]interface{}{},
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSubset(tt.desired, tt.current); got != tt.want {
+				t.Errorf("isSubset() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffAppDetail(t *testing.T) {
+	current := &appBean.AppDetail{
+		Metadata: &appBean.AppMetadata{AppName: "app", ProjectName: "team"},
+		GitMaterials: []*appBean.GitMaterial{
+			{GitRepoUrl: "https://github.com/org/app.git", CheckoutPath: "./"},
+			{GitRepoUrl: "https://github.com/org/lib.git", CheckoutPath: "./lib"},
+		},
+		GlobalDeploymentTemplate: &appBean.DeploymentTemplate{ChartRefId: 10, Template: map[string]interface{}{"replicaCount": 1}},
+		AppWorkflows:             []*appBean.AppWorkflow{{Name: "wf-1", CiPipeline: &appBean.CiPipelineDetails{Name: "ci"}}},
+		GlobalConfigMaps:         []*appBean.ConfigMap{{Name: "cm-1", Data: map[string]interface{}{"key": "value"}}},
+		EnvironmentOverrides: map[string]*appBean.EnvironmentOverride{
+			"prod": {DeploymentTemplate: &appBean.DeploymentTemplate{ChartRefId: 10, IsOverride: true, Template: map[string]interface{}{"replicaCount": 3}}},
+		},
+	}
+	desired := &appBean.AppDetail{
+		Metadata: &appBean.AppMetadata{AppName: "app", ProjectName: "team"},
+		GitMaterials: []*appBean.GitMaterial{
+			{GitRepoUrl: "https://github.com/org/app.git", CheckoutPath: "./"},
+		},
+		GlobalDeploymentTemplate: &appBean.DeploymentTemplate{ChartRefId: 10, Template: map[string]interface{}{"replicaCount": 2}},
+		AppWorkflows:             []*appBean.AppWorkflow{{Name: "wf-1", CiPipeline: &appBean.CiPipelineDetails{Name: "ci", IsManual: true}}},
+		GlobalConfigMaps:         []*appBean.ConfigMap{{Name: "cm-2", Data: map[string]interface{}{"key": "value"}}},
+		EnvironmentOverrides: map[string]*appBean.EnvironmentOverride{
+			"prod": {DeploymentTemplate: &appBean.DeploymentTemplate{ChartRefId: 10, IsOverride: false}},
+		},
+	}
+	want := []bean.SectionChange{
+		{Section: bean.SectionGitMaterials, Key: "./lib", Action: bean.ChangeActionDelete, Supported: true},
+		{Section: bean.SectionGlobalDeploymentTemplate, Action: bean.ChangeActionUpdate, Supported: true},
+		{Section: bean.SectionWorkflows, Key: "wf-1", Action: bean.ChangeActionUpdate, Reason: bean.WorkflowChangeManualReason},
+		{Section: bean.SectionGlobalConfigMaps, Key: "cm-2", Action: bean.ChangeActionCreate, Supported: true},
+		{Section: bean.SectionGlobalConfigMaps, Key: "cm-1", Action: bean.ChangeActionDelete, Supported: true},
+		{Section: bean.SectionEnvDeploymentTemplate, Environment: "prod", Action: bean.ChangeActionDelete, Reason: bean.OverrideRemovalManualReason},
+	}
+	got := diffAppDetail(desired, current)
+	if len(got) != len(want) {
+		t.Fatalf("diffAppDetail() returned %d changes, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if *got[i] != want[i] {
+			t.Errorf("diffAppDetail() change %d = %+v, want %+v", i, *got[i], want[i])
+		}
+	}
+	if sections := getDriftedSections(got); len(sections) != 5 {
+		t.Errorf("getDriftedSections() = %v, want 5 sections", sections)
+	}
+
+	desired.GlobalDeploymentTemplate.ChartRefId = 11
+	for _, change := range diffAppDetail(desired, current) {
+		if change.Section == bean.SectionGlobalDeploymentTemplate && change.Supported {
+			t.Errorf("chart change of the deployment template should not be supported")
+		}
+	}
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package read
+
+import (
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/appsAsCode/bean"
+	"github.com/devtron-labs/devtron/pkg/appsAsCode/repository"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+type AppsAsCodeReadService interface {
+	// CheckAppEditable returns a 423 api error when the app configuration is locked to its git definition
+	CheckAppEditable(appId int) error
+}
+
+type AppsAsCodeReadServiceImpl struct {
+	logger               *zap.SugaredLogger
+	appsAsCodeRepository repository.AppsAsCodeRepository
+}
+
+func NewAppsAsCodeReadServiceImpl(logger *zap.SugaredLogger,
+	appsAsCodeRepository repository.AppsAsCodeRepository) *AppsAsCodeReadServiceImpl {
+	return &AppsAsCodeReadServiceImpl{
+		logger:               logger,
+		appsAsCodeRepository: appsAsCodeRepository,
+	}
+}
+
+func (impl *AppsAsCodeReadServiceImpl) CheckAppEditable(appId int) error {
+	if appId == 0 {
+		return nil
+	}
+	locked, err := impl.appsAsCodeRepository.IsAppLocked(appId)
+	if err != nil {
+		impl.logger.Errorw("error in checking apps as code lock", "appId", appId, "err", err)
+		return err
+	}
+	if locked {
+		return util.DefaultApiError().
+			WithHttpStatusCode(http.StatusLocked).
+			WithInternalMessage(bean.AppLockedMessage).
+			WithUserMessage(bean.AppLockedMessage)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type AppCodeSource struct {
+	tableName     struct{}   `sql:"app_code_source" pg:",discard_unknown_columns"`
+	Id            int        `sql:"id,pk"`
+	Name          string     `sql:"name,notnull"`
+	GitProviderId int        `sql:"git_provider_id,notnull"`
+	RepoUrl       string     `sql:"repo_url,notnull"`
+	Branch        string     `sql:"branch"`
+	Path          string     `sql:"path"`
+	LockUiEdits   bool       `sql:"lock_ui_edits,notnull"`
+	Prune         bool       `sql:"prune,notnull"`
+	LastAppliedOn *time.Time `sql:"last_applied_on"`
+	Active        bool       `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+// AppCodeManagedApp links an app to the source it is reconciled from, an app is reconciled from a single source
+type AppCodeManagedApp struct {
+	tableName       struct{}   `sql:"app_code_managed_app" pg:",discard_unknown_columns"`
+	Id              int        `sql:"id,pk"`
+	SourceId        int        `sql:"source_id,notnull"`
+	AppId           int        `sql:"app_id,notnull"`
+	AppName         string     `sql:"app_name,notnull"`
+	FilePath        string     `sql:"file_path"`
+	Drifted         bool       `sql:"drifted,notnull"`
+	DriftedSections []string   `sql:"drifted_sections" pg:",array"`
+	LastAppliedOn   *time.Time `sql:"last_applied_on"`
+	LastCheckedOn   *time.Time `sql:"last_checked_on"`
+	Active          bool       `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type AppsAsCodeRepository interface {
+	SaveSource(source *AppCodeSource) error
+	UpdateSource(source *AppCodeSource) error
+	FindActiveSourceById(id int) (*AppCodeSource, error)
+	FindAllActiveSources() ([]*AppCodeSource, error)
+	SaveManagedApp(managedApp *AppCodeManagedApp) error
+	UpdateManagedApp(managedApp *AppCodeManagedApp) error
+	FindActiveManagedAppsBySourceId(sourceId int) ([]*AppCodeManagedApp, error)
+	FindActiveManagedAppByAppId(appId int) (*AppCodeManagedApp, error)
+	DeactivateManagedAppsBySourceId(sourceId int, userId int32) error
+	// IsAppLocked is true when the app is reconciled from an active source locking edits from the UI
+	IsAppLocked(appId int) (bool, error)
+}
+
+type AppsAsCodeRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewAppsAsCodeRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *AppsAsCodeRepositoryImpl {
+	return &AppsAsCodeRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (repo *AppsAsCodeRepositoryImpl) SaveSource(source *AppCodeSource) error {
+	return repo.dbConnection.Insert(source)
+}
+
+func (repo *AppsAsCodeRepositoryImpl) UpdateSource(source *AppCodeSource) error {
+	return repo.dbConnection.Update(source)
+}
+
+func (repo *AppsAsCodeRepositoryImpl) FindActiveSourceById(id int) (*AppCodeSource, error) {
+	source := &AppCodeSource{}
+	err := repo.dbConnection.Model(source).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return source, err
+}
+
+func (repo *AppsAsCodeRepositoryImpl) FindAllActiveSources() ([]*AppCodeSource, error) {
+	var sources []*AppCodeSource
+	err := repo.dbConnection.Model(&sources).
+		Where("active = ?", true).
+		Order("id").
+		Select()
+	return sources, err
+}
+
+func (repo *AppsAsCodeRepositoryImpl) SaveManagedApp(managedApp *AppCodeManagedApp) error {
+	return repo.dbConnection.Insert(managedApp)
+}
+
+func (repo *AppsAsCodeRepositoryImpl) UpdateManagedApp(managedApp *AppCodeManagedApp) error {
+	return repo.dbConnection.Update(managedApp)
+}
+
+func (repo *AppsAsCodeRepositoryImpl) FindActiveManagedAppsBySourceId(sourceId int) ([]*AppCodeManagedApp, error) {
+	var managedApps []*AppCodeManagedApp
+	err := repo.dbConnection.Model(&managedApps).
+		Where("source_id = ?", sourceId).
+		Where("active = ?", true).
+		Order("app_name").
+		Select()
+	return managedApps, err
+}
+
+func (repo *AppsAsCodeRepositoryImpl) FindActiveManagedAppByAppId(appId int) (*AppCodeManagedApp, error) {
+	managedApp := &AppCodeManagedApp{}
+	err := repo.dbConnection.Model(managedApp).
+		Where("app_id = ?", appId).
+		Where("active = ?", true).
+		Limit(1).
+		Select()
+	return managedApp, err
+}
+
+func (repo *AppsAsCodeRepositoryImpl) DeactivateManagedAppsBySourceId(sourceId int, userId int32) error {
+	_, err := repo.dbConnection.Model((*AppCodeManagedApp)(nil)).
+		Set("active = ?", false).
+		Set("updated_on = ?", time.Now()).
+		Set("updated_by = ?", userId).
+		Where("source_id = ?", sourceId).
+		Where("active = ?", true).
+		Update()
+	return err
+}
+
+func (repo *AppsAsCodeRepositoryImpl) IsAppLocked(appId int) (bool, error) {
+	var count int
+	query := "SELECT count(*) FROM app_code_managed_app m" +
+		" INNER JOIN app_code_source s ON s.id = m.source_id AND s.active = true AND s.lock_ui_edits = true" +
+		" WHERE m.app_id = ? AND m.active = true;"
+	_, err := repo.dbConnection.Query(pg.Scan(&count), query, appId)
+	return count > 0, err
+}
@@ -0,0 +1,39 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package appsAsCode
+
+import (
+	"github.com/devtron-labs/devtron/pkg/appsAsCode/read"
+	"github.com/devtron-labs/devtron/pkg/appsAsCode/repository"
+	"github.com/google/wire"
+)
+
+// ReadWireSet provides the edit lock checks to apis that change app configuration
+var ReadWireSet = wire.NewSet(
+	repository.NewAppsAsCodeRepositoryImpl,
+	wire.Bind(new(repository.AppsAsCodeRepository), new(*repository.AppsAsCodeRepositoryImpl)),
+
+	read.NewAppsAsCodeReadServiceImpl,
+	wire.Bind(new(read.AppsAsCodeReadService), new(*read.AppsAsCodeReadServiceImpl)),
+)
+
+var WireSet = wire.NewSet(
+	ReadWireSet,
+
+	NewAppsAsCodeServiceImpl,
+	wire.Bind(new(AppsAsCodeService), new(*AppsAsCodeServiceImpl)),
+)
@@ -29,6 +29,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/argoApplication/helper"
 	"github.com/devtron-labs/devtron/pkg/argoApplication/read/config"
 	"github.com/devtron-labs/devtron/pkg/cluster/adapter"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterCredentials"
 	clusterRepository "github.com/devtron-labs/devtron/pkg/cluster/repository"
 	"github.com/devtron-labs/devtron/pkg/deployment/common"
 	commonBean "github.com/devtron-labs/devtron/pkg/deployment/common/bean"
@@ -49,6 +50,14 @@ type ArgoApplicationService interface {
 	//FUll mode
 	// ResourceTree	returns the status for all Apps deployed via ArgoCd
 	GetResourceTree(ctx context.Context, acdQueryRequest *bean.AcdClientQueryRequest) (*argoApplication.ResourceTreeResponse, error)
+	// SyncArgoApplication starts a manual sync of an external argoCd app
+	SyncArgoApplication(ctx context.Context, app *bean.ArgoAppIdentifier, request *bean.ArgoAppSyncRequest) error
+	// RefreshArgoApplication requests a normal or hard refresh of an external argoCd app
+	RefreshArgoApplication(ctx context.Context, app *bean.ArgoAppIdentifier, request *bean.ArgoAppRefreshRequest) error
+	// RollbackArgoApplication syncs an external argoCd app to a previous entry of its sync history
+	RollbackArgoApplication(ctx context.Context, app *bean.ArgoAppIdentifier, request *bean.ArgoAppRollbackRequest) error
+	// GetArgoApplicationDiff returns the live and desired state of the resources of an external argoCd app
+	GetArgoApplicationDiff(ctx context.Context, app *bean.ArgoAppIdentifier) (*bean.ArgoAppDiffResponse, error)
 }
 
 type ArgoApplicationServiceImpl struct {
@@ -60,6 +69,7 @@ type ArgoApplicationServiceImpl struct {
 	k8sApplicationService        application.K8sApplicationService
 	argoApplicationConfigService config.ArgoApplicationConfigService
 	deploymentConfigService      common.DeploymentConfigService
+	clusterCredentialService     clusterCredentials.ClusterCredentialService
 }
 
 func NewArgoApplicationServiceImpl(logger *zap.SugaredLogger,
@@ -69,7 +79,8 @@ func NewArgoApplicationServiceImpl(logger *zap.SugaredLogger,
 	helmAppService service.HelmAppService,
 	k8sApplicationService application.K8sApplicationService,
 	argoApplicationConfigService config.ArgoApplicationConfigService,
-	deploymentConfigService common.DeploymentConfigService) *ArgoApplicationServiceImpl {
+	deploymentConfigService common.DeploymentConfigService,
+	clusterCredentialService clusterCredentials.ClusterCredentialService) *ArgoApplicationServiceImpl {
 	return &ArgoApplicationServiceImpl{
 		logger:                       logger,
 		clusterRepository:            clusterRepository,
@@ -79,6 +90,7 @@ func NewArgoApplicationServiceImpl(logger *zap.SugaredLogger,
 		k8sApplicationService:        k8sApplicationService,
 		argoApplicationConfigService: argoApplicationConfigService,
 		deploymentConfigService:      deploymentConfigService,
+		clusterCredentialService:     clusterCredentialService,
 	}
 
 }
@@ -117,7 +129,11 @@ func (impl *ArgoApplicationServiceImpl) ListApplications(clusterIds []int) ([]*b
 			continue
 		}
 		clusterBean := adapter.GetClusterBean(clusterObj)
-		clusterConfig := clusterBean.GetClusterConfig()
+		clusterConfig, err := clusterBean.ResolveClusterConfig(impl.clusterCredentialService)
+		if err != nil {
+			impl.logger.Errorw("error in resolving cluster credentials", "err", err, "clusterId", clusterObj.Id)
+			return nil, err
+		}
 		restConfig, err := impl.k8sUtil.GetRestConfigByCluster(clusterConfig)
 		if err != nil {
 			impl.logger.Errorw("error in getting rest config by cluster Id", "err", err, "clusterId", clusterObj.Id)
@@ -202,7 +218,11 @@ func (impl *ArgoApplicationServiceImpl) HibernateArgoApplication(ctx context.Con
 		impl.logger.Errorw("HibernateArgoApplication", "error in getting the cluster config", err, "clusterId", app.ClusterId, "appName", app.AppName)
 		return nil, err
 	}
-	conf := helper.ConvertClusterBeanToGrpcConfig(clusterBean)
+	conf, err := helper.ConvertClusterBeanToGrpcConfig(clusterBean, impl.clusterCredentialService)
+	if err != nil {
+		impl.logger.Errorw("HibernateArgoApplication", "error in resolving cluster credentials", err, "clusterId", app.ClusterId)
+		return nil, err
+	}
 
 	req := service.HibernateReqAdaptor(hibernateRequest)
 	req.ClusterConfig = conf
@@ -221,7 +241,11 @@ func (impl *ArgoApplicationServiceImpl) UnHibernateArgoApplication(ctx context.C
 		impl.logger.Errorw("HibernateArgoApplication", "error in getting the cluster config", err, "clusterId", app.ClusterId, "appName", app.AppName)
 		return nil, err
 	}
-	conf := helper.ConvertClusterBeanToGrpcConfig(clusterBean)
+	conf, err := helper.ConvertClusterBeanToGrpcConfig(clusterBean, impl.clusterCredentialService)
+	if err != nil {
+		impl.logger.Errorw("UnHibernateArgoApplication", "error in resolving cluster credentials", err, "clusterId", app.ClusterId)
+		return nil, err
+	}
 
 	req := service.HibernateReqAdaptor(hibernateRequest)
 	req.ClusterConfig = conf
@@ -237,3 +261,19 @@ func (impl *ArgoApplicationServiceImpl) UnHibernateArgoApplication(ctx context.C
 func (impl *ArgoApplicationServiceImpl) GetResourceTree(ctx context.Context, acdQueryRequest *bean.AcdClientQueryRequest) (*argoApplication.ResourceTreeResponse, error) {
 	return nil, util2.DefaultApiError().WithHttpStatusCode(http.StatusNotFound).WithInternalMessage(util.NotSupportedErr).WithUserMessage(util.NotSupportedErr)
 }
+
+func (impl *ArgoApplicationServiceImpl) SyncArgoApplication(ctx context.Context, app *bean.ArgoAppIdentifier, request *bean.ArgoAppSyncRequest) error {
+	return util2.DefaultApiError().WithHttpStatusCode(http.StatusNotFound).WithInternalMessage(util.NotSupportedErr).WithUserMessage(util.NotSupportedErr)
+}
+
+func (impl *ArgoApplicationServiceImpl) RefreshArgoApplication(ctx context.Context, app *bean.ArgoAppIdentifier, request *bean.ArgoAppRefreshRequest) error {
+	return util2.DefaultApiError().WithHttpStatusCode(http.StatusNotFound).WithInternalMessage(util.NotSupportedErr).WithUserMessage(util.NotSupportedErr)
+}
+
+func (impl *ArgoApplicationServiceImpl) RollbackArgoApplication(ctx context.Context, app *bean.ArgoAppIdentifier, request *bean.ArgoAppRollbackRequest) error {
+	return util2.DefaultApiError().WithHttpStatusCode(http.StatusNotFound).WithInternalMessage(util.NotSupportedErr).WithUserMessage(util.NotSupportedErr)
+}
+
+func (impl *ArgoApplicationServiceImpl) GetArgoApplicationDiff(ctx context.Context, app *bean.ArgoAppIdentifier) (*bean.ArgoAppDiffResponse, error) {
+	return nil, util2.DefaultApiError().WithHttpStatusCode(http.StatusNotFound).WithInternalMessage(util.NotSupportedErr).WithUserMessage(util.NotSupportedErr)
+}
@@ -29,9 +29,13 @@ import (
 	util2 "github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/app/appDetails/adapter"
 	"github.com/devtron-labs/devtron/pkg/argoApplication/bean"
+	"github.com/devtron-labs/devtron/pkg/argoApplication/helper"
 	"github.com/devtron-labs/devtron/pkg/argoApplication/read"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
 	"github.com/devtron-labs/devtron/pkg/cluster"
+	clusterBean "github.com/devtron-labs/devtron/pkg/cluster/bean"
 	"github.com/devtron-labs/devtron/pkg/cluster/environment"
+	"github.com/devtron-labs/devtron/pkg/kubernetesResourceAuditLogs"
 	util5 "github.com/devtron-labs/devtron/pkg/util"
 	"github.com/devtron-labs/devtron/util"
 	"google.golang.org/grpc"
@@ -48,13 +52,21 @@ type ArgoApplicationServiceExtendedImpl struct {
 	argoApplicationReadService read.ArgoApplicationReadService
 	clusterService             cluster.ClusterService
 	acdClientWrapper           argocdServer.ArgoClientWrapperService
+	userService                user.UserService
+	k8sResourceHistoryService  kubernetesResourceAuditLogs.K8sResourceHistoryService
 }
 
 func NewArgoApplicationServiceExtendedServiceImpl(argoApplicationServiceImpl *ArgoApplicationServiceImpl,
-	acdClientWrapper argocdServer.ArgoClientWrapperService) *ArgoApplicationServiceExtendedImpl {
+	acdClientWrapper argocdServer.ArgoClientWrapperService,
+	userService user.UserService,
+	k8sResourceHistoryService kubernetesResourceAuditLogs.K8sResourceHistoryService,
+	aCDAuthConfig *util5.ACDAuthConfig) *ArgoApplicationServiceExtendedImpl {
 	return &ArgoApplicationServiceExtendedImpl{
 		ArgoApplicationServiceImpl: argoApplicationServiceImpl,
+		aCDAuthConfig:              aCDAuthConfig,
 		acdClientWrapper:           acdClientWrapper,
+		userService:                userService,
+		k8sResourceHistoryService:  k8sResourceHistoryService,
 	}
 }
 
@@ -70,6 +82,159 @@ func (c *ArgoApplicationServiceExtendedImpl) UnHibernateArgoApplication(ctx cont
 	return c.ArgoApplicationServiceImpl.UnHibernateArgoApplication(ctx, app, hibernateRequest)
 }
 
+func (c *ArgoApplicationServiceExtendedImpl) SyncArgoApplication(ctx context.Context, app *bean.ArgoAppIdentifier, request *bean.ArgoAppSyncRequest) error {
+	application, err := c.getApplicationWithoutOngoingOperation(ctx, app)
+	if err != nil {
+		return err
+	}
+	syncOperation := helper.GetSyncOperation(application, request)
+	err = c.startOperation(ctx, app, syncOperation, request.UserId)
+	if err != nil {
+		return err
+	}
+	c.saveActionHistory(app, request.UserId, bean.ArgoAppActionSync)
+	return nil
+}
+
+func (c *ArgoApplicationServiceExtendedImpl) RefreshArgoApplication(ctx context.Context, app *bean.ArgoAppIdentifier, request *bean.ArgoAppRefreshRequest) error {
+	refreshType := v1alpha1.RefreshTypeNormal
+	if request.HardRefresh {
+		refreshType = v1alpha1.RefreshTypeHard
+	}
+	err := c.acdClientWrapper.RefreshArgoAppWithK8sClient(ctx, app.ClusterId, app.Namespace, app.AppName, refreshType)
+	if err != nil {
+		c.logger.Errorw("error in refreshing argo application", "app", app, "refreshType", refreshType, "err", err)
+		return err
+	}
+	c.saveActionHistory(app, request.UserId, bean.ArgoAppActionRefresh)
+	return nil
+}
+
+func (c *ArgoApplicationServiceExtendedImpl) RollbackArgoApplication(ctx context.Context, app *bean.ArgoAppIdentifier, request *bean.ArgoAppRollbackRequest) error {
+	application, err := c.getApplicationWithoutOngoingOperation(ctx, app)
+	if err != nil {
+		return err
+	}
+	if helper.IsAutoSyncEnabled(application) {
+		errMsg := "rollback cannot be initiated when auto-sync is enabled, disable auto-sync on the application first"
+		return util2.NewApiError(http.StatusPreconditionFailed, errMsg, errMsg)
+	}
+	syncOperation, found := helper.GetRollbackSyncOperation(application, request.HistoryId, request.Prune)
+	if !found {
+		errMsg := fmt.Sprintf("history entry %d not found for application %s", request.HistoryId, app.AppName)
+		return util2.NewApiError(http.StatusNotFound, errMsg, errMsg)
+	}
+	err = c.startOperation(ctx, app, syncOperation, request.UserId)
+	if err != nil {
+		return err
+	}
+	c.saveActionHistory(app, request.UserId, bean.ArgoAppActionRollback)
+	return nil
+}
+
+func (c *ArgoApplicationServiceExtendedImpl) GetArgoApplicationDiff(ctx context.Context, app *bean.ArgoAppIdentifier) (*bean.ArgoAppDiffResponse, error) {
+	application, err := c.acdClientWrapper.GetArgoAppByNameWithK8sClient(ctx, app.ClusterId, app.Namespace, app.AppName)
+	if err != nil {
+		c.logger.Errorw("error in fetching argo application", "app", app, "err", err)
+		return nil, err
+	}
+	response := &bean.ArgoAppDiffResponse{
+		AppName:    app.AppName,
+		SyncStatus: string(application.Status.Sync.Status),
+		Revision:   application.Status.Sync.Revision,
+		Resources:  make([]*bean.ArgoAppResourceDiff, 0, len(application.Status.Resources)),
+	}
+	// the target state is rendered by the argoCd server, only the instance connected to devtron can be queried for it
+	managedResources := make(map[string]*v1alpha1.ResourceDiff)
+	if c.isConnectedArgoCdInstance(app) {
+		resourceDiffs, err := c.acdClientWrapper.GetManagedResources(ctx, app.AppName, app.Namespace)
+		if err != nil {
+			c.logger.Errorw("error in fetching managed resources of argo application", "app", app, "err", err)
+			return nil, err
+		}
+		for _, resourceDiff := range resourceDiffs {
+			managedResources[resourceDiff.FullName()] = resourceDiff
+		}
+	} else {
+		response.Message = bean.DesiredStateNotAvailable
+	}
+	for _, resource := range application.Status.Resources {
+		resourceDiff := &bean.ArgoAppResourceDiff{
+			Group:           resource.Group,
+			Version:         resource.Version,
+			Kind:            resource.Kind,
+			Namespace:       resource.Namespace,
+			Name:            resource.Name,
+			SyncStatus:      string(resource.Status),
+			RequiresPruning: resource.RequiresPruning,
+		}
+		if resource.Health != nil {
+			resourceDiff.HealthStatus = string(resource.Health.Status)
+		}
+		managedResource, ok := managedResources[helper.GetResourceFullName(resource)]
+		if ok && resource.Status != v1alpha1.SyncStatusCodeSynced {
+			resourceDiff.DesiredState, err = helper.ParseResourceState(managedResource.TargetState)
+			if err != nil {
+				c.logger.Errorw("error in parsing target state of argo managed resource", "app", app, "resource", resource, "err", err)
+				return nil, err
+			}
+			liveState, err := helper.ParseResourceState(helper.GetLiveState(managedResource))
+			if err != nil {
+				c.logger.Errorw("error in parsing live state of argo managed resource", "app", app, "resource", resource, "err", err)
+				return nil, err
+			}
+			if liveState != nil {
+				resourceDiff.LiveState = helper.TrimLiveState(liveState)
+			}
+		}
+		response.Resources = append(response.Resources, resourceDiff)
+	}
+	return response, nil
+}
+
+// isConnectedArgoCdInstance reports whether the app object lives with the argoCd instance devtron has a grpc connection to
+func (c *ArgoApplicationServiceExtendedImpl) isConnectedArgoCdInstance(app *bean.ArgoAppIdentifier) bool {
+	return app.ClusterId == clusterBean.DefaultClusterId && app.Namespace == c.aCDAuthConfig.ACDConfigMapNamespace
+}
+
+func (c *ArgoApplicationServiceExtendedImpl) getApplicationWithoutOngoingOperation(ctx context.Context, app *bean.ArgoAppIdentifier) (*v1alpha1.Application, error) {
+	application, err := c.acdClientWrapper.GetArgoAppByNameWithK8sClient(ctx, app.ClusterId, app.Namespace, app.AppName)
+	if err != nil {
+		c.logger.Errorw("error in fetching argo application", "app", app, "err", err)
+		return nil, err
+	}
+	if application.Operation != nil {
+		errMsg := "another operation is already in progress on this application"
+		return nil, util2.NewApiError(http.StatusConflict, errMsg, errMsg)
+	}
+	return application, nil
+}
+
+func (c *ArgoApplicationServiceExtendedImpl) startOperation(ctx context.Context, app *bean.ArgoAppIdentifier, syncOperation *v1alpha1.SyncOperation, userId int32) error {
+	userEmail, err := c.userService.GetEmailById(userId)
+	if err != nil {
+		c.logger.Errorw("error in fetching user email", "userId", userId, "err", err)
+		return err
+	}
+	operation := &v1alpha1.Operation{
+		Sync:        syncOperation,
+		InitiatedBy: v1alpha1.OperationInitiator{Username: userEmail},
+	}
+	err = c.acdClientWrapper.StartArgoAppOperationWithK8sClient(ctx, app.ClusterId, app.Namespace, app.AppName, operation)
+	if err != nil {
+		c.logger.Errorw("error in starting operation on argo application", "app", app, "operation", operation, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (c *ArgoApplicationServiceExtendedImpl) saveActionHistory(app *bean.ArgoAppIdentifier, userId int32, actionType bean.ArgoAppActionType) {
+	err := c.k8sResourceHistoryService.SaveExternalArgoAppActionHistory(app, userId, string(actionType))
+	if err != nil {
+		c.logger.Errorw("error in saving audit log for argo application action", "app", app, "actionType", actionType, "err", err)
+	}
+}
+
 func (c *ArgoApplicationServiceExtendedImpl) updateArgoAppStatusMetaDataInResourceTree(application *v1alpha1.Application,
 	resourceTreeResponse *argoApplication.ResourceTreeResponse) *argoApplication.ResourceTreeResponse {
 	conditions := make([]v1alpha1.ApplicationCondition, 0)
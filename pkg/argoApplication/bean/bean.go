@@ -31,6 +31,7 @@ const (
 	AllNamespaces                = ""
 	DevtronCDNamespae            = "devtroncd"
 	ArgoLabelForManagedResources = "app.kubernetes.io/instance"
+	DesiredStateNotAvailable     = "desired and live states are available only for applications of the argoCd instance connected to devtron"
 )
 
 const (
@@ -152,3 +153,58 @@ const (
 	ImperativeClient  ClientMode = "imperative"
 	DeclarativeClient ClientMode = "declarative"
 )
+
+type ArgoAppActionType string
+
+const (
+	ArgoAppActionSync     ArgoAppActionType = "sync"
+	ArgoAppActionRefresh  ArgoAppActionType = "refresh"
+	ArgoAppActionRollback ArgoAppActionType = "rollback"
+)
+
+type ArgoAppSyncRequest struct {
+	AppId string `json:"appId" validate:"required"`
+	// Revision defaults to the target revision of the app source when empty
+	Revision string `json:"revision"`
+	Prune    bool   `json:"prune"`
+	DryRun   bool   `json:"dryRun"`
+	UserId   int32  `json:"-"`
+}
+
+type ArgoAppRefreshRequest struct {
+	AppId       string `json:"appId" validate:"required"`
+	HardRefresh bool   `json:"hardRefresh"`
+	UserId      int32  `json:"-"`
+}
+
+type ArgoAppRollbackRequest struct {
+	AppId string `json:"appId" validate:"required"`
+	// HistoryId is the id of the entry in the app's sync history to roll back to
+	HistoryId int64 `json:"historyId" validate:"required"`
+	Prune     bool  `json:"prune"`
+	UserId    int32 `json:"-"`
+}
+
+type ArgoAppResourceDiff struct {
+	Group           string `json:"group"`
+	Version         string `json:"version"`
+	Kind            string `json:"kind"`
+	Namespace       string `json:"namespace"`
+	Name            string `json:"name"`
+	SyncStatus      string `json:"syncStatus"`
+	HealthStatus    string `json:"healthStatus,omitempty"`
+	RequiresPruning bool   `json:"requiresPruning,omitempty"`
+	// LiveState is the manifest currently present in the destination cluster, normalised by argoCd
+	LiveState map[string]interface{} `json:"liveState,omitempty"`
+	// DesiredState is the target state argoCd rendered from the source at the target revision
+	DesiredState map[string]interface{} `json:"desiredState,omitempty"`
+}
+
+type ArgoAppDiffResponse struct {
+	AppName    string                 `json:"appName"`
+	SyncStatus string                 `json:"syncStatus"`
+	Revision   string                 `json:"revision"`
+	Resources  []*ArgoAppResourceDiff `json:"resources"`
+	// Message is set when states could not be fetched, e.g. the app belongs to an argoCd instance devtron is not connected to
+	Message string `json:"message,omitempty"`
+}
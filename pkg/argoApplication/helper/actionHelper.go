@@ -0,0 +1,88 @@
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/devtron-labs/devtron/pkg/argoApplication/bean"
+)
+
+const lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// GetSyncOperation builds the sync operation for a manual sync, an empty revision resolves to the target revision of the app source
+func GetSyncOperation(app *v1alpha1.Application, request *bean.ArgoAppSyncRequest) *v1alpha1.SyncOperation {
+	revision := request.Revision
+	if len(revision) == 0 && !app.Spec.HasMultipleSources() && app.Spec.Source != nil {
+		revision = app.Spec.Source.TargetRevision
+	}
+	return &v1alpha1.SyncOperation{
+		Revision: revision,
+		Prune:    request.Prune,
+		DryRun:   request.DryRun,
+	}
+}
+
+// GetRollbackSyncOperation builds a sync operation pinned to the source and revision of the given history entry,
+// returns false if the app has no history entry with that id
+func GetRollbackSyncOperation(app *v1alpha1.Application, historyId int64, prune bool) (*v1alpha1.SyncOperation, bool) {
+	for _, history := range app.Status.History {
+		if history.ID != historyId {
+			continue
+		}
+		syncOperation := &v1alpha1.SyncOperation{
+			Prune: prune,
+		}
+		if len(history.Sources) > 0 {
+			syncOperation.Sources = history.Sources
+			syncOperation.Revisions = history.Revisions
+		} else {
+			source := history.Source
+			syncOperation.Source = &source
+			syncOperation.Revision = history.Revision
+		}
+		return syncOperation, true
+	}
+	return nil, false
+}
+
+// IsAutoSyncEnabled reports whether argoCd would immediately revert a rollback
+func IsAutoSyncEnabled(app *v1alpha1.Application) bool {
+	return app.Spec.SyncPolicy != nil && app.Spec.SyncPolicy.Automated != nil
+}
+
+// ParseResourceState parses a json serialised manifest of a managed resource, nil if the state is absent
+func ParseResourceState(state string) (map[string]interface{}, error) {
+	if len(state) == 0 || state == "null" {
+		return nil, nil
+	}
+	manifest := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(state), &manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// GetLiveState prefers the live state with argoCd's diff normalisations applied, so that ignored fields do not show up
+func GetLiveState(resourceDiff *v1alpha1.ResourceDiff) string {
+	if len(resourceDiff.NormalizedLiveState) > 0 {
+		return resourceDiff.NormalizedLiveState
+	}
+	return resourceDiff.LiveState
+}
+
+// GetResourceFullName matches v1alpha1.ResourceDiff.FullName for the resource
+func GetResourceFullName(resource v1alpha1.ResourceStatus) string {
+	return fmt.Sprintf("%s/%s/%s/%s", resource.Group, resource.Kind, resource.Namespace, resource.Name)
+}
+
+// TrimLiveState drops the fields that are never part of the desired state so that the diff stays readable
+func TrimLiveState(liveState map[string]interface{}) map[string]interface{} {
+	delete(liveState, "status")
+	if metadata, ok := liveState["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "managedFields")
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			delete(annotations, lastAppliedConfigAnnotation)
+		}
+	}
+	return liveState
+}
@@ -0,0 +1,112 @@
+package helper
+
+import (
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/devtron-labs/devtron/pkg/argoApplication/bean"
+	"testing"
+)
+
+func TestGetSyncOperation(t *testing.T) {
+	app := &v1alpha1.Application{
+		Spec: v1alpha1.ApplicationSpec{
+			Source: &v1alpha1.ApplicationSource{RepoURL: "https://github.com/org/repo", TargetRevision: "main"},
+		},
+	}
+	tests := []struct {
+		name         string
+		request      *bean.ArgoAppSyncRequest
+		wantRevision string
+	}{
+		{name: "Test1_DefaultsToTargetRevision", request: &bean.ArgoAppSyncRequest{Prune: true}, wantRevision: "main"},
+		{name: "Test2_RequestedRevision", request: &bean.ArgoAppSyncRequest{Revision: "v1.2.0"}, wantRevision: "v1.2.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GetSyncOperation(app, tt.request)
+			if got.Revision != tt.wantRevision || got.Prune != tt.request.Prune {
+				t.Errorf("GetSyncOperation() = %+v, want revision %s prune %v", got, tt.wantRevision, tt.request.Prune)
+			}
+		})
+	}
+}
+
+func TestGetRollbackSyncOperation(t *testing.T) {
+	app := &v1alpha1.Application{
+		Status: v1alpha1.ApplicationStatus{
+			History: v1alpha1.RevisionHistories{
+				{ID: 1, Revision: "abc123", Source: v1alpha1.ApplicationSource{RepoURL: "https://github.com/org/repo", Path: "app"}},
+				{ID: 2, Revisions: []string{"def456", "1.0.0"}, Sources: v1alpha1.ApplicationSources{{RepoURL: "https://github.com/org/repo"}, {Chart: "app"}}},
+			},
+		},
+	}
+	t.Run("Test1_SingleSourceEntry", func(t *testing.T) {
+		got, found := GetRollbackSyncOperation(app, 1, true)
+		if !found || got.Revision != "abc123" || got.Source == nil || got.Source.Path != "app" || !got.Prune {
+			t.Errorf("GetRollbackSyncOperation() = %+v, found %v", got, found)
+		}
+	})
+	t.Run("Test2_MultiSourceEntry", func(t *testing.T) {
+		got, found := GetRollbackSyncOperation(app, 2, false)
+		if !found || got.Source != nil || len(got.Sources) != 2 || len(got.Revisions) != 2 {
+			t.Errorf("GetRollbackSyncOperation() = %+v, found %v", got, found)
+		}
+	})
+	t.Run("Test3_UnknownEntry", func(t *testing.T) {
+		if _, found := GetRollbackSyncOperation(app, 3, false); found {
+			t.Errorf("GetRollbackSyncOperation() found history entry 3, want not found")
+		}
+	})
+}
+
+func TestParseResourceState(t *testing.T) {
+	desiredState, err := ParseResourceState(`{"kind":"ConfigMap","data":{"key":"desired"}}`)
+	if err != nil || desiredState["data"].(map[string]interface{})["key"] != "desired" {
+		t.Fatalf("ParseResourceState() = %v, %v, want desired data", desiredState, err)
+	}
+	for _, state := range []string{"", "null"} {
+		if manifest, err := ParseResourceState(state); err != nil || manifest != nil {
+			t.Errorf("ParseResourceState(%q) = %v, %v, want nil", state, manifest, err)
+		}
+	}
+	if _, err = ParseResourceState("{"); err == nil {
+		t.Errorf("ParseResourceState() parsed an invalid manifest")
+	}
+	resourceDiff := &v1alpha1.ResourceDiff{LiveState: `{"kind":"ConfigMap"}`}
+	if GetLiveState(resourceDiff) != resourceDiff.LiveState {
+		t.Errorf("GetLiveState() did not fall back to the live state")
+	}
+	resourceDiff.NormalizedLiveState = `{"kind":"ConfigMap","data":{}}`
+	if GetLiveState(resourceDiff) != resourceDiff.NormalizedLiveState {
+		t.Errorf("GetLiveState() did not prefer the normalized live state")
+	}
+	resource := v1alpha1.ResourceStatus{Group: "apps", Kind: "Deployment", Namespace: "default", Name: "web"}
+	if name := GetResourceFullName(resource); name != (&v1alpha1.ResourceDiff{Group: "apps", Kind: "Deployment", Namespace: "default", Name: "web"}).FullName() {
+		t.Errorf("GetResourceFullName() = %s, want argoCd resource diff name", name)
+	}
+}
+
+func TestTrimLiveState(t *testing.T) {
+	liveState := map[string]interface{}{
+		"kind": "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":          "config",
+			"managedFields": []interface{}{},
+			"annotations": map[string]interface{}{
+				lastAppliedConfigAnnotation: `{"kind":"ConfigMap","data":{"key":"desired"}}`,
+			},
+		},
+		"data":   map[string]interface{}{"key": "live"},
+		"status": map[string]interface{}{},
+	}
+	trimmed := TrimLiveState(liveState)
+	metadata := trimmed["metadata"].(map[string]interface{})
+	if _, ok := trimmed["status"]; ok {
+		t.Errorf("TrimLiveState() kept status")
+	}
+	if _, ok := metadata["managedFields"]; ok {
+		t.Errorf("TrimLiveState() kept managedFields")
+	}
+	if _, ok := metadata["annotations"].(map[string]interface{})[lastAppliedConfigAnnotation]; ok {
+		t.Errorf("TrimLiveState() kept last applied annotation")
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"github.com/devtron-labs/common-lib/utils/k8s/commonBean"
 	"github.com/devtron-labs/devtron/api/helm-app/gRPC"
 	"github.com/devtron-labs/devtron/pkg/argoApplication/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/adapter"
+	clusterBean "github.com/devtron-labs/devtron/pkg/cluster/bean"
 	"github.com/devtron-labs/devtron/pkg/cluster/repository"
 	"strconv"
 	"strings"
@@ -29,21 +31,8 @@ func DecodeExternalArgoAppId(appId string) (*bean.ArgoAppIdentifier, error) {
 	}, nil
 }
 
-func ConvertClusterBeanToGrpcConfig(cluster repository.Cluster) *gRPC.ClusterConfig {
-	config := &gRPC.ClusterConfig{
-		ApiServerUrl:          cluster.ServerUrl,
-		Token:                 cluster.Config[commonBean.BearerToken],
-		ClusterId:             int32(cluster.Id),
-		ClusterName:           cluster.ClusterName,
-		InsecureSkipTLSVerify: cluster.InsecureSkipTlsVerify,
-	}
-	if cluster.InsecureSkipTlsVerify == false {
-		config.KeyData = cluster.Config[commonBean.TlsKey]
-		config.CertData = cluster.Config[commonBean.CertData]
-		config.CaData = cluster.Config[commonBean.CertificateAuthorityData]
-	}
-	return config
-
+func ConvertClusterBeanToGrpcConfig(cluster repository.Cluster, provider clusterBean.ClusterCredentialProvider) (*gRPC.ClusterConfig, error) {
+	return adapter.GetGrpcClusterConfig(adapter.GetClusterBean(cluster), provider)
 }
 
 func GetHealthSyncStatusDestinationServerAndManagedResourcesForArgoK8sRawObject(obj map[string]interface{}) (string,
@@ -27,6 +27,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/argoApplication/bean"
 	"github.com/devtron-labs/devtron/pkg/argoApplication/helper"
 	"github.com/devtron-labs/devtron/pkg/cluster/adapter"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterCredentials"
 	clusterRepository "github.com/devtron-labs/devtron/pkg/cluster/repository"
 	clientErrors "github.com/devtron-labs/devtron/pkg/errors"
 	"go.uber.org/zap"
@@ -43,24 +44,27 @@ type ArgoApplicationReadService interface {
 }
 
 type ArgoApplicationReadServiceImpl struct {
-	logger            *zap.SugaredLogger
-	clusterRepository clusterRepository.ClusterRepository
-	k8sUtil           *k8s.K8sServiceImpl
-	helmAppClient     gRPC.HelmAppClient
-	helmAppService    service.HelmAppService
+	logger                   *zap.SugaredLogger
+	clusterRepository        clusterRepository.ClusterRepository
+	k8sUtil                  *k8s.K8sServiceImpl
+	helmAppClient            gRPC.HelmAppClient
+	helmAppService           service.HelmAppService
+	clusterCredentialService clusterCredentials.ClusterCredentialService
 }
 
 func NewArgoApplicationReadServiceImpl(logger *zap.SugaredLogger,
 	clusterRepository clusterRepository.ClusterRepository,
 	k8sUtil *k8s.K8sServiceImpl,
 	helmAppClient gRPC.HelmAppClient,
-	helmAppService service.HelmAppService) *ArgoApplicationReadServiceImpl {
+	helmAppService service.HelmAppService,
+	clusterCredentialService clusterCredentials.ClusterCredentialService) *ArgoApplicationReadServiceImpl {
 	return &ArgoApplicationReadServiceImpl{
-		logger:            logger,
-		clusterRepository: clusterRepository,
-		k8sUtil:           k8sUtil,
-		helmAppService:    helmAppService,
-		helmAppClient:     helmAppClient,
+		logger:                   logger,
+		clusterRepository:        clusterRepository,
+		k8sUtil:                  k8sUtil,
+		helmAppService:           helmAppService,
+		helmAppClient:            helmAppClient,
+		clusterCredentialService: clusterCredentialService,
 	}
 
 }
@@ -95,7 +99,11 @@ func (impl *ArgoApplicationReadServiceImpl) GetAppDetailEA(ctx context.Context,
 		return nil, fmt.Errorf("error in connecting to cluster")
 	}
 	clusterBean := adapter.GetClusterBean(clusterWithApplicationObject)
-	clusterConfig := clusterBean.GetClusterConfig()
+	clusterConfig, err := clusterBean.ResolveClusterConfig(impl.clusterCredentialService)
+	if err != nil {
+		impl.logger.Errorw("error in resolving cluster credentials", "err", err, "clusterId", clusterId)
+		return nil, err
+	}
 	resp, err := impl.GetArgoManagedResources(resourceName, resourceNamespace, clusterConfig)
 	if err != nil {
 		impl.logger.Errorw("error in getting argo managed resources", "err", err)
@@ -9,6 +9,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/argoApplication/bean"
 	"github.com/devtron-labs/devtron/pkg/argoApplication/helper"
 	"github.com/devtron-labs/devtron/pkg/cluster/adapter"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterCredentials"
 	clusterRepository "github.com/devtron-labs/devtron/pkg/cluster/repository"
 	"go.uber.org/zap"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -17,9 +18,10 @@ import (
 )
 
 type ArgoApplicationConfigServiceImpl struct {
-	logger            *zap.SugaredLogger
-	k8sUtil           *k8s.K8sServiceImpl
-	clusterRepository clusterRepository.ClusterRepository
+	logger                   *zap.SugaredLogger
+	k8sUtil                  *k8s.K8sServiceImpl
+	clusterRepository        clusterRepository.ClusterRepository
+	clusterCredentialService clusterCredentials.ClusterCredentialService
 }
 
 type ArgoApplicationConfigService interface {
@@ -29,11 +31,13 @@ type ArgoApplicationConfigService interface {
 
 func NewArgoApplicationConfigServiceImpl(logger *zap.SugaredLogger,
 	k8sUtil *k8s.K8sServiceImpl,
-	clusterRepository clusterRepository.ClusterRepository) *ArgoApplicationConfigServiceImpl {
+	clusterRepository clusterRepository.ClusterRepository,
+	clusterCredentialService clusterCredentials.ClusterCredentialService) *ArgoApplicationConfigServiceImpl {
 	return &ArgoApplicationConfigServiceImpl{
-		logger:            logger,
-		k8sUtil:           k8sUtil,
-		clusterRepository: clusterRepository,
+		logger:                   logger,
+		k8sUtil:                  k8sUtil,
+		clusterRepository:        clusterRepository,
+		clusterCredentialService: clusterCredentialService,
 	}
 }
 
@@ -55,8 +59,12 @@ func (impl *ArgoApplicationConfigServiceImpl) GetClusterConfigFromAllClusters(cl
 		return nil, clusterWithApplicationObject, nil, fmt.Errorf("error in connecting to cluster")
 	}
 	clusterBean := adapter.GetClusterBean(clusterWithApplicationObject)
-	clusterConfig := clusterBean.GetClusterConfig()
-	return clusterConfig, clusterWithApplicationObject, clusterServerUrlIdMap, err
+	clusterConfig, err := clusterBean.ResolveClusterConfig(impl.clusterCredentialService)
+	if err != nil {
+		impl.logger.Errorw("error in resolving cluster credentials", "err", err, "clusterId", clusterId)
+		return nil, clusterWithApplicationObject, nil, err
+	}
+	return clusterConfig, clusterWithApplicationObject, clusterServerUrlIdMap, nil
 }
 
 func (impl *ArgoApplicationConfigServiceImpl) GetRestConfigForExternalArgo(ctx context.Context, clusterId int, externalArgoApplicationName string) (*rest.Config, error) {
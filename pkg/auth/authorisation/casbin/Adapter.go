@@ -44,6 +44,7 @@ const (
 var e *casbin.SyncedEnforcer
 var e2 *casbinv2.SyncedEnforcer
 var enforcerImplRef *EnforcerImpl
+var timeBoundAccessSyncerRef TimeBoundAccessSyncer
 var casbinVersion Version
 
 func isV2() bool {
@@ -138,6 +139,22 @@ func setEnforcerImpl(ref *EnforcerImpl) {
 	enforcerImplRef = ref
 }
 
+// TimeBoundAccessSyncer applies the start and expiry of time bound grants of a user to casbin, it is consulted before
+// every enforcement so that a lapsed grant is never honoured even if the background sweeper has not run yet
+type TimeBoundAccessSyncer interface {
+	SyncIfDue(emailId string)
+}
+
+func SetTimeBoundAccessSyncer(syncer TimeBoundAccessSyncer) {
+	timeBoundAccessSyncerRef = syncer
+}
+
+func syncTimeBoundAccessIfDue(emailId string) {
+	if timeBoundAccessSyncerRef != nil {
+		timeBoundAccessSyncerRef.SyncIfDue(emailId)
+	}
+}
+
 func AddPolicy(policies []bean.Policy) []bean.Policy {
 	defer handlePanic()
 	var failed = []bean.Policy{}
@@ -324,3 +341,11 @@ func handlePanic() {
 		log.Println("panic occurred:", err)
 	}
 }
+
+func GetPoliciesForSubject(subject string) ([][]string, error) {
+	subject = strings.ToLower(subject)
+	if isV2() {
+		return e2.GetFilteredPolicy(0, subject)
+	}
+	return e.GetFilteredPolicy(0, subject), nil
+}
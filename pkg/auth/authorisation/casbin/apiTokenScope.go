@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package casbin
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin/bean"
+	"strings"
+)
+
+// IsAllowedByApiTokenScope checks the request against the scope embedded in an api token, a nil scope allows everything
+func IsAllowedByApiTokenScope(scope *bean.ApiTokenScope, resource, action, resourceItem string) bool {
+	if scope == nil {
+		return true
+	}
+	switch scope.Type {
+	case bean.ApiTokenScopeTypeReadOnly:
+		return action == ActionGet
+	case bean.ApiTokenScopeTypeWebhook:
+		return action == ActionTrigger && (resource == ResourceApplications || resource == ResourceEnvironment)
+	case bean.ApiTokenScopeTypeCustom:
+		for _, permission := range scope.Permissions {
+			if matchesScopePart(resource, permission.Resource) && matchesScopePart(action, permission.Action) &&
+				MatchKeyByPart(strings.ToLower(resourceItem), strings.ToLower(permission.Object)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetApiTokenScopeFromClaims returns nil for tokens minted without a scope
+func GetApiTokenScopeFromClaims(mapClaims map[string]interface{}) (*bean.ApiTokenScope, error) {
+	scopeClaim, ok := mapClaims[bean.ApiTokenScopeClaim]
+	if !ok || scopeClaim == nil {
+		return nil, nil
+	}
+	scopeJson, err := json.Marshal(scopeClaim)
+	if err != nil {
+		return nil, err
+	}
+	scope := &bean.ApiTokenScope{}
+	err = json.Unmarshal(scopeJson, scope)
+	if err != nil {
+		return nil, err
+	}
+	return scope, nil
+}
+
+func matchesScopePart(value, pattern string) bool {
+	return pattern == "*" || value == pattern
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package casbin
+
+import (
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestIsAllowedByApiTokenScope(t *testing.T) {
+	assert.True(t, IsAllowedByApiTokenScope(nil, ResourceGlobal, ActionDelete, "*"))
+
+	readOnly := &bean.ApiTokenScope{Type: bean.ApiTokenScopeTypeReadOnly}
+	assert.True(t, IsAllowedByApiTokenScope(readOnly, ResourceApplications, ActionGet, "team/app"))
+	assert.False(t, IsAllowedByApiTokenScope(readOnly, ResourceApplications, ActionUpdate, "team/app"))
+
+	webhook := &bean.ApiTokenScope{Type: bean.ApiTokenScopeTypeWebhook}
+	assert.True(t, IsAllowedByApiTokenScope(webhook, ResourceApplications, ActionTrigger, "team/app"))
+	assert.True(t, IsAllowedByApiTokenScope(webhook, ResourceEnvironment, ActionTrigger, "env/app"))
+	assert.False(t, IsAllowedByApiTokenScope(webhook, ResourceApplications, ActionGet, "team/app"))
+
+	custom := &bean.ApiTokenScope{Type: bean.ApiTokenScopeTypeCustom, Permissions: []*bean.ApiTokenScopePermission{
+		{Resource: ResourceApplications, Action: "*", Object: "team/*"},
+		{Resource: ResourceEnvironment, Action: ActionGet, Object: "*"},
+	}}
+	assert.True(t, IsAllowedByApiTokenScope(custom, ResourceApplications, ActionUpdate, "Team/App"))
+	assert.False(t, IsAllowedByApiTokenScope(custom, ResourceApplications, ActionUpdate, "other/app"))
+	assert.True(t, IsAllowedByApiTokenScope(custom, ResourceEnvironment, ActionGet, "prod/app"))
+	assert.False(t, IsAllowedByApiTokenScope(custom, ResourceEnvironment, ActionTrigger, "prod/app"))
+	assert.False(t, IsAllowedByApiTokenScope(custom, ResourceGlobal, ActionGet, "*"))
+
+	assert.False(t, IsAllowedByApiTokenScope(&bean.ApiTokenScope{Type: "unknown"}, ResourceApplications, ActionGet, "team/app"))
+}
+
+func TestGetApiTokenScopeFromClaims(t *testing.T) {
+	scope, err := GetApiTokenScopeFromClaims(map[string]interface{}{"email": "API-TOKEN:ci"})
+	assert.Nil(t, err)
+	assert.Nil(t, scope)
+
+	scope, err = GetApiTokenScopeFromClaims(map[string]interface{}{
+		bean.ApiTokenScopeClaim: map[string]interface{}{
+			"type":        "custom",
+			"permissions": []interface{}{map[string]interface{}{"resource": "applications", "action": "get", "object": "*"}},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, bean.ApiTokenScopeTypeCustom, scope.Type)
+	assert.Equal(t, []*bean.ApiTokenScopePermission{{Resource: "applications", Action: "get", Object: "*"}}, scope.Permissions)
+}
@@ -13,3 +13,55 @@ type Resource string
 type Action string
 type Object string
 type PolicyType string
+
+const PolicyTypeP PolicyType = "p"
+
+type PolicyEffect string
+
+const (
+	PolicyEffectAllow PolicyEffect = "allow"
+	PolicyEffectDeny  PolicyEffect = "deny"
+)
+
+// GrantedPolicy is a policy reachable from a user, InheritedVia lists the roles and role groups between the user and Sub
+type GrantedPolicy struct {
+	Policy
+	Effect       PolicyEffect `json:"effect"`
+	InheritedVia []Subject    `json:"inheritedVia,omitempty"`
+}
+
+type EnforceExplanation struct {
+	Allowed         bool             `json:"allowed"`
+	MatchedPolicies []*GrantedPolicy `json:"matchedPolicies"`
+	DenyPolicies    []*GrantedPolicy `json:"denyPolicies"`
+}
+
+type ApiTokenScopeType string
+
+const (
+	// ApiTokenScopeTypeReadOnly allows only the get action
+	ApiTokenScopeTypeReadOnly ApiTokenScopeType = "readOnly"
+	// ApiTokenScopeTypeWebhook allows only the trigger action and is accepted only on the webhook apis
+	ApiTokenScopeTypeWebhook ApiTokenScopeType = "webhook"
+	// ApiTokenScopeTypeCustom allows only the listed permissions
+	ApiTokenScopeTypeCustom ApiTokenScopeType = "custom"
+)
+
+const (
+	ApiTokenScopeClaim      = "apiTokenScope"
+	ApiTokenAllowedIpsClaim = "allowedIps"
+)
+
+// ApiTokenScopePermission is matched like a casbin policy, parts of Object can be "*"
+type ApiTokenScopePermission struct {
+	Resource string `json:"resource" validate:"required"`
+	Action   string `json:"action" validate:"required"`
+	Object   string `json:"object" validate:"required"`
+}
+
+// ApiTokenScope is embedded in the api token, it narrows down the permissions of the api token user and never
+// grants anything the user does not have
+type ApiTokenScope struct {
+	Type        ApiTokenScopeType          `json:"type"`
+	Permissions []*ApiTokenScopePermission `json:"permissions,omitempty"`
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package casbin
+
+import (
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin/bean"
+	"strings"
+)
+
+type inheritedSubject struct {
+	subject string
+	via     []bean.Subject
+}
+
+// ExplainByEmail evaluates the request like EnforceByEmail, bypassing the cache, and returns the policies that decided it.
+func (e *EnforcerImpl) ExplainByEmail(emailId string, resource string, action string, resourceItem string) (*bean.EnforceExplanation, error) {
+	emailId = strings.ToLower(emailId)
+	resourceItem = strings.ToLower(resourceItem)
+	policies, err := e.GetEffectivePoliciesByEmail(emailId)
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := e.enforcerEnforce(emailId, resource, action, resourceItem)
+	if err != nil {
+		return nil, err
+	}
+	matched, denied := FilterPoliciesForRequest(policies, resource, action, resourceItem)
+	return &bean.EnforceExplanation{Allowed: allowed, MatchedPolicies: matched, DenyPolicies: denied}, nil
+}
+
+// GetEffectivePoliciesByEmail returns every policy the user holds directly or through roles and role groups.
+func (e *EnforcerImpl) GetEffectivePoliciesByEmail(emailId string) ([]*bean.GrantedPolicy, error) {
+	emailId = strings.ToLower(emailId)
+	syncTimeBoundAccessIfDue(emailId)
+	subjects, err := getInheritedSubjects(emailId)
+	if err != nil {
+		e.logger.Errorw("error in getting inherited subjects", "emailId", emailId, "err", err)
+		return nil, err
+	}
+	grantedPolicies := make([]*bean.GrantedPolicy, 0)
+	for _, subject := range subjects {
+		policies, err := GetPoliciesForSubject(subject.subject)
+		if err != nil {
+			e.logger.Errorw("error in getting policies for subject", "subject", subject.subject, "err", err)
+			return nil, err
+		}
+		for _, policy := range policies {
+			if len(policy) < 4 {
+				continue
+			}
+			effect := bean.PolicyEffectAllow
+			if len(policy) > 4 && policy[4] == string(bean.PolicyEffectDeny) {
+				effect = bean.PolicyEffectDeny
+			}
+			grantedPolicies = append(grantedPolicies, &bean.GrantedPolicy{
+				Policy: bean.Policy{
+					Type: bean.PolicyTypeP,
+					Sub:  bean.Subject(policy[0]),
+					Res:  bean.Resource(policy[1]),
+					Act:  bean.Action(policy[2]),
+					Obj:  bean.Object(policy[3]),
+				},
+				Effect:       effect,
+				InheritedVia: subject.via,
+			})
+		}
+	}
+	return grantedPolicies, nil
+}
+
+// getInheritedSubjects walks the grouping policies breadth first starting from the user, so every subject carries
+// the shortest chain of roles and role groups it was reached through.
+func getInheritedSubjects(emailId string) ([]inheritedSubject, error) {
+	visited := map[string]bool{emailId: true}
+	subjects := []inheritedSubject{{subject: emailId}}
+	for i := 0; i < len(subjects); i++ {
+		roles, err := GetRolesForUser(subjects[i].subject)
+		if err != nil {
+			return nil, err
+		}
+		for _, role := range roles {
+			if visited[role] {
+				continue
+			}
+			visited[role] = true
+			var via []bean.Subject
+			if i > 0 {
+				via = append(append(via, subjects[i].via...), bean.Subject(subjects[i].subject))
+			}
+			subjects = append(subjects, inheritedSubject{subject: role, via: via})
+		}
+	}
+	return subjects, nil
+}
+
+// FilterPoliciesForRequest splits the policies matching the request, as per the matcher in auth_model.conf, into allow and deny rules.
+func FilterPoliciesForRequest(policies []*bean.GrantedPolicy, resource string, action string, resourceItem string) (matched []*bean.GrantedPolicy, denied []*bean.GrantedPolicy) {
+	matched, denied = make([]*bean.GrantedPolicy, 0), make([]*bean.GrantedPolicy, 0)
+	for _, policy := range policies {
+		if !MatchKeyByPart(resource, string(policy.Res)) || !MatchKeyByPart(action, string(policy.Act)) ||
+			!MatchKeyByPart(resourceItem, string(policy.Obj)) {
+			continue
+		}
+		if policy.Effect == bean.PolicyEffectDeny {
+			denied = append(denied, policy)
+		} else {
+			matched = append(matched, policy)
+		}
+	}
+	return matched, denied
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package casbin
+
+import (
+	"github.com/casbin/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin/bean"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"testing"
+)
+
+const testAuthModel = `
+[request_definition]
+r = sub, res, act, obj
+
+[policy_definition]
+p = sub, res, act, obj, eft
+
+[policy_effect]
+e = some(where (p.eft == allow)) && !some(where (p.eft == deny))
+
+[role_definition]
+g = _, _
+
+[matchers]
+m = g(r.sub, p.sub) && matchKeyByPart(r.res, p.res) && matchKeyByPart(r.act, p.act) && matchKeyByPart(r.obj, p.obj)
+`
+
+func TestExplainByEmail(t *testing.T) {
+	enforcer := casbin.NewSyncedEnforcer(casbin.NewModel(testAuthModel))
+	enforcer.AddFunction("matchKeyByPart", MatchKeyByPartFunc)
+	enforcer.AddPolicy("role:trigger_devtron-demo_prod_", "applications", "trigger", "devtron-demo/*", "allow")
+	enforcer.AddPolicy("role:trigger_devtron-demo_prod_", "environment", "trigger", "prod/*", "allow")
+	enforcer.AddPolicy("role:deny_payments", "environment", "trigger", "prod/payments", "deny")
+	enforcer.AddPolicy("role:view_devtron-demo", "applications", "get", "devtron-demo/*", "allow")
+	enforcer.AddGroupingPolicy("user@example.com", "group:release_managers")
+	enforcer.AddGroupingPolicy("user@example.com", "role:view_devtron-demo")
+	enforcer.AddGroupingPolicy("group:release_managers", "role:trigger_devtron-demo_prod_")
+	enforcer.AddGroupingPolicy("group:release_managers", "role:deny_payments")
+	e, casbinVersion = enforcer, CasbinV1
+	enforcerImpl := &EnforcerImpl{Enforcer: enforcer, logger: zap.NewNop().Sugar()}
+
+	policies, err := enforcerImpl.GetEffectivePoliciesByEmail("User@example.com")
+	assert.Nil(t, err)
+	assert.Len(t, policies, 4)
+	for _, policy := range policies {
+		if policy.Sub == "role:view_devtron-demo" {
+			assert.Empty(t, policy.InheritedVia)
+		} else {
+			assert.Equal(t, []bean.Subject{"group:release_managers"}, policy.InheritedVia)
+		}
+	}
+
+	explanation, err := enforcerImpl.ExplainByEmail("user@example.com", "environment", "trigger", "prod/checkout")
+	assert.Nil(t, err)
+	assert.True(t, explanation.Allowed)
+	assert.Len(t, explanation.MatchedPolicies, 1)
+	assert.Empty(t, explanation.DenyPolicies)
+
+	explanation, err = enforcerImpl.ExplainByEmail("user@example.com", "environment", "trigger", "prod/payments")
+	assert.Nil(t, err)
+	assert.False(t, explanation.Allowed)
+	assert.Len(t, explanation.MatchedPolicies, 1)
+	assert.Len(t, explanation.DenyPolicies, 1)
+	assert.Equal(t, bean.Subject("role:deny_payments"), explanation.DenyPolicies[0].Sub)
+
+	explanation, err = enforcerImpl.ExplainByEmail("user@example.com", "applications", "delete", "devtron-demo/app")
+	assert.Nil(t, err)
+	assert.False(t, explanation.Allowed)
+	assert.Empty(t, explanation.MatchedPolicies)
+	assert.Empty(t, explanation.DenyPolicies)
+}
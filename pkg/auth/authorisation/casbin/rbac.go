@@ -31,6 +31,7 @@ import (
 	"github.com/casbin/casbin"
 	"github.com/devtron-labs/authenticator/jwt"
 	"github.com/devtron-labs/authenticator/middleware"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin/bean"
 	"github.com/patrickmn/go-cache"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
@@ -47,6 +48,8 @@ type Enforcer interface {
 	InvalidateCompleteCache()
 	ReloadPolicy() error
 	GetCacheDump() string
+	ExplainByEmail(emailId string, resource string, action string, resourceItem string) (*bean.EnforceExplanation, error)
+	GetEffectivePoliciesByEmail(emailId string) ([]*bean.GrantedPolicy, error)
 }
 
 func NewEnforcerImpl(
@@ -185,6 +188,7 @@ func (e *EnforcerImpl) enforceByEmailInBatchSync(wg *sync.WaitGroup, mutex *sync
 
 func (e *EnforcerImpl) EnforceByEmailInBatch(emailId string, resource string, action string, vals []string) map[string]bool {
 	emailId = strings.ToLower(emailId)
+	syncTimeBoundAccessIfDue(emailId)
 	var totalTimeGap int64 = 0
 	var maxTimegap int64 = 0
 	var minTimegap int64 = math.MaxInt64
@@ -393,8 +397,8 @@ func (e *EnforcerImpl) GetCacheDump() string {
 // enforce is a helper to additionally check a default role and invoke a custom claims enforcement function
 func (e *EnforcerImpl) enforce(token string, resource string, action string, resourceItem string) bool {
 	// check the default role
-	email, invalid := e.VerifyTokenAndGetEmail(token)
-	if invalid {
+	email, scope, invalid := e.verifyTokenAndGetEmailAndScope(token)
+	if invalid || !IsAllowedByApiTokenScope(scope, resource, action, resourceItem) {
 		return false
 	}
 	return e.EnforceByEmail(email, resource, action, resourceItem)
@@ -402,11 +406,26 @@ func (e *EnforcerImpl) enforce(token string, resource string, action string, res
 
 // enforceInBatch is a helper to additionally check a default role and invoke a custom claims enforcement function
 func (e *EnforcerImpl) enforceInBatch(token string, resource string, action string, vals []string) map[string]bool {
-	email, invalid := e.VerifyTokenAndGetEmail(token)
+	email, scope, invalid := e.verifyTokenAndGetEmailAndScope(token)
 	if invalid {
 		return make(map[string]bool)
 	}
-	return e.EnforceByEmailInBatch(email, resource, action, vals)
+	if scope == nil {
+		return e.EnforceByEmailInBatch(email, resource, action, vals)
+	}
+	allowedVals := make([]string, 0, len(vals))
+	for _, val := range vals {
+		if IsAllowedByApiTokenScope(scope, resource, action, val) {
+			allowedVals = append(allowedVals, val)
+		}
+	}
+	result := e.EnforceByEmailInBatch(email, resource, action, allowedVals)
+	for _, val := range vals {
+		if _, ok := result[val]; !ok {
+			result[val] = false
+		}
+	}
+	return result
 }
 
 func (e *EnforcerImpl) enforceAndUpdateCache(email string, resource string, action string, resourceItem string) bool {
@@ -451,25 +470,37 @@ func (e *EnforcerImpl) enforcerEnforce(email string, resource string, action str
 }
 
 func (e *EnforcerImpl) VerifyTokenAndGetEmail(tokenString string) (string, bool) {
+	email, _, invalid := e.verifyTokenAndGetEmailAndScope(tokenString)
+	return email, invalid
+}
+
+// verifyTokenAndGetEmailAndScope also returns the scope embedded in api tokens, a token with an unreadable scope is invalid
+func (e *EnforcerImpl) verifyTokenAndGetEmailAndScope(tokenString string) (string, *bean.ApiTokenScope, bool) {
 	claims, err := e.SessionManager.VerifyToken(tokenString)
 	if err != nil {
-		return "", true
+		return "", nil, true
 	}
 	mapClaims, err := jwt.MapClaims(claims)
 	if err != nil {
-		return "", true
+		return "", nil, true
+	}
+	scope, err := GetApiTokenScopeFromClaims(mapClaims)
+	if err != nil {
+		e.logger.Errorw("error in reading api token scope from token claims", "err", err)
+		return "", nil, true
 	}
 	email := jwt.GetField(mapClaims, "email")
 	sub := jwt.GetField(mapClaims, "sub")
 	if email == "" && (sub == "admin" || sub == "admin:login") {
 		email = "admin"
 	}
-	return email, false
+	return email, scope, false
 }
 
 // enforce is a helper to additionally check a default role and invoke a custom claims enforcement function
 func (e *EnforcerImpl) enforceByEmail(emailId string, resource string, action string, resourceItem string) bool {
 	defer handlePanic()
+	syncTimeBoundAccessIfDue(emailId)
 	response, found := e.enforceFromCache(emailId, resource, action, resourceItem)
 	if found {
 		cacheData := e.getEnforcerCacheLock(emailId)
@@ -0,0 +1,983 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/pkg/apiToken"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	"github.com/devtron-labs/devtron/pkg/auth/scim/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/scim/helper"
+	"github.com/devtron-labs/devtron/pkg/auth/scim/repository"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	userHelper "github.com/devtron-labs/devtron/pkg/auth/user/helper"
+	userRepository "github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	userUtil "github.com/devtron-labs/devtron/pkg/auth/user/util"
+	"github.com/devtron-labs/devtron/pkg/clusterTerminalAccess"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/pkg/terminal"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ScimService serves the Users and Groups resources of SCIM 2.0 (RFC 7643, RFC 7644) for identity providers.
+// Users are devtron users identified by their email id, groups are role groups. Deprovisioning a user deactivates it,
+// revokes the api tokens it created and disconnects its terminal sessions.
+type ScimService interface {
+	// Authenticate accepts only the api token dedicated to scim provisioning
+	Authenticate(ctx context.Context, token string) (*bean.Actor, error)
+
+	ListUsers(request *bean.ListRequest) (*bean.ListResponse, error)
+	GetUser(id string) (*bean.User, error)
+	CreateUser(ctx context.Context, actor *bean.Actor, scimUser *bean.User) (*bean.User, error)
+	ReplaceUser(ctx context.Context, actor *bean.Actor, id string, scimUser *bean.User) (*bean.User, error)
+	PatchUser(ctx context.Context, actor *bean.Actor, id string, request *bean.PatchRequest) (*bean.User, error)
+	DeleteUser(ctx context.Context, actor *bean.Actor, id string) error
+
+	ListGroups(request *bean.ListRequest) (*bean.ListResponse, error)
+	GetGroup(id string) (*bean.Group, error)
+	CreateGroup(actor *bean.Actor, scimGroup *bean.Group) (*bean.Group, error)
+	ReplaceGroup(actor *bean.Actor, id string, scimGroup *bean.Group) (*bean.Group, error)
+	PatchGroup(actor *bean.Actor, id string, request *bean.PatchRequest) (*bean.Group, error)
+	DeleteGroup(actor *bean.Actor, id string) error
+}
+
+type ScimServiceImpl struct {
+	logger                    *zap.SugaredLogger
+	config                    *bean.Config
+	scimResourceRepository    repository.ScimResourceRepository
+	userService               user.UserService
+	userRepository            userRepository.UserRepository
+	roleGroupService          user.RoleGroupService
+	roleGroupRepository       userRepository.RoleGroupRepository
+	apiTokenService           apiToken.ApiTokenService
+	apiTokenRepository        apiToken.ApiTokenRepository
+	userTerminalAccessService clusterTerminalAccess.UserTerminalAccessService
+	terminalSessionHandler    terminal.TerminalSessionHandler
+	enforcer                  casbin.Enforcer
+}
+
+func NewScimServiceImpl(logger *zap.SugaredLogger,
+	scimResourceRepository repository.ScimResourceRepository,
+	userService user.UserService,
+	userRepository userRepository.UserRepository,
+	roleGroupService user.RoleGroupService,
+	roleGroupRepository userRepository.RoleGroupRepository,
+	apiTokenService apiToken.ApiTokenService,
+	apiTokenRepository apiToken.ApiTokenRepository,
+	userTerminalAccessService clusterTerminalAccess.UserTerminalAccessService,
+	terminalSessionHandler terminal.TerminalSessionHandler,
+	enforcer casbin.Enforcer) (*ScimServiceImpl, error) {
+	config := &bean.Config{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing scim config", "err", err)
+		return nil, err
+	}
+	return &ScimServiceImpl{
+		logger:                    logger,
+		config:                    config,
+		scimResourceRepository:    scimResourceRepository,
+		userService:               userService,
+		userRepository:            userRepository,
+		roleGroupService:          roleGroupService,
+		roleGroupRepository:       roleGroupRepository,
+		apiTokenService:           apiTokenService,
+		apiTokenRepository:        apiTokenRepository,
+		userTerminalAccessService: userTerminalAccessService,
+		terminalSessionHandler:    terminalSessionHandler,
+		enforcer:                  enforcer,
+	}, nil
+}
+
+func (impl *ScimServiceImpl) Authenticate(ctx context.Context, token string) (*bean.Actor, error) {
+	if !impl.config.Enabled {
+		return nil, bean.NewError(http.StatusForbidden, "", bean.ProvisioningDisabledMessage)
+	}
+	if len(token) == 0 {
+		return nil, bean.NewError(http.StatusUnauthorized, "", bean.InvalidTokenMessage)
+	}
+	userId, userType, err := impl.userService.GetUserByToken(ctx, token)
+	if err != nil || userType != userBean.USER_TYPE_API_TOKEN {
+		return nil, bean.NewError(http.StatusUnauthorized, "", bean.InvalidTokenMessage)
+	}
+	emailId, err := impl.userService.GetActiveEmailById(userId)
+	if err != nil {
+		impl.logger.Errorw("error in getting email of scim token user", "userId", userId, "err", err)
+		return nil, err
+	}
+	if emailId != strings.ToLower(fmt.Sprintf("%s%s", userBean.API_TOKEN_USER_EMAIL_PREFIX, impl.config.ApiTokenName)) {
+		return nil, bean.NewError(http.StatusForbidden, "", bean.InvalidTokenMessage)
+	}
+	return &bean.Actor{UserId: userId, Token: token}, nil
+}
+
+func (impl *ScimServiceImpl) ListUsers(request *bean.ListRequest) (*bean.ListResponse, error) {
+	filter, err := helper.ParseFilter(request.Filter)
+	if err != nil {
+		return nil, err
+	}
+	models, resourceByUserId, err := impl.getProvisionableUsers()
+	if err != nil {
+		return nil, err
+	}
+	var matched []*userRepository.UserModel
+	for _, model := range models {
+		if filter.Matches(getUserAttributeValues(model, resourceByUserId[model.Id])) {
+			matched = append(matched, model)
+		}
+	}
+	from, to := helper.GetPage(len(matched), request.StartIndex, request.Count)
+	resources := make([]interface{}, 0, to-from)
+	for _, model := range matched[from:to] {
+		scimUser, err := impl.buildUser(model, resourceByUserId[model.Id])
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, scimUser)
+	}
+	return buildListResponse(len(matched), from, resources), nil
+}
+
+func (impl *ScimServiceImpl) GetUser(id string) (*bean.User, error) {
+	model, resource, err := impl.getUser(id)
+	if err != nil {
+		return nil, err
+	}
+	return impl.buildUser(model, resource)
+}
+
+func (impl *ScimServiceImpl) CreateUser(ctx context.Context, actor *bean.Actor, scimUser *bean.User) (*bean.User, error) {
+	emailId := helper.GetEmailId(scimUser)
+	if len(emailId) == 0 {
+		return nil, bean.NewError(http.StatusBadRequest, bean.ErrorTypeInvalidValue, bean.UserNameRequiredMessage)
+	}
+	existingUser, err := impl.userRepository.FetchActiveOrDeletedUserByEmail(emailId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting user by email", "emailId", emailId, "err", err)
+		return nil, err
+	}
+	if existingUser != nil && existingUser.Id > 0 && (existingUser.Active || userUtil.CheckIfAdminOrApiToken(existingUser.EmailId)) {
+		return nil, bean.NewError(http.StatusConflict, bean.ErrorTypeUniqueness, fmt.Sprintf(bean.UserAlreadyExistsMessage, emailId))
+	}
+	createdUsers, err := impl.userService.CreateUser(&userBean.UserInfo{EmailId: emailId, UserId: actor.UserId}, actor.Token, impl.getManagerAuth())
+	if err != nil {
+		impl.logger.Errorw("error in creating user over scim", "emailId", emailId, "err", err)
+		return nil, err
+	}
+	if len(createdUsers) != 1 {
+		return nil, fmt.Errorf("expected one user to be created for %s, found %d", emailId, len(createdUsers))
+	}
+	userId := createdUsers[0].Id
+	err = impl.scimResourceRepository.Save(&repository.ScimResource{
+		ResourceType: bean.ResourceTypeUser,
+		ResourceId:   userId,
+		ExternalId:   scimUser.ExternalId,
+		UserName:     scimUser.UserName,
+		Active:       true,
+		AuditLog:     sql.NewDefaultAuditLog(actor.UserId),
+	})
+	if err != nil {
+		impl.logger.Errorw("error in saving scim resource for user", "userId", userId, "err", err)
+		return nil, err
+	}
+	if scimUser.Active != nil && !*scimUser.Active {
+		model, err := impl.userRepository.GetById(userId)
+		if err != nil {
+			return nil, err
+		}
+		err = impl.deprovisionUser(ctx, actor, model)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return impl.GetUser(strconv.Itoa(int(userId)))
+}
+
+func (impl *ScimServiceImpl) ReplaceUser(ctx context.Context, actor *bean.Actor, id string, scimUser *bean.User) (*bean.User, error) {
+	model, resource, err := impl.getUser(id)
+	if err != nil {
+		return nil, err
+	}
+	if emailId := helper.GetEmailId(scimUser); emailId != model.EmailId {
+		return nil, bean.NewError(http.StatusBadRequest, bean.ErrorTypeMutability, bean.UserNameImmutableMessage)
+	}
+	resource.ExternalId = scimUser.ExternalId
+	resource.UserName = scimUser.UserName
+	err = impl.saveResource(actor, resource)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.setUserActive(ctx, actor, model, scimUser.Active == nil || *scimUser.Active)
+	if err != nil {
+		return nil, err
+	}
+	return impl.GetUser(id)
+}
+
+// PatchUser applies changes to active and externalId, attributes devtron does not keep are accepted and ignored
+func (impl *ScimServiceImpl) PatchUser(ctx context.Context, actor *bean.Actor, id string, request *bean.PatchRequest) (*bean.User, error) {
+	model, resource, err := impl.getUser(id)
+	if err != nil {
+		return nil, err
+	}
+	active := model.Active
+	for _, operation := range request.Operations {
+		values, err := getPatchValues(operation)
+		if err != nil {
+			return nil, err
+		}
+		for path, value := range values {
+			remove := strings.EqualFold(operation.Op, string(bean.PatchOperationRemove))
+			switch path {
+			case "active":
+				if remove {
+					continue
+				}
+				active, err = helper.ParseBoolValue(path, value)
+			case "externalid":
+				resource.ExternalId = ""
+				if !remove {
+					resource.ExternalId, err = helper.ParseStringValue(path, value)
+				}
+			case "username":
+				var userName string
+				userName, err = helper.ParseStringValue(path, value)
+				if err == nil && !strings.EqualFold(userName, model.EmailId) && !strings.EqualFold(userName, resource.UserName) {
+					err = bean.NewError(http.StatusBadRequest, bean.ErrorTypeMutability, bean.UserNameImmutableMessage)
+				}
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	err = impl.saveResource(actor, resource)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.setUserActive(ctx, actor, model, active)
+	if err != nil {
+		return nil, err
+	}
+	return impl.GetUser(id)
+}
+
+func (impl *ScimServiceImpl) DeleteUser(ctx context.Context, actor *bean.Actor, id string) error {
+	model, resource, err := impl.getUser(id)
+	if err != nil {
+		return err
+	}
+	if model.Active {
+		err = impl.deprovisionUser(ctx, actor, model)
+		if err != nil {
+			return err
+		}
+	}
+	if resource.Id > 0 {
+		resource.Active = false
+		resource.UpdateAuditLog(actor.UserId)
+		err = impl.scimResourceRepository.Update(resource)
+		if err != nil {
+			impl.logger.Errorw("error in deleting scim resource of user", "userId", model.Id, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (impl *ScimServiceImpl) ListGroups(request *bean.ListRequest) (*bean.ListResponse, error) {
+	filter, err := helper.ParseFilter(request.Filter)
+	if err != nil {
+		return nil, err
+	}
+	roleGroups, err := impl.roleGroupRepository.GetAllRoleGroup()
+	if err != nil {
+		impl.logger.Errorw("error in getting role groups", "err", err)
+		return nil, err
+	}
+	sort.Slice(roleGroups, func(i, j int) bool { return roleGroups[i].Id < roleGroups[j].Id })
+	resourceByGroupId, err := impl.getResourcesById(bean.ResourceTypeGroup)
+	if err != nil {
+		return nil, err
+	}
+	usersByEmail, err := impl.getActiveUsersByEmail()
+	if err != nil {
+		return nil, err
+	}
+	var matched []*userRepository.RoleGroup
+	for _, roleGroup := range roleGroups {
+		getMembers := func() []*bean.MultiValuedAttribute { return impl.getGroupMembers(roleGroup, usersByEmail) }
+		if filter.Matches(getGroupAttributeValues(roleGroup, resourceByGroupId[roleGroup.Id], getMembers)) {
+			matched = append(matched, roleGroup)
+		}
+	}
+	from, to := helper.GetPage(len(matched), request.StartIndex, request.Count)
+	resources := make([]interface{}, 0, to-from)
+	for _, roleGroup := range matched[from:to] {
+		resources = append(resources, buildGroup(roleGroup, resourceByGroupId[roleGroup.Id], impl.getGroupMembers(roleGroup, usersByEmail)))
+	}
+	return buildListResponse(len(matched), from, resources), nil
+}
+
+func (impl *ScimServiceImpl) GetGroup(id string) (*bean.Group, error) {
+	roleGroup, resource, err := impl.getRoleGroup(id)
+	if err != nil {
+		return nil, err
+	}
+	usersByEmail, err := impl.getActiveUsersByEmail()
+	if err != nil {
+		return nil, err
+	}
+	return buildGroup(roleGroup, resource, impl.getGroupMembers(roleGroup, usersByEmail)), nil
+}
+
+// CreateGroup creates a role group without permissions, these are granted to the role group in devtron
+func (impl *ScimServiceImpl) CreateGroup(actor *bean.Actor, scimGroup *bean.Group) (*bean.Group, error) {
+	if len(strings.TrimSpace(scimGroup.DisplayName)) == 0 {
+		return nil, bean.NewError(http.StatusBadRequest, bean.ErrorTypeInvalidValue, fmt.Sprintf(bean.InvalidPatchValueMessage, "displayName"))
+	}
+	exists, err := impl.roleGroupRepository.CheckRoleGroupExistByCasbinName(userHelper.GetCasbinNameFromRoleGroupName(scimGroup.DisplayName))
+	if err != nil {
+		impl.logger.Errorw("error in checking role group by name", "name", scimGroup.DisplayName, "err", err)
+		return nil, err
+	} else if exists {
+		return nil, bean.NewError(http.StatusConflict, bean.ErrorTypeUniqueness, fmt.Sprintf(bean.GroupAlreadyExistsMessage, scimGroup.DisplayName))
+	}
+	roleGroup, err := impl.roleGroupService.CreateRoleGroup(&userBean.RoleGroup{
+		Name:        scimGroup.DisplayName,
+		RoleFilters: make([]userBean.RoleFilter, 0),
+		UserId:      actor.UserId,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in creating role group over scim", "name", scimGroup.DisplayName, "err", err)
+		return nil, err
+	}
+	err = impl.scimResourceRepository.Save(&repository.ScimResource{
+		ResourceType: bean.ResourceTypeGroup,
+		ResourceId:   roleGroup.Id,
+		ExternalId:   scimGroup.ExternalId,
+		Active:       true,
+		AuditLog:     sql.NewDefaultAuditLog(actor.UserId),
+	})
+	if err != nil {
+		impl.logger.Errorw("error in saving scim resource for group", "roleGroupId", roleGroup.Id, "err", err)
+		return nil, err
+	}
+	id := strconv.Itoa(int(roleGroup.Id))
+	err = impl.setGroupMembers(actor, id, getMemberIds(scimGroup.Members))
+	if err != nil {
+		return nil, err
+	}
+	return impl.GetGroup(id)
+}
+
+func (impl *ScimServiceImpl) ReplaceGroup(actor *bean.Actor, id string, scimGroup *bean.Group) (*bean.Group, error) {
+	roleGroup, resource, err := impl.getRoleGroup(id)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(scimGroup.DisplayName, roleGroup.Name) {
+		return nil, bean.NewError(http.StatusBadRequest, bean.ErrorTypeMutability, bean.DisplayNameImmutableMessage)
+	}
+	resource.ExternalId = scimGroup.ExternalId
+	err = impl.saveResource(actor, resource)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.setGroupMembers(actor, id, getMemberIds(scimGroup.Members))
+	if err != nil {
+		return nil, err
+	}
+	return impl.GetGroup(id)
+}
+
+// PatchGroup applies member additions and removals one operation at a time, as identity providers send them
+func (impl *ScimServiceImpl) PatchGroup(actor *bean.Actor, id string, request *bean.PatchRequest) (*bean.Group, error) {
+	roleGroup, resource, err := impl.getRoleGroup(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, operation := range request.Operations {
+		opType := bean.PatchOperationType(strings.ToLower(operation.Op))
+		if strings.HasPrefix(strings.ToLower(operation.Path), "members[") {
+			// members[value eq "id"] addresses a single member
+			condition, err := helper.ParseValuePath(operation.Path)
+			if err != nil || condition.Attribute != "members.value" || condition.Operator != helper.OperatorEqual || opType != bean.PatchOperationRemove {
+				return nil, bean.NewError(http.StatusBadRequest, bean.ErrorTypeInvalidPath, fmt.Sprintf(bean.UnsupportedPatchPathMessage, operation.Path))
+			}
+			err = impl.updateGroupMembers(actor, roleGroup, nil, []string{condition.Value})
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		values, err := getPatchValues(operation)
+		if err != nil {
+			return nil, err
+		}
+		for path, value := range values {
+			switch path {
+			case "members":
+				err = impl.patchGroupMembers(actor, roleGroup, id, opType, value)
+			case "externalid":
+				resource.ExternalId = ""
+				if opType != bean.PatchOperationRemove {
+					resource.ExternalId, err = helper.ParseStringValue(path, value)
+				}
+				if err == nil {
+					err = impl.saveResource(actor, resource)
+				}
+			case "displayname":
+				var displayName string
+				displayName, err = helper.ParseStringValue(path, value)
+				if err == nil && !strings.EqualFold(displayName, roleGroup.Name) {
+					err = bean.NewError(http.StatusBadRequest, bean.ErrorTypeMutability, bean.DisplayNameImmutableMessage)
+				}
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return impl.GetGroup(id)
+}
+
+func (impl *ScimServiceImpl) DeleteGroup(actor *bean.Actor, id string) error {
+	roleGroup, resource, err := impl.getRoleGroup(id)
+	if err != nil {
+		return err
+	}
+	_, err = impl.roleGroupService.DeleteRoleGroup(&userBean.RoleGroup{Id: roleGroup.Id, UserId: actor.UserId})
+	if err != nil {
+		impl.logger.Errorw("error in deleting role group over scim", "roleGroupId", roleGroup.Id, "err", err)
+		return err
+	}
+	if resource.Id > 0 {
+		resource.Active = false
+		resource.UpdateAuditLog(actor.UserId)
+		err = impl.scimResourceRepository.Update(resource)
+		if err != nil {
+			impl.logger.Errorw("error in deleting scim resource of group", "roleGroupId", roleGroup.Id, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// getUser returns users deactivated over scim too, an empty resource is returned for users not provisioned over scim
+func (impl *ScimServiceImpl) getUser(id string) (*userRepository.UserModel, *repository.ScimResource, error) {
+	notFoundErr := bean.NewError(http.StatusNotFound, "", fmt.Sprintf(bean.UserNotFoundMessage, id))
+	userId, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, nil, notFoundErr
+	}
+	model, err := impl.userRepository.GetByIdIncludeDeleted(int32(userId))
+	if err == pg.ErrNoRows {
+		return nil, nil, notFoundErr
+	} else if err != nil {
+		impl.logger.Errorw("error in getting user", "userId", userId, "err", err)
+		return nil, nil, err
+	}
+	resource, err := impl.getResource(bean.ResourceTypeUser, model.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isProvisionable(model) || (!model.Active && resource.Id == 0) {
+		return nil, nil, notFoundErr
+	}
+	return model, resource, nil
+}
+
+func (impl *ScimServiceImpl) getRoleGroup(id string) (*userRepository.RoleGroup, *repository.ScimResource, error) {
+	notFoundErr := bean.NewError(http.StatusNotFound, "", fmt.Sprintf(bean.GroupNotFoundMessage, id))
+	roleGroupId, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, nil, notFoundErr
+	}
+	roleGroup, err := impl.roleGroupRepository.GetRoleGroupById(int32(roleGroupId))
+	if err == pg.ErrNoRows || (err == nil && !roleGroup.Active) {
+		return nil, nil, notFoundErr
+	} else if err != nil {
+		impl.logger.Errorw("error in getting role group", "roleGroupId", roleGroupId, "err", err)
+		return nil, nil, err
+	}
+	resource, err := impl.getResource(bean.ResourceTypeGroup, roleGroup.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return roleGroup, resource, nil
+}
+
+func (impl *ScimServiceImpl) getResource(resourceType bean.ResourceType, resourceId int32) (*repository.ScimResource, error) {
+	resource, err := impl.scimResourceRepository.FindActiveByResource(resourceType, resourceId)
+	if err == pg.ErrNoRows {
+		return &repository.ScimResource{ResourceType: resourceType, ResourceId: resourceId, Active: true}, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in getting scim resource", "resourceType", resourceType, "resourceId", resourceId, "err", err)
+		return nil, err
+	}
+	return resource, nil
+}
+
+func (impl *ScimServiceImpl) getResourcesById(resourceType bean.ResourceType) (map[int32]*repository.ScimResource, error) {
+	resources, err := impl.scimResourceRepository.FindAllActiveByResourceType(resourceType)
+	if err != nil {
+		impl.logger.Errorw("error in getting scim resources", "resourceType", resourceType, "err", err)
+		return nil, err
+	}
+	resourceById := make(map[int32]*repository.ScimResource, len(resources))
+	for _, resource := range resources {
+		resourceById[resource.ResourceId] = resource
+	}
+	return resourceById, nil
+}
+
+// saveResource starts tracking users and role groups which were not provisioned over scim on their first change
+func (impl *ScimServiceImpl) saveResource(actor *bean.Actor, resource *repository.ScimResource) error {
+	var err error
+	if resource.Id > 0 {
+		resource.UpdateAuditLog(actor.UserId)
+		err = impl.scimResourceRepository.Update(resource)
+	} else {
+		resource.AuditLog = sql.NewDefaultAuditLog(actor.UserId)
+		err = impl.scimResourceRepository.Save(resource)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving scim resource", "resourceType", resource.ResourceType, "resourceId", resource.ResourceId, "err", err)
+		return err
+	}
+	return nil
+}
+
+// getProvisionableUsers returns the active users along with the users deactivated over scim, ordered by id
+func (impl *ScimServiceImpl) getProvisionableUsers() ([]*userRepository.UserModel, map[int32]*repository.ScimResource, error) {
+	activeUsers, err := impl.userRepository.GetAllExcludingApiTokenUser()
+	if err != nil {
+		impl.logger.Errorw("error in getting users", "err", err)
+		return nil, nil, err
+	}
+	resourceByUserId, err := impl.getResourcesById(bean.ResourceTypeUser)
+	if err != nil {
+		return nil, nil, err
+	}
+	models := make([]*userRepository.UserModel, 0, len(activeUsers))
+	activeUserIds := make(map[int32]bool, len(activeUsers))
+	for i := range activeUsers {
+		activeUserIds[activeUsers[i].Id] = true
+		if isProvisionable(&activeUsers[i]) {
+			models = append(models, &activeUsers[i])
+		}
+	}
+	for userId := range resourceByUserId {
+		if activeUserIds[userId] {
+			continue
+		}
+		model, err := impl.userRepository.GetByIdIncludeDeleted(userId)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in getting user", "userId", userId, "err", err)
+			return nil, nil, err
+		}
+		if err == nil && !model.Active && isProvisionable(model) {
+			models = append(models, model)
+		}
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].Id < models[j].Id })
+	return models, resourceByUserId, nil
+}
+
+func (impl *ScimServiceImpl) getActiveUsersByEmail() (map[string]*userRepository.UserModel, error) {
+	activeUsers, err := impl.userRepository.GetAllExcludingApiTokenUser()
+	if err != nil {
+		impl.logger.Errorw("error in getting users", "err", err)
+		return nil, err
+	}
+	usersByEmail := make(map[string]*userRepository.UserModel, len(activeUsers))
+	for i := range activeUsers {
+		if isProvisionable(&activeUsers[i]) {
+			usersByEmail[strings.ToLower(activeUsers[i].EmailId)] = &activeUsers[i]
+		}
+	}
+	return usersByEmail, nil
+}
+
+func (impl *ScimServiceImpl) buildUser(model *userRepository.UserModel, resource *repository.ScimResource) (*bean.User, error) {
+	active := model.Active
+	scimUser := &bean.User{
+		Schemas:     []string{bean.UserSchema},
+		Id:          strconv.Itoa(int(model.Id)),
+		UserName:    model.EmailId,
+		DisplayName: model.EmailId,
+		Emails:      []*bean.MultiValuedAttribute{{Value: model.EmailId, Type: "work", Primary: true}},
+		Active:      &active,
+		Meta:        buildMeta(bean.ResourceTypeUser, model.Id, model.AuditLog),
+	}
+	if resource != nil {
+		scimUser.ExternalId = resource.ExternalId
+		if len(resource.UserName) > 0 {
+			scimUser.UserName = resource.UserName
+		}
+	}
+	if !model.Active {
+		return scimUser, nil
+	}
+	groups, err := impl.getUserGroups(model.EmailId)
+	if err != nil {
+		return nil, err
+	}
+	scimUser.Groups = groups
+	return scimUser, nil
+}
+
+func (impl *ScimServiceImpl) getUserGroups(emailId string) ([]*bean.MultiValuedAttribute, error) {
+	roles, err := casbin.GetRolesForUser(emailId)
+	if err != nil {
+		impl.logger.Errorw("error in getting casbin roles of user", "emailId", emailId, "err", err)
+		return nil, err
+	}
+	var groupCasbinNames []string
+	for _, role := range roles {
+		if strings.HasPrefix(role, "group:") {
+			groupCasbinNames = append(groupCasbinNames, role)
+		}
+	}
+	if len(groupCasbinNames) == 0 {
+		return nil, nil
+	}
+	roleGroups, err := impl.roleGroupRepository.GetRoleGroupListByCasbinNames(groupCasbinNames)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting role groups by casbin names", "casbinNames", groupCasbinNames, "err", err)
+		return nil, err
+	}
+	groups := make([]*bean.MultiValuedAttribute, 0, len(roleGroups))
+	for _, roleGroup := range roleGroups {
+		groups = append(groups, &bean.MultiValuedAttribute{
+			Value:   strconv.Itoa(int(roleGroup.Id)),
+			Display: roleGroup.Name,
+			Ref:     getLocation(bean.ResourceTypeGroup, roleGroup.Id),
+		})
+	}
+	return groups, nil
+}
+
+func (impl *ScimServiceImpl) getGroupMembers(roleGroup *userRepository.RoleGroup, usersByEmail map[string]*userRepository.UserModel) []*bean.MultiValuedAttribute {
+	emailIds, err := casbin.GetUserByRole(roleGroup.CasbinName)
+	if err != nil {
+		impl.logger.Errorw("error in getting users of role group", "casbinName", roleGroup.CasbinName, "err", err)
+		return nil
+	}
+	members := make([]*bean.MultiValuedAttribute, 0, len(emailIds))
+	for _, emailId := range emailIds {
+		if model, ok := usersByEmail[strings.ToLower(emailId)]; ok {
+			members = append(members, &bean.MultiValuedAttribute{
+				Value:   strconv.Itoa(int(model.Id)),
+				Display: model.EmailId,
+				Ref:     getLocation(bean.ResourceTypeUser, model.Id),
+			})
+		}
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].Value < members[j].Value })
+	return members
+}
+
+func (impl *ScimServiceImpl) patchGroupMembers(actor *bean.Actor, roleGroup *userRepository.RoleGroup, id string, opType bean.PatchOperationType, value json.RawMessage) error {
+	var memberIds []string
+	if len(value) > 0 {
+		var err error
+		memberIds, err = helper.ParseMemberIds("members", value)
+		if err != nil {
+			return err
+		}
+	}
+	switch opType {
+	case bean.PatchOperationAdd:
+		return impl.updateGroupMembers(actor, roleGroup, memberIds, nil)
+	case bean.PatchOperationRemove:
+		if len(value) == 0 {
+			// removing the members attribute removes all the members
+			return impl.setGroupMembers(actor, id, nil)
+		}
+		return impl.updateGroupMembers(actor, roleGroup, nil, memberIds)
+	case bean.PatchOperationReplace:
+		return impl.setGroupMembers(actor, id, memberIds)
+	}
+	return bean.NewError(http.StatusBadRequest, bean.ErrorTypeInvalidSyntax, fmt.Sprintf(bean.UnsupportedPatchOpMessage, opType))
+}
+
+// setGroupMembers makes the given users the only members of the role group
+func (impl *ScimServiceImpl) setGroupMembers(actor *bean.Actor, id string, memberIds []string) error {
+	roleGroup, _, err := impl.getRoleGroup(id)
+	if err != nil {
+		return err
+	}
+	usersByEmail, err := impl.getActiveUsersByEmail()
+	if err != nil {
+		return err
+	}
+	desired := make(map[string]bool, len(memberIds))
+	for _, memberId := range memberIds {
+		desired[memberId] = true
+	}
+	var removedIds []string
+	for _, member := range impl.getGroupMembers(roleGroup, usersByEmail) {
+		if !desired[member.Value] {
+			removedIds = append(removedIds, member.Value)
+		}
+	}
+	return impl.updateGroupMembers(actor, roleGroup, memberIds, removedIds)
+}
+
+// updateGroupMembers adds and removes the role group on each user through UserService so that the change is
+// audited and rbac checked like any other user update
+func (impl *ScimServiceImpl) updateGroupMembers(actor *bean.Actor, roleGroup *userRepository.RoleGroup, addedIds, removedIds []string) error {
+	changes := make(map[int32]bool, len(addedIds)+len(removedIds))
+	for ids, isMember := range map[*[]string]bool{&addedIds: true, &removedIds: false} {
+		for _, id := range *ids {
+			userId, err := strconv.Atoi(id)
+			if err != nil {
+				return bean.NewError(http.StatusBadRequest, bean.ErrorTypeInvalidValue, fmt.Sprintf(bean.UserNotFoundMessage, id))
+			}
+			changes[int32(userId)] = isMember
+		}
+	}
+	for userId, isMember := range changes {
+		err := impl.setRoleGroupMembership(actor, userId, roleGroup, isMember)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (impl *ScimServiceImpl) setRoleGroupMembership(actor *bean.Actor, userId int32, roleGroup *userRepository.RoleGroup, isMember bool) error {
+	userInfo, err := impl.userService.GetByIdWithoutGroupClaims(userId)
+	if err == pg.ErrNoRows {
+		if !isMember {
+			return nil
+		}
+		return bean.NewError(http.StatusBadRequest, bean.ErrorTypeInvalidValue, fmt.Sprintf(bean.UserNotFoundMessage, strconv.Itoa(int(userId))))
+	} else if err != nil {
+		impl.logger.Errorw("error in getting user", "userId", userId, "err", err)
+		return err
+	}
+	index := -1
+	for i, userRoleGroup := range userInfo.UserRoleGroup {
+		if userRoleGroup.RoleGroup != nil && userRoleGroup.RoleGroup.Id == roleGroup.Id {
+			index = i
+			break
+		}
+	}
+	if isMember == (index >= 0) {
+		return nil
+	}
+	if isMember {
+		userInfo.UserRoleGroup = append(userInfo.UserRoleGroup, userBean.UserRoleGroup{
+			RoleGroup: &userBean.RoleGroup{Id: roleGroup.Id, Name: roleGroup.Name},
+		})
+	} else if userInfo.UserRoleGroup[index].Source == userBean.RoleGroupMembershipSourceIdp {
+		// memberships synced from group claims are owned by the group claims sync
+		return nil
+	} else {
+		userInfo.UserRoleGroup = append(userInfo.UserRoleGroup[:index], userInfo.UserRoleGroup[index+1:]...)
+	}
+	userInfo.UserId = actor.UserId
+	_, err = impl.userService.UpdateUser(userInfo, actor.Token, nil, impl.getManagerAuth())
+	if err != nil {
+		impl.logger.Errorw("error in updating role group membership over scim", "userId", userId, "roleGroupId", roleGroup.Id, "isMember", isMember, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *ScimServiceImpl) setUserActive(ctx context.Context, actor *bean.Actor, model *userRepository.UserModel, active bool) error {
+	if active == model.Active {
+		return nil
+	}
+	if !active {
+		return impl.deprovisionUser(ctx, actor, model)
+	}
+	_, err := impl.userService.CreateUser(&userBean.UserInfo{EmailId: model.EmailId, UserId: actor.UserId}, actor.Token, impl.getManagerAuth())
+	if err != nil {
+		impl.logger.Errorw("error in reactivating user over scim", "userId", model.Id, "err", err)
+		return err
+	}
+	return nil
+}
+
+// deprovisionedSessionMsg is shown on the terminal sessions closed on deprovisioning
+const deprovisionedSessionMsg = "User deprovisioned"
+
+// deprovisionUser deactivates the user, revokes the api tokens it created and disconnects its terminal sessions
+func (impl *ScimServiceImpl) deprovisionUser(ctx context.Context, actor *bean.Actor, model *userRepository.UserModel) error {
+	if model.Id == actor.UserId {
+		return bean.NewError(http.StatusBadRequest, bean.ErrorTypeMutability, bean.SelfDeprovisionMessage)
+	}
+	// access is cut before the user is deactivated, a failure leaves the user active and the idp retries
+	apiTokens, err := impl.apiTokenRepository.FindAllActive()
+	if err != nil {
+		impl.logger.Errorw("error in getting api tokens", "err", err)
+		return err
+	}
+	for _, token := range apiTokens {
+		if token.CreatedBy != model.Id || token.User == nil {
+			continue
+		}
+		_, err = impl.apiTokenService.DeleteApiToken(token.Id, actor.UserId)
+		if err != nil {
+			impl.logger.Errorw("error in revoking api token of deprovisioned user", "userId", model.Id, "apiTokenId", token.Id, "err", err)
+			return err
+		}
+	}
+	impl.terminalSessionHandler.CloseUserSessions(model.Id, 1, deprovisionedSessionMsg)
+	impl.userTerminalAccessService.DisconnectAllSessionsForUser(ctx, model.Id)
+	_, err = impl.userService.DeleteUser(&userBean.UserInfo{Id: model.Id, UserId: actor.UserId})
+	if err != nil {
+		impl.logger.Errorw("error in deactivating user over scim", "userId", model.Id, "err", err)
+		return err
+	}
+	impl.logger.Infow("deprovisioned user over scim", "userId", model.Id, "emailId", model.EmailId)
+	return nil
+}
+
+func (impl *ScimServiceImpl) getManagerAuth() func(resource, token, object string) bool {
+	return func(resource, token, object string) bool {
+		return impl.enforcer.Enforce(token, resource, casbin.ActionUpdate, object)
+	}
+}
+
+// isProvisionable leaves out the system, admin and api token users which are managed by devtron itself
+func isProvisionable(model *userRepository.UserModel) bool {
+	return model.Id != userBean.SystemUserId && model.UserType != userBean.USER_TYPE_API_TOKEN && !userUtil.CheckIfAdminOrApiToken(model.EmailId)
+}
+
+// getPatchValues maps the lower cased path of the operation to its value, operations without a path carry an object
+// of attributes
+func getPatchValues(operation *bean.PatchOperation) (map[string]json.RawMessage, error) {
+	switch bean.PatchOperationType(strings.ToLower(operation.Op)) {
+	case bean.PatchOperationAdd, bean.PatchOperationRemove, bean.PatchOperationReplace:
+	default:
+		return nil, bean.NewError(http.StatusBadRequest, bean.ErrorTypeInvalidSyntax, fmt.Sprintf(bean.UnsupportedPatchOpMessage, operation.Op))
+	}
+	if len(operation.Path) > 0 {
+		return map[string]json.RawMessage{strings.ToLower(operation.Path): operation.Value}, nil
+	}
+	var attributes map[string]json.RawMessage
+	if err := json.Unmarshal(operation.Value, &attributes); err != nil {
+		return nil, bean.NewError(http.StatusBadRequest, bean.ErrorTypeInvalidValue, fmt.Sprintf(bean.InvalidPatchValueMessage, ""))
+	}
+	values := make(map[string]json.RawMessage, len(attributes))
+	for attribute, value := range attributes {
+		values[strings.ToLower(attribute)] = value
+	}
+	return values, nil
+}
+
+func getUserAttributeValues(model *userRepository.UserModel, resource *repository.ScimResource) func(attribute string) []string {
+	return func(attribute string) []string {
+		switch attribute {
+		case "id":
+			return []string{strconv.Itoa(int(model.Id))}
+		case "username":
+			if resource != nil && len(resource.UserName) > 0 {
+				return []string{resource.UserName, model.EmailId}
+			}
+			return []string{model.EmailId}
+		case "emails", "emails.value", "displayname":
+			return []string{model.EmailId}
+		case "externalid":
+			if resource != nil && len(resource.ExternalId) > 0 {
+				return []string{resource.ExternalId}
+			}
+		case "active":
+			return []string{strconv.FormatBool(model.Active)}
+		}
+		return nil
+	}
+}
+
+func getGroupAttributeValues(roleGroup *userRepository.RoleGroup, resource *repository.ScimResource, getMembers func() []*bean.MultiValuedAttribute) func(attribute string) []string {
+	return func(attribute string) []string {
+		switch attribute {
+		case "id":
+			return []string{strconv.Itoa(int(roleGroup.Id))}
+		case "displayname":
+			return []string{roleGroup.Name}
+		case "externalid":
+			if resource != nil && len(resource.ExternalId) > 0 {
+				return []string{resource.ExternalId}
+			}
+		case "members", "members.value":
+			return getMemberIds(getMembers())
+		}
+		return nil
+	}
+}
+
+func getMemberIds(members []*bean.MultiValuedAttribute) []string {
+	ids := make([]string, 0, len(members))
+	for _, member := range members {
+		if member != nil && len(member.Value) > 0 {
+			ids = append(ids, member.Value)
+		}
+	}
+	return ids
+}
+
+func buildGroup(roleGroup *userRepository.RoleGroup, resource *repository.ScimResource, members []*bean.MultiValuedAttribute) *bean.Group {
+	group := &bean.Group{
+		Schemas:     []string{bean.GroupSchema},
+		Id:          strconv.Itoa(int(roleGroup.Id)),
+		DisplayName: roleGroup.Name,
+		Members:     members,
+		Meta:        buildMeta(bean.ResourceTypeGroup, roleGroup.Id, roleGroup.AuditLog),
+	}
+	if resource != nil {
+		group.ExternalId = resource.ExternalId
+	}
+	return group
+}
+
+func buildMeta(resourceType bean.ResourceType, id int32, auditLog sql.AuditLog) *bean.Meta {
+	return &bean.Meta{
+		ResourceType: resourceType,
+		Created:      &auditLog.CreatedOn,
+		LastModified: &auditLog.UpdatedOn,
+		Location:     getLocation(resourceType, id),
+	}
+}
+
+func getLocation(resourceType bean.ResourceType, id int32) string {
+	return fmt.Sprintf("%s/%ss/%d", bean.BasePath, resourceType, id)
+}
+
+func buildListResponse(totalResults, from int, resources []interface{}) *bean.ListResponse {
+	return &bean.ListResponse{
+		Schemas:      []string{bean.ListResponseSchema},
+		TotalResults: totalResults,
+		StartIndex:   from + 1,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
@@ -0,0 +1,233 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	UserSchema                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	GroupSchema                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	ListResponseSchema          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	PatchOpSchema               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	ErrorSchema                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	ServiceProviderConfigSchema = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+)
+
+const (
+	ContentType     = "application/scim+json"
+	BasePath        = "/orchestrator/scim/v2"
+	DefaultCount    = 100
+	MaxCount        = 500
+	MaxFilterLength = 1000
+)
+
+type ResourceType string
+
+const (
+	ResourceTypeUser  ResourceType = "User"
+	ResourceTypeGroup ResourceType = "Group"
+)
+
+// ErrorType is the scimType of RFC 7644 section 3.12, sent along with 400 and 409 errors
+type ErrorType string
+
+const (
+	ErrorTypeInvalidFilter ErrorType = "invalidFilter"
+	ErrorTypeInvalidSyntax ErrorType = "invalidSyntax"
+	ErrorTypeInvalidPath   ErrorType = "invalidPath"
+	ErrorTypeInvalidValue  ErrorType = "invalidValue"
+	ErrorTypeMutability    ErrorType = "mutability"
+	ErrorTypeUniqueness    ErrorType = "uniqueness"
+)
+
+type PatchOperationType string
+
+const (
+	PatchOperationAdd     PatchOperationType = "add"
+	PatchOperationRemove  PatchOperationType = "remove"
+	PatchOperationReplace PatchOperationType = "replace"
+)
+
+// Config is read from env, provisioning stays disabled until the api token dedicated to the identity provider is named
+type Config struct {
+	Enabled      bool   `env:"SCIM_ENABLED" envDefault:"false"`
+	ApiTokenName string `env:"SCIM_API_TOKEN_NAME" envDefault:"scim-provisioner"`
+}
+
+// Actor is the api token user calling the scim apis, its token is used for the rbac checks of user updates
+type Actor struct {
+	UserId int32
+	Token  string
+}
+
+type Meta struct {
+	ResourceType ResourceType `json:"resourceType"`
+	Created      *time.Time   `json:"created,omitempty"`
+	LastModified *time.Time   `json:"lastModified,omitempty"`
+	Location     string       `json:"location,omitempty"`
+}
+
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type MultiValuedAttribute struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+type User struct {
+	Schemas     []string                `json:"schemas"`
+	Id          string                  `json:"id,omitempty"`
+	ExternalId  string                  `json:"externalId,omitempty"`
+	UserName    string                  `json:"userName"`
+	Name        *Name                   `json:"name,omitempty"`
+	DisplayName string                  `json:"displayName,omitempty"`
+	Emails      []*MultiValuedAttribute `json:"emails,omitempty"`
+	// Active is a pointer as a missing value means active on create
+	Active *bool                   `json:"active,omitempty"`
+	Groups []*MultiValuedAttribute `json:"groups,omitempty"`
+	Meta   *Meta                   `json:"meta,omitempty"`
+}
+
+type Group struct {
+	Schemas     []string                `json:"schemas"`
+	Id          string                  `json:"id,omitempty"`
+	ExternalId  string                  `json:"externalId,omitempty"`
+	DisplayName string                  `json:"displayName"`
+	Members     []*MultiValuedAttribute `json:"members,omitempty"`
+	Meta        *Meta                   `json:"meta,omitempty"`
+}
+
+type ListResponse struct {
+	Schemas      []string      `json:"schemas"`
+	TotalResults int           `json:"totalResults"`
+	StartIndex   int           `json:"startIndex"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	Resources    []interface{} `json:"Resources"`
+}
+
+type ListRequest struct {
+	Filter     string `json:"filter"`
+	StartIndex int    `json:"startIndex"`
+	Count      int    `json:"count"`
+}
+
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type PatchRequest struct {
+	Schemas    []string          `json:"schemas"`
+	Operations []*PatchOperation `json:"Operations"`
+}
+
+type Supported struct {
+	Supported bool `json:"supported"`
+}
+
+type FilterSupported struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+type BulkSupported struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+type AuthenticationScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type ServiceProviderConfig struct {
+	Schemas               []string                `json:"schemas"`
+	Patch                 Supported               `json:"patch"`
+	Bulk                  BulkSupported           `json:"bulk"`
+	Filter                FilterSupported         `json:"filter"`
+	ChangePassword        Supported               `json:"changePassword"`
+	Sort                  Supported               `json:"sort"`
+	Etag                  Supported               `json:"etag"`
+	AuthenticationSchemes []*AuthenticationScheme `json:"authenticationSchemes"`
+}
+
+func GetServiceProviderConfig() *ServiceProviderConfig {
+	return &ServiceProviderConfig{
+		Schemas: []string{ServiceProviderConfigSchema},
+		Patch:   Supported{Supported: true},
+		Filter:  FilterSupported{Supported: true, MaxResults: MaxCount},
+		AuthenticationSchemes: []*AuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "API Token",
+			Description: "Devtron api token dedicated to scim provisioning, sent as a bearer token",
+		}},
+	}
+}
+
+// Error is returned by the scim service and written as the scim error response by the handler
+type Error struct {
+	Schemas  []string  `json:"schemas"`
+	Status   string    `json:"status"`
+	ScimType ErrorType `json:"scimType,omitempty"`
+	Detail   string    `json:"detail,omitempty"`
+	// StatusCode is the http status of the response
+	StatusCode int `json:"-"`
+}
+
+func (e *Error) Error() string {
+	return e.Detail
+}
+
+func NewError(statusCode int, scimType ErrorType, detail string) *Error {
+	return &Error{
+		Schemas:    []string{ErrorSchema},
+		Status:     fmt.Sprint(statusCode),
+		ScimType:   scimType,
+		Detail:     detail,
+		StatusCode: statusCode,
+	}
+}
+
+const (
+	ProvisioningDisabledMessage = "scim provisioning is disabled"
+	InvalidTokenMessage         = "token is not the api token dedicated to scim provisioning"
+	UserNotFoundMessage         = "user %s not found"
+	GroupNotFoundMessage        = "group %s not found"
+	UserNameRequiredMessage     = "userName or a primary email is required and must be an email id"
+	UserAlreadyExistsMessage    = "user %s already exists"
+	GroupAlreadyExistsMessage   = "group %s already exists"
+	UserNameImmutableMessage    = "userName can not be changed"
+	DisplayNameImmutableMessage = "displayName of a group can not be changed"
+	UnsupportedPatchOpMessage   = "unsupported patch operation %s"
+	UnsupportedPatchPathMessage = "unsupported patch path %s"
+	InvalidPatchValueMessage    = "invalid value for patch path %s"
+	SelfDeprovisionMessage      = "the scim provisioning token user can not be deprovisioned"
+)
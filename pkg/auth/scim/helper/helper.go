@@ -0,0 +1,310 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/auth/scim/bean"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	OperatorEqual      = "eq"
+	OperatorNotEqual   = "ne"
+	OperatorContains   = "co"
+	OperatorStartsWith = "sw"
+	OperatorEndsWith   = "ew"
+	OperatorPresent    = "pr"
+)
+
+// FilterCondition is a single attribute expression of a scim filter, Attribute is lower cased
+type FilterCondition struct {
+	Attribute string
+	Operator  string
+	Value     string
+}
+
+// Filter is the conjunction of its conditions, "or" and grouping with parentheses are not supported
+type Filter []*FilterCondition
+
+// ParseFilter parses filters like `userName eq "john@example.com" and active eq true`. Value paths such as
+// `members[value eq "2"]` are read as `members.value eq "2"`.
+func ParseFilter(filter string) (Filter, error) {
+	filter = strings.TrimSpace(filter)
+	if len(filter) == 0 {
+		return nil, nil
+	}
+	if len(filter) > bean.MaxFilterLength {
+		return nil, newInvalidFilterError("filter is too long")
+	}
+	tokens, err := tokenize(filter)
+	if err != nil {
+		return nil, err
+	}
+	var result Filter
+	for i := 0; i < len(tokens); {
+		if len(result) > 0 {
+			if !strings.EqualFold(tokens[i], "and") {
+				return nil, newInvalidFilterError(fmt.Sprintf("expected 'and' but found '%s', only 'and' is supported", tokens[i]))
+			}
+			i++
+			if i == len(tokens) {
+				return nil, newInvalidFilterError("expression expected after 'and'")
+			}
+		}
+		if strings.Contains(tokens[i], "[") {
+			condition, err := ParseValuePath(tokens[i])
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, condition)
+			i++
+			continue
+		}
+		if i+1 == len(tokens) {
+			return nil, newInvalidFilterError(fmt.Sprintf("operator expected after '%s'", tokens[i]))
+		}
+		condition := &FilterCondition{Attribute: strings.ToLower(tokens[i]), Operator: strings.ToLower(tokens[i+1])}
+		i += 2
+		if condition.Operator != OperatorPresent {
+			if i == len(tokens) {
+				return nil, newInvalidFilterError(fmt.Sprintf("value expected after '%s'", condition.Operator))
+			}
+			condition.Value, err = unquote(tokens[i])
+			if err != nil {
+				return nil, err
+			}
+			i++
+		}
+		if !isSupportedOperator(condition.Operator) {
+			return nil, newInvalidFilterError(fmt.Sprintf("operator '%s' is not supported", condition.Operator))
+		}
+		result = append(result, condition)
+	}
+	return result, nil
+}
+
+// ParseValuePath parses `attribute[subAttribute op "value"]` into the condition `attribute.subAttribute op "value"`
+func ParseValuePath(path string) (*FilterCondition, error) {
+	open := strings.Index(path, "[")
+	if open <= 0 || !strings.HasSuffix(path, "]") {
+		return nil, newInvalidFilterError(fmt.Sprintf("invalid value path '%s'", path))
+	}
+	inner, err := ParseFilter(path[open+1 : len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	if len(inner) != 1 {
+		return nil, newInvalidFilterError(fmt.Sprintf("invalid value path '%s'", path))
+	}
+	condition := inner[0]
+	condition.Attribute = fmt.Sprintf("%s.%s", strings.ToLower(path[:open]), condition.Attribute)
+	return condition, nil
+}
+
+// Matches checks every condition against the values returned for its attribute, attributes without values only match
+// the "ne" operator
+func (f Filter) Matches(getValues func(attribute string) []string) bool {
+	for _, condition := range f {
+		if !condition.matches(getValues(condition.Attribute)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *FilterCondition) matches(values []string) bool {
+	value := strings.ToLower(c.Value)
+	if c.Operator == OperatorNotEqual {
+		for _, v := range values {
+			if strings.ToLower(v) == value {
+				return false
+			}
+		}
+		return true
+	}
+	for _, v := range values {
+		v = strings.ToLower(v)
+		switch c.Operator {
+		case OperatorEqual:
+			if v == value {
+				return true
+			}
+		case OperatorContains:
+			if strings.Contains(v, value) {
+				return true
+			}
+		case OperatorStartsWith:
+			if strings.HasPrefix(v, value) {
+				return true
+			}
+		case OperatorEndsWith:
+			if strings.HasSuffix(v, value) {
+				return true
+			}
+		case OperatorPresent:
+			if len(v) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetPage returns the bounds of the page for the 1 based startIndex, count is defaulted and capped
+func GetPage(total, startIndex, count int) (int, int) {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	if count <= 0 {
+		count = bean.DefaultCount
+	} else if count > bean.MaxCount {
+		count = bean.MaxCount
+	}
+	from := startIndex - 1
+	if from > total {
+		from = total
+	}
+	to := from + count
+	if to > total {
+		to = total
+	}
+	return from, to
+}
+
+// ParseBoolValue accepts json booleans as well as "True"/"False" strings which some identity providers send
+func ParseBoolValue(path string, value json.RawMessage) (bool, error) {
+	var boolValue bool
+	if err := json.Unmarshal(value, &boolValue); err == nil {
+		return boolValue, nil
+	}
+	var stringValue string
+	if err := json.Unmarshal(value, &stringValue); err == nil {
+		if parsed, err := strconv.ParseBool(stringValue); err == nil {
+			return parsed, nil
+		}
+	}
+	return false, newInvalidValueError(path)
+}
+
+func ParseStringValue(path string, value json.RawMessage) (string, error) {
+	var stringValue string
+	if err := json.Unmarshal(value, &stringValue); err != nil {
+		return "", newInvalidValueError(path)
+	}
+	return stringValue, nil
+}
+
+// ParseMemberIds reads the values of a member list, a single member object is accepted too
+func ParseMemberIds(path string, value json.RawMessage) ([]string, error) {
+	var members []*bean.MultiValuedAttribute
+	if err := json.Unmarshal(value, &members); err != nil {
+		member := &bean.MultiValuedAttribute{}
+		if err = json.Unmarshal(value, member); err != nil {
+			return nil, newInvalidValueError(path)
+		}
+		members = []*bean.MultiValuedAttribute{member}
+	}
+	ids := make([]string, 0, len(members))
+	for _, member := range members {
+		if member != nil && len(member.Value) > 0 {
+			ids = append(ids, member.Value)
+		}
+	}
+	return ids, nil
+}
+
+// GetEmailId picks the userName when it is an email id, else the primary email of the user
+func GetEmailId(user *bean.User) string {
+	if strings.Contains(user.UserName, "@") {
+		return strings.ToLower(user.UserName)
+	}
+	var emailId string
+	for _, email := range user.Emails {
+		if email == nil || !strings.Contains(email.Value, "@") {
+			continue
+		}
+		if email.Primary || len(emailId) == 0 {
+			emailId = email.Value
+		}
+	}
+	return strings.ToLower(emailId)
+}
+
+func tokenize(filter string) ([]string, error) {
+	var tokens []string
+	var current strings.Builder
+	inQuotes, escaped, bracketDepth := false, false, 0
+	for _, char := range filter {
+		switch {
+		case escaped:
+			escaped = false
+		case char == '\\' && inQuotes:
+			escaped = true
+		case char == '"':
+			inQuotes = !inQuotes
+		case char == '[' && !inQuotes:
+			bracketDepth++
+		case char == ']' && !inQuotes:
+			bracketDepth--
+		case char == ' ' && !inQuotes && bracketDepth == 0:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+			continue
+		}
+		current.WriteRune(char)
+	}
+	if inQuotes || bracketDepth != 0 {
+		return nil, newInvalidFilterError("unbalanced quotes or brackets in filter")
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens, nil
+}
+
+func unquote(token string) (string, error) {
+	if !strings.HasPrefix(token, "\"") {
+		return token, nil
+	}
+	var value string
+	if err := json.Unmarshal([]byte(token), &value); err != nil {
+		return "", newInvalidFilterError(fmt.Sprintf("invalid value %s", token))
+	}
+	return value, nil
+}
+
+func isSupportedOperator(operator string) bool {
+	switch operator {
+	case OperatorEqual, OperatorNotEqual, OperatorContains, OperatorStartsWith, OperatorEndsWith, OperatorPresent:
+		return true
+	}
+	return false
+}
+
+func newInvalidFilterError(detail string) error {
+	return bean.NewError(http.StatusBadRequest, bean.ErrorTypeInvalidFilter, detail)
+}
+
+func newInvalidValueError(path string) error {
+	return bean.NewError(http.StatusBadRequest, bean.ErrorTypeInvalidValue, fmt.Sprintf(bean.InvalidPatchValueMessage, path))
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/pkg/auth/scim/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseFilter(t *testing.T) {
+	filter, err := ParseFilter(`userName eq "John@Example.com" and active eq true`)
+	assert.Nil(t, err)
+	assert.Equal(t, Filter{
+		{Attribute: "username", Operator: "eq", Value: "John@Example.com"},
+		{Attribute: "active", Operator: "eq", Value: "true"},
+	}, filter)
+
+	filter, err = ParseFilter(`displayName sw "dev \"ops\"" AND members[value eq "12"] and externalId pr`)
+	assert.Nil(t, err)
+	assert.Equal(t, Filter{
+		{Attribute: "displayname", Operator: "sw", Value: `dev "ops"`},
+		{Attribute: "members.value", Operator: "eq", Value: "12"},
+		{Attribute: "externalid", Operator: "pr"},
+	}, filter)
+
+	filter, err = ParseFilter("  ")
+	assert.Nil(t, err)
+	assert.Nil(t, filter)
+
+	for _, invalid := range []string{`userName eq`, `userName gt "a"`, `userName eq "a" or userName eq "b"`, `userName eq "a`, `members[value eq "1"`} {
+		_, err = ParseFilter(invalid)
+		scimErr, ok := err.(*bean.Error)
+		assert.True(t, ok, invalid)
+		assert.Equal(t, bean.ErrorTypeInvalidFilter, scimErr.ScimType, invalid)
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	values := map[string][]string{
+		"username":     {"john@example.com"},
+		"emails.value": {"john@example.com", "jd@example.org"},
+	}
+	getValues := func(attribute string) []string { return values[attribute] }
+	cases := map[string]bool{
+		`userName eq "JOHN@example.com"`:                     true,
+		`userName ne "john@example.com"`:                     false,
+		`emails.value ew "example.org"`:                      true,
+		`userName sw "john" and emails.value co "jd@"`:       true,
+		`userName sw "john" and externalId pr`:               false,
+		`externalId ne "abc"`:                                true,
+		`emails[value eq "jd@example.org"]`:                  true,
+		`userName eq "john@example.com" and userName co "z"`: false,
+	}
+	for filter, expected := range cases {
+		parsed, err := ParseFilter(filter)
+		assert.Nil(t, err, filter)
+		assert.Equal(t, expected, parsed.Matches(getValues), filter)
+	}
+}
+
+func TestGetPage(t *testing.T) {
+	from, to := GetPage(10, 0, 0)
+	assert.Equal(t, 0, from)
+	assert.Equal(t, 10, to)
+	from, to = GetPage(10, 4, 3)
+	assert.Equal(t, 3, from)
+	assert.Equal(t, 6, to)
+	from, to = GetPage(10, 20, 5)
+	assert.Equal(t, 10, from)
+	assert.Equal(t, 10, to)
+}
+
+func TestParsePatchValues(t *testing.T) {
+	active, err := ParseBoolValue("active", json.RawMessage(`"False"`))
+	assert.Nil(t, err)
+	assert.False(t, active)
+	active, err = ParseBoolValue("active", json.RawMessage(`true`))
+	assert.Nil(t, err)
+	assert.True(t, active)
+	_, err = ParseBoolValue("active", json.RawMessage(`"maybe"`))
+	assert.NotNil(t, err)
+
+	ids, err := ParseMemberIds("members", json.RawMessage(`[{"value":"1"},{"value":"2","display":"b"}]`))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1", "2"}, ids)
+	ids, err = ParseMemberIds("members", json.RawMessage(`{"value":"3"}`))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"3"}, ids)
+}
+
+func TestGetEmailId(t *testing.T) {
+	assert.Equal(t, "john@example.com", GetEmailId(&bean.User{UserName: "John@Example.com"}))
+	assert.Equal(t, "jd@example.com", GetEmailId(&bean.User{UserName: "jdoe", Emails: []*bean.MultiValuedAttribute{
+		{Value: "other@example.com"}, {Value: "jd@example.com", Primary: true},
+	}}))
+	assert.Equal(t, "", GetEmailId(&bean.User{UserName: "jdoe"}))
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/auth/scim/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// ScimResource keeps the identity provider's view of a user or role group provisioned over scim. A user deactivated
+// by the identity provider stays active here so that it is still served with active false, deleting it over scim
+// marks the resource inactive.
+type ScimResource struct {
+	TableName    struct{}          `sql:"scim_resource" pg:",discard_unknown_columns"`
+	Id           int               `sql:"id,pk"`
+	ResourceType bean.ResourceType `sql:"resource_type,notnull"`
+	ResourceId   int32             `sql:"resource_id,notnull"`
+	ExternalId   string            `sql:"external_id"`
+	UserName     string            `sql:"user_name"`
+	Active       bool              `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type ScimResourceRepository interface {
+	Save(model *ScimResource) error
+	Update(model *ScimResource) error
+	FindActiveByResource(resourceType bean.ResourceType, resourceId int32) (*ScimResource, error)
+	FindAllActiveByResourceType(resourceType bean.ResourceType) ([]*ScimResource, error)
+}
+
+type ScimResourceRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewScimResourceRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ScimResourceRepositoryImpl {
+	return &ScimResourceRepositoryImpl{dbConnection: dbConnection, logger: logger}
+}
+
+func (impl *ScimResourceRepositoryImpl) Save(model *ScimResource) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *ScimResourceRepositoryImpl) Update(model *ScimResource) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *ScimResourceRepositoryImpl) FindActiveByResource(resourceType bean.ResourceType, resourceId int32) (*ScimResource, error) {
+	model := &ScimResource{}
+	err := impl.dbConnection.Model(model).
+		Where("resource_type = ?", resourceType).
+		Where("resource_id = ?", resourceId).
+		Where("active = ?", true).
+		Select()
+	return model, err
+}
+
+func (impl *ScimResourceRepositoryImpl) FindAllActiveByResourceType(resourceType bean.ResourceType) ([]*ScimResource, error) {
+	var models []*ScimResource
+	err := impl.dbConnection.Model(&models).
+		Where("resource_type = ?", resourceType).
+		Where("active = ?", true).
+		Select()
+	return models, err
+}
@@ -24,6 +24,8 @@ import (
 
 	"github.com/devtron-labs/common-lib/utils/k8s"
 	"github.com/devtron-labs/devtron/pkg/auth/authentication"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	"github.com/devtron-labs/devtron/pkg/auth/user/adapter"
 
 	util2 "github.com/devtron-labs/devtron/util"
 	"github.com/go-pg/pg"
@@ -40,11 +42,12 @@ type SSOLoginService interface {
 }
 
 type SSOLoginServiceImpl struct {
-	logger              *zap.SugaredLogger
-	ssoLoginRepository  SSOLoginRepository
-	K8sUtil             *k8s.K8sServiceImpl
-	devtronSecretConfig *util2.DevtronSecretConfig
-	userAuthOidcHelper  authentication.UserAuthOidcHelper
+	logger                     *zap.SugaredLogger
+	ssoLoginRepository         SSOLoginRepository
+	K8sUtil                    *k8s.K8sServiceImpl
+	devtronSecretConfig        *util2.DevtronSecretConfig
+	userAuthOidcHelper         authentication.UserAuthOidcHelper
+	userPermissionAuditService user.UserPermissionAuditService
 }
 
 type Config struct {
@@ -99,13 +102,15 @@ const (
 func NewSSOLoginServiceImpl(
 	logger *zap.SugaredLogger,
 	ssoLoginRepository SSOLoginRepository,
-	K8sUtil *k8s.K8sServiceImpl, envVariables *util2.EnvironmentVariables, userAuthOidcHelper authentication.UserAuthOidcHelper) *SSOLoginServiceImpl {
+	K8sUtil *k8s.K8sServiceImpl, envVariables *util2.EnvironmentVariables, userAuthOidcHelper authentication.UserAuthOidcHelper,
+	userPermissionAuditService user.UserPermissionAuditService) *SSOLoginServiceImpl {
 	serviceImpl := &SSOLoginServiceImpl{
-		logger:              logger,
-		ssoLoginRepository:  ssoLoginRepository,
-		K8sUtil:             K8sUtil,
-		devtronSecretConfig: envVariables.DevtronSecretConfig,
-		userAuthOidcHelper:  userAuthOidcHelper,
+		logger:                     logger,
+		ssoLoginRepository:         ssoLoginRepository,
+		K8sUtil:                    K8sUtil,
+		devtronSecretConfig:        envVariables.DevtronSecretConfig,
+		userAuthOidcHelper:         userAuthOidcHelper,
+		userPermissionAuditService: userPermissionAuditService,
 	}
 	return serviceImpl
 }
@@ -130,6 +135,7 @@ func (impl SSOLoginServiceImpl) CreateSSOLogin(request *bean.SSOLoginDto) (*bean
 		return nil, err
 	}
 	if existingModel != nil && existingModel.Id > 0 {
+		previousAuditState := getPermissionAuditState(existingModel)
 		existingModel.Active = false
 		existingModel.UpdatedOn = time.Now()
 		existingModel.UpdatedBy = request.UserId
@@ -138,6 +144,10 @@ func (impl SSOLoginServiceImpl) CreateSSOLogin(request *bean.SSOLoginDto) (*bean
 			impl.logger.Errorw("error in creating new sso login config", "error", err)
 			return nil, err
 		}
+		err = impl.saveAudit(tx, existingModel, bean.PermissionAuditActionUpdate, request.UserId, previousAuditState)
+		if err != nil {
+			return nil, err
+		}
 	}
 	model := &SSOLoginModel{
 		Name:   request.Name,
@@ -156,6 +166,10 @@ func (impl SSOLoginServiceImpl) CreateSSOLogin(request *bean.SSOLoginDto) (*bean
 		return nil, err
 	}
 	request.Id = model.Id
+	err = impl.saveAudit(tx, model, bean.PermissionAuditActionCreate, request.UserId, nil)
+	if err != nil {
+		return nil, err
+	}
 	_, err = impl.updateDexConfig(request)
 	if err != nil {
 		impl.logger.Errorw("error in creating new sso login config", "error", err)
@@ -199,6 +213,7 @@ func (impl SSOLoginServiceImpl) UpdateSSOLogin(request *bean.SSOLoginDto) (*bean
 	}
 	if existingModel != nil && existingModel.Id > 0 {
 		if existingModel.Id != model.Id {
+			previousAuditState := getPermissionAuditState(existingModel)
 			existingModel.Active = false
 			existingModel.UpdatedOn = time.Now()
 			existingModel.UpdatedBy = request.UserId
@@ -207,6 +222,10 @@ func (impl SSOLoginServiceImpl) UpdateSSOLogin(request *bean.SSOLoginDto) (*bean
 				impl.logger.Errorw("error in creating new sso login config", "error", err)
 				return nil, err
 			}
+			err = impl.saveAudit(tx, existingModel, bean.PermissionAuditActionUpdate, request.UserId, previousAuditState)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 	configString := string(configDataByte)
@@ -229,6 +248,7 @@ func (impl SSOLoginServiceImpl) UpdateSSOLogin(request *bean.SSOLoginDto) (*bean
 		return nil, err
 	}
 	updatedConfig := string(newConfigString)
+	previousAuditState := getPermissionAuditState(model)
 	model.Label = request.Label
 	model.Url = request.Url
 	model.Config = updatedConfig
@@ -240,6 +260,10 @@ func (impl SSOLoginServiceImpl) UpdateSSOLogin(request *bean.SSOLoginDto) (*bean
 		impl.logger.Errorw("error in creating new sso login config", "error", err)
 		return nil, err
 	}
+	err = impl.saveAudit(tx, model, bean.PermissionAuditActionUpdate, request.UserId, previousAuditState)
+	if err != nil {
+		return nil, err
+	}
 	request.Config = newConfigString
 	_, err = impl.updateDexConfig(request)
 	if err != nil {
@@ -258,6 +282,32 @@ func (impl SSOLoginServiceImpl) UpdateSSOLogin(request *bean.SSOLoginDto) (*bean
 	return request, nil
 }
 
+func (impl SSOLoginServiceImpl) saveAudit(tx *pg.Tx, model *SSOLoginModel, action bean.PermissionAuditAction, userId int32, previousAuditState *bean.PermissionAuditState) error {
+	err := impl.userPermissionAuditService.SaveAudit(tx, &bean.PermissionAuditDto{
+		EntityType:    bean.PermissionAuditEntitySsoConfig,
+		EntityId:      model.Id,
+		EntityName:    model.Name,
+		Action:        action,
+		ActorId:       userId,
+		PreviousState: previousAuditState,
+		CurrentState:  getPermissionAuditState(model),
+	})
+	if err != nil {
+		impl.logger.Errorw("error in saving permission audit for sso config", "ssoConfigId", model.Id, "action", action, "err", err)
+		return err
+	}
+	return nil
+}
+
+func getPermissionAuditState(model *SSOLoginModel) *bean.PermissionAuditState {
+	return adapter.BuildPermissionAuditStateForSsoConfig(&bean.SSOLoginDto{
+		Name:   model.Name,
+		Label:  model.Label,
+		Url:    model.Url,
+		Active: model.Active,
+	})
+}
+
 func (impl SSOLoginServiceImpl) updateDexConfig(request *bean.SSOLoginDto) (bool, error) {
 	flag := false
 	k8sClient, err := impl.K8sUtil.GetClientForInCluster()
@@ -0,0 +1,448 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package user
+
+import (
+	"fmt"
+	"github.com/caarlos0/env/v6"
+	"github.com/devtron-labs/devtron/internal/util"
+	casbin2 "github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	bean4 "github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/user/adapter"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	userHelper "github.com/devtron-labs/devtron/pkg/auth/user/helper"
+	"github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	"github.com/go-pg/pg"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GroupClaimsSyncService maps the group claims sent by the identity provider to role groups. Memberships are
+// reconciled on every login and by a periodic sync which also applies changes in the mappings.
+type GroupClaimsSyncService interface {
+	// RecordGroupClaims saves the claims of the login and reconciles the memberships of the user, nil groups are ignored
+	RecordGroupClaims(emailId, connectorId string, groups []string) error
+	ReconcileUser(emailId string) error
+	// SyncAll is the periodic sync
+	SyncAll()
+	// GetIdpRoleGroups returns the role groups whose membership is owned by the sync for the user
+	GetIdpRoleGroups(userId int32) ([]*repository.RoleGroup, error)
+	DeactivateMemberships(tx *pg.Tx, userIds []int32, loggedInUserId int32) error
+
+	GetMappings() ([]*userBean.RoleGroupClaimMappingDto, error)
+	CreateMapping(request *userBean.RoleGroupClaimMappingDto) (*userBean.RoleGroupClaimMappingDto, error)
+	DeleteMapping(id int, userId int32) error
+}
+
+type GroupClaimsSyncServiceImpl struct {
+	logger                                 *zap.SugaredLogger
+	userRepository                         repository.UserRepository
+	roleGroupRepository                    repository.RoleGroupRepository
+	roleGroupClaimMappingRepository        repository.RoleGroupClaimMappingRepository
+	userGroupClaimsRepository              repository.UserGroupClaimsRepository
+	userRoleGroupClaimMembershipRepository repository.UserRoleGroupClaimMembershipRepository
+	timeBoundAccessService                 TimeBoundAccessService
+	config                                 *userBean.GroupClaimsConfig
+	syncCron                               *cron.Cron
+	// syncLock serialises reconciliations so that login and periodic sync do not race on the same memberships
+	syncLock sync.Mutex
+}
+
+func NewGroupClaimsSyncServiceImpl(logger *zap.SugaredLogger,
+	userRepository repository.UserRepository,
+	roleGroupRepository repository.RoleGroupRepository,
+	roleGroupClaimMappingRepository repository.RoleGroupClaimMappingRepository,
+	userGroupClaimsRepository repository.UserGroupClaimsRepository,
+	userRoleGroupClaimMembershipRepository repository.UserRoleGroupClaimMembershipRepository,
+	timeBoundAccessService TimeBoundAccessService,
+	cronLogger *cron2.CronLoggerImpl) (*GroupClaimsSyncServiceImpl, error) {
+	config := &userBean.GroupClaimsConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing group claims config", "err", err)
+		return nil, err
+	}
+	syncCron := cron.New(cron.WithChain(cron.SkipIfStillRunning(cronLogger), cron.Recover(cronLogger)))
+	impl := &GroupClaimsSyncServiceImpl{
+		logger:                                 logger,
+		userRepository:                         userRepository,
+		roleGroupRepository:                    roleGroupRepository,
+		roleGroupClaimMappingRepository:        roleGroupClaimMappingRepository,
+		userGroupClaimsRepository:              userGroupClaimsRepository,
+		userRoleGroupClaimMembershipRepository: userRoleGroupClaimMembershipRepository,
+		timeBoundAccessService:                 timeBoundAccessService,
+		config:                                 config,
+		syncCron:                               syncCron,
+	}
+	syncCron.Start()
+	_, err = syncCron.AddFunc(fmt.Sprintf("@every %ds", config.SyncIntervalInSecs), impl.SyncAll)
+	if err != nil {
+		logger.Errorw("error in starting group claims sync", "err", err)
+		return nil, err
+	}
+	return impl, nil
+}
+
+func (impl *GroupClaimsSyncServiceImpl) RecordGroupClaims(emailId, connectorId string, groups []string) error {
+	if groups == nil {
+		return nil
+	}
+	emailId = strings.ToLower(emailId)
+	model, err := impl.userGroupClaimsRepository.FindByEmailId(emailId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting group claims of user", "emailId", emailId, "err", err)
+		return err
+	}
+	if err == pg.ErrNoRows {
+		model = &repository.UserGroupClaims{EmailId: emailId, AuditLog: sql.NewDefaultAuditLog(userBean.SystemUserId)}
+	} else {
+		model.UpdateAuditLog(userBean.SystemUserId)
+	}
+	model.ConnectorId = connectorId
+	model.GroupClaims = groups
+	model.LastLoginOn = time.Now()
+	if model.Id == 0 {
+		err = impl.userGroupClaimsRepository.Save(model)
+	} else {
+		err = impl.userGroupClaimsRepository.Update(model)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving group claims of user", "emailId", emailId, "err", err)
+		return err
+	}
+	return impl.ReconcileUser(emailId)
+}
+
+func (impl *GroupClaimsSyncServiceImpl) ReconcileUser(emailId string) error {
+	emailId = strings.ToLower(emailId)
+	mappings, err := impl.roleGroupClaimMappingRepository.FindAllActive()
+	if err != nil {
+		return err
+	}
+	claims, err := impl.userGroupClaimsRepository.FindByEmailId(emailId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting group claims of user", "emailId", emailId, "err", err)
+		return err
+	}
+	if err == pg.ErrNoRows {
+		claims = nil
+	}
+	user, err := impl.userRepository.FetchActiveOrDeletedUserByEmail(emailId)
+	if err == pg.ErrNoRows || (err == nil && !user.Active) {
+		// claims are kept, memberships are reconciled once the user is added
+		return nil
+	} else if err != nil {
+		impl.logger.Errorw("error in getting user for group claims", "emailId", emailId, "err", err)
+		return err
+	}
+	memberships, err := impl.userRoleGroupClaimMembershipRepository.GetActiveByUserId(user.Id)
+	if err != nil {
+		return err
+	}
+	impl.syncLock.Lock()
+	defer impl.syncLock.Unlock()
+	return impl.reconcile(user.Id, emailId, claims, memberships, mappings, time.Now())
+}
+
+func (impl *GroupClaimsSyncServiceImpl) SyncAll() {
+	mappings, err := impl.roleGroupClaimMappingRepository.FindAllActive()
+	if err != nil {
+		return
+	}
+	allClaims, err := impl.userGroupClaimsRepository.FindAll()
+	if err != nil {
+		return
+	}
+	allMemberships, err := impl.userRoleGroupClaimMembershipRepository.GetAllActive()
+	if err != nil {
+		return
+	}
+	claimsByEmail := make(map[string]*repository.UserGroupClaims, len(allClaims))
+	for _, claims := range allClaims {
+		claimsByEmail[claims.EmailId] = claims
+	}
+	membershipsByUserId := make(map[int32][]*repository.UserRoleGroupClaimMembership)
+	for _, membership := range allMemberships {
+		membershipsByUserId[membership.UserId] = append(membershipsByUserId[membership.UserId], membership)
+	}
+	// users having claims or memberships owned by the sync, the latter covers users whose claims were removed
+	emailIdVsUserId := make(map[string]int32)
+	if len(membershipsByUserId) > 0 {
+		userIds := make([]int32, 0, len(membershipsByUserId))
+		for userId := range membershipsByUserId {
+			userIds = append(userIds, userId)
+		}
+		users, err := impl.userRepository.GetByIds(userIds)
+		if err != nil {
+			impl.logger.Errorw("error in getting users for group claims sync", "err", err)
+			return
+		}
+		for _, user := range users {
+			emailIdVsUserId[strings.ToLower(user.EmailId)] = user.Id
+		}
+	}
+	for emailId := range claimsByEmail {
+		if _, ok := emailIdVsUserId[emailId]; ok {
+			continue
+		}
+		user, err := impl.userRepository.FetchActiveOrDeletedUserByEmail(emailId)
+		if err != nil || !user.Active {
+			continue
+		}
+		emailIdVsUserId[emailId] = user.Id
+	}
+	impl.syncLock.Lock()
+	defer impl.syncLock.Unlock()
+	now := time.Now()
+	for emailId, userId := range emailIdVsUserId {
+		err = impl.reconcile(userId, emailId, claimsByEmail[emailId], membershipsByUserId[userId], mappings, now)
+		if err != nil {
+			// continuing with other users, this one is retried in the next sync
+			impl.logger.Errorw("error in reconciling role group memberships from group claims", "emailId", emailId, "err", err)
+		}
+	}
+}
+
+// reconcile adds memberships for the role groups granted by the claims and removes the ones owned by the sync which
+// are no longer granted. Role groups which the user is already a member of, manually or through a time window,
+// are left to the manual assignment.
+func (impl *GroupClaimsSyncServiceImpl) reconcile(userId int32, emailId string, claims *repository.UserGroupClaims,
+	memberships []*repository.UserRoleGroupClaimMembership, mappings []*repository.RoleGroupClaimMapping, now time.Time) error {
+	desiredRoleGroupIds := make(map[int32]bool)
+	if claims != nil && !impl.areClaimsStale(claims, now) {
+		desiredRoleGroupIds = userHelper.GetRoleGroupIdsForGroupClaims(mappings, claims.ConnectorId, claims.GroupClaims)
+	}
+	ownedByRoleGroupId := make(map[int32]*repository.UserRoleGroupClaimMembership, len(memberships))
+	for _, membership := range memberships {
+		ownedByRoleGroupId[membership.RoleGroupId] = membership
+	}
+	roleGroupIds := make([]int32, 0, len(desiredRoleGroupIds)+len(ownedByRoleGroupId))
+	for roleGroupId := range desiredRoleGroupIds {
+		roleGroupIds = append(roleGroupIds, roleGroupId)
+	}
+	for roleGroupId := range ownedByRoleGroupId {
+		if !desiredRoleGroupIds[roleGroupId] {
+			roleGroupIds = append(roleGroupIds, roleGroupId)
+		}
+	}
+	if len(roleGroupIds) == 0 {
+		return nil
+	}
+	roleGroups, err := impl.roleGroupRepository.GetRoleGroupListByIds(roleGroupIds)
+	if err != nil {
+		impl.logger.Errorw("error in getting role groups for group claims", "roleGroupIds", roleGroupIds, "err", err)
+		return err
+	}
+	roleGroupById := make(map[int32]*repository.RoleGroup, len(roleGroups))
+	for _, roleGroup := range roleGroups {
+		roleGroupById[roleGroup.Id] = roleGroup
+	}
+	casbinRoles, err := casbin2.GetRolesForUser(emailId)
+	if err != nil {
+		impl.logger.Errorw("error in getting casbin roles of user", "emailId", emailId, "err", err)
+		return err
+	}
+	existingCasbinRoles := make(map[string]bool, len(casbinRoles))
+	for _, casbinRole := range casbinRoles {
+		existingCasbinRoles[casbinRole] = true
+	}
+	timeBoundRoleGroups, err := impl.timeBoundAccessService.GetActiveRoleGroupTimeoutWindows(userId)
+	if err != nil {
+		impl.logger.Errorw("error in getting time bound role groups of user", "userId", userId, "err", err)
+		return err
+	}
+
+	tx, err := impl.userRepository.GetConnection().Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	var policiesToBeAdded, policiesToBeRemoved []bean4.Policy
+	for roleGroupId := range desiredRoleGroupIds {
+		roleGroup, ok := roleGroupById[roleGroupId]
+		if !ok {
+			continue
+		}
+		policy := adapter.GetCasbinGroupPolicyForEmailAndRoleOnly(emailId, roleGroup.CasbinName)
+		if _, owned := ownedByRoleGroupId[roleGroupId]; owned {
+			if !existingCasbinRoles[roleGroup.CasbinName] {
+				policiesToBeAdded = append(policiesToBeAdded, policy)
+			}
+			continue
+		}
+		if _, timeBound := timeBoundRoleGroups[roleGroupId]; timeBound || existingCasbinRoles[roleGroup.CasbinName] {
+			continue
+		}
+		err = impl.userRoleGroupClaimMembershipRepository.Save(&repository.UserRoleGroupClaimMembership{
+			UserId:      userId,
+			RoleGroupId: roleGroupId,
+			Active:      true,
+			AuditLog:    sql.NewDefaultAuditLog(userBean.SystemUserId),
+		}, tx)
+		if err != nil {
+			impl.logger.Errorw("error in saving role group claim membership", "userId", userId, "roleGroupId", roleGroupId, "err", err)
+			return err
+		}
+		policiesToBeAdded = append(policiesToBeAdded, policy)
+	}
+	for roleGroupId, membership := range ownedByRoleGroupId {
+		if desiredRoleGroupIds[roleGroupId] && roleGroupById[roleGroupId] != nil {
+			continue
+		}
+		membership.Active = false
+		membership.UpdateAuditLog(userBean.SystemUserId)
+		err = impl.userRoleGroupClaimMembershipRepository.Update(membership, tx)
+		if err != nil {
+			impl.logger.Errorw("error in deactivating role group claim membership", "id", membership.Id, "err", err)
+			return err
+		}
+		if roleGroup, ok := roleGroupById[roleGroupId]; ok && existingCasbinRoles[roleGroup.CasbinName] {
+			policiesToBeRemoved = append(policiesToBeRemoved, adapter.GetCasbinGroupPolicyForEmailAndRoleOnly(emailId, roleGroup.CasbinName))
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	if len(policiesToBeRemoved) > 0 {
+		casbin2.RemovePolicy(policiesToBeRemoved)
+	}
+	if len(policiesToBeAdded) > 0 {
+		casbin2.AddPolicy(policiesToBeAdded)
+	}
+	if len(policiesToBeAdded) > 0 || len(policiesToBeRemoved) > 0 {
+		impl.logger.Infow("reconciled role group memberships from group claims", "emailId", emailId,
+			"added", len(policiesToBeAdded), "removed", len(policiesToBeRemoved))
+	}
+	return nil
+}
+
+func (impl *GroupClaimsSyncServiceImpl) areClaimsStale(claims *repository.UserGroupClaims, now time.Time) bool {
+	return impl.config.StaleAfterHrs > 0 && now.Sub(claims.LastLoginOn) > time.Duration(impl.config.StaleAfterHrs)*time.Hour
+}
+
+func (impl *GroupClaimsSyncServiceImpl) GetIdpRoleGroups(userId int32) ([]*repository.RoleGroup, error) {
+	memberships, err := impl.userRoleGroupClaimMembershipRepository.GetActiveByUserId(userId)
+	if err != nil || len(memberships) == 0 {
+		return nil, err
+	}
+	roleGroupIds := make([]int32, 0, len(memberships))
+	for _, membership := range memberships {
+		roleGroupIds = append(roleGroupIds, membership.RoleGroupId)
+	}
+	roleGroups, err := impl.roleGroupRepository.GetRoleGroupListByIds(roleGroupIds)
+	if err != nil {
+		impl.logger.Errorw("error in getting role groups of claim memberships", "userId", userId, "err", err)
+		return nil, err
+	}
+	return roleGroups, nil
+}
+
+func (impl *GroupClaimsSyncServiceImpl) DeactivateMemberships(tx *pg.Tx, userIds []int32, loggedInUserId int32) error {
+	return impl.userRoleGroupClaimMembershipRepository.DeactivateByUserIds(userIds, loggedInUserId, tx)
+}
+
+func (impl *GroupClaimsSyncServiceImpl) GetMappings() ([]*userBean.RoleGroupClaimMappingDto, error) {
+	mappings, err := impl.roleGroupClaimMappingRepository.FindAllActive()
+	if err != nil {
+		return nil, err
+	}
+	roleGroupIds := make([]int32, 0, len(mappings))
+	for _, mapping := range mappings {
+		roleGroupIds = append(roleGroupIds, mapping.RoleGroupId)
+	}
+	roleGroupNames := make(map[int32]string, len(roleGroupIds))
+	if len(roleGroupIds) > 0 {
+		roleGroups, err := impl.roleGroupRepository.GetRoleGroupListByIds(roleGroupIds)
+		if err != nil {
+			impl.logger.Errorw("error in getting role groups of claim mappings", "err", err)
+			return nil, err
+		}
+		for _, roleGroup := range roleGroups {
+			roleGroupNames[roleGroup.Id] = roleGroup.Name
+		}
+	}
+	dtos := make([]*userBean.RoleGroupClaimMappingDto, 0, len(mappings))
+	for _, mapping := range mappings {
+		dtos = append(dtos, &userBean.RoleGroupClaimMappingDto{
+			Id:            mapping.Id,
+			RoleGroupId:   mapping.RoleGroupId,
+			RoleGroupName: roleGroupNames[mapping.RoleGroupId],
+			ConnectorId:   mapping.ConnectorId,
+			GroupClaim:    mapping.GroupClaim,
+		})
+	}
+	return dtos, nil
+}
+
+func (impl *GroupClaimsSyncServiceImpl) CreateMapping(request *userBean.RoleGroupClaimMappingDto) (*userBean.RoleGroupClaimMappingDto, error) {
+	roleGroup, err := impl.roleGroupRepository.GetRoleGroupById(request.RoleGroupId)
+	if err == pg.ErrNoRows || (err == nil && !roleGroup.Active) {
+		return nil, util.NewApiError(http.StatusNotFound, userBean.RoleGroupNotFoundMessage, userBean.RoleGroupNotFoundMessage)
+	} else if err != nil {
+		impl.logger.Errorw("error in getting role group for claim mapping", "request", request, "err", err)
+		return nil, err
+	}
+	_, err = impl.roleGroupClaimMappingRepository.FindActiveByRoleGroupIdAndClaim(request.RoleGroupId, request.ConnectorId, request.GroupClaim)
+	if err == nil {
+		return nil, util.NewApiError(http.StatusConflict, userBean.GroupClaimMappingExistsMessage, userBean.GroupClaimMappingExistsMessage)
+	} else if err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting role group claim mapping", "request", request, "err", err)
+		return nil, err
+	}
+	model := &repository.RoleGroupClaimMapping{
+		RoleGroupId: request.RoleGroupId,
+		ConnectorId: request.ConnectorId,
+		GroupClaim:  request.GroupClaim,
+		Active:      true,
+		AuditLog:    sql.NewDefaultAuditLog(request.UserId),
+	}
+	err = impl.roleGroupClaimMappingRepository.Save(model)
+	if err != nil {
+		impl.logger.Errorw("error in saving role group claim mapping", "request", request, "err", err)
+		return nil, err
+	}
+	request.Id = model.Id
+	request.RoleGroupName = roleGroup.Name
+	// memberships of users who already logged in with the claim are applied right away
+	go impl.SyncAll()
+	return request, nil
+}
+
+func (impl *GroupClaimsSyncServiceImpl) DeleteMapping(id int, userId int32) error {
+	model, err := impl.roleGroupClaimMappingRepository.FindById(id)
+	if err == pg.ErrNoRows {
+		return util.NewApiError(http.StatusNotFound, userBean.GroupClaimMappingNotFoundMessage, userBean.GroupClaimMappingNotFoundMessage)
+	} else if err != nil {
+		impl.logger.Errorw("error in getting role group claim mapping", "id", id, "err", err)
+		return err
+	}
+	model.Active = false
+	model.UpdateAuditLog(userId)
+	err = impl.roleGroupClaimMappingRepository.Update(model)
+	if err != nil {
+		impl.logger.Errorw("error in deleting role group claim mapping", "id", id, "err", err)
+		return err
+	}
+	go impl.SyncAll()
+	return nil
+}
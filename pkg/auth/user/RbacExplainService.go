@@ -0,0 +1,197 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package user
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
+	casbin2 "github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	bean4 "github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin/bean"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	userHelper "github.com/devtron-labs/devtron/pkg/auth/user/helper"
+	"github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+)
+
+// RbacExplainService explains casbin decisions for a user or api token in terms of the policies and role groups behind them
+type RbacExplainService interface {
+	ResolveSubject(subject *userBean.RbacSubjectDto) (int32, string, error)
+	Explain(request *userBean.RbacExplainRequest) (*userBean.RbacExplainResponse, error)
+	GetEffectivePermissions(subject *userBean.RbacSubjectDto) (*userBean.EffectivePermissionsResponse, error)
+}
+
+type RbacExplainServiceImpl struct {
+	logger              *zap.SugaredLogger
+	userRepository      repository.UserRepository
+	roleGroupRepository repository.RoleGroupRepository
+	enforcer            casbin2.Enforcer
+}
+
+func NewRbacExplainServiceImpl(logger *zap.SugaredLogger,
+	userRepository repository.UserRepository,
+	roleGroupRepository repository.RoleGroupRepository,
+	enforcer casbin2.Enforcer) *RbacExplainServiceImpl {
+	return &RbacExplainServiceImpl{
+		logger:              logger,
+		userRepository:      userRepository,
+		roleGroupRepository: roleGroupRepository,
+		enforcer:            enforcer,
+	}
+}
+
+func (impl *RbacExplainServiceImpl) ResolveSubject(subject *userBean.RbacSubjectDto) (int32, string, error) {
+	if subject.UserId > 0 {
+		model, err := impl.userRepository.GetById(subject.UserId)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in getting user by id", "userId", subject.UserId, "err", err)
+			return 0, "", err
+		} else if err == pg.ErrNoRows {
+			return 0, "", util.NewApiError(http.StatusNotFound, userBean.RbacSubjectNotFoundMessage, userBean.RbacSubjectNotFoundMessage)
+		}
+		return model.Id, model.EmailId, nil
+	}
+	emailId := subject.EmailId
+	if len(emailId) == 0 && len(subject.ApiTokenName) > 0 {
+		emailId = fmt.Sprintf("%s%s", userBean.API_TOKEN_USER_EMAIL_PREFIX, subject.ApiTokenName)
+	}
+	if len(emailId) == 0 {
+		return 0, "", util.NewApiError(http.StatusBadRequest, userBean.RbacSubjectRequiredMessage, userBean.RbacSubjectRequiredMessage)
+	}
+	userInfo, err := impl.userRepository.FetchActiveUserByEmail(emailId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting user by email", "emailId", emailId, "err", err)
+		return 0, "", err
+	} else if userInfo.Id == 0 {
+		return 0, "", util.NewApiError(http.StatusNotFound, userBean.RbacSubjectNotFoundMessage, userBean.RbacSubjectNotFoundMessage)
+	}
+	return userInfo.Id, userInfo.EmailId, nil
+}
+
+func (impl *RbacExplainServiceImpl) Explain(request *userBean.RbacExplainRequest) (*userBean.RbacExplainResponse, error) {
+	userId, emailId, err := impl.ResolveSubject(&request.RbacSubjectDto)
+	if err != nil {
+		return nil, err
+	}
+	explanation, err := impl.enforcer.ExplainByEmail(emailId, request.Resource, request.Action, request.Object)
+	if err != nil {
+		impl.logger.Errorw("error in explaining enforce request", "emailId", emailId, "request", request, "err", err)
+		return nil, err
+	}
+	policies, err := impl.getPolicyDtos(append(explanation.MatchedPolicies, explanation.DenyPolicies...))
+	if err != nil {
+		return nil, err
+	}
+	response := &userBean.RbacExplainResponse{
+		UserId:          userId,
+		EmailId:         emailId,
+		Allowed:         explanation.Allowed,
+		MatchedPolicies: policies[:len(explanation.MatchedPolicies)],
+		DenyPolicies:    policies[len(explanation.MatchedPolicies):],
+	}
+	if response.Allowed {
+		response.Reason = fmt.Sprintf(userBean.RbacExplainAllowedReason, len(response.MatchedPolicies))
+	} else if len(response.DenyPolicies) > 0 {
+		response.Reason = fmt.Sprintf(userBean.RbacExplainDeniedByRuleReason, len(response.DenyPolicies))
+	} else {
+		response.Reason = userBean.RbacExplainNoMatchReason
+	}
+	return response, nil
+}
+
+func (impl *RbacExplainServiceImpl) GetEffectivePermissions(subject *userBean.RbacSubjectDto) (*userBean.EffectivePermissionsResponse, error) {
+	userId, emailId, err := impl.ResolveSubject(subject)
+	if err != nil {
+		return nil, err
+	}
+	grantedPolicies, err := impl.enforcer.GetEffectivePoliciesByEmail(emailId)
+	if err != nil {
+		impl.logger.Errorw("error in getting effective policies", "emailId", emailId, "err", err)
+		return nil, err
+	}
+	policies, err := impl.getPolicyDtos(grantedPolicies)
+	if err != nil {
+		return nil, err
+	}
+	response := &userBean.EffectivePermissionsResponse{
+		UserId:       userId,
+		EmailId:      emailId,
+		Permissions:  userHelper.BuildEffectivePermissions(policies),
+		DenyPolicies: make([]*userBean.RbacPolicyDto, 0),
+	}
+	for _, policy := range policies {
+		if policy.Effect == string(bean4.PolicyEffectDeny) {
+			response.DenyPolicies = append(response.DenyPolicies, policy)
+		} else if userHelper.IsSuperAdminPolicy(policy) {
+			response.SuperAdmin = true
+		}
+	}
+	return response, nil
+}
+
+// getPolicyDtos keeps the order of the policies and resolves the role group casbin names they were inherited through
+func (impl *RbacExplainServiceImpl) getPolicyDtos(grantedPolicies []*bean4.GrantedPolicy) ([]*userBean.RbacPolicyDto, error) {
+	var groupCasbinNames []string
+	for _, grantedPolicy := range grantedPolicies {
+		for _, subject := range getPolicySubjectChain(grantedPolicy) {
+			if strings.HasPrefix(subject, "group:") {
+				groupCasbinNames = append(groupCasbinNames, subject)
+			}
+		}
+	}
+	roleGroupNameByCasbinName := make(map[string]string)
+	if len(groupCasbinNames) > 0 {
+		roleGroups, err := impl.roleGroupRepository.GetRoleGroupListByCasbinNames(groupCasbinNames)
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in getting role groups by casbin names", "casbinNames", groupCasbinNames, "err", err)
+			return nil, err
+		}
+		for _, roleGroup := range roleGroups {
+			roleGroupNameByCasbinName[strings.ToLower(roleGroup.CasbinName)] = roleGroup.Name
+		}
+	}
+	policies := make([]*userBean.RbacPolicyDto, 0, len(grantedPolicies))
+	for _, grantedPolicy := range grantedPolicies {
+		policy := &userBean.RbacPolicyDto{
+			Subject:  string(grantedPolicy.Sub),
+			Resource: string(grantedPolicy.Res),
+			Action:   string(grantedPolicy.Act),
+			Object:   string(grantedPolicy.Obj),
+			Effect:   string(grantedPolicy.Effect),
+		}
+		for _, subject := range grantedPolicy.InheritedVia {
+			policy.InheritedVia = append(policy.InheritedVia, string(subject))
+		}
+		for _, subject := range getPolicySubjectChain(grantedPolicy) {
+			if roleGroupName, ok := roleGroupNameByCasbinName[subject]; ok {
+				policy.RoleGroups = append(policy.RoleGroups, roleGroupName)
+			}
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+func getPolicySubjectChain(grantedPolicy *bean4.GrantedPolicy) []string {
+	chain := make([]string, 0, len(grantedPolicy.InheritedVia)+1)
+	for _, subject := range grantedPolicy.InheritedVia {
+		chain = append(chain, string(subject))
+	}
+	return append(chain, string(grantedPolicy.Sub))
+}
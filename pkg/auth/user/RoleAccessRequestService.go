@@ -0,0 +1,321 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package user
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/caarlos0/env/v6"
+	"github.com/devtron-labs/devtron/internal/util"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	userHelper "github.com/devtron-labs/devtron/pkg/auth/user/helper"
+	"github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+// RoleAccessRequestService handles just in time access requests, an approved request is granted to the requester
+// as time bound role filters and role groups
+type RoleAccessRequestService interface {
+	CreateRequest(request *userBean.RoleAccessRequestDto) (*userBean.RoleAccessRequestDto, error)
+	// GetRequests returns requests of the user when userId is provided else requests of all users
+	GetRequests(userId int32, statuses []userBean.RoleAccessRequestStatus) ([]*userBean.RoleAccessRequestDto, error)
+	GetRequestById(id int) (*userBean.RoleAccessRequestDto, error)
+	ApproveRequest(review *userBean.RoleAccessRequestReviewDto, token string, managerAuth func(resource, token string, object string) bool) (*userBean.RoleAccessRequestDto, error)
+	RejectRequest(review *userBean.RoleAccessRequestReviewDto) (*userBean.RoleAccessRequestDto, error)
+	CancelRequest(review *userBean.RoleAccessRequestReviewDto) (*userBean.RoleAccessRequestDto, error)
+}
+
+type RoleAccessRequestServiceImpl struct {
+	logger                      *zap.SugaredLogger
+	userService                 UserService
+	userRepository              repository.UserRepository
+	roleAccessRequestRepository repository.RoleAccessRequestRepository
+	config                      *userBean.TimeBoundAccessConfig
+}
+
+func NewRoleAccessRequestServiceImpl(logger *zap.SugaredLogger,
+	userService UserService,
+	userRepository repository.UserRepository,
+	roleAccessRequestRepository repository.RoleAccessRequestRepository) (*RoleAccessRequestServiceImpl, error) {
+	config := &userBean.TimeBoundAccessConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing time bound access config", "err", err)
+		return nil, err
+	}
+	return &RoleAccessRequestServiceImpl{
+		logger:                      logger,
+		userService:                 userService,
+		userRepository:              userRepository,
+		roleAccessRequestRepository: roleAccessRequestRepository,
+		config:                      config,
+	}, nil
+}
+
+func (impl *RoleAccessRequestServiceImpl) CreateRequest(request *userBean.RoleAccessRequestDto) (*userBean.RoleAccessRequestDto, error) {
+	err := impl.validateRequest(request, time.Now())
+	if err != nil {
+		impl.logger.Errorw("invalid role access request", "request", request, "err", err)
+		return nil, err
+	}
+	// window is carried by the request, not by the individual filters
+	for i := range request.RoleFilters {
+		request.RoleFilters[i].ActiveFrom, request.RoleFilters[i].ExpiresAt = nil, nil
+	}
+	for i := range request.UserRoleGroups {
+		request.UserRoleGroups[i].ActiveFrom, request.UserRoleGroups[i].ExpiresAt = nil, nil
+	}
+	roleFilters, err := json.Marshal(request.RoleFilters)
+	if err != nil {
+		impl.logger.Errorw("error in marshalling role filters", "request", request, "err", err)
+		return nil, err
+	}
+	roleGroups, err := json.Marshal(request.UserRoleGroups)
+	if err != nil {
+		impl.logger.Errorw("error in marshalling role groups", "request", request, "err", err)
+		return nil, err
+	}
+	model := &repository.RoleAccessRequest{
+		UserId:      request.UserId,
+		RoleFilters: string(roleFilters),
+		RoleGroups:  string(roleGroups),
+		Reason:      request.Reason,
+		ExpiresAt:   *request.ExpiresAt,
+		Status:      userBean.RoleAccessRequestPending,
+		AuditLog:    sql.NewDefaultAuditLog(request.UserId),
+	}
+	if request.ActiveFrom != nil {
+		model.ActiveFrom = *request.ActiveFrom
+	}
+	err = impl.roleAccessRequestRepository.Save(model)
+	if err != nil {
+		impl.logger.Errorw("error in saving role access request", "request", request, "err", err)
+		return nil, err
+	}
+	return impl.getRoleAccessRequestDto(model, "")
+}
+
+func (impl *RoleAccessRequestServiceImpl) validateRequest(request *userBean.RoleAccessRequestDto, now time.Time) error {
+	if request.ExpiresAt == nil {
+		return util.NewApiError(http.StatusBadRequest, userBean.AccessRequestExpiryRequiredMessage, userBean.AccessRequestExpiryRequiredMessage)
+	}
+	if len(request.RoleFilters) == 0 && len(request.UserRoleGroups) == 0 {
+		return util.NewApiError(http.StatusBadRequest, userBean.AccessRequestEmptyMessage, userBean.AccessRequestEmptyMessage)
+	}
+	err := userHelper.ValidateTimeoutWindow(request.ActiveFrom, request.ExpiresAt, now)
+	if err != nil {
+		return err
+	}
+	windowStart := now
+	if request.ActiveFrom != nil && request.ActiveFrom.After(now) {
+		windowStart = *request.ActiveFrom
+	}
+	maxDuration := time.Duration(impl.config.MaxAccessRequestDurationHrs) * time.Hour
+	if request.ExpiresAt.Sub(windowStart) > maxDuration {
+		message := fmt.Sprintf(userBean.AccessRequestDurationExceededMessage, impl.config.MaxAccessRequestDurationHrs)
+		return util.NewApiError(http.StatusBadRequest, message, message)
+	}
+	return userHelper.ValidateUserRoleGroupRequest(request.UserRoleGroups)
+}
+
+func (impl *RoleAccessRequestServiceImpl) GetRequests(userId int32, statuses []userBean.RoleAccessRequestStatus) ([]*userBean.RoleAccessRequestDto, error) {
+	models, err := impl.roleAccessRequestRepository.FindAll(userId, statuses)
+	if err != nil {
+		return nil, err
+	}
+	userIds := make([]int32, 0, len(models))
+	for _, model := range models {
+		userIds = append(userIds, model.UserId)
+	}
+	userIdVsEmail := make(map[int32]string, len(userIds))
+	if len(userIds) > 0 {
+		users, err := impl.userRepository.GetByIds(userIds)
+		if err != nil {
+			impl.logger.Errorw("error in getting requesters of role access requests", "userIds", userIds, "err", err)
+			return nil, err
+		}
+		for _, user := range users {
+			userIdVsEmail[user.Id] = user.EmailId
+		}
+	}
+	requests := make([]*userBean.RoleAccessRequestDto, 0, len(models))
+	for _, model := range models {
+		request, err := impl.getRoleAccessRequestDto(model, userIdVsEmail[model.UserId])
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	return requests, nil
+}
+
+func (impl *RoleAccessRequestServiceImpl) GetRequestById(id int) (*userBean.RoleAccessRequestDto, error) {
+	model, err := impl.roleAccessRequestRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in getting role access request", "id", id, "err", err)
+		return nil, err
+	}
+	user, err := impl.userRepository.GetByIdIncludeDeleted(model.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in getting requester of role access request", "id", id, "err", err)
+		return nil, err
+	}
+	return impl.getRoleAccessRequestDto(model, user.EmailId)
+}
+
+func (impl *RoleAccessRequestServiceImpl) ApproveRequest(review *userBean.RoleAccessRequestReviewDto, token string, managerAuth func(resource, token string, object string) bool) (*userBean.RoleAccessRequestDto, error) {
+	model, request, err := impl.getPendingRequestForReview(review)
+	if err != nil {
+		return nil, err
+	}
+	if !request.ExpiresAt.After(time.Now()) {
+		return nil, util.NewApiError(http.StatusBadRequest, userBean.AccessRequestLapsedMessage, userBean.AccessRequestLapsedMessage)
+	}
+	userInfo, err := impl.userService.GetByIdWithoutGroupClaims(request.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in getting requester of role access request", "request", request, "err", err)
+		return nil, err
+	}
+	existingRoleFilters := make(map[string]bool, len(userInfo.RoleFilters))
+	for _, roleFilter := range userInfo.RoleFilters {
+		existingRoleFilters[getUniqueKeyForRoleFilter(roleFilter)] = true
+	}
+	for _, roleFilter := range request.RoleFilters {
+		if existingRoleFilters[getUniqueKeyForRoleFilter(roleFilter)] {
+			continue
+		}
+		roleFilter.ActiveFrom, roleFilter.ExpiresAt = request.ActiveFrom, request.ExpiresAt
+		userInfo.RoleFilters = append(userInfo.RoleFilters, roleFilter)
+	}
+	existingRoleGroups := make(map[string]bool, len(userInfo.UserRoleGroup))
+	for _, userRoleGroup := range userInfo.UserRoleGroup {
+		existingRoleGroups[getUniqueKeyForUserRoleGroup(userRoleGroup)] = true
+	}
+	for _, userRoleGroup := range request.UserRoleGroups {
+		if existingRoleGroups[getUniqueKeyForUserRoleGroup(userRoleGroup)] {
+			continue
+		}
+		userRoleGroup.ActiveFrom, userRoleGroup.ExpiresAt = request.ActiveFrom, request.ExpiresAt
+		userInfo.UserRoleGroup = append(userInfo.UserRoleGroup, userRoleGroup)
+	}
+	userInfo.UserId = review.UserId
+	// rbac of the reviewer over the requested access is checked by the caller
+	_, err = impl.userService.UpdateUser(userInfo, token, nil, managerAuth)
+	if err != nil {
+		impl.logger.Errorw("error in granting role access request", "request", request, "err", err)
+		return nil, err
+	}
+	return impl.updateReview(model, review, userBean.RoleAccessRequestApproved, request.EmailId)
+}
+
+func (impl *RoleAccessRequestServiceImpl) RejectRequest(review *userBean.RoleAccessRequestReviewDto) (*userBean.RoleAccessRequestDto, error) {
+	model, request, err := impl.getPendingRequestForReview(review)
+	if err != nil {
+		return nil, err
+	}
+	return impl.updateReview(model, review, userBean.RoleAccessRequestRejected, request.EmailId)
+}
+
+func (impl *RoleAccessRequestServiceImpl) CancelRequest(review *userBean.RoleAccessRequestReviewDto) (*userBean.RoleAccessRequestDto, error) {
+	model, err := impl.getPendingRequest(review.Id)
+	if err != nil {
+		return nil, err
+	}
+	if model.UserId != review.UserId {
+		return nil, util.NewApiError(http.StatusForbidden, "unauthorized user", "only the requester can cancel an access request")
+	}
+	return impl.updateReview(model, review, userBean.RoleAccessRequestCancelled, "")
+}
+
+func (impl *RoleAccessRequestServiceImpl) getPendingRequest(id int) (*repository.RoleAccessRequest, error) {
+	model, err := impl.roleAccessRequestRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in getting role access request", "id", id, "err", err)
+		return nil, err
+	}
+	if model.Status != userBean.RoleAccessRequestPending {
+		message := fmt.Sprintf(userBean.AccessRequestNotPendingMessage, model.Status)
+		return nil, util.NewApiError(http.StatusConflict, message, message)
+	}
+	return model, nil
+}
+
+func (impl *RoleAccessRequestServiceImpl) getPendingRequestForReview(review *userBean.RoleAccessRequestReviewDto) (*repository.RoleAccessRequest, *userBean.RoleAccessRequestDto, error) {
+	model, err := impl.getPendingRequest(review.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if model.UserId == review.UserId {
+		return nil, nil, util.NewApiError(http.StatusForbidden, userBean.AccessRequestSelfReviewMessage, userBean.AccessRequestSelfReviewMessage)
+	}
+	request, err := impl.getRoleAccessRequestDto(model, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	return model, request, nil
+}
+
+func (impl *RoleAccessRequestServiceImpl) updateReview(model *repository.RoleAccessRequest, review *userBean.RoleAccessRequestReviewDto, status userBean.RoleAccessRequestStatus, emailId string) (*userBean.RoleAccessRequestDto, error) {
+	model.Status = status
+	model.ReviewedBy = review.UserId
+	model.ReviewedOn = time.Now()
+	model.ReviewComment = review.Comment
+	model.UpdateAuditLog(review.UserId)
+	err := impl.roleAccessRequestRepository.Update(model)
+	if err != nil {
+		impl.logger.Errorw("error in updating role access request", "id", model.Id, "status", status, "err", err)
+		return nil, err
+	}
+	return impl.getRoleAccessRequestDto(model, emailId)
+}
+
+func (impl *RoleAccessRequestServiceImpl) getRoleAccessRequestDto(model *repository.RoleAccessRequest, emailId string) (*userBean.RoleAccessRequestDto, error) {
+	request := &userBean.RoleAccessRequestDto{
+		Id:            model.Id,
+		UserId:        model.UserId,
+		EmailId:       emailId,
+		Reason:        model.Reason,
+		Status:        model.Status,
+		ReviewedBy:    model.ReviewedBy,
+		ReviewComment: model.ReviewComment,
+		CreatedOn:     model.CreatedOn,
+	}
+	expiresAt := model.ExpiresAt
+	request.ExpiresAt = &expiresAt
+	if !model.ActiveFrom.IsZero() {
+		activeFrom := model.ActiveFrom
+		request.ActiveFrom = &activeFrom
+	}
+	if !model.ReviewedOn.IsZero() {
+		reviewedOn := model.ReviewedOn
+		request.ReviewedOn = &reviewedOn
+	}
+	err := json.Unmarshal([]byte(model.RoleFilters), &request.RoleFilters)
+	if err != nil {
+		impl.logger.Errorw("error in unmarshalling role filters of role access request", "id", model.Id, "err", err)
+		return nil, err
+	}
+	err = json.Unmarshal([]byte(model.RoleGroups), &request.UserRoleGroups)
+	if err != nil {
+		impl.logger.Errorw("error in unmarshalling role groups of role access request", "id", model.Id, "err", err)
+		return nil, err
+	}
+	return request, nil
+}
@@ -56,22 +56,25 @@ type RoleGroupService interface {
 }
 
 type RoleGroupServiceImpl struct {
-	userAuthRepository  repository.UserAuthRepository
-	logger              *zap.SugaredLogger
-	userRepository      repository.UserRepository
-	roleGroupRepository repository.RoleGroupRepository
-	userCommonService   UserCommonService
+	userAuthRepository         repository.UserAuthRepository
+	logger                     *zap.SugaredLogger
+	userRepository             repository.UserRepository
+	roleGroupRepository        repository.RoleGroupRepository
+	userCommonService          UserCommonService
+	userPermissionAuditService UserPermissionAuditService
 }
 
 func NewRoleGroupServiceImpl(userAuthRepository repository.UserAuthRepository,
 	logger *zap.SugaredLogger, userRepository repository.UserRepository,
-	roleGroupRepository repository.RoleGroupRepository, userCommonService UserCommonService) *RoleGroupServiceImpl {
+	roleGroupRepository repository.RoleGroupRepository, userCommonService UserCommonService,
+	userPermissionAuditService UserPermissionAuditService) *RoleGroupServiceImpl {
 	serviceImpl := &RoleGroupServiceImpl{
-		userAuthRepository:  userAuthRepository,
-		logger:              logger,
-		userRepository:      userRepository,
-		roleGroupRepository: roleGroupRepository,
-		userCommonService:   userCommonService,
+		userAuthRepository:         userAuthRepository,
+		logger:                     logger,
+		userRepository:             userRepository,
+		roleGroupRepository:        roleGroupRepository,
+		userCommonService:          userCommonService,
+		userPermissionAuditService: userPermissionAuditService,
 	}
 	cStore = sessions.NewCookieStore(randKey())
 	return serviceImpl
@@ -163,6 +166,18 @@ func (impl RoleGroupServiceImpl) CreateRoleGroup(request *bean2.RoleGroup) (*bea
 			casbin2.LoadPolicy()
 		}
 		//Ends
+		err = impl.userPermissionAuditService.SaveAudit(tx, &bean2.PermissionAuditDto{
+			EntityType:   bean2.PermissionAuditEntityRoleGroup,
+			EntityId:     model.Id,
+			EntityName:   model.Name,
+			Action:       bean2.PermissionAuditActionCreate,
+			ActorId:      request.UserId,
+			CurrentState: adapter.BuildPermissionAuditStateForRoleGroup(request),
+		})
+		if err != nil {
+			impl.logger.Errorw("error in saving permission audit for role group", "roleGroup", request.Name, "err", err)
+			return nil, err
+		}
 	}
 	err = tx.Commit()
 	if err != nil {
@@ -451,6 +466,11 @@ func (impl RoleGroupServiceImpl) UpdateRoleGroup(request *bean2.RoleGroup, token
 		impl.logger.Errorw("error encountered in UpdateRoleGroup", "error", err, "roleGroupId", roleGroup.Id)
 		return nil, err
 	}
+	previousAuditState, err := impl.getPermissionAuditState(roleGroup)
+	if err != nil {
+		impl.logger.Errorw("error encountered in UpdateRoleGroup", "error", err, "roleGroupId", roleGroup.Id)
+		return nil, err
+	}
 
 	//policyGroup.Name = request.Name
 	roleGroup.Description = request.Description
@@ -517,6 +537,19 @@ func (impl RoleGroupServiceImpl) UpdateRoleGroup(request *bean2.RoleGroup, token
 	//loading policy for syncing orchestrator to casbin with newly added policies
 	//(not calling this method in above if condition because we are also removing policies in this update service)
 	casbin2.LoadPolicy()
+	err = impl.userPermissionAuditService.SaveAudit(tx, &bean2.PermissionAuditDto{
+		EntityType:    bean2.PermissionAuditEntityRoleGroup,
+		EntityId:      roleGroup.Id,
+		EntityName:    roleGroup.Name,
+		Action:        bean2.PermissionAuditActionUpdate,
+		ActorId:       request.UserId,
+		PreviousState: previousAuditState,
+		CurrentState:  adapter.BuildPermissionAuditStateForRoleGroup(&bean2.RoleGroup{Name: roleGroup.Name, Description: request.Description, RoleFilters: request.RoleFilters, SuperAdmin: request.SuperAdmin}),
+	})
+	if err != nil {
+		impl.logger.Errorw("error in saving permission audit for role group", "roleGroupId", roleGroup.Id, "err", err)
+		return nil, err
+	}
 	err = tx.Commit()
 	if err != nil {
 		return nil, err
@@ -778,6 +811,10 @@ func (impl RoleGroupServiceImpl) DeleteRoleGroup(bean *bean2.RoleGroup) (bool, e
 		impl.logger.Errorw("error while fetching user from db", "error", err)
 		return false, err
 	}
+	err = impl.createAuditForDeleteOperation(tx, []*repository.RoleGroup{model}, bean.UserId)
+	if err != nil {
+		return false, err
+	}
 	roleGroupRoleMappingIds, err := impl.roleGroupRepository.GetRoleGroupRoleMappingIdsByRoleGroupId(model.Id)
 	if err != nil {
 		impl.logger.Errorw("error in getting all role group role mappings or not found", "err", err)
@@ -831,6 +868,42 @@ func (impl RoleGroupServiceImpl) DeleteRoleGroup(bean *bean2.RoleGroup) (bool, e
 	return true, nil
 }
 
+func (impl RoleGroupServiceImpl) createAuditForDeleteOperation(tx *pg.Tx, models []*repository.RoleGroup, userLoggedInId int32) error {
+	for _, model := range models {
+		previousAuditState, err := impl.getPermissionAuditState(model)
+		if err != nil {
+			impl.logger.Errorw("error in getting permission audit state for role group", "roleGroupId", model.Id, "err", err)
+			return err
+		}
+		err = impl.userPermissionAuditService.SaveAudit(tx, &bean2.PermissionAuditDto{
+			EntityType:    bean2.PermissionAuditEntityRoleGroup,
+			EntityId:      model.Id,
+			EntityName:    model.Name,
+			Action:        bean2.PermissionAuditActionDelete,
+			ActorId:       userLoggedInId,
+			PreviousState: previousAuditState,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getPermissionAuditState reads the permissions of the role group as saved
+func (impl RoleGroupServiceImpl) getPermissionAuditState(roleGroup *repository.RoleGroup) (*bean2.PermissionAuditState, error) {
+	roleFilters, superAdmin, err := impl.getRoleGroupMetadata(roleGroup)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.BuildPermissionAuditStateForRoleGroup(&bean2.RoleGroup{
+		Name:        roleGroup.Name,
+		Description: roleGroup.Description,
+		RoleFilters: roleFilters,
+		SuperAdmin:  superAdmin,
+	}), nil
+}
+
 // BulkDeleteRoleGroups takes in bulk delete request and return error
 func (impl RoleGroupServiceImpl) BulkDeleteRoleGroups(request *bean2.BulkDeleteRequest) (bool, error) {
 	// it handles ListingRequest if filters are applied will delete those users or will consider the given user ids.
@@ -885,6 +958,16 @@ func (impl RoleGroupServiceImpl) deleteRoleGroupsByIds(request *bean2.BulkDelete
 		impl.logger.Errorw("error in deleteRoleGroupsByIds", "request", request, "err", err)
 		return err
 	}
+	// audit before the mappings are removed, the previous state is read from them
+	models, err := impl.roleGroupRepository.GetRoleGroupListByIds(request.Ids)
+	if err != nil {
+		impl.logger.Errorw("error in deleteRoleGroupsByIds", "request", request, "err", err)
+		return err
+	}
+	err = impl.createAuditForDeleteOperation(tx, models, request.LoggedInUserId)
+	if err != nil {
+		return err
+	}
 	// delete mappings from orchestrator
 	err = impl.deleteMappingsFromOrchestrator(request.Ids, tx)
 	if err != nil {
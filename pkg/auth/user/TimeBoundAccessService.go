@@ -0,0 +1,526 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package user
+
+import (
+	"fmt"
+	"github.com/caarlos0/env/v6"
+	casbin2 "github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	bean4 "github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/user/adapter"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	userHelper "github.com/devtron-labs/devtron/pkg/auth/user/helper"
+	"github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	"github.com/go-pg/pg"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TimeBoundAccessService manages the time windows of role grants and group memberships. Casbin only knows about
+// grants which are active right now, so a grant is added to casbin when its window opens and removed when it lapses.
+type TimeBoundAccessService interface {
+	// GetOrCreateTimeoutWindowConfig returns nil for a permanent grant
+	GetOrCreateTimeoutWindowConfig(tx *pg.Tx, activeFrom, expiresAt *time.Time, userId int32) (*userBean.TimeoutWindowConfigDto, error)
+	GetTimeoutWindowConfigsByIds(ids []int) (map[int]*userBean.TimeoutWindowConfigDto, error)
+	// GetActiveRoleGroupTimeoutWindows returns role group id vs window of the time bound group memberships of the user
+	GetActiveRoleGroupTimeoutWindows(userId int32) (map[int32]*userBean.TimeoutWindowConfigDto, error)
+	// SaveRoleGroupTimeoutWindows replaces the time bound group memberships of the user with the given ones, nil
+	// windows are treated as permanent memberships
+	SaveRoleGroupTimeoutWindows(tx *pg.Tx, userId int32, roleGroupIdVsWindow map[int32]*userBean.TimeoutWindowConfigDto, loggedInUserId int32) error
+	DeactivateRoleGroupTimeoutWindows(tx *pg.Tx, userIds []int32, loggedInUserId int32) error
+	// SyncUserGrants reloads the time bound grants of the user and applies them to casbin
+	SyncUserGrants(userId int32, emailId string)
+	// SyncIfDue is called before every enforcement, it syncs the user only if one of their grants started or lapsed
+	SyncIfDue(emailId string)
+	// SyncAllGrants is the background sweeper
+	SyncAllGrants()
+}
+
+type TimeBoundAccessServiceImpl struct {
+	logger                               *zap.SugaredLogger
+	userAuthRepository                   repository.UserAuthRepository
+	userRepository                       repository.UserRepository
+	roleGroupRepository                  repository.RoleGroupRepository
+	timeoutWindowConfigRepository        repository.TimeoutWindowConfigRepository
+	userRoleGroupTimeoutWindowRepository repository.UserRoleGroupTimeoutWindowRepository
+	config                               *userBean.TimeBoundAccessConfig
+	sweeperCron                          *cron.Cron
+
+	// syncLock serialises syncs, grantsLock guards the in memory state below
+	syncLock      sync.Mutex
+	grantsLock    sync.RWMutex
+	grantsByEmail map[string]*userTimeBoundGrants
+	grantsLoaded  bool
+}
+
+type timeBoundGrant struct {
+	policy          bean4.Policy
+	window          *userBean.TimeoutWindowConfigDto
+	userRoleMapping *repository.UserRoleModel
+	roleGroupWindow *repository.UserRoleGroupTimeoutWindow
+}
+
+type userTimeBoundGrants struct {
+	userId         int32
+	grants         []*timeBoundGrant
+	nextTransition *time.Time
+}
+
+func (g *userTimeBoundGrants) isDueAt(t time.Time) bool {
+	return g.nextTransition != nil && !t.Before(*g.nextTransition)
+}
+
+func NewTimeBoundAccessServiceImpl(logger *zap.SugaredLogger,
+	userAuthRepository repository.UserAuthRepository,
+	userRepository repository.UserRepository,
+	roleGroupRepository repository.RoleGroupRepository,
+	timeoutWindowConfigRepository repository.TimeoutWindowConfigRepository,
+	userRoleGroupTimeoutWindowRepository repository.UserRoleGroupTimeoutWindowRepository,
+	cronLogger *cron2.CronLoggerImpl) (*TimeBoundAccessServiceImpl, error) {
+	config := &userBean.TimeBoundAccessConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing time bound access config", "err", err)
+		return nil, err
+	}
+	sweeperCron := cron.New(cron.WithChain(cron.SkipIfStillRunning(cronLogger), cron.Recover(cronLogger)))
+	impl := &TimeBoundAccessServiceImpl{
+		logger:                               logger,
+		userAuthRepository:                   userAuthRepository,
+		userRepository:                       userRepository,
+		roleGroupRepository:                  roleGroupRepository,
+		timeoutWindowConfigRepository:        timeoutWindowConfigRepository,
+		userRoleGroupTimeoutWindowRepository: userRoleGroupTimeoutWindowRepository,
+		config:                               config,
+		sweeperCron:                          sweeperCron,
+		grantsByEmail:                        make(map[string]*userTimeBoundGrants),
+	}
+	sweeperCron.Start()
+	_, err = sweeperCron.AddFunc(fmt.Sprintf("@every %ds", config.SweeperIntervalInSecs), impl.SyncAllGrants)
+	if err != nil {
+		logger.Errorw("error in starting time bound access sweeper", "err", err)
+		return nil, err
+	}
+	casbin2.SetTimeBoundAccessSyncer(impl)
+	return impl, nil
+}
+
+func (impl *TimeBoundAccessServiceImpl) GetOrCreateTimeoutWindowConfig(tx *pg.Tx, activeFrom, expiresAt *time.Time, userId int32) (*userBean.TimeoutWindowConfigDto, error) {
+	if !userHelper.HasTimeoutWindow(activeFrom, expiresAt) {
+		return nil, nil
+	}
+	expression, format := userHelper.GetTimeoutWindowExpression(activeFrom, expiresAt)
+	model, err := impl.timeoutWindowConfigRepository.GetByExpression(expression, format, tx)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in getting timeout window config", "expression", expression, "err", err)
+		return nil, err
+	}
+	if err == pg.ErrNoRows {
+		model = &repository.TimeoutWindowConfiguration{
+			TimeoutWindowExpression: expression,
+			ExpressionFormat:        format,
+			AuditLog:                sql.NewDefaultAuditLog(userId),
+		}
+		model, err = impl.timeoutWindowConfigRepository.CreateWithTxn(model, tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return impl.getTimeoutWindowConfigDto(model)
+}
+
+func (impl *TimeBoundAccessServiceImpl) getTimeoutWindowConfigDto(model *repository.TimeoutWindowConfiguration) (*userBean.TimeoutWindowConfigDto, error) {
+	activeFrom, expiresAt, err := userHelper.ParseTimeoutWindowExpression(model.TimeoutWindowExpression, model.ExpressionFormat)
+	if err != nil {
+		impl.logger.Errorw("error in parsing timeout window expression", "id", model.Id, "expression", model.TimeoutWindowExpression, "err", err)
+		return nil, err
+	}
+	return &userBean.TimeoutWindowConfigDto{Id: model.Id, ActiveFrom: activeFrom, ExpiresAt: expiresAt}, nil
+}
+
+func (impl *TimeBoundAccessServiceImpl) GetTimeoutWindowConfigsByIds(ids []int) (map[int]*userBean.TimeoutWindowConfigDto, error) {
+	idVsConfig := make(map[int]*userBean.TimeoutWindowConfigDto, len(ids))
+	models, err := impl.timeoutWindowConfigRepository.GetByIds(ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, model := range models {
+		dto, err := impl.getTimeoutWindowConfigDto(model)
+		if err != nil {
+			return nil, err
+		}
+		idVsConfig[model.Id] = dto
+	}
+	return idVsConfig, nil
+}
+
+func (impl *TimeBoundAccessServiceImpl) GetActiveRoleGroupTimeoutWindows(userId int32) (map[int32]*userBean.TimeoutWindowConfigDto, error) {
+	models, err := impl.userRoleGroupTimeoutWindowRepository.GetActiveByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+	twcIds := make([]int, 0, len(models))
+	for _, model := range models {
+		twcIds = append(twcIds, model.TimeoutWindowConfigurationId)
+	}
+	idVsConfig, err := impl.GetTimeoutWindowConfigsByIds(twcIds)
+	if err != nil {
+		return nil, err
+	}
+	roleGroupIdVsWindow := make(map[int32]*userBean.TimeoutWindowConfigDto, len(models))
+	for _, model := range models {
+		if window, ok := idVsConfig[model.TimeoutWindowConfigurationId]; ok {
+			roleGroupIdVsWindow[model.RoleGroupId] = window
+		}
+	}
+	return roleGroupIdVsWindow, nil
+}
+
+func (impl *TimeBoundAccessServiceImpl) SaveRoleGroupTimeoutWindows(tx *pg.Tx, userId int32, roleGroupIdVsWindow map[int32]*userBean.TimeoutWindowConfigDto, loggedInUserId int32) error {
+	existingModels, err := impl.userRoleGroupTimeoutWindowRepository.GetActiveByUserId(userId)
+	if err != nil {
+		return err
+	}
+	existingByRoleGroupId := make(map[int32]*repository.UserRoleGroupTimeoutWindow, len(existingModels))
+	for _, existing := range existingModels {
+		existingByRoleGroupId[existing.RoleGroupId] = existing
+	}
+	for roleGroupId, window := range roleGroupIdVsWindow {
+		if window == nil {
+			continue
+		}
+		existing, ok := existingByRoleGroupId[roleGroupId]
+		if ok {
+			delete(existingByRoleGroupId, roleGroupId)
+			if existing.TimeoutWindowConfigurationId == window.Id {
+				continue
+			}
+			existing.TimeoutWindowConfigurationId = window.Id
+			existing.UpdateAuditLog(loggedInUserId)
+			err = impl.userRoleGroupTimeoutWindowRepository.Update(existing, tx)
+		} else {
+			err = impl.userRoleGroupTimeoutWindowRepository.Save(&repository.UserRoleGroupTimeoutWindow{
+				UserId:                       userId,
+				RoleGroupId:                  roleGroupId,
+				TimeoutWindowConfigurationId: window.Id,
+				Active:                       true,
+				AuditLog:                     sql.NewDefaultAuditLog(loggedInUserId),
+			}, tx)
+		}
+		if err != nil {
+			impl.logger.Errorw("error in saving role group timeout window", "userId", userId, "roleGroupId", roleGroupId, "err", err)
+			return err
+		}
+	}
+	// memberships which are removed or made permanent are no longer time bound
+	for _, existing := range existingByRoleGroupId {
+		existing.Active = false
+		existing.UpdateAuditLog(loggedInUserId)
+		err = impl.userRoleGroupTimeoutWindowRepository.Update(existing, tx)
+		if err != nil {
+			impl.logger.Errorw("error in deactivating role group timeout window", "id", existing.Id, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (impl *TimeBoundAccessServiceImpl) DeactivateRoleGroupTimeoutWindows(tx *pg.Tx, userIds []int32, loggedInUserId int32) error {
+	return impl.userRoleGroupTimeoutWindowRepository.DeactivateByUserIds(userIds, loggedInUserId, tx)
+}
+
+func (impl *TimeBoundAccessServiceImpl) SyncIfDue(emailId string) {
+	impl.grantsLock.RLock()
+	grantsLoaded := impl.grantsLoaded
+	userGrants := impl.grantsByEmail[emailId]
+	impl.grantsLock.RUnlock()
+	if !grantsLoaded {
+		// casbin is ready by the time first enforcement happens, so the initial load is done lazily
+		impl.syncAllGrants(true)
+		return
+	}
+	if userGrants == nil || !userGrants.isDueAt(time.Now()) {
+		return
+	}
+	impl.syncUserGrants(userGrants.userId, emailId, true)
+}
+
+func (impl *TimeBoundAccessServiceImpl) SyncUserGrants(userId int32, emailId string) {
+	impl.syncUserGrants(userId, strings.ToLower(emailId), false)
+}
+
+func (impl *TimeBoundAccessServiceImpl) syncUserGrants(userId int32, emailId string, onlyIfDue bool) {
+	impl.syncLock.Lock()
+	defer impl.syncLock.Unlock()
+	impl.grantsLock.RLock()
+	staleGrants := impl.grantsByEmail[emailId]
+	impl.grantsLock.RUnlock()
+	if onlyIfDue && (staleGrants == nil || !staleGrants.isDueAt(time.Now())) {
+		// already synced by a concurrent enforcement while waiting for the lock
+		return
+	}
+	freshGrants, err := impl.getTimeBoundGrants(userId)
+	if err != nil {
+		impl.logger.Errorw("error in getting time bound grants of user, removing lapsed grants only", "userId", userId, "err", err)
+		impl.applyGrants(emailId, staleGrants, nil, time.Now())
+		return
+	}
+	syncedGrants := impl.applyGrants(emailId, staleGrants, freshGrants[emailId], time.Now())
+	impl.grantsLock.Lock()
+	defer impl.grantsLock.Unlock()
+	if syncedGrants == nil {
+		delete(impl.grantsByEmail, emailId)
+	} else {
+		impl.grantsByEmail[emailId] = syncedGrants
+	}
+}
+
+func (impl *TimeBoundAccessServiceImpl) SyncAllGrants() {
+	impl.syncAllGrants(false)
+}
+
+func (impl *TimeBoundAccessServiceImpl) syncAllGrants(onlyIfNotLoaded bool) {
+	impl.syncLock.Lock()
+	defer impl.syncLock.Unlock()
+	impl.grantsLock.RLock()
+	grantsLoaded := impl.grantsLoaded
+	staleGrantsByEmail := impl.grantsByEmail
+	impl.grantsLock.RUnlock()
+	if onlyIfNotLoaded && grantsLoaded {
+		return
+	}
+	freshGrantsByEmail, err := impl.getTimeBoundGrants(0)
+	if err != nil {
+		// not retrying on every enforcement, the next sweep will load the grants
+		impl.logger.Errorw("error in getting time bound grants", "err", err)
+		impl.grantsLock.Lock()
+		impl.grantsLoaded = true
+		impl.grantsLock.Unlock()
+		return
+	}
+	now := time.Now()
+	syncedGrantsByEmail := make(map[string]*userTimeBoundGrants, len(freshGrantsByEmail))
+	for emailId, freshGrants := range freshGrantsByEmail {
+		syncedGrants := impl.applyGrants(emailId, staleGrantsByEmail[emailId], freshGrants, now)
+		if syncedGrants != nil {
+			syncedGrantsByEmail[emailId] = syncedGrants
+		}
+	}
+	for emailId, staleGrants := range staleGrantsByEmail {
+		if _, ok := freshGrantsByEmail[emailId]; !ok {
+			impl.applyGrants(emailId, staleGrants, nil, now)
+		}
+	}
+	impl.grantsLock.Lock()
+	defer impl.grantsLock.Unlock()
+	impl.grantsByEmail = syncedGrantsByEmail
+	impl.grantsLoaded = true
+}
+
+// applyGrants brings casbin in line with the windows of the user's grants at the given time and cleans up lapsed
+// grants. Removals are also applied for the previously known grants so that a lapsed grant is revoked even if its
+// mapping was already cleaned up by another instance.
+func (impl *TimeBoundAccessServiceImpl) applyGrants(emailId string, staleGrants, freshGrants *userTimeBoundGrants, now time.Time) *userTimeBoundGrants {
+	casbinRoles, err := casbin2.GetRolesForUser(emailId)
+	if err != nil {
+		impl.logger.Errorw("error in getting casbin roles for user", "emailId", emailId, "err", err)
+		return staleGrants
+	}
+	casbinRoleSet := make(map[string]bool, len(casbinRoles))
+	for _, role := range casbinRoles {
+		casbinRoleSet[role] = true
+	}
+	policiesToRemove := make([]bean4.Policy, 0)
+	policiesToAdd := make([]bean4.Policy, 0)
+	lapsedGrants := make([]*timeBoundGrant, 0)
+	removedRoles := make(map[string]bool)
+	if staleGrants != nil {
+		for _, grant := range staleGrants.grants {
+			role := strings.ToLower(string(grant.policy.Obj))
+			if !grant.window.IsActiveAt(now) && casbinRoleSet[role] && !removedRoles[role] {
+				policiesToRemove = append(policiesToRemove, grant.policy)
+				removedRoles[role] = true
+			}
+		}
+	}
+	var syncedGrants *userTimeBoundGrants
+	if freshGrants != nil {
+		syncedGrants = &userTimeBoundGrants{userId: freshGrants.userId}
+		for _, grant := range freshGrants.grants {
+			role := strings.ToLower(string(grant.policy.Obj))
+			isActive := grant.window.IsActiveAt(now)
+			if isActive && !casbinRoleSet[role] {
+				policiesToAdd = append(policiesToAdd, grant.policy)
+			} else if !isActive && casbinRoleSet[role] && !removedRoles[role] {
+				policiesToRemove = append(policiesToRemove, grant.policy)
+				removedRoles[role] = true
+			}
+			if grant.window.IsExpiredAt(now) {
+				lapsedGrants = append(lapsedGrants, grant)
+				continue
+			}
+			syncedGrants.grants = append(syncedGrants.grants, grant)
+			if next := grant.window.GetNextTransitionAfter(now); next != nil &&
+				(syncedGrants.nextTransition == nil || next.Before(*syncedGrants.nextTransition)) {
+				syncedGrants.nextTransition = next
+			}
+		}
+		if len(syncedGrants.grants) == 0 {
+			syncedGrants = nil
+		}
+	}
+	if len(policiesToRemove) > 0 {
+		impl.logger.Infow("removing casbin policies of inactive time bound grants", "emailId", emailId, "policies", policiesToRemove)
+		casbin2.RemovePolicy(policiesToRemove)
+	}
+	if len(policiesToAdd) > 0 {
+		impl.logger.Infow("adding casbin policies of time bound grants which became active", "emailId", emailId, "policies", policiesToAdd)
+		casbin2.AddPolicy(policiesToAdd)
+	}
+	if len(lapsedGrants) > 0 {
+		err = impl.deleteLapsedGrants(lapsedGrants)
+		if err != nil {
+			// casbin is already updated, the mappings will be cleaned up in the next sweep
+			impl.logger.Errorw("error in cleaning up lapsed time bound grants", "emailId", emailId, "err", err)
+		}
+	}
+	return syncedGrants
+}
+
+func (impl *TimeBoundAccessServiceImpl) deleteLapsedGrants(lapsedGrants []*timeBoundGrant) error {
+	dbConnection := impl.userRepository.GetConnection()
+	tx, err := dbConnection.Begin()
+	if err != nil {
+		return err
+	}
+	// Rollback tx on error.
+	defer tx.Rollback()
+	for _, grant := range lapsedGrants {
+		if grant.userRoleMapping != nil {
+			_, err = impl.userAuthRepository.DeleteUserRoleMapping(grant.userRoleMapping, tx)
+		} else if grant.roleGroupWindow != nil {
+			grant.roleGroupWindow.Active = false
+			grant.roleGroupWindow.UpdateAuditLog(userBean.SystemUserId)
+			err = impl.userRoleGroupTimeoutWindowRepository.Update(grant.roleGroupWindow, tx)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// getTimeBoundGrants loads the time bound grants of the given user, or of all users when userId is 0, keyed by email
+func (impl *TimeBoundAccessServiceImpl) getTimeBoundGrants(userId int32) (map[string]*userTimeBoundGrants, error) {
+	var userRoleMappings []*repository.UserRoleModel
+	var roleGroupWindows []*repository.UserRoleGroupTimeoutWindow
+	var err error
+	if userId > 0 {
+		userRoleMappings, err = impl.userAuthRepository.GetUserRoleMappingByUserId(userId)
+		if err == nil {
+			roleGroupWindows, err = impl.userRoleGroupTimeoutWindowRepository.GetActiveByUserId(userId)
+		}
+	} else {
+		userRoleMappings, err = impl.userAuthRepository.GetUserRoleMappingsWithTimeoutWindow()
+		if err == nil {
+			roleGroupWindows, err = impl.userRoleGroupTimeoutWindowRepository.GetAllActive()
+		}
+	}
+	if err != nil && err != pg.ErrNoRows {
+		return nil, err
+	}
+	userIds := make([]int32, 0)
+	roleIds := make([]int, 0)
+	roleGroupIds := make([]int32, 0)
+	twcIds := make([]int, 0)
+	for _, mapping := range userRoleMappings {
+		if mapping.TimeoutWindowConfigurationId == 0 {
+			continue
+		}
+		userIds = append(userIds, mapping.UserId)
+		roleIds = append(roleIds, mapping.RoleId)
+		twcIds = append(twcIds, mapping.TimeoutWindowConfigurationId)
+	}
+	for _, roleGroupWindow := range roleGroupWindows {
+		userIds = append(userIds, roleGroupWindow.UserId)
+		roleGroupIds = append(roleGroupIds, roleGroupWindow.RoleGroupId)
+		twcIds = append(twcIds, roleGroupWindow.TimeoutWindowConfigurationId)
+	}
+	grantsByEmail := make(map[string]*userTimeBoundGrants)
+	if len(userIds) == 0 {
+		return grantsByEmail, nil
+	}
+	users, err := impl.userRepository.GetByIds(userIds)
+	if err != nil {
+		return nil, err
+	}
+	userIdVsEmail := make(map[int32]string, len(users))
+	for _, user := range users {
+		userIdVsEmail[user.Id] = user.EmailId
+	}
+	idVsWindow, err := impl.GetTimeoutWindowConfigsByIds(twcIds)
+	if err != nil {
+		return nil, err
+	}
+	roleIdVsRole := make(map[int]string)
+	if len(roleIds) > 0 {
+		roles, err := impl.userAuthRepository.GetRolesByIds(roleIds)
+		if err != nil {
+			return nil, err
+		}
+		for _, role := range roles {
+			roleIdVsRole[role.Id] = role.Role
+		}
+	}
+	roleGroupIdVsCasbinName := make(map[int32]string)
+	if len(roleGroupIds) > 0 {
+		roleGroups, err := impl.roleGroupRepository.GetRoleGroupListByIds(roleGroupIds)
+		if err != nil {
+			return nil, err
+		}
+		for _, roleGroup := range roleGroups {
+			roleGroupIdVsCasbinName[roleGroup.Id] = roleGroup.CasbinName
+		}
+	}
+	addGrant := func(userId int32, role string, window *userBean.TimeoutWindowConfigDto, grant *timeBoundGrant) {
+		emailId, ok := userIdVsEmail[userId]
+		if !ok || len(role) == 0 || window == nil {
+			return
+		}
+		grant.policy = adapter.GetCasbinGroupPolicy(emailId, role, window)
+		grant.window = window
+		if _, ok := grantsByEmail[emailId]; !ok {
+			grantsByEmail[emailId] = &userTimeBoundGrants{userId: userId}
+		}
+		grantsByEmail[emailId].grants = append(grantsByEmail[emailId].grants, grant)
+	}
+	for _, mapping := range userRoleMappings {
+		if mapping.TimeoutWindowConfigurationId == 0 {
+			continue
+		}
+		addGrant(mapping.UserId, roleIdVsRole[mapping.RoleId], idVsWindow[mapping.TimeoutWindowConfigurationId], &timeBoundGrant{userRoleMapping: mapping})
+	}
+	for _, roleGroupWindow := range roleGroupWindows {
+		addGrant(roleGroupWindow.UserId, roleGroupIdVsCasbinName[roleGroupWindow.RoleGroupId], idVsWindow[roleGroupWindow.TimeoutWindowConfigurationId], &timeBoundGrant{roleGroupWindow: roleGroupWindow})
+	}
+	return grantsByEmail, nil
+}
@@ -400,6 +400,7 @@ func WhitelistChecker(url string) bool {
 		"/orchestrator/auth/login",
 		"/dashboard",
 		"/orchestrator/webhook/git",
+		"/orchestrator/scim/v2",
 	}
 	for _, a := range prefixUrls {
 		if strings.Contains(url, a) {
@@ -0,0 +1,231 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package user
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	userHelper "github.com/devtron-labs/devtron/pkg/auth/user/helper"
+	"github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"strconv"
+	"time"
+)
+
+// UserPermissionAuditService records every change to users, role groups, api tokens and sso config with the state
+// before and after the change. Audits are saved in the transaction of the change when one is passed.
+type UserPermissionAuditService interface {
+	SaveAudit(tx *pg.Tx, audit *userBean.PermissionAuditDto) error
+	GetAudits(filter *userBean.PermissionAuditFilter) (*userBean.PermissionAuditListingResponse, error)
+	// ExportAudits returns all the audits matching the filter as csv, pagination is ignored
+	ExportAudits(filter *userBean.PermissionAuditFilter) ([]byte, error)
+}
+
+type UserPermissionAuditServiceImpl struct {
+	logger                        *zap.SugaredLogger
+	userPermissionAuditRepository repository.UserPermissionAuditRepository
+	userRepository                repository.UserRepository
+}
+
+func NewUserPermissionAuditServiceImpl(logger *zap.SugaredLogger,
+	userPermissionAuditRepository repository.UserPermissionAuditRepository,
+	userRepository repository.UserRepository) *UserPermissionAuditServiceImpl {
+	return &UserPermissionAuditServiceImpl{
+		logger:                        logger,
+		userPermissionAuditRepository: userPermissionAuditRepository,
+		userRepository:                userRepository,
+	}
+}
+
+func (impl *UserPermissionAuditServiceImpl) SaveAudit(tx *pg.Tx, audit *userBean.PermissionAuditDto) error {
+	previousState, err := marshalPermissionAuditState(audit.PreviousState)
+	if err != nil {
+		return err
+	}
+	currentState, err := marshalPermissionAuditState(audit.CurrentState)
+	if err != nil {
+		return err
+	}
+	model := &repository.UserPermissionAudit{
+		EntityType:    audit.EntityType,
+		EntityId:      audit.EntityId,
+		EntityName:    audit.EntityName,
+		Action:        audit.Action,
+		PreviousState: previousState,
+		CurrentState:  currentState,
+		AuditLog:      sql.NewDefaultAuditLog(audit.ActorId),
+	}
+	if tx != nil {
+		err = impl.userPermissionAuditRepository.SaveWithTx(model, tx)
+	} else {
+		err = impl.userPermissionAuditRepository.Save(model)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving user permission audit", "entityType", audit.EntityType, "entityId", audit.EntityId, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *UserPermissionAuditServiceImpl) GetAudits(filter *userBean.PermissionAuditFilter) (*userBean.PermissionAuditListingResponse, error) {
+	size := filter.Size
+	if size <= 0 {
+		size = userBean.PermissionAuditDefaultPageSize
+	}
+	audits, totalCount, err := impl.getAudits(filter, filter.Offset, size)
+	if err != nil {
+		return nil, err
+	}
+	return &userBean.PermissionAuditListingResponse{Audits: audits, TotalCount: totalCount}, nil
+}
+
+func (impl *UserPermissionAuditServiceImpl) ExportAudits(filter *userBean.PermissionAuditFilter) ([]byte, error) {
+	audits, _, err := impl.getAudits(filter, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	buffer := &bytes.Buffer{}
+	writer := csv.NewWriter(buffer)
+	rows := [][]string{{"id", "timestamp", "actor_id", "actor_email", "entity_type", "entity_id", "entity_name", "action",
+		"super_admin_changed", "added_role_filters", "removed_role_filters", "added_role_groups", "removed_role_groups",
+		"changed_attributes", "previous_state", "current_state"}}
+	for _, audit := range audits {
+		rows = append(rows, []string{
+			strconv.Itoa(audit.Id),
+			audit.CreatedOn.UTC().Format(time.RFC3339),
+			strconv.Itoa(int(audit.ActorId)),
+			audit.ActorEmailId,
+			string(audit.EntityType),
+			strconv.Itoa(int(audit.EntityId)),
+			audit.EntityName,
+			string(audit.Action),
+			strconv.FormatBool(audit.Diff.SuperAdminChanged),
+			toJsonString(audit.Diff.AddedRoleFilters),
+			toJsonString(audit.Diff.RemovedRoleFilters),
+			toJsonString(audit.Diff.AddedRoleGroups),
+			toJsonString(audit.Diff.RemovedRoleGroups),
+			toJsonString(audit.Diff.ChangedAttributes),
+			toJsonString(audit.PreviousState),
+			toJsonString(audit.CurrentState),
+		})
+	}
+	err = writer.WriteAll(rows)
+	if err != nil {
+		impl.logger.Errorw("error in writing permission audit csv", "err", err)
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (impl *UserPermissionAuditServiceImpl) getAudits(filter *userBean.PermissionAuditFilter, offset, size int) ([]*userBean.PermissionAuditDto, int, error) {
+	from, to, err := userHelper.GetPermissionAuditTimeRange(filter.From, filter.To)
+	if err != nil {
+		return nil, 0, err
+	}
+	models, totalCount, err := impl.userPermissionAuditRepository.FindAll(&repository.UserPermissionAuditQuery{
+		EntityType: filter.EntityType,
+		EntityId:   filter.EntityId,
+		ActorId:    filter.ActorId,
+		From:       from,
+		To:         to,
+		Offset:     offset,
+		Size:       size,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	actorEmails := impl.getActorEmails(models)
+	audits := make([]*userBean.PermissionAuditDto, 0, len(models))
+	for _, model := range models {
+		audit := &userBean.PermissionAuditDto{
+			Id:           model.Id,
+			EntityType:   model.EntityType,
+			EntityId:     model.EntityId,
+			EntityName:   model.EntityName,
+			Action:       model.Action,
+			ActorId:      model.CreatedBy,
+			ActorEmailId: actorEmails[model.CreatedBy],
+			CreatedOn:    model.CreatedOn,
+		}
+		audit.PreviousState, err = unmarshalPermissionAuditState(model.PreviousState)
+		if err != nil {
+			impl.logger.Errorw("error in reading previous state of permission audit", "id", model.Id, "err", err)
+			return nil, 0, err
+		}
+		audit.CurrentState, err = unmarshalPermissionAuditState(model.CurrentState)
+		if err != nil {
+			impl.logger.Errorw("error in reading current state of permission audit", "id", model.Id, "err", err)
+			return nil, 0, err
+		}
+		audit.Diff = userHelper.GetPermissionAuditDiff(audit.PreviousState, audit.CurrentState)
+		audits = append(audits, audit)
+	}
+	return audits, totalCount, nil
+}
+
+// getActorEmails includes deleted users, audits outlive the users who made the change
+func (impl *UserPermissionAuditServiceImpl) getActorEmails(models []*repository.UserPermissionAudit) map[int32]string {
+	actorEmails := make(map[int32]string)
+	for _, model := range models {
+		if _, ok := actorEmails[model.CreatedBy]; ok {
+			continue
+		}
+		actor, err := impl.userRepository.GetByIdIncludeDeleted(model.CreatedBy)
+		if err != nil {
+			impl.logger.Warnw("error in getting actor of permission audit", "actorId", model.CreatedBy, "err", err)
+			actorEmails[model.CreatedBy] = ""
+			continue
+		}
+		actorEmails[model.CreatedBy] = actor.EmailId
+	}
+	return actorEmails
+}
+
+func marshalPermissionAuditState(state *userBean.PermissionAuditState) (string, error) {
+	if state == nil {
+		return "", nil
+	}
+	stateBytes, err := json.Marshal(state)
+	if err != nil {
+		return "", err
+	}
+	return string(stateBytes), nil
+}
+
+func unmarshalPermissionAuditState(state string) (*userBean.PermissionAuditState, error) {
+	if len(state) == 0 {
+		return nil, nil
+	}
+	auditState := &userBean.PermissionAuditState{}
+	err := json.Unmarshal([]byte(state), auditState)
+	if err != nil {
+		return nil, err
+	}
+	return auditState, nil
+}
+
+func toJsonString(value interface{}) string {
+	valueBytes, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(valueBytes)
+}
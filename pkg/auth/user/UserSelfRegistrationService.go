@@ -21,6 +21,7 @@ import (
 	jwt2 "github.com/devtron-labs/authenticator/jwt"
 	"github.com/devtron-labs/devtron/pkg/auth/user/adapter"
 	"github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/user/helper"
 	"github.com/devtron-labs/devtron/pkg/auth/user/repository"
 	"github.com/golang-jwt/jwt/v4"
 	"go.uber.org/zap"
@@ -95,6 +96,10 @@ func (impl *UserSelfRegistrationServiceImpl) CheckSelfRegistrationRoles() (Check
 }
 
 func (impl *UserSelfRegistrationServiceImpl) SelfRegister(emailId string) (*bean.UserInfo, error) {
+	return impl.selfRegister(emailId, nil, "")
+}
+
+func (impl *UserSelfRegistrationServiceImpl) selfRegister(emailId string, groupsFromClaims []string, connectorId string) (*bean.UserInfo, error) {
 	roles, err := impl.CheckSelfRegistrationRoles()
 	if err != nil || roles.Enabled == false {
 		return nil, err
@@ -106,7 +111,7 @@ func (impl *UserSelfRegistrationServiceImpl) SelfRegister(emailId string) (*bean
 		SuperAdmin: false,
 	}
 
-	userInfos, err := impl.userService.SelfRegisterUserIfNotExists(adapter.BuildSelfRegisterDto(userInfo))
+	userInfos, err := impl.userService.SelfRegisterUserIfNotExists(adapter.BuildSelfRegisterDto(userInfo, groupsFromClaims, connectorId))
 	if err != nil {
 		impl.logger.Errorw("error while register user", "error", err)
 		return nil, err
@@ -125,16 +130,26 @@ func (impl *UserSelfRegistrationServiceImpl) CheckAndCreateUserIfConfigured(clai
 	if emailId == "" && sub == "admin" {
 		emailId = sub
 	}
+	groupsFromClaims, connectorId := helper.GetGroupClaimsFromTokenClaims(claims)
 	exists := impl.userService.UserExists(emailId)
 	var id int32
+	selfRegistered := false
 	if !exists {
 		impl.logger.Infow("self registering user,  ", "email", emailId)
-		user, err := impl.SelfRegister(emailId)
+		user, err := impl.selfRegister(emailId, groupsFromClaims, connectorId)
 		if err != nil {
 			impl.logger.Errorw("error while register user", "error", err)
 		} else if user != nil && user.Id > 0 {
 			id = user.Id
 			exists = true
+			selfRegistered = true
+		}
+	}
+	if !selfRegistered {
+		// claims are recorded for users not yet added as well, they get their role groups once created
+		err := impl.userService.UpdateDataForGroupClaims(adapter.BuildSelfRegisterDto(&bean.UserInfo{EmailId: emailId}, groupsFromClaims, connectorId))
+		if err != nil {
+			impl.logger.Errorw("error in syncing role groups from group claims", "email", emailId, "err", err)
 		}
 	}
 	if exists {
@@ -19,6 +19,7 @@ package user
 import (
 	"context"
 	"fmt"
+	"github.com/caarlos0/env"
 	bean4 "github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin/bean"
 	"github.com/devtron-labs/devtron/pkg/auth/user/adapter"
 	userHelper "github.com/devtron-labs/devtron/pkg/auth/user/helper"
@@ -26,6 +27,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/auth/user/repository/helper"
 	util3 "github.com/devtron-labs/devtron/pkg/auth/user/util"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -54,6 +56,8 @@ const (
 type UserService interface {
 	CreateUser(userInfo *userBean.UserInfo, token string, managerAuth func(resource, token string, object string) bool) ([]*userBean.UserInfo, error)
 	SelfRegisterUserIfNotExists(selfRegisterDto *userBean.SelfRegisterDto) ([]*userBean.UserInfo, error)
+	// UpdateDataForGroupClaims records the group claims of a login and reconciles the role groups mapped to them
+	UpdateDataForGroupClaims(selfRegisterDto *userBean.SelfRegisterDto) error
 	UpdateUser(userInfo *userBean.UserInfo, token string, checkRBACForUserUpdate func(token string, userInfo *userBean.UserInfo, isUserAlreadySuperAdmin bool,
 		eliminatedRoleFilters, eliminatedGroupRoles []*repository.RoleModel, mapOfExistingUserRoleGroup map[string]bool) (isAuthorised bool, err error), managerAuth func(resource, token string, object string) bool) (*userBean.UserInfo, error)
 	GetByIdWithoutGroupClaims(id int32) (*userBean.UserInfo, error)
@@ -101,6 +105,12 @@ type UserServiceImpl struct {
 	userCommonService   UserCommonService
 	userAuditService    UserAuditService
 	roleGroupService    RoleGroupService
+	// timeBoundAccessService keeps casbin in line with the time windows of grants
+	timeBoundAccessService TimeBoundAccessService
+	groupClaimsSyncService GroupClaimsSyncService
+	// userPermissionAuditService records the permissions of users before and after every change
+	userPermissionAuditService UserPermissionAuditService
+	apiTokenConfig             *userBean.ApiTokenConfig
 }
 
 func NewUserServiceImpl(userAuthRepository repository.UserAuthRepository,
@@ -108,7 +118,13 @@ func NewUserServiceImpl(userAuthRepository repository.UserAuthRepository,
 	userRepository repository.UserRepository,
 	userGroupRepository repository.RoleGroupRepository,
 	sessionManager2 *middleware.SessionManager, userCommonService UserCommonService, userAuditService UserAuditService,
-	roleGroupService RoleGroupService) *UserServiceImpl {
+	roleGroupService RoleGroupService, timeBoundAccessService TimeBoundAccessService,
+	groupClaimsSyncService GroupClaimsSyncService,
+	userPermissionAuditService UserPermissionAuditService) *UserServiceImpl {
+	apiTokenConfig := &userBean.ApiTokenConfig{}
+	if err := env.Parse(apiTokenConfig); err != nil {
+		logger.Errorw("error in parsing api token config, trusting only the remote address", "err", err)
+	}
 	serviceImpl := &UserServiceImpl{
 		userReqState:        make(map[int32]bool),
 		userAuthRepository:  userAuthRepository,
@@ -119,6 +135,12 @@ func NewUserServiceImpl(userAuthRepository repository.UserAuthRepository,
 		userCommonService:   userCommonService,
 		userAuditService:    userAuditService,
 		roleGroupService:    roleGroupService,
+
+		timeBoundAccessService: timeBoundAccessService,
+		groupClaimsSyncService: groupClaimsSyncService,
+
+		userPermissionAuditService: userPermissionAuditService,
+		apiTokenConfig:             apiTokenConfig,
 	}
 	cStore = sessions.NewCookieStore(randKey())
 	return serviceImpl
@@ -176,6 +198,11 @@ func (impl *UserServiceImpl) validateUserRequest(userInfo *userBean.UserInfo) er
 		impl.logger.Errorw("error in validateUserRequest", "err", err)
 		return err
 	}
+	err = userHelper.ValidateTimeoutWindows(userInfo.RoleFilters, userInfo.UserRoleGroup, time.Now())
+	if err != nil {
+		impl.logger.Errorw("error in validateUserRequest", "err", err)
+		return err
+	}
 	return nil
 }
 
@@ -454,6 +481,12 @@ func (impl *UserServiceImpl) createUserIfNotExists(userInfo *userBean.UserInfo,
 		}
 		//loading policy for syncing orchestrator to casbin with newly added policies
 		casbin2.LoadPolicy()
+		impl.timeBoundAccessService.SyncUserGrants(model.Id, model.EmailId)
+	}
+	// users logging in before being added get the role groups mapped to their group claims now
+	err = impl.groupClaimsSyncService.ReconcileUser(model.EmailId)
+	if err != nil {
+		impl.logger.Errorw("error in reconciling role groups from group claims, will be retried by periodic sync", "emailId", model.EmailId, "err", err)
 	}
 	return userInfo, nil
 }
@@ -477,7 +510,7 @@ func (impl *UserServiceImpl) CreateAndAddPoliciesForNonSuperAdmin(tx *pg.Tx, use
 	}
 
 	// UserRoleGroup Addition flow starts
-	policiesToBeAdded, err := impl.AddUserGroupPoliciesForCasbin(tx, userRoleGroup, emailId, userLoggedInId)
+	policiesToBeAdded, err := impl.AddUserGroupPoliciesForCasbin(tx, userRoleGroup, emailId, model.Id, userLoggedInId)
 	if err != nil {
 		impl.logger.Errorw("error encountered in CreateAndAddPoliciesForNonSuperAdmin", "err", err, "emailId", emailId)
 		return nil, err
@@ -487,22 +520,29 @@ func (impl *UserServiceImpl) CreateAndAddPoliciesForNonSuperAdmin(tx *pg.Tx, use
 	return policies, nil
 }
 
-// AddUserGroupPoliciesForCasbin : returns user and group mapping for casbin
-func (impl *UserServiceImpl) AddUserGroupPoliciesForCasbin(tx *pg.Tx, userRoleGroup []userBean.UserRoleGroup, emailId string, userLoggedInId int32) ([]bean4.Policy, error) {
+// AddUserGroupPoliciesForCasbin : returns user and group mapping for casbin, time bound memberships are returned only while their window is open
+func (impl *UserServiceImpl) AddUserGroupPoliciesForCasbin(tx *pg.Tx, userRoleGroup []userBean.UserRoleGroup, emailId string, userModelId int32, userLoggedInId int32) ([]bean4.Policy, error) {
 	var policies = make([]bean4.Policy, 0)
 	groupIdRoleGroupMap, err := impl.getGroupIdRoleGroupMap(userRoleGroup)
 	if err != nil {
 		impl.logger.Errorw("error in AddUserGroupPoliciesForCasbin", "userGroups", userRoleGroup, "err", err)
 		return nil, err
 	}
+	roleGroupIdVsWindow := make(map[int32]*userBean.TimeoutWindowConfigDto, len(userRoleGroup))
 	for _, item := range userRoleGroup {
 		userGroup := groupIdRoleGroupMap[item.RoleGroup.Id]
-		casbinPolicy, err := impl.getCasbinPolicyForGroup(tx, emailId, userGroup.CasbinName, item, userLoggedInId)
+		casbinPolicy, timeoutWindowConfigDto, err := impl.getCasbinPolicyForGroup(tx, emailId, userGroup.CasbinName, item, userLoggedInId)
 		if err != nil {
 			impl.logger.Errorw("error in AddUserGroupPoliciesForCasbin", "item", item, "err", err)
 			return nil, err
 		}
-		policies = append(policies, casbinPolicy)
+		roleGroupIdVsWindow[userGroup.Id] = timeoutWindowConfigDto
+		policies = append(policies, getPoliciesForActiveWindow(casbinPolicy, timeoutWindowConfigDto)...)
+	}
+	err = impl.timeBoundAccessService.SaveRoleGroupTimeoutWindows(tx, userModelId, roleGroupIdVsWindow, userLoggedInId)
+	if err != nil {
+		impl.logger.Errorw("error in AddUserGroupPoliciesForCasbin", "userModelId", userModelId, "err", err)
+		return nil, err
 	}
 	return policies, nil
 }
@@ -687,9 +727,15 @@ func (impl *UserServiceImpl) createOrUpdateUserRolesForClusterEntity(tx *pg.Tx,
 							}
 						}
 						if _, ok := existingRoles[roleModel.Id]; ok {
+							windowChanged, err := impl.updateTimeoutWindowForExistingRole(tx, existingRoles, roleModel.Id, timeoutWindowConfigDto, userId)
+							if err != nil {
+								impl.logger.Errorw("error in createOrUpdateUserRolesForClusterEntity", "userId", model.Id, "roleModelId", roleModel.Id, "err", err)
+								return nil, rolesChanged, err
+							}
+							rolesChanged = rolesChanged || windowChanged
 							//Adding policies which are removed
 							casbinPolicy := adapter.GetCasbinGroupPolicy(model.EmailId, roleModel.Role, timeoutWindowConfigDto)
-							policiesToBeAdded = append(policiesToBeAdded, casbinPolicy)
+							policiesToBeAdded = append(policiesToBeAdded, getPoliciesForActiveWindow(casbinPolicy, timeoutWindowConfigDto)...)
 						} else {
 							if roleModel.Id > 0 {
 								rolesChanged = true
@@ -700,7 +746,7 @@ func (impl *UserServiceImpl) createOrUpdateUserRolesForClusterEntity(tx *pg.Tx,
 									return nil, rolesChanged, err
 								}
 								casbinPolicy := adapter.GetCasbinGroupPolicy(model.EmailId, roleModel.Role, timeoutWindowConfigDto)
-								policiesToBeAdded = append(policiesToBeAdded, casbinPolicy)
+								policiesToBeAdded = append(policiesToBeAdded, getPoliciesForActiveWindow(casbinPolicy, timeoutWindowConfigDto)...)
 							}
 						}
 					}
@@ -801,6 +847,7 @@ func (impl *UserServiceImpl) UpdateUser(userInfo *userBean.UserInfo, token strin
 		impl.logger.Errorw("error while fetching user from db", "error", err)
 		return nil, err
 	}
+	previousAuditState := impl.getPermissionAuditState(model)
 
 	userGroupsUpdated, err := impl.updateUserGroupForUser(tx, userInfo, model)
 	if err != nil {
@@ -893,7 +940,7 @@ func (impl *UserServiceImpl) UpdateUser(userInfo *userBean.UserInfo, token strin
 		return nil, err
 	}
 
-	err = impl.saveAuditBasedOnActiveOrInactiveUser(tx, isUserActive, model, userInfo)
+	err = impl.saveAuditBasedOnActiveOrInactiveUser(tx, isUserActive, model, userInfo, previousAuditState)
 	if err != nil {
 		impl.logger.Errorw("error in creating audit for user", "err", err, "id", model.Id)
 		return nil, err
@@ -905,6 +952,7 @@ func (impl *UserServiceImpl) UpdateUser(userInfo *userBean.UserInfo, token strin
 	}
 	//loading policy for syncing orchestrator to casbin with newly added policies
 	casbin2.LoadPolicy()
+	impl.timeBoundAccessService.SyncUserGrants(model.Id, model.EmailId)
 	return userInfo, nil
 }
 
@@ -943,11 +991,7 @@ func (impl *UserServiceImpl) getUserMetadata(model *repository.UserModel) (bool,
 	}
 
 	isSuperAdmin := userHelper.CheckIfSuperAdminFromRoles(roles)
-	var roleFilters []userBean.RoleFilter
-	// merging considering base as env  first
-	roleFilters = impl.userCommonService.BuildRoleFiltersAfterMerging(ConvertRolesToEntityProcessors(roles), userBean.EnvironmentBasedKey)
-	// merging role filters based on application now, first took env as base merged, now application as base , merged
-	roleFilters = impl.userCommonService.BuildRoleFiltersAfterMerging(ConvertRoleFiltersToEntityProcessors(roleFilters), userBean.ApplicationBasedKey)
+	roleFilters := impl.getRoleFiltersWithTimeoutWindows(model.Id, roles)
 
 	groups, err := casbin2.GetRolesForUser(model.EmailId)
 	if err != nil {
@@ -961,15 +1005,61 @@ func (impl *UserServiceImpl) getUserMetadata(model *repository.UserModel) (bool,
 			filterGroups = append(filterGroups, item)
 		}
 	}
+	// time bound memberships are present in casbin only while their window is open, pending ones are fetched by id
+	roleGroupIdVsWindow, err := impl.timeBoundAccessService.GetActiveRoleGroupTimeoutWindows(model.Id)
+	if err != nil {
+		impl.logger.Warnw("error in getting role group timeout windows for user", "id", model.Id, "err", err)
+	}
+	idpRoleGroups, err := impl.groupClaimsSyncService.GetIdpRoleGroups(model.Id)
+	if err != nil {
+		impl.logger.Warnw("error in getting role groups synced from group claims for user", "id", model.Id, "err", err)
+	}
+	idpRoleGroupIds := make(map[int32]bool, len(idpRoleGroups))
+	for _, idpRoleGroup := range idpRoleGroups {
+		idpRoleGroupIds[idpRoleGroup.Id] = true
+	}
 
-	if len(filterGroups) > 0 {
-		filterGroupsModels, err := impl.roleGroupRepository.GetRoleGroupListByCasbinNames(filterGroups)
-		if err != nil {
-			impl.logger.Warnw("No Roles Found for user", "id", model.Id)
+	if len(filterGroups) > 0 || len(roleGroupIdVsWindow) > 0 {
+		var filterGroupsModels []*repository.RoleGroup
+		if len(filterGroups) > 0 {
+			filterGroupsModels, err = impl.roleGroupRepository.GetRoleGroupListByCasbinNames(filterGroups)
+			if err != nil {
+				impl.logger.Warnw("No Roles Found for user", "id", model.Id)
+			}
+		}
+		casbinRoleGroupIds := make(map[int32]bool, len(filterGroupsModels))
+		for _, item := range filterGroupsModels {
+			casbinRoleGroupIds[item.Id] = true
+		}
+		pendingRoleGroupIds := make([]int32, 0, len(roleGroupIdVsWindow))
+		for roleGroupId := range roleGroupIdVsWindow {
+			if !casbinRoleGroupIds[roleGroupId] {
+				pendingRoleGroupIds = append(pendingRoleGroupIds, roleGroupId)
+			}
+		}
+		if len(pendingRoleGroupIds) > 0 {
+			pendingGroupsModels, err := impl.roleGroupRepository.GetRoleGroupListByIds(pendingRoleGroupIds)
+			if err != nil {
+				impl.logger.Warnw("error in getting pending role groups for user", "id", model.Id, "err", err)
+			}
+			filterGroupsModels = append(filterGroupsModels, pendingGroupsModels...)
 		}
 		filterGroups = nil
+		now := time.Now()
 		for _, item := range filterGroupsModels {
-			userRoleGroups = append(userRoleGroups, userBean.UserRoleGroup{RoleGroup: &userBean.RoleGroup{Name: item.Name, Id: item.Id, Description: item.Description}})
+			userRoleGroup := userBean.UserRoleGroup{RoleGroup: &userBean.RoleGroup{Name: item.Name, Id: item.Id, Description: item.Description}}
+			userRoleGroup.Source = userBean.RoleGroupMembershipSourceManual
+			if idpRoleGroupIds[item.Id] {
+				userRoleGroup.Source = userBean.RoleGroupMembershipSourceIdp
+			}
+			if window, ok := roleGroupIdVsWindow[item.Id]; ok {
+				if window.IsExpiredAt(now) {
+					continue
+				}
+				userRoleGroup.ActiveFrom = window.ActiveFrom
+				userRoleGroup.ExpiresAt = window.ExpiresAt
+			}
+			userRoleGroups = append(userRoleGroups, userRoleGroup)
 			filterGroups = append(filterGroups, item.Name)
 		}
 	} else {
@@ -988,6 +1078,58 @@ func (impl *UserServiceImpl) getUserMetadata(model *repository.UserModel) (bool,
 	return isSuperAdmin, roleFilters, filterGroups, userRoleGroups
 }
 
+// getRoleFiltersWithTimeoutWindows merges roles sharing the same time window into role filters, lapsed grants are left out
+func (impl *UserServiceImpl) getRoleFiltersWithTimeoutWindows(userId int32, roles []*repository.RoleModel) []userBean.RoleFilter {
+	roleIdVsTwcId := make(map[int]int)
+	userRoleMappings, err := impl.userAuthRepository.GetUserRoleMappingByUserId(userId)
+	if err != nil {
+		impl.logger.Warnw("error in getting user role mappings, considering all roles as permanent", "userId", userId, "err", err)
+	}
+	twcIds := make([]int, 0)
+	for _, userRoleMapping := range userRoleMappings {
+		if userRoleMapping.TimeoutWindowConfigurationId > 0 {
+			roleIdVsTwcId[userRoleMapping.RoleId] = userRoleMapping.TimeoutWindowConfigurationId
+			twcIds = append(twcIds, userRoleMapping.TimeoutWindowConfigurationId)
+		}
+	}
+	twcIdVsWindow, err := impl.timeBoundAccessService.GetTimeoutWindowConfigsByIds(twcIds)
+	if err != nil {
+		impl.logger.Warnw("error in getting timeout window configs, considering all roles as permanent", "userId", userId, "err", err)
+		twcIdVsWindow = make(map[int]*userBean.TimeoutWindowConfigDto)
+	}
+	now := time.Now()
+	twcIdVsRoles := make(map[int][]*repository.RoleModel)
+	for _, role := range roles {
+		twcId := roleIdVsTwcId[role.Id]
+		if _, ok := twcIdVsWindow[twcId]; !ok {
+			twcId = 0
+		} else if twcIdVsWindow[twcId].IsExpiredAt(now) {
+			continue
+		}
+		twcIdVsRoles[twcId] = append(twcIdVsRoles[twcId], role)
+	}
+	twcIdsInOrder := make([]int, 0, len(twcIdVsRoles))
+	for twcId := range twcIdVsRoles {
+		twcIdsInOrder = append(twcIdsInOrder, twcId)
+	}
+	sort.Ints(twcIdsInOrder)
+	var roleFilters []userBean.RoleFilter
+	for _, twcId := range twcIdsInOrder {
+		// merging considering base as env  first
+		windowRoleFilters := impl.userCommonService.BuildRoleFiltersAfterMerging(ConvertRolesToEntityProcessors(twcIdVsRoles[twcId]), userBean.EnvironmentBasedKey)
+		// merging role filters based on application now, first took env as base merged, now application as base , merged
+		windowRoleFilters = impl.userCommonService.BuildRoleFiltersAfterMerging(ConvertRoleFiltersToEntityProcessors(windowRoleFilters), userBean.ApplicationBasedKey)
+		if window, ok := twcIdVsWindow[twcId]; ok {
+			for i := range windowRoleFilters {
+				windowRoleFilters[i].ActiveFrom = window.ActiveFrom
+				windowRoleFilters[i].ExpiresAt = window.ExpiresAt
+			}
+		}
+		roleFilters = append(roleFilters, windowRoleFilters...)
+	}
+	return roleFilters
+}
+
 // GetAll excluding API token user
 func (impl *UserServiceImpl) GetAll() ([]userBean.UserInfo, error) {
 	model, err := impl.userRepository.GetAllExcludingApiTokenUser()
@@ -1236,11 +1378,54 @@ func (impl *UserServiceImpl) GetLoggedInUser(r *http.Request) (int32, error) {
 	userId, userType, err := impl.GetUserByToken(r.Context(), token)
 	// if user is of api-token type, then update lastUsedBy and lastUsedAt
 	if err == nil && userType == userBean.USER_TYPE_API_TOKEN {
+		err = impl.checkApiTokenRequest(r, token)
+		if err != nil {
+			return 0, err
+		}
 		go impl.saveUserAudit(r, userId)
 	}
 	return userId, err
 }
 
+// checkApiTokenRequest enforces the source ip allowlist and the webhook scope embedded in the api token, and records
+// the use of the token version so that the previous version of a rotated token can be retired safely
+func (impl *UserServiceImpl) checkApiTokenRequest(r *http.Request, token string) error {
+	claims, err := impl.sessionManager2.VerifyToken(token)
+	if err != nil {
+		return err
+	}
+	mapClaims, err := jwt.MapClaims(claims)
+	if err != nil {
+		return err
+	}
+	clientIp := userHelper.GetApiTokenClientIp(r.RemoteAddr, strings.Join(r.Header.Values("X-Forwarded-For"), ","), impl.apiTokenConfig.TrustedProxyHops)
+	if !userHelper.IsClientIpAllowed(clientIp, userHelper.GetAllowedIpsFromTokenClaims(mapClaims)) {
+		impl.logger.Warnw("api token used from an ip outside its allowlist", "email", jwt.GetField(mapClaims, "email"), "clientIp", clientIp)
+		return util.NewApiError(http.StatusForbidden, "api token is not allowed from this ip address", "client ip not in api token allowlist")
+	}
+	scope, err := casbin2.GetApiTokenScopeFromClaims(mapClaims)
+	if err != nil {
+		return err
+	}
+	if scope != nil && scope.Type == bean4.ApiTokenScopeTypeWebhook && !strings.Contains(r.URL.Path, "/orchestrator/webhook/") {
+		return util.NewApiError(http.StatusForbidden, "api token is scoped to webhooks only", "webhook scoped api token used outside webhook apis")
+	}
+	go impl.saveApiTokenLastUsed(jwt.GetField(mapClaims, "email"), jwt.GetField(mapClaims, "version"), clientIp)
+	return nil
+}
+
+func (impl *UserServiceImpl) saveApiTokenLastUsed(email, version, clientIp string) {
+	tokenName, err := userHelper.ExtractTokenNameFromEmail(email)
+	if err != nil {
+		return
+	}
+	tokenVersion, _ := strconv.Atoi(version)
+	err = impl.userRepository.UpdateApiTokenLastUsed(tokenName, tokenVersion, clientIp)
+	if err != nil {
+		impl.logger.Errorw("error in saving last use of api token", "tokenName", tokenName, "err", err)
+	}
+}
+
 func (impl *UserServiceImpl) GetUserByToken(context context.Context, token string) (int32, string, error) {
 	_, span := otel.Tracer("userService").Start(context, "GetUserByToken")
 	email, version, err := impl.GetEmailAndVersionFromToken(token)
@@ -1413,6 +1598,11 @@ func (impl *UserServiceImpl) DeleteUser(userInfo *userBean.UserInfo) (bool, erro
 		impl.logger.Errorw("error while fetching user from db", "error", err)
 		return false, err
 	}
+	err = impl.createAuditForDeleteOperation(tx, []repository.UserModel{*model}, userInfo.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in creating audit for user", "err", err, "id", model.Id)
+		return false, err
+	}
 	userRolesMappingIds, err := impl.userAuthRepository.GetUserRoleMappingIdsByUserId(userInfo.Id)
 	if err != nil {
 		impl.logger.Errorw("error while fetching user from db", "error", err)
@@ -1425,6 +1615,16 @@ func (impl *UserServiceImpl) DeleteUser(userInfo *userBean.UserInfo) (bool, erro
 			return false, err
 		}
 	}
+	err = impl.timeBoundAccessService.DeactivateRoleGroupTimeoutWindows(tx, []int32{model.Id}, userInfo.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in DeleteUser", "userId", model.Id, "err", err)
+		return false, err
+	}
+	err = impl.groupClaimsSyncService.DeactivateMemberships(tx, []int32{model.Id}, userInfo.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in DeleteUser", "userId", model.Id, "err", err)
+		return false, err
+	}
 	model.Active = false
 	model.UpdatedBy = userInfo.UserId
 	model.UpdatedOn = time.Now()
@@ -1502,9 +1702,20 @@ func (impl *UserServiceImpl) deleteUsersByIds(request *userBean.BulkDeleteReques
 		impl.logger.Errorw("error in DeleteUsersForIds", "userIds", request.Ids, "err", err)
 		return err
 	}
+	models, err := impl.userRepository.GetByIds(request.Ids)
+	if err != nil {
+		impl.logger.Errorw("error in DeleteUsersForIds", "userIds", request.Ids, "err", err)
+		return err
+	}
+	// audits are taken before the mappings are removed so that the deleted permissions are recorded
+	err = impl.createAuditForDeleteOperation(tx, models, request.LoggedInUserId)
+	if err != nil {
+		impl.logger.Errorw("error in creating audit for users", "userIds", request.Ids, "err", err)
+		return err
+	}
 
 	// operations in orchestrator and getting emails ids for corresponding user ids
-	err = impl.deleteMappingsFromOrchestrator(request.Ids, tx)
+	err = impl.deleteMappingsFromOrchestrator(request.Ids, request.LoggedInUserId, tx)
 	if err != nil {
 		impl.logger.Errorw("error encountered in deleteUsersByIds", "request", request, "err", err)
 		return err
@@ -1552,7 +1763,7 @@ func (impl *UserServiceImpl) deleteMappingsFromCasbin(emailIds []string, totalCo
 }
 
 // deleteMappingsFromOrchestrator takes in userIds to be deleted and transaction returns error in case of any issue else nil
-func (impl *UserServiceImpl) deleteMappingsFromOrchestrator(userIds []int32, tx *pg.Tx) error {
+func (impl *UserServiceImpl) deleteMappingsFromOrchestrator(userIds []int32, loggedInUserId int32, tx *pg.Tx) error {
 	urmIds, err := impl.userAuthRepository.GetUserRoleMappingIdsByUserIds(userIds)
 	if err != nil {
 		impl.logger.Errorw("error in DeleteUsersForIds", "err", err)
@@ -1566,6 +1777,16 @@ func (impl *UserServiceImpl) deleteMappingsFromOrchestrator(userIds []int32, tx
 			return err
 		}
 	}
+	err = impl.timeBoundAccessService.DeactivateRoleGroupTimeoutWindows(tx, userIds, loggedInUserId)
+	if err != nil {
+		impl.logger.Errorw("error encountered in DeleteUsersForIds", "userIds", userIds, "err", err)
+		return err
+	}
+	err = impl.groupClaimsSyncService.DeactivateMemberships(tx, userIds, loggedInUserId)
+	if err != nil {
+		impl.logger.Errorw("error encountered in DeleteUsersForIds", "userIds", userIds, "err", err)
+		return err
+	}
 	return nil
 }
 
@@ -1728,9 +1949,15 @@ func (impl *UserServiceImpl) createOrUpdateUserRolesForOtherEntity(tx *pg.Tx, ro
 						}
 					}
 					if _, ok := existingRoles[roleModel.Id]; ok {
+						windowChanged, err := impl.updateTimeoutWindowForExistingRole(tx, existingRoles, roleModel.Id, timeoutWindowConfigDto, userId)
+						if err != nil {
+							impl.logger.Errorw("error in createOrUpdateUserRolesForOtherEntity", "userId", model.Id, "roleModelId", roleModel.Id, "err", err)
+							return nil, rolesChanged, err
+						}
+						rolesChanged = rolesChanged || windowChanged
 						//Adding policies which is removed
 						casbinPolicy := adapter.GetCasbinGroupPolicy(model.EmailId, roleModel.Role, timeoutWindowConfigDto)
-						policiesToBeAdded = append(policiesToBeAdded, casbinPolicy)
+						policiesToBeAdded = append(policiesToBeAdded, getPoliciesForActiveWindow(casbinPolicy, timeoutWindowConfigDto)...)
 					} else if roleModel.Id > 0 {
 						rolesChanged = true
 						userRoleModel := adapter2.GetUserRoleModelAdapter(model.Id, userId, roleModel.Id, timeoutWindowConfigDto)
@@ -1740,7 +1967,7 @@ func (impl *UserServiceImpl) createOrUpdateUserRolesForOtherEntity(tx *pg.Tx, ro
 							return nil, rolesChanged, err
 						}
 						casbinPolicy := adapter.GetCasbinGroupPolicy(model.EmailId, roleModel.Role, timeoutWindowConfigDto)
-						policiesToBeAdded = append(policiesToBeAdded, casbinPolicy)
+						policiesToBeAdded = append(policiesToBeAdded, getPoliciesForActiveWindow(casbinPolicy, timeoutWindowConfigDto)...)
 					}
 				}
 			}
@@ -1792,9 +2019,15 @@ func (impl *UserServiceImpl) createOrUpdateUserRolesForJobsEntity(tx *pg.Tx, rol
 						}
 					}
 					if _, ok := existingRoles[roleModel.Id]; ok {
+						windowChanged, err := impl.updateTimeoutWindowForExistingRole(tx, existingRoles, roleModel.Id, timeoutWindowConfigDto, userId)
+						if err != nil {
+							impl.logger.Errorw("error in createOrUpdateUserRolesForJobsEntity", "userId", model.Id, "roleModelId", roleModel.Id, "err", err)
+							return nil, rolesChanged, err
+						}
+						rolesChanged = rolesChanged || windowChanged
 						//Adding policies which is removed
 						casbinPolicy := adapter.GetCasbinGroupPolicy(model.EmailId, roleModel.Role, timeoutWindowConfigDto)
-						policiesToBeAdded = append(policiesToBeAdded, casbinPolicy)
+						policiesToBeAdded = append(policiesToBeAdded, getPoliciesForActiveWindow(casbinPolicy, timeoutWindowConfigDto)...)
 					} else if roleModel.Id > 0 {
 						rolesChanged = true
 						userRoleModel := adapter2.GetUserRoleModelAdapter(model.Id, userId, roleModel.Id, timeoutWindowConfigDto)
@@ -1804,7 +2037,7 @@ func (impl *UserServiceImpl) createOrUpdateUserRolesForJobsEntity(tx *pg.Tx, rol
 							return nil, rolesChanged, err
 						}
 						casbinPolicy := adapter.GetCasbinGroupPolicy(model.EmailId, roleModel.Role, timeoutWindowConfigDto)
-						policiesToBeAdded = append(policiesToBeAdded, casbinPolicy)
+						policiesToBeAdded = append(policiesToBeAdded, getPoliciesForActiveWindow(casbinPolicy, timeoutWindowConfigDto)...)
 					}
 				}
 			}
@@ -9,10 +9,14 @@ import (
 	userrepo "github.com/devtron-labs/devtron/pkg/auth/user/repository"
 	"github.com/go-pg/pg"
 	"strings"
+	"time"
 )
 
 func (impl *UserServiceImpl) UpdateDataForGroupClaims(dto *userBean.SelfRegisterDto) error {
-	return nil
+	if dto.UserInfo == nil || len(dto.UserInfo.EmailId) == 0 {
+		return nil
+	}
+	return impl.groupClaimsSyncService.RecordGroupClaims(dto.UserInfo.EmailId, dto.ConnectorId, dto.GroupsFromClaims)
 }
 
 func (impl *UserServiceImpl) mergeAccessRoleFiltersAndUserGroups(currentUserInfo, requestUserInfo *userBean.UserInfo) {
@@ -27,7 +31,16 @@ func (impl *UserServiceImpl) assignUserGroups(tx *pg.Tx, userInfo *userBean.User
 	return nil
 }
 
+// checkAndPerformOperationsForGroupClaims drops the role groups synced from group claims from the request, these are
+// owned by GroupClaimsSyncService and must not turn into manual memberships. Roles are always managed manually.
 func (impl *UserServiceImpl) checkAndPerformOperationsForGroupClaims(tx *pg.Tx, userInfo *userBean.UserInfo) (bool, error) {
+	userRoleGroups := make([]userBean.UserRoleGroup, 0, len(userInfo.UserRoleGroup))
+	for _, userRoleGroup := range userInfo.UserRoleGroup {
+		if userRoleGroup.Source != userBean.RoleGroupMembershipSourceIdp {
+			userRoleGroups = append(userRoleGroups, userRoleGroup)
+		}
+	}
+	userInfo.UserRoleGroup = userRoleGroups
 	return false, nil
 }
 
@@ -40,16 +53,94 @@ func validateAccessRoleFilters(info *userBean.UserInfo) error {
 }
 
 func (impl *UserServiceImpl) createAuditForSelfRegisterOperation(tx *pg.Tx, userResponseInfo *userBean.UserInfo) error {
-	return nil
+	return impl.userPermissionAuditService.SaveAudit(tx, &userBean.PermissionAuditDto{
+		EntityType: userBean.PermissionAuditEntityUser,
+		EntityId:   userResponseInfo.Id,
+		EntityName: userResponseInfo.EmailId,
+		Action:     userBean.PermissionAuditActionSelfRegister,
+		// the user registers on their first login, so they are the actor
+		ActorId:      userResponseInfo.Id,
+		CurrentState: adapter.BuildPermissionAuditStateForUser(userResponseInfo),
+	})
 }
 
 func (impl *UserServiceImpl) createAuditForCreateOperation(tx *pg.Tx, userResponseInfo *userBean.UserInfo, model *userrepo.UserModel) error {
+	return impl.userPermissionAuditService.SaveAudit(tx, &userBean.PermissionAuditDto{
+		EntityType:   userBean.PermissionAuditEntityUser,
+		EntityId:     model.Id,
+		EntityName:   model.EmailId,
+		Action:       userBean.PermissionAuditActionCreate,
+		ActorId:      userResponseInfo.UserId,
+		CurrentState: adapter.BuildPermissionAuditStateForUser(userResponseInfo),
+	})
+}
+
+func (impl *UserServiceImpl) createAuditForDeleteOperation(tx *pg.Tx, models []userrepo.UserModel, userLoggedInId int32) error {
+	for i := range models {
+		err := impl.userPermissionAuditService.SaveAudit(tx, &userBean.PermissionAuditDto{
+			EntityType:    userBean.PermissionAuditEntityUser,
+			EntityId:      models[i].Id,
+			EntityName:    models[i].EmailId,
+			Action:        userBean.PermissionAuditActionDelete,
+			ActorId:       userLoggedInId,
+			PreviousState: impl.getPermissionAuditState(&models[i]),
+		})
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (impl *UserServiceImpl) getCasbinPolicyForGroup(tx *pg.Tx, emailId, userGroupCasbinName string, userRoleGroup userBean.UserRoleGroup, userLoggedInId int32) (bean4.Policy, error) {
-	casbinPolicy := adapter.GetCasbinGroupPolicy(emailId, userGroupCasbinName, nil)
-	return casbinPolicy, nil
+// getPermissionAuditState reads the permissions of the user as saved, inactive users have none
+func (impl *UserServiceImpl) getPermissionAuditState(model *userrepo.UserModel) *userBean.PermissionAuditState {
+	if !model.Active {
+		return nil
+	}
+	isSuperAdmin, roleFilters, groups, userRoleGroups := impl.getUserMetadata(model)
+	return adapter.BuildPermissionAuditStateForUser(&userBean.UserInfo{
+		EmailId:       model.EmailId,
+		SuperAdmin:    isSuperAdmin,
+		RoleFilters:   roleFilters,
+		Groups:        groups,
+		UserRoleGroup: userRoleGroups,
+	})
+}
+
+func (impl *UserServiceImpl) getCasbinPolicyForGroup(tx *pg.Tx, emailId, userGroupCasbinName string, userRoleGroup userBean.UserRoleGroup, userLoggedInId int32) (bean4.Policy, *userBean.TimeoutWindowConfigDto, error) {
+	timeoutWindowConfigDto, err := impl.timeBoundAccessService.GetOrCreateTimeoutWindowConfig(tx, userRoleGroup.ActiveFrom, userRoleGroup.ExpiresAt, userLoggedInId)
+	if err != nil {
+		impl.logger.Errorw("error in getCasbinPolicyForGroup", "userRoleGroup", userRoleGroup, "err", err)
+		return bean4.Policy{}, nil, err
+	}
+	casbinPolicy := adapter.GetCasbinGroupPolicy(emailId, userGroupCasbinName, timeoutWindowConfigDto)
+	return casbinPolicy, timeoutWindowConfigDto, nil
+}
+
+// getPoliciesForActiveWindow returns the policy only while its time window is open, grants outside their window are
+// added to and removed from casbin by TimeBoundAccessService
+func getPoliciesForActiveWindow(policy bean4.Policy, timeoutWindowConfigDto *userBean.TimeoutWindowConfigDto) []bean4.Policy {
+	if !timeoutWindowConfigDto.IsActiveAt(time.Now()) {
+		return nil
+	}
+	return []bean4.Policy{policy}
+}
+
+// updateTimeoutWindowForExistingRole moves an already mapped role to the requested time window, returns true if the window changed
+func (impl *UserServiceImpl) updateTimeoutWindowForExistingRole(tx *pg.Tx, existingRoles map[int]userrepo.UserRoleModel, roleId int, timeoutWindowConfigDto *userBean.TimeoutWindowConfigDto, userId int32) (bool, error) {
+	userRoleModel, ok := existingRoles[roleId]
+	if !ok || userRoleModel.TimeoutWindowConfigurationId == timeoutWindowConfigDto.GetId() {
+		return false, nil
+	}
+	userRoleModel.TimeoutWindowConfigurationId = timeoutWindowConfigDto.GetId()
+	userRoleModel.UpdateAuditLog(userId)
+	_, err := impl.userAuthRepository.UpdateUserRoleMapping(&userRoleModel, tx)
+	if err != nil {
+		impl.logger.Errorw("error in updateTimeoutWindowForExistingRole", "userRoleModel", userRoleModel, "err", err)
+		return false, err
+	}
+	existingRoles[roleId] = userRoleModel
+	return true, nil
 }
 
 func getUniqueKeyForRoleFilter(role userBean.RoleFilter) string {
@@ -65,8 +156,21 @@ func (impl *UserServiceImpl) updateUserGroupForUser(tx *pg.Tx, userInfo *userBea
 	return false, nil
 }
 
-func (impl *UserServiceImpl) saveAuditBasedOnActiveOrInactiveUser(tx *pg.Tx, isUserActive bool, model *userrepo.UserModel, userInfo *userBean.UserInfo) error {
-	return nil
+// saveAuditBasedOnActiveOrInactiveUser records the update of an inactive user as its creation
+func (impl *UserServiceImpl) saveAuditBasedOnActiveOrInactiveUser(tx *pg.Tx, isUserActive bool, model *userrepo.UserModel, userInfo *userBean.UserInfo,
+	previousAuditState *userBean.PermissionAuditState) error {
+	if !isUserActive {
+		return impl.createAuditForCreateOperation(tx, userInfo, model)
+	}
+	return impl.userPermissionAuditService.SaveAudit(tx, &userBean.PermissionAuditDto{
+		EntityType:    userBean.PermissionAuditEntityUser,
+		EntityId:      model.Id,
+		EntityName:    model.EmailId,
+		Action:        userBean.PermissionAuditActionUpdate,
+		ActorId:       userInfo.UserId,
+		PreviousState: previousAuditState,
+		CurrentState:  adapter.BuildPermissionAuditStateForUser(userInfo),
+	})
 }
 
 func setStatusFilterType(request *userBean.ListingRequest) {
@@ -78,7 +182,7 @@ func setCurrentTimeInUserInfo(request *userBean.ListingRequest) {
 }
 
 func (impl *UserServiceImpl) getTimeoutWindowConfig(tx *pg.Tx, roleFilter userBean.RoleFilter, userLoggedInId int32) (*userBean.TimeoutWindowConfigDto, error) {
-	return nil, nil
+	return impl.timeBoundAccessService.GetOrCreateTimeoutWindowConfig(tx, roleFilter.ActiveFrom, roleFilter.ExpiresAt, userLoggedInId)
 }
 
 func getSubactionFromRoleFilter(roleFilter userBean.RoleFilter) string {
@@ -143,18 +247,46 @@ func (impl *UserServiceImpl) createOrUpdateUserRoleGroupsPolices(requestUserRole
 		oldGroupMap[oldItem] = oldItem
 		mapOfExistingUserRoleGroup[oldItem] = true
 	}
+	// memberships synced from group claims are managed by GroupClaimsSyncService and kept as is
+	idpRoleGroups, err := impl.groupClaimsSyncService.GetIdpRoleGroups(userInfoId)
+	if err != nil {
+		impl.logger.Errorw("error encountered in createOrUpdateUserRoleGroupsPolices", "userRoleGroups", requestUserRoleGroups, "emailId", emailId, "err", err)
+		return nil, nil, nil, nil, err
+	}
+	for _, idpRoleGroup := range idpRoleGroups {
+		newGroupMap[idpRoleGroup.CasbinName] = idpRoleGroup.CasbinName
+	}
 	// START GROUP POLICY
+	roleGroupIdVsWindow := make(map[int32]*userBean.TimeoutWindowConfigDto, len(requestUserRoleGroups))
 	for _, item := range requestUserRoleGroups {
+		if item.Source == userBean.RoleGroupMembershipSourceIdp {
+			continue
+		}
 		userGroup, err := impl.roleGroupRepository.GetRoleGroupByName(item.RoleGroup.Name)
 		if err != nil {
 			impl.logger.Errorw("error encountered in createOrUpdateUserRoleGroupsPolices", "userRoleGroups", requestUserRoleGroups, "emailId", emailId, "err", err)
 			return nil, nil, nil, nil, err
 		}
+		timeoutWindowConfigDto, err := impl.timeBoundAccessService.GetOrCreateTimeoutWindowConfig(tx, item.ActiveFrom, item.ExpiresAt, loggedInUser)
+		if err != nil {
+			impl.logger.Errorw("error encountered in createOrUpdateUserRoleGroupsPolices", "userRoleGroups", requestUserRoleGroups, "emailId", emailId, "err", err)
+			return nil, nil, nil, nil, err
+		}
+		roleGroupIdVsWindow[userGroup.Id] = timeoutWindowConfigDto
+		if !timeoutWindowConfigDto.IsActiveAt(time.Now()) {
+			// membership outside its window is kept out of casbin, the sweeper adds it once the window opens
+			continue
+		}
 		newGroupMap[userGroup.CasbinName] = userGroup.CasbinName
 		if _, ok := oldGroupMap[userGroup.CasbinName]; !ok {
 			addedPolicies = append(addedPolicies, bean4.Policy{Type: "g", Sub: bean4.Subject(emailId), Obj: bean4.Object(userGroup.CasbinName)})
 		}
 	}
+	err = impl.timeBoundAccessService.SaveRoleGroupTimeoutWindows(tx, userInfoId, roleGroupIdVsWindow, loggedInUser)
+	if err != nil {
+		impl.logger.Errorw("error encountered in createOrUpdateUserRoleGroupsPolices", "userRoleGroups", requestUserRoleGroups, "emailId", emailId, "err", err)
+		return nil, nil, nil, nil, err
+	}
 	for _, item := range userCasbinRoles {
 		if _, ok := newGroupMap[item]; !ok {
 			if item != userBean.SUPERADMIN {
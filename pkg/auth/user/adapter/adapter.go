@@ -21,6 +21,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin/bean"
 	bean2 "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"strconv"
 	"strings"
 )
 
@@ -51,9 +52,11 @@ func BuildUserInfoResponseAdapter(requestUserInfo *bean2.UserInfo, emailId strin
 	}
 }
 
-func BuildSelfRegisterDto(userInfo *bean2.UserInfo) *bean2.SelfRegisterDto {
+func BuildSelfRegisterDto(userInfo *bean2.UserInfo, groupsFromClaims []string, connectorId string) *bean2.SelfRegisterDto {
 	return &bean2.SelfRegisterDto{
-		UserInfo: userInfo,
+		UserInfo:         userInfo,
+		GroupsFromClaims: groupsFromClaims,
+		ConnectorId:      connectorId,
 	}
 }
 
@@ -71,6 +74,8 @@ func BuildRoleFilterFromRoleF(roleF bean2.RoleFilter) bean2.RoleFilter {
 		Kind:        roleF.Kind,
 		Resource:    roleF.Resource,
 		Workflow:    roleF.Workflow,
+		ActiveFrom:  roleF.ActiveFrom,
+		ExpiresAt:   roleF.ExpiresAt,
 	}
 }
 
@@ -90,3 +95,53 @@ func BuildUserInfo(id int32, emailId string, userGroupMapDto *bean2.UserGroupMap
 		UserRoleGroup: make([]bean2.UserRoleGroup, 0),
 	}
 }
+
+func BuildPermissionAuditStateForUser(userInfo *bean2.UserInfo) *bean2.PermissionAuditState {
+	roleGroups := make([]string, 0, len(userInfo.UserRoleGroup))
+	for _, userRoleGroup := range userInfo.UserRoleGroup {
+		if userRoleGroup.RoleGroup != nil && len(userRoleGroup.RoleGroup.Name) > 0 {
+			roleGroups = append(roleGroups, userRoleGroup.RoleGroup.Name)
+		}
+	}
+	if len(roleGroups) == 0 {
+		roleGroups = append(roleGroups, userInfo.Groups...)
+	}
+	return &bean2.PermissionAuditState{
+		Name:        userInfo.EmailId,
+		SuperAdmin:  userInfo.SuperAdmin,
+		RoleFilters: userInfo.RoleFilters,
+		RoleGroups:  roleGroups,
+	}
+}
+
+func BuildPermissionAuditStateForRoleGroup(roleGroup *bean2.RoleGroup) *bean2.PermissionAuditState {
+	return &bean2.PermissionAuditState{
+		Name:        roleGroup.Name,
+		SuperAdmin:  roleGroup.SuperAdmin,
+		RoleFilters: roleGroup.RoleFilters,
+		Attributes:  map[string]string{"description": roleGroup.Description},
+	}
+}
+
+// BuildPermissionAuditStateForApiToken leaves out the token itself, the permissions of a token are audited on its user
+func BuildPermissionAuditStateForApiToken(name, description string, expireAtInMs int64) *bean2.PermissionAuditState {
+	return &bean2.PermissionAuditState{
+		Name: name,
+		Attributes: map[string]string{
+			"description":  description,
+			"expireAtInMs": strconv.FormatInt(expireAtInMs, 10),
+		},
+	}
+}
+
+// BuildPermissionAuditStateForSsoConfig leaves out the connector config as it carries the client secrets
+func BuildPermissionAuditStateForSsoConfig(ssoLoginDto *bean2.SSOLoginDto) *bean2.PermissionAuditState {
+	return &bean2.PermissionAuditState{
+		Name: ssoLoginDto.Name,
+		Attributes: map[string]string{
+			"label":  ssoLoginDto.Label,
+			"url":    ssoLoginDto.Url,
+			"active": strconv.FormatBool(ssoLoginDto.Active),
+		},
+	}
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+type RoleGroupMembershipSource string
+
+const (
+	RoleGroupMembershipSourceManual RoleGroupMembershipSource = "MANUAL"
+	RoleGroupMembershipSourceIdp    RoleGroupMembershipSource = "IDP"
+)
+
+// claims set by dex in the id token
+const (
+	GroupsClaimKey          = "groups"
+	FederatedClaimsKey      = "federated_claims"
+	FederatedConnectorIdKey = "connector_id"
+)
+
+const (
+	GroupClaimMappingExistsMessage   = "group claim is already mapped to the role group"
+	GroupClaimMappingNotFoundMessage = "group claim mapping not found"
+	RoleGroupNotFoundMessage         = "role group not found"
+)
+
+type GroupClaimsConfig struct {
+	SyncIntervalInSecs int `env:"GROUP_CLAIMS_SYNC_INTERVAL_SECS" envDefault:"300"`
+	// StaleAfterHrs drops the memberships of users who have not logged in for this long, 0 keeps them until next login
+	StaleAfterHrs int `env:"GROUP_CLAIMS_STALE_AFTER_HOURS" envDefault:"0"`
+}
+
+type RoleGroupClaimMappingDto struct {
+	Id            int    `json:"id"`
+	RoleGroupId   int32  `json:"roleGroupId" validate:"required,min=1"`
+	RoleGroupName string `json:"roleGroupName,omitempty"`
+	// ConnectorId restricts the mapping to one dex connector, empty applies to all connectors
+	ConnectorId string `json:"connectorId,omitempty"`
+	GroupClaim  string `json:"groupClaim" validate:"required,max=250"`
+	UserId      int32  `json:"-"`
+}
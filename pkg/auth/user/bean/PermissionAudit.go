@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+type PermissionAuditEntityType string
+
+const (
+	PermissionAuditEntityUser      PermissionAuditEntityType = "USER"
+	PermissionAuditEntityRoleGroup PermissionAuditEntityType = "ROLE_GROUP"
+	PermissionAuditEntityApiToken  PermissionAuditEntityType = "API_TOKEN"
+	PermissionAuditEntitySsoConfig PermissionAuditEntityType = "SSO_CONFIG"
+)
+
+type PermissionAuditAction string
+
+const (
+	PermissionAuditActionCreate       PermissionAuditAction = "CREATE"
+	PermissionAuditActionUpdate       PermissionAuditAction = "UPDATE"
+	PermissionAuditActionDelete       PermissionAuditAction = "DELETE"
+	PermissionAuditActionSelfRegister PermissionAuditAction = "SELF_REGISTER"
+)
+
+const (
+	PermissionAuditDefaultPageSize         = 100
+	PermissionAuditExportFileName          = "permission-audit.csv"
+	InvalidPermissionAuditTimeRangeMessage = "invalid time range, from and to should be RFC3339 timestamps with from before to"
+)
+
+// PermissionAuditState is the snapshot of an entity saved before and after a change, secrets are never part of it
+type PermissionAuditState struct {
+	Name        string            `json:"name,omitempty"`
+	SuperAdmin  bool              `json:"superAdmin"`
+	RoleFilters []RoleFilter      `json:"roleFilters,omitempty"`
+	RoleGroups  []string          `json:"roleGroups,omitempty"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+}
+
+type PermissionAuditDiff struct {
+	SuperAdminChanged  bool         `json:"superAdminChanged"`
+	AddedRoleFilters   []RoleFilter `json:"addedRoleFilters,omitempty"`
+	RemovedRoleFilters []RoleFilter `json:"removedRoleFilters,omitempty"`
+	AddedRoleGroups    []string     `json:"addedRoleGroups,omitempty"`
+	RemovedRoleGroups  []string     `json:"removedRoleGroups,omitempty"`
+	ChangedAttributes  []string     `json:"changedAttributes,omitempty"`
+}
+
+type PermissionAuditDto struct {
+	Id            int                       `json:"id"`
+	EntityType    PermissionAuditEntityType `json:"entityType"`
+	EntityId      int32                     `json:"entityId"`
+	EntityName    string                    `json:"entityName"`
+	Action        PermissionAuditAction     `json:"action"`
+	ActorId       int32                     `json:"actorId"`
+	ActorEmailId  string                    `json:"actorEmailId,omitempty"`
+	PreviousState *PermissionAuditState     `json:"previousState,omitempty"`
+	CurrentState  *PermissionAuditState     `json:"currentState,omitempty"`
+	Diff          *PermissionAuditDiff      `json:"diff"`
+	CreatedOn     time.Time                 `json:"createdOn"`
+}
+
+// PermissionAuditFilter is decoded from the query params, From and To are RFC3339 timestamps
+type PermissionAuditFilter struct {
+	EntityType PermissionAuditEntityType `json:"entityType"`
+	EntityId   int32                     `json:"entityId"`
+	ActorId    int32                     `json:"actorId"`
+	From       string                    `json:"from"`
+	To         string                    `json:"to"`
+	Offset     int                       `json:"offset"`
+	Size       int                       `json:"size"`
+}
+
+type PermissionAuditListingResponse struct {
+	Audits     []*PermissionAuditDto `json:"audits"`
+	TotalCount int                   `json:"totalCount"`
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+type PermissionScope string
+
+const (
+	PermissionScopeApplication PermissionScope = "application"
+	PermissionScopeEnvironment PermissionScope = "environment"
+	PermissionScopeCluster     PermissionScope = "cluster"
+	PermissionScopeGlobal      PermissionScope = "global"
+)
+
+const (
+	RbacSubjectRequiredMessage = "one of userId, emailId or apiTokenName is required"
+	RbacSubjectNotFoundMessage = "user or api token not found"
+)
+
+// RbacSubjectDto identifies the user whose access is inspected, api tokens are resolved to their token user
+type RbacSubjectDto struct {
+	UserId       int32  `json:"userId,omitempty"`
+	EmailId      string `json:"emailId,omitempty"`
+	ApiTokenName string `json:"apiTokenName,omitempty"`
+}
+
+type RbacExplainRequest struct {
+	RbacSubjectDto
+	Resource string `json:"resource" validate:"required"`
+	Action   string `json:"action" validate:"required"`
+	Object   string `json:"object" validate:"required"`
+}
+
+type RbacPolicyDto struct {
+	Subject  string `json:"subject"`
+	Resource string `json:"resource"`
+	Action   string `json:"action"`
+	Object   string `json:"object"`
+	Effect   string `json:"effect"`
+	// InheritedVia is the chain of roles and role groups between the user and Subject, empty for direct grants
+	InheritedVia []string `json:"inheritedVia,omitempty"`
+	RoleGroups   []string `json:"roleGroups,omitempty"`
+}
+
+type RbacExplainResponse struct {
+	UserId          int32            `json:"userId"`
+	EmailId         string           `json:"emailId"`
+	Allowed         bool             `json:"allowed"`
+	Reason          string           `json:"reason"`
+	MatchedPolicies []*RbacPolicyDto `json:"matchedPolicies"`
+	DenyPolicies    []*RbacPolicyDto `json:"denyPolicies"`
+}
+
+// EffectivePermissionDto groups the allowed actions of a user on a resource and object pattern
+type EffectivePermissionDto struct {
+	Scope      PermissionScope `json:"scope"`
+	Resource   string          `json:"resource"`
+	Object     string          `json:"object"`
+	Actions    []string        `json:"actions"`
+	RoleGroups []string        `json:"roleGroups,omitempty"`
+	Direct     bool            `json:"direct"`
+}
+
+type EffectivePermissionsResponse struct {
+	UserId       int32                     `json:"userId"`
+	EmailId      string                    `json:"emailId"`
+	SuperAdmin   bool                      `json:"superAdmin"`
+	Permissions  []*EffectivePermissionDto `json:"permissions"`
+	DenyPolicies []*RbacPolicyDto          `json:"denyPolicies"`
+}
+
+const (
+	RbacExplainAllowedReason      = "allowed by %d matching policies"
+	RbacExplainDeniedByRuleReason = "denied by %d matching deny policies"
+	RbacExplainNoMatchReason      = "no policy grants the action on the object"
+)
@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+type TimeoutExpressionFormat int
+
+const (
+	// TimeStamp expression holds only the expiry of the window in RFC3339
+	TimeStamp TimeoutExpressionFormat = 1
+	// TimeRange expression holds "<activeFrom>,<expiresAt>" in RFC3339, either of the bounds can be empty
+	TimeRange TimeoutExpressionFormat = 2
+)
+
+const TimeRangeExpressionSeparator = ","
+
+type RoleAccessRequestStatus string
+
+const (
+	RoleAccessRequestPending   RoleAccessRequestStatus = "PENDING"
+	RoleAccessRequestApproved  RoleAccessRequestStatus = "APPROVED"
+	RoleAccessRequestRejected  RoleAccessRequestStatus = "REJECTED"
+	RoleAccessRequestCancelled RoleAccessRequestStatus = "CANCELLED"
+)
+
+const (
+	InvalidTimeoutWindowMessage          = "invalid time window, expiresAt should be in future and after activeFrom"
+	AccessRequestExpiryRequiredMessage   = "expiresAt is required for an access request"
+	AccessRequestEmptyMessage            = "access request should have at least one role filter or role group"
+	AccessRequestDurationExceededMessage = "requested access duration exceeds the allowed maximum of %d hours"
+	AccessRequestNotPendingMessage       = "access request is already %s"
+	AccessRequestSelfReviewMessage       = "requester cannot review their own access request"
+	AccessRequestLapsedMessage           = "requested access window has already lapsed"
+)
+
+type TimeBoundAccessConfig struct {
+	SweeperIntervalInSecs       int `env:"TIME_BOUND_ACCESS_SWEEPER_INTERVAL_SECS" envDefault:"60"`
+	MaxAccessRequestDurationHrs int `env:"TIME_BOUND_ACCESS_MAX_REQUEST_DURATION_HOURS" envDefault:"72"`
+}
+
+type RoleAccessRequestDto struct {
+	Id             int                     `json:"id"`
+	UserId         int32                   `json:"userId"`
+	EmailId        string                  `json:"emailId,omitempty"`
+	RoleFilters    []RoleFilter            `json:"roleFilters"`
+	UserRoleGroups []UserRoleGroup         `json:"userRoleGroups"`
+	Reason         string                  `json:"reason" validate:"required,max=500"`
+	ActiveFrom     *time.Time              `json:"activeFrom,omitempty"`
+	ExpiresAt      *time.Time              `json:"expiresAt"`
+	Status         RoleAccessRequestStatus `json:"status"`
+	ReviewedBy     int32                   `json:"reviewedBy,omitempty"`
+	ReviewedOn     *time.Time              `json:"reviewedOn,omitempty"`
+	ReviewComment  string                  `json:"reviewComment,omitempty"`
+	CreatedOn      time.Time               `json:"createdOn"`
+}
+
+type RoleAccessRequestReviewDto struct {
+	Id      int    `json:"id" validate:"required,min=1"`
+	Comment string `json:"comment" validate:"max=500"`
+	UserId  int32  `json:"-"`
+}
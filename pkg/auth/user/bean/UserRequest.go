@@ -73,6 +73,10 @@ type RoleFilter struct {
 	Kind      string `json:"kind"`
 	Resource  string `json:"resource"`
 	Workflow  string `json:"workflow"`
+
+	// ActiveFrom and ExpiresAt bound the grant in time, both being nil means the grant is permanent
+	ActiveFrom *time.Time `json:"activeFrom,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
 }
 
 func (rf RoleFilter) GetTeam() string        { return rf.Team }
@@ -166,7 +170,11 @@ type BulkDeleteRequest struct {
 }
 
 type UserRoleGroup struct {
-	RoleGroup *RoleGroup `json:"roleGroup"`
+	RoleGroup  *RoleGroup `json:"roleGroup"`
+	ActiveFrom *time.Time `json:"activeFrom,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	// Source tells if the membership is assigned manually or synced from the group claims of the identity provider
+	Source RoleGroupMembershipSource `json:"source,omitempty"`
 }
 
 type GroupPermissionsAuditDto struct {
@@ -208,9 +216,52 @@ func (pa *UserPermissionsAuditDto) WithEntityAudit(entityAudit sql.AuditLog) *Us
 
 type SelfRegisterDto struct {
 	UserInfo *UserInfo
+	// GroupsFromClaims is nil when the identity provider did not send a groups claim
+	GroupsFromClaims []string
+	ConnectorId      string
 }
 
 type TimeoutWindowConfigDto struct {
+	Id         int        `json:"id"`
+	ActiveFrom *time.Time `json:"activeFrom,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+}
+
+// GetId returns 0 for a nil config, which is how permanent grants are stored
+func (dto *TimeoutWindowConfigDto) GetId() int {
+	if dto == nil {
+		return 0
+	}
+	return dto.Id
+}
+
+// IsActiveAt tells if the grant bounded by this window is effective at the given time, nil window is always active
+func (dto *TimeoutWindowConfigDto) IsActiveAt(t time.Time) bool {
+	if dto == nil {
+		return true
+	}
+	if dto.ActiveFrom != nil && t.Before(*dto.ActiveFrom) {
+		return false
+	}
+	return !dto.IsExpiredAt(t)
+}
+
+func (dto *TimeoutWindowConfigDto) IsExpiredAt(t time.Time) bool {
+	return dto != nil && dto.ExpiresAt != nil && !t.Before(*dto.ExpiresAt)
+}
+
+// GetNextTransitionAfter returns the next instant after t at which the grant gets activated or expires, nil if there is none
+func (dto *TimeoutWindowConfigDto) GetNextTransitionAfter(t time.Time) *time.Time {
+	if dto == nil {
+		return nil
+	}
+	if dto.ActiveFrom != nil && t.Before(*dto.ActiveFrom) {
+		return dto.ActiveFrom
+	}
+	if dto.ExpiresAt != nil && t.Before(*dto.ExpiresAt) {
+		return dto.ExpiresAt
+	}
+	return nil
 }
 
 type UserGroupMapDto struct {
@@ -142,3 +142,9 @@ const (
 	ApplicationBasedKey MergingBaseKey = "application"
 	EnvironmentBasedKey MergingBaseKey = "environment"
 )
+
+type ApiTokenConfig struct {
+	// TrustedProxyHops is the number of proxies in front of devtron that append to X-Forwarded-For, 0 trusts only the
+	// remote address of the connection when enforcing the ip allowlist of api tokens
+	TrustedProxyHops int `env:"API_TOKEN_TRUSTED_PROXY_HOPS" envDefault:"0"`
+}
@@ -17,14 +17,21 @@
 package helper
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin"
+	casbinBean "github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin/bean"
 	"github.com/devtron-labs/devtron/pkg/auth/user/bean"
 	"github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	"github.com/golang-jwt/jwt/v4"
 	"golang.org/x/exp/slices"
+	"net"
 	"net/http"
+	"sort"
 	"strings"
+	"time"
 )
 
 func IsSystemOrAdminUser(userId int32) bool {
@@ -135,3 +142,336 @@ func ValidateRoleFilters(rolefilters []bean.RoleFilter) error {
 func ValidateUserRoleGroupRequest(userRoleGroups []bean.UserRoleGroup) error {
 	return nil
 }
+
+// GetTimeoutWindowExpression builds the expression stored in timeout_window_configuration for the given window, windows
+// with only an expiry are kept in TimeStamp format and the rest in TimeRange format
+func GetTimeoutWindowExpression(activeFrom, expiresAt *time.Time) (string, bean.TimeoutExpressionFormat) {
+	if activeFrom == nil && expiresAt != nil {
+		return formatWindowBound(expiresAt), bean.TimeStamp
+	}
+	return formatWindowBound(activeFrom) + bean.TimeRangeExpressionSeparator + formatWindowBound(expiresAt), bean.TimeRange
+}
+
+// ParseTimeoutWindowExpression is the reverse of GetTimeoutWindowExpression
+func ParseTimeoutWindowExpression(expression string, format bean.TimeoutExpressionFormat) (activeFrom, expiresAt *time.Time, err error) {
+	switch format {
+	case bean.TimeStamp:
+		expiresAt, err = parseWindowBound(expression)
+		return nil, expiresAt, err
+	case bean.TimeRange:
+		bounds := strings.Split(expression, bean.TimeRangeExpressionSeparator)
+		if len(bounds) != 2 {
+			return nil, nil, fmt.Errorf("invalid time range expression %q", expression)
+		}
+		activeFrom, err = parseWindowBound(bounds[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		expiresAt, err = parseWindowBound(bounds[1])
+		return activeFrom, expiresAt, err
+	default:
+		return nil, nil, fmt.Errorf("unsupported timeout window expression format %d", format)
+	}
+}
+
+func formatWindowBound(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func parseWindowBound(bound string) (*time.Time, error) {
+	if len(bound) == 0 {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, bound)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ValidateTimeoutWindow checks that a time bound grant is not already lapsed and has a sane range
+func ValidateTimeoutWindow(activeFrom, expiresAt *time.Time, now time.Time) error {
+	if expiresAt == nil {
+		return nil
+	}
+	if !expiresAt.After(now) || (activeFrom != nil && !expiresAt.After(*activeFrom)) {
+		return util.NewApiError(http.StatusBadRequest, bean.InvalidTimeoutWindowMessage, bean.InvalidTimeoutWindowMessage)
+	}
+	return nil
+}
+
+func ValidateTimeoutWindows(roleFilters []bean.RoleFilter, userRoleGroups []bean.UserRoleGroup, now time.Time) error {
+	for _, roleFilter := range roleFilters {
+		if err := ValidateTimeoutWindow(roleFilter.ActiveFrom, roleFilter.ExpiresAt, now); err != nil {
+			return err
+		}
+	}
+	for _, userRoleGroup := range userRoleGroups {
+		if err := ValidateTimeoutWindow(userRoleGroup.ActiveFrom, userRoleGroup.ExpiresAt, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HasTimeoutWindow tells if the grant is bounded in time
+func HasTimeoutWindow(activeFrom, expiresAt *time.Time) bool {
+	return activeFrom != nil || expiresAt != nil
+}
+
+// GetGroupClaimsFromTokenClaims returns the groups claim and the dex connector the user logged in with, groups are nil
+// when the identity provider did not send the claim so that memberships are not dropped for such connectors
+func GetGroupClaimsFromTokenClaims(claims jwt.MapClaims) (groups []string, connectorId string) {
+	if federatedClaims, ok := claims[bean.FederatedClaimsKey].(map[string]interface{}); ok {
+		connectorId, _ = federatedClaims[bean.FederatedConnectorIdKey].(string)
+	}
+	switch groupsClaim := claims[bean.GroupsClaimKey].(type) {
+	case []interface{}:
+		groups = make([]string, 0, len(groupsClaim))
+		for _, group := range groupsClaim {
+			if groupName, ok := group.(string); ok && len(groupName) > 0 {
+				groups = append(groups, groupName)
+			}
+		}
+	case []string:
+		groups = groupsClaim
+	case string:
+		groups = []string{groupsClaim}
+	}
+	return groups, connectorId
+}
+
+// GetRoleGroupIdsForGroupClaims returns the role groups granted by the group claims through the mappings
+func GetRoleGroupIdsForGroupClaims(mappings []*repository.RoleGroupClaimMapping, connectorId string, groupClaims []string) map[int32]bool {
+	roleGroupIds := make(map[int32]bool)
+	for _, mapping := range mappings {
+		if len(mapping.ConnectorId) > 0 && mapping.ConnectorId != connectorId {
+			continue
+		}
+		if slices.Contains(groupClaims, mapping.GroupClaim) {
+			roleGroupIds[mapping.RoleGroupId] = true
+		}
+	}
+	return roleGroupIds
+}
+
+// GetPermissionScope classifies a casbin resource, kubernetes resource policies use {cluster}/{namespace} as resource
+func GetPermissionScope(resource string) bean.PermissionScope {
+	switch resource {
+	case casbin.ResourceApplications, casbin.ResourceHelmApp, casbin.ResourceJobs, casbin.ResourceWorkflow:
+		return bean.PermissionScopeApplication
+	case casbin.ResourceEnvironment, casbin.ResourceGlobalEnvironment, casbin.ResourceJobsEnv:
+		return bean.PermissionScopeEnvironment
+	case casbin.ResourceCluster, casbin.ResourceTerminal:
+		return bean.PermissionScopeCluster
+	}
+	if strings.Contains(resource, "/") {
+		return bean.PermissionScopeCluster
+	}
+	return bean.PermissionScopeGlobal
+}
+
+func IsSuperAdminPolicy(policy *bean.RbacPolicyDto) bool {
+	return policy.Effect != string(casbinBean.PolicyEffectDeny) && policy.Resource == "*" && policy.Action == "*" && policy.Object == "*"
+}
+
+// BuildEffectivePermissions merges the allow policies by resource and object, deny policies are reported separately
+func BuildEffectivePermissions(policies []*bean.RbacPolicyDto) []*bean.EffectivePermissionDto {
+	permissions := make([]*bean.EffectivePermissionDto, 0)
+	permissionByKey := make(map[string]*bean.EffectivePermissionDto)
+	for _, policy := range policies {
+		if policy.Effect == string(casbinBean.PolicyEffectDeny) {
+			continue
+		}
+		key := policy.Resource + "$$" + policy.Object
+		permission, ok := permissionByKey[key]
+		if !ok {
+			permission = &bean.EffectivePermissionDto{
+				Scope:      GetPermissionScope(policy.Resource),
+				Resource:   policy.Resource,
+				Object:     policy.Object,
+				Actions:    make([]string, 0),
+				RoleGroups: make([]string, 0),
+			}
+			permissionByKey[key] = permission
+			permissions = append(permissions, permission)
+		}
+		if !slices.Contains(permission.Actions, policy.Action) {
+			permission.Actions = append(permission.Actions, policy.Action)
+		}
+		if len(policy.RoleGroups) == 0 {
+			permission.Direct = true
+		}
+		for _, roleGroup := range policy.RoleGroups {
+			if !slices.Contains(permission.RoleGroups, roleGroup) {
+				permission.RoleGroups = append(permission.RoleGroups, roleGroup)
+			}
+		}
+	}
+	for _, permission := range permissions {
+		sort.Strings(permission.Actions)
+		sort.Strings(permission.RoleGroups)
+	}
+	sort.SliceStable(permissions, func(i, j int) bool {
+		if permissions[i].Scope != permissions[j].Scope {
+			return permissions[i].Scope < permissions[j].Scope
+		}
+		if permissions[i].Resource != permissions[j].Resource {
+			return permissions[i].Resource < permissions[j].Resource
+		}
+		return permissions[i].Object < permissions[j].Object
+	})
+	return permissions
+}
+
+// GetPermissionAuditDiff compares the snapshots of an audit, a nil snapshot is treated as an entity without permissions
+func GetPermissionAuditDiff(previous, current *bean.PermissionAuditState) *bean.PermissionAuditDiff {
+	if previous == nil {
+		previous = &bean.PermissionAuditState{}
+	}
+	if current == nil {
+		current = &bean.PermissionAuditState{}
+	}
+	diff := &bean.PermissionAuditDiff{SuperAdminChanged: previous.SuperAdmin != current.SuperAdmin}
+	diff.AddedRoleFilters = getRoleFiltersNotIn(current.RoleFilters, previous.RoleFilters)
+	diff.RemovedRoleFilters = getRoleFiltersNotIn(previous.RoleFilters, current.RoleFilters)
+	for _, roleGroup := range current.RoleGroups {
+		if !slices.Contains(previous.RoleGroups, roleGroup) {
+			diff.AddedRoleGroups = append(diff.AddedRoleGroups, roleGroup)
+		}
+	}
+	for _, roleGroup := range previous.RoleGroups {
+		if !slices.Contains(current.RoleGroups, roleGroup) {
+			diff.RemovedRoleGroups = append(diff.RemovedRoleGroups, roleGroup)
+		}
+	}
+	for key, value := range current.Attributes {
+		if previousValue, ok := previous.Attributes[key]; !ok || previousValue != value {
+			diff.ChangedAttributes = append(diff.ChangedAttributes, key)
+		}
+	}
+	for key := range previous.Attributes {
+		if _, ok := current.Attributes[key]; !ok {
+			diff.ChangedAttributes = append(diff.ChangedAttributes, key)
+		}
+	}
+	sort.Strings(diff.ChangedAttributes)
+	return diff
+}
+
+func getRoleFiltersNotIn(roleFilters, otherRoleFilters []bean.RoleFilter) []bean.RoleFilter {
+	otherKeys := make(map[string]bool, len(otherRoleFilters))
+	for _, roleFilter := range otherRoleFilters {
+		otherKeys[getRoleFilterKey(roleFilter)] = true
+	}
+	var result []bean.RoleFilter
+	for _, roleFilter := range roleFilters {
+		if !otherKeys[getRoleFilterKey(roleFilter)] {
+			result = append(result, roleFilter)
+		}
+	}
+	return result
+}
+
+func getRoleFilterKey(roleFilter bean.RoleFilter) string {
+	key, _ := json.Marshal(roleFilter)
+	return string(key)
+}
+
+// GetPermissionAuditTimeRange parses the optional RFC3339 bounds of the audit filter
+func GetPermissionAuditTimeRange(from, to string) (*time.Time, *time.Time, error) {
+	fromTime, err := parseOptionalTime(from)
+	if err != nil {
+		return nil, nil, err
+	}
+	toTime, err := parseOptionalTime(to)
+	if err != nil {
+		return nil, nil, err
+	}
+	if fromTime != nil && toTime != nil && !fromTime.Before(*toTime) {
+		return nil, nil, util.NewApiError(http.StatusBadRequest, bean.InvalidPermissionAuditTimeRangeMessage, bean.InvalidPermissionAuditTimeRangeMessage)
+	}
+	return fromTime, toTime, nil
+}
+
+func parseOptionalTime(value string) (*time.Time, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, util.NewApiError(http.StatusBadRequest, bean.InvalidPermissionAuditTimeRangeMessage, err.Error())
+	}
+	return &parsed, nil
+}
+
+func GetAllowedIpsFromTokenClaims(claims jwt.MapClaims) []string {
+	var allowedIps []string
+	switch allowedIpsClaim := claims[casbinBean.ApiTokenAllowedIpsClaim].(type) {
+	case []interface{}:
+		for _, allowedIp := range allowedIpsClaim {
+			if ip, ok := allowedIp.(string); ok && len(ip) > 0 {
+				allowedIps = append(allowedIps, ip)
+			}
+		}
+	case []string:
+		allowedIps = allowedIpsClaim
+	}
+	return allowedIps
+}
+
+// ValidateAllowedIps accepts ip addresses and cidr ranges
+func ValidateAllowedIps(allowedIps []string) error {
+	for _, allowedIp := range allowedIps {
+		if net.ParseIP(allowedIp) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(allowedIp); err != nil {
+			return fmt.Errorf("'%s' is neither an ip address nor a cidr range", allowedIp)
+		}
+	}
+	return nil
+}
+
+// GetApiTokenClientIp resolves the ip that the api token allowlist is checked against. Entries of X-Forwarded-For are
+// only taken from the right, as many as there are trusted proxies, since everything left of them is set by the client.
+func GetApiTokenClientIp(remoteAddr, xForwardedFor string, trustedProxyHops int) string {
+	clientIp := remoteAddr
+	if trustedProxyHops > 0 && len(strings.TrimSpace(xForwardedFor)) > 0 {
+		forwardedIps := strings.Split(xForwardedFor, ",")
+		index := len(forwardedIps) - trustedProxyHops
+		if index < 0 {
+			index = 0
+		}
+		clientIp = strings.TrimSpace(forwardedIps[index])
+	}
+	if host, _, err := net.SplitHostPort(clientIp); err == nil {
+		clientIp = host
+	}
+	return clientIp
+}
+
+// IsClientIpAllowed matches the client ip against the allowlist, an empty allowlist allows every ip. The client ip is
+// resolved by GetApiTokenClientIp.
+func IsClientIpAllowed(clientIp string, allowedIps []string) bool {
+	if len(allowedIps) == 0 {
+		return true
+	}
+	ip := net.ParseIP(clientIp)
+	if ip == nil {
+		return false
+	}
+	for _, allowedIp := range allowedIps {
+		if allowed := net.ParseIP(allowedIp); allowed != nil {
+			if allowed.Equal(ip) {
+				return true
+			}
+		} else if _, ipNet, err := net.ParseCIDR(allowedIp); err == nil && ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,224 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/devtron-labs/devtron/pkg/auth/user/repository"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestTimeoutWindowExpression(t *testing.T) {
+	activeFrom := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	expiresAt := activeFrom.Add(4 * time.Hour)
+
+	t.Run("expiry only window uses timestamp format", func(t *testing.T) {
+		expression, format := GetTimeoutWindowExpression(nil, &expiresAt)
+		assert.Equal(t, bean.TimeStamp, format)
+		parsedFrom, parsedTo, err := ParseTimeoutWindowExpression(expression, format)
+		assert.Nil(t, err)
+		assert.Nil(t, parsedFrom)
+		assert.True(t, parsedTo.Equal(expiresAt))
+	})
+
+	t.Run("bounded window round trips", func(t *testing.T) {
+		expression, format := GetTimeoutWindowExpression(&activeFrom, &expiresAt)
+		assert.Equal(t, bean.TimeRange, format)
+		parsedFrom, parsedTo, err := ParseTimeoutWindowExpression(expression, format)
+		assert.Nil(t, err)
+		assert.True(t, parsedFrom.Equal(activeFrom))
+		assert.True(t, parsedTo.Equal(expiresAt))
+	})
+
+	t.Run("open ended window round trips", func(t *testing.T) {
+		expression, format := GetTimeoutWindowExpression(&activeFrom, nil)
+		parsedFrom, parsedTo, err := ParseTimeoutWindowExpression(expression, format)
+		assert.Nil(t, err)
+		assert.True(t, parsedFrom.Equal(activeFrom))
+		assert.Nil(t, parsedTo)
+	})
+
+	t.Run("malformed expression", func(t *testing.T) {
+		_, _, err := ParseTimeoutWindowExpression("not-a-range", bean.TimeRange)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestValidateTimeoutWindow(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	later := future.Add(time.Hour)
+
+	assert.Nil(t, ValidateTimeoutWindow(nil, nil, now))
+	assert.Nil(t, ValidateTimeoutWindow(&past, &future, now))
+	assert.Nil(t, ValidateTimeoutWindow(&future, &later, now))
+	assert.NotNil(t, ValidateTimeoutWindow(nil, &past, now))
+	assert.NotNil(t, ValidateTimeoutWindow(&later, &future, now))
+	assert.NotNil(t, ValidateTimeoutWindows(nil, []bean.UserRoleGroup{{ExpiresAt: &past}}, now))
+}
+
+func TestTimeoutWindowConfigDto(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	activeFrom, expiresAt := now.Add(time.Hour), now.Add(2*time.Hour)
+	window := &bean.TimeoutWindowConfigDto{Id: 1, ActiveFrom: &activeFrom, ExpiresAt: &expiresAt}
+
+	var permanent *bean.TimeoutWindowConfigDto
+	assert.True(t, permanent.IsActiveAt(now))
+	assert.False(t, permanent.IsExpiredAt(now))
+	assert.Nil(t, permanent.GetNextTransitionAfter(now))
+	assert.Equal(t, 0, permanent.GetId())
+
+	assert.False(t, window.IsActiveAt(now))
+	assert.Equal(t, activeFrom, *window.GetNextTransitionAfter(now))
+	assert.True(t, window.IsActiveAt(activeFrom))
+	assert.Equal(t, expiresAt, *window.GetNextTransitionAfter(activeFrom))
+	assert.False(t, window.IsActiveAt(expiresAt))
+	assert.True(t, window.IsExpiredAt(expiresAt))
+	assert.Nil(t, window.GetNextTransitionAfter(expiresAt))
+}
+
+func TestGetGroupClaimsFromTokenClaims(t *testing.T) {
+	groups, connectorId := GetGroupClaimsFromTokenClaims(jwt.MapClaims{
+		"email":            "user@example.com",
+		"groups":           []interface{}{"platform", "", "sre"},
+		"federated_claims": map[string]interface{}{"connector_id": "okta", "user_id": "123"},
+	})
+	assert.Equal(t, []string{"platform", "sre"}, groups)
+	assert.Equal(t, "okta", connectorId)
+
+	// connector not sending groups must not drop existing memberships
+	groups, connectorId = GetGroupClaimsFromTokenClaims(jwt.MapClaims{"email": "user@example.com"})
+	assert.Nil(t, groups)
+	assert.Equal(t, "", connectorId)
+
+	groups, _ = GetGroupClaimsFromTokenClaims(jwt.MapClaims{"groups": []interface{}{}})
+	assert.NotNil(t, groups)
+	assert.Empty(t, groups)
+}
+
+func TestGetRoleGroupIdsForGroupClaims(t *testing.T) {
+	mappings := []*repository.RoleGroupClaimMapping{
+		{RoleGroupId: 1, GroupClaim: "platform"},
+		{RoleGroupId: 2, GroupClaim: "sre", ConnectorId: "okta"},
+		{RoleGroupId: 3, GroupClaim: "sre", ConnectorId: "ldap"},
+		{RoleGroupId: 4, GroupClaim: "finance"},
+	}
+	assert.Equal(t, map[int32]bool{1: true, 2: true}, GetRoleGroupIdsForGroupClaims(mappings, "okta", []string{"platform", "sre"}))
+	assert.Equal(t, map[int32]bool{3: true}, GetRoleGroupIdsForGroupClaims(mappings, "ldap", []string{"sre"}))
+	assert.Empty(t, GetRoleGroupIdsForGroupClaims(mappings, "okta", nil))
+}
+
+func TestBuildEffectivePermissions(t *testing.T) {
+	permissions := BuildEffectivePermissions([]*bean.RbacPolicyDto{
+		{Resource: "environment", Action: "trigger", Object: "prod/*", Effect: "allow", RoleGroups: []string{"release"}},
+		{Resource: "applications", Action: "get", Object: "demo/*", Effect: "allow"},
+		{Resource: "applications", Action: "trigger", Object: "demo/*", Effect: "allow", RoleGroups: []string{"release"}},
+		{Resource: "environment", Action: "trigger", Object: "prod/payments", Effect: "deny"},
+		{Resource: "prod-cluster/default", Action: "get", Object: "apps/deployment/*", Effect: "allow"},
+	})
+	assert.Len(t, permissions, 3)
+	assert.Equal(t, bean.PermissionScopeApplication, permissions[0].Scope)
+	assert.Equal(t, []string{"get", "trigger"}, permissions[0].Actions)
+	assert.Equal(t, []string{"release"}, permissions[0].RoleGroups)
+	assert.True(t, permissions[0].Direct)
+	assert.Equal(t, bean.PermissionScopeCluster, permissions[1].Scope)
+	assert.Equal(t, bean.PermissionScopeEnvironment, permissions[2].Scope)
+	assert.False(t, permissions[2].Direct)
+
+	assert.True(t, IsSuperAdminPolicy(&bean.RbacPolicyDto{Resource: "*", Action: "*", Object: "*", Effect: "allow"}))
+	assert.False(t, IsSuperAdminPolicy(&bean.RbacPolicyDto{Resource: "*", Action: "*", Object: "*", Effect: "deny"}))
+}
+
+func TestGetPermissionAuditDiff(t *testing.T) {
+	previous := &bean.PermissionAuditState{
+		RoleFilters: []bean.RoleFilter{{Entity: "apps", Team: "demo", Action: "view"}, {Entity: "apps", Team: "demo", Action: "trigger"}},
+		RoleGroups:  []string{"dev", "qa"},
+		Attributes:  map[string]string{"description": "old", "url": "https://old"},
+	}
+	current := &bean.PermissionAuditState{
+		SuperAdmin:  true,
+		RoleFilters: []bean.RoleFilter{{Entity: "apps", Team: "demo", Action: "view"}, {Entity: "apps", Team: "demo", Action: "admin"}},
+		RoleGroups:  []string{"dev", "release"},
+		Attributes:  map[string]string{"description": "new", "label": "okta"},
+	}
+	diff := GetPermissionAuditDiff(previous, current)
+	assert.True(t, diff.SuperAdminChanged)
+	assert.Equal(t, []bean.RoleFilter{{Entity: "apps", Team: "demo", Action: "admin"}}, diff.AddedRoleFilters)
+	assert.Equal(t, []bean.RoleFilter{{Entity: "apps", Team: "demo", Action: "trigger"}}, diff.RemovedRoleFilters)
+	assert.Equal(t, []string{"release"}, diff.AddedRoleGroups)
+	assert.Equal(t, []string{"qa"}, diff.RemovedRoleGroups)
+	assert.Equal(t, []string{"description", "label", "url"}, diff.ChangedAttributes)
+
+	diff = GetPermissionAuditDiff(nil, current)
+	assert.Len(t, diff.AddedRoleFilters, 2)
+	assert.Empty(t, diff.RemovedRoleFilters)
+	diff = GetPermissionAuditDiff(previous, nil)
+	assert.False(t, diff.SuperAdminChanged)
+	assert.Len(t, diff.RemovedRoleGroups, 2)
+}
+
+func TestGetPermissionAuditTimeRange(t *testing.T) {
+	from, to, err := GetPermissionAuditTimeRange("", "")
+	assert.Nil(t, err)
+	assert.Nil(t, from)
+	assert.Nil(t, to)
+
+	from, to, err = GetPermissionAuditTimeRange("2024-01-01T00:00:00Z", "2024-02-01T00:00:00Z")
+	assert.Nil(t, err)
+	assert.True(t, from.Before(*to))
+
+	_, _, err = GetPermissionAuditTimeRange("2024-02-01T00:00:00Z", "2024-01-01T00:00:00Z")
+	assert.NotNil(t, err)
+	_, _, err = GetPermissionAuditTimeRange("yesterday", "")
+	assert.NotNil(t, err)
+}
+
+func TestIsClientIpAllowed(t *testing.T) {
+	allowedIps := []string{"10.0.0.0/24", "192.168.1.10"}
+	assert.True(t, IsClientIpAllowed("1.2.3.4", nil))
+	assert.True(t, IsClientIpAllowed("10.0.0.25", allowedIps))
+	assert.False(t, IsClientIpAllowed("10.0.1.25", allowedIps))
+	assert.False(t, IsClientIpAllowed("not-an-ip", allowedIps))
+
+	assert.Nil(t, ValidateAllowedIps(allowedIps))
+	assert.NotNil(t, ValidateAllowedIps([]string{"10.0.0.0/33"}))
+}
+
+func TestGetApiTokenClientIp(t *testing.T) {
+	allowedIps := []string{"10.0.0.0/24"}
+	assert.Equal(t, "192.168.1.10", GetApiTokenClientIp("192.168.1.10:51234", "", 0))
+
+	// without trusted proxies a spoofed X-Forwarded-For is ignored
+	clientIp := GetApiTokenClientIp("172.16.0.1:443", "10.0.0.7", 0)
+	assert.Equal(t, "172.16.0.1", clientIp)
+	assert.False(t, IsClientIpAllowed(clientIp, allowedIps))
+
+	// behind one proxy only the entry appended by that proxy is trusted, not the spoofed leftmost one
+	clientIp = GetApiTokenClientIp("10.96.0.5:443", "10.0.0.7, 172.16.0.1", 1)
+	assert.Equal(t, "172.16.0.1", clientIp)
+	assert.False(t, IsClientIpAllowed(clientIp, allowedIps))
+
+	clientIp = GetApiTokenClientIp("10.96.0.5:443", "172.16.0.1, 10.0.0.7, 10.96.0.4", 2)
+	assert.Equal(t, "10.0.0.7", clientIp)
+	assert.True(t, IsClientIpAllowed(clientIp, allowedIps))
+
+	// fewer entries than hops falls back to the leftmost entry
+	assert.Equal(t, "10.0.0.7", GetApiTokenClientIp("10.96.0.5:443", "10.0.0.7", 3))
+}
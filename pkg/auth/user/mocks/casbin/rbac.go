@@ -7,6 +7,7 @@ package mock_casbin
 import (
 	reflect "reflect"
 
+	bean "github.com/devtron-labs/devtron/pkg/auth/authorisation/casbin/bean"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -89,6 +90,21 @@ func (mr *MockEnforcerMockRecorder) EnforceErr(emailId, resource, action, resour
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnforceErr", reflect.TypeOf((*MockEnforcer)(nil).EnforceErr), emailId, resource, action, resourceItem)
 }
 
+// ExplainByEmail mocks base method.
+func (m *MockEnforcer) ExplainByEmail(emailId, resource, action, resourceItem string) (*bean.EnforceExplanation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExplainByEmail", emailId, resource, action, resourceItem)
+	ret0, _ := ret[0].(*bean.EnforceExplanation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExplainByEmail indicates an expected call of ExplainByEmail.
+func (mr *MockEnforcerMockRecorder) ExplainByEmail(emailId, resource, action, resourceItem interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExplainByEmail", reflect.TypeOf((*MockEnforcer)(nil).ExplainByEmail), emailId, resource, action, resourceItem)
+}
+
 // GetCacheDump mocks base method.
 func (m *MockEnforcer) GetCacheDump() string {
 	m.ctrl.T.Helper()
@@ -103,6 +119,21 @@ func (mr *MockEnforcerMockRecorder) GetCacheDump() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCacheDump", reflect.TypeOf((*MockEnforcer)(nil).GetCacheDump))
 }
 
+// GetEffectivePoliciesByEmail mocks base method.
+func (m *MockEnforcer) GetEffectivePoliciesByEmail(emailId string) ([]*bean.GrantedPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEffectivePoliciesByEmail", emailId)
+	ret0, _ := ret[0].([]*bean.GrantedPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEffectivePoliciesByEmail indicates an expected call of GetEffectivePoliciesByEmail.
+func (mr *MockEnforcerMockRecorder) GetEffectivePoliciesByEmail(emailId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEffectivePoliciesByEmail", reflect.TypeOf((*MockEnforcer)(nil).GetEffectivePoliciesByEmail), emailId)
+}
+
 // InvalidateCache mocks base method.
 func (m *MockEnforcer) InvalidateCache(emailId string) bool {
 	m.ctrl.T.Helper()
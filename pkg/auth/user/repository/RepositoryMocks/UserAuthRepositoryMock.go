@@ -466,6 +466,52 @@ func (_m *UserAuthRepository) GetUserRoleMappingByUserId(userId int32) ([]*repos
 	return r0, r1
 }
 
+// GetUserRoleMappingsWithTimeoutWindow provides a mock function with given fields:
+func (_m *UserAuthRepository) GetUserRoleMappingsWithTimeoutWindow() ([]*repository2.UserRoleModel, error) {
+	ret := _m.Called()
+
+	var r0 []*repository2.UserRoleModel
+	if rf, ok := ret.Get(0).(func() []*repository2.UserRoleModel); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*repository2.UserRoleModel)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateUserRoleMapping provides a mock function with given fields: userRoleModel, tx
+func (_m *UserAuthRepository) UpdateUserRoleMapping(userRoleModel *repository2.UserRoleModel, tx *pg.Tx) (*repository2.UserRoleModel, error) {
+	ret := _m.Called(userRoleModel, tx)
+
+	var r0 *repository2.UserRoleModel
+	if rf, ok := ret.Get(0).(func(*repository2.UserRoleModel, *pg.Tx) *repository2.UserRoleModel); ok {
+		r0 = rf(userRoleModel, tx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*repository2.UserRoleModel)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*repository2.UserRoleModel, *pg.Tx) error); ok {
+		r1 = rf(userRoleModel, tx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SyncOrchestratorToCasbin provides a mock function with given fields: team, entityName, env, tx
 func (_m *UserAuthRepository) SyncOrchestratorToCasbin(team string, entityName string, env string, tx *pg.Tx) (bool, error) {
 	ret := _m.Called(team, entityName, env, tx)
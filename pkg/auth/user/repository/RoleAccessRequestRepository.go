@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type RoleAccessRequestRepository interface {
+	Save(model *RoleAccessRequest) error
+	Update(model *RoleAccessRequest) error
+	FindById(id int) (*RoleAccessRequest, error)
+	// FindAll returns requests ordered by latest first, userId and statuses are applied only when provided
+	FindAll(userId int32, statuses []userBean.RoleAccessRequestStatus) ([]*RoleAccessRequest, error)
+}
+
+type RoleAccessRequest struct {
+	TableName     struct{}                         `sql:"role_access_request" pg:",discard_unknown_columns"`
+	Id            int                              `sql:"id,pk"`
+	UserId        int32                            `sql:"user_id,notnull"`
+	RoleFilters   string                           `sql:"role_filters"`
+	RoleGroups    string                           `sql:"role_groups"`
+	Reason        string                           `sql:"reason"`
+	ActiveFrom    time.Time                        `sql:"active_from"`
+	ExpiresAt     time.Time                        `sql:"expires_at,notnull"`
+	Status        userBean.RoleAccessRequestStatus `sql:"status,notnull"`
+	ReviewedBy    int32                            `sql:"reviewed_by"`
+	ReviewedOn    time.Time                        `sql:"reviewed_on"`
+	ReviewComment string                           `sql:"review_comment"`
+	sql.AuditLog
+}
+
+type RoleAccessRequestRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewRoleAccessRequestRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *RoleAccessRequestRepositoryImpl {
+	return &RoleAccessRequestRepositoryImpl{dbConnection: dbConnection, logger: logger}
+}
+
+func (impl *RoleAccessRequestRepositoryImpl) Save(model *RoleAccessRequest) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *RoleAccessRequestRepositoryImpl) Update(model *RoleAccessRequest) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *RoleAccessRequestRepositoryImpl) FindById(id int) (*RoleAccessRequest, error) {
+	model := &RoleAccessRequest{}
+	err := impl.dbConnection.Model(model).Where("id = ?", id).Select()
+	return model, err
+}
+
+func (impl *RoleAccessRequestRepositoryImpl) FindAll(userId int32, statuses []userBean.RoleAccessRequestStatus) ([]*RoleAccessRequest, error) {
+	var models []*RoleAccessRequest
+	query := impl.dbConnection.Model(&models)
+	if userId > 0 {
+		query = query.Where("user_id = ?", userId)
+	}
+	if len(statuses) > 0 {
+		query = query.Where("status in (?)", pg.In(statuses))
+	}
+	err := query.Order("id DESC").Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting role access requests", "userId", userId, "statuses", statuses, "err", err)
+		return nil, err
+	}
+	return models, nil
+}
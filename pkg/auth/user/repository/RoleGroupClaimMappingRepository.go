@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// RoleGroupClaimMappingRepository keeps which group claims of the identity provider grant which role group
+type RoleGroupClaimMappingRepository interface {
+	Save(model *RoleGroupClaimMapping) error
+	Update(model *RoleGroupClaimMapping) error
+	FindById(id int) (*RoleGroupClaimMapping, error)
+	FindAllActive() ([]*RoleGroupClaimMapping, error)
+	FindActiveByRoleGroupIdAndClaim(roleGroupId int32, connectorId, groupClaim string) (*RoleGroupClaimMapping, error)
+}
+
+type RoleGroupClaimMapping struct {
+	TableName   struct{} `sql:"role_group_claim_mapping" pg:",discard_unknown_columns"`
+	Id          int      `sql:"id,pk"`
+	RoleGroupId int32    `sql:"role_group_id,notnull"`
+	ConnectorId string   `sql:"connector_id"`
+	GroupClaim  string   `sql:"group_claim,notnull"`
+	Active      bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type RoleGroupClaimMappingRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewRoleGroupClaimMappingRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *RoleGroupClaimMappingRepositoryImpl {
+	return &RoleGroupClaimMappingRepositoryImpl{dbConnection: dbConnection, logger: logger}
+}
+
+func (impl *RoleGroupClaimMappingRepositoryImpl) Save(model *RoleGroupClaimMapping) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *RoleGroupClaimMappingRepositoryImpl) Update(model *RoleGroupClaimMapping) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *RoleGroupClaimMappingRepositoryImpl) FindById(id int) (*RoleGroupClaimMapping, error) {
+	model := &RoleGroupClaimMapping{}
+	err := impl.dbConnection.Model(model).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return model, err
+}
+
+func (impl *RoleGroupClaimMappingRepositoryImpl) FindAllActive() ([]*RoleGroupClaimMapping, error) {
+	var models []*RoleGroupClaimMapping
+	err := impl.dbConnection.Model(&models).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting role group claim mappings", "err", err)
+		return nil, err
+	}
+	return models, nil
+}
+
+func (impl *RoleGroupClaimMappingRepositoryImpl) FindActiveByRoleGroupIdAndClaim(roleGroupId int32, connectorId, groupClaim string) (*RoleGroupClaimMapping, error) {
+	model := &RoleGroupClaimMapping{}
+	err := impl.dbConnection.Model(model).
+		Where("role_group_id = ?", roleGroupId).
+		Where("COALESCE(connector_id, '') = ?", connectorId).
+		Where("group_claim = ?", groupClaim).
+		Where("active = ?", true).
+		Select()
+	return model, err
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type TimeoutWindowConfigRepository interface {
+	CreateWithTxn(model *TimeoutWindowConfiguration, tx *pg.Tx) (*TimeoutWindowConfiguration, error)
+	GetByExpression(expression string, format userBean.TimeoutExpressionFormat, tx *pg.Tx) (*TimeoutWindowConfiguration, error)
+	GetByIds(ids []int) ([]*TimeoutWindowConfiguration, error)
+}
+
+type TimeoutWindowConfiguration struct {
+	TableName               struct{}                         `sql:"timeout_window_configuration" pg:",discard_unknown_columns"`
+	Id                      int                              `sql:"id,pk"`
+	TimeoutWindowExpression string                           `sql:"timeout_window_expression,notnull"`
+	ExpressionFormat        userBean.TimeoutExpressionFormat `sql:"timeout_window_expression_format,notnull"`
+	sql.AuditLog
+}
+
+type TimeoutWindowConfigRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewTimeoutWindowConfigRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *TimeoutWindowConfigRepositoryImpl {
+	return &TimeoutWindowConfigRepositoryImpl{dbConnection: dbConnection, logger: logger}
+}
+
+func (impl *TimeoutWindowConfigRepositoryImpl) CreateWithTxn(model *TimeoutWindowConfiguration, tx *pg.Tx) (*TimeoutWindowConfiguration, error) {
+	err := tx.Insert(model)
+	if err != nil {
+		impl.logger.Errorw("error in creating timeout window configuration", "model", model, "err", err)
+		return nil, err
+	}
+	return model, nil
+}
+
+// GetByExpression is used to share a window configuration between grants, configurations are never updated once created.
+// It reads within the transaction so that configurations created earlier in the same request are reused.
+func (impl *TimeoutWindowConfigRepositoryImpl) GetByExpression(expression string, format userBean.TimeoutExpressionFormat, tx *pg.Tx) (*TimeoutWindowConfiguration, error) {
+	model := &TimeoutWindowConfiguration{}
+	err := tx.Model(model).
+		Where("timeout_window_expression = ?", expression).
+		Where("timeout_window_expression_format = ?", format).
+		Order("id DESC").
+		Limit(1).
+		Select()
+	if err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+func (impl *TimeoutWindowConfigRepositoryImpl) GetByIds(ids []int) ([]*TimeoutWindowConfiguration, error) {
+	var models []*TimeoutWindowConfiguration
+	if len(ids) == 0 {
+		return models, nil
+	}
+	err := impl.dbConnection.Model(&models).
+		Where("id in (?)", pg.In(ids)).
+		Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting timeout window configurations", "ids", ids, "err", err)
+		return nil, err
+	}
+	return models, nil
+}
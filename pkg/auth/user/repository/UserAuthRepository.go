@@ -50,6 +50,8 @@ type UserAuthRepository interface {
 	GetRoleByFilterForAllTypes(roleFieldDto *bean4.RoleModelFieldsDto) (RoleModel, error)
 	CreateUserRoleMapping(userRoleModel *UserRoleModel, tx *pg.Tx) (*UserRoleModel, error)
 	GetUserRoleMappingByUserId(userId int32) ([]*UserRoleModel, error)
+	GetUserRoleMappingsWithTimeoutWindow() ([]*UserRoleModel, error)
+	UpdateUserRoleMapping(userRoleModel *UserRoleModel, tx *pg.Tx) (*UserRoleModel, error)
 	GetUserRoleMappingIdsByUserId(userId int32) ([]int, error)
 	GetUserRoleMappingIdsByUserIds(userIds []int32) ([]int, error)
 	DeleteUserRoleMapping(userRoleModel *UserRoleModel, tx *pg.Tx) (bool, error)
@@ -319,6 +321,28 @@ func (impl UserAuthRepositoryImpl) GetUserRoleMappingByUserId(userId int32) ([]*
 	return userRoleModels, nil
 }
 
+// GetUserRoleMappingsWithTimeoutWindow returns all the time bound user role mappings
+func (impl UserAuthRepositoryImpl) GetUserRoleMappingsWithTimeoutWindow() ([]*UserRoleModel, error) {
+	var userRoleModels []*UserRoleModel
+	err := impl.dbConnection.Model(&userRoleModels).
+		Where("timeout_window_configuration_id IS NOT NULL").
+		Select()
+	if err != nil {
+		impl.Logger.Errorw("error in GetUserRoleMappingsWithTimeoutWindow", "err", err)
+		return userRoleModels, err
+	}
+	return userRoleModels, nil
+}
+
+func (impl UserAuthRepositoryImpl) UpdateUserRoleMapping(userRoleModel *UserRoleModel, tx *pg.Tx) (*UserRoleModel, error) {
+	err := tx.Update(userRoleModel)
+	if err != nil {
+		impl.Logger.Errorw("error in UpdateUserRoleMapping", "userRoleModel", userRoleModel, "err", err)
+		return userRoleModel, err
+	}
+	return userRoleModel, nil
+}
+
 func (impl UserAuthRepositoryImpl) GetUserRoleMappingIdsByUserId(userId int32) ([]int, error) {
 	var Id []int
 	err := impl.dbConnection.Model().
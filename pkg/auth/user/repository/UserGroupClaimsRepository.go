@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// UserGroupClaimsRepository keeps the group claims received at the last login of a user. Claims are kept against the
+// email so that users logging in before being added to devtron get their memberships once they are created.
+type UserGroupClaimsRepository interface {
+	Save(model *UserGroupClaims) error
+	Update(model *UserGroupClaims) error
+	FindByEmailId(emailId string) (*UserGroupClaims, error)
+	FindAll() ([]*UserGroupClaims, error)
+}
+
+type UserGroupClaims struct {
+	TableName   struct{}  `sql:"user_group_claims" pg:",discard_unknown_columns"`
+	Id          int       `sql:"id,pk"`
+	EmailId     string    `sql:"email_id,notnull"`
+	ConnectorId string    `sql:"connector_id"`
+	GroupClaims []string  `sql:"group_claims" pg:",array"`
+	LastLoginOn time.Time `sql:"last_login_on,notnull"`
+	sql.AuditLog
+}
+
+type UserGroupClaimsRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewUserGroupClaimsRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *UserGroupClaimsRepositoryImpl {
+	return &UserGroupClaimsRepositoryImpl{dbConnection: dbConnection, logger: logger}
+}
+
+func (impl *UserGroupClaimsRepositoryImpl) Save(model *UserGroupClaims) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *UserGroupClaimsRepositoryImpl) Update(model *UserGroupClaims) error {
+	return impl.dbConnection.Update(model)
+}
+
+func (impl *UserGroupClaimsRepositoryImpl) FindByEmailId(emailId string) (*UserGroupClaims, error) {
+	model := &UserGroupClaims{}
+	err := impl.dbConnection.Model(model).
+		Where("email_id = ?", emailId).
+		Select()
+	return model, err
+}
+
+func (impl *UserGroupClaimsRepositoryImpl) FindAll() ([]*UserGroupClaims, error) {
+	var models []*UserGroupClaims
+	err := impl.dbConnection.Model(&models).Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting user group claims", "err", err)
+		return nil, err
+	}
+	return models, nil
+}
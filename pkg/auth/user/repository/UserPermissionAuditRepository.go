@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// UserPermissionAuditRepository is append only, audits are never updated or deleted
+type UserPermissionAuditRepository interface {
+	Save(model *UserPermissionAudit) error
+	SaveWithTx(model *UserPermissionAudit, tx *pg.Tx) error
+	// FindAll returns the audits ordered by latest first along with the total count, size 0 returns all
+	FindAll(query *UserPermissionAuditQuery) ([]*UserPermissionAudit, int, error)
+}
+
+type UserPermissionAudit struct {
+	TableName     struct{}                           `sql:"user_permission_audit" pg:",discard_unknown_columns"`
+	Id            int                                `sql:"id,pk"`
+	EntityType    userBean.PermissionAuditEntityType `sql:"entity_type,notnull"`
+	EntityId      int32                              `sql:"entity_id,notnull"`
+	EntityName    string                             `sql:"entity_name"`
+	Action        userBean.PermissionAuditAction     `sql:"action,notnull"`
+	PreviousState string                             `sql:"previous_state"`
+	CurrentState  string                             `sql:"current_state"`
+	sql.AuditLog
+}
+
+type UserPermissionAuditQuery struct {
+	EntityType userBean.PermissionAuditEntityType
+	EntityId   int32
+	ActorId    int32
+	From       *time.Time
+	To         *time.Time
+	Offset     int
+	Size       int
+}
+
+type UserPermissionAuditRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewUserPermissionAuditRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *UserPermissionAuditRepositoryImpl {
+	return &UserPermissionAuditRepositoryImpl{dbConnection: dbConnection, logger: logger}
+}
+
+func (impl *UserPermissionAuditRepositoryImpl) Save(model *UserPermissionAudit) error {
+	return impl.dbConnection.Insert(model)
+}
+
+func (impl *UserPermissionAuditRepositoryImpl) SaveWithTx(model *UserPermissionAudit, tx *pg.Tx) error {
+	return tx.Insert(model)
+}
+
+func (impl *UserPermissionAuditRepositoryImpl) FindAll(query *UserPermissionAuditQuery) ([]*UserPermissionAudit, int, error) {
+	var models []*UserPermissionAudit
+	dbQuery := impl.dbConnection.Model(&models)
+	if len(query.EntityType) > 0 {
+		dbQuery = dbQuery.Where("entity_type = ?", query.EntityType)
+	}
+	if query.EntityId > 0 {
+		dbQuery = dbQuery.Where("entity_id = ?", query.EntityId)
+	}
+	if query.ActorId > 0 {
+		dbQuery = dbQuery.Where("created_by = ?", query.ActorId)
+	}
+	if query.From != nil {
+		dbQuery = dbQuery.Where("created_on >= ?", *query.From)
+	}
+	if query.To != nil {
+		dbQuery = dbQuery.Where("created_on < ?", *query.To)
+	}
+	dbQuery = dbQuery.Order("id DESC")
+	if query.Size > 0 {
+		dbQuery = dbQuery.Offset(query.Offset).Limit(query.Size)
+	}
+	totalCount, err := dbQuery.SelectAndCount()
+	if err != nil {
+		impl.logger.Errorw("error in getting user permission audits", "query", query, "err", err)
+		return nil, 0, err
+	}
+	return models, totalCount, nil
+}
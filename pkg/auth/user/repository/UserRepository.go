@@ -26,6 +26,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/auth/user/util"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
 	"go.uber.org/zap"
 	"time"
 )
@@ -49,6 +50,7 @@ type UserRepository interface {
 	UpdateRoleIdForUserRolesMappings(roleId int, newRoleId int) (*UserRoleModel, error)
 	GetCountExecutingQuery(query string, queryParams []interface{}) (int, error)
 	CheckIfTokenExistsByTokenNameAndVersion(tokenName string, tokenVersion int) (bool, error)
+	UpdateApiTokenLastUsed(tokenName string, tokenVersion int, clientIp string) error
 }
 
 type UserRepositoryImpl struct {
@@ -73,11 +75,12 @@ type UserModel struct {
 }
 
 type UserRoleModel struct {
-	TableName struct{} `sql:"user_roles" pg:",discard_unknown_columns"`
-	Id        int      `sql:"id,pk"`
-	UserId    int32    `sql:"user_id,notnull"`
-	RoleId    int      `sql:"role_id,notnull"`
-	User      UserModel
+	TableName                    struct{} `sql:"user_roles" pg:",discard_unknown_columns"`
+	Id                           int      `sql:"id,pk"`
+	UserId                       int32    `sql:"user_id,notnull"`
+	RoleId                       int      `sql:"role_id,notnull"`
+	TimeoutWindowConfigurationId int      `sql:"timeout_window_configuration_id"`
+	User                         UserModel
 	sql.AuditLog
 }
 
@@ -270,11 +273,27 @@ func (impl UserRepositoryImpl) GetCountExecutingQuery(query string, queryParams
 // below method does operation on api_token table,
 // we are writing this method here instead of ApiTokenRepository to avoid cyclic import
 func (impl UserRepositoryImpl) CheckIfTokenExistsByTokenNameAndVersion(tokenName string, tokenVersion int) (bool, error) {
+	// the previous version of a rotated token stays valid till the end of the rotation overlap
 	query := impl.dbConnection.Model().
 		Table(userBean.ApiTokenTableName).
 		Where("name = ?", tokenName).
-		Where("version = ?", tokenVersion)
+		WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			q = q.WhereOr("version = ?", tokenVersion).
+				WhereOr("previous_version = ? AND previous_version_expire_at_in_ms > ?", tokenVersion, time.Now().UnixMilli())
+			return q, nil
+		})
 
 	exists, err := query.Exists()
 	return exists, err
 }
+
+func (impl UserRepositoryImpl) UpdateApiTokenLastUsed(tokenName string, tokenVersion int, clientIp string) error {
+	_, err := impl.dbConnection.Model().
+		Table(userBean.ApiTokenTableName).
+		Set("last_used_at = ?", time.Now()).
+		Set("last_used_by_ip = ?", clientIp).
+		Set("last_used_version = ?", tokenVersion).
+		Where("name = ?", tokenName).
+		Update()
+	return err
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// UserRoleGroupClaimMembershipRepository keeps the role group memberships which were added to casbin by the group
+// claims sync, memberships assigned manually are never tracked here and are left untouched by the sync
+type UserRoleGroupClaimMembershipRepository interface {
+	Save(model *UserRoleGroupClaimMembership, tx *pg.Tx) error
+	Update(model *UserRoleGroupClaimMembership, tx *pg.Tx) error
+	GetActiveByUserId(userId int32) ([]*UserRoleGroupClaimMembership, error)
+	GetAllActive() ([]*UserRoleGroupClaimMembership, error)
+	DeactivateByUserIds(userIds []int32, loggedInUserId int32, tx *pg.Tx) error
+}
+
+type UserRoleGroupClaimMembership struct {
+	TableName   struct{} `sql:"user_role_group_claim_membership" pg:",discard_unknown_columns"`
+	Id          int      `sql:"id,pk"`
+	UserId      int32    `sql:"user_id,notnull"`
+	RoleGroupId int32    `sql:"role_group_id,notnull"`
+	Active      bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type UserRoleGroupClaimMembershipRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewUserRoleGroupClaimMembershipRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *UserRoleGroupClaimMembershipRepositoryImpl {
+	return &UserRoleGroupClaimMembershipRepositoryImpl{dbConnection: dbConnection, logger: logger}
+}
+
+func (impl *UserRoleGroupClaimMembershipRepositoryImpl) Save(model *UserRoleGroupClaimMembership, tx *pg.Tx) error {
+	return tx.Insert(model)
+}
+
+func (impl *UserRoleGroupClaimMembershipRepositoryImpl) Update(model *UserRoleGroupClaimMembership, tx *pg.Tx) error {
+	return tx.Update(model)
+}
+
+func (impl *UserRoleGroupClaimMembershipRepositoryImpl) GetActiveByUserId(userId int32) ([]*UserRoleGroupClaimMembership, error) {
+	var models []*UserRoleGroupClaimMembership
+	err := impl.dbConnection.Model(&models).
+		Where("user_id = ?", userId).
+		Where("active = ?", true).
+		Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting role group claim memberships for user", "userId", userId, "err", err)
+		return nil, err
+	}
+	return models, nil
+}
+
+func (impl *UserRoleGroupClaimMembershipRepositoryImpl) GetAllActive() ([]*UserRoleGroupClaimMembership, error) {
+	var models []*UserRoleGroupClaimMembership
+	err := impl.dbConnection.Model(&models).
+		Where("active = ?", true).
+		Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting active role group claim memberships", "err", err)
+		return nil, err
+	}
+	return models, nil
+}
+
+func (impl *UserRoleGroupClaimMembershipRepositoryImpl) DeactivateByUserIds(userIds []int32, loggedInUserId int32, tx *pg.Tx) error {
+	if len(userIds) == 0 {
+		return nil
+	}
+	_, err := tx.Model(&UserRoleGroupClaimMembership{}).
+		Set("active = ?", false).
+		Set("updated_on = now()").
+		Set("updated_by = ?", loggedInUserId).
+		Where("user_id in (?)", pg.In(userIds)).
+		Where("active = ?", true).
+		Update()
+	if err != nil {
+		impl.logger.Errorw("error in deactivating role group claim memberships", "userIds", userIds, "err", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+// UserRoleGroupTimeoutWindowRepository keeps the time window of user to role group memberships, the membership itself
+// lives in casbin and only time bound memberships have an entry here
+type UserRoleGroupTimeoutWindowRepository interface {
+	Save(model *UserRoleGroupTimeoutWindow, tx *pg.Tx) error
+	Update(model *UserRoleGroupTimeoutWindow, tx *pg.Tx) error
+	GetActiveByUserId(userId int32) ([]*UserRoleGroupTimeoutWindow, error)
+	GetAllActive() ([]*UserRoleGroupTimeoutWindow, error)
+	DeactivateByUserIds(userIds []int32, loggedInUserId int32, tx *pg.Tx) error
+}
+
+type UserRoleGroupTimeoutWindow struct {
+	TableName                    struct{} `sql:"user_role_group_timeout_window" pg:",discard_unknown_columns"`
+	Id                           int      `sql:"id,pk"`
+	UserId                       int32    `sql:"user_id,notnull"`
+	RoleGroupId                  int32    `sql:"role_group_id,notnull"`
+	TimeoutWindowConfigurationId int      `sql:"timeout_window_configuration_id,notnull"`
+	Active                       bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type UserRoleGroupTimeoutWindowRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewUserRoleGroupTimeoutWindowRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *UserRoleGroupTimeoutWindowRepositoryImpl {
+	return &UserRoleGroupTimeoutWindowRepositoryImpl{dbConnection: dbConnection, logger: logger}
+}
+
+func (impl *UserRoleGroupTimeoutWindowRepositoryImpl) Save(model *UserRoleGroupTimeoutWindow, tx *pg.Tx) error {
+	return tx.Insert(model)
+}
+
+func (impl *UserRoleGroupTimeoutWindowRepositoryImpl) Update(model *UserRoleGroupTimeoutWindow, tx *pg.Tx) error {
+	return tx.Update(model)
+}
+
+func (impl *UserRoleGroupTimeoutWindowRepositoryImpl) GetActiveByUserId(userId int32) ([]*UserRoleGroupTimeoutWindow, error) {
+	var models []*UserRoleGroupTimeoutWindow
+	err := impl.dbConnection.Model(&models).
+		Where("user_id = ?", userId).
+		Where("active = ?", true).
+		Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting role group timeout windows for user", "userId", userId, "err", err)
+		return nil, err
+	}
+	return models, nil
+}
+
+func (impl *UserRoleGroupTimeoutWindowRepositoryImpl) GetAllActive() ([]*UserRoleGroupTimeoutWindow, error) {
+	var models []*UserRoleGroupTimeoutWindow
+	err := impl.dbConnection.Model(&models).
+		Where("active = ?", true).
+		Select()
+	if err != nil {
+		impl.logger.Errorw("error in getting active role group timeout windows", "err", err)
+		return nil, err
+	}
+	return models, nil
+}
+
+func (impl *UserRoleGroupTimeoutWindowRepositoryImpl) DeactivateByUserIds(userIds []int32, loggedInUserId int32, tx *pg.Tx) error {
+	if len(userIds) == 0 {
+		return nil
+	}
+	_, err := tx.Model(&UserRoleGroupTimeoutWindow{}).
+		Set("active = ?", false).
+		Set("updated_on = now()").
+		Set("updated_by = ?", loggedInUserId).
+		Where("user_id in (?)", pg.In(userIds)).
+		Where("active = ?", true).
+		Update()
+	if err != nil {
+		impl.logger.Errorw("error in deactivating role group timeout windows", "userIds", userIds, "err", err)
+		return err
+	}
+	return nil
+}
@@ -33,6 +33,8 @@ func GetUserRoleModelAdapter(userId, userLoggedInId int32, roleId int, twcConfig
 		UserId:   userId,
 		RoleId:   roleId,
 		AuditLog: sql.NewDefaultAuditLog(userLoggedInId),
+
+		TimeoutWindowConfigurationId: twcConfigDto.GetId(),
 	}
 }
 
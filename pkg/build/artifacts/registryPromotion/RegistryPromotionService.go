@@ -0,0 +1,296 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registryPromotion
+
+import (
+	"context"
+	"fmt"
+	"github.com/caarlos0/env/v6"
+	repository2 "github.com/devtron-labs/devtron/internal/sql/repository"
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/build/artifacts/registryPromotion/bean"
+	"github.com/devtron-labs/devtron/pkg/build/artifacts/registryPromotion/repository"
+	"github.com/devtron-labs/devtron/pkg/build/artifacts/retention/registry"
+	"github.com/devtron-labs/devtron/pkg/build/pipeline/read"
+	repository3 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type RegistryPromotionService interface {
+	GetEnvironmentConfig(environmentId int) (*bean.EnvironmentPromotionConfigDto, error)
+	SaveEnvironmentConfig(request *bean.EnvironmentPromotionConfigDto) (*bean.EnvironmentPromotionConfigDto, error)
+	DeleteEnvironmentConfig(environmentId int, userId int32) error
+	GetArtifactPromotions(ciArtifactId int) ([]*bean.ArtifactPromotionDto, error)
+	// PromoteArtifactForDeployment copies the artifact image into the target registry of the environment when the
+	// environment is flagged for promotion, nil is returned for other environments
+	PromoteArtifactForDeployment(ctx context.Context, artifact *repository2.CiArtifact, environment *repository3.Environment, ciPipelineId int, userId int32) (*bean.PromotedImage, error)
+}
+
+type RegistryPromotionServiceImpl struct {
+	logger                        *zap.SugaredLogger
+	registryPromotionRepository   repository.RegistryPromotionRepository
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository
+	environmentRepository         repository3.EnvironmentRepository
+	ciPipelineConfigReadService   read.CiPipelineConfigReadService
+	config                        *bean.RegistryPromotionConfig
+}
+
+func GetRegistryPromotionConfig() (*bean.RegistryPromotionConfig, error) {
+	config := &bean.RegistryPromotionConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		return nil, err
+	}
+	return config, err
+}
+
+func NewRegistryPromotionServiceImpl(logger *zap.SugaredLogger,
+	registryPromotionRepository repository.RegistryPromotionRepository,
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository,
+	environmentRepository repository3.EnvironmentRepository,
+	ciPipelineConfigReadService read.CiPipelineConfigReadService) (*RegistryPromotionServiceImpl, error) {
+	config, err := GetRegistryPromotionConfig()
+	if err != nil {
+		logger.Errorw("error in parsing registry promotion config", "err", err)
+		return nil, err
+	}
+	return &RegistryPromotionServiceImpl{
+		logger:                        logger,
+		registryPromotionRepository:   registryPromotionRepository,
+		dockerArtifactStoreRepository: dockerArtifactStoreRepository,
+		environmentRepository:         environmentRepository,
+		ciPipelineConfigReadService:   ciPipelineConfigReadService,
+		config:                        config,
+	}, nil
+}
+
+func (impl *RegistryPromotionServiceImpl) GetEnvironmentConfig(environmentId int) (*bean.EnvironmentPromotionConfigDto, error) {
+	config, err := impl.registryPromotionRepository.FindActiveConfigByEnvironmentId(environmentId)
+	if err == pg.ErrNoRows {
+		return nil, util.NewApiError(http.StatusNotFound, bean.PromotionConfigNotFound, bean.PromotionConfigNotFound)
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching promotion config of environment", "environmentId", environmentId, "err", err)
+		return nil, err
+	}
+	return toConfigDto(config), nil
+}
+
+func (impl *RegistryPromotionServiceImpl) SaveEnvironmentConfig(request *bean.EnvironmentPromotionConfigDto) (*bean.EnvironmentPromotionConfigDto, error) {
+	_, err := impl.dockerArtifactStoreRepository.FindOne(request.TargetRegistryId)
+	if err == pg.ErrNoRows {
+		errMsg := fmt.Sprintf(bean.TargetRegistryNotFound, request.TargetRegistryId)
+		return nil, util.NewApiError(http.StatusBadRequest, errMsg, errMsg)
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching target registry", "targetRegistryId", request.TargetRegistryId, "err", err)
+		return nil, err
+	}
+	config, err := impl.registryPromotionRepository.FindActiveConfigByEnvironmentId(request.EnvironmentId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching promotion config of environment", "environmentId", request.EnvironmentId, "err", err)
+		return nil, err
+	}
+	if err == pg.ErrNoRows {
+		config = &repository.EnvironmentPromotionConfig{
+			EnvironmentId: request.EnvironmentId,
+			Active:        true,
+			AuditLog:      sql.NewDefaultAuditLog(request.UserId),
+		}
+	} else {
+		config.UpdateAuditLog(request.UserId)
+	}
+	config.TargetRegistryId = request.TargetRegistryId
+	config.RepositoryPrefix = request.RepositoryPrefix
+	if config.Id == 0 {
+		err = impl.registryPromotionRepository.SaveConfig(config)
+	} else {
+		err = impl.registryPromotionRepository.UpdateConfig(config)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving promotion config of environment", "request", request, "err", err)
+		return nil, err
+	}
+	return toConfigDto(config), nil
+}
+
+func (impl *RegistryPromotionServiceImpl) DeleteEnvironmentConfig(environmentId int, userId int32) error {
+	config, err := impl.registryPromotionRepository.FindActiveConfigByEnvironmentId(environmentId)
+	if err == pg.ErrNoRows {
+		return util.NewApiError(http.StatusNotFound, bean.PromotionConfigNotFound, bean.PromotionConfigNotFound)
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching promotion config of environment", "environmentId", environmentId, "err", err)
+		return err
+	}
+	config.Active = false
+	config.UpdateAuditLog(userId)
+	err = impl.registryPromotionRepository.UpdateConfig(config)
+	if err != nil {
+		impl.logger.Errorw("error in deleting promotion config of environment", "environmentId", environmentId, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *RegistryPromotionServiceImpl) GetArtifactPromotions(ciArtifactId int) ([]*bean.ArtifactPromotionDto, error) {
+	promotions, err := impl.registryPromotionRepository.FindPromotionsByArtifactId(ciArtifactId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching promotions of artifact", "ciArtifactId", ciArtifactId, "err", err)
+		return nil, err
+	}
+	result := make([]*bean.ArtifactPromotionDto, 0, len(promotions))
+	for _, promotion := range promotions {
+		result = append(result, &bean.ArtifactPromotionDto{
+			CiArtifactId:     promotion.CiArtifactId,
+			EnvironmentId:    promotion.EnvironmentId,
+			TargetRegistryId: promotion.TargetRegistryId,
+			SourceImage:      promotion.SourceImage,
+			PromotedImage:    promotion.PromotedImage,
+			ImageDigest:      promotion.ImageDigest,
+			PromotedOn:       promotion.CreatedOn,
+		})
+	}
+	return result, nil
+}
+
+func (impl *RegistryPromotionServiceImpl) PromoteArtifactForDeployment(ctx context.Context, artifact *repository2.CiArtifact, environment *repository3.Environment, ciPipelineId int, userId int32) (*bean.PromotedImage, error) {
+	config, err := impl.registryPromotionRepository.FindActiveConfigByEnvironmentId(environment.Id)
+	if err == pg.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching promotion config of environment", "environmentId", environment.Id, "err", err)
+		return nil, err
+	}
+	targetStore, err := impl.dockerArtifactStoreRepository.FindOne(config.TargetRegistryId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching target registry of environment", "environmentId", environment.Id, "targetRegistryId", config.TargetRegistryId, "err", err)
+		if err == pg.ErrNoRows {
+			errMsg := fmt.Sprintf(bean.TargetRegistryNotFound, config.TargetRegistryId)
+			return nil, util.NewApiError(http.StatusPreconditionFailed, errMsg, errMsg)
+		}
+		return nil, err
+	}
+	sourceRef, err := registry.ParseImageRef(artifact.Image, artifact.ImageDigest)
+	if err != nil {
+		impl.logger.Errorw("error in parsing artifact image", "image", artifact.Image, "err", err)
+		return nil, err
+	}
+	targetRef := BuildPromotedImageRef(sourceRef, targetStore.RegistryURL, config.RepositoryPrefix)
+	if targetRef.Domain == sourceRef.Domain && targetRef.Repository == sourceRef.Repository {
+		// image is built into the target registry itself
+		return &bean.PromotedImage{Image: artifact.Image, RegistryId: targetStore.Id}, nil
+	}
+	promotedImage := promotedImageName(targetRef)
+	promotion, err := impl.registryPromotionRepository.FindPromotion(artifact.Id, targetStore.Id, promotedImage)
+	if err == nil {
+		return &bean.PromotedImage{Image: promotion.PromotedImage, RegistryId: promotion.TargetRegistryId}, nil
+	} else if err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching promotion of artifact", "ciArtifactId", artifact.Id, "targetRegistryId", targetStore.Id, "err", err)
+		return nil, err
+	}
+	sourceStore, err := impl.getSourceRegistry(artifact, sourceRef, ciPipelineId)
+	if err != nil {
+		return nil, err
+	}
+	imageDigest, err := impl.copyToTarget(ctx, sourceStore, sourceRef, targetStore, targetRef)
+	if err != nil {
+		impl.logger.Errorw("error in promoting artifact image", "image", artifact.Image, "promotedImage", promotedImage, "err", err)
+		errMsg := fmt.Sprintf(bean.PromotionFailed, artifact.Image, targetStore.Id, err.Error())
+		return nil, util.NewApiError(http.StatusInternalServerError, errMsg, errMsg)
+	}
+	promotion = &repository.CiArtifactPromotion{
+		CiArtifactId:     artifact.Id,
+		EnvironmentId:    environment.Id,
+		TargetRegistryId: targetStore.Id,
+		SourceImage:      artifact.Image,
+		PromotedImage:    promotedImage,
+		ImageDigest:      imageDigest,
+		AuditLog:         sql.NewDefaultAuditLog(userId),
+	}
+	err = impl.registryPromotionRepository.SavePromotion(promotion)
+	if err != nil {
+		// image is already in the target registry, a concurrent deployment may have recorded it first
+		impl.logger.Warnw("error in saving promotion of artifact", "ciArtifactId", artifact.Id, "promotedImage", promotedImage, "err", err)
+	}
+	impl.logger.Infow("artifact image promoted", "ciArtifactId", artifact.Id, "environmentId", environment.Id, "promotedImage", promotedImage)
+	return &bean.PromotedImage{Image: promotedImage, RegistryId: targetStore.Id}, nil
+}
+
+func (impl *RegistryPromotionServiceImpl) copyToTarget(ctx context.Context, sourceStore *dockerRegistryRepository.DockerArtifactStore, sourceRef *registry.ImageRef,
+	targetStore *dockerRegistryRepository.DockerArtifactStore, targetRef *registry.ImageRef) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(impl.config.CopyTimeoutSecs)*time.Second)
+	defer cancel()
+	source, err := newRemoteRepository(sourceStore, sourceRef)
+	if err != nil {
+		return "", err
+	}
+	target, err := newRemoteRepository(targetStore, targetRef)
+	if err != nil {
+		return "", err
+	}
+	if targetStore.RegistryType == dockerRegistryRepository.REGISTRYTYPE_ECR {
+		err = ensureEcrRepository(ctx, targetStore, targetRef.Repository)
+		if err != nil {
+			return "", err
+		}
+	}
+	return copyImage(ctx, source, sourceReference(sourceRef), target, targetRef.Tag)
+}
+
+// getSourceRegistry finds the registry the artifact was pushed to, from the ci pipeline when possible and otherwise by
+// the registry host of the image
+func (impl *RegistryPromotionServiceImpl) getSourceRegistry(artifact *repository2.CiArtifact, sourceRef *registry.ImageRef, ciPipelineId int) (*dockerRegistryRepository.DockerArtifactStore, error) {
+	if ciPipelineId > 0 {
+		dockerRegistryId, err := impl.ciPipelineConfigReadService.GetDockerRegistryIdForCiPipeline(ciPipelineId, artifact)
+		if err != nil {
+			impl.logger.Errorw("error in fetching registry of ci pipeline", "ciPipelineId", ciPipelineId, "err", err)
+			return nil, err
+		}
+		if dockerRegistryId != nil {
+			store, err := impl.dockerArtifactStoreRepository.FindOne(*dockerRegistryId)
+			if err == nil {
+				return store, nil
+			} else if err != pg.ErrNoRows {
+				impl.logger.Errorw("error in fetching registry of artifact", "dockerRegistryId", *dockerRegistryId, "err", err)
+				return nil, err
+			}
+		}
+	}
+	stores, err := impl.dockerArtifactStoreRepository.FindAll()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching container registries", "err", err)
+		return nil, err
+	}
+	for i := range stores {
+		if registry.GetRegistryHost(stores[i].RegistryURL) == sourceRef.Domain {
+			return &stores[i], nil
+		}
+	}
+	errMsg := fmt.Sprintf(bean.SourceRegistryNotFound, artifact.Image)
+	return nil, util.NewApiError(http.StatusPreconditionFailed, errMsg, errMsg)
+}
+
+func toConfigDto(config *repository.EnvironmentPromotionConfig) *bean.EnvironmentPromotionConfigDto {
+	return &bean.EnvironmentPromotionConfigDto{
+		EnvironmentId:    config.EnvironmentId,
+		TargetRegistryId: config.TargetRegistryId,
+		RepositoryPrefix: config.RepositoryPrefix,
+	}
+}
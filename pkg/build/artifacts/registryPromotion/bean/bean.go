@@ -0,0 +1,57 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+const (
+	EnvironmentNotFound     = "environment %d not found"
+	TargetRegistryNotFound  = "target container registry %s not found"
+	PromotionConfigNotFound = "artifact promotion is not configured for this environment"
+	SourceRegistryNotFound  = "container registry of the artifact could not be found, image %s can not be promoted"
+	PromotionFailed         = "promotion of image %s to registry %s failed: %s"
+)
+
+// EnvironmentPromotionConfigDto flags an environment for artifact promotion, images deployed to it are copied into the
+// target registry first and deployed from there
+type EnvironmentPromotionConfigDto struct {
+	EnvironmentId    int    `json:"environmentId" validate:"required,number,gt=0"`
+	TargetRegistryId string `json:"targetRegistryId" validate:"required"`
+	// RepositoryPrefix is prepended to the repository path of the source image, e.g. prod/team/app for team/app
+	RepositoryPrefix string `json:"repositoryPrefix,omitempty" validate:"omitempty,max=200"`
+	UserId           int32  `json:"-"`
+}
+
+type ArtifactPromotionDto struct {
+	CiArtifactId     int       `json:"ciArtifactId"`
+	EnvironmentId    int       `json:"environmentId"`
+	TargetRegistryId string    `json:"targetRegistryId"`
+	SourceImage      string    `json:"sourceImage"`
+	PromotedImage    string    `json:"promotedImage"`
+	ImageDigest      string    `json:"imageDigest"`
+	PromotedOn       time.Time `json:"promotedOn"`
+}
+
+// PromotedImage is the reference deployed in place of the artifact image
+type PromotedImage struct {
+	Image      string
+	RegistryId string
+}
+
+type RegistryPromotionConfig struct {
+	CopyTimeoutSecs int `env:"ARTIFACT_PROMOTION_COPY_TIMEOUT_SECS" envDefault:"600"`
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registryPromotion
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/build/artifacts/retention/registry"
+	"path"
+	"strings"
+)
+
+// BuildPromotedImageRef keeps the repository path and tag of the source image under the host of the target registry,
+// repositoryPrefix is put in front of the path. Images without a tag are tagged after their digest
+func BuildPromotedImageRef(source *registry.ImageRef, targetRegistryUrl, repositoryPrefix string) *registry.ImageRef {
+	tag := source.Tag
+	if len(tag) == 0 {
+		tag = strings.Replace(source.Digest, ":", "-", 1)
+	}
+	repository := source.Repository
+	if prefix := strings.Trim(repositoryPrefix, "/"); len(prefix) > 0 {
+		repository = path.Join(prefix, repository)
+	}
+	return &registry.ImageRef{
+		Domain:     registry.GetRegistryHost(targetRegistryUrl),
+		Repository: repository,
+		Tag:        tag,
+	}
+}
+
+// sourceReference prefers the digest so that the copied content is exactly the built image
+func sourceReference(source *registry.ImageRef) string {
+	if len(source.Digest) > 0 {
+		return source.Digest
+	}
+	return source.Tag
+}
+
+func promotedImageName(image *registry.ImageRef) string {
+	return fmt.Sprintf("%s/%s:%s", image.Domain, image.Repository, image.Tag)
+}
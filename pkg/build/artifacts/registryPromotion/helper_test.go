@@ -0,0 +1,72 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registryPromotion
+
+import (
+	"github.com/devtron-labs/devtron/pkg/build/artifacts/retention/registry"
+	"testing"
+)
+
+func TestBuildPromotedImageRef(t *testing.T) {
+	tests := []struct {
+		name             string
+		sourceImage      string
+		sourceDigest     string
+		targetUrl        string
+		repositoryPrefix string
+		want             string
+	}{
+		{
+			name:        "keeps repository and tag",
+			sourceImage: "dev.registry.io/team/app:abc123",
+			targetUrl:   "https://prod.registry.io/",
+			want:        "prod.registry.io/team/app:abc123",
+		},
+		{
+			name:             "prefix is put before the repository",
+			sourceImage:      "dev.registry.io/team/app:abc123",
+			targetUrl:        "prod.registry.io",
+			repositoryPrefix: "/prod/",
+			want:             "prod.registry.io/prod/team/app:abc123",
+		},
+		{
+			name:         "digest only image is tagged after the digest",
+			sourceImage:  "dev.registry.io/team/app@sha256:0123456789012345678901234567890123456789012345678901234567890123",
+			sourceDigest: "",
+			targetUrl:    "prod.registry.io:5000",
+			want:         "prod.registry.io:5000/team/app:sha256-0123456789012345678901234567890123456789012345678901234567890123",
+		},
+		{
+			name:        "docker hub target",
+			sourceImage: "dev.registry.io/org/app:v1",
+			targetUrl:   "https://index.docker.io",
+			want:        "docker.io/org/app:v1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := registry.ParseImageRef(tt.sourceImage, tt.sourceDigest)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			got := promotedImageName(BuildPromotedImageRef(source, tt.targetUrl, tt.repositoryPrefix))
+			if got != tt.want {
+				t.Errorf("BuildPromotedImageRef() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registryPromotion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/devtron-labs/devtron/pkg/build/artifacts/retention/registry"
+	"github.com/devtron-labs/devtron/pkg/dockerRegistry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"strings"
+)
+
+// newRemoteRepository opens the repository of image in the registry of store with the credentials of the store
+func newRemoteRepository(store *dockerRegistryRepository.DockerArtifactStore, image *registry.ImageRef) (*remote.Repository, error) {
+	repo, err := remote.NewRepository(fmt.Sprintf("%s/%s", image.Domain, image.Repository))
+	if err != nil {
+		return nil, err
+	}
+	httpClient, err := registry.NewHttpClient(store.Connection, store.Cert, 0)
+	if err != nil {
+		return nil, err
+	}
+	username, password, err := getRegistryCredential(store)
+	if err != nil {
+		return nil, err
+	}
+	client := &auth.Client{
+		Client: httpClient,
+		Cache:  auth.NewCache(),
+	}
+	if len(username) > 0 || len(password) > 0 {
+		client.Credential = auth.StaticCredential(image.Domain, auth.Credential{Username: username, Password: password})
+	}
+	repo.Client = client
+	repo.PlainHTTP = strings.HasPrefix(store.RegistryURL, "http://")
+	return repo, nil
+}
+
+func getRegistryCredential(store *dockerRegistryRepository.DockerArtifactStore) (string, string, error) {
+	if store.RegistryType == dockerRegistryRepository.REGISTRYTYPE_ECR {
+		return dockerRegistry.CreateCredentialForEcr(store.AWSRegion, store.AWSAccessKeyId, store.AWSSecretAccessKey)
+	}
+	return store.Username, store.Password, nil
+}
+
+// ensureEcrRepository creates the target repository as ecr does not create repositories on push
+func ensureEcrRepository(ctx context.Context, store *dockerRegistryRepository.DockerArtifactStore, repository string) error {
+	config := &aws.Config{Region: aws.String(store.AWSRegion)}
+	if len(store.AWSAccessKeyId) > 0 && len(store.AWSSecretAccessKey) > 0 {
+		config.Credentials = credentials.NewStaticCredentials(store.AWSAccessKeyId, store.AWSSecretAccessKey, "")
+	}
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return err
+	}
+	_, err = ecr.New(sess).CreateRepositoryWithContext(ctx, &ecr.CreateRepositoryInput{RepositoryName: aws.String(repository)})
+	var awsErr awserr.Error
+	if err != nil && errors.As(err, &awsErr) && awsErr.Code() == ecr.ErrCodeRepositoryAlreadyExistsException {
+		return nil
+	}
+	return err
+}
+
+// copyImage copies the manifest of source along with everything it references into target and tags it there,
+// content already present in target is not transferred again. The digest of the copied manifest is returned
+func copyImage(ctx context.Context, source *remote.Repository, sourceReference string, target *remote.Repository, targetTag string) (string, error) {
+	root, err := source.Resolve(ctx, sourceReference)
+	if err != nil {
+		return "", err
+	}
+	err = copyNode(ctx, source, target, root)
+	if err != nil {
+		return "", err
+	}
+	err = target.Tag(ctx, root, targetTag)
+	if err != nil {
+		return "", err
+	}
+	return root.Digest.String(), nil
+}
+
+func copyNode(ctx context.Context, source, target *remote.Repository, node ocispec.Descriptor) error {
+	exists, err := target.Exists(ctx, node)
+	if err != nil {
+		return err
+	} else if exists {
+		return nil
+	}
+	successors, err := content.Successors(ctx, source, node)
+	if err != nil {
+		return err
+	}
+	for _, successor := range successors {
+		err = copyNode(ctx, source, target, successor)
+		if err != nil {
+			return err
+		}
+	}
+	reader, err := source.Fetch(ctx, node)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return target.Push(ctx, node, reader)
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+)
+
+type EnvironmentPromotionConfig struct {
+	tableName        struct{} `sql:"environment_promotion_config" pg:",discard_unknown_columns"`
+	Id               int      `sql:"id,pk"`
+	EnvironmentId    int      `sql:"environment_id,notnull"`
+	TargetRegistryId string   `sql:"target_registry_id,notnull"`
+	RepositoryPrefix string   `sql:"repository_prefix"`
+	Active           bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+// CiArtifactPromotion is the reference of an artifact image copied into a target registry, it is reused by every
+// environment promoting to the same registry
+type CiArtifactPromotion struct {
+	tableName        struct{} `sql:"ci_artifact_promotion" pg:",discard_unknown_columns"`
+	Id               int      `sql:"id,pk"`
+	CiArtifactId     int      `sql:"ci_artifact_id,notnull"`
+	EnvironmentId    int      `sql:"environment_id,notnull"`
+	TargetRegistryId string   `sql:"target_registry_id,notnull"`
+	SourceImage      string   `sql:"source_image,notnull"`
+	PromotedImage    string   `sql:"promoted_image,notnull"`
+	ImageDigest      string   `sql:"image_digest,notnull"`
+	sql.AuditLog
+}
+
+type RegistryPromotionRepository interface {
+	SaveConfig(config *EnvironmentPromotionConfig) error
+	UpdateConfig(config *EnvironmentPromotionConfig) error
+	FindActiveConfigByEnvironmentId(environmentId int) (*EnvironmentPromotionConfig, error)
+	SavePromotion(promotion *CiArtifactPromotion) error
+	FindPromotion(ciArtifactId int, targetRegistryId string, promotedImage string) (*CiArtifactPromotion, error)
+	FindPromotionsByArtifactId(ciArtifactId int) ([]*CiArtifactPromotion, error)
+}
+
+type RegistryPromotionRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewRegistryPromotionRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *RegistryPromotionRepositoryImpl {
+	return &RegistryPromotionRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (repo *RegistryPromotionRepositoryImpl) SaveConfig(config *EnvironmentPromotionConfig) error {
+	return repo.dbConnection.Insert(config)
+}
+
+func (repo *RegistryPromotionRepositoryImpl) UpdateConfig(config *EnvironmentPromotionConfig) error {
+	return repo.dbConnection.Update(config)
+}
+
+func (repo *RegistryPromotionRepositoryImpl) FindActiveConfigByEnvironmentId(environmentId int) (*EnvironmentPromotionConfig, error) {
+	config := &EnvironmentPromotionConfig{}
+	err := repo.dbConnection.Model(config).
+		Where("environment_id = ?", environmentId).
+		Where("active = ?", true).
+		Limit(1).
+		Select()
+	return config, err
+}
+
+func (repo *RegistryPromotionRepositoryImpl) SavePromotion(promotion *CiArtifactPromotion) error {
+	return repo.dbConnection.Insert(promotion)
+}
+
+func (repo *RegistryPromotionRepositoryImpl) FindPromotion(ciArtifactId int, targetRegistryId string, promotedImage string) (*CiArtifactPromotion, error) {
+	promotion := &CiArtifactPromotion{}
+	err := repo.dbConnection.Model(promotion).
+		Where("ci_artifact_id = ?", ciArtifactId).
+		Where("target_registry_id = ?", targetRegistryId).
+		Where("promoted_image = ?", promotedImage).
+		Limit(1).
+		Select()
+	return promotion, err
+}
+
+func (repo *RegistryPromotionRepositoryImpl) FindPromotionsByArtifactId(ciArtifactId int) ([]*CiArtifactPromotion, error) {
+	var promotions []*CiArtifactPromotion
+	err := repo.dbConnection.Model(&promotions).
+		Where("ci_artifact_id = ?", ciArtifactId).
+		Order("id").
+		Select()
+	return promotions, err
+}
@@ -0,0 +1,481 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/caarlos0/env/v6"
+	repository2 "github.com/devtron-labs/devtron/internal/sql/repository"
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/devtron-labs/devtron/pkg/build/artifacts/retention/bean"
+	"github.com/devtron-labs/devtron/pkg/build/artifacts/retention/registry"
+	"github.com/devtron-labs/devtron/pkg/build/artifacts/retention/repository"
+	"github.com/devtron-labs/devtron/pkg/pipeline"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	"github.com/go-pg/pg"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+type ImageRetentionService interface {
+	GetAllPolicies() ([]*bean.ImageRetentionPolicyDto, error)
+	GetPolicy(id int) (*bean.ImageRetentionPolicyDto, error)
+	CreatePolicy(request *bean.ImageRetentionPolicyDto) (*bean.ImageRetentionPolicyDto, error)
+	UpdatePolicy(request *bean.ImageRetentionPolicyDto) (*bean.ImageRetentionPolicyDto, error)
+	DeletePolicy(id int, userId int32) error
+	// RunPolicy computes the artifacts deletable by the policy, unless it is a dry run their images are deleted from the
+	// registry and the artifacts are marked as purged
+	RunPolicy(ctx context.Context, id int, dryRun bool, userId int32) (*bean.PurgeReport, error)
+	// PurgeAllPolicies runs every policy with auto purge enabled
+	PurgeAllPolicies()
+}
+
+type ImageRetentionServiceImpl struct {
+	logger                        *zap.SugaredLogger
+	imageRetentionRepository      repository.ImageRetentionRepository
+	ciPipelineRepository          pipelineConfig.CiPipelineRepository
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository
+	customTagService              pipeline.CustomTagService
+	config                        *bean.ImageRetentionConfig
+	retentionCron                 *cron.Cron
+}
+
+func GetImageRetentionConfig() (*bean.ImageRetentionConfig, error) {
+	config := &bean.ImageRetentionConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		return nil, err
+	}
+	return config, err
+}
+
+func NewImageRetentionServiceImpl(logger *zap.SugaredLogger,
+	imageRetentionRepository repository.ImageRetentionRepository,
+	ciPipelineRepository pipelineConfig.CiPipelineRepository,
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository,
+	customTagService pipeline.CustomTagService,
+	cronLogger *cron2.CronLoggerImpl) (*ImageRetentionServiceImpl, error) {
+	config, err := GetImageRetentionConfig()
+	if err != nil {
+		logger.Errorw("error in parsing image retention config", "err", err)
+		return nil, err
+	}
+	retentionCron := cron.New(cron.WithChain(cron.SkipIfStillRunning(cronLogger), cron.Recover(cronLogger)))
+	impl := &ImageRetentionServiceImpl{
+		logger:                        logger,
+		imageRetentionRepository:      imageRetentionRepository,
+		ciPipelineRepository:          ciPipelineRepository,
+		dockerArtifactStoreRepository: dockerArtifactStoreRepository,
+		customTagService:              customTagService,
+		config:                        config,
+		retentionCron:                 retentionCron,
+	}
+	if config.Enabled {
+		retentionCron.Start()
+		_, err = retentionCron.AddFunc(fmt.Sprintf("@every %ds", config.CronIntervalSecs), impl.PurgeAllPolicies)
+		if err != nil {
+			logger.Errorw("error in starting image retention cron", "err", err)
+			return nil, err
+		}
+	}
+	return impl, nil
+}
+
+func (impl *ImageRetentionServiceImpl) GetAllPolicies() ([]*bean.ImageRetentionPolicyDto, error) {
+	policies, err := impl.imageRetentionRepository.FindAllActivePolicies()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching image retention policies", "err", err)
+		return nil, err
+	}
+	result := make([]*bean.ImageRetentionPolicyDto, 0, len(policies))
+	for _, policy := range policies {
+		result = append(result, toPolicyDto(policy))
+	}
+	return result, nil
+}
+
+func (impl *ImageRetentionServiceImpl) GetPolicy(id int) (*bean.ImageRetentionPolicyDto, error) {
+	policy, err := impl.getActivePolicy(id)
+	if err != nil {
+		return nil, err
+	}
+	return toPolicyDto(policy), nil
+}
+
+func (impl *ImageRetentionServiceImpl) CreatePolicy(request *bean.ImageRetentionPolicyDto) (*bean.ImageRetentionPolicyDto, error) {
+	err := impl.validateScope(request)
+	if err != nil {
+		return nil, err
+	}
+	policy := &repository.ImageRetentionPolicy{Active: true, AuditLog: sql.NewDefaultAuditLog(request.UserId)}
+	updatePolicyModel(policy, request)
+	err = impl.imageRetentionRepository.SavePolicy(policy)
+	if err != nil {
+		impl.logger.Errorw("error in saving image retention policy", "request", request, "err", err)
+		return nil, err
+	}
+	return toPolicyDto(policy), nil
+}
+
+func (impl *ImageRetentionServiceImpl) UpdatePolicy(request *bean.ImageRetentionPolicyDto) (*bean.ImageRetentionPolicyDto, error) {
+	policy, err := impl.getActivePolicy(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.validateScope(request)
+	if err != nil {
+		return nil, err
+	}
+	updatePolicyModel(policy, request)
+	policy.UpdateAuditLog(request.UserId)
+	err = impl.imageRetentionRepository.UpdatePolicy(policy)
+	if err != nil {
+		impl.logger.Errorw("error in updating image retention policy", "request", request, "err", err)
+		return nil, err
+	}
+	return toPolicyDto(policy), nil
+}
+
+func (impl *ImageRetentionServiceImpl) DeletePolicy(id int, userId int32) error {
+	policy, err := impl.getActivePolicy(id)
+	if err != nil {
+		return err
+	}
+	policy.Active = false
+	policy.UpdateAuditLog(userId)
+	err = impl.imageRetentionRepository.UpdatePolicy(policy)
+	if err != nil {
+		impl.logger.Errorw("error in deleting image retention policy", "id", id, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *ImageRetentionServiceImpl) RunPolicy(ctx context.Context, id int, dryRun bool, userId int32) (*bean.PurgeReport, error) {
+	policy, err := impl.getActivePolicy(id)
+	if err != nil {
+		return nil, err
+	}
+	var scopeStore *dockerRegistryRepository.DockerArtifactStore
+	var candidates []*repository2.CiArtifact
+	if bean.PolicyScope(policy.Scope) == bean.PolicyScopeRegistry {
+		scopeStore, err = impl.dockerArtifactStoreRepository.FindOne(policy.DockerRegistryId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching container registry of image retention policy", "policyId", id, "err", err)
+			return nil, err
+		}
+		candidates, err = impl.imageRetentionRepository.FindUnpurgedArtifactsByRegistry(scopeStore.Id, registry.GetRegistryHost(scopeStore.RegistryURL))
+	} else {
+		candidates, err = impl.imageRetentionRepository.FindUnpurgedArtifactsByCiPipelineId(policy.CiPipelineId)
+	}
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching artifacts of image retention policy", "policyId", id, "err", err)
+		return nil, err
+	}
+	items, err := impl.evaluatePolicy(policy, candidates)
+	if err != nil {
+		return nil, err
+	}
+	if !dryRun {
+		impl.purgeImages(ctx, policy, scopeStore, candidates, items, userId)
+		now := time.Now()
+		policy.LastPurgedOn = &now
+		policy.UpdateAuditLog(userId)
+		err = impl.imageRetentionRepository.UpdatePolicy(policy)
+		if err != nil {
+			impl.logger.Errorw("error in updating last purge time of image retention policy", "policyId", id, "err", err)
+		}
+	}
+	return buildPurgeReport(policy.Id, dryRun, items), nil
+}
+
+func (impl *ImageRetentionServiceImpl) PurgeAllPolicies() {
+	policies, err := impl.imageRetentionRepository.FindAllActivePolicies()
+	if err != nil {
+		impl.logger.Errorw("error in fetching image retention policies", "err", err)
+		return
+	}
+	for _, policy := range policies {
+		if !policy.AutoPurge {
+			continue
+		}
+		report, err := impl.RunPolicy(context.Background(), policy.Id, false, userBean.SystemUserId)
+		if err != nil {
+			impl.logger.Errorw("error in running image retention policy", "policyId", policy.Id, "err", err)
+			continue
+		}
+		impl.logger.Infow("image retention policy run", "policyId", policy.Id, "purged", report.PurgedCount, "failed", report.FailedCount)
+	}
+}
+
+func (impl *ImageRetentionServiceImpl) evaluatePolicy(policy *repository.ImageRetentionPolicy, candidates []*repository2.CiArtifact) ([]*bean.ArtifactReportItem, error) {
+	var images, digests []string
+	for _, artifact := range candidates {
+		images = append(images, artifact.Image)
+		if IsContentDigest(artifact.ImageDigest) {
+			digests = append(digests, artifact.ImageDigest)
+		}
+	}
+	related, err := impl.imageRetentionRepository.FindArtifactsByImagesOrDigests(images, digests)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching artifacts sharing images", "policyId", policy.Id, "err", err)
+		return nil, err
+	}
+	artifactIds := make([]int, 0, len(candidates)+len(related))
+	for _, artifact := range append(append([]*repository2.CiArtifact{}, candidates...), related...) {
+		artifactIds = append(artifactIds, artifact.Id)
+	}
+	deployments, err := impl.imageRetentionRepository.FindDeploymentsByArtifactIds(artifactIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching deployments of artifacts", "policyId", policy.Id, "err", err)
+		return nil, err
+	}
+	currentlyDeployedIds, err := impl.imageRetentionRepository.FindCurrentlyDeployedArtifactIds(artifactIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching currently deployed artifacts", "policyId", policy.Id, "err", err)
+		return nil, err
+	}
+	return EvaluateRetention(policy, candidates, related, deployments, currentlyDeployedIds, time.Now()), nil
+}
+
+// purgeImages deletes the images of the items to delete, at most MaxDeletionsPerRun images are deleted in a run and
+// the rest are retained for the next run
+func (impl *ImageRetentionServiceImpl) purgeImages(ctx context.Context, policy *repository.ImageRetentionPolicy,
+	scopeStore *dockerRegistryRepository.DockerArtifactStore, candidates []*repository2.CiArtifact, items []*bean.ArtifactReportItem, userId int32) {
+	artifactById := make(map[int]*repository2.CiArtifact, len(candidates))
+	for _, artifact := range candidates {
+		artifactById[artifact.Id] = artifact
+	}
+	var stores []*dockerRegistryRepository.DockerArtifactStore
+	if scopeStore == nil {
+		allStores, err := impl.dockerArtifactStoreRepository.FindAll()
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching container registries", "policyId", policy.Id, "err", err)
+			return
+		}
+		for i := range allStores {
+			stores = append(stores, &allStores[i])
+		}
+	}
+	clients := make(map[string]registry.RegistryClient)
+	deleted := 0
+	for _, item := range items {
+		if item.Action != bean.ArtifactActionDelete {
+			continue
+		}
+		if deleted >= impl.config.MaxDeletionsPerRun {
+			item.Action = bean.ArtifactActionRetain
+			item.Reasons = append(item.Reasons, bean.RetainReasonRunLimit)
+			continue
+		}
+		deleted++
+		artifact := artifactById[item.CiArtifactId]
+		store := scopeStore
+		if store == nil {
+			store = findRegistryStore(artifact, stores)
+		}
+		if store == nil {
+			item.Action, item.Message = bean.ArtifactActionFailed, bean.ImageRegistryNotFound
+			continue
+		}
+		client, ok := clients[store.Id]
+		if !ok {
+			var err error
+			client, err = registry.NewRegistryClient(store, time.Duration(impl.config.RegistryTimeoutSecs)*time.Second)
+			if err != nil {
+				impl.logger.Errorw("error in creating registry client", "registryId", store.Id, "err", err)
+			}
+			clients[store.Id] = client
+		}
+		if client == nil {
+			item.Action, item.Message = bean.ArtifactActionFailed, fmt.Sprintf(bean.UnsupportedRegistry, store.RegistryType)
+			continue
+		}
+		err := impl.purgeImage(ctx, client, policy.Id, artifact, userId)
+		if err != nil && !errors.Is(err, registry.ErrImageNotFound) {
+			impl.logger.Errorw("error in purging image", "policyId", policy.Id, "image", artifact.Image, "err", err)
+			item.Action, item.Message = bean.ArtifactActionFailed, err.Error()
+			continue
+		}
+		item.Action = bean.ArtifactActionPurged
+		if err != nil {
+			item.Message = bean.ImageNotFoundInRegistry
+		}
+	}
+}
+
+// purgeImage marks the artifact purged even when the image was already gone from the registry, the image not found
+// error is still returned to be reported
+func (impl *ImageRetentionServiceImpl) purgeImage(ctx context.Context, client registry.RegistryClient, policyId int, artifact *repository2.CiArtifact, userId int32) error {
+	imageRef, err := registry.ParseImageRef(artifact.Image, artifact.ImageDigest)
+	if err != nil {
+		return err
+	}
+	deleteErr := client.DeleteImage(ctx, imageRef)
+	if deleteErr != nil && !errors.Is(deleteErr, registry.ErrImageNotFound) {
+		return deleteErr
+	}
+	purge := &repository.CiArtifactPurge{
+		CiArtifactId: artifact.Id,
+		PolicyId:     policyId,
+		Image:        artifact.Image,
+		ImageDigest:  artifact.ImageDigest,
+		AuditLog:     sql.NewDefaultAuditLog(userId),
+	}
+	err = impl.imageRetentionRepository.SavePurge(purge)
+	if err != nil {
+		return err
+	}
+	// the image path can be reserved again by custom tags once the image is gone
+	err = impl.customTagService.DeactivateImagePathReservationByImagePath([]string{artifact.Image})
+	if err != nil {
+		impl.logger.Errorw("error in releasing image path reservation", "image", artifact.Image, "err", err)
+	}
+	return deleteErr
+}
+
+func (impl *ImageRetentionServiceImpl) getActivePolicy(id int) (*repository.ImageRetentionPolicy, error) {
+	policy, err := impl.imageRetentionRepository.FindActivePolicyById(id)
+	if err == pg.ErrNoRows {
+		return nil, util.NewApiError(http.StatusNotFound, bean.PolicyNotFound, bean.PolicyNotFound)
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching image retention policy", "id", id, "err", err)
+		return nil, err
+	}
+	return policy, nil
+}
+
+// validateScope checks the scoped ci pipeline or registry exists and has no other policy
+func (impl *ImageRetentionServiceImpl) validateScope(request *bean.ImageRetentionPolicyDto) error {
+	var existing *repository.ImageRetentionPolicy
+	var err error
+	switch request.Scope {
+	case bean.PolicyScopeCiPipeline:
+		if request.CiPipelineId <= 0 {
+			return util.NewApiError(http.StatusBadRequest, bean.CiPipelineIdRequired, bean.CiPipelineIdRequired)
+		}
+		_, err = impl.ciPipelineRepository.FindById(request.CiPipelineId)
+		if err == pg.ErrNoRows {
+			message := fmt.Sprintf(bean.CiPipelineNotFound, request.CiPipelineId)
+			return util.NewApiError(http.StatusBadRequest, message, message)
+		} else if err != nil {
+			return err
+		}
+		existing, err = impl.imageRetentionRepository.FindActivePolicyByCiPipelineId(request.CiPipelineId)
+	case bean.PolicyScopeRegistry:
+		if len(request.DockerRegistryId) == 0 {
+			return util.NewApiError(http.StatusBadRequest, bean.RegistryIdRequired, bean.RegistryIdRequired)
+		}
+		_, err = impl.dockerArtifactStoreRepository.FindOne(request.DockerRegistryId)
+		if err == pg.ErrNoRows {
+			message := fmt.Sprintf(bean.RegistryNotFound, request.DockerRegistryId)
+			return util.NewApiError(http.StatusBadRequest, message, message)
+		} else if err != nil {
+			return err
+		}
+		existing, err = impl.imageRetentionRepository.FindActivePolicyByRegistryId(request.DockerRegistryId)
+	}
+	if err != nil && err != pg.ErrNoRows {
+		return err
+	}
+	if existing != nil && existing.Id > 0 && existing.Id != request.Id {
+		message := fmt.Sprintf(bean.PolicyAlreadyExists, request.Scope)
+		return util.NewApiError(http.StatusConflict, message, message)
+	}
+	return nil
+}
+
+func findRegistryStore(artifact *repository2.CiArtifact, stores []*dockerRegistryRepository.DockerArtifactStore) *dockerRegistryRepository.DockerArtifactStore {
+	if artifact.IsRegistryCredentialMapped() {
+		for _, store := range stores {
+			if store.Id == artifact.CredentialSourceValue {
+				return store
+			}
+		}
+	}
+	imageRef, err := registry.ParseImageRef(artifact.Image, artifact.ImageDigest)
+	if err != nil {
+		return nil
+	}
+	for _, store := range stores {
+		if registry.GetRegistryHost(store.RegistryURL) == imageRef.Domain {
+			return store
+		}
+	}
+	return nil
+}
+
+func updatePolicyModel(policy *repository.ImageRetentionPolicy, request *bean.ImageRetentionPolicyDto) {
+	policy.Name = request.Name
+	policy.Scope = string(request.Scope)
+	policy.CiPipelineId, policy.DockerRegistryId = 0, ""
+	if request.Scope == bean.PolicyScopeCiPipeline {
+		policy.CiPipelineId = request.CiPipelineId
+	} else {
+		policy.DockerRegistryId = request.DockerRegistryId
+	}
+	policy.KeepLastN = request.KeepLastN
+	policy.KeepDeployedWithinDays = request.KeepDeployedWithinDays
+	policy.KeepEverDeployedToProd = request.KeepEverDeployedToProd
+	policy.AutoPurge = request.AutoPurge
+}
+
+func toPolicyDto(policy *repository.ImageRetentionPolicy) *bean.ImageRetentionPolicyDto {
+	return &bean.ImageRetentionPolicyDto{
+		Id:                     policy.Id,
+		Name:                   policy.Name,
+		Scope:                  bean.PolicyScope(policy.Scope),
+		CiPipelineId:           policy.CiPipelineId,
+		DockerRegistryId:       policy.DockerRegistryId,
+		KeepLastN:              policy.KeepLastN,
+		KeepDeployedWithinDays: policy.KeepDeployedWithinDays,
+		KeepEverDeployedToProd: policy.KeepEverDeployedToProd,
+		AutoPurge:              policy.AutoPurge,
+		LastPurgedOn:           policy.LastPurgedOn,
+	}
+}
+
+func buildPurgeReport(policyId int, dryRun bool, items []*bean.ArtifactReportItem) *bean.PurgeReport {
+	report := &bean.PurgeReport{
+		PolicyId:    policyId,
+		DryRun:      dryRun,
+		TotalCount:  len(items),
+		Artifacts:   items,
+		GeneratedOn: time.Now(),
+	}
+	for _, item := range items {
+		switch item.Action {
+		case bean.ArtifactActionRetain:
+			report.RetainedCount++
+		case bean.ArtifactActionDelete:
+			report.DeleteCount++
+		case bean.ArtifactActionPurged:
+			report.PurgedCount++
+		case bean.ArtifactActionFailed:
+			report.FailedCount++
+		}
+	}
+	return report
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+type PolicyScope string
+
+const (
+	PolicyScopeCiPipeline PolicyScope = "CI_PIPELINE"
+	PolicyScopeRegistry   PolicyScope = "REGISTRY"
+)
+
+type ArtifactAction string
+
+const (
+	ArtifactActionRetain ArtifactAction = "RETAIN"
+	ArtifactActionDelete ArtifactAction = "DELETE"
+	ArtifactActionPurged ArtifactAction = "PURGED"
+	ArtifactActionFailed ArtifactAction = "FAILED"
+)
+
+// RetainReason tells which rule of the policy keeps an image, an image is deleted only when no rule keeps it
+type RetainReason string
+
+const (
+	RetainReasonLatest           RetainReason = "KEEP_LAST_N"
+	RetainReasonRecentlyDeployed RetainReason = "DEPLOYED_WITHIN_DAYS"
+	RetainReasonDeployedToProd   RetainReason = "DEPLOYED_TO_PRODUCTION"
+	RetainReasonRunning          RetainReason = "CURRENTLY_DEPLOYED"
+	RetainReasonSharedImage      RetainReason = "SHARED_WITH_RETAINED_ARTIFACT"
+	RetainReasonUnknownRegistry  RetainReason = "REGISTRY_NOT_FOUND"
+	RetainReasonRunLimit         RetainReason = "DELETION_LIMIT_REACHED"
+)
+
+const (
+	PolicyNotFound          = "image retention policy not found"
+	PolicyAlreadyExists     = "an image retention policy already exists for this %s"
+	CiPipelineIdRequired    = "ciPipelineId is required for CI_PIPELINE scope"
+	RegistryIdRequired      = "dockerRegistryId is required for REGISTRY scope"
+	CiPipelineNotFound      = "ci pipeline %d not found"
+	RegistryNotFound        = "container registry %s not found"
+	ImageRegistryNotFound   = "no container registry found for the image"
+	UnsupportedRegistry     = "image deletion is not supported for registry type %s"
+	ImageNotFoundInRegistry = "image not found in registry"
+)
+
+type ImageRetentionPolicyDto struct {
+	Id                     int         `json:"id"`
+	Name                   string      `json:"name" validate:"required,max=100"`
+	Scope                  PolicyScope `json:"scope" validate:"required,oneof=CI_PIPELINE REGISTRY"`
+	CiPipelineId           int         `json:"ciPipelineId,omitempty"`
+	DockerRegistryId       string      `json:"dockerRegistryId,omitempty"`
+	KeepLastN              int         `json:"keepLastN" validate:"required,min=1"`
+	KeepDeployedWithinDays int         `json:"keepDeployedWithinDays" validate:"min=0"`
+	KeepEverDeployedToProd bool        `json:"keepEverDeployedToProd"`
+	// AutoPurge lets the scheduled garbage collection delete images of this policy, else it is only run on demand
+	AutoPurge    bool       `json:"autoPurge"`
+	LastPurgedOn *time.Time `json:"lastPurgedOn,omitempty"`
+	UserId       int32      `json:"-"`
+}
+
+type ArtifactReportItem struct {
+	CiArtifactId int            `json:"ciArtifactId"`
+	Image        string         `json:"image"`
+	ImageDigest  string         `json:"imageDigest,omitempty"`
+	CreatedOn    time.Time      `json:"createdOn"`
+	Action       ArtifactAction `json:"action"`
+	Reasons      []RetainReason `json:"reasons,omitempty"`
+	Message      string         `json:"message,omitempty"`
+}
+
+// PurgeReport lists what a policy retains and deletes, nothing is deleted from the registry on a dry run
+type PurgeReport struct {
+	PolicyId      int                   `json:"policyId"`
+	DryRun        bool                  `json:"dryRun"`
+	TotalCount    int                   `json:"totalCount"`
+	RetainedCount int                   `json:"retainedCount"`
+	DeleteCount   int                   `json:"deleteCount"`
+	PurgedCount   int                   `json:"purgedCount"`
+	FailedCount   int                   `json:"failedCount"`
+	Artifacts     []*ArtifactReportItem `json:"artifacts"`
+	GeneratedOn   time.Time             `json:"generatedOn"`
+}
+
+type ImageRetentionConfig struct {
+	Enabled             bool `env:"IMAGE_RETENTION_GC_ENABLED" envDefault:"false"`
+	CronIntervalSecs    int  `env:"IMAGE_RETENTION_GC_CRON_INTERVAL_SECS" envDefault:"86400"`
+	MaxDeletionsPerRun  int  `env:"IMAGE_RETENTION_MAX_DELETIONS_PER_RUN" envDefault:"100"`
+	RegistryTimeoutSecs int  `env:"IMAGE_RETENTION_REGISTRY_TIMEOUT_SECS" envDefault:"30"`
+}
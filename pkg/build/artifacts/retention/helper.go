@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package retention
+
+import (
+	repository2 "github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/pkg/build/artifacts/retention/bean"
+	"github.com/devtron-labs/devtron/pkg/build/artifacts/retention/repository"
+	"strings"
+	"time"
+)
+
+// EvaluateRetention decides for each candidate artifact, newest first, whether the policy retains or deletes its image.
+// Deployments of related artifacts count for every artifact sharing their image or digest, as deleting a manifest
+// removes it for all of them. Images currently deployed on any pipeline are always retained.
+func EvaluateRetention(policy *repository.ImageRetentionPolicy, candidates []*repository2.CiArtifact, related []*repository2.CiArtifact,
+	deployments []*repository.ArtifactDeployment, currentlyDeployedIds []int, now time.Time) []*bean.ArtifactReportItem {
+	deploymentById := make(map[int]*repository.ArtifactDeployment, len(deployments))
+	for _, deployment := range deployments {
+		deploymentById[deployment.CiArtifactId] = deployment
+	}
+	currentlyDeployed := make(map[int]bool, len(currentlyDeployedIds))
+	for _, id := range currentlyDeployedIds {
+		currentlyDeployed[id] = true
+	}
+	// artifacts sharing an image or a digest are treated alike
+	artifactIdsByImage := make(map[string][]int)
+	artifactIdsByDigest := make(map[string][]int)
+	seen := make(map[int]bool)
+	for _, artifact := range append(append([]*repository2.CiArtifact{}, candidates...), related...) {
+		if seen[artifact.Id] {
+			continue
+		}
+		seen[artifact.Id] = true
+		artifactIdsByImage[artifact.Image] = append(artifactIdsByImage[artifact.Image], artifact.Id)
+		if IsContentDigest(artifact.ImageDigest) {
+			artifactIdsByDigest[artifact.ImageDigest] = append(artifactIdsByDigest[artifact.ImageDigest], artifact.Id)
+		}
+	}
+
+	deployedWithin := now.AddDate(0, 0, -policy.KeepDeployedWithinDays)
+	imagesSeenByRepo := make(map[string]map[string]bool)
+	items := make([]*bean.ArtifactReportItem, 0, len(candidates))
+	for _, artifact := range candidates {
+		item := &bean.ArtifactReportItem{
+			CiArtifactId: artifact.Id,
+			Image:        artifact.Image,
+			ImageDigest:  artifact.ImageDigest,
+			CreatedOn:    artifact.CreatedOn,
+		}
+		repo := GetImageRepository(artifact.Image)
+		if imagesSeenByRepo[repo] == nil {
+			imagesSeenByRepo[repo] = make(map[string]bool)
+		}
+		imagesSeenByRepo[repo][artifact.Image] = true
+		if len(imagesSeenByRepo[repo]) <= policy.KeepLastN {
+			item.Reasons = append(item.Reasons, bean.RetainReasonLatest)
+		}
+		relatedIds := append(append([]int{}, artifactIdsByImage[artifact.Image]...), artifactIdsByDigest[artifact.ImageDigest]...)
+		recentlyDeployed, deployedToProd, running := false, false, false
+		for _, id := range relatedIds {
+			running = running || currentlyDeployed[id]
+			if deployment, ok := deploymentById[id]; ok {
+				recentlyDeployed = recentlyDeployed || (policy.KeepDeployedWithinDays > 0 && deployment.LastDeployedOn.After(deployedWithin))
+				deployedToProd = deployedToProd || (policy.KeepEverDeployedToProd && deployment.DeployedToProd)
+			}
+		}
+		if running {
+			item.Reasons = append(item.Reasons, bean.RetainReasonRunning)
+		}
+		if recentlyDeployed {
+			item.Reasons = append(item.Reasons, bean.RetainReasonRecentlyDeployed)
+		}
+		if deployedToProd {
+			item.Reasons = append(item.Reasons, bean.RetainReasonDeployedToProd)
+		}
+		items = append(items, item)
+	}
+
+	// an image can not be deleted while another retained candidate refers to the same image or digest
+	retainedImages, retainedDigests := make(map[string]bool), make(map[string]bool)
+	for changed := true; changed; {
+		changed = false
+		for _, item := range items {
+			if len(item.Reasons) == 0 && (retainedImages[item.Image] || retainedDigests[item.ImageDigest]) {
+				item.Reasons = append(item.Reasons, bean.RetainReasonSharedImage)
+			}
+			if len(item.Reasons) > 0 && !retainedImages[item.Image] {
+				retainedImages[item.Image] = true
+				changed = true
+			}
+			if len(item.Reasons) > 0 && IsContentDigest(item.ImageDigest) && !retainedDigests[item.ImageDigest] {
+				retainedDigests[item.ImageDigest] = true
+				changed = true
+			}
+		}
+	}
+	for _, item := range items {
+		if len(item.Reasons) > 0 {
+			item.Action = bean.ArtifactActionRetain
+		} else {
+			item.Action = bean.ArtifactActionDelete
+		}
+	}
+	return items
+}
+
+// GetImageRepository strips the tag or digest from an image path
+func GetImageRepository(image string) string {
+	if index := strings.Index(image, "@"); index >= 0 {
+		image = image[:index]
+	}
+	if index := strings.LastIndex(image, ":"); index > strings.LastIndex(image, "/") {
+		image = image[:index]
+	}
+	return image
+}
+
+func IsContentDigest(digest string) bool {
+	return strings.HasPrefix(digest, "sha256:")
+}
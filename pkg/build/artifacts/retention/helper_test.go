@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package retention
+
+import (
+	repository2 "github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/pkg/build/artifacts/retention/bean"
+	"github.com/devtron-labs/devtron/pkg/build/artifacts/retention/repository"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestEvaluateRetention(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	artifact := func(id int, image, digest string) *repository2.CiArtifact {
+		return &repository2.CiArtifact{Id: id, Image: image, ImageDigest: digest}
+	}
+	// newest first
+	candidates := []*repository2.CiArtifact{
+		artifact(6, "reg.io/team/app:6", "sha256:6"),
+		artifact(5, "reg.io/team/app:5", "sha256:5"),
+		artifact(4, "reg.io/team/app:4", "sha256:4"),
+		artifact(3, "reg.io/team/app:3", "sha256:3"),
+		artifact(2, "reg.io/team/app:2", "sha256:2"),
+		artifact(1, "reg.io/team/app:1", "sha256:1"),
+	}
+	// artifact 10 of a linked pipeline shares the image of artifact 3, artifact 11 re-tagged the digest of artifact 2
+	related := []*repository2.CiArtifact{
+		artifact(10, "reg.io/team/app:3", "sha256:3"),
+		artifact(11, "reg.io/team/app:2-hotfix", "sha256:2"),
+	}
+	deployments := []*repository.ArtifactDeployment{
+		{CiArtifactId: 4, LastDeployedOn: now.AddDate(0, 0, -40), DeployedToProd: true},
+		{CiArtifactId: 10, LastDeployedOn: now.AddDate(0, 0, -2)},
+		{CiArtifactId: 1, LastDeployedOn: now.AddDate(0, 0, -90)},
+	}
+	policy := &repository.ImageRetentionPolicy{KeepLastN: 2, KeepDeployedWithinDays: 7, KeepEverDeployedToProd: true}
+
+	items := EvaluateRetention(policy, candidates, related, deployments, []int{11}, now)
+	expected := map[int][]bean.RetainReason{
+		6: {bean.RetainReasonLatest},
+		5: {bean.RetainReasonLatest},
+		4: {bean.RetainReasonDeployedToProd},
+		3: {bean.RetainReasonRecentlyDeployed},
+		2: {bean.RetainReasonRunning},
+		1: nil,
+	}
+	assert.Len(t, items, len(candidates))
+	for _, item := range items {
+		assert.Equal(t, expected[item.CiArtifactId], item.Reasons, item.CiArtifactId)
+		if len(expected[item.CiArtifactId]) == 0 {
+			assert.Equal(t, bean.ArtifactActionDelete, item.Action)
+		} else {
+			assert.Equal(t, bean.ArtifactActionRetain, item.Action)
+		}
+	}
+
+	policy.KeepEverDeployedToProd = false
+	items = EvaluateRetention(policy, candidates, related, deployments, nil, now)
+	for _, item := range items {
+		if item.CiArtifactId == 4 || item.CiArtifactId == 2 {
+			assert.Equal(t, bean.ArtifactActionDelete, item.Action, item.CiArtifactId)
+		}
+	}
+}
+
+func TestEvaluateRetentionSharedImage(t *testing.T) {
+	now := time.Now()
+	// the same image pushed twice, the older artifact must follow the newer one
+	candidates := []*repository2.CiArtifact{
+		{Id: 3, Image: "reg.io/team/app:b", ImageDigest: "sha256:b"},
+		{Id: 2, Image: "reg.io/team/app:a", ImageDigest: "sha256:a"},
+		{Id: 1, Image: "reg.io/team/app:b", ImageDigest: "sha256:b"},
+		{Id: 0, Image: "reg.io/team/other:a", ImageDigest: "sha256:o"},
+	}
+	policy := &repository.ImageRetentionPolicy{KeepLastN: 1}
+	items := EvaluateRetention(policy, candidates, nil, nil, nil, now)
+	actions := make(map[int]bean.ArtifactAction)
+	for _, item := range items {
+		actions[item.CiArtifactId] = item.Action
+	}
+	assert.Equal(t, bean.ArtifactActionRetain, actions[3])
+	assert.Equal(t, bean.ArtifactActionDelete, actions[2])
+	assert.Equal(t, bean.ArtifactActionRetain, actions[1])
+	// last N is counted per image repository
+	assert.Equal(t, bean.ArtifactActionRetain, actions[0])
+}
+
+func TestGetImageRepository(t *testing.T) {
+	assert.Equal(t, "reg.io:5000/team/app", GetImageRepository("reg.io:5000/team/app:v1"))
+	assert.Equal(t, "reg.io/team/app", GetImageRepository("reg.io/team/app@sha256:abc"))
+	assert.Equal(t, "reg.io:5000/team/app", GetImageRepository("reg.io:5000/team/app"))
+}
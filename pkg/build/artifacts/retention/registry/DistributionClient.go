@@ -0,0 +1,242 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.oci.image.index.v1+json",
+}
+
+// DistributionClient talks the registry v2 api implemented by gcr, artifact registry and most self-hosted registries.
+// Registries answering with a bearer challenge are handled through their token endpoint with the basic credentials.
+type DistributionClient struct {
+	baseUrl     string
+	username    string
+	password    string
+	httpClient  *http.Client
+	bearerToken string
+}
+
+func NewDistributionClient(baseUrl, username, password string, httpClient *http.Client) *DistributionClient {
+	return &DistributionClient{
+		baseUrl:    baseUrl,
+		username:   username,
+		password:   password,
+		httpClient: httpClient,
+	}
+}
+
+// DeleteImage removes the tag where the registry supports it and then the manifest by digest, the digest is resolved
+// from the tag when the artifact has none
+func (impl *DistributionClient) DeleteImage(ctx context.Context, image *ImageRef) error {
+	digest := image.Digest
+	if len(digest) == 0 {
+		resolved, err := impl.resolveDigest(ctx, image)
+		if err != nil {
+			return err
+		}
+		digest = resolved
+	}
+	tagDeleted := false
+	if len(image.Tag) > 0 {
+		statusCode, err := impl.deleteManifest(ctx, image.Repository, image.Tag)
+		if err != nil {
+			return err
+		}
+		// tag deletion is optional in the distribution spec, unsupported registries answer with 400, 405 or 501
+		switch {
+		case statusCode == http.StatusAccepted || statusCode == http.StatusOK:
+			tagDeleted = true
+		case statusCode == http.StatusNotFound, statusCode == http.StatusBadRequest,
+			statusCode == http.StatusMethodNotAllowed, statusCode == http.StatusNotImplemented:
+		default:
+			return fmt.Errorf("unexpected status %d while deleting tag %s", statusCode, image.String())
+		}
+	}
+	statusCode, err := impl.deleteManifest(ctx, image.Repository, digest)
+	if err != nil {
+		return err
+	}
+	switch statusCode {
+	case http.StatusAccepted, http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		if tagDeleted {
+			return nil
+		}
+		return ErrImageNotFound
+	}
+	return fmt.Errorf("unexpected status %d while deleting manifest %s of %s", statusCode, digest, image.String())
+}
+
+func (impl *DistributionClient) resolveDigest(ctx context.Context, image *ImageRef) (string, error) {
+	resp, err := impl.do(ctx, http.MethodHead, image.Repository, impl.manifestUrl(image.Repository, image.Tag))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", ErrImageNotFound
+	}
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if resp.StatusCode != http.StatusOK || len(digest) == 0 {
+		return "", fmt.Errorf("could not resolve digest of %s, status %d", image.String(), resp.StatusCode)
+	}
+	return digest, nil
+}
+
+func (impl *DistributionClient) deleteManifest(ctx context.Context, repository, reference string) (int, error) {
+	resp, err := impl.do(ctx, http.MethodDelete, repository, impl.manifestUrl(repository, reference))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func (impl *DistributionClient) manifestUrl(repository, reference string) string {
+	return fmt.Sprintf("%s/v2/%s/manifests/%s", impl.baseUrl, repository, reference)
+}
+
+// do sends the request with the cached bearer token or basic credentials, a bearer challenge is answered once
+func (impl *DistributionClient) do(ctx context.Context, method, repository, requestUrl string) (*http.Response, error) {
+	resp, err := impl.send(ctx, method, requestUrl)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+	challenge := resp.Header.Get("Www-Authenticate")
+	resp.Body.Close()
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return nil, fmt.Errorf("registry rejected the credentials, status %d", http.StatusUnauthorized)
+	}
+	token, err := impl.fetchBearerToken(ctx, ParseBearerChallenge(challenge), repository)
+	if err != nil {
+		return nil, err
+	}
+	impl.bearerToken = token
+	resp, err = impl.send(ctx, method, requestUrl)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		resp.Body.Close()
+		return nil, fmt.Errorf("registry denied %s on %s, status %d", method, repository, resp.StatusCode)
+	}
+	return resp, nil
+}
+
+func (impl *DistributionClient) send(ctx context.Context, method, requestUrl string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, requestUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if len(impl.bearerToken) > 0 {
+		req.Header.Set("Authorization", "Bearer "+impl.bearerToken)
+	} else if len(impl.username) > 0 || len(impl.password) > 0 {
+		req.SetBasicAuth(impl.username, impl.password)
+	}
+	return impl.httpClient.Do(req)
+}
+
+func (impl *DistributionClient) fetchBearerToken(ctx context.Context, challenge map[string]string, repository string) (string, error) {
+	realm := challenge["realm"]
+	if len(realm) == 0 {
+		return "", fmt.Errorf("bearer challenge of the registry has no realm")
+	}
+	query := url.Values{}
+	if service, ok := challenge["service"]; ok {
+		query.Set("service", service)
+	}
+	query.Set("scope", fmt.Sprintf("repository:%s:pull,delete", repository))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	if len(impl.username) > 0 || len(impl.password) > 0 {
+		req.SetBasicAuth(impl.username, impl.password)
+	}
+	resp, err := impl.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("could not get registry token, status %d: %s", resp.StatusCode, string(body))
+	}
+	tokenResponse := &struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(tokenResponse)
+	if err != nil {
+		return "", err
+	}
+	if len(tokenResponse.Token) > 0 {
+		return tokenResponse.Token, nil
+	}
+	return tokenResponse.AccessToken, nil
+}
+
+// ParseBearerChallenge reads the parameters of a header like `Bearer realm="https://auth/token",service="registry"`
+func ParseBearerChallenge(header string) map[string]string {
+	params := make(map[string]string)
+	header = strings.TrimSpace(header)
+	if index := strings.Index(header, " "); index > 0 {
+		header = header[index+1:]
+	}
+	for len(header) > 0 {
+		equals := strings.Index(header, "=")
+		if equals <= 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(header[:equals]))
+		header = strings.TrimSpace(header[equals+1:])
+		var value string
+		if strings.HasPrefix(header, "\"") {
+			end := strings.Index(header[1:], "\"")
+			if end < 0 {
+				value, header = header[1:], ""
+			} else {
+				value, header = header[1:end+1], header[end+2:]
+			}
+		} else if comma := strings.Index(header, ","); comma >= 0 {
+			value, header = header[:comma], header[comma:]
+		} else {
+			value, header = header, ""
+		}
+		params[key] = value
+		header = strings.TrimLeft(header, ", ")
+	}
+	return params
+}
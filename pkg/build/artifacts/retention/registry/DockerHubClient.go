@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const dockerHubApiUrl = "https://hub.docker.com"
+
+// DockerHubClient deletes tags through the docker hub api as docker hub does not implement manifest deletion of the
+// registry v2 api
+type DockerHubClient struct {
+	apiUrl     string
+	username   string
+	password   string
+	httpClient *http.Client
+	token      string
+}
+
+func NewDockerHubClient(username, password string, timeout time.Duration) *DockerHubClient {
+	return &DockerHubClient{
+		apiUrl:     dockerHubApiUrl,
+		username:   username,
+		password:   password,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (impl *DockerHubClient) DeleteImage(ctx context.Context, image *ImageRef) error {
+	if len(image.Tag) == 0 {
+		return errors.New("docker hub images can only be deleted by tag")
+	}
+	if len(impl.token) == 0 {
+		token, err := impl.login(ctx)
+		if err != nil {
+			return err
+		}
+		impl.token = token
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete,
+		fmt.Sprintf("%s/v2/repositories/%s/tags/%s/", impl.apiUrl, image.Repository, image.Tag), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "JWT "+impl.token)
+	resp, err := impl.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusNoContent, http.StatusOK, http.StatusAccepted:
+		return nil
+	case http.StatusNotFound:
+		return ErrImageNotFound
+	}
+	return fmt.Errorf("unexpected status %d while deleting %s from docker hub", resp.StatusCode, image.String())
+}
+
+func (impl *DockerHubClient) login(ctx context.Context) (string, error) {
+	body, err := json.Marshal(map[string]string{"username": impl.username, "password": impl.password})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, impl.apiUrl+"/v2/users/login", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := impl.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("docker hub login failed, status %d", resp.StatusCode)
+	}
+	loginResponse := &struct {
+		Token string `json:"token"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(loginResponse)
+	if err != nil {
+		return "", err
+	}
+	return loginResponse.Token, nil
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ecr"
+)
+
+type EcrClient struct {
+	client *ecr.ECR
+}
+
+// NewEcrClient uses the access keys of the registry, without keys the default aws credential chain is used
+func NewEcrClient(region, accessKeyId, secretAccessKey string) (*EcrClient, error) {
+	config := &aws.Config{Region: aws.String(region)}
+	if len(accessKeyId) > 0 && len(secretAccessKey) > 0 {
+		config.Credentials = credentials.NewStaticCredentials(accessKeyId, secretAccessKey, "")
+	}
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, err
+	}
+	return &EcrClient{client: ecr.New(sess)}, nil
+}
+
+func (impl *EcrClient) DeleteImage(ctx context.Context, image *ImageRef) error {
+	imageId := &ecr.ImageIdentifier{}
+	if len(image.Tag) > 0 {
+		imageId.ImageTag = aws.String(image.Tag)
+	} else {
+		imageId.ImageDigest = aws.String(image.Digest)
+	}
+	output, err := impl.client.BatchDeleteImageWithContext(ctx, &ecr.BatchDeleteImageInput{
+		RepositoryName: aws.String(image.Repository),
+		ImageIds:       []*ecr.ImageIdentifier{imageId},
+	})
+	if err != nil {
+		return err
+	}
+	for _, failure := range output.Failures {
+		if aws.StringValue(failure.FailureCode) == ecr.ImageFailureCodeImageNotFound {
+			return ErrImageNotFound
+		}
+		return fmt.Errorf("ecr could not delete %s: %s", image.String(), aws.StringValue(failure.FailureReason))
+	}
+	return nil
+}
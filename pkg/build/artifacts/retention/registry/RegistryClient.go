@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registry
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/docker/distribution/reference"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrImageNotFound is returned when the image is already gone from the registry, callers may treat it as deleted
+var ErrImageNotFound = errors.New("image not found in registry")
+
+const (
+	connectionInsecure       = "insecure"
+	connectionSecureWithCert = "secure-with-cert"
+	dockerHubDomain          = "docker.io"
+)
+
+// ImageRef identifies an image inside its registry, Repository is the path without the registry host
+type ImageRef struct {
+	Domain     string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+func (ref *ImageRef) String() string {
+	if len(ref.Tag) > 0 {
+		return fmt.Sprintf("%s/%s:%s", ref.Domain, ref.Repository, ref.Tag)
+	}
+	return fmt.Sprintf("%s/%s@%s", ref.Domain, ref.Repository, ref.Digest)
+}
+
+// RegistryClient deletes image manifests through the api of a container registry
+type RegistryClient interface {
+	DeleteImage(ctx context.Context, image *ImageRef) error
+}
+
+// ParseImageRef splits an image path like host/team/app:tag, imageDigest is used only when it is a content digest
+func ParseImageRef(image string, imageDigest string) (*ImageRef, error) {
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return nil, err
+	}
+	ref := &ImageRef{
+		Domain:     reference.Domain(named),
+		Repository: reference.Path(named),
+	}
+	if tagged, ok := named.(reference.Tagged); ok {
+		ref.Tag = tagged.Tag()
+	}
+	if digested, ok := named.(reference.Digested); ok {
+		ref.Digest = digested.Digest().String()
+	} else if strings.HasPrefix(imageDigest, "sha256:") {
+		ref.Digest = imageDigest
+	}
+	if len(ref.Tag) == 0 && len(ref.Digest) == 0 {
+		return nil, fmt.Errorf("image %s has neither a tag nor a digest", image)
+	}
+	return ref, nil
+}
+
+// GetRegistryHost strips the scheme and trailing path separators from the registry url of a container registry
+func GetRegistryHost(registryUrl string) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(registryUrl, "https://"), "http://")
+	host = strings.TrimSuffix(host, "/")
+	if host == "index.docker.io" || host == "registry-1.docker.io" {
+		return dockerHubDomain
+	}
+	return host
+}
+
+func NewRegistryClient(store *dockerRegistryRepository.DockerArtifactStore, timeout time.Duration) (RegistryClient, error) {
+	switch store.RegistryType {
+	case dockerRegistryRepository.REGISTRYTYPE_DOCKER_HUB:
+		return NewDockerHubClient(store.Username, store.Password, timeout), nil
+	case dockerRegistryRepository.REGISTRYTYPE_ECR:
+		return NewEcrClient(store.AWSRegion, store.AWSAccessKeyId, store.AWSSecretAccessKey)
+	case dockerRegistryRepository.REGISTRYTYPE_GCR, dockerRegistryRepository.REGISTRYTYPE_ARTIFACT_REGISTRY, dockerRegistryRepository.REGISTRYTYPE_OTHER,
+		dockerRegistryRepository.REGISTRYTYPE_HARBOR, dockerRegistryRepository.REGISTRYTYPE_QUAY:
+		httpClient, err := NewHttpClient(store.Connection, store.Cert, timeout)
+		if err != nil {
+			return nil, err
+		}
+		return NewDistributionClient(getRegistryBaseUrl(store.RegistryURL), store.Username, store.Password, httpClient), nil
+	}
+	return nil, fmt.Errorf("image deletion is not supported for registry type %s", store.RegistryType)
+}
+
+func getRegistryBaseUrl(registryUrl string) string {
+	if strings.HasPrefix(registryUrl, "http://") || strings.HasPrefix(registryUrl, "https://") {
+		return strings.TrimSuffix(registryUrl, "/")
+	}
+	return "https://" + strings.TrimSuffix(registryUrl, "/")
+}
+
+// NewHttpClient applies the tls settings of the registry connection, a zero timeout leaves requests bound by their context only
+func NewHttpClient(connection, cert string, timeout time.Duration) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	switch connection {
+	case connectionInsecure:
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	case connectionSecureWithCert:
+		certPool, err := x509.SystemCertPool()
+		if err != nil || certPool == nil {
+			certPool = x509.NewCertPool()
+		}
+		if !certPool.AppendCertsFromPEM([]byte(cert)) {
+			return nil, errors.New("invalid certificate of the container registry")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: certPool}
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}, nil
+}
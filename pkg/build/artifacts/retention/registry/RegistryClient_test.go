@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registry
+
+import (
+	"context"
+	"fmt"
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseImageRef(t *testing.T) {
+	ref, err := ParseImageRef("reg.io:5000/team/app:v1", "sha256:abc")
+	assert.Nil(t, err)
+	assert.Equal(t, &ImageRef{Domain: "reg.io:5000", Repository: "team/app", Tag: "v1", Digest: "sha256:abc"}, ref)
+
+	ref, err = ParseImageRef("devtron/app:v2", "")
+	assert.Nil(t, err)
+	assert.Equal(t, &ImageRef{Domain: "docker.io", Repository: "devtron/app", Tag: "v2"}, ref)
+
+	ref, err = ParseImageRef("nginx:latest", "not-a-digest")
+	assert.Nil(t, err)
+	assert.Equal(t, &ImageRef{Domain: "docker.io", Repository: "library/nginx", Tag: "latest"}, ref)
+
+	_, err = ParseImageRef("reg.io/team/app", "")
+	assert.NotNil(t, err)
+}
+
+func TestGetRegistryHost(t *testing.T) {
+	assert.Equal(t, "reg.io", GetRegistryHost("https://reg.io/"))
+	assert.Equal(t, "docker.io", GetRegistryHost("index.docker.io"))
+	assert.Equal(t, "asia-docker.pkg.dev", GetRegistryHost("asia-docker.pkg.dev"))
+}
+
+func TestParseBearerChallenge(t *testing.T) {
+	params := ParseBearerChallenge(`Bearer realm="https://auth.io/token",service="registry.io",scope="repository:team/app:pull"`)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.io/token",
+		"service": "registry.io",
+		"scope":   "repository:team/app:pull",
+	}, params)
+}
+
+// newDistributionServer serves a registry that supports deletion by digest only, deleted manifests are recorded
+func newDistributionServer(t *testing.T, deleted *[]string) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			user, password, _ := r.BasicAuth()
+			assert.Equal(t, "user", user)
+			assert.Equal(t, "secret", password)
+			assert.Equal(t, "repository:team/app:pull,delete", r.URL.Query().Get("scope"))
+			fmt.Fprint(w, `{"token":"abc"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer abc" {
+			w.Header().Set("Www-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodHead && r.URL.Path == "/v2/team/app/manifests/v1":
+			w.Header().Set("Docker-Content-Digest", "sha256:resolved")
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodDelete && r.URL.Path == "/v2/team/app/manifests/v1":
+			// tag deletion not supported
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case r.Method == http.MethodDelete && r.URL.Path == "/v2/team/app/manifests/sha256:resolved":
+			*deleted = append(*deleted, r.URL.Path)
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	return server
+}
+
+func TestDistributionClientDeleteImage(t *testing.T) {
+	var deleted []string
+	server := newDistributionServer(t, &deleted)
+	defer server.Close()
+
+	client := NewDistributionClient(server.URL, "user", "secret", server.Client())
+	err := client.DeleteImage(context.Background(), &ImageRef{Repository: "team/app", Tag: "v1"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"/v2/team/app/manifests/sha256:resolved"}, deleted)
+
+	err = client.DeleteImage(context.Background(), &ImageRef{Repository: "team/app", Tag: "gone"})
+	assert.ErrorIs(t, err, ErrImageNotFound)
+}
+
+func TestNewRegistryClientHarbor(t *testing.T) {
+	testNewRegistryClientForDistributionRegistry(t, dockerRegistryRepository.REGISTRYTYPE_HARBOR)
+}
+
+func TestNewRegistryClientQuay(t *testing.T) {
+	testNewRegistryClientForDistributionRegistry(t, dockerRegistryRepository.REGISTRYTYPE_QUAY)
+}
+
+func testNewRegistryClientForDistributionRegistry(t *testing.T, registryType dockerRegistryRepository.RegistryType) {
+	var deleted []string
+	server := newDistributionServer(t, &deleted)
+	defer server.Close()
+
+	store := &dockerRegistryRepository.DockerArtifactStore{
+		RegistryType: registryType,
+		RegistryURL:  server.URL,
+		Username:     "user",
+		Password:     "secret",
+	}
+	client, err := NewRegistryClient(store, time.Minute)
+	assert.Nil(t, err)
+	assert.IsType(t, &DistributionClient{}, client)
+	err = client.DeleteImage(context.Background(), &ImageRef{Repository: "team/app", Tag: "v1"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"/v2/team/app/manifests/sha256:resolved"}, deleted)
+}
@@ -0,0 +1,217 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	repository2 "github.com/devtron-labs/devtron/internal/sql/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+	"go.uber.org/zap"
+	"time"
+)
+
+type ImageRetentionPolicy struct {
+	tableName              struct{}   `sql:"image_retention_policy" pg:",discard_unknown_columns"`
+	Id                     int        `sql:"id,pk"`
+	Name                   string     `sql:"name,notnull"`
+	Scope                  string     `sql:"scope,notnull"`
+	CiPipelineId           int        `sql:"ci_pipeline_id"`
+	DockerRegistryId       string     `sql:"docker_registry_id"`
+	KeepLastN              int        `sql:"keep_last_n,notnull"`
+	KeepDeployedWithinDays int        `sql:"keep_deployed_within_days,notnull"`
+	KeepEverDeployedToProd bool       `sql:"keep_ever_deployed_to_prod,notnull"`
+	AutoPurge              bool       `sql:"auto_purge,notnull"`
+	LastPurgedOn           *time.Time `sql:"last_purged_on"`
+	Active                 bool       `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+// CiArtifactPurge marks an artifact whose image was deleted from the registry by a retention policy
+type CiArtifactPurge struct {
+	tableName    struct{} `sql:"ci_artifact_purge" pg:",discard_unknown_columns"`
+	Id           int      `sql:"id,pk"`
+	CiArtifactId int      `sql:"ci_artifact_id,notnull"`
+	PolicyId     int      `sql:"image_retention_policy_id,notnull"`
+	Image        string   `sql:"image,notnull"`
+	ImageDigest  string   `sql:"image_digest"`
+	sql.AuditLog
+}
+
+// ArtifactDeployment summarises the deployments of an artifact across all cd pipelines
+type ArtifactDeployment struct {
+	CiArtifactId   int       `sql:"ci_artifact_id"`
+	LastDeployedOn time.Time `sql:"last_deployed_on"`
+	DeployedToProd bool      `sql:"deployed_to_prod"`
+}
+
+type ImageRetentionRepository interface {
+	SavePolicy(policy *ImageRetentionPolicy) error
+	UpdatePolicy(policy *ImageRetentionPolicy) error
+	FindActivePolicyById(id int) (*ImageRetentionPolicy, error)
+	FindAllActivePolicies() ([]*ImageRetentionPolicy, error)
+	FindActivePolicyByCiPipelineId(ciPipelineId int) (*ImageRetentionPolicy, error)
+	FindActivePolicyByRegistryId(dockerRegistryId string) (*ImageRetentionPolicy, error)
+
+	// FindUnpurgedArtifactsByCiPipelineId returns the artifacts of the ci pipeline, newest first
+	FindUnpurgedArtifactsByCiPipelineId(ciPipelineId int) ([]*repository2.CiArtifact, error)
+	// FindUnpurgedArtifactsByRegistry returns the artifacts pushed to the registry, newest first
+	FindUnpurgedArtifactsByRegistry(dockerRegistryId string, imagePrefix string) ([]*repository2.CiArtifact, error)
+	// FindArtifactsByImagesOrDigests returns every artifact, in any pipeline, referring to one of the images or digests
+	FindArtifactsByImagesOrDigests(images []string, digests []string) ([]*repository2.CiArtifact, error)
+	FindDeploymentsByArtifactIds(ciArtifactIds []int) ([]*ArtifactDeployment, error)
+	// FindCurrentlyDeployedArtifactIds returns the artifacts that are the latest deployment of any active cd pipeline
+	FindCurrentlyDeployedArtifactIds(ciArtifactIds []int) ([]int, error)
+	SavePurge(purge *CiArtifactPurge) error
+}
+
+type ImageRetentionRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewImageRetentionRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ImageRetentionRepositoryImpl {
+	return &ImageRetentionRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (repo *ImageRetentionRepositoryImpl) SavePolicy(policy *ImageRetentionPolicy) error {
+	return repo.dbConnection.Insert(policy)
+}
+
+func (repo *ImageRetentionRepositoryImpl) UpdatePolicy(policy *ImageRetentionPolicy) error {
+	return repo.dbConnection.Update(policy)
+}
+
+func (repo *ImageRetentionRepositoryImpl) FindActivePolicyById(id int) (*ImageRetentionPolicy, error) {
+	policy := &ImageRetentionPolicy{}
+	err := repo.dbConnection.Model(policy).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return policy, err
+}
+
+func (repo *ImageRetentionRepositoryImpl) FindAllActivePolicies() ([]*ImageRetentionPolicy, error) {
+	var policies []*ImageRetentionPolicy
+	err := repo.dbConnection.Model(&policies).
+		Where("active = ?", true).
+		Order("id").
+		Select()
+	return policies, err
+}
+
+func (repo *ImageRetentionRepositoryImpl) FindActivePolicyByCiPipelineId(ciPipelineId int) (*ImageRetentionPolicy, error) {
+	policy := &ImageRetentionPolicy{}
+	err := repo.dbConnection.Model(policy).
+		Where("ci_pipeline_id = ?", ciPipelineId).
+		Where("active = ?", true).
+		Limit(1).
+		Select()
+	return policy, err
+}
+
+func (repo *ImageRetentionRepositoryImpl) FindActivePolicyByRegistryId(dockerRegistryId string) (*ImageRetentionPolicy, error) {
+	policy := &ImageRetentionPolicy{}
+	err := repo.dbConnection.Model(policy).
+		Where("docker_registry_id = ?", dockerRegistryId).
+		Where("active = ?", true).
+		Limit(1).
+		Select()
+	return policy, err
+}
+
+func (repo *ImageRetentionRepositoryImpl) FindUnpurgedArtifactsByCiPipelineId(ciPipelineId int) ([]*repository2.CiArtifact, error) {
+	var artifacts []*repository2.CiArtifact
+	err := repo.dbConnection.Model(&artifacts).
+		Where("ci_artifact.pipeline_id = ?", ciPipelineId).
+		Where("NOT EXISTS (SELECT 1 FROM ci_artifact_purge cap WHERE cap.ci_artifact_id = ci_artifact.id)").
+		Order("ci_artifact.id DESC").
+		Select()
+	return artifacts, err
+}
+
+func (repo *ImageRetentionRepositoryImpl) FindUnpurgedArtifactsByRegistry(dockerRegistryId string, imagePrefix string) ([]*repository2.CiArtifact, error) {
+	var artifacts []*repository2.CiArtifact
+	err := repo.dbConnection.Model(&artifacts).
+		WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			q = q.WhereOr("ci_artifact.image LIKE ?", imagePrefix+"/%").
+				WhereOr("ci_artifact.credentials_source_type = ? AND ci_artifact.credentials_source_value = ?", repository2.GLOBAL_CONTAINER_REGISTRY, dockerRegistryId)
+			return q, nil
+		}).
+		Where("NOT EXISTS (SELECT 1 FROM ci_artifact_purge cap WHERE cap.ci_artifact_id = ci_artifact.id)").
+		Order("ci_artifact.id DESC").
+		Select()
+	return artifacts, err
+}
+
+func (repo *ImageRetentionRepositoryImpl) FindArtifactsByImagesOrDigests(images []string, digests []string) ([]*repository2.CiArtifact, error) {
+	var artifacts []*repository2.CiArtifact
+	if len(images) == 0 && len(digests) == 0 {
+		return artifacts, nil
+	}
+	err := repo.dbConnection.Model(&artifacts).
+		WhereGroup(func(q *orm.Query) (*orm.Query, error) {
+			if len(images) > 0 {
+				q = q.WhereOr("ci_artifact.image IN (?)", pg.In(images))
+			}
+			if len(digests) > 0 {
+				q = q.WhereOr("ci_artifact.image_digest IN (?)", pg.In(digests))
+			}
+			return q, nil
+		}).
+		Select()
+	return artifacts, err
+}
+
+func (repo *ImageRetentionRepositoryImpl) FindDeploymentsByArtifactIds(ciArtifactIds []int) ([]*ArtifactDeployment, error) {
+	var deployments []*ArtifactDeployment
+	if len(ciArtifactIds) == 0 {
+		return deployments, nil
+	}
+	query := `SELECT cw.ci_artifact_id, MAX(cwr.started_on) AS last_deployed_on, BOOL_OR(env.default) AS deployed_to_prod
+		FROM cd_workflow cw
+		INNER JOIN cd_workflow_runner cwr ON cwr.cd_workflow_id = cw.id
+		INNER JOIN pipeline p ON p.id = cw.pipeline_id
+		INNER JOIN environment env ON env.id = p.environment_id
+		WHERE cwr.workflow_type = 'DEPLOY' AND cw.ci_artifact_id IN (?)
+		GROUP BY cw.ci_artifact_id;`
+	_, err := repo.dbConnection.Query(&deployments, query, pg.In(ciArtifactIds))
+	return deployments, err
+}
+
+func (repo *ImageRetentionRepositoryImpl) FindCurrentlyDeployedArtifactIds(ciArtifactIds []int) ([]int, error) {
+	var deployedArtifactIds []int
+	if len(ciArtifactIds) == 0 {
+		return deployedArtifactIds, nil
+	}
+	query := `SELECT DISTINCT ON (cw.pipeline_id) cw.ci_artifact_id
+		FROM cd_workflow cw
+		INNER JOIN cd_workflow_runner cwr ON cwr.cd_workflow_id = cw.id
+		INNER JOIN pipeline p ON p.id = cw.pipeline_id AND p.deleted = false
+		WHERE cwr.workflow_type = 'DEPLOY'
+		AND cw.pipeline_id IN (SELECT DISTINCT pipeline_id FROM cd_workflow WHERE ci_artifact_id IN (?))
+		ORDER BY cw.pipeline_id, cwr.id DESC;`
+	_, err := repo.dbConnection.Query(&deployedArtifactIds, query, pg.In(ciArtifactIds))
+	return deployedArtifactIds, err
+}
+
+func (repo *ImageRetentionRepositoryImpl) SavePurge(purge *CiArtifactPurge) error {
+	return repo.dbConnection.Insert(purge)
+}
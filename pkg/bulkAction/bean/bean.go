@@ -111,6 +111,8 @@ type BulkApplicationForEnvironmentPayload struct {
 type BulkApplicationForEnvironmentResponse struct {
 	BulkApplicationForEnvironmentPayload
 	Response map[string]map[string]bool `json:"response"`
+	// DeploymentWindowBlocked maps the pipelines skipped because of a closed deployment window to the reason
+	DeploymentWindowBlocked map[string]string `json:"deploymentWindowBlocked,omitempty"`
 }
 
 type BulkApplicationHibernateUnhibernateForEnvironmentResponse struct {
@@ -47,6 +47,8 @@ import (
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/adapter"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/chartRef"
 	bean3 "github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/chartRef/bean"
+	"github.com/devtron-labs/devtron/pkg/deploymentWindow"
+	bean6 "github.com/devtron-labs/devtron/pkg/deploymentWindow/bean"
 	"github.com/devtron-labs/devtron/pkg/eventProcessor/out"
 	"github.com/devtron-labs/devtron/pkg/pipeline"
 	repository4 "github.com/devtron-labs/devtron/pkg/pipeline/history/repository"
@@ -62,6 +64,7 @@ import (
 	"net/http"
 	"sort"
 	"strings"
+	"time"
 )
 
 type BulkUpdateService interface {
@@ -102,6 +105,7 @@ type BulkUpdateServiceImpl struct {
 	chartRefService                  chartRef.ChartRefService
 	deployedAppService               deployedApp.DeployedAppService
 	cdPipelineEventPublishService    out.CDPipelineEventPublishService
+	deploymentWindowService          deploymentWindow.DeploymentWindowService
 }
 
 func NewBulkUpdateServiceImpl(bulkUpdateRepository bulkUpdate.BulkUpdateRepository,
@@ -121,7 +125,8 @@ func NewBulkUpdateServiceImpl(bulkUpdateRepository bulkUpdate.BulkUpdateReposito
 	deployedAppMetricsService deployedAppMetrics.DeployedAppMetricsService,
 	chartRefService chartRef.ChartRefService,
 	deployedAppService deployedApp.DeployedAppService,
-	cdPipelineEventPublishService out.CDPipelineEventPublishService) *BulkUpdateServiceImpl {
+	cdPipelineEventPublishService out.CDPipelineEventPublishService,
+	deploymentWindowService deploymentWindow.DeploymentWindowService) *BulkUpdateServiceImpl {
 	return &BulkUpdateServiceImpl{
 		bulkUpdateRepository:             bulkUpdateRepository,
 		logger:                           logger,
@@ -141,6 +146,7 @@ func NewBulkUpdateServiceImpl(bulkUpdateRepository bulkUpdate.BulkUpdateReposito
 		chartRefService:                  chartRefService,
 		deployedAppService:               deployedAppService,
 		cdPipelineEventPublishService:    cdPipelineEventPublishService,
+		deploymentWindowService:          deploymentWindowService,
 	}
 
 }
@@ -1318,6 +1324,13 @@ func (impl BulkUpdateServiceImpl) BulkDeploy(request *bean4.BulkApplicationForEn
 	appResults, envResults := checkAuthBatch(token, appObjectArr, envObjectArr)
 	//authorization block ends here
 
+	env, err := impl.environmentRepository.FindById(request.EnvId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environment", "envId", request.EnvId, "err", err)
+		return nil, err
+	}
+	evaluatedAt := time.Now()
+	deploymentWindowBlocked := make(map[string]string)
 	response := make(map[string]map[string]bool)
 	for _, pipeline := range pipelines {
 		appKey := utils.GenerateIdentifierKey(pipeline.AppId, pipeline.App.AppName)
@@ -1337,6 +1350,26 @@ func (impl BulkUpdateServiceImpl) BulkDeploy(request *bean4.BulkApplicationForEn
 			response[appKey] = pipelineResponse
 			continue
 		}
+		windowState, err := impl.deploymentWindowService.GetDeploymentWindowState(&bean6.DeploymentWindowStateRequest{
+			AppId:     pipeline.AppId,
+			EnvId:     pipeline.EnvironmentId,
+			ClusterId: env.ClusterId,
+		}, evaluatedAt)
+		if err != nil {
+			impl.logger.Errorw("error in evaluating deployment window state", "pipelineId", pipeline.Id, "err", err)
+			pipelineResponse := response[appKey]
+			pipelineResponse[pipelineKey] = false
+			response[appKey] = pipelineResponse
+			continue
+		}
+		if !windowState.IsDeploymentAllowed {
+			//deployment window closed for this pipeline, skip cd trigger
+			deploymentWindowBlocked[pipelineKey] = windowState.GetBlockedMessage()
+			pipelineResponse := response[appKey]
+			pipelineResponse[pipelineKey] = false
+			response[appKey] = pipelineResponse
+			continue
+		}
 
 		artifactsListingFilterOptions := &bean.ArtifactsListFilterOptions{
 			Limit:        10,
@@ -1376,6 +1409,9 @@ func (impl BulkUpdateServiceImpl) BulkDeploy(request *bean4.BulkApplicationForEn
 	bulkOperationResponse := &bean4.BulkApplicationForEnvironmentResponse{}
 	bulkOperationResponse.BulkApplicationForEnvironmentPayload = *request
 	bulkOperationResponse.Response = response
+	if len(deploymentWindowBlocked) > 0 {
+		bulkOperationResponse.DeploymentWindowBlocked = deploymentWindowBlocked
+	}
 	return bulkOperationResponse, nil
 }
 
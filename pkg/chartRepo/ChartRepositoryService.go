@@ -24,6 +24,7 @@ import (
 	"github.com/devtron-labs/devtron/client/argocdServer"
 	"github.com/devtron-labs/devtron/client/argocdServer/repoCredsK8sClient/bean"
 	bean2 "github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterCredentials"
 	"github.com/devtron-labs/devtron/pkg/cluster/read"
 	"io"
 	"io/ioutil"
@@ -85,12 +86,14 @@ type ChartRepositoryServiceImpl struct {
 	serverEnvConfig          *serverEnvConfig.ServerEnvConfig
 	argoClientWrapperService argocdServer.ArgoClientWrapperService
 	clusterReadService       read.ClusterReadService
+	clusterCredentialService clusterCredentials.ClusterCredentialService
 }
 
 func NewChartRepositoryServiceImpl(logger *zap.SugaredLogger, repoRepository chartRepoRepository.ChartRepoRepository, K8sUtil *util3.K8sServiceImpl,
 	aCDAuthConfig *util2.ACDAuthConfig, client *http.Client, serverEnvConfig *serverEnvConfig.ServerEnvConfig,
 	argoClientWrapperService argocdServer.ArgoClientWrapperService,
-	clusterReadService read.ClusterReadService) *ChartRepositoryServiceImpl {
+	clusterReadService read.ClusterReadService,
+	clusterCredentialService clusterCredentials.ClusterCredentialService) *ChartRepositoryServiceImpl {
 	return &ChartRepositoryServiceImpl{
 		logger:                   logger,
 		repoRepository:           repoRepository,
@@ -100,6 +103,7 @@ func NewChartRepositoryServiceImpl(logger *zap.SugaredLogger, repoRepository cha
 		serverEnvConfig:          serverEnvConfig,
 		argoClientWrapperService: argoClientWrapperService,
 		clusterReadService:       clusterReadService,
+		clusterCredentialService: clusterCredentialService,
 	}
 }
 
@@ -507,7 +511,11 @@ func (impl *ChartRepositoryServiceImpl) TriggerChartSyncManual(chartProviderConf
 		return err
 	}
 
-	defaultClusterConfig := defaultClusterBean.GetClusterConfig()
+	defaultClusterConfig, err := defaultClusterBean.ResolveClusterConfig(impl.clusterCredentialService)
+	if err != nil {
+		impl.logger.Errorw("error in resolving default cluster credentials, TriggerChartSyncManual", "err", err)
+		return err
+	}
 
 	manualAppSyncJobByteArr := manualAppSyncJobByteArr(impl.serverEnvConfig.AppSyncImage, impl.serverEnvConfig.AppSyncJobResourcesObj, impl.serverEnvConfig.AppSyncServiceAccount, chartProviderConfig, impl.serverEnvConfig.ParallelismLimitForTagProcessing)
 	err = impl.K8sUtil.DeleteAndCreateJob(manualAppSyncJobByteArr, impl.aCDAuthConfig.ACDConfigMapNamespace, defaultClusterConfig)
@@ -23,6 +23,7 @@ import (
 	"github.com/devtron-labs/common-lib/utils/k8s/commonBean"
 	"github.com/devtron-labs/devtron/pkg/cluster/adapter"
 	"github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterCredentials"
 	repository2 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
 	"github.com/devtron-labs/devtron/pkg/cluster/read"
 	cronUtil "github.com/devtron-labs/devtron/util/cron"
@@ -71,7 +72,7 @@ const (
 type ClusterService interface {
 	Save(parent context.Context, bean *bean.ClusterBean, userId int32) (*bean.ClusterBean, error)
 	UpdateClusterDescription(bean *bean.ClusterBean, userId int32) error
-	ValidateKubeconfig(kubeConfig string) (map[string]*bean.ValidateClusterBean, error)
+	ValidateKubeconfig(kubeConfig string, contexts []string) (map[string]*bean.ValidateClusterBean, error)
 	FindOne(clusterName string) (*bean.ClusterBean, error)
 	FindOneActive(clusterName string) (*bean.ClusterBean, error)
 	FindAll() ([]*bean.ClusterBean, error)
@@ -95,7 +96,9 @@ type ClusterService interface {
 	HandleErrorInClusterConnections(clusters []*bean.ClusterBean, respMap *sync.Map, clusterExistInDb bool)
 	ConnectClustersInBatch(clusters []*bean.ClusterBean, clusterExistInDb bool)
 	ConvertClusterBeanToCluster(clusterBean *bean.ClusterBean, userId int32) *repository.Cluster
-	ConvertClusterBeanObjectToCluster(bean *bean.ClusterBean) *v1alpha1.Cluster
+	// ConvertClusterBeanObjectToCluster builds the argocd cluster of the bean, exec and oidc credentials are resolved
+	// into a bearer token or client certificate as argocd can not run the plugins itself
+	ConvertClusterBeanObjectToCluster(bean *bean.ClusterBean) (*v1alpha1.Cluster, error)
 
 	GetClusterConfigByClusterId(clusterId int) (*k8s.ClusterConfig, error)
 }
@@ -109,6 +112,9 @@ type ClusterServiceImpl struct {
 	userRepository      repository3.UserRepository
 	roleGroupRepository repository3.RoleGroupRepository
 	clusterReadService  read.ClusterReadService
+	// clusterCredentialService is the provider through which exec and oidc clusters get their credentials, taking
+	// it as a dependency makes sure it is registered before any cluster is connected
+	clusterCredentialService clusterCredentials.ClusterCredentialService
 }
 
 func NewClusterServiceImpl(repository repository.ClusterRepository, logger *zap.SugaredLogger,
@@ -117,7 +123,8 @@ func NewClusterServiceImpl(repository repository.ClusterRepository, logger *zap.
 	roleGroupRepository repository3.RoleGroupRepository,
 	envVariables *globalUtil.EnvironmentVariables,
 	cronLogger *cronUtil.CronLoggerImpl,
-	clusterReadService read.ClusterReadService) (*ClusterServiceImpl, error) {
+	clusterReadService read.ClusterReadService,
+	clusterCredentialService clusterCredentials.ClusterCredentialService) (*ClusterServiceImpl, error) {
 	clusterService := &ClusterServiceImpl{
 		clusterRepository:   repository,
 		logger:              logger,
@@ -127,6 +134,8 @@ func NewClusterServiceImpl(repository repository.ClusterRepository, logger *zap.
 		userRepository:      userRepository,
 		roleGroupRepository: roleGroupRepository,
 		clusterReadService:  clusterReadService,
+
+		clusterCredentialService: clusterCredentialService,
 	}
 	// initialise cron
 	newCron := cron.New(cron.WithChain(cron.Recover(cronLogger)))
@@ -212,7 +221,11 @@ func (impl *ClusterServiceImpl) Save(parent context.Context, bean *bean.ClusterB
 
 	model := impl.ConvertClusterBeanToCluster(bean, userId)
 
-	cfg := bean.GetClusterConfig()
+	cfg, err := bean.ResolveClusterConfig(impl.clusterCredentialService)
+	if err != nil {
+		impl.logger.Errorw("error in resolving cluster credentials", "clusterName", bean.ClusterName, "err", err)
+		return nil, err
+	}
 	client, err := impl.K8sUtil.GetK8sDiscoveryClient(cfg)
 	if err != nil {
 		return nil, err
@@ -414,6 +427,15 @@ func (impl *ClusterServiceImpl) Update(ctx context.Context, bean *bean.ClusterBe
 		bean.Config[commonBean.CertificateAuthorityData] = model.Config[commonBean.CertificateAuthorityData]
 	}
 
+	// exec and oidc auth settings are not part of the update request, retain them from the stored config
+	if len(bean.Config[clusterBean.AuthType]) == 0 {
+		for _, key := range clusterBean.CredentialConfigKeys {
+			if value, ok := model.Config[key]; ok {
+				bean.Config[key] = value
+			}
+		}
+	}
+
 	if bean.ServerUrl != model.ServerUrl || bean.InsecureSkipTLSVerify != model.InsecureSkipTlsVerify || dbConfigBearerToken != requestConfigBearerToken || dbConfigTlsKey != requestConfigTlsKey || dbConfigCertData != requestConfigCertData || dbConfigCAData != requestConfigCAData {
 		if bean.ClusterName == clusterBean.DEFAULT_CLUSTER {
 			impl.logger.Errorw("default_cluster is reserved by the system and cannot be updated, default_cluster", "name", bean.ClusterName)
@@ -448,12 +470,20 @@ func (impl *ClusterServiceImpl) Update(ctx context.Context, bean *bean.ClusterBe
 	}
 	model.ErrorInConnecting = "" //setting empty because config to be updated is already validated
 	model.Active = bean.Active
+	if clusterBean.IsCredentialAuthType(model.Config[clusterBean.AuthType]) {
+		// drop credentials issued for the previous auth settings
+		impl.clusterCredentialService.InvalidateCredentials(model.ServerUrl, model.Config)
+	}
 	model.Config = bean.Config
 	model.UpdatedBy = userId
 	model.UpdatedOn = time.Now()
 
 	if model.K8sVersion == "" {
-		cfg := bean.GetClusterConfig()
+		cfg, err := bean.ResolveClusterConfig(impl.clusterCredentialService)
+		if err != nil {
+			impl.logger.Errorw("error in resolving cluster credentials", "clusterId", bean.Id, "err", err)
+			return nil, err
+		}
 		client, err := impl.K8sUtil.GetK8sDiscoveryClient(cfg)
 		if err != nil {
 			return nil, err
@@ -514,23 +544,37 @@ func (impl *ClusterServiceImpl) Update(ctx context.Context, bean *bean.ClusterBe
 }
 
 func (impl *ClusterServiceImpl) SyncNsInformer(bean *bean.ClusterBean) {
-	requestConfig := bean.Config[commonBean.BearerToken]
 	//before creating new informer for cluster, close existing one
 	impl.K8sInformerFactory.CleanNamespaceInformer(bean.ClusterName)
 	//create new informer for cluster with new config
+	impl.K8sInformerFactory.BuildInformer([]*bean2.ClusterInfo{impl.getInformerClusterInfo(*bean)})
+}
+
+func (impl *ClusterServiceImpl) getInformerClusterInfo(clusterBean bean.ClusterBean) *bean2.ClusterInfo {
+	clusterConfig, err := clusterBean.ResolveClusterConfig(impl.clusterCredentialService)
+	if err != nil {
+		impl.logger.Errorw("error in resolving cluster credentials for informer", "clusterId", clusterBean.Id, "err", err)
+	}
 	clusterInfo := &bean2.ClusterInfo{
-		ClusterId:             bean.Id,
-		ClusterName:           bean.ClusterName,
-		BearerToken:           requestConfig,
-		ServerUrl:             bean.ServerUrl,
-		InsecureSkipTLSVerify: bean.InsecureSkipTLSVerify,
-	}
-	if !bean.InsecureSkipTLSVerify {
-		clusterInfo.KeyData = bean.Config[commonBean.TlsKey]
-		clusterInfo.CertData = bean.Config[commonBean.CertData]
-		clusterInfo.CAData = bean.Config[commonBean.CertificateAuthorityData]
-	}
-	impl.K8sInformerFactory.BuildInformer([]*bean2.ClusterInfo{clusterInfo})
+		ClusterId:             clusterBean.Id,
+		ClusterName:           clusterBean.ClusterName,
+		BearerToken:           clusterConfig.BearerToken,
+		ServerUrl:             clusterBean.ServerUrl,
+		InsecureSkipTLSVerify: clusterBean.InsecureSkipTLSVerify,
+		KeyData:               clusterConfig.KeyData,
+		CertData:              clusterConfig.CertData,
+		CAData:                clusterConfig.CAData,
+	}
+	if bean.IsCredentialAuthType(clusterBean.Config[bean.AuthType]) && len(clusterConfig.CertData) == 0 {
+		clusterInfo.TokenProvider = func() (string, error) {
+			resolvedConfig, err := clusterBean.ResolveClusterConfig(impl.clusterCredentialService)
+			if err != nil {
+				return "", err
+			}
+			return resolvedConfig.BearerToken, nil
+		}
+	}
+	return clusterInfo
 }
 
 func (impl *ClusterServiceImpl) Delete(bean *bean.ClusterBean, userId int32) error {
@@ -574,17 +618,7 @@ func (impl *ClusterServiceImpl) buildInformer() {
 	var clusterInfo []*bean2.ClusterInfo
 	for _, model := range models {
 		if !model.IsVirtualCluster {
-			bearerToken := model.Config[commonBean.BearerToken]
-			clusterInfo = append(clusterInfo, &bean2.ClusterInfo{
-				ClusterId:             model.Id,
-				ClusterName:           model.ClusterName,
-				BearerToken:           bearerToken,
-				ServerUrl:             model.ServerUrl,
-				InsecureSkipTLSVerify: model.InsecureSkipTlsVerify,
-				KeyData:               model.Config[commonBean.TlsKey],
-				CertData:              model.Config[commonBean.CertData],
-				CAData:                model.Config[commonBean.CertificateAuthorityData],
-			})
+			clusterInfo = append(clusterInfo, impl.getInformerClusterInfo(adapter.GetClusterBean(model)))
 		}
 	}
 	impl.K8sInformerFactory.BuildInformer(clusterInfo)
@@ -616,7 +650,10 @@ func (impl *ClusterServiceImpl) DeleteFromDb(bean *bean.ClusterBean, userId int3
 }
 
 func (impl *ClusterServiceImpl) CheckIfConfigIsValid(cluster *bean.ClusterBean) error {
-	clusterConfig := cluster.GetClusterConfig()
+	clusterConfig, err := cluster.ResolveClusterConfig(impl.clusterCredentialService)
+	if err != nil {
+		return fmt.Errorf("Failed to fetch cluster credentials : %v", err)
+	}
 	response, err := impl.K8sUtil.DiscoveryClientGetLiveZCall(clusterConfig)
 	if err != nil {
 		if _, ok := err.(*url.Error); ok {
@@ -785,17 +822,20 @@ func (impl *ClusterServiceImpl) ConnectClustersInBatch(clusters []*bean.ClusterB
 		wg.Add(1)
 		go func(idx int, cluster *bean.ClusterBean) {
 			defer wg.Done()
-			clusterConfig := cluster.GetClusterConfig()
-			_, _, k8sClientSet, err := impl.K8sUtil.GetK8sConfigAndClients(clusterConfig)
-			if err != nil {
-				respMap.Store(cluster.Id, err)
-				return
-			}
-
 			id := cluster.Id
 			if !clusterExistInDb {
 				id = idx
 			}
+			clusterConfig, err := cluster.ResolveClusterConfig(impl.clusterCredentialService)
+			if err != nil {
+				respMap.Store(id, fmt.Errorf("error in fetching cluster credentials: %v", err))
+				return
+			}
+			_, _, k8sClientSet, err := impl.K8sUtil.GetK8sConfigAndClients(clusterConfig)
+			if err != nil {
+				respMap.Store(id, err)
+				return
+			}
 			impl.GetAndUpdateConnectionStatusForOneCluster(k8sClientSet, id, respMap)
 		}(idx, cluster)
 	}
@@ -840,7 +880,7 @@ func (impl *ClusterServiceImpl) HandleErrorInClusterConnections(clusters []*bean
 	})
 }
 
-func (impl *ClusterServiceImpl) ValidateKubeconfig(kubeConfig string) (map[string]*bean.ValidateClusterBean, error) {
+func (impl *ClusterServiceImpl) ValidateKubeconfig(kubeConfig string, contexts []string) (map[string]*bean.ValidateClusterBean, error) {
 
 	kubeConfigObject := api.Config{}
 
@@ -891,8 +931,19 @@ func (impl *ClusterServiceImpl) ValidateKubeconfig(kubeConfig string) (map[strin
 		clusterListMapWithId[c.ClusterName] = c.Id
 	}
 
+	selectedContexts := make(map[string]bool, len(contexts))
+	for _, contextName := range contexts {
+		if _, ok := kubeConfigObject.Contexts[contextName]; !ok {
+			return nil, util.NewApiError(http.StatusBadRequest, fmt.Sprintf("context %s not found in kubeconfig", contextName), fmt.Sprintf("context %s not found in kubeconfig", contextName))
+		}
+		selectedContexts[contextName] = true
+	}
+
 	userInfosMap := map[string]*bean.UserInfo{}
-	for _, ctx := range kubeConfigObject.Contexts {
+	for contextName, ctx := range kubeConfigObject.Contexts {
+		if len(selectedContexts) > 0 && !selectedContexts[contextName] {
+			continue
+		}
 		clusterBeanObject := &bean.ClusterBean{}
 		clusterName := ctx.Cluster
 		userName := ctx.AuthInfo
@@ -927,7 +978,16 @@ func (impl *ClusterServiceImpl) ValidateKubeconfig(kubeConfig string) (map[strin
 
 		Config := make(map[string]string)
 
-		if (userInfoObj == nil || userInfoObj.Token == "" && clusterObj.InsecureSkipTLSVerify) && (clusterBeanObject.ErrorInConnecting == "") {
+		credentialAuthConfig, err := getKubeconfigCredentialAuthConfig(userInfoObj)
+		if err != nil && clusterBeanObject.ErrorInConnecting == "" {
+			clusterBeanObject.ErrorInConnecting = err.Error()
+		}
+		hasCredentialAuth := len(credentialAuthConfig) > 0
+		for key, value := range credentialAuthConfig {
+			Config[key] = value
+		}
+
+		if (userInfoObj == nil || userInfoObj.Token == "" && !hasCredentialAuth && clusterObj.InsecureSkipTLSVerify) && (clusterBeanObject.ErrorInConnecting == "") {
 			clusterBeanObject.ErrorInConnecting = "token missing from the kubeconfig"
 		}
 		Config[commonBean.BearerToken] = userInfoObj.Token
@@ -938,13 +998,14 @@ func (impl *ClusterServiceImpl) ValidateKubeconfig(kubeConfig string) (map[strin
 
 		if (clusterObj != nil) && !clusterObj.InsecureSkipTLSVerify && (clusterBeanObject.ErrorInConnecting == "") {
 			missingFieldsStr := ""
-			if string(userInfoObj.ClientKeyData) == "" {
+			// exec and oidc users authenticate with issued credentials, client certificates are optional for them
+			if string(userInfoObj.ClientKeyData) == "" && !hasCredentialAuth {
 				missingFieldsStr += "client-key-data" + ", "
 			}
 			if string(clusterObj.CertificateAuthorityData) == "" {
 				missingFieldsStr += "certificate-authority-data" + ", "
 			}
-			if string(userInfoObj.ClientCertificateData) == "" {
+			if string(userInfoObj.ClientCertificateData) == "" && !hasCredentialAuth {
 				missingFieldsStr += "client-certificate-data" + ", "
 			}
 			if len(missingFieldsStr) > 0 {
@@ -1037,33 +1098,13 @@ func (impl *ClusterServiceImpl) GetAndUpdateConnectionStatusForOneCluster(k8sCli
 	respMap.Store(clusterId, err)
 }
 
-func (impl *ClusterServiceImpl) ConvertClusterBeanObjectToCluster(bean *bean.ClusterBean) *v1alpha1.Cluster {
-	configMap := bean.Config
-	serverUrl := bean.ServerUrl
-	bearerToken := ""
-	if configMap[commonBean.BearerToken] != "" {
-		bearerToken = configMap[commonBean.BearerToken]
-	}
-	tlsConfig := v1alpha1.TLSClientConfig{
-		Insecure: bean.InsecureSkipTLSVerify,
-	}
-
-	if !bean.InsecureSkipTLSVerify {
-		tlsConfig.KeyData = []byte(bean.Config[commonBean.TlsKey])
-		tlsConfig.CertData = []byte(bean.Config[commonBean.CertData])
-		tlsConfig.CAData = []byte(bean.Config[commonBean.CertificateAuthorityData])
-	}
-	cdClusterConfig := v1alpha1.ClusterConfig{
-		BearerToken:     bearerToken,
-		TLSClientConfig: tlsConfig,
-	}
-
-	cl := &v1alpha1.Cluster{
-		Name:   bean.ClusterName,
-		Server: serverUrl,
-		Config: cdClusterConfig,
+func (impl *ClusterServiceImpl) ConvertClusterBeanObjectToCluster(bean *bean.ClusterBean) (*v1alpha1.Cluster, error) {
+	clusterConfig, err := bean.ResolveClusterConfig(impl.clusterCredentialService)
+	if err != nil {
+		impl.logger.Errorw("error in resolving cluster credentials", "clusterId", bean.Id, "err", err)
+		return nil, err
 	}
-	return cl
+	return getArgoCdCluster(bean, clusterConfig), nil
 }
 
 func (impl *ClusterServiceImpl) GetClusterConfigByClusterId(clusterId int) (*k8s.ClusterConfig, error) {
@@ -1072,7 +1113,10 @@ func (impl *ClusterServiceImpl) GetClusterConfigByClusterId(clusterId int) (*k8s
 		impl.logger.Errorw("error in getting clusterBean by cluster id", "err", err, "clusterId", clusterId)
 		return nil, err
 	}
-	rq := *clusterBean
-	clusterConfig := rq.GetClusterConfig()
+	clusterConfig, err := clusterBean.ResolveClusterConfig(impl.clusterCredentialService)
+	if err != nil {
+		impl.logger.Errorw("error in resolving cluster credentials", "clusterId", clusterId, "err", err)
+		return nil, err
+	}
 	return clusterConfig, nil
 }
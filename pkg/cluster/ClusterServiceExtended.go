@@ -19,13 +19,18 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"github.com/caarlos0/env"
 	"github.com/devtron-labs/common-lib/utils/k8s/commonBean"
 	"github.com/devtron-labs/devtron/client/argocdServer"
+	"github.com/devtron-labs/devtron/pkg/cluster/adapter"
 	"github.com/devtron-labs/devtron/pkg/cluster/bean"
 	"github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
 	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/config"
+	cronUtil "github.com/devtron-labs/devtron/util/cron"
+	"github.com/robfig/cron/v3"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	cluster3 "github.com/argoproj/argo-cd/v2/pkg/apiclient/cluster"
@@ -37,6 +42,12 @@ import (
 	repository2 "github.com/devtron-labs/devtron/pkg/appStore/installedApp/repository"
 )
 
+type ArgoCdCredentialSyncConfig struct {
+	// SyncIntervalSecs is how often the credentials of exec and oidc clusters are checked, tokens pushed to argocd
+	// stay valid for at least twice this interval
+	SyncIntervalSecs int `env:"CLUSTER_ARGOCD_CREDENTIAL_SYNC_INTERVAL_SECS" envDefault:"120"`
+}
+
 // extends ClusterServiceImpl and enhances method of ClusterService with full mode specific errors
 type ClusterServiceImplExtended struct {
 	environmentRepository   repository.EnvironmentRepository
@@ -44,6 +55,9 @@ type ClusterServiceImplExtended struct {
 	installedAppRepository  repository2.InstalledAppRepository
 	argoCDClientWrapper     argocdServer.ArgoClientWrapperService
 	gitOpsConfigReadService config.GitOpsConfigReadService
+	argoCdCredentialConfig  *ArgoCdCredentialSyncConfig
+	// argoCdSyncedTokens holds the token last pushed to argocd per cluster id
+	argoCdSyncedTokens sync.Map
 	*ClusterServiceImpl
 }
 
@@ -51,16 +65,76 @@ func NewClusterServiceImplExtended(environmentRepository repository.EnvironmentR
 	grafanaClient grafana.GrafanaClient, installedAppRepository repository2.InstalledAppRepository,
 	gitOpsConfigReadService config.GitOpsConfigReadService,
 	clusterServiceImpl *ClusterServiceImpl,
-	argoCDClientWrapper argocdServer.ArgoClientWrapperService) *ClusterServiceImplExtended {
+	argoCDClientWrapper argocdServer.ArgoClientWrapperService,
+	cronLogger *cronUtil.CronLoggerImpl) (*ClusterServiceImplExtended, error) {
+	argoCdCredentialConfig := &ArgoCdCredentialSyncConfig{}
+	err := env.Parse(argoCdCredentialConfig)
+	if err != nil {
+		clusterServiceImpl.logger.Errorw("error in parsing argocd credential sync config", "err", err)
+		return nil, err
+	}
 	clusterServiceExt := &ClusterServiceImplExtended{
 		environmentRepository:   environmentRepository,
 		grafanaClient:           grafanaClient,
 		installedAppRepository:  installedAppRepository,
 		argoCDClientWrapper:     argoCDClientWrapper,
 		gitOpsConfigReadService: gitOpsConfigReadService,
+		argoCdCredentialConfig:  argoCdCredentialConfig,
 		ClusterServiceImpl:      clusterServiceImpl,
 	}
-	return clusterServiceExt
+	syncCron := cron.New(cron.WithChain(cron.SkipIfStillRunning(cronLogger), cron.Recover(cronLogger)))
+	syncCron.Start()
+	_, err = syncCron.AddFunc(fmt.Sprintf("@every %ds", argoCdCredentialConfig.SyncIntervalSecs), clusterServiceExt.syncArgoCdCredentials)
+	if err != nil {
+		clusterServiceImpl.logger.Errorw("error in starting argocd credential sync cron", "err", err)
+		return nil, err
+	}
+	return clusterServiceExt, nil
+}
+
+// syncArgoCdCredentials pushes fresh credentials of exec and oidc clusters to argocd before the ones stored in its
+// cluster secrets expire, argocd only supports the static bearer token or client certificate
+func (impl *ClusterServiceImplExtended) syncArgoCdCredentials() {
+	gitOpsConfigurationStatus, err := impl.gitOpsConfigReadService.IsGitOpsConfigured()
+	if err != nil {
+		impl.logger.Errorw("error in checking gitops configuration for argocd credential sync", "err", err)
+		return
+	}
+	if !gitOpsConfigurationStatus.IsGitOpsConfiguredAndArgoCdInstalled() {
+		return
+	}
+	clusters, err := impl.clusterRepository.FindAllActiveExceptVirtual()
+	if err != nil {
+		impl.logger.Errorw("error in fetching clusters for argocd credential sync", "err", err)
+		return
+	}
+	minValidity := 2 * time.Duration(impl.argoCdCredentialConfig.SyncIntervalSecs) * time.Second
+	for _, model := range clusters {
+		if !bean.IsCredentialAuthType(model.Config[bean.AuthType]) {
+			continue
+		}
+		credentials, err := impl.clusterCredentialService.GetCredentialsValidFor(model.Id, model.ServerUrl, model.Config, minValidity)
+		if err != nil {
+			impl.logger.Errorw("error in fetching credentials for argocd credential sync", "clusterId", model.Id, "err", err)
+			continue
+		}
+		syncedToken, ok := impl.argoCdSyncedTokens.Load(model.Id)
+		if ok && syncedToken == credentials.Token+credentials.CertData {
+			continue
+		}
+		clusterBean := adapter.GetClusterBean(model)
+		clusterConfig, err := clusterBean.ResolveClusterConfig(impl.clusterCredentialService)
+		if err != nil {
+			impl.logger.Errorw("error in resolving cluster config for argocd credential sync", "clusterId", model.Id, "err", err)
+			continue
+		}
+		_, err = impl.argoCDClientWrapper.UpdateCluster(context.Background(), &cluster3.ClusterUpdateRequest{Cluster: getArgoCdCluster(&clusterBean, clusterConfig)})
+		if err != nil {
+			impl.logger.Errorw("error in updating cluster credentials in argocd", "clusterId", model.Id, "err", err)
+			continue
+		}
+		impl.argoCdSyncedTokens.Store(model.Id, credentials.Token+credentials.CertData)
+	}
 }
 
 func (impl *ClusterServiceImplExtended) FindAllWithoutConfig() ([]*bean.ClusterBean, error) {
@@ -238,33 +312,10 @@ func (impl *ClusterServiceImplExtended) Update(ctx context.Context, bean *bean.C
 
 	// if git-ops configured, then only update cluster in ACD, otherwise ignore
 	if gitOpsConfigurationStatus.IsGitOpsConfiguredAndArgoCdInstalled() {
-		configMap := bean.Config
-		serverUrl := bean.ServerUrl
-		bearerToken := ""
-		if configMap[commonBean.BearerToken] != "" {
-			bearerToken = configMap[commonBean.BearerToken]
-		}
-
-		tlsConfig := v1alpha1.TLSClientConfig{
-			Insecure: bean.InsecureSkipTLSVerify,
-		}
-		if !bean.InsecureSkipTLSVerify {
-			tlsConfig.KeyData = []byte(configMap[commonBean.TlsKey])
-			tlsConfig.CertData = []byte(configMap[commonBean.CertData])
-			tlsConfig.CAData = []byte(configMap[commonBean.CertificateAuthorityData])
-		}
-
-		cdClusterConfig := v1alpha1.ClusterConfig{
-			BearerToken:     bearerToken,
-			TLSClientConfig: tlsConfig,
-		}
-
-		cl := &v1alpha1.Cluster{
-			Name:   bean.ClusterName,
-			Server: serverUrl,
-			Config: cdClusterConfig,
+		cl, err := impl.ConvertClusterBeanObjectToCluster(bean)
+		if err != nil {
+			return nil, err
 		}
-
 		_, err = impl.argoCDClientWrapper.UpdateCluster(ctx, &cluster3.ClusterUpdateRequest{Cluster: cl})
 
 		if err != nil {
@@ -352,9 +403,11 @@ func (impl *ClusterServiceImplExtended) Save(ctx context.Context, bean *bean.Clu
 	// if git-ops configured, then only add cluster in ACD, otherwise ignore
 	if gitOpsConfigurationStatus.IsGitOpsConfiguredAndArgoCdInstalled() {
 		//create it into argo cd as well
-		cl := impl.ConvertClusterBeanObjectToCluster(bean)
-
-		_, err = impl.argoCDClientWrapper.CreateCluster(ctx, &cluster3.ClusterCreateRequest{Upsert: true, Cluster: cl})
+		var cl *v1alpha1.Cluster
+		cl, err = impl.ConvertClusterBeanObjectToCluster(bean)
+		if err == nil {
+			_, err = impl.argoCDClientWrapper.CreateCluster(ctx, &cluster3.ClusterCreateRequest{Upsert: true, Cluster: cl})
+		}
 		if err != nil {
 			impl.logger.Errorw("service err, Save", "err", err, "payload", cl)
 			err1 := impl.ClusterServiceImpl.Delete(bean, userId) //FIXME nishant call local
@@ -17,6 +17,7 @@
 package adapter
 
 import (
+	"github.com/devtron-labs/devtron/api/helm-app/gRPC"
 	"github.com/devtron-labs/devtron/pkg/cluster/bean"
 	"github.com/devtron-labs/devtron/pkg/cluster/repository"
 )
@@ -36,6 +37,7 @@ func GetClusterBean(model repository.Cluster) bean.ClusterBean {
 	clusterBean.IsVirtualCluster = model.IsVirtualCluster
 	clusterBean.ErrorInConnecting = model.ErrorInConnecting
 	clusterBean.IsProd = model.IsProd
+	clusterBean.Capabilities = model.Capabilities
 	clusterBean.PrometheusAuth = &bean.PrometheusAuth{
 		UserName:      model.PUserName,
 		Password:      model.PPassword,
@@ -44,3 +46,22 @@ func GetClusterBean(model repository.Cluster) bean.ClusterBean {
 	}
 	return clusterBean
 }
+
+// GetGrpcClusterConfig builds the kubelink cluster config of a cluster, resolving the short-lived credentials of
+// exec and oidc clusters. The config is returned along with the error so that list calls can still report the cluster.
+func GetGrpcClusterConfig(clusterBean bean.ClusterBean, provider bean.ClusterCredentialProvider) (*gRPC.ClusterConfig, error) {
+	clusterConfig, err := clusterBean.ResolveClusterConfig(provider)
+	config := &gRPC.ClusterConfig{
+		ApiServerUrl:          clusterBean.ServerUrl,
+		Token:                 clusterConfig.BearerToken,
+		ClusterId:             int32(clusterBean.Id),
+		ClusterName:           clusterBean.ClusterName,
+		InsecureSkipTLSVerify: clusterBean.InsecureSkipTLSVerify,
+	}
+	if clusterBean.InsecureSkipTLSVerify == false {
+		config.KeyData = clusterConfig.KeyData
+		config.CertData = clusterConfig.CertData
+		config.CaData = clusterConfig.CAData
+	}
+	return config, err
+}
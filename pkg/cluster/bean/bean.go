@@ -1,8 +1,10 @@
 package bean
 
 import (
+	"fmt"
 	"github.com/devtron-labs/common-lib/utils/k8s"
 	"github.com/devtron-labs/common-lib/utils/k8s/commonBean"
+	"time"
 )
 
 const (
@@ -10,6 +12,60 @@ const (
 	DEFAULT_CLUSTER  = "default_cluster"
 )
 
+// config keys used for clusters authenticating through a kubeconfig exec plugin or oidc auth provider
+const (
+	AuthType              = "auth_type"
+	ExecConfig            = "exec_config"
+	OidcIssuerUrl         = "oidc_issuer_url"
+	OidcClientId          = "oidc_client_id"
+	OidcClientSecret      = "oidc_client_secret"
+	OidcRefreshToken      = "oidc_refresh_token"
+	OidcIdToken           = "oidc_id_token"
+	OidcCertAuthorityData = "oidc_certificate_authority_data"
+)
+
+// CredentialConfigKeys are all config keys holding exec or oidc auth settings of a cluster
+var CredentialConfigKeys = []string{AuthType, ExecConfig, OidcIssuerUrl, OidcClientId, OidcClientSecret, OidcRefreshToken, OidcIdToken, OidcCertAuthorityData}
+
+const (
+	AuthTypeExec = "exec"
+	AuthTypeOidc = "oidc"
+)
+
+// capabilities detected on a cluster through its discovery api
+const (
+	CapabilityArgoRollouts = "argoRollouts"
+	CapabilityKeda         = "keda"
+	CapabilityIstio        = "istio"
+	CapabilityCertManager  = "certManager"
+)
+
+// ExecAuthConfig is the exec plugin section of a kubeconfig user, stored as json against ExecConfig
+type ExecAuthConfig struct {
+	ApiVersion string            `json:"apiVersion,omitempty"`
+	Command    string            `json:"command"`
+	Args       []string          `json:"args,omitempty"`
+	Env        map[string]string `json:"env,omitempty"`
+}
+
+// ClusterCredentials are short-lived credentials issued by an exec plugin or oidc provider
+type ClusterCredentials struct {
+	Token     string
+	CertData  string
+	KeyData   string
+	ExpiresOn time.Time
+	HasExpiry bool
+}
+
+// ClusterCredentialProvider resolves credentials for clusters whose config carries an AuthType
+type ClusterCredentialProvider interface {
+	GetCredentials(clusterId int, serverUrl string, config map[string]string) (*ClusterCredentials, error)
+}
+
+func IsCredentialAuthType(authType string) bool {
+	return authType == AuthTypeExec || authType == AuthTypeOidc
+}
+
 type PrometheusAuth struct {
 	UserName      string `json:"userName,omitempty"`
 	Password      string `json:"password,omitempty"`
@@ -38,8 +94,11 @@ type ClusterBean struct {
 	IsVirtualCluster        bool                       `json:"isVirtualCluster"`
 	ClusterUpdated          bool                       `json:"clusterUpdated"`
 	IsProd                  bool                       `json:"isProd"`
+	Capabilities            map[string]bool            `json:"capabilities,omitempty"`
 }
 
+// GetClusterConfig builds the cluster config from the stored credentials only, use ResolveClusterConfig to talk to
+// the cluster as exec and oidc clusters do not store their credentials
 func (bean ClusterBean) GetClusterConfig() *k8s.ClusterConfig {
 	host := bean.ServerUrl
 	configMap := bean.Config
@@ -54,6 +113,29 @@ func (bean ClusterBean) GetClusterConfig() *k8s.ClusterConfig {
 	return clusterCfg
 }
 
+// ResolveClusterConfig builds the cluster config like GetClusterConfig, fetching the short-lived credentials of exec
+// and oidc clusters from the provider. The error faced while fetching them is returned so that callers can surface it.
+func (bean ClusterBean) ResolveClusterConfig(provider ClusterCredentialProvider) (*k8s.ClusterConfig, error) {
+	clusterCfg := bean.GetClusterConfig()
+	configMap := bean.Config
+	if !IsCredentialAuthType(configMap[AuthType]) {
+		return clusterCfg, nil
+	}
+	if provider == nil {
+		return clusterCfg, fmt.Errorf("no credential provider for auth type %s", configMap[AuthType])
+	}
+	credentials, err := provider.GetCredentials(bean.Id, bean.ServerUrl, configMap)
+	if err != nil {
+		return clusterCfg, err
+	}
+	clusterCfg.BearerToken = credentials.Token
+	if len(credentials.CertData) > 0 && len(credentials.KeyData) > 0 {
+		clusterCfg.CertData = credentials.CertData
+		clusterCfg.KeyData = credentials.KeyData
+	}
+	return clusterCfg, nil
+}
+
 type UserInfo struct {
 	UserName          string            `json:"userName,omitempty"`
 	Config            map[string]string `json:"config,omitempty"`
@@ -71,6 +153,8 @@ type UserClusterBeanMapping struct {
 
 type Kubeconfig struct {
 	Config string `json:"config"`
+	// Contexts limits the import to the given kubeconfig contexts, all contexts are imported when empty
+	Contexts []string `json:"contexts,omitempty"`
 }
 
 type DefaultClusterComponent struct {
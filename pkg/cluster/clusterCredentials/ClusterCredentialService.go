@@ -0,0 +1,291 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clusterCredentials
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ClusterCredentialService issues short-lived credentials for clusters imported with exec plugin or oidc kubeconfig users
+type ClusterCredentialService interface {
+	bean.ClusterCredentialProvider
+	// GetCredentialsValidFor returns credentials that stay valid for at least minValidity, fetching new ones when the
+	// cached credentials expire earlier. Used where credentials are handed over to systems that can not refresh them.
+	GetCredentialsValidFor(clusterId int, serverUrl string, config map[string]string, minValidity time.Duration) (*bean.ClusterCredentials, error)
+	InvalidateCredentials(serverUrl string, config map[string]string)
+}
+
+type ClusterCredentialServiceImpl struct {
+	logger            *zap.SugaredLogger
+	clusterRepository repository.ClusterRepository
+	config            *ClusterCredentialConfig
+	cache             map[string]*cachedCredentials
+	cacheLock         sync.RWMutex
+	// fetchLocks serialises credential fetches per cache key so that a plugin is not executed concurrently for a cluster
+	fetchLocks sync.Map
+}
+
+func NewClusterCredentialServiceImpl(logger *zap.SugaredLogger, clusterRepository repository.ClusterRepository) (*ClusterCredentialServiceImpl, error) {
+	config := &ClusterCredentialConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		logger.Errorw("error in parsing cluster credential config", "err", err)
+		return nil, err
+	}
+	impl := &ClusterCredentialServiceImpl{
+		logger:            logger,
+		clusterRepository: clusterRepository,
+		config:            config,
+		cache:             make(map[string]*cachedCredentials),
+	}
+	return impl, nil
+}
+
+func (impl *ClusterCredentialServiceImpl) GetCredentials(clusterId int, serverUrl string, config map[string]string) (*bean.ClusterCredentials, error) {
+	return impl.GetCredentialsValidFor(clusterId, serverUrl, config, credentialExpiryBuffer)
+}
+
+func (impl *ClusterCredentialServiceImpl) GetCredentialsValidFor(clusterId int, serverUrl string, config map[string]string, minValidity time.Duration) (*bean.ClusterCredentials, error) {
+	cacheKey := getCredentialCacheKey(serverUrl, config)
+	if credentials := impl.getCachedCredentials(cacheKey, minValidity); credentials != nil {
+		return credentials, nil
+	}
+	fetchLock, _ := impl.fetchLocks.LoadOrStore(cacheKey, &sync.Mutex{})
+	fetchLock.(*sync.Mutex).Lock()
+	defer fetchLock.(*sync.Mutex).Unlock()
+	// credentials could have been fetched while waiting for the lock
+	if credentials := impl.getCachedCredentials(cacheKey, minValidity); credentials != nil {
+		return credentials, nil
+	}
+	var credentials *bean.ClusterCredentials
+	var err error
+	switch config[bean.AuthType] {
+	case bean.AuthTypeExec:
+		credentials, err = impl.getExecCredentials(config)
+	case bean.AuthTypeOidc:
+		credentials, err = impl.getOidcCredentials(clusterId, config, minValidity)
+	default:
+		err = fmt.Errorf("unsupported cluster auth type %q", config[bean.AuthType])
+	}
+	if err != nil {
+		impl.logger.Errorw("error in fetching cluster credentials", "clusterId", clusterId, "authType", config[bean.AuthType], "err", err)
+		return nil, err
+	}
+	impl.cacheCredentials(cacheKey, credentials)
+	return credentials, nil
+}
+
+func (impl *ClusterCredentialServiceImpl) InvalidateCredentials(serverUrl string, config map[string]string) {
+	impl.cacheLock.Lock()
+	defer impl.cacheLock.Unlock()
+	delete(impl.cache, getCredentialCacheKey(serverUrl, config))
+}
+
+func (impl *ClusterCredentialServiceImpl) getCachedCredentials(cacheKey string, minValidity time.Duration) *bean.ClusterCredentials {
+	impl.cacheLock.RLock()
+	defer impl.cacheLock.RUnlock()
+	cached, ok := impl.cache[cacheKey]
+	if !ok || !cached.isValidFor(time.Now(), minValidity) {
+		return nil
+	}
+	return cached.credentials
+}
+
+func (impl *ClusterCredentialServiceImpl) cacheCredentials(cacheKey string, credentials *bean.ClusterCredentials) {
+	validTill := time.Now().Add(time.Duration(impl.config.CredentialCacheTtlSecs) * time.Second)
+	if credentials.HasExpiry {
+		validTill = credentials.ExpiresOn.Add(-credentialExpiryBuffer)
+	}
+	impl.cacheLock.Lock()
+	defer impl.cacheLock.Unlock()
+	impl.cache[cacheKey] = &cachedCredentials{credentials: credentials, validTill: validTill}
+}
+
+func (impl *ClusterCredentialServiceImpl) getExecCredentials(config map[string]string) (*bean.ClusterCredentials, error) {
+	if !impl.config.ExecAuthEnabled {
+		return nil, errors.New("exec plugin auth is disabled, set CLUSTER_EXEC_AUTH_ENABLED to enable it")
+	}
+	execConfig := &bean.ExecAuthConfig{}
+	err := json.Unmarshal([]byte(config[bean.ExecConfig]), execConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exec config: %v", err)
+	}
+	// only the binary name is honoured, plugins are always resolved from PATH
+	command := filepath.Base(execConfig.Command)
+	if !impl.config.isCommandAllowed(command) {
+		return nil, fmt.Errorf("exec plugin %q is not allowed, allowed plugins can be configured via CLUSTER_EXEC_AUTH_ALLOWED_COMMANDS", command)
+	}
+	if err = validateExecArgs(command, execConfig.Args); err != nil {
+		return nil, err
+	}
+	if err = validateExecEnv(execConfig.Env); err != nil {
+		return nil, err
+	}
+	commandPath, err := exec.LookPath(command)
+	if err != nil {
+		return nil, fmt.Errorf("exec plugin %q not found: %v", command, err)
+	}
+	apiVersion := execConfig.ApiVersion
+	if len(apiVersion) == 0 {
+		apiVersion = defaultExecCredentialVersion
+	}
+	execInfo, err := getExecInfo(apiVersion)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(impl.config.CredentialTimeoutSecs)*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, commandPath, execConfig.Args...)
+	cmd.Env = getExecEnv(execConfig.Env, execInfo)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if err != nil {
+		// stderr can carry credentials or details of the orchestrator's environment, it is only logged
+		impl.logger.Errorw("error in running exec plugin", "command", command, "err", err, "stderr", truncateCommandError(stderr.String()))
+		return nil, fmt.Errorf("exec plugin %q failed: %v, check the orchestrator logs for details", command, err)
+	}
+	return parseExecCredential(stdout.Bytes(), apiVersion)
+}
+
+func (impl *ClusterCredentialServiceImpl) getOidcCredentials(clusterId int, config map[string]string, minValidity time.Duration) (*bean.ClusterCredentials, error) {
+	idToken := config[bean.OidcIdToken]
+	if !isIdTokenValid(idToken, time.Now(), minValidity) {
+		if len(config[bean.OidcRefreshToken]) == 0 {
+			return nil, errors.New("oidc id token has expired and no refresh token is configured")
+		}
+		refreshedToken, err := impl.refreshOidcToken(clusterId, config)
+		if err != nil {
+			return nil, err
+		}
+		idToken = refreshedToken
+	}
+	credentials := &bean.ClusterCredentials{Token: idToken}
+	if expiry, err := getIdTokenExpiry(idToken); err == nil {
+		credentials.ExpiresOn = expiry
+		credentials.HasExpiry = true
+	}
+	return credentials, nil
+}
+
+func (impl *ClusterCredentialServiceImpl) refreshOidcToken(clusterId int, config map[string]string) (string, error) {
+	httpClient, err := impl.getOidcHttpClient(config[bean.OidcCertAuthorityData])
+	if err != nil {
+		return "", err
+	}
+	tokenEndpoint, err := impl.getOidcTokenEndpoint(httpClient, config[bean.OidcIssuerUrl])
+	if err != nil {
+		return "", err
+	}
+	oauthConfig := &oauth2.Config{
+		ClientID:     config[bean.OidcClientId],
+		ClientSecret: config[bean.OidcClientSecret],
+		Endpoint:     oauth2.Endpoint{TokenURL: tokenEndpoint},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(impl.config.CredentialTimeoutSecs)*time.Second)
+	defer cancel()
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+	token, err := oauthConfig.TokenSource(ctx, &oauth2.Token{RefreshToken: config[bean.OidcRefreshToken]}).Token()
+	if err != nil {
+		return "", fmt.Errorf("error in refreshing oidc token: %v", err)
+	}
+	idToken, ok := token.Extra(oidcIdTokenExtraKey).(string)
+	if !ok || len(idToken) == 0 {
+		return "", errors.New("oidc token response does not contain an id_token")
+	}
+	refreshToken := config[bean.OidcRefreshToken]
+	if len(token.RefreshToken) > 0 {
+		refreshToken = token.RefreshToken
+	}
+	impl.saveOidcTokens(clusterId, idToken, refreshToken)
+	return idToken, nil
+}
+
+// saveOidcTokens persists the refreshed tokens as providers may rotate the refresh token on every use
+func (impl *ClusterCredentialServiceImpl) saveOidcTokens(clusterId int, idToken, refreshToken string) {
+	if clusterId == 0 {
+		return
+	}
+	model, err := impl.clusterRepository.FindById(clusterId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cluster for saving oidc tokens", "clusterId", clusterId, "err", err)
+		return
+	}
+	if model.Config == nil || model.Config[bean.AuthType] != bean.AuthTypeOidc {
+		return
+	}
+	model.Config[bean.OidcIdToken] = idToken
+	model.Config[bean.OidcRefreshToken] = refreshToken
+	model.UpdatedOn = time.Now()
+	err = impl.clusterRepository.Update(model)
+	if err != nil {
+		impl.logger.Errorw("error in saving refreshed oidc tokens", "clusterId", clusterId, "err", err)
+	}
+}
+
+func (impl *ClusterCredentialServiceImpl) getOidcHttpClient(caData string) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if len(caData) > 0 {
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(decodeCertificateAuthorityData(caData)) {
+			return nil, errors.New("invalid idp-certificate-authority-data")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: certPool}
+	}
+	return &http.Client{
+		Transport: transport,
+		Timeout:   time.Duration(impl.config.CredentialTimeoutSecs) * time.Second,
+	}, nil
+}
+
+func (impl *ClusterCredentialServiceImpl) getOidcTokenEndpoint(httpClient *http.Client, issuerUrl string) (string, error) {
+	response, err := httpClient.Get(strings.TrimSuffix(issuerUrl, "/") + oidcDiscoveryPath)
+	if err != nil {
+		return "", fmt.Errorf("error in fetching oidc discovery document: %v", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("oidc discovery returned status %d", response.StatusCode)
+	}
+	discovery := &oidcDiscoveryDocument{}
+	err = json.NewDecoder(response.Body).Decode(discovery)
+	if err != nil {
+		return "", fmt.Errorf("error in reading oidc discovery document: %v", err)
+	}
+	if len(discovery.TokenEndpoint) == 0 {
+		return "", errors.New("token_endpoint missing from oidc discovery document")
+	}
+	return discovery.TokenEndpoint, nil
+}
@@ -0,0 +1,302 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clusterCredentials
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientAuthV1 "k8s.io/client-go/pkg/apis/clientauthentication/v1"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	execCredentialKind            = "ExecCredential"
+	defaultExecCredentialVersion  = "client.authentication.k8s.io/v1beta1"
+	kubernetesExecInfoEnv         = "KUBERNETES_EXEC_INFO"
+	oidcDiscoveryPath             = "/.well-known/openid-configuration"
+	oidcIdTokenExtraKey           = "id_token"
+	credentialExpiryBuffer        = time.Minute
+	maxCredentialCommandErrLength = 500
+)
+
+type ClusterCredentialConfig struct {
+	ExecAuthEnabled        bool   `env:"CLUSTER_EXEC_AUTH_ENABLED" envDefault:"false"`
+	ExecAllowedCommands    string `env:"CLUSTER_EXEC_AUTH_ALLOWED_COMMANDS" envDefault:"aws,aws-iam-authenticator,gke-gcloud-auth-plugin,kubelogin"`
+	CredentialTimeoutSecs  int    `env:"CLUSTER_CREDENTIAL_TIMEOUT_SECS" envDefault:"30"`
+	CredentialCacheTtlSecs int    `env:"CLUSTER_CREDENTIAL_CACHE_TTL_SECS" envDefault:"600"`
+}
+
+func (cfg *ClusterCredentialConfig) isCommandAllowed(command string) bool {
+	for _, allowedCommand := range strings.Split(cfg.ExecAllowedCommands, ",") {
+		if strings.TrimSpace(allowedCommand) == command {
+			return true
+		}
+	}
+	return false
+}
+
+// execPluginArgSpec lists the arguments a plugin is run with, flags that read local files or alter where the plugin
+// loads its configuration from are left out so that a cluster config can not make a plugin use the orchestrator's files
+type execPluginArgSpec struct {
+	// subCommand are the leading positional arguments every invocation must start with
+	subCommand []string
+	// valueFlags take a value, either as --flag=value or as the next argument
+	valueFlags map[string]bool
+	boolFlags  map[string]bool
+}
+
+var execPluginArgSpecs = map[string]execPluginArgSpec{
+	"aws": {
+		subCommand: []string{"eks", "get-token"},
+		valueFlags: toSet("--cluster-name", "--cluster-id", "--region", "--role-arn", "--output", "--duration-seconds"),
+	},
+	"aws-iam-authenticator": {
+		subCommand: []string{"token"},
+		valueFlags: toSet("-i", "--cluster-id", "-r", "--role", "--region", "--session-name"),
+		boolFlags:  toSet("--forward-session-name"),
+	},
+	"gke-gcloud-auth-plugin": {
+		boolFlags: toSet("--use_application_default_credentials"),
+	},
+	"kubelogin": {
+		subCommand: []string{"get-token"},
+		valueFlags: toSet("-l", "--login", "--server-id", "--client-id", "--tenant-id", "-e", "--environment", "--client-secret"),
+		boolFlags:  toSet("--legacy"),
+	},
+}
+
+// execBaseEnvNames are the only variables of the orchestrator's environment passed on to exec plugins
+var execBaseEnvNames = []string{"PATH", "HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "http_proxy", "https_proxy", "no_proxy"}
+
+// blockedExecEnvNames and blockedExecEnvPrefixes can not be set by the exec config of a cluster as they change which
+// binaries or libraries the plugin loads
+var blockedExecEnvNames = toSet("PATH", "HOME", "BASH_ENV", "ENV", "SHELLOPTS", "BASHOPTS", "PS4", "IFS", "CDPATH",
+	"GCONV_PATH", "PYTHONPATH", "PYTHONSTARTUP", "PYTHONHOME", "NODE_OPTIONS", "PERLLIB", "PERL5LIB", "PERL5OPT",
+	"RUBYLIB", "RUBYOPT", "GODEBUG", kubernetesExecInfoEnv)
+
+var blockedExecEnvPrefixes = []string{"LD_", "DYLD_", "BASH_FUNC_"}
+
+func toSet(values ...string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
+
+// validateExecArgs only accepts the invocations described in execPluginArgSpecs, plugins allowed through
+// CLUSTER_EXEC_AUTH_ALLOWED_COMMANDS without a spec are run without arguments
+func validateExecArgs(command string, args []string) error {
+	spec, ok := execPluginArgSpecs[command]
+	if !ok {
+		if len(args) > 0 {
+			return fmt.Errorf("arguments are not supported for exec plugin %q", command)
+		}
+		return nil
+	}
+	if len(args) < len(spec.subCommand) {
+		return fmt.Errorf("exec plugin %q must be invoked as %q", command, strings.Join(append([]string{command}, spec.subCommand...), " "))
+	}
+	for i, subCommand := range spec.subCommand {
+		if args[i] != subCommand {
+			return fmt.Errorf("exec plugin %q must be invoked as %q", command, strings.Join(append([]string{command}, spec.subCommand...), " "))
+		}
+	}
+	flags := args[len(spec.subCommand):]
+	for i := 0; i < len(flags); i++ {
+		flag, _, hasValue := strings.Cut(flags[i], "=")
+		switch {
+		case spec.boolFlags[flag] && !hasValue:
+		case spec.valueFlags[flag] && hasValue:
+		case spec.valueFlags[flag]:
+			if i+1 >= len(flags) {
+				return fmt.Errorf("value missing for argument %q of exec plugin %q", flag, command)
+			}
+			i++
+		default:
+			return fmt.Errorf("argument %q is not allowed for exec plugin %q", flag, command)
+		}
+	}
+	return nil
+}
+
+func validateExecEnv(execEnv map[string]string) error {
+	for name := range execEnv {
+		if len(name) == 0 || strings.ContainsAny(name, "=\x00") {
+			return fmt.Errorf("invalid environment variable name %q in exec config", name)
+		}
+		if blockedExecEnvNames[name] {
+			return fmt.Errorf("environment variable %q can not be set in exec config", name)
+		}
+		for _, prefix := range blockedExecEnvPrefixes {
+			if strings.HasPrefix(name, prefix) {
+				return fmt.Errorf("environment variable %q can not be set in exec config", name)
+			}
+		}
+	}
+	return nil
+}
+
+// getExecEnv builds the environment of an exec plugin from execBaseEnvNames and the exec config, credentials and
+// settings of the orchestrator itself are never inherited. HOME points to a scratch directory for plugin caches.
+func getExecEnv(execEnv map[string]string, execInfo string) []string {
+	cmdEnv := make([]string, 0, len(execBaseEnvNames)+len(execEnv)+2)
+	for _, name := range execBaseEnvNames {
+		if value, ok := os.LookupEnv(name); ok {
+			cmdEnv = append(cmdEnv, fmt.Sprintf("%s=%s", name, value))
+		}
+	}
+	cmdEnv = append(cmdEnv, fmt.Sprintf("HOME=%s", os.TempDir()))
+	for name, value := range execEnv {
+		cmdEnv = append(cmdEnv, fmt.Sprintf("%s=%s", name, value))
+	}
+	return append(cmdEnv, fmt.Sprintf("%s=%s", kubernetesExecInfoEnv, execInfo))
+}
+
+type cachedCredentials struct {
+	credentials *bean.ClusterCredentials
+	validTill   time.Time
+}
+
+func (cached *cachedCredentials) isValidFor(now time.Time, minValidity time.Duration) bool {
+	if now.After(cached.validTill) {
+		return false
+	}
+	return !cached.credentials.HasExpiry || cached.credentials.ExpiresOn.After(now.Add(minValidity))
+}
+
+type oidcDiscoveryDocument struct {
+	TokenEndpoint string `json:"token_endpoint"`
+}
+
+// getCredentialCacheKey identifies the auth settings of a cluster, the id token is left out so that
+// credentials refreshed through the refresh token are reused by beans still carrying the old id token
+func getCredentialCacheKey(serverUrl string, config map[string]string) string {
+	hash := sha256.New()
+	for _, value := range []string{serverUrl, config[bean.AuthType], config[bean.ExecConfig], config[bean.OidcIssuerUrl],
+		config[bean.OidcClientId], config[bean.OidcRefreshToken]} {
+		hash.Write([]byte(value))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func getExecInfo(apiVersion string) (string, error) {
+	execInfo := &clientAuthV1.ExecCredential{
+		TypeMeta: v1.TypeMeta{
+			Kind:       execCredentialKind,
+			APIVersion: apiVersion,
+		},
+		Spec: clientAuthV1.ExecCredentialSpec{Interactive: false},
+	}
+	execInfoJson, err := json.Marshal(execInfo)
+	if err != nil {
+		return "", err
+	}
+	return string(execInfoJson), nil
+}
+
+// parseExecCredential reads the ExecCredential printed by an exec plugin, v1 and v1beta1 share the same status shape
+func parseExecCredential(output []byte, apiVersion string) (*bean.ClusterCredentials, error) {
+	execCredential := &clientAuthV1.ExecCredential{}
+	err := json.Unmarshal(output, execCredential)
+	if err != nil {
+		return nil, fmt.Errorf("exec plugin output is not a valid ExecCredential: %v", err)
+	}
+	if execCredential.Kind != execCredentialKind {
+		return nil, fmt.Errorf("exec plugin returned kind %q, expected %s", execCredential.Kind, execCredentialKind)
+	}
+	if execCredential.APIVersion != apiVersion {
+		return nil, fmt.Errorf("exec plugin returned api version %q, expected %s", execCredential.APIVersion, apiVersion)
+	}
+	status := execCredential.Status
+	if status == nil {
+		return nil, errors.New("exec plugin did not return a status")
+	}
+	if len(status.Token) == 0 && (len(status.ClientCertificateData) == 0 || len(status.ClientKeyData) == 0) {
+		return nil, errors.New("exec plugin returned neither a token nor a client certificate and key")
+	}
+	credentials := &bean.ClusterCredentials{
+		Token:    status.Token,
+		CertData: status.ClientCertificateData,
+		KeyData:  status.ClientKeyData,
+	}
+	if status.ExpirationTimestamp != nil && !status.ExpirationTimestamp.IsZero() {
+		credentials.ExpiresOn = status.ExpirationTimestamp.Time
+		credentials.HasExpiry = true
+	}
+	return credentials, nil
+}
+
+// getIdTokenExpiry reads the exp claim of an oidc id token without verifying it, the api server verifies the token
+func getIdTokenExpiry(idToken string) (time.Time, error) {
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		return time.Time{}, errors.New("id token is not a valid jwt")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error in decoding id token payload: %v", err)
+	}
+	claims := struct {
+		Exp int64 `json:"exp"`
+	}{}
+	err = json.Unmarshal(payload, &claims)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error in reading id token claims: %v", err)
+	}
+	if claims.Exp == 0 {
+		return time.Time{}, errors.New("exp claim missing from id token")
+	}
+	return time.Unix(claims.Exp, 0), nil
+}
+
+func isIdTokenValid(idToken string, now time.Time, minValidity time.Duration) bool {
+	if len(idToken) == 0 {
+		return false
+	}
+	expiry, err := getIdTokenExpiry(idToken)
+	if err != nil {
+		return false
+	}
+	return expiry.After(now.Add(minValidity))
+}
+
+// decodeCertificateAuthorityData accepts the base64 encoded pem used in kubeconfigs as well as plain pem
+func decodeCertificateAuthorityData(caData string) []byte {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(caData))
+	if err != nil {
+		return []byte(caData)
+	}
+	return decoded
+}
+
+func truncateCommandError(message string) string {
+	message = strings.TrimSpace(message)
+	if len(message) > maxCredentialCommandErrLength {
+		return message[:maxCredentialCommandErrLength]
+	}
+	return message
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clusterCredentials
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func testIdToken(exp int64) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"iss":"https://issuer","exp":%d}`, exp)))
+	return "eyJhbGciOiJSUzI1NiJ9." + payload + ".c2lnbmF0dXJl"
+}
+
+func TestParseExecCredential(t *testing.T) {
+	output := []byte(`{"kind":"ExecCredential","apiVersion":"client.authentication.k8s.io/v1beta1",
+		"status":{"token":"k8s-aws-v1.token","expirationTimestamp":"2030-01-02T15:04:05Z"}}`)
+	credentials, err := parseExecCredential(output, "client.authentication.k8s.io/v1beta1")
+	assert.Nil(t, err)
+	assert.Equal(t, "k8s-aws-v1.token", credentials.Token)
+	assert.True(t, credentials.HasExpiry)
+	assert.Equal(t, time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC), credentials.ExpiresOn.UTC())
+
+	certOutput := []byte(`{"kind":"ExecCredential","apiVersion":"client.authentication.k8s.io/v1",
+		"status":{"clientCertificateData":"cert","clientKeyData":"key"}}`)
+	credentials, err = parseExecCredential(certOutput, "client.authentication.k8s.io/v1")
+	assert.Nil(t, err)
+	assert.Equal(t, "cert", credentials.CertData)
+	assert.Equal(t, "key", credentials.KeyData)
+	assert.False(t, credentials.HasExpiry)
+
+	_, err = parseExecCredential(output, "client.authentication.k8s.io/v1")
+	assert.NotNil(t, err, "api version mismatch")
+	_, err = parseExecCredential([]byte(`{"kind":"ExecCredential","apiVersion":"client.authentication.k8s.io/v1","status":{}}`), "client.authentication.k8s.io/v1")
+	assert.NotNil(t, err, "empty status")
+	_, err = parseExecCredential([]byte("not json"), "client.authentication.k8s.io/v1")
+	assert.NotNil(t, err)
+}
+
+func TestIdTokenExpiry(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	expiry, err := getIdTokenExpiry(testIdToken(1700003600))
+	assert.Nil(t, err)
+	assert.Equal(t, time.Unix(1700003600, 0), expiry)
+	assert.True(t, isIdTokenValid(testIdToken(1700003600), now, credentialExpiryBuffer))
+	// tokens expiring within the buffer are refreshed ahead of time
+	assert.False(t, isIdTokenValid(testIdToken(1700000030), now, credentialExpiryBuffer))
+	assert.False(t, isIdTokenValid(testIdToken(1700000600), now, 15*time.Minute))
+	assert.False(t, isIdTokenValid("", now, credentialExpiryBuffer))
+	assert.False(t, isIdTokenValid("not-a-jwt", now, credentialExpiryBuffer))
+}
+
+func TestCachedCredentialsIsValidFor(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	cached := &cachedCredentials{
+		credentials: &bean.ClusterCredentials{Token: "token", ExpiresOn: now.Add(10 * time.Minute), HasExpiry: true},
+		validTill:   now.Add(9 * time.Minute),
+	}
+	assert.True(t, cached.isValidFor(now, credentialExpiryBuffer))
+	assert.False(t, cached.isValidFor(now, 15*time.Minute))
+	assert.False(t, cached.isValidFor(now.Add(9*time.Minute+time.Second), credentialExpiryBuffer))
+	cached.credentials.HasExpiry = false
+	assert.True(t, cached.isValidFor(now, 15*time.Minute))
+}
+
+func TestGetCredentialCacheKey(t *testing.T) {
+	config := map[string]string{
+		bean.AuthType:         bean.AuthTypeOidc,
+		bean.OidcIssuerUrl:    "https://issuer",
+		bean.OidcClientId:     "kubernetes",
+		bean.OidcRefreshToken: "refresh",
+		bean.OidcIdToken:      "old-id-token",
+	}
+	key := getCredentialCacheKey("https://cluster", config)
+	config[bean.OidcIdToken] = "new-id-token"
+	assert.Equal(t, key, getCredentialCacheKey("https://cluster", config))
+	assert.NotEqual(t, key, getCredentialCacheKey("https://other-cluster", config))
+	config[bean.OidcRefreshToken] = "rotated"
+	assert.NotEqual(t, key, getCredentialCacheKey("https://cluster", config))
+}
+
+func TestIsCommandAllowed(t *testing.T) {
+	config := &ClusterCredentialConfig{ExecAllowedCommands: "aws, kubelogin"}
+	assert.True(t, config.isCommandAllowed("aws"))
+	assert.True(t, config.isCommandAllowed("kubelogin"))
+	assert.False(t, config.isCommandAllowed("sh"))
+}
+
+func TestValidateExecArgs(t *testing.T) {
+	assert.Nil(t, validateExecArgs("aws", []string{"eks", "get-token", "--cluster-name", "prod", "--region=us-east-1"}))
+	assert.Nil(t, validateExecArgs("kubelogin", []string{"get-token", "-l", "spn", "--server-id", "abc"}))
+	assert.Nil(t, validateExecArgs("gke-gcloud-auth-plugin", nil))
+	assert.NotNil(t, validateExecArgs("aws", []string{"s3", "cp", "/var/run/secrets", "s3://bucket"}))
+	assert.NotNil(t, validateExecArgs("aws", []string{"eks", "get-token", "--profile", "default"}))
+	assert.NotNil(t, validateExecArgs("aws", []string{"eks", "get-token", "--cluster-name"}))
+	assert.NotNil(t, validateExecArgs("kubelogin", []string{"get-token", "--client-certificate", "/etc/devtron/cert.pem"}))
+	assert.NotNil(t, validateExecArgs("custom-plugin", []string{"--any"}))
+}
+
+func TestValidateExecEnv(t *testing.T) {
+	assert.Nil(t, validateExecEnv(map[string]string{"AWS_PROFILE": "prod", "AAD_SERVICE_PRINCIPAL_CLIENT_ID": "id"}))
+	for _, name := range []string{"LD_PRELOAD", "LD_LIBRARY_PATH", "DYLD_INSERT_LIBRARIES", "PATH", "BASH_ENV", "HOME", kubernetesExecInfoEnv, "A=B"} {
+		assert.NotNil(t, validateExecEnv(map[string]string{name: "x"}), name)
+	}
+}
+
+func TestGetExecEnv(t *testing.T) {
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "orchestrator-secret")
+	t.Setenv("PATH", "/usr/bin")
+	cmdEnv := getExecEnv(map[string]string{"AWS_REGION": "us-east-1"}, "{}")
+	assert.Contains(t, cmdEnv, "PATH=/usr/bin")
+	assert.Contains(t, cmdEnv, "AWS_REGION=us-east-1")
+	assert.Contains(t, cmdEnv, kubernetesExecInfoEnv+"={}")
+	for _, entry := range cmdEnv {
+		assert.NotContains(t, entry, "orchestrator-secret")
+	}
+}
@@ -0,0 +1,272 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clusterHealth
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/common-lib/utils/k8s/commonBean"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/cluster/adapter"
+	"github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterCredentials"
+	healthBean "github.com/devtron-labs/devtron/pkg/cluster/clusterHealth/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterHealth/repository"
+	clusterRepository "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	cronUtil "github.com/devtron-labs/devtron/util/cron"
+	"github.com/go-pg/pg"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type ClusterHealthService interface {
+	// ProbeCluster probes the cluster right away, records the result in its health history and refreshes its capabilities
+	ProbeCluster(clusterId int) (*healthBean.ClusterHealthProbeDto, error)
+	// GetHealthHistory returns the probes of the cluster in the window, the last 24 hours when from and to are not given
+	GetHealthHistory(clusterId int, from, to time.Time) (*healthBean.ClusterHealthHistory, error)
+}
+
+type ClusterHealthServiceImpl struct {
+	logger                       *zap.SugaredLogger
+	k8sUtil                      *k8s.K8sServiceImpl
+	clusterRepository            clusterRepository.ClusterRepository
+	clusterHealthProbeRepository repository.ClusterHealthProbeRepository
+	config                       *healthBean.ClusterHealthConfig
+	probeCron                    *cron.Cron
+	clusterCredentialService     clusterCredentials.ClusterCredentialService
+}
+
+func GetClusterHealthConfig() (*healthBean.ClusterHealthConfig, error) {
+	config := &healthBean.ClusterHealthConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		return nil, err
+	}
+	return config, err
+}
+
+func NewClusterHealthServiceImpl(logger *zap.SugaredLogger, k8sUtil *k8s.K8sServiceImpl,
+	clusterRepository clusterRepository.ClusterRepository,
+	clusterHealthProbeRepository repository.ClusterHealthProbeRepository,
+	cronLogger *cronUtil.CronLoggerImpl,
+	clusterCredentialService clusterCredentials.ClusterCredentialService) (*ClusterHealthServiceImpl, error) {
+	config, err := GetClusterHealthConfig()
+	if err != nil {
+		logger.Errorw("error in parsing cluster health config", "err", err)
+		return nil, err
+	}
+	probeCron := cron.New(cron.WithChain(cron.SkipIfStillRunning(cronLogger), cron.Recover(cronLogger)))
+	impl := &ClusterHealthServiceImpl{
+		logger:                       logger,
+		k8sUtil:                      k8sUtil,
+		clusterRepository:            clusterRepository,
+		clusterHealthProbeRepository: clusterHealthProbeRepository,
+		config:                       config,
+		probeCron:                    probeCron,
+		clusterCredentialService:     clusterCredentialService,
+	}
+	if config.Enabled {
+		probeCron.Start()
+		_, err = probeCron.AddFunc(fmt.Sprintf("@every %ds", config.ProbeIntervalSecs), impl.probeAllClusters)
+		if err != nil {
+			logger.Errorw("error in starting cluster health probe cron", "err", err)
+			return nil, err
+		}
+	}
+	return impl, nil
+}
+
+func (impl *ClusterHealthServiceImpl) probeAllClusters() {
+	clusters, err := impl.clusterRepository.FindAllActiveExceptVirtual()
+	if err != nil {
+		impl.logger.Errorw("error in fetching clusters for health probes", "err", err)
+		return
+	}
+	concurrency := impl.config.ProbeConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, cluster := range clusters {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(cluster clusterRepository.Cluster) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			if _, err := impl.probeAndSave(&cluster); err != nil {
+				impl.logger.Errorw("error in probing cluster health", "clusterId", cluster.Id, "err", err)
+			}
+		}(cluster)
+	}
+	wg.Wait()
+	retentionStart := time.Now().AddDate(0, 0, -impl.config.RetentionDays)
+	err = impl.clusterHealthProbeRepository.DeleteProbesBefore(retentionStart)
+	if err != nil {
+		impl.logger.Errorw("error in deleting old cluster health probes", "before", retentionStart, "err", err)
+	}
+}
+
+func (impl *ClusterHealthServiceImpl) ProbeCluster(clusterId int) (*healthBean.ClusterHealthProbeDto, error) {
+	cluster, err := impl.getCluster(clusterId)
+	if err != nil {
+		return nil, err
+	}
+	if cluster.IsVirtualCluster {
+		msg := "health probes are not supported for isolated clusters"
+		return nil, util.NewApiError(http.StatusBadRequest, msg, msg)
+	}
+	return impl.probeAndSave(cluster)
+}
+
+func (impl *ClusterHealthServiceImpl) GetHealthHistory(clusterId int, from, to time.Time) (*healthBean.ClusterHealthHistory, error) {
+	cluster, err := impl.getCluster(clusterId)
+	if err != nil {
+		return nil, err
+	}
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.Add(-defaultHistoryWindow)
+	}
+	if from.After(to) {
+		msg := "from should be before to"
+		return nil, util.NewApiError(http.StatusBadRequest, msg, msg)
+	}
+	probes, err := impl.clusterHealthProbeRepository.FindByClusterId(clusterId, from, to)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cluster health probes", "clusterId", clusterId, "err", err)
+		return nil, err
+	}
+	history := &healthBean.ClusterHealthHistory{
+		ClusterId:     cluster.Id,
+		ClusterName:   cluster.ClusterName,
+		From:          from,
+		To:            to,
+		Capabilities:  cluster.Capabilities,
+		UptimePercent: getUptimePercent(probes),
+		Probes:        make([]*healthBean.ClusterHealthProbeDto, 0, len(probes)),
+	}
+	for _, probe := range probes {
+		history.Probes = append(history.Probes, toProbeDto(probe))
+	}
+	latest, err := impl.clusterHealthProbeRepository.FindLatestByClusterId(clusterId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching latest cluster health probe", "clusterId", clusterId, "err", err)
+		return nil, err
+	}
+	if err == nil {
+		history.Latest = toProbeDto(latest)
+	}
+	return history, nil
+}
+
+func (impl *ClusterHealthServiceImpl) getCluster(clusterId int) (*clusterRepository.Cluster, error) {
+	cluster, err := impl.clusterRepository.FindById(clusterId)
+	if err != nil {
+		if err == pg.ErrNoRows {
+			msg := fmt.Sprintf("cluster %d not found", clusterId)
+			return nil, util.NewApiError(http.StatusNotFound, msg, msg)
+		}
+		impl.logger.Errorw("error in fetching cluster", "clusterId", clusterId, "err", err)
+		return nil, err
+	}
+	return cluster, nil
+}
+
+func (impl *ClusterHealthServiceImpl) probeAndSave(cluster *clusterRepository.Cluster) (*healthBean.ClusterHealthProbeDto, error) {
+	clusterBean := adapter.GetClusterBean(*cluster)
+	probe, capabilities := impl.probe(clusterBean)
+	err := impl.clusterHealthProbeRepository.Save(probe)
+	if err != nil {
+		impl.logger.Errorw("error in saving cluster health probe", "clusterId", cluster.Id, "err", err)
+		return nil, err
+	}
+	if capabilities != nil && isCapabilitiesChanged(cluster.Capabilities, capabilities) {
+		err = impl.clusterRepository.UpdateClusterCapabilities(cluster.Id, capabilities)
+		if err != nil {
+			impl.logger.Errorw("error in updating cluster capabilities", "clusterId", cluster.Id, "err", err)
+			return nil, err
+		}
+	}
+	probeDto := toProbeDto(probe)
+	probeDto.Capabilities = capabilities
+	if probeDto.Capabilities == nil {
+		probeDto.Capabilities = cluster.Capabilities
+	}
+	return probeDto, nil
+}
+
+// probe checks livez, readyz and the server version of the cluster, capabilities are nil when they could not be detected
+func (impl *ClusterHealthServiceImpl) probe(cluster bean.ClusterBean) (*repository.ClusterHealthProbe, map[string]bool) {
+	probe := &repository.ClusterHealthProbe{
+		ClusterId: cluster.Id,
+		Status:    healthBean.StatusUnreachable,
+		ProbedOn:  time.Now(),
+	}
+	clusterConfig, err := cluster.ResolveClusterConfig(impl.clusterCredentialService)
+	if err != nil {
+		probe.Message = fmt.Sprintf("error in fetching cluster credentials: %v", err)
+		return probe, nil
+	}
+	restConfig, err := impl.k8sUtil.GetRestConfigByCluster(clusterConfig)
+	if err != nil {
+		probe.Message = fmt.Sprintf("error in building cluster config: %v", err)
+		return probe, nil
+	}
+	restConfig.Timeout = time.Duration(impl.config.ProbeTimeoutSecs) * time.Second
+	_, clientSet, err := impl.k8sUtil.GetK8sConfigAndClientsByRestConfig(restConfig)
+	if err != nil {
+		probe.Message = fmt.Sprintf("error in building cluster client: %v", err)
+		return probe, nil
+	}
+	startTime := time.Now()
+	_, err = impl.k8sUtil.GetLiveZCall(commonBean.LiveZ, clientSet)
+	probe.LatencyMs = time.Since(startTime).Milliseconds()
+	if err != nil {
+		probe.Message = fmt.Sprintf("livez check failed: %v", err)
+		return probe, nil
+	}
+	probe.Live = true
+	_, err = impl.k8sUtil.GetLiveZCall(readyZPath, clientSet)
+	if err != nil {
+		probe.Message = fmt.Sprintf("readyz check failed: %v", err)
+	} else {
+		probe.Ready = true
+	}
+	probe.Status = deriveHealthStatus(probe.Live, probe.Ready)
+	serverVersion, err := impl.k8sUtil.GetServerVersionFromDiscoveryClient(clientSet)
+	if err != nil {
+		impl.logger.Errorw("error in fetching server version during health probe", "clusterId", cluster.Id, "err", err)
+	} else {
+		probe.ServerVersion = serverVersion.GitVersion
+	}
+	discoveryClient := clientSet.Discovery()
+	capabilities, err := detectCapabilities(discoveryClient.ServerGroups, discoveryClient.ServerResourcesForGroupVersion)
+	if err != nil {
+		impl.logger.Errorw("error in detecting cluster capabilities", "clusterId", cluster.Id, "err", err)
+		return probe, nil
+	}
+	return probe, capabilities
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"time"
+)
+
+const (
+	StatusHealthy     = "Healthy"
+	StatusDegraded    = "Degraded"
+	StatusUnreachable = "Unreachable"
+)
+
+type ClusterHealthConfig struct {
+	Enabled           bool `env:"CLUSTER_HEALTH_PROBE_ENABLED" envDefault:"true"`
+	ProbeIntervalSecs int  `env:"CLUSTER_HEALTH_PROBE_INTERVAL_SECS" envDefault:"300"`
+	ProbeTimeoutSecs  int  `env:"CLUSTER_HEALTH_PROBE_TIMEOUT_SECS" envDefault:"15"`
+	RetentionDays     int  `env:"CLUSTER_HEALTH_HISTORY_RETENTION_DAYS" envDefault:"7"`
+	// ProbeConcurrency limits how many clusters are probed in parallel by the cron
+	ProbeConcurrency int `env:"CLUSTER_HEALTH_PROBE_CONCURRENCY" envDefault:"5"`
+}
+
+// CapabilityResource is a kind whose presence in the discovery api marks a capability on the cluster
+type CapabilityResource struct {
+	Group string
+	Kind  string
+}
+
+type ClusterHealthProbeDto struct {
+	ClusterId     int             `json:"clusterId"`
+	Status        string          `json:"status"`
+	Live          bool            `json:"live"`
+	Ready         bool            `json:"ready"`
+	LatencyMs     int64           `json:"latencyMs"`
+	ServerVersion string          `json:"serverVersion,omitempty"`
+	Message       string          `json:"message,omitempty"`
+	ProbedOn      time.Time       `json:"probedOn"`
+	Capabilities  map[string]bool `json:"capabilities,omitempty"`
+}
+
+type ClusterHealthHistory struct {
+	ClusterId    int                    `json:"clusterId"`
+	ClusterName  string                 `json:"clusterName"`
+	From         time.Time              `json:"from"`
+	To           time.Time              `json:"to"`
+	Latest       *ClusterHealthProbeDto `json:"latest,omitempty"`
+	Capabilities map[string]bool        `json:"capabilities,omitempty"`
+	// UptimePercent is the share of probes in the window in which the cluster was not unreachable
+	UptimePercent float64                  `json:"uptimePercent"`
+	Probes        []*ClusterHealthProbeDto `json:"probes"`
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clusterHealth
+
+import (
+	"github.com/devtron-labs/devtron/pkg/cluster/bean"
+	healthBean "github.com/devtron-labs/devtron/pkg/cluster/clusterHealth/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterHealth/repository"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+const (
+	readyZPath           = "/readyz"
+	defaultHistoryWindow = 24 * time.Hour
+)
+
+// capabilityResources maps every detected capability to the kind its crds install
+var capabilityResources = map[string]healthBean.CapabilityResource{
+	bean.CapabilityArgoRollouts: {Group: "argoproj.io", Kind: "Rollout"},
+	bean.CapabilityKeda:         {Group: "keda.sh", Kind: "ScaledObject"},
+	bean.CapabilityIstio:        {Group: "networking.istio.io", Kind: "VirtualService"},
+	bean.CapabilityCertManager:  {Group: "cert-manager.io", Kind: "Certificate"},
+}
+
+func deriveHealthStatus(live, ready bool) string {
+	if !live {
+		return healthBean.StatusUnreachable
+	}
+	if !ready {
+		return healthBean.StatusDegraded
+	}
+	return healthBean.StatusHealthy
+}
+
+// detectCapabilities checks the preferred version of every capability group for its kind, a capability
+// is false when its group is not served by the cluster
+func detectCapabilities(serverGroups func() (*metav1.APIGroupList, error),
+	serverResources func(groupVersion string) (*metav1.APIResourceList, error)) (map[string]bool, error) {
+	groupList, err := serverGroups()
+	if err != nil {
+		return nil, err
+	}
+	preferredVersions := make(map[string]string, len(groupList.Groups))
+	for _, group := range groupList.Groups {
+		preferredVersions[group.Name] = group.PreferredVersion.GroupVersion
+	}
+	capabilities := make(map[string]bool, len(capabilityResources))
+	for capability, resource := range capabilityResources {
+		groupVersion, ok := preferredVersions[resource.Group]
+		if !ok || len(groupVersion) == 0 {
+			capabilities[capability] = false
+			continue
+		}
+		resourceList, err := serverResources(groupVersion)
+		if err != nil {
+			return nil, err
+		}
+		capabilities[capability] = hasKind(resourceList, resource.Kind)
+	}
+	return capabilities, nil
+}
+
+func hasKind(resourceList *metav1.APIResourceList, kind string) bool {
+	if resourceList == nil {
+		return false
+	}
+	for _, resource := range resourceList.APIResources {
+		if resource.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func isCapabilitiesChanged(existing, detected map[string]bool) bool {
+	if len(existing) != len(detected) {
+		return true
+	}
+	for capability, present := range detected {
+		if existingPresent, ok := existing[capability]; !ok || existingPresent != present {
+			return true
+		}
+	}
+	return false
+}
+
+func getUptimePercent(probes []*repository.ClusterHealthProbe) float64 {
+	if len(probes) == 0 {
+		return 0
+	}
+	reachable := 0
+	for _, probe := range probes {
+		if probe.Status != healthBean.StatusUnreachable {
+			reachable++
+		}
+	}
+	return float64(reachable) * 100 / float64(len(probes))
+}
+
+func toProbeDto(probe *repository.ClusterHealthProbe) *healthBean.ClusterHealthProbeDto {
+	return &healthBean.ClusterHealthProbeDto{
+		ClusterId:     probe.ClusterId,
+		Status:        probe.Status,
+		Live:          probe.Live,
+		Ready:         probe.Ready,
+		LatencyMs:     probe.LatencyMs,
+		ServerVersion: probe.ServerVersion,
+		Message:       probe.Message,
+		ProbedOn:      probe.ProbedOn,
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clusterHealth
+
+import (
+	"errors"
+	"github.com/devtron-labs/devtron/pkg/cluster/bean"
+	healthBean "github.com/devtron-labs/devtron/pkg/cluster/clusterHealth/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterHealth/repository"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func TestDeriveHealthStatus(t *testing.T) {
+	assert.Equal(t, healthBean.StatusHealthy, deriveHealthStatus(true, true))
+	assert.Equal(t, healthBean.StatusDegraded, deriveHealthStatus(true, false))
+	assert.Equal(t, healthBean.StatusUnreachable, deriveHealthStatus(false, false))
+}
+
+func TestDetectCapabilities(t *testing.T) {
+	serverGroups := func() (*metav1.APIGroupList, error) {
+		return &metav1.APIGroupList{Groups: []metav1.APIGroup{
+			{Name: "apps", PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "apps/v1"}},
+			{Name: "argoproj.io", PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "argoproj.io/v1alpha1"}},
+			{Name: "cert-manager.io", PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "cert-manager.io/v1"}},
+		}}, nil
+	}
+	serverResources := func(groupVersion string) (*metav1.APIResourceList, error) {
+		switch groupVersion {
+		case "argoproj.io/v1alpha1":
+			// argo workflows without rollouts shares the group
+			return &metav1.APIResourceList{APIResources: []metav1.APIResource{{Kind: "Workflow"}, {Kind: "Rollout"}}}, nil
+		case "cert-manager.io/v1":
+			return &metav1.APIResourceList{APIResources: []metav1.APIResource{{Kind: "Issuer"}}}, nil
+		}
+		t.Fatalf("unexpected group version %s", groupVersion)
+		return nil, nil
+	}
+	capabilities, err := detectCapabilities(serverGroups, serverResources)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]bool{
+		bean.CapabilityArgoRollouts: true,
+		bean.CapabilityKeda:         false,
+		bean.CapabilityIstio:        false,
+		bean.CapabilityCertManager:  false,
+	}, capabilities)
+
+	_, err = detectCapabilities(func() (*metav1.APIGroupList, error) {
+		return nil, errors.New("discovery failed")
+	}, serverResources)
+	assert.NotNil(t, err)
+}
+
+func TestIsCapabilitiesChanged(t *testing.T) {
+	detected := map[string]bool{bean.CapabilityKeda: true, bean.CapabilityIstio: false}
+	assert.True(t, isCapabilitiesChanged(nil, detected))
+	assert.True(t, isCapabilitiesChanged(map[string]bool{bean.CapabilityKeda: false, bean.CapabilityIstio: false}, detected))
+	assert.False(t, isCapabilitiesChanged(map[string]bool{bean.CapabilityKeda: true, bean.CapabilityIstio: false}, detected))
+}
+
+func TestGetUptimePercent(t *testing.T) {
+	assert.Equal(t, float64(0), getUptimePercent(nil))
+	probes := []*repository.ClusterHealthProbe{
+		{Status: healthBean.StatusHealthy},
+		{Status: healthBean.StatusDegraded},
+		{Status: healthBean.StatusHealthy},
+		{Status: healthBean.StatusUnreachable},
+	}
+	assert.Equal(t, float64(75), getUptimePercent(probes))
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type ClusterHealthProbe struct {
+	tableName     struct{}  `sql:"cluster_health_probe" pg:",discard_unknown_columns"`
+	Id            int       `sql:"id,pk"`
+	ClusterId     int       `sql:"cluster_id,notnull"`
+	Status        string    `sql:"status,notnull"`
+	Live          bool      `sql:"live,notnull"`
+	Ready         bool      `sql:"ready,notnull"`
+	LatencyMs     int64     `sql:"latency_ms,notnull"`
+	ServerVersion string    `sql:"server_version"`
+	Message       string    `sql:"message"`
+	ProbedOn      time.Time `sql:"probed_on,notnull"`
+}
+
+type ClusterHealthProbeRepository interface {
+	Save(probe *ClusterHealthProbe) error
+	FindByClusterId(clusterId int, from, to time.Time) ([]*ClusterHealthProbe, error)
+	FindLatestByClusterId(clusterId int) (*ClusterHealthProbe, error)
+	DeleteProbesBefore(before time.Time) error
+}
+
+type ClusterHealthProbeRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewClusterHealthProbeRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ClusterHealthProbeRepositoryImpl {
+	return &ClusterHealthProbeRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (repo *ClusterHealthProbeRepositoryImpl) Save(probe *ClusterHealthProbe) error {
+	return repo.dbConnection.Insert(probe)
+}
+
+func (repo *ClusterHealthProbeRepositoryImpl) FindByClusterId(clusterId int, from, to time.Time) ([]*ClusterHealthProbe, error) {
+	var probes []*ClusterHealthProbe
+	err := repo.dbConnection.Model(&probes).
+		Where("cluster_id = ?", clusterId).
+		Where("probed_on >= ?", from).
+		Where("probed_on <= ?", to).
+		Order("probed_on ASC").
+		Select()
+	return probes, err
+}
+
+func (repo *ClusterHealthProbeRepositoryImpl) FindLatestByClusterId(clusterId int) (*ClusterHealthProbe, error) {
+	probe := &ClusterHealthProbe{}
+	err := repo.dbConnection.Model(probe).
+		Where("cluster_id = ?", clusterId).
+		Order("probed_on DESC").
+		Limit(1).
+		Select()
+	return probe, err
+}
+
+func (repo *ClusterHealthProbeRepositoryImpl) DeleteProbesBefore(before time.Time) error {
+	_, err := repo.dbConnection.Model((*ClusterHealthProbe)(nil)).Where("probed_on < ?", before).Delete()
+	return err
+}
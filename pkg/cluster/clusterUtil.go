@@ -1,11 +1,106 @@
 package cluster
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
 
 const (
 	SECRET_NAME = "cluster-event"
 )
 
+const (
+	oidcAuthProviderName            = "oidc"
+	oidcIssuerUrlKey                = "idp-issuer-url"
+	oidcClientIdKey                 = "client-id"
+	oidcClientSecretKey             = "client-secret"
+	oidcRefreshTokenKey             = "refresh-token"
+	oidcIdTokenKey                  = "id-token"
+	oidcCertificateAuthorityDataKey = "idp-certificate-authority-data"
+)
+
 func ParseSecretNameForKubelinkInformer(clusterId int) string {
 	return fmt.Sprintf("%s-%d", SECRET_NAME, clusterId)
 }
+
+// getKubeconfigCredentialAuthConfig returns the cluster config entries for a kubeconfig user authenticating
+// through an exec plugin or the oidc auth provider, nil is returned for token and client certificate users.
+func getKubeconfigCredentialAuthConfig(authInfo *api.AuthInfo) (map[string]string, error) {
+	if authInfo == nil {
+		return nil, nil
+	}
+	if authInfo.Exec != nil {
+		if len(authInfo.Exec.Command) == 0 {
+			return nil, fmt.Errorf("command missing from the exec config of the user")
+		}
+		if authInfo.Exec.InteractiveMode == api.AlwaysExecInteractiveMode {
+			return nil, fmt.Errorf("exec plugins requiring interactive mode are not supported")
+		}
+		execConfig := bean.ExecAuthConfig{
+			ApiVersion: authInfo.Exec.APIVersion,
+			Command:    authInfo.Exec.Command,
+			Args:       authInfo.Exec.Args,
+		}
+		if len(authInfo.Exec.Env) > 0 {
+			execConfig.Env = make(map[string]string, len(authInfo.Exec.Env))
+			for _, envVar := range authInfo.Exec.Env {
+				execConfig.Env[envVar.Name] = envVar.Value
+			}
+		}
+		execConfigJson, err := json.Marshal(execConfig)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]string{
+			bean.AuthType:   bean.AuthTypeExec,
+			bean.ExecConfig: string(execConfigJson),
+		}, nil
+	}
+	if authInfo.AuthProvider != nil {
+		if authInfo.AuthProvider.Name != oidcAuthProviderName {
+			return nil, fmt.Errorf("auth provider %s is not supported", authInfo.AuthProvider.Name)
+		}
+		providerConfig := authInfo.AuthProvider.Config
+		if len(providerConfig[oidcIssuerUrlKey]) == 0 || len(providerConfig[oidcClientIdKey]) == 0 {
+			return nil, fmt.Errorf("idp-issuer-url and client-id are required for the oidc auth provider")
+		}
+		if len(providerConfig[oidcIdTokenKey]) == 0 && len(providerConfig[oidcRefreshTokenKey]) == 0 {
+			return nil, fmt.Errorf("id-token or refresh-token is required for the oidc auth provider")
+		}
+		return map[string]string{
+			bean.AuthType:              bean.AuthTypeOidc,
+			bean.OidcIssuerUrl:         providerConfig[oidcIssuerUrlKey],
+			bean.OidcClientId:          providerConfig[oidcClientIdKey],
+			bean.OidcClientSecret:      providerConfig[oidcClientSecretKey],
+			bean.OidcRefreshToken:      providerConfig[oidcRefreshTokenKey],
+			bean.OidcIdToken:           providerConfig[oidcIdTokenKey],
+			bean.OidcCertAuthorityData: providerConfig[oidcCertificateAuthorityDataKey],
+		}, nil
+	}
+	return nil, nil
+}
+
+// getArgoCdCluster builds the argocd cluster from the resolved config, which carries the current credentials
+// of exec and oidc clusters
+func getArgoCdCluster(clusterBean *bean.ClusterBean, clusterConfig *k8s.ClusterConfig) *v1alpha1.Cluster {
+	tlsConfig := v1alpha1.TLSClientConfig{
+		Insecure: clusterBean.InsecureSkipTLSVerify,
+	}
+	if !clusterBean.InsecureSkipTLSVerify {
+		tlsConfig.KeyData = []byte(clusterConfig.KeyData)
+		tlsConfig.CertData = []byte(clusterConfig.CertData)
+		tlsConfig.CAData = []byte(clusterConfig.CAData)
+	}
+	return &v1alpha1.Cluster{
+		Name:   clusterBean.ClusterName,
+		Server: clusterBean.ServerUrl,
+		Config: v1alpha1.ClusterConfig{
+			BearerToken:     clusterConfig.BearerToken,
+			TLSClientConfig: tlsConfig,
+		},
+	}
+}
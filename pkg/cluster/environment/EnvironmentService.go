@@ -22,10 +22,14 @@ import (
 	bean3 "github.com/devtron-labs/devtron/pkg/attributes/bean"
 	"github.com/devtron-labs/devtron/pkg/cluster"
 	bean4 "github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterCredentials"
 	adapter2 "github.com/devtron-labs/devtron/pkg/cluster/environment/adapter"
 	bean2 "github.com/devtron-labs/devtron/pkg/cluster/environment/bean"
 	"github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/cluster/namespaceTemplate"
+	bean5 "github.com/devtron-labs/devtron/pkg/cluster/namespaceTemplate/bean"
 	"github.com/devtron-labs/devtron/pkg/cluster/read"
+	"net/http"
 	"strconv"
 	"strings"
 	"sync"
@@ -77,9 +81,11 @@ type EnvironmentServiceImpl struct {
 	K8sUtil               *util2.K8sServiceImpl
 	k8sInformerFactory    informer.K8sInformerFactory
 	//propertiesConfigService pipeline.PropertiesConfigService
-	userAuthService      user.UserAuthService
-	attributesRepository repository2.AttributesRepository
-	clusterReadService   read.ClusterReadService
+	userAuthService          user.UserAuthService
+	attributesRepository     repository2.AttributesRepository
+	clusterReadService       read.ClusterReadService
+	namespaceTemplateService namespaceTemplate.NamespaceTemplateService
+	clusterCredentialService clusterCredentials.ClusterCredentialService
 }
 
 func NewEnvironmentServiceImpl(environmentRepository repository.EnvironmentRepository,
@@ -87,7 +93,9 @@ func NewEnvironmentServiceImpl(environmentRepository repository.EnvironmentRepos
 	K8sUtil *util2.K8sServiceImpl, k8sInformerFactory informer.K8sInformerFactory,
 	//  propertiesConfigService pipeline.PropertiesConfigService,
 	userAuthService user.UserAuthService, attributesRepository repository2.AttributesRepository,
-	clusterReadService read.ClusterReadService) *EnvironmentServiceImpl {
+	clusterReadService read.ClusterReadService,
+	namespaceTemplateService namespaceTemplate.NamespaceTemplateService,
+	clusterCredentialService clusterCredentials.ClusterCredentialService) *EnvironmentServiceImpl {
 	return &EnvironmentServiceImpl{
 		environmentRepository: environmentRepository,
 		logger:                logger,
@@ -95,9 +103,11 @@ func NewEnvironmentServiceImpl(environmentRepository repository.EnvironmentRepos
 		K8sUtil:               K8sUtil,
 		k8sInformerFactory:    k8sInformerFactory,
 		//propertiesConfigService: propertiesConfigService,
-		userAuthService:      userAuthService,
-		attributesRepository: attributesRepository,
-		clusterReadService:   clusterReadService,
+		userAuthService:          userAuthService,
+		attributesRepository:     attributesRepository,
+		clusterReadService:       clusterReadService,
+		namespaceTemplateService: namespaceTemplateService,
+		clusterCredentialService: clusterCredentialService,
 	}
 }
 
@@ -117,6 +127,13 @@ func (impl EnvironmentServiceImpl) Create(mappings *bean2.EnvironmentBean, userI
 		return nil, err
 	}
 
+	if mappings.NamespaceTemplateId > 0 {
+		err = impl.validateNamespaceTemplate(mappings, clusterBean)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	identifier := clusterBean.ClusterName + "__" + mappings.Namespace
 
 	model, err := impl.environmentRepository.FindByEnvNameOrIdentifierOrNamespace(mappings.ClusterId, mappings.Environment, identifier, mappings.Namespace)
@@ -148,12 +165,20 @@ func (impl EnvironmentServiceImpl) Create(mappings *bean2.EnvironmentBean, userI
 		return mappings, err
 	}
 	if len(model.Namespace) > 0 {
-		cfg := clusterBean.GetClusterConfig()
-		if _, _, err := impl.K8sUtil.CreateNsIfNotExists(model.Namespace, cfg); err != nil {
+		if cfg, err := clusterBean.ResolveClusterConfig(impl.clusterCredentialService); err != nil {
+			impl.logger.Errorw("error in resolving cluster credentials", "clusterId", clusterBean.Id, "err", err)
+		} else if _, _, err := impl.K8sUtil.CreateNsIfNotExists(model.Namespace, cfg); err != nil {
 			impl.logger.Errorw("error in creating ns", "ns", model.Namespace, "err", err)
 		}
 
 	}
+	if mappings.NamespaceTemplateId > 0 {
+		// a failed provisioning is recorded against the template mapping and can be re-applied later
+		attachRequest := &bean5.AttachTemplateRequest{EnvId: model.Id, TemplateId: mappings.NamespaceTemplateId}
+		if _, err := impl.namespaceTemplateService.ApplyTemplateToEnvironment(attachRequest, userId); err != nil {
+			impl.logger.Errorw("error in applying namespace template", "envId", model.Id, "templateId", mappings.NamespaceTemplateId, "err", err)
+		}
+	}
 
 	//ignore grafana if no prometheus url found
 	if len(clusterBean.PrometheusUrl) > 0 {
@@ -167,6 +192,19 @@ func (impl EnvironmentServiceImpl) Create(mappings *bean2.EnvironmentBean, userI
 	return mappings, nil
 }
 
+func (impl EnvironmentServiceImpl) validateNamespaceTemplate(mappings *bean2.EnvironmentBean, clusterBean *bean4.ClusterBean) error {
+	if clusterBean.IsVirtualCluster || len(mappings.Namespace) == 0 {
+		msg := "namespace template can only be used for an environment with a namespace in a connected cluster"
+		return util.NewApiError(http.StatusBadRequest, msg, msg)
+	}
+	_, err := impl.namespaceTemplateService.GetTemplate(mappings.NamespaceTemplateId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching namespace template", "templateId", mappings.NamespaceTemplateId, "err", err)
+		return err
+	}
+	return nil
+}
+
 func (impl EnvironmentServiceImpl) FindOne(environment string) (*bean2.EnvironmentBean, error) {
 	model, err := impl.environmentRepository.FindOne(environment)
 	if err != nil {
@@ -261,8 +299,9 @@ func (impl EnvironmentServiceImpl) Update(mappings *bean2.EnvironmentBean, userI
 
 	//namespace create if not exist
 	if len(model.Namespace) > 0 {
-		cfg := clusterBean.GetClusterConfig()
-		if _, _, err := impl.K8sUtil.CreateNsIfNotExists(model.Namespace, cfg); err != nil {
+		if cfg, err := clusterBean.ResolveClusterConfig(impl.clusterCredentialService); err != nil {
+			impl.logger.Errorw("error in resolving cluster credentials", "clusterId", clusterBean.Id, "err", err)
+		} else if _, _, err := impl.K8sUtil.CreateNsIfNotExists(model.Namespace, cfg); err != nil {
 			impl.logger.Errorw("error in creating ns", "ns", model.Namespace, "err", err)
 		}
 	}
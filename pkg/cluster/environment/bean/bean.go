@@ -31,6 +31,7 @@ type EnvironmentBean struct {
 	AppCount               int      `json:"appCount"`
 	IsVirtualEnvironment   bool     `json:"isVirtualEnvironment"`
 	AllowedDeploymentTypes []string `json:"allowedDeploymentTypes"`
+	NamespaceTemplateId    int      `json:"namespaceTemplateId,omitempty"`
 	ClusterServerUrl       string   `json:"-"`
 	ErrorInConnecting      string   `json:"-"`
 }
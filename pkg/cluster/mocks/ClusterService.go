@@ -29,7 +29,7 @@ func (_m *ClusterService) ConnectClustersInBatch(clusters []*bean.ClusterBean, c
 }
 
 // ConvertClusterBeanObjectToCluster provides a mock function with given fields: bean
-func (_m *ClusterService) ConvertClusterBeanObjectToCluster(bean *bean.ClusterBean) *v1alpha1.Cluster {
+func (_m *ClusterService) ConvertClusterBeanObjectToCluster(bean *bean.ClusterBean) (*v1alpha1.Cluster, error) {
 	ret := _m.Called(bean)
 
 	var r0 *v1alpha1.Cluster
@@ -41,7 +41,14 @@ func (_m *ClusterService) ConvertClusterBeanObjectToCluster(bean *bean.ClusterBe
 		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*bean.ClusterBean) error); ok {
+		r1 = rf(bean)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // ConvertClusterBeanToCluster provides a mock function with given fields: clusterBean, userId
@@ -498,13 +505,13 @@ func (_m *ClusterService) Update(ctx context.Context, bean *bean.ClusterBean, us
 	return r0, r1
 }
 
-// ValidateKubeconfig provides a mock function with given fields: kubeConfig
-func (_m *ClusterService) ValidateKubeconfig(kubeConfig string) (map[string]*bean.ValidateClusterBean, error) {
-	ret := _m.Called(kubeConfig)
+// ValidateKubeconfig provides a mock function with given fields: kubeConfig, contexts
+func (_m *ClusterService) ValidateKubeconfig(kubeConfig string, contexts []string) (map[string]*bean.ValidateClusterBean, error) {
+	ret := _m.Called(kubeConfig, contexts)
 
 	var r0 map[string]*bean.ValidateClusterBean
-	if rf, ok := ret.Get(0).(func(string) map[string]*bean.ValidateClusterBean); ok {
-		r0 = rf(kubeConfig)
+	if rf, ok := ret.Get(0).(func(string, []string) map[string]*bean.ValidateClusterBean); ok {
+		r0 = rf(kubeConfig, contexts)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(map[string]*bean.ValidateClusterBean)
@@ -512,8 +519,8 @@ func (_m *ClusterService) ValidateKubeconfig(kubeConfig string) (map[string]*bea
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(kubeConfig)
+	if rf, ok := ret.Get(1).(func(string, []string) error); ok {
+		r1 = rf(kubeConfig, contexts)
 	} else {
 		r1 = ret.Error(1)
 	}
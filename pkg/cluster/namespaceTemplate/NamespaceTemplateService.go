@@ -0,0 +1,803 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package namespaceTemplate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/common-lib/utils/k8s"
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterCredentials"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/cluster/namespaceTemplate/bean"
+	templateRepository "github.com/devtron-labs/devtron/pkg/cluster/namespaceTemplate/repository"
+	"github.com/devtron-labs/devtron/pkg/cluster/read"
+	"github.com/devtron-labs/devtron/pkg/dockerRegistry"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type NamespaceTemplateService interface {
+	CreateTemplate(request *bean.NamespaceTemplateRequest, userId int32) (*bean.NamespaceTemplateDto, error)
+	// UpdateTemplate saves the request as the next version of the template, environments keep their applied
+	// version until the template is re-applied on them
+	UpdateTemplate(request *bean.NamespaceTemplateRequest, userId int32) (*bean.NamespaceTemplateDto, error)
+	DeleteTemplate(templateId int, userId int32) error
+	GetTemplate(templateId int) (*bean.NamespaceTemplateDto, error)
+	GetAllTemplates() ([]*bean.NamespaceTemplateDto, error)
+	// ApplyTemplateToEnvironment provisions the namespace of the environment with the template and maps it to the
+	// environment, a failed apply is recorded on the mapping and reported through its status
+	ApplyTemplateToEnvironment(request *bean.AttachTemplateRequest, userId int32) (*bean.EnvironmentMappingDto, error)
+	ReapplyTemplate(request *bean.ApplyTemplateRequest, userId int32) ([]*bean.EnvironmentMappingDto, error)
+	GetTemplateDrift(templateId int) (*bean.TemplateDriftReport, error)
+}
+
+type NamespaceTemplateServiceImpl struct {
+	logger                        *zap.SugaredLogger
+	k8sUtil                       *k8s.K8sServiceImpl
+	namespaceTemplateRepository   templateRepository.NamespaceTemplateRepository
+	environmentRepository         repository.EnvironmentRepository
+	clusterReadService            read.ClusterReadService
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository
+	clusterCredentialService      clusterCredentials.ClusterCredentialService
+}
+
+func NewNamespaceTemplateServiceImpl(logger *zap.SugaredLogger, k8sUtil *k8s.K8sServiceImpl,
+	namespaceTemplateRepository templateRepository.NamespaceTemplateRepository,
+	environmentRepository repository.EnvironmentRepository,
+	clusterReadService read.ClusterReadService,
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository,
+	clusterCredentialService clusterCredentials.ClusterCredentialService) *NamespaceTemplateServiceImpl {
+	return &NamespaceTemplateServiceImpl{
+		logger:                        logger,
+		k8sUtil:                       k8sUtil,
+		namespaceTemplateRepository:   namespaceTemplateRepository,
+		environmentRepository:         environmentRepository,
+		clusterReadService:            clusterReadService,
+		dockerArtifactStoreRepository: dockerArtifactStoreRepository,
+		clusterCredentialService:      clusterCredentialService,
+	}
+}
+
+func (impl *NamespaceTemplateServiceImpl) CreateTemplate(request *bean.NamespaceTemplateRequest, userId int32) (*bean.NamespaceTemplateDto, error) {
+	err := impl.validateRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := impl.namespaceTemplateRepository.FindActiveByName(request.Name)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in finding namespace template by name", "name", request.Name, "err", err)
+		return nil, err
+	}
+	if existing.Id > 0 {
+		msg := fmt.Sprintf("namespace template %s already exists", request.Name)
+		return nil, util.NewApiError(http.StatusConflict, msg, msg)
+	}
+	template := &templateRepository.NamespaceTemplate{
+		Name:          request.Name,
+		Description:   request.Description,
+		LatestVersion: 1,
+		Active:        true,
+		AuditLog:      sql.NewDefaultAuditLog(userId),
+	}
+	version, err := impl.buildVersion(template.LatestVersion, request.Spec, userId)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.namespaceTemplateRepository.SaveTemplate(template, version)
+	if err != nil {
+		impl.logger.Errorw("error in saving namespace template", "name", request.Name, "err", err)
+		return nil, err
+	}
+	return impl.GetTemplate(template.Id)
+}
+
+func (impl *NamespaceTemplateServiceImpl) UpdateTemplate(request *bean.NamespaceTemplateRequest, userId int32) (*bean.NamespaceTemplateDto, error) {
+	err := impl.validateRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	template, err := impl.getTemplate(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	if template.Name != request.Name {
+		msg := "name of a namespace template can not be changed"
+		return nil, util.NewApiError(http.StatusBadRequest, msg, msg)
+	}
+	template.Description = request.Description
+	template.LatestVersion++
+	template.UpdateAuditLog(userId)
+	version, err := impl.buildVersion(template.LatestVersion, request.Spec, userId)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.namespaceTemplateRepository.SaveTemplate(template, version)
+	if err != nil {
+		impl.logger.Errorw("error in saving namespace template version", "templateId", template.Id, "err", err)
+		return nil, err
+	}
+	return impl.GetTemplate(template.Id)
+}
+
+func (impl *NamespaceTemplateServiceImpl) DeleteTemplate(templateId int, userId int32) error {
+	template, err := impl.getTemplate(templateId)
+	if err != nil {
+		return err
+	}
+	mappings, err := impl.namespaceTemplateRepository.FindEnvMappingsByTemplateId(templateId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching environments of namespace template", "templateId", templateId, "err", err)
+		return err
+	}
+	if len(mappings) > 0 {
+		msg := fmt.Sprintf("namespace template is used by %d environments", len(mappings))
+		return util.NewApiError(http.StatusConflict, msg, msg)
+	}
+	template.Active = false
+	template.UpdateAuditLog(userId)
+	return impl.namespaceTemplateRepository.UpdateTemplate(template)
+}
+
+func (impl *NamespaceTemplateServiceImpl) GetTemplate(templateId int) (*bean.NamespaceTemplateDto, error) {
+	template, err := impl.getTemplate(templateId)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := impl.namespaceTemplateRepository.FindVersions(templateId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching namespace template versions", "templateId", templateId, "err", err)
+		return nil, err
+	}
+	dto := toTemplateDto(template)
+	for _, version := range versions {
+		spec, err := parseSpec(version.Spec)
+		if err != nil {
+			impl.logger.Errorw("error in parsing namespace template spec", "templateId", templateId, "version", version.Version, "err", err)
+			return nil, err
+		}
+		if version.Version == template.LatestVersion {
+			dto.Spec = spec
+		}
+		dto.Versions = append(dto.Versions, &bean.NamespaceTemplateVersionDto{
+			Version:   version.Version,
+			Spec:      spec,
+			CreatedBy: version.CreatedBy,
+			CreatedOn: version.CreatedOn,
+		})
+	}
+	dto.Environments, err = impl.getEnvironmentMappings(templateId)
+	if err != nil {
+		return nil, err
+	}
+	return dto, nil
+}
+
+func (impl *NamespaceTemplateServiceImpl) GetAllTemplates() ([]*bean.NamespaceTemplateDto, error) {
+	templates, err := impl.namespaceTemplateRepository.FindAllActive()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching namespace templates", "err", err)
+		return nil, err
+	}
+	dtos := make([]*bean.NamespaceTemplateDto, 0, len(templates))
+	for _, template := range templates {
+		dtos = append(dtos, toTemplateDto(template))
+	}
+	return dtos, nil
+}
+
+func (impl *NamespaceTemplateServiceImpl) ApplyTemplateToEnvironment(request *bean.AttachTemplateRequest, userId int32) (*bean.EnvironmentMappingDto, error) {
+	template, err := impl.getTemplate(request.TemplateId)
+	if err != nil {
+		return nil, err
+	}
+	environment, err := impl.environmentRepository.FindById(request.EnvId)
+	if err != nil {
+		if err == pg.ErrNoRows {
+			msg := fmt.Sprintf("environment %d not found", request.EnvId)
+			return nil, util.NewApiError(http.StatusNotFound, msg, msg)
+		}
+		impl.logger.Errorw("error in fetching environment", "envId", request.EnvId, "err", err)
+		return nil, err
+	}
+	if environment.IsVirtualEnvironment || len(environment.Namespace) == 0 {
+		msg := "namespace templates can only be applied on environments with a namespace in a connected cluster"
+		return nil, util.NewApiError(http.StatusBadRequest, msg, msg)
+	}
+	return impl.applyToEnvironment(template, request.Version, environment, userId)
+}
+
+func (impl *NamespaceTemplateServiceImpl) ReapplyTemplate(request *bean.ApplyTemplateRequest, userId int32) ([]*bean.EnvironmentMappingDto, error) {
+	template, err := impl.getTemplate(request.TemplateId)
+	if err != nil {
+		return nil, err
+	}
+	mappings, err := impl.namespaceTemplateRepository.FindEnvMappingsByTemplateId(template.Id)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching environments of namespace template", "templateId", template.Id, "err", err)
+		return nil, err
+	}
+	selectedEnvIds := make(map[int]bool, len(request.EnvIds))
+	for _, envId := range request.EnvIds {
+		selectedEnvIds[envId] = true
+	}
+	results := make([]*bean.EnvironmentMappingDto, 0, len(mappings))
+	for _, mapping := range mappings {
+		if len(selectedEnvIds) > 0 && !selectedEnvIds[mapping.EnvId] {
+			continue
+		}
+		delete(selectedEnvIds, mapping.EnvId)
+		environment, err := impl.environmentRepository.FindById(mapping.EnvId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching environment", "envId", mapping.EnvId, "err", err)
+			return nil, err
+		}
+		result, err := impl.applyToEnvironment(template, request.Version, environment, userId)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	for envId := range selectedEnvIds {
+		msg := fmt.Sprintf("environment %d does not use namespace template %s", envId, template.Name)
+		return nil, util.NewApiError(http.StatusBadRequest, msg, msg)
+	}
+	return results, nil
+}
+
+func (impl *NamespaceTemplateServiceImpl) GetTemplateDrift(templateId int) (*bean.TemplateDriftReport, error) {
+	template, err := impl.getTemplate(templateId)
+	if err != nil {
+		return nil, err
+	}
+	mappings, err := impl.namespaceTemplateRepository.FindEnvMappingsByTemplateId(templateId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching environments of namespace template", "templateId", templateId, "err", err)
+		return nil, err
+	}
+	report := &bean.TemplateDriftReport{
+		TemplateId:    template.Id,
+		TemplateName:  template.Name,
+		LatestVersion: template.LatestVersion,
+		Environments:  make([]*bean.EnvironmentDrift, 0, len(mappings)),
+	}
+	specs := make(map[int]*bean.NamespaceTemplateSpec)
+	for _, mapping := range mappings {
+		environment, err := impl.environmentRepository.FindById(mapping.EnvId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching environment", "envId", mapping.EnvId, "err", err)
+			return nil, err
+		}
+		envDrift := &bean.EnvironmentDrift{
+			EnvId:          environment.Id,
+			EnvName:        environment.Name,
+			Namespace:      environment.Namespace,
+			AppliedVersion: mapping.AppliedVersion,
+			Drifts:         make([]*bean.DriftItem, 0),
+		}
+		report.Environments = append(report.Environments, envDrift)
+		if mapping.AppliedVersion != template.LatestVersion {
+			envDrift.Drifts = append(envDrift.Drifts, &bean.DriftItem{Kind: bean.KindNamespace, Name: environment.Namespace, Type: bean.DriftTypeOutdatedVersion,
+				Message: fmt.Sprintf("version %d is applied, latest version is %d", mapping.AppliedVersion, template.LatestVersion)})
+		}
+		spec, ok := specs[mapping.AppliedVersion]
+		if !ok {
+			spec, err = impl.getVersionSpec(template.Id, mapping.AppliedVersion)
+			if err != nil {
+				return nil, err
+			}
+			specs[mapping.AppliedVersion] = spec
+		}
+		drifts, err := impl.getEnvironmentDrift(template.Name, mapping.AppliedVersion, spec, environment)
+		if err != nil {
+			impl.logger.Errorw("error in computing namespace drift", "envId", environment.Id, "err", err)
+			envDrift.Error = err.Error()
+		}
+		envDrift.Drifts = append(envDrift.Drifts, drifts...)
+		envDrift.InSync = len(envDrift.Drifts) == 0 && len(envDrift.Error) == 0
+	}
+	return report, nil
+}
+
+func (impl *NamespaceTemplateServiceImpl) validateRequest(request *bean.NamespaceTemplateRequest) error {
+	err := validateSpec(request.Spec)
+	if err != nil {
+		return util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	for _, registryId := range request.Spec.ImagePullSecretRegistryIds {
+		_, err = impl.dockerArtifactStoreRepository.FindOne(registryId)
+		if err != nil {
+			if err == pg.ErrNoRows {
+				msg := fmt.Sprintf("container registry %s not found", registryId)
+				return util.NewApiError(http.StatusBadRequest, msg, msg)
+			}
+			impl.logger.Errorw("error in fetching container registry", "registryId", registryId, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (impl *NamespaceTemplateServiceImpl) buildVersion(version int, spec *bean.NamespaceTemplateSpec, userId int32) (*templateRepository.NamespaceTemplateVersion, error) {
+	specJson, err := json.Marshal(spec)
+	if err != nil {
+		impl.logger.Errorw("error in marshalling namespace template spec", "err", err)
+		return nil, err
+	}
+	return &templateRepository.NamespaceTemplateVersion{
+		Version:   version,
+		Spec:      string(specJson),
+		CreatedOn: time.Now(),
+		CreatedBy: userId,
+	}, nil
+}
+
+func (impl *NamespaceTemplateServiceImpl) getTemplate(templateId int) (*templateRepository.NamespaceTemplate, error) {
+	template, err := impl.namespaceTemplateRepository.FindById(templateId)
+	if err != nil {
+		if err == pg.ErrNoRows {
+			msg := fmt.Sprintf("namespace template %d not found", templateId)
+			return nil, util.NewApiError(http.StatusNotFound, msg, msg)
+		}
+		impl.logger.Errorw("error in fetching namespace template", "templateId", templateId, "err", err)
+		return nil, err
+	}
+	return template, nil
+}
+
+func (impl *NamespaceTemplateServiceImpl) getVersionSpec(templateId, version int) (*bean.NamespaceTemplateSpec, error) {
+	templateVersion, err := impl.namespaceTemplateRepository.FindVersion(templateId, version)
+	if err != nil {
+		if err == pg.ErrNoRows {
+			msg := fmt.Sprintf("version %d of namespace template %d not found", version, templateId)
+			return nil, util.NewApiError(http.StatusNotFound, msg, msg)
+		}
+		impl.logger.Errorw("error in fetching namespace template version", "templateId", templateId, "version", version, "err", err)
+		return nil, err
+	}
+	return parseSpec(templateVersion.Spec)
+}
+
+func (impl *NamespaceTemplateServiceImpl) getEnvironmentMappings(templateId int) ([]*bean.EnvironmentMappingDto, error) {
+	mappings, err := impl.namespaceTemplateRepository.FindEnvMappingsByTemplateId(templateId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching environments of namespace template", "templateId", templateId, "err", err)
+		return nil, err
+	}
+	if len(mappings) == 0 {
+		return nil, nil
+	}
+	envIds := make([]*int, 0, len(mappings))
+	for _, mapping := range mappings {
+		envId := mapping.EnvId
+		envIds = append(envIds, &envId)
+	}
+	environments, err := impl.environmentRepository.FindByIds(envIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environments", "envIds", envIds, "err", err)
+		return nil, err
+	}
+	environmentMap := make(map[int]*repository.Environment, len(environments))
+	for _, environment := range environments {
+		environmentMap[environment.Id] = environment
+	}
+	dtos := make([]*bean.EnvironmentMappingDto, 0, len(mappings))
+	for _, mapping := range mappings {
+		dtos = append(dtos, toEnvironmentMappingDto(mapping, environmentMap[mapping.EnvId]))
+	}
+	return dtos, nil
+}
+
+// applyToEnvironment provisions the namespace with the given version, the latest one when version is 0,
+// and records the outcome on the mapping of the environment
+func (impl *NamespaceTemplateServiceImpl) applyToEnvironment(template *templateRepository.NamespaceTemplate, version int,
+	environment *repository.Environment, userId int32) (*bean.EnvironmentMappingDto, error) {
+	if version == 0 {
+		version = template.LatestVersion
+	}
+	spec, err := impl.getVersionSpec(template.Id, version)
+	if err != nil {
+		return nil, err
+	}
+	mapping, err := impl.namespaceTemplateRepository.FindEnvMappingByEnvId(environment.Id)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching namespace template mapping", "envId", environment.Id, "err", err)
+		return nil, err
+	}
+	if mapping.Id == 0 {
+		mapping.EnvId = environment.Id
+		mapping.AuditLog = sql.NewDefaultAuditLog(userId)
+	}
+	mapping.NamespaceTemplateId = template.Id
+	mapping.AppliedVersion = version
+	mapping.AppliedOn = time.Now()
+	mapping.Status = bean.ApplyStatusSucceeded
+	mapping.Message = ""
+	mapping.UpdateAuditLog(userId)
+	skipped, err := impl.provisionNamespace(template.Name, version, spec, environment)
+	if err != nil {
+		impl.logger.Errorw("error in applying namespace template", "templateId", template.Id, "version", version, "envId", environment.Id, "err", err)
+		mapping.Status = bean.ApplyStatusFailed
+		mapping.Message = err.Error()
+	} else if len(skipped) > 0 {
+		mapping.Message = strings.Join(skipped, "; ")
+	}
+	err = impl.namespaceTemplateRepository.SaveOrUpdateEnvMapping(mapping)
+	if err != nil {
+		impl.logger.Errorw("error in saving namespace template mapping", "envId", environment.Id, "err", err)
+		return nil, err
+	}
+	return toEnvironmentMappingDto(mapping, environment), nil
+}
+
+func (impl *NamespaceTemplateServiceImpl) getClientSet(clusterId int) (*kubernetes.Clientset, error) {
+	clusterBean, err := impl.clusterReadService.FindById(clusterId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cluster", "clusterId", clusterId, "err", err)
+		return nil, err
+	}
+	if clusterBean.IsVirtualCluster {
+		return nil, fmt.Errorf("cluster %s is an isolated cluster", clusterBean.ClusterName)
+	}
+	clusterConfig, err := clusterBean.ResolveClusterConfig(impl.clusterCredentialService)
+	if err != nil {
+		impl.logger.Errorw("error in resolving cluster credentials", "clusterId", clusterBean.Id, "err", err)
+		return nil, err
+	}
+	_, _, clientSet, err := impl.k8sUtil.GetK8sConfigAndClients(clusterConfig)
+	if err != nil {
+		return nil, err
+	}
+	return clientSet, nil
+}
+
+// provisionNamespace applies the template version to the namespace of the environment and returns why the image
+// pull secrets of some registries were not created
+func (impl *NamespaceTemplateServiceImpl) provisionNamespace(templateName string, version int, spec *bean.NamespaceTemplateSpec, environment *repository.Environment) ([]string, error) {
+	clientSet, err := impl.getClientSet(environment.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	namespaceName := environment.Namespace
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		namespace, err := clientSet.CoreV1().Namespaces().Get(ctx, namespaceName, metav1.GetOptions{})
+		if k8sErrors.IsNotFound(err) {
+			namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespaceName}}
+			applyNamespaceMetadata(namespace, spec, templateName)
+			_, err = clientSet.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
+			return err
+		} else if err != nil {
+			return err
+		}
+		if applyNamespaceMetadata(namespace, spec, templateName) {
+			_, err = clientSet.CoreV1().Namespaces().Update(ctx, namespace, metav1.UpdateOptions{})
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error in updating namespace: %v", err)
+	}
+	err = impl.applyResourceQuota(ctx, clientSet, buildResourceQuota(spec, namespaceName, templateName, version), namespaceName)
+	if err != nil {
+		return nil, fmt.Errorf("error in applying resource quota: %v", err)
+	}
+	err = impl.applyLimitRange(ctx, clientSet, buildLimitRange(spec, namespaceName, templateName, version), namespaceName)
+	if err != nil {
+		return nil, fmt.Errorf("error in applying limit range: %v", err)
+	}
+	err = impl.applyNetworkPolicies(ctx, clientSet, buildNetworkPolicies(spec, namespaceName, templateName, version), namespaceName)
+	if err != nil {
+		return nil, fmt.Errorf("error in applying network policies: %v", err)
+	}
+	skipped, err := impl.applyImagePullSecrets(ctx, clientSet, spec.ImagePullSecretRegistryIds, environment.ClusterId, namespaceName, templateName, version)
+	if err != nil {
+		return nil, fmt.Errorf("error in applying image pull secrets: %v", err)
+	}
+	return skipped, nil
+}
+
+func (impl *NamespaceTemplateServiceImpl) applyResourceQuota(ctx context.Context, clientSet *kubernetes.Clientset, desired *corev1.ResourceQuota, namespace string) error {
+	client := clientSet.CoreV1().ResourceQuotas(namespace)
+	existing, err := client.Get(ctx, bean.ResourceQuotaName, metav1.GetOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+	switch {
+	case desired == nil && exists && isManaged(existing.ObjectMeta):
+		return client.Delete(ctx, bean.ResourceQuotaName, metav1.DeleteOptions{})
+	case desired == nil:
+		return nil
+	case !exists:
+		_, err = client.Create(ctx, desired, metav1.CreateOptions{})
+		return err
+	}
+	existing.Labels, existing.Annotations, existing.Spec = mergeMetadata(existing.Labels, desired.Labels), mergeMetadata(existing.Annotations, desired.Annotations), desired.Spec
+	_, err = client.Update(ctx, existing, metav1.UpdateOptions{})
+	return err
+}
+
+func (impl *NamespaceTemplateServiceImpl) applyLimitRange(ctx context.Context, clientSet *kubernetes.Clientset, desired *corev1.LimitRange, namespace string) error {
+	client := clientSet.CoreV1().LimitRanges(namespace)
+	existing, err := client.Get(ctx, bean.LimitRangeName, metav1.GetOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+	switch {
+	case desired == nil && exists && isManaged(existing.ObjectMeta):
+		return client.Delete(ctx, bean.LimitRangeName, metav1.DeleteOptions{})
+	case desired == nil:
+		return nil
+	case !exists:
+		_, err = client.Create(ctx, desired, metav1.CreateOptions{})
+		return err
+	}
+	existing.Labels, existing.Annotations, existing.Spec = mergeMetadata(existing.Labels, desired.Labels), mergeMetadata(existing.Annotations, desired.Annotations), desired.Spec
+	_, err = client.Update(ctx, existing, metav1.UpdateOptions{})
+	return err
+}
+
+// applyNetworkPolicies creates or updates the desired policies and deletes the managed ones no longer in the template
+func (impl *NamespaceTemplateServiceImpl) applyNetworkPolicies(ctx context.Context, clientSet *kubernetes.Clientset, desired []*networkingv1.NetworkPolicy, namespace string) error {
+	client := clientSet.NetworkingV1().NetworkPolicies(namespace)
+	managed, err := client.List(ctx, metav1.ListOptions{LabelSelector: getManagedSelector()})
+	if err != nil {
+		return err
+	}
+	stale := make(map[string]bool, len(managed.Items))
+	for _, policy := range managed.Items {
+		stale[policy.Name] = true
+	}
+	for _, policy := range desired {
+		delete(stale, policy.Name)
+		existing, err := client.Get(ctx, policy.Name, metav1.GetOptions{})
+		if k8sErrors.IsNotFound(err) {
+			_, err = client.Create(ctx, policy, metav1.CreateOptions{})
+		} else if err == nil {
+			existing.Labels, existing.Annotations, existing.Spec = mergeMetadata(existing.Labels, policy.Labels), mergeMetadata(existing.Annotations, policy.Annotations), policy.Spec
+			_, err = client.Update(ctx, existing, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			return fmt.Errorf("network policy %s: %v", policy.Name, err)
+		}
+	}
+	for name := range stale {
+		err = client.Delete(ctx, name, metav1.DeleteOptions{})
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			return fmt.Errorf("network policy %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// applyImagePullSecrets creates the pull secrets of the registries allowed on the cluster and adds them to the default
+// service account, it returns why the secrets of the other registries were not created
+func (impl *NamespaceTemplateServiceImpl) applyImagePullSecrets(ctx context.Context, clientSet *kubernetes.Clientset, registryIds []string, clusterId int, namespace, templateName string, version int) ([]string, error) {
+	secretNames, skipped, err := impl.getImagePullSecretNames(registryIds, clusterId)
+	if err != nil {
+		return nil, err
+	}
+	client := clientSet.CoreV1().Secrets(namespace)
+	for _, registryId := range registryIds {
+		secretName, ok := secretNames[registryId]
+		if !ok {
+			continue
+		}
+		dockerRegistryBean, err := impl.dockerArtifactStoreRepository.FindOne(registryId)
+		if err != nil {
+			return nil, fmt.Errorf("registry %s: %v", registryId, err)
+		}
+		ipsCredential, err := dockerRegistry.GetIpsCredential(dockerRegistryBean)
+		if err != nil {
+			return nil, fmt.Errorf("registry %s: %v", registryId, err)
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: getManagedObjectMeta(secretName, namespace, templateName, version),
+			Type:       corev1.SecretTypeDockerConfigJson,
+			Data:       dockerRegistry.BuildIpsData(ipsCredential.RegistryURL, ipsCredential.Username, ipsCredential.Password, ipsCredential.Email),
+		}
+		existing, err := client.Get(ctx, secretName, metav1.GetOptions{})
+		if k8sErrors.IsNotFound(err) {
+			_, err = client.Create(ctx, secret, metav1.CreateOptions{})
+		} else if err == nil {
+			existing.Labels, existing.Annotations, existing.Data = mergeMetadata(existing.Labels, secret.Labels), mergeMetadata(existing.Annotations, secret.Annotations), secret.Data
+			_, err = client.Update(ctx, existing, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("secret %s: %v", secretName, err)
+		}
+	}
+	if len(secretNames) == 0 {
+		return skipped, nil
+	}
+	return skipped, retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		serviceAccounts := clientSet.CoreV1().ServiceAccounts(namespace)
+		serviceAccount, err := serviceAccounts.Get(ctx, bean.DefaultServiceAccountName, metav1.GetOptions{})
+		if k8sErrors.IsNotFound(err) {
+			// the service account controller may not have created it yet in a new namespace
+			serviceAccount = &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: bean.DefaultServiceAccountName, Namespace: namespace}}
+			addImagePullSecrets(serviceAccount, getSortedSecretNames(registryIds, secretNames))
+			_, err = serviceAccounts.Create(ctx, serviceAccount, metav1.CreateOptions{})
+			return err
+		} else if err != nil {
+			return err
+		}
+		if addImagePullSecrets(serviceAccount, getSortedSecretNames(registryIds, secretNames)) {
+			_, err = serviceAccounts.Update(ctx, serviceAccount, metav1.UpdateOptions{})
+		}
+		return err
+	})
+}
+
+// getImagePullSecretNames returns the pull secret name of every registry whose secret the template creates on the
+// cluster, and why the others are skipped
+func (impl *NamespaceTemplateServiceImpl) getImagePullSecretNames(registryIds []string, clusterId int) (map[string]string, []string, error) {
+	secretNames := make(map[string]string, len(registryIds))
+	skipped := make([]string, 0)
+	for _, registryId := range registryIds {
+		dockerRegistryBean, err := impl.dockerArtifactStoreRepository.FindOne(registryId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching container registry", "registryId", registryId, "err", err)
+			return nil, nil, fmt.Errorf("registry %s: %v", registryId, err)
+		}
+		secretName, skipReason := getImagePullSecretName(dockerRegistryBean, clusterId)
+		if len(skipReason) > 0 {
+			skipped = append(skipped, skipReason)
+			continue
+		}
+		secretNames[registryId] = secretName
+	}
+	return secretNames, skipped, nil
+}
+
+func (impl *NamespaceTemplateServiceImpl) getEnvironmentDrift(templateName string, version int, spec *bean.NamespaceTemplateSpec, environment *repository.Environment) ([]*bean.DriftItem, error) {
+	clientSet, err := impl.getClientSet(environment.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	secretNames, _, err := impl.getImagePullSecretNames(spec.ImagePullSecretRegistryIds, environment.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	live, err := impl.getLiveNamespaceState(clientSet, environment.Namespace, getSortedSecretNames(spec.ImagePullSecretRegistryIds, secretNames))
+	if err != nil {
+		return nil, err
+	}
+	return computeDrift(spec, templateName, version, getSortedSecretNames(spec.ImagePullSecretRegistryIds, secretNames), live), nil
+}
+
+func (impl *NamespaceTemplateServiceImpl) getLiveNamespaceState(clientSet *kubernetes.Clientset, namespace string, secretNames []string) (*liveNamespaceState, error) {
+	ctx := context.Background()
+	live := &liveNamespaceState{SecretNames: make(map[string]bool, len(secretNames))}
+	namespaceObject, err := clientSet.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if k8sErrors.IsNotFound(err) {
+		return live, nil
+	} else if err != nil {
+		return nil, err
+	}
+	live.Namespace = namespaceObject
+	resourceQuota, err := clientSet.CoreV1().ResourceQuotas(namespace).Get(ctx, bean.ResourceQuotaName, metav1.GetOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return nil, err
+	} else if err == nil {
+		live.ResourceQuota = resourceQuota
+	}
+	limitRange, err := clientSet.CoreV1().LimitRanges(namespace).Get(ctx, bean.LimitRangeName, metav1.GetOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return nil, err
+	} else if err == nil {
+		live.LimitRange = limitRange
+	}
+	policies, err := clientSet.NetworkingV1().NetworkPolicies(namespace).List(ctx, metav1.ListOptions{LabelSelector: getManagedSelector()})
+	if err != nil {
+		return nil, err
+	}
+	live.NetworkPolicies = policies.Items
+	for _, secretName := range secretNames {
+		_, err = clientSet.CoreV1().Secrets(namespace).Get(ctx, secretName, metav1.GetOptions{})
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			return nil, err
+		}
+		live.SecretNames[secretName] = err == nil
+	}
+	serviceAccount, err := clientSet.CoreV1().ServiceAccounts(namespace).Get(ctx, bean.DefaultServiceAccountName, metav1.GetOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return nil, err
+	} else if err == nil {
+		live.ServiceAccount = serviceAccount
+	}
+	return live, nil
+}
+
+func getManagedSelector() string {
+	return labels.SelectorFromSet(labels.Set{bean.ManagedByLabelKey: bean.ManagedByLabelValue}).String()
+}
+
+func isManaged(objectMeta metav1.ObjectMeta) bool {
+	return objectMeta.Labels[bean.ManagedByLabelKey] == bean.ManagedByLabelValue
+}
+
+func mergeMetadata(existing, desired map[string]string) map[string]string {
+	if existing == nil {
+		existing = make(map[string]string, len(desired))
+	}
+	for key, value := range desired {
+		existing[key] = value
+	}
+	return existing
+}
+
+// getSortedSecretNames returns the secret names in the order of the registries in the template
+func getSortedSecretNames(registryIds []string, secretNames map[string]string) []string {
+	names := make([]string, 0, len(secretNames))
+	for _, registryId := range registryIds {
+		if secretName, ok := secretNames[registryId]; ok {
+			names = append(names, secretName)
+		}
+	}
+	return names
+}
+
+func parseSpec(specJson string) (*bean.NamespaceTemplateSpec, error) {
+	spec := &bean.NamespaceTemplateSpec{}
+	err := json.Unmarshal([]byte(specJson), spec)
+	if err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+func toTemplateDto(template *templateRepository.NamespaceTemplate) *bean.NamespaceTemplateDto {
+	return &bean.NamespaceTemplateDto{
+		Id:            template.Id,
+		Name:          template.Name,
+		Description:   template.Description,
+		LatestVersion: template.LatestVersion,
+		UpdatedOn:     template.UpdatedOn,
+	}
+}
+
+func toEnvironmentMappingDto(mapping *templateRepository.NamespaceTemplateEnvMapping, environment *repository.Environment) *bean.EnvironmentMappingDto {
+	dto := &bean.EnvironmentMappingDto{
+		EnvId:          mapping.EnvId,
+		AppliedVersion: mapping.AppliedVersion,
+		Status:         mapping.Status,
+		Message:        mapping.Message,
+		AppliedOn:      mapping.AppliedOn,
+	}
+	if environment != nil {
+		dto.EnvName = environment.Name
+		dto.Namespace = environment.Namespace
+		dto.ClusterId = environment.ClusterId
+	}
+	return dto
+}
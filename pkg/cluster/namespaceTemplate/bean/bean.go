@@ -0,0 +1,148 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"time"
+)
+
+const (
+	// ManagedByLabelKey marks the resources created in a namespace from a template
+	ManagedByLabelKey         = "devtron.ai/managed-by"
+	ManagedByLabelValue       = "namespace-template"
+	TemplateLabelKey          = "devtron.ai/namespace-template"
+	TemplateVersionAnnotKey   = "devtron.ai/namespace-template-version"
+	AppliedLabelsAnnotKey     = "devtron.ai/namespace-template-labels"
+	AppliedAnnotationsKey     = "devtron.ai/namespace-template-annotations"
+	ResourceQuotaName         = "devtron-namespace-quota"
+	LimitRangeName            = "devtron-namespace-limits"
+	DefaultServiceAccountName = "default"
+)
+
+const (
+	ApplyStatusSucceeded = "Succeeded"
+	ApplyStatusFailed    = "Failed"
+)
+
+const (
+	DriftTypeMissing         = "Missing"
+	DriftTypeModified        = "Modified"
+	DriftTypeUnexpected      = "Unexpected"
+	DriftTypeOutdatedVersion = "OutdatedVersion"
+)
+
+const (
+	KindNamespace      = "Namespace"
+	KindResourceQuota  = "ResourceQuota"
+	KindLimitRange     = "LimitRange"
+	KindNetworkPolicy  = "NetworkPolicy"
+	KindSecret         = "Secret"
+	KindServiceAccount = "ServiceAccount"
+)
+
+// NamespaceTemplateSpec is what a template version provisions in the namespace of an environment
+type NamespaceTemplateSpec struct {
+	Labels          map[string]string         `json:"labels,omitempty"`
+	Annotations     map[string]string         `json:"annotations,omitempty"`
+	ResourceQuota   *corev1.ResourceQuotaSpec `json:"resourceQuota,omitempty"`
+	LimitRange      *corev1.LimitRangeSpec    `json:"limitRange,omitempty"`
+	NetworkPolicies []*NetworkPolicyTemplate  `json:"networkPolicies,omitempty"`
+	// ImagePullSecretRegistryIds are container registries whose image pull secret is created in the namespace
+	// and added to its default service account
+	ImagePullSecretRegistryIds []string `json:"imagePullSecretRegistryIds,omitempty"`
+}
+
+type NetworkPolicyTemplate struct {
+	Name string                         `json:"name" validate:"required"`
+	Spec networkingv1.NetworkPolicySpec `json:"spec"`
+}
+
+type NamespaceTemplateRequest struct {
+	Id          int                    `json:"id"`
+	Name        string                 `json:"name" validate:"required,max=100"`
+	Description string                 `json:"description"`
+	Spec        *NamespaceTemplateSpec `json:"spec" validate:"required"`
+}
+
+type NamespaceTemplateDto struct {
+	Id            int                            `json:"id"`
+	Name          string                         `json:"name"`
+	Description   string                         `json:"description"`
+	LatestVersion int                            `json:"latestVersion"`
+	Spec          *NamespaceTemplateSpec         `json:"spec,omitempty"`
+	Versions      []*NamespaceTemplateVersionDto `json:"versions,omitempty"`
+	Environments  []*EnvironmentMappingDto       `json:"environments,omitempty"`
+	UpdatedOn     time.Time                      `json:"updatedOn"`
+}
+
+type NamespaceTemplateVersionDto struct {
+	Version   int                    `json:"version"`
+	Spec      *NamespaceTemplateSpec `json:"spec,omitempty"`
+	CreatedBy int32                  `json:"createdBy"`
+	CreatedOn time.Time              `json:"createdOn"`
+}
+
+type EnvironmentMappingDto struct {
+	EnvId          int       `json:"envId"`
+	EnvName        string    `json:"envName,omitempty"`
+	Namespace      string    `json:"namespace,omitempty"`
+	ClusterId      int       `json:"clusterId,omitempty"`
+	AppliedVersion int       `json:"appliedVersion"`
+	Status         string    `json:"status"`
+	Message        string    `json:"message,omitempty"`
+	AppliedOn      time.Time `json:"appliedOn"`
+}
+
+// ApplyTemplateRequest re-applies a template version to the environments using it, all of them when EnvIds is empty.
+// the latest version is applied when Version is 0
+type ApplyTemplateRequest struct {
+	TemplateId int   `json:"-"`
+	Version    int   `json:"version"`
+	EnvIds     []int `json:"envIds"`
+}
+
+type AttachTemplateRequest struct {
+	EnvId      int `json:"-"`
+	TemplateId int `json:"templateId" validate:"required"`
+	Version    int `json:"version"`
+}
+
+type DriftItem struct {
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+type EnvironmentDrift struct {
+	EnvId          int          `json:"envId"`
+	EnvName        string       `json:"envName"`
+	Namespace      string       `json:"namespace"`
+	AppliedVersion int          `json:"appliedVersion"`
+	InSync         bool         `json:"inSync"`
+	Error          string       `json:"error,omitempty"`
+	Drifts         []*DriftItem `json:"drifts"`
+}
+
+type TemplateDriftReport struct {
+	TemplateId    int                 `json:"templateId"`
+	TemplateName  string              `json:"templateName"`
+	LatestVersion int                 `json:"latestVersion"`
+	Environments  []*EnvironmentDrift `json:"environments"`
+}
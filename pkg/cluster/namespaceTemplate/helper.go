@@ -0,0 +1,356 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package namespaceTemplate
+
+import (
+	"fmt"
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/devtron-labs/devtron/pkg/cluster/namespaceTemplate/bean"
+	"github.com/devtron-labs/devtron/pkg/dockerRegistry"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// liveNamespaceState is what a template manages in a namespace as currently present in the cluster
+type liveNamespaceState struct {
+	Namespace       *corev1.Namespace
+	ResourceQuota   *corev1.ResourceQuota
+	LimitRange      *corev1.LimitRange
+	NetworkPolicies []networkingv1.NetworkPolicy
+	SecretNames     map[string]bool
+	ServiceAccount  *corev1.ServiceAccount
+}
+
+func validateSpec(spec *bean.NamespaceTemplateSpec) error {
+	if spec == nil {
+		return fmt.Errorf("spec is required")
+	}
+	for key, value := range spec.Labels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid label key %q: %s", key, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("invalid value of label %q: %s", key, strings.Join(errs, ", "))
+		}
+	}
+	for key := range spec.Annotations {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid annotation key %q: %s", key, strings.Join(errs, ", "))
+		}
+	}
+	policyNames := make(map[string]bool, len(spec.NetworkPolicies))
+	for _, policy := range spec.NetworkPolicies {
+		if policy == nil {
+			return fmt.Errorf("network policy can not be empty")
+		}
+		if errs := validation.IsDNS1123Subdomain(policy.Name); len(errs) > 0 {
+			return fmt.Errorf("invalid network policy name %q: %s", policy.Name, strings.Join(errs, ", "))
+		}
+		if policyNames[policy.Name] {
+			return fmt.Errorf("network policy %q is defined more than once", policy.Name)
+		}
+		policyNames[policy.Name] = true
+	}
+	registryIds := make(map[string]bool, len(spec.ImagePullSecretRegistryIds))
+	for _, registryId := range spec.ImagePullSecretRegistryIds {
+		if len(registryId) == 0 || registryIds[registryId] {
+			return fmt.Errorf("image pull secret registries should be unique and non empty")
+		}
+		registryIds[registryId] = true
+	}
+	return nil
+}
+
+func getManagedObjectMeta(name, namespace, templateName string, version int) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      name,
+		Namespace: namespace,
+		Labels: map[string]string{
+			bean.ManagedByLabelKey: bean.ManagedByLabelValue,
+			bean.TemplateLabelKey:  templateName,
+		},
+		Annotations: map[string]string{
+			bean.TemplateVersionAnnotKey: strconv.Itoa(version),
+		},
+	}
+}
+
+func buildResourceQuota(spec *bean.NamespaceTemplateSpec, namespace, templateName string, version int) *corev1.ResourceQuota {
+	if spec.ResourceQuota == nil {
+		return nil
+	}
+	return &corev1.ResourceQuota{
+		ObjectMeta: getManagedObjectMeta(bean.ResourceQuotaName, namespace, templateName, version),
+		Spec:       *spec.ResourceQuota.DeepCopy(),
+	}
+}
+
+func buildLimitRange(spec *bean.NamespaceTemplateSpec, namespace, templateName string, version int) *corev1.LimitRange {
+	if spec.LimitRange == nil {
+		return nil
+	}
+	limitRange := &corev1.LimitRange{
+		ObjectMeta: getManagedObjectMeta(bean.LimitRangeName, namespace, templateName, version),
+		Spec:       *spec.LimitRange.DeepCopy(),
+	}
+	normaliseLimitRangeSpec(&limitRange.Spec)
+	return limitRange
+}
+
+func buildNetworkPolicies(spec *bean.NamespaceTemplateSpec, namespace, templateName string, version int) []*networkingv1.NetworkPolicy {
+	policies := make([]*networkingv1.NetworkPolicy, 0, len(spec.NetworkPolicies))
+	for _, policyTemplate := range spec.NetworkPolicies {
+		policy := &networkingv1.NetworkPolicy{
+			ObjectMeta: getManagedObjectMeta(policyTemplate.Name, namespace, templateName, version),
+			Spec:       *policyTemplate.Spec.DeepCopy(),
+		}
+		normaliseNetworkPolicySpec(&policy.Spec)
+		policies = append(policies, policy)
+	}
+	return policies
+}
+
+// normaliseLimitRangeSpec applies the defaults the api server sets on container limits so that
+// the desired spec can be compared with the live one
+func normaliseLimitRangeSpec(spec *corev1.LimitRangeSpec) {
+	for i := range spec.Limits {
+		item := &spec.Limits[i]
+		if item.Type != corev1.LimitTypeContainer {
+			continue
+		}
+		if item.Default == nil {
+			item.Default = make(corev1.ResourceList)
+		}
+		if item.DefaultRequest == nil {
+			item.DefaultRequest = make(corev1.ResourceList)
+		}
+		for key, value := range item.Max {
+			if _, ok := item.Default[key]; !ok {
+				item.Default[key] = value.DeepCopy()
+			}
+		}
+		for key, value := range item.Default {
+			if _, ok := item.DefaultRequest[key]; !ok {
+				item.DefaultRequest[key] = value.DeepCopy()
+			}
+		}
+		for key, value := range item.Min {
+			if _, ok := item.DefaultRequest[key]; !ok {
+				item.DefaultRequest[key] = value.DeepCopy()
+			}
+		}
+	}
+}
+
+// normaliseNetworkPolicySpec applies the defaults the api server sets on network policies
+func normaliseNetworkPolicySpec(spec *networkingv1.NetworkPolicySpec) {
+	defaultProtocol := func(ports []networkingv1.NetworkPolicyPort) {
+		for i := range ports {
+			if ports[i].Protocol == nil {
+				protocol := corev1.ProtocolTCP
+				ports[i].Protocol = &protocol
+			}
+		}
+	}
+	for i := range spec.Ingress {
+		defaultProtocol(spec.Ingress[i].Ports)
+	}
+	for i := range spec.Egress {
+		defaultProtocol(spec.Egress[i].Ports)
+	}
+	if len(spec.PolicyTypes) == 0 {
+		spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+		if len(spec.Egress) > 0 {
+			spec.PolicyTypes = append(spec.PolicyTypes, networkingv1.PolicyTypeEgress)
+		}
+	}
+}
+
+// applyNamespaceMetadata sets the template labels and annotations on the namespace, keys set by a previous
+// version and dropped since are removed. returns true if the namespace was changed
+func applyNamespaceMetadata(namespace *corev1.Namespace, spec *bean.NamespaceTemplateSpec, templateName string) bool {
+	if namespace.Labels == nil {
+		namespace.Labels = make(map[string]string)
+	}
+	if namespace.Annotations == nil {
+		namespace.Annotations = make(map[string]string)
+	}
+	changed := false
+	setValue := func(values map[string]string, key, value string) {
+		if existing, ok := values[key]; !ok || existing != value {
+			values[key] = value
+			changed = true
+		}
+	}
+	removeStaleKeys := func(values map[string]string, previousKeys string, desired map[string]string) {
+		for _, key := range strings.Split(previousKeys, ",") {
+			if _, ok := desired[key]; len(key) > 0 && !ok {
+				if _, exists := values[key]; exists {
+					delete(values, key)
+					changed = true
+				}
+			}
+		}
+	}
+	removeStaleKeys(namespace.Labels, namespace.Annotations[bean.AppliedLabelsAnnotKey], spec.Labels)
+	removeStaleKeys(namespace.Annotations, namespace.Annotations[bean.AppliedAnnotationsKey], spec.Annotations)
+	for key, value := range spec.Labels {
+		setValue(namespace.Labels, key, value)
+	}
+	for key, value := range spec.Annotations {
+		setValue(namespace.Annotations, key, value)
+	}
+	setValue(namespace.Labels, bean.TemplateLabelKey, templateName)
+	setValue(namespace.Annotations, bean.AppliedLabelsAnnotKey, joinSortedKeys(spec.Labels))
+	setValue(namespace.Annotations, bean.AppliedAnnotationsKey, joinSortedKeys(spec.Annotations))
+	return changed
+}
+
+func joinSortedKeys(values map[string]string) string {
+	return strings.Join(sortedKeys(values), ",")
+}
+
+// addImagePullSecrets adds the missing secrets to the service account, returns true if it was changed
+func addImagePullSecrets(serviceAccount *corev1.ServiceAccount, secretNames []string) bool {
+	existing := make(map[string]bool, len(serviceAccount.ImagePullSecrets))
+	for _, reference := range serviceAccount.ImagePullSecrets {
+		existing[reference.Name] = true
+	}
+	changed := false
+	for _, secretName := range secretNames {
+		if !existing[secretName] {
+			serviceAccount.ImagePullSecrets = append(serviceAccount.ImagePullSecrets, corev1.LocalObjectReference{Name: secretName})
+			existing[secretName] = true
+			changed = true
+		}
+	}
+	return changed
+}
+
+// getImagePullSecretName returns the name of the pull secret the template creates for the registry on the cluster, or
+// why it creates none. The ips cluster lists of the registry apply as they do on deployment, and ecr registries are left
+// to the deployment as their tokens expire in 12 hours and only a deployment refreshes them
+func getImagePullSecretName(registry *dockerRegistryRepository.DockerArtifactStore, clusterId int) (string, string) {
+	ipsConfig := registry.IpsConfig
+	if ipsConfig == nil || !dockerRegistry.CheckIfImagePullSecretAccessProvided(ipsConfig.AppliedClusterIdsCsv, ipsConfig.IgnoredClusterIdsCsv, clusterId, false) {
+		return "", fmt.Sprintf("image pull secret of registry %s skipped, the registry does not allow image pull secrets on this cluster", registry.Id)
+	}
+	if registry.RegistryType == dockerRegistryRepository.REGISTRYTYPE_ECR {
+		return "", fmt.Sprintf("image pull secret of registry %s skipped, ecr image pull secrets are created on deployment", registry.Id)
+	}
+	return dockerRegistry.BuildIpsName(registry.Id, string(ipsConfig.CredentialType), ipsConfig.CredentialValue), ""
+}
+
+// computeDrift compares the live state of a namespace with what the template version provisions in it
+func computeDrift(spec *bean.NamespaceTemplateSpec, templateName string, version int, imagePullSecretNames []string, live *liveNamespaceState) []*bean.DriftItem {
+	drifts := make([]*bean.DriftItem, 0)
+	if live.Namespace == nil {
+		return append(drifts, &bean.DriftItem{Kind: bean.KindNamespace, Type: bean.DriftTypeMissing, Message: "namespace not found"})
+	}
+	namespaceName := live.Namespace.Name
+	for _, key := range sortedKeys(spec.Labels) {
+		if value, ok := live.Namespace.Labels[key]; !ok {
+			drifts = append(drifts, &bean.DriftItem{Kind: bean.KindNamespace, Name: namespaceName, Type: bean.DriftTypeMissing, Message: fmt.Sprintf("label %s is missing", key)})
+		} else if value != spec.Labels[key] {
+			drifts = append(drifts, &bean.DriftItem{Kind: bean.KindNamespace, Name: namespaceName, Type: bean.DriftTypeModified, Message: fmt.Sprintf("label %s is %q, expected %q", key, value, spec.Labels[key])})
+		}
+	}
+	for _, key := range sortedKeys(spec.Annotations) {
+		if value, ok := live.Namespace.Annotations[key]; !ok {
+			drifts = append(drifts, &bean.DriftItem{Kind: bean.KindNamespace, Name: namespaceName, Type: bean.DriftTypeMissing, Message: fmt.Sprintf("annotation %s is missing", key)})
+		} else if value != spec.Annotations[key] {
+			drifts = append(drifts, &bean.DriftItem{Kind: bean.KindNamespace, Name: namespaceName, Type: bean.DriftTypeModified, Message: fmt.Sprintf("annotation %s has been changed", key)})
+		}
+	}
+
+	desiredQuota := buildResourceQuota(spec, namespaceName, templateName, version)
+	switch {
+	case desiredQuota != nil && live.ResourceQuota == nil:
+		drifts = append(drifts, &bean.DriftItem{Kind: bean.KindResourceQuota, Name: bean.ResourceQuotaName, Type: bean.DriftTypeMissing, Message: "resource quota not found"})
+	case desiredQuota == nil && live.ResourceQuota != nil:
+		drifts = append(drifts, &bean.DriftItem{Kind: bean.KindResourceQuota, Name: bean.ResourceQuotaName, Type: bean.DriftTypeUnexpected, Message: "resource quota is not part of the template"})
+	case desiredQuota != nil && !equality.Semantic.DeepEqual(desiredQuota.Spec, live.ResourceQuota.Spec):
+		drifts = append(drifts, &bean.DriftItem{Kind: bean.KindResourceQuota, Name: bean.ResourceQuotaName, Type: bean.DriftTypeModified, Message: "resource quota spec differs from the template"})
+	}
+
+	desiredLimitRange := buildLimitRange(spec, namespaceName, templateName, version)
+	switch {
+	case desiredLimitRange != nil && live.LimitRange == nil:
+		drifts = append(drifts, &bean.DriftItem{Kind: bean.KindLimitRange, Name: bean.LimitRangeName, Type: bean.DriftTypeMissing, Message: "limit range not found"})
+	case desiredLimitRange == nil && live.LimitRange != nil:
+		drifts = append(drifts, &bean.DriftItem{Kind: bean.KindLimitRange, Name: bean.LimitRangeName, Type: bean.DriftTypeUnexpected, Message: "limit range is not part of the template"})
+	case desiredLimitRange != nil && !equality.Semantic.DeepEqual(desiredLimitRange.Spec, live.LimitRange.Spec):
+		drifts = append(drifts, &bean.DriftItem{Kind: bean.KindLimitRange, Name: bean.LimitRangeName, Type: bean.DriftTypeModified, Message: "limit range spec differs from the template"})
+	}
+
+	livePolicies := make(map[string]networkingv1.NetworkPolicy, len(live.NetworkPolicies))
+	for _, policy := range live.NetworkPolicies {
+		livePolicies[policy.Name] = policy
+	}
+	for _, desiredPolicy := range buildNetworkPolicies(spec, namespaceName, templateName, version) {
+		livePolicy, ok := livePolicies[desiredPolicy.Name]
+		if !ok {
+			drifts = append(drifts, &bean.DriftItem{Kind: bean.KindNetworkPolicy, Name: desiredPolicy.Name, Type: bean.DriftTypeMissing, Message: "network policy not found"})
+		} else if !equality.Semantic.DeepEqual(desiredPolicy.Spec, livePolicy.Spec) {
+			drifts = append(drifts, &bean.DriftItem{Kind: bean.KindNetworkPolicy, Name: desiredPolicy.Name, Type: bean.DriftTypeModified, Message: "network policy spec differs from the template"})
+		}
+		delete(livePolicies, desiredPolicy.Name)
+	}
+	for _, name := range sortedPolicyNames(livePolicies) {
+		drifts = append(drifts, &bean.DriftItem{Kind: bean.KindNetworkPolicy, Name: name, Type: bean.DriftTypeUnexpected, Message: "network policy is not part of the template"})
+	}
+
+	serviceAccountSecrets := make(map[string]bool)
+	if live.ServiceAccount != nil {
+		for _, reference := range live.ServiceAccount.ImagePullSecrets {
+			serviceAccountSecrets[reference.Name] = true
+		}
+	}
+	for _, secretName := range imagePullSecretNames {
+		if !live.SecretNames[secretName] {
+			drifts = append(drifts, &bean.DriftItem{Kind: bean.KindSecret, Name: secretName, Type: bean.DriftTypeMissing, Message: "image pull secret not found"})
+		}
+		if !serviceAccountSecrets[secretName] {
+			drifts = append(drifts, &bean.DriftItem{Kind: bean.KindServiceAccount, Name: bean.DefaultServiceAccountName, Type: bean.DriftTypeModified, Message: fmt.Sprintf("image pull secret %s is not set on the service account", secretName)})
+		}
+	}
+	return drifts
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedPolicyNames(policies map[string]networkingv1.NetworkPolicy) []string {
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,153 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package namespaceTemplate
+
+import (
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/devtron-labs/devtron/pkg/cluster/namespaceTemplate/bean"
+	"github.com/devtron-labs/devtron/pkg/dockerRegistry"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func TestValidateSpec(t *testing.T) {
+	assert.NotNil(t, validateSpec(nil))
+	assert.Nil(t, validateSpec(&bean.NamespaceTemplateSpec{
+		Labels:                     map[string]string{"team": "payments"},
+		NetworkPolicies:            []*bean.NetworkPolicyTemplate{{Name: "deny-all"}},
+		ImagePullSecretRegistryIds: []string{"docker-hub"},
+	}))
+	assert.NotNil(t, validateSpec(&bean.NamespaceTemplateSpec{Labels: map[string]string{"team": "not a label value"}}))
+	assert.NotNil(t, validateSpec(&bean.NamespaceTemplateSpec{Annotations: map[string]string{"invalid key/": "value"}}))
+	assert.NotNil(t, validateSpec(&bean.NamespaceTemplateSpec{NetworkPolicies: []*bean.NetworkPolicyTemplate{{Name: "deny-all"}, {Name: "deny-all"}}}))
+	assert.NotNil(t, validateSpec(&bean.NamespaceTemplateSpec{NetworkPolicies: []*bean.NetworkPolicyTemplate{{Name: "Deny_All"}}}))
+	assert.NotNil(t, validateSpec(&bean.NamespaceTemplateSpec{ImagePullSecretRegistryIds: []string{"docker-hub", "docker-hub"}}))
+}
+
+func TestApplyNamespaceMetadata(t *testing.T) {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments", Labels: map[string]string{"owner": "platform"}}}
+	spec := &bean.NamespaceTemplateSpec{Labels: map[string]string{"team": "payments", "tier": "backend"}}
+	assert.True(t, applyNamespaceMetadata(namespace, spec, "standard"))
+	assert.Equal(t, "payments", namespace.Labels["team"])
+	assert.Equal(t, "standard", namespace.Labels[bean.TemplateLabelKey])
+	assert.Equal(t, "team,tier", namespace.Annotations[bean.AppliedLabelsAnnotKey])
+	assert.False(t, applyNamespaceMetadata(namespace, spec, "standard"))
+
+	// labels dropped from the template are removed, labels not set by it are kept
+	assert.True(t, applyNamespaceMetadata(namespace, &bean.NamespaceTemplateSpec{Labels: map[string]string{"team": "payments"}}, "standard"))
+	assert.NotContains(t, namespace.Labels, "tier")
+	assert.Equal(t, "platform", namespace.Labels["owner"])
+}
+
+func TestAddImagePullSecrets(t *testing.T) {
+	serviceAccount := &corev1.ServiceAccount{ImagePullSecrets: []corev1.LocalObjectReference{{Name: "existing"}}}
+	assert.True(t, addImagePullSecrets(serviceAccount, []string{"existing", "devtron-ips-1"}))
+	assert.Equal(t, []corev1.LocalObjectReference{{Name: "existing"}, {Name: "devtron-ips-1"}}, serviceAccount.ImagePullSecrets)
+	assert.False(t, addImagePullSecrets(serviceAccount, []string{"devtron-ips-1"}))
+}
+
+func TestGetImagePullSecretName(t *testing.T) {
+	ipsConfig := func(appliedClusterIdsCsv, ignoredClusterIdsCsv string) *dockerRegistryRepository.DockerRegistryIpsConfig {
+		return &dockerRegistryRepository.DockerRegistryIpsConfig{CredentialType: dockerRegistry.IPS_CREDENTIAL_TYPE_SAME_AS_REGISTRY,
+			AppliedClusterIdsCsv: appliedClusterIdsCsv, IgnoredClusterIdsCsv: ignoredClusterIdsCsv}
+	}
+	tests := []struct {
+		name       string
+		registry   *dockerRegistryRepository.DockerArtifactStore
+		secretName string
+	}{
+		{"allowed on all clusters", &dockerRegistryRepository.DockerArtifactStore{Id: "docker-hub", IpsConfig: ipsConfig("-1", "")}, "dockerhub-dtron-ips"},
+		{"allowed on the cluster", &dockerRegistryRepository.DockerArtifactStore{Id: "docker-hub", IpsConfig: ipsConfig("1,2", "")}, "dockerhub-dtron-ips"},
+		{"not in the applied clusters", &dockerRegistryRepository.DockerArtifactStore{Id: "docker-hub", IpsConfig: ipsConfig("1", "")}, ""},
+		{"ignored on the cluster", &dockerRegistryRepository.DockerArtifactStore{Id: "docker-hub", IpsConfig: ipsConfig("", "2")}, ""},
+		{"no ips config", &dockerRegistryRepository.DockerArtifactStore{Id: "docker-hub"}, ""},
+		{"ecr registry", &dockerRegistryRepository.DockerArtifactStore{Id: "ecr", RegistryType: dockerRegistryRepository.REGISTRYTYPE_ECR, IpsConfig: ipsConfig("-1", "")}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secretName, skipReason := getImagePullSecretName(tt.registry, 2)
+			assert.Equal(t, tt.secretName, secretName)
+			assert.Equal(t, len(tt.secretName) == 0, len(skipReason) > 0)
+		})
+	}
+}
+
+func TestNormaliseLimitRangeSpec(t *testing.T) {
+	spec := &corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+		Type: corev1.LimitTypeContainer,
+		Max:  corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+		Min:  corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+	}}}
+	normaliseLimitRangeSpec(spec)
+	item := spec.Limits[0]
+	assert.True(t, resource.MustParse("2").Equal(item.Default[corev1.ResourceCPU]))
+	assert.True(t, resource.MustParse("2").Equal(item.DefaultRequest[corev1.ResourceCPU]))
+	assert.True(t, resource.MustParse("64Mi").Equal(item.DefaultRequest[corev1.ResourceMemory]))
+}
+
+func TestNormaliseNetworkPolicySpec(t *testing.T) {
+	port := networkingv1.NetworkPolicyPort{}
+	spec := &networkingv1.NetworkPolicySpec{
+		Ingress: []networkingv1.NetworkPolicyIngressRule{{Ports: []networkingv1.NetworkPolicyPort{port}}},
+		Egress:  []networkingv1.NetworkPolicyEgressRule{{}},
+	}
+	normaliseNetworkPolicySpec(spec)
+	assert.Equal(t, corev1.ProtocolTCP, *spec.Ingress[0].Ports[0].Protocol)
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}, spec.PolicyTypes)
+}
+
+func TestComputeDrift(t *testing.T) {
+	spec := &bean.NamespaceTemplateSpec{
+		Labels:          map[string]string{"team": "payments"},
+		ResourceQuota:   &corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")}},
+		NetworkPolicies: []*bean.NetworkPolicyTemplate{{Name: "deny-all"}},
+	}
+	assert.Equal(t, bean.DriftTypeMissing, computeDrift(spec, "standard", 1, nil, &liveNamespaceState{})[0].Type)
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments"}}
+	applyNamespaceMetadata(namespace, spec, "standard")
+	inSync := &liveNamespaceState{
+		Namespace:       namespace,
+		ResourceQuota:   buildResourceQuota(spec, "payments", "standard", 1),
+		NetworkPolicies: []networkingv1.NetworkPolicy{*buildNetworkPolicies(spec, "payments", "standard", 1)[0]},
+		SecretNames:     map[string]bool{"devtron-ips-1": true},
+		ServiceAccount:  &corev1.ServiceAccount{ImagePullSecrets: []corev1.LocalObjectReference{{Name: "devtron-ips-1"}}},
+	}
+	assert.Empty(t, computeDrift(spec, "standard", 1, []string{"devtron-ips-1"}, inSync))
+
+	inSync.ResourceQuota.Spec.Hard[corev1.ResourcePods] = resource.MustParse("20")
+	inSync.Namespace.Labels["team"] = "billing"
+	inSync.LimitRange = &corev1.LimitRange{}
+	inSync.NetworkPolicies = append(inSync.NetworkPolicies, networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: "allow-all"}})
+	inSync.ServiceAccount.ImagePullSecrets = nil
+	drifts := computeDrift(spec, "standard", 1, []string{"devtron-ips-1"}, inSync)
+	driftTypes := make(map[string]string)
+	for _, drift := range drifts {
+		driftTypes[drift.Kind] = drift.Type
+	}
+	assert.Equal(t, map[string]string{
+		bean.KindNamespace:      bean.DriftTypeModified,
+		bean.KindResourceQuota:  bean.DriftTypeModified,
+		bean.KindLimitRange:     bean.DriftTypeUnexpected,
+		bean.KindNetworkPolicy:  bean.DriftTypeUnexpected,
+		bean.KindServiceAccount: bean.DriftTypeModified,
+	}, driftTypes)
+}
@@ -0,0 +1,174 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type NamespaceTemplate struct {
+	tableName     struct{} `sql:"namespace_template" pg:",discard_unknown_columns"`
+	Id            int      `sql:"id,pk"`
+	Name          string   `sql:"name,notnull"`
+	Description   string   `sql:"description"`
+	LatestVersion int      `sql:"latest_version,notnull"`
+	Active        bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type NamespaceTemplateVersion struct {
+	tableName           struct{}  `sql:"namespace_template_version" pg:",discard_unknown_columns"`
+	Id                  int       `sql:"id,pk"`
+	NamespaceTemplateId int       `sql:"namespace_template_id,notnull"`
+	Version             int       `sql:"version,notnull"`
+	Spec                string    `sql:"spec,notnull"`
+	CreatedOn           time.Time `sql:"created_on,notnull"`
+	CreatedBy           int32     `sql:"created_by,notnull"`
+}
+
+type NamespaceTemplateEnvMapping struct {
+	tableName           struct{}  `sql:"namespace_template_env_mapping" pg:",discard_unknown_columns"`
+	Id                  int       `sql:"id,pk"`
+	NamespaceTemplateId int       `sql:"namespace_template_id,notnull"`
+	EnvId               int       `sql:"env_id,notnull"`
+	AppliedVersion      int       `sql:"applied_version,notnull"`
+	Status              string    `sql:"status,notnull"`
+	Message             string    `sql:"message"`
+	AppliedOn           time.Time `sql:"applied_on,notnull"`
+	sql.AuditLog
+}
+
+type NamespaceTemplateRepository interface {
+	// SaveTemplate saves a new template or the next version of an existing one together with its spec
+	SaveTemplate(template *NamespaceTemplate, version *NamespaceTemplateVersion) error
+	UpdateTemplate(template *NamespaceTemplate) error
+	FindById(id int) (*NamespaceTemplate, error)
+	FindActiveByName(name string) (*NamespaceTemplate, error)
+	FindAllActive() ([]*NamespaceTemplate, error)
+	FindVersion(templateId, version int) (*NamespaceTemplateVersion, error)
+	FindVersions(templateId int) ([]*NamespaceTemplateVersion, error)
+	SaveOrUpdateEnvMapping(mapping *NamespaceTemplateEnvMapping) error
+	FindEnvMappingByEnvId(envId int) (*NamespaceTemplateEnvMapping, error)
+	// FindEnvMappingsByTemplateId returns the mappings of active environments only
+	FindEnvMappingsByTemplateId(templateId int) ([]*NamespaceTemplateEnvMapping, error)
+}
+
+type NamespaceTemplateRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewNamespaceTemplateRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *NamespaceTemplateRepositoryImpl {
+	return &NamespaceTemplateRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (repo *NamespaceTemplateRepositoryImpl) SaveTemplate(template *NamespaceTemplate, version *NamespaceTemplateVersion) error {
+	return repo.dbConnection.RunInTransaction(func(tx *pg.Tx) error {
+		var err error
+		if template.Id == 0 {
+			err = tx.Insert(template)
+		} else {
+			err = tx.Update(template)
+		}
+		if err != nil {
+			return err
+		}
+		version.NamespaceTemplateId = template.Id
+		return tx.Insert(version)
+	})
+}
+
+func (repo *NamespaceTemplateRepositoryImpl) UpdateTemplate(template *NamespaceTemplate) error {
+	return repo.dbConnection.Update(template)
+}
+
+func (repo *NamespaceTemplateRepositoryImpl) FindById(id int) (*NamespaceTemplate, error) {
+	template := &NamespaceTemplate{}
+	err := repo.dbConnection.Model(template).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return template, err
+}
+
+func (repo *NamespaceTemplateRepositoryImpl) FindActiveByName(name string) (*NamespaceTemplate, error) {
+	template := &NamespaceTemplate{}
+	err := repo.dbConnection.Model(template).
+		Where("name = ?", name).
+		Where("active = ?", true).
+		Select()
+	return template, err
+}
+
+func (repo *NamespaceTemplateRepositoryImpl) FindAllActive() ([]*NamespaceTemplate, error) {
+	var templates []*NamespaceTemplate
+	err := repo.dbConnection.Model(&templates).
+		Where("active = ?", true).
+		Order("name ASC").
+		Select()
+	return templates, err
+}
+
+func (repo *NamespaceTemplateRepositoryImpl) FindVersion(templateId, version int) (*NamespaceTemplateVersion, error) {
+	templateVersion := &NamespaceTemplateVersion{}
+	err := repo.dbConnection.Model(templateVersion).
+		Where("namespace_template_id = ?", templateId).
+		Where("version = ?", version).
+		Select()
+	return templateVersion, err
+}
+
+func (repo *NamespaceTemplateRepositoryImpl) FindVersions(templateId int) ([]*NamespaceTemplateVersion, error) {
+	var versions []*NamespaceTemplateVersion
+	err := repo.dbConnection.Model(&versions).
+		Where("namespace_template_id = ?", templateId).
+		Order("version DESC").
+		Select()
+	return versions, err
+}
+
+func (repo *NamespaceTemplateRepositoryImpl) SaveOrUpdateEnvMapping(mapping *NamespaceTemplateEnvMapping) error {
+	if mapping.Id == 0 {
+		return repo.dbConnection.Insert(mapping)
+	}
+	return repo.dbConnection.Update(mapping)
+}
+
+func (repo *NamespaceTemplateRepositoryImpl) FindEnvMappingByEnvId(envId int) (*NamespaceTemplateEnvMapping, error) {
+	mapping := &NamespaceTemplateEnvMapping{}
+	err := repo.dbConnection.Model(mapping).
+		Where("env_id = ?", envId).
+		Select()
+	return mapping, err
+}
+
+func (repo *NamespaceTemplateRepositoryImpl) FindEnvMappingsByTemplateId(templateId int) ([]*NamespaceTemplateEnvMapping, error) {
+	var mappings []*NamespaceTemplateEnvMapping
+	err := repo.dbConnection.Model(&mappings).
+		Join("INNER JOIN environment env ON env.id = namespace_template_env_mapping.env_id").
+		Where("namespace_template_env_mapping.namespace_template_id = ?", templateId).
+		Where("env.active = ?", true).
+		Order("namespace_template_env_mapping.env_id ASC").
+		Select()
+	return mappings, err
+}
@@ -43,6 +43,7 @@ type Cluster struct {
 	IsVirtualCluster       bool              `sql:"is_virtual_cluster"`
 	InsecureSkipTlsVerify  bool              `sql:"insecure_skip_tls_verify"`
 	IsProd                 bool              `sql:"is_prod"`
+	Capabilities           map[string]bool   `sql:"capabilities"`
 	sql.AuditLog
 }
 
@@ -67,6 +68,7 @@ type ClusterRepository interface {
 	Delete(model *Cluster) error
 	MarkClusterDeleted(model *Cluster) error
 	UpdateClusterConnectionStatus(clusterId int, errorInConnecting string) error
+	UpdateClusterCapabilities(clusterId int, capabilities map[string]bool) error
 	FindActiveClusters() ([]Cluster, error)
 	SaveAll(models []*Cluster) error
 	FindByNames(clusterNames []string) ([]*Cluster, error)
@@ -207,6 +209,14 @@ func (impl ClusterRepositoryImpl) UpdateClusterConnectionStatus(clusterId int, e
 	return err
 }
 
+func (impl ClusterRepositoryImpl) UpdateClusterCapabilities(clusterId int, capabilities map[string]bool) error {
+	cluster := &Cluster{}
+	_, err := impl.dbConnection.Model(cluster).
+		Set("capabilities = ?", capabilities).Where("id = ?", clusterId).
+		Update()
+	return err
+}
+
 func (impl ClusterRepositoryImpl) FindByClusterURL(clusterURL string) (*Cluster, error) {
 	cluster := &Cluster{}
 	err := impl.dbConnection.
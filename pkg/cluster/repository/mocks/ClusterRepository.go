@@ -290,6 +290,20 @@ func (_m *ClusterRepository) Update(model *repository.Cluster) error {
 	return r0
 }
 
+// UpdateClusterCapabilities provides a mock function with given fields: clusterId, capabilities
+func (_m *ClusterRepository) UpdateClusterCapabilities(clusterId int, capabilities map[string]bool) error {
+	ret := _m.Called(clusterId, capabilities)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, map[string]bool) error); ok {
+		r0 = rf(clusterId, capabilities)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateClusterConnectionStatus provides a mock function with given fields: clusterId, errorInConnecting
 func (_m *ClusterRepository) UpdateClusterConnectionStatus(clusterId int, errorInConnecting string) error {
 	ret := _m.Called(clusterId, errorInConnecting)
@@ -583,6 +583,7 @@ func (impl *UserTerminalAccessServiceImpl) checkAndStartSession(ctx context.Cont
 			Namespace: namespace,
 			PodName:   terminalAccessPodName,
 			ClusterId: clusterId,
+			UserId:    terminalAccessData.UserId,
 		}
 		_, terminalMessage, err := impl.terminalSessionHandler.GetTerminalSession(request)
 		if err != nil {
@@ -0,0 +1,398 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canaryAnalysis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/caarlos0/env/v6"
+	k8s2 "github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/common-lib/utils/k8s/commonBean"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/timelineStatus"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/cluster/read"
+	"github.com/devtron-labs/devtron/pkg/deployment/canaryAnalysis/adapter"
+	"github.com/devtron-labs/devtron/pkg/deployment/canaryAnalysis/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/canaryAnalysis/repository"
+	"github.com/devtron-labs/devtron/pkg/k8s"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	"github.com/go-pg/pg"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"net/http"
+	"time"
+)
+
+type CanaryAnalysisService interface {
+	CreateTemplate(request *bean.AnalysisTemplateDto) (*bean.AnalysisTemplateDto, error)
+	UpdateTemplate(request *bean.AnalysisTemplateDto) (*bean.AnalysisTemplateDto, error)
+	DeleteTemplate(id int, userId int32) error
+	GetTemplateById(id int) (*bean.AnalysisTemplateDto, error)
+	GetTemplatesByAppId(appId int) ([]*bean.AnalysisTemplateDto, error)
+	// StartAnalysisRun starts a canary analysis for a triggered deployment if a template is configured for its app and environment
+	StartAnalysisRun(request *bean.StartAnalysisRequest) error
+	GetAnalysisRunsByWfrId(wfrId int) ([]*bean.AnalysisRunDto, error)
+	// ProcessDueAnalysisRuns takes a measurement for every running analysis whose interval has elapsed
+	ProcessDueAnalysisRuns()
+}
+
+type CanaryAnalysisServiceImpl struct {
+	logger                           *zap.SugaredLogger
+	canaryAnalysisRepository         repository.CanaryAnalysisRepository
+	pipelineStatusTimelineRepository pipelineConfig.PipelineStatusTimelineRepository
+	clusterReadService               read.ClusterReadService
+	k8sCommonService                 k8s.K8sCommonService
+	k8sUtil                          k8s2.K8sService
+	prometheusQueryClient            PrometheusQueryClient
+	analysisCron                     *cron.Cron
+}
+
+func GetCanaryAnalysisConfig() (*bean.CanaryAnalysisConfig, error) {
+	config := &bean.CanaryAnalysisConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		return nil, err
+	}
+	return config, err
+}
+
+func NewCanaryAnalysisServiceImpl(logger *zap.SugaredLogger,
+	canaryAnalysisRepository repository.CanaryAnalysisRepository,
+	pipelineStatusTimelineRepository pipelineConfig.PipelineStatusTimelineRepository,
+	clusterReadService read.ClusterReadService,
+	k8sCommonService k8s.K8sCommonService,
+	k8sUtil k8s2.K8sService,
+	cronLogger *cron2.CronLoggerImpl) (*CanaryAnalysisServiceImpl, error) {
+	config, err := GetCanaryAnalysisConfig()
+	if err != nil {
+		logger.Errorw("error in parsing canary analysis config", "err", err)
+		return nil, err
+	}
+	analysisCron := cron.New(cron.WithChain(cron.SkipIfStillRunning(cronLogger), cron.Recover(cronLogger)))
+	impl := &CanaryAnalysisServiceImpl{
+		logger:                           logger,
+		canaryAnalysisRepository:         canaryAnalysisRepository,
+		pipelineStatusTimelineRepository: pipelineStatusTimelineRepository,
+		clusterReadService:               clusterReadService,
+		k8sCommonService:                 k8sCommonService,
+		k8sUtil:                          k8sUtil,
+		prometheusQueryClient:            NewPrometheusQueryClientImpl(time.Duration(config.PrometheusQueryTimeoutSecs) * time.Second),
+		analysisCron:                     analysisCron,
+	}
+	analysisCron.Start()
+	_, err = analysisCron.AddFunc(fmt.Sprintf("@every %ds", config.CronIntervalSecs), impl.ProcessDueAnalysisRuns)
+	if err != nil {
+		logger.Errorw("error in starting canary analysis cron", "err", err)
+		return nil, err
+	}
+	return impl, nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) CreateTemplate(request *bean.AnalysisTemplateDto) (*bean.AnalysisTemplateDto, error) {
+	if err := ValidateAnalysisTemplate(request); err != nil {
+		return nil, err
+	}
+	existing, err := impl.canaryAnalysisRepository.FindActiveTemplateByAppIdAndEnvId(request.AppId, request.EnvId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching analysis template", "appId", request.AppId, "envId", request.EnvId, "err", err)
+		return nil, err
+	}
+	if existing != nil && existing.Id > 0 {
+		return nil, util.NewApiError(http.StatusConflict, "an analysis template already exists for this app and environment", "analysis template already exists")
+	}
+	template, err := adapter.GetTemplateDbObject(request)
+	if err != nil {
+		return nil, err
+	}
+	template.Id = 0
+	template.CreateAuditLog(request.UserId)
+	if err = impl.canaryAnalysisRepository.SaveTemplate(template); err != nil {
+		impl.logger.Errorw("error in saving analysis template", "template", template, "err", err)
+		return nil, err
+	}
+	request.Id = template.Id
+	return request, nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) UpdateTemplate(request *bean.AnalysisTemplateDto) (*bean.AnalysisTemplateDto, error) {
+	if err := ValidateAnalysisTemplate(request); err != nil {
+		return nil, err
+	}
+	existing, err := impl.canaryAnalysisRepository.FindTemplateById(request.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching analysis template", "id", request.Id, "err", err)
+		return nil, err
+	}
+	template, err := adapter.GetTemplateDbObject(request)
+	if err != nil {
+		return nil, err
+	}
+	// the scope of a template is fixed once created
+	template.AppId = existing.AppId
+	template.EnvId = existing.EnvId
+	template.AuditLog = existing.AuditLog
+	template.UpdateAuditLog(request.UserId)
+	if err = impl.canaryAnalysisRepository.UpdateTemplate(template); err != nil {
+		impl.logger.Errorw("error in updating analysis template", "template", template, "err", err)
+		return nil, err
+	}
+	request.AppId = template.AppId
+	request.EnvId = template.EnvId
+	return request, nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) DeleteTemplate(id int, userId int32) error {
+	template, err := impl.canaryAnalysisRepository.FindTemplateById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching analysis template", "id", id, "err", err)
+		return err
+	}
+	template.Active = false
+	template.UpdateAuditLog(userId)
+	return impl.canaryAnalysisRepository.UpdateTemplate(template)
+}
+
+func (impl *CanaryAnalysisServiceImpl) GetTemplateById(id int) (*bean.AnalysisTemplateDto, error) {
+	template, err := impl.canaryAnalysisRepository.FindTemplateById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching analysis template", "id", id, "err", err)
+		return nil, err
+	}
+	return adapter.GetTemplateDto(template)
+}
+
+func (impl *CanaryAnalysisServiceImpl) GetTemplatesByAppId(appId int) ([]*bean.AnalysisTemplateDto, error) {
+	templates, err := impl.canaryAnalysisRepository.FindTemplatesByAppId(appId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching analysis templates", "appId", appId, "err", err)
+		return nil, err
+	}
+	result := make([]*bean.AnalysisTemplateDto, 0, len(templates))
+	for _, template := range templates {
+		templateDto, err := adapter.GetTemplateDto(template)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, templateDto)
+	}
+	return result, nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) StartAnalysisRun(request *bean.StartAnalysisRequest) error {
+	template, err := impl.canaryAnalysisRepository.FindActiveTemplateByAppIdAndEnvId(request.AppId, request.EnvId)
+	if err == pg.ErrNoRows {
+		return nil
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching analysis template", "appId", request.AppId, "envId", request.EnvId, "err", err)
+		return err
+	}
+	// a new deployment supersedes the analysis of the previous one
+	runningRuns, err := impl.canaryAnalysisRepository.FindRunningRunsByPipelineId(request.PipelineId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching running analysis runs", "pipelineId", request.PipelineId, "err", err)
+		return err
+	}
+	for _, runningRun := range runningRuns {
+		impl.finishRun(runningRun, bean.AnalysisRunStatusAborted, bean.AnalysisActionNone, "superseded by a newer deployment", request.UserId)
+	}
+	run := adapter.NewAnalysisRunDbObject(template, request, time.Now())
+	if err = impl.canaryAnalysisRepository.SaveRun(run); err != nil {
+		impl.logger.Errorw("error in saving analysis run", "run", run, "err", err)
+		return err
+	}
+	impl.saveTimeline(run.CdWorkflowRunnerId, timelineStatus.TIMELINE_STATUS_CANARY_ANALYSIS_STARTED,
+		fmt.Sprintf(timelineStatus.TIMELINE_DESCRIPTION_CANARY_ANALYSIS_STARTED, template.Name), request.UserId)
+	return nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) GetAnalysisRunsByWfrId(wfrId int) ([]*bean.AnalysisRunDto, error) {
+	runs, err := impl.canaryAnalysisRepository.FindRunsByWfrId(wfrId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching analysis runs", "wfrId", wfrId, "err", err)
+		return nil, err
+	}
+	result := make([]*bean.AnalysisRunDto, 0, len(runs))
+	for _, run := range runs {
+		result = append(result, adapter.GetAnalysisRunDto(run))
+	}
+	return result, nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) ProcessDueAnalysisRuns() {
+	runs, err := impl.canaryAnalysisRepository.FindRunsDueForMeasurement(time.Now())
+	if err != nil {
+		impl.logger.Errorw("error in fetching analysis runs due for measurement", "err", err)
+		return
+	}
+	for _, run := range runs {
+		impl.processAnalysisRun(run)
+	}
+}
+
+func (impl *CanaryAnalysisServiceImpl) processAnalysisRun(run *repository.CanaryAnalysisRun) {
+	if run.Template == nil || !run.Template.Active {
+		impl.finishRun(run, bean.AnalysisRunStatusAborted, bean.AnalysisActionNone, "analysis template has been deleted", 1)
+		return
+	}
+	template, err := adapter.GetTemplateDto(run.Template)
+	if err != nil {
+		impl.logger.Errorw("error in reading analysis template", "templateId", run.TemplateId, "err", err)
+		impl.finishRun(run, bean.AnalysisRunStatusError, bean.AnalysisActionNone, "invalid analysis template", 1)
+		return
+	}
+	endpoint, err := impl.getPrometheusEndpoint(run.ClusterId)
+	if err != nil {
+		impl.logger.Errorw("error in getting prometheus endpoint", "clusterId", run.ClusterId, "err", err)
+		impl.finishRun(run, bean.AnalysisRunStatusError, bean.AnalysisActionNone, err.Error(), 1)
+		return
+	}
+	now := time.Now()
+	measurement := takeMeasurement(context.Background(), impl.prometheusQueryClient, endpoint, template.Metrics, now)
+	if measurement.Passed {
+		run.SuccessfulCount++
+	} else {
+		run.FailedCount++
+	}
+	if measurementJson, err := json.Marshal(measurement); err == nil {
+		run.LastMeasurement = string(measurementJson)
+	}
+	runStatus := getRunStatusAfterMeasurement(run.SuccessfulCount, run.FailedCount, template)
+	switch runStatus {
+	case bean.AnalysisRunStatusSucceeded:
+		action := bean.AnalysisActionNone
+		message := fmt.Sprintf("%d of %d measurements passed", run.SuccessfulCount, run.SuccessfulCount+run.FailedCount)
+		if template.AutoPromote {
+			if err = impl.promoteRollout(run); err != nil {
+				impl.logger.Errorw("error in promoting rollout", "runId", run.Id, "rollout", run.RolloutName, "err", err)
+				message = fmt.Sprintf("%s, promotion failed: %s", message, err.Error())
+			} else {
+				action = bean.AnalysisActionPromote
+			}
+		}
+		impl.finishRun(run, runStatus, action, message, 1)
+	case bean.AnalysisRunStatusFailed:
+		action := bean.AnalysisActionNone
+		message := fmt.Sprintf("%d measurements failed, failure limit is %d", run.FailedCount, template.FailureLimit)
+		if template.AutoRollback {
+			if err = impl.abortRollout(run); err != nil {
+				impl.logger.Errorw("error in aborting rollout", "runId", run.Id, "rollout", run.RolloutName, "err", err)
+				message = fmt.Sprintf("%s, rollback failed: %s", message, err.Error())
+			} else {
+				action = bean.AnalysisActionRollback
+			}
+		}
+		impl.finishRun(run, runStatus, action, message, 1)
+	default:
+		run.NextMeasurementAt = now.Add(time.Duration(template.IntervalSeconds) * time.Second)
+		run.UpdateAuditLog(1)
+		if err = impl.canaryAnalysisRepository.UpdateRun(run); err != nil {
+			impl.logger.Errorw("error in updating analysis run", "runId", run.Id, "err", err)
+		}
+	}
+}
+
+func (impl *CanaryAnalysisServiceImpl) getPrometheusEndpoint(clusterId int) (*PrometheusEndpoint, error) {
+	cluster, err := impl.clusterReadService.FindById(clusterId)
+	if err != nil {
+		return nil, err
+	}
+	if len(cluster.PrometheusUrl) == 0 {
+		return nil, fmt.Errorf(bean.PrometheusNotConfigured)
+	}
+	endpoint := &PrometheusEndpoint{Url: cluster.PrometheusUrl}
+	if cluster.PrometheusAuth != nil && !cluster.PrometheusAuth.IsAnonymous {
+		endpoint.UserName = cluster.PrometheusAuth.UserName
+		endpoint.Password = cluster.PrometheusAuth.Password
+	}
+	return endpoint, nil
+}
+
+func (impl *CanaryAnalysisServiceImpl) finishRun(run *repository.CanaryAnalysisRun, runStatus bean.AnalysisRunStatus, action bean.AnalysisAction, message string, userId int32) {
+	finishedOn := time.Now()
+	run.Status = string(runStatus)
+	run.Action = string(action)
+	run.Message = message
+	run.FinishedOn = &finishedOn
+	run.UpdateAuditLog(userId)
+	if err := impl.canaryAnalysisRepository.UpdateRun(run); err != nil {
+		impl.logger.Errorw("error in updating analysis run", "runId", run.Id, "err", err)
+		return
+	}
+	switch runStatus {
+	case bean.AnalysisRunStatusSucceeded:
+		impl.saveTimeline(run.CdWorkflowRunnerId, timelineStatus.TIMELINE_STATUS_CANARY_ANALYSIS_SUCCEEDED, getTimelineDetail(action, message), userId)
+	case bean.AnalysisRunStatusFailed, bean.AnalysisRunStatusError:
+		impl.saveTimeline(run.CdWorkflowRunnerId, timelineStatus.TIMELINE_STATUS_CANARY_ANALYSIS_FAILED, getTimelineDetail(action, message), userId)
+	}
+}
+
+func getTimelineDetail(action bean.AnalysisAction, message string) string {
+	switch action {
+	case bean.AnalysisActionPromote:
+		return fmt.Sprintf("Canary analysis passed, canary promoted: %s.", message)
+	case bean.AnalysisActionRollback:
+		return fmt.Sprintf("Canary analysis failed, canary rolled back: %s.", message)
+	}
+	return fmt.Sprintf("Canary analysis completed: %s.", message)
+}
+
+func (impl *CanaryAnalysisServiceImpl) saveTimeline(wfrId int, status timelineStatus.TimelineStatus, statusDetail string, userId int32) {
+	// saved directly as the analysis usually concludes after the deployment has reached a terminal timeline status
+	timeline := &pipelineConfig.PipelineStatusTimeline{
+		CdWorkflowRunnerId: wfrId,
+		Status:             status,
+		StatusDetail:       statusDetail,
+		StatusTime:         time.Now(),
+	}
+	timeline.CreateAuditLog(userId)
+	if err := impl.pipelineStatusTimelineRepository.SaveTimelines([]*pipelineConfig.PipelineStatusTimeline{timeline}); err != nil {
+		impl.logger.Errorw("error in saving canary analysis timeline", "wfrId", wfrId, "status", status, "err", err)
+	}
+}
+
+func getRolloutGvk() schema.GroupVersionKind {
+	return schema.GroupVersionKind{Group: commonBean.K8sClusterResourceRolloutGroup, Version: "v1alpha1", Kind: commonBean.K8sClusterResourceRolloutKind}
+}
+
+// promoteRollout fully promotes the canary, same as `kubectl argo rollouts promote --full`
+func (impl *CanaryAnalysisServiceImpl) promoteRollout(run *repository.CanaryAnalysisRun) error {
+	return impl.patchRolloutStatus(run, `{"status":{"promoteFull":true}}`)
+}
+
+// abortRollout aborts the canary and scales the stable version back up, same as `kubectl argo rollouts abort`
+func (impl *CanaryAnalysisServiceImpl) abortRollout(run *repository.CanaryAnalysisRun) error {
+	return impl.patchRolloutStatus(run, `{"status":{"abort":true}}`)
+}
+
+func (impl *CanaryAnalysisServiceImpl) patchRolloutStatus(run *repository.CanaryAnalysisRun, patch string) error {
+	ctx := context.Background()
+	restConfig, err, _ := impl.k8sCommonService.GetRestConfigByClusterId(ctx, run.ClusterId)
+	if err != nil {
+		return err
+	}
+	resourceIf, _, err := impl.k8sUtil.GetResourceIf(restConfig, getRolloutGvk())
+	if err != nil {
+		return err
+	}
+	_, err = resourceIf.Namespace(run.Namespace).Patch(ctx, run.RolloutName, types.MergePatchType, []byte(patch), metav1.PatchOptions{FieldManager: "devtron"}, "status")
+	return err
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"encoding/json"
+	"github.com/devtron-labs/devtron/pkg/deployment/canaryAnalysis/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/canaryAnalysis/repository"
+	"time"
+)
+
+func GetTemplateDbObject(template *bean.AnalysisTemplateDto) (*repository.CanaryAnalysisTemplate, error) {
+	metrics, err := json.Marshal(template.Metrics)
+	if err != nil {
+		return nil, err
+	}
+	return &repository.CanaryAnalysisTemplate{
+		Id:                  template.Id,
+		Name:                template.Name,
+		AppId:               template.AppId,
+		EnvId:               template.EnvId,
+		Metrics:             string(metrics),
+		InitialDelaySeconds: template.InitialDelaySeconds,
+		IntervalSeconds:     template.IntervalSeconds,
+		Count:               template.Count,
+		FailureLimit:        template.FailureLimit,
+		AutoPromote:         template.AutoPromote,
+		AutoRollback:        template.AutoRollback,
+		Active:              true,
+	}, nil
+}
+
+func GetTemplateDto(template *repository.CanaryAnalysisTemplate) (*bean.AnalysisTemplateDto, error) {
+	metrics := make([]*bean.MetricDto, 0)
+	if len(template.Metrics) > 0 {
+		if err := json.Unmarshal([]byte(template.Metrics), &metrics); err != nil {
+			return nil, err
+		}
+	}
+	return &bean.AnalysisTemplateDto{
+		Id:                  template.Id,
+		Name:                template.Name,
+		AppId:               template.AppId,
+		EnvId:               template.EnvId,
+		Metrics:             metrics,
+		InitialDelaySeconds: template.InitialDelaySeconds,
+		IntervalSeconds:     template.IntervalSeconds,
+		Count:               template.Count,
+		FailureLimit:        template.FailureLimit,
+		AutoPromote:         template.AutoPromote,
+		AutoRollback:        template.AutoRollback,
+	}, nil
+}
+
+func NewAnalysisRunDbObject(template *repository.CanaryAnalysisTemplate, request *bean.StartAnalysisRequest, now time.Time) *repository.CanaryAnalysisRun {
+	run := &repository.CanaryAnalysisRun{
+		TemplateId:         template.Id,
+		PipelineId:         request.PipelineId,
+		CdWorkflowRunnerId: request.CdWorkflowRunnerId,
+		ClusterId:          request.ClusterId,
+		Namespace:          request.Namespace,
+		RolloutName:        request.RolloutName,
+		Status:             string(bean.AnalysisRunStatusRunning),
+		Action:             string(bean.AnalysisActionNone),
+		NextMeasurementAt:  now.Add(time.Duration(template.InitialDelaySeconds) * time.Second),
+	}
+	run.CreateAuditLog(request.UserId)
+	return run
+}
+
+func GetAnalysisRunDto(run *repository.CanaryAnalysisRun) *bean.AnalysisRunDto {
+	runDto := &bean.AnalysisRunDto{
+		Id:                 run.Id,
+		TemplateId:         run.TemplateId,
+		PipelineId:         run.PipelineId,
+		CdWorkflowRunnerId: run.CdWorkflowRunnerId,
+		Status:             bean.AnalysisRunStatus(run.Status),
+		Action:             bean.AnalysisAction(run.Action),
+		SuccessfulCount:    run.SuccessfulCount,
+		FailedCount:        run.FailedCount,
+		Message:            run.Message,
+		StartedOn:          run.CreatedOn,
+		FinishedOn:         run.FinishedOn,
+	}
+	if run.Template != nil {
+		runDto.TemplateName = run.Template.Name
+	}
+	if len(run.LastMeasurement) > 0 {
+		measurement := &bean.Measurement{}
+		if err := json.Unmarshal([]byte(run.LastMeasurement), measurement); err == nil {
+			runDto.LastMeasurement = measurement
+		}
+	}
+	return runDto
+}
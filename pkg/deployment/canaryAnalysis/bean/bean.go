@@ -0,0 +1,166 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"time"
+)
+
+type ThresholdOperator string
+
+const (
+	OperatorGreaterThan        ThresholdOperator = "GREATER_THAN"
+	OperatorGreaterThanOrEqual ThresholdOperator = "GREATER_THAN_OR_EQUAL"
+	OperatorLessThan           ThresholdOperator = "LESS_THAN"
+	OperatorLessThanOrEqual    ThresholdOperator = "LESS_THAN_OR_EQUAL"
+)
+
+func (operator ThresholdOperator) IsValid() bool {
+	switch operator {
+	case OperatorGreaterThan, OperatorGreaterThanOrEqual, OperatorLessThan, OperatorLessThanOrEqual:
+		return true
+	}
+	return false
+}
+
+// Compare reports whether value satisfies the operator against threshold
+func (operator ThresholdOperator) Compare(value, threshold float64) bool {
+	switch operator {
+	case OperatorGreaterThan:
+		return value > threshold
+	case OperatorGreaterThanOrEqual:
+		return value >= threshold
+	case OperatorLessThan:
+		return value < threshold
+	case OperatorLessThanOrEqual:
+		return value <= threshold
+	}
+	return false
+}
+
+type AnalysisRunStatus string
+
+const (
+	AnalysisRunStatusRunning   AnalysisRunStatus = "RUNNING"
+	AnalysisRunStatusSucceeded AnalysisRunStatus = "SUCCEEDED"
+	AnalysisRunStatusFailed    AnalysisRunStatus = "FAILED"
+	// AnalysisRunStatusError is set when the analysis could not be performed, e.g. prometheus is not configured
+	AnalysisRunStatusError AnalysisRunStatus = "ERROR"
+	// AnalysisRunStatusAborted is set when a newer deployment supersedes the run or the template is deleted
+	AnalysisRunStatusAborted AnalysisRunStatus = "ABORTED"
+)
+
+func (status AnalysisRunStatus) IsTerminal() bool {
+	return status != AnalysisRunStatusRunning
+}
+
+type AnalysisAction string
+
+const (
+	AnalysisActionNone     AnalysisAction = "NONE"
+	AnalysisActionPromote  AnalysisAction = "PROMOTED"
+	AnalysisActionRollback AnalysisAction = "ROLLED_BACK"
+)
+
+const (
+	DefaultIntervalSeconds = 60
+	DefaultCount           = 5
+	MinIntervalSeconds     = 10
+)
+
+const (
+	InvalidTemplateName     = "analysis template name is required"
+	InvalidTemplateScope    = "appId and envId are required for an analysis template"
+	InvalidTemplateMetrics  = "at least one metric is required for an analysis template"
+	InvalidMetric           = "metric name and query are required and operator must be one of GREATER_THAN, GREATER_THAN_OR_EQUAL, LESS_THAN and LESS_THAN_OR_EQUAL"
+	InvalidInterval         = "intervalSeconds must be at least 10"
+	InvalidCount            = "count must be greater than zero and failureLimit must not be negative"
+	PrometheusNotConfigured = "prometheus endpoint is not configured for the cluster of this environment"
+)
+
+type MetricDto struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+	// Operator and Threshold define a successful measurement, e.g. error rate LESS_THAN 0.01
+	Operator  ThresholdOperator `json:"operator"`
+	Threshold float64           `json:"threshold"`
+}
+
+type AnalysisTemplateDto struct {
+	Id      int          `json:"id"`
+	Name    string       `json:"name" validate:"required,max=100"`
+	AppId   int          `json:"appId"`
+	EnvId   int          `json:"envId"`
+	Metrics []*MetricDto `json:"metrics"`
+	// InitialDelaySeconds is waited after the deployment is triggered before the first measurement
+	InitialDelaySeconds int `json:"initialDelaySeconds"`
+	IntervalSeconds     int `json:"intervalSeconds"`
+	// Count is the number of successful measurements after which the canary is promoted
+	Count int `json:"count"`
+	// FailureLimit is the number of failed measurements tolerated before the canary is rolled back
+	FailureLimit int   `json:"failureLimit"`
+	AutoPromote  bool  `json:"autoPromote"`
+	AutoRollback bool  `json:"autoRollback"`
+	UserId       int32 `json:"-"`
+}
+
+type MetricResult struct {
+	Name   string  `json:"name"`
+	Query  string  `json:"query"`
+	Value  float64 `json:"value"`
+	Passed bool    `json:"passed"`
+	Error  string  `json:"error,omitempty"`
+}
+
+type Measurement struct {
+	Passed     bool            `json:"passed"`
+	Results    []*MetricResult `json:"results"`
+	MeasuredAt time.Time       `json:"measuredAt"`
+}
+
+type AnalysisRunDto struct {
+	Id                 int               `json:"id"`
+	TemplateId         int               `json:"templateId"`
+	TemplateName       string            `json:"templateName"`
+	PipelineId         int               `json:"pipelineId"`
+	CdWorkflowRunnerId int               `json:"cdWorkflowRunnerId"`
+	Status             AnalysisRunStatus `json:"status"`
+	Action             AnalysisAction    `json:"action"`
+	SuccessfulCount    int               `json:"successfulCount"`
+	FailedCount        int               `json:"failedCount"`
+	Message            string            `json:"message,omitempty"`
+	LastMeasurement    *Measurement      `json:"lastMeasurement,omitempty"`
+	StartedOn          time.Time         `json:"startedOn"`
+	FinishedOn         *time.Time        `json:"finishedOn,omitempty"`
+}
+
+// StartAnalysisRequest carries the deployment a canary analysis is started for
+type StartAnalysisRequest struct {
+	AppId              int
+	EnvId              int
+	ClusterId          int
+	PipelineId         int
+	CdWorkflowRunnerId int
+	RolloutName        string
+	Namespace          string
+	UserId             int32
+}
+
+type CanaryAnalysisConfig struct {
+	CronIntervalSecs           int `env:"CANARY_ANALYSIS_CRON_INTERVAL_SECS" envDefault:"30"`
+	PrometheusQueryTimeoutSecs int `env:"CANARY_ANALYSIS_PROMETHEUS_QUERY_TIMEOUT_SECS" envDefault:"10"`
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canaryAnalysis
+
+import (
+	"context"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/deployment/canaryAnalysis/bean"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func newValidationError(message string) *util.ApiError {
+	return util.NewApiError(http.StatusBadRequest, message, message)
+}
+
+// ValidateAnalysisTemplate validates the request and fills defaults for optional fields
+func ValidateAnalysisTemplate(template *bean.AnalysisTemplateDto) error {
+	if len(strings.TrimSpace(template.Name)) == 0 {
+		return newValidationError(bean.InvalidTemplateName)
+	}
+	if template.AppId <= 0 || template.EnvId <= 0 {
+		return newValidationError(bean.InvalidTemplateScope)
+	}
+	if len(template.Metrics) == 0 {
+		return newValidationError(bean.InvalidTemplateMetrics)
+	}
+	for _, metric := range template.Metrics {
+		if metric == nil || len(strings.TrimSpace(metric.Name)) == 0 || len(strings.TrimSpace(metric.Query)) == 0 || !metric.Operator.IsValid() {
+			return newValidationError(bean.InvalidMetric)
+		}
+	}
+	if template.IntervalSeconds == 0 {
+		template.IntervalSeconds = bean.DefaultIntervalSeconds
+	}
+	if template.Count == 0 {
+		template.Count = bean.DefaultCount
+	}
+	if template.IntervalSeconds < bean.MinIntervalSeconds {
+		return newValidationError(bean.InvalidInterval)
+	}
+	if template.Count < 0 || template.FailureLimit < 0 || template.InitialDelaySeconds < 0 {
+		return newValidationError(bean.InvalidCount)
+	}
+	return nil
+}
+
+// takeMeasurement evaluates every metric of the template, a measurement passes only if all metrics pass
+func takeMeasurement(ctx context.Context, client PrometheusQueryClient, endpoint *PrometheusEndpoint, metrics []*bean.MetricDto, now time.Time) *bean.Measurement {
+	measurement := &bean.Measurement{
+		Passed:     true,
+		Results:    make([]*bean.MetricResult, 0, len(metrics)),
+		MeasuredAt: now,
+	}
+	for _, metric := range metrics {
+		result := &bean.MetricResult{
+			Name:  metric.Name,
+			Query: metric.Query,
+		}
+		value, err := client.QueryValue(ctx, endpoint, metric.Query, now)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Value = value
+			result.Passed = metric.Operator.Compare(value, metric.Threshold)
+		}
+		if !result.Passed {
+			measurement.Passed = false
+		}
+		measurement.Results = append(measurement.Results, result)
+	}
+	return measurement
+}
+
+// getRunStatusAfterMeasurement decides whether the run is finished once a measurement has been counted
+func getRunStatusAfterMeasurement(successfulCount, failedCount int, template *bean.AnalysisTemplateDto) bean.AnalysisRunStatus {
+	if failedCount > template.FailureLimit {
+		return bean.AnalysisRunStatusFailed
+	}
+	if successfulCount >= template.Count {
+		return bean.AnalysisRunStatusSucceeded
+	}
+	return bean.AnalysisRunStatusRunning
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canaryAnalysis
+
+import (
+	"context"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/deployment/canaryAnalysis/bean"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newPrometheusStub serves instant query responses keyed by the query string
+func newPrometheusStub(t *testing.T, responses map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("error in parsing prometheus request: %v", err)
+		}
+		response, ok := responses[r.Form.Get("query")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"status":"error","errorType":"bad_data","error":"unknown query"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, response)
+	}))
+}
+
+func TestTakeMeasurement(t *testing.T) {
+	server := newPrometheusStub(t, map[string]string{
+		"error_rate":   `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1710417600,"0.02"]}]}}`,
+		"latency_p99":  `{"status":"success","data":{"resultType":"scalar","result":[1710417600,"450"]}}`,
+		"multi_series": `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"pod":"a"},"value":[1710417600,"1"]},{"metric":{"pod":"b"},"value":[1710417600,"2"]}]}}`,
+	})
+	defer server.Close()
+	client := NewPrometheusQueryClientImpl(5 * time.Second)
+	endpoint := &PrometheusEndpoint{Url: server.URL}
+	now := time.Date(2024, time.March, 14, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		metrics     []*bean.MetricDto
+		wantPassed  bool
+		wantResults []bool
+	}{
+		{
+			name: "Test1_AllMetricsWithinThreshold",
+			metrics: []*bean.MetricDto{
+				{Name: "errors", Query: "error_rate", Operator: bean.OperatorLessThan, Threshold: 0.05},
+				{Name: "latency", Query: "latency_p99", Operator: bean.OperatorLessThanOrEqual, Threshold: 500},
+			},
+			wantPassed:  true,
+			wantResults: []bool{true, true},
+		},
+		{
+			name: "Test2_OneMetricBreachesThreshold",
+			metrics: []*bean.MetricDto{
+				{Name: "errors", Query: "error_rate", Operator: bean.OperatorLessThan, Threshold: 0.01},
+				{Name: "latency", Query: "latency_p99", Operator: bean.OperatorLessThan, Threshold: 500},
+			},
+			wantPassed:  false,
+			wantResults: []bool{false, true},
+		},
+		{
+			name: "Test3_QueryReturningMultipleSeriesFails",
+			metrics: []*bean.MetricDto{
+				{Name: "multi", Query: "multi_series", Operator: bean.OperatorGreaterThan, Threshold: 0},
+			},
+			wantPassed:  false,
+			wantResults: []bool{false},
+		},
+		{
+			name: "Test4_QueryErrorFails",
+			metrics: []*bean.MetricDto{
+				{Name: "unknown", Query: "unknown_query", Operator: bean.OperatorGreaterThan, Threshold: 0},
+			},
+			wantPassed:  false,
+			wantResults: []bool{false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			measurement := takeMeasurement(context.Background(), client, endpoint, tt.metrics, now)
+			if measurement.Passed != tt.wantPassed {
+				t.Errorf("takeMeasurement() passed = %v, want %v, results %+v", measurement.Passed, tt.wantPassed, measurement.Results)
+			}
+			if len(measurement.Results) != len(tt.wantResults) {
+				t.Fatalf("takeMeasurement() got %d results, want %d", len(measurement.Results), len(tt.wantResults))
+			}
+			for i, result := range measurement.Results {
+				if result.Passed != tt.wantResults[i] {
+					t.Errorf("takeMeasurement() result %s passed = %v, want %v, err %s", result.Name, result.Passed, tt.wantResults[i], result.Error)
+				}
+			}
+		})
+	}
+}
+
+func TestGetRunStatusAfterMeasurement(t *testing.T) {
+	template := &bean.AnalysisTemplateDto{Count: 3, FailureLimit: 1}
+	tests := []struct {
+		name            string
+		successfulCount int
+		failedCount     int
+		want            bean.AnalysisRunStatus
+	}{
+		{name: "Test1_StillRunning", successfulCount: 1, failedCount: 1, want: bean.AnalysisRunStatusRunning},
+		{name: "Test2_EnoughSuccessfulMeasurements", successfulCount: 3, failedCount: 1, want: bean.AnalysisRunStatusSucceeded},
+		{name: "Test3_FailureLimitExceeded", successfulCount: 2, failedCount: 2, want: bean.AnalysisRunStatusFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getRunStatusAfterMeasurement(tt.successfulCount, tt.failedCount, template); got != tt.want {
+				t.Errorf("getRunStatusAfterMeasurement() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateAnalysisTemplate(t *testing.T) {
+	validMetrics := []*bean.MetricDto{{Name: "errors", Query: "error_rate", Operator: bean.OperatorLessThan, Threshold: 0.05}}
+	tests := []struct {
+		name     string
+		template *bean.AnalysisTemplateDto
+		wantErr  bool
+	}{
+		{name: "Test1_ValidTemplateWithDefaults", template: &bean.AnalysisTemplateDto{Name: "canary", AppId: 1, EnvId: 2, Metrics: validMetrics}},
+		{name: "Test2_MissingMetrics", template: &bean.AnalysisTemplateDto{Name: "canary", AppId: 1, EnvId: 2}, wantErr: true},
+		{name: "Test3_InvalidOperator", template: &bean.AnalysisTemplateDto{Name: "canary", AppId: 1, EnvId: 2, Metrics: []*bean.MetricDto{{Name: "errors", Query: "error_rate", Operator: "EQUALS"}}}, wantErr: true},
+		{name: "Test4_IntervalTooShort", template: &bean.AnalysisTemplateDto{Name: "canary", AppId: 1, EnvId: 2, Metrics: validMetrics, IntervalSeconds: 5}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAnalysisTemplate(tt.template)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateAnalysisTemplate() err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (tt.template.IntervalSeconds != bean.DefaultIntervalSeconds || tt.template.Count != bean.DefaultCount) {
+				t.Errorf("ValidateAnalysisTemplate() did not fill defaults, got interval %d count %d", tt.template.IntervalSeconds, tt.template.Count)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package canaryAnalysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"math"
+	"net/http"
+	"time"
+)
+
+// PrometheusQueryClient runs instant queries against a prometheus compatible endpoint and reduces the result to a single value
+type PrometheusQueryClient interface {
+	QueryValue(ctx context.Context, endpoint *PrometheusEndpoint, query string, ts time.Time) (float64, error)
+}
+
+type PrometheusEndpoint struct {
+	Url      string
+	UserName string
+	Password string
+}
+
+type PrometheusQueryClientImpl struct {
+	timeout time.Duration
+}
+
+func NewPrometheusQueryClientImpl(timeout time.Duration) *PrometheusQueryClientImpl {
+	return &PrometheusQueryClientImpl{
+		timeout: timeout,
+	}
+}
+
+type basicAuthRoundTripper struct {
+	userName string
+	password string
+	next     http.RoundTripper
+}
+
+func (rt *basicAuthRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.SetBasicAuth(rt.userName, rt.password)
+	return rt.next.RoundTrip(req)
+}
+
+func (impl *PrometheusQueryClientImpl) QueryValue(ctx context.Context, endpoint *PrometheusEndpoint, query string, ts time.Time) (float64, error) {
+	config := api.Config{Address: endpoint.Url}
+	if len(endpoint.UserName) > 0 {
+		config.RoundTripper = &basicAuthRoundTripper{userName: endpoint.UserName, password: endpoint.Password, next: api.DefaultRoundTripper}
+	}
+	client, err := api.NewClient(config)
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, impl.timeout)
+	defer cancel()
+	value, _, err := promv1.NewAPI(client).Query(ctx, query, ts, promv1.WithTimeout(impl.timeout))
+	if err != nil {
+		return 0, err
+	}
+	return reduceToScalar(value)
+}
+
+// reduceToScalar accepts a scalar or a single element vector, anything else is ambiguous for a threshold check
+func reduceToScalar(value model.Value) (float64, error) {
+	var result float64
+	switch v := value.(type) {
+	case *model.Scalar:
+		result = float64(v.Value)
+	case model.Vector:
+		if len(v) != 1 {
+			return 0, fmt.Errorf("query returned %d series, expected exactly one", len(v))
+		}
+		result = float64(v[0].Value)
+	default:
+		return 0, fmt.Errorf("unsupported query result type %s", value.Type())
+	}
+	if math.IsNaN(result) || math.IsInf(result, 0) {
+		return 0, errors.New("query returned a non numeric value")
+	}
+	return result, nil
+}
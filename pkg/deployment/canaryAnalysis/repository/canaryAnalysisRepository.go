@@ -0,0 +1,176 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type CanaryAnalysisTemplate struct {
+	tableName           struct{} `sql:"canary_analysis_template" pg:",discard_unknown_columns"`
+	Id                  int      `sql:"id,pk"`
+	Name                string   `sql:"name,notnull"`
+	AppId               int      `sql:"app_id,notnull"`
+	EnvId               int      `sql:"env_id,notnull"`
+	Metrics             string   `sql:"metrics"`
+	InitialDelaySeconds int      `sql:"initial_delay_seconds"`
+	IntervalSeconds     int      `sql:"interval_seconds,notnull"`
+	Count               int      `sql:"count,notnull"`
+	FailureLimit        int      `sql:"failure_limit,notnull"`
+	AutoPromote         bool     `sql:"auto_promote,notnull"`
+	AutoRollback        bool     `sql:"auto_rollback,notnull"`
+	Active              bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type CanaryAnalysisRun struct {
+	tableName          struct{}   `sql:"canary_analysis_run" pg:",discard_unknown_columns"`
+	Id                 int        `sql:"id,pk"`
+	TemplateId         int        `sql:"template_id,notnull"`
+	PipelineId         int        `sql:"pipeline_id,notnull"`
+	CdWorkflowRunnerId int        `sql:"cd_workflow_runner_id,notnull"`
+	ClusterId          int        `sql:"cluster_id"`
+	Namespace          string     `sql:"namespace"`
+	RolloutName        string     `sql:"rollout_name"`
+	Status             string     `sql:"status,notnull"`
+	Action             string     `sql:"action"`
+	SuccessfulCount    int        `sql:"successful_count,notnull"`
+	FailedCount        int        `sql:"failed_count,notnull"`
+	LastMeasurement    string     `sql:"last_measurement"`
+	Message            string     `sql:"message"`
+	NextMeasurementAt  time.Time  `sql:"next_measurement_at"`
+	FinishedOn         *time.Time `sql:"finished_on"`
+	Template           *CanaryAnalysisTemplate
+	sql.AuditLog
+}
+
+type CanaryAnalysisRepository interface {
+	SaveTemplate(template *CanaryAnalysisTemplate) error
+	UpdateTemplate(template *CanaryAnalysisTemplate) error
+	FindTemplateById(id int) (*CanaryAnalysisTemplate, error)
+	FindTemplatesByAppId(appId int) ([]*CanaryAnalysisTemplate, error)
+	FindActiveTemplateByAppIdAndEnvId(appId, envId int) (*CanaryAnalysisTemplate, error)
+
+	SaveRun(run *CanaryAnalysisRun) error
+	UpdateRun(run *CanaryAnalysisRun) error
+	FindRunningRunsByPipelineId(pipelineId int) ([]*CanaryAnalysisRun, error)
+	FindRunsDueForMeasurement(now time.Time) ([]*CanaryAnalysisRun, error)
+	FindRunsByWfrId(wfrId int) ([]*CanaryAnalysisRun, error)
+}
+
+type CanaryAnalysisRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewCanaryAnalysisRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *CanaryAnalysisRepositoryImpl {
+	return &CanaryAnalysisRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (repo *CanaryAnalysisRepositoryImpl) SaveTemplate(template *CanaryAnalysisTemplate) error {
+	return repo.dbConnection.Insert(template)
+}
+
+func (repo *CanaryAnalysisRepositoryImpl) UpdateTemplate(template *CanaryAnalysisTemplate) error {
+	return repo.dbConnection.Update(template)
+}
+
+func (repo *CanaryAnalysisRepositoryImpl) FindTemplateById(id int) (*CanaryAnalysisTemplate, error) {
+	template := &CanaryAnalysisTemplate{}
+	err := repo.dbConnection.Model(template).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return template, err
+}
+
+func (repo *CanaryAnalysisRepositoryImpl) FindTemplatesByAppId(appId int) ([]*CanaryAnalysisTemplate, error) {
+	var templates []*CanaryAnalysisTemplate
+	err := repo.dbConnection.Model(&templates).
+		Where("app_id = ?", appId).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	if err == pg.ErrNoRows {
+		err = nil
+	}
+	return templates, err
+}
+
+func (repo *CanaryAnalysisRepositoryImpl) FindActiveTemplateByAppIdAndEnvId(appId, envId int) (*CanaryAnalysisTemplate, error) {
+	template := &CanaryAnalysisTemplate{}
+	err := repo.dbConnection.Model(template).
+		Where("app_id = ?", appId).
+		Where("env_id = ?", envId).
+		Where("active = ?", true).
+		Limit(1).
+		Select()
+	return template, err
+}
+
+func (repo *CanaryAnalysisRepositoryImpl) SaveRun(run *CanaryAnalysisRun) error {
+	return repo.dbConnection.Insert(run)
+}
+
+func (repo *CanaryAnalysisRepositoryImpl) UpdateRun(run *CanaryAnalysisRun) error {
+	return repo.dbConnection.Update(run)
+}
+
+func (repo *CanaryAnalysisRepositoryImpl) FindRunningRunsByPipelineId(pipelineId int) ([]*CanaryAnalysisRun, error) {
+	var runs []*CanaryAnalysisRun
+	err := repo.dbConnection.Model(&runs).
+		Where("pipeline_id = ?", pipelineId).
+		Where("status = ?", "RUNNING").
+		Select()
+	if err == pg.ErrNoRows {
+		err = nil
+	}
+	return runs, err
+}
+
+func (repo *CanaryAnalysisRepositoryImpl) FindRunsDueForMeasurement(now time.Time) ([]*CanaryAnalysisRun, error) {
+	var runs []*CanaryAnalysisRun
+	err := repo.dbConnection.Model(&runs).
+		Column("canary_analysis_run.*", "Template").
+		Where("canary_analysis_run.status = ?", "RUNNING").
+		Where("canary_analysis_run.next_measurement_at <= ?", now).
+		Order("canary_analysis_run.id ASC").
+		Select()
+	if err == pg.ErrNoRows {
+		err = nil
+	}
+	return runs, err
+}
+
+func (repo *CanaryAnalysisRepositoryImpl) FindRunsByWfrId(wfrId int) ([]*CanaryAnalysisRun, error) {
+	var runs []*CanaryAnalysisRun
+	err := repo.dbConnection.Model(&runs).
+		Column("canary_analysis_run.*", "Template").
+		Where("canary_analysis_run.cd_workflow_runner_id = ?", wfrId).
+		Order("canary_analysis_run.id DESC").
+		Select()
+	if err == pg.ErrNoRows {
+		err = nil
+	}
+	return runs, err
+}
@@ -0,0 +1,561 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helmDrift
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/caarlos0/env/v6"
+	k8s2 "github.com/devtron-labs/common-lib/utils/k8s"
+	apiBean "github.com/devtron-labs/devtron/api/bean"
+	"github.com/devtron-labs/devtron/api/helm-app/service"
+	helmBean "github.com/devtron-labs/devtron/api/helm-app/service/bean"
+	client "github.com/devtron-labs/devtron/client/events"
+	"github.com/devtron-labs/devtron/internal/sql/repository/chartConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
+	"github.com/devtron-labs/devtron/internal/util"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/common"
+	"github.com/devtron-labs/devtron/pkg/deployment/helmDrift/bean"
+	"github.com/devtron-labs/devtron/pkg/deployment/helmDrift/repository"
+	"github.com/devtron-labs/devtron/pkg/k8s"
+	bean5 "github.com/devtron-labs/devtron/pkg/k8s/bean"
+	util2 "github.com/devtron-labs/devtron/util"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	eventUtil "github.com/devtron-labs/devtron/util/event"
+	"github.com/go-pg/pg"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"net/http"
+	"sort"
+	"time"
+)
+
+type HelmDriftService interface {
+	// GetDriftReport returns the result of the last drift check of the helm pipeline of an app and environment
+	GetDriftReport(appId, envId int) (*bean.DriftReportDto, error)
+	// GetDriftSummary returns the drift last recorded for a helm pipeline, nil when it was never checked
+	GetDriftSummary(pipelineId int) (*bean.DriftSummaryDto, error)
+	// CheckDrift compares the live release with the last deployment from Devtron right away and stores the result
+	CheckDrift(ctx context.Context, request *bean.DriftActionRequest) (*bean.DriftReportDto, error)
+	// ReapplyRelease rolls the release back to the helm revision of the last deployment from Devtron and returns the
+	// drift checked after it
+	ReapplyRelease(ctx context.Context, request *bean.DriftActionRequest) (*bean.DriftReportDto, error)
+	UpdateAutoRemediate(request *bean.AutoRemediateRequest) (*bean.DriftReportDto, error)
+	// DetectDriftForAllPipelines checks every helm pipeline, notifies on new drift and re-applies where auto remediation is enabled
+	DetectDriftForAllPipelines()
+}
+
+type HelmDriftServiceImpl struct {
+	logger                     *zap.SugaredLogger
+	helmReleaseDriftRepository repository.HelmReleaseDriftRepository
+	pipelineRepository         pipelineConfig.PipelineRepository
+	cdWorkflowRepository       pipelineConfig.CdWorkflowRepository
+	pipelineOverrideRepository chartConfig.PipelineOverrideRepository
+	deploymentConfigService    common.DeploymentConfigService
+	helmAppService             service.HelmAppService
+	k8sCommonService           k8s.K8sCommonService
+	eventClient                client.EventClient
+	eventFactory               client.EventFactory
+	config                     *bean.HelmDriftConfig
+	driftCron                  *cron.Cron
+}
+
+func GetHelmDriftConfig() (*bean.HelmDriftConfig, error) {
+	config := &bean.HelmDriftConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		return nil, err
+	}
+	return config, err
+}
+
+func NewHelmDriftServiceImpl(logger *zap.SugaredLogger,
+	helmReleaseDriftRepository repository.HelmReleaseDriftRepository,
+	pipelineRepository pipelineConfig.PipelineRepository,
+	cdWorkflowRepository pipelineConfig.CdWorkflowRepository,
+	pipelineOverrideRepository chartConfig.PipelineOverrideRepository,
+	deploymentConfigService common.DeploymentConfigService,
+	helmAppService service.HelmAppService,
+	k8sCommonService k8s.K8sCommonService,
+	eventClient client.EventClient,
+	eventFactory client.EventFactory,
+	cronLogger *cron2.CronLoggerImpl) (*HelmDriftServiceImpl, error) {
+	config, err := GetHelmDriftConfig()
+	if err != nil {
+		logger.Errorw("error in parsing helm drift config", "err", err)
+		return nil, err
+	}
+	driftCron := cron.New(cron.WithChain(cron.SkipIfStillRunning(cronLogger), cron.Recover(cronLogger)))
+	impl := &HelmDriftServiceImpl{
+		logger:                     logger,
+		helmReleaseDriftRepository: helmReleaseDriftRepository,
+		pipelineRepository:         pipelineRepository,
+		cdWorkflowRepository:       cdWorkflowRepository,
+		pipelineOverrideRepository: pipelineOverrideRepository,
+		deploymentConfigService:    deploymentConfigService,
+		helmAppService:             helmAppService,
+		k8sCommonService:           k8sCommonService,
+		eventClient:                eventClient,
+		eventFactory:               eventFactory,
+		config:                     config,
+		driftCron:                  driftCron,
+	}
+	if config.DetectionEnabled {
+		driftCron.Start()
+		_, err = driftCron.AddFunc(fmt.Sprintf("@every %ds", config.CronIntervalSecs), impl.DetectDriftForAllPipelines)
+		if err != nil {
+			logger.Errorw("error in starting helm drift detection cron", "err", err)
+			return nil, err
+		}
+	}
+	return impl, nil
+}
+
+func (impl *HelmDriftServiceImpl) GetDriftReport(appId, envId int) (*bean.DriftReportDto, error) {
+	pipeline, err := impl.getHelmPipeline(appId, envId)
+	if err != nil {
+		return nil, err
+	}
+	drift, err := impl.helmReleaseDriftRepository.FindByPipelineId(pipeline.Id)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching helm release drift", "pipelineId", pipeline.Id, "err", err)
+		return nil, err
+	}
+	if err == pg.ErrNoRows {
+		return &bean.DriftReportDto{
+			AppId:      appId,
+			EnvId:      envId,
+			PipelineId: pipeline.Id,
+			Status:     bean.DriftStatusUnknown,
+			Resources:  make([]*bean.ResourceDrift, 0),
+		}, nil
+	}
+	return impl.getDriftReportDto(pipeline, drift)
+}
+
+func (impl *HelmDriftServiceImpl) GetDriftSummary(pipelineId int) (*bean.DriftSummaryDto, error) {
+	drift, err := impl.helmReleaseDriftRepository.FindByPipelineId(pipelineId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching helm release drift", "pipelineId", pipelineId, "err", err)
+		return nil, err
+	}
+	if err == pg.ErrNoRows {
+		return nil, nil
+	}
+	resources := make([]*bean.ResourceDrift, 0)
+	if len(drift.DriftedResources) > 0 {
+		if err = json.Unmarshal([]byte(drift.DriftedResources), &resources); err != nil {
+			impl.logger.Errorw("error in parsing drifted resources", "pipelineId", pipelineId, "err", err)
+			return nil, err
+		}
+	}
+	for _, resource := range resources {
+		resource.Fields = nil
+	}
+	return &bean.DriftSummaryDto{
+		Status:          bean.DriftStatus(drift.Status),
+		DesiredRevision: drift.DesiredRevision,
+		LiveRevision:    drift.LiveRevision,
+		Message:         drift.Message,
+		Resources:       resources,
+		AutoRemediate:   drift.AutoRemediate,
+		LastCheckedOn:   drift.LastCheckedOn,
+	}, nil
+}
+
+func (impl *HelmDriftServiceImpl) CheckDrift(ctx context.Context, request *bean.DriftActionRequest) (*bean.DriftReportDto, error) {
+	pipeline, err := impl.getHelmPipeline(request.AppId, request.EnvId)
+	if err != nil {
+		return nil, err
+	}
+	drift, _, err := impl.checkAndSaveDrift(ctx, pipeline, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return impl.getDriftReportDto(pipeline, drift)
+}
+
+func (impl *HelmDriftServiceImpl) ReapplyRelease(ctx context.Context, request *bean.DriftActionRequest) (*bean.DriftReportDto, error) {
+	pipeline, err := impl.getHelmPipeline(request.AppId, request.EnvId)
+	if err != nil {
+		return nil, err
+	}
+	drift, _, err := impl.checkAndSaveDrift(ctx, pipeline, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if drift.Status != string(bean.DriftStatusDrifted) {
+		return impl.getDriftReportDto(pipeline, drift)
+	}
+	if drift.DesiredRevision == 0 {
+		return nil, util.NewApiError(http.StatusPreconditionFailed, "no helm revision matches the last deployment from Devtron, redeploy the pipeline to restore the release", "no matching helm revision found")
+	}
+	if err = impl.reapply(ctx, pipeline, drift, request.UserId); err != nil {
+		return nil, err
+	}
+	drift, _, err = impl.checkAndSaveDrift(ctx, pipeline, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return impl.getDriftReportDto(pipeline, drift)
+}
+
+func (impl *HelmDriftServiceImpl) UpdateAutoRemediate(request *bean.AutoRemediateRequest) (*bean.DriftReportDto, error) {
+	pipeline, err := impl.getHelmPipeline(request.AppId, request.EnvId)
+	if err != nil {
+		return nil, err
+	}
+	drift, err := impl.helmReleaseDriftRepository.FindByPipelineId(pipeline.Id)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching helm release drift", "pipelineId", pipeline.Id, "err", err)
+		return nil, err
+	}
+	if err == pg.ErrNoRows {
+		drift = &repository.HelmReleaseDrift{
+			PipelineId: pipeline.Id,
+			Status:     string(bean.DriftStatusUnknown),
+		}
+		drift.CreateAuditLog(request.UserId)
+		drift.AutoRemediate = request.AutoRemediate
+		err = impl.helmReleaseDriftRepository.Save(drift)
+	} else {
+		drift.AutoRemediate = request.AutoRemediate
+		drift.UpdateAuditLog(request.UserId)
+		err = impl.helmReleaseDriftRepository.Update(drift)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving helm release drift", "pipelineId", pipeline.Id, "err", err)
+		return nil, err
+	}
+	return impl.getDriftReportDto(pipeline, drift)
+}
+
+func (impl *HelmDriftServiceImpl) DetectDriftForAllPipelines() {
+	pipelines, err := impl.pipelineRepository.FindActiveByDeploymentAppType(util.PIPELINE_DEPLOYMENT_TYPE_HELM)
+	if err != nil {
+		impl.logger.Errorw("error in fetching helm pipelines for drift detection", "err", err)
+		return
+	}
+	for _, pipeline := range pipelines {
+		ctx := context.Background()
+		drift, isNewDrift, err := impl.checkAndSaveDrift(ctx, pipeline, userBean.SystemUserId)
+		if err != nil {
+			impl.logger.Errorw("error in checking drift of helm release", "pipelineId", pipeline.Id, "err", err)
+			continue
+		}
+		if isNewDrift {
+			impl.sendDriftNotification(pipeline, drift)
+		}
+		if drift.Status == string(bean.DriftStatusDrifted) && drift.AutoRemediate && drift.DesiredRevision > 0 {
+			if err = impl.reapply(ctx, pipeline, drift, userBean.SystemUserId); err != nil {
+				impl.logger.Errorw("error in auto remediating drift of helm release", "pipelineId", pipeline.Id, "err", err)
+			}
+		}
+	}
+}
+
+func (impl *HelmDriftServiceImpl) getHelmPipeline(appId, envId int) (*pipelineConfig.Pipeline, error) {
+	pipelines, err := impl.pipelineRepository.FindActiveByAppIdAndEnvironmentId(appId, envId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching pipeline", "appId", appId, "envId", envId, "err", err)
+		return nil, err
+	}
+	if len(pipelines) == 0 {
+		return nil, util.NewApiError(http.StatusNotFound, "no deployment pipeline found for this app and environment", "pipeline not found")
+	}
+	deploymentConfig, err := impl.deploymentConfigService.GetAndMigrateConfigIfAbsentForDevtronApps(appId, envId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment config", "appId", appId, "envId", envId, "err", err)
+		return nil, err
+	}
+	if !util.IsHelmApp(deploymentConfig.DeploymentAppType) {
+		return nil, util.NewApiError(http.StatusBadRequest, bean.NotAHelmPipeline, bean.NotAHelmPipeline)
+	}
+	return pipelines[0], nil
+}
+
+// checkAndSaveDrift detects drift and persists it, the returned flag is set when the drift differs from the last notified one
+func (impl *HelmDriftServiceImpl) checkAndSaveDrift(ctx context.Context, pipeline *pipelineConfig.Pipeline, userId int32) (*repository.HelmReleaseDrift, bool, error) {
+	report, err := impl.detectDrift(ctx, pipeline)
+	if err != nil {
+		return nil, false, err
+	}
+	resources, err := json.Marshal(report.Resources)
+	if err != nil {
+		return nil, false, err
+	}
+	driftHash := ""
+	if report.Status == bean.DriftStatusDrifted {
+		driftHash, err = GetDriftHash(report.DesiredRevision, report.LiveRevision, report.Resources)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	drift, err := impl.helmReleaseDriftRepository.FindByPipelineId(pipeline.Id)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching helm release drift", "pipelineId", pipeline.Id, "err", err)
+		return nil, false, err
+	}
+	isNew := err == pg.ErrNoRows
+	if isNew {
+		drift = &repository.HelmReleaseDrift{PipelineId: pipeline.Id}
+		drift.CreateAuditLog(userId)
+	}
+	isNewDrift := report.Status == bean.DriftStatusDrifted && drift.DriftHash != driftHash
+	now := time.Now()
+	drift.Status = string(report.Status)
+	drift.DesiredRevision = report.DesiredRevision
+	drift.LiveRevision = report.LiveRevision
+	drift.DriftedResources = string(resources)
+	drift.DriftHash = driftHash
+	drift.Message = report.Message
+	drift.LastCheckedOn = &now
+	drift.UpdateAuditLog(userId)
+	if isNew {
+		err = impl.helmReleaseDriftRepository.Save(drift)
+	} else {
+		err = impl.helmReleaseDriftRepository.Update(drift)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving helm release drift", "pipelineId", pipeline.Id, "err", err)
+		return nil, false, err
+	}
+	return drift, isNewDrift, nil
+}
+
+// detectDrift finds the helm revision rendered by the last successful Devtron deployment and compares its manifest
+// with live objects. Values of the revision are matched with the merged values sent to helm for that deployment.
+func (impl *HelmDriftServiceImpl) detectDrift(ctx context.Context, pipeline *pipelineConfig.Pipeline) (*bean.DriftReportDto, error) {
+	report := &bean.DriftReportDto{
+		AppId:      pipeline.AppId,
+		EnvId:      pipeline.EnvironmentId,
+		PipelineId: pipeline.Id,
+		Status:     bean.DriftStatusUnknown,
+		Resources:  make([]*bean.ResourceDrift, 0),
+	}
+	wfr, err := impl.cdWorkflowRepository.FindLatestByPipelineIdAndRunnerType(pipeline.Id, apiBean.CD_WORKFLOW_TYPE_DEPLOY)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching latest deployment", "pipelineId", pipeline.Id, "err", err)
+		return nil, err
+	}
+	if util.IsErrNoRows(err) || wfr.Id == 0 {
+		report.Message = bean.NoDeploymentFound
+		return report, nil
+	}
+	if wfr.Status != cdWorkflow.WorkflowSucceeded && wfr.Status != string(health.HealthStatusHealthy) {
+		report.Message = fmt.Sprintf(bean.DeploymentNotSucceeded, wfr.Status)
+		return report, nil
+	}
+	override, err := impl.pipelineOverrideRepository.FindLatestByCdWorkflowId(wfr.CdWorkflowId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching pipeline override of deployment", "cdWorkflowId", wfr.CdWorkflowId, "err", err)
+		return nil, err
+	}
+	appIdentifier := getAppIdentifier(pipeline)
+	history, err := impl.helmAppService.GetDeploymentHistory(ctx, appIdentifier)
+	if err != nil {
+		impl.logger.Errorw("error in fetching helm release history", "appIdentifier", appIdentifier, "err", err)
+		return nil, err
+	}
+	revisions := make([]int32, 0, len(history.GetDeploymentHistory()))
+	for _, deployment := range history.GetDeploymentHistory() {
+		revisions = append(revisions, deployment.GetVersion())
+	}
+	if len(revisions) == 0 {
+		report.Message = bean.NoDeploymentFound
+		return report, nil
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i] > revisions[j] })
+	report.LiveRevision = int(revisions[0])
+
+	desiredManifest := ""
+	for i, revision := range revisions {
+		if i >= impl.config.MaxRevisionsToMatch {
+			break
+		}
+		detail, err := impl.helmAppService.GetDeploymentDetail(ctx, appIdentifier, revision)
+		if err != nil {
+			impl.logger.Errorw("error in fetching helm release revision", "appIdentifier", appIdentifier, "revision", revision, "err", err)
+			return nil, err
+		}
+		if detail.ValuesYaml == nil {
+			continue
+		}
+		isEqual, err := IsValuesEqual(*detail.ValuesYaml, override.PipelineMergedValues)
+		if err != nil {
+			impl.logger.Errorw("error in comparing helm release values", "appIdentifier", appIdentifier, "revision", revision, "err", err)
+			return nil, err
+		}
+		if isEqual {
+			report.DesiredRevision = int(revision)
+			if detail.Manifest != nil {
+				desiredManifest = *detail.Manifest
+			}
+			break
+		}
+	}
+	report.Status = bean.DriftStatusDrifted
+	if report.DesiredRevision == 0 {
+		report.Message = bean.NoMatchingHelmRevision
+		return report, nil
+	}
+	if report.DesiredRevision != report.LiveRevision {
+		report.Message = fmt.Sprintf(bean.ReleaseUpgradedOutOfBand, report.LiveRevision, report.DesiredRevision)
+	}
+	resources, err := impl.getResourceDrifts(ctx, pipeline, desiredManifest)
+	if err != nil {
+		return nil, err
+	}
+	report.Resources = resources
+	if report.DesiredRevision == report.LiveRevision && len(resources) == 0 {
+		report.Status = bean.DriftStatusInSync
+	}
+	return report, nil
+}
+
+func (impl *HelmDriftServiceImpl) getResourceDrifts(ctx context.Context, pipeline *pipelineConfig.Pipeline, desiredManifest string) ([]*bean.ResourceDrift, error) {
+	objects, err := ParseManifest(desiredManifest)
+	if err != nil {
+		impl.logger.Errorw("error in parsing helm release manifest", "pipelineId", pipeline.Id, "err", err)
+		return nil, err
+	}
+	resources := make([]*bean.ResourceDrift, 0)
+	for _, desired := range objects {
+		gvk := desired.GroupVersionKind()
+		namespace := desired.GetNamespace()
+		if len(namespace) == 0 {
+			namespace = pipeline.Environment.Namespace
+		}
+		request := &bean5.ResourceRequestBean{
+			ClusterId: pipeline.Environment.ClusterId,
+			K8sRequest: &k8s2.K8sRequestBean{
+				ResourceIdentifier: k8s2.ResourceIdentifier{
+					Name:             desired.GetName(),
+					Namespace:        namespace,
+					GroupVersionKind: gvk,
+				},
+			},
+		}
+		resourceDrift := &bean.ResourceDrift{
+			Group:     gvk.Group,
+			Version:   gvk.Version,
+			Kind:      gvk.Kind,
+			Name:      desired.GetName(),
+			Namespace: namespace,
+		}
+		live, err := impl.k8sCommonService.GetResource(ctx, request)
+		if err != nil && k8sErrors.IsNotFound(err) {
+			resourceDrift.DriftType = bean.ResourceMissing
+			resources = append(resources, resourceDrift)
+			continue
+		} else if err != nil {
+			impl.logger.Errorw("error in fetching live resource", "pipelineId", pipeline.Id, "resource", request.K8sRequest.ResourceIdentifier, "err", err)
+			return nil, err
+		}
+		fields, err := GetDriftedFields(desired, &live.ManifestResponse.Manifest)
+		if err != nil {
+			impl.logger.Errorw("error in comparing live resource", "pipelineId", pipeline.Id, "resource", request.K8sRequest.ResourceIdentifier, "err", err)
+			return nil, err
+		}
+		if len(fields) > 0 {
+			resourceDrift.DriftType = bean.ResourceModified
+			resourceDrift.Fields = fields
+			resources = append(resources, resourceDrift)
+		}
+	}
+	SortResourceDrifts(resources)
+	return resources, nil
+}
+
+// reapply rolls back to the revision of the last Devtron deployment, helm's three way merge also reverts edits made on live objects
+func (impl *HelmDriftServiceImpl) reapply(ctx context.Context, pipeline *pipelineConfig.Pipeline, drift *repository.HelmReleaseDrift, userId int32) error {
+	appIdentifier := getAppIdentifier(pipeline)
+	success, err := impl.helmAppService.RollbackRelease(ctx, appIdentifier, int32(drift.DesiredRevision))
+	if err != nil {
+		impl.logger.Errorw("error in re-applying helm release", "appIdentifier", appIdentifier, "revision", drift.DesiredRevision, "err", err)
+		return err
+	}
+	if !success {
+		return util.NewApiError(http.StatusInternalServerError, "could not re-apply the helm release", "helm rollback was not successful")
+	}
+	now := time.Now()
+	drift.RemediatedOn = &now
+	drift.UpdateAuditLog(userId)
+	if err = impl.helmReleaseDriftRepository.Update(drift); err != nil {
+		impl.logger.Errorw("error in updating helm release drift", "pipelineId", pipeline.Id, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *HelmDriftServiceImpl) sendDriftNotification(pipeline *pipelineConfig.Pipeline, drift *repository.HelmReleaseDrift) {
+	event, err := impl.eventFactory.Build(eventUtil.ConfigDrift, &pipeline.Id, pipeline.AppId, &pipeline.EnvironmentId, eventUtil.CD)
+	if err != nil {
+		impl.logger.Errorw("error in building drift notification event", "pipelineId", pipeline.Id, "err", err)
+		return
+	}
+	event = impl.eventFactory.BuildExtraCDData(event, nil, 0, apiBean.CD_WORKFLOW_TYPE_DEPLOY)
+	if _, err = impl.eventClient.WriteNotificationEvent(event); err != nil {
+		impl.logger.Errorw("error in sending drift notification", "pipelineId", pipeline.Id, "err", err)
+		return
+	}
+	now := time.Now()
+	drift.NotifiedOn = &now
+	if err = impl.helmReleaseDriftRepository.Update(drift); err != nil {
+		impl.logger.Errorw("error in updating helm release drift", "pipelineId", pipeline.Id, "err", err)
+	}
+}
+
+func (impl *HelmDriftServiceImpl) getDriftReportDto(pipeline *pipelineConfig.Pipeline, drift *repository.HelmReleaseDrift) (*bean.DriftReportDto, error) {
+	resources := make([]*bean.ResourceDrift, 0)
+	if len(drift.DriftedResources) > 0 {
+		if err := json.Unmarshal([]byte(drift.DriftedResources), &resources); err != nil {
+			impl.logger.Errorw("error in parsing drifted resources", "pipelineId", pipeline.Id, "err", err)
+			return nil, err
+		}
+	}
+	return &bean.DriftReportDto{
+		AppId:           pipeline.AppId,
+		EnvId:           pipeline.EnvironmentId,
+		PipelineId:      pipeline.Id,
+		Status:          bean.DriftStatus(drift.Status),
+		DesiredRevision: drift.DesiredRevision,
+		LiveRevision:    drift.LiveRevision,
+		Message:         drift.Message,
+		Resources:       resources,
+		AutoRemediate:   drift.AutoRemediate,
+		LastCheckedOn:   drift.LastCheckedOn,
+		RemediatedOn:    drift.RemediatedOn,
+	}, nil
+}
+
+func getAppIdentifier(pipeline *pipelineConfig.Pipeline) *helmBean.AppIdentifier {
+	releaseName := pipeline.DeploymentAppName
+	if len(releaseName) == 0 {
+		releaseName = util2.BuildDeployedAppName(pipeline.App.AppName, pipeline.Environment.Name)
+	}
+	return &helmBean.AppIdentifier{
+		ClusterId:   pipeline.Environment.ClusterId,
+		Namespace:   pipeline.Environment.Namespace,
+		ReleaseName: releaseName,
+	}
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"time"
+)
+
+type DriftStatus string
+
+const (
+	DriftStatusInSync  DriftStatus = "IN_SYNC"
+	DriftStatusDrifted DriftStatus = "DRIFTED"
+	DriftStatusUnknown DriftStatus = "UNKNOWN"
+)
+
+type ResourceDriftType string
+
+const (
+	ResourceModified ResourceDriftType = "MODIFIED"
+	ResourceMissing  ResourceDriftType = "MISSING"
+)
+
+const (
+	NoDeploymentFound        = "no deployment found for this pipeline"
+	NoMatchingHelmRevision   = "none of the recent helm revisions match the last deployment from Devtron, the release was upgraded outside Devtron"
+	ReleaseUpgradedOutOfBand = "helm release was upgraded outside Devtron, live revision is %d while the last Devtron deployment is revision %d"
+	DeploymentNotSucceeded   = "drift is checked only after a successful deployment, latest deployment is %s"
+	NotAHelmPipeline         = "drift detection is supported only for helm deployed pipelines"
+	MaskedValue              = "******"
+)
+
+// DriftedField is a path in a desired manifest whose live value differs, Live is nil when the field is absent on the cluster
+type DriftedField struct {
+	Path    string      `json:"path"`
+	Desired interface{} `json:"desired"`
+	Live    interface{} `json:"live"`
+}
+
+type ResourceDrift struct {
+	Group     string            `json:"group"`
+	Version   string            `json:"version"`
+	Kind      string            `json:"kind"`
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	DriftType ResourceDriftType `json:"driftType"`
+	Fields    []*DriftedField   `json:"fields,omitempty"`
+}
+
+type DriftReportDto struct {
+	AppId           int              `json:"appId"`
+	EnvId           int              `json:"envId"`
+	PipelineId      int              `json:"pipelineId"`
+	Status          DriftStatus      `json:"status"`
+	DesiredRevision int              `json:"desiredRevision"`
+	LiveRevision    int              `json:"liveRevision"`
+	Message         string           `json:"message,omitempty"`
+	Resources       []*ResourceDrift `json:"resources"`
+	AutoRemediate   bool             `json:"autoRemediate"`
+	LastCheckedOn   *time.Time       `json:"lastCheckedOn,omitempty"`
+	RemediatedOn    *time.Time       `json:"remediatedOn,omitempty"`
+}
+
+// DriftSummaryDto is the drift last recorded for a pipeline, shown in app details without the drifted fields
+type DriftSummaryDto struct {
+	Status          DriftStatus      `json:"status"`
+	DesiredRevision int              `json:"desiredRevision"`
+	LiveRevision    int              `json:"liveRevision"`
+	Message         string           `json:"message,omitempty"`
+	Resources       []*ResourceDrift `json:"resources"`
+	AutoRemediate   bool             `json:"autoRemediate"`
+	LastCheckedOn   *time.Time       `json:"lastCheckedOn,omitempty"`
+}
+
+type DriftActionRequest struct {
+	AppId  int   `json:"appId" validate:"required,number,gt=0"`
+	EnvId  int   `json:"envId" validate:"required,number,gt=0"`
+	UserId int32 `json:"-"`
+}
+
+type AutoRemediateRequest struct {
+	AppId         int   `json:"appId" validate:"required,number,gt=0"`
+	EnvId         int   `json:"envId" validate:"required,number,gt=0"`
+	AutoRemediate bool  `json:"autoRemediate"`
+	UserId        int32 `json:"-"`
+}
+
+type HelmDriftConfig struct {
+	CronIntervalSecs    int  `env:"HELM_DRIFT_DETECTION_CRON_INTERVAL_SECS" envDefault:"900"`
+	DetectionEnabled    bool `env:"HELM_DRIFT_DETECTION_ENABLED" envDefault:"true"`
+	MaxRevisionsToMatch int  `env:"HELM_DRIFT_MAX_REVISIONS_TO_MATCH" envDefault:"5"`
+}
@@ -0,0 +1,239 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helmDrift
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/deployment/helmDrift/bean"
+	"io"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sYaml "k8s.io/apimachinery/pkg/util/yaml"
+	"reflect"
+	"sigs.k8s.io/yaml"
+	"sort"
+	"strings"
+)
+
+const secretKind = "Secret"
+
+// IsValuesEqual compares two values yaml semantically, so formatting and key order of the release values do not matter
+func IsValuesEqual(first, second string) (bool, error) {
+	firstValues, err := parseValues(first)
+	if err != nil {
+		return false, err
+	}
+	secondValues, err := parseValues(second)
+	if err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(firstValues, secondValues), nil
+}
+
+func parseValues(values string) (map[string]interface{}, error) {
+	parsed := make(map[string]interface{})
+	if len(strings.TrimSpace(values)) == 0 {
+		return parsed, nil
+	}
+	jsonValues, err := yaml.YAMLToJSON([]byte(values))
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(jsonValues, &parsed); err != nil {
+		return nil, err
+	}
+	if parsed == nil {
+		parsed = make(map[string]interface{})
+	}
+	return parsed, nil
+}
+
+// ParseManifest splits a rendered multi document helm manifest into objects, documents without a kind are skipped
+func ParseManifest(manifest string) ([]*unstructured.Unstructured, error) {
+	objects := make([]*unstructured.Unstructured, 0)
+	decoder := k8sYaml.NewYAMLOrJSONDecoder(strings.NewReader(manifest), 4096)
+	for {
+		object := make(map[string]interface{})
+		err := decoder.Decode(&object)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if len(object) == 0 {
+			continue
+		}
+		manifestObject := &unstructured.Unstructured{Object: object}
+		if len(manifestObject.GetKind()) == 0 || len(manifestObject.GetName()) == 0 {
+			continue
+		}
+		objects = append(objects, manifestObject)
+	}
+	return objects, nil
+}
+
+// GetDriftedFields returns the fields of desired whose live value differs. Only fields present in desired are compared
+// as the api server defaults and controllers add fields which were never part of the release.
+func GetDriftedFields(desired, live *unstructured.Unstructured) ([]*bean.DriftedField, error) {
+	desiredObject, err := normalizeObject(desired.Object)
+	if err != nil {
+		return nil, err
+	}
+	liveObject, err := normalizeObject(live.Object)
+	if err != nil {
+		return nil, err
+	}
+	kind := desired.GetKind()
+	fields := make([]*bean.DriftedField, 0)
+	for key, desiredValue := range desiredObject {
+		if key == "status" {
+			continue
+		}
+		// stringData is write only, the api server merges it into data
+		if kind == secretKind && key == "stringData" {
+			continue
+		}
+		fields = compareValues(key, desiredValue, liveObject[key], fields)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Path < fields[j].Path
+	})
+	if kind == secretKind {
+		for _, field := range fields {
+			field.Desired = maskValue(field.Desired)
+			field.Live = maskValue(field.Live)
+		}
+	}
+	return fields, nil
+}
+
+// normalizeObject round trips through json so that desired (parsed from yaml) and live (int64 from the api server)
+// numbers compare as the same type
+func normalizeObject(object map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	normalized := make(map[string]interface{})
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
+
+func compareValues(path string, desired, live interface{}, fields []*bean.DriftedField) []*bean.DriftedField {
+	if desired == nil {
+		return fields
+	}
+	if live == nil && isZeroValue(desired) {
+		// omitempty fields are dropped by the api server
+		return fields
+	}
+	switch desiredValue := desired.(type) {
+	case map[string]interface{}:
+		liveValue, ok := live.(map[string]interface{})
+		if !ok {
+			return append(fields, &bean.DriftedField{Path: path, Desired: desired, Live: live})
+		}
+		for key, value := range desiredValue {
+			fields = compareValues(path+"."+key, value, liveValue[key], fields)
+		}
+	case []interface{}:
+		liveValue, ok := live.([]interface{})
+		if !ok || len(liveValue) != len(desiredValue) {
+			return append(fields, &bean.DriftedField{Path: path, Desired: desired, Live: live})
+		}
+		for i := range desiredValue {
+			fields = compareValues(fmt.Sprintf("%s[%d]", path, i), desiredValue[i], liveValue[i], fields)
+		}
+	default:
+		if !isScalarEqual(desired, live) {
+			return append(fields, &bean.DriftedField{Path: path, Desired: desired, Live: live})
+		}
+	}
+	return fields
+}
+
+func isZeroValue(value interface{}) bool {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		return len(typedValue) == 0
+	case []interface{}:
+		return len(typedValue) == 0
+	case string:
+		return len(typedValue) == 0
+	case bool:
+		return !typedValue
+	case float64:
+		return typedValue == 0
+	}
+	return false
+}
+
+// isScalarEqual also treats equivalent quantities as equal, the api server canonicalises values like cpu: 0.5 to 500m
+func isScalarEqual(desired, live interface{}) bool {
+	if live == nil {
+		return false
+	}
+	if reflect.DeepEqual(desired, live) {
+		return true
+	}
+	desiredQuantity, err := resource.ParseQuantity(fmt.Sprint(desired))
+	if err != nil {
+		return false
+	}
+	liveQuantity, err := resource.ParseQuantity(fmt.Sprint(live))
+	if err != nil {
+		return false
+	}
+	return desiredQuantity.Cmp(liveQuantity) == 0
+}
+
+func maskValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	return bean.MaskedValue
+}
+
+func SortResourceDrifts(resources []*bean.ResourceDrift) {
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Kind != resources[j].Kind {
+			return resources[i].Kind < resources[j].Kind
+		}
+		if resources[i].Namespace != resources[j].Namespace {
+			return resources[i].Namespace < resources[j].Namespace
+		}
+		return resources[i].Name < resources[j].Name
+	})
+}
+
+// GetDriftHash identifies a drift so that a notification is sent once per distinct drift and not on every check
+func GetDriftHash(desiredRevision, liveRevision int, resources []*bean.ResourceDrift) (string, error) {
+	data, err := json.Marshal(struct {
+		DesiredRevision int                   `json:"desiredRevision"`
+		LiveRevision    int                   `json:"liveRevision"`
+		Resources       []*bean.ResourceDrift `json:"resources"`
+	}{desiredRevision, liveRevision, resources})
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), nil
+}
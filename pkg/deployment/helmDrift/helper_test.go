@@ -0,0 +1,199 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helmDrift
+
+import (
+	"github.com/devtron-labs/devtron/pkg/deployment/helmDrift/bean"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"testing"
+)
+
+const desiredManifest = `
+---
+# Source: app/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: app-service
+spec:
+  ports:
+    - port: 80
+      targetPort: 8080
+---
+# Source: app/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  labels:
+    app: app
+spec:
+  replicas: 2
+  template:
+    spec:
+      hostNetwork: false
+      containers:
+        - name: app
+          image: app:v1
+          resources:
+            limits:
+              cpu: 0.5
+              memory: 1Gi
+---
+`
+
+func TestParseManifest(t *testing.T) {
+	objects, err := ParseManifest(desiredManifest)
+	if err != nil {
+		t.Fatalf("ParseManifest() error = %v", err)
+	}
+	if len(objects) != 2 {
+		t.Fatalf("ParseManifest() got %d objects, want 2", len(objects))
+	}
+	if objects[0].GetKind() != "Service" || objects[1].GetKind() != "Deployment" || objects[1].GetName() != "app" {
+		t.Errorf("ParseManifest() got unexpected objects %s/%s, %s/%s", objects[0].GetKind(), objects[0].GetName(), objects[1].GetKind(), objects[1].GetName())
+	}
+}
+
+func TestIsValuesEqual(t *testing.T) {
+	tests := []struct {
+		name   string
+		first  string
+		second string
+		want   bool
+	}{
+		{name: "Test1_DifferentFormatting", first: "replicaCount: 2\nimage:\n  tag: v1\n", second: `{"image":{"tag":"v1"},"replicaCount":2}`, want: true},
+		{name: "Test2_DifferentValue", first: "replicaCount: 2", second: "replicaCount: 3", want: false},
+		{name: "Test3_EmptyValues", first: "", second: "{}", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IsValuesEqual(tt.first, tt.second)
+			if err != nil {
+				t.Fatalf("IsValuesEqual() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IsValuesEqual() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDriftedFields(t *testing.T) {
+	objects, err := ParseManifest(desiredManifest)
+	if err != nil {
+		t.Fatalf("ParseManifest() error = %v", err)
+	}
+	desired := objects[1]
+	liveDeployment := func(replicas int64, image string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":            "app",
+				"namespace":       "default",
+				"resourceVersion": "1234",
+				"labels":          map[string]interface{}{"app": "app"},
+				"annotations":     map[string]interface{}{"meta.helm.sh/release-name": "app"},
+			},
+			"spec": map[string]interface{}{
+				"replicas": replicas,
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{
+							map[string]interface{}{
+								"name":                     "app",
+								"image":                    image,
+								"terminationMessagePolicy": "File",
+								"resources": map[string]interface{}{
+									"limits": map[string]interface{}{"cpu": "500m", "memory": "1Gi"},
+								},
+							},
+						},
+					},
+				},
+			},
+			"status": map[string]interface{}{"replicas": int64(1)},
+		}}
+	}
+	tests := []struct {
+		name      string
+		live      *unstructured.Unstructured
+		wantPaths []string
+	}{
+		{name: "Test1_DefaultedAndCanonicalisedFieldsAreInSync", live: liveDeployment(2, "app:v1"), wantPaths: []string{}},
+		{name: "Test2_EditedFieldsAreReported", live: liveDeployment(5, "app:hotfix"), wantPaths: []string{"spec.replicas", "spec.template.spec.containers[0].image"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := GetDriftedFields(desired, tt.live)
+			if err != nil {
+				t.Fatalf("GetDriftedFields() error = %v", err)
+			}
+			if len(fields) != len(tt.wantPaths) {
+				t.Fatalf("GetDriftedFields() got %d fields, want %d", len(fields), len(tt.wantPaths))
+			}
+			for i, field := range fields {
+				if field.Path != tt.wantPaths[i] {
+					t.Errorf("GetDriftedFields() path = %v, want %v", field.Path, tt.wantPaths[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetDriftedFieldsMasksSecrets(t *testing.T) {
+	desired := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "app-secret"},
+		"stringData": map[string]interface{}{"password": "plain"},
+		"data":       map[string]interface{}{"token": "b2xk"},
+	}}
+	live := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "app-secret"},
+		"data":       map[string]interface{}{"token": "bmV3", "password": "cGxhaW4="},
+	}}
+	fields, err := GetDriftedFields(desired, live)
+	if err != nil {
+		t.Fatalf("GetDriftedFields() error = %v", err)
+	}
+	if len(fields) != 1 || fields[0].Path != "data.token" {
+		t.Fatalf("GetDriftedFields() got %v, want only data.token", fields)
+	}
+	if fields[0].Desired != bean.MaskedValue || fields[0].Live != bean.MaskedValue {
+		t.Errorf("GetDriftedFields() secret values are not masked")
+	}
+}
+
+func TestGetDriftHash(t *testing.T) {
+	resources := []*bean.ResourceDrift{{Kind: "Deployment", Name: "app", DriftType: bean.ResourceModified}}
+	first, err := GetDriftHash(1, 1, resources)
+	if err != nil {
+		t.Fatalf("GetDriftHash() error = %v", err)
+	}
+	second, _ := GetDriftHash(1, 1, resources)
+	third, _ := GetDriftHash(1, 2, resources)
+	if first != second {
+		t.Errorf("GetDriftHash() is not stable for the same drift")
+	}
+	if first == third {
+		t.Errorf("GetDriftHash() is same for different drifts")
+	}
+}
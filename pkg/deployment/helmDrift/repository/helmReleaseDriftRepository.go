@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type HelmReleaseDrift struct {
+	tableName        struct{}   `sql:"helm_release_drift" pg:",discard_unknown_columns"`
+	Id               int        `sql:"id,pk"`
+	PipelineId       int        `sql:"pipeline_id,notnull"`
+	AutoRemediate    bool       `sql:"auto_remediate,notnull"`
+	Status           string     `sql:"status,notnull"`
+	DesiredRevision  int        `sql:"desired_revision"`
+	LiveRevision     int        `sql:"live_revision"`
+	DriftedResources string     `sql:"drifted_resources"`
+	DriftHash        string     `sql:"drift_hash"`
+	Message          string     `sql:"message"`
+	LastCheckedOn    *time.Time `sql:"last_checked_on"`
+	NotifiedOn       *time.Time `sql:"notified_on"`
+	RemediatedOn     *time.Time `sql:"remediated_on"`
+	sql.AuditLog
+}
+
+type HelmReleaseDriftRepository interface {
+	Save(drift *HelmReleaseDrift) error
+	Update(drift *HelmReleaseDrift) error
+	FindByPipelineId(pipelineId int) (*HelmReleaseDrift, error)
+}
+
+type HelmReleaseDriftRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewHelmReleaseDriftRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *HelmReleaseDriftRepositoryImpl {
+	return &HelmReleaseDriftRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (repo *HelmReleaseDriftRepositoryImpl) Save(drift *HelmReleaseDrift) error {
+	return repo.dbConnection.Insert(drift)
+}
+
+func (repo *HelmReleaseDriftRepositoryImpl) Update(drift *HelmReleaseDrift) error {
+	return repo.dbConnection.Update(drift)
+}
+
+func (repo *HelmReleaseDriftRepositoryImpl) FindByPipelineId(pipelineId int) (*HelmReleaseDrift, error) {
+	drift := &HelmReleaseDrift{}
+	err := repo.dbConnection.Model(drift).
+		Where("pipeline_id = ?", pipelineId).
+		Limit(1).
+		Select()
+	return drift, err
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/app"
 	appBean "github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/devtron-labs/devtron/pkg/build/artifacts/registryPromotion"
 	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
 	repository2 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
 	"github.com/devtron-labs/devtron/pkg/deployment/common"
@@ -50,6 +51,8 @@ import (
 	"github.com/devtron-labs/devtron/pkg/k8s"
 	bean4 "github.com/devtron-labs/devtron/pkg/k8s/bean"
 	repository3 "github.com/devtron-labs/devtron/pkg/pipeline/history/repository"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/resourceQuota"
+	resourceQuotaBean "github.com/devtron-labs/devtron/pkg/policyGovernance/resourceQuota/bean"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/devtron-labs/devtron/pkg/variables"
 	"github.com/devtron-labs/devtron/pkg/variables/parsers"
@@ -101,6 +104,8 @@ type ManifestCreationServiceImpl struct {
 	deploymentTemplateHistoryRepository repository3.DeploymentTemplateHistoryRepository
 	deploymentConfigService             common.DeploymentConfigService
 	envConfigOverrideReadService        read.EnvConfigOverrideService
+	registryPromotionService            registryPromotion.RegistryPromotionService
+	resourceQuotaService                resourceQuota.ResourceQuotaService
 }
 
 func NewManifestCreationServiceImpl(logger *zap.SugaredLogger,
@@ -126,7 +131,9 @@ func NewManifestCreationServiceImpl(logger *zap.SugaredLogger,
 	pipelineConfigRepository chartConfig.PipelineConfigRepository,
 	deploymentTemplateHistoryRepository repository3.DeploymentTemplateHistoryRepository,
 	deploymentConfigService common.DeploymentConfigService,
-	envConfigOverrideService read.EnvConfigOverrideService) *ManifestCreationServiceImpl {
+	envConfigOverrideService read.EnvConfigOverrideService,
+	registryPromotionService registryPromotion.RegistryPromotionService,
+	resourceQuotaService resourceQuota.ResourceQuotaService) *ManifestCreationServiceImpl {
 	return &ManifestCreationServiceImpl{
 		logger:                              logger,
 		dockerRegistryIpsConfigService:      dockerRegistryIpsConfigService,
@@ -152,6 +159,8 @@ func NewManifestCreationServiceImpl(logger *zap.SugaredLogger,
 		deploymentTemplateHistoryRepository: deploymentTemplateHistoryRepository,
 		deploymentConfigService:             deploymentConfigService,
 		envConfigOverrideReadService:        envConfigOverrideService,
+		registryPromotionService:            registryPromotionService,
+		resourceQuotaService:                resourceQuotaService,
 	}
 }
 
@@ -232,6 +241,14 @@ func (impl *ManifestCreationServiceImpl) GetValuesOverrideForTrigger(ctx context
 	}
 	valuesOverrideResponse.EnvOverride = envOverride
 
+	// image is deployed from the target registry when the environment is flagged for promotion
+	deployedArtifact, err := impl.getArtifactForEnvironment(newCtx, artifact, envOverride.Environment, pipeline.CiPipelineId, overrideRequest.UserId)
+	if err != nil {
+		impl.logger.Errorw("error in promoting artifact for environment", "ciArtifactId", artifact.Id, "envId", overrideRequest.EnvId, "err", err)
+		return valuesOverrideResponse, err
+	}
+	overrideRequest.Image = deployedArtifact.Image
+
 	// Conditional Block based on PipelineOverrideCreated --> start
 	if !isPipelineOverrideCreated {
 		pipelineOverride, err = impl.savePipelineOverride(newCtx, overrideRequest, envOverride.Id, triggeredAt)
@@ -251,7 +268,7 @@ func (impl *ManifestCreationServiceImpl) GetValuesOverrideForTrigger(ctx context
 		return valuesOverrideResponse, err
 	}
 	//TODO: check status and apply lock
-	releaseOverrideJson, err := impl.getReleaseOverride(envOverride, overrideRequest, artifact, pipelineOverride.PipelineReleaseCounter, strategy, &appMetrics)
+	releaseOverrideJson, err := impl.getReleaseOverride(envOverride, overrideRequest, deployedArtifact, pipelineOverride.PipelineReleaseCounter, strategy, &appMetrics)
 	valuesOverrideResponse.ReleaseOverrideJSON = releaseOverrideJson
 	if err != nil {
 		return valuesOverrideResponse, err
@@ -290,9 +307,29 @@ func (impl *ManifestCreationServiceImpl) GetValuesOverrideForTrigger(ctx context
 				impl.logger.Errorw("error in autoscaling check before trigger", "pipelineId", overrideRequest.PipelineId, "err", err)
 				return valuesOverrideResponse, err
 			}
+			if overrideRequest.DeploymentType != models.DEPLOYMENTTYPE_STOP {
+				err = impl.resourceQuotaService.CheckDeploymentBudget(newCtx, &resourceQuotaBean.DeploymentBudgetRequest{
+					AppId:              overrideRequest.AppId,
+					AppName:            pipeline.App.AppName,
+					TeamId:             pipeline.App.TeamId,
+					EnvironmentId:      overrideRequest.EnvId,
+					EnvironmentName:    envOverride.Environment.Name,
+					Namespace:          envOverride.Namespace,
+					PipelineId:         overrideRequest.PipelineId,
+					PipelineOverrideId: pipelineOverride.Id,
+					DeploymentAppName:  appName,
+					ChartRefId:         envOverride.Chart.ChartRefId,
+					MergedValues:       mergedValues,
+					UserId:             overrideRequest.UserId,
+				})
+				if err != nil {
+					impl.logger.Errorw("error in resource budget check before trigger", "pipelineId", overrideRequest.PipelineId, "err", err)
+					return valuesOverrideResponse, err
+				}
+			}
 		}
 		// handle image pull secret if access given
-		mergedValues, err = impl.dockerRegistryIpsConfigService.HandleImagePullSecretOnApplicationDeployment(newCtx, envOverride.Environment, artifact, pipeline.CiPipelineId, mergedValues)
+		mergedValues, err = impl.dockerRegistryIpsConfigService.HandleImagePullSecretOnApplicationDeployment(newCtx, envOverride.Environment, deployedArtifact, pipeline.CiPipelineId, mergedValues)
 		if err != nil {
 			return valuesOverrideResponse, err
 		}
@@ -311,6 +348,24 @@ func (impl *ManifestCreationServiceImpl) GetValuesOverrideForTrigger(ctx context
 	return valuesOverrideResponse, err
 }
 
+// getArtifactForEnvironment returns a copy of the artifact pointing to its promoted image when the environment promotes
+// artifacts into another registry, otherwise the artifact itself
+func (impl *ManifestCreationServiceImpl) getArtifactForEnvironment(ctx context.Context, artifact *repository.CiArtifact, environment *repository2.Environment, ciPipelineId int, userId int32) (*repository.CiArtifact, error) {
+	if environment == nil {
+		return artifact, nil
+	}
+	promotedImage, err := impl.registryPromotionService.PromoteArtifactForDeployment(ctx, artifact, environment, ciPipelineId, userId)
+	if err != nil {
+		return nil, err
+	} else if promotedImage == nil {
+		return artifact, nil
+	}
+	deployedArtifact := *artifact
+	deployedArtifact.Image = promotedImage.Image
+	deployedArtifact.PromotedRegistryId = promotedImage.RegistryId
+	return &deployedArtifact, nil
+}
+
 func (impl *ManifestCreationServiceImpl) getDeploymentStrategyByTriggerType(overrideRequest *bean.ValuesOverrideRequest, ctx context.Context) (*chartConfig.PipelineStrategy, error) {
 	newCtx, span := otel.Tracer("orchestrator").Start(ctx, "ManifestCreationServiceImpl.getDeploymentStrategyByTriggerType")
 	defer span.End()
@@ -30,6 +30,7 @@ import (
 	bean7 "github.com/devtron-labs/devtron/client/argocdServer/bean"
 	client "github.com/devtron-labs/devtron/client/events"
 	gitSensorClient "github.com/devtron-labs/devtron/client/gitSensor"
+	"github.com/devtron-labs/devtron/internal/constants"
 	"github.com/devtron-labs/devtron/internal/middleware"
 	"github.com/devtron-labs/devtron/internal/sql/models"
 	repository3 "github.com/devtron-labs/devtron/internal/sql/repository"
@@ -51,8 +52,11 @@ import (
 	"github.com/devtron-labs/devtron/pkg/build/git/gitMaterial/read"
 	pipeline2 "github.com/devtron-labs/devtron/pkg/build/pipeline"
 	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterCredentials"
 	repository2 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
 	repository5 "github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/deployment/canaryAnalysis"
+	bean12 "github.com/devtron-labs/devtron/pkg/deployment/canaryAnalysis/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/common"
 	bean9 "github.com/devtron-labs/devtron/pkg/deployment/common/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/config"
@@ -65,6 +69,8 @@ import (
 	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
 	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/helper"
 	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/userDeploymentRequest/service"
+	"github.com/devtron-labs/devtron/pkg/deploymentWindow"
+	bean11 "github.com/devtron-labs/devtron/pkg/deploymentWindow/bean"
 	clientErrors "github.com/devtron-labs/devtron/pkg/errors"
 	"github.com/devtron-labs/devtron/pkg/eventProcessor/out"
 	"github.com/devtron-labs/devtron/pkg/imageDigestPolicy"
@@ -173,6 +179,9 @@ type TriggerServiceImpl struct {
 	attributeService                    attributes.AttributesService
 	clusterRepository                   repository5.ClusterRepository
 	cdWorkflowRunnerService             cd.CdWorkflowRunnerService
+	deploymentWindowService             deploymentWindow.DeploymentWindowService
+	canaryAnalysisService               canaryAnalysis.CanaryAnalysisService
+	clusterCredentialService            clusterCredentials.ClusterCredentialService
 }
 
 func NewTriggerServiceImpl(logger *zap.SugaredLogger,
@@ -232,6 +241,9 @@ func NewTriggerServiceImpl(logger *zap.SugaredLogger,
 	attributeService attributes.AttributesService,
 	clusterRepository repository5.ClusterRepository,
 	cdWorkflowRunnerService cd.CdWorkflowRunnerService,
+	deploymentWindowService deploymentWindow.DeploymentWindowService,
+	canaryAnalysisService canaryAnalysis.CanaryAnalysisService,
+	clusterCredentialService clusterCredentials.ClusterCredentialService,
 ) (*TriggerServiceImpl, error) {
 	impl := &TriggerServiceImpl{
 		logger:                              logger,
@@ -296,7 +308,10 @@ func NewTriggerServiceImpl(logger *zap.SugaredLogger,
 		attributeService:            attributeService,
 		cdWorkflowRunnerService:     cdWorkflowRunnerService,
 
-		clusterRepository: clusterRepository,
+		clusterRepository:        clusterRepository,
+		deploymentWindowService:  deploymentWindowService,
+		canaryAnalysisService:    canaryAnalysisService,
+		clusterCredentialService: clusterCredentialService,
 	}
 	config, err := types.GetCdConfig()
 	if err != nil {
@@ -371,6 +386,11 @@ func (impl *TriggerServiceImpl) validateDeploymentTriggerRequest(ctx context.Con
 		return err
 	}
 	// custom GitOps repo url validation --> Ends
+	err = impl.validateDeploymentWindow(newCtx, validateDeploymentTriggerObj)
+	if err != nil {
+		impl.logger.Errorw("deployment window validation error, TriggerStage", "cdPipelineId", validateDeploymentTriggerObj.CdPipeline.Id, "err", err)
+		return err
+	}
 	var isVulnerable bool
 	// if request is for rollback then bypass vulnerability validation
 	if !validateDeploymentTriggerObj.IsDeploymentTypeRollback() {
@@ -393,6 +413,95 @@ func (impl *TriggerServiceImpl) validateDeploymentTriggerRequest(ctx context.Con
 	return nil
 }
 
+// validateDeploymentWindow blocks the deployment while a deployment window is closed for the pipeline's app/env/cluster.
+// Super admins can deploy through a closed window by providing a reason, which is audited against the runner. The runner
+// is marked failed whenever the deployment does not go ahead so that it is not left in a non-terminal state.
+func (impl *TriggerServiceImpl) validateDeploymentWindow(ctx context.Context, validateDeploymentTriggerObj *bean.ValidateDeploymentTriggerObj) error {
+	newCtx, span := otel.Tracer("orchestrator").Start(ctx, "TriggerServiceImpl.validateDeploymentWindow")
+	defer span.End()
+	err := impl.checkDeploymentWindow(newCtx, validateDeploymentTriggerObj)
+	if err != nil {
+		runner := validateDeploymentTriggerObj.Runner
+		if dbErr := impl.cdWorkflowCommonService.MarkCurrentDeploymentFailed(runner, err, validateDeploymentTriggerObj.TriggeredBy); dbErr != nil {
+			impl.logger.Errorw("error while updating current runner status to failed, validateDeploymentWindow", "wfrId", runner.Id, "err", dbErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (impl *TriggerServiceImpl) checkDeploymentWindow(ctx context.Context, validateDeploymentTriggerObj *bean.ValidateDeploymentTriggerObj) error {
+	cdPipeline := validateDeploymentTriggerObj.CdPipeline
+	clusterId := cdPipeline.Environment.ClusterId
+	if clusterId == 0 {
+		env, err := impl.envRepository.FindById(cdPipeline.EnvironmentId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching environment", "envId", cdPipeline.EnvironmentId, "err", err)
+			return err
+		}
+		clusterId = env.ClusterId
+	}
+	stateRequest := &bean11.DeploymentWindowStateRequest{
+		AppId:     cdPipeline.AppId,
+		EnvId:     cdPipeline.EnvironmentId,
+		ClusterId: clusterId,
+	}
+	state, err := impl.deploymentWindowService.GetDeploymentWindowState(stateRequest, time.Now())
+	if err != nil {
+		impl.logger.Errorw("error in getting deployment window state", "request", stateRequest, "err", err)
+		return err
+	}
+	if state.IsDeploymentAllowed {
+		return nil
+	}
+	runner := validateDeploymentTriggerObj.Runner
+	overrideReason := strings.TrimSpace(validateDeploymentTriggerObj.DeploymentWindowOverrideReason)
+	if len(overrideReason) > 0 {
+		// super admin flag is only set in context for manual triggers, automatic triggers can never override
+		isSuperAdmin, err := globalUtil.GetIsSuperAdminFromContext(ctx)
+		if err != nil || !isSuperAdmin {
+			return util.NewApiError(http.StatusForbidden, bean11.OverrideNotPermitted, bean11.OverrideNotPermitted).WithCode(constants.DeploymentWindowFail)
+		}
+		auditRequest := &bean11.OverrideAuditRequest{
+			PipelineId:         cdPipeline.Id,
+			AppId:              cdPipeline.AppId,
+			EnvId:              cdPipeline.EnvironmentId,
+			CdWorkflowRunnerId: runner.Id,
+			Reason:             overrideReason,
+			State:              state,
+			UserId:             validateDeploymentTriggerObj.TriggeredBy,
+		}
+		if err = impl.deploymentWindowService.SaveOverrideAudit(auditRequest); err != nil {
+			impl.logger.Errorw("error in saving deployment window override audit", "request", auditRequest, "err", err)
+			return err
+		}
+		impl.logger.Infow("deployment window overridden", "cdPipelineId", cdPipeline.Id, "wfrId", runner.Id, "userId", validateDeploymentTriggerObj.TriggeredBy)
+		return nil
+	}
+	message := state.GetBlockedMessage()
+	return util.NewApiError(http.StatusForbidden, message, message).WithCode(constants.DeploymentWindowFail)
+}
+
+// startCanaryAnalysisIfApplicable starts the metric analysis for canary deployments, failures are logged and do not fail the release
+func (impl *TriggerServiceImpl) startCanaryAnalysisIfApplicable(overrideRequest *bean3.ValuesOverrideRequest, valuesOverrideResponse *app.ValuesOverrideResponse) {
+	if valuesOverrideResponse.PipelineStrategy == nil || valuesOverrideResponse.PipelineStrategy.Strategy != chartRepoRepository.DEPLOYMENT_STRATEGY_CANARY {
+		return
+	}
+	request := &bean12.StartAnalysisRequest{
+		AppId:              overrideRequest.AppId,
+		EnvId:              overrideRequest.EnvId,
+		ClusterId:          overrideRequest.ClusterId,
+		PipelineId:         overrideRequest.PipelineId,
+		CdWorkflowRunnerId: overrideRequest.WfrId,
+		RolloutName:        overrideRequest.ReleaseName,
+		Namespace:          overrideRequest.Namespace,
+		UserId:             overrideRequest.UserId,
+	}
+	if err := impl.canaryAnalysisService.StartAnalysisRun(request); err != nil {
+		impl.logger.Errorw("error in starting canary analysis", "request", request, "err", err)
+	}
+}
+
 // TODO: write a wrapper to handle auto and manual trigger
 func (impl *TriggerServiceImpl) ManualCdTrigger(triggerContext bean.TriggerContext, overrideRequest *bean3.ValuesOverrideRequest) (int, string, *bean4.ManifestPushTemplate, error) {
 
@@ -542,6 +651,7 @@ func (impl *TriggerServiceImpl) ManualCdTrigger(triggerContext bean.TriggerConte
 		}
 		if isNotHibernateRequest(overrideRequest.DeploymentType) {
 			validateReqObj := adapter.NewValidateDeploymentTriggerObj(runner, cdPipeline, artifact.ImageDigest, envDeploymentConfig, overrideRequest.UserId, overrideRequest.IsRollbackDeployment)
+			validateReqObj.DeploymentWindowOverrideReason = overrideRequest.DeploymentWindowOverrideReason
 			validationErr := impl.validateDeploymentTriggerRequest(ctx, validateReqObj)
 			if validationErr != nil {
 				impl.logger.Errorw("validation error deployment request", "cdWfr", runner.Id, "err", validationErr)
@@ -894,6 +1004,7 @@ func (impl *TriggerServiceImpl) TriggerRelease(ctx context.Context, overrideRequ
 	if dbErr != nil {
 		impl.logger.Errorw("error in creating timeline status for deployment completed", "err", dbErr, "timeline", timeline)
 	}
+	impl.startCanaryAnalysisIfApplicable(overrideRequest, valuesOverrideResponse)
 	impl.logger.Debugw("triggered pipeline for release successfully", "wfrId", overrideRequest.WfrId, "builtChartPath", builtChartPath)
 	return releaseNo, valuesOverrideResponse.ManifestPushTemplate, nil
 }
@@ -2,7 +2,6 @@ package devtronApps
 
 import (
 	"context"
-	"github.com/devtron-labs/common-lib/utils/k8s/commonBean"
 	bean3 "github.com/devtron-labs/devtron/api/bean"
 	bean6 "github.com/devtron-labs/devtron/api/helm-app/bean"
 	"github.com/devtron-labs/devtron/api/helm-app/gRPC"
@@ -11,6 +10,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/app"
 	bean4 "github.com/devtron-labs/devtron/pkg/app/bean"
 	bean2 "github.com/devtron-labs/devtron/pkg/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/adapter"
 	"github.com/devtron-labs/devtron/pkg/deployment/manifest/publish"
 	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
 	"github.com/devtron-labs/devtron/pkg/pipeline/repository"
@@ -44,17 +44,10 @@ func (impl *TriggerServiceImpl) getHelmHistoryLimitAndChartMetadataForHelmAppCre
 		impl.logger.Errorw("error in getting cluster by id, found nil object", "clusterId", envOverride.Environment.ClusterId)
 		return nil, 0, nil, err
 	}
-	bearerToken := cluster.Config[commonBean.BearerToken]
-	clusterConfig := &gRPC.ClusterConfig{
-		ClusterName:           cluster.ClusterName,
-		Token:                 bearerToken,
-		ApiServerUrl:          cluster.ServerUrl,
-		InsecureSkipTLSVerify: cluster.InsecureSkipTlsVerify,
-	}
-	if cluster.InsecureSkipTlsVerify == false {
-		clusterConfig.KeyData = cluster.Config[commonBean.TlsKey]
-		clusterConfig.CertData = cluster.Config[commonBean.CertData]
-		clusterConfig.CaData = cluster.Config[commonBean.CertificateAuthorityData]
+	clusterConfig, err := adapter.GetGrpcClusterConfig(adapter.GetClusterBean(*cluster), impl.clusterCredentialService)
+	if err != nil {
+		impl.logger.Errorw("error in resolving cluster credentials", "clusterId", cluster.Id, "err", err)
+		return nil, 0, nil, err
 	}
 	releaseIdentifier := &gRPC.ReleaseIdentifier{
 		ReleaseName:      releaseName,
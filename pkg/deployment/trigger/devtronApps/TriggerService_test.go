@@ -0,0 +1,128 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package devtronApps
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/deployment/trigger/devtronApps/bean"
+	"github.com/devtron-labs/devtron/pkg/deploymentWindow"
+	bean2 "github.com/devtron-labs/devtron/pkg/deploymentWindow/bean"
+	"github.com/devtron-labs/devtron/pkg/workflow/cd"
+	globalUtil "github.com/devtron-labs/devtron/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type deploymentWindowServiceStub struct {
+	deploymentWindow.DeploymentWindowService
+	state        *bean2.DeploymentWindowState
+	saveAuditErr error
+	savedAudits  []*bean2.OverrideAuditRequest
+}
+
+func (stub *deploymentWindowServiceStub) GetDeploymentWindowState(request *bean2.DeploymentWindowStateRequest, evaluationTime time.Time) (*bean2.DeploymentWindowState, error) {
+	return stub.state, nil
+}
+
+func (stub *deploymentWindowServiceStub) SaveOverrideAudit(request *bean2.OverrideAuditRequest) error {
+	if stub.saveAuditErr != nil {
+		return stub.saveAuditErr
+	}
+	stub.savedAudits = append(stub.savedAudits, request)
+	return nil
+}
+
+type cdWorkflowCommonServiceStub struct {
+	cd.CdWorkflowCommonService
+	failedRunners map[int]error
+}
+
+func (stub *cdWorkflowCommonServiceStub) MarkCurrentDeploymentFailed(runner *pipelineConfig.CdWorkflowRunner, releaseErr error, triggeredBy int32) error {
+	stub.failedRunners[runner.Id] = releaseErr
+	return nil
+}
+
+func TestValidateDeploymentWindow(t *testing.T) {
+	logger, err := util.NewSugardLogger()
+	assert.Nil(t, err)
+	blockedState := &bean2.DeploymentWindowState{
+		IsDeploymentAllowed: false,
+		BlockingWindows:     []*bean2.WindowSummary{{Id: 1, Name: "freeze", Type: bean2.WindowTypeBlock}},
+	}
+	getTriggerObj := func(runnerId int, overrideReason string) *bean.ValidateDeploymentTriggerObj {
+		return &bean.ValidateDeploymentTriggerObj{
+			Runner: &pipelineConfig.CdWorkflowRunner{Id: runnerId},
+			CdPipeline: &pipelineConfig.Pipeline{
+				Id: 1, AppId: 2, EnvironmentId: 3,
+				Environment: repository.Environment{Id: 3, ClusterId: 4},
+			},
+			TriggeredBy:                    5,
+			DeploymentWindowOverrideReason: overrideReason,
+		}
+	}
+	tests := []struct {
+		name           string
+		state          *bean2.DeploymentWindowState
+		isSuperAdmin   bool
+		overrideReason string
+		saveAuditErr   error
+		wantStatus     int
+		wantErr        bool
+		wantAudit      bool
+	}{
+		{name: "window open", state: &bean2.DeploymentWindowState{IsDeploymentAllowed: true}},
+		{name: "blocked", state: blockedState, wantErr: true, wantStatus: http.StatusForbidden},
+		{name: "override denied for non super admin", state: blockedState, overrideReason: "hotfix", wantErr: true, wantStatus: http.StatusForbidden},
+		{name: "override allowed for super admin", state: blockedState, isSuperAdmin: true, overrideReason: "hotfix", wantAudit: true},
+		{name: "override audit failure", state: blockedState, isSuperAdmin: true, overrideReason: "hotfix", saveAuditErr: errors.New("db down"), wantErr: true},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			windowService := &deploymentWindowServiceStub{state: tt.state, saveAuditErr: tt.saveAuditErr}
+			workflowService := &cdWorkflowCommonServiceStub{failedRunners: make(map[int]error)}
+			impl := &TriggerServiceImpl{
+				logger:                  logger,
+				deploymentWindowService: windowService,
+				cdWorkflowCommonService: workflowService,
+			}
+			ctx := globalUtil.SetSuperAdminInContext(context.Background(), tt.isSuperAdmin)
+			runnerId := i + 1
+			err := impl.validateDeploymentWindow(ctx, getTriggerObj(runnerId, tt.overrideReason))
+			if !tt.wantErr {
+				assert.Nil(t, err)
+				assert.Empty(t, workflowService.failedRunners)
+				assert.Equal(t, tt.wantAudit, len(windowService.savedAudits) == 1)
+				return
+			}
+			assert.NotNil(t, err)
+			assert.Equal(t, err, workflowService.failedRunners[runnerId], "runner should be marked failed with the trigger error")
+			if tt.wantStatus != 0 {
+				apiErr, ok := err.(*util.ApiError)
+				assert.True(t, ok)
+				assert.Equal(t, tt.wantStatus, apiErr.HttpStatusCode)
+			}
+			assert.Empty(t, windowService.savedAudits)
+		})
+	}
+}
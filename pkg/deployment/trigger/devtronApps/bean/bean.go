@@ -111,6 +111,8 @@ type ValidateDeploymentTriggerObj struct {
 	DeploymentConfig     *bean2.DeploymentConfig
 	TriggeredBy          int32
 	IsRollbackDeployment bool
+	// DeploymentWindowOverrideReason is set when a super admin deploys through an active deployment window
+	DeploymentWindowOverrideReason string
 }
 
 func (r *ValidateDeploymentTriggerObj) IsDeploymentTypeRollback() bool {
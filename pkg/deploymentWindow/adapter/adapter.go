@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adapter
+
+import (
+	"github.com/devtron-labs/devtron/pkg/deploymentWindow/bean"
+	"github.com/devtron-labs/devtron/pkg/deploymentWindow/repository"
+	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
+	"github.com/devtron-labs/devtron/pkg/sql"
+)
+
+func GetDeploymentWindowDbObject(dto *bean.DeploymentWindowDto) *repository.DeploymentWindow {
+	return &repository.DeploymentWindow{
+		Id:          dto.Id,
+		Name:        dto.Name,
+		Description: dto.Description,
+		Type:        string(dto.Type),
+		Frequency:   string(dto.Frequency),
+		StartTime:   dto.StartTime,
+		EndTime:     dto.EndTime,
+		TimeFrom:    dto.TimeFrom,
+		TimeTo:      dto.TimeTo,
+		WeekDays:    dto.WeekDays,
+		DayFrom:     dto.DayFrom,
+		DayTo:       dto.DayTo,
+		ValidFrom:   dto.ValidFrom,
+		ValidTill:   dto.ValidTill,
+		TimeZone:    dto.TimeZone,
+		Active:      true,
+	}
+}
+
+func GetDeploymentWindowDto(model *repository.DeploymentWindow) *bean.DeploymentWindowDto {
+	return &bean.DeploymentWindowDto{
+		Id:          model.Id,
+		Name:        model.Name,
+		Description: model.Description,
+		Type:        bean.WindowType(model.Type),
+		Frequency:   bean.Frequency(model.Frequency),
+		StartTime:   model.StartTime,
+		EndTime:     model.EndTime,
+		TimeFrom:    model.TimeFrom,
+		TimeTo:      model.TimeTo,
+		WeekDays:    model.WeekDays,
+		DayFrom:     model.DayFrom,
+		DayTo:       model.DayTo,
+		ValidFrom:   model.ValidFrom,
+		ValidTill:   model.ValidTill,
+		TimeZone:    model.TimeZone,
+		Scopes:      make([]bean.WindowScope, 0),
+	}
+}
+
+func GetQualifierMapping(windowId int, qualifier resourceQualifiers.Qualifier, identifierKey int, scope bean.WindowScope, userId int32) *resourceQualifiers.QualifierMapping {
+	return &resourceQualifiers.QualifierMapping{
+		ResourceId:            windowId,
+		ResourceType:          resourceQualifiers.DeploymentWindow,
+		QualifierId:           int(qualifier),
+		IdentifierKey:         identifierKey,
+		IdentifierValueInt:    scope.Id,
+		IdentifierValueString: scope.Name,
+		Active:                true,
+		AuditLog:              sql.NewDefaultAuditLog(userId),
+	}
+}
+
+func GetOverrideAuditDbObject(request *bean.OverrideAuditRequest) *repository.DeploymentWindowOverrideAudit {
+	windowIds := make([]int, 0)
+	if request.State != nil {
+		for _, window := range request.State.BlockingWindows {
+			windowIds = append(windowIds, window.Id)
+		}
+	}
+	audit := &repository.DeploymentWindowOverrideAudit{
+		PipelineId:         request.PipelineId,
+		AppId:              request.AppId,
+		EnvId:              request.EnvId,
+		CdWorkflowRunnerId: request.CdWorkflowRunnerId,
+		WindowIds:          windowIds,
+		Reason:             request.Reason,
+	}
+	audit.CreateAuditLog(request.UserId)
+	return audit
+}
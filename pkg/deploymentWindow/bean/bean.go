@@ -0,0 +1,181 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type WindowType string
+
+const (
+	// WindowTypeAllow permits deployments only while the window is open
+	WindowTypeAllow WindowType = "ALLOW"
+	// WindowTypeBlock blocks deployments while the window is open (freeze/blackout)
+	WindowTypeBlock WindowType = "BLOCK"
+)
+
+func (t WindowType) IsValid() bool {
+	return t == WindowTypeAllow || t == WindowTypeBlock
+}
+
+type Frequency string
+
+const (
+	FrequencyOnce    Frequency = "ONCE"
+	FrequencyDaily   Frequency = "DAILY"
+	FrequencyWeekly  Frequency = "WEEKLY"
+	FrequencyMonthly Frequency = "MONTHLY"
+)
+
+func (f Frequency) IsValid() bool {
+	switch f {
+	case FrequencyOnce, FrequencyDaily, FrequencyWeekly, FrequencyMonthly:
+		return true
+	}
+	return false
+}
+
+func (f Frequency) IsRecurring() bool {
+	return f == FrequencyDaily || f == FrequencyWeekly || f == FrequencyMonthly
+}
+
+type ScopeType string
+
+const (
+	ScopeGlobal  ScopeType = "GLOBAL"
+	ScopeCluster ScopeType = "CLUSTER"
+	ScopeEnv     ScopeType = "ENV"
+	ScopeApp     ScopeType = "APP"
+)
+
+const (
+	// HourMinuteLayout is the layout used for time of day in recurring windows
+	HourMinuteLayout = "15:04"
+	// EvaluationHorizon is how far ahead the next open window is searched for
+	EvaluationHorizon = 62 * 24 * time.Hour
+)
+
+const (
+	InvalidWindowName      = "deployment window name is required"
+	InvalidWindowType      = "invalid deployment window type, supported types are ALLOW and BLOCK"
+	InvalidWindowFrequency = "invalid frequency, supported values are ONCE, DAILY, WEEKLY and MONTHLY"
+	InvalidOneOffWindow    = "startTime and endTime are required and startTime must be before endTime for a one-off window"
+	InvalidRecurringTime   = "timeFrom and timeTo are required in HH:MM format for a recurring window"
+	InvalidWeekDays        = "weekDays are required for a weekly window and must be between 0 (Sunday) and 6 (Saturday)"
+	InvalidMonthDays       = "dayFrom and dayTo must be between 1 and 31 for a monthly window"
+	InvalidTimeZone        = "invalid timeZone"
+	InvalidWindowScope     = "at least one valid scope is required, supported scope types are GLOBAL, CLUSTER, ENV and APP"
+	OverrideReasonRequired = "a reason is required to override an active deployment window"
+	OverrideNotPermitted   = "only super admins can override an active deployment window"
+)
+
+type WindowScope struct {
+	Type ScopeType `json:"type"`
+	// Id is the cluster, environment or app id depending on Type; unused for GLOBAL
+	Id   int    `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+type DeploymentWindowDto struct {
+	Id          int        `json:"id"`
+	Name        string     `json:"name" validate:"required,max=100"`
+	Description string     `json:"description"`
+	Type        WindowType `json:"type"`
+	Frequency   Frequency  `json:"frequency"`
+	// StartTime and EndTime bound a one-off window
+	StartTime *time.Time `json:"startTime,omitempty"`
+	EndTime   *time.Time `json:"endTime,omitempty"`
+	// TimeFrom and TimeTo are HH:MM times of day for recurring windows, TimeTo before TimeFrom spans midnight
+	TimeFrom string `json:"timeFrom,omitempty"`
+	TimeTo   string `json:"timeTo,omitempty"`
+	// WeekDays are the days a weekly window opens on, 0 is Sunday
+	WeekDays []int `json:"weekDays,omitempty"`
+	// DayFrom and DayTo bound a monthly window, DayTo before DayFrom spans into the next month
+	DayFrom int `json:"dayFrom,omitempty"`
+	DayTo   int `json:"dayTo,omitempty"`
+	// ValidFrom and ValidTill optionally bound the recurrence of a recurring window
+	ValidFrom *time.Time    `json:"validFrom,omitempty"`
+	ValidTill *time.Time    `json:"validTill,omitempty"`
+	TimeZone  string        `json:"timeZone"`
+	Scopes    []WindowScope `json:"scopes"`
+	UserId    int32         `json:"-"`
+}
+
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (i Interval) Contains(t time.Time) bool {
+	return !t.Before(i.Start) && t.Before(i.End)
+}
+
+type WindowSummary struct {
+	Id   int        `json:"id"`
+	Name string     `json:"name"`
+	Type WindowType `json:"type"`
+	// EndsAt is the time the currently open occurrence closes, if known
+	EndsAt *time.Time `json:"endsAt,omitempty"`
+}
+
+type DeploymentWindowState struct {
+	IsDeploymentAllowed bool `json:"isDeploymentAllowed"`
+	// BlockingWindows are the windows responsible for the current block
+	BlockingWindows []*WindowSummary `json:"blockingWindows,omitempty"`
+	// ActiveAllowWindows are the allow windows open right now
+	ActiveAllowWindows []*WindowSummary `json:"activeAllowWindows,omitempty"`
+	// NextOpenAt is when deployments will be allowed again, nil when already allowed or nothing opens within the horizon
+	NextOpenAt *time.Time `json:"nextOpenAt,omitempty"`
+	// OpenUntil is when the current or next open period closes, nil when it stays open beyond the horizon
+	OpenUntil   *time.Time `json:"openUntil,omitempty"`
+	EvaluatedAt time.Time  `json:"evaluatedAt"`
+}
+
+func (state *DeploymentWindowState) GetBlockingWindowNames() []string {
+	names := make([]string, 0, len(state.BlockingWindows))
+	for _, window := range state.BlockingWindows {
+		names = append(names, window.Name)
+	}
+	return names
+}
+
+func (state *DeploymentWindowState) GetBlockedMessage() string {
+	message := fmt.Sprintf("deployment blocked by deployment window(s) %s", strings.Join(state.GetBlockingWindowNames(), ", "))
+	if state.NextOpenAt != nil {
+		message = fmt.Sprintf("%s, deployments are allowed again from %s", message, state.NextOpenAt.Format(time.RFC3339))
+	}
+	return message
+}
+
+type DeploymentWindowStateRequest struct {
+	AppId     int
+	EnvId     int
+	ClusterId int
+}
+
+type OverrideAuditRequest struct {
+	PipelineId         int
+	AppId              int
+	EnvId              int
+	CdWorkflowRunnerId int
+	Reason             string
+	State              *DeploymentWindowState
+	UserId             int32
+}
@@ -0,0 +1,334 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentWindow
+
+import (
+	"fmt"
+	repository2 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/deploymentWindow/adapter"
+	"github.com/devtron-labs/devtron/pkg/deploymentWindow/bean"
+	"github.com/devtron-labs/devtron/pkg/deploymentWindow/repository"
+	bean2 "github.com/devtron-labs/devtron/pkg/devtronResource/bean"
+	"github.com/devtron-labs/devtron/pkg/devtronResource/read"
+	"github.com/devtron-labs/devtron/pkg/resourceQualifiers"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+type DeploymentWindowService interface {
+	CreateDeploymentWindow(request *bean.DeploymentWindowDto) (*bean.DeploymentWindowDto, error)
+	UpdateDeploymentWindow(request *bean.DeploymentWindowDto) (*bean.DeploymentWindowDto, error)
+	DeleteDeploymentWindow(id int, userId int32) error
+	GetDeploymentWindowById(id int) (*bean.DeploymentWindowDto, error)
+	GetAllDeploymentWindows() ([]*bean.DeploymentWindowDto, error)
+
+	// GetDeploymentWindowState evaluates all windows applicable to the app/env/cluster at the given time
+	GetDeploymentWindowState(request *bean.DeploymentWindowStateRequest, evaluationTime time.Time) (*bean.DeploymentWindowState, error)
+	// GetDeploymentWindowStateForAppEnv resolves the cluster of the environment and evaluates the state at the current time
+	GetDeploymentWindowStateForAppEnv(appId, envId int) (*bean.DeploymentWindowState, error)
+	// SaveOverrideAudit records a break-glass deployment performed while a window was blocking
+	SaveOverrideAudit(request *bean.OverrideAuditRequest) error
+}
+
+type DeploymentWindowServiceImpl struct {
+	logger                       *zap.SugaredLogger
+	deploymentWindowRepository   repository.DeploymentWindowRepository
+	qualifierMappingService      resourceQualifiers.QualifierMappingService
+	devtronResourceSearchableKey read.DevtronResourceSearchableKeyService
+	environmentRepository        repository2.EnvironmentRepository
+}
+
+func NewDeploymentWindowServiceImpl(logger *zap.SugaredLogger,
+	deploymentWindowRepository repository.DeploymentWindowRepository,
+	qualifierMappingService resourceQualifiers.QualifierMappingService,
+	devtronResourceSearchableKey read.DevtronResourceSearchableKeyService,
+	environmentRepository repository2.EnvironmentRepository) *DeploymentWindowServiceImpl {
+	return &DeploymentWindowServiceImpl{
+		logger:                       logger,
+		deploymentWindowRepository:   deploymentWindowRepository,
+		qualifierMappingService:      qualifierMappingService,
+		devtronResourceSearchableKey: devtronResourceSearchableKey,
+		environmentRepository:        environmentRepository,
+	}
+}
+
+func (impl *DeploymentWindowServiceImpl) CreateDeploymentWindow(request *bean.DeploymentWindowDto) (*bean.DeploymentWindowDto, error) {
+	request.Name = strings.TrimSpace(request.Name)
+	if err := ValidateDeploymentWindow(request); err != nil {
+		return nil, err
+	}
+	tx, err := impl.deploymentWindowRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer impl.deploymentWindowRepository.RollbackTx(tx)
+
+	window := adapter.GetDeploymentWindowDbObject(request)
+	window.CreateAuditLog(request.UserId)
+	if err = impl.deploymentWindowRepository.Save(tx, window); err != nil {
+		impl.logger.Errorw("error in saving deployment window", "name", request.Name, "err", err)
+		return nil, err
+	}
+	if err = impl.createScopeMappings(tx, window.Id, request.Scopes, request.UserId); err != nil {
+		return nil, err
+	}
+	if err = impl.deploymentWindowRepository.CommitTx(tx); err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	request.Id = window.Id
+	return request, nil
+}
+
+func (impl *DeploymentWindowServiceImpl) UpdateDeploymentWindow(request *bean.DeploymentWindowDto) (*bean.DeploymentWindowDto, error) {
+	request.Name = strings.TrimSpace(request.Name)
+	if err := ValidateDeploymentWindow(request); err != nil {
+		return nil, err
+	}
+	existing, err := impl.deploymentWindowRepository.FindById(request.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment window", "id", request.Id, "err", err)
+		return nil, err
+	}
+	tx, err := impl.deploymentWindowRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return nil, err
+	}
+	defer impl.deploymentWindowRepository.RollbackTx(tx)
+
+	window := adapter.GetDeploymentWindowDbObject(request)
+	window.AuditLog = existing.AuditLog
+	window.UpdateAuditLog(request.UserId)
+	if err = impl.deploymentWindowRepository.Update(tx, window); err != nil {
+		impl.logger.Errorw("error in updating deployment window", "id", request.Id, "err", err)
+		return nil, err
+	}
+	if err = impl.deleteScopeMappings(tx, window.Id, request.UserId); err != nil {
+		return nil, err
+	}
+	if err = impl.createScopeMappings(tx, window.Id, request.Scopes, request.UserId); err != nil {
+		return nil, err
+	}
+	if err = impl.deploymentWindowRepository.CommitTx(tx); err != nil {
+		impl.logger.Errorw("error in committing transaction", "err", err)
+		return nil, err
+	}
+	return request, nil
+}
+
+func (impl *DeploymentWindowServiceImpl) DeleteDeploymentWindow(id int, userId int32) error {
+	window, err := impl.deploymentWindowRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment window", "id", id, "err", err)
+		return err
+	}
+	tx, err := impl.deploymentWindowRepository.StartTx()
+	if err != nil {
+		impl.logger.Errorw("error in starting transaction", "err", err)
+		return err
+	}
+	defer impl.deploymentWindowRepository.RollbackTx(tx)
+
+	window.Active = false
+	window.UpdateAuditLog(userId)
+	if err = impl.deploymentWindowRepository.Update(tx, window); err != nil {
+		impl.logger.Errorw("error in deleting deployment window", "id", id, "err", err)
+		return err
+	}
+	if err = impl.deleteScopeMappings(tx, id, userId); err != nil {
+		return err
+	}
+	return impl.deploymentWindowRepository.CommitTx(tx)
+}
+
+func (impl *DeploymentWindowServiceImpl) GetDeploymentWindowById(id int) (*bean.DeploymentWindowDto, error) {
+	window, err := impl.deploymentWindowRepository.FindById(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment window", "id", id, "err", err)
+		return nil, err
+	}
+	windows, err := impl.enrichScopes([]*repository.DeploymentWindow{window})
+	if err != nil {
+		return nil, err
+	}
+	return windows[0], nil
+}
+
+func (impl *DeploymentWindowServiceImpl) GetAllDeploymentWindows() ([]*bean.DeploymentWindowDto, error) {
+	windows, err := impl.deploymentWindowRepository.FindAllActive()
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment windows", "err", err)
+		return nil, err
+	}
+	return impl.enrichScopes(windows)
+}
+
+func (impl *DeploymentWindowServiceImpl) GetDeploymentWindowStateForAppEnv(appId, envId int) (*bean.DeploymentWindowState, error) {
+	env, err := impl.environmentRepository.FindById(envId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environment", "envId", envId, "err", err)
+		return nil, err
+	}
+	request := &bean.DeploymentWindowStateRequest{
+		AppId:     appId,
+		EnvId:     envId,
+		ClusterId: env.ClusterId,
+	}
+	return impl.GetDeploymentWindowState(request, time.Now())
+}
+
+func (impl *DeploymentWindowServiceImpl) GetDeploymentWindowState(request *bean.DeploymentWindowStateRequest, evaluationTime time.Time) (*bean.DeploymentWindowState, error) {
+	mappings, err := impl.qualifierMappingService.GetQualifierMappings(resourceQualifiers.DeploymentWindow, nil, nil)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching deployment window mappings", "err", err)
+		return nil, err
+	}
+	windowIds := make([]int, 0)
+	for _, mapping := range mappings {
+		if impl.isMappingApplicable(mapping, request) {
+			windowIds = append(windowIds, mapping.ResourceId)
+		}
+	}
+	windows, err := impl.deploymentWindowRepository.FindActiveByIds(windowIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching deployment windows", "windowIds", windowIds, "err", err)
+		return nil, err
+	}
+	windowDtos := make([]*bean.DeploymentWindowDto, 0, len(windows))
+	for _, window := range windows {
+		windowDtos = append(windowDtos, adapter.GetDeploymentWindowDto(window))
+	}
+	state, err := EvaluateDeploymentWindowState(windowDtos, evaluationTime)
+	if err != nil {
+		impl.logger.Errorw("error in evaluating deployment window state", "request", request, "err", err)
+		return nil, err
+	}
+	return state, nil
+}
+
+func (impl *DeploymentWindowServiceImpl) SaveOverrideAudit(request *bean.OverrideAuditRequest) error {
+	err := impl.deploymentWindowRepository.SaveOverrideAudit(adapter.GetOverrideAuditDbObject(request))
+	if err != nil {
+		impl.logger.Errorw("error in saving deployment window override audit", "pipelineId", request.PipelineId, "err", err)
+	}
+	return err
+}
+
+func (impl *DeploymentWindowServiceImpl) isMappingApplicable(mapping *resourceQualifiers.QualifierMapping, request *bean.DeploymentWindowStateRequest) bool {
+	switch resourceQualifiers.Qualifier(mapping.QualifierId) {
+	case resourceQualifiers.GLOBAL_QUALIFIER:
+		return true
+	case resourceQualifiers.CLUSTER_QUALIFIER:
+		return mapping.IdentifierValueInt == request.ClusterId
+	case resourceQualifiers.ENV_QUALIFIER:
+		return mapping.IdentifierValueInt == request.EnvId
+	case resourceQualifiers.APP_QUALIFIER:
+		return mapping.IdentifierValueInt == request.AppId
+	}
+	return false
+}
+
+func (impl *DeploymentWindowServiceImpl) createScopeMappings(tx *pg.Tx, windowId int, scopes []bean.WindowScope, userId int32) error {
+	searchableKeyMap := impl.devtronResourceSearchableKey.GetAllSearchableKeyNameIdMap()
+	mappings := make([]*resourceQualifiers.QualifierMapping, 0, len(scopes))
+	for _, scope := range scopes {
+		var mapping *resourceQualifiers.QualifierMapping
+		switch scope.Type {
+		case bean.ScopeGlobal:
+			mapping = adapter.GetQualifierMapping(windowId, resourceQualifiers.GLOBAL_QUALIFIER, 0, bean.WindowScope{}, userId)
+		case bean.ScopeCluster:
+			mapping = adapter.GetQualifierMapping(windowId, resourceQualifiers.CLUSTER_QUALIFIER, searchableKeyMap[bean2.DEVTRON_RESOURCE_SEARCHABLE_KEY_CLUSTER_ID], scope, userId)
+		case bean.ScopeEnv:
+			mapping = adapter.GetQualifierMapping(windowId, resourceQualifiers.ENV_QUALIFIER, searchableKeyMap[bean2.DEVTRON_RESOURCE_SEARCHABLE_KEY_ENV_ID], scope, userId)
+		case bean.ScopeApp:
+			mapping = adapter.GetQualifierMapping(windowId, resourceQualifiers.APP_QUALIFIER, searchableKeyMap[bean2.DEVTRON_RESOURCE_SEARCHABLE_KEY_APP_ID], scope, userId)
+		default:
+			return fmt.Errorf("unsupported deployment window scope type %s", scope.Type)
+		}
+		mappings = append(mappings, mapping)
+	}
+	_, err := impl.qualifierMappingService.CreateQualifierMappings(mappings, tx)
+	if err != nil {
+		impl.logger.Errorw("error in creating deployment window scope mappings", "windowId", windowId, "err", err)
+	}
+	return err
+}
+
+func (impl *DeploymentWindowServiceImpl) deleteScopeMappings(tx *pg.Tx, windowId int, userId int32) error {
+	mappings, err := impl.qualifierMappingService.GetQualifierMappings(resourceQualifiers.DeploymentWindow, nil, []int{windowId})
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching deployment window mappings", "windowId", windowId, "err", err)
+		return err
+	}
+	if len(mappings) == 0 {
+		return nil
+	}
+	mappingIds := make([]int, 0, len(mappings))
+	for _, mapping := range mappings {
+		mappingIds = append(mappingIds, mapping.Id)
+	}
+	err = impl.qualifierMappingService.DeleteAllByIds(mappingIds, userId, tx)
+	if err != nil {
+		impl.logger.Errorw("error in deleting deployment window mappings", "windowId", windowId, "err", err)
+	}
+	return err
+}
+
+func (impl *DeploymentWindowServiceImpl) enrichScopes(windows []*repository.DeploymentWindow) ([]*bean.DeploymentWindowDto, error) {
+	windowDtos := make([]*bean.DeploymentWindowDto, 0, len(windows))
+	if len(windows) == 0 {
+		return windowDtos, nil
+	}
+	windowIds := make([]int, 0, len(windows))
+	for _, window := range windows {
+		windowIds = append(windowIds, window.Id)
+	}
+	mappings, err := impl.qualifierMappingService.GetQualifierMappings(resourceQualifiers.DeploymentWindow, nil, windowIds)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching deployment window mappings", "windowIds", windowIds, "err", err)
+		return nil, err
+	}
+	windowIdToScopes := make(map[int][]bean.WindowScope)
+	for _, mapping := range mappings {
+		scope := bean.WindowScope{Id: mapping.IdentifierValueInt, Name: mapping.IdentifierValueString}
+		switch resourceQualifiers.Qualifier(mapping.QualifierId) {
+		case resourceQualifiers.GLOBAL_QUALIFIER:
+			scope = bean.WindowScope{Type: bean.ScopeGlobal}
+		case resourceQualifiers.CLUSTER_QUALIFIER:
+			scope.Type = bean.ScopeCluster
+		case resourceQualifiers.ENV_QUALIFIER:
+			scope.Type = bean.ScopeEnv
+		case resourceQualifiers.APP_QUALIFIER:
+			scope.Type = bean.ScopeApp
+		default:
+			continue
+		}
+		windowIdToScopes[mapping.ResourceId] = append(windowIdToScopes[mapping.ResourceId], scope)
+	}
+	for _, window := range windows {
+		windowDto := adapter.GetDeploymentWindowDto(window)
+		if scopes, ok := windowIdToScopes[window.Id]; ok {
+			windowDto.Scopes = scopes
+		}
+		windowDtos = append(windowDtos, windowDto)
+	}
+	return windowDtos, nil
+}
@@ -0,0 +1,337 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentWindow
+
+import (
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/deploymentWindow/bean"
+	"golang.org/x/exp/slices"
+	"net/http"
+	"sort"
+	"time"
+)
+
+func newValidationError(message string) *util.ApiError {
+	return util.NewApiError(http.StatusBadRequest, message, message)
+}
+
+func ValidateDeploymentWindow(window *bean.DeploymentWindowDto) error {
+	if len(window.Name) == 0 {
+		return newValidationError(bean.InvalidWindowName)
+	}
+	if !window.Type.IsValid() {
+		return newValidationError(bean.InvalidWindowType)
+	}
+	if !window.Frequency.IsValid() {
+		return newValidationError(bean.InvalidWindowFrequency)
+	}
+	if _, err := getLocation(window.TimeZone); err != nil {
+		return newValidationError(bean.InvalidTimeZone)
+	}
+	if len(window.Scopes) == 0 {
+		return newValidationError(bean.InvalidWindowScope)
+	}
+	for _, scope := range window.Scopes {
+		switch scope.Type {
+		case bean.ScopeGlobal:
+		case bean.ScopeCluster, bean.ScopeEnv, bean.ScopeApp:
+			if scope.Id <= 0 {
+				return newValidationError(bean.InvalidWindowScope)
+			}
+		default:
+			return newValidationError(bean.InvalidWindowScope)
+		}
+	}
+	if window.Frequency == bean.FrequencyOnce {
+		if window.StartTime == nil || window.EndTime == nil || !window.EndTime.After(*window.StartTime) {
+			return newValidationError(bean.InvalidOneOffWindow)
+		}
+		return nil
+	}
+	if _, _, err := parseHourMinute(window.TimeFrom); err != nil {
+		return newValidationError(bean.InvalidRecurringTime)
+	}
+	if _, _, err := parseHourMinute(window.TimeTo); err != nil {
+		return newValidationError(bean.InvalidRecurringTime)
+	}
+	switch window.Frequency {
+	case bean.FrequencyWeekly:
+		if len(window.WeekDays) == 0 {
+			return newValidationError(bean.InvalidWeekDays)
+		}
+		for _, day := range window.WeekDays {
+			if day < int(time.Sunday) || day > int(time.Saturday) {
+				return newValidationError(bean.InvalidWeekDays)
+			}
+		}
+	case bean.FrequencyMonthly:
+		if window.DayFrom < 1 || window.DayFrom > 31 || window.DayTo < 1 || window.DayTo > 31 {
+			return newValidationError(bean.InvalidMonthDays)
+		}
+	}
+	return nil
+}
+
+func getLocation(timeZone string) (*time.Location, error) {
+	if len(timeZone) == 0 {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(timeZone)
+}
+
+func parseHourMinute(value string) (int, int, error) {
+	parsed, err := time.Parse(bean.HourMinuteLayout, value)
+	if err != nil {
+		return 0, 0, err
+	}
+	return parsed.Hour(), parsed.Minute(), nil
+}
+
+func daysInMonth(year int, month time.Month, loc *time.Location) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, loc).Day()
+}
+
+func clampDay(day, year int, month time.Month, loc *time.Location) int {
+	if maxDay := daysInMonth(year, month, loc); day > maxDay {
+		return maxDay
+	}
+	return day
+}
+
+// GetIntervals expands a window into the concrete occurrences overlapping [from, to)
+func GetIntervals(window *bean.DeploymentWindowDto, from, to time.Time) ([]bean.Interval, error) {
+	loc, err := getLocation(window.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+	intervals := make([]bean.Interval, 0)
+	if window.Frequency == bean.FrequencyOnce {
+		if window.StartTime != nil && window.EndTime != nil {
+			intervals = append(intervals, bean.Interval{Start: *window.StartTime, End: *window.EndTime})
+		}
+		return filterOverlapping(intervals, from, to), nil
+	}
+	hourFrom, minuteFrom, err := parseHourMinute(window.TimeFrom)
+	if err != nil {
+		return nil, err
+	}
+	hourTo, minuteTo, err := parseHourMinute(window.TimeTo)
+	if err != nil {
+		return nil, err
+	}
+	switch window.Frequency {
+	case bean.FrequencyDaily, bean.FrequencyWeekly:
+		fromInLoc := from.In(loc)
+		day := time.Date(fromInLoc.Year(), fromInLoc.Month(), fromInLoc.Day()-1, 0, 0, 0, 0, loc)
+		for !day.After(to) {
+			if window.Frequency == bean.FrequencyDaily || slices.Contains(window.WeekDays, int(day.Weekday())) {
+				start := time.Date(day.Year(), day.Month(), day.Day(), hourFrom, minuteFrom, 0, 0, loc)
+				end := time.Date(day.Year(), day.Month(), day.Day(), hourTo, minuteTo, 0, 0, loc)
+				if !end.After(start) {
+					end = time.Date(day.Year(), day.Month(), day.Day()+1, hourTo, minuteTo, 0, 0, loc)
+				}
+				intervals = append(intervals, bean.Interval{Start: start, End: end})
+			}
+			day = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc)
+		}
+	case bean.FrequencyMonthly:
+		fromInLoc := from.In(loc)
+		month := time.Date(fromInLoc.Year(), fromInLoc.Month()-1, 1, 0, 0, 0, 0, loc)
+		for !month.After(to) {
+			startDay := clampDay(window.DayFrom, month.Year(), month.Month(), loc)
+			start := time.Date(month.Year(), month.Month(), startDay, hourFrom, minuteFrom, 0, 0, loc)
+			endMonth := month
+			if window.DayTo < window.DayFrom {
+				endMonth = time.Date(month.Year(), month.Month()+1, 1, 0, 0, 0, 0, loc)
+			}
+			endDay := clampDay(window.DayTo, endMonth.Year(), endMonth.Month(), loc)
+			end := time.Date(endMonth.Year(), endMonth.Month(), endDay, hourTo, minuteTo, 0, 0, loc)
+			if !end.After(start) {
+				nextMonth := time.Date(endMonth.Year(), endMonth.Month()+1, 1, 0, 0, 0, 0, loc)
+				endDay = clampDay(window.DayTo, nextMonth.Year(), nextMonth.Month(), loc)
+				end = time.Date(nextMonth.Year(), nextMonth.Month(), endDay, hourTo, minuteTo, 0, 0, loc)
+			}
+			intervals = append(intervals, bean.Interval{Start: start, End: end})
+			month = time.Date(month.Year(), month.Month()+1, 1, 0, 0, 0, 0, loc)
+		}
+	}
+	intervals = clipToValidity(intervals, window.ValidFrom, window.ValidTill)
+	return filterOverlapping(intervals, from, to), nil
+}
+
+func clipToValidity(intervals []bean.Interval, validFrom, validTill *time.Time) []bean.Interval {
+	clipped := make([]bean.Interval, 0, len(intervals))
+	for _, interval := range intervals {
+		if validFrom != nil && interval.Start.Before(*validFrom) {
+			interval.Start = *validFrom
+		}
+		if validTill != nil && interval.End.After(*validTill) {
+			interval.End = *validTill
+		}
+		if interval.End.After(interval.Start) {
+			clipped = append(clipped, interval)
+		}
+	}
+	return clipped
+}
+
+func filterOverlapping(intervals []bean.Interval, from, to time.Time) []bean.Interval {
+	filtered := make([]bean.Interval, 0, len(intervals))
+	for _, interval := range intervals {
+		if interval.End.After(from) && interval.Start.Before(to) {
+			filtered = append(filtered, interval)
+		}
+	}
+	return filtered
+}
+
+// getExpiry returns the time after which a window can never open again, nil if it recurs indefinitely
+func getExpiry(window *bean.DeploymentWindowDto) *time.Time {
+	if window.Frequency == bean.FrequencyOnce {
+		return window.EndTime
+	}
+	return window.ValidTill
+}
+
+type windowOccurrences struct {
+	window    *bean.DeploymentWindowDto
+	intervals []bean.Interval
+}
+
+func (o *windowOccurrences) activeInterval(t time.Time) *bean.Interval {
+	for i := range o.intervals {
+		if o.intervals[i].Contains(t) {
+			return &o.intervals[i]
+		}
+	}
+	return nil
+}
+
+// isLive reports whether the window is in effect at t, between its validity start and its expiry
+func (o *windowOccurrences) isLive(t time.Time) bool {
+	if o.window.ValidFrom != nil && t.Before(*o.window.ValidFrom) {
+		return false
+	}
+	expiry := getExpiry(o.window)
+	return expiry == nil || t.Before(*expiry)
+}
+
+func isAllowedAt(occurrences []*windowOccurrences, t time.Time) bool {
+	allowRequired, allowActive := false, false
+	for _, occurrence := range occurrences {
+		active := occurrence.activeInterval(t) != nil
+		switch occurrence.window.Type {
+		case bean.WindowTypeBlock:
+			if active {
+				return false
+			}
+		case bean.WindowTypeAllow:
+			if occurrence.isLive(t) {
+				allowRequired = true
+			}
+			allowActive = allowActive || active
+		}
+	}
+	return !allowRequired || allowActive
+}
+
+func newWindowSummary(window *bean.DeploymentWindowDto, interval *bean.Interval) *bean.WindowSummary {
+	summary := &bean.WindowSummary{
+		Id:   window.Id,
+		Name: window.Name,
+		Type: window.Type,
+	}
+	if interval != nil {
+		endsAt := interval.End
+		summary.EndsAt = &endsAt
+	}
+	return summary
+}
+
+// EvaluateDeploymentWindowState computes whether deployments are allowed at now for the given applicable windows.
+// A deployment is blocked while any BLOCK window is open, or when ALLOW windows exist and none of them is open.
+func EvaluateDeploymentWindowState(windows []*bean.DeploymentWindowDto, now time.Time) (*bean.DeploymentWindowState, error) {
+	horizon := now.Add(bean.EvaluationHorizon)
+	occurrences := make([]*windowOccurrences, 0, len(windows))
+	boundaries := []time.Time{now}
+	for _, window := range windows {
+		intervals, err := GetIntervals(window, now, horizon)
+		if err != nil {
+			return nil, err
+		}
+		occurrences = append(occurrences, &windowOccurrences{window: window, intervals: intervals})
+		for _, interval := range intervals {
+			boundaries = append(boundaries, interval.Start, interval.End)
+		}
+		if window.ValidFrom != nil {
+			boundaries = append(boundaries, *window.ValidFrom)
+		}
+		if expiry := getExpiry(window); expiry != nil {
+			boundaries = append(boundaries, *expiry)
+		}
+	}
+	sort.Slice(boundaries, func(i, j int) bool {
+		return boundaries[i].Before(boundaries[j])
+	})
+
+	state := &bean.DeploymentWindowState{
+		IsDeploymentAllowed: isAllowedAt(occurrences, now),
+		EvaluatedAt:         now,
+	}
+	var closedAllowWindows []*bean.WindowSummary
+	for _, occurrence := range occurrences {
+		interval := occurrence.activeInterval(now)
+		switch occurrence.window.Type {
+		case bean.WindowTypeBlock:
+			if interval != nil {
+				state.BlockingWindows = append(state.BlockingWindows, newWindowSummary(occurrence.window, interval))
+			}
+		case bean.WindowTypeAllow:
+			if interval != nil {
+				state.ActiveAllowWindows = append(state.ActiveAllowWindows, newWindowSummary(occurrence.window, interval))
+			} else if occurrence.isLive(now) {
+				closedAllowWindows = append(closedAllowWindows, newWindowSummary(occurrence.window, nil))
+			}
+		}
+	}
+	// open block windows take precedence, otherwise deployments are blocked only because every live allow window is closed
+	if !state.IsDeploymentAllowed && len(state.BlockingWindows) == 0 {
+		state.BlockingWindows = closedAllowWindows
+	}
+
+	openFrom := now
+	if !state.IsDeploymentAllowed {
+		nextOpen := findNextBoundary(boundaries, now, func(t time.Time) bool { return isAllowedAt(occurrences, t) })
+		if nextOpen == nil {
+			return state, nil
+		}
+		state.NextOpenAt = nextOpen
+		openFrom = *nextOpen
+	}
+	state.OpenUntil = findNextBoundary(boundaries, openFrom, func(t time.Time) bool { return !isAllowedAt(occurrences, t) })
+	return state, nil
+}
+
+func findNextBoundary(boundaries []time.Time, after time.Time, matches func(t time.Time) bool) *time.Time {
+	for _, boundary := range boundaries {
+		if boundary.After(after) && matches(boundary) {
+			next := boundary
+			return &next
+		}
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deploymentWindow
+
+import (
+	"github.com/devtron-labs/devtron/pkg/deploymentWindow/bean"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetIntervals(t *testing.T) {
+	from := time.Date(2024, time.March, 14, 12, 0, 0, 0, time.UTC) // Thursday
+	to := from.Add(7 * 24 * time.Hour)
+	tests := []struct {
+		name      string
+		window    *bean.DeploymentWindowDto
+		wantCount int
+		wantFirst bean.Interval
+	}{
+		{
+			name: "Test1_DailyWindowSpanningMidnight",
+			window: &bean.DeploymentWindowDto{
+				Frequency: bean.FrequencyDaily,
+				TimeFrom:  "22:00",
+				TimeTo:    "02:00",
+			},
+			wantCount: 7,
+			wantFirst: bean.Interval{
+				Start: time.Date(2024, time.March, 14, 22, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, time.March, 15, 2, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "Test2_WeeklyWindowOnWeekends",
+			window: &bean.DeploymentWindowDto{
+				Frequency: bean.FrequencyWeekly,
+				TimeFrom:  "00:00",
+				TimeTo:    "23:59",
+				WeekDays:  []int{int(time.Saturday), int(time.Sunday)},
+			},
+			wantCount: 2,
+			wantFirst: bean.Interval{
+				Start: time.Date(2024, time.March, 16, 0, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, time.March, 16, 23, 59, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "Test3_MonthlyWindowOutsideRange",
+			window: &bean.DeploymentWindowDto{
+				Frequency: bean.FrequencyMonthly,
+				TimeFrom:  "00:00",
+				TimeTo:    "00:00",
+				DayFrom:   31,
+				DayTo:     2,
+			},
+			wantCount: 0,
+		},
+		{
+			name: "Test4_DailyWindowInTimeZoneWithValidity",
+			window: &bean.DeploymentWindowDto{
+				Frequency: bean.FrequencyDaily,
+				TimeFrom:  "09:00",
+				TimeTo:    "10:00",
+				TimeZone:  "Asia/Kolkata",
+				ValidTill: timePtr(time.Date(2024, time.March, 16, 0, 0, 0, 0, time.UTC)),
+			},
+			wantCount: 1,
+			wantFirst: bean.Interval{
+				Start: time.Date(2024, time.March, 15, 3, 30, 0, 0, time.UTC),
+				End:   time.Date(2024, time.March, 15, 4, 30, 0, 0, time.UTC),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetIntervals(tt.window, from, to)
+			if err != nil {
+				t.Fatalf("GetIntervals() error = %v", err)
+			}
+			if len(got) != tt.wantCount {
+				t.Fatalf("GetIntervals() returned %d intervals, want %d", len(got), tt.wantCount)
+			}
+			if tt.wantCount > 0 && (!got[0].Start.Equal(tt.wantFirst.Start) || !got[0].End.Equal(tt.wantFirst.End)) {
+				t.Errorf("GetIntervals() first interval = %v, want %v", got[0], tt.wantFirst)
+			}
+		})
+	}
+}
+
+func TestEvaluateDeploymentWindowState(t *testing.T) {
+	now := time.Date(2024, time.March, 14, 12, 0, 0, 0, time.UTC)
+	freeze := &bean.DeploymentWindowDto{
+		Id:        1,
+		Name:      "release-freeze",
+		Type:      bean.WindowTypeBlock,
+		Frequency: bean.FrequencyOnce,
+		StartTime: timePtr(now.Add(-time.Hour)),
+		EndTime:   timePtr(now.Add(2 * time.Hour)),
+	}
+	businessHours := &bean.DeploymentWindowDto{
+		Id:        2,
+		Name:      "business-hours",
+		Type:      bean.WindowTypeAllow,
+		Frequency: bean.FrequencyDaily,
+		TimeFrom:  "14:00",
+		TimeTo:    "18:00",
+	}
+	eveningHours := &bean.DeploymentWindowDto{
+		Id:        3,
+		Name:      "evening-hours",
+		Type:      bean.WindowTypeAllow,
+		Frequency: bean.FrequencyDaily,
+		TimeFrom:  "20:00",
+		TimeTo:    "22:00",
+	}
+	upcomingBusinessHours := &bean.DeploymentWindowDto{
+		Id:        4,
+		Name:      "upcoming-business-hours",
+		Type:      bean.WindowTypeAllow,
+		Frequency: bean.FrequencyDaily,
+		TimeFrom:  "14:00",
+		TimeTo:    "18:00",
+		ValidFrom: timePtr(now.Add(24 * time.Hour)),
+	}
+	tests := []struct {
+		name           string
+		windows        []*bean.DeploymentWindowDto
+		wantAllowed    bool
+		wantBlockingBy []string
+		wantNextOpenAt *time.Time
+		wantOpenUntil  *time.Time
+	}{
+		{
+			name:        "Test1_NoWindows",
+			windows:     nil,
+			wantAllowed: true,
+		},
+		{
+			name:           "Test2_ActiveBlockWindow",
+			windows:        []*bean.DeploymentWindowDto{freeze},
+			wantAllowed:    false,
+			wantBlockingBy: []string{"release-freeze"},
+			wantNextOpenAt: timePtr(now.Add(2 * time.Hour)),
+		},
+		{
+			name:           "Test3_OutsideAllowWindow",
+			windows:        []*bean.DeploymentWindowDto{businessHours},
+			wantAllowed:    false,
+			wantBlockingBy: []string{"business-hours"},
+			wantNextOpenAt: timePtr(now.Add(2 * time.Hour)),
+			wantOpenUntil:  timePtr(now.Add(6 * time.Hour)),
+		},
+		{
+			name:           "Test4_BlockTakesPrecedenceOverAllow",
+			windows:        []*bean.DeploymentWindowDto{businessHours, freeze},
+			wantAllowed:    false,
+			wantBlockingBy: []string{"release-freeze"},
+			wantNextOpenAt: timePtr(now.Add(2 * time.Hour)),
+			wantOpenUntil:  timePtr(now.Add(6 * time.Hour)),
+		},
+		{
+			name:           "Test5_EveryClosedAllowWindowReported",
+			windows:        []*bean.DeploymentWindowDto{businessHours, eveningHours},
+			wantAllowed:    false,
+			wantBlockingBy: []string{"business-hours", "evening-hours"},
+			wantNextOpenAt: timePtr(now.Add(2 * time.Hour)),
+			wantOpenUntil:  timePtr(now.Add(6 * time.Hour)),
+		},
+		{
+			name:          "Test6_AllowWindowNotLiveBeforeValidFrom",
+			windows:       []*bean.DeploymentWindowDto{upcomingBusinessHours},
+			wantAllowed:   true,
+			wantOpenUntil: timePtr(now.Add(24 * time.Hour)),
+		},
+		{
+			name:           "Test7_AllowWindowLiveFromValidFrom",
+			windows:        []*bean.DeploymentWindowDto{upcomingBusinessHours, businessHours},
+			wantAllowed:    false,
+			wantBlockingBy: []string{"business-hours"},
+			wantNextOpenAt: timePtr(now.Add(2 * time.Hour)),
+			wantOpenUntil:  timePtr(now.Add(6 * time.Hour)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EvaluateDeploymentWindowState(tt.windows, now)
+			if err != nil {
+				t.Fatalf("EvaluateDeploymentWindowState() error = %v", err)
+			}
+			if got.IsDeploymentAllowed != tt.wantAllowed {
+				t.Errorf("IsDeploymentAllowed = %v, want %v", got.IsDeploymentAllowed, tt.wantAllowed)
+			}
+			if gotBlockingBy := got.GetBlockingWindowNames(); len(gotBlockingBy) != len(tt.wantBlockingBy) || (len(gotBlockingBy) > 0 && !reflect.DeepEqual(gotBlockingBy, tt.wantBlockingBy)) {
+				t.Errorf("BlockingWindows = %v, want %v", got.GetBlockingWindowNames(), tt.wantBlockingBy)
+			}
+			if !equalTimePtr(got.NextOpenAt, tt.wantNextOpenAt) {
+				t.Errorf("NextOpenAt = %v, want %v", got.NextOpenAt, tt.wantNextOpenAt)
+			}
+			if !equalTimePtr(got.OpenUntil, tt.wantOpenUntil) {
+				t.Errorf("OpenUntil = %v, want %v", got.OpenUntil, tt.wantOpenUntil)
+			}
+		})
+	}
+}
+
+func TestValidateDeploymentWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  *bean.DeploymentWindowDto
+		wantErr bool
+	}{
+		{
+			name: "Test1_ValidWeeklyWindow",
+			window: &bean.DeploymentWindowDto{
+				Name: "weekend", Type: bean.WindowTypeBlock, Frequency: bean.FrequencyWeekly,
+				TimeFrom: "00:00", TimeTo: "23:59", WeekDays: []int{0, 6},
+				Scopes: []bean.WindowScope{{Type: bean.ScopeGlobal}},
+			},
+		},
+		{
+			name: "Test2_InvalidWeekDay",
+			window: &bean.DeploymentWindowDto{
+				Name: "weekend", Type: bean.WindowTypeBlock, Frequency: bean.FrequencyWeekly,
+				TimeFrom: "00:00", TimeTo: "23:59", WeekDays: []int{7},
+				Scopes: []bean.WindowScope{{Type: bean.ScopeGlobal}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test3_OneOffEndBeforeStart",
+			window: &bean.DeploymentWindowDto{
+				Name: "freeze", Type: bean.WindowTypeBlock, Frequency: bean.FrequencyOnce,
+				StartTime: timePtr(time.Date(2024, time.March, 2, 0, 0, 0, 0, time.UTC)),
+				EndTime:   timePtr(time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)),
+				Scopes:    []bean.WindowScope{{Type: bean.ScopeApp, Id: 1}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Test4_MissingScope",
+			window: &bean.DeploymentWindowDto{
+				Name: "daily", Type: bean.WindowTypeAllow, Frequency: bean.FrequencyDaily,
+				TimeFrom: "09:00", TimeTo: "17:00",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateDeploymentWindow(tt.window); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDeploymentWindow() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func equalTimePtr(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
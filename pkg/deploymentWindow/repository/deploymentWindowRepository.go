@@ -0,0 +1,144 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type DeploymentWindow struct {
+	tableName   struct{}   `sql:"deployment_window" pg:",discard_unknown_columns"`
+	Id          int        `sql:"id,pk"`
+	Name        string     `sql:"name,notnull"`
+	Description string     `sql:"description"`
+	Type        string     `sql:"type,notnull"`
+	Frequency   string     `sql:"frequency,notnull"`
+	StartTime   *time.Time `sql:"start_time"`
+	EndTime     *time.Time `sql:"end_time"`
+	TimeFrom    string     `sql:"time_from"`
+	TimeTo      string     `sql:"time_to"`
+	WeekDays    []int      `sql:"week_days" pg:",array"`
+	DayFrom     int        `sql:"day_from"`
+	DayTo       int        `sql:"day_to"`
+	ValidFrom   *time.Time `sql:"valid_from"`
+	ValidTill   *time.Time `sql:"valid_till"`
+	TimeZone    string     `sql:"time_zone"`
+	Active      bool       `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type DeploymentWindowOverrideAudit struct {
+	tableName          struct{} `sql:"deployment_window_override_audit" pg:",discard_unknown_columns"`
+	Id                 int      `sql:"id,pk"`
+	PipelineId         int      `sql:"pipeline_id"`
+	AppId              int      `sql:"app_id"`
+	EnvId              int      `sql:"env_id"`
+	CdWorkflowRunnerId int      `sql:"cd_workflow_runner_id"`
+	WindowIds          []int    `sql:"window_ids" pg:",array"`
+	Reason             string   `sql:"reason,notnull"`
+	sql.AuditLog
+}
+
+type DeploymentWindowRepository interface {
+	sql.TransactionWrapper
+	Save(tx *pg.Tx, window *DeploymentWindow) error
+	Update(tx *pg.Tx, window *DeploymentWindow) error
+	FindById(id int) (*DeploymentWindow, error)
+	FindAllActive() ([]*DeploymentWindow, error)
+	FindActiveByIds(ids []int) ([]*DeploymentWindow, error)
+	SaveOverrideAudit(audit *DeploymentWindowOverrideAudit) error
+	FindOverrideAuditsByPipelineId(pipelineId int, limit int) ([]*DeploymentWindowOverrideAudit, error)
+}
+
+type DeploymentWindowRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+	*sql.TransactionUtilImpl
+}
+
+func NewDeploymentWindowRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger,
+	transactionUtilImpl *sql.TransactionUtilImpl) *DeploymentWindowRepositoryImpl {
+	return &DeploymentWindowRepositoryImpl{
+		dbConnection:        dbConnection,
+		logger:              logger,
+		TransactionUtilImpl: transactionUtilImpl,
+	}
+}
+
+func (repo *DeploymentWindowRepositoryImpl) Save(tx *pg.Tx, window *DeploymentWindow) error {
+	return tx.Insert(window)
+}
+
+func (repo *DeploymentWindowRepositoryImpl) Update(tx *pg.Tx, window *DeploymentWindow) error {
+	return tx.Update(window)
+}
+
+func (repo *DeploymentWindowRepositoryImpl) FindById(id int) (*DeploymentWindow, error) {
+	window := &DeploymentWindow{}
+	err := repo.dbConnection.Model(window).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return window, err
+}
+
+func (repo *DeploymentWindowRepositoryImpl) FindAllActive() ([]*DeploymentWindow, error) {
+	var windows []*DeploymentWindow
+	err := repo.dbConnection.Model(&windows).
+		Where("active = ?", true).
+		Order("id ASC").
+		Select()
+	if err == pg.ErrNoRows {
+		err = nil
+	}
+	return windows, err
+}
+
+func (repo *DeploymentWindowRepositoryImpl) FindActiveByIds(ids []int) ([]*DeploymentWindow, error) {
+	var windows []*DeploymentWindow
+	if len(ids) == 0 {
+		return windows, nil
+	}
+	err := repo.dbConnection.Model(&windows).
+		Where("id IN (?)", pg.In(ids)).
+		Where("active = ?", true).
+		Select()
+	if err == pg.ErrNoRows {
+		err = nil
+	}
+	return windows, err
+}
+
+func (repo *DeploymentWindowRepositoryImpl) SaveOverrideAudit(audit *DeploymentWindowOverrideAudit) error {
+	return repo.dbConnection.Insert(audit)
+}
+
+func (repo *DeploymentWindowRepositoryImpl) FindOverrideAuditsByPipelineId(pipelineId int, limit int) ([]*DeploymentWindowOverrideAudit, error) {
+	var audits []*DeploymentWindowOverrideAudit
+	err := repo.dbConnection.Model(&audits).
+		Where("pipeline_id = ?", pipelineId).
+		Order("id DESC").
+		Limit(limit).
+		Select()
+	if err == pg.ErrNoRows {
+		err = nil
+	}
+	return audits, err
+}
@@ -18,12 +18,12 @@ package dockerRegistry
 
 import (
 	"context"
-	"encoding/json"
 	"github.com/devtron-labs/common-lib/utils/k8s"
 	repository3 "github.com/devtron-labs/devtron/internal/sql/repository"
 	"github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
 	util2 "github.com/devtron-labs/devtron/internal/util"
 	ciConfig "github.com/devtron-labs/devtron/pkg/build/pipeline/read"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterCredentials"
 	repository2 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
 	"github.com/devtron-labs/devtron/pkg/cluster/read"
 	"github.com/go-pg/pg"
@@ -33,7 +33,6 @@ import (
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	"net/http"
 	"strconv"
-	"strings"
 )
 
 type DockerRegistryIpsConfigService interface {
@@ -48,13 +47,15 @@ type DockerRegistryIpsConfigServiceImpl struct {
 	dockerArtifactStoreRepository     repository.DockerArtifactStoreRepository
 	clusterReadService                read.ClusterReadService
 	ciPipelineConfigReadService       ciConfig.CiPipelineConfigReadService
+	clusterCredentialService          clusterCredentials.ClusterCredentialService
 }
 
 func NewDockerRegistryIpsConfigServiceImpl(logger *zap.SugaredLogger, dockerRegistryIpsConfigRepository repository.DockerRegistryIpsConfigRepository,
 	k8sUtil *k8s.K8sServiceImpl,
 	dockerArtifactStoreRepository repository.DockerArtifactStoreRepository,
 	clusterReadService read.ClusterReadService,
-	ciPipelineConfigReadService ciConfig.CiPipelineConfigReadService) *DockerRegistryIpsConfigServiceImpl {
+	ciPipelineConfigReadService ciConfig.CiPipelineConfigReadService,
+	clusterCredentialService clusterCredentials.ClusterCredentialService) *DockerRegistryIpsConfigServiceImpl {
 	return &DockerRegistryIpsConfigServiceImpl{
 		logger:                            logger,
 		dockerRegistryIpsConfigRepository: dockerRegistryIpsConfigRepository,
@@ -62,6 +63,7 @@ func NewDockerRegistryIpsConfigServiceImpl(logger *zap.SugaredLogger, dockerRegi
 		dockerArtifactStoreRepository:     dockerArtifactStoreRepository,
 		clusterReadService:                clusterReadService,
 		ciPipelineConfigReadService:       ciPipelineConfigReadService,
+		clusterCredentialService:          clusterCredentialService,
 	}
 }
 
@@ -86,17 +88,23 @@ func (impl DockerRegistryIpsConfigServiceImpl) HandleImagePullSecretOnApplicatio
 	clusterId := environment.ClusterId
 	impl.logger.Infow("handling ips if access given", "ciPipelineId", ciPipelineId, "clusterId", clusterId)
 
-	if ciPipelineId == 0 {
-		impl.logger.Warn("returning as ciPipelineId is found 0")
-		return valuesFileContent, nil
-	}
-
-	dockerRegistryId, err := impl.ciPipelineConfigReadService.GetDockerRegistryIdForCiPipeline(ciPipelineId, artifact)
-	if err != nil {
-		impl.logger.Errorw("error in getting docker registry", "dockerRegistryId", dockerRegistryId, "error", err)
-		return valuesFileContent, err
-	} else if dockerRegistryId == nil {
-		return valuesFileContent, nil
+	var dockerRegistryId *string
+	if len(artifact.PromotedRegistryId) > 0 {
+		// image was promoted for this environment, it is pulled from the target registry
+		dockerRegistryId = &artifact.PromotedRegistryId
+	} else {
+		if ciPipelineId == 0 {
+			impl.logger.Warn("returning as ciPipelineId is found 0")
+			return valuesFileContent, nil
+		}
+		var err error
+		dockerRegistryId, err = impl.ciPipelineConfigReadService.GetDockerRegistryIdForCiPipeline(ciPipelineId, artifact)
+		if err != nil {
+			impl.logger.Errorw("error in getting docker registry", "dockerRegistryId", dockerRegistryId, "error", err)
+			return valuesFileContent, err
+		} else if dockerRegistryId == nil {
+			return valuesFileContent, nil
+		}
 	}
 
 	dockerRegistryBean, err := impl.dockerArtifactStoreRepository.FindOne(*dockerRegistryId)
@@ -147,71 +155,28 @@ func (impl DockerRegistryIpsConfigServiceImpl) HandleImagePullSecretOnApplicatio
 func (impl DockerRegistryIpsConfigServiceImpl) createOrUpdateDockerRegistryImagePullSecret(clusterId int, namespace string, ipsName string, dockerRegistryBean *repository.DockerArtifactStore) error {
 	impl.logger.Infow("creating/updating ips", "ipsName", ipsName, "clusterId", clusterId)
 
-	username := dockerRegistryBean.Username
-	password := dockerRegistryBean.Password
-	registryURL := dockerRegistryBean.RegistryURL
-	var email string
-
-	// fetch from custom credentials
-	if dockerRegistryBean.IpsConfig.CredentialType == IPS_CREDENTIAL_TYPE_CUSTOM_CREDENTIAL {
-		var dockerIpsCustomCredential DockerIpsCustomCredential
-		credentialValue := dockerRegistryBean.IpsConfig.CredentialValue
-		err := json.Unmarshal([]byte(credentialValue), &dockerIpsCustomCredential)
-		if err != nil {
-			impl.logger.Errorw("error in unmarshalling custom credentials", "credentialValue", credentialValue, "error", err)
-			return err
-		}
-		if len(dockerIpsCustomCredential.Server) > 0 {
-			registryURL = dockerIpsCustomCredential.Server
-		}
-		if len(dockerIpsCustomCredential.Username) > 0 {
-			username = dockerIpsCustomCredential.Username
-		}
-		if len(dockerIpsCustomCredential.Password) > 0 {
-			password = dockerIpsCustomCredential.Password
-		}
-		if len(dockerIpsCustomCredential.Email) > 0 {
-			email = dockerIpsCustomCredential.Email
-		}
+	ipsCredential, err := GetIpsCredential(dockerRegistryBean)
+	if err != nil {
+		impl.logger.Errorw("error in getting ips credential", "clusterId", clusterId, "dockerRegistryId", dockerRegistryBean.Id, "error", err)
+		return err
 	}
-
-	registryType := dockerRegistryBean.RegistryType
-
 	// ignore for ecr ec2_iam role
-	if registryType == repository.REGISTRYTYPE_ECR {
-		awsAccessKeyId := dockerRegistryBean.AWSAccessKeyId
-		awsSecretAccessKey := dockerRegistryBean.AWSSecretAccessKey
-		if len(awsAccessKeyId) == 0 || len(awsSecretAccessKey) == 0 {
-			impl.logger.Info("ignoring for ecr ec2_iam role")
-			return nil
-		}
-		// create credential for ecr
-		impl.logger.Info("creating ecr credential")
-		ecrUsername, ecrPassword, err := CreateCredentialForEcr(dockerRegistryBean.AWSRegion, awsAccessKeyId, awsSecretAccessKey)
-		if err != nil {
-			impl.logger.Errorw("error in creating ecr credential", "clusterId", clusterId, "error", err)
-			return err
-		}
-		username = ecrUsername
-		password = ecrPassword
-	}
-
-	// for gcr and artifact-registry, remove single quote from start and end, with this secret does not work
-	if (registryType == repository.REGISTRYTYPE_GCR || registryType == repository.REGISTRYTYPE_ARTIFACT_REGISTRY) && username == repository.JSON_KEY_USERNAME {
-		if strings.HasPrefix(password, "'") {
-			password = password[1:]
-		}
-		if strings.HasSuffix(password, "'") {
-			password = password[:len(password)-1]
-		}
+	if ipsCredential == nil {
+		impl.logger.Info("ignoring for ecr ec2_iam role")
+		return nil
 	}
+	registryURL, username, password, email := ipsCredential.RegistryURL, ipsCredential.Username, ipsCredential.Password, ipsCredential.Email
 
 	clusterBean, err := impl.clusterReadService.FindById(clusterId)
 	if err != nil {
 		impl.logger.Errorw("error in getting cluster", "clusterId", clusterId, "error", err)
 		return err
 	}
-	cfg := clusterBean.GetClusterConfig()
+	cfg, err := clusterBean.ResolveClusterConfig(impl.clusterCredentialService)
+	if err != nil {
+		impl.logger.Errorw("error in resolving cluster credentials", "clusterId", clusterId, "error", err)
+		return err
+	}
 	k8sClient, err := impl.k8sUtil.GetCoreV1Client(cfg)
 	if err != nil {
 		impl.logger.Errorw("error in getting k8s client", "clusterId", clusterId, "error", err)
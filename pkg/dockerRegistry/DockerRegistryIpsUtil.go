@@ -24,6 +24,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ecr"
+	repository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/kubectl/pkg/cmd/create"
 	"regexp"
@@ -46,6 +47,69 @@ type DockerIpsCustomCredential struct {
 	Email    string `json:"email"`
 }
 
+// IpsCredential is the registry login written into an image pull secret
+type IpsCredential struct {
+	RegistryURL string
+	Username    string
+	Password    string
+	Email       string
+}
+
+// GetIpsCredential resolves the login used in the image pull secret of a registry, nil is returned
+// for ecr registries relying on the ec2 iam role as no secret is needed for them
+func GetIpsCredential(dockerRegistryBean *repository.DockerArtifactStore) (*IpsCredential, error) {
+	ipsCredential := &IpsCredential{
+		RegistryURL: dockerRegistryBean.RegistryURL,
+		Username:    dockerRegistryBean.Username,
+		Password:    dockerRegistryBean.Password,
+	}
+
+	// fetch from custom credentials
+	if dockerRegistryBean.IpsConfig != nil && dockerRegistryBean.IpsConfig.CredentialType == IPS_CREDENTIAL_TYPE_CUSTOM_CREDENTIAL {
+		var dockerIpsCustomCredential DockerIpsCustomCredential
+		err := json.Unmarshal([]byte(dockerRegistryBean.IpsConfig.CredentialValue), &dockerIpsCustomCredential)
+		if err != nil {
+			return nil, fmt.Errorf("error in unmarshalling custom credentials: %v", err)
+		}
+		if len(dockerIpsCustomCredential.Server) > 0 {
+			ipsCredential.RegistryURL = dockerIpsCustomCredential.Server
+		}
+		if len(dockerIpsCustomCredential.Username) > 0 {
+			ipsCredential.Username = dockerIpsCustomCredential.Username
+		}
+		if len(dockerIpsCustomCredential.Password) > 0 {
+			ipsCredential.Password = dockerIpsCustomCredential.Password
+		}
+		if len(dockerIpsCustomCredential.Email) > 0 {
+			ipsCredential.Email = dockerIpsCustomCredential.Email
+		}
+	}
+
+	registryType := dockerRegistryBean.RegistryType
+
+	if registryType == repository.REGISTRYTYPE_ECR {
+		awsAccessKeyId := dockerRegistryBean.AWSAccessKeyId
+		awsSecretAccessKey := dockerRegistryBean.AWSSecretAccessKey
+		if len(awsAccessKeyId) == 0 || len(awsSecretAccessKey) == 0 {
+			return nil, nil
+		}
+		// create credential for ecr
+		ecrUsername, ecrPassword, err := CreateCredentialForEcr(dockerRegistryBean.AWSRegion, awsAccessKeyId, awsSecretAccessKey)
+		if err != nil {
+			return nil, fmt.Errorf("error in creating ecr credential: %v", err)
+		}
+		ipsCredential.Username = ecrUsername
+		ipsCredential.Password = ecrPassword
+	}
+
+	// for gcr and artifact-registry, remove single quote from start and end, with this secret does not work
+	if (registryType == repository.REGISTRYTYPE_GCR || registryType == repository.REGISTRYTYPE_ARTIFACT_REGISTRY) && ipsCredential.Username == repository.JSON_KEY_USERNAME {
+		ipsCredential.Password = strings.TrimPrefix(ipsCredential.Password, "'")
+		ipsCredential.Password = strings.TrimSuffix(ipsCredential.Password, "'")
+	}
+	return ipsCredential, nil
+}
+
 func CheckIfImagePullSecretAccessProvided(appliedClusterIdsCsv string, ignoredClusterIdsCsv string, clusterId int, isVirtualEnv bool) bool {
 	if isVirtualEnv {
 		return false
@@ -162,11 +226,12 @@ func SetIpsNameInValues(valuesContent []byte, ipsName string) ([]byte, error) {
 
 // returns username and password
 func CreateCredentialForEcr(awsRegion, awsAccessKey, awsSecretKey string) (string, string, error) {
-	creds := credentials.NewStaticCredentials(awsAccessKey, awsSecretKey, "")
-	sess, err := session.NewSession(&aws.Config{
-		Region:      &awsRegion,
-		Credentials: creds,
-	})
+	config := &aws.Config{Region: &awsRegion}
+	// without access keys the default aws credential chain of the orchestrator is used
+	if len(awsAccessKey) > 0 && len(awsSecretKey) > 0 {
+		config.Credentials = credentials.NewStaticCredentials(awsAccessKey, awsSecretKey, "")
+	}
+	sess, err := session.NewSession(config)
 	if err != nil {
 		return "", "", err
 	}
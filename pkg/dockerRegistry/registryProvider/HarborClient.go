@@ -0,0 +1,205 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registryProvider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	harborApiPath         = "/api/v2.0"
+	harborPageSize        = 100
+	harborMaxRepositories = 500
+	harborReportMimeType  = "application/vnd.security.vulnerability.report; version=1.1"
+	harborLegacyMimeType  = "application/vnd.scanner.adapter.vuln.report.harbor+json; version=1.0"
+)
+
+type HarborClient struct {
+	baseUrl    string
+	username   string
+	password   string
+	account    *RobotAccount
+	httpClient *http.Client
+}
+
+func NewHarborClient(baseUrl, username, password string, account *RobotAccount, httpClient *http.Client) *HarborClient {
+	return &HarborClient{
+		baseUrl:    baseUrl,
+		username:   username,
+		password:   password,
+		account:    account,
+		httpClient: httpClient,
+	}
+}
+
+type harborProject struct {
+	Name string `json:"name"`
+}
+
+type harborRepository struct {
+	Name string `json:"name"`
+}
+
+type harborRobot struct {
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type harborRobotSecret struct {
+	Secret string `json:"secret"`
+}
+
+type harborReport struct {
+	Scanner *struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"scanner"`
+	Vulnerabilities []*struct {
+		Id         string `json:"id"`
+		Package    string `json:"package"`
+		Version    string `json:"version"`
+		FixVersion string `json:"fix_version"`
+		Severity   string `json:"severity"`
+	} `json:"vulnerabilities"`
+}
+
+// ListRepositories lists repositories project by project, a project level robot only sees its own project
+func (impl *HarborClient) ListRepositories(ctx context.Context, search string) ([]string, error) {
+	var projects []string
+	if impl.account != nil && len(impl.account.Namespace) > 0 {
+		projects = []string{impl.account.Namespace}
+	} else {
+		var harborProjects []*harborProject
+		err := impl.get(ctx, fmt.Sprintf("/projects?page=1&page_size=%d", harborPageSize), &harborProjects)
+		if err != nil {
+			return nil, err
+		}
+		for _, project := range harborProjects {
+			projects = append(projects, project.Name)
+		}
+	}
+	repositories := make([]string, 0)
+	for _, project := range projects {
+		path := fmt.Sprintf("/projects/%s/repositories?page=1&page_size=%d", url.PathEscape(project), harborPageSize)
+		if len(search) > 0 {
+			path += "&q=" + url.QueryEscape("name=~"+search)
+		}
+		var harborRepositories []*harborRepository
+		err := impl.get(ctx, path, &harborRepositories)
+		if err != nil {
+			return nil, err
+		}
+		for _, repository := range harborRepositories {
+			repositories = append(repositories, repository.Name)
+		}
+		if len(repositories) >= harborMaxRepositories {
+			break
+		}
+	}
+	return repositories, nil
+}
+
+func (impl *HarborClient) GetVulnerabilityReport(ctx context.Context, repository, reference string) (*VulnerabilityReport, error) {
+	project, repositoryName, found := strings.Cut(repository, "/")
+	if !found {
+		return nil, fmt.Errorf("harbor repository %s has no project", repository)
+	}
+	// harbor expects the slashes of nested repository names to be encoded twice
+	path := fmt.Sprintf("/projects/%s/repositories/%s/artifacts/%s/additions/vulnerabilities",
+		url.PathEscape(project), url.PathEscape(url.PathEscape(repositoryName)), url.PathEscape(reference))
+	reports := make(map[string]*harborReport)
+	err := impl.get(ctx, path, &reports)
+	if err == errNotFound {
+		return nil, ErrScanReportNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	report := reports[harborReportMimeType]
+	if report == nil {
+		report = reports[harborLegacyMimeType]
+	}
+	if report == nil {
+		return nil, ErrScanReportNotFound
+	}
+	result := &VulnerabilityReport{Scanner: "harbor"}
+	if report.Scanner != nil {
+		result.Scanner = strings.TrimSpace(report.Scanner.Name + " " + report.Scanner.Version)
+	}
+	for _, vulnerability := range report.Vulnerabilities {
+		result.Vulnerabilities = append(result.Vulnerabilities, &Vulnerability{
+			CveName:      vulnerability.Id,
+			Severity:     vulnerability.Severity,
+			Package:      vulnerability.Package,
+			Version:      vulnerability.Version,
+			FixedVersion: vulnerability.FixVersion,
+		})
+	}
+	return result, nil
+}
+
+// RotateRobotSecret refreshes the secret of the configured robot with its own credentials, harbor generates the new
+// secret when an empty one is sent. The robot needs permission to list robots to find its own id
+func (impl *HarborClient) RotateRobotSecret(ctx context.Context, adminToken string) (string, error) {
+	if impl.account == nil {
+		return "", ErrNotRobotAccount
+	}
+	var robots []*harborRobot
+	path := fmt.Sprintf("/robots?page=1&page_size=%d&q=%s", harborPageSize, url.QueryEscape("name=~"+impl.account.Name))
+	err := impl.get(ctx, path, &robots)
+	if err != nil {
+		return "", err
+	}
+	var robot *harborRobot
+	for _, item := range robots {
+		if item.Name == impl.username {
+			robot = item
+			break
+		}
+	}
+	if robot == nil {
+		return "", fmt.Errorf("harbor robot account %s not found", impl.username)
+	}
+	var secret harborRobotSecret
+	err = impl.do(ctx, http.MethodPatch, fmt.Sprintf("/robots/%d", robot.Id), &harborRobotSecret{}, &secret)
+	if err != nil {
+		return "", err
+	}
+	if len(secret.Secret) == 0 {
+		return "", fmt.Errorf("harbor returned no secret for robot account %s", impl.username)
+	}
+	return secret.Secret, nil
+}
+
+func (impl *HarborClient) get(ctx context.Context, path string, response interface{}) error {
+	return impl.do(ctx, http.MethodGet, path, nil, response)
+}
+
+func (impl *HarborClient) do(ctx context.Context, method, path string, body, response interface{}) error {
+	req, err := newJsonRequest(ctx, method, impl.baseUrl+harborApiPath+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Accept-Vulnerabilities", harborReportMimeType+", "+harborLegacyMimeType)
+	if len(impl.username) > 0 || len(impl.password) > 0 {
+		req.SetBasicAuth(impl.username, impl.password)
+	}
+	return getJson(impl.httpClient, req, response)
+}
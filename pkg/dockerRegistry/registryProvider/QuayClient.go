@@ -0,0 +1,187 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registryProvider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	quayApiPath         = "/api/v1"
+	quayStatusScanned   = "scanned"
+	quayMaxRepositories = 500
+)
+
+type QuayClient struct {
+	baseUrl    string
+	token      string
+	account    *RobotAccount
+	httpClient *http.Client
+}
+
+// NewQuayClient authorises api calls only when the registry is configured with an oauth token, robot accounts can not
+// call the quay api and only see public repositories there
+func NewQuayClient(baseUrl, username, password string, account *RobotAccount, httpClient *http.Client) *QuayClient {
+	client := &QuayClient{
+		baseUrl:    baseUrl,
+		account:    account,
+		httpClient: httpClient,
+	}
+	if username == QuayOAuthTokenUsername {
+		client.token = password
+	}
+	return client
+}
+
+type quayRepositoryList struct {
+	Repositories []*struct {
+		Namespace string `json:"namespace"`
+		Name      string `json:"name"`
+	} `json:"repositories"`
+	NextPage string `json:"next_page"`
+}
+
+type quayRobot struct {
+	Name  string `json:"name"`
+	Token string `json:"token"`
+}
+
+type quaySecurity struct {
+	Status string `json:"status"`
+	Data   *struct {
+		Layer *struct {
+			Features []*struct {
+				Name            string `json:"Name"`
+				Version         string `json:"Version"`
+				Vulnerabilities []*struct {
+					Name     string `json:"Name"`
+					Severity string `json:"Severity"`
+					FixedBy  string `json:"FixedBy"`
+				} `json:"Vulnerabilities"`
+			} `json:"Features"`
+		} `json:"Layer"`
+	} `json:"data"`
+}
+
+// ListRepositories lists the namespace of the robot account, for oauth tokens the search has to start with the namespace
+// as in namespace/app
+func (impl *QuayClient) ListRepositories(ctx context.Context, search string) ([]string, error) {
+	namespace, _, found := strings.Cut(search, "/")
+	if impl.account != nil {
+		namespace = impl.account.Namespace
+	} else if !found || len(namespace) == 0 {
+		return nil, fmt.Errorf("search quay repositories as namespace/name when no robot account is configured")
+	}
+	query := url.Values{}
+	query.Set("namespace", namespace)
+	if len(impl.token) == 0 {
+		query.Set("public", "true")
+	}
+	repositories := make([]string, 0)
+	for {
+		var response quayRepositoryList
+		err := impl.get(ctx, "/repository?"+query.Encode(), &response)
+		if err != nil {
+			return nil, err
+		}
+		for _, repository := range response.Repositories {
+			name := repository.Namespace + "/" + repository.Name
+			if len(search) == 0 || strings.Contains(name, search) {
+				repositories = append(repositories, name)
+			}
+		}
+		if len(response.NextPage) == 0 || len(repositories) >= quayMaxRepositories {
+			break
+		}
+		query.Set("next_page", response.NextPage)
+	}
+	return repositories, nil
+}
+
+// GetVulnerabilityReport needs the manifest digest, quay reports security data per manifest
+func (impl *QuayClient) GetVulnerabilityReport(ctx context.Context, repository, reference string) (*VulnerabilityReport, error) {
+	if !strings.HasPrefix(reference, "sha256:") {
+		return nil, fmt.Errorf("quay scan results are looked up by manifest digest, %s is not a digest", reference)
+	}
+	var security quaySecurity
+	err := impl.get(ctx, fmt.Sprintf("/repository/%s/manifest/%s/security?vulnerabilities=true", repository, url.PathEscape(reference)), &security)
+	if err == errNotFound {
+		return nil, ErrScanReportNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if security.Status != quayStatusScanned || security.Data == nil || security.Data.Layer == nil {
+		return nil, ErrScanReportNotFound
+	}
+	result := &VulnerabilityReport{Scanner: "quay"}
+	for _, feature := range security.Data.Layer.Features {
+		for _, vulnerability := range feature.Vulnerabilities {
+			result.Vulnerabilities = append(result.Vulnerabilities, &Vulnerability{
+				CveName:      vulnerability.Name,
+				Severity:     vulnerability.Severity,
+				Package:      feature.Name,
+				Version:      feature.Version,
+				FixedVersion: vulnerability.FixedBy,
+			})
+		}
+	}
+	return result, nil
+}
+
+// RotateRobotSecret regenerates the robot token through the organisation api and falls back to the user namespace,
+// robots can not call the quay api so adminToken has to be an oauth token with admin rights on the namespace
+func (impl *QuayClient) RotateRobotSecret(ctx context.Context, adminToken string) (string, error) {
+	if impl.account == nil {
+		return "", ErrNotRobotAccount
+	}
+	if len(adminToken) == 0 {
+		return "", fmt.Errorf("quay robot tokens are regenerated with an oauth token of a namespace admin, none was given")
+	}
+	var robot quayRobot
+	path := fmt.Sprintf("/organization/%s/robots/%s/regenerate", url.PathEscape(impl.account.Namespace), url.PathEscape(impl.account.Name))
+	err := impl.do(ctx, http.MethodPost, path, adminToken, &robot)
+	if err == errNotFound {
+		// robots of a user namespace are managed by the user, the token has to belong to that user
+		err = impl.do(ctx, http.MethodPost, fmt.Sprintf("/user/robots/%s/regenerate", url.PathEscape(impl.account.Name)), adminToken, &robot)
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(robot.Token) == 0 {
+		return "", fmt.Errorf("quay returned no token for robot account %s+%s", impl.account.Namespace, impl.account.Name)
+	}
+	return robot.Token, nil
+}
+
+func (impl *QuayClient) get(ctx context.Context, path string, response interface{}) error {
+	return impl.do(ctx, http.MethodGet, path, impl.token, response)
+}
+
+func (impl *QuayClient) do(ctx context.Context, method, path, token string, response interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, impl.baseUrl+quayApiPath+path, nil)
+	if err != nil {
+		return err
+	}
+	if len(token) > 0 {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return getJson(impl.httpClient, req, response)
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registryProvider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/devtron-labs/devtron/pkg/build/artifacts/retention/registry"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrScanReportNotFound is returned when the registry has not scanned the image (yet)
+var ErrScanReportNotFound = errors.New("registry has no scan report for the image")
+
+var errNotFound = errors.New("not found in registry")
+
+type Vulnerability struct {
+	CveName      string
+	Severity     string
+	Package      string
+	Version      string
+	FixedVersion string
+}
+
+// VulnerabilityReport is the result of the scanner built into the registry for one image manifest
+type VulnerabilityReport struct {
+	Scanner         string
+	Vulnerabilities []*Vulnerability
+}
+
+// RegistryProviderClient talks to the management api of registries that are more than a plain distribution registry
+type RegistryProviderClient interface {
+	// ListRepositories returns repository paths (without registry host) visible to the registry credentials
+	ListRepositories(ctx context.Context, search string) ([]string, error)
+	// GetVulnerabilityReport returns the scan result of the registry, reference is a tag or a manifest digest
+	GetVulnerabilityReport(ctx context.Context, repository, reference string) (*VulnerabilityReport, error)
+	// RotateRobotSecret regenerates the secret of the robot account the client is configured with and returns it,
+	// adminToken authorises the call where the robot can not rotate its own secret (quay)
+	RotateRobotSecret(ctx context.Context, adminToken string) (string, error)
+}
+
+// ErrNotRobotAccount is returned on rotation when the registry is not configured with a robot account
+var ErrNotRobotAccount = errors.New("registry is not configured with a robot account")
+
+func IsProviderRegistryType(registryType dockerRegistryRepository.RegistryType) bool {
+	return registryType == dockerRegistryRepository.REGISTRYTYPE_HARBOR || registryType == dockerRegistryRepository.REGISTRYTYPE_QUAY
+}
+
+func NewRegistryProviderClient(store *dockerRegistryRepository.DockerArtifactStore, timeout time.Duration) (RegistryProviderClient, error) {
+	httpClient, err := registry.NewHttpClient(store.Connection, store.Cert, timeout)
+	if err != nil {
+		return nil, err
+	}
+	account, err := ParseRobotAccount(store.RegistryType, store.Username)
+	if err != nil {
+		return nil, err
+	}
+	switch store.RegistryType {
+	case dockerRegistryRepository.REGISTRYTYPE_HARBOR:
+		return NewHarborClient(getApiBaseUrl(store.RegistryURL), store.Username, store.Password, account, httpClient), nil
+	case dockerRegistryRepository.REGISTRYTYPE_QUAY:
+		return NewQuayClient(getApiBaseUrl(store.RegistryURL), store.Username, store.Password, account, httpClient), nil
+	}
+	return nil, fmt.Errorf("registry type %s has no management api", store.RegistryType)
+}
+
+// getApiBaseUrl drops any path of the registry url, the management apis are served from the root of the host
+func getApiBaseUrl(registryUrl string) string {
+	scheme := "https://"
+	if strings.HasPrefix(registryUrl, "http://") {
+		scheme = "http://"
+	}
+	host := strings.TrimPrefix(strings.TrimPrefix(registryUrl, "https://"), "http://")
+	if index := strings.Index(host, "/"); index >= 0 {
+		host = host[:index]
+	}
+	return scheme + host
+}
+
+// newJsonRequest encodes body as the json payload of the request, a nil body sends no payload
+func newJsonRequest(ctx context.Context, method, url string, body interface{}) (*http.Request, error) {
+	var payload io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		payload = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, payload)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+func getJson(httpClient *http.Client, req *http.Request, response interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return errNotFound
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("registry denied %s, status %d", req.URL.Path, resp.StatusCode)
+	case resp.StatusCode >= http.StatusBadRequest:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("registry returned status %d for %s: %s", resp.StatusCode, req.URL.Path, string(body))
+	}
+	return json.NewDecoder(resp.Body).Decode(response)
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registryProvider
+
+import (
+	"fmt"
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"strings"
+)
+
+const (
+	harborRobotPrefix = "robot$"
+	// QuayOAuthTokenUsername logs into quay with an oauth access token as password, the token also authorises api calls
+	QuayOAuthTokenUsername = "$oauthtoken"
+)
+
+// RobotAccount is the parsed username of a registry robot account, Namespace is the harbor project or quay organisation
+// the robot is limited to and is empty for system level harbor robots
+type RobotAccount struct {
+	Name      string
+	Namespace string
+}
+
+// ParseRobotAccount understands harbor robots (robot$name, robot$project+name) and quay robots (namespace+name).
+// Usernames of regular accounts return nil
+func ParseRobotAccount(registryType dockerRegistryRepository.RegistryType, username string) (*RobotAccount, error) {
+	switch registryType {
+	case dockerRegistryRepository.REGISTRYTYPE_HARBOR:
+		if !strings.HasPrefix(username, harborRobotPrefix) {
+			return nil, nil
+		}
+		name := strings.TrimPrefix(username, harborRobotPrefix)
+		if len(name) == 0 {
+			return nil, fmt.Errorf("harbor robot account %s has no name", username)
+		}
+		if project, robotName, found := strings.Cut(name, "+"); found {
+			if len(project) == 0 || len(robotName) == 0 {
+				return nil, fmt.Errorf("harbor robot account %s should look like robot$project+name", username)
+			}
+			return &RobotAccount{Name: robotName, Namespace: project}, nil
+		}
+		return &RobotAccount{Name: name}, nil
+	case dockerRegistryRepository.REGISTRYTYPE_QUAY:
+		if username == QuayOAuthTokenUsername {
+			return nil, nil
+		}
+		namespace, robotName, found := strings.Cut(username, "+")
+		if !found {
+			return nil, nil
+		}
+		if len(namespace) == 0 || len(robotName) == 0 {
+			return nil, fmt.Errorf("quay robot account %s should look like namespace+name", username)
+		}
+		return &RobotAccount{Name: robotName, Namespace: namespace}, nil
+	}
+	return nil, nil
+}
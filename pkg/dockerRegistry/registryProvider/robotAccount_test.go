@@ -0,0 +1,148 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package registryProvider
+
+import (
+	"context"
+	"encoding/json"
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseRobotAccount(t *testing.T) {
+	tests := []struct {
+		name         string
+		registryType dockerRegistryRepository.RegistryType
+		username     string
+		want         *RobotAccount
+		wantErr      bool
+	}{
+		{name: "harbor system robot", registryType: dockerRegistryRepository.REGISTRYTYPE_HARBOR, username: "robot$ci", want: &RobotAccount{Name: "ci"}},
+		{name: "harbor project robot", registryType: dockerRegistryRepository.REGISTRYTYPE_HARBOR, username: "robot$payments+ci", want: &RobotAccount{Name: "ci", Namespace: "payments"}},
+		{name: "harbor user", registryType: dockerRegistryRepository.REGISTRYTYPE_HARBOR, username: "admin"},
+		{name: "harbor robot without name", registryType: dockerRegistryRepository.REGISTRYTYPE_HARBOR, username: "robot$", wantErr: true},
+		{name: "harbor project robot without name", registryType: dockerRegistryRepository.REGISTRYTYPE_HARBOR, username: "robot$payments+", wantErr: true},
+		{name: "quay robot", registryType: dockerRegistryRepository.REGISTRYTYPE_QUAY, username: "acme+deployer", want: &RobotAccount{Name: "deployer", Namespace: "acme"}},
+		{name: "quay oauth token", registryType: dockerRegistryRepository.REGISTRYTYPE_QUAY, username: QuayOAuthTokenUsername},
+		{name: "quay robot without namespace", registryType: dockerRegistryRepository.REGISTRYTYPE_QUAY, username: "+deployer", wantErr: true},
+		{name: "other registry", registryType: dockerRegistryRepository.REGISTRYTYPE_OTHER, username: "robot$ci"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRobotAccount(tt.registryType, tt.username)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRobotAccount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("ParseRobotAccount() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil || *got != *tt.want {
+				t.Errorf("ParseRobotAccount() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHarborClient_RotateRobotSecret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, _ := r.BasicAuth(); username != "robot$payments+ci" || password != "old" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2.0/robots":
+			if r.URL.Query().Get("q") != "name=~ci" {
+				t.Errorf("unexpected robot query %s", r.URL.Query().Get("q"))
+			}
+			_ = json.NewEncoder(w).Encode([]*harborRobot{{Id: 3, Name: "robot$payments+ci-old"}, {Id: 7, Name: "robot$payments+ci"}})
+		case r.Method == http.MethodPatch && r.URL.Path == "/api/v2.0/robots/7":
+			var body harborRobotSecret
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Secret != "" {
+				t.Errorf("unexpected refresh body %+v, err %v", body, err)
+			}
+			_ = json.NewEncoder(w).Encode(&harborRobotSecret{Secret: "new"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewHarborClient(server.URL, "robot$payments+ci", "old", &RobotAccount{Name: "ci", Namespace: "payments"}, server.Client())
+	secret, err := client.RotateRobotSecret(context.Background(), "")
+	if err != nil || secret != "new" {
+		t.Fatalf("RotateRobotSecret() = %q, %v, want new", secret, err)
+	}
+
+	client = NewHarborClient(server.URL, "robot$payments+ci", "wrong", &RobotAccount{Name: "ci", Namespace: "payments"}, server.Client())
+	if _, err = client.RotateRobotSecret(context.Background(), ""); err == nil {
+		t.Errorf("RotateRobotSecret() with wrong secret should fail")
+	}
+
+	client = NewHarborClient(server.URL, "admin", "old", nil, server.Client())
+	if _, err = client.RotateRobotSecret(context.Background(), ""); err != ErrNotRobotAccount {
+		t.Errorf("RotateRobotSecret() without robot account error = %v, want %v", err, ErrNotRobotAccount)
+	}
+}
+
+func TestQuayClient_RotateRobotSecret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer admin-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/organization/acme/robots/deployer/regenerate":
+			_ = json.NewEncoder(w).Encode(&quayRobot{Name: "acme+deployer", Token: "org-token"})
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/user/robots/builder/regenerate":
+			_ = json.NewEncoder(w).Encode(&quayRobot{Name: "jane+builder", Token: "user-token"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		account    *RobotAccount
+		adminToken string
+		want       string
+		wantErr    bool
+	}{
+		{name: "organisation robot", account: &RobotAccount{Name: "deployer", Namespace: "acme"}, adminToken: "admin-token", want: "org-token"},
+		{name: "user robot", account: &RobotAccount{Name: "builder", Namespace: "jane"}, adminToken: "admin-token", want: "user-token"},
+		{name: "missing admin token", account: &RobotAccount{Name: "deployer", Namespace: "acme"}, wantErr: true},
+		{name: "wrong admin token", account: &RobotAccount{Name: "deployer", Namespace: "acme"}, adminToken: "robot-token", wantErr: true},
+		{name: "not a robot", adminToken: "admin-token", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewQuayClient(server.URL, "", "", tt.account, server.Client())
+			got, err := client.RotateRobotSecret(context.Background(), tt.adminToken)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RotateRobotSecret() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("RotateRobotSecret() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
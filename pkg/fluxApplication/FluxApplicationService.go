@@ -3,13 +3,14 @@ package fluxApplication
 import (
 	"context"
 	"fmt"
-	"github.com/devtron-labs/common-lib/utils/k8s/commonBean"
 	"github.com/devtron-labs/devtron/api/connector"
 	"github.com/devtron-labs/devtron/api/helm-app/gRPC"
 	openapi "github.com/devtron-labs/devtron/api/helm-app/openapiClient"
 	"github.com/devtron-labs/devtron/api/helm-app/service"
 	"github.com/devtron-labs/devtron/api/helm-app/service/read"
 	"github.com/devtron-labs/devtron/pkg/cluster"
+	"github.com/devtron-labs/devtron/pkg/cluster/adapter"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterCredentials"
 	"github.com/devtron-labs/devtron/pkg/fluxApplication/bean"
 	"github.com/gogo/protobuf/proto"
 	"go.opentelemetry.io/otel"
@@ -25,23 +26,26 @@ type FluxApplicationService interface {
 }
 
 type FluxApplicationServiceImpl struct {
-	logger             *zap.SugaredLogger
-	helmAppReadService read.HelmAppReadService
-	clusterService     cluster.ClusterService
-	helmAppClient      gRPC.HelmAppClient
-	pump               connector.Pump
+	logger                   *zap.SugaredLogger
+	helmAppReadService       read.HelmAppReadService
+	clusterService           cluster.ClusterService
+	helmAppClient            gRPC.HelmAppClient
+	pump                     connector.Pump
+	clusterCredentialService clusterCredentials.ClusterCredentialService
 }
 
 func NewFluxApplicationServiceImpl(logger *zap.SugaredLogger,
 	helmAppReadService read.HelmAppReadService,
 	clusterService cluster.ClusterService,
-	helmAppClient gRPC.HelmAppClient, pump connector.Pump) *FluxApplicationServiceImpl {
+	helmAppClient gRPC.HelmAppClient, pump connector.Pump,
+	clusterCredentialService clusterCredentials.ClusterCredentialService) *FluxApplicationServiceImpl {
 	return &FluxApplicationServiceImpl{
-		logger:             logger,
-		helmAppReadService: helmAppReadService,
-		clusterService:     clusterService,
-		helmAppClient:      helmAppClient,
-		pump:               pump,
+		logger:                   logger,
+		helmAppReadService:       helmAppReadService,
+		clusterService:           clusterService,
+		helmAppClient:            helmAppClient,
+		pump:                     pump,
+		clusterCredentialService: clusterCredentialService,
 	}
 
 }
@@ -137,17 +141,10 @@ func (impl *FluxApplicationServiceImpl) listApplications(ctx context.Context, cl
 	}
 
 	for _, clusterDetail := range clusters {
-		config := &gRPC.ClusterConfig{
-			ApiServerUrl:          clusterDetail.ServerUrl,
-			Token:                 clusterDetail.Config[commonBean.BearerToken],
-			ClusterId:             int32(clusterDetail.Id),
-			ClusterName:           clusterDetail.ClusterName,
-			InsecureSkipTLSVerify: clusterDetail.InsecureSkipTLSVerify,
-		}
-		if clusterDetail.InsecureSkipTLSVerify == false {
-			config.KeyData = clusterDetail.Config[commonBean.TlsKey]
-			config.CertData = clusterDetail.Config[commonBean.CertData]
-			config.CaData = clusterDetail.Config[commonBean.CertificateAuthorityData]
+		config, err := adapter.GetGrpcClusterConfig(clusterDetail, impl.clusterCredentialService)
+		if err != nil {
+			// the cluster is still listed, kubelink reports it as errored
+			impl.logger.Errorw("error in resolving cluster credentials", "clusterId", clusterDetail.Id, "err", err)
 		}
 		req.Clusters = append(req.Clusters, config)
 	}
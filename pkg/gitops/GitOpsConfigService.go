@@ -33,6 +33,7 @@ import (
 	config2 "github.com/devtron-labs/devtron/client/argocdServer/config"
 	"github.com/devtron-labs/devtron/client/argocdServer/connection"
 	repository2 "github.com/devtron-labs/devtron/client/argocdServer/repository"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterCredentials"
 	"github.com/devtron-labs/devtron/pkg/cluster/read"
 	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/config"
 	"github.com/devtron-labs/devtron/pkg/deployment/gitOps/git"
@@ -85,6 +86,7 @@ type GitOpsConfigServiceImpl struct {
 	argoClientWrapperService argocdServer.ArgoClientWrapperService
 	clusterReadService       read.ClusterReadService
 	moduleReadService        moduleRead.ModuleReadService
+	clusterCredentialService clusterCredentials.ClusterCredentialService
 }
 
 func NewGitOpsConfigServiceImpl(Logger *zap.SugaredLogger,
@@ -101,7 +103,8 @@ func NewGitOpsConfigServiceImpl(Logger *zap.SugaredLogger,
 	argoCDConfigGetter config2.ArgoCDConfigGetter,
 	argoClientWrapperService argocdServer.ArgoClientWrapperService,
 	clusterReadService read.ClusterReadService,
-	moduleReadService moduleRead.ModuleReadService) *GitOpsConfigServiceImpl {
+	moduleReadService moduleRead.ModuleReadService,
+	clusterCredentialService clusterCredentials.ClusterCredentialService) *GitOpsConfigServiceImpl {
 	return &GitOpsConfigServiceImpl{
 		logger:                   Logger,
 		gitOpsRepository:         gitOpsRepository,
@@ -119,6 +122,7 @@ func NewGitOpsConfigServiceImpl(Logger *zap.SugaredLogger,
 		argoClientWrapperService: argoClientWrapperService,
 		clusterReadService:       clusterReadService,
 		moduleReadService:        moduleReadService,
+		clusterCredentialService: clusterCredentialService,
 	}
 }
 
@@ -255,7 +259,10 @@ func (impl *GitOpsConfigServiceImpl) registerGitOpsClientConfig(ctx context.Cont
 		if err != nil {
 			return nil, err
 		}
-		cfg := clusterBean.GetClusterConfig()
+		cfg, err := clusterBean.ResolveClusterConfig(impl.clusterCredentialService)
+		if err != nil {
+			return nil, err
+		}
 
 		client, err := impl.K8sUtil.GetCoreV1Client(cfg)
 		if err != nil {
@@ -351,7 +358,11 @@ func (impl *GitOpsConfigServiceImpl) registerClustersInArgoCd(ctx context.Contex
 			return err
 		}
 		for _, clusterBean := range clusters {
-			cl := impl.clusterService.ConvertClusterBeanObjectToCluster(&clusterBean)
+			cl, err := impl.clusterService.ConvertClusterBeanObjectToCluster(&clusterBean)
+			if err != nil {
+				impl.logger.Errorw("error in resolving cluster credentials", "clusterName", clusterBean.ClusterName, "err", err)
+				return err
+			}
 			_, err = impl.argoClientWrapperService.CreateCluster(ctx, &cluster3.ClusterCreateRequest{Upsert: true, Cluster: cl})
 			if err != nil {
 				impl.logger.Errorw("Error while upserting cluster in acd", "clusterName", clusterBean.ClusterName, "err", err)
@@ -542,7 +553,10 @@ func (impl *GitOpsConfigServiceImpl) patchGitOpsClientConfig(model *repository.G
 		if err != nil {
 			return err
 		}
-		cfg := clusterBean.GetClusterConfig()
+		cfg, err := clusterBean.ResolveClusterConfig(impl.clusterCredentialService)
+		if err != nil {
+			return err
+		}
 
 		client, err := impl.K8sUtil.GetCoreV1Client(cfg)
 		if err != nil {
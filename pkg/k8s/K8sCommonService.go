@@ -27,6 +27,7 @@ import (
 	internalUtil "github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/argoApplication/read/config"
 	bean2 "github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterCredentials"
 	bean4 "github.com/devtron-labs/devtron/pkg/cluster/environment/bean"
 	"github.com/devtron-labs/devtron/pkg/cluster/read"
 	bean3 "github.com/devtron-labs/devtron/pkg/k8s/application/bean"
@@ -75,6 +76,7 @@ type K8sCommonServiceImpl struct {
 	K8sApplicationServiceConfig  *K8sApplicationServiceConfig
 	argoApplicationConfigService config.ArgoApplicationConfigService
 	ClusterReadService           read.ClusterReadService
+	clusterCredentialService     clusterCredentials.ClusterCredentialService
 }
 type K8sApplicationServiceConfig struct {
 	BatchSize        int `env:"BATCH_SIZE" envDefault:"5"`
@@ -83,7 +85,8 @@ type K8sApplicationServiceConfig struct {
 
 func NewK8sCommonServiceImpl(Logger *zap.SugaredLogger, k8sUtils *k8s.K8sServiceImpl,
 	argoApplicationConfigService config.ArgoApplicationConfigService,
-	ClusterReadService read.ClusterReadService) *K8sCommonServiceImpl {
+	ClusterReadService read.ClusterReadService,
+	clusterCredentialService clusterCredentials.ClusterCredentialService) *K8sCommonServiceImpl {
 	cfg := &K8sApplicationServiceConfig{}
 	err := env.Parse(cfg)
 	if err != nil {
@@ -95,6 +98,7 @@ func NewK8sCommonServiceImpl(Logger *zap.SugaredLogger, k8sUtils *k8s.K8sService
 		K8sApplicationServiceConfig:  cfg,
 		argoApplicationConfigService: argoApplicationConfigService,
 		ClusterReadService:           ClusterReadService,
+		clusterCredentialService:     clusterCredentialService,
 	}
 }
 
@@ -322,7 +326,11 @@ func (impl *K8sCommonServiceImpl) GetRestConfigByClusterId(ctx context.Context,
 		impl.logger.Errorw("error in getting cluster by ID", "err", err, "clusterId", clusterId)
 		return nil, err, nil
 	}
-	clusterConfig := cluster.GetClusterConfig()
+	clusterConfig, err := cluster.ResolveClusterConfig(impl.clusterCredentialService)
+	if err != nil {
+		impl.logger.Errorw("error in resolving cluster credentials", "err", err, "clusterId", clusterId)
+		return nil, err, nil
+	}
 	restConfig, err := impl.K8sUtil.GetRestConfigByCluster(clusterConfig)
 	if err != nil {
 		impl.logger.Errorw("Error in getting rest config", "err", err, "clusterId", clusterId)
@@ -443,7 +451,11 @@ func (impl *K8sCommonServiceImpl) GetCoreClientByClusterId(clusterId int) (*kube
 		return nil, nil, err
 	}
 
-	clusterConfig := clusterBean.GetClusterConfig()
+	clusterConfig, err := clusterBean.ResolveClusterConfig(impl.clusterCredentialService)
+	if err != nil {
+		impl.logger.Errorw("error in resolving cluster credentials", "err", err, "clusterId", clusterId)
+		return nil, nil, err
+	}
 	v1Client, err := impl.K8sUtil.GetCoreV1Client(clusterConfig)
 	if err != nil {
 		//not logging clusterConfig as it contains sensitive data
@@ -693,7 +705,11 @@ func (impl *K8sCommonServiceImpl) PortNumberExtraction(resp []bean5.BatchResourc
 }
 
 func (impl *K8sCommonServiceImpl) GetK8sConfigAndClients(ctx context.Context, cluster *bean2.ClusterBean) (*rest.Config, *http.Client, *kubernetes.Clientset, error) {
-	clusterConfig := cluster.GetClusterConfig()
+	clusterConfig, err := cluster.ResolveClusterConfig(impl.clusterCredentialService)
+	if err != nil {
+		impl.logger.Errorw("error in resolving cluster credentials", "err", err, "clusterId", cluster.Id)
+		return nil, nil, nil, err
+	}
 	return impl.K8sUtil.GetK8sConfigAndClients(clusterConfig)
 }
 
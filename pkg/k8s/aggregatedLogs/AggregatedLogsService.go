@@ -0,0 +1,269 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aggregatedLogs
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"github.com/caarlos0/env"
+	k8s2 "github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/cluster/read"
+	"github.com/devtron-labs/devtron/pkg/k8s"
+	"github.com/devtron-labs/devtron/pkg/k8s/aggregatedLogs/bean"
+	"go.uber.org/zap"
+	"io"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"net/http"
+	"sort"
+)
+
+type AggregatedLogsService interface {
+	// GetLogTargets resolves the containers of the pods selected by the request, pods selected later (e.g. on a
+	// scale up) are not part of the targets
+	GetLogTargets(ctx context.Context, request *bean.AggregatedLogsRequest) (*bean.LogTargets, error)
+	// StreamLogs streams the filtered lines of all the targets as "<timestamp> [pod/container] <message>", followed
+	// logs are written as they arrive and other logs are ordered by time
+	StreamLogs(ctx context.Context, request *bean.AggregatedLogsRequest, logTargets *bean.LogTargets) (io.ReadCloser, error)
+	// DownloadLogs streams a zip archive with a "<pod>/<container>.log" file of filtered lines per target
+	DownloadLogs(ctx context.Context, request *bean.AggregatedLogsRequest, logTargets *bean.LogTargets) (io.ReadCloser, error)
+}
+
+type AggregatedLogsServiceImpl struct {
+	logger             *zap.SugaredLogger
+	k8sUtil            *k8s2.K8sServiceImpl
+	k8sCommonService   k8s.K8sCommonService
+	clusterReadService read.ClusterReadService
+	config             *bean.AggregatedLogsConfig
+}
+
+func GetAggregatedLogsConfig() (*bean.AggregatedLogsConfig, error) {
+	config := &bean.AggregatedLogsConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		return nil, err
+	}
+	return config, err
+}
+
+func NewAggregatedLogsServiceImpl(logger *zap.SugaredLogger, k8sUtil *k8s2.K8sServiceImpl,
+	k8sCommonService k8s.K8sCommonService,
+	clusterReadService read.ClusterReadService,
+	config *bean.AggregatedLogsConfig) *AggregatedLogsServiceImpl {
+	return &AggregatedLogsServiceImpl{
+		logger:             logger,
+		k8sUtil:            k8sUtil,
+		k8sCommonService:   k8sCommonService,
+		clusterReadService: clusterReadService,
+		config:             config,
+	}
+}
+
+func (impl *AggregatedLogsServiceImpl) GetLogTargets(ctx context.Context, request *bean.AggregatedLogsRequest) (*bean.LogTargets, error) {
+	resourceRequest := request.ResourceRequest
+	namespace := resourceRequest.K8sRequest.ResourceIdentifier.Namespace
+	clusterBean, err := impl.clusterReadService.FindById(resourceRequest.ClusterId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cluster detail", "clusterId", resourceRequest.ClusterId, "err", err)
+		return nil, err
+	}
+	restConfig, err := impl.k8sCommonService.GetRestConfigOfCluster(ctx, resourceRequest)
+	if err != nil {
+		return nil, err
+	}
+	selector, err := impl.getPodSelector(ctx, restConfig, namespace, request)
+	if err != nil {
+		return nil, err
+	}
+	_, clientSet, err := impl.k8sUtil.GetK8sConfigAndClientsByRestConfig(restConfig)
+	if err != nil {
+		impl.logger.Errorw("error in clientSet", "err", err)
+		return nil, err
+	}
+	pods, err := clientSet.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		impl.logger.Errorw("error in listing pods", "clusterId", resourceRequest.ClusterId, "namespace", namespace, "selector", selector.String(), "err", err)
+		return nil, err
+	}
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
+	containerName := resourceRequest.K8sRequest.PodLogsRequest.ContainerName
+	targets := make([]*bean.LogTarget, 0)
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == v1.PodPending {
+			// containers of a pending pod have not started, there are no logs to stream yet
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			if len(containerName) > 0 && container.Name != containerName {
+				continue
+			}
+			targets = append(targets, &bean.LogTarget{PodName: pod.Name, ContainerName: container.Name})
+		}
+	}
+	if len(targets) == 0 {
+		msg := fmt.Sprintf("no started pods found for selector %q in namespace %s", selector.String(), namespace)
+		return nil, util.NewApiError(http.StatusNotFound, msg, msg)
+	}
+	if len(targets) > impl.config.MaxStreams {
+		msg := fmt.Sprintf("selector %q matches %d pod containers, at most %d can be streamed together, narrow down the selector or the container", selector.String(), len(targets), impl.config.MaxStreams)
+		return nil, util.NewApiError(http.StatusBadRequest, msg, msg)
+	}
+	return &bean.LogTargets{
+		ClusterName: clusterBean.ClusterName,
+		Namespace:   namespace,
+		Targets:     targets,
+	}, nil
+}
+
+// getPodSelector returns the pod selector of the requested workload or the parsed label selector, an empty selector
+// is rejected as it would select every pod of the namespace
+func (impl *AggregatedLogsServiceImpl) getPodSelector(ctx context.Context, restConfig *rest.Config, namespace string, request *bean.AggregatedLogsRequest) (labels.Selector, error) {
+	if !request.IsWorkloadRequest() {
+		selector, err := labels.Parse(request.LabelSelector)
+		if err != nil {
+			msg := fmt.Sprintf("invalid label selector %q: %s", request.LabelSelector, err.Error())
+			return nil, util.NewApiError(http.StatusBadRequest, msg, msg)
+		}
+		if selector.Empty() {
+			return nil, util.NewApiError(http.StatusBadRequest, "label selector is required", "label selector is required")
+		}
+		return selector, nil
+	}
+	gvk, ok := bean.WorkloadGVKs[request.WorkloadKind]
+	if !ok {
+		msg := fmt.Sprintf("unsupported workload kind %s", request.WorkloadKind)
+		return nil, util.NewApiError(http.StatusBadRequest, msg, msg)
+	}
+	workload, err := impl.k8sUtil.GetResource(ctx, namespace, request.WorkloadName, gvk, restConfig)
+	if err != nil {
+		impl.logger.Errorw("error in fetching workload", "namespace", namespace, "kind", request.WorkloadKind, "name", request.WorkloadName, "err", err)
+		return nil, err
+	}
+	selectorMap, found, err := unstructured.NestedMap(workload.Manifest.Object, "spec", "selector")
+	if err != nil || !found {
+		msg := fmt.Sprintf("%s %s has no pod selector", request.WorkloadKind, request.WorkloadName)
+		return nil, util.NewApiError(http.StatusBadRequest, msg, msg)
+	}
+	labelSelector := &metav1.LabelSelector{}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(selectorMap, labelSelector)
+	if err != nil {
+		impl.logger.Errorw("error in converting workload selector", "kind", request.WorkloadKind, "name", request.WorkloadName, "err", err)
+		return nil, err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil || selector.Empty() {
+		msg := fmt.Sprintf("%s %s has no valid pod selector", request.WorkloadKind, request.WorkloadName)
+		return nil, util.NewApiError(http.StatusBadRequest, msg, msg)
+	}
+	return selector, nil
+}
+
+// openSources opens the log streams of the targets, a target whose logs can not be fetched (e.g. a pod deleted
+// meanwhile) is skipped unless no stream could be opened at all
+func (impl *AggregatedLogsServiceImpl) openSources(ctx context.Context, request *bean.AggregatedLogsRequest, logTargets *bean.LogTargets) ([]*logSource, error) {
+	restConfig, err := impl.k8sCommonService.GetRestConfigOfCluster(ctx, request.ResourceRequest)
+	if err != nil {
+		return nil, err
+	}
+	podLogsRequest := request.ResourceRequest.K8sRequest.PodLogsRequest
+	sources := make([]*logSource, 0, len(logTargets.Targets))
+	for _, target := range logTargets.Targets {
+		stream, err := impl.k8sUtil.GetPodLogs(ctx, restConfig, target.PodName, logTargets.Namespace, podLogsRequest.SinceTime, podLogsRequest.TailLines,
+			podLogsRequest.SinceSeconds, podLogsRequest.Follow, target.ContainerName, podLogsRequest.IsPrevContainerLogsEnabled)
+		if err != nil {
+			impl.logger.Warnw("skipping pod container, error in getting logs", "namespace", logTargets.Namespace, "podName", target.PodName, "containerName", target.ContainerName, "err", err)
+			continue
+		}
+		sources = append(sources, newLogSource(target, stream))
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("could not get logs of any of the %d selected pod containers", len(logTargets.Targets))
+	}
+	return sources, nil
+}
+
+func (impl *AggregatedLogsServiceImpl) StreamLogs(ctx context.Context, request *bean.AggregatedLogsRequest, logTargets *bean.LogTargets) (io.ReadCloser, error) {
+	filter, err := newLineFilter(request.Grep, request.Regex)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := impl.openSources(ctx, request, logTargets)
+	if err != nil {
+		return nil, err
+	}
+	reader, writer := io.Pipe()
+	go func() {
+		var err error
+		if request.ResourceRequest.K8sRequest.PodLogsRequest.Follow {
+			err = writeInterleaved(writer, sources, filter)
+		} else {
+			err = writeOrdered(writer, sources, filter)
+		}
+		writer.CloseWithError(err)
+	}()
+	return &aggregatedLogStream{PipeReader: reader, sources: sources}, nil
+}
+
+func (impl *AggregatedLogsServiceImpl) DownloadLogs(ctx context.Context, request *bean.AggregatedLogsRequest, logTargets *bean.LogTargets) (io.ReadCloser, error) {
+	filter, err := newLineFilter(request.Grep, request.Regex)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := impl.openSources(ctx, request, logTargets)
+	if err != nil {
+		return nil, err
+	}
+	reader, writer := io.Pipe()
+	go func() {
+		err := writeArchive(writer, sources, filter)
+		if err != nil {
+			impl.logger.Errorw("error in writing pod logs archive", "namespace", logTargets.Namespace, "err", err)
+		}
+		writer.CloseWithError(err)
+	}()
+	return &aggregatedLogStream{PipeReader: reader, sources: sources}, nil
+}
+
+func writeArchive(w io.Writer, sources []*logSource, filter *lineFilter) error {
+	archive := zip.NewWriter(w)
+	for _, source := range sources {
+		file, err := archive.Create(fmt.Sprintf("%s/%s.log", source.target.PodName, source.target.ContainerName))
+		if err != nil {
+			return err
+		}
+		for {
+			line, err := source.next(filter)
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return err
+			}
+			if _, err = file.Write([]byte(line.timestamp + " " + line.message + "\n")); err != nil {
+				return err
+			}
+		}
+	}
+	return archive.Close()
+}
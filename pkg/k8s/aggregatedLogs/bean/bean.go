@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	bean2 "github.com/devtron-labs/devtron/pkg/k8s/bean"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	LabelSelectorParam = "labelSelector"
+	WorkloadKindParam  = "workloadKind"
+	WorkloadNameParam  = "workloadName"
+	GrepParam          = "grep"
+	RegexParam         = "regex"
+
+	ArchiveFilePrefix = "podlogs"
+)
+
+// WorkloadGVKs are the workloads whose pod selector can be used to select the pods to stream logs of
+var WorkloadGVKs = map[string]schema.GroupVersionKind{
+	"Deployment":  {Group: "apps", Version: "v1", Kind: "Deployment"},
+	"StatefulSet": {Group: "apps", Version: "v1", Kind: "StatefulSet"},
+	"Rollout":     {Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout"},
+}
+
+type AggregatedLogsConfig struct {
+	// MaxStreams caps the number of pod containers whose logs are streamed in one request
+	MaxStreams int `env:"AGGREGATED_LOGS_MAX_STREAMS" envDefault:"100"`
+}
+
+// AggregatedLogsRequest selects pods either by LabelSelector or by the pod selector of a workload, the pod logs
+// options (since time, tail lines, follow, container) are taken from ResourceRequest.K8sRequest.PodLogsRequest
+type AggregatedLogsRequest struct {
+	ResourceRequest *bean2.ResourceRequestBean
+	LabelSelector   string
+	WorkloadKind    string
+	WorkloadName    string
+	// Grep keeps the lines containing the text, Regex keeps the lines matching the expression, both apply when set
+	Grep  string
+	Regex string
+}
+
+func (request *AggregatedLogsRequest) IsWorkloadRequest() bool {
+	return len(request.WorkloadKind) > 0
+}
+
+type LogTarget struct {
+	PodName       string
+	ContainerName string
+}
+
+type LogTargets struct {
+	ClusterName string
+	Namespace   string
+	Targets     []*LogTarget
+}
+
+// PodNames returns the distinct pods of the targets in order
+func (logTargets *LogTargets) PodNames() []string {
+	podNames := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, target := range logTargets.Targets {
+		if !seen[target.PodName] {
+			seen[target.PodName] = true
+			podNames = append(podNames, target.PodName)
+		}
+	}
+	return podNames
+}
@@ -0,0 +1,208 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aggregatedLogs
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/k8s/aggregatedLogs/bean"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// lineFilter keeps the log lines whose message contains grep and matches regex, a nil filter keeps all the lines
+type lineFilter struct {
+	grep  string
+	regex *regexp.Regexp
+}
+
+func newLineFilter(grep, regex string) (*lineFilter, error) {
+	if len(grep) == 0 && len(regex) == 0 {
+		return nil, nil
+	}
+	filter := &lineFilter{grep: grep}
+	if len(regex) > 0 {
+		compiled, err := regexp.Compile(regex)
+		if err != nil {
+			msg := fmt.Sprintf("invalid regex %q: %s", regex, err.Error())
+			return nil, util.NewApiError(http.StatusBadRequest, msg, msg)
+		}
+		filter.regex = compiled
+	}
+	return filter, nil
+}
+
+func (filter *lineFilter) matches(message string) bool {
+	if filter == nil {
+		return true
+	}
+	if len(filter.grep) > 0 && !strings.Contains(message, filter.grep) {
+		return false
+	}
+	return filter.regex == nil || filter.regex.MatchString(message)
+}
+
+type logLine struct {
+	time      time.Time
+	timestamp string
+	message   string
+}
+
+// logSource reads the lines of the log stream of one container, the lines are expected to be prefixed with their
+// RFC3339 timestamp as the kubernetes api returns them
+type logSource struct {
+	target   *bean.LogTarget
+	prefix   string
+	reader   *bufio.Reader
+	stream   io.ReadCloser
+	lastTime time.Time
+}
+
+func newLogSource(target *bean.LogTarget, stream io.ReadCloser) *logSource {
+	return &logSource{
+		target: target,
+		prefix: fmt.Sprintf("[%s/%s]", target.PodName, target.ContainerName),
+		reader: bufio.NewReader(stream),
+		stream: stream,
+	}
+}
+
+// next returns the next line which passes the filter, io.EOF is returned once the stream is drained
+func (source *logSource) next(filter *lineFilter) (*logLine, error) {
+	for {
+		raw, err := source.reader.ReadString('\n')
+		if len(raw) == 0 && err != nil {
+			return nil, err
+		}
+		line := source.parse(strings.TrimRight(raw, "\r\n"))
+		if filter.matches(line.message) {
+			return line, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parse splits the timestamp off the line, a line without a valid timestamp is given the time of the previous line
+// so that it stays in place in the merged stream and the timestamp is always the first field of the formatted line
+func (source *logSource) parse(raw string) *logLine {
+	splitLog := strings.SplitN(raw, " ", 2)
+	if parsedTime, err := time.Parse(time.RFC3339Nano, splitLog[0]); err == nil {
+		source.lastTime = parsedTime
+		line := &logLine{time: parsedTime, timestamp: splitLog[0]}
+		if len(splitLog) == 2 {
+			line.message = splitLog[1]
+		}
+		return line
+	}
+	if source.lastTime.IsZero() {
+		source.lastTime = time.Now().UTC()
+	}
+	return &logLine{time: source.lastTime, timestamp: source.lastTime.Format(time.RFC3339Nano), message: raw}
+}
+
+// format writes the line as "<timestamp> [pod/container] <message>"
+func (source *logSource) format(line *logLine) []byte {
+	return []byte(line.timestamp + " " + source.prefix + " " + line.message + "\n")
+}
+
+func (source *logSource) close() error {
+	return source.stream.Close()
+}
+
+// writeOrdered merges the lines of all the sources ordered by time, every source is read till its end so the sources
+// must not be followed streams
+func writeOrdered(w io.Writer, sources []*logSource, filter *lineFilter) error {
+	heads := make([]*logLine, len(sources))
+	var err error
+	for i, source := range sources {
+		heads[i], err = source.next(filter)
+		if err != nil && err != io.EOF {
+			return err
+		}
+	}
+	for {
+		earliest := -1
+		for i, head := range heads {
+			if head != nil && (earliest < 0 || head.time.Before(heads[earliest].time)) {
+				earliest = i
+			}
+		}
+		if earliest < 0 {
+			return nil
+		}
+		if _, err = w.Write(sources[earliest].format(heads[earliest])); err != nil {
+			return err
+		}
+		heads[earliest], err = sources[earliest].next(filter)
+		if err != nil && err != io.EOF {
+			return err
+		}
+	}
+}
+
+// writeInterleaved writes the lines of all the sources as they arrive, used for followed streams which do not end
+// till the request is cancelled
+func writeInterleaved(w io.Writer, sources []*logSource, filter *lineFilter) error {
+	var writeLock sync.Mutex
+	var writeErr error
+	wg := sync.WaitGroup{}
+	for _, source := range sources {
+		wg.Add(1)
+		go func(source *logSource) {
+			defer wg.Done()
+			for {
+				line, err := source.next(filter)
+				if err != nil {
+					return
+				}
+				writeLock.Lock()
+				if writeErr == nil {
+					_, writeErr = w.Write(source.format(line))
+				}
+				failed := writeErr != nil
+				writeLock.Unlock()
+				if failed {
+					return
+				}
+			}
+		}(source)
+	}
+	wg.Wait()
+	return writeErr
+}
+
+// aggregatedLogStream closes the log streams of all the sources along with the reader, which unblocks the writers
+// of followed streams
+type aggregatedLogStream struct {
+	*io.PipeReader
+	sources []*logSource
+}
+
+func (stream *aggregatedLogStream) Close() error {
+	err := stream.PipeReader.Close()
+	for _, source := range stream.sources {
+		_ = source.close()
+	}
+	return err
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aggregatedLogs
+
+import (
+	"archive/zip"
+	"bytes"
+	"github.com/devtron-labs/devtron/pkg/k8s/aggregatedLogs/bean"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func newTestSource(podName, containerName string, lines ...string) *logSource {
+	stream := io.NopCloser(strings.NewReader(strings.Join(lines, "\n")))
+	return newLogSource(&bean.LogTarget{PodName: podName, ContainerName: containerName}, stream)
+}
+
+func TestWriteOrdered(t *testing.T) {
+	t.Run("lines are merged by time and prefixed", func(t *testing.T) {
+		sources := []*logSource{
+			newTestSource("app-1", "main", "2024-05-01T10:00:00.000000001Z started", "2024-05-01T10:00:02Z serving"),
+			newTestSource("app-2", "main", "2024-05-01T10:00:01Z started", "2024-05-01T10:00:03Z serving"),
+		}
+		var buf bytes.Buffer
+		err := writeOrdered(&buf, sources, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "2024-05-01T10:00:00.000000001Z [app-1/main] started\n"+
+			"2024-05-01T10:00:01Z [app-2/main] started\n"+
+			"2024-05-01T10:00:02Z [app-1/main] serving\n"+
+			"2024-05-01T10:00:03Z [app-2/main] serving\n", buf.String())
+	})
+
+	t.Run("grep and regex filter the messages", func(t *testing.T) {
+		filter, err := newLineFilter("error", `code=5\d\d`)
+		assert.NoError(t, err)
+		sources := []*logSource{
+			newTestSource("app-1", "main", "2024-05-01T10:00:00Z error code=404", "2024-05-01T10:00:02Z error code=503"),
+			newTestSource("app-2", "sidecar", "2024-05-01T10:00:01Z info code=500", "2024-05-01T10:00:03Z error code=502"),
+		}
+		var buf bytes.Buffer
+		err = writeOrdered(&buf, sources, filter)
+		assert.NoError(t, err)
+		assert.Equal(t, "2024-05-01T10:00:02Z [app-1/main] error code=503\n"+
+			"2024-05-01T10:00:03Z [app-2/sidecar] error code=502\n", buf.String())
+	})
+
+	t.Run("line without timestamp keeps the time of the previous line", func(t *testing.T) {
+		sources := []*logSource{
+			newTestSource("app-1", "main", "2024-05-01T10:00:00Z panic: boom", "\tat main.go:10"),
+		}
+		var buf bytes.Buffer
+		err := writeOrdered(&buf, sources, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "2024-05-01T10:00:00Z [app-1/main] panic: boom\n"+
+			"2024-05-01T10:00:00Z [app-1/main] \tat main.go:10\n", buf.String())
+	})
+}
+
+func TestWriteInterleaved(t *testing.T) {
+	sources := []*logSource{
+		newTestSource("app-1", "main", "2024-05-01T10:00:00Z a", "2024-05-01T10:00:02Z b"),
+		newTestSource("app-2", "main", "2024-05-01T10:00:01Z c"),
+	}
+	var buf bytes.Buffer
+	err := writeInterleaved(&buf, sources, nil)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	sort.Strings(lines)
+	assert.Equal(t, []string{"2024-05-01T10:00:00Z [app-1/main] a", "2024-05-01T10:00:01Z [app-2/main] c", "2024-05-01T10:00:02Z [app-1/main] b"}, lines)
+}
+
+func TestWriteArchive(t *testing.T) {
+	sources := []*logSource{
+		newTestSource("app-1", "main", "2024-05-01T10:00:00Z a"),
+		newTestSource("app-2", "main", "2024-05-01T10:00:01Z b"),
+	}
+	var buf bytes.Buffer
+	err := writeArchive(&buf, sources, nil)
+	assert.NoError(t, err)
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	files := make(map[string]string)
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		assert.NoError(t, err)
+		content, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		files[file.Name] = string(content)
+	}
+	assert.Equal(t, map[string]string{
+		"app-1/main.log": "2024-05-01T10:00:00Z a\n",
+		"app-2/main.log": "2024-05-01T10:00:01Z b\n",
+	}, files)
+}
+
+func TestNewLineFilter(t *testing.T) {
+	filter, err := newLineFilter("", "")
+	assert.NoError(t, err)
+	assert.Nil(t, filter)
+	assert.True(t, filter.matches("anything"))
+	_, err = newLineFilter("", "(")
+	assert.Error(t, err)
+}
@@ -24,6 +24,7 @@ import (
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/kubernetes"
+	"sort"
 	"strings"
 	"time"
 )
@@ -177,8 +178,8 @@ type NodeDrainHelper struct {
 	GracePeriodSeconds  int  `json:"gracePeriodSeconds"`
 	IgnoreAllDaemonSets bool `json:"ignoreAllDaemonSets"`
 	// DisableEviction forces drain to use delete rather than evict
-	DisableEviction bool `json:"disableEviction"`
-	K8sClientSet    *kubernetes.Clientset
+	DisableEviction bool                  `json:"disableEviction"`
+	K8sClientSet    *kubernetes.Clientset `json:"-"`
 }
 
 type NodeDetails struct {
@@ -227,6 +228,22 @@ func (l *PodDeleteList) Errors() []error {
 	return errs
 }
 
+// Warnings returns the warnings of the filtered pods grouped by the warning, e.g. ignored DaemonSet-managed pods
+func (l *PodDeleteList) Warnings() []string {
+	warnedPods := make(map[string][]string)
+	for _, i := range l.items {
+		if i.Status.Reason == PodDeleteStatusTypeWarning {
+			warnedPods[i.Status.Message] = append(warnedPods[i.Status.Message], fmt.Sprintf("%s/%s", i.Pod.Namespace, i.Pod.Name))
+		}
+	}
+	warnings := make([]string, 0, len(warnedPods))
+	for msg, pods := range warnedPods {
+		warnings = append(warnings, fmt.Sprintf("%s: %s", msg, strings.Join(pods, ", ")))
+	}
+	sort.Strings(warnings)
+	return warnings
+}
+
 // PodDeleteStatus informs filters if a pod should be deleted
 type PodDeleteStatus struct {
 	Delete  bool
@@ -339,6 +339,11 @@ func (impl *K8sCapacityServiceImpl) getNodeGroupAndTaints(node *corev1.Node) (st
 }
 
 func (impl *K8sCapacityServiceImpl) getNodeGroup(node *corev1.Node) string {
+	return GetNodeGroup(node)
+}
+
+// GetNodeGroup returns the node group of the node out of the node group labels of the cloud providers
+func GetNodeGroup(node *corev1.Node) string {
 	var nodeGroup = ""
 	//different cloud providers have their own node group label
 	for _, label := range bean.NodeGroupLabels {
@@ -660,7 +665,7 @@ func (impl *K8sCapacityServiceImpl) DrainNode(ctx context.Context, request *bean
 		}
 	}
 	request.NodeDrainHelper.K8sClientSet = k8sClientSet
-	err = impl.deleteOrEvictPods(request.Name, request.NodeDrainHelper)
+	err = impl.deleteOrEvictPods(ctx, request.Name, request.NodeDrainHelper)
 	if err != nil {
 		if client.IsDaemonSetPodDeleteError(err) {
 			impl.logger.Errorw("daemonSet-managed pods can't be deleted", "err", err, "nodeName", request.Name)
@@ -770,7 +775,7 @@ func validateTaintEffect(effect corev1.TaintEffect) error {
 	return nil
 }
 
-func (impl *K8sCapacityServiceImpl) deleteOrEvictPods(nodeName string, nodeDrainHelper *bean.NodeDrainHelper) error {
+func (impl *K8sCapacityServiceImpl) deleteOrEvictPods(ctx context.Context, nodeName string, nodeDrainHelper *bean.NodeDrainHelper) error {
 	impl.logger.Infow("received node drain - deleteOrEvictPods request", "nodeName", nodeName, "nodeDrainHelper", nodeDrainHelper)
 	list, errs := GetPodsByNodeNameForDeletion(nodeName, nodeDrainHelper)
 	if errs != nil {
@@ -795,13 +800,13 @@ func (impl *K8sCapacityServiceImpl) deleteOrEvictPods(nodeName string, nodeDrain
 			return err
 		}
 		if !evictionGroupVersion.Empty() {
-			return impl.evictPods(pods, nodeDrainHelper.K8sClientSet, evictionGroupVersion, deleteOptions)
+			return impl.evictPods(ctx, pods, nodeDrainHelper.K8sClientSet, evictionGroupVersion, deleteOptions)
 		}
 	}
 	return nil
 }
 
-func (impl *K8sCapacityServiceImpl) evictPods(pods []corev1.Pod, k8sClientSet *kubernetes.Clientset, evictionGroupVersion schema.GroupVersion, deleteOptions v1.DeleteOptions) error {
+func (impl *K8sCapacityServiceImpl) evictPods(ctx context.Context, pods []corev1.Pod, k8sClientSet *kubernetes.Clientset, evictionGroupVersion schema.GroupVersion, deleteOptions v1.DeleteOptions) error {
 	impl.logger.Infow("receive pod eviction request", "pods", pods)
 	returnCh := make(chan error, 1)
 	for _, pod := range pods {
@@ -809,18 +814,26 @@ func (impl *K8sCapacityServiceImpl) evictPods(pods []corev1.Pod, k8sClientSet *k
 		go func(pod corev1.Pod, returnCh chan error) {
 			// Create a temporary pod, so we don't mutate the pod in the loop.
 			activePod := pod
-			err := k8s2.EvictPod(activePod, k8sClientSet, evictionGroupVersion, deleteOptions)
-			if err == nil {
-				returnCh <- nil
-				return
-			} else if apierrors.IsNotFound(err) {
-				returnCh <- nil
-				return
-			} else if apierrors.IsTooManyRequests(err) {
-				time.Sleep(5 * time.Second)
-			} else {
-				returnCh <- fmt.Errorf("error when evicting pods/%q -n %q: %v", activePod.Name, activePod.Namespace, err)
-				return
+			for {
+				err := k8s2.EvictPod(activePod, k8sClientSet, evictionGroupVersion, deleteOptions)
+				if err == nil {
+					returnCh <- nil
+					return
+				} else if apierrors.IsNotFound(err) {
+					returnCh <- nil
+					return
+				} else if apierrors.IsTooManyRequests(err) {
+					// eviction is disallowed by a pod disruption budget for now, retrying till the context is done
+					select {
+					case <-ctx.Done():
+						returnCh <- fmt.Errorf("error when evicting pods/%q -n %q: %v", activePod.Name, activePod.Namespace, err)
+						return
+					case <-time.After(5 * time.Second):
+					}
+				} else {
+					returnCh <- fmt.Errorf("error when evicting pods/%q -n %q: %v", activePod.Name, activePod.Namespace, err)
+					return
+				}
 			}
 		}(pod, returnCh)
 	}
@@ -0,0 +1,362 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package capacityHistory
+
+import (
+	"context"
+	"fmt"
+	"github.com/caarlos0/env/v6"
+	k8s2 "github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/util"
+	chartRepoRepository "github.com/devtron-labs/devtron/pkg/chartRepo/repository"
+	"github.com/devtron-labs/devtron/pkg/cluster"
+	bean2 "github.com/devtron-labs/devtron/pkg/cluster/bean"
+	repository2 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/deployment/manifest/deploymentTemplate/read"
+	"github.com/devtron-labs/devtron/pkg/k8s"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacityHistory/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacityHistory/repository"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"net/http"
+	"sort"
+	"time"
+)
+
+type CapacityHistoryService interface {
+	// CaptureSnapshots records the capacity of every reachable cluster and purges the snapshots past retention
+	CaptureSnapshots()
+	GetCapacityHistory(request *bean.CapacityHistoryRequest) (*bean.CapacityHistory, error)
+	// GetRecommendations compares the deployment template resources of the devtron apps of a cluster with their usage
+	// observed over the lookback window
+	GetRecommendations(request *bean.RecommendationRequest) ([]*bean.RightSizingRecommendation, error)
+}
+
+type CapacityHistoryServiceImpl struct {
+	logger                    *zap.SugaredLogger
+	k8sUtil                   *k8s2.K8sServiceImpl
+	k8sCommonService          k8s.K8sCommonService
+	clusterService            cluster.ClusterService
+	capacityHistoryRepository repository.CapacityHistoryRepository
+	appRepository             app.AppRepository
+	environmentRepository     repository2.EnvironmentRepository
+	chartRepository           chartRepoRepository.ChartRepository
+	envConfigOverrideService  read.EnvConfigOverrideService
+	config                    *bean.CapacityHistoryConfig
+	snapshotCron              *cron.Cron
+}
+
+func GetCapacityHistoryConfig() (*bean.CapacityHistoryConfig, error) {
+	config := &bean.CapacityHistoryConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		return nil, err
+	}
+	return config, err
+}
+
+func NewCapacityHistoryServiceImpl(logger *zap.SugaredLogger, k8sUtil *k8s2.K8sServiceImpl,
+	k8sCommonService k8s.K8sCommonService,
+	clusterService cluster.ClusterService,
+	capacityHistoryRepository repository.CapacityHistoryRepository,
+	appRepository app.AppRepository,
+	environmentRepository repository2.EnvironmentRepository,
+	chartRepository chartRepoRepository.ChartRepository,
+	envConfigOverrideService read.EnvConfigOverrideService,
+	cronLogger *cron2.CronLoggerImpl) (*CapacityHistoryServiceImpl, error) {
+	config, err := GetCapacityHistoryConfig()
+	if err != nil {
+		logger.Errorw("error in parsing capacity history config", "err", err)
+		return nil, err
+	}
+	snapshotCron := cron.New(cron.WithChain(cron.SkipIfStillRunning(cronLogger), cron.Recover(cronLogger)))
+	impl := &CapacityHistoryServiceImpl{
+		logger:                    logger,
+		k8sUtil:                   k8sUtil,
+		k8sCommonService:          k8sCommonService,
+		clusterService:            clusterService,
+		capacityHistoryRepository: capacityHistoryRepository,
+		appRepository:             appRepository,
+		environmentRepository:     environmentRepository,
+		chartRepository:           chartRepository,
+		envConfigOverrideService:  envConfigOverrideService,
+		config:                    config,
+		snapshotCron:              snapshotCron,
+	}
+	if config.Enabled {
+		snapshotCron.Start()
+		_, err = snapshotCron.AddFunc(fmt.Sprintf("@every %ds", config.SnapshotIntervalSecs), impl.CaptureSnapshots)
+		if err != nil {
+			logger.Errorw("error in starting capacity snapshot cron", "err", err)
+			return nil, err
+		}
+	}
+	return impl, nil
+}
+
+func (impl *CapacityHistoryServiceImpl) CaptureSnapshots() {
+	clusters, err := impl.clusterService.FindAllExceptVirtual()
+	if err != nil {
+		impl.logger.Errorw("error in fetching clusters for capacity snapshots", "err", err)
+		return
+	}
+	capturedOn := time.Now()
+	for _, clusterBean := range clusters {
+		if len(clusterBean.ErrorInConnecting) > 0 {
+			continue
+		}
+		err = impl.captureClusterSnapshots(clusterBean, capturedOn)
+		if err != nil {
+			impl.logger.Errorw("error in capturing capacity snapshots", "clusterId", clusterBean.Id, "err", err)
+		}
+	}
+	retentionStart := capturedOn.AddDate(0, 0, -impl.config.RetentionDays)
+	err = impl.capacityHistoryRepository.DeleteSnapshotsBefore(retentionStart)
+	if err != nil {
+		impl.logger.Errorw("error in purging capacity snapshots", "before", retentionStart, "err", err)
+	}
+}
+
+func (impl *CapacityHistoryServiceImpl) captureClusterSnapshots(clusterBean *bean2.ClusterBean, capturedOn time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	restConfig, k8sHttpClient, k8sClientSet, err := impl.k8sCommonService.GetK8sConfigAndClients(ctx, clusterBean)
+	if err != nil {
+		return err
+	}
+	nodeList, err := impl.k8sUtil.GetNodesList(ctx, k8sClientSet)
+	if err != nil {
+		return err
+	}
+	podList, err := impl.k8sUtil.GetPodsListForNamespace(ctx, k8sClientSet, corev1.NamespaceAll)
+	if err != nil {
+		return err
+	}
+	usage := &clusterUsage{}
+	metricsClientSet, err := impl.k8sUtil.GetMetricsClientSet(restConfig, k8sHttpClient)
+	if err != nil {
+		impl.logger.Warnw("error in getting metrics client set, capturing capacity without usage", "clusterId", clusterBean.Id, "err", err)
+	} else {
+		// metrics-server is optional, the snapshots are captured without usage when it is not installed
+		nodeMetricsList, err := impl.k8sUtil.GetNmList(ctx, metricsClientSet)
+		if err == nil && nodeMetricsList != nil {
+			usage.nodeUsage = make(map[string]corev1.ResourceList, len(nodeMetricsList.Items))
+			for _, nodeMetrics := range nodeMetricsList.Items {
+				usage.nodeUsage[nodeMetrics.Name] = nodeMetrics.Usage
+			}
+		}
+		podMetricsList, err := metricsClientSet.MetricsV1beta1().PodMetricses(corev1.NamespaceAll).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			impl.logger.Warnw("error in getting pod metrics, capturing capacity without pod usage", "clusterId", clusterBean.Id, "err", err)
+		} else {
+			usage.podUsage = make(map[string]map[string]corev1.ResourceList, len(podMetricsList.Items))
+			for _, podMetrics := range podMetricsList.Items {
+				containerUsage := make(map[string]corev1.ResourceList, len(podMetrics.Containers))
+				for _, container := range podMetrics.Containers {
+					containerUsage[container.Name] = container.Usage
+				}
+				usage.podUsage[podMetrics.Namespace+"/"+podMetrics.Name] = containerUsage
+			}
+		}
+	}
+	snapshots, appSnapshots := buildSnapshots(clusterBean.Id, nodeList.Items, podList.Items, usage, capturedOn)
+	return impl.capacityHistoryRepository.SaveSnapshots(snapshots, appSnapshots)
+}
+
+func (impl *CapacityHistoryServiceImpl) GetCapacityHistory(request *bean.CapacityHistoryRequest) (*bean.CapacityHistory, error) {
+	if request.Scope != bean.ScopeCluster && request.Scope != bean.ScopeNode && request.Scope != bean.ScopeNamespace {
+		msg := fmt.Sprintf("invalid scope %s, supported scopes are %s, %s and %s", request.Scope, bean.ScopeCluster, bean.ScopeNode, bean.ScopeNamespace)
+		return nil, util.NewApiError(http.StatusBadRequest, msg, msg)
+	}
+	if request.To.IsZero() {
+		request.To = time.Now()
+	}
+	if request.From.IsZero() {
+		request.From = request.To.Add(-24 * time.Hour)
+	}
+	if !request.From.Before(request.To) {
+		msg := "from has to be before to"
+		return nil, util.NewApiError(http.StatusBadRequest, msg, msg)
+	}
+	snapshots, err := impl.capacityHistoryRepository.FindSnapshots(request.ClusterId, request.Scope, request.Names, request.From, request.To)
+	if err != nil {
+		impl.logger.Errorw("error in fetching capacity snapshots", "request", request, "err", err)
+		return nil, err
+	}
+	return &bean.CapacityHistory{
+		ClusterId: request.ClusterId,
+		Scope:     request.Scope,
+		From:      request.From,
+		To:        request.To,
+		Series:    toHistorySeries(snapshots),
+	}, nil
+}
+
+func (impl *CapacityHistoryServiceImpl) GetRecommendations(request *bean.RecommendationRequest) ([]*bean.RightSizingRecommendation, error) {
+	from := time.Now().AddDate(0, 0, -impl.config.RecommendationDays)
+	var snapshots []*repository.AppResourceUsageSnapshot
+	var err error
+	if request.AppId > 0 && request.EnvId > 0 {
+		snapshots, err = impl.capacityHistoryRepository.FindAppSnapshots(request.AppId, request.EnvId, from)
+	} else {
+		snapshots, err = impl.capacityHistoryRepository.FindAppSnapshotsByClusterId(request.ClusterId, from)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in fetching app resource usage snapshots", "request", request, "err", err)
+		return nil, err
+	}
+	snapshotsByApp := make(map[appEnvKey][]*repository.AppResourceUsageSnapshot)
+	keys := make([]appEnvKey, 0)
+	for _, snapshot := range snapshots {
+		if snapshot.ClusterId != request.ClusterId || (request.AppId > 0 && snapshot.AppId != request.AppId) {
+			continue
+		}
+		key := appEnvKey{appId: snapshot.AppId, envId: snapshot.EnvId}
+		if _, ok := snapshotsByApp[key]; !ok {
+			keys = append(keys, key)
+		}
+		snapshotsByApp[key] = append(snapshotsByApp[key], snapshot)
+	}
+	appNames, envNames, err := impl.getAppAndEnvNames(keys)
+	if err != nil {
+		return nil, err
+	}
+	recommendations := make([]*bean.RightSizingRecommendation, 0, len(keys))
+	for _, key := range keys {
+		appName, ok := appNames[key.appId]
+		if !ok {
+			// the app has been deleted since
+			continue
+		}
+		recommendation, err := impl.getRecommendation(key, snapshotsByApp[key])
+		if err != nil {
+			return nil, err
+		}
+		recommendation.AppName = appName
+		recommendation.EnvName = envNames[key.envId]
+		recommendations = append(recommendations, recommendation)
+	}
+	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].AppName != recommendations[j].AppName {
+			return recommendations[i].AppName < recommendations[j].AppName
+		}
+		return recommendations[i].EnvName < recommendations[j].EnvName
+	})
+	return recommendations, nil
+}
+
+func (impl *CapacityHistoryServiceImpl) getAppAndEnvNames(keys []appEnvKey) (map[int]string, map[int]string, error) {
+	appNames, envNames := make(map[int]string), make(map[int]string)
+	if len(keys) == 0 {
+		return appNames, envNames, nil
+	}
+	appIds, envIds := make([]*int, 0, len(keys)), make([]*int, 0, len(keys))
+	for _, key := range keys {
+		appId, envId := key.appId, key.envId
+		appIds = append(appIds, &appId)
+		envIds = append(envIds, &envId)
+	}
+	apps, err := impl.appRepository.FindByIds(appIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching apps", "err", err)
+		return nil, nil, err
+	}
+	environments, err := impl.environmentRepository.FindByIds(envIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environments", "err", err)
+		return nil, nil, err
+	}
+	for _, devtronApp := range apps {
+		appNames[devtronApp.Id] = devtronApp.AppName
+	}
+	for _, environment := range environments {
+		envNames[environment.Id] = environment.Name
+	}
+	return appNames, envNames, nil
+}
+
+// getRecommendation recommends against the resources of the deployment template the environment deploys, the resources
+// of the running pods are compared when the template resources can not be read
+func (impl *CapacityHistoryServiceImpl) getRecommendation(key appEnvKey, snapshots []*repository.AppResourceUsageSnapshot) (*bean.RightSizingRecommendation, error) {
+	suggestion, templateValues, err := impl.getDeploymentTemplate(key)
+	if err != nil {
+		return nil, err
+	}
+	current, fromTemplate := templateResources(templateValues)
+	if !fromTemplate {
+		latest := snapshots[len(snapshots)-1]
+		current = &bean.ContainerResources{
+			CpuRequest:    latest.CpuRequestsMillis,
+			CpuLimit:      latest.CpuLimitsMillis,
+			MemoryRequest: latest.MemoryRequestsBytes,
+			MemoryLimit:   latest.MemoryLimitsBytes,
+		}
+	}
+	recommendation := recommend(current, snapshots, impl.config)
+	recommendation.AppId = key.appId
+	recommendation.EnvId = key.envId
+	if recommendation.Status != bean.RecommendationOverProvisioned && recommendation.Status != bean.RecommendationUnderProvisioned {
+		return recommendation, nil
+	}
+	if suggestion == nil || !fromTemplate && templateValues["resources"] != nil {
+		// resources set in a form which can not be patched safely, like scoped variables
+		recommendation.Message = "resources of the deployment template could not be read, patch suggestion is not available"
+		return recommendation, nil
+	}
+	suggestion.Patch, suggestion.Resources = buildPatch(templateValues, recommendation.Recommended)
+	recommendation.Suggestion = suggestion
+	return recommendation, nil
+}
+
+// getDeploymentTemplate returns the deployment template values the environment deploys with, the environment override
+// when the environment overrides the base template
+func (impl *CapacityHistoryServiceImpl) getDeploymentTemplate(key appEnvKey) (*bean.PatchSuggestion, map[string]interface{}, error) {
+	envOverride, err := impl.envConfigOverrideService.ActiveEnvConfigOverride(key.appId, key.envId)
+	if err != nil {
+		return nil, nil, err
+	}
+	suggestion := &bean.PatchSuggestion{}
+	var values string
+	if envOverride != nil && envOverride.Id > 0 && envOverride.IsOverride {
+		suggestion.TemplateScope = bean.TemplateScopeEnvOverride
+		suggestion.EnvOverrideId = envOverride.Id
+		suggestion.ChartId = envOverride.ChartId
+		values = envOverride.EnvOverrideValues
+	} else {
+		chart, err := impl.chartRepository.FindLatestChartForAppByAppId(key.appId)
+		if err != nil {
+			if util.IsErrNoRows(err) {
+				return nil, map[string]interface{}{}, nil
+			}
+			impl.logger.Errorw("error in getting chart by appId", "appId", key.appId, "err", err)
+			return nil, nil, err
+		}
+		suggestion.TemplateScope = bean.TemplateScopeBase
+		suggestion.ChartId = chart.Id
+		values = chart.GlobalOverride
+	}
+	templateValues, err := parseTemplateValues(values)
+	if err != nil {
+		impl.logger.Warnw("error in parsing deployment template values", "appId", key.appId, "envId", key.envId, "err", err)
+		return nil, map[string]interface{}{}, nil
+	}
+	return suggestion, templateValues, nil
+}
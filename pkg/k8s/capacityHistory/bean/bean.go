@@ -0,0 +1,154 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"time"
+)
+
+const (
+	ScopeCluster   = "cluster"
+	ScopeNode      = "node"
+	ScopeNamespace = "namespace"
+)
+
+const (
+	// DevtronAppIdLabel and DevtronEnvIdLabel are set on the pods of the devtron charts
+	DevtronAppIdLabel = "appId"
+	DevtronEnvIdLabel = "envId"
+)
+
+const (
+	RecommendationOverProvisioned  = "OverProvisioned"
+	RecommendationUnderProvisioned = "UnderProvisioned"
+	RecommendationRightSized       = "RightSized"
+	RecommendationInsufficientData = "InsufficientData"
+)
+
+const (
+	TemplateScopeBase        = "BASE"
+	TemplateScopeEnvOverride = "ENV_OVERRIDE"
+)
+
+type CapacityHistoryConfig struct {
+	Enabled                 bool `env:"CAPACITY_HISTORY_ENABLED" envDefault:"true"`
+	SnapshotIntervalSecs    int  `env:"CAPACITY_SNAPSHOT_INTERVAL_SECS" envDefault:"900"`
+	RetentionDays           int  `env:"CAPACITY_HISTORY_RETENTION_DAYS" envDefault:"30"`
+	RecommendationDays      int  `env:"RIGHT_SIZING_LOOKBACK_DAYS" envDefault:"7"`
+	RecommendationMinSample int  `env:"RIGHT_SIZING_MIN_SAMPLES" envDefault:"24"`
+	// HeadroomPercent is added over the observed usage, ThresholdPercent is how far the requests can be from the
+	// recommendation before the app is reported as over provisioned
+	HeadroomPercent  int `env:"RIGHT_SIZING_HEADROOM_PERCENT" envDefault:"20"`
+	ThresholdPercent int `env:"RIGHT_SIZING_THRESHOLD_PERCENT" envDefault:"30"`
+}
+
+type CapacityHistoryRequest struct {
+	ClusterId int
+	Scope     string
+	Names     []string
+	From      time.Time
+	To        time.Time
+}
+
+type ResourceHistoryPoint struct {
+	Allocatable int64 `json:"allocatable"`
+	Requests    int64 `json:"requests"`
+	Limits      int64 `json:"limits"`
+	Usage       int64 `json:"usage"`
+}
+
+type CapacityHistoryPoint struct {
+	CapturedOn time.Time `json:"capturedOn"`
+	// Cpu is in millicores and Memory in bytes, usage is zero when metrics were not available
+	Cpu              ResourceHistoryPoint `json:"cpu"`
+	Memory           ResourceHistoryPoint `json:"memory"`
+	PodCount         int                  `json:"podCount"`
+	MetricsAvailable bool                 `json:"metricsAvailable"`
+}
+
+type CapacityHistorySeries struct {
+	Name   string                  `json:"name"`
+	Points []*CapacityHistoryPoint `json:"points"`
+}
+
+type CapacityHistory struct {
+	ClusterId int                      `json:"clusterId"`
+	Scope     string                   `json:"scope"`
+	From      time.Time                `json:"from"`
+	To        time.Time                `json:"to"`
+	Series    []*CapacityHistorySeries `json:"series"`
+}
+
+type RecommendationRequest struct {
+	ClusterId int
+	AppId     int
+	EnvId     int
+}
+
+// ContainerResources are the requests and limits of a container, cpu in millicores and memory in bytes, zero when unset
+type ContainerResources struct {
+	CpuRequest    int64 `json:"cpuRequest"`
+	CpuLimit      int64 `json:"cpuLimit"`
+	MemoryRequest int64 `json:"memoryRequest"`
+	MemoryLimit   int64 `json:"memoryLimit"`
+}
+
+type ObservedUsage struct {
+	Samples      int       `json:"samples"`
+	CpuP95       int64     `json:"cpuP95"`
+	CpuMax       int64     `json:"cpuMax"`
+	MemoryP95    int64     `json:"memoryP95"`
+	MemoryMax    int64     `json:"memoryMax"`
+	Replicas     int       `json:"replicas"`
+	ObservedFrom time.Time `json:"observedFrom"`
+	ObservedTo   time.Time `json:"observedTo"`
+}
+
+// PatchOperation is a json patch operation on the deployment template values
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+type PatchSuggestion struct {
+	// TemplateScope is the template the patch applies to, the environment override when the environment overrides the
+	// base deployment template
+	TemplateScope string `json:"templateScope"`
+	ChartId       int    `json:"chartId"`
+	// EnvOverrideId is set for the environment override template
+	EnvOverrideId int                    `json:"envOverrideId,omitempty"`
+	Patch         []*PatchOperation      `json:"patch"`
+	Resources     map[string]interface{} `json:"resources"`
+}
+
+type RightSizingRecommendation struct {
+	AppId         int                 `json:"appId"`
+	AppName       string              `json:"appName"`
+	EnvId         int                 `json:"envId"`
+	EnvName       string              `json:"envName"`
+	ContainerName string              `json:"containerName"`
+	Status        string              `json:"status"`
+	Message       string              `json:"message,omitempty"`
+	Current       *ContainerResources `json:"current"`
+	Recommended   *ContainerResources `json:"recommended,omitempty"`
+	Usage         *ObservedUsage      `json:"usage"`
+	// CpuSavings and MemorySavings are for all the replicas, negative when the recommendation needs more resources
+	CpuSavings    int64            `json:"cpuSavings"`
+	MemorySavings int64            `json:"memorySavings"`
+	Suggestion    *PatchSuggestion `json:"suggestion,omitempty"`
+}
@@ -0,0 +1,538 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package capacityHistory
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacityHistory/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacityHistory/repository"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	resourcehelper "k8s.io/kubectl/pkg/util/resource"
+	"sigs.k8s.io/yaml"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	mebibyte = int64(1024 * 1024)
+	// minimum recommended requests, below these the scheduler and the kubelet overheads dominate
+	minCpuRequestMillis   = int64(10)
+	minMemoryRequestBytes = 16 * mebibyte
+	cpuRoundingMillis     = int64(5)
+)
+
+type appEnvKey struct {
+	appId int
+	envId int
+}
+
+// clusterUsage is the metrics-server usage of a cluster, nil maps when metrics-server is not available
+type clusterUsage struct {
+	nodeUsage map[string]corev1.ResourceList
+	// podUsage is by namespace/name and then by container name
+	podUsage map[string]map[string]corev1.ResourceList
+}
+
+type resourceTotals struct {
+	allocatable corev1.ResourceList
+	requests    corev1.ResourceList
+	limits      corev1.ResourceList
+	usage       corev1.ResourceList
+	podCount    int
+}
+
+func newResourceTotals() *resourceTotals {
+	return &resourceTotals{
+		allocatable: corev1.ResourceList{},
+		requests:    corev1.ResourceList{},
+		limits:      corev1.ResourceList{},
+		usage:       corev1.ResourceList{},
+	}
+}
+
+func addResources(total corev1.ResourceList, resources corev1.ResourceList) {
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		quantity, ok := resources[name]
+		if !ok {
+			continue
+		}
+		sum := total[name]
+		sum.Add(quantity)
+		total[name] = sum
+	}
+}
+
+func (totals *resourceTotals) toSnapshot(clusterId int, scope, name string, metricsAvailable bool, capturedOn time.Time) *repository.CapacitySnapshot {
+	return &repository.CapacitySnapshot{
+		ClusterId:              clusterId,
+		Scope:                  scope,
+		Name:                   name,
+		CpuAllocatableMillis:   cpuMillis(totals.allocatable),
+		CpuRequestsMillis:      cpuMillis(totals.requests),
+		CpuLimitsMillis:        cpuMillis(totals.limits),
+		CpuUsageMillis:         cpuMillis(totals.usage),
+		MemoryAllocatableBytes: memoryBytes(totals.allocatable),
+		MemoryRequestsBytes:    memoryBytes(totals.requests),
+		MemoryLimitsBytes:      memoryBytes(totals.limits),
+		MemoryUsageBytes:       memoryBytes(totals.usage),
+		PodCount:               totals.podCount,
+		MetricsAvailable:       metricsAvailable,
+		CapturedOn:             capturedOn,
+	}
+}
+
+func cpuMillis(resources corev1.ResourceList) int64 {
+	quantity := resources[corev1.ResourceCPU]
+	return quantity.MilliValue()
+}
+
+func memoryBytes(resources corev1.ResourceList) int64 {
+	quantity := resources[corev1.ResourceMemory]
+	return quantity.Value()
+}
+
+func podKey(pod *corev1.Pod) string {
+	return pod.Namespace + "/" + pod.Name
+}
+
+// buildSnapshots computes the cluster, node and namespace capacity of a cluster at a point of time and the usage of
+// the main container of the devtron app pods, the app usage is skipped when pod metrics are not available
+func buildSnapshots(clusterId int, nodes []corev1.Node, pods []corev1.Pod, usage *clusterUsage,
+	capturedOn time.Time) ([]*repository.CapacitySnapshot, []*repository.AppResourceUsageSnapshot) {
+	clusterTotals := newResourceTotals()
+	nodeTotals := make(map[string]*resourceTotals, len(nodes))
+	for _, node := range nodes {
+		totals := newResourceTotals()
+		addResources(totals.allocatable, node.Status.Allocatable)
+		addResources(clusterTotals.allocatable, node.Status.Allocatable)
+		if usage.nodeUsage != nil {
+			addResources(totals.usage, usage.nodeUsage[node.Name])
+			addResources(clusterTotals.usage, usage.nodeUsage[node.Name])
+		}
+		nodeTotals[node.Name] = totals
+	}
+	namespaceTotals := make(map[string]*resourceTotals)
+	appPods := make(map[appEnvKey][]*corev1.Pod)
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		requests, limits := resourcehelper.PodRequestsAndLimits(pod)
+		totalsOfPod := []*resourceTotals{clusterTotals}
+		if totals, ok := nodeTotals[pod.Spec.NodeName]; ok {
+			totalsOfPod = append(totalsOfPod, totals)
+		}
+		totals, ok := namespaceTotals[pod.Namespace]
+		if !ok {
+			totals = newResourceTotals()
+			namespaceTotals[pod.Namespace] = totals
+		}
+		totalsOfPod = append(totalsOfPod, totals)
+		for _, podTotals := range totalsOfPod {
+			addResources(podTotals.requests, requests)
+			addResources(podTotals.limits, limits)
+			podTotals.podCount++
+		}
+		if usage.podUsage != nil {
+			for _, containerUsage := range usage.podUsage[podKey(pod)] {
+				addResources(totals.usage, containerUsage)
+			}
+		}
+		if key, ok := getAppEnvKey(pod); ok && pod.Status.Phase == corev1.PodRunning {
+			appPods[key] = append(appPods[key], pod)
+		}
+	}
+	nodeMetricsAvailable, podMetricsAvailable := usage.nodeUsage != nil, usage.podUsage != nil
+	snapshots := make([]*repository.CapacitySnapshot, 0, 1+len(nodeTotals)+len(namespaceTotals))
+	snapshots = append(snapshots, clusterTotals.toSnapshot(clusterId, bean.ScopeCluster, "", nodeMetricsAvailable, capturedOn))
+	for _, node := range nodes {
+		snapshots = append(snapshots, nodeTotals[node.Name].toSnapshot(clusterId, bean.ScopeNode, node.Name, nodeMetricsAvailable, capturedOn))
+	}
+	namespaces := make([]string, 0, len(namespaceTotals))
+	for namespace := range namespaceTotals {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	for _, namespace := range namespaces {
+		snapshots = append(snapshots, namespaceTotals[namespace].toSnapshot(clusterId, bean.ScopeNamespace, namespace, podMetricsAvailable, capturedOn))
+	}
+	appSnapshots := make([]*repository.AppResourceUsageSnapshot, 0, len(appPods))
+	if !podMetricsAvailable {
+		return snapshots, appSnapshots
+	}
+	for key, podsOfApp := range appPods {
+		if appSnapshot := buildAppSnapshot(clusterId, key, podsOfApp, usage.podUsage, capturedOn); appSnapshot != nil {
+			appSnapshots = append(appSnapshots, appSnapshot)
+		}
+	}
+	sort.Slice(appSnapshots, func(i, j int) bool {
+		if appSnapshots[i].AppId != appSnapshots[j].AppId {
+			return appSnapshots[i].AppId < appSnapshots[j].AppId
+		}
+		return appSnapshots[i].EnvId < appSnapshots[j].EnvId
+	})
+	return snapshots, appSnapshots
+}
+
+// buildAppSnapshot aggregates the usage of the main container, the first container of the pod, which is the container
+// the resources of the deployment template apply to
+func buildAppSnapshot(clusterId int, key appEnvKey, pods []*corev1.Pod, podUsage map[string]map[string]corev1.ResourceList,
+	capturedOn time.Time) *repository.AppResourceUsageSnapshot {
+	var appSnapshot *repository.AppResourceUsageSnapshot
+	var cpuTotal, memoryTotal int64
+	measured := 0
+	for _, pod := range pods {
+		if len(pod.Spec.Containers) == 0 {
+			continue
+		}
+		container := pod.Spec.Containers[0]
+		containerUsage, ok := podUsage[podKey(pod)][container.Name]
+		if !ok {
+			continue
+		}
+		if appSnapshot == nil {
+			appSnapshot = &repository.AppResourceUsageSnapshot{
+				ClusterId:           clusterId,
+				AppId:               key.appId,
+				EnvId:               key.envId,
+				ContainerName:       container.Name,
+				PodCount:            len(pods),
+				CpuRequestsMillis:   cpuMillis(container.Resources.Requests),
+				CpuLimitsMillis:     cpuMillis(container.Resources.Limits),
+				MemoryRequestsBytes: memoryBytes(container.Resources.Requests),
+				MemoryLimitsBytes:   memoryBytes(container.Resources.Limits),
+				CapturedOn:          capturedOn,
+			}
+		}
+		cpu, memory := cpuMillis(containerUsage), memoryBytes(containerUsage)
+		cpuTotal += cpu
+		memoryTotal += memory
+		if cpu > appSnapshot.CpuUsageMaxMillis {
+			appSnapshot.CpuUsageMaxMillis = cpu
+		}
+		if memory > appSnapshot.MemoryUsageMaxBytes {
+			appSnapshot.MemoryUsageMaxBytes = memory
+		}
+		measured++
+	}
+	if appSnapshot == nil {
+		return nil
+	}
+	appSnapshot.CpuUsageAvgMillis = cpuTotal / int64(measured)
+	appSnapshot.MemoryUsageAvgBytes = memoryTotal / int64(measured)
+	return appSnapshot
+}
+
+func getAppEnvKey(pod *corev1.Pod) (appEnvKey, bool) {
+	appId, err := strconv.Atoi(pod.Labels[bean.DevtronAppIdLabel])
+	if err != nil || appId <= 0 {
+		return appEnvKey{}, false
+	}
+	envId, err := strconv.Atoi(pod.Labels[bean.DevtronEnvIdLabel])
+	if err != nil || envId <= 0 {
+		return appEnvKey{}, false
+	}
+	return appEnvKey{appId: appId, envId: envId}, true
+}
+
+// toHistorySeries groups the snapshots, ordered by capture time, by name
+func toHistorySeries(snapshots []*repository.CapacitySnapshot) []*bean.CapacityHistorySeries {
+	seriesByName := make(map[string]*bean.CapacityHistorySeries)
+	result := make([]*bean.CapacityHistorySeries, 0)
+	for _, snapshot := range snapshots {
+		series, ok := seriesByName[snapshot.Name]
+		if !ok {
+			series = &bean.CapacityHistorySeries{Name: snapshot.Name, Points: make([]*bean.CapacityHistoryPoint, 0)}
+			seriesByName[snapshot.Name] = series
+			result = append(result, series)
+		}
+		series.Points = append(series.Points, &bean.CapacityHistoryPoint{
+			CapturedOn: snapshot.CapturedOn,
+			Cpu: bean.ResourceHistoryPoint{
+				Allocatable: snapshot.CpuAllocatableMillis,
+				Requests:    snapshot.CpuRequestsMillis,
+				Limits:      snapshot.CpuLimitsMillis,
+				Usage:       snapshot.CpuUsageMillis,
+			},
+			Memory: bean.ResourceHistoryPoint{
+				Allocatable: snapshot.MemoryAllocatableBytes,
+				Requests:    snapshot.MemoryRequestsBytes,
+				Limits:      snapshot.MemoryLimitsBytes,
+				Usage:       snapshot.MemoryUsageBytes,
+			},
+			PodCount:         snapshot.PodCount,
+			MetricsAvailable: snapshot.MetricsAvailable,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// percentile returns the nearest rank percentile of the values
+func percentile(values []int64, p int) int64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := make([]int64, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func roundUp(value, step int64) int64 {
+	if value%step == 0 {
+		return value
+	}
+	return (value/step + 1) * step
+}
+
+func withHeadroom(value int64, percent int) int64 {
+	return value * int64(100+percent) / 100
+}
+
+// observeUsage summarises the snapshots of an app in an environment, only the snapshots of the latest main container
+// are considered so that a renamed container does not mix with the old one
+func observeUsage(snapshots []*repository.AppResourceUsageSnapshot) *bean.ObservedUsage {
+	observed := &bean.ObservedUsage{}
+	if len(snapshots) == 0 {
+		return observed
+	}
+	latest := snapshots[len(snapshots)-1]
+	cpuValues, memoryValues := make([]int64, 0, len(snapshots)), make([]int64, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		if snapshot.ContainerName != latest.ContainerName {
+			continue
+		}
+		if observed.Samples == 0 {
+			observed.ObservedFrom = snapshot.CapturedOn
+		}
+		observed.Samples++
+		cpuValues = append(cpuValues, snapshot.CpuUsageMaxMillis)
+		memoryValues = append(memoryValues, snapshot.MemoryUsageMaxBytes)
+		if snapshot.CpuUsageMaxMillis > observed.CpuMax {
+			observed.CpuMax = snapshot.CpuUsageMaxMillis
+		}
+		if snapshot.MemoryUsageMaxBytes > observed.MemoryMax {
+			observed.MemoryMax = snapshot.MemoryUsageMaxBytes
+		}
+	}
+	observed.CpuP95 = percentile(cpuValues, 95)
+	observed.MemoryP95 = percentile(memoryValues, 95)
+	observed.Replicas = latest.PodCount
+	observed.ObservedTo = latest.CapturedOn
+	return observed
+}
+
+// recommendResources sizes the cpu requests on the 95th percentile of the usage and the memory requests on the peak
+// usage, as running short of memory gets the container killed. The limit to request ratio of the current resources
+// is kept, a limit is recommended only when one is set
+func recommendResources(current *bean.ContainerResources, usage *bean.ObservedUsage, headroomPercent int) *bean.ContainerResources {
+	recommended := &bean.ContainerResources{
+		CpuRequest:    roundUp(withHeadroom(usage.CpuP95, headroomPercent), cpuRoundingMillis),
+		MemoryRequest: roundUp(withHeadroom(usage.MemoryMax, headroomPercent), mebibyte),
+	}
+	if recommended.CpuRequest < minCpuRequestMillis {
+		recommended.CpuRequest = minCpuRequestMillis
+	}
+	if recommended.MemoryRequest < minMemoryRequestBytes {
+		recommended.MemoryRequest = minMemoryRequestBytes
+	}
+	recommended.CpuLimit = scaleLimit(current.CpuLimit, current.CpuRequest, recommended.CpuRequest, cpuRoundingMillis)
+	recommended.MemoryLimit = scaleLimit(current.MemoryLimit, current.MemoryRequest, recommended.MemoryRequest, mebibyte)
+	return recommended
+}
+
+func scaleLimit(currentLimit, currentRequest, recommendedRequest, step int64) int64 {
+	if currentLimit <= 0 {
+		return 0
+	}
+	if currentRequest <= 0 {
+		if currentLimit < recommendedRequest {
+			return recommendedRequest
+		}
+		return currentLimit
+	}
+	limit := roundUp(recommendedRequest*currentLimit/currentRequest, step)
+	if limit < recommendedRequest {
+		return recommendedRequest
+	}
+	return limit
+}
+
+// recommend compares the current resources with the observed usage of an app in an environment
+func recommend(current *bean.ContainerResources, snapshots []*repository.AppResourceUsageSnapshot,
+	config *bean.CapacityHistoryConfig) *bean.RightSizingRecommendation {
+	usage := observeUsage(snapshots)
+	recommendation := &bean.RightSizingRecommendation{
+		Current: current,
+		Usage:   usage,
+	}
+	if len(snapshots) > 0 {
+		recommendation.ContainerName = snapshots[len(snapshots)-1].ContainerName
+	}
+	if usage.Samples < config.RecommendationMinSample {
+		recommendation.Status = bean.RecommendationInsufficientData
+		recommendation.Message = fmt.Sprintf("%d usage samples found, at least %d are needed for a recommendation", usage.Samples, config.RecommendationMinSample)
+		return recommendation
+	}
+	recommended := recommendResources(current, usage, config.HeadroomPercent)
+	recommendation.Recommended = recommended
+	switch {
+	case current.CpuRequest < recommended.CpuRequest || current.MemoryRequest < recommended.MemoryRequest:
+		recommendation.Status = bean.RecommendationUnderProvisioned
+		if current.CpuRequest == 0 || current.MemoryRequest == 0 {
+			recommendation.Message = "requests are not set, the pods can be scheduled on nodes without capacity for them"
+		}
+	case current.CpuRequest > withHeadroom(recommended.CpuRequest, config.ThresholdPercent) ||
+		current.MemoryRequest > withHeadroom(recommended.MemoryRequest, config.ThresholdPercent):
+		recommendation.Status = bean.RecommendationOverProvisioned
+	default:
+		recommendation.Status = bean.RecommendationRightSized
+	}
+	if current.CpuRequest > 0 {
+		recommendation.CpuSavings = (current.CpuRequest - recommended.CpuRequest) * int64(usage.Replicas)
+	}
+	if current.MemoryRequest > 0 {
+		recommendation.MemorySavings = (current.MemoryRequest - recommended.MemoryRequest) * int64(usage.Replicas)
+	}
+	return recommendation
+}
+
+// parseTemplateValues parses the values of a deployment template, stored as json but accepted as yaml as well
+func parseTemplateValues(values string) (map[string]interface{}, error) {
+	templateValues := make(map[string]interface{})
+	if len(values) == 0 {
+		return templateValues, nil
+	}
+	err := yaml.Unmarshal([]byte(values), &templateValues)
+	return templateValues, err
+}
+
+// templateResources reads the resources of the main container from the deployment template values, false when the
+// template does not set them in a parsable form, like when they are scoped variables
+func templateResources(templateValues map[string]interface{}) (*bean.ContainerResources, bool) {
+	resources, ok := templateValues["resources"].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	current := &bean.ContainerResources{}
+	for _, field := range []struct {
+		section string
+		name    corev1.ResourceName
+		target  *int64
+	}{
+		{"requests", corev1.ResourceCPU, &current.CpuRequest},
+		{"limits", corev1.ResourceCPU, &current.CpuLimit},
+		{"requests", corev1.ResourceMemory, &current.MemoryRequest},
+		{"limits", corev1.ResourceMemory, &current.MemoryLimit},
+	} {
+		section, _ := resources[field.section].(map[string]interface{})
+		value, ok := section[string(field.name)]
+		if !ok || value == nil {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(fmt.Sprint(value))
+		if err != nil {
+			return nil, false
+		}
+		if field.name == corev1.ResourceCPU {
+			*field.target = quantity.MilliValue()
+		} else {
+			*field.target = quantity.Value()
+		}
+	}
+	return current, true
+}
+
+func cpuString(millis int64) string {
+	return resource.NewMilliQuantity(millis, resource.DecimalSI).String()
+}
+
+func memoryString(bytes int64) string {
+	if bytes%mebibyte == 0 {
+		return fmt.Sprintf("%dMi", bytes/mebibyte)
+	}
+	return resource.NewQuantity(bytes, resource.BinarySI).String()
+}
+
+// buildPatch returns the json patch setting the recommended resources on the deployment template values along with
+// the resulting resources
+func buildPatch(templateValues map[string]interface{}, recommended *bean.ContainerResources) ([]*bean.PatchOperation, map[string]interface{}) {
+	sections := map[string]map[string]interface{}{
+		"requests": {
+			string(corev1.ResourceCPU):    cpuString(recommended.CpuRequest),
+			string(corev1.ResourceMemory): memoryString(recommended.MemoryRequest),
+		},
+		"limits": {},
+	}
+	if recommended.CpuLimit > 0 {
+		sections["limits"][string(corev1.ResourceCPU)] = cpuString(recommended.CpuLimit)
+	}
+	if recommended.MemoryLimit > 0 {
+		sections["limits"][string(corev1.ResourceMemory)] = memoryString(recommended.MemoryLimit)
+	}
+	existing, hasResources := templateValues["resources"].(map[string]interface{})
+	resources := make(map[string]interface{})
+	for key, value := range existing {
+		resources[key] = value
+	}
+	patch := make([]*bean.PatchOperation, 0)
+	for _, sectionName := range []string{"requests", "limits"} {
+		values := sections[sectionName]
+		if len(values) == 0 {
+			continue
+		}
+		existingSection, hasSection := resources[sectionName].(map[string]interface{})
+		section := make(map[string]interface{})
+		for key, value := range existingSection {
+			section[key] = value
+		}
+		for _, name := range []string{string(corev1.ResourceCPU), string(corev1.ResourceMemory)} {
+			value, ok := values[name]
+			if !ok {
+				continue
+			}
+			if hasResources && hasSection {
+				op := "add"
+				if _, exists := existingSection[name]; exists {
+					op = "replace"
+				}
+				patch = append(patch, &bean.PatchOperation{Op: op, Path: fmt.Sprintf("/resources/%s/%s", sectionName, name), Value: value})
+			}
+			section[name] = value
+		}
+		if hasResources && !hasSection {
+			patch = append(patch, &bean.PatchOperation{Op: "add", Path: "/resources/" + sectionName, Value: section})
+		}
+		resources[sectionName] = section
+	}
+	if !hasResources {
+		patch = append(patch, &bean.PatchOperation{Op: "add", Path: "/resources", Value: resources})
+	}
+	return patch, resources
+}
@@ -0,0 +1,192 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package capacityHistory
+
+import (
+	"github.com/devtron-labs/devtron/pkg/k8s/capacityHistory/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacityHistory/repository"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func resourceList(cpu, memory string) corev1.ResourceList {
+	return corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(cpu),
+		corev1.ResourceMemory: resource.MustParse(memory),
+	}
+}
+
+func newTestPod(name, namespace, nodeName string, podLabels map[string]string, requests corev1.ResourceList) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: podLabels},
+		Spec: corev1.PodSpec{
+			NodeName:   nodeName,
+			Containers: []corev1.Container{{Name: "main", Resources: corev1.ResourceRequirements{Requests: requests}}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestBuildSnapshots(t *testing.T) {
+	capturedOn := time.Now()
+	nodes := []corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}, Status: corev1.NodeStatus{Allocatable: resourceList("2", "4Gi")}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}, Status: corev1.NodeStatus{Allocatable: resourceList("2", "4Gi")}},
+	}
+	appLabels := map[string]string{"appId": "3", "envId": "2"}
+	pods := []corev1.Pod{
+		newTestPod("web-1", "web", "node-1", appLabels, resourceList("500m", "512Mi")),
+		newTestPod("web-2", "web", "node-2", appLabels, resourceList("500m", "512Mi")),
+		newTestPod("agent", "kube-system", "node-1", nil, resourceList("100m", "128Mi")),
+	}
+	completed := newTestPod("job", "web", "node-2", nil, resourceList("1", "1Gi"))
+	completed.Status.Phase = corev1.PodSucceeded
+	pods = append(pods, completed)
+
+	t.Run("capacity is captured by cluster, node and namespace along with app usage", func(t *testing.T) {
+		usage := &clusterUsage{
+			nodeUsage: map[string]corev1.ResourceList{"node-1": resourceList("800m", "2Gi"), "node-2": resourceList("400m", "1Gi")},
+			podUsage: map[string]map[string]corev1.ResourceList{
+				"web/web-1": {"main": resourceList("300m", "256Mi")},
+				"web/web-2": {"main": resourceList("100m", "128Mi")},
+			},
+		}
+		snapshots, appSnapshots := buildSnapshots(1, nodes, pods, usage, capturedOn)
+		assert.Len(t, snapshots, 5)
+		clusterSnapshot := snapshots[0]
+		assert.Equal(t, bean.ScopeCluster, clusterSnapshot.Scope)
+		assert.Equal(t, int64(4000), clusterSnapshot.CpuAllocatableMillis)
+		assert.Equal(t, int64(1100), clusterSnapshot.CpuRequestsMillis)
+		assert.Equal(t, int64(1200), clusterSnapshot.CpuUsageMillis)
+		assert.Equal(t, 3, clusterSnapshot.PodCount)
+		assert.True(t, clusterSnapshot.MetricsAvailable)
+		assert.Equal(t, "node-1", snapshots[1].Name)
+		assert.Equal(t, int64(600), snapshots[1].CpuRequestsMillis)
+		assert.Equal(t, "kube-system", snapshots[3].Name)
+		assert.Equal(t, "web", snapshots[4].Name)
+		assert.Equal(t, int64(400), snapshots[4].CpuUsageMillis)
+		assert.Len(t, appSnapshots, 1)
+		appSnapshot := appSnapshots[0]
+		assert.Equal(t, 3, appSnapshot.AppId)
+		assert.Equal(t, 2, appSnapshot.EnvId)
+		assert.Equal(t, 2, appSnapshot.PodCount)
+		assert.Equal(t, int64(500), appSnapshot.CpuRequestsMillis)
+		assert.Equal(t, int64(200), appSnapshot.CpuUsageAvgMillis)
+		assert.Equal(t, int64(300), appSnapshot.CpuUsageMaxMillis)
+		assert.Equal(t, 256*mebibyte, appSnapshot.MemoryUsageMaxBytes)
+	})
+
+	t.Run("app usage is skipped without pod metrics", func(t *testing.T) {
+		snapshots, appSnapshots := buildSnapshots(1, nodes, pods, &clusterUsage{}, capturedOn)
+		assert.Len(t, snapshots, 5)
+		assert.False(t, snapshots[0].MetricsAvailable)
+		assert.Zero(t, snapshots[0].CpuUsageMillis)
+		assert.Empty(t, appSnapshots)
+	})
+}
+
+func newUsageSnapshots(count int, cpu, memory int64) []*repository.AppResourceUsageSnapshot {
+	snapshots := make([]*repository.AppResourceUsageSnapshot, 0, count)
+	start := time.Now().Add(-time.Duration(count) * time.Hour)
+	for i := 0; i < count; i++ {
+		snapshots = append(snapshots, &repository.AppResourceUsageSnapshot{
+			ContainerName:       "main",
+			PodCount:            2,
+			CpuUsageMaxMillis:   cpu,
+			MemoryUsageMaxBytes: memory,
+			CapturedOn:          start.Add(time.Duration(i) * time.Hour),
+		})
+	}
+	return snapshots
+}
+
+func TestRecommend(t *testing.T) {
+	config := &bean.CapacityHistoryConfig{RecommendationMinSample: 10, HeadroomPercent: 20, ThresholdPercent: 30}
+
+	t.Run("over provisioned requests are reduced keeping the limit ratio", func(t *testing.T) {
+		current := &bean.ContainerResources{CpuRequest: 1000, CpuLimit: 2000, MemoryRequest: 1024 * mebibyte, MemoryLimit: 1024 * mebibyte}
+		recommendation := recommend(current, newUsageSnapshots(20, 100, 200*mebibyte), config)
+		assert.Equal(t, bean.RecommendationOverProvisioned, recommendation.Status)
+		assert.Equal(t, &bean.ContainerResources{CpuRequest: 120, CpuLimit: 240, MemoryRequest: 240 * mebibyte, MemoryLimit: 240 * mebibyte}, recommendation.Recommended)
+		assert.Equal(t, int64(2*(1000-120)), recommendation.CpuSavings)
+		assert.Equal(t, 2*(1024-240)*mebibyte, recommendation.MemorySavings)
+	})
+
+	t.Run("requests below the usage are under provisioned", func(t *testing.T) {
+		current := &bean.ContainerResources{CpuRequest: 100, MemoryRequest: 128 * mebibyte}
+		recommendation := recommend(current, newUsageSnapshots(20, 300, 200*mebibyte), config)
+		assert.Equal(t, bean.RecommendationUnderProvisioned, recommendation.Status)
+		assert.Zero(t, recommendation.Recommended.CpuLimit)
+		assert.Negative(t, recommendation.CpuSavings)
+	})
+
+	t.Run("requests close to the recommendation are right sized", func(t *testing.T) {
+		current := &bean.ContainerResources{CpuRequest: 130, MemoryRequest: 256 * mebibyte}
+		recommendation := recommend(current, newUsageSnapshots(20, 100, 200*mebibyte), config)
+		assert.Equal(t, bean.RecommendationRightSized, recommendation.Status)
+	})
+
+	t.Run("too few samples give no recommendation", func(t *testing.T) {
+		recommendation := recommend(&bean.ContainerResources{}, newUsageSnapshots(5, 100, 200*mebibyte), config)
+		assert.Equal(t, bean.RecommendationInsufficientData, recommendation.Status)
+		assert.Nil(t, recommendation.Recommended)
+	})
+}
+
+func TestTemplateResourcesAndPatch(t *testing.T) {
+	recommended := &bean.ContainerResources{CpuRequest: 120, CpuLimit: 240, MemoryRequest: 240 * mebibyte}
+
+	t.Run("existing resources are replaced", func(t *testing.T) {
+		templateValues, err := parseTemplateValues(`{"replicaCount":2,"resources":{"limits":{"cpu":"2","memory":"1Gi"},"requests":{"cpu":1,"memory":"1Gi"}}}`)
+		assert.NoError(t, err)
+		current, ok := templateResources(templateValues)
+		assert.True(t, ok)
+		assert.Equal(t, &bean.ContainerResources{CpuRequest: 1000, CpuLimit: 2000, MemoryRequest: 1024 * mebibyte, MemoryLimit: 1024 * mebibyte}, current)
+		patch, resources := buildPatch(templateValues, recommended)
+		assert.Equal(t, []*bean.PatchOperation{
+			{Op: "replace", Path: "/resources/requests/cpu", Value: "120m"},
+			{Op: "replace", Path: "/resources/requests/memory", Value: "240Mi"},
+			{Op: "replace", Path: "/resources/limits/cpu", Value: "240m"},
+		}, patch)
+		assert.Equal(t, map[string]interface{}{
+			"requests": map[string]interface{}{"cpu": "120m", "memory": "240Mi"},
+			"limits":   map[string]interface{}{"cpu": "240m", "memory": "1Gi"},
+		}, resources)
+	})
+
+	t.Run("missing resources are added", func(t *testing.T) {
+		templateValues, err := parseTemplateValues(`{"replicaCount":2}`)
+		assert.NoError(t, err)
+		_, ok := templateResources(templateValues)
+		assert.False(t, ok)
+		patch, _ := buildPatch(templateValues, recommended)
+		assert.Len(t, patch, 1)
+		assert.Equal(t, "add", patch[0].Op)
+		assert.Equal(t, "/resources", patch[0].Path)
+	})
+
+	t.Run("scoped variables are not parsable", func(t *testing.T) {
+		templateValues, err := parseTemplateValues(`{"resources":{"requests":{"cpu":"@{{cpu}}"}}}`)
+		assert.NoError(t, err)
+		_, ok := templateResources(templateValues)
+		assert.False(t, ok)
+	})
+}
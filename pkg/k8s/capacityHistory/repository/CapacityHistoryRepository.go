@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type CapacitySnapshot struct {
+	tableName              struct{}  `sql:"capacity_snapshot" pg:",discard_unknown_columns"`
+	Id                     int       `sql:"id,pk"`
+	ClusterId              int       `sql:"cluster_id,notnull"`
+	Scope                  string    `sql:"scope,notnull"`
+	Name                   string    `sql:"name,notnull"`
+	CpuAllocatableMillis   int64     `sql:"cpu_allocatable_millis,notnull"`
+	CpuRequestsMillis      int64     `sql:"cpu_requests_millis,notnull"`
+	CpuLimitsMillis        int64     `sql:"cpu_limits_millis,notnull"`
+	CpuUsageMillis         int64     `sql:"cpu_usage_millis,notnull"`
+	MemoryAllocatableBytes int64     `sql:"memory_allocatable_bytes,notnull"`
+	MemoryRequestsBytes    int64     `sql:"memory_requests_bytes,notnull"`
+	MemoryLimitsBytes      int64     `sql:"memory_limits_bytes,notnull"`
+	MemoryUsageBytes       int64     `sql:"memory_usage_bytes,notnull"`
+	PodCount               int       `sql:"pod_count,notnull"`
+	MetricsAvailable       bool      `sql:"metrics_available,notnull"`
+	CapturedOn             time.Time `sql:"captured_on,notnull"`
+}
+
+type AppResourceUsageSnapshot struct {
+	tableName           struct{}  `sql:"app_resource_usage_snapshot" pg:",discard_unknown_columns"`
+	Id                  int       `sql:"id,pk"`
+	ClusterId           int       `sql:"cluster_id,notnull"`
+	AppId               int       `sql:"app_id,notnull"`
+	EnvId               int       `sql:"env_id,notnull"`
+	ContainerName       string    `sql:"container_name,notnull"`
+	PodCount            int       `sql:"pod_count,notnull"`
+	CpuRequestsMillis   int64     `sql:"cpu_requests_millis,notnull"`
+	CpuLimitsMillis     int64     `sql:"cpu_limits_millis,notnull"`
+	MemoryRequestsBytes int64     `sql:"memory_requests_bytes,notnull"`
+	MemoryLimitsBytes   int64     `sql:"memory_limits_bytes,notnull"`
+	CpuUsageAvgMillis   int64     `sql:"cpu_usage_avg_millis,notnull"`
+	CpuUsageMaxMillis   int64     `sql:"cpu_usage_max_millis,notnull"`
+	MemoryUsageAvgBytes int64     `sql:"memory_usage_avg_bytes,notnull"`
+	MemoryUsageMaxBytes int64     `sql:"memory_usage_max_bytes,notnull"`
+	CapturedOn          time.Time `sql:"captured_on,notnull"`
+}
+
+type CapacityHistoryRepository interface {
+	SaveSnapshots(snapshots []*CapacitySnapshot, appSnapshots []*AppResourceUsageSnapshot) error
+	FindSnapshots(clusterId int, scope string, names []string, from, to time.Time) ([]*CapacitySnapshot, error)
+	FindAppSnapshotsByClusterId(clusterId int, from time.Time) ([]*AppResourceUsageSnapshot, error)
+	FindAppSnapshots(appId, envId int, from time.Time) ([]*AppResourceUsageSnapshot, error)
+	DeleteSnapshotsBefore(before time.Time) error
+}
+
+type CapacityHistoryRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewCapacityHistoryRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *CapacityHistoryRepositoryImpl {
+	return &CapacityHistoryRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+// SaveSnapshots saves the snapshots of one capture of a cluster together
+func (repo *CapacityHistoryRepositoryImpl) SaveSnapshots(snapshots []*CapacitySnapshot, appSnapshots []*AppResourceUsageSnapshot) error {
+	return repo.dbConnection.RunInTransaction(func(tx *pg.Tx) error {
+		if len(snapshots) > 0 {
+			if err := tx.Insert(&snapshots); err != nil {
+				return err
+			}
+		}
+		if len(appSnapshots) > 0 {
+			if err := tx.Insert(&appSnapshots); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (repo *CapacityHistoryRepositoryImpl) FindSnapshots(clusterId int, scope string, names []string, from, to time.Time) ([]*CapacitySnapshot, error) {
+	var snapshots []*CapacitySnapshot
+	query := repo.dbConnection.Model(&snapshots).
+		Where("cluster_id = ?", clusterId).
+		Where("scope = ?", scope).
+		Where("captured_on >= ?", from).
+		Where("captured_on <= ?", to)
+	if len(names) > 0 {
+		query = query.Where("name in (?)", pg.In(names))
+	}
+	err := query.Order("captured_on ASC").Select()
+	return snapshots, err
+}
+
+func (repo *CapacityHistoryRepositoryImpl) FindAppSnapshotsByClusterId(clusterId int, from time.Time) ([]*AppResourceUsageSnapshot, error) {
+	var snapshots []*AppResourceUsageSnapshot
+	err := repo.dbConnection.Model(&snapshots).
+		Where("cluster_id = ?", clusterId).
+		Where("captured_on >= ?", from).
+		Order("captured_on ASC").
+		Select()
+	return snapshots, err
+}
+
+func (repo *CapacityHistoryRepositoryImpl) FindAppSnapshots(appId, envId int, from time.Time) ([]*AppResourceUsageSnapshot, error) {
+	var snapshots []*AppResourceUsageSnapshot
+	err := repo.dbConnection.Model(&snapshots).
+		Where("app_id = ?", appId).
+		Where("env_id = ?", envId).
+		Where("captured_on >= ?", from).
+		Order("captured_on ASC").
+		Select()
+	return snapshots, err
+}
+
+func (repo *CapacityHistoryRepositoryImpl) DeleteSnapshotsBefore(before time.Time) error {
+	_, err := repo.dbConnection.Model((*CapacitySnapshot)(nil)).Where("captured_on < ?", before).Delete()
+	if err != nil {
+		return err
+	}
+	_, err = repo.dbConnection.Model((*AppResourceUsageSnapshot)(nil)).Where("captured_on < ?", before).Delete()
+	return err
+}
@@ -22,7 +22,9 @@ import (
 	"go.uber.org/zap"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
+	"net/http"
 	"sync"
 	"time"
 )
@@ -83,15 +85,15 @@ func (impl *K8sInformerFactoryImpl) BuildInformer(clusterInfo []*bean.ClusterInf
 			CertData:              info.CertData,
 			CAData:                info.CAData,
 		}
-		impl.buildInformerAndNamespaceList(info.ClusterName, clusterConfig)
+		impl.buildInformerAndNamespaceList(info.ClusterName, clusterConfig, info.TokenProvider)
 	}
 	return
 }
 
-func (impl *K8sInformerFactoryImpl) buildInformerAndNamespaceList(clusterName string, clusterConfig *k8s.ClusterConfig) sync.Map {
+func (impl *K8sInformerFactoryImpl) buildInformerAndNamespaceList(clusterName string, clusterConfig *k8s.ClusterConfig, tokenProvider func() (string, error)) sync.Map {
 	allNamespaces := sync.Map{}
 	impl.globalMapClusterNamespace.Store(clusterName, &allNamespaces)
-	_, _, clusterClient, err := impl.k8sUtil.GetK8sConfigAndClients(clusterConfig)
+	clusterClient, err := impl.getClientSet(clusterConfig, tokenProvider)
 	if err != nil {
 		impl.logger.Errorw("error in getting k8s clientset", "err", err, "clusterName", clusterConfig.ClusterName)
 		return impl.globalMapClusterNamespace
@@ -120,6 +122,39 @@ func (impl *K8sInformerFactoryImpl) buildInformerAndNamespaceList(clusterName st
 	return impl.globalMapClusterNamespace
 }
 
+// getClientSet returns the client set for the informer, with a token provider the token is fetched per request
+// as the informer outlives short-lived credentials
+func (impl *K8sInformerFactoryImpl) getClientSet(clusterConfig *k8s.ClusterConfig, tokenProvider func() (string, error)) (*kubernetes.Clientset, error) {
+	if tokenProvider == nil {
+		_, _, clusterClient, err := impl.k8sUtil.GetK8sConfigAndClients(clusterConfig)
+		return clusterClient, err
+	}
+	restConfig, err := impl.k8sUtil.GetRestConfigByCluster(clusterConfig)
+	if err != nil {
+		return nil, err
+	}
+	restConfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &tokenProviderRoundTripper{tokenProvider: tokenProvider, rt: rt}
+	})
+	_, clusterClient, err := impl.k8sUtil.GetK8sConfigAndClientsByRestConfig(restConfig)
+	return clusterClient, err
+}
+
+type tokenProviderRoundTripper struct {
+	tokenProvider func() (string, error)
+	rt            http.RoundTripper
+}
+
+func (rt *tokenProviderRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := rt.tokenProvider()
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return rt.rt.RoundTrip(req)
+}
+
 func (impl *K8sInformerFactoryImpl) CleanNamespaceInformer(clusterName string) {
 	stopper := impl.informerStopper[clusterName]
 	if stopper != nil {
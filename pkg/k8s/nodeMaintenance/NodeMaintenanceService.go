@@ -0,0 +1,940 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package nodeMaintenance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/caarlos0/env"
+	k8s2 "github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	"github.com/devtron-labs/devtron/internal/util"
+	userBean "github.com/devtron-labs/devtron/pkg/auth/user/bean"
+	repository2 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/k8s"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity"
+	capacityBean "github.com/devtron-labs/devtron/pkg/k8s/capacity/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/nodeMaintenance/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/nodeMaintenance/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	cron2 "github.com/devtron-labs/devtron/util/cron"
+	"github.com/go-pg/pg"
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type NodeMaintenanceService interface {
+	// ResolveNodeNames returns the requested nodes, or all the nodes of the requested node group
+	ResolveNodeNames(ctx context.Context, request *bean.NodeMaintenanceRequest) ([]string, error)
+	// Analyse reports the pods and pod disruption budgets which would block draining the nodes and the devtron apps
+	// whose pods would be evicted
+	Analyse(ctx context.Context, request *bean.NodeMaintenanceRequest) (*bean.PreflightAnalysis, error)
+	// StartMaintenance persists the maintenance and drains its nodes in the background, Concurrency nodes at a time
+	StartMaintenance(ctx context.Context, request *bean.NodeMaintenanceRequest) (*bean.NodeMaintenanceDto, error)
+	GetMaintenance(id int) (*bean.NodeMaintenanceDto, error)
+	GetMaintenances(clusterId int) ([]*bean.NodeMaintenanceDto, error)
+	// CompleteMaintenance uncordons the nodes of a maintenance whose nodes are all drained
+	CompleteMaintenance(id int, userId int32) (*bean.NodeMaintenanceDto, error)
+	// CancelMaintenance stops draining and uncordons the nodes cordoned by the maintenance
+	CancelMaintenance(id int, userId int32) (*bean.NodeMaintenanceDto, error)
+	// ResumeMaintenance retries the failed nodes of a failed maintenance and drains the remaining ones
+	ResumeMaintenance(id int, userId int32) (*bean.NodeMaintenanceDto, error)
+}
+
+type maintenanceRun struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+type NodeMaintenanceServiceImpl struct {
+	logger                    *zap.SugaredLogger
+	k8sUtil                   *k8s2.K8sServiceImpl
+	k8sCommonService          k8s.K8sCommonService
+	k8sCapacityService        capacity.K8sCapacityService
+	nodeMaintenanceRepository repository.NodeMaintenanceRepository
+	appRepository             app.AppRepository
+	environmentRepository     repository2.EnvironmentRepository
+	transactionWrapper        sql.TransactionWrapper
+	config                    *bean.NodeMaintenanceConfig
+	maintenanceCron           *cron.Cron
+	// leaseOwner identifies this replica on the leases of the maintenances it runs
+	leaseOwner string
+	runs       map[int]*maintenanceRun
+	runsLock   sync.Mutex
+	// statusLock serialises the status transitions of the maintenances
+	statusLock sync.Mutex
+}
+
+func GetNodeMaintenanceConfig() (*bean.NodeMaintenanceConfig, error) {
+	config := &bean.NodeMaintenanceConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		return nil, err
+	}
+	return config, err
+}
+
+func NewNodeMaintenanceServiceImpl(logger *zap.SugaredLogger, k8sUtil *k8s2.K8sServiceImpl,
+	k8sCommonService k8s.K8sCommonService,
+	k8sCapacityService capacity.K8sCapacityService,
+	nodeMaintenanceRepository repository.NodeMaintenanceRepository,
+	appRepository app.AppRepository,
+	environmentRepository repository2.EnvironmentRepository,
+	transactionWrapper sql.TransactionWrapper,
+	config *bean.NodeMaintenanceConfig,
+	cronLogger *cron2.CronLoggerImpl) (*NodeMaintenanceServiceImpl, error) {
+	maintenanceCron := cron.New(cron.WithChain(cron.SkipIfStillRunning(cronLogger), cron.Recover(cronLogger)))
+	impl := &NodeMaintenanceServiceImpl{
+		logger:                    logger,
+		k8sUtil:                   k8sUtil,
+		k8sCommonService:          k8sCommonService,
+		k8sCapacityService:        k8sCapacityService,
+		nodeMaintenanceRepository: nodeMaintenanceRepository,
+		appRepository:             appRepository,
+		environmentRepository:     environmentRepository,
+		transactionWrapper:        transactionWrapper,
+		config:                    config,
+		maintenanceCron:           maintenanceCron,
+		leaseOwner:                getLeaseOwner(),
+		runs:                      make(map[int]*maintenanceRun),
+	}
+	maintenanceCron.Start()
+	_, err := maintenanceCron.AddFunc(watchMaintenancesSchedule, impl.watchMaintenances)
+	if err != nil {
+		logger.Errorw("error in starting node maintenance cron", "err", err)
+		return nil, err
+	}
+	return impl, nil
+}
+
+const (
+	watchMaintenancesSchedule = "@every 1m"
+	leasePollInterval         = 2 * time.Second
+)
+
+// getLeaseOwner is the pod name, with a random suffix as a restarted pod must not take over the leases of its
+// previous run before they expire
+func getLeaseOwner() string {
+	hostname, err := os.Hostname()
+	if err != nil || len(hostname) == 0 {
+		hostname = "orchestrator"
+	}
+	return hostname + "-" + uuid.NewString()[:8]
+}
+
+func (impl *NodeMaintenanceServiceImpl) ResolveNodeNames(ctx context.Context, request *bean.NodeMaintenanceRequest) ([]string, error) {
+	_, _, clientSet, err := impl.k8sCommonService.GetK8sConfigAndClientsByClusterId(ctx, request.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := impl.getNodes(ctx, clientSet, request)
+	if err != nil {
+		return nil, err
+	}
+	nodeNames := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		nodeNames = append(nodeNames, node.Name)
+	}
+	return nodeNames, nil
+}
+
+func (impl *NodeMaintenanceServiceImpl) getNodes(ctx context.Context, clientSet *kubernetes.Clientset, request *bean.NodeMaintenanceRequest) ([]corev1.Node, error) {
+	if len(request.NodeNames) == 0 && len(request.NodeGroup) == 0 {
+		return nil, util.NewApiError(http.StatusBadRequest, "either nodeNames or nodeGroup is required", "either nodeNames or nodeGroup is required")
+	}
+	nodeList, err := clientSet.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		impl.logger.Errorw("error in listing nodes", "clusterId", request.ClusterId, "err", err)
+		return nil, err
+	}
+	nodesByName := make(map[string]corev1.Node, len(nodeList.Items))
+	for _, node := range nodeList.Items {
+		nodesByName[node.Name] = node
+	}
+	nodes := make([]corev1.Node, 0)
+	if len(request.NodeNames) > 0 {
+		seen := make(map[string]bool)
+		for _, nodeName := range request.NodeNames {
+			node, ok := nodesByName[nodeName]
+			if !ok {
+				msg := fmt.Sprintf("node %s not found", nodeName)
+				return nil, util.NewApiError(http.StatusNotFound, msg, msg)
+			}
+			if !seen[nodeName] {
+				seen[nodeName] = true
+				nodes = append(nodes, node)
+			}
+		}
+	} else {
+		for _, node := range nodeList.Items {
+			if capacity.GetNodeGroup(&node) == request.NodeGroup {
+				nodes = append(nodes, node)
+			}
+		}
+		if len(nodes) == 0 {
+			msg := fmt.Sprintf("no nodes found in node group %s", request.NodeGroup)
+			return nil, util.NewApiError(http.StatusNotFound, msg, msg)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+	return nodes, nil
+}
+
+// getDrainHelper returns the drain options of the request, a nil value drains like the drain api defaults to, with
+// the grace period of the pods
+func getDrainHelper(request *bean.NodeMaintenanceRequest) *capacityBean.NodeDrainHelper {
+	if request.NodeDrainHelper == nil {
+		return &capacityBean.NodeDrainHelper{GracePeriodSeconds: -1}
+	}
+	drainHelper := *request.NodeDrainHelper
+	return &drainHelper
+}
+
+func (impl *NodeMaintenanceServiceImpl) Analyse(ctx context.Context, request *bean.NodeMaintenanceRequest) (*bean.PreflightAnalysis, error) {
+	_, _, clientSet, err := impl.k8sCommonService.GetK8sConfigAndClientsByClusterId(ctx, request.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := impl.getNodes(ctx, clientSet, request)
+	if err != nil {
+		return nil, err
+	}
+	pods := make([]corev1.Pod, 0)
+	for _, node := range nodes {
+		podList, err := clientSet.CoreV1().Pods(corev1.NamespaceAll).List(ctx, metav1.ListOptions{
+			FieldSelector: fields.SelectorFromSet(fields.Set{"spec.nodeName": node.Name}).String(),
+		})
+		if err != nil {
+			impl.logger.Errorw("error in listing pods of node", "clusterId", request.ClusterId, "nodeName", node.Name, "err", err)
+			return nil, err
+		}
+		pods = append(pods, podList.Items...)
+	}
+	var budgets []policyv1.PodDisruptionBudget
+	budgetList, err := clientSet.PolicyV1().PodDisruptionBudgets(corev1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		// clusters older than 1.21 do not serve policy/v1, the analysis goes on without the budgets
+		impl.logger.Warnw("error in listing pod disruption budgets, skipping budget analysis", "clusterId", request.ClusterId, "err", err)
+	} else {
+		budgets = budgetList.Items
+	}
+	drainHelper := getDrainHelper(request)
+	drainHelper.K8sClientSet = clientSet
+	analysis, affectedApps := analyseDrain(nodes, pods, budgets, drainHelper)
+	analysis.ClusterId = request.ClusterId
+	analysis.AffectedApps, err = impl.getAffectedApps(affectedApps)
+	if err != nil {
+		return nil, err
+	}
+	return analysis, nil
+}
+
+func (impl *NodeMaintenanceServiceImpl) getAffectedApps(affectedApps map[appEnvKey]*bean.AffectedApp) ([]*bean.AffectedApp, error) {
+	result := make([]*bean.AffectedApp, 0, len(affectedApps))
+	if len(affectedApps) == 0 {
+		return result, nil
+	}
+	appIds, envIds := make([]*int, 0), make([]*int, 0)
+	for key := range affectedApps {
+		appId, envId := key.appId, key.envId
+		appIds = append(appIds, &appId)
+		envIds = append(envIds, &envId)
+	}
+	apps, err := impl.appRepository.FindByIds(appIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching apps", "err", err)
+		return nil, err
+	}
+	environments, err := impl.environmentRepository.FindByIds(envIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching environments", "err", err)
+		return nil, err
+	}
+	appNames, envNames := make(map[int]string), make(map[int]string)
+	for _, devtronApp := range apps {
+		appNames[devtronApp.Id] = devtronApp.AppName
+	}
+	for _, environment := range environments {
+		envNames[environment.Id] = environment.Name
+	}
+	for key, affectedApp := range affectedApps {
+		appName, ok := appNames[key.appId]
+		if !ok {
+			// labels of a deleted app, or of a workload not deployed through devtron
+			continue
+		}
+		affectedApp.AppName = appName
+		affectedApp.EnvName = envNames[key.envId]
+		result = append(result, affectedApp)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].AppName != result[j].AppName {
+			return result[i].AppName < result[j].AppName
+		}
+		return result[i].EnvName < result[j].EnvName
+	})
+	return result, nil
+}
+
+func (impl *NodeMaintenanceServiceImpl) StartMaintenance(ctx context.Context, request *bean.NodeMaintenanceRequest) (*bean.NodeMaintenanceDto, error) {
+	if request.Concurrency == 0 {
+		request.Concurrency = 1
+	}
+	if request.Concurrency > impl.config.MaxConcurrency {
+		msg := fmt.Sprintf("at most %d nodes can be drained together", impl.config.MaxConcurrency)
+		return nil, util.NewApiError(http.StatusBadRequest, msg, msg)
+	}
+	nodeNames, err := impl.ResolveNodeNames(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.checkNodesNotInMaintenance(request.ClusterId, nodeNames)
+	if err != nil {
+		return nil, err
+	}
+	if !request.SkipPreflightChecks {
+		analysis, err := impl.Analyse(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		if analysis.Blocked {
+			msg := "preflight checks found pods or pod disruption budgets blocking the drain, analyse the nodes for details or skip the preflight checks"
+			return nil, util.NewApiError(http.StatusPreconditionFailed, msg, msg)
+		}
+	}
+	drainOptions, err := json.Marshal(getDrainHelper(request))
+	if err != nil {
+		return nil, err
+	}
+	maintenance := &repository.NodeMaintenance{
+		ClusterId:         request.ClusterId,
+		NodeGroup:         request.NodeGroup,
+		Status:            bean.MaintenanceStatusRunning,
+		Concurrency:       request.Concurrency,
+		UncordonAfterSecs: request.UncordonAfterSecs,
+		DrainOptions:      string(drainOptions),
+	}
+	maintenance.CreateAuditLog(request.UserId)
+	err = impl.saveMaintenance(maintenance, nodeNames)
+	if err != nil {
+		impl.logger.Errorw("error in saving node maintenance", "clusterId", request.ClusterId, "nodeNames", nodeNames, "err", err)
+		return nil, err
+	}
+	impl.logger.Infow("node maintenance started", "id", maintenance.Id, "clusterId", request.ClusterId, "nodeNames", nodeNames, "userId", request.UserId)
+	impl.startRun(maintenance.Id)
+	return impl.GetMaintenance(maintenance.Id)
+}
+
+func (impl *NodeMaintenanceServiceImpl) saveMaintenance(maintenance *repository.NodeMaintenance, nodeNames []string) error {
+	tx, err := impl.transactionWrapper.StartTx()
+	if err != nil {
+		return err
+	}
+	defer impl.transactionWrapper.RollbackTx(tx)
+	err = impl.nodeMaintenanceRepository.Save(maintenance, tx)
+	if err != nil {
+		return err
+	}
+	nodes := make([]*repository.NodeMaintenanceNode, 0, len(nodeNames))
+	for _, nodeName := range nodeNames {
+		node := &repository.NodeMaintenanceNode{
+			NodeMaintenanceId: maintenance.Id,
+			NodeName:          nodeName,
+			Status:            bean.NodeStatusPending,
+		}
+		node.AuditLog = maintenance.AuditLog
+		nodes = append(nodes, node)
+	}
+	err = impl.nodeMaintenanceRepository.SaveNodes(nodes, tx)
+	if err != nil {
+		return err
+	}
+	return impl.transactionWrapper.CommitTx(tx)
+}
+
+// checkNodesNotInMaintenance rejects nodes which are part of a maintenance yet to be completed or cancelled
+func (impl *NodeMaintenanceServiceImpl) checkNodesNotInMaintenance(clusterId int, nodeNames []string) error {
+	maintenances, err := impl.nodeMaintenanceRepository.FindByClusterId(clusterId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching node maintenances", "clusterId", clusterId, "err", err)
+		return err
+	}
+	activeIds := make([]int, 0)
+	for _, maintenance := range maintenances {
+		if isActive(maintenance.Status) {
+			activeIds = append(activeIds, maintenance.Id)
+		}
+	}
+	activeNodes, err := impl.nodeMaintenanceRepository.FindNodesByMaintenanceIds(activeIds)
+	if err != nil {
+		impl.logger.Errorw("error in fetching nodes of node maintenances", "ids", activeIds, "err", err)
+		return err
+	}
+	requested := make(map[string]bool, len(nodeNames))
+	for _, nodeName := range nodeNames {
+		requested[nodeName] = true
+	}
+	for _, node := range activeNodes {
+		if requested[node.NodeName] {
+			msg := fmt.Sprintf("node %s is part of node maintenance %d which is yet to be completed", node.NodeName, node.NodeMaintenanceId)
+			return util.NewApiError(http.StatusConflict, msg, msg)
+		}
+	}
+	return nil
+}
+
+func isActive(status string) bool {
+	for _, activeStatus := range bean.ActiveMaintenanceStatuses {
+		if status == activeStatus {
+			return true
+		}
+	}
+	return false
+}
+
+func (impl *NodeMaintenanceServiceImpl) GetMaintenance(id int) (*bean.NodeMaintenanceDto, error) {
+	maintenance, err := impl.getMaintenance(id)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := impl.nodeMaintenanceRepository.FindNodesByMaintenanceId(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching nodes of node maintenance", "id", id, "err", err)
+		return nil, err
+	}
+	return toDto(maintenance, nodes), nil
+}
+
+func (impl *NodeMaintenanceServiceImpl) getMaintenance(id int) (*repository.NodeMaintenance, error) {
+	maintenance, err := impl.nodeMaintenanceRepository.FindById(id)
+	if err != nil {
+		if errors.Is(err, pg.ErrNoRows) {
+			msg := fmt.Sprintf("node maintenance %d not found", id)
+			return nil, util.NewApiError(http.StatusNotFound, msg, msg)
+		}
+		impl.logger.Errorw("error in fetching node maintenance", "id", id, "err", err)
+		return nil, err
+	}
+	return maintenance, nil
+}
+
+func (impl *NodeMaintenanceServiceImpl) GetMaintenances(clusterId int) ([]*bean.NodeMaintenanceDto, error) {
+	maintenances, err := impl.nodeMaintenanceRepository.FindByClusterId(clusterId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching node maintenances", "clusterId", clusterId, "err", err)
+		return nil, err
+	}
+	ids := make([]int, 0, len(maintenances))
+	for _, maintenance := range maintenances {
+		ids = append(ids, maintenance.Id)
+	}
+	nodes, err := impl.nodeMaintenanceRepository.FindNodesByMaintenanceIds(ids)
+	if err != nil {
+		impl.logger.Errorw("error in fetching nodes of node maintenances", "clusterId", clusterId, "err", err)
+		return nil, err
+	}
+	nodesById := make(map[int][]*repository.NodeMaintenanceNode)
+	for _, node := range nodes {
+		nodesById[node.NodeMaintenanceId] = append(nodesById[node.NodeMaintenanceId], node)
+	}
+	result := make([]*bean.NodeMaintenanceDto, 0, len(maintenances))
+	for _, maintenance := range maintenances {
+		result = append(result, toDto(maintenance, nodesById[maintenance.Id]))
+	}
+	return result, nil
+}
+
+func (impl *NodeMaintenanceServiceImpl) CompleteMaintenance(id int, userId int32) (*bean.NodeMaintenanceDto, error) {
+	impl.statusLock.Lock()
+	defer impl.statusLock.Unlock()
+	maintenance, err := impl.getMaintenance(id)
+	if err != nil {
+		return nil, err
+	}
+	if maintenance.Status != bean.MaintenanceStatusInMaintenance {
+		msg := fmt.Sprintf("node maintenance is %s, only a maintenance with all its nodes drained can be completed", maintenance.Status)
+		return nil, util.NewApiError(http.StatusBadRequest, msg, msg)
+	}
+	// the lease keeps other replicas from completing the maintenance at the same time
+	acquired, err := impl.nodeMaintenanceRepository.AcquireLease(id, bean.MaintenanceStatusInMaintenance, impl.leaseOwner, impl.config.GetLeaseDuration())
+	if err != nil {
+		impl.logger.Errorw("error in acquiring lease of node maintenance", "id", id, "err", err)
+		return nil, err
+	} else if !acquired {
+		msg := "node maintenance is being completed by another request"
+		return nil, util.NewApiError(http.StatusConflict, msg, msg)
+	}
+	defer impl.releaseLease(id)
+	err = impl.restoreNodes(maintenance, bean.MaintenanceStatusCompleted, userId)
+	if err != nil {
+		return nil, err
+	}
+	return impl.GetMaintenance(id)
+}
+
+func (impl *NodeMaintenanceServiceImpl) CancelMaintenance(id int, userId int32) (*bean.NodeMaintenanceDto, error) {
+	impl.statusLock.Lock()
+	maintenance, err := impl.getMaintenance(id)
+	if err != nil {
+		impl.statusLock.Unlock()
+		return nil, err
+	}
+	if maintenance.Status != bean.MaintenanceStatusRunning && maintenance.Status != bean.MaintenanceStatusFailed {
+		impl.statusLock.Unlock()
+		msg := fmt.Sprintf("node maintenance is %s, only a running or failed maintenance can be cancelled", maintenance.Status)
+		return nil, util.NewApiError(http.StatusBadRequest, msg, msg)
+	}
+	maintenance.Status = bean.MaintenanceStatusCancelled
+	maintenance.UpdateAuditLog(userId)
+	err = impl.nodeMaintenanceRepository.Update(maintenance)
+	impl.statusLock.Unlock()
+	if err != nil {
+		impl.logger.Errorw("error in updating node maintenance", "id", id, "err", err)
+		return nil, err
+	}
+	// the run sees the status change and does not update the maintenance any further, the nodes are restored once
+	// the drains in progress have stopped, a run on another replica stops when it fails to renew its lease
+	impl.stopRun(id)
+	impl.waitForLeaseRelease(id)
+	err = impl.restoreNodes(maintenance, bean.MaintenanceStatusCancelled, userId)
+	if err != nil {
+		return nil, err
+	}
+	return impl.GetMaintenance(id)
+}
+
+func (impl *NodeMaintenanceServiceImpl) ResumeMaintenance(id int, userId int32) (*bean.NodeMaintenanceDto, error) {
+	impl.statusLock.Lock()
+	defer impl.statusLock.Unlock()
+	maintenance, err := impl.getMaintenance(id)
+	if err != nil {
+		return nil, err
+	}
+	if maintenance.Status != bean.MaintenanceStatusFailed {
+		msg := fmt.Sprintf("node maintenance is %s, only a failed maintenance can be resumed", maintenance.Status)
+		return nil, util.NewApiError(http.StatusBadRequest, msg, msg)
+	}
+	nodes, err := impl.nodeMaintenanceRepository.FindNodesByMaintenanceId(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching nodes of node maintenance", "id", id, "err", err)
+		return nil, err
+	}
+	for _, node := range nodes {
+		if node.Status != bean.NodeStatusFailed {
+			continue
+		}
+		node.Status = bean.NodeStatusPending
+		node.Message = ""
+		node.UpdateAuditLog(userId)
+		err = impl.nodeMaintenanceRepository.UpdateNode(node)
+		if err != nil {
+			impl.logger.Errorw("error in updating node of node maintenance", "id", id, "nodeName", node.NodeName, "err", err)
+			return nil, err
+		}
+	}
+	maintenance.Status = bean.MaintenanceStatusRunning
+	maintenance.Message = ""
+	maintenance.UpdateAuditLog(userId)
+	err = impl.nodeMaintenanceRepository.Update(maintenance)
+	if err != nil {
+		impl.logger.Errorw("error in updating node maintenance", "id", id, "err", err)
+		return nil, err
+	}
+	impl.startRun(id)
+	return impl.GetMaintenance(id)
+}
+
+// restoreNodes uncordons the nodes cordoned by the maintenance and moves it to the final status, nodes which were
+// cordoned before the maintenance are left cordoned and nodes never drained are skipped
+func (impl *NodeMaintenanceServiceImpl) restoreNodes(maintenance *repository.NodeMaintenance, status string, userId int32) error {
+	nodes, err := impl.nodeMaintenanceRepository.FindNodesByMaintenanceId(maintenance.Id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching nodes of node maintenance", "id", maintenance.Id, "err", err)
+		return err
+	}
+	_, _, clientSet, err := impl.k8sCommonService.GetK8sConfigAndClientsByClusterId(context.Background(), maintenance.ClusterId)
+	if err != nil {
+		return err
+	}
+	failedNodes := make([]string, 0)
+	for _, node := range nodes {
+		switch {
+		case node.Status == bean.NodeStatusPending:
+			node.Status = bean.NodeStatusSkipped
+		case node.StartedOn.IsZero() || node.Status == bean.NodeStatusUncordoned || node.Status == bean.NodeStatusSkipped:
+			continue
+		case node.WasUnschedulable:
+			node.Message = "left cordoned as it was cordoned before the maintenance"
+		default:
+			err = impl.uncordonNode(clientSet, node.NodeName)
+			if err != nil {
+				impl.logger.Errorw("error in uncordoning node", "id", maintenance.Id, "nodeName", node.NodeName, "err", err)
+				node.Message = fmt.Sprintf("uncordon failed: %s", err.Error())
+				failedNodes = append(failedNodes, node.NodeName)
+			} else {
+				node.Status = bean.NodeStatusUncordoned
+			}
+		}
+		node.UpdateAuditLog(userId)
+		err = impl.nodeMaintenanceRepository.UpdateNode(node)
+		if err != nil {
+			impl.logger.Errorw("error in updating node of node maintenance", "id", maintenance.Id, "nodeName", node.NodeName, "err", err)
+			return err
+		}
+	}
+	maintenance.Status = status
+	maintenance.Message = ""
+	if len(failedNodes) > 0 {
+		maintenance.Message = fmt.Sprintf("nodes %s could not be uncordoned", strings.Join(failedNodes, ", "))
+	}
+	maintenance.UpdateAuditLog(userId)
+	err = impl.nodeMaintenanceRepository.Update(maintenance)
+	if err != nil {
+		impl.logger.Errorw("error in updating node maintenance", "id", maintenance.Id, "err", err)
+		return err
+	}
+	impl.logger.Infow("node maintenance finished", "id", maintenance.Id, "status", status, "userId", userId)
+	return nil
+}
+
+func (impl *NodeMaintenanceServiceImpl) uncordonNode(clientSet *kubernetes.Clientset, nodeName string) error {
+	node, err := impl.k8sUtil.GetNodeByName(context.Background(), clientSet, nodeName)
+	if err != nil {
+		return err
+	}
+	if !node.Spec.Unschedulable {
+		return nil
+	}
+	_, err = k8s2.UpdateNodeUnschedulableProperty(false, node, clientSet)
+	return err
+}
+
+// startRun drains the maintenance in the background once this replica holds its lease, a maintenance leased by
+// another replica is left to it
+func (impl *NodeMaintenanceServiceImpl) startRun(id int) {
+	impl.runsLock.Lock()
+	defer impl.runsLock.Unlock()
+	if _, ok := impl.runs[id]; ok {
+		return
+	}
+	acquired, err := impl.nodeMaintenanceRepository.AcquireLease(id, bean.MaintenanceStatusRunning, impl.leaseOwner, impl.config.GetLeaseDuration())
+	if err != nil {
+		impl.logger.Errorw("error in acquiring lease of node maintenance", "id", id, "err", err)
+		return
+	} else if !acquired {
+		impl.logger.Debugw("node maintenance is run by another replica", "id", id)
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &maintenanceRun{cancel: cancel, done: make(chan struct{})}
+	impl.runs[id] = run
+	go impl.renewLease(ctx, id, cancel)
+	go func() {
+		defer func() {
+			impl.runsLock.Lock()
+			delete(impl.runs, id)
+			impl.runsLock.Unlock()
+			cancel()
+			impl.releaseLease(id)
+			close(run.done)
+		}()
+		impl.runMaintenance(ctx, id)
+	}()
+}
+
+// renewLease keeps the lease while the run lasts and stops the run once the lease is lost, which is also how a run
+// notices that the maintenance was cancelled on another replica
+func (impl *NodeMaintenanceServiceImpl) renewLease(ctx context.Context, id int, cancel context.CancelFunc) {
+	ticker := time.NewTicker(impl.config.GetLeaseDuration() / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			acquired, err := impl.nodeMaintenanceRepository.AcquireLease(id, bean.MaintenanceStatusRunning, impl.leaseOwner, impl.config.GetLeaseDuration())
+			if err != nil {
+				impl.logger.Errorw("error in renewing lease of node maintenance", "id", id, "err", err)
+				continue
+			}
+			if !acquired {
+				impl.logger.Infow("node maintenance is no longer running here, stopping its drains", "id", id)
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+func (impl *NodeMaintenanceServiceImpl) releaseLease(id int) {
+	err := impl.nodeMaintenanceRepository.ReleaseLease(id, impl.leaseOwner)
+	if err != nil {
+		impl.logger.Errorw("error in releasing lease of node maintenance", "id", id, "err", err)
+	}
+}
+
+// waitForLeaseRelease waits for the run of another replica to stop its drains, at most until its lease expires
+func (impl *NodeMaintenanceServiceImpl) waitForLeaseRelease(id int) {
+	deadline := time.Now().Add(impl.config.GetLeaseDuration())
+	for time.Now().Before(deadline) {
+		held, err := impl.nodeMaintenanceRepository.IsLeaseHeld(id)
+		if err != nil {
+			impl.logger.Errorw("error in checking lease of node maintenance", "id", id, "err", err)
+			return
+		} else if !held {
+			return
+		}
+		time.Sleep(leasePollInterval)
+	}
+}
+
+// stopRun cancels the drains of the maintenance and waits for the run to end
+func (impl *NodeMaintenanceServiceImpl) stopRun(id int) {
+	impl.runsLock.Lock()
+	run, ok := impl.runs[id]
+	impl.runsLock.Unlock()
+	if !ok {
+		return
+	}
+	run.cancel()
+	<-run.done
+}
+
+// runMaintenance drains the pending nodes batch by batch, a node left draining by a restart is drained again. The
+// run stops at the first batch with a failed node so that a drain problem does not spread to more nodes
+func (impl *NodeMaintenanceServiceImpl) runMaintenance(ctx context.Context, id int) {
+	maintenance, err := impl.getMaintenance(id)
+	if err != nil {
+		return
+	}
+	nodes, err := impl.nodeMaintenanceRepository.FindNodesByMaintenanceId(id)
+	if err != nil {
+		impl.logger.Errorw("error in fetching nodes of node maintenance", "id", id, "err", err)
+		return
+	}
+	drainHelper := &capacityBean.NodeDrainHelper{GracePeriodSeconds: -1}
+	if len(maintenance.DrainOptions) > 0 {
+		err = json.Unmarshal([]byte(maintenance.DrainOptions), drainHelper)
+		if err != nil {
+			impl.finishRun(ctx, id, bean.MaintenanceStatusFailed, fmt.Sprintf("invalid drain options: %s", err.Error()))
+			return
+		}
+	}
+	_, _, clientSet, err := impl.k8sCommonService.GetK8sConfigAndClientsByClusterId(ctx, maintenance.ClusterId)
+	if err != nil {
+		impl.finishRun(ctx, id, bean.MaintenanceStatusFailed, err.Error())
+		return
+	}
+	pending := make([]*repository.NodeMaintenanceNode, 0, len(nodes))
+	for _, node := range nodes {
+		if node.Status == bean.NodeStatusPending || node.Status == bean.NodeStatusDraining {
+			pending = append(pending, node)
+		}
+	}
+	concurrency := maintenance.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	for start := 0; start < len(pending); start += concurrency {
+		end := start + concurrency
+		if end > len(pending) {
+			end = len(pending)
+		}
+		failedNodes := make([]string, 0)
+		var failedLock sync.Mutex
+		wg := sync.WaitGroup{}
+		for _, node := range pending[start:end] {
+			wg.Add(1)
+			go func(node *repository.NodeMaintenanceNode) {
+				defer wg.Done()
+				if ok := impl.drainNode(ctx, maintenance, node, drainHelper, clientSet); !ok {
+					failedLock.Lock()
+					failedNodes = append(failedNodes, node.NodeName)
+					failedLock.Unlock()
+				}
+			}(node)
+		}
+		wg.Wait()
+		if ctx.Err() != nil {
+			return
+		}
+		if len(failedNodes) > 0 {
+			sort.Strings(failedNodes)
+			impl.finishRun(ctx, id, bean.MaintenanceStatusFailed, fmt.Sprintf("nodes %s could not be drained", strings.Join(failedNodes, ", ")))
+			return
+		}
+	}
+	impl.finishRun(ctx, id, bean.MaintenanceStatusInMaintenance, "")
+}
+
+// drainNode cordons and drains the node, the cordon state of the node before the maintenance is recorded on the first
+// attempt so that the node is restored to it
+func (impl *NodeMaintenanceServiceImpl) drainNode(ctx context.Context, maintenance *repository.NodeMaintenance, node *repository.NodeMaintenanceNode,
+	drainHelper *capacityBean.NodeDrainHelper, clientSet *kubernetes.Clientset) bool {
+	if node.StartedOn.IsZero() {
+		k8sNode, err := impl.k8sUtil.GetNodeByName(ctx, clientSet, node.NodeName)
+		if err != nil {
+			impl.logger.Errorw("error in getting node", "id", maintenance.Id, "nodeName", node.NodeName, "err", err)
+			impl.updateNode(node, bean.NodeStatusFailed, err.Error(), maintenance.UpdatedBy)
+			return false
+		}
+		node.WasUnschedulable = k8sNode.Spec.Unschedulable
+		node.StartedOn = time.Now()
+	}
+	impl.updateNode(node, bean.NodeStatusDraining, "", maintenance.UpdatedBy)
+	drainCtx, cancel := context.WithTimeout(ctx, impl.config.GetDrainTimeout())
+	defer cancel()
+	nodeDrainHelper := *drainHelper
+	_, err := impl.k8sCapacityService.DrainNode(drainCtx, &capacityBean.NodeUpdateRequestDto{
+		ClusterId:       maintenance.ClusterId,
+		Name:            node.NodeName,
+		NodeDrainHelper: &nodeDrainHelper,
+	})
+	if ctx.Err() != nil {
+		// cancelled, the node stays draining and is either restored or drained again on resume
+		return true
+	}
+	node.FinishedOn = time.Now()
+	if err != nil {
+		impl.logger.Errorw("error in draining node", "id", maintenance.Id, "nodeName", node.NodeName, "err", err)
+		impl.updateNode(node, bean.NodeStatusFailed, err.Error(), maintenance.UpdatedBy)
+		return false
+	}
+	impl.updateNode(node, bean.NodeStatusDrained, "", maintenance.UpdatedBy)
+	return true
+}
+
+func (impl *NodeMaintenanceServiceImpl) updateNode(node *repository.NodeMaintenanceNode, status, message string, userId int32) {
+	node.Status = status
+	node.Message = message
+	node.UpdateAuditLog(userId)
+	err := impl.nodeMaintenanceRepository.UpdateNode(node)
+	if err != nil {
+		impl.logger.Errorw("error in updating node of node maintenance", "id", node.NodeMaintenanceId, "nodeName", node.NodeName, "status", status, "err", err)
+	}
+}
+
+// finishRun moves a running maintenance to the status, unless it has been cancelled meanwhile
+func (impl *NodeMaintenanceServiceImpl) finishRun(ctx context.Context, id int, status, message string) {
+	impl.statusLock.Lock()
+	defer impl.statusLock.Unlock()
+	maintenance, err := impl.getMaintenance(id)
+	if err != nil || maintenance.Status != bean.MaintenanceStatusRunning || ctx.Err() != nil {
+		return
+	}
+	maintenance.Status = status
+	maintenance.Message = message
+	if status == bean.MaintenanceStatusInMaintenance {
+		maintenance.DrainedOn = time.Now()
+	}
+	maintenance.UpdatedOn = time.Now()
+	err = impl.nodeMaintenanceRepository.Update(maintenance)
+	if err != nil {
+		impl.logger.Errorw("error in updating node maintenance", "id", id, "status", status, "err", err)
+		return
+	}
+	impl.logger.Infow("node maintenance run finished", "id", id, "status", status, "message", message)
+}
+
+// watchMaintenances takes over the running maintenances whose lease expired, those of a restarted or lost replica,
+// and completes the maintenances due to be uncordoned
+func (impl *NodeMaintenanceServiceImpl) watchMaintenances() {
+	maintenances, err := impl.nodeMaintenanceRepository.FindByStatuses([]string{bean.MaintenanceStatusRunning})
+	if err != nil {
+		impl.logger.Errorw("error in fetching running node maintenances", "err", err)
+	}
+	for _, maintenance := range maintenances {
+		impl.startRun(maintenance.Id)
+	}
+	impl.completeDueMaintenances()
+}
+
+func (impl *NodeMaintenanceServiceImpl) completeDueMaintenances() {
+	maintenances, err := impl.nodeMaintenanceRepository.FindByStatuses([]string{bean.MaintenanceStatusInMaintenance})
+	if err != nil {
+		impl.logger.Errorw("error in fetching node maintenances in maintenance", "err", err)
+		return
+	}
+	for _, maintenance := range maintenances {
+		if maintenance.UncordonAfterSecs <= 0 || time.Since(maintenance.DrainedOn) < time.Duration(maintenance.UncordonAfterSecs)*time.Second {
+			continue
+		}
+		_, err = impl.CompleteMaintenance(maintenance.Id, userBean.SystemUserId)
+		if err != nil {
+			impl.logger.Errorw("error in completing node maintenance", "id", maintenance.Id, "err", err)
+		}
+	}
+}
+
+func toDto(maintenance *repository.NodeMaintenance, nodes []*repository.NodeMaintenanceNode) *bean.NodeMaintenanceDto {
+	dto := &bean.NodeMaintenanceDto{
+		Id:                maintenance.Id,
+		ClusterId:         maintenance.ClusterId,
+		NodeGroup:         maintenance.NodeGroup,
+		Status:            maintenance.Status,
+		Concurrency:       maintenance.Concurrency,
+		UncordonAfterSecs: maintenance.UncordonAfterSecs,
+		Message:           maintenance.Message,
+		Nodes:             make([]*bean.MaintenanceNodeDto, 0, len(nodes)),
+		CreatedBy:         maintenance.CreatedBy,
+		CreatedOn:         maintenance.CreatedOn,
+		UpdatedOn:         maintenance.UpdatedOn,
+	}
+	if len(maintenance.DrainOptions) > 0 {
+		drainHelper := &capacityBean.NodeDrainHelper{}
+		if err := json.Unmarshal([]byte(maintenance.DrainOptions), drainHelper); err == nil {
+			dto.NodeDrainHelper = drainHelper
+		}
+	}
+	if !maintenance.DrainedOn.IsZero() {
+		drainedOn := maintenance.DrainedOn
+		dto.DrainedOn = &drainedOn
+	}
+	for _, node := range nodes {
+		nodeDto := &bean.MaintenanceNodeDto{
+			Name:    node.NodeName,
+			Status:  node.Status,
+			Message: node.Message,
+		}
+		if !node.StartedOn.IsZero() {
+			startedOn := node.StartedOn
+			nodeDto.StartedOn = &startedOn
+		}
+		if !node.FinishedOn.IsZero() {
+			finishedOn := node.FinishedOn
+			nodeDto.FinishedOn = &finishedOn
+		}
+		dto.Nodes = append(dto.Nodes, nodeDto)
+	}
+	return dto
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity/bean"
+	"time"
+)
+
+const (
+	// MaintenanceStatusRunning nodes are being drained batch by batch
+	MaintenanceStatusRunning = "Running"
+	// MaintenanceStatusInMaintenance all the nodes are drained and stay cordoned till the maintenance is completed
+	MaintenanceStatusInMaintenance = "InMaintenance"
+	MaintenanceStatusCompleted     = "Completed"
+	// MaintenanceStatusFailed a node could not be drained, the remaining nodes are not drained till it is resumed
+	MaintenanceStatusFailed    = "Failed"
+	MaintenanceStatusCancelled = "Cancelled"
+)
+
+const (
+	NodeStatusPending    = "Pending"
+	NodeStatusDraining   = "Draining"
+	NodeStatusDrained    = "Drained"
+	NodeStatusFailed     = "Failed"
+	NodeStatusUncordoned = "Uncordoned"
+	// NodeStatusSkipped the maintenance was cancelled before the node was drained
+	NodeStatusSkipped = "Skipped"
+)
+
+const (
+	// pod labels set by the devtron charts
+	DevtronAppIdLabel = "appId"
+	DevtronEnvIdLabel = "envId"
+)
+
+// ActiveMaintenanceStatuses are the statuses in which the nodes of a maintenance can not be part of another one
+var ActiveMaintenanceStatuses = []string{MaintenanceStatusRunning, MaintenanceStatusInMaintenance, MaintenanceStatusFailed}
+
+type NodeMaintenanceConfig struct {
+	// DrainTimeoutSecs bounds the drain of a node, evictions disallowed by pod disruption budgets are retried till then
+	DrainTimeoutSecs int `env:"NODE_MAINTENANCE_DRAIN_TIMEOUT_SECS" envDefault:"1800"`
+	MaxConcurrency   int `env:"NODE_MAINTENANCE_MAX_CONCURRENCY" envDefault:"5"`
+	// LeaseSecs is how long a replica keeps a maintenance without renewing, another replica resumes it after that
+	LeaseSecs int `env:"NODE_MAINTENANCE_LEASE_SECS" envDefault:"60"`
+}
+
+func (config *NodeMaintenanceConfig) GetDrainTimeout() time.Duration {
+	return time.Duration(config.DrainTimeoutSecs) * time.Second
+}
+
+// minLeaseSecs leaves room to renew the lease a few times before it expires
+const minLeaseSecs = 15
+
+func (config *NodeMaintenanceConfig) GetLeaseDuration() time.Duration {
+	if config.LeaseSecs < minLeaseSecs {
+		return minLeaseSecs * time.Second
+	}
+	return time.Duration(config.LeaseSecs) * time.Second
+}
+
+// NodeMaintenanceRequest selects the nodes either by name or by node group
+type NodeMaintenanceRequest struct {
+	ClusterId int      `json:"clusterId" validate:"required,min=1"`
+	NodeNames []string `json:"nodeNames"`
+	NodeGroup string   `json:"nodeGroup"`
+	// Concurrency is the number of nodes drained together, defaults to one node at a time
+	Concurrency int `json:"concurrency" validate:"min=0"`
+	// UncordonAfterSecs uncordons the nodes this long after all of them are drained, zero keeps them cordoned till the
+	// maintenance is completed
+	UncordonAfterSecs   int                   `json:"uncordonAfterSecs" validate:"min=0"`
+	SkipPreflightChecks bool                  `json:"skipPreflightChecks"`
+	NodeDrainHelper     *bean.NodeDrainHelper `json:"nodeDrainOptions"`
+	UserId              int32                 `json:"-"`
+}
+
+type PreflightAnalysis struct {
+	ClusterId         int                         `json:"clusterId"`
+	Nodes             []*NodeAnalysis             `json:"nodes"`
+	DisruptionBudgets []*DisruptionBudgetAnalysis `json:"disruptionBudgets"`
+	AffectedApps      []*AffectedApp              `json:"affectedApps"`
+	// Blocked is set when a node can not be drained as is, see the blocking reasons of the nodes and the budgets
+	Blocked bool `json:"blocked"`
+}
+
+type NodeAnalysis struct {
+	Name            string   `json:"name"`
+	NodeGroup       string   `json:"nodeGroup"`
+	Unschedulable   bool     `json:"unschedulable"`
+	PodsToEvict     []string `json:"podsToEvict"`
+	BlockingReasons []string `json:"blockingReasons"`
+	Warnings        []string `json:"warnings"`
+}
+
+type DisruptionBudgetAnalysis struct {
+	Name               string `json:"name"`
+	Namespace          string `json:"namespace"`
+	DisruptionsAllowed int    `json:"disruptionsAllowed"`
+	// PodsToEvict is the count of pods of the budget on the selected nodes, MaxPodsOnANode the most on a single node
+	PodsToEvict    int    `json:"podsToEvict"`
+	MaxPodsOnANode int    `json:"maxPodsOnANode"`
+	Blocking       bool   `json:"blocking"`
+	Message        string `json:"message,omitempty"`
+}
+
+type AffectedApp struct {
+	AppId    int      `json:"appId"`
+	AppName  string   `json:"appName"`
+	EnvId    int      `json:"envId"`
+	EnvName  string   `json:"envName"`
+	PodCount int      `json:"podCount"`
+	Nodes    []string `json:"nodes"`
+}
+
+type NodeMaintenanceDto struct {
+	Id                int                   `json:"id"`
+	ClusterId         int                   `json:"clusterId"`
+	NodeGroup         string                `json:"nodeGroup,omitempty"`
+	Status            string                `json:"status"`
+	Concurrency       int                   `json:"concurrency"`
+	UncordonAfterSecs int                   `json:"uncordonAfterSecs"`
+	NodeDrainHelper   *bean.NodeDrainHelper `json:"nodeDrainOptions,omitempty"`
+	Message           string                `json:"message,omitempty"`
+	DrainedOn         *time.Time            `json:"drainedOn,omitempty"`
+	Nodes             []*MaintenanceNodeDto `json:"nodes"`
+	CreatedBy         int32                 `json:"createdBy"`
+	CreatedOn         time.Time             `json:"createdOn"`
+	UpdatedOn         time.Time             `json:"updatedOn"`
+}
+
+type MaintenanceNodeDto struct {
+	Name       string     `json:"name"`
+	Status     string     `json:"status"`
+	Message    string     `json:"message,omitempty"`
+	StartedOn  *time.Time `json:"startedOn,omitempty"`
+	FinishedOn *time.Time `json:"finishedOn,omitempty"`
+}
+
+// NodeNames returns the names of the nodes of the maintenance
+func (dto *NodeMaintenanceDto) NodeNames() []string {
+	nodeNames := make([]string, 0, len(dto.Nodes))
+	for _, node := range dto.Nodes {
+		nodeNames = append(nodeNames, node.Name)
+	}
+	return nodeNames
+}
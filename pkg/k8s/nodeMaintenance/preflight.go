@@ -0,0 +1,148 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package nodeMaintenance
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/k8s/capacity"
+	capacityBean "github.com/devtron-labs/devtron/pkg/k8s/capacity/bean"
+	"github.com/devtron-labs/devtron/pkg/k8s/nodeMaintenance/bean"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sort"
+	"strconv"
+)
+
+type appEnvKey struct {
+	appId int
+	envId int
+}
+
+type budgetPods struct {
+	budget    *policyv1.PodDisruptionBudget
+	selector  labels.Selector
+	podsCount int
+	nodePods  map[string]int
+}
+
+// analyseDrain builds the preflight analysis of draining the nodes, the pods are filtered like the drain does so that
+// the pods which would fail the drain show up as blocking reasons of their node. The affected apps are returned by
+// app and env id, their names are left to the caller
+func analyseDrain(nodes []corev1.Node, pods []corev1.Pod, budgets []policyv1.PodDisruptionBudget,
+	drainHelper *capacityBean.NodeDrainHelper) (*bean.PreflightAnalysis, map[appEnvKey]*bean.AffectedApp) {
+	podsByNode := make(map[string][]corev1.Pod)
+	for _, pod := range pods {
+		podsByNode[pod.Spec.NodeName] = append(podsByNode[pod.Spec.NodeName], pod)
+	}
+	budgetsPods := make([]*budgetPods, 0, len(budgets))
+	for i := range budgets {
+		if budgets[i].Spec.Selector == nil {
+			// a nil selector selects no pods
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(budgets[i].Spec.Selector)
+		if err != nil {
+			continue
+		}
+		budgetsPods = append(budgetsPods, &budgetPods{budget: &budgets[i], selector: selector, nodePods: make(map[string]int)})
+	}
+	analysis := &bean.PreflightAnalysis{
+		Nodes:             make([]*bean.NodeAnalysis, 0, len(nodes)),
+		DisruptionBudgets: make([]*bean.DisruptionBudgetAnalysis, 0),
+	}
+	affectedApps := make(map[appEnvKey]*bean.AffectedApp)
+	for i := range nodes {
+		node := &nodes[i]
+		podList := capacityBean.FilterPods(&corev1.PodList{Items: podsByNode[node.Name]}, drainHelper.MakeFilters())
+		nodeAnalysis := &bean.NodeAnalysis{
+			Name:            node.Name,
+			NodeGroup:       capacity.GetNodeGroup(node),
+			Unschedulable:   node.Spec.Unschedulable,
+			PodsToEvict:     make([]string, 0),
+			BlockingReasons: make([]string, 0),
+			Warnings:        podList.Warnings(),
+		}
+		for _, err := range podList.Errors() {
+			nodeAnalysis.BlockingReasons = append(nodeAnalysis.BlockingReasons, err.Error())
+		}
+		sort.Strings(nodeAnalysis.BlockingReasons)
+		for _, pod := range podList.Pods() {
+			nodeAnalysis.PodsToEvict = append(nodeAnalysis.PodsToEvict, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+			for _, budgetPod := range budgetsPods {
+				if budgetPod.budget.Namespace == pod.Namespace && budgetPod.selector.Matches(labels.Set(pod.Labels)) {
+					budgetPod.podsCount++
+					budgetPod.nodePods[node.Name]++
+				}
+			}
+			addAffectedApp(affectedApps, &pod)
+		}
+		if len(nodeAnalysis.BlockingReasons) > 0 {
+			analysis.Blocked = true
+		}
+		analysis.Nodes = append(analysis.Nodes, nodeAnalysis)
+	}
+	for _, budgetPod := range budgetsPods {
+		if budgetPod.podsCount == 0 {
+			continue
+		}
+		budgetAnalysis := &bean.DisruptionBudgetAnalysis{
+			Name:               budgetPod.budget.Name,
+			Namespace:          budgetPod.budget.Namespace,
+			DisruptionsAllowed: int(budgetPod.budget.Status.DisruptionsAllowed),
+			PodsToEvict:        budgetPod.podsCount,
+		}
+		for _, count := range budgetPod.nodePods {
+			if count > budgetAnalysis.MaxPodsOnANode {
+				budgetAnalysis.MaxPodsOnANode = count
+			}
+		}
+		if budgetAnalysis.DisruptionsAllowed == 0 {
+			budgetAnalysis.Blocking = true
+			budgetAnalysis.Message = "budget allows no disruptions, its pods can not be evicted till more of its pods are healthy"
+			analysis.Blocked = true
+		} else if budgetAnalysis.MaxPodsOnANode > budgetAnalysis.DisruptionsAllowed {
+			budgetAnalysis.Message = fmt.Sprintf("%d pods of the budget are on one node while %d disruptions are allowed, the drain waits for replacements to be healthy",
+				budgetAnalysis.MaxPodsOnANode, budgetAnalysis.DisruptionsAllowed)
+		}
+		analysis.DisruptionBudgets = append(analysis.DisruptionBudgets, budgetAnalysis)
+	}
+	return analysis, affectedApps
+}
+
+// addAffectedApp counts the pod against its devtron app and environment, known from the labels of the devtron charts
+func addAffectedApp(affectedApps map[appEnvKey]*bean.AffectedApp, pod *corev1.Pod) {
+	appId, err := strconv.Atoi(pod.Labels[bean.DevtronAppIdLabel])
+	if err != nil || appId <= 0 {
+		return
+	}
+	envId, err := strconv.Atoi(pod.Labels[bean.DevtronEnvIdLabel])
+	if err != nil || envId <= 0 {
+		return
+	}
+	key := appEnvKey{appId: appId, envId: envId}
+	affectedApp, ok := affectedApps[key]
+	if !ok {
+		affectedApp = &bean.AffectedApp{AppId: appId, EnvId: envId, Nodes: make([]string, 0)}
+		affectedApps[key] = affectedApp
+	}
+	affectedApp.PodCount++
+	if len(affectedApp.Nodes) == 0 || affectedApp.Nodes[len(affectedApp.Nodes)-1] != pod.Spec.NodeName {
+		affectedApp.Nodes = append(affectedApp.Nodes, pod.Spec.NodeName)
+	}
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package nodeMaintenance
+
+import (
+	capacityBean "github.com/devtron-labs/devtron/pkg/k8s/capacity/bean"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func newTestNode(name string) corev1.Node {
+	return corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+}
+
+func newTestPod(name, nodeName string, podLabels map[string]string, managed bool) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: podLabels},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if managed {
+		controller := true
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: name + "-rs", Controller: &controller}}
+	}
+	return pod
+}
+
+func newTestBudget(name string, matchLabels map[string]string, disruptionsAllowed int32) policyv1.PodDisruptionBudget {
+	return policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: matchLabels}},
+		Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: disruptionsAllowed},
+	}
+}
+
+func TestAnalyseDrain(t *testing.T) {
+	appLabels := map[string]string{"app": "web", "appId": "3", "envId": "2"}
+
+	t.Run("pods to evict and affected apps are reported by node", func(t *testing.T) {
+		nodes := []corev1.Node{newTestNode("node-1"), newTestNode("node-2")}
+		pods := []corev1.Pod{
+			newTestPod("web-1", "node-1", appLabels, true),
+			newTestPod("web-2", "node-2", appLabels, true),
+			newTestPod("other", "node-3", nil, true),
+		}
+		analysis, affectedApps := analyseDrain(nodes, pods, nil, &capacityBean.NodeDrainHelper{})
+		assert.False(t, analysis.Blocked)
+		assert.Len(t, analysis.Nodes, 2)
+		assert.Equal(t, []string{"default/web-1"}, analysis.Nodes[0].PodsToEvict)
+		assert.Equal(t, []string{"default/web-2"}, analysis.Nodes[1].PodsToEvict)
+		assert.Len(t, affectedApps, 1)
+		affectedApp := affectedApps[appEnvKey{appId: 3, envId: 2}]
+		assert.Equal(t, 2, affectedApp.PodCount)
+		assert.Equal(t, []string{"node-1", "node-2"}, affectedApp.Nodes)
+	})
+
+	t.Run("unmanaged pods block the drain unless forced", func(t *testing.T) {
+		nodes := []corev1.Node{newTestNode("node-1")}
+		pods := []corev1.Pod{newTestPod("standalone", "node-1", nil, false)}
+		analysis, _ := analyseDrain(nodes, pods, nil, &capacityBean.NodeDrainHelper{})
+		assert.True(t, analysis.Blocked)
+		assert.Len(t, analysis.Nodes[0].BlockingReasons, 1)
+		assert.Empty(t, analysis.Nodes[0].PodsToEvict)
+
+		analysis, _ = analyseDrain(nodes, pods, nil, &capacityBean.NodeDrainHelper{Force: true})
+		assert.False(t, analysis.Blocked)
+		assert.Equal(t, []string{"default/standalone"}, analysis.Nodes[0].PodsToEvict)
+		assert.Len(t, analysis.Nodes[0].Warnings, 1)
+	})
+
+	t.Run("disruption budgets of the evicted pods are analysed", func(t *testing.T) {
+		nodes := []corev1.Node{newTestNode("node-1")}
+		pods := []corev1.Pod{
+			newTestPod("web-1", "node-1", appLabels, true),
+			newTestPod("web-2", "node-1", appLabels, true),
+			newTestPod("db-1", "node-1", map[string]string{"app": "db"}, true),
+		}
+		budgets := []policyv1.PodDisruptionBudget{
+			newTestBudget("web", map[string]string{"app": "web"}, 1),
+			newTestBudget("db", map[string]string{"app": "db"}, 0),
+			newTestBudget("cache", map[string]string{"app": "cache"}, 0),
+		}
+		analysis, _ := analyseDrain(nodes, pods, budgets, &capacityBean.NodeDrainHelper{})
+		assert.True(t, analysis.Blocked)
+		assert.Len(t, analysis.DisruptionBudgets, 2)
+		web, db := analysis.DisruptionBudgets[0], analysis.DisruptionBudgets[1]
+		assert.Equal(t, "web", web.Name)
+		assert.False(t, web.Blocking)
+		assert.Equal(t, 2, web.PodsToEvict)
+		assert.Equal(t, 2, web.MaxPodsOnANode)
+		assert.NotEmpty(t, web.Message)
+		assert.Equal(t, "db", db.Name)
+		assert.True(t, db.Blocking)
+	})
+}
@@ -0,0 +1,170 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+// NodeMaintenance leaves out the lease columns, they are only changed through the lease queries so that updates of the
+// maintenance do not overwrite a lease taken meanwhile
+type NodeMaintenance struct {
+	tableName         struct{}  `sql:"node_maintenance" pg:",discard_unknown_columns"`
+	Id                int       `sql:"id,pk"`
+	ClusterId         int       `sql:"cluster_id,notnull"`
+	NodeGroup         string    `sql:"node_group"`
+	Status            string    `sql:"status,notnull"`
+	Concurrency       int       `sql:"concurrency,notnull"`
+	UncordonAfterSecs int       `sql:"uncordon_after_secs,notnull"`
+	DrainOptions      string    `sql:"drain_options"`
+	Message           string    `sql:"message"`
+	DrainedOn         time.Time `sql:"drained_on"`
+	sql.AuditLog
+}
+
+type NodeMaintenanceNode struct {
+	tableName         struct{}  `sql:"node_maintenance_node" pg:",discard_unknown_columns"`
+	Id                int       `sql:"id,pk"`
+	NodeMaintenanceId int       `sql:"node_maintenance_id,notnull"`
+	NodeName          string    `sql:"node_name,notnull"`
+	Status            string    `sql:"status,notnull"`
+	Message           string    `sql:"message"`
+	WasUnschedulable  bool      `sql:"was_unschedulable,notnull"`
+	StartedOn         time.Time `sql:"started_on"`
+	FinishedOn        time.Time `sql:"finished_on"`
+	sql.AuditLog
+}
+
+type NodeMaintenanceRepository interface {
+	Save(maintenance *NodeMaintenance, tx *pg.Tx) error
+	Update(maintenance *NodeMaintenance) error
+	FindById(id int) (*NodeMaintenance, error)
+	FindByClusterId(clusterId int) ([]*NodeMaintenance, error)
+	FindByStatuses(statuses []string) ([]*NodeMaintenance, error)
+	SaveNodes(nodes []*NodeMaintenanceNode, tx *pg.Tx) error
+	UpdateNode(node *NodeMaintenanceNode) error
+	FindNodesByMaintenanceId(maintenanceId int) ([]*NodeMaintenanceNode, error)
+	FindNodesByMaintenanceIds(maintenanceIds []int) ([]*NodeMaintenanceNode, error)
+	// AcquireLease takes or renews the lease of the maintenance for the owner while the maintenance is in the status,
+	// false is returned when the status changed or another owner holds an unexpired lease
+	AcquireLease(id int, status string, owner string, duration time.Duration) (bool, error)
+	ReleaseLease(id int, owner string) error
+	// IsLeaseHeld tells if any owner holds an unexpired lease of the maintenance
+	IsLeaseHeld(id int) (bool, error)
+}
+
+type NodeMaintenanceRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewNodeMaintenanceRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *NodeMaintenanceRepositoryImpl {
+	return &NodeMaintenanceRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (repo *NodeMaintenanceRepositoryImpl) Save(maintenance *NodeMaintenance, tx *pg.Tx) error {
+	return tx.Insert(maintenance)
+}
+
+func (repo *NodeMaintenanceRepositoryImpl) Update(maintenance *NodeMaintenance) error {
+	return repo.dbConnection.Update(maintenance)
+}
+
+func (repo *NodeMaintenanceRepositoryImpl) FindById(id int) (*NodeMaintenance, error) {
+	maintenance := &NodeMaintenance{}
+	err := repo.dbConnection.Model(maintenance).
+		Where("id = ?", id).
+		Select()
+	return maintenance, err
+}
+
+func (repo *NodeMaintenanceRepositoryImpl) FindByClusterId(clusterId int) ([]*NodeMaintenance, error) {
+	var maintenances []*NodeMaintenance
+	err := repo.dbConnection.Model(&maintenances).
+		Where("cluster_id = ?", clusterId).
+		Order("id DESC").
+		Select()
+	return maintenances, err
+}
+
+func (repo *NodeMaintenanceRepositoryImpl) FindByStatuses(statuses []string) ([]*NodeMaintenance, error) {
+	var maintenances []*NodeMaintenance
+	err := repo.dbConnection.Model(&maintenances).
+		Where("status IN (?)", pg.In(statuses)).
+		Order("id").
+		Select()
+	return maintenances, err
+}
+
+func (repo *NodeMaintenanceRepositoryImpl) SaveNodes(nodes []*NodeMaintenanceNode, tx *pg.Tx) error {
+	return tx.Insert(&nodes)
+}
+
+func (repo *NodeMaintenanceRepositoryImpl) UpdateNode(node *NodeMaintenanceNode) error {
+	return repo.dbConnection.Update(node)
+}
+
+func (repo *NodeMaintenanceRepositoryImpl) FindNodesByMaintenanceId(maintenanceId int) ([]*NodeMaintenanceNode, error) {
+	var nodes []*NodeMaintenanceNode
+	err := repo.dbConnection.Model(&nodes).
+		Where("node_maintenance_id = ?", maintenanceId).
+		Order("id").
+		Select()
+	return nodes, err
+}
+
+func (repo *NodeMaintenanceRepositoryImpl) FindNodesByMaintenanceIds(maintenanceIds []int) ([]*NodeMaintenanceNode, error) {
+	var nodes []*NodeMaintenanceNode
+	if len(maintenanceIds) == 0 {
+		return nodes, nil
+	}
+	err := repo.dbConnection.Model(&nodes).
+		Where("node_maintenance_id IN (?)", pg.In(maintenanceIds)).
+		Order("id").
+		Select()
+	return nodes, err
+}
+
+func (repo *NodeMaintenanceRepositoryImpl) AcquireLease(id int, status string, owner string, duration time.Duration) (bool, error) {
+	result, err := repo.dbConnection.Exec("UPDATE node_maintenance SET lease_owner = ?, lease_expires_on = now() + ? * interval '1 second'"+
+		" WHERE id = ? AND status = ? AND (lease_owner IS NULL OR lease_owner = ? OR lease_expires_on < now());",
+		owner, int(duration.Seconds()), id, status, owner)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (repo *NodeMaintenanceRepositoryImpl) ReleaseLease(id int, owner string) error {
+	_, err := repo.dbConnection.Exec("UPDATE node_maintenance SET lease_owner = NULL, lease_expires_on = NULL WHERE id = ? AND lease_owner = ?;", id, owner)
+	return err
+}
+
+func (repo *NodeMaintenanceRepositoryImpl) IsLeaseHeld(id int) (bool, error) {
+	count, err := repo.dbConnection.Model((*NodeMaintenance)(nil)).
+		Where("id = ?", id).
+		Where("lease_owner IS NOT NULL").
+		Where("lease_expires_on >= now()").
+		Count()
+	return count > 0, err
+}
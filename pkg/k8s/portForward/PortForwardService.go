@@ -0,0 +1,359 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package portForward
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/caarlos0/env"
+	"github.com/devtron-labs/common-lib/utils/k8s"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/argoApplication/read/config"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterCredentials"
+	"github.com/devtron-labs/devtron/pkg/cluster/read"
+	"github.com/devtron-labs/devtron/pkg/k8s/portForward/bean"
+	"github.com/devtron-labs/devtron/pkg/kubernetesResourceAuditLogs"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	podGVK     = schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+	serviceGVK = schema.GroupVersionKind{Version: "v1", Kind: "Service"}
+)
+
+type PortForwardService interface {
+	CreateSession(ctx context.Context, request *bean.PortForwardSessionRequest) (*bean.PortForwardSessionResponse, error)
+	// ValidateSession checks that the session is alive and owned by the user, to be called before upgrading the connection
+	ValidateSession(sessionId string, userId int32) error
+	// Connect tunnels the websocket connection to the target port until either side closes or the session ends
+	Connect(sessionId string, conn *websocket.Conn) error
+	CloseSession(sessionId string, userId int32) error
+}
+
+type PortForwardServiceImpl struct {
+	logger                       *zap.SugaredLogger
+	k8sUtil                      *k8s.K8sServiceImpl
+	clusterReadService           read.ClusterReadService
+	argoApplicationConfigService config.ArgoApplicationConfigService
+	k8sResourceHistoryService    kubernetesResourceAuditLogs.K8sResourceHistoryService
+	config                       *bean.PortForwardConfig
+	sessions                     map[string]*portForwardSession
+	sessionsLock                 sync.RWMutex
+	clusterCredentialService     clusterCredentials.ClusterCredentialService
+}
+
+func GetPortForwardConfig() (*bean.PortForwardConfig, error) {
+	config := &bean.PortForwardConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		return nil, err
+	}
+	return config, err
+}
+
+func NewPortForwardServiceImpl(logger *zap.SugaredLogger, k8sUtil *k8s.K8sServiceImpl,
+	clusterReadService read.ClusterReadService,
+	argoApplicationConfigService config.ArgoApplicationConfigService,
+	k8sResourceHistoryService kubernetesResourceAuditLogs.K8sResourceHistoryService,
+	config *bean.PortForwardConfig,
+	clusterCredentialService clusterCredentials.ClusterCredentialService) *PortForwardServiceImpl {
+	impl := &PortForwardServiceImpl{
+		logger:                       logger,
+		k8sUtil:                      k8sUtil,
+		clusterReadService:           clusterReadService,
+		argoApplicationConfigService: argoApplicationConfigService,
+		k8sResourceHistoryService:    k8sResourceHistoryService,
+		config:                       config,
+		sessions:                     make(map[string]*portForwardSession),
+		clusterCredentialService:     clusterCredentialService,
+	}
+	go impl.closeExpiredSessions()
+	return impl
+}
+
+func (impl *PortForwardServiceImpl) CreateSession(ctx context.Context, request *bean.PortForwardSessionRequest) (*bean.PortForwardSessionResponse, error) {
+	restConfig, clientSet, err := impl.getRestConfigAndClientSet(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	podName, targetPort := request.PodName, request.Port
+	if len(request.ServiceName) > 0 {
+		podName, targetPort, err = impl.getServiceTarget(ctx, clientSet, request)
+	} else {
+		err = impl.validatePod(ctx, clientSet, request.Namespace, request.PodName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sessionId, err := genSessionId()
+	if err != nil {
+		return nil, err
+	}
+	session := newPortForwardSession(sessionId, request, podName, targetPort, restConfig, clientSet)
+	impl.sessionsLock.Lock()
+	impl.sessions[sessionId] = session
+	impl.sessionsLock.Unlock()
+	impl.logger.Infow("port-forward session created", "sessionId", sessionId, "userId", request.UserId, "clusterId", request.ClusterId,
+		"namespace", request.Namespace, "podName", podName, "serviceName", request.ServiceName, "port", request.Port, "targetPort", targetPort)
+	impl.saveHistory(session, bean.PortForwardStartedAction)
+	return &bean.PortForwardSessionResponse{
+		SessionId:       sessionId,
+		Namespace:       request.Namespace,
+		PodName:         podName,
+		ServiceName:     request.ServiceName,
+		Port:            request.Port,
+		TargetPort:      targetPort,
+		ConnectPath:     bean.ConnectPathPrefix + sessionId,
+		IdleTimeoutSecs: impl.config.IdleTimeoutSecs,
+		ExpiresOn:       session.createdOn.Add(impl.config.GetMaxSessionDuration()),
+	}, nil
+}
+
+func (impl *PortForwardServiceImpl) getRestConfigAndClientSet(ctx context.Context, request *bean.PortForwardSessionRequest) (*rest.Config, *kubernetes.Clientset, error) {
+	if len(request.ExternalArgoApplicationName) > 0 {
+		restConfig, err := impl.argoApplicationConfigService.GetRestConfigForExternalArgo(ctx, request.ClusterId, request.ExternalArgoApplicationName)
+		if err != nil {
+			impl.logger.Errorw("error in getting rest config", "clusterId", request.ClusterId, "externalArgoApplicationName", request.ExternalArgoApplicationName, "err", err)
+			return nil, nil, err
+		}
+		_, clientSet, err := impl.k8sUtil.GetK8sConfigAndClientsByRestConfig(restConfig)
+		if err != nil {
+			impl.logger.Errorw("error in clientSet", "err", err)
+			return nil, nil, err
+		}
+		return restConfig, clientSet, nil
+	}
+	clusterBean, err := impl.clusterReadService.FindById(request.ClusterId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching cluster detail", "clusterId", request.ClusterId, "err", err)
+		return nil, nil, err
+	}
+	clusterConfig, err := clusterBean.ResolveClusterConfig(impl.clusterCredentialService)
+	if err != nil {
+		impl.logger.Errorw("error in resolving cluster credentials", "clusterId", request.ClusterId, "err", err)
+		return nil, nil, err
+	}
+	restConfig, err := impl.k8sUtil.GetRestConfigByCluster(clusterConfig)
+	if err != nil {
+		impl.logger.Errorw("error in getting rest config by cluster", "clusterName", clusterConfig.ClusterName, "err", err)
+		return nil, nil, err
+	}
+	_, clientSet, err := impl.k8sUtil.GetK8sConfigAndClientsByRestConfig(restConfig)
+	if err != nil {
+		impl.logger.Errorw("error in clientSet", "err", err)
+		return nil, nil, err
+	}
+	// a rest config with custom transport breaks the spdy client, the tls config is populated back instead
+	clusterConfig.PopulateTlsConfigurationsInto(restConfig)
+	restConfig.Transport = nil
+	return restConfig, clientSet, nil
+}
+
+func (impl *PortForwardServiceImpl) validatePod(ctx context.Context, clientSet kubernetes.Interface, namespace, podName string) error {
+	pod, err := clientSet.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		impl.logger.Errorw("error in fetching pod for port-forward", "namespace", namespace, "podName", podName, "err", err)
+		return err
+	}
+	if pod.Status.Phase != v1.PodRunning {
+		msg := fmt.Sprintf("pod %s is %s, port-forward needs a running pod", podName, pod.Status.Phase)
+		return util.NewApiError(http.StatusBadRequest, msg, msg)
+	}
+	return nil
+}
+
+// getServiceTarget picks a ready pod selected by the service and resolves the service port to its target container port
+func (impl *PortForwardServiceImpl) getServiceTarget(ctx context.Context, clientSet kubernetes.Interface, request *bean.PortForwardSessionRequest) (string, int, error) {
+	service, err := clientSet.CoreV1().Services(request.Namespace).Get(ctx, request.ServiceName, metav1.GetOptions{})
+	if err != nil {
+		impl.logger.Errorw("error in fetching service for port-forward", "namespace", request.Namespace, "serviceName", request.ServiceName, "err", err)
+		return "", 0, err
+	}
+	if len(service.Spec.Selector) == 0 {
+		msg := fmt.Sprintf("service %s has no pod selector", request.ServiceName)
+		return "", 0, util.NewApiError(http.StatusBadRequest, msg, msg)
+	}
+	var servicePort *v1.ServicePort
+	for i := range service.Spec.Ports {
+		if int(service.Spec.Ports[i].Port) == request.Port {
+			servicePort = &service.Spec.Ports[i]
+			break
+		}
+	}
+	if servicePort == nil {
+		msg := fmt.Sprintf("service %s does not expose port %d", request.ServiceName, request.Port)
+		return "", 0, util.NewApiError(http.StatusBadRequest, msg, msg)
+	}
+	pods, err := clientSet.CoreV1().Pods(request.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(service.Spec.Selector).String(),
+	})
+	if err != nil {
+		impl.logger.Errorw("error in listing pods of service", "namespace", request.Namespace, "serviceName", request.ServiceName, "err", err)
+		return "", 0, err
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !isPodReady(pod) {
+			continue
+		}
+		targetPort, ok := getTargetPort(pod, servicePort)
+		if ok {
+			return pod.Name, targetPort, nil
+		}
+	}
+	msg := fmt.Sprintf("no ready pod of service %s serves port %d", request.ServiceName, request.Port)
+	return "", 0, util.NewApiError(http.StatusBadRequest, msg, msg)
+}
+
+func isPodReady(pod *v1.Pod) bool {
+	if pod.Status.Phase != v1.PodRunning || pod.DeletionTimestamp != nil {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// getTargetPort resolves the target port of the service port on the pod, named target ports are looked up in the
+// container ports of the pod
+func getTargetPort(pod *v1.Pod, servicePort *v1.ServicePort) (int, bool) {
+	targetPort := servicePort.TargetPort
+	if targetPort.Type == intstr.Int {
+		if targetPort.IntVal == 0 {
+			// target port defaults to the service port
+			return int(servicePort.Port), true
+		}
+		return int(targetPort.IntVal), true
+	}
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if port.Name == targetPort.StrVal && port.Protocol == servicePort.Protocol {
+				return int(port.ContainerPort), true
+			}
+		}
+	}
+	return 0, false
+}
+
+func (impl *PortForwardServiceImpl) ValidateSession(sessionId string, userId int32) error {
+	_, err := impl.getSession(sessionId, userId)
+	return err
+}
+
+func (impl *PortForwardServiceImpl) getSession(sessionId string, userId int32) (*portForwardSession, error) {
+	impl.sessionsLock.RLock()
+	session, ok := impl.sessions[sessionId]
+	impl.sessionsLock.RUnlock()
+	if !ok {
+		return nil, util.NewApiError(http.StatusNotFound, bean.SessionNotFoundMessage, bean.SessionNotFoundMessage)
+	}
+	if session.request.UserId != userId {
+		return nil, util.NewApiError(http.StatusForbidden, bean.SessionForbiddenMessage, bean.SessionForbiddenMessage)
+	}
+	return session, nil
+}
+
+func (impl *PortForwardServiceImpl) Connect(sessionId string, conn *websocket.Conn) error {
+	impl.sessionsLock.RLock()
+	session, ok := impl.sessions[sessionId]
+	impl.sessionsLock.RUnlock()
+	if !ok {
+		closeWebsocket(conn, websocket.ClosePolicyViolation, bean.SessionNotFoundMessage)
+		return util.NewApiError(http.StatusNotFound, bean.SessionNotFoundMessage, bean.SessionNotFoundMessage)
+	}
+	err := session.tunnel(conn)
+	if err != nil {
+		impl.logger.Errorw("error in port-forward connection", "sessionId", sessionId, "podName", session.podName, "port", session.targetPort, "err", err)
+	}
+	return err
+}
+
+func (impl *PortForwardServiceImpl) CloseSession(sessionId string, userId int32) error {
+	session, err := impl.getSession(sessionId, userId)
+	if err != nil {
+		return err
+	}
+	impl.closeSession(session, "closed by user")
+	return nil
+}
+
+func (impl *PortForwardServiceImpl) closeSession(session *portForwardSession, reason string) {
+	impl.sessionsLock.Lock()
+	delete(impl.sessions, session.id)
+	impl.sessionsLock.Unlock()
+	if session.close() {
+		impl.logger.Infow("port-forward session closed", "sessionId", session.id, "userId", session.request.UserId, "reason", reason)
+		impl.saveHistory(session, bean.PortForwardClosedAction)
+	}
+}
+
+// closeExpiredSessions closes the sessions idle for longer than the idle timeout or past their max duration
+func (impl *PortForwardServiceImpl) closeExpiredSessions() {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		now := time.Now()
+		var expired []*portForwardSession
+		impl.sessionsLock.RLock()
+		for _, session := range impl.sessions {
+			if now.Sub(session.getLastActivity()) > impl.config.GetIdleTimeout() || now.Sub(session.createdOn) > impl.config.GetMaxSessionDuration() {
+				expired = append(expired, session)
+			}
+		}
+		impl.sessionsLock.RUnlock()
+		for _, session := range expired {
+			impl.closeSession(session, "expired")
+		}
+	}
+}
+
+func (impl *PortForwardServiceImpl) saveHistory(session *portForwardSession, actionType string) {
+	gvk, resourceName := podGVK, session.podName
+	if len(session.request.ServiceName) > 0 {
+		gvk, resourceName = serviceGVK, session.request.ServiceName
+	}
+	go func() {
+		err := impl.k8sResourceHistoryService.SaveResourceActionHistory(session.request.ClusterId, session.request.Namespace, gvk, resourceName, session.request.UserId, actionType)
+		if err != nil {
+			impl.logger.Errorw("error in saving port-forward audit", "sessionId", session.id, "actionType", actionType, "err", err)
+		}
+	}()
+}
+
+func genSessionId() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
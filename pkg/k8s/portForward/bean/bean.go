@@ -0,0 +1,70 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+const (
+	// actions audited in the kubernetes resource history
+	PortForwardStartedAction = "port_forward_started"
+	PortForwardClosedAction  = "port_forward_closed"
+
+	// ConnectPathPrefix is the websocket path of a session, the session id is appended to it
+	ConnectPathPrefix = "/orchestrator/k8s/port-forward/connect/"
+
+	SessionNotFoundMessage  = "port-forward session not found or expired"
+	SessionForbiddenMessage = "port-forward session belongs to another user"
+	SessionClosedMessage    = "port-forward session closed"
+)
+
+type PortForwardConfig struct {
+	// IdleTimeoutSecs closes a session without any traffic, connected or not, for this long
+	IdleTimeoutSecs        int `env:"PORT_FORWARD_IDLE_TIMEOUT_SECS" envDefault:"600"`
+	MaxSessionDurationSecs int `env:"PORT_FORWARD_MAX_SESSION_DURATION_SECS" envDefault:"14400"`
+}
+
+func (config *PortForwardConfig) GetIdleTimeout() time.Duration {
+	return time.Duration(config.IdleTimeoutSecs) * time.Second
+}
+
+func (config *PortForwardConfig) GetMaxSessionDuration() time.Duration {
+	return time.Duration(config.MaxSessionDurationSecs) * time.Second
+}
+
+// PortForwardSessionRequest targets either a pod port or a service port, the latter is forwarded to a ready pod
+// backing the service
+type PortForwardSessionRequest struct {
+	ClusterId                   int
+	ExternalArgoApplicationName string
+	Namespace                   string
+	PodName                     string
+	ServiceName                 string
+	Port                        int
+	UserId                      int32
+}
+
+type PortForwardSessionResponse struct {
+	SessionId       string    `json:"sessionId"`
+	Namespace       string    `json:"namespace"`
+	PodName         string    `json:"podName"`
+	ServiceName     string    `json:"serviceName,omitempty"`
+	Port            int       `json:"port"`
+	TargetPort      int       `json:"targetPort"`
+	ConnectPath     string    `json:"connectPath"`
+	IdleTimeoutSecs int       `json:"idleTimeoutSecs"`
+	ExpiresOn       time.Time `json:"expiresOn"`
+}
@@ -0,0 +1,203 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/k8s/portForward/bean"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	sessionPathPrefix = "/orchestrator/k8s/port-forward/session/"
+	tokenHeader       = "token"
+	bufferSize        = 32 * 1024
+)
+
+// SessionTarget is the port to forward to, the identifier is the cluster id or an app id as used for terminal sessions
+type SessionTarget struct {
+	Identifier  string
+	AppType     string
+	Namespace   string
+	PodName     string
+	ServiceName string
+	Port        int
+}
+
+// LocalClient listens on a local address and tunnels each accepted connection over a websocket of a port-forward
+// session, like kubectl port-forward without a kubeconfig
+type LocalClient struct {
+	serverUrl  *url.URL
+	token      string
+	httpClient *http.Client
+	dialer     *websocket.Dialer
+	logger     *zap.SugaredLogger
+}
+
+func NewLocalClient(serverUrl string, token string, logger *zap.SugaredLogger) (*LocalClient, error) {
+	parsedUrl, err := url.Parse(strings.TrimSuffix(serverUrl, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https" {
+		return nil, fmt.Errorf("invalid server url %q, expected http or https scheme", serverUrl)
+	}
+	return &LocalClient{
+		serverUrl:  parsedUrl,
+		token:      token,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+		dialer:     websocket.DefaultDialer,
+		logger:     logger,
+	}, nil
+}
+
+func (client *LocalClient) CreateSession(target *SessionTarget) (*bean.PortForwardSessionResponse, error) {
+	kind, name := "pod", target.PodName
+	if len(target.ServiceName) > 0 {
+		kind, name = "service", target.ServiceName
+	}
+	sessionUrl := *client.serverUrl
+	sessionUrl.Path += sessionPathPrefix + strings.Join([]string{url.PathEscape(target.Identifier), target.Namespace, kind, name, fmt.Sprint(target.Port)}, "/")
+	if len(target.AppType) > 0 {
+		sessionUrl.RawQuery = url.Values{"appType": []string{target.AppType}}.Encode()
+	}
+	req, err := http.NewRequest(http.MethodPost, sessionUrl.String(), bytes.NewReader(nil))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(tokenHeader, client.token)
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	response := &struct {
+		Result *bean.PortForwardSessionResponse `json:"result"`
+		Errors []*util.ApiError                 `json:"errors"`
+	}{}
+	err = json.NewDecoder(resp.Body).Decode(response)
+	if err != nil {
+		return nil, fmt.Errorf("error in decoding port-forward session response, status %d: %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || response.Result == nil {
+		if len(response.Errors) > 0 {
+			return nil, fmt.Errorf("error in creating port-forward session, status %d: %v", resp.StatusCode, response.Errors[0].UserMessage)
+		}
+		return nil, fmt.Errorf("error in creating port-forward session, status %d", resp.StatusCode)
+	}
+	return response.Result, nil
+}
+
+// Forward serves the connections of the listener until the context is done or the session is gone
+func (client *LocalClient) Forward(ctx context.Context, listener net.Listener, connectPath string) error {
+	wsUrl := *client.serverUrl
+	wsUrl.Scheme = strings.Replace(wsUrl.Scheme, "http", "ws", 1)
+	wsUrl.Path += connectPath
+	go func() {
+		<-ctx.Done()
+		_ = listener.Close()
+	}()
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		localConn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer localConn.Close()
+			err := client.tunnel(ctx, wsUrl.String(), localConn)
+			if err != nil {
+				client.logger.Errorw("error in forwarding connection", "remoteAddr", localConn.RemoteAddr().String(), "err", err)
+			}
+		}()
+	}
+}
+
+func (client *LocalClient) tunnel(ctx context.Context, wsUrl string, localConn net.Conn) error {
+	headers := http.Header{}
+	headers.Set(tokenHeader, client.token)
+	wsConn, resp, err := client.dialer.DialContext(ctx, wsUrl, headers)
+	if err != nil {
+		if resp != nil {
+			return fmt.Errorf("error in connecting to port-forward session, status %d: %w", resp.StatusCode, err)
+		}
+		return err
+	}
+	defer wsConn.Close()
+	result := make(chan error, 2)
+	go func() {
+		buf := make([]byte, bufferSize)
+		for {
+			n, err := localConn.Read(buf)
+			if n > 0 {
+				if writeErr := wsConn.WriteMessage(websocket.BinaryMessage, buf[:n]); writeErr != nil {
+					result <- writeErr
+					return
+				}
+			}
+			if errors.Is(err, io.EOF) {
+				_ = wsConn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+				result <- nil
+				return
+			} else if err != nil {
+				result <- err
+				return
+			}
+		}
+	}()
+	go func() {
+		for {
+			_, data, err := wsConn.ReadMessage()
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				result <- nil
+				return
+			} else if err != nil {
+				result <- err
+				return
+			}
+			if _, err = localConn.Write(data); err != nil {
+				result <- err
+				return
+			}
+		}
+	}()
+	select {
+	case err = <-result:
+		return err
+	case <-ctx.Done():
+		return nil
+	}
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/k8s/portForward/bean"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLocalClient(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	mux := http.NewServeMux()
+	mux.HandleFunc(sessionPathPrefix, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(tokenHeader) != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": []*util.ApiError{{UserMessage: "unauthorized"}}})
+			return
+		}
+		assert.Equal(t, sessionPathPrefix+"1/default/service/redis/6379", r.URL.Path)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"result": &bean.PortForwardSessionResponse{
+			SessionId:   "abc",
+			ConnectPath: bean.ConnectPathPrefix + "abc",
+			TargetPort:  6379,
+		}})
+	})
+	// echoes back the tunnelled bytes
+	mux.HandleFunc(bean.ConnectPathPrefix, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get(tokenHeader))
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err = conn.WriteMessage(messageType, data); err != nil {
+				return
+			}
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	target := &SessionTarget{Identifier: "1", Namespace: "default", ServiceName: "redis", Port: 6379}
+
+	t.Run("session creation error is surfaced", func(t *testing.T) {
+		localClient, err := NewLocalClient(server.URL, "wrong", zap.NewNop().Sugar())
+		assert.Nil(t, err)
+		_, err = localClient.CreateSession(target)
+		assert.ErrorContains(t, err, "unauthorized")
+	})
+
+	t.Run("local connections are tunnelled over the session websocket", func(t *testing.T) {
+		localClient, err := NewLocalClient(server.URL, "secret", zap.NewNop().Sugar())
+		assert.Nil(t, err)
+		session, err := localClient.CreateSession(target)
+		assert.Nil(t, err)
+		assert.Equal(t, 6379, session.TargetPort)
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.Nil(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		forwardErr := make(chan error, 1)
+		go func() {
+			forwardErr <- localClient.Forward(ctx, listener, session.ConnectPath)
+		}()
+		for i := 0; i < 2; i++ {
+			conn, err := net.Dial("tcp", listener.Addr().String())
+			assert.Nil(t, err)
+			_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+			_, err = conn.Write([]byte("PING"))
+			assert.Nil(t, err)
+			reply := make([]byte, 4)
+			_, err = io.ReadFull(conn, reply)
+			assert.Nil(t, err)
+			assert.Equal(t, "PING", string(reply))
+			_ = conn.Close()
+		}
+		cancel()
+		assert.Nil(t, <-forwardErr)
+	})
+
+	t.Run("server url scheme is validated", func(t *testing.T) {
+		_, err := NewLocalClient("ftp://devtron", "secret", zap.NewNop().Sugar())
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,199 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package portForward
+
+import (
+	"errors"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/k8s/portForward/bean"
+	"github.com/gorilla/websocket"
+	"io"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	tunnelBufferSize    = 32 * 1024
+	closeMessageTimeout = 5 * time.Second
+)
+
+// portForwardSession authorises any number of connections to the target port, each websocket connection is
+// tunnelled through its own stream pair as kubectl does for each local connection
+type portForwardSession struct {
+	id           string
+	request      *bean.PortForwardSessionRequest
+	podName      string
+	targetPort   int
+	restConfig   *rest.Config
+	clientSet    kubernetes.Interface
+	createdOn    time.Time
+	lastActivity atomic.Int64
+	requestId    atomic.Int32
+	done         chan struct{}
+	closeOnce    sync.Once
+}
+
+func newPortForwardSession(id string, request *bean.PortForwardSessionRequest, podName string, targetPort int,
+	restConfig *rest.Config, clientSet kubernetes.Interface) *portForwardSession {
+	session := &portForwardSession{
+		id:         id,
+		request:    request,
+		podName:    podName,
+		targetPort: targetPort,
+		restConfig: restConfig,
+		clientSet:  clientSet,
+		createdOn:  time.Now(),
+		done:       make(chan struct{}),
+	}
+	session.touch()
+	return session
+}
+
+func (session *portForwardSession) touch() {
+	session.lastActivity.Store(time.Now().UnixNano())
+}
+
+func (session *portForwardSession) getLastActivity() time.Time {
+	return time.Unix(0, session.lastActivity.Load())
+}
+
+// close ends the session along with its connections, reports whether it was open
+func (session *portForwardSession) close() bool {
+	closed := false
+	session.closeOnce.Do(func() {
+		close(session.done)
+		closed = true
+	})
+	return closed
+}
+
+func (session *portForwardSession) dial() (httpstream.Connection, error) {
+	transport, upgrader, err := spdy.RoundTripperFor(session.restConfig)
+	if err != nil {
+		return nil, err
+	}
+	url := session.clientSet.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(session.request.Namespace).
+		Name(session.podName).
+		SubResource("portforward").
+		URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+	streamConn, _, err := dialer.Dial(portforward.PortForwardProtocolV1Name)
+	return streamConn, err
+}
+
+func (session *portForwardSession) tunnel(conn *websocket.Conn) error {
+	defer conn.Close()
+	streamConn, err := session.dial()
+	if err != nil {
+		closeWebsocket(conn, websocket.CloseInternalServerErr, "error in connecting to pod")
+		return err
+	}
+	defer streamConn.Close()
+
+	headers := http.Header{}
+	headers.Set(v1.StreamType, v1.StreamTypeError)
+	headers.Set(v1.PortHeader, strconv.Itoa(session.targetPort))
+	headers.Set(v1.PortForwardRequestIDHeader, strconv.Itoa(int(session.requestId.Add(1))))
+	errorStream, err := streamConn.CreateStream(headers)
+	if err != nil {
+		closeWebsocket(conn, websocket.CloseInternalServerErr, "error in creating error stream")
+		return err
+	}
+	// only read from the error stream
+	_ = errorStream.Close()
+	headers.Set(v1.StreamType, v1.StreamTypeData)
+	dataStream, err := streamConn.CreateStream(headers)
+	if err != nil {
+		closeWebsocket(conn, websocket.CloseInternalServerErr, "error in creating data stream")
+		return err
+	}
+
+	result := make(chan error, 3)
+	go func() {
+		message, err := io.ReadAll(errorStream)
+		if err != nil {
+			result <- err
+		} else if len(message) > 0 {
+			result <- fmt.Errorf("error forwarding port %d to pod %s: %s", session.targetPort, session.podName, string(message))
+		}
+	}()
+	go func() {
+		result <- session.copyToWebsocket(conn, dataStream)
+	}()
+	go func() {
+		result <- session.copyFromWebsocket(conn, dataStream)
+	}()
+	select {
+	case err = <-result:
+		if err != nil {
+			closeWebsocket(conn, websocket.CloseInternalServerErr, err.Error())
+		}
+		return err
+	case <-session.done:
+		closeWebsocket(conn, websocket.CloseGoingAway, bean.SessionClosedMessage)
+		return nil
+	}
+}
+
+func (session *portForwardSession) copyToWebsocket(conn *websocket.Conn, dataStream io.Reader) error {
+	buf := make([]byte, tunnelBufferSize)
+	for {
+		n, err := dataStream.Read(buf)
+		if n > 0 {
+			session.touch()
+			if writeErr := conn.WriteMessage(websocket.BinaryMessage, buf[:n]); writeErr != nil {
+				return writeErr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			closeWebsocket(conn, websocket.CloseNormalClosure, "")
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+func (session *portForwardSession) copyFromWebsocket(conn *websocket.Conn, dataStream io.Writer) error {
+	for {
+		_, data, err := conn.ReadMessage()
+		if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		session.touch()
+		if _, err = dataStream.Write(data); err != nil {
+			return err
+		}
+	}
+}
+
+func closeWebsocket(conn *websocket.Conn, code int, reason string) {
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(closeMessageTimeout))
+}
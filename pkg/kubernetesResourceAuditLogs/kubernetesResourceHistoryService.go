@@ -21,10 +21,13 @@ import (
 	"github.com/devtron-labs/common-lib/utils/k8s"
 	"github.com/devtron-labs/devtron/api/helm-app/service/bean"
 	"github.com/devtron-labs/devtron/internal/sql/repository/app"
+	argoBean "github.com/devtron-labs/devtron/pkg/argoApplication/bean"
 	repository2 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
 	"github.com/devtron-labs/devtron/pkg/kubernetesResourceAuditLogs/repository"
 	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"time"
 )
 
@@ -37,6 +40,9 @@ const (
 type K8sResourceHistoryService interface {
 	SaveArgoCdAppsResourceDeleteHistory(query *application.ApplicationResourceDeleteRequest, appId int, envId int, userId int32) error
 	SaveHelmAppsResourceHistory(appIdentifier *bean.AppIdentifier, k8sRequestBean *k8s.K8sRequestBean, userId int32, actionType string) error
+	SaveExternalArgoAppActionHistory(appIdentifier *argoBean.ArgoAppIdentifier, userId int32, actionType string) error
+	// SaveResourceActionHistory audits an action on a resource of a cluster, the environment is resolved from the namespace
+	SaveResourceActionHistory(clusterId int, namespace string, gvk schema.GroupVersionKind, resourceName string, userId int32, actionType string) error
 }
 
 type K8sResourceHistoryServiceImpl struct {
@@ -113,3 +119,58 @@ func (impl K8sResourceHistoryServiceImpl) SaveHelmAppsResourceHistory(appIdentif
 	return err
 
 }
+
+func (impl K8sResourceHistoryServiceImpl) SaveExternalArgoAppActionHistory(appIdentifier *argoBean.ArgoAppIdentifier, userId int32, actionType string) error {
+	k8sResourceHistory := repository.K8sResourceHistory{
+		AppName:      appIdentifier.AppName,
+		ClusterId:    appIdentifier.ClusterId,
+		Namespace:    appIdentifier.Namespace,
+		ResourceName: appIdentifier.AppName,
+		Kind:         argoBean.ArgoApplicationKind,
+		Group:        argoBean.ArgoGroup,
+		AuditLog: sql.AuditLog{
+			CreatedBy: userId,
+			CreatedOn: time.Now(),
+			UpdatedBy: userId,
+			UpdatedOn: time.Now(),
+		},
+		ActionType:        actionType,
+		DeploymentAppType: GitOps,
+	}
+	err := impl.K8sResourceHistoryRepository.SaveK8sResourceHistory(&k8sResourceHistory)
+	if err != nil {
+		impl.logger.Errorw("error in saving external argo app action history", "appIdentifier", appIdentifier, "actionType", actionType, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl K8sResourceHistoryServiceImpl) SaveResourceActionHistory(clusterId int, namespace string, gvk schema.GroupVersionKind, resourceName string, userId int32, actionType string) error {
+	k8sResourceHistory := repository.K8sResourceHistory{
+		ClusterId:    clusterId,
+		Namespace:    namespace,
+		ResourceName: resourceName,
+		Kind:         gvk.Kind,
+		Group:        gvk.Group,
+		AuditLog: sql.AuditLog{
+			CreatedBy: userId,
+			CreatedOn: time.Now(),
+			UpdatedBy: userId,
+			UpdatedOn: time.Now(),
+		},
+		ActionType: actionType,
+	}
+	env, err := impl.envRepository.FindOneByNamespaceAndClusterId(namespace, clusterId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching environment of resource", "clusterId", clusterId, "namespace", namespace, "err", err)
+		return err
+	} else if err == nil {
+		k8sResourceHistory.EnvId = env.Id
+	}
+	err = impl.K8sResourceHistoryRepository.SaveK8sResourceHistory(&k8sResourceHistory)
+	if err != nil {
+		impl.logger.Errorw("error in saving resource action history", "clusterId", clusterId, "namespace", namespace, "resourceName", resourceName, "actionType", actionType, "err", err)
+		return err
+	}
+	return nil
+}
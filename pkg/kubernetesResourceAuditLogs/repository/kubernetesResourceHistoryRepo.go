@@ -35,6 +35,7 @@ type K8sResourceHistory struct {
 	ForceDelete       bool     `sql:"force_delete, omitempty"`
 	ActionType        string   `sql:"action_type"`
 	DeploymentAppType string   `sql:"deployment_app_type"`
+	ClusterId         int      `sql:"cluster_id"`
 	sql.AuditLog
 }
 
@@ -28,6 +28,7 @@ import (
 	"github.com/devtron-labs/devtron/pkg/build/artifacts/imageTagging"
 	"github.com/devtron-labs/devtron/pkg/cluster/adapter"
 	bean3 "github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterCredentials"
 	repository3 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
 	common2 "github.com/devtron-labs/devtron/pkg/deployment/common"
 	"github.com/devtron-labs/devtron/pkg/pipeline/constants"
@@ -103,6 +104,7 @@ type CdHandlerImpl struct {
 	deploymentConfigService      common2.DeploymentConfigService
 	workflowStageStatusService   workflowStatus.WorkFlowStageStatusService
 	cdWorkflowRunnerService      cd.CdWorkflowRunnerService
+	clusterCredentialService     clusterCredentials.ClusterCredentialService
 }
 
 func NewCdHandlerImpl(Logger *zap.SugaredLogger, userService user.UserService,
@@ -118,6 +120,7 @@ func NewCdHandlerImpl(Logger *zap.SugaredLogger, userService user.UserService,
 	deploymentConfigService common2.DeploymentConfigService,
 	workflowStageStatusService workflowStatus.WorkFlowStageStatusService,
 	cdWorkflowRunnerService cd.CdWorkflowRunnerService,
+	clusterCredentialService clusterCredentials.ClusterCredentialService,
 ) *CdHandlerImpl {
 	cdh := &CdHandlerImpl{
 		Logger:                       Logger,
@@ -140,6 +143,7 @@ func NewCdHandlerImpl(Logger *zap.SugaredLogger, userService user.UserService,
 		deploymentConfigService:      deploymentConfigService,
 		workflowStageStatusService:   workflowStageStatusService,
 		cdWorkflowRunnerService:      cdWorkflowRunnerService,
+		clusterCredentialService:     clusterCredentialService,
 	}
 	config, err := types.GetCdConfig()
 	if err != nil {
@@ -171,7 +175,6 @@ func (impl *CdHandlerImpl) CancelStage(workflowRunnerId int, forceAbort bool, us
 	if env != nil && env.Cluster != nil {
 		clusterBean = adapter.GetClusterBean(*env.Cluster)
 	}
-	clusterConfig := clusterBean.GetClusterConfig()
 	var isExtCluster bool
 	if workflowRunner.WorkflowType == types.PRE {
 		isExtCluster = pipeline.RunPreStageInEnv
@@ -180,6 +183,11 @@ func (impl *CdHandlerImpl) CancelStage(workflowRunnerId int, forceAbort bool, us
 	}
 	var restConfig *rest.Config
 	if isExtCluster {
+		clusterConfig, err := clusterBean.ResolveClusterConfig(impl.clusterCredentialService)
+		if err != nil {
+			impl.Logger.Errorw("error in resolving cluster credentials", "err", err, "clusterId", clusterBean.Id)
+			return 0, err
+		}
 		restConfig, err = impl.k8sUtil.GetRestConfigByCluster(clusterConfig)
 		if err != nil {
 			impl.Logger.Errorw("error in getting rest config by cluster id", "err", err)
@@ -534,7 +542,11 @@ func (impl *CdHandlerImpl) GetRunningWorkflowLogs(environmentId int, pipelineId
 	if env != nil && env.Cluster != nil {
 		clusterBean = adapter.GetClusterBean(*env.Cluster)
 	}
-	clusterConfig := clusterBean.GetClusterConfig()
+	clusterConfig, err := clusterBean.ResolveClusterConfig(impl.clusterCredentialService)
+	if err != nil {
+		impl.Logger.Errorw("error in resolving cluster credentials", "err", err, "clusterId", clusterBean.Id)
+		return nil, nil, err
+	}
 	var isExtCluster bool
 	if cdWorkflow.WorkflowType == types.PRE {
 		isExtCluster = pipeline.RunPreStageInEnv
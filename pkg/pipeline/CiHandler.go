@@ -30,6 +30,7 @@ import (
 	bean4 "github.com/devtron-labs/devtron/pkg/build/pipeline/bean"
 	"github.com/devtron-labs/devtron/pkg/cluster/adapter"
 	bean5 "github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterCredentials"
 	"github.com/devtron-labs/devtron/pkg/cluster/environment"
 	repository2 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
 	constants2 "github.com/devtron-labs/devtron/pkg/pipeline/constants"
@@ -126,6 +127,7 @@ type CiHandlerImpl struct {
 	blobConfigStorageService     BlobStorageConfigService
 	envService                   environment.EnvironmentService
 	workFlowStageStatusService   workflowStatus.WorkFlowStageStatusService
+	clusterCredentialService     clusterCredentials.ClusterCredentialService
 }
 
 func NewCiHandlerImpl(Logger *zap.SugaredLogger, ciService CiService, ciPipelineMaterialRepository pipelineConfig.CiPipelineMaterialRepository, gitSensorClient gitSensor.Client, ciWorkflowRepository pipelineConfig.CiWorkflowRepository, workflowService WorkflowService,
@@ -133,7 +135,8 @@ func NewCiHandlerImpl(Logger *zap.SugaredLogger, ciService CiService, ciPipeline
 	appListingRepository repository.AppListingRepository, K8sUtil *k8s.K8sServiceImpl, cdPipelineRepository pipelineConfig.PipelineRepository, enforcerUtil rbac.EnforcerUtil, resourceGroupService resourceGroup.ResourceGroupService, envRepository repository2.EnvironmentRepository,
 	imageTaggingService imageTagging.ImageTaggingService, k8sCommonService k8s2.K8sCommonService, clusterService cluster.ClusterService, blobConfigStorageService BlobStorageConfigService, appWorkflowRepository appWorkflow.AppWorkflowRepository, customTagService CustomTagService,
 	envService environment.EnvironmentService,
-	workFlowStageStatusService workflowStatus.WorkFlowStageStatusService) *CiHandlerImpl {
+	workFlowStageStatusService workflowStatus.WorkFlowStageStatusService,
+	clusterCredentialService clusterCredentials.ClusterCredentialService) *CiHandlerImpl {
 	cih := &CiHandlerImpl{
 		Logger:                       Logger,
 		ciService:                    ciService,
@@ -161,6 +164,7 @@ func NewCiHandlerImpl(Logger *zap.SugaredLogger, ciService CiService, ciPipeline
 		blobConfigStorageService:     blobConfigStorageService,
 		envService:                   envService,
 		workFlowStageStatusService:   workFlowStageStatusService,
+		clusterCredentialService:     clusterCredentialService,
 	}
 	config, err := types.GetCiConfig()
 	if err != nil {
@@ -727,7 +731,11 @@ func (impl *CiHandlerImpl) getRestConfig(workflow *pipelineConfig.CiWorkflow) (*
 
 	clusterBean := adapter.GetClusterBean(*env.Cluster)
 
-	clusterConfig := clusterBean.GetClusterConfig()
+	clusterConfig, err := clusterBean.ResolveClusterConfig(impl.clusterCredentialService)
+	if err != nil {
+		impl.Logger.Errorw("error in resolving cluster credentials", "err", err, "clusterId", clusterBean.Id)
+		return nil, err
+	}
 	restConfig, err := impl.K8sUtil.GetRestConfigByCluster(clusterConfig)
 	if err != nil {
 		impl.Logger.Errorw("error in getting rest config by cluster id", "err", err)
@@ -867,7 +875,11 @@ func (impl *CiHandlerImpl) getWorkflowLogs(ciWorkflow *pipelineConfig.CiWorkflow
 		if env != nil && env.Cluster != nil {
 			clusterBean = adapter.GetClusterBean(*env.Cluster)
 		}
-		clusterConfig = clusterBean.GetClusterConfig()
+		clusterConfig, err = clusterBean.ResolveClusterConfig(impl.clusterCredentialService)
+		if err != nil {
+			impl.Logger.Errorw("error in resolving cluster credentials", "err", err, "clusterId", clusterBean.Id)
+			return nil, nil, err
+		}
 		isExt = true
 	}
 
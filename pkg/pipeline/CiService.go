@@ -866,7 +866,7 @@ func (impl *CiServiceImpl) buildWfRequestForCiPipeline(pipeline *pipelineConfig.
 
 	if dockerRegistry != nil {
 		workflowRequest.DockerRegistryId = dockerRegistry.Id
-		workflowRequest.DockerRegistryType = string(dockerRegistry.RegistryType)
+		workflowRequest.DockerRegistryType = dockerRegistry.RegistryType.GetClientCompatibleType()
 		workflowRequest.DockerImageTag = dockerImageTag
 		workflowRequest.DockerRegistryURL = dockerRegistry.RegistryURL
 		workflowRequest.DockerRepository = dockerRepository
@@ -18,10 +18,12 @@ package pipeline
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	bean2 "github.com/devtron-labs/devtron/api/helm-app/gRPC"
 	client "github.com/devtron-labs/devtron/api/helm-app/service"
 	"github.com/devtron-labs/devtron/client/argocdServer"
+	"github.com/devtron-labs/devtron/pkg/dockerRegistry/registryProvider"
 	"github.com/devtron-labs/devtron/pkg/pipeline/types"
 	"github.com/devtron-labs/devtron/pkg/sql"
 	"github.com/go-pg/pg"
@@ -52,6 +54,10 @@ type DockerRegistryConfig interface {
 	FilterOCIRegistryConfigForSpecificRepoType(ociRegistryConfigList []*repository.OCIRegistryConfig, repositoryType string) *repository.OCIRegistryConfig
 	FilterRegistryBeanListBasedOnStorageTypeAndAction(bean []types.DockerArtifactStoreBean, storageType string, actionTypes ...string) []types.DockerArtifactStoreBean
 	ValidateRegistryStorageType(registryId string, storageType string, storageActions ...string) bool
+	// FetchRepositoriesForAutocomplete lists repositories through the api of harbor and quay registries
+	FetchRepositoriesForAutocomplete(storeId string, search string) ([]string, error)
+	// RotateRobotAccountSecret regenerates the secret of the harbor or quay robot account of the registry and saves it
+	RotateRobotAccountSecret(storeId string, adminToken string, userId int32) (*types.DockerArtifactStoreBean, error)
 }
 
 const (
@@ -98,6 +104,7 @@ func NewDockerArtifactStore(bean *types.DockerArtifactStoreBean, isActive bool,
 		Connection:             bean.Connection,
 		Cert:                   bean.Cert,
 		Active:                 isActive,
+		ImportScanResults:      bean.ImportScanResults,
 		AuditLog:               sql.AuditLog{CreatedBy: createdBy, CreatedOn: createdOn, UpdatedOn: updatedOn, UpdatedBy: updateBy},
 	}
 }
@@ -437,6 +444,7 @@ func (impl DockerRegistryConfigImpl) FetchAllDockerAccounts() ([]types.DockerArt
 			Cert:                   store.Cert,
 			Active:                 store.Active,
 			IsOCICompliantRegistry: store.IsOCICompliantRegistry,
+			ImportScanResults:      store.ImportScanResults,
 		}
 		if store.IsOCICompliantRegistry {
 			impl.PopulateOCIRegistryConfig(&store, &storeBean)
@@ -496,6 +504,7 @@ func (impl DockerRegistryConfigImpl) FetchOneDockerAccount(storeId string) (*typ
 		Cert:                   store.Cert,
 		Active:                 store.Active,
 		IsOCICompliantRegistry: store.IsOCICompliantRegistry,
+		ImportScanResults:      store.ImportScanResults,
 	}
 	if store.IsOCICompliantRegistry {
 		impl.PopulateOCIRegistryConfig(store, storeBean)
@@ -898,7 +907,21 @@ func (impl DockerRegistryConfigImpl) CheckInActiveDockerAccount(storeId string)
 
 const ociRegistryInvalidCredsMsg = "Invalid authentication credentials. Please verify."
 
+const importScanResultsNotSupportedMsg = "Scan results can only be imported from harbor and quay registries."
+
 func (impl DockerRegistryConfigImpl) ValidateRegistryCredentials(bean *types.DockerArtifactStoreBean) error {
+	if bean.ImportScanResults && !registryProvider.IsProviderRegistryType(bean.RegistryType) {
+		return util.DefaultApiError().
+			WithUserMessage(importScanResultsNotSupportedMsg).
+			WithInternalMessage(importScanResultsNotSupportedMsg).
+			WithHttpStatusCode(http.StatusBadRequest)
+	}
+	if _, err := registryProvider.ParseRobotAccount(bean.RegistryType, bean.Username); err != nil {
+		return util.DefaultApiError().
+			WithUserMessage(err.Error()).
+			WithInternalMessage(err.Error()).
+			WithHttpStatusCode(http.StatusBadRequest)
+	}
 	if bean.IsPublic ||
 		bean.RegistryType == repository.REGISTRYTYPE_GCR ||
 		bean.RegistryType == repository.REGISTRYTYPE_ARTIFACT_REGISTRY ||
@@ -912,7 +935,7 @@ func (impl DockerRegistryConfigImpl) ValidateRegistryCredentials(bean *types.Doc
 		AwsRegion:    bean.AWSRegion,
 		AccessKey:    bean.AWSAccessKeyId,
 		SecretKey:    bean.AWSSecretAccessKey,
-		RegistryType: string(bean.RegistryType),
+		RegistryType: bean.RegistryType.GetClientCompatibleType(),
 		IsPublic:     bean.IsPublic,
 		Connection:   bean.Connection,
 	}
@@ -934,3 +957,86 @@ func (impl DockerRegistryConfigImpl) ValidateRegistryCredentials(bean *types.Doc
 
 	return nil
 }
+
+const repositoryListingNotSupportedMsg = "Repositories can only be listed for harbor and quay registries."
+
+func (impl DockerRegistryConfigImpl) FetchRepositoriesForAutocomplete(storeId string, search string) ([]string, error) {
+	store, err := impl.dockerArtifactStoreRepository.FindOne(storeId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching registry", "storeId", storeId, "err", err)
+		return nil, err
+	}
+	if !registryProvider.IsProviderRegistryType(store.RegistryType) {
+		return nil, util.DefaultApiError().
+			WithUserMessage(repositoryListingNotSupportedMsg).
+			WithInternalMessage(repositoryListingNotSupportedMsg).
+			WithHttpStatusCode(http.StatusBadRequest)
+	}
+	client, err := registryProvider.NewRegistryProviderClient(store, 30*time.Second)
+	if err != nil {
+		impl.logger.Errorw("error in creating registry api client", "storeId", storeId, "err", err)
+		return nil, err
+	}
+	repositories, err := client.ListRepositories(context.Background(), search)
+	if err != nil {
+		impl.logger.Errorw("error in listing repositories of registry", "storeId", storeId, "search", search, "err", err)
+		return nil, util.DefaultApiError().
+			WithUserMessage("error in listing repositories of the registry").
+			WithInternalMessage(err.Error()).
+			WithHttpStatusCode(http.StatusBadGateway)
+	}
+	return repositories, nil
+}
+
+const robotRotationNotSupportedMsg = "Robot account secrets can only be rotated for harbor and quay registries configured with a robot account."
+
+// RotateRobotAccountSecret rotates the secret in the registry first and then goes through Update, so the new secret is
+// validated and reaches the OCI and argocd repository configs like a manual password change
+func (impl DockerRegistryConfigImpl) RotateRobotAccountSecret(storeId string, adminToken string, userId int32) (*types.DockerArtifactStoreBean, error) {
+	store, err := impl.dockerArtifactStoreRepository.FindOne(storeId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching registry", "storeId", storeId, "err", err)
+		return nil, err
+	}
+	if !registryProvider.IsProviderRegistryType(store.RegistryType) {
+		return nil, util.DefaultApiError().
+			WithUserMessage(robotRotationNotSupportedMsg).
+			WithInternalMessage(robotRotationNotSupportedMsg).
+			WithHttpStatusCode(http.StatusBadRequest)
+	}
+	client, err := registryProvider.NewRegistryProviderClient(store, 30*time.Second)
+	if err != nil {
+		impl.logger.Errorw("error in creating registry api client", "storeId", storeId, "err", err)
+		return nil, err
+	}
+	secret, err := client.RotateRobotSecret(context.Background(), adminToken)
+	if errors.Is(err, registryProvider.ErrNotRobotAccount) {
+		return nil, util.DefaultApiError().
+			WithUserMessage(robotRotationNotSupportedMsg).
+			WithInternalMessage(err.Error()).
+			WithHttpStatusCode(http.StatusBadRequest)
+	} else if err != nil {
+		impl.logger.Errorw("error in rotating robot account secret", "storeId", storeId, "username", store.Username, "err", err)
+		return nil, util.DefaultApiError().
+			WithUserMessage("error in rotating the robot account secret in the registry").
+			WithInternalMessage(err.Error()).
+			WithHttpStatusCode(http.StatusBadGateway)
+	}
+	bean, err := impl.FetchOneDockerAccount(storeId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching registry", "storeId", storeId, "err", err)
+		return nil, err
+	}
+	bean.Password = secret
+	bean.User = userId
+	res, err := impl.Update(bean)
+	if err != nil {
+		// the old secret is already invalid in the registry, only another rotation recovers the registry
+		impl.logger.Errorw("robot account secret rotated in registry but not saved", "storeId", storeId, "username", store.Username, "err", err)
+		return nil, util.DefaultApiError().
+			WithUserMessage("robot account secret was rotated in the registry but could not be saved, rotate it again").
+			WithInternalMessage(err.Error()).
+			WithHttpStatusCode(http.StatusInternalServerError)
+	}
+	return res, nil
+}
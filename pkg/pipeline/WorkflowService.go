@@ -25,10 +25,11 @@ import (
 	"github.com/argoproj/argo-workflows/v3/workflow/util"
 	"github.com/devtron-labs/common-lib/utils"
 	"github.com/devtron-labs/common-lib/utils/k8s"
-	"github.com/devtron-labs/common-lib/utils/k8s/commonBean"
 	"github.com/devtron-labs/devtron/api/bean"
 	"github.com/devtron-labs/devtron/internal/sql/repository/pipelineConfig/bean/workflow/cdWorkflow"
 	bean2 "github.com/devtron-labs/devtron/pkg/build/pipeline/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/adapter"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterCredentials"
 	repository2 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
 	"github.com/devtron-labs/devtron/pkg/config/read"
 	v1 "github.com/devtron-labs/devtron/pkg/infraConfig/bean/v1"
@@ -61,17 +62,18 @@ type WorkflowService interface {
 }
 
 type WorkflowServiceImpl struct {
-	Logger                 *zap.SugaredLogger
-	config                 *rest.Config
-	ciCdConfig             *types.CiCdConfig
-	configMapService       read.ConfigReadService
-	envRepository          repository2.EnvironmentRepository
-	globalCMCSService      GlobalCMCSService
-	argoWorkflowExecutor   executors.ArgoWorkflowExecutor
-	systemWorkflowExecutor executors.SystemWorkflowExecutor
-	k8sUtil                *k8s.K8sServiceImpl
-	k8sCommonService       k8s2.K8sCommonService
-	infraProvider          infraProviders.InfraProvider
+	Logger                   *zap.SugaredLogger
+	config                   *rest.Config
+	ciCdConfig               *types.CiCdConfig
+	configMapService         read.ConfigReadService
+	envRepository            repository2.EnvironmentRepository
+	globalCMCSService        GlobalCMCSService
+	argoWorkflowExecutor     executors.ArgoWorkflowExecutor
+	systemWorkflowExecutor   executors.SystemWorkflowExecutor
+	k8sUtil                  *k8s.K8sServiceImpl
+	k8sCommonService         k8s2.K8sCommonService
+	infraProvider            infraProviders.InfraProvider
+	clusterCredentialService clusterCredentials.ClusterCredentialService
 }
 
 // TODO: Move to bean
@@ -85,18 +87,20 @@ func NewWorkflowServiceImpl(Logger *zap.SugaredLogger,
 	k8sUtil *k8s.K8sServiceImpl,
 	systemWorkflowExecutor executors.SystemWorkflowExecutor,
 	k8sCommonService k8s2.K8sCommonService,
-	infraProvider infraProviders.InfraProvider) (*WorkflowServiceImpl, error) {
+	infraProvider infraProviders.InfraProvider,
+	clusterCredentialService clusterCredentials.ClusterCredentialService) (*WorkflowServiceImpl, error) {
 	commonWorkflowService := &WorkflowServiceImpl{
-		Logger:                 Logger,
-		ciCdConfig:             ciCdConfig,
-		configMapService:       configMapService,
-		envRepository:          envRepository,
-		globalCMCSService:      globalCMCSService,
-		argoWorkflowExecutor:   argoWorkflowExecutor,
-		k8sUtil:                k8sUtil,
-		systemWorkflowExecutor: systemWorkflowExecutor,
-		k8sCommonService:       k8sCommonService,
-		infraProvider:          infraProvider,
+		Logger:                   Logger,
+		ciCdConfig:               ciCdConfig,
+		configMapService:         configMapService,
+		envRepository:            envRepository,
+		globalCMCSService:        globalCMCSService,
+		argoWorkflowExecutor:     argoWorkflowExecutor,
+		k8sUtil:                  k8sUtil,
+		systemWorkflowExecutor:   systemWorkflowExecutor,
+		k8sCommonService:         k8sCommonService,
+		infraProvider:            infraProvider,
+		clusterCredentialService: clusterCredentialService,
 	}
 	restConfig, err := k8sUtil.GetK8sInClusterRestConfig()
 	if err != nil {
@@ -227,19 +231,12 @@ func (impl *WorkflowServiceImpl) shouldAddExistingCmCsInWorkflow(workflowRequest
 func (impl *WorkflowServiceImpl) getClusterConfig(workflowRequest *types.WorkflowRequest) (*rest.Config, error) {
 	env := workflowRequest.Env
 	if workflowRequest.IsExtRun {
-		configMap := env.Cluster.Config
-		bearerToken := configMap[commonBean.BearerToken]
-		clusterConfig := &k8s.ClusterConfig{
-			ClusterName:           env.Cluster.ClusterName,
-			BearerToken:           bearerToken,
-			Host:                  env.Cluster.ServerUrl,
-			InsecureSkipTLSVerify: env.Cluster.InsecureSkipTlsVerify,
-		}
-		if !env.Cluster.InsecureSkipTlsVerify {
-			clusterConfig.KeyData = configMap[commonBean.TlsKey]
-			clusterConfig.CertData = configMap[commonBean.CertData]
-			clusterConfig.CAData = configMap[commonBean.CertificateAuthorityData]
+		clusterConfig, err := adapter.GetClusterBean(*env.Cluster).ResolveClusterConfig(impl.clusterCredentialService)
+		if err != nil {
+			impl.Logger.Errorw("error in resolving cluster credentials", "err", err, "clusterId", env.Cluster.Id)
+			return nil, err
 		}
+		clusterConfig.ClusterName = env.Cluster.ClusterName
 		restConfig, err := impl.k8sUtil.GetRestConfigByCluster(clusterConfig)
 		if err != nil {
 			impl.Logger.Errorw("error in getting rest config from cluster config", "err", err, "appId", workflowRequest.AppId)
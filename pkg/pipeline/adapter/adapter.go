@@ -54,7 +54,7 @@ func UpdateRegistryDetailsToWrfReq(cdStageWorkflowRequest *types.WorkflowRequest
 	cdStageWorkflowRequest.DockerCert = dockerRegistry.Cert
 	cdStageWorkflowRequest.AccessKey = dockerRegistry.AWSAccessKeyId
 	cdStageWorkflowRequest.SecretKey = dockerRegistry.AWSSecretAccessKey
-	cdStageWorkflowRequest.DockerRegistryType = string(dockerRegistry.RegistryType)
+	cdStageWorkflowRequest.DockerRegistryType = dockerRegistry.RegistryType.GetClientCompatibleType()
 	cdStageWorkflowRequest.DockerRegistryURL = dockerRegistry.RegistryURL
 	cdStageWorkflowRequest.DockerRegistryId = dockerRegistry.Id
 }
@@ -172,7 +172,7 @@ func (impl *PluginInputVariableParserImpl) getPluginRegistryCredentialsByRegistr
 		return nil, err
 	}
 	return &bean2.RegistryCredentials{
-		RegistryType:       string(registryCredentials.RegistryType),
+		RegistryType:       registryCredentials.RegistryType.GetClientCompatibleType(),
 		RegistryURL:        registryCredentials.RegistryURL,
 		Username:           registryCredentials.Username,
 		Password:           registryCredentials.Password,
@@ -38,6 +38,7 @@ type DockerArtifactStoreBean struct {
 	Connection              string                       `json:"connection"`
 	Cert                    string                       `json:"cert"`
 	Active                  bool                         `json:"active"`
+	ImportScanResults       bool                         `json:"importScanResults"`
 	DisabledFields          []DisabledFields             `json:"disabledFields"`
 	User                    int32                        `json:"-"`
 	DockerRegistryIpsConfig *DockerRegistryIpsConfigBean `json:"ipsConfig,omitempty"`
@@ -52,4 +53,9 @@ type DockerRegistryIpsConfigBean struct {
 	Active               bool                                       `json:"active,omitempty"`
 }
 
+// RobotSecretRotationRequest carries the oauth token of a namespace admin, only quay needs it to regenerate robot tokens
+type RobotSecretRotationRequest struct {
+	AdminToken string `json:"adminToken,omitempty"`
+}
+
 type DisabledFields string
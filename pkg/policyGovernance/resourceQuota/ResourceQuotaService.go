@@ -0,0 +1,511 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resourceQuota
+
+import (
+	"context"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/models"
+	"github.com/devtron-labs/devtron/internal/util"
+	repository2 "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/generateManifest"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/resourceQuota/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/resourceQuota/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	teamRepository "github.com/devtron-labs/devtron/pkg/team/repository"
+	"github.com/go-pg/pg"
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// violationReportWindow is how far back the consumption report lists budget violations
+	violationReportWindow       = 7 * 24 * time.Hour
+	maxReportedViolationsPerEnv = 20
+)
+
+type ResourceQuotaService interface {
+	GetAllBudgets() ([]*bean.ResourceBudgetDto, error)
+	// SaveBudget creates the budget of the scope or replaces the limits of the existing one
+	SaveBudget(request *bean.ResourceBudgetDto) (*bean.ResourceBudgetDto, error)
+	DeleteBudget(id int, userId int32) error
+	// GetConsumptionReport sums the resources claimed by the deployed templates of the environment, of all
+	// environments when environmentId is 0, against their budgets
+	GetConsumptionReport(environmentId int) (*bean.ConsumptionReportDto, error)
+	// CheckDeploymentBudget evaluates the rendered manifest of a deployment against the budgets of its environment and
+	// team, an error is returned when a budget in block mode would be exceeded
+	CheckDeploymentBudget(ctx context.Context, request *bean.DeploymentBudgetRequest) error
+}
+
+type ResourceQuotaServiceImpl struct {
+	logger                    *zap.SugaredLogger
+	resourceQuotaRepository   repository.ResourceQuotaRepository
+	environmentRepository     repository2.EnvironmentRepository
+	teamRepository            teamRepository.TeamRepository
+	deploymentTemplateService generateManifest.DeploymentTemplateService
+}
+
+func NewResourceQuotaServiceImpl(logger *zap.SugaredLogger,
+	resourceQuotaRepository repository.ResourceQuotaRepository,
+	environmentRepository repository2.EnvironmentRepository,
+	teamRepository teamRepository.TeamRepository,
+	deploymentTemplateService generateManifest.DeploymentTemplateService) *ResourceQuotaServiceImpl {
+	return &ResourceQuotaServiceImpl{
+		logger:                    logger,
+		resourceQuotaRepository:   resourceQuotaRepository,
+		environmentRepository:     environmentRepository,
+		teamRepository:            teamRepository,
+		deploymentTemplateService: deploymentTemplateService,
+	}
+}
+
+func (impl *ResourceQuotaServiceImpl) GetAllBudgets() ([]*bean.ResourceBudgetDto, error) {
+	budgets, err := impl.resourceQuotaRepository.FindAllActiveBudgets()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching resource budgets", "err", err)
+		return nil, err
+	}
+	resp := make([]*bean.ResourceBudgetDto, 0, len(budgets))
+	for _, budget := range budgets {
+		resp = append(resp, toBudgetDto(budget))
+	}
+	return resp, nil
+}
+
+func (impl *ResourceQuotaServiceImpl) SaveBudget(request *bean.ResourceBudgetDto) (*bean.ResourceBudgetDto, error) {
+	err := impl.validateBudget(request)
+	if err != nil {
+		return nil, err
+	}
+	budget, err := impl.resourceQuotaRepository.FindActiveBudgetByScope(request.EnvironmentId, request.TeamId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching resource budget of scope", "environmentId", request.EnvironmentId, "teamId", request.TeamId, "err", err)
+		return nil, err
+	}
+	isNew := err == pg.ErrNoRows
+	if isNew {
+		budget = &repository.ResourceBudget{
+			EnvironmentId: request.EnvironmentId,
+			TeamId:        request.TeamId,
+			Active:        true,
+			AuditLog:      sql.NewDefaultAuditLog(request.UserId),
+		}
+	} else {
+		budget.UpdateAuditLog(request.UserId)
+	}
+	budget.Cpu = request.Cpu
+	budget.Memory = request.Memory
+	budget.Replicas = request.Replicas
+	budget.Storage = request.Storage
+	budget.Mode = string(request.Mode)
+	if isNew {
+		err = impl.resourceQuotaRepository.SaveBudget(budget)
+	} else {
+		err = impl.resourceQuotaRepository.UpdateBudget(budget)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in saving resource budget", "budget", budget, "err", err)
+		return nil, err
+	}
+	return toBudgetDto(budget), nil
+}
+
+func (impl *ResourceQuotaServiceImpl) DeleteBudget(id int, userId int32) error {
+	budget, err := impl.resourceQuotaRepository.FindActiveBudgetById(id)
+	if err == pg.ErrNoRows {
+		errMsg := fmt.Sprintf(bean.BudgetNotFound, id)
+		return util.NewApiError(http.StatusNotFound, errMsg, errMsg)
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching resource budget", "id", id, "err", err)
+		return err
+	}
+	budget.Active = false
+	budget.UpdateAuditLog(userId)
+	err = impl.resourceQuotaRepository.UpdateBudget(budget)
+	if err != nil {
+		impl.logger.Errorw("error in deleting resource budget", "id", id, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *ResourceQuotaServiceImpl) GetConsumptionReport(environmentId int) (*bean.ConsumptionReportDto, error) {
+	var environments []*repository2.Environment
+	var environmentIds []int
+	if environmentId > 0 {
+		environment, err := impl.environmentRepository.FindById(environmentId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching environment", "environmentId", environmentId, "err", err)
+			return nil, err
+		}
+		environments = append(environments, environment)
+		environmentIds = append(environmentIds, environmentId)
+	} else {
+		var err error
+		environments, err = impl.environmentRepository.FindAllActive()
+		if err != nil && err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching environments", "err", err)
+			return nil, err
+		}
+	}
+	templates, err := impl.resourceQuotaRepository.FindDeployedTemplates(environmentIds, 0)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching deployed templates", "environmentIds", environmentIds, "err", err)
+		return nil, err
+	}
+	budgets, err := impl.resourceQuotaRepository.FindAllActiveBudgets()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching resource budgets", "err", err)
+		return nil, err
+	}
+	reportEnvironmentIds := make([]int, 0, len(environments))
+	envReports := make(map[int]*bean.EnvironmentConsumptionDto, len(environments))
+	envUsages := make(map[int]*resourceUsage, len(environments))
+	envTeamUsages := make(map[int]map[int]*resourceUsage, len(environments))
+	report := &bean.ConsumptionReportDto{
+		Environments: make([]*bean.EnvironmentConsumptionDto, 0, len(environments)),
+		TeamBudgets:  make([]*bean.BudgetHeadroomDto, 0),
+	}
+	for _, environment := range environments {
+		envReport := &bean.EnvironmentConsumptionDto{
+			EnvironmentId:    environment.Id,
+			EnvironmentName:  environment.Name,
+			Apps:             make([]*bean.AppUsageDto, 0),
+			Budgets:          make([]*bean.BudgetHeadroomDto, 0),
+			RecentViolations: make([]*bean.BudgetViolationDto, 0),
+		}
+		report.Environments = append(report.Environments, envReport)
+		reportEnvironmentIds = append(reportEnvironmentIds, environment.Id)
+		envReports[environment.Id] = envReport
+		envUsages[environment.Id] = &resourceUsage{}
+		envTeamUsages[environment.Id] = make(map[int]*resourceUsage)
+	}
+	for _, template := range templates {
+		envReport, ok := envReports[template.EnvironmentId]
+		if !ok {
+			continue
+		}
+		usage := impl.getTemplateUsage(template)
+		envUsages[template.EnvironmentId].add(usage)
+		if _, ok = envTeamUsages[template.EnvironmentId][template.TeamId]; !ok {
+			envTeamUsages[template.EnvironmentId][template.TeamId] = &resourceUsage{}
+		}
+		envTeamUsages[template.EnvironmentId][template.TeamId].add(usage)
+		envReport.Apps = append(envReport.Apps, &bean.AppUsageDto{
+			AppId:   template.AppId,
+			AppName: template.AppName,
+			TeamId:  template.TeamId,
+			Usage:   usage.toDto(),
+		})
+	}
+	for envId, envReport := range envReports {
+		envReport.Usage = envUsages[envId].toDto()
+	}
+	for _, budget := range budgets {
+		if budget.EnvironmentId == 0 {
+			headroom, err := impl.getTeamBudgetHeadroom(budget)
+			if err != nil {
+				return nil, err
+			}
+			report.TeamBudgets = append(report.TeamBudgets, headroom)
+			continue
+		}
+		envReport, ok := envReports[budget.EnvironmentId]
+		if !ok {
+			continue
+		}
+		used := envUsages[budget.EnvironmentId]
+		if budget.TeamId > 0 {
+			used = envTeamUsages[budget.EnvironmentId][budget.TeamId]
+			if used == nil {
+				used = &resourceUsage{}
+			}
+		}
+		headroom, err := getBudgetHeadroom(budget, used)
+		if err != nil {
+			impl.logger.Errorw("error in computing headroom of resource budget", "budgetId", budget.Id, "err", err)
+			return nil, err
+		}
+		envReport.Budgets = append(envReport.Budgets, headroom)
+	}
+	violations, err := impl.resourceQuotaRepository.FindViolationsSince(reportEnvironmentIds, time.Now().Add(-violationReportWindow))
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching resource budget violations", "err", err)
+		return nil, err
+	}
+	for _, violation := range violations {
+		envReport := envReports[violation.EnvironmentId]
+		if envReport == nil || len(envReport.RecentViolations) >= maxReportedViolationsPerEnv {
+			continue
+		}
+		envReport.RecentViolations = append(envReport.RecentViolations, &bean.BudgetViolationDto{
+			BudgetId:      violation.ResourceBudgetId,
+			AppId:         violation.AppId,
+			EnvironmentId: violation.EnvironmentId,
+			PipelineId:    violation.PipelineId,
+			Mode:          bean.BudgetMode(violation.Mode),
+			Message:       violation.Message,
+			TriggeredBy:   violation.CreatedBy,
+			TriggeredOn:   violation.CreatedOn,
+		})
+	}
+	return report, nil
+}
+
+func (impl *ResourceQuotaServiceImpl) CheckDeploymentBudget(ctx context.Context, request *bean.DeploymentBudgetRequest) error {
+	_, span := otel.Tracer("orchestrator").Start(ctx, "ResourceQuotaServiceImpl.CheckDeploymentBudget")
+	defer span.End()
+	budgets, err := impl.resourceQuotaRepository.FindApplicableBudgets(request.EnvironmentId, request.TeamId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching resource budgets of deployment", "environmentId", request.EnvironmentId, "teamId", request.TeamId, "err", err)
+		return err
+	}
+	if len(budgets) == 0 {
+		return nil
+	}
+	requested, measured, err := impl.getRequestedUsage(ctx, request)
+	if err != nil {
+		impl.logger.Errorw("error in reading resources of deployment template", "pipelineId", request.PipelineId, "err", err)
+		return util.DefaultApiError().
+			WithHttpStatusCode(http.StatusPreconditionFailed).
+			WithInternalMessage(err.Error()).
+			WithUserMessage(err.Error())
+	}
+	var blockingMessages []string
+	for _, budget := range budgets {
+		limits, err := getBudgetLimits(budget)
+		if err != nil {
+			impl.logger.Errorw("error in parsing limits of resource budget", "budgetId", budget.Id, "err", err)
+			return err
+		}
+		used, err := impl.getUsageExcludingPipeline(budget, request.PipelineId)
+		if err != nil {
+			return err
+		}
+		exceeded := getExceededResources(limits, used, requested)
+		if len(exceeded) == 0 {
+			continue
+		}
+		message := fmt.Sprintf(bean.BudgetExceeded, impl.getBudgetScopeName(budget), strings.Join(exceeded, ", "))
+		impl.saveViolation(budget, request, message)
+		if bean.BudgetMode(budget.Mode) == bean.BudgetModeBlock {
+			blockingMessages = append(blockingMessages, message)
+		} else {
+			impl.logger.Warnw("deployment exceeds resource budget", "budgetId", budget.Id, "pipelineId", request.PipelineId, "message", message)
+		}
+	}
+	if len(blockingMessages) > 0 {
+		message := strings.Join(blockingMessages, "; ")
+		return util.DefaultApiError().
+			WithHttpStatusCode(http.StatusPreconditionFailed).
+			WithInternalMessage(message).
+			WithUserMessage(message)
+	}
+	if measured {
+		impl.saveUsage(request, requested)
+	}
+	return nil
+}
+
+// getRequestedUsage measures the rendered manifest of the deployment, when the chart can not be rendered the claim is
+// estimated from the merged template which only knows the reference charts
+func (impl *ResourceQuotaServiceImpl) getRequestedUsage(ctx context.Context, request *bean.DeploymentBudgetRequest) (*resourceUsage, bool, error) {
+	manifest, err := impl.deploymentTemplateService.GenerateManifest(ctx, &generateManifest.DeploymentTemplateRequest{
+		AppId:             request.AppId,
+		EnvId:             request.EnvironmentId,
+		AppName:           request.AppName,
+		EnvName:           request.EnvironmentName,
+		Namespace:         request.Namespace,
+		DeploymentAppName: request.DeploymentAppName,
+		ChartRefId:        request.ChartRefId,
+		PipelineId:        request.PipelineId,
+	}, string(request.MergedValues))
+	if err == nil && manifest.Manifest != nil {
+		usage, err := getManifestUsage(*manifest.Manifest)
+		if err == nil {
+			return usage, true, nil
+		}
+		impl.logger.Warnw("error in reading resources of rendered manifest, estimating from template", "pipelineId", request.PipelineId, "err", err)
+	} else if err != nil {
+		impl.logger.Warnw("error in rendering manifest of deployment, estimating from template", "pipelineId", request.PipelineId, "err", err)
+	}
+	usage, err := getValuesUsage(request.MergedValues)
+	return usage, false, err
+}
+
+// saveUsage keeps the measured claim of the deployment for the budget checks of later deployments, without it they
+// fall back to the estimate from the merged template
+func (impl *ResourceQuotaServiceImpl) saveUsage(request *bean.DeploymentBudgetRequest, usage *resourceUsage) {
+	if request.PipelineOverrideId == 0 {
+		return
+	}
+	err := impl.resourceQuotaRepository.SaveUsage(&repository.ResourceBudgetUsage{
+		PipelineOverrideId: request.PipelineOverrideId,
+		Cpu:                usage.cpu,
+		Memory:             usage.memory,
+		Replicas:           usage.replicas,
+		Storage:            usage.storage,
+		AuditLog:           sql.NewDefaultAuditLog(request.UserId),
+	})
+	if err != nil {
+		impl.logger.Errorw("error in saving resource usage of deployment", "pipelineOverrideId", request.PipelineOverrideId, "err", err)
+	}
+}
+
+// getUsageExcludingPipeline sums the deployed templates in the scope of the budget, leaving out the pipeline being
+// deployed as its new template replaces the deployed one
+func (impl *ResourceQuotaServiceImpl) getUsageExcludingPipeline(budget *repository.ResourceBudget, pipelineId int) (*resourceUsage, error) {
+	var environmentIds []int
+	if budget.EnvironmentId > 0 {
+		environmentIds = append(environmentIds, budget.EnvironmentId)
+	}
+	templates, err := impl.resourceQuotaRepository.FindDeployedTemplates(environmentIds, budget.TeamId)
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching deployed templates of resource budget", "budgetId", budget.Id, "err", err)
+		return nil, err
+	}
+	used := &resourceUsage{}
+	for _, template := range templates {
+		if template.PipelineId == pipelineId {
+			continue
+		}
+		used.add(impl.getTemplateUsage(template))
+	}
+	return used, nil
+}
+
+func (impl *ResourceQuotaServiceImpl) getTeamBudgetHeadroom(budget *repository.ResourceBudget) (*bean.BudgetHeadroomDto, error) {
+	used, err := impl.getUsageExcludingPipeline(budget, 0)
+	if err != nil {
+		return nil, err
+	}
+	headroom, err := getBudgetHeadroom(budget, used)
+	if err != nil {
+		impl.logger.Errorw("error in computing headroom of resource budget", "budgetId", budget.Id, "err", err)
+		return nil, err
+	}
+	return headroom, nil
+}
+
+// getTemplateUsage reads the claim of a deployed template, stopped deployments and templates that can not be read claim
+// nothing. Deployments measured at trigger time use their rendered manifest, older ones are estimated from the template
+func (impl *ResourceQuotaServiceImpl) getTemplateUsage(template *repository.DeployedTemplate) *resourceUsage {
+	if template.DeploymentType == models.DEPLOYMENTTYPE_STOP {
+		return &resourceUsage{}
+	}
+	if template.UsageMeasured {
+		return &resourceUsage{cpu: template.Cpu, memory: template.Memory, replicas: template.Replicas, storage: template.Storage}
+	}
+	usage, err := getValuesUsage([]byte(template.MergedValues))
+	if err != nil {
+		impl.logger.Warnw("skipping deployed template with unreadable resources", "pipelineId", template.PipelineId, "err", err)
+		return &resourceUsage{}
+	}
+	return usage
+}
+
+func (impl *ResourceQuotaServiceImpl) saveViolation(budget *repository.ResourceBudget, request *bean.DeploymentBudgetRequest, message string) {
+	violation := &repository.ResourceBudgetViolation{
+		ResourceBudgetId: budget.Id,
+		AppId:            request.AppId,
+		EnvironmentId:    request.EnvironmentId,
+		PipelineId:       request.PipelineId,
+		Mode:             budget.Mode,
+		Message:          message,
+		AuditLog:         sql.NewDefaultAuditLog(request.UserId),
+	}
+	err := impl.resourceQuotaRepository.SaveViolation(violation)
+	if err != nil {
+		// recording the violation is not blocking for the deployment
+		impl.logger.Errorw("error in saving resource budget violation", "budgetId", budget.Id, "pipelineId", request.PipelineId, "err", err)
+	}
+}
+
+func (impl *ResourceQuotaServiceImpl) getBudgetScopeName(budget *repository.ResourceBudget) string {
+	var scopes []string
+	if budget.TeamId > 0 {
+		teamName := fmt.Sprintf("%d", budget.TeamId)
+		if team, err := impl.teamRepository.FindOne(budget.TeamId); err == nil {
+			teamName = team.Name
+		}
+		scopes = append(scopes, fmt.Sprintf("project %s", teamName))
+	}
+	if budget.EnvironmentId > 0 {
+		envName := fmt.Sprintf("%d", budget.EnvironmentId)
+		if environment, err := impl.environmentRepository.FindById(budget.EnvironmentId); err == nil {
+			envName = environment.Name
+		}
+		scopes = append(scopes, fmt.Sprintf("environment %s", envName))
+	}
+	return strings.Join(scopes, " in ")
+}
+
+func (impl *ResourceQuotaServiceImpl) validateBudget(request *bean.ResourceBudgetDto) error {
+	if request.EnvironmentId == 0 && request.TeamId == 0 {
+		return util.NewApiError(http.StatusBadRequest, bean.BudgetScopeRequired, bean.BudgetScopeRequired)
+	}
+	if len(request.Cpu) == 0 && len(request.Memory) == 0 && request.Replicas == 0 && len(request.Storage) == 0 {
+		return util.NewApiError(http.StatusBadRequest, bean.BudgetLimitRequired, bean.BudgetLimitRequired)
+	}
+	_, err := getBudgetLimits(&repository.ResourceBudget{Cpu: request.Cpu, Memory: request.Memory, Storage: request.Storage})
+	if err != nil {
+		return util.NewApiError(http.StatusBadRequest, err.Error(), err.Error())
+	}
+	if request.EnvironmentId > 0 {
+		_, err = impl.environmentRepository.FindById(request.EnvironmentId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching environment of resource budget", "environmentId", request.EnvironmentId, "err", err)
+			return util.NewApiError(http.StatusBadRequest, "environment not found", err.Error())
+		}
+	}
+	if request.TeamId > 0 {
+		_, err = impl.teamRepository.FindOne(request.TeamId)
+		if err != nil {
+			impl.logger.Errorw("error in fetching team of resource budget", "teamId", request.TeamId, "err", err)
+			return util.NewApiError(http.StatusBadRequest, "project not found", err.Error())
+		}
+	}
+	return nil
+}
+
+func getBudgetHeadroom(budget *repository.ResourceBudget, used *resourceUsage) (*bean.BudgetHeadroomDto, error) {
+	limits, err := getBudgetLimits(budget)
+	if err != nil {
+		return nil, err
+	}
+	return &bean.BudgetHeadroomDto{
+		Budget:   toBudgetDto(budget),
+		Used:     used.toDto(),
+		Headroom: getHeadroom(limits, used),
+	}, nil
+}
+
+func toBudgetDto(budget *repository.ResourceBudget) *bean.ResourceBudgetDto {
+	return &bean.ResourceBudgetDto{
+		Id:            budget.Id,
+		EnvironmentId: budget.EnvironmentId,
+		TeamId:        budget.TeamId,
+		Cpu:           budget.Cpu,
+		Memory:        budget.Memory,
+		Replicas:      budget.Replicas,
+		Storage:       budget.Storage,
+		Mode:          bean.BudgetMode(budget.Mode),
+	}
+}
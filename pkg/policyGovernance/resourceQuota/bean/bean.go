@@ -0,0 +1,124 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+type BudgetMode string
+
+const (
+	// BudgetModeBlock fails the deployment trigger when the budget would be exceeded
+	BudgetModeBlock BudgetMode = "block"
+	// BudgetModeWarn lets the deployment through and records the violation
+	BudgetModeWarn BudgetMode = "warn"
+)
+
+const (
+	BudgetNotFound       = "resource budget %d not found"
+	BudgetScopeRequired  = "either environmentId or teamId is required for a resource budget"
+	BudgetLimitRequired  = "at least one of cpu, memory, replicas or storage should be limited"
+	InvalidQuantity      = "invalid %s quantity %q: %s"
+	BudgetExceeded       = "deployment exceeds resource budget of %s: %s"
+	BudgetExceededDetail = "%s %s requested, %s available of %s"
+)
+
+const (
+	ResourceCpu      = "cpu"
+	ResourceMemory   = "memory"
+	ResourceReplicas = "replicas"
+	ResourceStorage  = "storage"
+)
+
+// ResourceBudgetDto limits the resources apps may claim in an environment, in all environments of a team or for a team
+// within one environment. Limits are kubernetes quantities, an empty limit is unbounded
+type ResourceBudgetDto struct {
+	Id            int        `json:"id"`
+	EnvironmentId int        `json:"environmentId,omitempty" validate:"omitempty,number,gt=0"`
+	TeamId        int        `json:"teamId,omitempty" validate:"omitempty,number,gt=0"`
+	Cpu           string     `json:"cpu,omitempty"`
+	Memory        string     `json:"memory,omitempty"`
+	Replicas      int        `json:"replicas,omitempty" validate:"omitempty,number,gte=0"`
+	Storage       string     `json:"storage,omitempty"`
+	Mode          BudgetMode `json:"mode" validate:"required,oneof=block warn"`
+	UserId        int32      `json:"-"`
+}
+
+// ResourceUsageDto is the resource claim of deployments summed over a scope, cpu in cores and sizes in bytes
+type ResourceUsageDto struct {
+	Cpu      string `json:"cpu"`
+	Memory   string `json:"memory"`
+	Replicas int    `json:"replicas"`
+	Storage  string `json:"storage"`
+}
+
+type AppUsageDto struct {
+	AppId   int               `json:"appId"`
+	AppName string            `json:"appName"`
+	TeamId  int               `json:"teamId"`
+	Usage   *ResourceUsageDto `json:"usage"`
+}
+
+type BudgetHeadroomDto struct {
+	Budget *ResourceBudgetDto `json:"budget"`
+	Used   *ResourceUsageDto  `json:"used"`
+	// Headroom is left of each limited resource, unbounded resources are left empty
+	Headroom *ResourceUsageDto `json:"headroom"`
+}
+
+type BudgetViolationDto struct {
+	BudgetId      int        `json:"budgetId"`
+	AppId         int        `json:"appId"`
+	EnvironmentId int        `json:"environmentId"`
+	PipelineId    int        `json:"pipelineId"`
+	Mode          BudgetMode `json:"mode"`
+	Message       string     `json:"message"`
+	TriggeredBy   int32      `json:"triggeredBy"`
+	TriggeredOn   time.Time  `json:"triggeredOn"`
+}
+
+type EnvironmentConsumptionDto struct {
+	EnvironmentId    int                   `json:"environmentId"`
+	EnvironmentName  string                `json:"environmentName"`
+	Usage            *ResourceUsageDto     `json:"usage"`
+	Apps             []*AppUsageDto        `json:"apps"`
+	Budgets          []*BudgetHeadroomDto  `json:"budgets"`
+	RecentViolations []*BudgetViolationDto `json:"recentViolations"`
+}
+
+type ConsumptionReportDto struct {
+	Environments []*EnvironmentConsumptionDto `json:"environments"`
+	// TeamBudgets are the budgets of teams spanning all environments
+	TeamBudgets []*BudgetHeadroomDto `json:"teamBudgets"`
+}
+
+// DeploymentBudgetRequest is the deployment being triggered, MergedValues is the final deployment template
+// DeploymentBudgetRequest carries what is needed to render the chart of the deployment, PipelineOverrideId keys the
+// measured usage
+type DeploymentBudgetRequest struct {
+	AppId              int
+	AppName            string
+	TeamId             int
+	EnvironmentId      int
+	EnvironmentName    string
+	Namespace          string
+	PipelineId         int
+	PipelineOverrideId int
+	DeploymentAppName  string
+	ChartRefId         int
+	MergedValues       []byte
+	UserId             int32
+}
@@ -0,0 +1,415 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resourceQuota
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/resourceQuota/bean"
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/resourceQuota/repository"
+	globalUtil "github.com/devtron-labs/devtron/util"
+	"io"
+	"k8s.io/apimachinery/pkg/api/resource"
+	k8sYaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+	"strings"
+)
+
+// defaultPvcStorage is the size the reference charts request when persistentVolumeClaim.storage is not set
+const defaultPvcStorage = "5Gi"
+
+// resourceUsage is the claim of a deployment, cpu in millicores and sizes in bytes. For budget limits a negative
+// value marks the resource as unbounded
+type resourceUsage struct {
+	cpu      int64
+	memory   int64
+	replicas int64
+	storage  int64
+}
+
+func (usage *resourceUsage) add(other *resourceUsage) {
+	usage.cpu += other.cpu
+	usage.memory += other.memory
+	usage.replicas += other.replicas
+	usage.storage += other.storage
+}
+
+func (usage *resourceUsage) sub(other *resourceUsage) {
+	usage.cpu -= other.cpu
+	usage.memory -= other.memory
+	usage.replicas -= other.replicas
+	usage.storage -= other.storage
+}
+
+func (usage *resourceUsage) toDto() *bean.ResourceUsageDto {
+	return &bean.ResourceUsageDto{
+		Cpu:      formatCpu(usage.cpu),
+		Memory:   formatBytes(usage.memory),
+		Replicas: int(usage.replicas),
+		Storage:  formatBytes(usage.storage),
+	}
+}
+
+func formatCpu(milliCores int64) string {
+	return resource.NewMilliQuantity(milliCores, resource.DecimalSI).String()
+}
+
+func formatBytes(bytes int64) string {
+	return resource.NewQuantity(bytes, resource.BinarySI).String()
+}
+
+// getManifestUsage sums the claim of the objects of a rendered manifest, so it holds for any chart. Containers claim
+// their request and fall back to their limit, pods claim the larger of their containers and their biggest init
+// container. Workloads are counted at the maximum of the autoscaler targeting them, daemon sets once and jobs at their
+// parallelism. Storage is the size of the claims, stateful set claim templates count once per replica
+func getManifestUsage(manifest string) (*resourceUsage, error) {
+	objects, err := decodeManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+	maxReplicas := make(map[string]int64)
+	for _, object := range objects {
+		var target interface{}
+		var replicas interface{}
+		switch object["kind"] {
+		case "HorizontalPodAutoscaler":
+			target, replicas = getValue(object, "spec", "scaleTargetRef"), getValue(object, "spec", "maxReplicas")
+		case "ScaledObject":
+			target, replicas = getValue(object, "spec", "scaleTargetRef"), getValue(object, "spec", "maxReplicaCount")
+			if replicas == nil {
+				replicas = kedaDefaultMaxReplicas
+			}
+		default:
+			continue
+		}
+		kind, _ := getValue(target, "kind").(string)
+		if len(kind) == 0 {
+			kind = "Deployment"
+		}
+		name, _ := getValue(target, "name").(string)
+		count, err := parseCount(replicas)
+		if err != nil {
+			return nil, err
+		}
+		maxReplicas[kind+"/"+name] = count
+	}
+
+	usage := &resourceUsage{}
+	for _, object := range objects {
+		kind, _ := object["kind"].(string)
+		name, _ := getValue(object, "metadata", "name").(string)
+		var podSpec interface{}
+		var replicas interface{} = int64(1)
+		switch kind {
+		case "Deployment", "StatefulSet", "ReplicaSet", "Rollout":
+			podSpec = getValue(object, "spec", "template", "spec")
+			if value := getValue(object, "spec", "replicas"); value != nil {
+				replicas = value
+			}
+		case "DaemonSet":
+			podSpec = getValue(object, "spec", "template", "spec")
+		case "Pod":
+			podSpec = getValue(object, "spec")
+		case "Job":
+			podSpec = getValue(object, "spec", "template", "spec")
+			if value := getValue(object, "spec", "parallelism"); value != nil {
+				replicas = value
+			}
+		case "CronJob":
+			podSpec = getValue(object, "spec", "jobTemplate", "spec", "template", "spec")
+			if value := getValue(object, "spec", "jobTemplate", "spec", "parallelism"); value != nil {
+				replicas = value
+			}
+		case "PersistentVolumeClaim":
+			storage, err := getClaimStorage(object)
+			if err != nil {
+				return nil, err
+			}
+			usage.storage += storage
+			continue
+		default:
+			continue
+		}
+		count, err := parseCount(replicas)
+		if err != nil {
+			return nil, err
+		}
+		if autoscaled, ok := maxReplicas[kind+"/"+name]; ok {
+			count = autoscaled
+		}
+		podUsage, err := getPodSpecUsage(podSpec)
+		if err != nil {
+			return nil, err
+		}
+		usage.replicas += count
+		usage.cpu += podUsage.cpu * count
+		usage.memory += podUsage.memory * count
+		if kind == "StatefulSet" {
+			claimTemplates, _ := getValue(object, "spec", "volumeClaimTemplates").([]interface{})
+			for _, claimTemplate := range claimTemplates {
+				storage, err := getClaimStorage(claimTemplate)
+				if err != nil {
+					return nil, err
+				}
+				usage.storage += storage * count
+			}
+		}
+	}
+	return usage, nil
+}
+
+// kedaDefaultMaxReplicas is what keda scales to when maxReplicaCount is not set
+const kedaDefaultMaxReplicas = 100
+
+func decodeManifest(manifest string) ([]map[string]interface{}, error) {
+	decoder := k8sYaml.NewYAMLOrJSONDecoder(strings.NewReader(manifest), 4096)
+	var objects []map[string]interface{}
+	for {
+		object := make(map[string]interface{})
+		err := decoder.Decode(&object)
+		if err == io.EOF {
+			return objects, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if len(object) > 0 {
+			objects = append(objects, object)
+		}
+	}
+}
+
+func getPodSpecUsage(podSpec interface{}) (*resourceUsage, error) {
+	usage := &resourceUsage{}
+	containers, _ := getValue(podSpec, "containers").([]interface{})
+	for _, container := range containers {
+		cpu, err := getPodResource(container, bean.ResourceCpu)
+		if err != nil {
+			return nil, err
+		}
+		memory, err := getPodResource(container, bean.ResourceMemory)
+		if err != nil {
+			return nil, err
+		}
+		usage.cpu += cpu.MilliValue()
+		usage.memory += memory.Value()
+	}
+	// init containers run one after the other before the containers start
+	initContainers, _ := getValue(podSpec, "initContainers").([]interface{})
+	for _, container := range initContainers {
+		cpu, err := getPodResource(container, bean.ResourceCpu)
+		if err != nil {
+			return nil, err
+		}
+		memory, err := getPodResource(container, bean.ResourceMemory)
+		if err != nil {
+			return nil, err
+		}
+		usage.cpu = max(usage.cpu, cpu.MilliValue())
+		usage.memory = max(usage.memory, memory.Value())
+	}
+	return usage, nil
+}
+
+func getClaimStorage(claim interface{}) (int64, error) {
+	value := getValue(claim, "spec", "resources", "requests", "storage")
+	if value == nil {
+		return 0, nil
+	}
+	quantity, err := parseQuantity(bean.ResourceStorage, fmt.Sprint(value))
+	if err != nil {
+		return 0, err
+	}
+	return quantity.Value(), nil
+}
+
+func parseCount(value interface{}) (int64, error) {
+	count, err := globalUtil.ParseFloatNumber(value)
+	if err != nil {
+		return 0, fmt.Errorf(bean.InvalidQuantity, bean.ResourceReplicas, fmt.Sprint(value), err.Error())
+	}
+	return int64(count), nil
+}
+
+// getValuesUsage estimates the claim from the merged deployment template when no rendered manifest was measured. It
+// only knows the keys of the devtron reference charts (deployment, rollout and statefulset charts): replicaCount,
+// autoscaling, kedaAutoscaling, resources, persistentVolumeClaim and statefulSetConfig. Other charts claim nothing here
+func getValuesUsage(mergedValues []byte) (*resourceUsage, error) {
+	values := make(map[string]interface{})
+	err := yaml.Unmarshal(mergedValues, &values)
+	if err != nil {
+		return nil, err
+	}
+	replicas, err := getReplicas(values)
+	if err != nil {
+		return nil, err
+	}
+	usage := &resourceUsage{replicas: replicas}
+	cpu, err := getPodResource(values, bean.ResourceCpu)
+	if err != nil {
+		return nil, err
+	}
+	memory, err := getPodResource(values, bean.ResourceMemory)
+	if err != nil {
+		return nil, err
+	}
+	usage.cpu = cpu.MilliValue() * replicas
+	usage.memory = memory.Value() * replicas
+
+	if pvcName, _ := getValue(values, "persistentVolumeClaim", "name").(string); len(pvcName) > 0 {
+		storage := defaultPvcStorage
+		if value := getValue(values, "persistentVolumeClaim", "storage"); value != nil {
+			storage = fmt.Sprint(value)
+		}
+		quantity, err := parseQuantity(bean.ResourceStorage, storage)
+		if err != nil {
+			return nil, err
+		}
+		usage.storage += quantity.Value()
+	}
+	// every replica of a stateful set gets its own claims
+	volumeClaimTemplates, _ := getValue(values, "statefulSetConfig", "volumeClaimTemplates").([]interface{})
+	for _, claimTemplate := range volumeClaimTemplates {
+		value := getValue(claimTemplate, "spec", "resources", "requests", "storage")
+		if value == nil {
+			continue
+		}
+		quantity, err := parseQuantity(bean.ResourceStorage, fmt.Sprint(value))
+		if err != nil {
+			return nil, err
+		}
+		usage.storage += quantity.Value() * replicas
+	}
+	return usage, nil
+}
+
+func getReplicas(values map[string]interface{}) (int64, error) {
+	replicaCount := getValue(values, "replicaCount")
+	if enabled, _ := getValue(values, "autoscaling", "enabled").(bool); enabled {
+		replicaCount = getValue(values, "autoscaling", "MaxReplicas")
+	} else if enabled, _ = getValue(values, "kedaAutoscaling", "enabled").(bool); enabled {
+		replicaCount = getValue(values, "kedaAutoscaling", "maxReplicaCount")
+	}
+	if replicaCount == nil {
+		return 1, nil
+	}
+	return parseCount(replicaCount)
+}
+
+// getPodResource prefers the request of the container and falls back to its limit, as kubernetes does
+func getPodResource(values interface{}, resourceName string) (resource.Quantity, error) {
+	value := getValue(values, "resources", "requests", resourceName)
+	if value == nil {
+		value = getValue(values, "resources", "limits", resourceName)
+	}
+	if value == nil {
+		return resource.Quantity{}, nil
+	}
+	return parseQuantity(resourceName, fmt.Sprint(value))
+}
+
+func getValue(values interface{}, path ...string) interface{} {
+	current := values
+	for _, key := range path {
+		valueMap, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = valueMap[key]
+	}
+	return current
+}
+
+func parseQuantity(resourceName, value string) (resource.Quantity, error) {
+	quantity, err := resource.ParseQuantity(strings.TrimSpace(value))
+	if err != nil {
+		return quantity, fmt.Errorf(bean.InvalidQuantity, resourceName, value, err.Error())
+	}
+	return quantity, nil
+}
+
+// getBudgetLimits converts the limits of a budget, unbounded resources are negative
+func getBudgetLimits(budget *repository.ResourceBudget) (*resourceUsage, error) {
+	limits := &resourceUsage{cpu: -1, memory: -1, replicas: -1, storage: -1}
+	if len(budget.Cpu) > 0 {
+		quantity, err := parseQuantity(bean.ResourceCpu, budget.Cpu)
+		if err != nil {
+			return nil, err
+		}
+		limits.cpu = quantity.MilliValue()
+	}
+	if len(budget.Memory) > 0 {
+		quantity, err := parseQuantity(bean.ResourceMemory, budget.Memory)
+		if err != nil {
+			return nil, err
+		}
+		limits.memory = quantity.Value()
+	}
+	if budget.Replicas > 0 {
+		limits.replicas = int64(budget.Replicas)
+	}
+	if len(budget.Storage) > 0 {
+		quantity, err := parseQuantity(bean.ResourceStorage, budget.Storage)
+		if err != nil {
+			return nil, err
+		}
+		limits.storage = quantity.Value()
+	}
+	return limits, nil
+}
+
+// getExceededResources lists every limited resource that the requested claim does not fit into next to what is used
+func getExceededResources(limits, used, requested *resourceUsage) []string {
+	var exceeded []string
+	check := func(resourceName string, limit, used, requested int64, format func(int64) string) {
+		if limit < 0 || used+requested <= limit {
+			return
+		}
+		available := limit - used
+		if available < 0 {
+			available = 0
+		}
+		exceeded = append(exceeded, fmt.Sprintf(bean.BudgetExceededDetail, format(requested), resourceName, format(available), format(limit)))
+	}
+	check(bean.ResourceCpu, limits.cpu, used.cpu, requested.cpu, formatCpu)
+	check(bean.ResourceMemory, limits.memory, used.memory, requested.memory, formatBytes)
+	check(bean.ResourceReplicas, limits.replicas, used.replicas, requested.replicas, func(count int64) string { return fmt.Sprint(count) })
+	check(bean.ResourceStorage, limits.storage, used.storage, requested.storage, formatBytes)
+	return exceeded
+}
+
+// getHeadroom is what is left of every limited resource, unbounded resources are left empty
+func getHeadroom(limits, used *resourceUsage) *bean.ResourceUsageDto {
+	left := func(limit, used int64) int64 {
+		if used > limit {
+			return 0
+		}
+		return limit - used
+	}
+	headroom := &bean.ResourceUsageDto{}
+	if limits.cpu >= 0 {
+		headroom.Cpu = formatCpu(left(limits.cpu, used.cpu))
+	}
+	if limits.memory >= 0 {
+		headroom.Memory = formatBytes(left(limits.memory, used.memory))
+	}
+	if limits.replicas >= 0 {
+		headroom.Replicas = int(left(limits.replicas, used.replicas))
+	}
+	if limits.storage >= 0 {
+		headroom.Storage = formatBytes(left(limits.storage, used.storage))
+	}
+	return headroom
+}
@@ -0,0 +1,242 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resourceQuota
+
+import (
+	"github.com/devtron-labs/devtron/pkg/policyGovernance/resourceQuota/repository"
+	"testing"
+)
+
+func TestGetValuesUsage(t *testing.T) {
+	tests := []struct {
+		name         string
+		mergedValues string
+		want         resourceUsage
+		wantErr      bool
+	}{
+		{
+			name:         "requests times replicas",
+			mergedValues: `{"replicaCount": 3, "resources": {"requests": {"cpu": "500m", "memory": "1Gi"}, "limits": {"cpu": "1", "memory": "2Gi"}}}`,
+			want:         resourceUsage{cpu: 1500, memory: 3 << 30, replicas: 3},
+		},
+		{
+			name:         "limits when requests are missing",
+			mergedValues: `{"replicaCount": 2, "resources": {"limits": {"cpu": 0.5, "memory": "256Mi"}}}`,
+			want:         resourceUsage{cpu: 1000, memory: 512 << 20, replicas: 2},
+		},
+		{
+			name:         "autoscaling maximum",
+			mergedValues: `{"replicaCount": 1, "autoscaling": {"enabled": true, "MaxReplicas": 10}, "resources": {"requests": {"cpu": "100m"}}}`,
+			want:         resourceUsage{cpu: 1000, replicas: 10},
+		},
+		{
+			name:         "keda maximum",
+			mergedValues: `{"kedaAutoscaling": {"enabled": true, "maxReplicaCount": 4}}`,
+			want:         resourceUsage{replicas: 4},
+		},
+		{
+			name:         "pvc with default size",
+			mergedValues: `{"replicaCount": 2, "persistentVolumeClaim": {"name": "data"}}`,
+			want:         resourceUsage{replicas: 2, storage: 5 << 30},
+		},
+		{
+			name:         "stateful set claims per replica",
+			mergedValues: `{"replicaCount": 3, "statefulSetConfig": {"volumeClaimTemplates": [{"spec": {"resources": {"requests": {"storage": "2Gi"}}}}]}}`,
+			want:         resourceUsage{replicas: 3, storage: 6 << 30},
+		},
+		{
+			name:         "yaml template",
+			mergedValues: "replicaCount: 2\nresources:\n  requests:\n    cpu: 2\n",
+			want:         resourceUsage{cpu: 4000, replicas: 2},
+		},
+		{
+			name:         "invalid quantity",
+			mergedValues: `{"resources": {"requests": {"cpu": "lots"}}}`,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getValuesUsage([]byte(tt.mergedValues))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getValuesUsage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && *got != tt.want {
+				t.Errorf("getValuesUsage() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetManifestUsage(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     resourceUsage
+		wantErr  bool
+	}{
+		{
+			name: "deployment requests times replicas",
+			manifest: `---
+# Source: app/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+        - name: app
+          resources:
+            requests: {cpu: 500m, memory: 1Gi}
+            limits: {cpu: 1, memory: 2Gi}
+        - name: sidecar
+          resources:
+            limits: {cpu: 100m, memory: 128Mi}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+`,
+			want: resourceUsage{cpu: 1800, memory: 3 * (1<<30 + 128<<20), replicas: 3},
+		},
+		{
+			name: "hpa and keda maximum",
+			manifest: `apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata:
+  name: app
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+        - resources: {requests: {cpu: 100m}}
+---
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+spec:
+  scaleTargetRef: {apiVersion: argoproj.io/v1alpha1, kind: Rollout, name: app}
+  maxReplicas: 10
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+spec:
+  template:
+    spec:
+      containers:
+        - resources: {requests: {memory: 64Mi}}
+---
+apiVersion: keda.sh/v1alpha1
+kind: ScaledObject
+spec:
+  scaleTargetRef: {name: worker}
+  maxReplicaCount: 4
+`,
+			want: resourceUsage{cpu: 1000, memory: 4 * 64 << 20, replicas: 14},
+		},
+		{
+			name: "init containers and cronjob parallelism",
+			manifest: `apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: job
+spec:
+  jobTemplate:
+    spec:
+      parallelism: 2
+      template:
+        spec:
+          initContainers:
+            - resources: {requests: {cpu: 2}}
+          containers:
+            - resources: {requests: {cpu: 500m}}
+`,
+			want: resourceUsage{cpu: 4000, replicas: 2},
+		},
+		{
+			name: "claims and stateful set claim templates",
+			manifest: `apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+spec:
+  resources: {requests: {storage: 5Gi}}
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers: []
+  volumeClaimTemplates:
+    - spec:
+        resources: {requests: {storage: 2Gi}}
+`,
+			want: resourceUsage{replicas: 3, storage: 11 << 30},
+		},
+		{
+			name:     "invalid quantity",
+			manifest: "kind: Pod\nspec:\n  containers:\n    - resources: {requests: {cpu: lots}}\n",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getManifestUsage(tt.manifest)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getManifestUsage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && *got != tt.want {
+				t.Errorf("getManifestUsage() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetExceededResources(t *testing.T) {
+	limits, err := getBudgetLimits(&repository.ResourceBudget{Cpu: "8", Memory: "16Gi", Replicas: 10})
+	if err != nil {
+		t.Fatalf("getBudgetLimits() error = %v", err)
+	}
+	used := &resourceUsage{cpu: 6000, memory: 4 << 30, replicas: 4, storage: 100 << 30}
+
+	exceeded := getExceededResources(limits, used, &resourceUsage{cpu: 2000, memory: 12 << 30, replicas: 6, storage: 1 << 40})
+	if len(exceeded) != 0 {
+		t.Errorf("claim filling the budget should fit, got %v", exceeded)
+	}
+	exceeded = getExceededResources(limits, used, &resourceUsage{cpu: 64000, memory: 1 << 30, replicas: 7})
+	if len(exceeded) != 2 {
+		t.Fatalf("cpu and replicas should exceed the budget, got %v", exceeded)
+	}
+	if want := "64 cpu requested, 2 available of 8"; exceeded[0] != want {
+		t.Errorf("getExceededResources() = %q, want %q", exceeded[0], want)
+	}
+
+	headroom := getHeadroom(limits, used)
+	if headroom.Cpu != "2" || headroom.Memory != "12Gi" || headroom.Replicas != 6 || headroom.Storage != "" {
+		t.Errorf("getHeadroom() = %+v", headroom)
+	}
+}
@@ -0,0 +1,220 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/internal/sql/models"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"github.com/go-pg/pg/orm"
+	"go.uber.org/zap"
+	"time"
+)
+
+// ResourceBudget is scoped to an environment, a team or a team within an environment, a zero id leaves the column null
+type ResourceBudget struct {
+	tableName     struct{} `sql:"resource_budget" pg:",discard_unknown_columns"`
+	Id            int      `sql:"id,pk"`
+	EnvironmentId int      `sql:"environment_id"`
+	TeamId        int      `sql:"team_id"`
+	Cpu           string   `sql:"cpu"`
+	Memory        string   `sql:"memory"`
+	Replicas      int      `sql:"replicas"`
+	Storage       string   `sql:"storage"`
+	Mode          string   `sql:"mode,notnull"`
+	Active        bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type ResourceBudgetViolation struct {
+	tableName        struct{} `sql:"resource_budget_violation" pg:",discard_unknown_columns"`
+	Id               int      `sql:"id,pk"`
+	ResourceBudgetId int      `sql:"resource_budget_id,notnull"`
+	AppId            int      `sql:"app_id,notnull"`
+	EnvironmentId    int      `sql:"environment_id,notnull"`
+	PipelineId       int      `sql:"pipeline_id,notnull"`
+	Mode             string   `sql:"mode,notnull"`
+	Message          string   `sql:"message,notnull"`
+	sql.AuditLog
+}
+
+// ResourceBudgetUsage is what the rendered manifest of a deployment claims, cpu in millicores and sizes in bytes
+type ResourceBudgetUsage struct {
+	tableName          struct{} `sql:"resource_budget_usage" pg:",discard_unknown_columns"`
+	Id                 int      `sql:"id,pk"`
+	PipelineOverrideId int      `sql:"pipeline_override_id,notnull"`
+	Cpu                int64    `sql:"cpu,notnull"`
+	Memory             int64    `sql:"memory,notnull"`
+	Replicas           int64    `sql:"replicas,notnull"`
+	Storage            int64    `sql:"storage,notnull"`
+	sql.AuditLog
+}
+
+// DeployedTemplate is the merged deployment template of the latest deployment of a cd pipeline, the usage columns are
+// only set when the rendered manifest of that deployment was measured
+type DeployedTemplate struct {
+	PipelineId     int                   `sql:"pipeline_id"`
+	AppId          int                   `sql:"app_id"`
+	AppName        string                `sql:"app_name"`
+	TeamId         int                   `sql:"team_id"`
+	EnvironmentId  int                   `sql:"environment_id"`
+	MergedValues   string                `sql:"merged_values_yaml"`
+	DeploymentType models.DeploymentType `sql:"deployment_type"`
+	UsageMeasured  bool                  `sql:"usage_measured"`
+	Cpu            int64                 `sql:"cpu"`
+	Memory         int64                 `sql:"memory"`
+	Replicas       int64                 `sql:"replicas"`
+	Storage        int64                 `sql:"storage"`
+}
+
+type ResourceQuotaRepository interface {
+	SaveBudget(budget *ResourceBudget) error
+	UpdateBudget(budget *ResourceBudget) error
+	FindActiveBudgetById(id int) (*ResourceBudget, error)
+	FindActiveBudgetByScope(environmentId, teamId int) (*ResourceBudget, error)
+	FindAllActiveBudgets() ([]*ResourceBudget, error)
+	// FindApplicableBudgets returns the budgets of the environment, of the team and of the team within the environment
+	FindApplicableBudgets(environmentId, teamId int) ([]*ResourceBudget, error)
+	SaveViolation(violation *ResourceBudgetViolation) error
+	FindViolationsSince(environmentIds []int, since time.Time) ([]*ResourceBudgetViolation, error)
+	// FindDeployedTemplates returns the latest deployed template of every active cd pipeline, filtered on the
+	// environments and the team when given
+	FindDeployedTemplates(environmentIds []int, teamId int) ([]*DeployedTemplate, error)
+	// SaveUsage stores the measured usage of a pipeline override, replacing an earlier measurement
+	SaveUsage(usage *ResourceBudgetUsage) error
+}
+
+type ResourceQuotaRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewResourceQuotaRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *ResourceQuotaRepositoryImpl {
+	return &ResourceQuotaRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (repo *ResourceQuotaRepositoryImpl) SaveBudget(budget *ResourceBudget) error {
+	return repo.dbConnection.Insert(budget)
+}
+
+func (repo *ResourceQuotaRepositoryImpl) UpdateBudget(budget *ResourceBudget) error {
+	return repo.dbConnection.Update(budget)
+}
+
+func (repo *ResourceQuotaRepositoryImpl) FindActiveBudgetById(id int) (*ResourceBudget, error) {
+	budget := &ResourceBudget{}
+	err := repo.dbConnection.Model(budget).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return budget, err
+}
+
+func (repo *ResourceQuotaRepositoryImpl) FindActiveBudgetByScope(environmentId, teamId int) (*ResourceBudget, error) {
+	budget := &ResourceBudget{}
+	query := repo.dbConnection.Model(budget).
+		Where("active = ?", true)
+	if environmentId > 0 {
+		query = query.Where("environment_id = ?", environmentId)
+	} else {
+		query = query.Where("environment_id IS NULL")
+	}
+	if teamId > 0 {
+		query = query.Where("team_id = ?", teamId)
+	} else {
+		query = query.Where("team_id IS NULL")
+	}
+	err := query.Limit(1).Select()
+	return budget, err
+}
+
+func (repo *ResourceQuotaRepositoryImpl) FindAllActiveBudgets() ([]*ResourceBudget, error) {
+	var budgets []*ResourceBudget
+	err := repo.dbConnection.Model(&budgets).
+		Where("active = ?", true).
+		Order("id").
+		Select()
+	return budgets, err
+}
+
+func (repo *ResourceQuotaRepositoryImpl) FindApplicableBudgets(environmentId, teamId int) ([]*ResourceBudget, error) {
+	var budgets []*ResourceBudget
+	err := repo.dbConnection.Model(&budgets).
+		Where("active = ?", true).
+		WhereGroup(func(query *orm.Query) (*orm.Query, error) {
+			query = query.
+				WhereOr("environment_id = ? AND team_id IS NULL", environmentId).
+				WhereOr("team_id = ? AND (environment_id = ? OR environment_id IS NULL)", teamId, environmentId)
+			return query, nil
+		}).
+		Order("id").
+		Select()
+	return budgets, err
+}
+
+func (repo *ResourceQuotaRepositoryImpl) SaveViolation(violation *ResourceBudgetViolation) error {
+	return repo.dbConnection.Insert(violation)
+}
+
+func (repo *ResourceQuotaRepositoryImpl) FindViolationsSince(environmentIds []int, since time.Time) ([]*ResourceBudgetViolation, error) {
+	var violations []*ResourceBudgetViolation
+	if len(environmentIds) == 0 {
+		return violations, nil
+	}
+	err := repo.dbConnection.Model(&violations).
+		Where("environment_id in (?)", pg.In(environmentIds)).
+		Where("created_on > ?", since).
+		Order("id DESC").
+		Select()
+	return violations, err
+}
+
+func (repo *ResourceQuotaRepositoryImpl) FindDeployedTemplates(environmentIds []int, teamId int) ([]*DeployedTemplate, error) {
+	var templates []*DeployedTemplate
+	query := "SELECT DISTINCT ON (p.id) p.id AS pipeline_id, p.app_id, a.app_name, a.team_id, p.environment_id, " +
+		" pco.merged_values_yaml, pco.deployment_type," +
+		" rbu.id IS NOT NULL AS usage_measured, rbu.cpu, rbu.memory, rbu.replicas, rbu.storage" +
+		" FROM pipeline p" +
+		" INNER JOIN app a ON a.id = p.app_id AND a.active = true" +
+		" INNER JOIN pipeline_config_override pco ON pco.pipeline_id = p.id" +
+		" LEFT JOIN resource_budget_usage rbu ON rbu.pipeline_override_id = pco.id" +
+		" WHERE p.deleted = false AND pco.merged_values_yaml <> ''"
+	var queryParams []interface{}
+	if len(environmentIds) > 0 {
+		query += " AND p.environment_id in (?)"
+		queryParams = append(queryParams, pg.In(environmentIds))
+	}
+	if teamId > 0 {
+		query += " AND a.team_id = ?"
+		queryParams = append(queryParams, teamId)
+	}
+	query += " ORDER BY p.id, pco.id DESC;"
+	_, err := repo.dbConnection.Query(&templates, query, queryParams...)
+	return templates, err
+}
+
+func (repo *ResourceQuotaRepositoryImpl) SaveUsage(usage *ResourceBudgetUsage) error {
+	_, err := repo.dbConnection.Model(usage).
+		OnConflict("(pipeline_override_id) DO UPDATE").
+		Set("cpu = EXCLUDED.cpu, memory = EXCLUDED.memory, replicas = EXCLUDED.replicas, storage = EXCLUDED.storage").
+		Set("updated_on = EXCLUDED.updated_on, updated_by = EXCLUDED.updated_by").
+		Insert()
+	return err
+}
@@ -0,0 +1,262 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package imageScanning
+
+import (
+	"context"
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/sql/repository"
+	dockerRegistryRepository "github.com/devtron-labs/devtron/internal/sql/repository/dockerRegistry"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/build/artifacts/retention/registry"
+	"github.com/devtron-labs/devtron/pkg/dockerRegistry/registryProvider"
+	bean3 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/bean"
+	repository3 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository"
+	securityBean "github.com/devtron-labs/devtron/pkg/policyGovernance/security/imageScanning/repository/bean"
+	repository2 "github.com/devtron-labs/devtron/pkg/policyGovernance/security/scanTool/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	registryScanToolVersion  = "V1"
+	registryApiTimeout       = 60 * time.Second
+	registryScanNotEnabled   = "import of scan results is not enabled for the registry of this image"
+	registryScanNotAvailable = "registry has not scanned this image yet"
+	registryOfImageNotFound  = "container registry of the image could not be found"
+)
+
+// registryScanToolNames are the scan_tool_metadata entries the imported results are recorded against
+var registryScanToolNames = map[dockerRegistryRepository.RegistryType]string{
+	dockerRegistryRepository.REGISTRYTYPE_HARBOR: "HARBOR",
+	dockerRegistryRepository.REGISTRYTYPE_QUAY:   "QUAY",
+}
+
+type RegistryScanImportService interface {
+	// ImportRegistryScanResult copies the vulnerability report that harbor or quay generated for the artifact image into
+	// the image scan results, as if the image had been scanned after the build
+	ImportRegistryScanResult(ctx context.Context, artifactId int, userId int32) (*bean3.RegistryScanImportResponse, error)
+}
+
+type RegistryScanImportServiceImpl struct {
+	logger                                    *zap.SugaredLogger
+	ciArtifactRepository                      repository.CiArtifactRepository
+	dockerArtifactStoreRepository             dockerRegistryRepository.DockerArtifactStoreRepository
+	scanHistoryRepository                     repository3.ImageScanHistoryRepository
+	scanResultRepository                      repository3.ImageScanResultRepository
+	cveStoreRepository                        repository3.CveStoreRepository
+	scanToolMetadataRepository                repository2.ScanToolMetadataRepository
+	scanToolExecutionHistoryMappingRepository repository3.ScanToolExecutionHistoryMappingRepository
+}
+
+func NewRegistryScanImportServiceImpl(logger *zap.SugaredLogger,
+	ciArtifactRepository repository.CiArtifactRepository,
+	dockerArtifactStoreRepository dockerRegistryRepository.DockerArtifactStoreRepository,
+	scanHistoryRepository repository3.ImageScanHistoryRepository,
+	scanResultRepository repository3.ImageScanResultRepository,
+	cveStoreRepository repository3.CveStoreRepository,
+	scanToolMetadataRepository repository2.ScanToolMetadataRepository,
+	scanToolExecutionHistoryMappingRepository repository3.ScanToolExecutionHistoryMappingRepository) *RegistryScanImportServiceImpl {
+	return &RegistryScanImportServiceImpl{
+		logger:                                    logger,
+		ciArtifactRepository:                      ciArtifactRepository,
+		dockerArtifactStoreRepository:             dockerArtifactStoreRepository,
+		scanHistoryRepository:                     scanHistoryRepository,
+		scanResultRepository:                      scanResultRepository,
+		cveStoreRepository:                        cveStoreRepository,
+		scanToolMetadataRepository:                scanToolMetadataRepository,
+		scanToolExecutionHistoryMappingRepository: scanToolExecutionHistoryMappingRepository,
+	}
+}
+
+func (impl *RegistryScanImportServiceImpl) ImportRegistryScanResult(ctx context.Context, artifactId int, userId int32) (*bean3.RegistryScanImportResponse, error) {
+	artifact, err := impl.ciArtifactRepository.Get(artifactId)
+	if err != nil {
+		impl.logger.Errorw("error in fetching artifact", "artifactId", artifactId, "err", err)
+		return nil, err
+	}
+	imageRef, err := registry.ParseImageRef(artifact.Image, artifact.ImageDigest)
+	if err != nil {
+		impl.logger.Errorw("error in parsing artifact image", "image", artifact.Image, "err", err)
+		return nil, err
+	}
+	store, err := impl.findRegistryOfArtifact(artifact, imageRef)
+	if err != nil {
+		return nil, err
+	}
+	if !store.ImportScanResults || !registryProvider.IsProviderRegistryType(store.RegistryType) {
+		return nil, util.NewApiError(http.StatusBadRequest, registryScanNotEnabled, registryScanNotEnabled)
+	}
+	scanTool, err := impl.scanToolMetadataRepository.FindByNameAndVersionIncludingInactive(registryScanToolNames[store.RegistryType], registryScanToolVersion)
+	if err != nil {
+		impl.logger.Errorw("error in fetching scan tool of registry", "registryType", store.RegistryType, "err", err)
+		return nil, err
+	}
+	client, err := registryProvider.NewRegistryProviderClient(store, registryApiTimeout)
+	if err != nil {
+		impl.logger.Errorw("error in creating registry api client", "storeId", store.Id, "err", err)
+		return nil, err
+	}
+	reference := imageRef.Digest
+	if len(reference) == 0 {
+		reference = imageRef.Tag
+	}
+	report, err := client.GetVulnerabilityReport(ctx, imageRef.Repository, reference)
+	if err == registryProvider.ErrScanReportNotFound {
+		return nil, util.NewApiError(http.StatusNotFound, registryScanNotAvailable, err.Error())
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching scan report from registry", "storeId", store.Id, "image", artifact.Image, "err", err)
+		return nil, err
+	}
+	executionHistory, err := impl.saveScanResult(artifact, scanTool, report, userId)
+	if err != nil {
+		return nil, err
+	}
+	artifact.Scanned = true
+	artifact.UpdateAuditLog(userId)
+	err = impl.ciArtifactRepository.Update(artifact)
+	if err != nil {
+		impl.logger.Errorw("error in marking artifact scanned", "artifactId", artifactId, "err", err)
+		return nil, err
+	}
+	return &bean3.RegistryScanImportResponse{
+		ArtifactId:         artifact.Id,
+		Image:              artifact.Image,
+		Scanner:            report.Scanner,
+		ScanExecutionId:    executionHistory.Id,
+		VulnerabilityCount: len(report.Vulnerabilities),
+	}, nil
+}
+
+func (impl *RegistryScanImportServiceImpl) saveScanResult(artifact *repository.CiArtifact, scanTool *repository2.ScanToolMetadata,
+	report *registryProvider.VulnerabilityReport, userId int32) (*repository3.ImageScanExecutionHistory, error) {
+	now := time.Now()
+	executionHistory := &repository3.ImageScanExecutionHistory{
+		Image:         artifact.Image,
+		ImageHash:     artifact.ImageDigest,
+		ExecutionTime: now,
+		ExecutedBy:    int(userId),
+		SourceType:    repository3.SourceTypeImage,
+		SourceSubType: repository3.SourceSubTypeCi,
+	}
+	err := impl.scanHistoryRepository.Save(executionHistory)
+	if err != nil {
+		impl.logger.Errorw("error in saving scan execution history", "image", artifact.Image, "err", err)
+		return nil, err
+	}
+	err = impl.scanToolExecutionHistoryMappingRepository.Save(&repository3.ScanToolExecutionHistoryMapping{
+		ImageScanExecutionHistoryId: executionHistory.Id,
+		ScanToolId:                  scanTool.Id,
+		ExecutionStartTime:          now,
+		ExecutionFinishTime:         now,
+		State:                       repository3.ScanExecutionProcessStateCompleted,
+		AuditLog:                    sql.NewDefaultAuditLog(userId),
+	})
+	if err != nil {
+		impl.logger.Errorw("error in saving scan tool execution mapping", "executionHistoryId", executionHistory.Id, "err", err)
+		return nil, err
+	}
+	savedCves := make(map[string]bool)
+	for _, vulnerability := range report.Vulnerabilities {
+		if !savedCves[vulnerability.CveName] {
+			err = impl.ensureCveStore(vulnerability, userId)
+			if err != nil {
+				return nil, err
+			}
+			savedCves[vulnerability.CveName] = true
+		}
+		err = impl.scanResultRepository.Save(&repository3.ImageScanExecutionResult{
+			CveStoreName:                vulnerability.CveName,
+			ImageScanExecutionHistoryId: executionHistory.Id,
+			ScanToolId:                  scanTool.Id,
+			Package:                     vulnerability.Package,
+			Version:                     vulnerability.Version,
+			FixedVersion:                vulnerability.FixedVersion,
+			Target:                      artifact.Image,
+		})
+		if err != nil {
+			impl.logger.Errorw("error in saving scan result", "cve", vulnerability.CveName, "err", err)
+			return nil, err
+		}
+	}
+	return executionHistory, nil
+}
+
+func (impl *RegistryScanImportServiceImpl) ensureCveStore(vulnerability *registryProvider.Vulnerability, userId int32) error {
+	_, err := impl.cveStoreRepository.FindByName(vulnerability.CveName)
+	if err == nil {
+		return nil
+	} else if err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching cve", "cve", vulnerability.CveName, "err", err)
+		return err
+	}
+	cve := &repository3.CveStore{
+		Name:         vulnerability.CveName,
+		Package:      vulnerability.Package,
+		Version:      vulnerability.Version,
+		FixedVersion: vulnerability.FixedVersion,
+		AuditLog:     sql.NewDefaultAuditLog(userId),
+	}
+	cve.SetStandardSeverity(toStandardSeverity(vulnerability.Severity))
+	err = impl.cveStoreRepository.Save(cve)
+	if err != nil {
+		impl.logger.Errorw("error in saving cve", "cve", vulnerability.CveName, "err", err)
+		return err
+	}
+	return nil
+}
+
+// findRegistryOfArtifact prefers the registry mapped on the artifact and otherwise matches the registry host of the image
+func (impl *RegistryScanImportServiceImpl) findRegistryOfArtifact(artifact *repository.CiArtifact, imageRef *registry.ImageRef) (*dockerRegistryRepository.DockerArtifactStore, error) {
+	if artifact.IsRegistryCredentialMapped() {
+		store, err := impl.dockerArtifactStoreRepository.FindOne(artifact.CredentialSourceValue)
+		if err == nil {
+			return store, nil
+		} else if err != pg.ErrNoRows {
+			impl.logger.Errorw("error in fetching registry of artifact", "storeId", artifact.CredentialSourceValue, "err", err)
+			return nil, err
+		}
+	}
+	stores, err := impl.dockerArtifactStoreRepository.FindAll()
+	if err != nil && err != pg.ErrNoRows {
+		impl.logger.Errorw("error in fetching registries", "err", err)
+		return nil, err
+	}
+	for i := range stores {
+		if registry.GetRegistryHost(stores[i].RegistryURL) == imageRef.Domain {
+			return &stores[i], nil
+		}
+	}
+	return nil, util.NewApiError(http.StatusNotFound, registryOfImageNotFound, fmt.Sprintf("%s, image %s", registryOfImageNotFound, artifact.Image))
+}
+
+// toStandardSeverity maps the severities of harbor and quay, which also report negligible findings, to the scan severities
+func toStandardSeverity(severity string) securityBean.Severity {
+	severity = strings.ToLower(severity)
+	if severity == "negligible" {
+		return securityBean.Low
+	}
+	standardSeverity, err := securityBean.SeverityStringToEnumWithError(severity)
+	if err != nil {
+		return securityBean.Unknown
+	}
+	return standardSeverity
+}
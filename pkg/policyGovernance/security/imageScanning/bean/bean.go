@@ -116,3 +116,12 @@ type AppEnvMetadata struct {
 func NewAppEnvMetadata(appId, envId int) AppEnvMetadata {
 	return AppEnvMetadata{AppId: appId, EnvId: envId}
 }
+
+// RegistryScanImportResponse summarises the scan result of harbor or quay imported for an artifact
+type RegistryScanImportResponse struct {
+	ArtifactId         int    `json:"artifactId"`
+	Image              string `json:"image"`
+	Scanner            string `json:"scanner"`
+	ScanExecutionId    int    `json:"scanExecutionId"`
+	VulnerabilityCount int    `json:"vulnerabilityCount"`
+}
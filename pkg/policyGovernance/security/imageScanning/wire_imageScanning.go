@@ -20,6 +20,9 @@ var ImageScanningWireSet = wire.NewSet(
 	read.NewImageScanDeployInfoReadService,
 	wire.Bind(new(read.ImageScanDeployInfoReadService), new(*read.ImageScanDeployInfoReadServiceImpl)),
 
+	NewRegistryScanImportServiceImpl,
+	wire.Bind(new(RegistryScanImportService), new(*RegistryScanImportServiceImpl)),
+
 	NewImageScanDeployInfoService,
 	wire.Bind(new(ImageScanDeployInfoService), new(*ImageScanDeployInfoServiceImpl)),
 
@@ -43,6 +43,7 @@ type ScanToolMetadata struct {
 type ScanToolMetadataRepository interface {
 	FindActiveToolByScanTarget(scanTarget bean.ScanTargetType) (*ScanToolMetadata, error)
 	FindByNameAndVersion(name, version string) (*ScanToolMetadata, error)
+	FindByNameAndVersionIncludingInactive(name, version string) (*ScanToolMetadata, error)
 	FindActiveById(id int) (*ScanToolMetadata, error)
 	Save(tx *pg.Tx, model *ScanToolMetadata) (*ScanToolMetadata, error)
 	Update(model *ScanToolMetadata) (*ScanToolMetadata, error)
@@ -91,6 +92,19 @@ func (repo *ScanToolMetadataRepositoryImpl) FindByNameAndVersion(name, version s
 	return model, nil
 }
 
+// FindByNameAndVersionIncludingInactive also finds tools that never run scans themselves, like the scanners of registries
+func (repo *ScanToolMetadataRepositoryImpl) FindByNameAndVersionIncludingInactive(name, version string) (*ScanToolMetadata, error) {
+	model := &ScanToolMetadata{}
+	err := repo.dbConnection.Model(model).
+		Where("name = ?", name).Where("version = ?", version).
+		Where("deleted = ?", false).Select()
+	if err != nil {
+		repo.logger.Errorw("error in getting tool by name and version", "err", err, "name", name, "version", version)
+		return nil, err
+	}
+	return model, nil
+}
+
 func (repo *ScanToolMetadataRepositoryImpl) FindActiveById(id int) (*ScanToolMetadata, error) {
 	model := &ScanToolMetadata{}
 	err := repo.dbConnection.Model(model).Where("id = ?", id).
@@ -0,0 +1,169 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commandPolicy
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const (
+	escapeChar    = '\x1b'
+	interruptChar = '\x03'
+	killLineChar  = '\x15'
+	killWordChar  = '\x17'
+	backspaceChar = '\b'
+	deleteChar    = '\x7f'
+)
+
+type escapeState int
+
+const (
+	escapeNone escapeState = iota
+	// escapeStart follows the escape char, the next char decides the kind of sequence
+	escapeStart
+	// escapeCSI is a control sequence, ESC [ params final, ended by a char in the 0x40-0x7e range
+	escapeCSI
+	// escapeSS3 is a single shift sequence, ESC O final, sent by the function and arrow keys
+	escapeSS3
+)
+
+type denyRule struct {
+	policyId   int
+	policyName string
+	regex      *regexp.Regexp
+}
+
+// CommandFilter rebuilds the command lines out of the keystrokes sent to the terminal, the line editing keys
+// are applied, escape sequences such as the arrow keys and tab completions are ignored as only the shell knows their
+// effect. Commands matching a deny rule are not submitted, the line is killed instead of entered.
+// Not safe for concurrent use, the stdin of a terminal session is read from a single goroutine
+type CommandFilter struct {
+	auditEnabled bool
+	rules        []*denyRule
+	line         []rune
+	escape       escapeState
+	onCommand    func(command string, blockedBy *denyRule)
+}
+
+func newCommandFilter(auditEnabled bool, rules []*denyRule, onCommand func(command string, blockedBy *denyRule)) *CommandFilter {
+	return &CommandFilter{
+		auditEnabled: auditEnabled,
+		rules:        rules,
+		onCommand:    onCommand,
+	}
+}
+
+// BlockedCommand is a command line held back from the terminal along with the policy denying it
+type BlockedCommand struct {
+	Command    string
+	PolicyName string
+}
+
+// Filter returns the input to be forwarded to the terminal and the commands blocked in it
+func (filter *CommandFilter) Filter(input string) (string, []*BlockedCommand) {
+	var output strings.Builder
+	var blockedCommands []*BlockedCommand
+	for _, char := range input {
+		if filter.consumeEscape(char) {
+			output.WriteRune(char)
+			continue
+		}
+		switch char {
+		case '\r', '\n':
+			command := strings.Join(strings.Fields(string(filter.line)), " ")
+			filter.line = filter.line[:0]
+			if len(command) == 0 {
+				output.WriteRune(char)
+				continue
+			}
+			blockedBy := filter.getDenyRule(command)
+			if blockedBy != nil {
+				output.WriteRune(killLineChar)
+				blockedCommands = append(blockedCommands, &BlockedCommand{Command: command, PolicyName: blockedBy.policyName})
+			} else {
+				output.WriteRune(char)
+			}
+			if filter.onCommand != nil && (filter.auditEnabled || blockedBy != nil) {
+				filter.onCommand(command, blockedBy)
+			}
+			continue
+		case backspaceChar, deleteChar:
+			if len(filter.line) > 0 {
+				filter.line = filter.line[:len(filter.line)-1]
+			}
+		case interruptChar, killLineChar:
+			filter.line = filter.line[:0]
+		case killWordChar:
+			filter.killWord()
+		case escapeChar:
+			filter.escape = escapeStart
+		default:
+			if !unicode.IsControl(char) {
+				filter.line = append(filter.line, char)
+			}
+		}
+		output.WriteRune(char)
+	}
+	return output.String(), blockedCommands
+}
+
+// consumeEscape reports whether the char is part of an escape sequence
+func (filter *CommandFilter) consumeEscape(char rune) bool {
+	switch filter.escape {
+	case escapeStart:
+		switch char {
+		case '[':
+			filter.escape = escapeCSI
+		case 'O':
+			filter.escape = escapeSS3
+		default:
+			filter.escape = escapeNone
+		}
+		return true
+	case escapeCSI:
+		if char >= 0x40 && char <= 0x7e {
+			filter.escape = escapeNone
+		}
+		return true
+	case escapeSS3:
+		filter.escape = escapeNone
+		return true
+	}
+	return false
+}
+
+func (filter *CommandFilter) killWord() {
+	end := len(filter.line)
+	for end > 0 && unicode.IsSpace(filter.line[end-1]) {
+		end--
+	}
+	for end > 0 && !unicode.IsSpace(filter.line[end-1]) {
+		end--
+	}
+	filter.line = filter.line[:end]
+}
+
+func (filter *CommandFilter) getDenyRule(command string) *denyRule {
+	for _, rule := range filter.rules {
+		if rule.regex.MatchString(command) {
+			return rule
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commandPolicy
+
+import (
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+)
+
+func TestCommandFilter(t *testing.T) {
+	rules := []*denyRule{
+		{policyId: 1, policyName: "prod", regex: regexp.MustCompile(`^kubectl delete (ns|namespace)\b`)},
+		{policyId: 1, policyName: "prod", regex: regexp.MustCompile(`rm -rf /$`)},
+	}
+	type audited struct {
+		command  string
+		policyId int
+	}
+	newFilter := func(auditEnabled bool) (*CommandFilter, *[]audited) {
+		commands := &[]audited{}
+		filter := newCommandFilter(auditEnabled, rules, func(command string, blockedBy *denyRule) {
+			entry := audited{command: command}
+			if blockedBy != nil {
+				entry.policyId = blockedBy.policyId
+			}
+			*commands = append(*commands, entry)
+		})
+		return filter, commands
+	}
+
+	t.Run("allowed command is forwarded and audited", func(t *testing.T) {
+		filter, commands := newFilter(true)
+		output, blocked := filter.Filter("ls   -la")
+		assert.Equal(t, "ls   -la", output)
+		assert.Empty(t, blocked)
+		output, blocked = filter.Filter("\r")
+		assert.Equal(t, "\r", output)
+		assert.Empty(t, blocked)
+		assert.Equal(t, []audited{{command: "ls -la"}}, *commands)
+	})
+
+	t.Run("line editing keys are applied", func(t *testing.T) {
+		filter, commands := newFilter(true)
+		// backspace, arrow key escape sequence, ctrl-w on a word and ctrl-c dropping a line
+		filter.Filter("echo x\x7fy\x1b[A\x1bOB")
+		filter.Filter(" oops\x17done\r")
+		filter.Filter("half typed\x03pwd\r")
+		assert.Equal(t, []audited{{command: "echo y done"}, {command: "pwd"}}, *commands)
+	})
+
+	t.Run("denylisted command is killed instead of entered", func(t *testing.T) {
+		filter, commands := newFilter(false)
+		output, blocked := filter.Filter("kubectl  delete ns prod\r")
+		assert.Equal(t, "kubectl  delete ns prod\x15", output)
+		assert.Equal(t, []*BlockedCommand{{Command: "kubectl delete ns prod", PolicyName: "prod"}}, blocked)
+		// allowed commands are not audited when auditing is disabled
+		output, blocked = filter.Filter("kubectl get ns\r")
+		assert.Equal(t, "kubectl get ns\r", output)
+		assert.Empty(t, blocked)
+		assert.Equal(t, []audited{{command: "kubectl delete ns prod", policyId: 1}}, *commands)
+	})
+
+	t.Run("pasted lines are checked one by one", func(t *testing.T) {
+		filter, commands := newFilter(true)
+		output, blocked := filter.Filter("cd /tmp\nrm -rf /\nrm -rf /tmp/x\n")
+		assert.Equal(t, "cd /tmp\nrm -rf /\x15rm -rf /tmp/x\n", output)
+		assert.Len(t, blocked, 1)
+		assert.Equal(t, []audited{{command: "cd /tmp"}, {command: "rm -rf /", policyId: 1}, {command: "rm -rf /tmp/x"}}, *commands)
+	})
+}
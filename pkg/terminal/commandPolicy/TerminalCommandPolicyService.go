@@ -0,0 +1,294 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commandPolicy
+
+import (
+	"fmt"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	environmentRepository "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/pkg/terminal/commandPolicy/bean"
+	"github.com/devtron-labs/devtron/pkg/terminal/commandPolicy/repository"
+	"go.uber.org/zap"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+type TerminalCommandPolicyService interface {
+	// GetCommandFilter returns the filter of the commands of the session, nil when no policy applies to it
+	GetCommandFilter(detail *bean.CommandSessionDetail) (*CommandFilter, error)
+	GetPolicies() ([]*bean.TerminalCommandPolicyDto, error)
+	CreatePolicy(request *bean.TerminalCommandPolicyDto) (*bean.TerminalCommandPolicyDto, error)
+	UpdatePolicy(request *bean.TerminalCommandPolicyDto) (*bean.TerminalCommandPolicyDto, error)
+	DeletePolicy(id int, userId int32) error
+	GetCommandAudits(filter *repository.CommandAuditFilter) (*bean.TerminalCommandAuditListResponse, error)
+}
+
+type TerminalCommandPolicyServiceImpl struct {
+	logger                          *zap.SugaredLogger
+	terminalCommandPolicyRepository repository.TerminalCommandPolicyRepository
+	environmentRepository           environmentRepository.EnvironmentRepository
+	userService                     user.UserService
+}
+
+func NewTerminalCommandPolicyServiceImpl(logger *zap.SugaredLogger,
+	terminalCommandPolicyRepository repository.TerminalCommandPolicyRepository,
+	environmentRepository environmentRepository.EnvironmentRepository,
+	userService user.UserService) *TerminalCommandPolicyServiceImpl {
+	return &TerminalCommandPolicyServiceImpl{
+		logger:                          logger,
+		terminalCommandPolicyRepository: terminalCommandPolicyRepository,
+		environmentRepository:           environmentRepository,
+		userService:                     userService,
+	}
+}
+
+func (impl *TerminalCommandPolicyServiceImpl) GetCommandFilter(detail *bean.CommandSessionDetail) (*CommandFilter, error) {
+	if detail.EnvironmentId == 0 {
+		// cluster terminal sessions are matched to the environment of their namespace
+		environment, err := impl.environmentRepository.FindOneByNamespaceAndClusterId(detail.Namespace, detail.ClusterId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in finding environment of terminal session", "clusterId", detail.ClusterId, "namespace", detail.Namespace, "err", err)
+			return nil, err
+		} else if err == nil {
+			detail.EnvironmentId = environment.Id
+		}
+	}
+	policies, err := impl.terminalCommandPolicyRepository.FindApplicablePolicies(detail.ClusterId, detail.EnvironmentId)
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching terminal command policies", "clusterId", detail.ClusterId, "environmentId", detail.EnvironmentId, "err", err)
+		return nil, err
+	}
+	auditEnabled := false
+	var rules []*denyRule
+	for _, policy := range policies {
+		auditEnabled = auditEnabled || policy.AuditEnabled
+		for _, pattern := range policy.DenyPatterns {
+			regex, err := regexp.Compile(pattern)
+			if err != nil {
+				// patterns are validated on save, not expected
+				impl.logger.Errorw("skipping invalid terminal command deny pattern", "policyId", policy.Id, "pattern", pattern, "err", err)
+				continue
+			}
+			rules = append(rules, &denyRule{policyId: policy.Id, policyName: policy.Name, regex: regex})
+		}
+	}
+	if !auditEnabled && len(rules) == 0 {
+		return nil, nil
+	}
+	return newCommandFilter(auditEnabled, rules, func(command string, blockedBy *denyRule) {
+		impl.auditCommand(detail, command, blockedBy)
+	}), nil
+}
+
+func (impl *TerminalCommandPolicyServiceImpl) auditCommand(detail *bean.CommandSessionDetail, command string, blockedBy *denyRule) {
+	now := time.Now()
+	audit := &repository.TerminalCommandAudit{
+		SessionId:     detail.SessionId,
+		UserId:        detail.UserId,
+		ClusterId:     detail.ClusterId,
+		EnvironmentId: detail.EnvironmentId,
+		Namespace:     detail.Namespace,
+		PodName:       detail.PodName,
+		ContainerName: detail.ContainerName,
+		Command:       command,
+		Blocked:       blockedBy != nil,
+		ExecutedOn:    now,
+		AuditLog:      sql.AuditLog{CreatedOn: now, CreatedBy: detail.UserId, UpdatedOn: now, UpdatedBy: detail.UserId},
+	}
+	if blockedBy != nil {
+		audit.PolicyId = blockedBy.policyId
+	}
+	impl.logger.Infow("terminal command", "sessionId", detail.SessionId, "userId", detail.UserId, "clusterId", detail.ClusterId,
+		"namespace", detail.Namespace, "podName", detail.PodName, "command", command, "blocked", audit.Blocked, "policyId", audit.PolicyId)
+	// saved in background so that the terminal input is not held up on the db
+	go func() {
+		err := impl.terminalCommandPolicyRepository.SaveCommandAudit(audit)
+		if err != nil {
+			impl.logger.Errorw("error in saving terminal command audit", "sessionId", detail.SessionId, "err", err)
+		}
+	}()
+}
+
+func (impl *TerminalCommandPolicyServiceImpl) GetPolicies() ([]*bean.TerminalCommandPolicyDto, error) {
+	policies, err := impl.terminalCommandPolicyRepository.FindActivePolicies()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching terminal command policies", "err", err)
+		return nil, err
+	}
+	response := make([]*bean.TerminalCommandPolicyDto, 0, len(policies))
+	for _, policy := range policies {
+		response = append(response, getPolicyDto(policy))
+	}
+	return response, nil
+}
+
+func (impl *TerminalCommandPolicyServiceImpl) CreatePolicy(request *bean.TerminalCommandPolicyDto) (*bean.TerminalCommandPolicyDto, error) {
+	err := impl.validatePolicy(request)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	policy := &repository.TerminalCommandPolicy{
+		Name:          request.Name,
+		ClusterId:     request.ClusterId,
+		EnvironmentId: request.EnvironmentId,
+		AuditEnabled:  request.AuditEnabled,
+		DenyPatterns:  request.DenyPatterns,
+		Active:        true,
+		AuditLog:      sql.AuditLog{CreatedOn: now, CreatedBy: request.UserId, UpdatedOn: now, UpdatedBy: request.UserId},
+	}
+	err = impl.terminalCommandPolicyRepository.SavePolicy(policy)
+	if err != nil {
+		impl.logger.Errorw("error in saving terminal command policy", "request", request, "err", err)
+		return nil, err
+	}
+	return getPolicyDto(policy), nil
+}
+
+func (impl *TerminalCommandPolicyServiceImpl) UpdatePolicy(request *bean.TerminalCommandPolicyDto) (*bean.TerminalCommandPolicyDto, error) {
+	policy, err := impl.getActivePolicy(request.Id)
+	if err != nil {
+		return nil, err
+	}
+	err = impl.validatePolicy(request)
+	if err != nil {
+		return nil, err
+	}
+	policy.Name = request.Name
+	policy.ClusterId = request.ClusterId
+	policy.EnvironmentId = request.EnvironmentId
+	policy.AuditEnabled = request.AuditEnabled
+	policy.DenyPatterns = request.DenyPatterns
+	policy.UpdatedOn = time.Now()
+	policy.UpdatedBy = request.UserId
+	err = impl.terminalCommandPolicyRepository.UpdatePolicy(policy)
+	if err != nil {
+		impl.logger.Errorw("error in updating terminal command policy", "request", request, "err", err)
+		return nil, err
+	}
+	return getPolicyDto(policy), nil
+}
+
+func (impl *TerminalCommandPolicyServiceImpl) DeletePolicy(id int, userId int32) error {
+	policy, err := impl.getActivePolicy(id)
+	if err != nil {
+		return err
+	}
+	policy.Active = false
+	policy.UpdatedOn = time.Now()
+	policy.UpdatedBy = userId
+	err = impl.terminalCommandPolicyRepository.UpdatePolicy(policy)
+	if err != nil {
+		impl.logger.Errorw("error in deleting terminal command policy", "id", id, "err", err)
+		return err
+	}
+	return nil
+}
+
+func (impl *TerminalCommandPolicyServiceImpl) getActivePolicy(id int) (*repository.TerminalCommandPolicy, error) {
+	policy, err := impl.terminalCommandPolicyRepository.FindActivePolicyById(id)
+	if util.IsErrNoRows(err) {
+		return nil, util.NewApiError(http.StatusNotFound, bean.PolicyNotFoundMessage, bean.PolicyNotFoundMessage)
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching terminal command policy", "id", id, "err", err)
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (impl *TerminalCommandPolicyServiceImpl) validatePolicy(request *bean.TerminalCommandPolicyDto) error {
+	for _, pattern := range request.DenyPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			msg := fmt.Sprintf(bean.InvalidDenyPatternMessage, pattern, err.Error())
+			return util.NewApiError(http.StatusBadRequest, msg, msg)
+		}
+	}
+	if request.EnvironmentId > 0 {
+		environment, err := impl.environmentRepository.FindById(request.EnvironmentId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in fetching environment of terminal command policy", "environmentId", request.EnvironmentId, "err", err)
+			return err
+		}
+		if util.IsErrNoRows(err) || environment.ClusterId != request.ClusterId {
+			msg := fmt.Sprintf(bean.EnvironmentNotInClusterError, request.EnvironmentId, request.ClusterId)
+			return util.NewApiError(http.StatusBadRequest, msg, msg)
+		}
+	}
+	return nil
+}
+
+func (impl *TerminalCommandPolicyServiceImpl) GetCommandAudits(filter *repository.CommandAuditFilter) (*bean.TerminalCommandAuditListResponse, error) {
+	audits, totalCount, err := impl.terminalCommandPolicyRepository.FindCommandAudits(filter)
+	if err != nil {
+		return nil, err
+	}
+	var userIds []int32
+	for _, audit := range audits {
+		userIds = append(userIds, audit.UserId)
+	}
+	emailIds := make(map[int32]string)
+	if len(userIds) > 0 {
+		users, err := impl.userService.GetByIds(userIds)
+		if err != nil {
+			impl.logger.Errorw("error in fetching users of terminal command audits", "userIds", userIds, "err", err)
+			return nil, err
+		}
+		for _, userInfo := range users {
+			emailIds[userInfo.Id] = userInfo.EmailId
+		}
+	}
+	response := &bean.TerminalCommandAuditListResponse{
+		TotalCount: totalCount,
+		Commands:   make([]*bean.TerminalCommandAuditDto, 0, len(audits)),
+	}
+	for _, audit := range audits {
+		response.Commands = append(response.Commands, &bean.TerminalCommandAuditDto{
+			Id:            audit.Id,
+			SessionId:     audit.SessionId,
+			UserId:        audit.UserId,
+			EmailId:       emailIds[audit.UserId],
+			ClusterId:     audit.ClusterId,
+			EnvironmentId: audit.EnvironmentId,
+			Namespace:     audit.Namespace,
+			PodName:       audit.PodName,
+			ContainerName: audit.ContainerName,
+			Command:       audit.Command,
+			Blocked:       audit.Blocked,
+			PolicyId:      audit.PolicyId,
+			ExecutedOn:    audit.ExecutedOn,
+		})
+	}
+	return response, nil
+}
+
+func getPolicyDto(policy *repository.TerminalCommandPolicy) *bean.TerminalCommandPolicyDto {
+	denyPatterns := policy.DenyPatterns
+	if denyPatterns == nil {
+		denyPatterns = []string{}
+	}
+	return &bean.TerminalCommandPolicyDto{
+		Id:            policy.Id,
+		Name:          policy.Name,
+		ClusterId:     policy.ClusterId,
+		EnvironmentId: policy.EnvironmentId,
+		AuditEnabled:  policy.AuditEnabled,
+		DenyPatterns:  denyPatterns,
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import "time"
+
+const (
+	// CommandBlockedMessage is toasted in the terminal when a denylisted command is entered
+	CommandBlockedMessage        = "command %q is blocked by terminal command policy %q"
+	InvalidDenyPatternMessage    = "invalid deny pattern %q: %s"
+	EnvironmentNotInClusterError = "environment %d does not belong to cluster %d"
+	PolicyNotFoundMessage        = "terminal command policy not found"
+)
+
+// CommandSessionDetail identifies the terminal session whose commands are filtered
+type CommandSessionDetail struct {
+	SessionId     string
+	UserId        int32
+	ClusterId     int
+	EnvironmentId int
+	Namespace     string
+	PodName       string
+	ContainerName string
+}
+
+// TerminalCommandPolicyDto applies to all terminal sessions of the cluster, or only to the ones of the environment
+// when set. DenyPatterns are regular expressions matched against the entered command lines, whitespace collapsed
+type TerminalCommandPolicyDto struct {
+	Id            int      `json:"id"`
+	Name          string   `json:"name" validate:"required,max=250"`
+	ClusterId     int      `json:"clusterId" validate:"gt=0"`
+	EnvironmentId int      `json:"environmentId,omitempty" validate:"gte=0"`
+	AuditEnabled  bool     `json:"auditEnabled"`
+	DenyPatterns  []string `json:"denyPatterns" validate:"dive,required"`
+	UserId        int32    `json:"-"`
+}
+
+type TerminalCommandAuditDto struct {
+	Id            int       `json:"id"`
+	SessionId     string    `json:"sessionId"`
+	UserId        int32     `json:"userId"`
+	EmailId       string    `json:"emailId,omitempty"`
+	ClusterId     int       `json:"clusterId"`
+	EnvironmentId int       `json:"environmentId,omitempty"`
+	Namespace     string    `json:"namespace"`
+	PodName       string    `json:"podName"`
+	ContainerName string    `json:"containerName,omitempty"`
+	Command       string    `json:"command"`
+	Blocked       bool      `json:"blocked"`
+	PolicyId      int       `json:"policyId,omitempty"`
+	ExecutedOn    time.Time `json:"executedOn"`
+}
+
+type TerminalCommandAuditListResponse struct {
+	TotalCount int                        `json:"totalCount"`
+	Commands   []*TerminalCommandAuditDto `json:"commands"`
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type TerminalCommandPolicy struct {
+	tableName     struct{} `sql:"terminal_command_policy" pg:",discard_unknown_columns"`
+	Id            int      `sql:"id,pk"`
+	Name          string   `sql:"name,notnull"`
+	ClusterId     int      `sql:"cluster_id,notnull"`
+	EnvironmentId int      `sql:"environment_id"`
+	AuditEnabled  bool     `sql:"audit_enabled,notnull"`
+	DenyPatterns  []string `sql:"deny_patterns" pg:",array"`
+	Active        bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+type TerminalCommandAudit struct {
+	tableName     struct{}  `sql:"terminal_command_audit" pg:",discard_unknown_columns"`
+	Id            int       `sql:"id,pk"`
+	SessionId     string    `sql:"session_id,notnull"`
+	UserId        int32     `sql:"user_id,notnull"`
+	ClusterId     int       `sql:"cluster_id,notnull"`
+	EnvironmentId int       `sql:"environment_id"`
+	Namespace     string    `sql:"namespace,notnull"`
+	PodName       string    `sql:"pod_name,notnull"`
+	ContainerName string    `sql:"container_name"`
+	Command       string    `sql:"command,notnull"`
+	Blocked       bool      `sql:"blocked,notnull"`
+	PolicyId      int       `sql:"policy_id"`
+	ExecutedOn    time.Time `sql:"executed_on,notnull"`
+	sql.AuditLog
+}
+
+// CommandAuditFilter narrows down the audited commands, zero values are not filtered on
+type CommandAuditFilter struct {
+	UserId      int32
+	ClusterId   int
+	SessionId   string
+	BlockedOnly bool
+	From        *time.Time
+	To          *time.Time
+	Offset      int
+	Size        int
+}
+
+type TerminalCommandPolicyRepository interface {
+	FindActivePolicies() ([]*TerminalCommandPolicy, error)
+	FindActivePolicyById(id int) (*TerminalCommandPolicy, error)
+	// FindApplicablePolicies returns the cluster wide policies of the cluster along with the ones of the environment
+	FindApplicablePolicies(clusterId, environmentId int) ([]*TerminalCommandPolicy, error)
+	SavePolicy(policy *TerminalCommandPolicy) error
+	UpdatePolicy(policy *TerminalCommandPolicy) error
+	SaveCommandAudit(audit *TerminalCommandAudit) error
+	// FindCommandAudits returns a page of the audited commands matching the filter, latest first, with the total match count
+	FindCommandAudits(filter *CommandAuditFilter) ([]*TerminalCommandAudit, int, error)
+}
+
+type TerminalCommandPolicyRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewTerminalCommandPolicyRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *TerminalCommandPolicyRepositoryImpl {
+	return &TerminalCommandPolicyRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (repo *TerminalCommandPolicyRepositoryImpl) FindActivePolicies() ([]*TerminalCommandPolicy, error) {
+	var policies []*TerminalCommandPolicy
+	err := repo.dbConnection.Model(&policies).
+		Where("active = ?", true).
+		Order("id").
+		Select()
+	return policies, err
+}
+
+func (repo *TerminalCommandPolicyRepositoryImpl) FindActivePolicyById(id int) (*TerminalCommandPolicy, error) {
+	policy := &TerminalCommandPolicy{}
+	err := repo.dbConnection.Model(policy).
+		Where("id = ?", id).
+		Where("active = ?", true).
+		Select()
+	return policy, err
+}
+
+func (repo *TerminalCommandPolicyRepositoryImpl) FindApplicablePolicies(clusterId, environmentId int) ([]*TerminalCommandPolicy, error) {
+	var policies []*TerminalCommandPolicy
+	query := repo.dbConnection.Model(&policies).
+		Where("cluster_id = ?", clusterId).
+		Where("active = ?", true)
+	if environmentId > 0 {
+		query = query.Where("environment_id IS NULL OR environment_id = ?", environmentId)
+	} else {
+		query = query.Where("environment_id IS NULL")
+	}
+	err := query.Order("id").Select()
+	return policies, err
+}
+
+func (repo *TerminalCommandPolicyRepositoryImpl) SavePolicy(policy *TerminalCommandPolicy) error {
+	return repo.dbConnection.Insert(policy)
+}
+
+func (repo *TerminalCommandPolicyRepositoryImpl) UpdatePolicy(policy *TerminalCommandPolicy) error {
+	return repo.dbConnection.Update(policy)
+}
+
+func (repo *TerminalCommandPolicyRepositoryImpl) SaveCommandAudit(audit *TerminalCommandAudit) error {
+	return repo.dbConnection.Insert(audit)
+}
+
+func (repo *TerminalCommandPolicyRepositoryImpl) FindCommandAudits(filter *CommandAuditFilter) ([]*TerminalCommandAudit, int, error) {
+	var audits []*TerminalCommandAudit
+	query := repo.dbConnection.Model(&audits)
+	if filter.UserId > 0 {
+		query = query.Where("user_id = ?", filter.UserId)
+	}
+	if filter.ClusterId > 0 {
+		query = query.Where("cluster_id = ?", filter.ClusterId)
+	}
+	if len(filter.SessionId) > 0 {
+		query = query.Where("session_id = ?", filter.SessionId)
+	}
+	if filter.BlockedOnly {
+		query = query.Where("blocked = ?", true)
+	}
+	if filter.From != nil {
+		query = query.Where("executed_on >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("executed_on <= ?", *filter.To)
+	}
+	count, err := query.
+		Order("executed_on DESC").
+		Offset(filter.Offset).
+		Limit(filter.Size).
+		SelectAndCount()
+	if err != nil && err != pg.ErrNoRows {
+		repo.logger.Errorw("error in fetching terminal command audits", "filter", filter, "err", err)
+		return nil, 0, err
+	}
+	return audits, count, nil
+}
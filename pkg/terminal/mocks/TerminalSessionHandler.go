@@ -40,6 +40,11 @@ func (_m *TerminalSessionHandler) Close(sessionId string, statusCode uint32, msg
 	_m.Called(sessionId, statusCode, msg)
 }
 
+// CloseUserSessions provides a mock function with given fields: userId, statusCode, msg
+func (_m *TerminalSessionHandler) CloseUserSessions(userId int32, statusCode uint32, msg string) {
+	_m.Called(userId, statusCode, msg)
+}
+
 // GetTerminalSession provides a mock function with given fields: req
 func (_m *TerminalSessionHandler) GetTerminalSession(req *terminal.TerminalSessionRequest) (int, *terminal.TerminalMessage, error) {
 	ret := _m.Called(req)
@@ -0,0 +1,163 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/devtron-labs/devtron/pkg/terminal/recording/bean"
+	"io"
+	"math"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	asciicastOutputEvent = "o"
+	asciicastInputEvent  = "i"
+	asciicastResizeEvent = "r"
+)
+
+// AsciicastRecorder records a terminal session in the asciicast v2 format. Events are appended to a local file as
+// they happen, the header is written along with them by WriteRecording once the session is over as the terminal
+// size is only known after the first resize
+type AsciicastRecorder struct {
+	lock       sync.Mutex
+	detail     *bean.RecordingSessionDetail
+	startedOn  time.Time
+	width      uint16
+	height     uint16
+	eventsPath string
+	eventsFile *os.File
+	events     *bufio.Writer
+	eventCount int
+	closed     bool
+	onClose    func(recorder *AsciicastRecorder)
+}
+
+func newAsciicastRecorder(eventsPath string, detail *bean.RecordingSessionDetail, onClose func(recorder *AsciicastRecorder)) (*AsciicastRecorder, error) {
+	eventsFile, err := os.Create(eventsPath)
+	if err != nil {
+		return nil, err
+	}
+	return &AsciicastRecorder{
+		detail:     detail,
+		startedOn:  time.Now(),
+		eventsPath: eventsPath,
+		eventsFile: eventsFile,
+		events:     bufio.NewWriter(eventsFile),
+		onClose:    onClose,
+	}, nil
+}
+
+// RecordOutput records the output of the process, called for stdout and stderr
+func (recorder *AsciicastRecorder) RecordOutput(data []byte) {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+	recorder.writeEvent(asciicastOutputEvent, string(data))
+}
+
+// RecordInput records the keystrokes of the user
+func (recorder *AsciicastRecorder) RecordInput(data string) {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+	recorder.writeEvent(asciicastInputEvent, data)
+}
+
+func (recorder *AsciicastRecorder) RecordResize(cols, rows uint16) {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+	if recorder.width == 0 || recorder.height == 0 {
+		recorder.width, recorder.height = cols, rows
+	}
+	recorder.writeEvent(asciicastResizeEvent, fmt.Sprintf("%dx%d", cols, rows))
+}
+
+// writeEvent appends an event line, errors are not surfaced as they must not break the terminal session itself
+func (recorder *AsciicastRecorder) writeEvent(code string, data string) {
+	if recorder.closed {
+		return
+	}
+	elapsed := math.Round(time.Since(recorder.startedOn).Seconds()*1e6) / 1e6
+	line, err := json.Marshal([]interface{}{elapsed, code, data})
+	if err != nil {
+		return
+	}
+	_, _ = recorder.events.Write(append(line, '\n'))
+	recorder.eventCount++
+}
+
+// Close stops the recording and hands it over to onClose, it can be called more than once
+func (recorder *AsciicastRecorder) Close() {
+	recorder.lock.Lock()
+	if recorder.closed {
+		recorder.lock.Unlock()
+		return
+	}
+	recorder.closed = true
+	_ = recorder.events.Flush()
+	_ = recorder.eventsFile.Close()
+	recorder.lock.Unlock()
+	if recorder.onClose != nil {
+		go recorder.onClose(recorder)
+	}
+}
+
+func (recorder *AsciicastRecorder) EventCount() int {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+	return recorder.eventCount
+}
+
+// WriteRecording writes the complete asciicast recording, header followed by the events, to be called after Close
+func (recorder *AsciicastRecorder) WriteRecording(w io.Writer) error {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+	header := bean.AsciicastHeader{
+		Version:   bean.AsciicastVersion,
+		Width:     recorder.width,
+		Height:    recorder.height,
+		Timestamp: recorder.startedOn.Unix(),
+		Title:     fmt.Sprintf("%s/%s %s", recorder.detail.Namespace, recorder.detail.PodName, recorder.detail.ContainerName),
+		Env:       map[string]string{"SHELL": recorder.detail.Shell, "TERM": "xterm"},
+	}
+	if header.Width == 0 || header.Height == 0 {
+		header.Width, header.Height = bean.DefaultTerminalWidth, bean.DefaultTerminalHeight
+	}
+	headerLine, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(headerLine, '\n'))
+	if err != nil {
+		return err
+	}
+	eventsFile, err := os.Open(recorder.eventsPath)
+	if err != nil {
+		return err
+	}
+	defer eventsFile.Close()
+	_, err = io.Copy(w, eventsFile)
+	return err
+}
+
+// Cleanup removes the local events file
+func (recorder *AsciicastRecorder) Cleanup() error {
+	return os.Remove(recorder.eventsPath)
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package recording
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"github.com/devtron-labs/devtron/pkg/terminal/recording/bean"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+func TestAsciicastRecorder(t *testing.T) {
+	detail := &bean.RecordingSessionDetail{SessionId: "abc", Namespace: "default", PodName: "pod-1", ContainerName: "app", Shell: "bash"}
+	eventsPath := filepath.Join(t.TempDir(), "abc.events")
+
+	t.Run("header and events are written in asciicast v2 format", func(t *testing.T) {
+		closed := make(chan *AsciicastRecorder, 1)
+		recorder, err := newAsciicastRecorder(eventsPath, detail, func(recorder *AsciicastRecorder) {
+			closed <- recorder
+		})
+		assert.Nil(t, err)
+		recorder.RecordResize(120, 40)
+		recorder.RecordInput("ls\r")
+		recorder.RecordOutput([]byte("file.txt\r\n"))
+		recorder.RecordResize(100, 30)
+		recorder.Close()
+		recorder.Close()
+		assert.Equal(t, recorder, <-closed)
+		// events after close are dropped
+		recorder.RecordOutput([]byte("ignored"))
+		assert.Equal(t, 4, recorder.EventCount())
+
+		var buf bytes.Buffer
+		assert.Nil(t, recorder.WriteRecording(&buf))
+		scanner := bufio.NewScanner(&buf)
+		assert.True(t, scanner.Scan())
+		header := bean.AsciicastHeader{}
+		assert.Nil(t, json.Unmarshal(scanner.Bytes(), &header))
+		assert.Equal(t, bean.AsciicastVersion, header.Version)
+		assert.Equal(t, uint16(120), header.Width)
+		assert.Equal(t, uint16(40), header.Height)
+		assert.Equal(t, "default/pod-1 app", header.Title)
+		assert.Equal(t, "bash", header.Env["SHELL"])
+
+		var events [][]interface{}
+		for scanner.Scan() {
+			var event []interface{}
+			assert.Nil(t, json.Unmarshal(scanner.Bytes(), &event))
+			events = append(events, event)
+		}
+		assert.Len(t, events, 4)
+		assert.Equal(t, []interface{}{"r", "120x40"}, events[0][1:])
+		assert.Equal(t, []interface{}{"i", "ls\r"}, events[1][1:])
+		assert.Equal(t, []interface{}{"o", "file.txt\r\n"}, events[2][1:])
+		assert.Equal(t, []interface{}{"r", "100x30"}, events[3][1:])
+		assert.Nil(t, recorder.Cleanup())
+	})
+
+	t.Run("default terminal size is used when the session never got resized", func(t *testing.T) {
+		recorder, err := newAsciicastRecorder(eventsPath, detail, nil)
+		assert.Nil(t, err)
+		recorder.RecordOutput([]byte("$ "))
+		recorder.Close()
+
+		var buf bytes.Buffer
+		assert.Nil(t, recorder.WriteRecording(&buf))
+		header := bean.AsciicastHeader{}
+		assert.Nil(t, json.NewDecoder(&buf).Decode(&header))
+		assert.Equal(t, uint16(bean.DefaultTerminalWidth), header.Width)
+		assert.Equal(t, uint16(bean.DefaultTerminalHeight), header.Height)
+		assert.Nil(t, recorder.Cleanup())
+	})
+}
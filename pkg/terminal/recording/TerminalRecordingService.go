@@ -0,0 +1,376 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package recording
+
+import (
+	"fmt"
+	"github.com/caarlos0/env"
+	blob_storage "github.com/devtron-labs/common-lib/blob-storage"
+	"github.com/devtron-labs/devtron/internal/util"
+	"github.com/devtron-labs/devtron/pkg/auth/user"
+	environmentRepository "github.com/devtron-labs/devtron/pkg/cluster/environment/repository"
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/devtron-labs/devtron/pkg/terminal/recording/bean"
+	"github.com/devtron-labs/devtron/pkg/terminal/recording/repository"
+	"go.uber.org/zap"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+type TerminalRecordingService interface {
+	// StartRecording starts recording the session when its environment requires it, nil is returned otherwise
+	StartRecording(detail *bean.RecordingSessionDetail) (*AsciicastRecorder, error)
+	GetRecordings(filter *repository.RecordingFilter) (*bean.TerminalRecordingListResponse, error)
+	// GetRecordingForReplay downloads the asciicast recording, the cleanup func removes the downloaded file
+	GetRecordingForReplay(id int) (*os.File, func() error, error)
+	GetPolicy() (*bean.TerminalRecordingPolicyDto, error)
+	SavePolicy(request *bean.TerminalRecordingPolicyDto) (*bean.TerminalRecordingPolicyDto, error)
+}
+
+type TerminalRecordingServiceImpl struct {
+	logger                      *zap.SugaredLogger
+	terminalRecordingRepository repository.TerminalRecordingRepository
+	environmentRepository       environmentRepository.EnvironmentRepository
+	userService                 user.UserService
+	config                      *bean.TerminalRecordingConfig
+}
+
+func GetTerminalRecordingConfig() (*bean.TerminalRecordingConfig, error) {
+	config := &bean.TerminalRecordingConfig{}
+	err := env.Parse(config)
+	if err != nil {
+		return nil, err
+	}
+	return config, err
+}
+
+func NewTerminalRecordingServiceImpl(logger *zap.SugaredLogger,
+	terminalRecordingRepository repository.TerminalRecordingRepository,
+	environmentRepository environmentRepository.EnvironmentRepository,
+	userService user.UserService,
+	config *bean.TerminalRecordingConfig) *TerminalRecordingServiceImpl {
+	return &TerminalRecordingServiceImpl{
+		logger:                      logger,
+		terminalRecordingRepository: terminalRecordingRepository,
+		environmentRepository:       environmentRepository,
+		userService:                 userService,
+		config:                      config,
+	}
+}
+
+func (impl *TerminalRecordingServiceImpl) StartRecording(detail *bean.RecordingSessionDetail) (*AsciicastRecorder, error) {
+	if detail.EnvironmentId == 0 {
+		// cluster terminal sessions are matched to the environment of their namespace
+		environment, err := impl.environmentRepository.FindOneByNamespaceAndClusterId(detail.Namespace, detail.ClusterId)
+		if err != nil && !util.IsErrNoRows(err) {
+			impl.logger.Errorw("error in finding environment of terminal session", "clusterId", detail.ClusterId, "namespace", detail.Namespace, "err", err)
+			return nil, err
+		} else if err == nil {
+			detail.EnvironmentId = environment.Id
+		}
+	}
+	if detail.EnvironmentId == 0 {
+		return nil, nil
+	}
+	required, err := impl.terminalRecordingRepository.IsRecordingRequired(detail.EnvironmentId)
+	if err != nil {
+		impl.logger.Errorw("error in checking terminal recording policy", "environmentId", detail.EnvironmentId, "err", err)
+		return nil, err
+	} else if !required {
+		return nil, nil
+	}
+	if !impl.config.BlobStorageEnabled {
+		return nil, util.NewApiError(http.StatusPreconditionFailed, bean.RecordingStorageNotConfiguredMessage, bean.RecordingStorageNotConfiguredMessage)
+	}
+	err = os.MkdirAll(impl.config.RecordingLocalPath, os.ModePerm)
+	if err != nil {
+		impl.logger.Errorw("error in creating terminal recording directory", "path", impl.config.RecordingLocalPath, "err", err)
+		return nil, err
+	}
+	now := time.Now()
+	recording := &repository.TerminalSessionRecording{
+		SessionId:     detail.SessionId,
+		UserId:        detail.UserId,
+		ClusterId:     detail.ClusterId,
+		EnvironmentId: detail.EnvironmentId,
+		AppId:         detail.AppId,
+		Namespace:     detail.Namespace,
+		PodName:       detail.PodName,
+		ContainerName: detail.ContainerName,
+		Shell:         detail.Shell,
+		Status:        repository.RecordingStatusRecording,
+		StartedOn:     now,
+		AuditLog:      sql.AuditLog{CreatedOn: now, CreatedBy: detail.UserId, UpdatedOn: now, UpdatedBy: detail.UserId},
+	}
+	err = impl.terminalRecordingRepository.SaveRecording(recording)
+	if err != nil {
+		impl.logger.Errorw("error in saving terminal session recording", "sessionId", detail.SessionId, "err", err)
+		return nil, err
+	}
+	eventsPath := filepath.Join(impl.config.RecordingLocalPath, detail.SessionId+".events")
+	recorder, err := newAsciicastRecorder(eventsPath, detail, func(recorder *AsciicastRecorder) {
+		impl.finishRecording(recording, recorder)
+	})
+	if err != nil {
+		impl.logger.Errorw("error in creating terminal session recorder", "sessionId", detail.SessionId, "err", err)
+		impl.markRecordingFailed(recording, err)
+		return nil, err
+	}
+	return recorder, nil
+}
+
+// finishRecording uploads the recording of a closed session to blob storage
+func (impl *TerminalRecordingServiceImpl) finishRecording(recording *repository.TerminalSessionRecording, recorder *AsciicastRecorder) {
+	defer func() {
+		if err := recorder.Cleanup(); err != nil {
+			impl.logger.Warnw("error in removing terminal recording events file", "sessionId", recording.SessionId, "err", err)
+		}
+	}()
+	now := time.Now()
+	recording.EndedOn = &now
+	if recorder.EventCount() == 0 {
+		recording.Status = repository.RecordingStatusEmpty
+		impl.updateRecording(recording)
+		return
+	}
+	castPath := filepath.Join(impl.config.RecordingLocalPath, recording.SessionId+bean.AsciicastFileSuffix)
+	size, err := impl.writeCastFile(castPath, recorder)
+	if err == nil {
+		blobKey := impl.getBlobKey(recording)
+		err = blob_storage.NewBlobStorageServiceImpl(impl.logger).UploadToBlobWithSession(impl.getBlobStorageRequest(castPath, blobKey))
+		recording.BlobKey = blobKey
+		recording.SizeBytes = size
+	}
+	if removeErr := os.Remove(castPath); removeErr != nil && !os.IsNotExist(removeErr) {
+		impl.logger.Warnw("error in removing terminal recording file", "path", castPath, "err", removeErr)
+	}
+	if err != nil {
+		impl.logger.Errorw("error in uploading terminal session recording", "sessionId", recording.SessionId, "err", err)
+		impl.markRecordingFailed(recording, err)
+		return
+	}
+	recording.Status = repository.RecordingStatusUploaded
+	impl.updateRecording(recording)
+}
+
+func (impl *TerminalRecordingServiceImpl) writeCastFile(castPath string, recorder *AsciicastRecorder) (int64, error) {
+	castFile, err := os.Create(castPath)
+	if err != nil {
+		return 0, err
+	}
+	defer castFile.Close()
+	err = recorder.WriteRecording(castFile)
+	if err != nil {
+		return 0, err
+	}
+	fileInfo, err := castFile.Stat()
+	if err != nil {
+		return 0, err
+	}
+	return fileInfo.Size(), nil
+}
+
+func (impl *TerminalRecordingServiceImpl) markRecordingFailed(recording *repository.TerminalSessionRecording, err error) {
+	recording.Status = repository.RecordingStatusFailed
+	recording.Error = err.Error()
+	impl.updateRecording(recording)
+}
+
+func (impl *TerminalRecordingServiceImpl) updateRecording(recording *repository.TerminalSessionRecording) {
+	recording.UpdatedOn = time.Now()
+	err := impl.terminalRecordingRepository.UpdateRecording(recording)
+	if err != nil {
+		impl.logger.Errorw("error in updating terminal session recording", "sessionId", recording.SessionId, "status", recording.Status, "err", err)
+	}
+}
+
+// getBlobKey partitions the recordings by day of the session start
+func (impl *TerminalRecordingServiceImpl) getBlobKey(recording *repository.TerminalSessionRecording) string {
+	return path.Join(impl.config.RecordingKeyPrefix, recording.StartedOn.UTC().Format("2006/01/02"), recording.SessionId+bean.AsciicastFileSuffix)
+}
+
+func (impl *TerminalRecordingServiceImpl) getBlobStorageRequest(sourceKey, destinationKey string) *blob_storage.BlobStorageRequest {
+	return &blob_storage.BlobStorageRequest{
+		StorageType:    impl.config.CloudProvider,
+		SourceKey:      sourceKey,
+		DestinationKey: destinationKey,
+		AwsS3BaseConfig: &blob_storage.AwsS3BaseConfig{
+			AccessKey:         impl.config.BlobStorageS3AccessKey,
+			Passkey:           impl.config.BlobStorageS3SecretKey,
+			EndpointUrl:       impl.config.BlobStorageS3Endpoint,
+			IsInSecure:        impl.config.BlobStorageS3EndpointInsecure,
+			BucketName:        impl.config.BuildLogsBucket,
+			Region:            impl.config.BucketRegion,
+			VersioningEnabled: impl.config.BlobStorageS3BucketVersioned,
+		},
+		AzureBlobBaseConfig: &blob_storage.AzureBlobBaseConfig{
+			Enabled:           impl.config.CloudProvider == blob_storage.BLOB_STORAGE_AZURE,
+			AccountName:       impl.config.AzureAccountName,
+			AccountKey:        impl.config.AzureAccountKey,
+			BlobContainerName: impl.config.AzureBlobContainerCiLog,
+		},
+		GcpBlobBaseConfig: &blob_storage.GcpBlobBaseConfig{
+			BucketName:             impl.config.BuildLogsBucket,
+			CredentialFileJsonData: impl.config.BlobStorageGcpCredentialJson,
+		},
+	}
+}
+
+func (impl *TerminalRecordingServiceImpl) GetRecordings(filter *repository.RecordingFilter) (*bean.TerminalRecordingListResponse, error) {
+	recordings, totalCount, err := impl.terminalRecordingRepository.FindRecordings(filter)
+	if err != nil {
+		return nil, err
+	}
+	var userIds []int32
+	for _, recording := range recordings {
+		userIds = append(userIds, recording.UserId)
+	}
+	emailIds := make(map[int32]string)
+	if len(userIds) > 0 {
+		users, err := impl.userService.GetByIds(userIds)
+		if err != nil {
+			impl.logger.Errorw("error in fetching users of terminal session recordings", "userIds", userIds, "err", err)
+			return nil, err
+		}
+		for _, userInfo := range users {
+			emailIds[userInfo.Id] = userInfo.EmailId
+		}
+	}
+	response := &bean.TerminalRecordingListResponse{
+		TotalCount: totalCount,
+		Recordings: make([]*bean.TerminalRecordingDto, 0, len(recordings)),
+	}
+	for _, recording := range recordings {
+		response.Recordings = append(response.Recordings, &bean.TerminalRecordingDto{
+			Id:            recording.Id,
+			SessionId:     recording.SessionId,
+			UserId:        recording.UserId,
+			EmailId:       emailIds[recording.UserId],
+			ClusterId:     recording.ClusterId,
+			EnvironmentId: recording.EnvironmentId,
+			AppId:         recording.AppId,
+			Namespace:     recording.Namespace,
+			PodName:       recording.PodName,
+			ContainerName: recording.ContainerName,
+			Shell:         recording.Shell,
+			Status:        recording.Status,
+			SizeBytes:     recording.SizeBytes,
+			Error:         recording.Error,
+			StartedOn:     recording.StartedOn,
+			EndedOn:       recording.EndedOn,
+		})
+	}
+	return response, nil
+}
+
+func (impl *TerminalRecordingServiceImpl) GetRecordingForReplay(id int) (*os.File, func() error, error) {
+	recording, err := impl.terminalRecordingRepository.FindRecordingById(id)
+	if util.IsErrNoRows(err) {
+		return nil, nil, util.NewApiError(http.StatusNotFound, "terminal session recording not found", "terminal session recording not found")
+	} else if err != nil {
+		impl.logger.Errorw("error in fetching terminal session recording", "id", id, "err", err)
+		return nil, nil, err
+	}
+	if recording.Status != repository.RecordingStatusUploaded {
+		return nil, nil, util.NewApiError(http.StatusNotFound, bean.RecordingNotAvailableMessage, fmt.Sprintf("%s, status %s", bean.RecordingNotAvailableMessage, recording.Status))
+	}
+	err = os.MkdirAll(impl.config.RecordingLocalPath, os.ModePerm)
+	if err != nil {
+		return nil, nil, err
+	}
+	localPath := filepath.Join(impl.config.RecordingLocalPath, fmt.Sprintf("replay-%d-%d%s", recording.Id, time.Now().UnixNano(), bean.AsciicastFileSuffix))
+	_, _, err = blob_storage.NewBlobStorageServiceImpl(impl.logger).Get(impl.getBlobStorageRequest(recording.BlobKey, localPath))
+	if err != nil {
+		impl.logger.Errorw("error in downloading terminal session recording", "id", id, "blobKey", recording.BlobKey, "err", err)
+		_ = os.Remove(localPath)
+		return nil, nil, err
+	}
+	file, err := os.Open(localPath)
+	if err != nil {
+		_ = os.Remove(localPath)
+		return nil, nil, err
+	}
+	cleanUpFunc := func() error {
+		fErr := file.Close()
+		if rErr := os.Remove(localPath); rErr != nil {
+			return rErr
+		}
+		return fErr
+	}
+	return file, cleanUpFunc, nil
+}
+
+func (impl *TerminalRecordingServiceImpl) GetPolicy() (*bean.TerminalRecordingPolicyDto, error) {
+	policies, err := impl.terminalRecordingRepository.FindActivePolicies()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching terminal recording policy", "err", err)
+		return nil, err
+	}
+	response := &bean.TerminalRecordingPolicyDto{EnvironmentIds: make([]int, 0, len(policies))}
+	for _, policy := range policies {
+		response.EnvironmentIds = append(response.EnvironmentIds, policy.EnvironmentId)
+	}
+	return response, nil
+}
+
+// SavePolicy replaces the environments requiring recording with the requested ones
+func (impl *TerminalRecordingServiceImpl) SavePolicy(request *bean.TerminalRecordingPolicyDto) (*bean.TerminalRecordingPolicyDto, error) {
+	policies, err := impl.terminalRecordingRepository.FindActivePolicies()
+	if err != nil && !util.IsErrNoRows(err) {
+		impl.logger.Errorw("error in fetching terminal recording policy", "err", err)
+		return nil, err
+	}
+	requested := make(map[int]bool, len(request.EnvironmentIds))
+	for _, environmentId := range request.EnvironmentIds {
+		requested[environmentId] = true
+	}
+	now := time.Now()
+	for _, policy := range policies {
+		if requested[policy.EnvironmentId] {
+			delete(requested, policy.EnvironmentId)
+			continue
+		}
+		policy.Active = false
+		policy.UpdatedOn = now
+		policy.UpdatedBy = request.UserId
+		err = impl.terminalRecordingRepository.UpdatePolicy(policy)
+		if err != nil {
+			impl.logger.Errorw("error in removing environment from terminal recording policy", "environmentId", policy.EnvironmentId, "err", err)
+			return nil, err
+		}
+	}
+	for _, environmentId := range request.EnvironmentIds {
+		if !requested[environmentId] {
+			continue
+		}
+		delete(requested, environmentId)
+		policy := &repository.TerminalRecordingPolicy{
+			EnvironmentId: environmentId,
+			Active:        true,
+			AuditLog:      sql.AuditLog{CreatedOn: now, CreatedBy: request.UserId, UpdatedOn: now, UpdatedBy: request.UserId},
+		}
+		err = impl.terminalRecordingRepository.SavePolicy(policy)
+		if err != nil {
+			impl.logger.Errorw("error in adding environment to terminal recording policy", "environmentId", environmentId, "err", err)
+			return nil, err
+		}
+	}
+	return impl.GetPolicy()
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bean
+
+import (
+	blob_storage "github.com/devtron-labs/common-lib/blob-storage"
+	"github.com/devtron-labs/devtron/pkg/terminal/recording/repository"
+	"time"
+)
+
+const (
+	AsciicastVersion     = 2
+	AsciicastContentType = "application/x-asciicast"
+	AsciicastFileSuffix  = ".cast"
+	// default terminal size of the recording header when the session never got resized
+	DefaultTerminalWidth  = 80
+	DefaultTerminalHeight = 24
+)
+
+const (
+	RecordingStorageNotConfiguredMessage = "terminal sessions of this environment have to be recorded but blob storage is not configured"
+	RecordingNotAvailableMessage         = "recording of this terminal session is not available for replay"
+)
+
+// TerminalRecordingConfig reuses the blob storage configuration of the ci logs, recordings are stored in the build logs bucket
+type TerminalRecordingConfig struct {
+	BlobStorageEnabled            bool                         `env:"BLOB_STORAGE_ENABLED" envDefault:"false"`
+	CloudProvider                 blob_storage.BlobStorageType `env:"BLOB_STORAGE_PROVIDER" envDefault:"S3"`
+	BlobStorageS3AccessKey        string                       `env:"BLOB_STORAGE_S3_ACCESS_KEY"`
+	BlobStorageS3SecretKey        string                       `env:"BLOB_STORAGE_S3_SECRET_KEY"`
+	BlobStorageS3Endpoint         string                       `env:"BLOB_STORAGE_S3_ENDPOINT"`
+	BlobStorageS3EndpointInsecure bool                         `env:"BLOB_STORAGE_S3_ENDPOINT_INSECURE" envDefault:"false"`
+	BlobStorageS3BucketVersioned  bool                         `env:"BLOB_STORAGE_S3_BUCKET_VERSIONED" envDefault:"true"`
+	BlobStorageGcpCredentialJson  string                       `env:"BLOB_STORAGE_GCP_CREDENTIALS_JSON"`
+	AzureAccountName              string                       `env:"AZURE_ACCOUNT_NAME"`
+	AzureAccountKey               string                       `env:"AZURE_ACCOUNT_KEY"`
+	AzureBlobContainerCiLog       string                       `env:"AZURE_BLOB_CONTAINER_CI_LOG"`
+	BuildLogsBucket               string                       `env:"DEFAULT_BUILD_LOGS_BUCKET" envDefault:"devtron-pro-ci-logs"`
+	BucketRegion                  string                       `env:"DEFAULT_CACHE_BUCKET_REGION" envDefault:"us-east-2"`
+	RecordingKeyPrefix            string                       `env:"TERMINAL_RECORDING_KEY_PREFIX" envDefault:"terminal-recordings"`
+	// RecordingLocalPath holds the recordings of the running sessions until they get uploaded
+	RecordingLocalPath string `env:"TERMINAL_RECORDING_LOCAL_PATH" envDefault:"/tmp/terminal-recordings"`
+}
+
+// RecordingSessionDetail identifies the terminal session being recorded
+type RecordingSessionDetail struct {
+	SessionId     string
+	UserId        int32
+	ClusterId     int
+	EnvironmentId int
+	AppId         int
+	Namespace     string
+	PodName       string
+	ContainerName string
+	Shell         string
+}
+
+type TerminalRecordingDto struct {
+	Id            int                        `json:"id"`
+	SessionId     string                     `json:"sessionId"`
+	UserId        int32                      `json:"userId"`
+	EmailId       string                     `json:"emailId,omitempty"`
+	ClusterId     int                        `json:"clusterId"`
+	EnvironmentId int                        `json:"environmentId,omitempty"`
+	AppId         int                        `json:"appId,omitempty"`
+	Namespace     string                     `json:"namespace"`
+	PodName       string                     `json:"podName"`
+	ContainerName string                     `json:"containerName,omitempty"`
+	Shell         string                     `json:"shell,omitempty"`
+	Status        repository.RecordingStatus `json:"status"`
+	SizeBytes     int64                      `json:"sizeBytes"`
+	Error         string                     `json:"error,omitempty"`
+	StartedOn     time.Time                  `json:"startedOn"`
+	EndedOn       *time.Time                 `json:"endedOn,omitempty"`
+}
+
+type TerminalRecordingListResponse struct {
+	TotalCount int                     `json:"totalCount"`
+	Recordings []*TerminalRecordingDto `json:"recordings"`
+}
+
+// TerminalRecordingPolicyDto lists the environments whose app pod and cluster terminal sessions are recorded
+type TerminalRecordingPolicyDto struct {
+	EnvironmentIds []int `json:"environmentIds" validate:"dive,gt=0"`
+	UserId         int32 `json:"-"`
+}
+
+// AsciicastHeader is the first line of an asciicast v2 recording
+type AsciicastHeader struct {
+	Version   int               `json:"version"`
+	Width     uint16            `json:"width"`
+	Height    uint16            `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright (c) 2024. Devtron Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repository
+
+import (
+	"github.com/devtron-labs/devtron/pkg/sql"
+	"github.com/go-pg/pg"
+	"go.uber.org/zap"
+	"time"
+)
+
+type RecordingStatus string
+
+const (
+	RecordingStatusRecording RecordingStatus = "Recording"
+	RecordingStatusUploaded  RecordingStatus = "Uploaded"
+	RecordingStatusFailed    RecordingStatus = "Failed"
+	// RecordingStatusEmpty is for sessions closed before any terminal output, nothing gets uploaded for them
+	RecordingStatusEmpty RecordingStatus = "Empty"
+)
+
+type TerminalSessionRecording struct {
+	tableName     struct{}        `sql:"terminal_session_recording" pg:",discard_unknown_columns"`
+	Id            int             `sql:"id,pk"`
+	SessionId     string          `sql:"session_id,notnull"`
+	UserId        int32           `sql:"user_id,notnull"`
+	ClusterId     int             `sql:"cluster_id,notnull"`
+	EnvironmentId int             `sql:"environment_id"`
+	AppId         int             `sql:"app_id"`
+	Namespace     string          `sql:"namespace,notnull"`
+	PodName       string          `sql:"pod_name,notnull"`
+	ContainerName string          `sql:"container_name"`
+	Shell         string          `sql:"shell"`
+	Status        RecordingStatus `sql:"status,notnull"`
+	BlobKey       string          `sql:"blob_key"`
+	SizeBytes     int64           `sql:"size_bytes"`
+	Error         string          `sql:"error"`
+	StartedOn     time.Time       `sql:"started_on,notnull"`
+	EndedOn       *time.Time      `sql:"ended_on"`
+	sql.AuditLog
+}
+
+type TerminalRecordingPolicy struct {
+	tableName     struct{} `sql:"terminal_recording_policy" pg:",discard_unknown_columns"`
+	Id            int      `sql:"id,pk"`
+	EnvironmentId int      `sql:"environment_id,notnull"`
+	Active        bool     `sql:"active,notnull"`
+	sql.AuditLog
+}
+
+// RecordingFilter narrows down the recordings, zero values are not filtered on
+type RecordingFilter struct {
+	UserId    int32
+	ClusterId int
+	Namespace string
+	PodName   string
+	From      *time.Time
+	To        *time.Time
+	Offset    int
+	Size      int
+}
+
+type TerminalRecordingRepository interface {
+	SaveRecording(recording *TerminalSessionRecording) error
+	UpdateRecording(recording *TerminalSessionRecording) error
+	FindRecordingById(id int) (*TerminalSessionRecording, error)
+	// FindRecordings returns a page of the recordings matching the filter, latest first, with the total match count
+	FindRecordings(filter *RecordingFilter) ([]*TerminalSessionRecording, int, error)
+	FindActivePolicies() ([]*TerminalRecordingPolicy, error)
+	IsRecordingRequired(environmentId int) (bool, error)
+	SavePolicy(policy *TerminalRecordingPolicy) error
+	UpdatePolicy(policy *TerminalRecordingPolicy) error
+}
+
+type TerminalRecordingRepositoryImpl struct {
+	dbConnection *pg.DB
+	logger       *zap.SugaredLogger
+}
+
+func NewTerminalRecordingRepositoryImpl(dbConnection *pg.DB, logger *zap.SugaredLogger) *TerminalRecordingRepositoryImpl {
+	return &TerminalRecordingRepositoryImpl{
+		dbConnection: dbConnection,
+		logger:       logger,
+	}
+}
+
+func (repo *TerminalRecordingRepositoryImpl) SaveRecording(recording *TerminalSessionRecording) error {
+	return repo.dbConnection.Insert(recording)
+}
+
+func (repo *TerminalRecordingRepositoryImpl) UpdateRecording(recording *TerminalSessionRecording) error {
+	return repo.dbConnection.Update(recording)
+}
+
+func (repo *TerminalRecordingRepositoryImpl) FindRecordingById(id int) (*TerminalSessionRecording, error) {
+	recording := &TerminalSessionRecording{}
+	err := repo.dbConnection.Model(recording).
+		Where("id = ?", id).
+		Select()
+	return recording, err
+}
+
+func (repo *TerminalRecordingRepositoryImpl) FindRecordings(filter *RecordingFilter) ([]*TerminalSessionRecording, int, error) {
+	var recordings []*TerminalSessionRecording
+	query := repo.dbConnection.Model(&recordings)
+	if filter.UserId > 0 {
+		query = query.Where("user_id = ?", filter.UserId)
+	}
+	if filter.ClusterId > 0 {
+		query = query.Where("cluster_id = ?", filter.ClusterId)
+	}
+	if len(filter.Namespace) > 0 {
+		query = query.Where("namespace = ?", filter.Namespace)
+	}
+	if len(filter.PodName) > 0 {
+		query = query.Where("pod_name LIKE ?", "%"+filter.PodName+"%")
+	}
+	if filter.From != nil {
+		query = query.Where("started_on >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("started_on <= ?", *filter.To)
+	}
+	count, err := query.
+		Order("started_on DESC").
+		Offset(filter.Offset).
+		Limit(filter.Size).
+		SelectAndCount()
+	if err != nil && err != pg.ErrNoRows {
+		repo.logger.Errorw("error in fetching terminal session recordings", "filter", filter, "err", err)
+		return nil, 0, err
+	}
+	return recordings, count, nil
+}
+
+func (repo *TerminalRecordingRepositoryImpl) FindActivePolicies() ([]*TerminalRecordingPolicy, error) {
+	var policies []*TerminalRecordingPolicy
+	err := repo.dbConnection.Model(&policies).
+		Where("active = ?", true).
+		Order("environment_id").
+		Select()
+	return policies, err
+}
+
+func (repo *TerminalRecordingRepositoryImpl) IsRecordingRequired(environmentId int) (bool, error) {
+	return repo.dbConnection.Model(&TerminalRecordingPolicy{}).
+		Where("environment_id = ?", environmentId).
+		Where("active = ?", true).
+		Exists()
+}
+
+func (repo *TerminalRecordingRepositoryImpl) SavePolicy(policy *TerminalRecordingPolicy) error {
+	return repo.dbConnection.Insert(policy)
+}
+
+func (repo *TerminalRecordingRepositoryImpl) UpdatePolicy(policy *TerminalRecordingPolicy) error {
+	return repo.dbConnection.Update(policy)
+}
@@ -26,13 +26,19 @@ import (
 	"github.com/caarlos0/env"
 	"github.com/devtron-labs/common-lib/utils/k8s"
 	"github.com/devtron-labs/devtron/internal/middleware"
+	"github.com/devtron-labs/devtron/internal/util"
 	"github.com/devtron-labs/devtron/pkg/argoApplication/read/config"
 	"github.com/devtron-labs/devtron/pkg/cluster"
 	"github.com/devtron-labs/devtron/pkg/cluster/bean"
+	"github.com/devtron-labs/devtron/pkg/cluster/clusterCredentials"
 	"github.com/devtron-labs/devtron/pkg/cluster/environment"
 	bean2 "github.com/devtron-labs/devtron/pkg/cluster/environment/bean"
 	"github.com/devtron-labs/devtron/pkg/cluster/read"
 	"github.com/devtron-labs/devtron/pkg/cluster/repository"
+	"github.com/devtron-labs/devtron/pkg/terminal/commandPolicy"
+	commandPolicyBean "github.com/devtron-labs/devtron/pkg/terminal/commandPolicy/bean"
+	"github.com/devtron-labs/devtron/pkg/terminal/recording"
+	recordingBean "github.com/devtron-labs/devtron/pkg/terminal/recording/bean"
 	errors1 "github.com/juju/errors"
 	"go.uber.org/zap"
 	"io"
@@ -75,7 +81,10 @@ type TerminalSession struct {
 	podName           string
 	namespace         string
 	clusterId         string
+	userId            int32
 	startedOn         time.Time
+	recorder          *recording.AsciicastRecorder
+	commandFilter     *commandPolicy.CommandFilter
 }
 
 // TerminalMessage is the messaging protocol between ShellController and TerminalSession.
@@ -119,8 +128,24 @@ func (t TerminalSession) Read(p []byte) (int, error) {
 
 	switch msg.Op {
 	case "stdin":
-		return copy(p, msg.Data), nil
+		if t.recorder != nil {
+			t.recorder.RecordInput(msg.Data)
+		}
+		data := msg.Data
+		if t.commandFilter != nil {
+			var blockedCommands []*commandPolicy.BlockedCommand
+			data, blockedCommands = t.commandFilter.Filter(msg.Data)
+			for _, blockedCommand := range blockedCommands {
+				if err := t.Toast(fmt.Sprintf(commandPolicyBean.CommandBlockedMessage, blockedCommand.Command, blockedCommand.PolicyName)); err != nil {
+					log.Printf("error in sending blocked command toast, sessionId: %s, err: %v", t.id, err)
+				}
+			}
+		}
+		return copy(p, data), nil
 	case "resize":
+		if t.recorder != nil {
+			t.recorder.RecordResize(msg.Cols, msg.Rows)
+		}
 		t.sizeChan <- remotecommand.TerminalSize{Width: msg.Cols, Height: msg.Rows}
 		return 0, nil
 	default:
@@ -142,6 +167,9 @@ func (t TerminalSession) Write(p []byte) (int, error) {
 	if err = t.sockJSSession.Send(string(msg)); err != nil {
 		return 0, err
 	}
+	if t.recorder != nil {
+		t.recorder.RecordOutput(p)
+	}
 	return len(p), nil
 }
 
@@ -224,6 +252,9 @@ func (sm *SessionMap) Close(sessionId string, status uint32, reason string) {
 	defer sm.Lock.Unlock()
 
 	terminalSession := sm.Sessions[sessionId]
+	if terminalSession.recorder != nil {
+		terminalSession.recorder.Close()
+	}
 
 	if terminalSession.sockJSSession != nil {
 
@@ -244,6 +275,30 @@ func (sm *SessionMap) Close(sessionId string, status uint32, reason string) {
 
 }
 
+// CloseUserSessions closes every terminal session opened by the user, sessions not bound yet are cancelled so the
+// user can not attach to them anymore
+func (sm *SessionMap) CloseUserSessions(userId int32, status uint32, reason string) {
+	sm.Lock.RLock()
+	sessionIds := make([]string, 0)
+	for sessionId, terminalSession := range sm.Sessions {
+		if terminalSession.userId == userId {
+			sessionIds = append(sessionIds, sessionId)
+		}
+	}
+	sm.Lock.RUnlock()
+
+	for _, sessionId := range sessionIds {
+		terminalSession := sm.Get(sessionId)
+		if terminalSession.sockJSSession == nil {
+			if terminalSession.contextCancelFunc != nil {
+				terminalSession.contextCancelFunc()
+			}
+			continue
+		}
+		sm.Close(sessionId, status, reason)
+	}
+}
+
 func isConnectionClosedByError(status uint32) bool {
 	if status == 2 {
 		return true
@@ -444,6 +499,8 @@ func WaitForTerminal(k8sClient kubernetes.Interface, cfg *rest.Config, request *
 type TerminalSessionHandler interface {
 	GetTerminalSession(req *TerminalSessionRequest) (statusCode int, message *TerminalMessage, err error)
 	Close(sessionId string, statusCode uint32, msg string)
+	// CloseUserSessions closes all terminal sessions of the user, used when the user is removed
+	CloseUserSessions(userId int32, statusCode uint32, msg string)
 	ValidateSession(sessionId string) bool
 	ValidateShell(req *TerminalSessionRequest) (bool, error)
 	AutoSelectShell(req *TerminalSessionRequest) (string, error)
@@ -457,12 +514,18 @@ type TerminalSessionHandlerImpl struct {
 	ephemeralContainerService    cluster.EphemeralContainerService
 	argoApplicationConfigService config.ArgoApplicationConfigService
 	ClusterReadService           read.ClusterReadService
+	terminalRecordingService     recording.TerminalRecordingService
+	terminalCommandPolicyService commandPolicy.TerminalCommandPolicyService
+	clusterCredentialService     clusterCredentials.ClusterCredentialService
 }
 
 func NewTerminalSessionHandlerImpl(environmentService environment.EnvironmentService,
 	logger *zap.SugaredLogger, k8sUtil *k8s.K8sServiceImpl, ephemeralContainerService cluster.EphemeralContainerService,
 	argoApplicationConfigService config.ArgoApplicationConfigService,
-	ClusterReadService read.ClusterReadService) *TerminalSessionHandlerImpl {
+	ClusterReadService read.ClusterReadService,
+	terminalRecordingService recording.TerminalRecordingService,
+	terminalCommandPolicyService commandPolicy.TerminalCommandPolicyService,
+	clusterCredentialService clusterCredentials.ClusterCredentialService) *TerminalSessionHandlerImpl {
 	return &TerminalSessionHandlerImpl{
 		environmentService:           environmentService,
 		logger:                       logger,
@@ -470,6 +533,9 @@ func NewTerminalSessionHandlerImpl(environmentService environment.EnvironmentSer
 		ephemeralContainerService:    ephemeralContainerService,
 		argoApplicationConfigService: argoApplicationConfigService,
 		ClusterReadService:           ClusterReadService,
+		terminalRecordingService:     terminalRecordingService,
+		terminalCommandPolicyService: terminalCommandPolicyService,
+		clusterCredentialService:     clusterCredentialService,
 	}
 }
 
@@ -477,6 +543,10 @@ func (impl *TerminalSessionHandlerImpl) Close(sessionId string, statusCode uint3
 	terminalSessions.Close(sessionId, statusCode, msg)
 }
 
+func (impl *TerminalSessionHandlerImpl) CloseUserSessions(userId int32, statusCode uint32, msg string) {
+	terminalSessions.CloseUserSessions(userId, statusCode, msg)
+}
+
 func (impl *TerminalSessionHandlerImpl) ValidateSession(sessionId string) bool {
 	if sessionId == "" {
 		return false
@@ -501,6 +571,41 @@ func (impl *TerminalSessionHandlerImpl) GetTerminalSession(req *TerminalSessionR
 		return statusCode, nil, err
 	}
 	req.SessionId = sessionID
+	recorder, err := impl.terminalRecordingService.StartRecording(&recordingBean.RecordingSessionDetail{
+		SessionId:     sessionID,
+		UserId:        req.UserId,
+		ClusterId:     req.ClusterId,
+		EnvironmentId: req.EnvironmentId,
+		AppId:         req.AppId,
+		Namespace:     req.Namespace,
+		PodName:       req.PodName,
+		ContainerName: req.ContainerName,
+		Shell:         req.Shell,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in starting terminal session recording", "podName", req.PodName, "namespace", req.Namespace, "err", err)
+		statusCode := http.StatusInternalServerError
+		if apiErr, ok := err.(*util.ApiError); ok {
+			statusCode = apiErr.HttpStatusCode
+		}
+		return statusCode, nil, err
+	}
+	commandFilter, err := impl.terminalCommandPolicyService.GetCommandFilter(&commandPolicyBean.CommandSessionDetail{
+		SessionId:     sessionID,
+		UserId:        req.UserId,
+		ClusterId:     req.ClusterId,
+		EnvironmentId: req.EnvironmentId,
+		Namespace:     req.Namespace,
+		PodName:       req.PodName,
+		ContainerName: req.ContainerName,
+	})
+	if err != nil {
+		impl.logger.Errorw("error in fetching terminal command filter", "podName", req.PodName, "namespace", req.Namespace, "err", err)
+		if recorder != nil {
+			recorder.Close()
+		}
+		return http.StatusInternalServerError, nil, err
+	}
 	sessionCtx, cancelFunc := context.WithCancel(context.Background())
 	terminalSessions.Set(sessionID, TerminalSession{
 		id:                sessionID,
@@ -512,6 +617,9 @@ func (impl *TerminalSessionHandlerImpl) GetTerminalSession(req *TerminalSessionR
 		podName:           req.PodName,
 		namespace:         req.Namespace,
 		clusterId:         strconv.Itoa(req.ClusterId),
+		userId:            req.UserId,
+		recorder:          recorder,
+		commandFilter:     commandFilter,
 	})
 	config, client, err := impl.getClientSetAndRestConfigForTerminalConn(req)
 
@@ -524,6 +632,9 @@ func (impl *TerminalSessionHandlerImpl) GetTerminalSession(req *TerminalSessionR
 
 	if err != nil {
 		impl.logger.Errorw("error in fetching config", "err", err)
+		if recorder != nil {
+			recorder.Close()
+		}
 		return http.StatusInternalServerError, nil, err
 	}
 	go WaitForTerminal(client, config, req)
@@ -565,7 +676,11 @@ func (impl *TerminalSessionHandlerImpl) getClientSetAndRestConfigForTerminalConn
 			return nil, nil, fmt.Errorf("not able to find cluster-config")
 		}
 
-		clusterConfig = clusterBean.GetClusterConfig()
+		clusterConfig, err = clusterBean.ResolveClusterConfig(impl.clusterCredentialService)
+		if err != nil {
+			impl.logger.Errorw("error in resolving cluster credentials", "err", err, "clusterId", clusterBean.Id)
+			return nil, nil, err
+		}
 		restConfig, err = impl.k8sUtil.GetRestConfigByCluster(clusterConfig)
 		if err != nil {
 			impl.logger.Errorw("error in getting rest config by cluster", "err", err, "clusterName", clusterConfig.ClusterName)
@@ -668,7 +783,11 @@ func (impl *TerminalSessionHandlerImpl) saveEphemeralContainerTerminalAccessAudi
 			impl.logger.Errorw("error occurred in finding clusterBean by Id", "clusterId", req.ClusterId, "err", err)
 			return err
 		}
-		clusterConfig := clusterBean.GetClusterConfig()
+		clusterConfig, err := clusterBean.ResolveClusterConfig(impl.clusterCredentialService)
+		if err != nil {
+			impl.logger.Errorw("error in resolving cluster credentials", "err", err, "clusterId", req.ClusterId)
+			return err
+		}
 		restConfig, err = impl.k8sUtil.GetRestConfigByCluster(clusterConfig)
 		if err != nil {
 			impl.logger.Errorw("error in getting rest config", "err", err, "clusterId", req.ClusterId, "externalArgoApplicationName", req.ExternalArgoApplicationName)
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_deployment_window_override_audit_pipeline_id;
+DROP TABLE IF EXISTS public.deployment_window_override_audit;
+DROP SEQUENCE IF EXISTS id_seq_deployment_window_override_audit;
+DROP TABLE IF EXISTS public.deployment_window;
+DROP SEQUENCE IF EXISTS id_seq_deployment_window;
+
+COMMIT;
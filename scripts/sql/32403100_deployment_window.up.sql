@@ -0,0 +1,51 @@
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_window;
+
+CREATE TABLE IF NOT EXISTS public.deployment_window
+(
+    "id"          int4         NOT NULL DEFAULT nextval('id_seq_deployment_window'::regclass),
+    "name"        varchar(100) NOT NULL,
+    "description" text,
+    "type"        varchar(20)  NOT NULL,
+    "frequency"   varchar(20)  NOT NULL,
+    "start_time"  timestamptz,
+    "end_time"    timestamptz,
+    "time_from"   varchar(5),
+    "time_to"     varchar(5),
+    "week_days"   int4[],
+    "day_from"    int4,
+    "day_to"      int4,
+    "valid_from"  timestamptz,
+    "valid_till"  timestamptz,
+    "time_zone"   varchar(100),
+    "active"      bool         NOT NULL DEFAULT true,
+    "created_on"  timestamptz  NOT NULL,
+    "created_by"  int4         NOT NULL,
+    "updated_on"  timestamptz  NOT NULL,
+    "updated_by"  int4         NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_deployment_window_override_audit;
+
+CREATE TABLE IF NOT EXISTS public.deployment_window_override_audit
+(
+    "id"                    int4        NOT NULL DEFAULT nextval('id_seq_deployment_window_override_audit'::regclass),
+    "pipeline_id"           int4        NOT NULL,
+    "app_id"                int4        NOT NULL,
+    "env_id"                int4        NOT NULL,
+    "cd_workflow_runner_id" int4,
+    "window_ids"            int4[],
+    "reason"                text        NOT NULL,
+    "created_on"            timestamptz NOT NULL,
+    "created_by"            int4        NOT NULL,
+    "updated_on"            timestamptz NOT NULL,
+    "updated_by"            int4        NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS idx_deployment_window_override_audit_pipeline_id
+    ON public.deployment_window_override_audit (pipeline_id);
+
+COMMIT;
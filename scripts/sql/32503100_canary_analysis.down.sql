@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_canary_analysis_run_cd_workflow_runner_id;
+DROP INDEX IF EXISTS idx_canary_analysis_run_status_next_measurement_at;
+DROP TABLE IF EXISTS public.canary_analysis_run;
+DROP SEQUENCE IF EXISTS id_seq_canary_analysis_run;
+DROP INDEX IF EXISTS idx_canary_analysis_template_app_id_env_id;
+DROP TABLE IF EXISTS public.canary_analysis_template;
+DROP SEQUENCE IF EXISTS id_seq_canary_analysis_template;
+
+COMMIT;
@@ -0,0 +1,62 @@
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_canary_analysis_template;
+
+CREATE TABLE IF NOT EXISTS public.canary_analysis_template
+(
+    "id"                    int4         NOT NULL DEFAULT nextval('id_seq_canary_analysis_template'::regclass),
+    "name"                  varchar(100) NOT NULL,
+    "app_id"                int4         NOT NULL,
+    "env_id"                int4         NOT NULL,
+    "metrics"               text,
+    "initial_delay_seconds" int4,
+    "interval_seconds"      int4         NOT NULL,
+    "count"                 int4         NOT NULL,
+    "failure_limit"         int4         NOT NULL,
+    "auto_promote"          bool         NOT NULL DEFAULT false,
+    "auto_rollback"         bool         NOT NULL DEFAULT false,
+    "active"                bool         NOT NULL DEFAULT true,
+    "created_on"            timestamptz  NOT NULL,
+    "created_by"            int4         NOT NULL,
+    "updated_on"            timestamptz  NOT NULL,
+    "updated_by"            int4         NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS idx_canary_analysis_template_app_id_env_id
+    ON public.canary_analysis_template (app_id, env_id);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_canary_analysis_run;
+
+CREATE TABLE IF NOT EXISTS public.canary_analysis_run
+(
+    "id"                    int4         NOT NULL DEFAULT nextval('id_seq_canary_analysis_run'::regclass),
+    "template_id"           int4         NOT NULL,
+    "pipeline_id"           int4         NOT NULL,
+    "cd_workflow_runner_id" int4         NOT NULL,
+    "cluster_id"            int4,
+    "namespace"             varchar(250),
+    "rollout_name"          varchar(250),
+    "status"                varchar(20)  NOT NULL,
+    "action"                varchar(20),
+    "successful_count"      int4         NOT NULL DEFAULT 0,
+    "failed_count"          int4         NOT NULL DEFAULT 0,
+    "last_measurement"      text,
+    "message"               text,
+    "next_measurement_at"   timestamptz,
+    "finished_on"           timestamptz,
+    "created_on"            timestamptz  NOT NULL,
+    "created_by"            int4         NOT NULL,
+    "updated_on"            timestamptz  NOT NULL,
+    "updated_by"            int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT canary_analysis_run_template_id_fkey FOREIGN KEY ("template_id") REFERENCES "public"."canary_analysis_template" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS idx_canary_analysis_run_status_next_measurement_at
+    ON public.canary_analysis_run (status, next_measurement_at);
+
+CREATE INDEX IF NOT EXISTS idx_canary_analysis_run_cd_workflow_runner_id
+    ON public.canary_analysis_run (cd_workflow_runner_id);
+
+COMMIT;
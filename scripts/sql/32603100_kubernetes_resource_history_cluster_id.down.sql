@@ -0,0 +1 @@
+ALTER TABLE "public"."kubernetes_resource_history" DROP COLUMN IF EXISTS "cluster_id";
@@ -0,0 +1 @@
+ALTER TABLE "public"."kubernetes_resource_history" ADD COLUMN IF NOT EXISTS "cluster_id" integer;
@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_installed_app_upgrade_run_installed_app_id_status;
+DROP TABLE IF EXISTS public.installed_app_upgrade_run;
+DROP SEQUENCE IF EXISTS id_seq_installed_app_upgrade_run;
+DROP INDEX IF EXISTS idx_unique_installed_app_upgrade_policy_installed_app_id;
+DROP TABLE IF EXISTS public.installed_app_upgrade_policy;
+DROP SEQUENCE IF EXISTS id_seq_installed_app_upgrade_policy;
+
+COMMIT;
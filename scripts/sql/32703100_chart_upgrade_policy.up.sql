@@ -0,0 +1,50 @@
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_installed_app_upgrade_policy;
+
+CREATE TABLE IF NOT EXISTS public.installed_app_upgrade_policy
+(
+    "id"                 int4         NOT NULL DEFAULT nextval('id_seq_installed_app_upgrade_policy'::regclass),
+    "installed_app_id"   int4         NOT NULL,
+    "policy_type"        varchar(20)  NOT NULL,
+    "version_constraint" varchar(100),
+    "active"             bool         NOT NULL DEFAULT true,
+    "created_on"         timestamptz  NOT NULL,
+    "created_by"         int4         NOT NULL,
+    "updated_on"         timestamptz  NOT NULL,
+    "updated_by"         int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT installed_app_upgrade_policy_installed_app_id_fkey FOREIGN KEY ("installed_app_id") REFERENCES "public"."installed_apps" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_installed_app_upgrade_policy_installed_app_id
+    ON public.installed_app_upgrade_policy (installed_app_id)
+    WHERE active = true;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_installed_app_upgrade_run;
+
+CREATE TABLE IF NOT EXISTS public.installed_app_upgrade_run
+(
+    "id"                                   int4         NOT NULL DEFAULT nextval('id_seq_installed_app_upgrade_run'::regclass),
+    "installed_app_id"                     int4         NOT NULL,
+    "policy_id"                            int4         NOT NULL,
+    "from_app_store_application_version_id" int4 NOT NULL,
+    "to_app_store_application_version_id"  int4         NOT NULL,
+    "previous_version_history_id"          int4,
+    "version_history_id"                   int4,
+    "status"                               varchar(20)  NOT NULL,
+    "message"                              text,
+    "health_check_deadline"                timestamptz,
+    "finished_on"                          timestamptz,
+    "created_on"                           timestamptz  NOT NULL,
+    "created_by"                           int4         NOT NULL,
+    "updated_on"                           timestamptz  NOT NULL,
+    "updated_by"                           int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT installed_app_upgrade_run_policy_id_fkey FOREIGN KEY ("policy_id") REFERENCES "public"."installed_app_upgrade_policy" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS idx_installed_app_upgrade_run_installed_app_id_status
+    ON public.installed_app_upgrade_run (installed_app_id, status);
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+DELETE FROM "public"."notification_templates"
+WHERE event_type_id = 10
+  AND template_name IN ('config drift slack template', 'config drift ses template', 'config drift smtp template');
+DELETE FROM "public"."notification_settings" WHERE event_type_id = 10;
+DELETE FROM "public"."event" WHERE id = 10 AND event_type = 'CONFIG DRIFT';
+DROP INDEX IF EXISTS idx_unique_helm_release_drift_pipeline_id;
+DROP TABLE IF EXISTS public.helm_release_drift;
+DROP SEQUENCE IF EXISTS id_seq_helm_release_drift;
+
+COMMIT;
@@ -0,0 +1,94 @@
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_helm_release_drift;
+
+CREATE TABLE IF NOT EXISTS public.helm_release_drift
+(
+    "id"                int4         NOT NULL DEFAULT nextval('id_seq_helm_release_drift'::regclass),
+    "pipeline_id"       int4         NOT NULL,
+    "auto_remediate"    bool         NOT NULL DEFAULT false,
+    "status"            varchar(20)  NOT NULL,
+    "desired_revision"  int4,
+    "live_revision"     int4,
+    "drifted_resources" text,
+    "drift_hash"        varchar(64),
+    "message"           text,
+    "last_checked_on"   timestamptz,
+    "notified_on"       timestamptz,
+    "remediated_on"     timestamptz,
+    "created_on"        timestamptz  NOT NULL,
+    "created_by"        int4         NOT NULL,
+    "updated_on"        timestamptz  NOT NULL,
+    "updated_by"        int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT helm_release_drift_pipeline_id_fkey FOREIGN KEY ("pipeline_id") REFERENCES "public"."pipeline" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_helm_release_drift_pipeline_id
+    ON public.helm_release_drift (pipeline_id);
+
+INSERT INTO "public"."event" (id, event_type, description)
+SELECT 10, 'CONFIG DRIFT', ''
+WHERE NOT EXISTS (SELECT 1 FROM "public"."event" WHERE id = 10);
+
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+VALUES ('slack', 'CD', 10, 'config drift slack template', '{
+    "text": ":warning: Configuration drift detected | Application > {{appName}} | Environment > {{envName}}",
+    "blocks": [{
+            "type": "section",
+            "text": {
+                "type": "mrkdwn",
+                "text": ":warning: *Configuration drift detected on {{envName}}*\n<!date^{{eventTime}}^{date_long} {time} | \"-\">"
+            }
+        },
+        {
+            "type": "divider"
+        },
+        {
+            "type": "section",
+            "fields": [{
+                    "type": "mrkdwn",
+                    "text": "*Application*\n{{appName}}\n*Pipeline*\n{{pipelineName}}"
+                },
+                {
+                    "type": "mrkdwn",
+                    "text": "*Environment*\n{{envName}}"
+                }
+            ]
+        },
+        {
+            "type": "actions",
+            "elements": [{
+                    "type": "button",
+                    "text": {
+                        "type": "plain_text",
+                        "text": "App details",
+                        "emoji": true
+                    }
+                    {{#appDetailsLink}}
+                    ,
+                    "url": "{{& appDetailsLink}}"
+                    {{/appDetailsLink}}
+                }
+            ]
+        }
+    ]
+}');
+
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+VALUES ('ses', 'CD', 10, 'config drift ses template', '{
+    "from": "{{fromEmail}}",
+    "to": "{{toEmail}}",
+    "subject": "Configuration drift detected | Application: {{appName}} | Environment: {{envName}}",
+    "html": "<table cellpadding=\"0\" style=\"font-family:Arial,Verdana,Helvetica;width:600px;border-collapse:inherit;border-spacing:0;border:1px solid #d0d4d9;border-radius:8px;padding:16px 20px;margin:20px auto\"><tr><td colspan=\"2\"><div style=\"background-color:#fff8e5;border-radius:8px;padding:20px\"><div style=\"font-size:16px;line-height:24px;font-weight:600;margin-bottom:6px;color:#000a14\">Configuration drift detected on {{envName}}</div><span style=\"font-size:14px;line-height:20px;color:#000a14\">{{eventTime}}</span></div></td></tr><tr><td><br></td></tr><tr><td><div style=\"color:#3b444c;font-size:13px\">Application</div></td><td><div style=\"color:#3b444c;font-size:13px\">Environment</div></td></tr><tr><td><div style=\"color:#000a14;font-size:14px\">{{appName}}</div></td><td><div style=\"color:#000a14;font-size:14px\">{{envName}}</div></td></tr><tr><td><br></td></tr><tr><td colspan=\"2\"><div style=\"color:#3b444c;font-size:13px\">Live resources of the helm release no longer match the last deployment from Devtron.</div></td></tr><tr><td><br></td></tr><tr><td colspan=\"2\"><a href=\"{{&appDetailsLink}}\" style=\"padding:7px 12px;font-size:12px;font-weight:600;border-radius:4px;text-decoration:none;background:#06c;color:#fff\">View App Details</a></td></tr></table>"
+}');
+
+INSERT INTO "public"."notification_templates" (channel_type, node_type, event_type_id, template_name, template_payload)
+VALUES ('smtp', 'CD', 10, 'config drift smtp template', '{
+    "from": "{{fromEmail}}",
+    "to": "{{toEmail}}",
+    "subject": "Configuration drift detected | Application: {{appName}} | Environment: {{envName}}",
+    "html": "<table cellpadding=\"0\" style=\"font-family:Arial,Verdana,Helvetica;width:600px;border-collapse:inherit;border-spacing:0;border:1px solid #d0d4d9;border-radius:8px;padding:16px 20px;margin:20px auto\"><tr><td colspan=\"2\"><div style=\"background-color:#fff8e5;border-radius:8px;padding:20px\"><div style=\"font-size:16px;line-height:24px;font-weight:600;margin-bottom:6px;color:#000a14\">Configuration drift detected on {{envName}}</div><span style=\"font-size:14px;line-height:20px;color:#000a14\">{{eventTime}}</span></div></td></tr><tr><td><br></td></tr><tr><td><div style=\"color:#3b444c;font-size:13px\">Application</div></td><td><div style=\"color:#3b444c;font-size:13px\">Environment</div></td></tr><tr><td><div style=\"color:#000a14;font-size:14px\">{{appName}}</div></td><td><div style=\"color:#000a14;font-size:14px\">{{envName}}</div></td></tr><tr><td><br></td></tr><tr><td colspan=\"2\"><div style=\"color:#3b444c;font-size:13px\">Live resources of the helm release no longer match the last deployment from Devtron.</div></td></tr><tr><td><br></td></tr><tr><td colspan=\"2\"><a href=\"{{&appDetailsLink}}\" style=\"padding:7px 12px;font-size:12px;font-weight:600;border-radius:4px;text-decoration:none;background:#06c;color:#fff\">View App Details</a></td></tr></table>"
+}');
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_role_access_request_status;
+DROP TABLE IF EXISTS public.role_access_request;
+DROP SEQUENCE IF EXISTS id_seq_role_access_request;
+DROP INDEX IF EXISTS idx_user_roles_timeout_window_configuration_id;
+DROP INDEX IF EXISTS idx_unique_active_user_role_group_timeout_window;
+DROP TABLE IF EXISTS public.user_role_group_timeout_window;
+DROP SEQUENCE IF EXISTS id_seq_user_role_group_timeout_window;
+
+COMMIT;
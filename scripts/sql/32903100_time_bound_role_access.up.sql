@@ -0,0 +1,56 @@
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_user_role_group_timeout_window;
+
+CREATE TABLE IF NOT EXISTS public.user_role_group_timeout_window
+(
+    "id"                              int4        NOT NULL DEFAULT nextval('id_seq_user_role_group_timeout_window'::regclass),
+    "user_id"                         int4        NOT NULL,
+    "role_group_id"                   int4        NOT NULL,
+    "timeout_window_configuration_id" int4        NOT NULL,
+    "active"                          bool        NOT NULL DEFAULT true,
+    "created_on"                      timestamptz NOT NULL,
+    "created_by"                      int4        NOT NULL,
+    "updated_on"                      timestamptz NOT NULL,
+    "updated_by"                      int4        NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT user_role_group_timeout_window_user_id_fkey FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id"),
+    CONSTRAINT user_role_group_timeout_window_role_group_id_fkey FOREIGN KEY ("role_group_id") REFERENCES "public"."role_group" ("id"),
+    CONSTRAINT user_role_group_timeout_window_twc_id_fkey FOREIGN KEY ("timeout_window_configuration_id") REFERENCES "public"."timeout_window_configuration" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_active_user_role_group_timeout_window
+    ON public.user_role_group_timeout_window (user_id, role_group_id)
+    WHERE active = true;
+
+CREATE INDEX IF NOT EXISTS idx_user_roles_timeout_window_configuration_id
+    ON public.user_roles (timeout_window_configuration_id)
+    WHERE timeout_window_configuration_id IS NOT NULL;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_role_access_request;
+
+CREATE TABLE IF NOT EXISTS public.role_access_request
+(
+    "id"             int4         NOT NULL DEFAULT nextval('id_seq_role_access_request'::regclass),
+    "user_id"        int4         NOT NULL,
+    "role_filters"   text,
+    "role_groups"    text,
+    "reason"         text,
+    "active_from"    timestamptz,
+    "expires_at"     timestamptz  NOT NULL,
+    "status"         varchar(20)  NOT NULL,
+    "reviewed_by"    int4,
+    "reviewed_on"    timestamptz,
+    "review_comment" text,
+    "created_on"     timestamptz  NOT NULL,
+    "created_by"     int4         NOT NULL,
+    "updated_on"     timestamptz  NOT NULL,
+    "updated_by"     int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT role_access_request_user_id_fkey FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id")
+);
+
+CREATE INDEX IF NOT EXISTS idx_role_access_request_status
+    ON public.role_access_request (status);
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.user_role_group_claim_membership;
+DROP SEQUENCE IF EXISTS public.id_seq_user_role_group_claim_membership;
+
+DROP TABLE IF EXISTS public.user_group_claims;
+DROP SEQUENCE IF EXISTS public.id_seq_user_group_claims;
+
+DROP TABLE IF EXISTS public.role_group_claim_mapping;
+DROP SEQUENCE IF EXISTS public.id_seq_role_group_claim_mapping;
+
+COMMIT;
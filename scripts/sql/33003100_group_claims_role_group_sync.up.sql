@@ -0,0 +1,64 @@
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_role_group_claim_mapping;
+
+CREATE TABLE IF NOT EXISTS public.role_group_claim_mapping
+(
+    "id"            int4         NOT NULL DEFAULT nextval('id_seq_role_group_claim_mapping'::regclass),
+    "role_group_id" int4         NOT NULL,
+    "connector_id"  varchar(250),
+    "group_claim"   varchar(250) NOT NULL,
+    "active"        bool         NOT NULL DEFAULT true,
+    "created_on"    timestamptz  NOT NULL,
+    "created_by"    int4         NOT NULL,
+    "updated_on"    timestamptz  NOT NULL,
+    "updated_by"    int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT role_group_claim_mapping_role_group_id_fkey FOREIGN KEY ("role_group_id") REFERENCES "public"."role_group" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_active_role_group_claim_mapping
+    ON public.role_group_claim_mapping (role_group_id, COALESCE(connector_id, ''), group_claim)
+    WHERE active = true;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_user_group_claims;
+
+CREATE TABLE IF NOT EXISTS public.user_group_claims
+(
+    "id"            int4         NOT NULL DEFAULT nextval('id_seq_user_group_claims'::regclass),
+    "email_id"      varchar(256) NOT NULL,
+    "connector_id"  varchar(250),
+    "group_claims"  text[],
+    "last_login_on" timestamptz  NOT NULL,
+    "created_on"    timestamptz  NOT NULL,
+    "created_by"    int4         NOT NULL,
+    "updated_on"    timestamptz  NOT NULL,
+    "updated_by"    int4         NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_user_group_claims_email_id
+    ON public.user_group_claims (email_id);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_user_role_group_claim_membership;
+
+CREATE TABLE IF NOT EXISTS public.user_role_group_claim_membership
+(
+    "id"            int4        NOT NULL DEFAULT nextval('id_seq_user_role_group_claim_membership'::regclass),
+    "user_id"       int4        NOT NULL,
+    "role_group_id" int4        NOT NULL,
+    "active"        bool        NOT NULL DEFAULT true,
+    "created_on"    timestamptz NOT NULL,
+    "created_by"    int4        NOT NULL,
+    "updated_on"    timestamptz NOT NULL,
+    "updated_by"    int4        NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT user_role_group_claim_membership_user_id_fkey FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id"),
+    CONSTRAINT user_role_group_claim_membership_role_group_id_fkey FOREIGN KEY ("role_group_id") REFERENCES "public"."role_group" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_active_user_role_group_claim_membership
+    ON public.user_role_group_claim_membership (user_id, role_group_id)
+    WHERE active = true;
+
+COMMIT;
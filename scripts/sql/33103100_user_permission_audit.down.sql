@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.user_permission_audit;
+DROP SEQUENCE IF EXISTS public.id_seq_user_permission_audit;
+
+COMMIT;
@@ -0,0 +1,27 @@
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_user_permission_audit;
+
+CREATE TABLE IF NOT EXISTS public.user_permission_audit
+(
+    "id"             int4         NOT NULL DEFAULT nextval('id_seq_user_permission_audit'::regclass),
+    "entity_type"    varchar(50)  NOT NULL,
+    "entity_id"      int4         NOT NULL,
+    "entity_name"    varchar(256),
+    "action"         varchar(50)  NOT NULL,
+    "previous_state" text,
+    "current_state"  text,
+    "created_on"     timestamptz  NOT NULL,
+    "created_by"     int4         NOT NULL,
+    "updated_on"     timestamptz  NOT NULL,
+    "updated_by"     int4         NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_permission_audit_entity
+    ON public.user_permission_audit (entity_type, entity_id);
+
+CREATE INDEX IF NOT EXISTS idx_user_permission_audit_created_on
+    ON public.user_permission_audit (created_on);
+
+COMMIT;
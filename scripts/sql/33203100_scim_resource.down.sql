@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.scim_resource;
+DROP SEQUENCE IF EXISTS public.id_seq_scim_resource;
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_scim_resource;
+
+CREATE TABLE IF NOT EXISTS public.scim_resource
+(
+    "id"            int4         NOT NULL DEFAULT nextval('id_seq_scim_resource'::regclass),
+    "resource_type" varchar(20)  NOT NULL,
+    "resource_id"   int4         NOT NULL,
+    "external_id"   varchar(256),
+    "user_name"     varchar(256),
+    "active"        bool         NOT NULL,
+    "created_on"    timestamptz  NOT NULL,
+    "created_by"    int4         NOT NULL,
+    "updated_on"    timestamptz  NOT NULL,
+    "updated_by"    int4         NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_scim_resource_active
+    ON public.scim_resource (resource_type, resource_id)
+    WHERE active = true;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+ALTER TABLE public.api_token
+    DROP COLUMN IF EXISTS scope,
+    DROP COLUMN IF EXISTS allowed_ips,
+    DROP COLUMN IF EXISTS previous_version,
+    DROP COLUMN IF EXISTS previous_version_expire_at_in_ms,
+    DROP COLUMN IF EXISTS last_used_at,
+    DROP COLUMN IF EXISTS last_used_by_ip,
+    DROP COLUMN IF EXISTS last_used_version;
+
+COMMIT;
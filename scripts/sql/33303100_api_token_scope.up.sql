@@ -0,0 +1,12 @@
+BEGIN;
+
+ALTER TABLE public.api_token
+    ADD COLUMN IF NOT EXISTS scope                            text,
+    ADD COLUMN IF NOT EXISTS allowed_ips                      text[],
+    ADD COLUMN IF NOT EXISTS previous_version                 integer,
+    ADD COLUMN IF NOT EXISTS previous_version_expire_at_in_ms bigint,
+    ADD COLUMN IF NOT EXISTS last_used_at                     timestamptz,
+    ADD COLUMN IF NOT EXISTS last_used_by_ip                  varchar(100),
+    ADD COLUMN IF NOT EXISTS last_used_version                integer;
+
+COMMIT;
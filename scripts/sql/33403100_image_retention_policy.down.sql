@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.ci_artifact_purge;
+DROP SEQUENCE IF EXISTS public.id_seq_ci_artifact_purge;
+DROP TABLE IF EXISTS public.image_retention_policy;
+DROP SEQUENCE IF EXISTS public.id_seq_image_retention_policy;
+
+COMMIT;
@@ -0,0 +1,51 @@
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_image_retention_policy;
+
+CREATE TABLE IF NOT EXISTS public.image_retention_policy
+(
+    "id"                         int4         NOT NULL DEFAULT nextval('id_seq_image_retention_policy'::regclass),
+    "name"                       varchar(100) NOT NULL,
+    "scope"                      varchar(20)  NOT NULL,
+    "ci_pipeline_id"             int4,
+    "docker_registry_id"         varchar(250),
+    "keep_last_n"                int4         NOT NULL,
+    "keep_deployed_within_days"  int4         NOT NULL DEFAULT 0,
+    "keep_ever_deployed_to_prod" bool         NOT NULL DEFAULT true,
+    "auto_purge"                 bool         NOT NULL DEFAULT false,
+    "last_purged_on"             timestamptz,
+    "active"                     bool         NOT NULL,
+    "created_on"                 timestamptz  NOT NULL,
+    "created_by"                 int4         NOT NULL,
+    "updated_on"                 timestamptz  NOT NULL,
+    "updated_by"                 int4         NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_image_retention_policy_ci_pipeline_id
+    ON public.image_retention_policy (ci_pipeline_id) WHERE active = true AND ci_pipeline_id IS NOT NULL AND ci_pipeline_id > 0;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_image_retention_policy_docker_registry_id
+    ON public.image_retention_policy (docker_registry_id) WHERE active = true AND docker_registry_id IS NOT NULL AND docker_registry_id <> '';
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_ci_artifact_purge;
+
+CREATE TABLE IF NOT EXISTS public.ci_artifact_purge
+(
+    "id"                        int4         NOT NULL DEFAULT nextval('id_seq_ci_artifact_purge'::regclass),
+    "ci_artifact_id"            int4         NOT NULL,
+    "image_retention_policy_id" int4         NOT NULL,
+    "image"                     text         NOT NULL,
+    "image_digest"              text,
+    "created_on"                timestamptz  NOT NULL,
+    "created_by"                int4         NOT NULL,
+    "updated_on"                timestamptz  NOT NULL,
+    "updated_by"                int4         NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT ci_artifact_purge_policy_id_fkey FOREIGN KEY ("image_retention_policy_id") REFERENCES "public"."image_retention_policy" ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_ci_artifact_purge_ci_artifact_id
+    ON public.ci_artifact_purge (ci_artifact_id);
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.ci_artifact_promotion;
+DROP SEQUENCE IF EXISTS public.id_seq_ci_artifact_promotion;
+DROP TABLE IF EXISTS public.environment_promotion_config;
+DROP SEQUENCE IF EXISTS public.id_seq_environment_promotion_config;
+
+COMMIT;
@@ -0,0 +1,44 @@
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_environment_promotion_config;
+
+CREATE TABLE IF NOT EXISTS public.environment_promotion_config
+(
+    "id"                 int4         NOT NULL DEFAULT nextval('id_seq_environment_promotion_config'::regclass),
+    "environment_id"     int4         NOT NULL,
+    "target_registry_id" varchar(250) NOT NULL,
+    "repository_prefix"  varchar(200),
+    "active"             bool         NOT NULL,
+    "created_on"         timestamptz  NOT NULL,
+    "created_by"         int4         NOT NULL,
+    "updated_on"         timestamptz  NOT NULL,
+    "updated_by"         int4         NOT NULL,
+    CONSTRAINT "environment_promotion_config_environment_id_fkey" FOREIGN KEY ("environment_id") REFERENCES "public"."environment" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_environment_promotion_config_environment_id
+    ON public.environment_promotion_config (environment_id) WHERE active = true;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_ci_artifact_promotion;
+
+CREATE TABLE IF NOT EXISTS public.ci_artifact_promotion
+(
+    "id"                 int4         NOT NULL DEFAULT nextval('id_seq_ci_artifact_promotion'::regclass),
+    "ci_artifact_id"     int4         NOT NULL,
+    "environment_id"     int4         NOT NULL,
+    "target_registry_id" varchar(250) NOT NULL,
+    "source_image"       text         NOT NULL,
+    "promoted_image"     text         NOT NULL,
+    "image_digest"       text         NOT NULL,
+    "created_on"         timestamptz  NOT NULL,
+    "created_by"         int4         NOT NULL,
+    "updated_on"         timestamptz  NOT NULL,
+    "updated_by"         int4         NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_ci_artifact_promotion
+    ON public.ci_artifact_promotion (ci_artifact_id, target_registry_id, promoted_image);
+
+COMMIT;
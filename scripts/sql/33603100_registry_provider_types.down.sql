@@ -0,0 +1,13 @@
+BEGIN;
+
+DELETE FROM public.registry_index_mapping WHERE registry_type = 'harbor';
+
+-- keeps the quay mapping seeded by 148 for scan tool id 3
+DELETE FROM public.registry_index_mapping WHERE registry_type = 'quay' AND scan_tool_id <> 3
+AND scan_tool_id IN (SELECT id FROM public.scan_tool_metadata WHERE name = 'TRIVY' AND version = 'V1');
+
+DELETE FROM public.scan_tool_metadata WHERE name IN ('HARBOR', 'QUAY') AND version = 'V1';
+
+ALTER TABLE public.docker_artifact_store DROP COLUMN IF EXISTS import_scan_results;
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN;
+
+ALTER TABLE public.docker_artifact_store ADD COLUMN IF NOT EXISTS import_scan_results bool NOT NULL DEFAULT false;
+
+-- tools against which the scan results imported from harbor and quay are recorded, never picked up for scanning
+INSERT INTO public.scan_tool_metadata(name, version, server_base_url, result_descriptor_template, scan_target, active, deleted, created_on, created_by, updated_on, updated_by, tool_metadata)
+SELECT 'HARBOR', 'V1', null, null, 'IMAGE', false, false, now()::timestamp, '1', now()::timestamp, '1', null
+WHERE NOT EXISTS (SELECT 1 FROM public.scan_tool_metadata WHERE name = 'HARBOR' AND version = 'V1');
+
+INSERT INTO public.scan_tool_metadata(name, version, server_base_url, result_descriptor_template, scan_target, active, deleted, created_on, created_by, updated_on, updated_by, tool_metadata)
+SELECT 'QUAY', 'V1', null, null, 'IMAGE', false, false, now()::timestamp, '1', now()::timestamp, '1', null
+WHERE NOT EXISTS (SELECT 1 FROM public.scan_tool_metadata WHERE name = 'QUAY' AND version = 'V1');
+
+-- harbor images are scanned by trivy with username and password like any other registry
+INSERT INTO public.registry_index_mapping(scan_tool_id, registry_type, starting_index)
+SELECT id, 'harbor', 1 FROM public.scan_tool_metadata WHERE name = 'TRIVY' AND version = 'V1'
+AND NOT EXISTS (SELECT 1 FROM public.registry_index_mapping WHERE registry_type = 'harbor');
+
+-- quay robots and tokens are scanned by trivy the same way, 148 only seeded quay for scan tool id 3
+INSERT INTO public.registry_index_mapping(scan_tool_id, registry_type, starting_index)
+SELECT tool.id, 'quay', 1 FROM public.scan_tool_metadata tool WHERE tool.name = 'TRIVY' AND tool.version = 'V1'
+AND NOT EXISTS (SELECT 1 FROM public.registry_index_mapping mapping WHERE mapping.registry_type = 'quay' AND mapping.scan_tool_id = tool.id);
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.resource_budget_usage;
+DROP SEQUENCE IF EXISTS id_seq_resource_budget_usage;
+
+DROP TABLE IF EXISTS public.resource_budget_violation;
+DROP SEQUENCE IF EXISTS id_seq_resource_budget_violation;
+
+DROP TABLE IF EXISTS public.resource_budget;
+DROP SEQUENCE IF EXISTS id_seq_resource_budget;
+
+COMMIT;
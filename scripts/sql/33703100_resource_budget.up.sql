@@ -0,0 +1,73 @@
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_resource_budget;
+
+CREATE TABLE IF NOT EXISTS public.resource_budget
+(
+    "id"             int4        NOT NULL DEFAULT nextval('id_seq_resource_budget'::regclass),
+    "environment_id" int4,
+    "team_id"        int4,
+    "cpu"            varchar(50),
+    "memory"         varchar(50),
+    "replicas"       int4,
+    "storage"        varchar(50),
+    "mode"           varchar(10) NOT NULL,
+    "active"         bool        NOT NULL,
+    "created_on"     timestamptz NOT NULL,
+    "created_by"     int4        NOT NULL,
+    "updated_on"     timestamptz NOT NULL,
+    "updated_by"     int4        NOT NULL,
+    CONSTRAINT "resource_budget_environment_id_fkey" FOREIGN KEY ("environment_id") REFERENCES "public"."environment" ("id"),
+    CONSTRAINT "resource_budget_team_id_fkey" FOREIGN KEY ("team_id") REFERENCES "public"."team" ("id"),
+    CONSTRAINT "resource_budget_scope_check" CHECK (environment_id IS NOT NULL OR team_id IS NOT NULL),
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_resource_budget_scope
+    ON public.resource_budget (COALESCE(environment_id, 0), COALESCE(team_id, 0)) WHERE active = true;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_resource_budget_violation;
+
+CREATE TABLE IF NOT EXISTS public.resource_budget_violation
+(
+    "id"                 int4        NOT NULL DEFAULT nextval('id_seq_resource_budget_violation'::regclass),
+    "resource_budget_id" int4        NOT NULL,
+    "app_id"             int4        NOT NULL,
+    "environment_id"     int4        NOT NULL,
+    "pipeline_id"        int4        NOT NULL,
+    "mode"               varchar(10) NOT NULL,
+    "message"            text        NOT NULL,
+    "created_on"         timestamptz NOT NULL,
+    "created_by"         int4        NOT NULL,
+    "updated_on"         timestamptz NOT NULL,
+    "updated_by"         int4        NOT NULL,
+    CONSTRAINT "resource_budget_violation_resource_budget_id_fkey" FOREIGN KEY ("resource_budget_id") REFERENCES "public"."resource_budget" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS idx_resource_budget_violation_environment_id
+    ON public.resource_budget_violation (environment_id, created_on);
+
+-- resources claimed by the rendered manifest of a deployment, written when the deployment is checked against budgets
+CREATE SEQUENCE IF NOT EXISTS id_seq_resource_budget_usage;
+
+CREATE TABLE IF NOT EXISTS public.resource_budget_usage
+(
+    "id"                   int4        NOT NULL DEFAULT nextval('id_seq_resource_budget_usage'::regclass),
+    "pipeline_override_id" int4        NOT NULL,
+    "cpu"                  int8        NOT NULL,
+    "memory"               int8        NOT NULL,
+    "replicas"             int8        NOT NULL,
+    "storage"              int8        NOT NULL,
+    "created_on"           timestamptz NOT NULL,
+    "created_by"           int4        NOT NULL,
+    "updated_on"           timestamptz NOT NULL,
+    "updated_by"           int4        NOT NULL,
+    CONSTRAINT "resource_budget_usage_pipeline_override_id_fkey" FOREIGN KEY ("pipeline_override_id") REFERENCES "public"."pipeline_config_override" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_resource_budget_usage_pipeline_override_id
+    ON public.resource_budget_usage (pipeline_override_id);
+
+COMMIT;
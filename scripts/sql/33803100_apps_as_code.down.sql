@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.app_code_managed_app;
+DROP SEQUENCE IF EXISTS id_seq_app_code_managed_app;
+
+DROP TABLE IF EXISTS public.app_code_source;
+DROP SEQUENCE IF EXISTS id_seq_app_code_source;
+
+COMMIT;
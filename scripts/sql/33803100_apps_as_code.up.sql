@@ -0,0 +1,52 @@
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_app_code_source;
+
+CREATE TABLE IF NOT EXISTS public.app_code_source
+(
+    "id"              int4         NOT NULL DEFAULT nextval('id_seq_app_code_source'::regclass),
+    "name"            varchar(100) NOT NULL,
+    "git_provider_id" int4         NOT NULL,
+    "repo_url"        text         NOT NULL,
+    "branch"          varchar(250),
+    "path"            text,
+    "lock_ui_edits"   bool         NOT NULL DEFAULT false,
+    "prune"           bool         NOT NULL DEFAULT false,
+    "last_applied_on" timestamptz,
+    "active"          bool         NOT NULL,
+    "created_on"      timestamptz  NOT NULL,
+    "created_by"      int4         NOT NULL,
+    "updated_on"      timestamptz  NOT NULL,
+    "updated_by"      int4         NOT NULL,
+    CONSTRAINT "app_code_source_git_provider_id_fkey" FOREIGN KEY ("git_provider_id") REFERENCES "public"."git_provider" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_app_code_managed_app;
+
+CREATE TABLE IF NOT EXISTS public.app_code_managed_app
+(
+    "id"               int4         NOT NULL DEFAULT nextval('id_seq_app_code_managed_app'::regclass),
+    "source_id"        int4         NOT NULL,
+    "app_id"           int4         NOT NULL,
+    "app_name"         varchar(250) NOT NULL,
+    "file_path"        text,
+    "drifted"          bool         NOT NULL DEFAULT false,
+    "drifted_sections" text[],
+    "last_applied_on"  timestamptz,
+    "last_checked_on"  timestamptz,
+    "active"           bool         NOT NULL,
+    "created_on"       timestamptz  NOT NULL,
+    "created_by"       int4         NOT NULL,
+    "updated_on"       timestamptz  NOT NULL,
+    "updated_by"       int4         NOT NULL,
+    CONSTRAINT "app_code_managed_app_source_id_fkey" FOREIGN KEY ("source_id") REFERENCES "public"."app_code_source" ("id"),
+    CONSTRAINT "app_code_managed_app_app_id_fkey" FOREIGN KEY ("app_id") REFERENCES "public"."app" ("id"),
+    PRIMARY KEY ("id")
+);
+
+-- an app is reconciled from a single source
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_app_code_managed_app
+    ON public.app_code_managed_app (app_id) WHERE active = true;
+
+COMMIT;
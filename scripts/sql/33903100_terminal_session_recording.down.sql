@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.terminal_recording_policy;
+DROP SEQUENCE IF EXISTS id_seq_terminal_recording_policy;
+
+DROP TABLE IF EXISTS public.terminal_session_recording;
+DROP SEQUENCE IF EXISTS id_seq_terminal_session_recording;
+
+COMMIT;
@@ -0,0 +1,58 @@
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_terminal_session_recording;
+
+CREATE TABLE IF NOT EXISTS public.terminal_session_recording
+(
+    "id"             int4         NOT NULL DEFAULT nextval('id_seq_terminal_session_recording'::regclass),
+    "session_id"     varchar(100) NOT NULL,
+    "user_id"        int4         NOT NULL,
+    "cluster_id"     int4         NOT NULL,
+    "environment_id" int4,
+    "app_id"         int4,
+    "namespace"      varchar(250) NOT NULL,
+    "pod_name"       varchar(250) NOT NULL,
+    "container_name" varchar(250),
+    "shell"          varchar(50),
+    "status"         varchar(50)  NOT NULL,
+    "blob_key"       text,
+    "size_bytes"     int8,
+    "error"          text,
+    "started_on"     timestamptz  NOT NULL,
+    "ended_on"       timestamptz,
+    "created_on"     timestamptz  NOT NULL,
+    "created_by"     int4         NOT NULL,
+    "updated_on"     timestamptz  NOT NULL,
+    "updated_by"     int4         NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS idx_terminal_session_recording_started_on
+    ON public.terminal_session_recording (started_on);
+
+CREATE INDEX IF NOT EXISTS idx_terminal_session_recording_user_id
+    ON public.terminal_session_recording (user_id, started_on);
+
+CREATE INDEX IF NOT EXISTS idx_terminal_session_recording_pod
+    ON public.terminal_session_recording (cluster_id, namespace, pod_name);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_terminal_recording_policy;
+
+-- environments whose terminal sessions have to be recorded
+CREATE TABLE IF NOT EXISTS public.terminal_recording_policy
+(
+    "id"             int4        NOT NULL DEFAULT nextval('id_seq_terminal_recording_policy'::regclass),
+    "environment_id" int4        NOT NULL,
+    "active"         bool        NOT NULL,
+    "created_on"     timestamptz NOT NULL,
+    "created_by"     int4        NOT NULL,
+    "updated_on"     timestamptz NOT NULL,
+    "updated_by"     int4        NOT NULL,
+    CONSTRAINT "terminal_recording_policy_environment_id_fkey" FOREIGN KEY ("environment_id") REFERENCES "public"."environment" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_terminal_recording_policy_environment
+    ON public.terminal_recording_policy (environment_id) WHERE active = true;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.terminal_command_audit;
+DROP SEQUENCE IF EXISTS id_seq_terminal_command_audit;
+
+DROP TABLE IF EXISTS public.terminal_command_policy;
+DROP SEQUENCE IF EXISTS id_seq_terminal_command_policy;
+
+COMMIT;
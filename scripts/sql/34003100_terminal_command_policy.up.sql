@@ -0,0 +1,59 @@
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_terminal_command_policy;
+
+-- command filter of the terminal sessions of a cluster, or of a single environment of it
+CREATE TABLE IF NOT EXISTS public.terminal_command_policy
+(
+    "id"             int4         NOT NULL DEFAULT nextval('id_seq_terminal_command_policy'::regclass),
+    "name"           varchar(250) NOT NULL,
+    "cluster_id"     int4         NOT NULL,
+    "environment_id" int4,
+    "audit_enabled"  bool         NOT NULL DEFAULT true,
+    "deny_patterns"  text[],
+    "active"         bool         NOT NULL,
+    "created_on"     timestamptz  NOT NULL,
+    "created_by"     int4         NOT NULL,
+    "updated_on"     timestamptz  NOT NULL,
+    "updated_by"     int4         NOT NULL,
+    CONSTRAINT "terminal_command_policy_cluster_id_fkey" FOREIGN KEY ("cluster_id") REFERENCES "public"."cluster" ("id"),
+    CONSTRAINT "terminal_command_policy_environment_id_fkey" FOREIGN KEY ("environment_id") REFERENCES "public"."environment" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS idx_terminal_command_policy_cluster_id
+    ON public.terminal_command_policy (cluster_id) WHERE active = true;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_terminal_command_audit;
+
+CREATE TABLE IF NOT EXISTS public.terminal_command_audit
+(
+    "id"             int4         NOT NULL DEFAULT nextval('id_seq_terminal_command_audit'::regclass),
+    "session_id"     varchar(100) NOT NULL,
+    "user_id"        int4         NOT NULL,
+    "cluster_id"     int4         NOT NULL,
+    "environment_id" int4,
+    "namespace"      varchar(250) NOT NULL,
+    "pod_name"       varchar(250) NOT NULL,
+    "container_name" varchar(250),
+    "command"        text         NOT NULL,
+    "blocked"        bool         NOT NULL,
+    "policy_id"      int4,
+    "executed_on"    timestamptz  NOT NULL,
+    "created_on"     timestamptz  NOT NULL,
+    "created_by"     int4         NOT NULL,
+    "updated_on"     timestamptz  NOT NULL,
+    "updated_by"     int4         NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS idx_terminal_command_audit_executed_on
+    ON public.terminal_command_audit (executed_on);
+
+CREATE INDEX IF NOT EXISTS idx_terminal_command_audit_user_id
+    ON public.terminal_command_audit (user_id, executed_on);
+
+CREATE INDEX IF NOT EXISTS idx_terminal_command_audit_session_id
+    ON public.terminal_command_audit (session_id);
+
+COMMIT;
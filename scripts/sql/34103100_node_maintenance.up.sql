@@ -0,0 +1,54 @@
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_node_maintenance;
+
+-- rolling drain of a set of nodes of a cluster, the progress is tracked per node in node_maintenance_node
+CREATE TABLE IF NOT EXISTS public.node_maintenance
+(
+    "id"                  int4         NOT NULL DEFAULT nextval('id_seq_node_maintenance'::regclass),
+    "cluster_id"          int4         NOT NULL,
+    "node_group"          varchar(250),
+    "status"              varchar(50)  NOT NULL,
+    "concurrency"         int4         NOT NULL,
+    "uncordon_after_secs" int4         NOT NULL DEFAULT 0,
+    "drain_options"       text,
+    "message"             text,
+    "drained_on"          timestamptz,
+    -- the orchestrator replica running or completing the maintenance, taken over once the lease expires
+    "lease_owner"         varchar(250),
+    "lease_expires_on"    timestamptz,
+    "created_on"          timestamptz  NOT NULL,
+    "created_by"          int4         NOT NULL,
+    "updated_on"          timestamptz  NOT NULL,
+    "updated_by"          int4         NOT NULL,
+    CONSTRAINT "node_maintenance_cluster_id_fkey" FOREIGN KEY ("cluster_id") REFERENCES "public"."cluster" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS idx_node_maintenance_status
+    ON public.node_maintenance (status);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_node_maintenance_node;
+
+CREATE TABLE IF NOT EXISTS public.node_maintenance_node
+(
+    "id"                  int4         NOT NULL DEFAULT nextval('id_seq_node_maintenance_node'::regclass),
+    "node_maintenance_id" int4         NOT NULL,
+    "node_name"           varchar(250) NOT NULL,
+    "status"              varchar(50)  NOT NULL,
+    "message"             text,
+    "was_unschedulable"   bool         NOT NULL DEFAULT false,
+    "started_on"          timestamptz,
+    "finished_on"         timestamptz,
+    "created_on"          timestamptz  NOT NULL,
+    "created_by"          int4         NOT NULL,
+    "updated_on"          timestamptz  NOT NULL,
+    "updated_by"          int4         NOT NULL,
+    CONSTRAINT "node_maintenance_node_node_maintenance_id_fkey" FOREIGN KEY ("node_maintenance_id") REFERENCES "public"."node_maintenance" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS idx_node_maintenance_node_node_maintenance_id
+    ON public.node_maintenance_node (node_maintenance_id);
+
+COMMIT;
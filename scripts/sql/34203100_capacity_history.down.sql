@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.app_resource_usage_snapshot;
+DROP SEQUENCE IF EXISTS id_seq_app_resource_usage_snapshot;
+
+DROP TABLE IF EXISTS public.capacity_snapshot;
+DROP SEQUENCE IF EXISTS id_seq_capacity_snapshot;
+
+COMMIT;
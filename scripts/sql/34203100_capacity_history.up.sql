@@ -0,0 +1,59 @@
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_capacity_snapshot;
+
+-- periodic capacity of a cluster, of its nodes and of its namespaces, scope tells which one the row is for
+CREATE TABLE IF NOT EXISTS public.capacity_snapshot
+(
+    "id"                       int8         NOT NULL DEFAULT nextval('id_seq_capacity_snapshot'::regclass),
+    "cluster_id"               int4         NOT NULL,
+    "scope"                    varchar(50)  NOT NULL,
+    "name"                     varchar(250) NOT NULL,
+    "cpu_allocatable_millis"   int8         NOT NULL DEFAULT 0,
+    "cpu_requests_millis"      int8         NOT NULL DEFAULT 0,
+    "cpu_limits_millis"        int8         NOT NULL DEFAULT 0,
+    "cpu_usage_millis"         int8         NOT NULL DEFAULT 0,
+    "memory_allocatable_bytes" int8         NOT NULL DEFAULT 0,
+    "memory_requests_bytes"    int8         NOT NULL DEFAULT 0,
+    "memory_limits_bytes"      int8         NOT NULL DEFAULT 0,
+    "memory_usage_bytes"       int8         NOT NULL DEFAULT 0,
+    "pod_count"                int4         NOT NULL DEFAULT 0,
+    "metrics_available"        bool         NOT NULL DEFAULT false,
+    "captured_on"              timestamptz  NOT NULL,
+    CONSTRAINT "capacity_snapshot_cluster_id_fkey" FOREIGN KEY ("cluster_id") REFERENCES "public"."cluster" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS idx_capacity_snapshot_cluster_scope_captured_on
+    ON public.capacity_snapshot (cluster_id, scope, captured_on);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_app_resource_usage_snapshot;
+
+-- periodic usage of the main container of the pods of a devtron app in an environment, requests and limits are per pod
+CREATE TABLE IF NOT EXISTS public.app_resource_usage_snapshot
+(
+    "id"                     int8         NOT NULL DEFAULT nextval('id_seq_app_resource_usage_snapshot'::regclass),
+    "cluster_id"             int4         NOT NULL,
+    "app_id"                 int4         NOT NULL,
+    "env_id"                 int4         NOT NULL,
+    "container_name"         varchar(250) NOT NULL,
+    "pod_count"              int4         NOT NULL,
+    "cpu_requests_millis"    int8         NOT NULL DEFAULT 0,
+    "cpu_limits_millis"      int8         NOT NULL DEFAULT 0,
+    "memory_requests_bytes"  int8         NOT NULL DEFAULT 0,
+    "memory_limits_bytes"    int8         NOT NULL DEFAULT 0,
+    "cpu_usage_avg_millis"   int8         NOT NULL DEFAULT 0,
+    "cpu_usage_max_millis"   int8         NOT NULL DEFAULT 0,
+    "memory_usage_avg_bytes" int8         NOT NULL DEFAULT 0,
+    "memory_usage_max_bytes" int8         NOT NULL DEFAULT 0,
+    "captured_on"            timestamptz  NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS idx_app_resource_usage_snapshot_cluster_captured_on
+    ON public.app_resource_usage_snapshot (cluster_id, captured_on);
+
+CREATE INDEX IF NOT EXISTS idx_app_resource_usage_snapshot_app_env_captured_on
+    ON public.app_resource_usage_snapshot (app_id, env_id, captured_on);
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.cluster_health_probe;
+DROP SEQUENCE IF EXISTS id_seq_cluster_health_probe;
+
+ALTER TABLE public.cluster DROP COLUMN IF EXISTS capabilities;
+
+COMMIT;
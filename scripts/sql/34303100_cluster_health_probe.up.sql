@@ -0,0 +1,27 @@
+BEGIN;
+
+-- capabilities detected on the cluster, e.g. {"argoRollouts": true, "keda": false}
+ALTER TABLE public.cluster ADD COLUMN IF NOT EXISTS capabilities jsonb;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_cluster_health_probe;
+
+-- result of every periodic or on demand connectivity probe of a cluster
+CREATE TABLE IF NOT EXISTS public.cluster_health_probe
+(
+    "id"             int8         NOT NULL DEFAULT nextval('id_seq_cluster_health_probe'::regclass),
+    "cluster_id"     int4         NOT NULL,
+    "status"         varchar(50)  NOT NULL,
+    "live"           bool         NOT NULL DEFAULT false,
+    "ready"          bool         NOT NULL DEFAULT false,
+    "latency_ms"     int8         NOT NULL DEFAULT 0,
+    "server_version" varchar(100),
+    "message"        text,
+    "probed_on"      timestamptz  NOT NULL,
+    CONSTRAINT "cluster_health_probe_cluster_id_fkey" FOREIGN KEY ("cluster_id") REFERENCES "public"."cluster" ("id"),
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX IF NOT EXISTS idx_cluster_health_probe_cluster_probed_on
+    ON public.cluster_health_probe (cluster_id, probed_on);
+
+COMMIT;
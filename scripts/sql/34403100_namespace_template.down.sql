@@ -0,0 +1,12 @@
+BEGIN;
+
+DROP TABLE IF EXISTS public.namespace_template_env_mapping;
+DROP SEQUENCE IF EXISTS id_seq_namespace_template_env_mapping;
+
+DROP TABLE IF EXISTS public.namespace_template_version;
+DROP SEQUENCE IF EXISTS id_seq_namespace_template_version;
+
+DROP TABLE IF EXISTS public.namespace_template;
+DROP SEQUENCE IF EXISTS id_seq_namespace_template;
+
+COMMIT;
@@ -0,0 +1,61 @@
+BEGIN;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_namespace_template;
+
+CREATE TABLE IF NOT EXISTS public.namespace_template
+(
+    "id"             int4         NOT NULL DEFAULT nextval('id_seq_namespace_template'::regclass),
+    "name"           varchar(100) NOT NULL,
+    "description"    text,
+    "latest_version" int4         NOT NULL DEFAULT 1,
+    "active"         bool         NOT NULL DEFAULT true,
+    "created_on"     timestamptz  NOT NULL,
+    "created_by"     int4         NOT NULL,
+    "updated_on"     timestamptz  NOT NULL,
+    "updated_by"     int4         NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_namespace_template_name
+    ON public.namespace_template (name)
+    WHERE active = true;
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_namespace_template_version;
+
+-- every update of a template adds a version, spec holds the resources provisioned in the namespace as json
+CREATE TABLE IF NOT EXISTS public.namespace_template_version
+(
+    "id"                    int4        NOT NULL DEFAULT nextval('id_seq_namespace_template_version'::regclass),
+    "namespace_template_id" int4        NOT NULL,
+    "version"               int4        NOT NULL,
+    "spec"                  text        NOT NULL,
+    "created_on"            timestamptz NOT NULL,
+    "created_by"            int4        NOT NULL,
+    CONSTRAINT "namespace_template_version_template_id_fkey" FOREIGN KEY ("namespace_template_id") REFERENCES "public"."namespace_template" ("id"),
+    UNIQUE ("namespace_template_id", "version"),
+    PRIMARY KEY ("id")
+);
+
+CREATE SEQUENCE IF NOT EXISTS id_seq_namespace_template_env_mapping;
+
+-- template applied on the namespace of an environment, an environment uses at most one template
+CREATE TABLE IF NOT EXISTS public.namespace_template_env_mapping
+(
+    "id"                    int4        NOT NULL DEFAULT nextval('id_seq_namespace_template_env_mapping'::regclass),
+    "namespace_template_id" int4        NOT NULL,
+    "env_id"                int4        NOT NULL,
+    "applied_version"       int4        NOT NULL,
+    "status"                varchar(50) NOT NULL,
+    "message"               text,
+    "applied_on"            timestamptz NOT NULL,
+    "created_on"            timestamptz NOT NULL,
+    "created_by"            int4        NOT NULL,
+    "updated_on"            timestamptz NOT NULL,
+    "updated_by"            int4        NOT NULL,
+    CONSTRAINT "namespace_template_env_mapping_template_id_fkey" FOREIGN KEY ("namespace_template_id") REFERENCES "public"."namespace_template" ("id"),
+    CONSTRAINT "namespace_template_env_mapping_env_id_fkey" FOREIGN KEY ("env_id") REFERENCES "public"."environment" ("id"),
+    UNIQUE ("env_id"),
+    PRIMARY KEY ("id")
+);
+
+COMMIT;
@@ -21,6 +21,7 @@ type EventType int
 const Trigger EventType = 1
 const Success EventType = 2
 const Fail EventType = 3
+const ConfigDrift EventType = 10
 
 type PipelineType string
 